@@ -0,0 +1,73 @@
+package e2e
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config controls where and how the smoke suite runs
+type Config struct {
+	Enabled bool
+
+	// BaseURL is the default address of every service; the per-service URLs override it
+	// when the services are not reachable behind a single address
+	BaseURL          string
+	UsersURL         string
+	GoalsURL         string
+	PaymentsURL      string
+	NotificationsURL string
+
+	StepTimeout     time.Duration
+	EventualTimeout time.Duration
+	PollInterval    time.Duration
+
+	// RunID namespaces every user and goal created by a run
+	RunID string
+}
+
+// LoadConfig reads the suite configuration from E2E_* environment variables
+func LoadConfig() Config {
+	baseURL := os.Getenv("E2E_BASE_URL")
+
+	return Config{
+		Enabled:          os.Getenv("E2E_ENABLED") == "true",
+		BaseURL:          baseURL,
+		UsersURL:         getEnv("E2E_USERS_URL", baseURL),
+		GoalsURL:         getEnv("E2E_GOALS_URL", baseURL),
+		PaymentsURL:      getEnv("E2E_PAYMENTS_URL", baseURL),
+		NotificationsURL: getEnv("E2E_NOTIFICATIONS_URL", baseURL),
+		StepTimeout:      time.Duration(getEnvInt("E2E_STEP_TIMEOUT_SECONDS", 30)) * time.Second,
+		EventualTimeout:  time.Duration(getEnvInt("E2E_EVENTUAL_TIMEOUT_SECONDS", 60)) * time.Second,
+		PollInterval:     time.Duration(getEnvInt("E2E_POLL_INTERVAL_MS", 1000)) * time.Millisecond,
+		RunID:            getEnv("E2E_RUN_ID", fmt.Sprintf("%d", time.Now().UnixNano())),
+	}
+}
+
+// Validate checks that every service has an address
+func (c Config) Validate() error {
+	if c.UsersURL == "" || c.GoalsURL == "" || c.PaymentsURL == "" || c.NotificationsURL == "" {
+		return errors.New("E2E_BASE_URL (or every E2E_*_URL override) is required")
+	}
+	return nil
+}
+
+// getEnv gets an environment variable with a fallback value
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvInt gets an environment variable as int with a fallback value
+func getEnvInt(key string, fallback int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,10 @@
+// Package e2e is a smoke suite that walks the critical user journey against a running
+// environment: registration, goal creation, a contribution paid through the mock
+// provider, proof, vote, withdrawal and the resulting notifications.
+//
+// Its test only runs when E2E_ENABLED=true, outside -short, and needs E2E_BASE_URL (or
+// per-service E2E_*_URL overrides). The users and payments services must have
+// ENABLE_TEST_HOOKS=true, which they refuse in production. Everything it creates is
+// namespaced by E2E_RUN_ID and goals are private, so runs never collide or leak into
+// public listings. Run it with `make e2e`.
+package e2e
@@ -0,0 +1,19 @@
+package e2e
+
+import "testing"
+
+func TestSmokeJourney(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the smoke suite in short mode")
+	}
+	cfg := LoadConfig()
+	if !cfg.Enabled {
+		t.Skip("E2E_ENABLED is not true; skipping the smoke suite")
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Invalid configuration: %v", err)
+	}
+
+	t.Logf("Running e2e smoke suite (run %s)", cfg.RunID)
+	newJourney(cfg).Run(NewRunner(t, cfg))
+}
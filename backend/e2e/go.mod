@@ -0,0 +1,7 @@
+module github.com/gofund/e2e
+
+go 1.24.0
+
+require github.com/gofund/sdk v0.0.0
+
+replace github.com/gofund/sdk => ../sdk
@@ -0,0 +1,67 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// The test hooks are not part of the public API, so they are called directly rather
+// than through the SDK. Services only register them when ENABLE_TEST_HOOKS=true and
+// never in production.
+
+// verifyEmail calls POST /test/users/:id/verify-email on users-service
+func verifyEmail(ctx context.Context, usersURL, userID string) error {
+	return postHook(ctx, usersURL+"/test/users/"+url.PathEscape(userID)+"/verify-email", nil)
+}
+
+// completeMockPayment calls POST /api/v1/payments/mock/complete on payments-service,
+// which records a verified payment for the contribution and emits PaymentVerified
+func completeMockPayment(ctx context.Context, paymentsURL, userID, goalID, contributionID string, amount int64) error {
+	body := map[string]interface{}{
+		"user_id":         userID,
+		"goal_id":         goalID,
+		"contribution_id": contributionID,
+		"amount":          amount,
+	}
+	return postHook(ctx, paymentsURL+"/api/v1/payments/mock/complete", body)
+}
+
+func postHook(ctx context.Context, endpoint string, body interface{}) error {
+	var reqBody io.Reader = http.NoBody
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(endpoint, "/"), reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		message := strings.TrimSpace(string(respBody))
+		// An unregistered route gets gin's plain-text 404; a hook's own 404 is JSON
+		if resp.StatusCode == http.StatusNotFound && !strings.HasPrefix(message, "{") {
+			return fmt.Errorf("test hook %s is not registered (is ENABLE_TEST_HOOKS=true on a non-production service?)", endpoint)
+		}
+		return fmt.Errorf("test hook %s returned %d: %s", endpoint, resp.StatusCode, message)
+	}
+	return nil
+}
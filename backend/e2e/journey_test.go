@@ -0,0 +1,240 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gofund/sdk"
+)
+
+const (
+	contributionAmount int64 = 500000 // ₦5,000 in kobo
+	withdrawalAmount   int64 = 200000
+	testPassword             = "E2e-Passw0rd!"
+)
+
+// actor is a registered user with clients authenticated as them
+type actor struct {
+	user          *sdk.User
+	users         *sdk.UsersClient
+	goals         *sdk.GoalsClient
+	notifications *sdk.NotificationsClient
+}
+
+// journey walks the critical path: two users, a goal, a contribution, a proof, a vote,
+// a withdrawal and the notifications all of that produces
+type journey struct {
+	cfg Config
+
+	owner  *actor
+	backer *actor
+
+	goal         *sdk.Goal
+	contribution *sdk.Contribution
+	proof        *sdk.Proof
+}
+
+func newJourney(cfg Config) *journey {
+	return &journey{cfg: cfg}
+}
+
+// Run registers every step of the journey with the runner
+func (j *journey) Run(r *Runner) {
+	r.Step("register goal owner", func(ctx context.Context) (err error) {
+		j.owner, err = j.register(ctx, "owner")
+		return err
+	})
+	r.Step("register backer", func(ctx context.Context) (err error) {
+		j.backer, err = j.register(ctx, "backer")
+		return err
+	})
+	r.Step("verify owner email", j.verifyOwnerEmail)
+	r.Step("create goal with milestones", j.createGoal)
+	r.Step("create contribution intent", j.createContribution)
+	r.Step("complete payment with mock provider", func(ctx context.Context) error {
+		return completeMockPayment(ctx, j.cfg.PaymentsURL, j.backer.user.ID, j.goal.ID, j.contribution.ID, contributionAmount)
+	})
+	r.Eventually("contribution confirms", j.assertContributionConfirmed)
+	r.Eventually("goal progress updates", j.assertProgress)
+	r.Step("submit proof", j.submitProof)
+	r.Step("vote on proof", j.vote)
+	r.Step("request withdrawal", j.requestWithdrawal)
+	r.Eventually("owner is notified of contribution", func(ctx context.Context) error {
+		return j.assertNotified(ctx, j.owner, "contribution_confirmed")
+	})
+	r.Eventually("backer is notified of payment", func(ctx context.Context) error {
+		return j.assertNotified(ctx, j.backer, "payment_verified")
+	})
+}
+
+// register signs up a user namespaced by the run ID
+func (j *journey) register(ctx context.Context, role string) (*actor, error) {
+	name := fmt.Sprintf("e2e_%s_%s", role, j.cfg.RunID)
+
+	resp, err := sdk.NewUsersClient(j.cfg.UsersURL).Register(ctx, &sdk.RegisterRequest{
+		Email:     name + "@e2e.gofund.test",
+		Username:  name,
+		Password:  testPassword,
+		FirstName: "E2E",
+		LastName:  role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.User == nil || resp.User.ID == "" || resp.AccessToken == "" {
+		return nil, errors.New("register response is missing the user or access token")
+	}
+
+	opts := []sdk.Option{sdk.WithAccessToken(resp.AccessToken), sdk.WithUserID(resp.User.ID)}
+	return &actor{
+		user:          resp.User,
+		users:         sdk.NewUsersClient(j.cfg.UsersURL, opts...),
+		goals:         sdk.NewGoalsClient(j.cfg.GoalsURL, opts...),
+		notifications: sdk.NewNotificationsClient(j.cfg.NotificationsURL, opts...),
+	}, nil
+}
+
+func (j *journey) verifyOwnerEmail(ctx context.Context) error {
+	if err := verifyEmail(ctx, j.cfg.UsersURL, j.owner.user.ID); err != nil {
+		return err
+	}
+
+	profile, err := j.owner.users.GetProfile(ctx)
+	if err != nil {
+		return err
+	}
+	if !profile.EmailVerified {
+		return errors.New("profile still reports the email as unverified")
+	}
+	return nil
+}
+
+func (j *journey) createGoal(ctx context.Context) (err error) {
+	// Private, so smoke-test goals never show up in public listings
+	public := false
+	j.goal, err = j.owner.goals.CreateGoal(ctx, &sdk.CreateGoalRequest{
+		Title:                "E2E smoke " + j.cfg.RunID,
+		Description:          "Created by the e2e smoke suite",
+		TargetAmount:         contributionAmount * 2,
+		Currency:             "NGN",
+		DepositBankName:      "Test Bank",
+		DepositAccountNumber: "0123456789",
+		DepositAccountName:   "E2E Owner",
+		IsPublic:             &public,
+		Milestones: []sdk.CreateMilestoneRequest{
+			{Title: "First half", TargetAmount: contributionAmount, OrderIndex: 0},
+			{Title: "Second half", TargetAmount: contributionAmount, OrderIndex: 1},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if len(j.goal.Milestones) != 2 {
+		return fmt.Errorf("expected 2 milestones, got %d", len(j.goal.Milestones))
+	}
+	return nil
+}
+
+func (j *journey) createContribution(ctx context.Context) (err error) {
+	j.contribution, err = j.backer.goals.CreateContribution(ctx, &sdk.CreateContributionRequest{
+		GoalID: j.goal.ID,
+		Amount: contributionAmount,
+	})
+	if err != nil {
+		return err
+	}
+	if j.contribution.Status != "PENDING" {
+		return fmt.Errorf("expected a PENDING intent, got %s", j.contribution.Status)
+	}
+	return nil
+}
+
+func (j *journey) assertContributionConfirmed(ctx context.Context) error {
+	contribution, err := j.backer.goals.GetContribution(ctx, j.contribution.ID)
+	if err != nil {
+		return err
+	}
+	if contribution.Status != "CONFIRMED" {
+		return fmt.Errorf("contribution is %s, want CONFIRMED", contribution.Status)
+	}
+	return nil
+}
+
+func (j *journey) assertProgress(ctx context.Context) error {
+	progress, err := j.owner.goals.GetGoalProgress(ctx, j.goal.ID)
+	if err != nil {
+		return err
+	}
+	if progress.TotalContributions != contributionAmount {
+		return fmt.Errorf("total contributions is %d, want %d", progress.TotalContributions, contributionAmount)
+	}
+	if progress.ContributorCount != 1 {
+		return fmt.Errorf("contributor count is %d, want 1", progress.ContributorCount)
+	}
+	return nil
+}
+
+func (j *journey) submitProof(ctx context.Context) (err error) {
+	j.proof, err = j.owner.goals.CreateProof(ctx, &sdk.CreateProofRequest{
+		GoalID:      j.goal.ID,
+		MilestoneID: &j.goal.Milestones[0].ID,
+		Title:       "Receipt",
+		Description: "Proof submitted by the e2e smoke suite",
+		MediaURLs:   []string{"https://example.com/e2e-receipt.png"},
+	})
+	return err
+}
+
+func (j *journey) vote(ctx context.Context) error {
+	if _, err := j.backer.goals.CreateVote(ctx, &sdk.CreateVoteRequest{
+		ProofID:     j.proof.ID,
+		IsSatisfied: true,
+		Comment:     "Looks good",
+	}); err != nil {
+		return err
+	}
+
+	stats, err := j.backer.goals.GetVoteStats(ctx, j.proof.ID)
+	if err != nil {
+		return err
+	}
+	if stats.SatisfiedVotes != 1 {
+		return fmt.Errorf("satisfied votes is %d, want 1", stats.SatisfiedVotes)
+	}
+	return nil
+}
+
+func (j *journey) requestWithdrawal(ctx context.Context) error {
+	withdrawal, err := j.owner.goals.CreateWithdrawal(ctx, &sdk.CreateWithdrawalRequest{
+		GoalID: j.goal.ID,
+		Amount: withdrawalAmount,
+	})
+	if err != nil {
+		return err
+	}
+	// PROCESSING once the payout has been handed to payments-service
+	if withdrawal.Status != "PENDING" && withdrawal.Status != "PROCESSING" {
+		return fmt.Errorf("withdrawal is %s, want PENDING or PROCESSING", withdrawal.Status)
+	}
+	return nil
+}
+
+// assertNotified checks that the actor has a notification of the given type for the goal
+func (j *journey) assertNotified(ctx context.Context, a *actor, notificationType string) error {
+	page, err := a.notifications.ListNotifications(ctx, sdk.ListNotificationsQuery{
+		Type:     notificationType,
+		Page:     1,
+		PageSize: 50,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, notification := range page.Notifications {
+		if goalID, _ := notification.Data["goal_id"].(string); goalID == j.goal.ID {
+			return nil
+		}
+	}
+	return fmt.Errorf("no %s notification for goal %s yet", notificationType, j.goal.ID)
+}
@@ -0,0 +1,70 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Runner executes the journey's steps in order, each as a subtest. Each step depends on
+// the ones before it, so after the first failure the remaining steps are skipped.
+type Runner struct {
+	t      *testing.T
+	cfg    Config
+	failed string
+}
+
+// NewRunner creates a runner for t using the configured timeouts
+func NewRunner(t *testing.T, cfg Config) *Runner {
+	return &Runner{t: t, cfg: cfg}
+}
+
+// Step runs fn once with the per-step timeout
+func (r *Runner) Step(name string, fn func(ctx context.Context) error) {
+	r.run(name, r.cfg.StepTimeout, fn)
+}
+
+// Eventually retries fn until it succeeds or the eventual-consistency timeout expires.
+// Use it for assertions on state that is updated asynchronously through events.
+func (r *Runner) Eventually(name string, fn func(ctx context.Context) error) {
+	r.run(name, r.cfg.EventualTimeout, func(ctx context.Context) error {
+		return retry(ctx, r.cfg.PollInterval, fn)
+	})
+}
+
+func (r *Runner) run(name string, timeout time.Duration, fn func(ctx context.Context) error) {
+	r.t.Run(name, func(t *testing.T) {
+		if r.failed != "" {
+			t.Skipf("skipped after %q failed", r.failed)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		start := time.Now()
+		if err := fn(ctx); err != nil {
+			r.failed = name
+			t.Fatalf("failed after %s: %v", time.Since(start).Round(time.Millisecond), err)
+		}
+	})
+}
+
+// retry calls fn every interval until it succeeds, returning the last error once ctx expires
+func retry(ctx context.Context, interval time.Duration, fn func(ctx context.Context) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (gave up: %v)", err, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -226,6 +226,16 @@ table "goals" {
     type = boolean
     default = true
   }
+  column "close_on_target" {
+    null = false
+    type = boolean
+    default = false
+  }
+  column "fixed_contribution_amount" {
+    null = false
+    type = bigint
+    default = 0
+  }
   column "deposit_bank_name" {
     null = true
     type = varchar(100)
@@ -295,6 +305,15 @@ table "contributions" {
     type = varchar(20)
     default = "PENDING"
   }
+  column "is_anonymous" {
+    null = false
+    type = boolean
+    default = false
+  }
+  column "expires_at" {
+    null = true
+    type = timestamptz
+  }
   column "created_at" {
     null = false
     type = timestamptz
@@ -322,6 +341,10 @@ table "contributions" {
     columns = [column.payment_id]
   }
   
+  index "idx_contributions_expires_at" {
+    columns = [column.expires_at]
+  }
+  
   foreign_key "fk_contributions_goal" {
     columns     = [column.goal_id]
     ref_columns = [table.goals.column.id]
@@ -390,6 +413,40 @@ table "proofs" {
   }
 }
 
+table "goal_trending" {
+  schema = schema.public
+  column "goal_id" {
+    null = false
+    type = uuid
+  }
+  column "score" {
+    null = false
+    type = double_precision
+  }
+  column "rank" {
+    null = false
+    type = integer
+  }
+  column "computed_at" {
+    null = false
+    type = timestamptz
+  }
+  
+  primary_key {
+    columns = [column.goal_id]
+  }
+  
+  index "idx_goal_trending_rank" {
+    columns = [column.rank]
+  }
+  
+  foreign_key "fk_goal_trending_goal" {
+    columns     = [column.goal_id]
+    ref_columns = [table.goals.column.id]
+    on_delete   = CASCADE
+  }
+}
+
 table "votes" {
   schema = schema.public
   column "id" {
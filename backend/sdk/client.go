@@ -0,0 +1,284 @@
+// Package sdk provides typed Go clients for the public APIs of the GoFund services.
+//
+// The request and response types in this package mirror the services' DTOs so
+// that internal tools can call the services without importing their internal
+// packages.
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+)
+
+// APIError is returned when a service responds with a non-2xx status code
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    string
+	// DetailFields holds structured details, e.g. the amounts of a milestone over-allocation
+	DetailFields map[string]interface{}
+	// RequestID identifies the failed request in the service logs
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("gofund api error %d [%s]: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("gofund api error %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap maps the HTTP status code to one of the package sentinel errors so
+// callers can use errors.Is(err, sdk.ErrNotFound)
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity:
+		return ErrBadRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusConflict:
+		return ErrConflict
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServer
+	}
+	return nil
+}
+
+// Client is the base HTTP client shared by all service clients
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	accessToken string
+	userID      string
+
+	// Signed identity for direct service-to-service calls, see WithIdentity
+	roles          string
+	emailVerified  string
+	identitySecret string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient overrides the underlying HTTP client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAccessToken sets the bearer token sent with every request (used when calling through Nginx)
+func WithAccessToken(token string) Option {
+	return func(c *Client) {
+		c.accessToken = token
+	}
+}
+
+// WithUserID sets the X-User-ID header sent with every request. Services drop it
+// unless it is signed, so direct service-to-service calls should use WithIdentity.
+func WithUserID(userID string) Option {
+	return func(c *Client) {
+		c.userID = userID
+	}
+}
+
+// WithIdentity sends the caller's identity headers with every request, signed with
+// the secret services share with the gateway (IDENTITY_HEADER_SECRET), for direct
+// service-to-service calls that bypass Nginx. roles is comma-separated.
+func WithIdentity(secret, userID, roles string, emailVerified bool) Option {
+	return func(c *Client) {
+		c.identitySecret = secret
+		c.userID = userID
+		c.roles = roles
+		c.emailVerified = fmt.Sprint(emailVerified)
+	}
+}
+
+// NewClient creates a new base client for the given service URL
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// signIdentity signs identity headers the way users-service does for the gateway
+func signIdentity(secret, userID, roles, emailVerified string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(userID + "\n" + roles + "\n" + emailVerified))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// do sends a request with body (if non-nil) encoded as JSON and decodes a JSON response
+// into out (if non-nil)
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	if body == nil {
+		return c.send(ctx, method, path, query, "", nil, out)
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return c.send(ctx, method, path, query, "application/json", bytes.NewReader(payload), out)
+}
+
+// send sends a request with an already encoded body of contentType and decodes a JSON
+// response into out (if non-nil)
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader, out interface{}) error {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+	if c.userID != "" {
+		req.Header.Set("X-User-ID", c.userID)
+	}
+	if c.identitySecret != "" {
+		req.Header.Set("X-User-Roles", c.roles)
+		req.Header.Set("X-User-Email-Verified", c.emailVerified)
+		req.Header.Set("X-Internal-Identity-Signature", signIdentity(c.identitySecret, c.userID, c.roles, c.emailVerified))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return decodeAPIError(resp.StatusCode, respBody)
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
+
+// decodeAPIError understands the error shapes used across the services:
+// {"error": "..."}, {"error": "...", "details": "..."},
+// {"status": "error", "message": "...", "error": "..."} and the shared format
+// {"error", "code", "message", "details": {...}, "request_id"}
+func decodeAPIError(statusCode int, body []byte) error {
+	apiErr := &APIError{StatusCode: statusCode}
+
+	var payload struct {
+		Error     json.RawMessage `json:"error"`
+		Code      string          `json:"code"`
+		Message   string          `json:"message"`
+		Details   json.RawMessage `json:"details"`
+		RequestID string          `json:"request_id"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+		return apiErr
+	}
+
+	apiErr.Code = payload.Code
+	apiErr.Message = payload.Message
+	apiErr.RequestID = payload.RequestID
+
+	// "details" is either a plain string or an object of structured fields
+	if len(payload.Details) > 0 {
+		if err := json.Unmarshal(payload.Details, &apiErr.Details); err != nil {
+			apiErr.Details = ""
+			_ = json.Unmarshal(payload.Details, &apiErr.DetailFields)
+		}
+	}
+
+	// "error" is either a plain string or a structured {"code", "message"} object
+	var errString string
+	var errObject struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(payload.Error, &errString); err == nil && errString != "" {
+		if apiErr.Message == "" {
+			apiErr.Message = errString
+		} else if errString != apiErr.Message {
+			apiErr.Details = errString
+		}
+	} else if err := json.Unmarshal(payload.Error, &errObject); err == nil {
+		if errObject.Code != "" {
+			apiErr.Code = errObject.Code
+		}
+		if errObject.Message != "" {
+			apiErr.Message = errObject.Message
+		}
+	}
+
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(statusCode)
+	}
+
+	return apiErr
+}
+
+// pageQuery builds the pagination query string used by list endpoints
+func pageQuery(pageKey string, page int, sizeKey string, size int) url.Values {
+	query := url.Values{}
+	if page > 0 {
+		query.Set(pageKey, fmt.Sprintf("%d", page))
+	}
+	if size > 0 {
+		query.Set(sizeKey, fmt.Sprintf("%d", size))
+	}
+	return query
+}
@@ -0,0 +1,197 @@
+package sdk
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+const testUserID = "3f0c9a52-8d1e-4b7a-9c2f-6e5d4a3b2c1d"
+
+// recordedRequest is what the test server saw of a request
+type recordedRequest struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   []byte
+}
+
+// newTestServer answers every request with status and response, recording the last
+// request it saw
+func newTestServer(t *testing.T, status int, response string) (*httptest.Server, *recordedRequest) {
+	t.Helper()
+	recorded := &recordedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read request body: %v", err)
+		}
+		*recorded = recordedRequest{
+			method: r.Method,
+			path:   r.URL.EscapedPath(),
+			query:  r.URL.Query(),
+			header: r.Header.Clone(),
+			body:   body,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+	return server, recorded
+}
+
+func TestNewClientTrimsTrailingSlash(t *testing.T) {
+	server, recorded := newTestServer(t, http.StatusOK, `{"id": "g1"}`)
+	client := NewGoalsClient(server.URL + "/")
+
+	if _, err := client.GetGoal(context.Background(), "g1"); err != nil {
+		t.Fatalf("GetGoal: %v", err)
+	}
+	if recorded.path != "/api/v1/goals/g1" {
+		t.Errorf("path = %s, want /api/v1/goals/g1", recorded.path)
+	}
+	if got := recorded.header.Get("Authorization"); got != "" {
+		t.Errorf("Authorization = %q without an access token, want none", got)
+	}
+	if got := recorded.header.Get("X-User-ID"); got != "" {
+		t.Errorf("X-User-ID = %q without a user ID, want none", got)
+	}
+}
+
+func TestWithIdentitySignsTheIdentityHeaders(t *testing.T) {
+	server, recorded := newTestServer(t, http.StatusOK, `{"id": "g1"}`)
+	client := NewGoalsClient(server.URL, WithIdentity("identity-secret", testUserID, "user,admin", true))
+
+	if _, err := client.GetGoal(context.Background(), "g1"); err != nil {
+		t.Fatalf("GetGoal: %v", err)
+	}
+
+	// The payload users-service signs: user ID, roles and email verification, one per line
+	mac := hmac.New(sha256.New, []byte("identity-secret"))
+	mac.Write([]byte(testUserID + "\nuser,admin\ntrue"))
+	want := map[string]string{
+		"X-User-ID":                     testUserID,
+		"X-User-Roles":                  "user,admin",
+		"X-User-Email-Verified":         "true",
+		"X-Internal-Identity-Signature": hex.EncodeToString(mac.Sum(nil)),
+	}
+	for name, value := range want {
+		if got := recorded.header.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		sentinel error
+		want     APIError
+	}{
+		{
+			name:     "plain error",
+			status:   http.StatusNotFound,
+			response: `{"error": "goal not found"}`,
+			sentinel: ErrNotFound,
+			want:     APIError{StatusCode: http.StatusNotFound, Message: "goal not found"},
+		},
+		{
+			name:     "error with details",
+			status:   http.StatusBadRequest,
+			response: `{"error": "invalid request", "details": "amount is required"}`,
+			sentinel: ErrBadRequest,
+			want:     APIError{StatusCode: http.StatusBadRequest, Message: "invalid request", Details: "amount is required"},
+		},
+		{
+			name:     "status envelope",
+			status:   http.StatusForbidden,
+			response: `{"status": "error", "message": "not the goal owner", "error": "forbidden"}`,
+			sentinel: ErrForbidden,
+			want:     APIError{StatusCode: http.StatusForbidden, Message: "not the goal owner", Details: "forbidden"},
+		},
+		{
+			name:     "shared format",
+			status:   http.StatusConflict,
+			response: `{"error": "conflict", "code": "MILESTONE_OVER_ALLOCATED", "message": "milestones exceed the goal target", "details": {"excess": 5000}, "request_id": "req-1"}`,
+			sentinel: ErrConflict,
+			want: APIError{
+				StatusCode:   http.StatusConflict,
+				Code:         "MILESTONE_OVER_ALLOCATED",
+				Message:      "milestones exceed the goal target",
+				Details:      "conflict",
+				DetailFields: map[string]interface{}{"excess": float64(5000)},
+				RequestID:    "req-1",
+			},
+		},
+		{
+			name:     "error object",
+			status:   http.StatusUnprocessableEntity,
+			response: `{"error": {"code": "INVALID_AMOUNT", "message": "amount must be positive"}}`,
+			sentinel: ErrBadRequest,
+			want:     APIError{StatusCode: http.StatusUnprocessableEntity, Code: "INVALID_AMOUNT", Message: "amount must be positive"},
+		},
+		{
+			name:     "not JSON",
+			status:   http.StatusBadGateway,
+			response: "upstream unavailable\n",
+			sentinel: ErrServer,
+			want:     APIError{StatusCode: http.StatusBadGateway, Message: "upstream unavailable"},
+		},
+		{
+			name:     "empty object",
+			status:   http.StatusTooManyRequests,
+			response: `{}`,
+			sentinel: ErrRateLimited,
+			want:     APIError{StatusCode: http.StatusTooManyRequests, Message: "Too Many Requests"},
+		},
+		{
+			name:     "unauthorized",
+			status:   http.StatusUnauthorized,
+			response: `{"error": "token expired"}`,
+			sentinel: ErrUnauthorized,
+			want:     APIError{StatusCode: http.StatusUnauthorized, Message: "token expired"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newTestServer(t, tt.status, tt.response)
+			client := NewGoalsClient(server.URL)
+
+			goal, err := client.GetGoal(context.Background(), "g1")
+			if goal != nil {
+				t.Errorf("GetGoal returned %+v with an error response", goal)
+			}
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("err = %v, want %v", err, tt.sentinel)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %T, want *APIError", err)
+			}
+			if !reflect.DeepEqual(*apiErr, tt.want) {
+				t.Errorf("APIError = %+v, want %+v", *apiErr, tt.want)
+			}
+		})
+	}
+}
+
+func TestUndecodableResponse(t *testing.T) {
+	server, _ := newTestServer(t, http.StatusOK, `{"id": `)
+	client := NewGoalsClient(server.URL)
+
+	if _, err := client.GetGoal(context.Background(), "g1"); err == nil {
+		t.Fatal("GetGoal decoded a truncated response")
+	}
+}
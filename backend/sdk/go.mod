@@ -0,0 +1,3 @@
+module github.com/gofund/sdk
+
+go 1.24.0
@@ -0,0 +1,1602 @@
+package sdk
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Goal mirrors models.Goal as returned by the goals service
+type Goal struct {
+	ID                        string     `json:"id"`
+	OwnerID                   string     `json:"owner_id"`
+	Title                     string     `json:"title"`
+	Slug                      string     `json:"slug,omitempty"`
+	Description               string     `json:"description"`
+	TargetAmount              int64      `json:"target_amount"`
+	Currency                  string     `json:"currency"`
+	Deadline                  *time.Time `json:"deadline,omitempty"`
+	Status                    string     `json:"status"`
+	IsPublic                  bool       `json:"is_public"`
+	Visibility                string     `json:"visibility"`
+	CloseOnTarget             bool       `json:"close_on_target"`
+	FixedContributionAmount   int64      `json:"fixed_contribution_amount"`
+	RequireProofForWithdrawal bool       `json:"require_proof_for_withdrawal"`
+	WeightedVoting            bool       `json:"weighted_voting"`
+	RequiredApprovals         int        `json:"required_approvals"`
+	// FeeMode is "absorb" (the platform fee comes out of each contribution) or "add_on"
+	// (contributors pay it on top)
+	FeeMode string `json:"fee_mode"`
+	// CurrentAmount is confirmed contributions net of completed refunds; ContributorCount
+	// counts contributors who still have money in the goal
+	CurrentAmount        int64       `json:"current_amount"`
+	ContributorCount     int64       `json:"contributor_count"`
+	SuspendedAt          *time.Time  `json:"suspended_at,omitempty"`
+	SuspensionReason     string      `json:"suspension_reason,omitempty"`
+	DepositBankName      string      `json:"deposit_bank_name,omitempty"`
+	DepositAccountNumber string      `json:"deposit_account_number,omitempty"`
+	DepositAccountName   string      `json:"deposit_account_name,omitempty"`
+	CreatedAt            time.Time   `json:"created_at"`
+	UpdatedAt            time.Time   `json:"updated_at"`
+	Milestones           []Milestone `json:"milestones,omitempty"`
+}
+
+// Milestone mirrors models.Milestone
+type Milestone struct {
+	ID                 string     `json:"id"`
+	GoalID             string     `json:"goal_id"`
+	Title              string     `json:"title"`
+	Description        string     `json:"description"`
+	TargetAmount       int64      `json:"target_amount"`
+	OrderIndex         int        `json:"order_index"`
+	IsRecurring        bool       `json:"is_recurring"`
+	RecurrenceType     *string    `json:"recurrence_type,omitempty"`
+	RecurrenceInterval int        `json:"recurrence_interval,omitempty"`
+	NextDueDate        *time.Time `json:"next_due_date,omitempty"`
+	ParentMilestoneID  *string    `json:"parent_milestone_id,omitempty"`
+	Status             string     `json:"status"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// Contribution mirrors models.Contribution
+type Contribution struct {
+	ID          string     `json:"id"`
+	GoalID      string     `json:"goal_id"`
+	MilestoneID *string    `json:"milestone_id,omitempty"`
+	UserID      string     `json:"user_id"`
+	PaymentID   *string    `json:"payment_id,omitempty"`
+	Amount      int64      `json:"amount"`
+	Currency    string     `json:"currency"`
+	Status      string     `json:"status"`
+	IsAnonymous bool       `json:"is_anonymous"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// Platform fee: FeeMode is empty for contributions made before fees, NetAmount is what
+	// reaches the goal
+	FeeMode   string `json:"fee_mode,omitempty"`
+	FeeAmount int64  `json:"fee_amount"`
+	NetAmount int64  `json:"net_amount"`
+	// ChargeAmount and WillDiscloseIdentity are only set on the CreateContribution
+	// response; ChargeAmount is what checkout will charge
+	ChargeAmount         int64 `json:"charge_amount,omitempty"`
+	WillDiscloseIdentity bool  `json:"will_disclose_identity,omitempty"`
+	// RefundStatus is only set by ListMyContributions, once a refund has reached the contribution
+	RefundStatus *string `json:"refund_status,omitempty"`
+}
+
+// RecurringContribution mirrors models.RecurringContribution
+type RecurringContribution struct {
+	ID                string     `json:"id"`
+	UserID            string     `json:"user_id"`
+	GoalID            string     `json:"goal_id"`
+	Amount            int64      `json:"amount"`
+	Currency          string     `json:"currency"`
+	Interval          string     `json:"interval"`
+	IsAnonymous       bool       `json:"is_anonymous"`
+	Status            string     `json:"status"`
+	NextChargeDate    time.Time  `json:"next_charge_date"`
+	FailedAttempts    int        `json:"failed_attempts"`
+	RetryAt           *time.Time `json:"retry_at,omitempty"`
+	LastFailureReason string     `json:"last_failure_reason,omitempty"`
+	LastChargedAt     *time.Time `json:"last_charged_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// Withdrawal mirrors models.Withdrawal
+type Withdrawal struct {
+	ID                  string     `json:"id"`
+	GoalID              string     `json:"goal_id"`
+	MilestoneID         *string    `json:"milestone_id,omitempty"`
+	OwnerID             string     `json:"owner_id"`
+	RequestedBy         *string    `json:"requested_by,omitempty"`
+	Amount              int64      `json:"amount"`
+	Currency            string     `json:"currency"`
+	BankName            string     `json:"bank_name"`
+	BankCode            string     `json:"bank_code,omitempty"`
+	AccountNumber       string     `json:"account_number"`
+	AccountName         string     `json:"account_name"`
+	Status              string     `json:"status"`
+	TransferReference   string     `json:"transfer_reference,omitempty"`
+	FailureReason       string     `json:"failure_reason,omitempty"`
+	LedgerTransactionID *string    `json:"ledger_transaction_id,omitempty"`
+	RequestedAt         time.Time  `json:"requested_at"`
+	CompletedAt         *time.Time `json:"completed_at,omitempty"`
+	// Approvals is only set on withdrawals from goals that require more than one approval
+	Approvals []WithdrawalApproval `json:"approvals,omitempty"`
+}
+
+// ExportRow is one row of a goal export requested as JSON. Amount is in major units
+// (e.g. "5000.50"), as in the CSV export.
+type ExportRow struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Date        string `json:"date"`
+	Contributor string `json:"contributor"`
+	Amount      string `json:"amount"`
+	Currency    string `json:"currency"`
+	Status      string `json:"status"`
+	Reference   string `json:"reference"`
+}
+
+// WithdrawalApproval mirrors models.WithdrawalApproval
+type WithdrawalApproval struct {
+	ID           string    `json:"id"`
+	WithdrawalID string    `json:"withdrawal_id"`
+	ApproverID   string    `json:"approver_id"`
+	ApprovedAt   time.Time `json:"approved_at"`
+}
+
+// ProofMedia mirrors models.ProofMedia
+type ProofMedia struct {
+	ID          string    `json:"id"`
+	UploadedBy  string    `json:"uploaded_by"`
+	ProofID     *string   `json:"proof_id,omitempty"`
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WithdrawalStatusEvent mirrors models.WithdrawalStatusEvent
+type WithdrawalStatusEvent struct {
+	ID           string    `json:"id"`
+	WithdrawalID string    `json:"withdrawal_id"`
+	FromStatus   string    `json:"from_status,omitempty"`
+	Status       string    `json:"status"`
+	Reason       string    `json:"reason,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// GoalCollaborator mirrors models.GoalCollaborator
+type GoalCollaborator struct {
+	ID        string    `json:"id"`
+	GoalID    string    `json:"goal_id"`
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	InvitedBy string    `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Proof mirrors models.Proof
+type Proof struct {
+	ID          string     `json:"id"`
+	GoalID      string     `json:"goal_id"`
+	MilestoneID *string    `json:"milestone_id,omitempty"`
+	SubmittedBy string     `json:"submitted_by"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	MediaURLs   []string   `json:"media_urls,omitempty"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+	Status      string     `json:"status"`
+	VerifiedAt  *time.Time `json:"verified_at,omitempty"`
+	RejectedAt  *time.Time `json:"rejected_at,omitempty"`
+	Votes       []Vote     `json:"votes,omitempty"`
+}
+
+// Comment mirrors models.Comment
+type Comment struct {
+	ID        string     `json:"id"`
+	GoalID    string     `json:"goal_id"`
+	UserID    string     `json:"user_id"`
+	ParentID  *string    `json:"parent_id,omitempty"`
+	Body      string     `json:"body"`
+	IsDeleted bool       `json:"is_deleted"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// GoalUpdate mirrors models.GoalUpdate
+type GoalUpdate struct {
+	ID        string    `json:"id"`
+	GoalID    string    `json:"goal_id"`
+	AuthorID  string    `json:"author_id"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	MediaURLs []string  `json:"media_urls,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Vote mirrors models.Vote
+type Vote struct {
+	ID           string    `json:"id"`
+	ProofID      string    `json:"proof_id"`
+	VoterID      string    `json:"voter_id"`
+	IsSatisfied  bool      `json:"is_satisfied"`
+	Comment      string    `json:"comment,omitempty"`
+	VotedAt      time.Time `json:"voted_at"`
+	ShowIdentity bool      `json:"show_identity"`
+}
+
+// ProofVote mirrors dto.ProofVote
+type ProofVote struct {
+	ID          string
+	VoterID     *string
+	IsSatisfied bool
+	Comment     string
+	VotedAt     time.Time
+}
+
+// Refund mirrors models.Refund
+type Refund struct {
+	ID                string                 `json:"id"`
+	GoalID            string                 `json:"goal_id"`
+	InitiatedBy       string                 `json:"initiated_by"`
+	RefundPercentage  float64                `json:"refund_percentage"`
+	TotalRefundAmount int64                  `json:"total_refund_amount"`
+	Currency          string                 `json:"currency"`
+	Reason            string                 `json:"reason,omitempty"`
+	Status            string                 `json:"status"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt         time.Time              `json:"created_at"`
+	CompletedAt       *time.Time             `json:"completed_at,omitempty"`
+	Disbursements     []RefundDisbursement   `json:"disbursements,omitempty"`
+}
+
+// RefundDisbursement mirrors models.RefundDisbursement
+type RefundDisbursement struct {
+	ID                      string     `json:"id"`
+	RefundID                string     `json:"refund_id"`
+	ContributionID          string     `json:"contribution_id"`
+	UserID                  string     `json:"user_id"`
+	Amount                  int64      `json:"amount"`
+	Currency                string     `json:"currency"`
+	SettlementBankName      string     `json:"settlement_bank_name,omitempty"`
+	SettlementAccountNumber string     `json:"settlement_account_number,omitempty"`
+	SettlementAccountName   string     `json:"settlement_account_name,omitempty"`
+	Status                  string     `json:"status"`
+	LedgerTransactionID     *string    `json:"ledger_transaction_id,omitempty"`
+	CreatedAt               time.Time  `json:"created_at"`
+	CompletedAt             *time.Time `json:"completed_at,omitempty"`
+}
+
+// RefundRequest mirrors models.RefundRequest
+type RefundRequest struct {
+	ID             string     `json:"id"`
+	GoalID         string     `json:"goal_id"`
+	ContributionID string     `json:"contribution_id"`
+	UserID         string     `json:"user_id"`
+	Amount         int64      `json:"amount"`
+	Currency       string     `json:"currency"`
+	Reason         string     `json:"reason"`
+	Status         string     `json:"status"`
+	AutoApproved   bool       `json:"auto_approved"`
+	ReviewedBy     *string    `json:"reviewed_by,omitempty"`
+	ReviewNote     string     `json:"review_note,omitempty"`
+	RefundID       *string    `json:"refund_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// RefundPlan mirrors dto.RefundPlan
+type RefundPlan struct {
+	GoalID                    string                `json:"goal_id"`
+	RefundPercentage          float64               `json:"refund_percentage"`
+	Scope                     string                `json:"scope"`
+	Currency                  string                `json:"currency"`
+	TotalContributed          int64                 `json:"total_contributed"`
+	TotalFees                 int64                 `json:"total_fees"`
+	TotalWithdrawn            int64                 `json:"total_withdrawn"`
+	TotalReserved             int64                 `json:"total_reserved"`
+	TotalRefunded             int64                 `json:"total_refunded"`
+	AvailableBalance          int64                 `json:"available_balance"`
+	TotalRefundAmount         int64                 `json:"total_refund_amount"`
+	MissingSettlementAccounts int                   `json:"missing_settlement_accounts"`
+	Disbursements             []PlannedDisbursement `json:"disbursements"`
+}
+
+// PlannedDisbursement mirrors dto.PlannedDisbursement
+type PlannedDisbursement struct {
+	ContributionID       string `json:"contribution_id"`
+	UserID               string `json:"user_id"`
+	ContributionAmount   int64  `json:"contribution_amount"`
+	AlreadyRefunded      int64  `json:"already_refunded"`
+	Amount               int64  `json:"amount"`
+	Currency             string `json:"currency"`
+	HasSettlementAccount bool   `json:"has_settlement_account"`
+}
+
+// CreateGoalRequest mirrors dto.CreateGoalRequest
+type CreateGoalRequest struct {
+	Title        string
+	Description  string
+	TargetAmount int64
+	Currency     string
+	Deadline     *time.Time
+	// DepositBankName, DepositAccountNumber and DepositAccountName are the account
+	// withdrawals are paid into
+	DepositBankName      string
+	DepositAccountNumber string
+	DepositAccountName   string
+	Milestones           []CreateMilestoneRequest
+	// Visibility is PUBLIC (the default), UNLISTED or PRIVATE; IsPublic false means PRIVATE
+	Visibility    string
+	IsPublic      *bool
+	CloseOnTarget bool
+	// FixedContributionAmount makes every contribution this exact amount; zero allows any amount
+	FixedContributionAmount int64
+	// RequireProofForWithdrawal only allows withdrawals backed by a verified proof
+	RequireProofForWithdrawal bool
+	// WeightedVoting weights proof votes by each voter's confirmed contributions
+	WeightedVoting bool
+	// FeeMode is "absorb" (the default) or "add_on"
+	FeeMode string
+}
+
+// CreateMilestoneRequest mirrors dto.CreateMilestoneRequest
+type CreateMilestoneRequest struct {
+	Title       string
+	Description string
+	// TargetAmount left at zero takes an even share of the goal's unallocated target
+	TargetAmount       int64
+	OrderIndex         int
+	IsRecurring        bool
+	RecurrenceType     *string
+	RecurrenceInterval int
+	NextDueDate        *time.Time
+}
+
+// UpdateMilestoneRequest mirrors dto.UpdateMilestoneRequest
+type UpdateMilestoneRequest struct {
+	Title        *string
+	Description  *string
+	TargetAmount *int64
+	// ApplyToFuture also edits the later, not yet completed instances of a recurring milestone
+	ApplyToFuture bool
+}
+
+// UpdateGoalRequest mirrors dto.UpdateGoalRequest
+type UpdateGoalRequest struct {
+	Title       *string
+	Description *string
+	// TargetAmount may only be raised once the goal has confirmed contributions
+	TargetAmount *int64
+	// Currency may only change while the goal has no contributions
+	Currency *string
+	// A new deposit account on a goal with confirmed contributions must be held in the
+	// owner's registered name
+	DepositBankName      *string
+	DepositAccountNumber *string
+	DepositAccountName   *string
+	// Visibility takes precedence over IsPublic when both are set
+	Visibility    *string
+	IsPublic      *bool
+	CloseOnTarget *bool
+	// FixedContributionAmount set to zero lifts the restriction
+	FixedContributionAmount   *int64
+	RequireProofForWithdrawal *bool
+	WeightedVoting            *bool
+	// FeeMode applies to contributions made from now on
+	FeeMode *string
+	// RequiredApprovals may not exceed the owner plus the goal's collaborators
+	RequiredApprovals *int
+}
+
+// AddCollaboratorRequest mirrors dto.AddCollaboratorRequest
+type AddCollaboratorRequest struct {
+	UserID string
+	// Role is "owner" (may request and approve withdrawals) or "approver"
+	Role string
+}
+
+// CreateContributionRequest mirrors dto.CreateContributionRequest
+type CreateContributionRequest struct {
+	GoalID      string
+	MilestoneID *string
+	Amount      int64
+	// Currency, when set, must be the goal's currency
+	Currency    string
+	IsAnonymous bool
+	// AllowMultiples accepts an integer multiple of a goal's fixed contribution amount
+	AllowMultiples bool
+	// UseActiveMilestone assigns a contribution without a MilestoneID to the goal's active milestone
+	UseActiveMilestone bool
+	// InviteToken lets the holder of an invite link contribute to a private goal
+	InviteToken string
+}
+
+// CreateRecurringContributionRequest mirrors dto.CreateRecurringContributionRequest.
+// Interval is WEEKLY or MONTHLY; a zero Amount reuses the contribution's amount.
+type CreateRecurringContributionRequest struct {
+	ContributionID string
+	Interval       string
+	Amount         int64
+}
+
+// UpdateRecurringContributionRequest mirrors dto.UpdateRecurringContributionRequest.
+// Status is ACTIVE to resume or PAUSED to pause.
+type UpdateRecurringContributionRequest struct {
+	Amount   *int64
+	Interval *string
+	Status   *string
+}
+
+// CreateWithdrawalRequest mirrors dto.CreateWithdrawalRequest
+type CreateWithdrawalRequest struct {
+	GoalID        string
+	MilestoneID   *string
+	Amount        int64
+	BankName      string
+	BankCode      string
+	AccountNumber string
+	AccountName   string
+}
+
+// CreateProofRequest mirrors dto.CreateProofRequest
+type CreateProofRequest struct {
+	GoalID      string
+	MilestoneID *string
+	Title       string
+	Description string
+	MediaURLs   []string
+}
+
+// CreateVoteRequest mirrors dto.CreateVoteRequest
+type CreateVoteRequest struct {
+	ProofID      string
+	IsSatisfied  bool
+	Comment      string
+	ShowIdentity bool
+}
+
+// CreateCommentRequest mirrors dto.CreateCommentRequest
+type CreateCommentRequest struct {
+	Body     string
+	ParentID *string
+}
+
+// CreateGoalUpdateRequest mirrors dto.CreateGoalUpdateRequest
+type CreateGoalUpdateRequest struct {
+	Title     string
+	Body      string
+	MediaURLs []string
+}
+
+// InitiateRefundRequest mirrors dto.InitiateRefundRequest
+type InitiateRefundRequest struct {
+	GoalID           string  `json:"goal_id"`
+	RefundPercentage float64 `json:"refund_percentage"`
+	Reason           string  `json:"reason"`
+	// ContributionIDs and UserIDs, when set, limit the refund to those contributions and
+	// to the contributions of those users
+	ContributionIDs []string `json:"contribution_ids,omitempty"`
+	UserIDs         []string `json:"user_ids,omitempty"`
+}
+
+// GoalProgress mirrors dto.GoalProgress
+type GoalProgress struct {
+	Goal               Goal
+	TotalContributions int64
+	TotalWithdrawals   int64
+	// TotalFees is the platform fees taken out of contributions; they are not available
+	// to withdraw
+	TotalFees        int64
+	AvailableBalance int64
+	ProgressPercent  float64
+	ContributorCount int64
+	Milestones       []MilestoneProgress
+	// ActiveMilestoneID is the milestone currently being funded, nil when there is none
+	ActiveMilestoneID *string
+	// GoalWithdrawalBlockedReason is the error code a goal-level withdrawal would be
+	// refused with (e.g. "proof_required"); empty when it is allowed
+	GoalWithdrawalBlockedReason string
+}
+
+// GoalStats mirrors dto.GoalStats
+type GoalStats struct {
+	GoalID        string                 `json:"goal_id"`
+	Currency      string                 `json:"currency"`
+	Days          int                    `json:"days"`
+	Daily         []GoalDailyStats       `json:"daily"`
+	AverageAmount float64                `json:"average_amount"`
+	MedianAmount  float64                `json:"median_amount"`
+	Contributions GoalContributionCounts `json:"contributions"`
+	Refunds       GoalRefundStats        `json:"refunds"`
+	ComputedAt    time.Time              `json:"computed_at"`
+}
+
+// GoalDailyStats mirrors dto.GoalDailyStats
+type GoalDailyStats struct {
+	Date              string `json:"date"` // YYYY-MM-DD, UTC
+	Count             int64  `json:"count"`
+	Amount            int64  `json:"amount"`
+	NewContributors   int64  `json:"new_contributors"`
+	TotalContributors int64  `json:"total_contributors"`
+}
+
+// GoalContributionCounts mirrors dto.GoalContributionCounts
+type GoalContributionCounts struct {
+	Pending   int64 `json:"pending"`
+	Confirmed int64 `json:"confirmed"`
+	Failed    int64 `json:"failed"`
+	Expired   int64 `json:"expired"`
+	Refunded  int64 `json:"refunded"`
+}
+
+// GoalRefundStats mirrors dto.GoalRefundStats
+type GoalRefundStats struct {
+	Count          int64 `json:"count"`
+	RefundedAmount int64 `json:"refunded_amount"`
+	PendingAmount  int64 `json:"pending_amount"`
+	FailedAmount   int64 `json:"failed_amount"`
+}
+
+// PlatformStats mirrors dto.PlatformStats
+type PlatformStats struct {
+	Raised       []PlatformRaised `json:"raised"`
+	GoalsFunded  int64            `json:"goals_funded"`
+	ActiveGoals  int64            `json:"active_goals"`
+	Contributors int64            `json:"contributors"`
+	ComputedAt   time.Time        `json:"computed_at"`
+}
+
+// PlatformRaised mirrors dto.PlatformRaised
+type PlatformRaised struct {
+	Currency    string  `json:"currency"`
+	Amount      int64   `json:"amount"`
+	AmountMajor float64 `json:"amount_major"`
+}
+
+// MilestoneProgress mirrors dto.MilestoneProgress
+type MilestoneProgress struct {
+	Milestone       Milestone
+	CurrentAmount   int64
+	ProgressPercent float64
+	// WithdrawalBlockedReason is the error code a withdrawal against the milestone would
+	// be refused with; empty when it is allowed
+	WithdrawalBlockedReason string
+}
+
+// VoteStats mirrors dto.VoteStats
+type VoteStats struct {
+	TotalVotes             int64
+	SatisfiedVotes         int64
+	UnsatisfiedVotes       int64
+	SatisfactionRate       float64
+	RequiredSatisfiedVotes int64
+	WeightedVoting         bool
+	// Weighted stats: amounts are the voters' confirmed contributions to the goal
+	SatisfiedAmount          int64
+	UnsatisfiedAmount        int64
+	TotalContributions       int64
+	WeightedSatisfactionRate float64
+	RequiredWeightedRate     float64
+	Status                   string
+	VerifiedAt               *time.Time
+	RejectedAt               *time.Time
+}
+
+// PublicGoalsPage is the response of ListPublicGoals, ListAllGoals and ListWatchedGoals
+type PublicGoalsPage struct {
+	Data  []Goal `json:"data"`
+	Total int64  `json:"total"`
+	Page  int    `json:"page"`
+	Size  int    `json:"size"`
+}
+
+// GoalSearchPage mirrors dto.GoalSearchResponse
+type GoalSearchPage struct {
+	Data  []GoalSearchResult `json:"data"`
+	Total int64              `json:"total"`
+	Page  int                `json:"page"`
+	Size  int                `json:"size"`
+}
+
+// GoalSearchResult mirrors dto.GoalSearchResult. TitleHighlight and Snippet are
+// HTML-escaped, with the matched words wrapped in <mark>.
+type GoalSearchResult struct {
+	Goal
+	Score          float64 `json:"score"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+// AuditLogEntry mirrors models.AuditLog
+type AuditLogEntry struct {
+	ID         string                 `json:"id"`
+	GoalID     string                 `json:"goal_id"`
+	ActorID    string                 `json:"actor_id"`
+	Action     string                 `json:"action"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	Before     map[string]interface{} `json:"before,omitempty"`
+	After      map[string]interface{} `json:"after,omitempty"`
+	IP         string                 `json:"ip,omitempty"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	CreatedAt  string                 `json:"created_at"`
+}
+
+// AuditLogPage mirrors dto.AuditLogResponse
+type AuditLogPage struct {
+	Data  []AuditLogEntry `json:"data"`
+	Total int64           `json:"total"`
+	Page  int             `json:"page"`
+	Size  int             `json:"size"`
+}
+
+// GoalInvite mirrors dto.GoalInvite
+type GoalInvite struct {
+	GoalID    string    `json:"goal_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GoalShareMeta mirrors dto.GoalShareMeta
+type GoalShareMeta struct {
+	GoalID          string  `json:"goal_id"`
+	Slug            string  `json:"slug,omitempty"`
+	Title           string  `json:"title"`
+	Description     string  `json:"description"`
+	CurrentAmount   int64   `json:"current_amount"`
+	TargetAmount    int64   `json:"target_amount"`
+	Currency        string  `json:"currency"`
+	ProgressPercent float64 `json:"progress_percent"`
+	ImageURL        string  `json:"image_url,omitempty"`
+}
+
+// TrendingGoal mirrors dto.TrendingGoal
+type TrendingGoal struct {
+	Goal               Goal
+	Score              float64
+	Rank               int
+	TotalContributions int64
+	ContributorCount   int64
+	ProgressPercent    float64
+}
+
+// TrendingGoals mirrors dto.TrendingGoals. ComputedAt is nil when the service
+// fell back to the most recently active goals.
+type TrendingGoals struct {
+	Goals      []TrendingGoal
+	ComputedAt *time.Time
+}
+
+// ContributionFeedItem mirrors dto.ContributionFeedItem
+type ContributionFeedItem struct {
+	ID          string
+	UserID      string
+	DisplayName string
+	Amount      int64
+	Currency    string
+	CreatedAt   time.Time
+}
+
+// ContributionFeed mirrors dto.ContributionFeed
+type ContributionFeed struct {
+	Items    []ContributionFeedItem
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// CommentItem mirrors dto.CommentItem
+type CommentItem struct {
+	ID          string
+	GoalID      string
+	UserID      *string
+	DisplayName string
+	ParentID    *string
+	Body        string
+	IsDeleted   bool
+	CreatedAt   time.Time
+	Replies     []CommentItem
+}
+
+// CommentThreads mirrors dto.CommentThreads
+type CommentThreads struct {
+	Items    []CommentItem
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// GoalUpdatePage mirrors dto.GoalUpdatePage
+type GoalUpdatePage struct {
+	Items    []GoalUpdate
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// OwnerContributionItem mirrors dto.OwnerContributionItem
+type OwnerContributionItem struct {
+	ID                string
+	UserID            string
+	DisplayName       string
+	Name              string
+	Email             string
+	Amount            int64
+	Currency          string
+	IsAnonymous       bool
+	IdentityDisclosed bool
+	CreatedAt         time.Time
+}
+
+// OwnerContributionList mirrors dto.OwnerContributionList
+type OwnerContributionList struct {
+	Items    []OwnerContributionItem
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// WithdrawalHistoryItem mirrors dto.WithdrawalHistoryItem
+type WithdrawalHistoryItem struct {
+	ID                 string
+	MilestoneID        *string
+	Amount             int64
+	Currency           string
+	Status             string
+	BankName           string
+	AccountName        string
+	AccountNumberLast4 string
+	RequestedBy        *string
+	TransferReference  string
+	FailureReason      string
+	RequestedAt        time.Time
+	CompletedAt        *time.Time
+}
+
+// WithdrawalHistory mirrors dto.WithdrawalHistory
+type WithdrawalHistory struct {
+	Items    []WithdrawalHistoryItem
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// WithdrawalDetail mirrors dto.WithdrawalDetail
+type WithdrawalDetail struct {
+	WithdrawalHistoryItem
+	StatusHistory []WithdrawalStatusEvent
+}
+
+// MyGoalsPage is the response of ListMyGoals
+type MyGoalsPage struct {
+	Goals []Goal `json:"goals"`
+	Total int64  `json:"total"`
+	Page  int    `json:"page"`
+	Limit int    `json:"limit"`
+}
+
+// CompletedMilestone is the response of CompleteMilestone
+type CompletedMilestone struct {
+	Completed *Milestone `json:"completed"`
+	Next      *Milestone `json:"next"`
+}
+
+// MilestoneAllocation mirrors dto.MilestoneAllocation
+type MilestoneAllocation struct {
+	TargetAmount      int64
+	AllocatedAmount   int64
+	UnallocatedAmount int64
+}
+
+// CreatedMilestone is the response of CreateMilestone
+type CreatedMilestone struct {
+	Milestone  *Milestone          `json:"milestone"`
+	Allocation MilestoneAllocation `json:"allocation"`
+}
+
+// UpdatedMilestone is the response of UpdateMilestone
+type UpdatedMilestone struct {
+	Milestone  Milestone           `json:"milestone"`
+	Propagated []Milestone         `json:"propagated,omitempty"`
+	Allocation MilestoneAllocation `json:"allocation"`
+}
+
+// DeletedMilestone is the response of DeleteMilestone
+type DeletedMilestone struct {
+	Message                 string `json:"message"`
+	ReassignedContributions int64  `json:"reassigned_contributions"`
+}
+
+// ContributionReceipt mirrors dto.ContributionReceipt
+type ContributionReceipt struct {
+	ContributionID   string    `json:"contribution_id"`
+	GoalTitle        string    `json:"goal_title"`
+	Amount           int64     `json:"amount"`
+	Currency         string    `json:"currency"`
+	PaymentReference string    `json:"payment_reference"`
+	PaidAt           time.Time `json:"paid_at"`
+	ContributorName  string    `json:"contributor_name"`
+}
+
+// ContributionRefund mirrors dto.ContributionRefund
+type ContributionRefund struct {
+	ContributionID     string     `json:"contribution_id"`
+	RefundID           string     `json:"refund_id"`
+	DisbursementID     string     `json:"disbursement_id"`
+	Status             string     `json:"status"`
+	Amount             int64      `json:"amount"`
+	Currency           string     `json:"currency"`
+	BankName           string     `json:"bank_name,omitempty"`
+	AccountNumberLast4 string     `json:"account_number_last4,omitempty"`
+	FailureReason      string     `json:"failure_reason,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty"`
+}
+
+// BalanceDiscrepancy mirrors dto.BalanceDiscrepancy
+type BalanceDiscrepancy struct {
+	Kind           string   `json:"kind"`
+	ContributionID *string  `json:"contribution_id,omitempty"`
+	GoalsAmount    int64    `json:"goals_amount"`
+	LedgerAmount   int64    `json:"ledger_amount"`
+	LedgerEntryIDs []string `json:"ledger_entry_ids"`
+}
+
+// GoalBalanceCheck mirrors dto.GoalBalanceCheck
+type GoalBalanceCheck struct {
+	GoalID                string               `json:"goal_id"`
+	Currency              string               `json:"currency"`
+	GoalsBalance          int64                `json:"goals_balance"`
+	LedgerBalance         int64                `json:"ledger_balance"`
+	LedgerSnapshotBalance *int64               `json:"ledger_snapshot_balance"`
+	Delta                 int64                `json:"delta"`
+	Consistent            bool                 `json:"consistent"`
+	Discrepancies         []BalanceDiscrepancy `json:"discrepancies"`
+	CheckedAt             time.Time            `json:"checked_at"`
+}
+
+// GoalsClient is a typed client for the goals service
+type GoalsClient struct {
+	*Client
+}
+
+// NewGoalsClient creates a new goals service client
+func NewGoalsClient(baseURL string, opts ...Option) *GoalsClient {
+	return &GoalsClient{Client: NewClient(baseURL, opts...)}
+}
+
+// Public goal list orderings for ListPublicGoals
+const (
+	GoalSortNewest      = "newest"
+	GoalSortMostFunded  = "most_funded"
+	GoalSortMostPopular = "most_popular"
+	GoalSortEndingSoon  = "ending_soon"
+)
+
+// ListPublicGoals calls GET /api/v1/goals. An empty sort lists the newest goals first.
+func (gc *GoalsClient) ListPublicGoals(ctx context.Context, sort string, page, pageSize int) (*PublicGoalsPage, error) {
+	var resp PublicGoalsPage
+	query := pageQuery("page", page, "pageSize", pageSize)
+	if sort != "" {
+		query.Set("sort", sort)
+	}
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SearchGoals calls GET /api/v1/goals/search
+func (gc *GoalsClient) SearchGoals(ctx context.Context, q string, page, pageSize int) (*GoalSearchPage, error) {
+	var resp GoalSearchPage
+	query := pageQuery("page", page, "pageSize", pageSize)
+	query.Set("q", q)
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/search", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetTrendingGoals calls GET /api/v1/goals/trending
+func (gc *GoalsClient) GetTrendingGoals(ctx context.Context, limit int) (*TrendingGoals, error) {
+	var resp TrendingGoals
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/trending", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetGoal calls GET /api/v1/goals/:id
+func (gc *GoalsClient) GetGoal(ctx context.Context, goalID string) (*Goal, error) {
+	var goal Goal
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID), nil, nil, &goal); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// GetInvitedGoal calls GET /api/v1/goals/:id with an invite link token, which opens a
+// private goal
+func (gc *GoalsClient) GetInvitedGoal(ctx context.Context, goalID, inviteToken string) (*Goal, error) {
+	var goal Goal
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID), url.Values{"invite": {inviteToken}}, nil, &goal); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// GetPlatformStats calls GET /api/v1/goals/stats/platform
+func (gc *GoalsClient) GetPlatformStats(ctx context.Context) (*PlatformStats, error) {
+	var stats PlatformStats
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/stats/platform", nil, nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetGoalBySlug calls GET /api/v1/goals/slug/:slug
+func (gc *GoalsClient) GetGoalBySlug(ctx context.Context, slug string) (*Goal, error) {
+	var goal Goal
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/slug/"+url.PathEscape(slug), nil, nil, &goal); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// GetShareMeta calls GET /api/v1/goals/:id/share-meta
+func (gc *GoalsClient) GetShareMeta(ctx context.Context, goalID string) (*GoalShareMeta, error) {
+	var meta GoalShareMeta
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID)+"/share-meta", nil, nil, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// GetGoalProgress calls GET /api/v1/goals/:id/progress
+func (gc *GoalsClient) GetGoalProgress(ctx context.Context, goalID string) (*GoalProgress, error) {
+	var progress GoalProgress
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID)+"/progress", nil, nil, &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// GetGoalStats calls GET /api/v1/goals/:id/stats with days of daily stats (zero for the
+// server default); only the goal owner may call it
+func (gc *GoalsClient) GetGoalStats(ctx context.Context, goalID string, days int) (*GoalStats, error) {
+	query := url.Values{}
+	if days > 0 {
+		query.Set("days", strconv.Itoa(days))
+	}
+	var stats GoalStats
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID)+"/stats", query, nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetContributionFeed calls GET /api/v1/goals/:id/contributions
+func (gc *GoalsClient) GetContributionFeed(ctx context.Context, goalID string, page, pageSize int) (*ContributionFeed, error) {
+	var feed ContributionFeed
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID)+"/contributions", pageQuery("page", page, "pageSize", pageSize), nil, &feed); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// ListComments calls GET /api/v1/goals/:id/comments
+func (gc *GoalsClient) ListComments(ctx context.Context, goalID string, page, pageSize int) (*CommentThreads, error) {
+	var threads CommentThreads
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID)+"/comments", pageQuery("page", page, "pageSize", pageSize), nil, &threads); err != nil {
+		return nil, err
+	}
+	return &threads, nil
+}
+
+// CreateComment calls POST /api/v1/goals/:id/comments
+func (gc *GoalsClient) CreateComment(ctx context.Context, goalID string, req *CreateCommentRequest) (*Comment, error) {
+	var comment Comment
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/"+url.PathEscape(goalID)+"/comments", nil, req, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// DeleteComment calls DELETE /api/v1/goals/:id/comments/:commentId
+func (gc *GoalsClient) DeleteComment(ctx context.Context, goalID, commentID string) error {
+	return gc.do(ctx, http.MethodDelete, "/api/v1/goals/"+url.PathEscape(goalID)+"/comments/"+url.PathEscape(commentID), nil, nil, nil)
+}
+
+// ListGoalUpdates calls GET /api/v1/goals/:id/updates
+func (gc *GoalsClient) ListGoalUpdates(ctx context.Context, goalID string, page, pageSize int) (*GoalUpdatePage, error) {
+	var updates GoalUpdatePage
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID)+"/updates", pageQuery("page", page, "pageSize", pageSize), nil, &updates); err != nil {
+		return nil, err
+	}
+	return &updates, nil
+}
+
+// PostGoalUpdate calls POST /api/v1/goals/:id/updates
+func (gc *GoalsClient) PostGoalUpdate(ctx context.Context, goalID string, req *CreateGoalUpdateRequest) (*GoalUpdate, error) {
+	var update GoalUpdate
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/"+url.PathEscape(goalID)+"/updates", nil, req, &update); err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+// GetOwnerContributions calls GET /api/v1/goals/:id/contributors
+func (gc *GoalsClient) GetOwnerContributions(ctx context.Context, goalID string, page, pageSize int) (*OwnerContributionList, error) {
+	var list OwnerContributionList
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID)+"/contributors", pageQuery("page", page, "pageSize", pageSize), nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ListMyGoals calls GET /api/v1/goals/my
+func (gc *GoalsClient) ListMyGoals(ctx context.Context, page, limit int) (*MyGoalsPage, error) {
+	var resp MyGoalsPage
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/my", pageQuery("page", page, "limit", limit), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListWatchedGoals calls GET /api/v1/goals/watched
+func (gc *GoalsClient) ListWatchedGoals(ctx context.Context, page, pageSize int) (*PublicGoalsPage, error) {
+	var resp PublicGoalsPage
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/watched", pageQuery("page", page, "pageSize", pageSize), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListGoalAuditLog calls GET /api/v1/goals/:id/audit; only the goal owner and admins may call it
+func (gc *GoalsClient) ListGoalAuditLog(ctx context.Context, goalID string, page, pageSize int) (*AuditLogPage, error) {
+	var resp AuditLogPage
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID)+"/audit", pageQuery("page", page, "pageSize", pageSize), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListCollaborators calls GET /api/v1/goals/:id/collaborators
+func (gc *GoalsClient) ListCollaborators(ctx context.Context, goalID string) ([]GoalCollaborator, error) {
+	var collaborators []GoalCollaborator
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID)+"/collaborators", nil, nil, &collaborators); err != nil {
+		return nil, err
+	}
+	return collaborators, nil
+}
+
+// AddCollaborator calls POST /api/v1/goals/:id/collaborators; only the goal owner may call it
+func (gc *GoalsClient) AddCollaborator(ctx context.Context, goalID string, req *AddCollaboratorRequest) (*GoalCollaborator, error) {
+	var collaborator GoalCollaborator
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/"+url.PathEscape(goalID)+"/collaborators", nil, req, &collaborator); err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+// CreateGoalInvite calls POST /api/v1/goals/:id/invites; only the goal owner and
+// collaborators with the owner role may call it
+func (gc *GoalsClient) CreateGoalInvite(ctx context.Context, goalID string) (*GoalInvite, error) {
+	var invite GoalInvite
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/"+url.PathEscape(goalID)+"/invites", nil, nil, &invite); err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// RemoveCollaborator calls DELETE /api/v1/goals/:id/collaborators/:userId; only the goal
+// owner may call it
+func (gc *GoalsClient) RemoveCollaborator(ctx context.Context, goalID, userID string) error {
+	return gc.do(ctx, http.MethodDelete, "/api/v1/goals/"+url.PathEscape(goalID)+"/collaborators/"+url.PathEscape(userID), nil, nil, nil)
+}
+
+// ExportGoal calls GET /api/v1/goals/:id/export with format=json; exportType is
+// contributions, withdrawals or all. Only the goal owner may call it; use the endpoint
+// directly for the CSV.
+func (gc *GoalsClient) ExportGoal(ctx context.Context, goalID, exportType string) ([]ExportRow, error) {
+	query := url.Values{"format": {"json"}}
+	if exportType != "" {
+		query.Set("type", exportType)
+	}
+	var rows []ExportRow
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID)+"/export", query, nil, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// WatchGoal calls POST /api/v1/goals/:id/watch
+func (gc *GoalsClient) WatchGoal(ctx context.Context, goalID string) error {
+	return gc.do(ctx, http.MethodPost, "/api/v1/goals/"+url.PathEscape(goalID)+"/watch", nil, nil, nil)
+}
+
+// UnwatchGoal calls DELETE /api/v1/goals/:id/watch
+func (gc *GoalsClient) UnwatchGoal(ctx context.Context, goalID string) error {
+	return gc.do(ctx, http.MethodDelete, "/api/v1/goals/"+url.PathEscape(goalID)+"/watch", nil, nil, nil)
+}
+
+// CreateGoal calls POST /api/v1/goals
+func (gc *GoalsClient) CreateGoal(ctx context.Context, req *CreateGoalRequest) (*Goal, error) {
+	var goal Goal
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals", nil, req, &goal); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// UpdateGoal calls PATCH /api/v1/goals/:id
+func (gc *GoalsClient) UpdateGoal(ctx context.Context, goalID string, req *UpdateGoalRequest) (*Goal, error) {
+	var goal Goal
+	if err := gc.do(ctx, http.MethodPatch, "/api/v1/goals/"+url.PathEscape(goalID), nil, req, &goal); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// CloseGoal calls POST /api/v1/goals/:id/close
+func (gc *GoalsClient) CloseGoal(ctx context.Context, goalID string) (*Goal, error) {
+	var goal Goal
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/"+url.PathEscape(goalID)+"/close", nil, nil, &goal); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// CancelGoal calls POST /api/v1/goals/:id/cancel
+func (gc *GoalsClient) CancelGoal(ctx context.Context, goalID string) (*Goal, error) {
+	var goal Goal
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/"+url.PathEscape(goalID)+"/cancel", nil, nil, &goal); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// DeleteGoal calls DELETE /api/v1/goals/:id. Goals that moved money, or have pending
+// contributions that still may, are archived rather than deleted; the archived goal is returned in that case and nil otherwise.
+func (gc *GoalsClient) DeleteGoal(ctx context.Context, goalID string) (*Goal, error) {
+	var resp struct {
+		Goal *Goal `json:"goal"`
+	}
+	if err := gc.do(ctx, http.MethodDelete, "/api/v1/goals/"+url.PathEscape(goalID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Goal, nil
+}
+
+// CreateMilestone calls POST /api/v1/goals/:id/milestones
+func (gc *GoalsClient) CreateMilestone(ctx context.Context, goalID string, req *CreateMilestoneRequest) (*CreatedMilestone, error) {
+	var resp CreatedMilestone
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/"+url.PathEscape(goalID)+"/milestones", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetGoalMilestones calls GET /api/v1/goals/:goalId/milestones
+func (gc *GoalsClient) GetGoalMilestones(ctx context.Context, goalID string) ([]Milestone, error) {
+	var resp struct {
+		Milestones []Milestone `json:"milestones"`
+	}
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID)+"/milestones", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Milestones, nil
+}
+
+// CompleteMilestone calls POST /api/v1/goals/milestones/:milestoneId/complete
+func (gc *GoalsClient) CompleteMilestone(ctx context.Context, milestoneID string) (*CompletedMilestone, error) {
+	var resp CompletedMilestone
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/milestones/"+url.PathEscape(milestoneID)+"/complete", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateMilestone calls PATCH /api/v1/goals/milestones/:milestoneId
+func (gc *GoalsClient) UpdateMilestone(ctx context.Context, milestoneID string, req *UpdateMilestoneRequest) (*UpdatedMilestone, error) {
+	var resp UpdatedMilestone
+	if err := gc.do(ctx, http.MethodPatch, "/api/v1/goals/milestones/"+url.PathEscape(milestoneID), nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteMilestone calls DELETE /api/v1/goals/milestones/:milestoneId. A milestone with
+// confirmed contributions is only deleted with merge, which moves them to the goal.
+func (gc *GoalsClient) DeleteMilestone(ctx context.Context, milestoneID string, merge bool) (*DeletedMilestone, error) {
+	var query url.Values
+	if merge {
+		query = url.Values{"mode": {"merge"}}
+	}
+	var resp DeletedMilestone
+	if err := gc.do(ctx, http.MethodDelete, "/api/v1/goals/milestones/"+url.PathEscape(milestoneID), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateContribution calls POST /api/v1/contributions
+func (gc *GoalsClient) CreateContribution(ctx context.Context, req *CreateContributionRequest) (*Contribution, error) {
+	var contribution Contribution
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/contributions", nil, req, &contribution); err != nil {
+		return nil, err
+	}
+	return &contribution, nil
+}
+
+// GetContribution calls GET /api/v1/contributions/:id
+func (gc *GoalsClient) GetContribution(ctx context.Context, contributionID string) (*Contribution, error) {
+	var contribution Contribution
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/contributions/"+url.PathEscape(contributionID), nil, nil, &contribution); err != nil {
+		return nil, err
+	}
+	return &contribution, nil
+}
+
+// GetContributionReceipt calls GET /api/v1/contributions/:id/receipt. The SDK asks for
+// JSON; browsers get the HTML page and application/pdf gets a PDF.
+func (gc *GoalsClient) GetContributionReceipt(ctx context.Context, contributionID string) (*ContributionReceipt, error) {
+	var receipt ContributionReceipt
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/contributions/"+url.PathEscape(contributionID)+"/receipt", nil, nil, &receipt); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// GetContributionRefund calls GET /api/v1/contributions/:id/refund
+func (gc *GoalsClient) GetContributionRefund(ctx context.Context, contributionID string) (*ContributionRefund, error) {
+	var refund ContributionRefund
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/contributions/"+url.PathEscape(contributionID)+"/refund", nil, nil, &refund); err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// ListMyContributions calls GET /api/v1/contributions/my
+func (gc *GoalsClient) ListMyContributions(ctx context.Context) ([]Contribution, error) {
+	var resp struct {
+		Contributions []Contribution `json:"contributions"`
+		Total         int            `json:"total"`
+	}
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/contributions/my", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Contributions, nil
+}
+
+// CreateRecurringContribution calls POST /api/v1/contributions/recurring
+func (gc *GoalsClient) CreateRecurringContribution(ctx context.Context, req *CreateRecurringContributionRequest) (*RecurringContribution, error) {
+	var recurring RecurringContribution
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/contributions/recurring", nil, req, &recurring); err != nil {
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+// ListRecurringContributions calls GET /api/v1/contributions/recurring
+func (gc *GoalsClient) ListRecurringContributions(ctx context.Context) ([]RecurringContribution, error) {
+	var resp struct {
+		RecurringContributions []RecurringContribution `json:"recurring_contributions"`
+		Total                  int                     `json:"total"`
+	}
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/contributions/recurring", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.RecurringContributions, nil
+}
+
+// GetRecurringContribution calls GET /api/v1/contributions/recurring/:id
+func (gc *GoalsClient) GetRecurringContribution(ctx context.Context, recurringID string) (*RecurringContribution, error) {
+	var recurring RecurringContribution
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/contributions/recurring/"+url.PathEscape(recurringID), nil, nil, &recurring); err != nil {
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+// UpdateRecurringContribution calls PATCH /api/v1/contributions/recurring/:id
+func (gc *GoalsClient) UpdateRecurringContribution(ctx context.Context, recurringID string, req *UpdateRecurringContributionRequest) (*RecurringContribution, error) {
+	var recurring RecurringContribution
+	if err := gc.do(ctx, http.MethodPatch, "/api/v1/contributions/recurring/"+url.PathEscape(recurringID), nil, req, &recurring); err != nil {
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+// CancelRecurringContribution calls DELETE /api/v1/contributions/recurring/:id
+func (gc *GoalsClient) CancelRecurringContribution(ctx context.Context, recurringID string) error {
+	return gc.do(ctx, http.MethodDelete, "/api/v1/contributions/recurring/"+url.PathEscape(recurringID), nil, nil, nil)
+}
+
+// CreateWithdrawal calls POST /api/v1/goals/withdraw
+func (gc *GoalsClient) CreateWithdrawal(ctx context.Context, req *CreateWithdrawalRequest) (*Withdrawal, error) {
+	var withdrawal Withdrawal
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/withdraw", nil, req, &withdrawal); err != nil {
+		return nil, err
+	}
+	return &withdrawal, nil
+}
+
+// ApproveWithdrawal calls POST /api/v1/goals/withdrawals/:id/approve
+func (gc *GoalsClient) ApproveWithdrawal(ctx context.Context, withdrawalID string) (*Withdrawal, error) {
+	var withdrawal Withdrawal
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/withdrawals/"+url.PathEscape(withdrawalID)+"/approve", nil, nil, &withdrawal); err != nil {
+		return nil, err
+	}
+	return &withdrawal, nil
+}
+
+// GetWithdrawalHistory calls GET /api/v1/goals/:id/withdrawals
+func (gc *GoalsClient) GetWithdrawalHistory(ctx context.Context, goalID string, page, pageSize int) (*WithdrawalHistory, error) {
+	var history WithdrawalHistory
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/"+url.PathEscape(goalID)+"/withdrawals", pageQuery("page", page, "pageSize", pageSize), nil, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// GetWithdrawal calls GET /api/v1/goals/withdrawals/:id
+func (gc *GoalsClient) GetWithdrawal(ctx context.Context, withdrawalID string) (*WithdrawalDetail, error) {
+	var detail WithdrawalDetail
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/withdrawals/"+url.PathEscape(withdrawalID), nil, nil, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// UploadProofMedia calls POST /api/v1/goals/proofs/upload with file as the multipart
+// "file" field. Cite the returned URL in CreateProofRequest.MediaURLs.
+func (gc *GoalsClient) UploadProofMedia(ctx context.Context, filename string, file io.Reader) (*ProofMedia, error) {
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		part, err := form.CreateFormFile("file", filename)
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+	defer body.Close()
+
+	var media ProofMedia
+	if err := gc.send(ctx, http.MethodPost, "/api/v1/goals/proofs/upload", nil, form.FormDataContentType(), body, &media); err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// DeleteProof calls DELETE /api/v1/goals/proofs/:proofId; only the goal owner may call
+// it, before anyone has voted on the proof
+func (gc *GoalsClient) DeleteProof(ctx context.Context, proofID string) error {
+	return gc.do(ctx, http.MethodDelete, "/api/v1/goals/proofs/"+url.PathEscape(proofID), nil, nil, nil)
+}
+
+// CreateProof calls POST /api/v1/goals/proofs
+func (gc *GoalsClient) CreateProof(ctx context.Context, req *CreateProofRequest) (*Proof, error) {
+	var proof Proof
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/proofs", nil, req, &proof); err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
+// GetProofs calls GET /api/v1/goals/proofs?goalId=
+func (gc *GoalsClient) GetProofs(ctx context.Context, goalID string) ([]Proof, error) {
+	var proofs []Proof
+	query := url.Values{"goalId": []string{goalID}}
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/proofs", query, nil, &proofs); err != nil {
+		return nil, err
+	}
+	return proofs, nil
+}
+
+// GetProof calls GET /api/v1/goals/proofs/:proofId
+func (gc *GoalsClient) GetProof(ctx context.Context, proofID string) (*Proof, error) {
+	var proof Proof
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/proofs/"+url.PathEscape(proofID), nil, nil, &proof); err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
+// GetVoteStats calls GET /api/v1/goals/proofs/:proofId/stats
+func (gc *GoalsClient) GetVoteStats(ctx context.Context, proofID string) (*VoteStats, error) {
+	var stats VoteStats
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/proofs/"+url.PathEscape(proofID)+"/stats", nil, nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// CreateVote calls POST /api/v1/goals/votes
+func (gc *GoalsClient) CreateVote(ctx context.Context, req *CreateVoteRequest) (*Vote, error) {
+	var vote Vote
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/votes", nil, req, &vote); err != nil {
+		return nil, err
+	}
+	return &vote, nil
+}
+
+// RetractVote calls DELETE /api/v1/goals/votes/:proofId and returns the proof's updated
+// vote tally
+func (gc *GoalsClient) RetractVote(ctx context.Context, proofID string) (*VoteStats, error) {
+	var stats VoteStats
+	if err := gc.do(ctx, http.MethodDelete, "/api/v1/goals/votes/"+url.PathEscape(proofID), nil, nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// ListProofVotes calls GET /api/v1/goals/proofs/:proofId/votes
+func (gc *GoalsClient) ListProofVotes(ctx context.Context, proofID string) ([]ProofVote, error) {
+	var votes []ProofVote
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/proofs/"+url.PathEscape(proofID)+"/votes", nil, nil, &votes); err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// InitiateRefund calls POST /api/v1/goals/refunds
+func (gc *GoalsClient) InitiateRefund(ctx context.Context, req *InitiateRefundRequest) (*Refund, error) {
+	var resp struct {
+		Refund  *Refund `json:"refund"`
+		Message string  `json:"message"`
+	}
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/refunds", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Refund, nil
+}
+
+// PreviewRefund calls POST /api/v1/goals/refunds/preview
+func (gc *GoalsClient) PreviewRefund(ctx context.Context, req *InitiateRefundRequest) (*RefundPlan, error) {
+	var resp struct {
+		Preview *RefundPlan `json:"preview"`
+	}
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/refunds/preview", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Preview, nil
+}
+
+// GetRefund calls GET /api/v1/goals/refunds/:id
+func (gc *GoalsClient) GetRefund(ctx context.Context, refundID string) (*Refund, error) {
+	var resp struct {
+		Refund *Refund `json:"refund"`
+	}
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/refunds/"+url.PathEscape(refundID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Refund, nil
+}
+
+// GetGoalRefunds calls GET /api/v1/goals/goals/:goalId/refunds
+func (gc *GoalsClient) GetGoalRefunds(ctx context.Context, goalID string) ([]Refund, error) {
+	var resp struct {
+		Refunds []Refund `json:"refunds"`
+		Count   int      `json:"count"`
+	}
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/goals/"+url.PathEscape(goalID)+"/refunds", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Refunds, nil
+}
+
+// RequestContributionRefund calls POST /api/v1/contributions/:id/refund-request. Within
+// the grace window on an open goal the request comes back already APPROVED.
+func (gc *GoalsClient) RequestContributionRefund(ctx context.Context, contributionID, reason string) (*RefundRequest, error) {
+	var resp struct {
+		RefundRequest *RefundRequest `json:"refund_request"`
+	}
+	body := map[string]string{"reason": reason}
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/contributions/"+url.PathEscape(contributionID)+"/refund-request", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.RefundRequest, nil
+}
+
+// GetGoalRefundRequests calls GET /api/v1/goals/goals/:goalId/refund-requests. An empty
+// status lists every request.
+func (gc *GoalsClient) GetGoalRefundRequests(ctx context.Context, goalID, status string) ([]RefundRequest, error) {
+	var resp struct {
+		RefundRequests []RefundRequest `json:"refund_requests"`
+		Count          int             `json:"count"`
+	}
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
+	}
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/goals/"+url.PathEscape(goalID)+"/refund-requests", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.RefundRequests, nil
+}
+
+// ApproveRefundRequest calls POST /api/v1/goals/refund-requests/:id/approve
+func (gc *GoalsClient) ApproveRefundRequest(ctx context.Context, requestID, note string) (*RefundRequest, error) {
+	return gc.reviewRefundRequest(ctx, requestID, "approve", note)
+}
+
+// DenyRefundRequest calls POST /api/v1/goals/refund-requests/:id/deny
+func (gc *GoalsClient) DenyRefundRequest(ctx context.Context, requestID, note string) (*RefundRequest, error) {
+	return gc.reviewRefundRequest(ctx, requestID, "deny", note)
+}
+
+// reviewRefundRequest posts the goal owner's decision, "approve" or "deny", on a refund request
+func (gc *GoalsClient) reviewRefundRequest(ctx context.Context, requestID, decision, note string) (*RefundRequest, error) {
+	var resp struct {
+		RefundRequest *RefundRequest `json:"refund_request"`
+	}
+	body := map[string]string{"note": note}
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/refund-requests/"+url.PathEscape(requestID)+"/"+decision, nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.RefundRequest, nil
+}
+
+// ListAllGoals calls GET /api/v1/goals/admin/all (admin only). An empty status lists
+// goals of every status.
+func (gc *GoalsClient) ListAllGoals(ctx context.Context, status string, page, pageSize int) (*PublicGoalsPage, error) {
+	var resp PublicGoalsPage
+	query := pageQuery("page", page, "pageSize", pageSize)
+	if status != "" {
+		query.Set("status", status)
+	}
+	if err := gc.do(ctx, http.MethodGet, "/api/v1/goals/admin/all", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SuspendGoal calls POST /api/v1/goals/admin/:id/suspend (admin only)
+func (gc *GoalsClient) SuspendGoal(ctx context.Context, goalID, reason string) (*Goal, error) {
+	var goal Goal
+	body := map[string]string{"reason": reason}
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/admin/"+url.PathEscape(goalID)+"/suspend", nil, body, &goal); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// UnsuspendGoal calls POST /api/v1/goals/admin/:id/unsuspend (admin only)
+func (gc *GoalsClient) UnsuspendGoal(ctx context.Context, goalID string) (*Goal, error) {
+	var goal Goal
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/admin/"+url.PathEscape(goalID)+"/unsuspend", nil, nil, &goal); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// VerifyGoalBalance calls POST /api/v1/goals/admin/:id/verify-balance (admin only)
+func (gc *GoalsClient) VerifyGoalBalance(ctx context.Context, goalID string) (*GoalBalanceCheck, error) {
+	var check GoalBalanceCheck
+	if err := gc.do(ctx, http.MethodPost, "/api/v1/goals/admin/"+url.PathEscape(goalID)+"/verify-balance", nil, nil, &check); err != nil {
+		return nil, err
+	}
+	return &check, nil
+}
@@ -0,0 +1,185 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AccountBalance mirrors dto.AccountBalance in the ledger service
+type AccountBalance struct {
+	AccountID   *string    `json:"account_id"`
+	EntityID    string     `json:"entity_id"`
+	AccountType string     `json:"account_type"`
+	Currency    string     `json:"currency"`
+	Balance     int64      `json:"balance"`
+	Source      string     `json:"source"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// LedgerTransactionSummary mirrors dto.TransactionSummary
+type LedgerTransactionSummary struct {
+	ID              string                 `json:"id"`
+	Type            string                 `json:"type"`
+	Description     string                 `json:"description"`
+	Status          string                 `json:"status"`
+	TransactionDate time.Time              `json:"transaction_date"`
+	Metadata        map[string]interface{} `json:"metadata"`
+}
+
+// LedgerEntry mirrors dto.LedgerEntryItem
+type LedgerEntry struct {
+	ID          string                    `json:"id"`
+	AccountID   string                    `json:"account_id"`
+	EntryType   string                    `json:"entry_type"`
+	Amount      int64                     `json:"amount"`
+	Currency    string                    `json:"currency"`
+	Description string                    `json:"description"`
+	Metadata    map[string]interface{}    `json:"metadata"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	Transaction *LedgerTransactionSummary `json:"transaction"`
+}
+
+// LedgerEntriesQuery selects a page of an account's entries. AccountType is "GOAL" or
+// "USER"; From and To are optional.
+type LedgerEntriesQuery struct {
+	AccountType string
+	Currency    string
+	From        *time.Time
+	To          *time.Time
+	Page        int
+	PageSize    int
+}
+
+// LedgerEntriesPage mirrors dto.LedgerEntryPage
+type LedgerEntriesPage struct {
+	Items    []LedgerEntry `json:"items"`
+	Total    int64         `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+}
+
+// LedgerTransactionLeg mirrors dto.TransactionLeg
+type LedgerTransactionLeg struct {
+	ID          string    `json:"id"`
+	AccountID   string    `json:"account_id"`
+	AccountType string    `json:"account_type"`
+	EntityID    string    `json:"entity_id"`
+	EntryType   string    `json:"entry_type"`
+	Amount      int64     `json:"amount"`
+	Currency    string    `json:"currency"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// LedgerTransaction mirrors dto.TransactionDetail
+type LedgerTransaction struct {
+	ID              string                 `json:"id"`
+	Type            string                 `json:"type"`
+	Description     string                 `json:"description"`
+	Amount          int64                  `json:"amount"`
+	Currency        string                 `json:"currency"`
+	Status          string                 `json:"status"`
+	Metadata        map[string]interface{} `json:"metadata"`
+	TransactionDate time.Time              `json:"transaction_date"`
+	CreatedAt       time.Time              `json:"created_at"`
+	Entries         []LedgerTransactionLeg `json:"entries"`
+}
+
+// ReconciliationReport mirrors models.ReconciliationReport
+type ReconciliationReport struct {
+	ID              string    `json:"id"`
+	RunID           string    `json:"run_id"`
+	AccountID       string    `json:"account_id"`
+	Currency        string    `json:"currency"`
+	ExpectedBalance int64     `json:"expected_balance"`
+	ActualBalance   int64     `json:"actual_balance"`
+	Difference      int64     `json:"difference"`
+	SnapshotMissing bool      `json:"snapshot_missing"`
+	Corrected       bool      `json:"corrected"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// ReconciliationResult mirrors dto.ReconciliationResult
+type ReconciliationResult struct {
+	RunID           string                 `json:"run_id"`
+	DryRun          bool                   `json:"dry_run"`
+	AccountsChecked int                    `json:"accounts_checked"`
+	MismatchCount   int                    `json:"mismatch_count"`
+	CorrectedCount  int                    `json:"corrected_count"`
+	Mismatches      []ReconciliationReport `json:"mismatches"`
+	StartedAt       time.Time              `json:"started_at"`
+	CompletedAt     time.Time              `json:"completed_at"`
+}
+
+// LedgerClient is a typed client for the ledger service
+type LedgerClient struct {
+	*Client
+}
+
+// NewLedgerClient creates a new ledger service client
+func NewLedgerClient(baseURL string, opts ...Option) *LedgerClient {
+	return &LedgerClient{Client: NewClient(baseURL, opts...)}
+}
+
+// GetBalance calls GET /api/v1/ledger/accounts/:entityId/balance. An empty currency
+// means NGN.
+func (lc *LedgerClient) GetBalance(ctx context.Context, entityID, accountType, currency string) (*AccountBalance, error) {
+	query := url.Values{}
+	query.Set("type", accountType)
+	if currency != "" {
+		query.Set("currency", currency)
+	}
+
+	var balance AccountBalance
+	if err := lc.do(ctx, http.MethodGet, "/api/v1/ledger/accounts/"+url.PathEscape(entityID)+"/balance", query, nil, &balance); err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// ListEntries calls GET /api/v1/ledger/accounts/:entityId/entries
+func (lc *LedgerClient) ListEntries(ctx context.Context, entityID string, q LedgerEntriesQuery) (*LedgerEntriesPage, error) {
+	query := pageQuery("page", q.Page, "page_size", q.PageSize)
+	query.Set("type", q.AccountType)
+	if q.Currency != "" {
+		query.Set("currency", q.Currency)
+	}
+	if q.From != nil {
+		query.Set("from", q.From.Format(time.RFC3339))
+	}
+	if q.To != nil {
+		query.Set("to", q.To.Format(time.RFC3339))
+	}
+
+	var resp LedgerEntriesPage
+	if err := lc.do(ctx, http.MethodGet, "/api/v1/ledger/accounts/"+url.PathEscape(entityID)+"/entries", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetTransaction calls GET /api/v1/ledger/transactions/:id
+func (lc *LedgerClient) GetTransaction(ctx context.Context, transactionID string) (*LedgerTransaction, error) {
+	var transaction LedgerTransaction
+	if err := lc.do(ctx, http.MethodGet, "/api/v1/ledger/transactions/"+url.PathEscape(transactionID), nil, nil, &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// Reconcile calls POST /api/v1/ledger/reconcile (admin only). Mismatched snapshots are
+// only corrected when dryRun is false.
+func (lc *LedgerClient) Reconcile(ctx context.Context, dryRun bool) (*ReconciliationResult, error) {
+	query := url.Values{}
+	if !dryRun {
+		query.Set("dry_run", "false")
+	}
+
+	var result ReconciliationResult
+	if err := lc.do(ctx, http.MethodPost, "/api/v1/ledger/reconcile", query, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,192 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Notification mirrors models.Notification in the notifications service
+type Notification struct {
+	ID                string                 `json:"id"`
+	UserID            string                 `json:"user_id"`
+	Type              string                 `json:"type"`
+	Title             string                 `json:"title"`
+	Message           string                 `json:"message"`
+	Data              map[string]interface{} `json:"data"`
+	EmailSent         bool                   `json:"email_sent"`
+	EmailSentAt       *time.Time             `json:"email_sent_at,omitempty"`
+	EmailFailedReason *string                `json:"email_failed_reason,omitempty"`
+	RetryCount        int                    `json:"retry_count"`
+	IsRead            bool                   `json:"is_read"`
+	ReadAt            *time.Time             `json:"read_at,omitempty"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+	// Link is where the notification leads, when it leads anywhere
+	Link *NotificationLink `json:"link,omitempty"`
+}
+
+// NotificationLink mirrors models.NotificationLink. Type is "goal", "proof", "payment" or
+// "refund".
+type NotificationLink struct {
+	Type      string              `json:"type"`
+	ID        string              `json:"id"`
+	Secondary *NotificationAction `json:"secondary,omitempty"`
+}
+
+// NotificationAction mirrors models.NotificationAction
+type NotificationAction struct {
+	Action string `json:"action"`
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+}
+
+// NotificationPreferences mirrors models.NotificationPreferences
+type NotificationPreferences struct {
+	ID                        string    `json:"id"`
+	UserID                    string    `json:"user_id"`
+	EmailEnabled              bool      `json:"email_enabled"`
+	PaymentNotifications      bool      `json:"payment_notifications"`
+	ContributionNotifications bool      `json:"contribution_notifications"`
+	WithdrawalNotifications   bool      `json:"withdrawal_notifications"`
+	ProofNotifications        bool      `json:"proof_notifications"`
+	GoalNotifications         bool      `json:"goal_notifications"`
+	MarketingEmails           bool      `json:"marketing_emails"`
+	EmailFrequency            string    `json:"email_frequency"`
+	CreatedAt                 time.Time `json:"created_at"`
+	UpdatedAt                 time.Time `json:"updated_at"`
+}
+
+// UpdatePreferencesRequest mirrors models.UpdatePreferencesRequest
+type UpdatePreferencesRequest struct {
+	EmailEnabled              *bool `json:"email_enabled,omitempty"`
+	PaymentNotifications      *bool `json:"payment_notifications,omitempty"`
+	ContributionNotifications *bool `json:"contribution_notifications,omitempty"`
+	WithdrawalNotifications   *bool `json:"withdrawal_notifications,omitempty"`
+	ProofNotifications        *bool `json:"proof_notifications,omitempty"`
+	GoalNotifications         *bool `json:"goal_notifications,omitempty"`
+	MarketingEmails           *bool `json:"marketing_emails,omitempty"`
+	// EmailFrequency is "instant", "hourly" or "daily"
+	EmailFrequency *string `json:"email_frequency,omitempty"`
+}
+
+// ListNotificationsQuery mirrors models.ListNotificationsQuery
+type ListNotificationsQuery struct {
+	IsRead   *bool
+	Type     string
+	Page     int
+	PageSize int
+}
+
+// NotificationsPage mirrors dto.PaginatedNotifications
+type NotificationsPage struct {
+	Notifications []Notification `json:"notifications"`
+	Total         int64          `json:"total"`
+	Page          int            `json:"page"`
+	PageSize      int            `json:"page_size"`
+	TotalPages    int            `json:"total_pages"`
+}
+
+// NotificationsClient is a typed client for the notifications service
+type NotificationsClient struct {
+	*Client
+}
+
+// NewNotificationsClient creates a new notifications service client
+func NewNotificationsClient(baseURL string, opts ...Option) *NotificationsClient {
+	return &NotificationsClient{Client: NewClient(baseURL, opts...)}
+}
+
+// ListNotifications calls GET /api/v1/notifications
+func (nc *NotificationsClient) ListNotifications(ctx context.Context, q ListNotificationsQuery) (*NotificationsPage, error) {
+	query := pageQuery("page", q.Page, "page_size", q.PageSize)
+	if q.IsRead != nil {
+		query.Set("is_read", strconv.FormatBool(*q.IsRead))
+	}
+	if q.Type != "" {
+		query.Set("type", q.Type)
+	}
+
+	var resp NotificationsPage
+	if err := nc.do(ctx, http.MethodGet, "/api/v1/notifications", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetNotification calls GET /api/v1/notifications/:id
+func (nc *NotificationsClient) GetNotification(ctx context.Context, notificationID string) (*Notification, error) {
+	var notification Notification
+	if err := nc.do(ctx, http.MethodGet, "/api/v1/notifications/"+url.PathEscape(notificationID), nil, nil, &notification); err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkAsRead calls PUT /api/v1/notifications/:id/read
+func (nc *NotificationsClient) MarkAsRead(ctx context.Context, notificationID string) error {
+	return nc.do(ctx, http.MethodPut, "/api/v1/notifications/"+url.PathEscape(notificationID)+"/read", nil, nil, nil)
+}
+
+// DeleteNotification calls DELETE /api/v1/notifications/:id
+func (nc *NotificationsClient) DeleteNotification(ctx context.Context, notificationID string) error {
+	return nc.do(ctx, http.MethodDelete, "/api/v1/notifications/"+url.PathEscape(notificationID), nil, nil, nil)
+}
+
+// MarkAllAsRead calls PUT /api/v1/notifications/read-all, limited to one type when
+// notificationType is not empty, and returns how many notifications were marked
+func (nc *NotificationsClient) MarkAllAsRead(ctx context.Context, notificationType string) (int64, error) {
+	var query url.Values
+	if notificationType != "" {
+		query = url.Values{"type": {notificationType}}
+	}
+	var resp struct {
+		Updated int64 `json:"updated"`
+	}
+	if err := nc.do(ctx, http.MethodPut, "/api/v1/notifications/read-all", query, nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Updated, nil
+}
+
+// DeleteNotifications calls DELETE /api/v1/notifications and returns how many of the
+// given notifications were deleted
+func (nc *NotificationsClient) DeleteNotifications(ctx context.Context, notificationIDs []string) (int64, error) {
+	req := struct {
+		IDs []string `json:"ids"`
+	}{IDs: notificationIDs}
+	var resp struct {
+		Deleted int64 `json:"deleted"`
+	}
+	if err := nc.do(ctx, http.MethodDelete, "/api/v1/notifications", nil, req, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Deleted, nil
+}
+
+// GetUnreadCount calls GET /api/v1/notifications/unread/count
+func (nc *NotificationsClient) GetUnreadCount(ctx context.Context) (int64, error) {
+	var resp struct {
+		Count int64 `json:"count"`
+	}
+	if err := nc.do(ctx, http.MethodGet, "/api/v1/notifications/unread/count", nil, nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+// GetPreferences calls GET /api/v1/notifications/preferences
+func (nc *NotificationsClient) GetPreferences(ctx context.Context) (*NotificationPreferences, error) {
+	var preferences NotificationPreferences
+	if err := nc.do(ctx, http.MethodGet, "/api/v1/notifications/preferences", nil, nil, &preferences); err != nil {
+		return nil, err
+	}
+	return &preferences, nil
+}
+
+// UpdatePreferences calls PUT /api/v1/notifications/preferences
+func (nc *NotificationsClient) UpdatePreferences(ctx context.Context, req *UpdatePreferencesRequest) error {
+	return nc.do(ctx, http.MethodPut, "/api/v1/notifications/preferences", nil, req, nil)
+}
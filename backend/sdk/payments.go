@@ -0,0 +1,216 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// InitializePaymentRequest mirrors dto.InitializePaymentRequest
+type InitializePaymentRequest struct {
+	UserID         string                 `json:"user_id"`
+	GoalID         string                 `json:"goal_id"`
+	ContributionID string                 `json:"contribution_id,omitempty"`
+	Amount         int64                  `json:"amount"`
+	Currency       string                 `json:"currency"`
+	Email          string                 `json:"email"`
+	CallbackURL    string                 `json:"callback_url,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// InitializePaymentResponse mirrors dto.InitializePaymentResponse
+type InitializePaymentResponse struct {
+	PaymentID        string `json:"payment_id"`
+	Amount           int64  `json:"amount"`     // what checkout charges, the fee included
+	FeeAmount        int64  `json:"fee_amount"` // platform fee added on top of the requested amount
+	AuthorizationURL string `json:"authorization_url"`
+	AccessCode       string `json:"access_code"`
+	Reference        string `json:"reference"`
+	Status           string `json:"status,omitempty"`
+}
+
+// VerifyPaymentResponse mirrors dto.VerifyPaymentResponse
+type VerifyPaymentResponse struct {
+	PaymentID string                 `json:"payment_id"`
+	Reference string                 `json:"reference"`
+	Status    string                 `json:"status"`
+	Amount    int64                  `json:"amount"`
+	Currency  string                 `json:"currency"`
+	PaidAt    *string                `json:"paid_at,omitempty"`
+	Channel   string                 `json:"channel,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	// AppMetadata holds our own metadata fields as validated after the Paystack round trip
+	AppMetadata *PaymentAppMetadata `json:"app_metadata,omitempty"`
+}
+
+// PaymentStatus mirrors dto.PaymentStatusResponse
+type PaymentStatus struct {
+	PaymentID string                 `json:"payment_id"`
+	Reference string                 `json:"reference"`
+	Status    string                 `json:"status"`
+	Amount    int64                  `json:"amount"`
+	Currency  string                 `json:"currency"`
+	PaidAt    *string                `json:"paid_at,omitempty"`
+	CreatedAt string                 `json:"created_at"`
+	UpdatedAt string                 `json:"updated_at"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	// AppMetadata holds our own metadata fields as validated after the Paystack round trip
+	AppMetadata *PaymentAppMetadata `json:"app_metadata,omitempty"`
+}
+
+// PaymentList mirrors dto.PaymentListResponse
+type PaymentList struct {
+	Items    []PaymentStatus `json:"items"`
+	Total    int64           `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+}
+
+// PaymentAppMetadata mirrors models.PaymentAppMetadata
+type PaymentAppMetadata struct {
+	PaymentID      string                 `json:"payment_id,omitempty"`
+	UserID         string                 `json:"user_id,omitempty"`
+	GoalID         string                 `json:"goal_id,omitempty"`
+	ContributionID string                 `json:"contribution_id,omitempty"`
+	MilestoneID    string                 `json:"milestone_id,omitempty"`
+	Custom         map[string]interface{} `json:"custom,omitempty"`
+}
+
+// PaymentMethod mirrors models.SavedPaymentMethod
+type PaymentMethod struct {
+	ID         string `json:"id"`
+	UserID     string `json:"user_id"`
+	Channel    string `json:"channel,omitempty"`
+	CardType   string `json:"card_type,omitempty"`
+	Last4      string `json:"last4,omitempty"`
+	ExpMonth   string `json:"exp_month,omitempty"`
+	ExpYear    string `json:"exp_year,omitempty"`
+	Bank       string `json:"bank,omitempty"`
+	IsReusable bool   `json:"is_reusable"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at"`
+}
+
+// Bank mirrors dto.Bank
+type Bank struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Code string `json:"code"`
+}
+
+// ResolvedAccount mirrors dto.ResolveAccountResponse
+type ResolvedAccount struct {
+	AccountNumber string `json:"account_number"`
+	AccountName   string `json:"account_name"`
+	BankCode      string `json:"bank_code"`
+	BankName      string `json:"bank_name"`
+}
+
+// paymentsEnvelope is the {"status": "success", "data": ...} wrapper used by the payments service
+type paymentsEnvelope struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data"`
+}
+
+// PaymentsClient is a typed client for the payments service
+type PaymentsClient struct {
+	*Client
+}
+
+// NewPaymentsClient creates a new payments service client
+func NewPaymentsClient(baseURL string, opts ...Option) *PaymentsClient {
+	return &PaymentsClient{Client: NewClient(baseURL, opts...)}
+}
+
+// InitializePayment calls POST /api/v1/payments/initialize
+func (pc *PaymentsClient) InitializePayment(ctx context.Context, req *InitializePaymentRequest) (*InitializePaymentResponse, error) {
+	var data InitializePaymentResponse
+	if err := pc.do(ctx, http.MethodPost, "/api/v1/payments/initialize", nil, req, &paymentsEnvelope{Data: &data}); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// VerifyPayment calls GET /api/v1/payments/verify/:reference
+func (pc *PaymentsClient) VerifyPayment(ctx context.Context, reference string) (*VerifyPaymentResponse, error) {
+	var data VerifyPaymentResponse
+	if err := pc.do(ctx, http.MethodGet, "/api/v1/payments/verify/"+url.PathEscape(reference), nil, nil, &paymentsEnvelope{Data: &data}); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetPaymentStatus calls GET /api/v1/payments/:paymentId/status
+func (pc *PaymentsClient) GetPaymentStatus(ctx context.Context, paymentID string) (*PaymentStatus, error) {
+	var data PaymentStatus
+	if err := pc.do(ctx, http.MethodGet, "/api/v1/payments/"+url.PathEscape(paymentID)+"/status", nil, nil, &paymentsEnvelope{Data: &data}); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// ListMyPayments calls GET /api/v1/payments/my. An empty status lists payments in every status.
+func (pc *PaymentsClient) ListMyPayments(ctx context.Context, status string, page, pageSize int) (*PaymentList, error) {
+	var data PaymentList
+	query := pageQuery("page", page, "page_size", pageSize)
+	if status != "" {
+		query.Set("status", status)
+	}
+	if err := pc.do(ctx, http.MethodGet, "/api/v1/payments/my", query, nil, &paymentsEnvelope{Data: &data}); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// ListGoalPayments calls GET /api/v1/payments/goal/:goalId; only the goal owner may call it
+func (pc *PaymentsClient) ListGoalPayments(ctx context.Context, goalID, status string, page, pageSize int) (*PaymentList, error) {
+	var data PaymentList
+	query := pageQuery("page", page, "page_size", pageSize)
+	if status != "" {
+		query.Set("status", status)
+	}
+	if err := pc.do(ctx, http.MethodGet, "/api/v1/payments/goal/"+url.PathEscape(goalID), query, nil, &paymentsEnvelope{Data: &data}); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// ListPaymentMethods calls GET /api/v1/payments/methods
+func (pc *PaymentsClient) ListPaymentMethods(ctx context.Context) ([]PaymentMethod, error) {
+	var data []PaymentMethod
+	if err := pc.do(ctx, http.MethodGet, "/api/v1/payments/methods", nil, nil, &paymentsEnvelope{Data: &data}); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// DeletePaymentMethod calls DELETE /api/v1/payments/methods/:methodId
+func (pc *PaymentsClient) DeletePaymentMethod(ctx context.Context, methodID string) error {
+	return pc.do(ctx, http.MethodDelete, "/api/v1/payments/methods/"+url.PathEscape(methodID), nil, nil, nil)
+}
+
+// ListBanks calls GET /api/v1/payments/banks
+func (pc *PaymentsClient) ListBanks(ctx context.Context, country string) ([]Bank, error) {
+	var data []Bank
+	query := url.Values{}
+	if country != "" {
+		query.Set("country", country)
+	}
+	if err := pc.do(ctx, http.MethodGet, "/api/v1/payments/banks", query, nil, &paymentsEnvelope{Data: &data}); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ResolveAccount calls GET /api/v1/payments/resolve-account
+func (pc *PaymentsClient) ResolveAccount(ctx context.Context, accountNumber, bankCode string) (*ResolvedAccount, error) {
+	var data ResolvedAccount
+	query := url.Values{
+		"account_number": []string{accountNumber},
+		"bank_code":      []string{bankCode},
+	}
+	if err := pc.do(ctx, http.MethodGet, "/api/v1/payments/resolve-account", query, nil, &paymentsEnvelope{Data: &data}); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
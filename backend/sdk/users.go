@@ -0,0 +1,366 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// LoginRequest mirrors dto.LoginRequest
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RegisterRequest mirrors dto.RegisterRequest
+type RegisterRequest struct {
+	Email                   string `json:"email"`
+	Username                string `json:"username,omitempty"`
+	Password                string `json:"password,omitempty"`
+	FirstName               string `json:"first_name,omitempty"`
+	LastName                string `json:"last_name,omitempty"`
+	Phone                   string `json:"phone,omitempty"`
+	SettlementBankName      string `json:"settlement_bank_name,omitempty"`
+	SettlementAccountNumber string `json:"settlement_account_number,omitempty"`
+	SettlementAccountName   string `json:"settlement_account_name,omitempty"`
+}
+
+// SetPasswordRequest mirrors dto.SetPasswordRequest
+type SetPasswordRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// UpdateProfileRequest mirrors dto.UpdateProfileRequest
+type UpdateProfileRequest struct {
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+	// AvatarURL and Bio are left alone when nil and cleared when empty
+	AvatarURL *string `json:"avatar_url,omitempty"`
+	Bio       *string `json:"bio,omitempty"`
+}
+
+// ResetPasswordRequest mirrors dto.ResetPasswordRequest
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// ChangePasswordRequest mirrors dto.ChangePasswordRequest. RefreshToken identifies the
+// session to keep signed in; every other session is signed out.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+	RefreshToken    string `json:"refresh_token,omitempty"`
+}
+
+// SettlementAccountRequest is the body of PUT /users/settlement-account
+type SettlementAccountRequest struct {
+	BankName      string `json:"bank_name"`
+	AccountNumber string `json:"account_number"`
+	AccountName   string `json:"account_name"`
+}
+
+// User mirrors dto.UserResponse
+type User struct {
+	ID            string     `json:"id"`
+	Email         string     `json:"email"`
+	Username      string     `json:"username"`
+	FirstName     string     `json:"first_name"`
+	LastName      string     `json:"last_name"`
+	Phone         string     `json:"phone"`
+	AvatarURL     string     `json:"avatar_url,omitempty"`
+	Bio           string     `json:"bio,omitempty"`
+	EmailVerified bool       `json:"email_verified"`
+	PhoneVerified bool       `json:"phone_verified"`
+	KYCVerified   bool       `json:"kyc_verified"`
+	KYCVerifiedAt *time.Time `json:"kyc_verified_at,omitempty"`
+	Role          string     `json:"role"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// PublicProfile mirrors dto.PublicProfile
+type PublicProfile struct {
+	Username        string    `json:"username"`
+	FirstName       string    `json:"first_name"`
+	AvatarURL       string    `json:"avatar_url,omitempty"`
+	Bio             string    `json:"bio,omitempty"`
+	JoinedAt        time.Time `json:"joined_at"`
+	PublicGoalCount int64     `json:"public_goal_count"`
+}
+
+// AuthResponse mirrors dto.AuthResponse
+type AuthResponse struct {
+	User         *User  `json:"user"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// TokenPair mirrors jwt.TokenPair
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// SubmitKYCRequest mirrors dto.SubmitKYCRequest
+type SubmitKYCRequest struct {
+	DocumentType   string   `json:"document_type"`
+	DocumentNumber string   `json:"document_number"`
+	DocumentURLs   []string `json:"document_urls,omitempty"`
+}
+
+// ReviewKYCRequest mirrors dto.ReviewKYCRequest
+type ReviewKYCRequest struct {
+	Decision string `json:"decision"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// KYCSubmissionSummary mirrors dto.KYCSubmissionSummary
+type KYCSubmissionSummary struct {
+	ID              string     `json:"id"`
+	DocumentType    string     `json:"document_type"`
+	DocumentNumber  string     `json:"document_number"`
+	Status          string     `json:"status"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+	SubmittedAt     time.Time  `json:"submitted_at"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// KYCStatus mirrors dto.KYCStatusResponse
+type KYCStatus struct {
+	KYCVerified   bool                  `json:"kyc_verified"`
+	KYCVerifiedAt *time.Time            `json:"kyc_verified_at,omitempty"`
+	NIN           string                `json:"nin,omitempty"`
+	Submission    *KYCSubmissionSummary `json:"submission,omitempty"`
+}
+
+// KYCSubmission mirrors models.KYCSubmission
+type KYCSubmission struct {
+	ID              string     `json:"id"`
+	UserID          string     `json:"user_id"`
+	DocumentType    string     `json:"document_type"`
+	DocumentNumber  string     `json:"document_number"`
+	DocumentURLs    []string   `json:"document_urls"`
+	Status          string     `json:"status"`
+	ReviewerID      *string    `json:"reviewer_id,omitempty"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// KYCSubmissionPage mirrors dto.KYCSubmissionListResponse
+type KYCSubmissionPage struct {
+	Data  []KYCSubmission `json:"data"`
+	Total int64           `json:"total"`
+	Page  int             `json:"page"`
+	Size  int             `json:"size"`
+}
+
+// Session mirrors dto.SessionResponse
+type Session struct {
+	ID        string    `json:"id"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UsersClient is a typed client for the users service
+type UsersClient struct {
+	*Client
+}
+
+// NewUsersClient creates a new users service client
+func NewUsersClient(baseURL string, opts ...Option) *UsersClient {
+	return &UsersClient{Client: NewClient(baseURL, opts...)}
+}
+
+// Login calls POST /auth/login
+func (uc *UsersClient) Login(ctx context.Context, req *LoginRequest) (*AuthResponse, error) {
+	var resp AuthResponse
+	if err := uc.do(ctx, http.MethodPost, "/auth/login", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Register calls POST /auth/register
+func (uc *UsersClient) Register(ctx context.Context, req *RegisterRequest) (*AuthResponse, error) {
+	var resp AuthResponse
+	if err := uc.do(ctx, http.MethodPost, "/auth/register", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RefreshToken calls POST /auth/refresh. The returned pair replaces refreshToken, which
+// must not be used again: users-service revokes every session on refresh token reuse.
+func (uc *UsersClient) RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	var resp TokenPair
+	body := map[string]string{"refresh_token": refreshToken}
+	if err := uc.do(ctx, http.MethodPost, "/auth/refresh", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Logout calls POST /auth/logout
+func (uc *UsersClient) Logout(ctx context.Context, refreshToken string) error {
+	body := map[string]string{"refresh_token": refreshToken}
+	return uc.do(ctx, http.MethodPost, "/auth/logout", nil, body, nil)
+}
+
+// ForgotPassword calls POST /auth/forgot-password
+func (uc *UsersClient) ForgotPassword(ctx context.Context, email string) error {
+	body := map[string]string{"email": email}
+	return uc.do(ctx, http.MethodPost, "/auth/forgot-password", nil, body, nil)
+}
+
+// ResetPassword calls POST /auth/reset-password
+func (uc *UsersClient) ResetPassword(ctx context.Context, req *ResetPasswordRequest) error {
+	return uc.do(ctx, http.MethodPost, "/auth/reset-password", nil, req, nil)
+}
+
+// VerifyEmail calls POST /auth/verify-email
+func (uc *UsersClient) VerifyEmail(ctx context.Context, token string) error {
+	body := map[string]string{"token": token}
+	return uc.do(ctx, http.MethodPost, "/auth/verify-email", nil, body, nil)
+}
+
+// ResendEmailVerification calls POST /auth/verify-email/resend
+func (uc *UsersClient) ResendEmailVerification(ctx context.Context, email string) error {
+	body := map[string]string{"email": email}
+	return uc.do(ctx, http.MethodPost, "/auth/verify-email/resend", nil, body, nil)
+}
+
+// GetProfile calls GET /users/profile
+func (uc *UsersClient) GetProfile(ctx context.Context) (*User, error) {
+	var resp struct {
+		User *User `json:"user"`
+	}
+	if err := uc.do(ctx, http.MethodGet, "/users/profile", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// GetPublicProfile calls GET /users/:username/public
+func (uc *UsersClient) GetPublicProfile(ctx context.Context, username string) (*PublicProfile, error) {
+	var resp struct {
+		User *PublicProfile `json:"user"`
+	}
+	if err := uc.do(ctx, http.MethodGet, "/users/"+url.PathEscape(username)+"/public", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// UpdateProfile calls PUT /users/profile
+func (uc *UsersClient) UpdateProfile(ctx context.Context, req *UpdateProfileRequest) (*User, error) {
+	var resp struct {
+		User *User `json:"user"`
+	}
+	if err := uc.do(ctx, http.MethodPut, "/users/profile", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// ChangePassword calls PUT /users/password
+func (uc *UsersClient) ChangePassword(ctx context.Context, req *ChangePasswordRequest) error {
+	return uc.do(ctx, http.MethodPut, "/users/password", nil, req, nil)
+}
+
+// ListSessions calls GET /users/sessions
+func (uc *UsersClient) ListSessions(ctx context.Context) ([]Session, error) {
+	var resp struct {
+		Sessions []Session `json:"sessions"`
+	}
+	if err := uc.do(ctx, http.MethodGet, "/users/sessions", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Sessions, nil
+}
+
+// RevokeSession calls DELETE /users/sessions/:id
+func (uc *UsersClient) RevokeSession(ctx context.Context, sessionID string) error {
+	return uc.do(ctx, http.MethodDelete, "/users/sessions/"+url.PathEscape(sessionID), nil, nil, nil)
+}
+
+// UpdateSettlementAccount calls PUT /users/settlement-account
+func (uc *UsersClient) UpdateSettlementAccount(ctx context.Context, req *SettlementAccountRequest) error {
+	return uc.do(ctx, http.MethodPut, "/users/settlement-account", nil, req, nil)
+}
+
+// SubmitNIN calls POST /users/kyc/submit-nin
+func (uc *UsersClient) SubmitNIN(ctx context.Context, nin string) (*KYCStatus, error) {
+	var resp KYCStatus
+	body := map[string]string{"nin": nin}
+	if err := uc.do(ctx, http.MethodPost, "/users/kyc/submit-nin", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SubmitKYC calls POST /users/kyc
+func (uc *UsersClient) SubmitKYC(ctx context.Context, req *SubmitKYCRequest) (*KYCStatus, error) {
+	var resp KYCStatus
+	if err := uc.do(ctx, http.MethodPost, "/users/kyc", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetKYCStatus calls GET /users/kyc
+func (uc *UsersClient) GetKYCStatus(ctx context.Context) (*KYCStatus, error) {
+	var resp KYCStatus
+	if err := uc.do(ctx, http.MethodGet, "/users/kyc", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListPendingKYCSubmissions calls GET /admin/kyc/pending; admin only
+func (uc *UsersClient) ListPendingKYCSubmissions(ctx context.Context, page, pageSize int) (*KYCSubmissionPage, error) {
+	var resp KYCSubmissionPage
+	if err := uc.do(ctx, http.MethodGet, "/admin/kyc/pending", pageQuery("page", page, "pageSize", pageSize), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ReviewKYCSubmission calls POST /admin/kyc/:id/review; admin only
+func (uc *UsersClient) ReviewKYCSubmission(ctx context.Context, submissionID string, req *ReviewKYCRequest) (*KYCSubmission, error) {
+	var resp KYCSubmission
+	if err := uc.do(ctx, http.MethodPost, "/admin/kyc/"+url.PathEscape(submissionID)+"/review", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ContributionSignup calls POST /public/users/contribution-signup
+func (uc *UsersClient) ContributionSignup(ctx context.Context, req *RegisterRequest) (*User, error) {
+	var resp struct {
+		User *User `json:"user"`
+	}
+	if err := uc.do(ctx, http.MethodPost, "/public/users/contribution-signup", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// SetPassword calls POST /public/users/set-password
+func (uc *UsersClient) SetPassword(ctx context.Context, req *SetPasswordRequest) (*AuthResponse, error) {
+	var resp AuthResponse
+	if err := uc.do(ctx, http.MethodPost, "/public/users/set-password", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -12,12 +12,16 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofund/goals-service/internal/config"
-	"github.com/gofund/goals-service/internal/controllers"
 	"github.com/gofund/goals-service/internal/events"
 	"github.com/gofund/goals-service/internal/middleware"
 	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/router"
 	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/goals-service/internal/storage"
+	"github.com/gofund/shared/buildinfo"
 	"github.com/gofund/shared/database"
+	"github.com/gofund/shared/fees"
+	"github.com/gofund/shared/health"
 	"github.com/gofund/shared/messaging"
 	"github.com/gofund/shared/metrics"
 	"github.com/joho/godotenv"
@@ -25,6 +29,13 @@ import (
 	"gorm.io/gorm/logger"
 )
 
+// @title Goals Service API
+// @version 1.0
+// @description Goals, milestones, contributions, withdrawals, proofs, refunds, comments and updates.
+// @description Protected routes are called through the gateway, which verifies the bearer token and forwards the caller's identity.
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
@@ -34,6 +45,8 @@ func main() {
 	// Load config
 	cfg := config.LoadConfig()
 
+	buildinfo.LogStartup(cfg.Datadog.Service, cfg.Datadog.Version)
+
 	// Initialize Datadog
 	if err := metrics.InitDatadog(cfg.Datadog.Service, cfg.Datadog.Env, cfg.Datadog.Version); err != nil {
 		log.Printf("Warning: Failed to initialize Datadog: %v", err)
@@ -42,13 +55,15 @@ func main() {
 
 	// Initialize Database
 	db, err := database.SetupDatabase(database.Config{
-		Host:     cfg.Database.Host,
-		Port:     stringToInt(cfg.Database.Port),
-		User:     cfg.Database.User,
-		Password: cfg.Database.Password,
-		DBName:   cfg.Database.DBName,
-		SSLMode:  cfg.Database.SSLMode,
-		LogLevel: logger.Info,
+		Host:               cfg.Database.Host,
+		Port:               stringToInt(cfg.Database.Port),
+		User:               cfg.Database.User,
+		Password:           cfg.Database.Password,
+		DBName:             cfg.Database.DBName,
+		SSLMode:            cfg.Database.SSLMode,
+		LogLevel:           logger.Info,
+		SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+		ReplicaHosts:       cfg.Database.ReplicaHosts,
 	})
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
@@ -65,26 +80,93 @@ func main() {
 	// Initialize Messaging
 	var publisher messaging.Publisher
 	if rabbitConn != nil {
-		var err error
-		publisher, err = messaging.NewRabbitMQPublisher(rabbitConn, cfg.RabbitMQ.Exchange)
+		rabbitPublisher, err := messaging.NewRabbitMQPublisher(rabbitConn, cfg.RabbitMQ.Exchange)
 		if err != nil {
 			log.Printf("Warning: Failed to create RabbitMQ publisher: %v", err)
+		} else {
+			publisher = rabbitPublisher
 		}
 	}
 
 	// Initialize Repositories
 	repo := repository.NewRepository(db)
+	dataQualityRepo := repository.NewDataQualityRepository(db)
+	trendingRepo := repository.NewTrendingRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	updateRepo := repository.NewGoalUpdateRepository(db)
+	watchRepo := repository.NewGoalWatchRepository(db)
+	recurringRepo := repository.NewRecurringContributionRepository(db)
+	auditRepo := repository.NewAuditLogRepository(db)
+
+	// Services record events in the outbox, which the dispatcher drains to RabbitMQ. Events
+	// announcing a state change are recorded in the transaction that makes it.
+	outbox := service.NewOutboxPublisher(repo)
+	outboxDispatcher := service.NewOutboxDispatcher(repo, publisher, cfg.Outbox.Retention)
 
 	// Initialize Services
-	goalService := service.NewGoalService(repo)
-	contributionService := service.NewContributionService(repo)
-	withdrawalService := service.NewWithdrawalService(repo)
-	proofService := service.NewProofService(repo, publisher)
-	voteService := service.NewVoteService(repo, publisher)
-	refundService := service.NewRefundService(db, publisher)
+	auditService := service.NewAuditService(auditRepo, repo)
+	usersClient := service.NewUsersClient(cfg.Users.URL, cfg.Users.CacheTTL)
+	paymentsClient := service.NewPaymentsClient(cfg.Payments.URL)
+	inviteTokens := service.NewInviteTokens(cfg.Goals.InviteSecret, cfg.Goals.InviteTTL)
+	goalService := service.NewGoalService(repo, auditService, usersClient, paymentsClient, inviteTokens)
+	feeSchedule, err := fees.ParseSchedule(cfg.Fees.Schedule)
+	if err != nil {
+		log.Fatalf("Invalid PLATFORM_FEES: %v", err)
+	}
+	contributionService := service.NewContributionService(repo, usersClient, inviteTokens, feeSchedule, cfg.Contributions.IntentTTL, cfg.Contributions.DisclosureThreshold)
+	balanceCheckService := service.NewBalanceCheckService(repo, service.NewLedgerClient(cfg.Ledger.URL), cfg.Ledger.BlockWithdrawalsOnMismatch, cfg.Ledger.MismatchThreshold)
+	withdrawalService := service.NewWithdrawalService(repo, balanceCheckService, auditService, usersClient, cfg.Withdrawals.KYCThreshold)
+	proofMedia, err := storage.NewLocalStore(cfg.Proofs.MediaDir, cfg.Proofs.MediaBaseURL)
+	if err != nil {
+		log.Fatalf("Failed to set up proof media storage: %v", err)
+	}
+	proofService := service.NewProofService(repo, proofMedia)
+	voteService := service.NewVoteService(repo)
+	receiptService := service.NewReceiptService(repo, usersClient, paymentsClient)
+	recurringService := service.NewRecurringContributionService(recurringRepo, repo, contributionService, paymentsClient, outbox, cfg.Contributions.RecurringMaxAttempts)
+	refundService := service.NewRefundService(repo, usersClient, auditService, cfg.Refunds.GraceWindow)
+	commentService := service.NewCommentService(commentRepo, repo, outbox, usersClient)
+	updateService := service.NewGoalUpdateService(updateRepo, repo, outbox)
+	watchService := service.NewWatchService(watchRepo, repo)
+	collaboratorService := service.NewCollaboratorService(repo)
+	exportService := service.NewExportService(repo, usersClient)
+	statsService := service.NewGoalStatsService(repo, cfg.Goals.StatsCacheTTL)
+	platformStatsService := service.NewPlatformStatsService(repo, cfg.Goals.PlatformStatsCacheTTL)
+	dataQualityService := service.NewDataQualityService(dataQualityRepo)
+	moderationService := service.NewModerationService(repo, goalService)
+	trendingService := service.NewTrendingService(repo, trendingRepo, service.TrendingWeights{
+		Window:            cfg.Trending.Window,
+		HalfLife:          cfg.Trending.HalfLife,
+		AmountWeight:      cfg.Trending.AmountWeight,
+		ContributorWeight: cfg.Trending.ContributorWeight,
+	}, cfg.Trending.TopN)
+
+	// Backfill the stored goal funding totals and repair any drift since the last start
+	if _, err := goalService.ReconcileGoalTotals(context.Background()); err != nil {
+		log.Printf("Failed to reconcile goal funding totals: %v", err)
+	}
+
+	// Goals hidden before visibility levels existed become private
+	if _, err := goalService.BackfillVisibility(context.Background()); err != nil {
+		log.Printf("Failed to backfill goal visibility: %v", err)
+	}
+
+	// Give goals created before slugs existed a shareable slug
+	if _, err := goalService.BackfillSlugs(context.Background()); err != nil {
+		log.Printf("Failed to backfill goal slugs: %v", err)
+	}
+
+	// Start trending goals job
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go trendingService.Run(jobCtx, cfg.Trending.Interval)
+	go contributionService.RunExpiry(jobCtx, cfg.Contributions.ExpiryInterval)
+	go goalService.RunDeadlineEnforcement(jobCtx, cfg.Goals.DeadlineInterval)
+	go recurringService.RunCharges(jobCtx, cfg.Contributions.RecurringInterval)
+	go outboxDispatcher.Run(jobCtx, cfg.Outbox.Interval)
 
 	// Initialize Event Handlers
-	eventHandler := events.NewEventHandler(contributionService, goalService, publisher)
+	eventHandler := events.NewEventHandler(contributionService, withdrawalService, refundService)
 
 	// Start consuming events if RabbitMQ is connected
 	if rabbitConn != nil {
@@ -92,74 +174,81 @@ func main() {
 		if err != nil {
 			log.Printf("Failed to create RabbitMQ consumer: %v", err)
 		} else {
-			err = consumer.Consume("PaymentVerified", eventHandler.HandlePaymentVerified)
+			err = consumer.ConsumeContext("PaymentVerified", eventHandler.HandlePaymentVerified)
 			if err != nil {
 				log.Printf("Failed to start consuming PaymentVerified: %v", err)
 			}
+			if err := consumer.ConsumeContext("WithdrawalCompleted", eventHandler.HandleWithdrawalCompleted); err != nil {
+				log.Printf("Failed to start consuming WithdrawalCompleted: %v", err)
+			}
+			if err := consumer.ConsumeContext("WithdrawalFailed", eventHandler.HandleWithdrawalFailed); err != nil {
+				log.Printf("Failed to start consuming WithdrawalFailed: %v", err)
+			}
+			if err := consumer.ConsumeContext("RefundDisbursementCompleted", eventHandler.HandleRefundDisbursementCompleted); err != nil {
+				log.Printf("Failed to start consuming RefundDisbursementCompleted: %v", err)
+			}
+			if err := consumer.ConsumeContext("RefundDisbursementFailed", eventHandler.HandleRefundDisbursementFailed); err != nil {
+				log.Printf("Failed to start consuming RefundDisbursementFailed: %v", err)
+			}
 		}
 	}
 
-	// Initialize Controllers
-	goalController := controllers.NewGoalController(goalService)
-	contributionController := controllers.NewContributionController(contributionService, withdrawalService, proofService, voteService)
-	refundController := controllers.NewRefundController(refundService)
-
 	// Setup Router
 	if cfg.Server.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	r := gin.Default()
 
+	// Tag every request with an ID that error responses, logs and published events carry
+	r.Use(middleware.RequestID())
+
+	// Let the audit log record who made each change from where
+	r.Use(middleware.ClientIP())
+
+	// Give every request a deadline; it reaches the database through the request context
+	r.Use(middleware.Timeout(cfg.Server.RequestTimeout))
+
 	// Datadog tracing middleware
 	r.Use(gintrace.Middleware(cfg.Datadog.Service))
 
-	// Routes
-	api := r.Group("/api/v1/goals")
-	{
-		// Public routes (or read-only)
-		api.GET("", goalController.ListPublicGoals)
-		api.GET("/list", goalController.ListPublicGoals) // Alias for frontend compatibility
-		api.GET("/:id", goalController.GetGoal)
-		api.GET("/view/:id", goalController.GetGoal) // Alias for frontend compatibility
-		api.GET("/:id/progress", goalController.GetGoalProgress)
-		api.GET("/proofs", contributionController.GetProofs)
-		api.GET("/proofs/:proofId/stats", contributionController.GetVoteStats)
-
-		// Protected routes
-		protected := api.Group("")
-		protected.Use(middleware.AuthMiddleware())
-		{
-			protected.GET("/my", goalController.GetMyGoals)
-			protected.POST("", goalController.CreateGoal)
-			protected.PATCH("/:id", goalController.UpdateGoal)
-			protected.POST("/:id/milestones", goalController.CreateMilestone)
-			protected.GET("/:goalId/milestones", goalController.GetGoalMilestones)
-			protected.POST("/milestones/:milestoneId/complete", goalController.CompleteMilestone)
-			
-			protected.POST("/contribute", contributionController.CreateContribution)
-			protected.POST("/withdraw", contributionController.CreateWithdrawal)
-			protected.POST("/proofs", contributionController.CreateProof)
-			protected.POST("/votes", contributionController.CreateVote)
-
-			protected.POST("/refunds", refundController.InitiateRefund)
-			protected.GET("/refunds/:id", refundController.GetRefund)
-			protected.GET("/goals/:goalId/refunds", refundController.GetGoalRefunds)
-		}
+	// Drop identity headers that did not come from the gateway
+	if cfg.Identity.HeaderSecret == "" {
+		log.Printf("Warning: IDENTITY_HEADER_SECRET not set; X-User-* headers cannot be verified")
 	}
+	r.Use(middleware.IdentityGuard(cfg.Identity.HeaderSecret))
 
-	// Contributions routes
-	contributions := r.Group("/api/v1/contributions")
-	contributions.Use(middleware.AuthMiddleware())
-	{
-		contributions.GET("/my", contributionController.GetMyContributions)
-		contributions.GET("/:id", contributionController.GetContribution)
-		contributions.POST("", contributionController.CreateContribution)
-	}
+	router.SetupRoutes(r, router.Services{
+		Goals:         goalService,
+		Trending:      trendingService,
+		Contributions: contributionService,
+		Withdrawals:   withdrawalService,
+		Proofs:        proofService,
+		Votes:         voteService,
+		Refunds:       refundService,
+		Receipts:      receiptService,
+		Comments:      commentService,
+		Updates:       updateService,
+		Watches:       watchService,
+		Collaborators: collaboratorService,
+		Exports:       exportService,
+		Audit:         auditService,
+		Stats:         statsService,
+		PlatformStats: platformStatsService,
+		Recurring:     recurringService,
+		Moderation:    moderationService,
+		DataQuality:   dataQualityService,
+		BalanceCheck:  balanceCheckService,
+	}, cfg)
 
-	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "UP", "timestamp": time.Now()})
-	})
+	// Build info
+	r.GET("/version", gin.WrapF(buildinfo.Handler(cfg.Datadog.Service, cfg.Datadog.Version)))
+
+	// Health checks: live only says the process is up, ready probes dependencies
+	r.GET("/health/live", gin.WrapF(health.LiveHandler(cfg.Datadog.Service)))
+	r.GET("/health/ready", gin.WrapF(health.ReadyHandler(health.DefaultTimeout,
+		health.Gorm("postgres", db),
+		health.RabbitMQ("rabbitmq", rabbitConn),
+	)))
 
 	// Start Server with Graceful Shutdown
 	port := cfg.Server.Port
@@ -181,6 +270,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
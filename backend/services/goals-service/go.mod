@@ -4,11 +4,14 @@ go 1.24.0
 
 require (
 	github.com/gin-gonic/gin v1.10.1
+	github.com/gofund/sdk v0.0.0
 	github.com/gofund/shared v0.0.0
 	github.com/google/uuid v1.6.0
 	github.com/joho/godotenv v1.5.1
 	gopkg.in/DataDog/dd-trace-go.v1 v1.74.8
+	gorm.io/driver/postgres v1.6.0
 	gorm.io/gorm v1.31.1
+	gorm.io/plugin/dbresolver v1.6.2
 )
 
 require (
@@ -127,7 +130,8 @@ require (
 	google.golang.org/protobuf v1.36.6 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
-	gorm.io/driver/postgres v1.6.0 // indirect
 )
 
 replace github.com/gofund/shared => ../../shared
+
+replace github.com/gofund/sdk => ../../sdk
@@ -3,21 +3,38 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds all configuration for the Goals Service
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	RabbitMQ RabbitMQConfig
-	Redis    RedisConfig
-	Datadog  DatadogConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	RabbitMQ      RabbitMQConfig
+	Outbox        OutboxConfig
+	Redis         RedisConfig
+	Datadog       DatadogConfig
+	Trending      TrendingConfig
+	Contributions ContributionConfig
+	Goals         GoalConfig
+	Withdrawals   WithdrawalConfig
+	Refunds       RefundConfig
+	Fees          FeeConfig
+	Proofs        ProofConfig
+	Users         UsersServiceConfig
+	Payments      PaymentsServiceConfig
+	Ledger        LedgerServiceConfig
+	Identity      IdentityConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
 	Port string
 	Env  string
+	// RequestTimeout bounds how long a request's database work may run
+	RequestTimeout time.Duration
 }
 
 // DatabaseConfig holds database configuration
@@ -28,6 +45,12 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// Queries slower than SlowQueryThreshold are logged and reported to Datadog; zero
+	// turns this off
+	SlowQueryThreshold time.Duration
+	// ReplicaHosts are read replicas that plain reads go to, as host or host:port; with
+	// none, every query goes to Host
+	ReplicaHosts []string
 }
 
 // RabbitMQConfig holds RabbitMQ configuration
@@ -37,6 +60,15 @@ type RabbitMQConfig struct {
 	QueueName string
 }
 
+// OutboxConfig holds event outbox dispatcher configuration
+type OutboxConfig struct {
+	// Interval is how often events waiting in the outbox are published to RabbitMQ
+	Interval time.Duration
+	// Retention is how long published events are kept before they are deleted; zero
+	// keeps them forever
+	Retention time.Duration
+}
+
 // RedisConfig holds Redis configuration
 type RedisConfig struct {
 	Host     string
@@ -52,20 +84,115 @@ type DatadogConfig struct {
 	Version string
 }
 
+// TrendingConfig holds trending goals job configuration
+type TrendingConfig struct {
+	Interval          time.Duration
+	TopN              int
+	Window            time.Duration
+	HalfLife          time.Duration
+	AmountWeight      float64
+	ContributorWeight float64
+}
+
+// ContributionConfig holds contribution intent expiry and identity disclosure configuration
+type ContributionConfig struct {
+	IntentTTL      time.Duration
+	ExpiryInterval time.Duration
+	// DisclosureThreshold is the amount (in kobo) at or above which a contributor's
+	// identity is disclosed to the goal owner, anonymous or not. Zero disables disclosure.
+	DisclosureThreshold int64
+	// Recurring contributions due are charged every RecurringInterval; a failed charge is
+	// retried until RecurringMaxAttempts, then the recurring contribution is paused
+	RecurringInterval    time.Duration
+	RecurringMaxAttempts int
+}
+
+// GoalConfig holds goal deadline enforcement, creation and invite link configuration
+type GoalConfig struct {
+	DeadlineInterval time.Duration
+	// RequireVerifiedEmail refuses goal creation to users who have not verified their email
+	RequireVerifiedEmail bool
+	// InviteSecret signs invite links to private goals, which last InviteTTL. Without a
+	// secret no invites can be created.
+	InviteSecret string
+	InviteTTL    time.Duration
+	// StatsCacheTTL is how long a goal's dashboard stats are served from memory, and
+	// PlatformStatsCacheTTL how long the platform-wide totals are
+	StatsCacheTTL         time.Duration
+	PlatformStatsCacheTTL time.Duration
+}
+
+// WithdrawalConfig holds withdrawal policy settings
+type WithdrawalConfig struct {
+	// KYCThreshold is the amount (kobo) above which the requester must be KYC verified.
+	// Zero disables the check.
+	KYCThreshold int64
+}
+
+// RefundConfig holds contributor refund request settings
+type RefundConfig struct {
+	// A contributor's refund request on an open goal made within GraceWindow of
+	// contributing is approved automatically; later requests wait for the goal owner
+	GraceWindow time.Duration
+}
+
+// FeeConfig holds the platform fee schedule
+type FeeConfig struct {
+	// Schedule lists the fee per currency as CURRENCY:BASIS_POINTS:FIXED entries, see
+	// fees.ParseSchedule; payments-service must be given the same schedule
+	Schedule string
+}
+
+// ProofConfig holds proof media storage settings
+type ProofConfig struct {
+	// MediaDir is where uploaded proof media is kept; it is served at MediaBaseURL
+	MediaDir     string
+	MediaBaseURL string
+}
+
+// UsersServiceConfig holds settings for the internal users-service client
+type UsersServiceConfig struct {
+	URL      string
+	CacheTTL time.Duration
+}
+
+// PaymentsServiceConfig holds settings for the internal payments-service client
+type PaymentsServiceConfig struct {
+	URL string
+}
+
+// LedgerServiceConfig holds settings for the internal ledger-service client and the
+// goal balance consistency check
+type LedgerServiceConfig struct {
+	URL string
+	// BlockWithdrawalsOnMismatch refuses withdrawals while a goal's balance differs from
+	// the ledger's by more than MismatchThreshold (kobo)
+	BlockWithdrawalsOnMismatch bool
+	MismatchThreshold          int64
+}
+
+// IdentityConfig holds settings for verifying gateway identity headers
+type IdentityConfig struct {
+	HeaderSecret string
+}
+
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8083"),
-			Env:  getEnv("ENV", "development"),
+			Port:           getEnv("PORT", "8083"),
+			Env:            getEnv("ENV", "development"),
+			RequestTimeout: time.Duration(getEnvInt("REQUEST_TIMEOUT_SECONDS", 10)) * time.Second,
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("GOALS_DB_HOST", "localhost"),
-			Port:     getEnv("GOALS_DB_PORT", "5432"),
-			User:     getEnv("GOALS_DB_USER", "postgres"),
-			Password: getEnv("GOALS_DB_PASSWORD", "postgres"),
-			DBName:   getEnv("GOALS_DB_NAME", "goals_db"),
-			SSLMode:  getEnv("GOALS_DB_SSLMODE", "disable"),
+			Host:               getEnv("GOALS_DB_HOST", "localhost"),
+			Port:               getEnv("GOALS_DB_PORT", "5432"),
+			User:               getEnv("GOALS_DB_USER", "postgres"),
+			Password:           getEnv("GOALS_DB_PASSWORD", "postgres"),
+			DBName:             getEnv("GOALS_DB_NAME", "goals_db"),
+			SSLMode:            getEnv("GOALS_DB_SSLMODE", "disable"),
+			SlowQueryThreshold: time.Duration(getEnvInt("GOALS_DB_SLOW_QUERY_MS", 200)) * time.Millisecond,
+			ReplicaHosts:       getEnvList("GOALS_DB_REPLICA_HOSTS"),
 		},
 
 		RabbitMQ: RabbitMQConfig{
@@ -73,6 +200,10 @@ func LoadConfig() *Config {
 			Exchange:  getEnv("RABBITMQ_EXCHANGE", "gofund_events"),
 			QueueName: getEnv("RABBITMQ_QUEUE", "goals_queue"),
 		},
+		Outbox: OutboxConfig{
+			Interval:  time.Duration(getEnvInt("OUTBOX_DISPATCH_INTERVAL_SECONDS", 2)) * time.Second,
+			Retention: time.Duration(getEnvInt("OUTBOX_RETENTION_HOURS", 168)) * time.Hour,
+		},
 
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -85,6 +216,59 @@ func LoadConfig() *Config {
 			Env:     getEnv("DD_ENV", "dev"),
 			Version: getEnv("DD_VERSION", "1.0.0"),
 		},
+		Trending: TrendingConfig{
+			Interval:          time.Duration(getEnvInt("TRENDING_INTERVAL_MINUTES", 15)) * time.Minute,
+			TopN:              getEnvInt("TRENDING_TOP_N", 50),
+			Window:            time.Duration(getEnvInt("TRENDING_WINDOW_HOURS", 72)) * time.Hour,
+			HalfLife:          time.Duration(getEnvInt("TRENDING_HALF_LIFE_HOURS", 24)) * time.Hour,
+			AmountWeight:      getEnvFloat("TRENDING_AMOUNT_WEIGHT", 1.0),
+			ContributorWeight: getEnvFloat("TRENDING_CONTRIBUTOR_WEIGHT", 0.5),
+		},
+		Contributions: ContributionConfig{
+			IntentTTL:      time.Duration(getEnvInt("CONTRIBUTION_INTENT_TTL_MINUTES", 30)) * time.Minute,
+			ExpiryInterval: time.Duration(getEnvInt("CONTRIBUTION_EXPIRY_INTERVAL_MINUTES", 5)) * time.Minute,
+			// ₦500,000
+			DisclosureThreshold:  int64(getEnvInt("CONTRIBUTION_DISCLOSURE_THRESHOLD", 50000000)),
+			RecurringInterval:    time.Duration(getEnvInt("RECURRING_CHARGE_INTERVAL_MINUTES", 15)) * time.Minute,
+			RecurringMaxAttempts: getEnvInt("RECURRING_CHARGE_MAX_ATTEMPTS", 3),
+		},
+		Goals: GoalConfig{
+			DeadlineInterval:      time.Duration(getEnvInt("GOAL_DEADLINE_INTERVAL_MINUTES", 15)) * time.Minute,
+			RequireVerifiedEmail:  getEnv("GOAL_REQUIRE_VERIFIED_EMAIL", "false") == "true",
+			InviteSecret:          getEnv("GOAL_INVITE_SECRET", ""),
+			InviteTTL:             time.Duration(getEnvInt("GOAL_INVITE_TTL_HOURS", 168)) * time.Hour,
+			StatsCacheTTL:         time.Duration(getEnvInt("GOAL_STATS_CACHE_TTL_SECONDS", 60)) * time.Second,
+			PlatformStatsCacheTTL: time.Duration(getEnvInt("PLATFORM_STATS_CACHE_TTL_SECONDS", 300)) * time.Second,
+		},
+		Withdrawals: WithdrawalConfig{
+			// ₦100,000
+			KYCThreshold: int64(getEnvInt("WITHDRAWAL_KYC_THRESHOLD", 10000000)),
+		},
+		Refunds: RefundConfig{
+			GraceWindow: time.Duration(getEnvInt("REFUND_REQUEST_GRACE_HOURS", 24)) * time.Hour,
+		},
+		Fees: FeeConfig{
+			Schedule: getEnv("PLATFORM_FEES", ""),
+		},
+		Proofs: ProofConfig{
+			MediaDir:     getEnv("PROOF_MEDIA_DIR", "./data/proof-media"),
+			MediaBaseURL: getEnv("PROOF_MEDIA_BASE_URL", "http://localhost:8083/api/v1/goals/proofs/media"),
+		},
+		Users: UsersServiceConfig{
+			URL:      getEnv("USERS_SERVICE_URL", "http://localhost:8084"),
+			CacheTTL: time.Duration(getEnvInt("USERS_CACHE_TTL_MINUTES", 10)) * time.Minute,
+		},
+		Payments: PaymentsServiceConfig{
+			URL: getEnv("PAYMENTS_SERVICE_URL", "http://localhost:8081"),
+		},
+		Ledger: LedgerServiceConfig{
+			URL:                        getEnv("LEDGER_SERVICE_URL", "http://localhost:8082"),
+			BlockWithdrawalsOnMismatch: getEnv("LEDGER_BLOCK_WITHDRAWALS_ON_MISMATCH", "false") == "true",
+			MismatchThreshold:          int64(getEnvInt("LEDGER_MISMATCH_THRESHOLD", 0)),
+		},
+		Identity: IdentityConfig{
+			HeaderSecret: getEnv("IDENTITY_HEADER_SECRET", ""),
+		},
 	}
 }
 
@@ -108,3 +292,34 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// getEnvInt gets an integer environment variable with fallback
+func getEnvInt(key string, fallback int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+// getEnvList gets a comma-separated environment variable as a list, skipping empty items
+func getEnvList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvFloat gets a float environment variable with fallback
+func getEnvFloat(key string, fallback float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,316 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/shared/models"
+)
+
+// AdminController handles operator-only endpoints
+type AdminController struct {
+	dataQualityService  *service.DataQualityService
+	goalService         *service.GoalService
+	balanceCheckService *service.BalanceCheckService
+	moderationService   *service.ModerationService
+}
+
+// NewAdminController creates a new admin controller instance
+func NewAdminController(
+	dataQualityService *service.DataQualityService,
+	goalService *service.GoalService,
+	balanceCheckService *service.BalanceCheckService,
+	moderationService *service.ModerationService,
+) *AdminController {
+	return &AdminController{
+		dataQualityService:  dataQualityService,
+		goalService:         goalService,
+		balanceCheckService: balanceCheckService,
+		moderationService:   moderationService,
+	}
+}
+
+// GetOrphans reports orphaned contributions, withdrawals, votes and disbursements.
+// Passing repair=archive moves the orphans into archive tables.
+//
+// @Summary Report orphaned records
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param repair query string false "Archive the orphans instead of only reporting them" Enums(archive)
+// @Success 200 {object} dto.OrphanReport
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /admin/data-quality/orphans [get]
+func (ac *AdminController) GetOrphans(c *gin.Context) {
+	report, err := ac.dataQualityService.GetOrphanReport(c.Request.Context(), c.Query("repair"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ReconcileGoalTotals recomputes every goal's stored funding totals from its contributions
+// and refunds, repairing any drift
+//
+// @Summary Recompute every goal's funding totals
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.ReconcileResponse
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /admin/data-quality/goal-totals/reconcile [post]
+func (ac *AdminController) ReconcileGoalTotals(c *gin.Context) {
+	reconciled, err := ac.goalService.ReconcileGoalTotals(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ReconcileResponse{Reconciled: reconciled})
+}
+
+// ListAllGoals lists goals of any visibility and status, optionally filtered by ?status=
+//
+// @Summary List goals of any visibility and status
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {object} dto.GoalListResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/admin/all [get]
+func (ac *AdminController) ListAllGoals(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	status := models.GoalStatus(strings.ToUpper(c.Query("status")))
+
+	goals, total, err := ac.goalService.ListAllGoals(c.Request.Context(), status, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GoalListResponse{
+		Data:  goals,
+		Total: total,
+		Page:  page,
+		Size:  pageSize,
+	})
+}
+
+// SuspendGoal suspends a goal, blocking contributions and withdrawals
+//
+// @Summary Suspend a goal
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param request body dto.SuspendGoalRequest true "Suspension reason"
+// @Success 200 {object} models.Goal
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/admin/{id}/suspend [post]
+func (ac *AdminController) SuspendGoal(c *gin.Context) {
+	adminID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	id, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// The reason is optional, so an empty body is fine
+	var req dto.SuspendGoalRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, invalidRequest(err))
+			return
+		}
+	}
+
+	goal, err := ac.goalService.SuspendGoal(c.Request.Context(), id, adminID, req.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// UnsuspendGoal lifts a goal's suspension
+//
+// @Summary Lift a goal's suspension
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} models.Goal
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/admin/{id}/unsuspend [post]
+func (ac *AdminController) UnsuspendGoal(c *gin.Context) {
+	adminID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	id, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goal, err := ac.goalService.UnsuspendGoal(c.Request.Context(), id, adminID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// VerifyGoalBalance compares a goal's balance with its ledger balance and lists the
+// contributions and ledger entries they disagree on
+//
+// @Summary Compare a goal's balance with the ledger
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} dto.GoalBalanceCheck
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/admin/{id}/verify-balance [post]
+func (ac *AdminController) VerifyGoalBalance(c *gin.Context) {
+	id, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	check, err := ac.balanceCheckService.VerifyGoalBalance(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
+
+// GetModerationQueue lists reported and automatically flagged goals, highest priority
+// first. The priority weighs the number of open reports and flags, the money raised and
+// how recently the goal was last reported.
+//
+// @Summary List goals awaiting moderation
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param category query string false "Only goals with an open report in this category" Enums(fraud, spam, misleading, inappropriate, other, proof_rejected)
+// @Param status query string false "Filter by goal status"
+// @Param min_reports query int false "Minimum open reports, flags included"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {object} dto.ModerationQueueResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /admin/goals/moderation-queue [get]
+func (ac *AdminController) GetModerationQueue(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	category := models.GoalReportCategory(c.Query("category"))
+	status := models.GoalStatus(c.Query("status"))
+	var minReports int64
+	if value := c.Query("min_reports"); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			respondError(c, invalidRequest(err))
+			return
+		}
+		minReports = n
+	}
+
+	items, total, err := ac.moderationService.GetModerationQueue(c.Request.Context(), category, status, minReports, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ModerationQueueResponse{
+		Data:  items,
+		Total: total,
+		Page:  page,
+		Size:  pageSize,
+	})
+}
+
+// BulkModerate suspends, unsuspends or dismisses the reports on up to 50 goals. Each goal
+// is handled on its own, so the response reports successes and failures goal by goal.
+//
+// @Summary Apply a moderation action to several goals
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BulkModerationRequest true "Goals and action"
+// @Success 200 {object} dto.BulkModerationResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /admin/goals/bulk [post]
+func (ac *AdminController) BulkModerate(c *gin.Context) {
+	adminID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req dto.BulkModerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	response, err := ac.moderationService.BulkModerate(c.Request.Context(), adminID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
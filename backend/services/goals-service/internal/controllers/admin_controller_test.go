@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/middleware"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/shared/identity"
+	"github.com/google/uuid"
+)
+
+// newModerationTestRouter mounts the moderation queue and bulk moderation routes behind
+// the admin middleware, as cmd/main.go does
+func newModerationTestRouter(adminController *AdminController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	admin := r.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	admin.GET("/goals/moderation-queue", adminController.GetModerationQueue)
+	admin.POST("/goals/bulk", adminController.BulkModerate)
+	return r
+}
+
+// serveAs serves a request for a user with the given roles
+func serveAs(r http.Handler, method, path string, userID uuid.UUID, roles string, body interface{}) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	if userID != uuid.Nil {
+		req.Header.Set("X-User-ID", userID.String())
+	}
+	req.Header.Set(identity.HeaderUserRoles, roles)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestModerationRoutesRequireAdmin checks callers without the admin role are turned away
+// before reaching the (here absent) moderation service
+func TestModerationRoutesRequireAdmin(t *testing.T) {
+	r := newModerationTestRouter(NewAdminController(nil, nil, nil, nil))
+	bulk := dto.BulkModerationRequest{GoalIDs: []string{uuid.NewString()}, Action: dto.ModerationActionSuspend}
+
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		roles  string
+		want   int
+	}{
+		{name: "anonymous", want: http.StatusUnauthorized},
+		{name: "user", userID: uuid.New(), roles: "user", want: http.StatusForbidden},
+		{name: "moderator", userID: uuid.New(), roles: "user,moderator", want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serveAs(r, http.MethodGet, "/admin/goals/moderation-queue", tt.userID, tt.roles, nil); w.Code != tt.want {
+				t.Errorf("moderation queue status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if w := serveAs(r, http.MethodPost, "/admin/goals/bulk", tt.userID, tt.roles, bulk); w.Code != tt.want {
+				t.Errorf("bulk moderation status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestModerationEndpoints(t *testing.T) {
+	db := testdb.Open(t)
+	repo := repository.NewRepository(db)
+	audit := service.NewAuditService(repository.NewAuditLogRepository(db), repo)
+	goals := service.NewGoalService(repo, audit, nil, nil, service.NewInviteTokens("test-invite-secret", time.Hour))
+	r := newModerationTestRouter(NewAdminController(nil, goals, nil, service.NewModerationService(repo, goals)))
+	adminID := uuid.New()
+
+	var queue dto.ModerationQueueResponse
+	decode(t, serveAs(r, http.MethodGet, "/admin/goals/moderation-queue?min_reports=1&page=1&pageSize=10", adminID, "user,admin", nil), http.StatusOK, &queue)
+	if queue.Total != 0 || queue.Size != 10 {
+		t.Errorf("queue = %+v, want an empty page of 10", queue)
+	}
+	if w := serveAs(r, http.MethodGet, "/admin/goals/moderation-queue?min_reports=many", adminID, "admin", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("malformed min_reports status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	tooMany := make([]string, 51)
+	for i := range tooMany {
+		tooMany[i] = uuid.NewString()
+	}
+
+	tests := []struct {
+		name string
+		body dto.BulkModerationRequest
+		want int
+	}{
+		{name: "too many goals", body: dto.BulkModerationRequest{GoalIDs: tooMany, Action: dto.ModerationActionSuspend}, want: http.StatusBadRequest},
+		{name: "malformed goal ID", body: dto.BulkModerationRequest{GoalIDs: []string{"goal-1"}, Action: dto.ModerationActionSuspend}, want: http.StatusBadRequest},
+		{name: "unknown action", body: dto.BulkModerationRequest{GoalIDs: tooMany[:1], Action: "delete"}, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serveAs(r, http.MethodPost, "/admin/goals/bulk", adminID, "admin", tt.body); w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+
+	// A goal that cannot be moderated fails on its own without failing the request
+	w := serveAs(r, http.MethodPost, "/admin/goals/bulk", adminID, "admin", dto.BulkModerationRequest{GoalIDs: tooMany[:2], Action: dto.ModerationActionSuspend})
+	var response dto.BulkModerationResponse
+	decode(t, w, http.StatusOK, &response)
+	if response.Failed != 2 || len(response.Results) != 2 || response.Results[0].ErrorCode != "goal_not_found" {
+		t.Errorf("response = %+v, want both goals reported not found", response)
+	}
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/shared/models"
+)
+
+// AuditController serves the audit log of sensitive goal and financial actions
+type AuditController struct {
+	auditService *service.AuditService
+}
+
+// NewAuditController creates a new audit controller instance
+func NewAuditController(auditService *service.AuditService) *AuditController {
+	return &AuditController{
+		auditService: auditService,
+	}
+}
+
+// ListGoalAuditLog handles GET /api/v1/goals/:id/audit
+//
+// @Summary List a goal's audit log (owner or admin)
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {object} dto.AuditLogResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/audit [get]
+func (ac *AuditController) ListGoalAuditLog(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	isAdmin := hasRole(c, string(models.UserRoleAdmin))
+	entries, total, err := ac.auditService.ListGoalAuditLog(c.Request.Context(), goalID, userID, isAdmin, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.AuditLogResponse{
+		Data:  entries,
+		Total: total,
+		Page:  page,
+		Size:  pageSize,
+	})
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/service"
+)
+
+// CollaboratorController handles the collaborators of co-owned goals
+type CollaboratorController struct {
+	collaboratorService *service.CollaboratorService
+}
+
+// NewCollaboratorController creates a new collaborator controller instance
+func NewCollaboratorController(collaboratorService *service.CollaboratorService) *CollaboratorController {
+	return &CollaboratorController{
+		collaboratorService: collaboratorService,
+	}
+}
+
+// ListCollaborators handles GET /api/v1/goals/:id/collaborators
+//
+// @Summary List a goal's collaborators (owner or collaborator)
+// @Tags collaborators
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {array} models.GoalCollaborator
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/collaborators [get]
+func (cc *CollaboratorController) ListCollaborators(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	collaborators, err := cc.collaboratorService.ListCollaborators(c.Request.Context(), goalID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, collaborators)
+}
+
+// AddCollaborator handles POST /api/v1/goals/:id/collaborators
+//
+// @Summary Add a collaborator to a goal (owner only)
+// @Tags collaborators
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param request body dto.AddCollaboratorRequest true "Collaborator to add"
+// @Success 201 {object} models.GoalCollaborator
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/collaborators [post]
+func (cc *CollaboratorController) AddCollaborator(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req dto.AddCollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	collaborator, err := cc.collaboratorService.AddCollaborator(c.Request.Context(), goalID, userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, collaborator)
+}
+
+// RemoveCollaborator handles DELETE /api/v1/goals/:id/collaborators/:userId
+//
+// @Summary Remove a collaborator from a goal (owner only)
+// @Tags collaborators
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param userId path string true "Collaborator's user ID"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/collaborators/{userId} [delete]
+func (cc *CollaboratorController) RemoveCollaborator(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	collaboratorID, err := parseID(c.Param("userId"), "user")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := cc.collaboratorService.RemoveCollaborator(c.Request.Context(), goalID, userID, collaboratorID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Collaborator removed"})
+}
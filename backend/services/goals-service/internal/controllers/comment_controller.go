@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/google/uuid"
+)
+
+// CommentController handles comment threads on goals
+type CommentController struct {
+	commentService *service.CommentService
+}
+
+// NewCommentController creates a new comment controller instance
+func NewCommentController(commentService *service.CommentService) *CommentController {
+	return &CommentController{
+		commentService: commentService,
+	}
+}
+
+// ListComments handles GET /api/v1/goals/:id/comments
+//
+// @Summary List a goal's comment threads
+// @Tags comments
+// @Produce json
+// @Param id path string true "Goal ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {object} dto.CommentThreads
+// @Failure 400 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/comments [get]
+func (cc *CommentController) ListComments(c *gin.Context) {
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	viewerID, _ := uuid.Parse(c.GetHeader("X-User-ID"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	threads, err := cc.commentService.ListComments(c.Request.Context(), goalID, viewerID, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, threads)
+}
+
+// CreateComment handles POST /api/v1/goals/:id/comments
+//
+// @Summary Comment on a goal
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param request body dto.CreateCommentRequest true "Comment to post"
+// @Success 201 {object} models.Comment
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/comments [post]
+func (cc *CommentController) CreateComment(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	comment, err := cc.commentService.CreateComment(c.Request.Context(), goalID, userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// DeleteComment handles DELETE /api/v1/goals/:id/comments/:commentId
+//
+// @Summary Delete a comment
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param commentId path string true "Comment ID"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/comments/{commentId} [delete]
+func (cc *CommentController) DeleteComment(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	commentID, err := parseID(c.Param("commentId"), "comment")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := cc.commentService.DeleteComment(c.Request.Context(), goalID, commentID, userID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Comment deleted"})
+}
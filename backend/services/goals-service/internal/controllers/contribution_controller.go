@@ -1,7 +1,9 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofund/goals-service/internal/dto"
@@ -33,79 +35,358 @@ func NewContributionController(
 }
 
 // CreateContribution handles contribution creation
+//
+// @Summary Start a contribution
+// @Tags contributions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateContributionRequest true "Contribution to start"
+// @Success 201 {object} dto.ContributionIntent
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/contribute [post]
+// @Router /api/v1/contributions [post]
 func (cc *ContributionController) CreateContribution(c *gin.Context) {
-	userIDStr := c.GetHeader("X-User-ID")
-	userID, _ := uuid.Parse(userIDStr)
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 
 	var req dto.CreateContributionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
-	contribution, err := cc.contributionService.CreateContribution(userID, req)
+	contribution, err := cc.contributionService.CreateContribution(c.Request.Context(), userID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, contribution)
+	c.JSON(http.StatusCreated, dto.ContributionIntent{
+		Contribution:         contribution,
+		ChargeAmount:         contribution.ChargeAmount(),
+		WillDiscloseIdentity: cc.contributionService.WillDiscloseIdentity(contribution.Amount),
+	})
 }
 
 // CreateWithdrawal handles withdrawal request creation
+//
+// @Summary Request a withdrawal
+// @Tags withdrawals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateWithdrawalRequest true "Withdrawal to request"
+// @Success 201 {object} models.Withdrawal
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/withdraw [post]
 func (cc *ContributionController) CreateWithdrawal(c *gin.Context) {
-	userIDStr := c.GetHeader("X-User-ID")
-	userID, _ := uuid.Parse(userIDStr)
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 
 	var req dto.CreateWithdrawalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
-	withdrawal, err := cc.withdrawalService.CreateWithdrawal(userID, req)
+	withdrawal, err := cc.withdrawalService.CreateWithdrawal(c.Request.Context(), userID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, withdrawal)
 }
 
+// ApproveWithdrawal handles POST /api/v1/goals/withdrawals/:id/approve
+//
+// @Summary Approve a withdrawal on a co-owned goal
+// @Description Records the caller's approval. Once the goal's required approvals are met
+// @Description the withdrawal is released for payout.
+// @Tags withdrawals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Withdrawal ID"
+// @Success 200 {object} models.Withdrawal
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/withdrawals/{id}/approve [post]
+func (cc *ContributionController) ApproveWithdrawal(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	withdrawalID, err := parseID(c.Param("id"), "withdrawal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	withdrawal, err := cc.withdrawalService.ApproveWithdrawal(c.Request.Context(), withdrawalID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, withdrawal)
+}
+
+// GetWithdrawalHistory handles GET /api/v1/goals/:id/withdrawals
+//
+// @Summary List a goal's withdrawals (owner only)
+// @Description Withdrawals newest first, with account numbers masked to their last four digits.
+// @Tags withdrawals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {object} dto.WithdrawalHistory
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/withdrawals [get]
+func (cc *ContributionController) GetWithdrawalHistory(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	history, err := cc.withdrawalService.GetWithdrawalHistory(c.Request.Context(), goalID, userID, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetWithdrawal handles GET /api/v1/goals/withdrawals/:id
+//
+// @Summary Get a withdrawal with its status history (owner only)
+// @Tags withdrawals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Withdrawal ID"
+// @Success 200 {object} dto.WithdrawalDetail
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/withdrawals/{id} [get]
+func (cc *ContributionController) GetWithdrawal(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	withdrawalID, err := parseID(c.Param("id"), "withdrawal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	detail, err := cc.withdrawalService.GetWithdrawalDetail(c.Request.Context(), withdrawalID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
 // CreateProof handles proof submission
+//
+// @Summary Submit a proof of spending
+// @Tags proofs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateProofRequest true "Proof to submit"
+// @Success 201 {object} models.Proof
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/proofs [post]
 func (cc *ContributionController) CreateProof(c *gin.Context) {
-	userIDStr := c.GetHeader("X-User-ID")
-	userID, _ := uuid.Parse(userIDStr)
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 
 	var req dto.CreateProofRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
-	proof, err := cc.proofService.CreateProof(userID, req)
+	proof, err := cc.proofService.CreateProof(c.Request.Context(), userID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, proof)
 }
 
+// UploadProofMedia handles POST /api/v1/goals/proofs/upload
+//
+// @Summary Upload a proof media file
+// @Description Stores a JPEG or PNG image (10 MB), PDF (20 MB) or MP4 video (100 MB) and
+// @Description returns the URL to cite in a proof's media_urls.
+// @Tags proofs
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Media file"
+// @Success 201 {object} models.ProofMedia
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/proofs/upload [post]
+func (cc *ContributionController) UploadProofMedia(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Leave room for the multipart framing around the largest file accepted
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxProofMediaSize+1<<20)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(c, service.ErrMediaTooLarge)
+			return
+		}
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer file.Close()
+
+	media, err := cc.proofService.UploadProofMedia(c.Request.Context(), userID, file, header.Size)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// DeleteProof handles DELETE /api/v1/goals/proofs/:proofId
+//
+// @Summary Delete a proof no one has voted on (owner only)
+// @Description Also deletes the media files the proof cites.
+// @Tags proofs
+// @Security BearerAuth
+// @Param proofId path string true "Proof ID"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/proofs/{proofId} [delete]
+func (cc *ContributionController) DeleteProof(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	proofID, err := parseID(c.Param("proofId"), "proof")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := cc.proofService.DeleteProof(c.Request.Context(), proofID, userID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Proof deleted"})
+}
+
 // CreateVote handles voting on a proof
+//
+// @Summary Vote on a proof
+// @Tags proofs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateVoteRequest true "Vote to cast"
+// @Success 201 {object} models.Vote
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/votes [post]
 func (cc *ContributionController) CreateVote(c *gin.Context) {
-	userIDStr := c.GetHeader("X-User-ID")
-	userID, _ := uuid.Parse(userIDStr)
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 
 	var req dto.CreateVoteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
-	vote, err := cc.voteService.CreateVote(userID, req)
+	vote, err := cc.voteService.CreateVote(c.Request.Context(), userID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -113,35 +394,147 @@ func (cc *ContributionController) CreateVote(c *gin.Context) {
 }
 
 // GetVoteStats retrieves vote statistics for a proof
+//
+// @Summary Get a proof's vote tally
+// @Tags proofs
+// @Produce json
+// @Param proofId path string true "Proof ID"
+// @Success 200 {object} dto.VoteStats
+// @Failure 400 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/proofs/{proofId}/stats [get]
 func (cc *ContributionController) GetVoteStats(c *gin.Context) {
-	proofIDStr := c.Param("proofId")
-	proofID, err := uuid.Parse(proofIDStr)
+	proofID, err := parseID(c.Param("proofId"), "proof")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	stats, err := cc.voteService.GetVoteStats(c.Request.Context(), proofID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// RetractVote handles DELETE /api/v1/goals/votes/:proofId
+//
+// @Summary Withdraw your vote on a proof
+// @Description Returns the proof's updated vote tally. A verified proof stays verified.
+// @Tags proofs
+// @Produce json
+// @Security BearerAuth
+// @Param proofId path string true "Proof ID"
+// @Success 200 {object} dto.VoteStats
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/votes/{proofId} [delete]
+func (cc *ContributionController) RetractVote(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	proofID, err := parseID(c.Param("proofId"), "proof")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid proof ID"})
+		respondError(c, err)
 		return
 	}
 
-	stats, err := cc.voteService.GetVoteStats(proofID)
+	stats, err := cc.voteService.RetractVote(c.Request.Context(), proofID, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, stats)
 }
 
+// ListProofVotes handles GET /api/v1/goals/proofs/:proofId/votes
+//
+// @Summary List a proof's votes and comments
+// @Description Voters are hidden, except to themselves and, when they opted in, to the goal owner.
+// @Tags proofs
+// @Produce json
+// @Param proofId path string true "Proof ID"
+// @Success 200 {array} dto.ProofVote
+// @Failure 400 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/proofs/{proofId}/votes [get]
+func (cc *ContributionController) ListProofVotes(c *gin.Context) {
+	proofID, err := parseID(c.Param("proofId"), "proof")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	viewerID, _ := uuid.Parse(c.GetHeader("X-User-ID"))
+
+	votes, err := cc.voteService.ListProofVotes(c.Request.Context(), proofID, viewerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, votes)
+}
+
+// GetProof retrieves a proof with its votes and verification status
+//
+// @Summary Get a proof with its votes
+// @Tags proofs
+// @Produce json
+// @Param proofId path string true "Proof ID"
+// @Success 200 {object} models.Proof
+// @Failure 400 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/proofs/{proofId} [get]
+func (cc *ContributionController) GetProof(c *gin.Context) {
+	proofID, err := parseID(c.Param("proofId"), "proof")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	viewerID, _ := uuid.Parse(c.GetHeader("X-User-ID"))
+
+	proof, err := cc.proofService.GetProof(c.Request.Context(), proofID, viewerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, proof)
+}
+
 // GetProofs retrieves all proofs for a goal
+//
+// @Summary List a goal's proofs
+// @Tags proofs
+// @Produce json
+// @Param goalId query string true "Goal ID"
+// @Success 200 {array} models.Proof
+// @Failure 400 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/proofs [get]
 func (cc *ContributionController) GetProofs(c *gin.Context) {
-	goalIDStr := c.Query("goalId")
-	goalID, err := uuid.Parse(goalIDStr)
+	goalID, err := parseID(c.Query("goalId"), "goal")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		respondError(c, err)
 		return
 	}
 
-	proofs, err := cc.proofService.GetProofsByGoal(goalID)
+	proofs, err := cc.proofService.GetProofsByGoal(c.Request.Context(), goalID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -149,35 +542,131 @@ func (cc *ContributionController) GetProofs(c *gin.Context) {
 }
 
 // GetMyContributions retrieves all contributions by the authenticated user
+//
+// @Summary List the caller's contributions
+// @Tags contributions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.ContributionListResponse
+// @Failure 401 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/contributions/my [get]
 func (cc *ContributionController) GetMyContributions(c *gin.Context) {
-	userIDStr := c.GetHeader("X-User-ID")
-	userID, err := uuid.Parse(userIDStr)
+	userID, err := requireUser(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		respondError(c, err)
 		return
 	}
 
-	contributions, err := cc.contributionService.GetContributionsByUser(userID)
+	contributions, err := cc.contributionService.GetContributionsByUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"contributions": contributions, "total": len(contributions)})
+	c.JSON(http.StatusOK, dto.ContributionListResponse{Contributions: contributions, Total: len(contributions)})
+}
+
+// GetContributionFeed returns a goal's confirmed contributions with contributor names
+//
+// @Summary List a goal's confirmed contributions
+// @Tags contributions
+// @Produce json
+// @Param id path string true "Goal ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {object} dto.ContributionFeed
+// @Failure 400 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/contributions [get]
+func (cc *ContributionController) GetContributionFeed(c *gin.Context) {
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	viewerID, _ := uuid.Parse(c.GetHeader("X-User-ID"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	feed, err := cc.contributionService.GetContributionFeed(c.Request.Context(), goalID, viewerID, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// GetOwnerContributions returns a goal's confirmed contributions to its owner, identifying
+// contributors at or above the disclosure threshold
+//
+// @Summary List a goal's contributors (owner only)
+// @Tags contributions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {object} dto.OwnerContributionList
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/contributors [get]
+func (cc *ContributionController) GetOwnerContributions(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	list, err := cc.contributionService.GetOwnerContributions(c.Request.Context(), goalID, userID, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
 }
 
 // GetContribution retrieves a single contribution by ID
+//
+// @Summary Get a contribution
+// @Tags contributions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Contribution ID"
+// @Success 200 {object} models.Contribution
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/contributions/{id} [get]
 func (cc *ContributionController) GetContribution(c *gin.Context) {
-	contributionIDStr := c.Param("id")
-	contributionID, err := uuid.Parse(contributionIDStr)
+	contributionID, err := parseID(c.Param("id"), "contribution")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contribution ID"})
+		respondError(c, err)
 		return
 	}
 
-	contribution, err := cc.contributionService.GetContributionByID(contributionID)
+	viewerID, _ := uuid.Parse(c.GetHeader("X-User-ID"))
+
+	contribution, err := cc.contributionService.GetContributionByID(c.Request.Context(), contributionID, viewerID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Contribution not found"})
+		respondError(c, err)
 		return
 	}
 
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/google/uuid"
+)
+
+// TestContributionIntentWarnsOfDisclosure checks the will_disclose_identity flag a client
+// shows its pre-payment warning from, either side of the threshold
+func TestContributionIntentWarnsOfDisclosure(t *testing.T) {
+	const threshold = 50_000_000
+	db := testdb.Open(t)
+	repo := repository.NewRepository(db)
+	goals := service.NewGoalService(repo, service.NewAuditService(repository.NewAuditLogRepository(db), repo), nil, nil, service.NewInviteTokens("test-invite-secret", time.Hour))
+	controller := NewContributionController(service.NewContributionService(repo, nil, nil, nil, 0, threshold), nil, nil, nil)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/v1/contributions", controller.CreateContribution)
+
+	goal, err := goals.CreateGoal(context.Background(), uuid.New(), dto.CreateGoalRequest{
+		Title:                "Community borehole",
+		TargetAmount:         200_000_000,
+		Currency:             "NGN",
+		DepositBankName:      "Test Bank",
+		DepositAccountNumber: "0123456789",
+		DepositAccountName:   "Ada Obi",
+	})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		amount    int64
+		anonymous bool
+		want      bool
+	}{
+		{name: "just below the threshold", amount: threshold - 1, anonymous: true, want: false},
+		{name: "at the threshold", amount: threshold, anonymous: true, want: true},
+		{name: "at the threshold, not anonymous", amount: threshold, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(t, r, http.MethodPost, "/api/v1/contributions", uuid.New(), dto.CreateContributionRequest{GoalID: goal.ID, Amount: tt.amount, IsAnonymous: tt.anonymous})
+			var intent map[string]interface{}
+			decode(t, w, http.StatusCreated, &intent)
+
+			if got, ok := intent["will_disclose_identity"].(bool); !ok || got != tt.want {
+				t.Errorf("will_disclose_identity = %v, want %v", intent["will_disclose_identity"], tt.want)
+			}
+		})
+	}
+}
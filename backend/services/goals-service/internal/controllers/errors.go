@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/service"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/httperr"
+	"github.com/gofund/shared/identity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	errUnauthenticated = apperrors.Unauthenticated("unauthenticated", "User not authenticated")
+	errNotFound        = apperrors.NotFound("not_found", "record not found")
+)
+
+// respondError writes err in the shared error format (see shared/httperr). The status comes
+// from the error's kind (see shared/errors.HTTPStatus), so controllers never pick one themselves.
+func respondError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = errNotFound
+	}
+
+	var details map[string]interface{}
+	var overAllocated *service.MilestoneOverAllocationError
+	if errors.As(err, &overAllocated) {
+		details = map[string]interface{}{
+			"target_amount":    overAllocated.TargetAmount,
+			"allocated_amount": overAllocated.AllocatedAmount,
+			"overage":          overAllocated.Overage,
+		}
+	}
+	var mediaErr *service.ProofMediaError
+	if errors.As(err, &mediaErr) {
+		details = map[string]interface{}{"media_urls": mediaErr.Invalid}
+	}
+
+	httperr.Respond(c.Writer, c.Request, err, details)
+}
+
+// invalidRequest classifies a request body that failed to bind
+func invalidRequest(err error) error {
+	return apperrors.Validation("invalid_request", err.Error())
+}
+
+// parseID parses a UUID path or query parameter, naming it in the error when it is malformed
+func parseID(value, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, apperrors.Validation("invalid_id", "Invalid "+name+" ID")
+	}
+	return id, nil
+}
+
+// requireUser returns the authenticated user's ID from the X-User-ID header
+func requireUser(c *gin.Context) (uuid.UUID, error) {
+	userID, err := uuid.Parse(c.GetHeader("X-User-ID"))
+	if err != nil {
+		return uuid.Nil, errUnauthenticated
+	}
+	return userID, nil
+}
+
+// hasRole reports whether the X-User-Roles header (set by Nginx after auth verification)
+// includes role
+func hasRole(c *gin.Context, role string) bool {
+	for _, r := range strings.Split(c.GetHeader(identity.HeaderUserRoles), ",") {
+		if strings.TrimSpace(r) == role {
+			return true
+		}
+	}
+	return false
+}
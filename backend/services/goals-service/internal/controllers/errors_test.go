@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/shared/httperr"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// respond runs respondError for err and returns the status and decoded body
+func respond(t *testing.T, err error) (int, httperr.Response) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	respondError(c, err)
+
+	var body httperr.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode error body %s: %v", w.Body.String(), err)
+	}
+	return w.Code, body
+}
+
+// TestRespondErrorStatusClasses pins the status of each class of service error: missing
+// resources are 404, ownership and permission 403, state conflicts 409, validation 400 and
+// missing credentials 401
+func TestRespondErrorStatusClasses(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{service.ErrGoalNotFound, http.StatusNotFound},
+		{service.ErrMilestoneNotFound, http.StatusNotFound},
+		{service.ErrContributionNotFound, http.StatusNotFound},
+		{service.ErrProofNotFound, http.StatusNotFound},
+		{gorm.ErrRecordNotFound, http.StatusNotFound},
+		{fmt.Errorf("loading goal: %w", gorm.ErrRecordNotFound), http.StatusNotFound},
+		{service.ErrUnauthorized, http.StatusForbidden},
+		{service.ErrNotContributor, http.StatusForbidden},
+		{service.ErrKYCRequired, http.StatusForbidden},
+		{service.ErrGoalNotOpen, http.StatusConflict},
+		{service.ErrInvalidGoalStatus, http.StatusConflict},
+		{service.ErrAlreadyVoted, http.StatusConflict},
+		{service.ErrRefundInProgress, http.StatusConflict},
+		{service.ErrInsufficientBalance, http.StatusConflict},
+		{service.ErrGoalSuspended, http.StatusConflict},
+		{service.ErrGoalTargetReached, http.StatusConflict},
+		{service.ErrInvalidAmount, http.StatusBadRequest},
+		{service.ErrFixedAmountMismatch.WithDetail("must be exactly 5000"), http.StatusBadRequest},
+		{service.ErrCurrencyMismatch, http.StatusBadRequest},
+		{invalidRequest(errors.New("unexpected EOF")), http.StatusBadRequest},
+		{errUnauthenticated, http.StatusUnauthorized},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			status, body := respond(t, tt.err)
+			if status != tt.want {
+				t.Errorf("status = %d, want %d", status, tt.want)
+			}
+			if body.Code == "" || body.Error == "" || body.Error != body.Message {
+				t.Errorf("body = %+v, want a code and matching error and message", body)
+			}
+		})
+	}
+}
+
+func TestRespondErrorOverAllocationDetails(t *testing.T) {
+	status, body := respond(t, &service.MilestoneOverAllocationError{TargetAmount: 100_000, AllocatedAmount: 110_000, Overage: 10_000})
+
+	if status != http.StatusBadRequest || body.Code != "milestone_targets_exceed_goal" {
+		t.Fatalf("status %d, code %q; want 400 milestone_targets_exceed_goal", status, body.Code)
+	}
+	if body.Details["overage"] != float64(10_000) {
+		t.Errorf("details = %v, want the overage", body.Details)
+	}
+}
+
+// newStatusTestRouter serves the milestone completion and contribution routes over a test
+// database, with the goal service to arrange state through
+func newStatusTestRouter(t *testing.T) (*gin.Engine, *service.GoalService) {
+	t.Helper()
+	db := testdb.Open(t)
+	repo := repository.NewRepository(db)
+	audit := service.NewAuditService(repository.NewAuditLogRepository(db), repo)
+	goals := service.NewGoalService(repo, audit, nil, nil, service.NewInviteTokens("test-invite-secret", time.Hour))
+	goalController := NewGoalController(goals, nil)
+	contributionController := NewContributionController(service.NewContributionService(repo, nil, nil, nil, 0, 0), nil, nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/v1/goals/milestones/:milestoneId/complete", goalController.CompleteMilestone)
+	r.POST("/api/v1/contributions", contributionController.CreateContribution)
+	return r, goals
+}
+
+func createStatusTestGoal(t *testing.T, goals *service.GoalService, ownerID uuid.UUID) *models.Goal {
+	t.Helper()
+	goal, err := goals.CreateGoal(context.Background(), ownerID, dto.CreateGoalRequest{
+		Title:                "Community borehole",
+		TargetAmount:         1_000_000,
+		Currency:             "NGN",
+		DepositBankName:      "Test Bank",
+		DepositAccountNumber: "0123456789",
+		DepositAccountName:   "Ada Obi",
+		Milestones:           []dto.CreateMilestoneRequest{{Title: "Drilling"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	return goal
+}
+
+// TestEndpointStatusCodes is the per-endpoint contract for the cases that used to answer
+// 400 for everything
+func TestEndpointStatusCodes(t *testing.T) {
+	r, goals := newStatusTestRouter(t)
+	ownerID := uuid.New()
+	goal := createStatusTestGoal(t, goals, ownerID)
+	suspended := createStatusTestGoal(t, goals, ownerID)
+	if _, err := goals.SuspendGoal(context.Background(), suspended.ID, uuid.New(), "under review"); err != nil {
+		t.Fatalf("SuspendGoal: %v", err)
+	}
+	milestonePath := "/api/v1/goals/milestones/" + goal.Milestones[0].ID.String() + "/complete"
+
+	tests := []struct {
+		name         string
+		method, path string
+		userID       uuid.UUID
+		body         interface{}
+		want         int
+	}{
+		{"complete milestone anonymously", http.MethodPost, milestonePath, uuid.Nil, nil, http.StatusUnauthorized},
+		{"complete malformed milestone ID", http.MethodPost, "/api/v1/goals/milestones/not-a-uuid/complete", ownerID, nil, http.StatusBadRequest},
+		{"complete missing milestone", http.MethodPost, "/api/v1/goals/milestones/" + uuid.NewString() + "/complete", ownerID, nil, http.StatusNotFound},
+		{"complete another owner's milestone", http.MethodPost, milestonePath, uuid.New(), nil, http.StatusForbidden},
+		{"contribute anonymously", http.MethodPost, "/api/v1/contributions", uuid.Nil, dto.CreateContributionRequest{GoalID: goal.ID, Amount: 5_000}, http.StatusUnauthorized},
+		{"contribute with a malformed body", http.MethodPost, "/api/v1/contributions", uuid.New(), "not an object", http.StatusBadRequest},
+		{"contribute a zero amount", http.MethodPost, "/api/v1/contributions", uuid.New(), dto.CreateContributionRequest{GoalID: goal.ID}, http.StatusBadRequest},
+		{"contribute to a missing goal", http.MethodPost, "/api/v1/contributions", uuid.New(), dto.CreateContributionRequest{GoalID: uuid.New(), Amount: 5_000}, http.StatusNotFound},
+		{"contribute to a suspended goal", http.MethodPost, "/api/v1/contributions", uuid.New(), dto.CreateContributionRequest{GoalID: suspended.ID, Amount: 5_000}, http.StatusConflict},
+		{"contribute to an open goal", http.MethodPost, "/api/v1/contributions", uuid.New(), dto.CreateContributionRequest{GoalID: goal.ID, Amount: 5_000}, http.StatusCreated},
+		{"complete own milestone", http.MethodPost, milestonePath, ownerID, nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve(t, r, tt.method, tt.path, tt.userID, tt.body); w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/export"
+	"github.com/gofund/goals-service/internal/service"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/logger"
+)
+
+// exportFlushEvery is how many rows are written between flushes to the client
+const exportFlushEvery = 500
+
+// ExportController serves goal exports for spreadsheets
+type ExportController struct {
+	exportService *service.ExportService
+}
+
+// NewExportController creates a new export controller instance
+func NewExportController(exportService *service.ExportService) *ExportController {
+	return &ExportController{
+		exportService: exportService,
+	}
+}
+
+// ExportGoal handles GET /api/v1/goals/:id/export. The export is streamed, so an error
+// after the first row cannot change the status; the response is cut short instead.
+//
+// @Summary Export a goal's contributions and withdrawals (owner only)
+// @Tags goals
+// @Produce text/csv,json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param type query string false "contributions, withdrawals or all" default(all)
+// @Param format query string false "csv or json" default(csv)
+// @Success 200 {string} string "CSV with a header row, or a JSON array"
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/export [get]
+func (ec *ExportController) ExportGoal(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	exportType := c.DefaultQuery("type", service.ExportAll)
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != export.FormatJSON {
+		respondError(c, apperrors.Validation("invalid_export_format", "format must be csv or json"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	goal, err := ec.exportService.PrepareExport(ctx, goalID, userID, exportType)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	name := goal.ID.String()
+	if goal.Slug != nil {
+		name = *goal.Slug
+	}
+	filename := fmt.Sprintf("gofund-%s-%s-%s.%s", name, exportType, time.Now().UTC().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", export.ContentType(format))
+	c.Status(http.StatusOK)
+
+	writer, err := export.NewRowWriter(format, c.Writer)
+	if err != nil {
+		logger.Printf(ctx, "Failed to start export of goal %s: %v", goal.ID, err)
+		return
+	}
+
+	rows := 0
+	err = ec.exportService.StreamExport(ctx, goal, exportType, func(row dto.ExportRow) error {
+		if err := writer.WriteRow(row); err != nil {
+			return err
+		}
+		rows++
+		if rows%exportFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Printf(ctx, "Export of goal %s stopped after %d rows: %v", goal.ID, rows, err)
+		return
+	}
+	if err := writer.Close(); err != nil {
+		logger.Printf(ctx, "Failed to finish export of goal %s: %v", goal.ID, err)
+	}
+}
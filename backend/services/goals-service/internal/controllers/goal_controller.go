@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"net/http"
 
 	"strconv"
@@ -8,132 +9,491 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gofund/goals-service/internal/dto"
 	"github.com/gofund/goals-service/internal/service"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/models"
 	"github.com/google/uuid"
 )
 
 // GoalController handles goal-related endpoints
 type GoalController struct {
-	goalService *service.GoalService
+	goalService     *service.GoalService
+	trendingService *service.TrendingService
 }
 
 // NewGoalController creates a new goal controller instance
-func NewGoalController(goalService *service.GoalService) *GoalController {
+func NewGoalController(goalService *service.GoalService, trendingService *service.TrendingService) *GoalController {
 	return &GoalController{
-		goalService: goalService,
+		goalService:     goalService,
+		trendingService: trendingService,
 	}
 }
 
 // ListPublicGoals handles retrieving public goals with pagination
+//
+// @Summary List public goals
+// @Tags goals
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param sort query string false "Sort order" Enums(newest, most_funded, most_popular, ending_soon)
+// @Success 200 {object} dto.PublicGoalListResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals [get]
+// @Router /api/v1/goals/list [get]
 func (gc *GoalController) ListPublicGoals(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
 
-	goals, total, err := gc.goalService.ListPublicGoals(page, pageSize)
+	goals, total, err := gc.goalService.ListPublicGoals(c.Request.Context(), c.Query("sort"), page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  goals,
-		"total": total,
-		"page":  page,
-		"size":  pageSize,
+	c.JSON(http.StatusOK, dto.PublicGoalListResponse{
+		Data:  publicGoalResponses(goals),
+		Total: total,
+		Page:  page,
+		Size:  pageSize,
 	})
 }
 
-// CreateGoal handles goal creation
-func (gc *GoalController) CreateGoal(c *gin.Context) {
-	userIDStr := c.GetHeader("X-User-ID")
-	if userIDStr == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+// SearchGoals ranks listed goals by relevance to a search
+//
+// @Summary Search goals
+// @Description Full-text search over goal titles and descriptions, title matches ranking higher, boosted for open, well-funded and recent goals. Queries with characters other than letters and digits fall back to a substring match.
+// @Tags goals
+// @Produce json
+// @Param q query string true "Search text, at most 200 characters"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size, at most 50" default(10)
+// @Success 200 {object} dto.GoalSearchResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/search [get]
+func (gc *GoalController) SearchGoals(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	hits, total, err := gc.goalService.SearchGoals(c.Request.Context(), c.Query("q"), page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GoalSearchResponse{
+		Data:  goalSearchResults(hits),
+		Total: total,
+		Page:  page,
+		Size:  pageSize,
+	})
+}
+
+// GetTrendingGoals returns goals gaining momentum, most trending first
+//
+// @Summary List trending goals
+// @Tags goals
+// @Produce json
+// @Param limit query int false "Maximum number of goals" default(10)
+// @Success 200 {object} dto.TrendingGoals
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/trending [get]
+func (gc *GoalController) GetTrendingGoals(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	trending, err := gc.trendingService.GetTrendingGoals(c.Request.Context(), limit)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	userID, err := uuid.Parse(userIDStr)
+	c.JSON(http.StatusOK, trending)
+}
+
+// CreateGoal handles goal creation
+//
+// @Summary Create a goal
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateGoalRequest true "Goal to create"
+// @Success 201 {object} models.Goal
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals [post]
+func (gc *GoalController) CreateGoal(c *gin.Context) {
+	userID, err := requireUser(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		respondError(c, err)
 		return
 	}
 
 	var req dto.CreateGoalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
-	goal, err := gc.goalService.CreateGoal(userID, req)
+	goal, err := gc.goalService.CreateGoal(c.Request.Context(), userID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, goal)
 }
 
-// GetGoal retrieves a goal by ID
+// GetGoal retrieves a goal by ID. The owner gets the full detail (dto.OwnerGoalResponse);
+// everyone else a dto.PublicGoalResponse without banking details or withdrawals.
+//
+// @Summary Get a goal
+// @Tags goals
+// @Produce json
+// @Param id path string true "Goal ID"
+// @Param invite query string false "Invite link token, required for private goals the caller is not a member of"
+// @Success 200 {object} dto.PublicGoalResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id} [get]
+// @Router /api/v1/goals/view/{id} [get]
 func (gc *GoalController) GetGoal(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := uuid.Parse(idStr)
+	id, err := parseID(c.Param("id"), "goal")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		respondError(c, err)
 		return
 	}
 
-	goal, err := gc.goalService.GetGoal(id)
+	viewerID, _ := uuid.Parse(c.GetHeader("X-User-ID"))
+
+	goal, err := gc.goalService.GetGoal(c.Request.Context(), id, viewerID, c.Query("invite"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, goalResponse(goal, viewerID))
+}
+
+// GetGoalBySlug retrieves a goal by its shareable slug, presented like GetGoal
+//
+// @Summary Get a goal by slug
+// @Tags goals
+// @Produce json
+// @Param slug path string true "Goal slug"
+// @Param invite query string false "Invite link token, required for private goals the caller is not a member of"
+// @Success 200 {object} dto.PublicGoalResponse
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/slug/{slug} [get]
+func (gc *GoalController) GetGoalBySlug(c *gin.Context) {
+	viewerID, _ := uuid.Parse(c.GetHeader("X-User-ID"))
+
+	goal, err := gc.goalService.GetGoalBySlug(c.Request.Context(), c.Param("slug"), viewerID, c.Query("invite"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, goalResponse(goal, viewerID))
+}
+
+// GetShareMeta handles GET /api/v1/goals/:id/share-meta, the fields link previews render
+//
+// @Summary Get a goal's link preview metadata
+// @Tags goals
+// @Produce json
+// @Param id path string true "Goal ID"
+// @Success 200 {object} dto.GoalShareMeta
+// @Failure 400 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/share-meta [get]
+func (gc *GoalController) GetShareMeta(c *gin.Context) {
+	id, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	viewerID, _ := uuid.Parse(c.GetHeader("X-User-ID"))
+
+	meta, err := gc.goalService.GetShareMeta(c.Request.Context(), id, viewerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, meta)
+}
+
+// CreateGoalInvite handles POST /api/v1/goals/:id/invites, issuing an invite link token
+// that opens the goal, and lets its holder contribute, even when the goal is private
+//
+// @Summary Create a goal invite link
+// @Tags goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 201 {object} dto.GoalInvite
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/invites [post]
+func (gc *GoalController) CreateGoalInvite(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	id, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	invite, err := gc.goalService.CreateGoalInvite(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invite)
+}
+
+// GetInternalGoal handles GET /internal/goals/:id, used by other services (e.g.
+// payments-service) to check who owns a goal
+//
+// @Summary Get a goal's owner and status (service to service)
+// @Tags internal
+// @Produce json
+// @Param id path string true "Goal ID"
+// @Success 200 {object} dto.InternalGoal
+// @Failure 400 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /internal/goals/{id} [get]
+func (gc *GoalController) GetInternalGoal(c *gin.Context) {
+	id, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goal, err := gc.goalService.GetInternalGoal(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, goal)
 }
 
+// GetInternalOwnerGoals handles GET /internal/users/:id/goals, used by other services
+// (e.g. users-service public profiles) to count the goals a user owns
+//
+// @Summary Summarise a user's goals (service to service)
+// @Tags internal
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.InternalOwnerGoals
+// @Failure 400 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /internal/users/{id}/goals [get]
+func (gc *GoalController) GetInternalOwnerGoals(c *gin.Context) {
+	ownerID, err := parseID(c.Param("id"), "user")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	summary, err := gc.goalService.GetInternalOwnerGoals(c.Request.Context(), ownerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
 // UpdateGoal updates a goal
+//
+// @Summary Update a goal
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param request body dto.UpdateGoalRequest true "Fields to change"
+// @Success 200 {object} models.Goal
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id} [patch]
 func (gc *GoalController) UpdateGoal(c *gin.Context) {
-	userIDStr := c.GetHeader("X-User-ID")
-	userID, _ := uuid.Parse(userIDStr)
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 
-	idStr := c.Param("id")
-	id, err := uuid.Parse(idStr)
+	id, err := parseID(c.Param("id"), "goal")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		respondError(c, err)
 		return
 	}
 
 	var req dto.UpdateGoalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
-	goal, err := gc.goalService.UpdateGoal(id, userID, req)
+	goal, err := gc.goalService.UpdateGoal(c.Request.Context(), id, userID, req)
 	if err != nil {
-		status := http.StatusBadRequest
-		if err == service.ErrUnauthorized {
-			status = http.StatusForbidden
-		} else if err == service.ErrGoalNotFound {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, goal)
 }
 
+// CloseGoal closes a goal to new contributions
+//
+// @Summary Close a goal to new contributions
+// @Tags goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} models.Goal
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/close [post]
+func (gc *GoalController) CloseGoal(c *gin.Context) {
+	gc.transitionGoal(c, gc.goalService.CloseGoal)
+}
+
+// CancelGoal cancels a goal so contributions can be refunded
+//
+// @Summary Cancel a goal
+// @Tags goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} models.Goal
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/cancel [post]
+func (gc *GoalController) CancelGoal(c *gin.Context) {
+	gc.transitionGoal(c, gc.goalService.CancelGoal)
+}
+
+// transitionGoal runs an owner-only status change
+func (gc *GoalController) transitionGoal(c *gin.Context, transition func(ctx context.Context, goalID, userID uuid.UUID) (*models.Goal, error)) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	id, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goal, err := transition(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// DeleteGoal deletes a goal, or archives it if money has moved or may still move through it
+//
+// @Summary Delete or archive a goal
+// @Tags goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} dto.DeleteGoalResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id} [delete]
+func (gc *GoalController) DeleteGoal(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	id, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	archived, err := gc.goalService.DeleteGoal(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if archived != nil {
+		c.JSON(http.StatusOK, dto.DeleteGoalResponse{Message: "Goal has contributions or withdrawals and was archived instead of deleted", Goal: archived})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeleteGoalResponse{Message: "Goal deleted"})
+}
+
 // GetGoalProgress returns progress information for a goal
+//
+// @Summary Get a goal's funding progress
+// @Tags goals
+// @Produce json
+// @Param id path string true "Goal ID"
+// @Param invite query string false "Invite link token, required for private goals the caller is not a member of"
+// @Success 200 {object} dto.GoalProgress
+// @Failure 400 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/progress [get]
 func (gc *GoalController) GetGoalProgress(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := uuid.Parse(idStr)
+	id, err := parseID(c.Param("id"), "goal")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		respondError(c, err)
 		return
 	}
 
-	progress, err := gc.goalService.GetGoalProgress(id)
+	viewerID, _ := uuid.Parse(c.GetHeader("X-User-ID"))
+
+	progress, err := gc.goalService.GetGoalProgress(c.Request.Context(), id, viewerID, c.Query("invite"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -141,96 +501,255 @@ func (gc *GoalController) GetGoalProgress(c *gin.Context) {
 }
 
 // CreateMilestone creates a new milestone for a goal
+//
+// @Summary Add a milestone to a goal
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param request body dto.CreateMilestoneRequest true "Milestone to create"
+// @Success 201 {object} dto.CreatedMilestoneResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/milestones [post]
 func (gc *GoalController) CreateMilestone(c *gin.Context) {
-	userIDStr := c.GetHeader("X-User-ID")
-	userID, _ := uuid.Parse(userIDStr)
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 
-	idStr := c.Param("id")
-	id, err := uuid.Parse(idStr)
+	id, err := parseID(c.Param("id"), "goal")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		respondError(c, err)
 		return
 	}
 
 	var req dto.CreateMilestoneRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
-	milestone, err := gc.goalService.CreateMilestone(id, userID, req)
+	created, err := gc.goalService.CreateMilestone(c.Request.Context(), id, userID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, milestone)
+	c.JSON(http.StatusCreated, dto.CreatedMilestoneResponse{
+		Milestone:  created.Milestone,
+		Allocation: created.Allocation,
+	})
 }
 
 // CompleteMilestone marks a milestone as completed
+//
+// @Summary Complete a milestone
+// @Tags milestones
+// @Produce json
+// @Security BearerAuth
+// @Param milestoneId path string true "Milestone ID"
+// @Success 200 {object} dto.CompletedMilestoneResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/milestones/{milestoneId}/complete [post]
 func (gc *GoalController) CompleteMilestone(c *gin.Context) {
-	userIDStr := c.GetHeader("X-User-ID")
-	userID, _ := uuid.Parse(userIDStr)
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	milestoneID, err := parseID(c.Param("milestoneId"), "milestone")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	milestone, nextMilestone, err := gc.goalService.CompleteMilestone(c.Request.Context(), milestoneID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CompletedMilestoneResponse{
+		Completed: milestone,
+		Next:      nextMilestone,
+	})
+}
+
+// UpdateMilestone edits a milestone
+//
+// @Summary Edit a milestone
+// @Description Edit a milestone's title, description or target amount. With ApplyToFuture, the later not yet completed instances of a recurring milestone are edited too.
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param milestoneId path string true "Milestone ID"
+// @Param request body dto.UpdateMilestoneRequest true "Fields to change"
+// @Success 200 {object} dto.UpdatedMilestoneResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/milestones/{milestoneId} [patch]
+func (gc *GoalController) UpdateMilestone(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	milestoneID, err := parseID(c.Param("milestoneId"), "milestone")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req dto.UpdateMilestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
 
-	milestoneIDStr := c.Param("milestoneId")
-	milestoneID, err := uuid.Parse(milestoneIDStr)
+	updated, err := gc.goalService.UpdateMilestone(c.Request.Context(), milestoneID, userID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid milestone ID"})
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.UpdatedMilestoneResponse{
+		Milestone:  updated.Milestone,
+		Propagated: updated.Propagated,
+		Allocation: updated.Allocation,
+	})
+}
+
+// DeleteMilestone deletes a milestone
+//
+// @Summary Delete a milestone
+// @Description Delete a milestone without withdrawals. One with confirmed contributions is only deleted with mode=merge, which moves its contributions to the goal as a whole.
+// @Tags milestones
+// @Produce json
+// @Security BearerAuth
+// @Param milestoneId path string true "Milestone ID"
+// @Param mode query string false "merge to move confirmed contributions to the goal"
+// @Success 200 {object} dto.DeletedMilestoneResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/milestones/{milestoneId} [delete]
+func (gc *GoalController) DeleteMilestone(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	milestoneID, err := parseID(c.Param("milestoneId"), "milestone")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var merge bool
+	switch mode := c.Query("mode"); mode {
+	case "":
+	case "merge":
+		merge = true
+	default:
+		respondError(c, apperrors.Validation("invalid_delete_mode", "mode must be merge or omitted"))
 		return
 	}
 
-	milestone, nextMilestone, err := gc.goalService.CompleteMilestone(milestoneID, userID)
+	reassigned, err := gc.goalService.DeleteMilestone(c.Request.Context(), milestoneID, userID, merge)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"completed": milestone,
-		"next":      nextMilestone,
+	c.JSON(http.StatusOK, dto.DeletedMilestoneResponse{
+		Message:                 "Milestone deleted successfully",
+		ReassignedContributions: reassigned,
 	})
 }
 
 // GetMyGoals retrieves all goals created by the authenticated user
+//
+// @Summary List the caller's goals
+// @Tags goals
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page size" default(20)
+// @Success 200 {object} dto.MyGoalsResponse
+// @Failure 401 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/my [get]
 func (gc *GoalController) GetMyGoals(c *gin.Context) {
-	userIDStr := c.GetHeader("X-User-ID")
-	userID, err := uuid.Parse(userIDStr)
+	userID, err := requireUser(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		respondError(c, err)
 		return
 	}
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
-	goals, total, err := gc.goalService.ListUserGoals(userID, page, pageSize)
+	goals, total, err := gc.goalService.ListUserGoals(c.Request.Context(), userID, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"goals": goals,
-		"total": total,
-		"page":  page,
-		"limit": pageSize,
+	c.JSON(http.StatusOK, dto.MyGoalsResponse{
+		Goals: goals,
+		Total: total,
+		Page:  page,
+		Limit: pageSize,
 	})
 }
 
 // GetGoalMilestones retrieves all milestones for a goal
+//
+// @Summary List a goal's milestones
+// @Tags milestones
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} dto.MilestoneListResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/milestones [get]
 func (gc *GoalController) GetGoalMilestones(c *gin.Context) {
-	goalIDStr := c.Param("goalId")
-	goalID, err := uuid.Parse(goalIDStr)
+	goalID, err := parseID(c.Param("id"), "goal")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		respondError(c, err)
 		return
 	}
 
-	milestones, err := gc.goalService.GetGoalMilestones(goalID)
+	milestones, err := gc.goalService.GetGoalMilestones(c.Request.Context(), goalID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"milestones": milestones})
+	c.JSON(http.StatusOK, dto.MilestoneListResponse{Milestones: milestones})
 }
@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// newGoalTestRouter serves the goal create, list and get routes over a test database
+func newGoalTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	db := testdb.Open(t)
+	repo := repository.NewRepository(db)
+	audit := service.NewAuditService(repository.NewAuditLogRepository(db), repo)
+	goals := service.NewGoalService(repo, audit, nil, nil, service.NewInviteTokens("test-invite-secret", time.Hour))
+	controller := NewGoalController(goals, nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/v1/goals", controller.CreateGoal)
+	r.GET("/api/v1/goals", controller.ListPublicGoals)
+	r.GET("/api/v1/goals/:id", controller.GetGoal)
+	return r
+}
+
+// serve sends a request to r as userID (anonymous when nil) with body encoded as JSON
+func serve(t *testing.T, r http.Handler, method, path string, userID uuid.UUID, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatalf("failed to encode request: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	if userID != uuid.Nil {
+		req.Header.Set("X-User-ID", userID.String())
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// decode decodes a response with the wanted status into v
+func decode(t *testing.T, w *httptest.ResponseRecorder, status int, v interface{}) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d: %s", w.Code, status, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("failed to decode response: %v: %s", err, w.Body.String())
+	}
+}
+
+// TestGoalCreateListGet builds goals through the create, list and get endpoints with the
+// request and response DTOs, so a field renamed on one side of the model, service and
+// DTOs but not the others fails here.
+func TestGoalCreateListGet(t *testing.T) {
+	r := newGoalTestRouter(t)
+	ownerID := uuid.New()
+
+	public, private := true, false
+	create := dto.CreateGoalRequest{
+		Title:                   "Community borehole",
+		Description:             "Clean water for the street",
+		TargetAmount:            1_000_000,
+		Currency:                "NGN",
+		DepositBankName:         "Test Bank",
+		DepositAccountNumber:    "0123456789",
+		DepositAccountName:      "Ada Obi",
+		IsPublic:                &public,
+		FixedContributionAmount: 50_000,
+		Milestones: []dto.CreateMilestoneRequest{
+			{Title: "Drilling", TargetAmount: 600_000, OrderIndex: 0},
+			{Title: "Pump", TargetAmount: 400_000, OrderIndex: 1},
+		},
+	}
+
+	var created models.Goal
+	decode(t, serve(t, r, http.MethodPost, "/api/v1/goals", ownerID, create), http.StatusCreated, &created)
+	if created.OwnerID != ownerID {
+		t.Errorf("owner = %s, want %s", created.OwnerID, ownerID)
+	}
+	if created.DepositBankName != create.DepositBankName ||
+		created.DepositAccountNumber != create.DepositAccountNumber ||
+		created.DepositAccountName != create.DepositAccountName {
+		t.Errorf("deposit account = %q/%q/%q, want %q/%q/%q",
+			created.DepositBankName, created.DepositAccountNumber, created.DepositAccountName,
+			create.DepositBankName, create.DepositAccountNumber, create.DepositAccountName)
+	}
+	if !created.IsPublic || created.Visibility != models.GoalVisibilityPublic {
+		t.Errorf("is_public = %v, visibility = %s; want a public goal", created.IsPublic, created.Visibility)
+	}
+	if created.FixedContributionAmount != create.FixedContributionAmount {
+		t.Errorf("fixed contribution amount = %d, want %d", created.FixedContributionAmount, create.FixedContributionAmount)
+	}
+	if len(created.Milestones) != 2 {
+		t.Errorf("milestones = %d, want 2", len(created.Milestones))
+	}
+
+	hidden := create
+	hidden.Title = "Private savings"
+	hidden.IsPublic = &private
+	hidden.Milestones = nil
+	var createdPrivate models.Goal
+	decode(t, serve(t, r, http.MethodPost, "/api/v1/goals", ownerID, hidden), http.StatusCreated, &createdPrivate)
+	if createdPrivate.IsPublic {
+		t.Errorf("goal created with IsPublic false is public")
+	}
+
+	// The public list holds only the public goal, without its deposit account
+	w := serve(t, r, http.MethodGet, "/api/v1/goals", uuid.Nil, nil)
+	var list dto.PublicGoalListResponse
+	decode(t, w, http.StatusOK, &list)
+	if list.Total != 1 || len(list.Data) != 1 || list.Data[0].ID != created.ID {
+		t.Fatalf("public list = %d goals (total %d), want only %s", len(list.Data), list.Total, created.ID)
+	}
+	if listed := list.Data[0]; listed.Title != create.Title || listed.FixedContributionAmount != create.FixedContributionAmount || !listed.IsPublic {
+		t.Errorf("listed goal = %+v, want the created goal", listed)
+	}
+	assertNoDepositAccount(t, w.Body.String())
+
+	// The owner gets the deposit account back
+	var owned dto.OwnerGoalResponse
+	decode(t, serve(t, r, http.MethodGet, "/api/v1/goals/"+created.ID.String(), ownerID, nil), http.StatusOK, &owned)
+	if owned.ID != created.ID || owned.DepositAccountNumber != create.DepositAccountNumber {
+		t.Errorf("owner view = %s with account %q, want %s with %q",
+			owned.ID, owned.DepositAccountNumber, created.ID, create.DepositAccountNumber)
+	}
+
+	// Anyone else gets the public view, and cannot find the private goal
+	w = serve(t, r, http.MethodGet, "/api/v1/goals/"+created.ID.String(), uuid.New(), nil)
+	var viewed dto.PublicGoalResponse
+	decode(t, w, http.StatusOK, &viewed)
+	if viewed.ID != created.ID || len(viewed.Milestones) != 2 {
+		t.Errorf("public view = %s with %d milestones, want %s with 2", viewed.ID, len(viewed.Milestones), created.ID)
+	}
+	assertNoDepositAccount(t, w.Body.String())
+
+	if w := serve(t, r, http.MethodGet, "/api/v1/goals/"+createdPrivate.ID.String(), uuid.New(), nil); w.Code != http.StatusNotFound {
+		t.Errorf("private goal for a stranger: status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+// assertNoDepositAccount fails when a response body carries deposit account details
+func assertNoDepositAccount(t *testing.T, body string) {
+	t.Helper()
+	for _, key := range []string{"deposit_bank_name", "deposit_account_number", "deposit_account_name", "0123456789"} {
+		if strings.Contains(body, key) {
+			t.Errorf("response carries %q: %s", key, body)
+		}
+	}
+}
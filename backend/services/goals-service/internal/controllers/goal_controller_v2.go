@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/presenters"
+	"github.com/gofund/goals-service/internal/service"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/google/uuid"
+)
+
+const maxPageSizeV2 = 100
+
+// GoalControllerV2 serves the v2 goal endpoints. It is a thin adapter over the same
+// GoalService as v1: it parses and validates input, calls the service and shapes the
+// result with the presenters. Adding a v2 endpoint means adding a method here and a route.
+type GoalControllerV2 struct {
+	goalService *service.GoalService
+}
+
+// NewGoalControllerV2 creates a new v2 goal controller instance
+func NewGoalControllerV2(goalService *service.GoalService) *GoalControllerV2 {
+	return &GoalControllerV2{goalService: goalService}
+}
+
+// ListGoals lists public goals
+//
+// @Summary List public goals
+// @Tags goals-v2
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param sort query string false "Sort order" Enums(newest, most_funded, most_popular, ending_soon)
+// @Success 200 {object} presenters.GoalList
+// @Failure 400 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v2/goals [get]
+func (gc *GoalControllerV2) ListGoals(c *gin.Context) {
+	page, pageSize, err := parsePagination(c, 10)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goals, total, err := gc.goalService.ListPublicGoals(c.Request.Context(), c.Query("sort"), page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, presenters.NewGoalList(goals, page, pageSize, total))
+}
+
+// GetGoal retrieves a goal by ID
+//
+// @Summary Get a goal
+// @Tags goals-v2
+// @Produce json
+// @Param id path string true "Goal ID"
+// @Param invite query string false "Invite link token, required for private goals the caller is not a member of"
+// @Success 200 {object} presenters.Goal
+// @Failure 400 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v2/goals/{id} [get]
+func (gc *GoalControllerV2) GetGoal(c *gin.Context) {
+	id, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	viewerID, _ := uuid.Parse(c.GetHeader("X-User-ID"))
+
+	goal, err := gc.goalService.GetGoal(c.Request.Context(), id, viewerID, c.Query("invite"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, presenters.NewGoal(goal))
+}
+
+// GetGoalProgress retrieves a goal's funding progress
+//
+// @Summary Get a goal's funding progress
+// @Tags goals-v2
+// @Produce json
+// @Param id path string true "Goal ID"
+// @Param invite query string false "Invite link token, required for private goals the caller is not a member of"
+// @Success 200 {object} presenters.GoalProgress
+// @Failure 400 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v2/goals/{id}/progress [get]
+func (gc *GoalControllerV2) GetGoalProgress(c *gin.Context) {
+	id, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	viewerID, _ := uuid.Parse(c.GetHeader("X-User-ID"))
+
+	progress, err := gc.goalService.GetGoalProgress(c.Request.Context(), id, viewerID, c.Query("invite"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, presenters.NewGoalProgress(progress))
+}
+
+// parsePagination reads the v2 page and page_size query parameters. Unlike v1, which
+// silently falls back to defaults, malformed or out-of-range values are rejected.
+func parsePagination(c *gin.Context, defaultPageSize int) (page, pageSize int, err error) {
+	page, pageSize = 1, defaultPageSize
+
+	if value := c.Query("page"); value != "" {
+		if page, err = strconv.Atoi(value); err != nil || page < 1 {
+			return 0, 0, apperrors.Validation("invalid_pagination", "page must be a positive integer")
+		}
+	}
+	if value := c.Query("page_size"); value != "" {
+		if pageSize, err = strconv.Atoi(value); err != nil || pageSize < 1 || pageSize > maxPageSizeV2 {
+			return 0, 0, apperrors.Validation("invalid_pagination", "page_size must be between 1 and "+strconv.Itoa(maxPageSizeV2))
+		}
+	}
+	return page, pageSize, nil
+}
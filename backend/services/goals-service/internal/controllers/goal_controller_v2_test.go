@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/middleware"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// apiVersion reads the parts of a version's goal read responses the shared assertions
+// compare, so the same checks run against both versions
+type apiVersion struct {
+	name   string
+	prefix string
+	// listItems returns the goals and total of a list response
+	listItems func(body map[string]interface{}) ([]interface{}, float64)
+	// progress returns the goal and total contributions of a progress response
+	progress func(body map[string]interface{}) (map[string]interface{}, float64)
+}
+
+var apiVersions = []apiVersion{
+	{
+		name:   "v1",
+		prefix: "/api/v1/goals",
+		listItems: func(body map[string]interface{}) ([]interface{}, float64) {
+			items, _ := body["data"].([]interface{})
+			total, _ := body["total"].(float64)
+			return items, total
+		},
+		progress: func(body map[string]interface{}) (map[string]interface{}, float64) {
+			goal, _ := body["Goal"].(map[string]interface{})
+			total, _ := body["TotalContributions"].(float64)
+			return goal, total
+		},
+	},
+	{
+		name:   "v2",
+		prefix: "/api/v2/goals",
+		listItems: func(body map[string]interface{}) ([]interface{}, float64) {
+			items, _ := body["items"].([]interface{})
+			pagination, _ := body["pagination"].(map[string]interface{})
+			total, _ := pagination["total"].(float64)
+			return items, total
+		},
+		progress: func(body map[string]interface{}) (map[string]interface{}, float64) {
+			goal, _ := body["goal"].(map[string]interface{})
+			total, _ := body["total_contributions"].(float64)
+			return goal, total
+		},
+	},
+}
+
+// newVersionedTestRouter mounts the goal read endpoints under both versions as the service
+// does, with a public goal holding one confirmed contribution and a private goal beside it
+func newVersionedTestRouter(t *testing.T) (*gin.Engine, *models.Goal) {
+	t.Helper()
+	db := testdb.Open(t)
+	repo := repository.NewRepository(db)
+	goals := service.NewGoalService(repo, service.NewAuditService(repository.NewAuditLogRepository(db), repo), nil, nil, service.NewInviteTokens("test-invite-secret", time.Hour))
+	v1, v2 := NewGoalController(goals, nil), NewGoalControllerV2(goals)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	api := r.Group("/api/v1/goals", middleware.APIVersion("v1"), middleware.Deprecated("/api/v2/goals"))
+	api.GET("", v1.ListPublicGoals)
+	api.GET("/:id", v1.GetGoal)
+	api.GET("/:id/progress", v1.GetGoalProgress)
+	apiV2 := r.Group("/api/v2/goals", middleware.APIVersion("v2"))
+	apiV2.GET("", v2.ListGoals)
+	apiV2.GET("/:id", v2.GetGoal)
+	apiV2.GET("/:id/progress", v2.GetGoalProgress)
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	public, private := true, false
+	create := dto.CreateGoalRequest{
+		Title:                "Community borehole",
+		TargetAmount:         1_000_000,
+		Currency:             "NGN",
+		DepositBankName:      "Test Bank",
+		DepositAccountNumber: "0123456789",
+		DepositAccountName:   "Ada Obi",
+		IsPublic:             &public,
+		Milestones:           []dto.CreateMilestoneRequest{{Title: "Drilling"}},
+	}
+	goal, err := goals.CreateGoal(ctx, ownerID, create)
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	create.Title, create.IsPublic, create.Milestones = "Private savings", &private, nil
+	if _, err := goals.CreateGoal(ctx, ownerID, create); err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+
+	paymentID := uuid.New()
+	if err := repo.Contribution.CreateContribution(ctx, &models.Contribution{
+		GoalID:    goal.ID,
+		UserID:    uuid.New(),
+		PaymentID: &paymentID,
+		Amount:    250_000,
+		NetAmount: 250_000,
+		Currency:  "NGN",
+		Status:    models.ContributionStatusConfirmed,
+	}); err != nil {
+		t.Fatalf("CreateContribution: %v", err)
+	}
+	return r, goal
+}
+
+// TestGoalReadsAgreeAcrossVersions runs the same service-level assertions against the v1
+// and v2 goal read endpoints
+func TestGoalReadsAgreeAcrossVersions(t *testing.T) {
+	r, goal := newVersionedTestRouter(t)
+
+	for _, version := range apiVersions {
+		t.Run(version.name, func(t *testing.T) {
+			var list map[string]interface{}
+			w := serve(t, r, http.MethodGet, version.prefix, uuid.Nil, nil)
+			decode(t, w, http.StatusOK, &list)
+			if got := w.Header().Get("Api-Version"); got != version.name {
+				t.Errorf("Api-Version = %q, want %q", got, version.name)
+			}
+			items, total := version.listItems(list)
+			if len(items) != 1 || total != 1 {
+				t.Fatalf("listed %d goals of %v, want only the public goal", len(items), total)
+			}
+			if listed := items[0].(map[string]interface{}); listed["id"] != goal.ID.String() {
+				t.Errorf("listed goal %v, want %s", listed["id"], goal.ID)
+			}
+
+			var detail map[string]interface{}
+			decode(t, serve(t, r, http.MethodGet, version.prefix+"/"+goal.ID.String(), uuid.Nil, nil), http.StatusOK, &detail)
+			if detail["id"] != goal.ID.String() || detail["title"] != goal.Title || detail["target_amount"] != float64(goal.TargetAmount) {
+				t.Errorf("detail = %v, want goal %s", detail, goal.ID)
+			}
+
+			var progress map[string]interface{}
+			decode(t, serve(t, r, http.MethodGet, version.prefix+"/"+goal.ID.String()+"/progress", uuid.Nil, nil), http.StatusOK, &progress)
+			progressGoal, contributed := version.progress(progress)
+			if progressGoal["id"] != goal.ID.String() || contributed != 250_000 {
+				t.Errorf("progress of goal %v with %v contributed, want %s with 250000", progressGoal["id"], contributed, goal.ID)
+			}
+
+			if w := serve(t, r, http.MethodGet, version.prefix+"/"+uuid.NewString(), uuid.Nil, nil); w.Code != http.StatusNotFound {
+				t.Errorf("missing goal: status = %d, want %d", w.Code, http.StatusNotFound)
+			}
+			if w := serve(t, r, http.MethodGet, version.prefix+"/not-a-uuid/progress", uuid.Nil, nil); w.Code != http.StatusBadRequest {
+				t.Errorf("malformed ID: status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+// keys returns the sorted keys of a JSON object
+func keys(object interface{}) []string {
+	m, _ := object.(map[string]interface{})
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func assertKeys(t *testing.T, what string, object interface{}, want ...string) {
+	t.Helper()
+	sort.Strings(want)
+	if got := keys(object); !reflect.DeepEqual(got, want) {
+		t.Errorf("%s keys = %v, want %v", what, got, want)
+	}
+}
+
+// TestGoalReadContracts pins the JSON shape of each version's goal read responses. v1 is
+// frozen: a change here breaks existing clients.
+func TestGoalReadContracts(t *testing.T) {
+	r, goal := newVersionedTestRouter(t)
+	get := func(path string) map[string]interface{} {
+		var body map[string]interface{}
+		decode(t, serve(t, r, http.MethodGet, path, uuid.Nil, nil), http.StatusOK, &body)
+		return body
+	}
+
+	v1Goal := []string{
+		"id", "owner_id", "title", "slug", "description", "target_amount", "currency", "status",
+		"is_public", "visibility", "close_on_target", "fixed_contribution_amount",
+		"require_proof_for_withdrawal", "weighted_voting", "fee_mode", "required_approvals",
+		"current_amount", "contributor_count", "created_at", "updated_at", "milestones", "contributions",
+	}
+	v1List := get("/api/v1/goals")
+	assertKeys(t, "v1 list", v1List, "data", "total", "page", "size")
+	v1Detail := get("/api/v1/goals/" + goal.ID.String())
+	assertKeys(t, "v1 detail", v1Detail, v1Goal...)
+	assertKeys(t, "v1 progress", get("/api/v1/goals/"+goal.ID.String()+"/progress"),
+		"Goal", "TotalContributions", "TotalWithdrawals", "TotalFees", "AvailableBalance", "ProgressPercent",
+		"ContributorCount", "Milestones", "ActiveMilestoneID", "GoalWithdrawalBlockedReason")
+
+	v2Goal := []string{
+		"id", "owner_id", "title", "description", "target_amount", "currency", "deadline", "status",
+		"is_public", "visibility", "close_on_target", "fixed_contribution_amount",
+		"require_proof_for_withdrawal", "weighted_voting", "fee_mode", "current_amount",
+		"contributor_count", "milestones", "created_at", "updated_at",
+	}
+	v2Milestone := []string{
+		"id", "title", "description", "target_amount", "order_index", "is_recurring", "recurrence_type",
+		"recurrence_interval", "next_due_date", "parent_milestone_id", "status", "completed_at",
+	}
+	v2List := get("/api/v2/goals")
+	assertKeys(t, "v2 list", v2List, "items", "pagination")
+	assertKeys(t, "v2 pagination", v2List["pagination"], "page", "page_size", "total", "total_pages")
+	if items, _ := v2List["items"].([]interface{}); len(items) == 1 {
+		assertKeys(t, "v2 listed goal", items[0], v2Goal...)
+	}
+	v2Detail := get("/api/v2/goals/" + goal.ID.String())
+	assertKeys(t, "v2 detail", v2Detail, v2Goal...)
+	if milestones, _ := v2Detail["milestones"].([]interface{}); len(milestones) == 1 {
+		assertKeys(t, "v2 milestone", milestones[0], v2Milestone...)
+	} else {
+		t.Errorf("v2 milestones = %v, want the goal's one milestone", v2Detail["milestones"])
+	}
+	v2Progress := get("/api/v2/goals/" + goal.ID.String() + "/progress")
+	assertKeys(t, "v2 progress", v2Progress,
+		"goal", "total_contributions", "total_withdrawals", "total_fees", "available_balance", "progress_percent",
+		"contributor_count", "milestones", "active_milestone_id", "withdrawal_blocked_reason")
+	if milestones, _ := v2Progress["milestones"].([]interface{}); len(milestones) == 1 {
+		assertKeys(t, "v2 milestone progress", milestones[0], "milestone", "current_amount", "progress_percent", "withdrawal_blocked_reason")
+	}
+}
+
+// TestV2RejectsMalformedPagination checks the corrected v2 pagination, which v1 silently
+// replaces with defaults
+func TestV2RejectsMalformedPagination(t *testing.T) {
+	r, _ := newVersionedTestRouter(t)
+
+	for _, query := range []string{"page=0", "page=x", "page_size=0", "page_size=101"} {
+		if w := serve(t, r, http.MethodGet, "/api/v2/goals?"+query, uuid.Nil, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("v2 %s: status = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+		if w := serve(t, r, http.MethodGet, "/api/v1/goals?"+query, uuid.Nil, nil); w.Code != http.StatusOK {
+			t.Errorf("v1 %s: status = %d, want %d", query, w.Code, http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// goalResponse presents a goal in full to its owner, and as a PublicGoalResponse to
+// everyone else
+func goalResponse(goal *models.Goal, viewerID uuid.UUID) interface{} {
+	if viewerID != uuid.Nil && goal.OwnerID == viewerID {
+		return dto.OwnerGoalResponse{Goal: *goal}
+	}
+	return publicGoalResponse(goal)
+}
+
+// publicGoalResponse presents a goal without its deposit account or withdrawals, and
+// with its confirmed contributions trimmed to amount, date and contributor. Contributors
+// of anonymous contributions must already be masked.
+func publicGoalResponse(goal *models.Goal) dto.PublicGoalResponse {
+	// A milestone's own contributions and withdrawals are left out too, should they be loaded
+	var milestones []models.Milestone
+	for _, milestone := range goal.Milestones {
+		milestone.Contributions = nil
+		milestone.Withdrawals = nil
+		milestones = append(milestones, milestone)
+	}
+
+	var contributions []dto.PublicContributionResponse
+	for _, contribution := range goal.Contributions {
+		if contribution.Status != models.ContributionStatusConfirmed {
+			continue
+		}
+		public := dto.PublicContributionResponse{
+			Amount:      contribution.Amount,
+			Currency:    contribution.Currency,
+			IsAnonymous: contribution.IsAnonymous,
+			CreatedAt:   contribution.CreatedAt,
+		}
+		if contribution.UserID != uuid.Nil {
+			contributorID := contribution.UserID
+			public.ContributorID = &contributorID
+		}
+		contributions = append(contributions, public)
+	}
+
+	return dto.PublicGoalResponse{
+		ID:                        goal.ID,
+		OwnerID:                   goal.OwnerID,
+		Title:                     goal.Title,
+		Slug:                      goal.Slug,
+		Description:               goal.Description,
+		TargetAmount:              goal.TargetAmount,
+		Currency:                  goal.Currency,
+		Deadline:                  goal.Deadline,
+		Status:                    goal.Status,
+		IsPublic:                  goal.IsPublic,
+		Visibility:                goal.Visibility,
+		CloseOnTarget:             goal.CloseOnTarget,
+		FixedContributionAmount:   goal.FixedContributionAmount,
+		RequireProofForWithdrawal: goal.RequireProofForWithdrawal,
+		WeightedVoting:            goal.WeightedVoting,
+		FeeMode:                   goal.FeeMode,
+		RequiredApprovals:         goal.RequiredApprovals,
+		SuspendedAt:               goal.SuspendedAt,
+		SuspensionReason:          goal.SuspensionReason,
+		CurrentAmount:             goal.CurrentAmount,
+		ContributorCount:          goal.ContributorCount,
+		CreatedAt:                 goal.CreatedAt,
+		UpdatedAt:                 goal.UpdatedAt,
+		Milestones:                milestones,
+		Contributions:             contributions,
+		Proofs:                    goal.Proofs,
+	}
+}
+
+// publicGoalResponses presents a page of goals with publicGoalResponse
+func publicGoalResponses(goals []models.Goal) []dto.PublicGoalResponse {
+	responses := make([]dto.PublicGoalResponse, len(goals))
+	for i := range goals {
+		responses[i] = publicGoalResponse(&goals[i])
+	}
+	return responses
+}
+
+// goalSearchResults presents a page of search hits with publicGoalResponse
+func goalSearchResults(hits []dto.GoalSearchHit) []dto.GoalSearchResult {
+	results := make([]dto.GoalSearchResult, len(hits))
+	for i := range hits {
+		results[i] = dto.GoalSearchResult{
+			PublicGoalResponse: publicGoalResponse(&hits[i].Goal),
+			Score:              hits[i].Score,
+			TitleHighlight:     hits[i].TitleHighlight,
+			Snippet:            hits[i].Snippet,
+		}
+	}
+	return results
+}
@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// sensitiveGoalKeys are the keys a goal served to anyone but its owner must not contain,
+// at any depth
+var sensitiveGoalKeys = []string{
+	"deposit_bank_name", "deposit_account_number", "deposit_account_name",
+	"withdrawals", "bank_name", "account_number", "account_name", "bank_code",
+	"payment_id", "fee_amount", "net_amount", "refunds",
+}
+
+// jsonKeys returns every object key in a JSON document, at any depth
+func jsonKeys(t *testing.T, body []byte) map[string]bool {
+	t.Helper()
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("failed to decode %s: %v", body, err)
+	}
+	keys := make(map[string]bool)
+	var walk func(interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, value := range v {
+				keys[key] = true
+				walk(value)
+			}
+		case []interface{}:
+			for _, value := range v {
+				walk(value)
+			}
+		}
+	}
+	walk(doc)
+	return keys
+}
+
+// assertNoSensitiveKeys fails if body holds any of sensitiveGoalKeys
+func assertNoSensitiveKeys(t *testing.T, body []byte) {
+	t.Helper()
+	keys := jsonKeys(t, body)
+	for _, key := range sensitiveGoalKeys {
+		if keys[key] {
+			t.Errorf("public payload contains %q: %s", key, body)
+		}
+	}
+}
+
+// bankedGoal is a goal with every sensitive association loaded
+func bankedGoal() *models.Goal {
+	goalID, milestoneID := uuid.New(), uuid.New()
+	paymentID := uuid.New()
+	withdrawal := models.Withdrawal{
+		ID: uuid.New(), GoalID: goalID, Amount: 50_000, Currency: "NGN",
+		BankName: "Test Bank", AccountNumber: "0123456789", AccountName: "Ada Obi", BankCode: "058",
+	}
+	contribution := models.Contribution{
+		ID: uuid.New(), GoalID: goalID, UserID: uuid.New(), PaymentID: &paymentID,
+		Amount: 100_000, Currency: "NGN", Status: models.ContributionStatusConfirmed, FeeAmount: 1_500, NetAmount: 98_500,
+	}
+	return &models.Goal{
+		ID:                   goalID,
+		OwnerID:              uuid.New(),
+		Title:                "Community borehole",
+		TargetAmount:         1_000_000,
+		Currency:             "NGN",
+		Status:               models.GoalStatusOpen,
+		DepositBankName:      "Test Bank",
+		DepositAccountNumber: "0123456789",
+		DepositAccountName:   "Ada Obi",
+		Milestones: []models.Milestone{{
+			ID: milestoneID, GoalID: goalID, Title: "Drilling",
+			Contributions: []models.Contribution{contribution},
+			Withdrawals:   []models.Withdrawal{withdrawal},
+		}},
+		Contributions: []models.Contribution{
+			contribution,
+			{ID: uuid.New(), GoalID: goalID, UserID: uuid.New(), Amount: 20_000, Currency: "NGN", Status: models.ContributionStatusPending},
+			// Anonymous contributors are masked before presentation
+			{ID: uuid.New(), GoalID: goalID, Amount: 30_000, Currency: "NGN", Status: models.ContributionStatusConfirmed, IsAnonymous: true},
+		},
+		Withdrawals: []models.Withdrawal{withdrawal},
+		Refunds:     []models.Refund{{ID: uuid.New(), GoalID: goalID, TotalRefundAmount: 10_000}},
+	}
+}
+
+func TestPublicGoalResponseHasNoSensitiveKeys(t *testing.T) {
+	goal := bankedGoal()
+	for name, viewerID := range map[string]uuid.UUID{"anonymous": uuid.Nil, "another user": uuid.New()} {
+		t.Run(name, func(t *testing.T) {
+			body, err := json.Marshal(goalResponse(goal, viewerID))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertNoSensitiveKeys(t, body)
+			if strings.Contains(string(body), goal.DepositAccountNumber) {
+				t.Errorf("public payload contains the deposit account number: %s", body)
+			}
+
+			var public dto.PublicGoalResponse
+			if err := json.Unmarshal(body, &public); err != nil {
+				t.Fatal(err)
+			}
+			// Only the two confirmed contributions, the anonymous one without a contributor
+			if len(public.Contributions) != 2 {
+				t.Fatalf("contributions = %d, want the 2 confirmed", len(public.Contributions))
+			}
+			if public.Contributions[1].ContributorID != nil {
+				t.Errorf("anonymous contribution names contributor %s", public.Contributions[1].ContributorID)
+			}
+		})
+	}
+}
+
+func TestPublicContributionKeys(t *testing.T) {
+	body, err := json.Marshal(publicGoalResponse(bankedGoal()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var public struct {
+		Contributions []map[string]interface{} `json:"contributions"`
+	}
+	if err := json.Unmarshal(body, &public); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"amount", "contributor_id", "created_at", "currency", "is_anonymous"}
+	got := make([]string, 0, len(public.Contributions[0]))
+	for key := range public.Contributions[0] {
+		got = append(got, key)
+	}
+	sort.Strings(got)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("contribution keys = %v, want %v", got, want)
+	}
+}
+
+func TestOwnerGoalResponseKeepsBankDetails(t *testing.T) {
+	goal := bankedGoal()
+	body, err := json.Marshal(goalResponse(goal, goal.OwnerID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := jsonKeys(t, body)
+	for _, key := range []string{"deposit_bank_name", "deposit_account_number", "deposit_account_name", "withdrawals"} {
+		if !keys[key] {
+			t.Errorf("owner payload is missing %q", key)
+		}
+	}
+}
+
+// TestGetGoalHidesBankDetailsFromOthers reads a goal with a contribution and a withdrawal
+// through the endpoint as its owner, another user and anonymously
+func TestGetGoalHidesBankDetailsFromOthers(t *testing.T) {
+	db := testdb.Open(t)
+	repo := repository.NewRepository(db)
+	goals := service.NewGoalService(repo, nil, nil, nil, service.NewInviteTokens("test-invite-secret", time.Hour))
+	controller := NewGoalController(goals, nil)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/v1/goals", controller.ListPublicGoals)
+	r.GET("/api/v1/goals/:id", controller.GetGoal)
+	ctx := context.Background()
+
+	ownerID := uuid.New()
+	goal := createStatusTestGoal(t, goals, ownerID)
+	paymentID := uuid.New()
+	contribution := &models.Contribution{
+		GoalID: goal.ID, UserID: uuid.New(), PaymentID: &paymentID, Amount: 100_000, Currency: "NGN",
+		Status: models.ContributionStatusConfirmed, NetAmount: 100_000,
+	}
+	if err := repo.Contribution.CreateContribution(ctx, contribution); err != nil {
+		t.Fatalf("CreateContribution: %v", err)
+	}
+	withdrawal := &models.Withdrawal{
+		GoalID: goal.ID, OwnerID: ownerID, Amount: 50_000, Currency: "NGN",
+		BankName: "Test Bank", AccountNumber: "0123456789", AccountName: "Ada Obi", Status: models.WithdrawalStatusCompleted,
+	}
+	if err := db.Create(withdrawal).Error; err != nil {
+		t.Fatalf("failed to seed withdrawal: %v", err)
+	}
+
+	path := "/api/v1/goals/" + goal.ID.String()
+	for name, viewerID := range map[string]uuid.UUID{"anonymous": uuid.Nil, "another user": uuid.New()} {
+		t.Run(name, func(t *testing.T) {
+			w := serve(t, r, http.MethodGet, path, viewerID, nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+			}
+			assertNoSensitiveKeys(t, w.Body.Bytes())
+		})
+	}
+
+	w := serve(t, r, http.MethodGet, path, ownerID, nil)
+	var owner dto.OwnerGoalResponse
+	decode(t, w, http.StatusOK, &owner)
+	if owner.DepositAccountNumber != "0123456789" || len(owner.Withdrawals) != 1 {
+		t.Errorf("owner sees account %q and %d withdrawals, want the full detail", owner.DepositAccountNumber, len(owner.Withdrawals))
+	}
+
+	list := serve(t, r, http.MethodGet, "/api/v1/goals", uuid.Nil, nil)
+	if list.Code != http.StatusOK {
+		t.Fatalf("list status = %d: %s", list.Code, list.Body.String())
+	}
+	assertNoSensitiveKeys(t, list.Body.Bytes())
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/service"
+)
+
+// GoalStatsController serves the dashboard stats of a goal to its owner, and the
+// platform-wide totals to everyone
+type GoalStatsController struct {
+	statsService         *service.GoalStatsService
+	platformStatsService *service.PlatformStatsService
+}
+
+// NewGoalStatsController creates a new goal stats controller instance
+func NewGoalStatsController(statsService *service.GoalStatsService, platformStatsService *service.PlatformStatsService) *GoalStatsController {
+	return &GoalStatsController{
+		statsService:         statsService,
+		platformStatsService: platformStatsService,
+	}
+}
+
+// GetGoalStats handles GET /api/v1/goals/:id/stats
+//
+// @Summary Get a goal's dashboard stats (owner only)
+// @Description Daily confirmed contribution totals and contributor growth over the last days days, average and median contribution, contribution counts by status and refund totals. Stats may be up to a minute old.
+// @Tags goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param days query int false "Days of daily stats, 1 to 365" default(30)
+// @Success 200 {object} dto.GoalStats
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/stats [get]
+func (sc *GoalStatsController) GetGoalStats(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(service.DefaultGoalStatsDays)))
+
+	stats, err := sc.statsService.GetGoalStats(c.Request.Context(), goalID, userID, days)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetPlatformStats handles GET /api/v1/goals/stats/platform
+//
+// @Summary Get platform-wide totals
+// @Description Amount raised per currency, goals funded, active goals and distinct contributors across the platform, for the homepage. Totals may be a few minutes old.
+// @Tags goals
+// @Produce json
+// @Success 200 {object} dto.PlatformStats
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/stats/platform [get]
+func (sc *GoalStatsController) GetPlatformStats(c *gin.Context) {
+	stats, err := sc.platformStatsService.GetPlatformStats(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/receipts"
+	"github.com/gofund/goals-service/internal/service"
+)
+
+// ReceiptController serves contribution receipts
+type ReceiptController struct {
+	receiptService *service.ReceiptService
+}
+
+// NewReceiptController creates a new receipt controller instance
+func NewReceiptController(receiptService *service.ReceiptService) *ReceiptController {
+	return &ReceiptController{
+		receiptService: receiptService,
+	}
+}
+
+// GetReceipt handles GET /api/v1/contributions/:id/receipt. The format follows the Accept
+// header: HTML by default, PDF for application/pdf and the raw fields for application/json.
+//
+// @Summary Get a contribution receipt
+// @Tags contributions
+// @Produce html,application/pdf,json
+// @Security BearerAuth
+// @Param id path string true "Contribution ID"
+// @Success 200 {object} dto.ContributionReceipt
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/contributions/{id}/receipt [get]
+func (rc *ReceiptController) GetReceipt(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	contributionID, err := parseID(c.Param("id"), "contribution")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	receipt, err := rc.receiptService.GetReceipt(c.Request.Context(), contributionID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	switch c.NegotiateFormat(gin.MIMEHTML, "application/pdf", gin.MIMEJSON) {
+	case "application/pdf":
+		filename := fmt.Sprintf("gofund-receipt-%s.pdf", receipt.ContributionID)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Data(http.StatusOK, "application/pdf", receipts.RenderPDF(receipt))
+	case gin.MIMEJSON:
+		c.JSON(http.StatusOK, receipt)
+	default:
+		page, err := receipts.RenderHTML(receipt)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	}
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/service"
+)
+
+// RecurringContributionController handles contributors' recurring contributions
+type RecurringContributionController struct {
+	recurringService *service.RecurringContributionService
+}
+
+// NewRecurringContributionController creates a new recurring contribution controller instance
+func NewRecurringContributionController(recurringService *service.RecurringContributionService) *RecurringContributionController {
+	return &RecurringContributionController{
+		recurringService: recurringService,
+	}
+}
+
+// CreateRecurringContribution handles POST /api/v1/contributions/recurring
+//
+// @Summary Set up a recurring contribution
+// @Description Charges the card a confirmed contribution was paid with every week or month
+// @Tags contributions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateRecurringContributionRequest true "Recurring contribution to set up"
+// @Success 201 {object} models.RecurringContribution
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/contributions/recurring [post]
+func (rc *RecurringContributionController) CreateRecurringContribution(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req dto.CreateRecurringContributionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	recurring, err := rc.recurringService.CreateRecurringContribution(c.Request.Context(), userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, recurring)
+}
+
+// ListRecurringContributions handles GET /api/v1/contributions/recurring
+//
+// @Summary List the caller's recurring contributions
+// @Tags contributions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.RecurringContributionListResponse
+// @Failure 401 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/contributions/recurring [get]
+func (rc *RecurringContributionController) ListRecurringContributions(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	recurring, err := rc.recurringService.ListRecurringContributions(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RecurringContributionListResponse{RecurringContributions: recurring, Total: len(recurring)})
+}
+
+// GetRecurringContribution handles GET /api/v1/contributions/recurring/:id
+//
+// @Summary Get one of the caller's recurring contributions
+// @Tags contributions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recurring contribution ID"
+// @Success 200 {object} models.RecurringContribution
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/contributions/recurring/{id} [get]
+func (rc *RecurringContributionController) GetRecurringContribution(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	id, err := parseID(c.Param("id"), "recurring contribution")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	recurring, err := rc.recurringService.GetRecurringContribution(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recurring)
+}
+
+// UpdateRecurringContribution handles PATCH /api/v1/contributions/recurring/:id
+//
+// @Summary Change, pause or resume a recurring contribution
+// @Tags contributions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recurring contribution ID"
+// @Param request body dto.UpdateRecurringContributionRequest true "Changes to make"
+// @Success 200 {object} models.RecurringContribution
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/contributions/recurring/{id} [patch]
+func (rc *RecurringContributionController) UpdateRecurringContribution(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	id, err := parseID(c.Param("id"), "recurring contribution")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req dto.UpdateRecurringContributionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	recurring, err := rc.recurringService.UpdateRecurringContribution(c.Request.Context(), id, userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recurring)
+}
+
+// CancelRecurringContribution handles DELETE /api/v1/contributions/recurring/:id
+//
+// @Summary Cancel a recurring contribution
+// @Tags contributions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recurring contribution ID"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/contributions/recurring/{id} [delete]
+func (rc *RecurringContributionController) CancelRecurringContribution(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	id, err := parseID(c.Param("id"), "recurring contribution")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := rc.recurringService.CancelRecurringContribution(c.Request.Context(), id, userID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Recurring contribution cancelled"})
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gofund/goals-service/internal/dto"
 	"github.com/gofund/goals-service/internal/service"
-	"github.com/google/uuid"
 )
 
 // RefundController handles refund-related endpoints
@@ -22,93 +21,347 @@ func NewRefundController(refundService *service.RefundService) *RefundController
 }
 
 // InitiateRefund handles refund initiation by goal owner
+//
+// @Summary Refund a goal's contributors
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.InitiateRefundRequest true "Refund to initiate"
+// @Success 201 {object} dto.RefundInitiatedResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/refunds [post]
 func (rc *RefundController) InitiateRefund(c *gin.Context) {
 	// Extract user ID from header (set by Nginx after auth verification)
-	userIDStr := c.GetHeader("X-User-ID")
-	if userIDStr == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User not authenticated",
-		})
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req dto.InitiateRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
 		return
 	}
 
-	userID, err := uuid.Parse(userIDStr)
+	// Initiate refund
+	refund, err := rc.refundService.InitiateRefund(c.Request.Context(), userID, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid user ID",
-		})
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.RefundInitiatedResponse{
+		Refund:  refund,
+		Message: "Refund initiated successfully",
+	})
+}
+
+// PreviewRefund returns the disbursements a refund would create without initiating it
+//
+// @Summary Preview a refund without initiating it
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.InitiateRefundRequest true "Refund to preview"
+// @Success 200 {object} dto.RefundPreviewResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/refunds/preview [post]
+func (rc *RefundController) PreviewRefund(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
 	var req dto.InitiateRefundRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"details": err.Error(),
-		})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
-	// Initiate refund
-	refund, err := rc.refundService.InitiateRefund(userID, &req)
+	plan, err := rc.refundService.PreviewRefund(c.Request.Context(), userID, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"refund":  refund,
-		"message": "Refund initiated successfully",
+	c.JSON(http.StatusOK, dto.RefundPreviewResponse{
+		Preview: plan,
 	})
 }
 
 // GetRefund retrieves a refund by ID
+//
+// @Summary Get a refund
+// @Tags refunds
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Refund ID"
+// @Success 200 {object} dto.RefundResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/refunds/{id} [get]
 func (rc *RefundController) GetRefund(c *gin.Context) {
-	refundIDStr := c.Param("id")
-	refundID, err := uuid.Parse(refundIDStr)
+	refundID, err := parseID(c.Param("id"), "refund")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid refund ID",
-		})
+		respondError(c, err)
 		return
 	}
 
-	refund, err := rc.refundService.GetRefund(refundID)
+	refund, err := rc.refundService.GetRefund(c.Request.Context(), refundID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"refund": refund,
+	c.JSON(http.StatusOK, dto.RefundResponse{
+		Refund: refund,
 	})
 }
 
 // GetGoalRefunds retrieves all refunds for a goal
+//
+// @Summary List a goal's refunds
+// @Tags refunds
+// @Produce json
+// @Security BearerAuth
+// @Param goalId path string true "Goal ID"
+// @Success 200 {object} dto.RefundListResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/goals/{goalId}/refunds [get]
 func (rc *RefundController) GetGoalRefunds(c *gin.Context) {
-	goalIDStr := c.Param("goalId")
-	goalID, err := uuid.Parse(goalIDStr)
+	goalID, err := parseID(c.Param("goalId"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	refunds, err := rc.refundService.GetGoalRefunds(c.Request.Context(), goalID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RefundListResponse{
+		Refunds: refunds,
+		Count:   len(refunds),
+	})
+}
+
+// GetContributionRefund returns the latest refund disbursement of one of the caller's
+// contributions
+//
+// @Summary Get a contribution's refund (contributor only)
+// @Tags contributions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Contribution ID"
+// @Success 200 {object} dto.ContributionRefund
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/contributions/{id}/refund [get]
+func (rc *RefundController) GetContributionRefund(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	contributionID, err := parseID(c.Param("id"), "contribution")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	refund, err := rc.refundService.GetContributionRefund(c.Request.Context(), contributionID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, refund)
+}
+
+// RequestRefund lets a contributor ask for one of their contributions to be refunded.
+// Requests within the grace window on an open goal are approved at once.
+//
+// @Summary Request a refund of a contribution (contributor only)
+// @Tags contributions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Contribution ID"
+// @Param request body dto.RequestRefundRequest true "Why the refund is requested"
+// @Success 201 {object} dto.RefundRequestResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/contributions/{id}/refund-request [post]
+func (rc *RefundController) RequestRefund(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	contributionID, err := parseID(c.Param("id"), "contribution")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req dto.RequestRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	request, err := rc.refundService.RequestRefund(c.Request.Context(), contributionID, userID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.RefundRequestResponse{
+		RefundRequest: request,
+	})
+}
+
+// GetGoalRefundRequests lists a goal's refund requests for its owner
+//
+// @Summary List a goal's refund requests (owner only)
+// @Tags refunds
+// @Produce json
+// @Security BearerAuth
+// @Param goalId path string true "Goal ID"
+// @Param status query string false "Only requests with this status (PENDING, APPROVED or DENIED)"
+// @Success 200 {object} dto.RefundRequestListResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/goals/{goalId}/refund-requests [get]
+func (rc *RefundController) GetGoalRefundRequests(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("goalId"), "goal")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid goal ID",
-		})
+		respondError(c, err)
 		return
 	}
 
-	refunds, err := rc.refundService.GetGoalRefunds(goalID)
+	requests, err := rc.refundService.GetGoalRefundRequests(c.Request.Context(), goalID, userID, c.Query("status"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RefundRequestListResponse{
+		RefundRequests: requests,
+		Count:          len(requests),
+	})
+}
+
+// ApproveRefundRequest approves a pending refund request and starts the refund
+//
+// @Summary Approve a refund request (owner only)
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Refund request ID"
+// @Param request body dto.ReviewRefundRequestRequest false "Note to the contributor"
+// @Success 200 {object} dto.RefundRequestResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/refund-requests/{id}/approve [post]
+func (rc *RefundController) ApproveRefundRequest(c *gin.Context) {
+	rc.reviewRefundRequest(c, true)
+}
+
+// DenyRefundRequest turns down a pending refund request
+//
+// @Summary Deny a refund request (owner only)
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Refund request ID"
+// @Param request body dto.ReviewRefundRequestRequest false "Note to the contributor"
+// @Success 200 {object} dto.RefundRequestResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/refund-requests/{id}/deny [post]
+func (rc *RefundController) DenyRefundRequest(c *gin.Context) {
+	rc.reviewRefundRequest(c, false)
+}
+
+// reviewRefundRequest approves or denies the refund request named in the path
+func (rc *RefundController) reviewRefundRequest(c *gin.Context, approve bool) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	requestID, err := parseID(c.Param("id"), "refund request")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// The note is optional, so an empty body is fine
+	var req dto.ReviewRefundRequestRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, invalidRequest(err))
+			return
+		}
+	}
+
+	request, err := rc.refundService.ReviewRefundRequest(c.Request.Context(), requestID, userID, approve, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"refunds": refunds,
-		"count":   len(refunds),
+	c.JSON(http.StatusOK, dto.RefundRequestResponse{
+		RefundRequest: request,
 	})
 }
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/service"
+)
+
+// ReportController handles users' reports of goals
+type ReportController struct {
+	moderationService *service.ModerationService
+}
+
+// NewReportController creates a new report controller instance
+func NewReportController(moderationService *service.ModerationService) *ReportController {
+	return &ReportController{
+		moderationService: moderationService,
+	}
+}
+
+// ReportGoal handles POST /api/v1/goals/:id/reports
+//
+// @Summary Report a goal for moderation
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param request body dto.ReportGoalRequest true "Report category and details"
+// @Success 201 {object} models.GoalReport
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 409 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/reports [post]
+func (rc *ReportController) ReportGoal(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req dto.ReportGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	report, err := rc.moderationService.ReportGoal(c.Request.Context(), goalID, userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
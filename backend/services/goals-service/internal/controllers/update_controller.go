@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/google/uuid"
+)
+
+// GoalUpdateController handles updates owners post to their goals
+type GoalUpdateController struct {
+	updateService *service.GoalUpdateService
+}
+
+// NewGoalUpdateController creates a new goal update controller instance
+func NewGoalUpdateController(updateService *service.GoalUpdateService) *GoalUpdateController {
+	return &GoalUpdateController{
+		updateService: updateService,
+	}
+}
+
+// ListUpdates handles GET /api/v1/goals/:id/updates
+//
+// @Summary List a goal's updates
+// @Tags updates
+// @Produce json
+// @Param id path string true "Goal ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {object} dto.GoalUpdatePage
+// @Failure 400 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/updates [get]
+func (uc *GoalUpdateController) ListUpdates(c *gin.Context) {
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	viewerID, _ := uuid.Parse(c.GetHeader("X-User-ID"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	updates, err := uc.updateService.ListUpdates(c.Request.Context(), goalID, viewerID, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updates)
+}
+
+// PostUpdate handles POST /api/v1/goals/:id/updates
+//
+// @Summary Post an update to a goal
+// @Tags updates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param request body dto.CreateGoalUpdateRequest true "Update to post"
+// @Success 201 {object} models.GoalUpdate
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 403 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/updates [post]
+func (uc *GoalUpdateController) PostUpdate(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req dto.CreateGoalUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	update, err := uc.updateService.PostUpdate(c.Request.Context(), goalID, userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, update)
+}
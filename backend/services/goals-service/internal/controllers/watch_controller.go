@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/service"
+)
+
+// WatchController handles users' goal watch lists
+type WatchController struct {
+	watchService *service.WatchService
+}
+
+// NewWatchController creates a new watch controller instance
+func NewWatchController(watchService *service.WatchService) *WatchController {
+	return &WatchController{
+		watchService: watchService,
+	}
+}
+
+// WatchGoal handles POST /api/v1/goals/:id/watch
+//
+// @Summary Watch a goal
+// @Tags watch
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 404 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/watch [post]
+func (wc *WatchController) WatchGoal(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := wc.watchService.WatchGoal(c.Request.Context(), goalID, userID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Goal added to watch list"})
+}
+
+// UnwatchGoal handles DELETE /api/v1/goals/:id/watch
+//
+// @Summary Stop watching a goal
+// @Tags watch
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} httperr.Response
+// @Failure 401 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/{id}/watch [delete]
+func (wc *WatchController) UnwatchGoal(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	goalID, err := parseID(c.Param("id"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := wc.watchService.UnwatchGoal(c.Request.Context(), goalID, userID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Goal removed from watch list"})
+}
+
+// ListWatchedGoals handles GET /api/v1/goals/watched
+//
+// @Summary List the goals the caller watches
+// @Tags watch
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Success 200 {object} dto.GoalListResponse
+// @Failure 401 {object} httperr.Response
+// @Failure 500 {object} httperr.Response
+// @Router /api/v1/goals/watched [get]
+func (wc *WatchController) ListWatchedGoals(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	goals, total, err := wc.watchService.ListWatchedGoals(c.Request.Context(), userID, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GoalListResponse{
+		Data:  goals,
+		Total: total,
+		Page:  page,
+		Size:  pageSize,
+	})
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Balance discrepancy kinds
+const (
+	// DiscrepancyMissingFromLedger is a confirmed contribution with no ledger entries
+	DiscrepancyMissingFromLedger = "missing_from_ledger"
+	// DiscrepancyMissingFromGoals is a contribution the ledger holds money for that is not
+	// confirmed in goals-service
+	DiscrepancyMissingFromGoals = "missing_from_goals"
+	// DiscrepancyAmountMismatch is a contribution whose ledger net differs from its
+	// confirmed amount less completed refunds
+	DiscrepancyAmountMismatch = "amount_mismatch"
+	// DiscrepancyUnlinkedEntry is a ledger credit not tied to any contribution
+	DiscrepancyUnlinkedEntry = "unlinked_entry"
+)
+
+// GoalBalanceCheck compares a goal's balance in goals-service with its ledger balance.
+// Delta is the goals-service balance minus the ledger balance.
+type GoalBalanceCheck struct {
+	GoalID                uuid.UUID            `json:"goal_id"`
+	Currency              string               `json:"currency"`
+	GoalsBalance          int64                `json:"goals_balance"`
+	LedgerBalance         int64                `json:"ledger_balance"`
+	LedgerSnapshotBalance *int64               `json:"ledger_snapshot_balance"`
+	Delta                 int64                `json:"delta"`
+	Consistent            bool                 `json:"consistent"`
+	Discrepancies         []BalanceDiscrepancy `json:"discrepancies"`
+	CheckedAt             time.Time            `json:"checked_at"`
+}
+
+// BalanceDiscrepancy is a single contribution (or unlinked ledger credit) on which
+// goals-service and the ledger disagree, with the ledger entries involved
+type BalanceDiscrepancy struct {
+	Kind           string      `json:"kind"`
+	ContributionID *uuid.UUID  `json:"contribution_id,omitempty"`
+	GoalsAmount    int64       `json:"goals_amount"`
+	LedgerAmount   int64       `json:"ledger_amount"`
+	LedgerEntryIDs []uuid.UUID `json:"ledger_entry_ids"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateCommentRequest represents a request to comment on a goal, or to reply to one of
+// its top-level comments when ParentID is set
+type CreateCommentRequest struct {
+	Body     string
+	ParentID *uuid.UUID
+}
+
+// CommentItem is a comment labelled with its author's display name. A deleted comment
+// keeps its place in the thread with the body "[removed]" and no author.
+type CommentItem struct {
+	ID          uuid.UUID
+	GoalID      uuid.UUID
+	UserID      *uuid.UUID
+	DisplayName string
+	ParentID    *uuid.UUID
+	Body        string
+	IsDeleted   bool
+	CreatedAt   time.Time
+	// Replies are oldest first; always empty on a reply
+	Replies []CommentItem
+}
+
+// CommentThreads is a page of a goal's top-level comments, newest first, with their replies
+type CommentThreads struct {
+	Items    []CommentItem
+	Total    int64
+	Page     int
+	PageSize int
+}
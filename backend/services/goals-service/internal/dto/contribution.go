@@ -1,12 +1,54 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
 
 // CreateContributionRequest represents a request to create a contribution
 type CreateContributionRequest struct {
 	GoalID      uuid.UUID
 	MilestoneID *uuid.UUID
 	Amount      int64
+	// Currency, when set, must be the goal's currency; the contribution always takes the goal's
+	Currency    string
+	IsAnonymous bool
+	// AllowMultiples accepts an integer multiple of a goal's fixed contribution amount,
+	// e.g. paying several periods of dues at once
+	AllowMultiples bool
+	// UseActiveMilestone assigns a contribution without a MilestoneID to the goal's active
+	// milestone, when it has one
+	UseActiveMilestone bool
+	// InviteToken is the token of an invite link, which lets a private goal's invitees
+	// contribute to it
+	InviteToken string
+}
+
+// ContributionIntent is a newly created contribution intent. WillDiscloseIdentity warns
+// the contributor, before paying, that the goal owner will see their name and email.
+// ChargeAmount is what checkout will charge, the platform fee included on add-on goals.
+type ContributionIntent struct {
+	*models.Contribution
+	ChargeAmount         int64 `json:"charge_amount"`
+	WillDiscloseIdentity bool  `json:"will_disclose_identity"`
+}
+
+// CreateRecurringContributionRequest sets up a recurring contribution that charges the card
+// a confirmed contribution was paid with. Amount defaults to that contribution's amount.
+type CreateRecurringContributionRequest struct {
+	ContributionID uuid.UUID
+	Interval       models.RecurrenceType
+	Amount         int64
+}
+
+// UpdateRecurringContributionRequest changes a recurring contribution; Status pauses
+// (PAUSED) or resumes (ACTIVE) it. Fields left out are unchanged.
+type UpdateRecurringContributionRequest struct {
+	Amount   *int64
+	Interval *models.RecurrenceType
+	Status   *models.RecurringContributionStatus
 }
 
 // CreateWithdrawalRequest represents a request to create a withdrawal
@@ -17,6 +59,9 @@ type CreateWithdrawalRequest struct {
 	BankName      string
 	AccountNumber string
 	AccountName   string
+	// BankCode is the provider's code for BankName. When empty, payments-service looks
+	// it up by name before paying out.
+	BankCode string
 }
 
 // CreateProofRequest represents a request to create a proof
@@ -33,12 +78,143 @@ type CreateVoteRequest struct {
 	ProofID     uuid.UUID
 	IsSatisfied bool
 	Comment     string
+	// ShowIdentity lets the goal owner see who cast the vote
+	ShowIdentity bool
+}
+
+// ProofVote is a vote as listed under its proof. VoterID is only set for the voter
+// themselves, and for the goal owner when the voter chose to show their identity.
+type ProofVote struct {
+	ID          uuid.UUID
+	VoterID     *uuid.UUID
+	IsSatisfied bool
+	Comment     string
+	VotedAt     time.Time
 }
 
-// VoteStats represents vote statistics
+// VoteStats represents vote statistics, both by head count and weighted by each voter's
+// confirmed contributions. WeightedVoting says which of the two decides verification.
 type VoteStats struct {
-	TotalVotes       int64
-	SatisfiedVotes   int64
-	UnsatisfiedVotes int64
-	SatisfactionRate float64
+	TotalVotes             int64
+	SatisfiedVotes         int64
+	UnsatisfiedVotes       int64
+	SatisfactionRate       float64
+	RequiredSatisfiedVotes int64
+	WeightedVoting         bool
+	// Weighted stats: amounts are the voters' confirmed contributions to the goal
+	SatisfiedAmount          int64
+	UnsatisfiedAmount        int64
+	TotalContributions       int64
+	WeightedSatisfactionRate float64
+	RequiredWeightedRate     float64
+	Status                   models.ProofStatus
+	VerifiedAt               *time.Time
+	RejectedAt               *time.Time
+}
+
+// ContributionFeedItem is a confirmed contribution labelled with the contributor's display name
+type ContributionFeedItem struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	DisplayName string
+	Amount      int64
+	Currency    string
+	CreatedAt   time.Time
+}
+
+// OwnerContributionItem is a confirmed contribution as its goal's owner sees it. Name and
+// Email are filled in when the amount reaches the disclosure threshold.
+type OwnerContributionItem struct {
+	ID                uuid.UUID
+	UserID            uuid.UUID
+	DisplayName       string
+	Name              string
+	Email             string
+	Amount            int64
+	Currency          string
+	IsAnonymous       bool
+	IdentityDisclosed bool
+	CreatedAt         time.Time
+}
+
+// OwnerContributionList is a page of a goal's confirmed contributions for its owner, newest first
+type OwnerContributionList struct {
+	Items    []OwnerContributionItem
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// ContributionFeed is a page of a goal's confirmed contributions, newest first
+type ContributionFeed struct {
+	Items    []ContributionFeedItem
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// ContributionReceipt is the proof of payment for a confirmed contribution
+type ContributionReceipt struct {
+	ContributionID   uuid.UUID `json:"contribution_id"`
+	GoalTitle        string    `json:"goal_title"`
+	Amount           int64     `json:"amount"`
+	Currency         string    `json:"currency"`
+	PaymentReference string    `json:"payment_reference"`
+	PaidAt           time.Time `json:"paid_at"`
+	ContributorName  string    `json:"contributor_name"`
+}
+
+// MyContribution is one of the caller's contributions. RefundStatus is the status of its
+// latest refund disbursement, set only once a refund has reached it.
+type MyContribution struct {
+	models.Contribution
+	RefundStatus *models.RefundStatus `json:"refund_status,omitempty"`
+}
+
+// ContributionRefund is the latest refund disbursement of a contribution, as its
+// contributor sees it. Only the last four digits of the account number are kept.
+type ContributionRefund struct {
+	ContributionID     uuid.UUID           `json:"contribution_id"`
+	RefundID           uuid.UUID           `json:"refund_id"`
+	DisbursementID     uuid.UUID           `json:"disbursement_id"`
+	Status             models.RefundStatus `json:"status"`
+	Amount             int64               `json:"amount"`
+	Currency           string              `json:"currency"`
+	BankName           string              `json:"bank_name,omitempty"`
+	AccountNumberLast4 string              `json:"account_number_last4,omitempty"`
+	FailureReason      string              `json:"failure_reason,omitempty"`
+	CreatedAt          time.Time           `json:"created_at"`
+	CompletedAt        *time.Time          `json:"completed_at,omitempty"`
+}
+
+// WithdrawalHistoryItem is a withdrawal as its goal's owner sees it in the withdrawal
+// history. Only the last four digits of the account number are kept.
+type WithdrawalHistoryItem struct {
+	ID                 uuid.UUID
+	MilestoneID        *uuid.UUID
+	Amount             int64
+	Currency           string
+	Status             models.WithdrawalStatus
+	BankName           string
+	AccountName        string
+	AccountNumberLast4 string
+	RequestedBy        *uuid.UUID
+	TransferReference  string
+	FailureReason      string
+	RequestedAt        time.Time
+	CompletedAt        *time.Time
+}
+
+// WithdrawalHistory is a page of a goal's withdrawals for its owner, newest first
+type WithdrawalHistory struct {
+	Items    []WithdrawalHistoryItem
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// WithdrawalDetail is a single withdrawal with the statuses it went through, oldest first
+type WithdrawalDetail struct {
+	WithdrawalHistoryItem
+	StatusHistory []models.WithdrawalStatusEvent
 }
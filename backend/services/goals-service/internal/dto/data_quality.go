@@ -0,0 +1,17 @@
+package dto
+
+// OrphanReportEntry summarises one class of orphaned rows
+type OrphanReportEntry struct {
+	Name      string   `json:"name"`
+	Table     string   `json:"table"`
+	Count     int64    `json:"count"`
+	SampleIDs []string `json:"sample_ids"`
+	Repaired  int64    `json:"repaired,omitempty"`
+}
+
+// OrphanReport is the response of the orphan data-quality check
+type OrphanReport struct {
+	Checks       []OrphanReportEntry `json:"checks"`
+	TotalOrphans int64               `json:"total_orphans"`
+	RepairMode   string              `json:"repair_mode,omitempty"`
+}
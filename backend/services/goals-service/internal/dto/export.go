@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportRow is one contribution or withdrawal in a goal's export
+type ExportRow struct {
+	Type        string // "contribution" or "withdrawal"
+	ID          uuid.UUID
+	Date        time.Time
+	Contributor string // display name, "Anonymous" when hidden, empty for withdrawals
+	Amount      int64  // smallest currency unit
+	Currency    string
+	Status      string
+	// Reference is the payment ID for a contribution and the transfer reference for a withdrawal
+	Reference string
+}
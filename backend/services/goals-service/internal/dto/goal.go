@@ -4,26 +4,43 @@ import (
 	"time"
 
 	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
 )
 
 // CreateGoalRequest represents a request to create a goal
 type CreateGoalRequest struct {
-	Title         string
-	Description   string
-	TargetAmount  int64
-	Currency      string
-	Deadline      *time.Time
-	BankName      string
-	AccountNumber string
-	AccountName   string
-	Milestones    []CreateMilestoneRequest
+	Title        string
+	Description  string
+	TargetAmount int64
+	Currency     string
+	Deadline     *time.Time
+	// DepositBankName, DepositAccountNumber and DepositAccountName are the account
+	// withdrawals are paid into
+	DepositBankName      string
+	DepositAccountNumber string
+	DepositAccountName   string
+	Milestones           []CreateMilestoneRequest
+	// Visibility is PUBLIC (the default), UNLISTED or PRIVATE. IsPublic is the older
+	// switch, used when Visibility is empty: false makes the goal PRIVATE.
+	Visibility    models.GoalVisibility
 	IsPublic      *bool
+	CloseOnTarget bool
+	// FixedContributionAmount makes every contribution this exact amount; zero allows any amount
+	FixedContributionAmount int64
+	// RequireProofForWithdrawal only allows withdrawals backed by a verified proof
+	RequireProofForWithdrawal bool
+	// WeightedVoting weights proof votes by each voter's confirmed contributions
+	WeightedVoting bool
+	// FeeMode is absorb (the default; the fee comes out of each contribution) or add_on
+	// (contributors pay the fee on top)
+	FeeMode models.FeeMode
 }
 
 // CreateMilestoneRequest represents a request to create a milestone
 type CreateMilestoneRequest struct {
-	Title              string
-	Description        string
+	Title       string
+	Description string
+	// TargetAmount left at zero takes an even share of the goal's unallocated target
 	TargetAmount       int64
 	OrderIndex         int
 	IsRecurring        bool
@@ -32,14 +49,76 @@ type CreateMilestoneRequest struct {
 	NextDueDate        *time.Time
 }
 
+// UpdateMilestoneRequest represents a request to edit a milestone. Nil fields are left
+// unchanged.
+type UpdateMilestoneRequest struct {
+	Title        *string
+	Description  *string
+	TargetAmount *int64
+	// ApplyToFuture also applies the edit to the later, not yet completed instances of a
+	// recurring milestone; instances created afterwards follow the latest one either way
+	ApplyToFuture bool
+}
+
+// MilestoneAllocation shows how much of a goal's target its milestones claim
+type MilestoneAllocation struct {
+	TargetAmount      int64
+	AllocatedAmount   int64
+	UnallocatedAmount int64
+}
+
+// CreatedMilestone is a newly created milestone with the goal's allocation after it
+type CreatedMilestone struct {
+	Milestone  models.Milestone
+	Allocation MilestoneAllocation
+}
+
+// UpdatedMilestone is an edited milestone, the later recurring instances the edit was
+// applied to, and the goal's allocation after it
+type UpdatedMilestone struct {
+	Milestone  models.Milestone
+	Propagated []models.Milestone
+	Allocation MilestoneAllocation
+}
+
 // UpdateGoalRequest represents a request to update a goal
 type UpdateGoalRequest struct {
-	Title         *string
-	Description   *string
-	BankName      *string
-	AccountNumber *string
-	AccountName   *string
+	Title       *string
+	Description *string
+	// TargetAmount may only be raised once the goal has confirmed contributions
+	TargetAmount *int64
+	// Currency may only change while the goal has no contributions
+	Currency *string
+	// A new deposit account on a goal with confirmed contributions must resolve, through
+	// the bank, to the owner's registered name; DepositAccountName is then taken from
+	// the bank
+	DepositBankName      *string
+	DepositAccountNumber *string
+	DepositAccountName   *string
+	// Visibility takes precedence over IsPublic when both are set
+	Visibility    *models.GoalVisibility
 	IsPublic      *bool
+	CloseOnTarget *bool
+	// FixedContributionAmount set to zero lifts the restriction
+	FixedContributionAmount   *int64
+	RequireProofForWithdrawal *bool
+	WeightedVoting            *bool
+	// FeeMode applies to contributions made from now on
+	FeeMode *models.FeeMode
+	// RequiredApprovals may not exceed the owner plus the goal's collaborators
+	RequiredApprovals *int
+}
+
+// AddCollaboratorRequest represents a goal owner's request to add a collaborator
+type AddCollaboratorRequest struct {
+	UserID uuid.UUID
+	// Role is "owner" (may request and approve withdrawals) or "approver"
+	Role models.CollaboratorRole
+}
+
+// SuspendGoalRequest represents an admin's request to suspend a goal
+type SuspendGoalRequest struct {
+	Reason string
 }
 
 // GoalProgress represents goal progress information
@@ -47,10 +126,27 @@ type GoalProgress struct {
 	Goal               models.Goal
 	TotalContributions int64
 	TotalWithdrawals   int64
-	AvailableBalance   int64
-	ProgressPercent    float64
-	ContributorCount   int64
-	Milestones         []MilestoneProgress
+	// TotalFees is the platform fees taken out of contributions the goal absorbed the
+	// fee on; they are not available to withdraw
+	TotalFees        int64
+	AvailableBalance int64
+	ProgressPercent  float64
+	ContributorCount int64
+	Milestones       []MilestoneProgress
+	// ActiveMilestoneID is the milestone currently being funded, nil when there is none
+	ActiveMilestoneID *uuid.UUID
+	// GoalWithdrawalBlockedReason explains why goal-level withdrawals are currently
+	// refused (e.g. "proof_required"); empty when they are allowed
+	GoalWithdrawalBlockedReason string
+}
+
+// GoalSearchHit is a goal matching a search with its relevance score, its title with the
+// matches marked up and a snippet of its description around them
+type GoalSearchHit struct {
+	Goal           models.Goal
+	Score          float64
+	TitleHighlight string
+	Snippet        string
 }
 
 // MilestoneProgress represents milestone progress information
@@ -58,4 +154,142 @@ type MilestoneProgress struct {
 	Milestone       models.Milestone
 	CurrentAmount   int64
 	ProgressPercent float64
+	// WithdrawalBlockedReason explains why withdrawals against the milestone are
+	// currently refused; empty when they are allowed
+	WithdrawalBlockedReason string
+}
+
+// TrendingGoal represents a goal in the trending list with its funding progress
+type TrendingGoal struct {
+	Goal               models.Goal
+	Score              float64
+	Rank               int
+	TotalContributions int64
+	ContributorCount   int64
+	ProgressPercent    float64
+}
+
+// TrendingGoals is the response of the trending goals endpoint.
+// ComputedAt is nil when the list fell back to most-recently-active goals.
+type TrendingGoals struct {
+	Goals      []TrendingGoal
+	ComputedAt *time.Time
+}
+
+// CreateGoalUpdateRequest represents a request to post an update to a goal
+type CreateGoalUpdateRequest struct {
+	Title     string
+	Body      string
+	MediaURLs []string
+}
+
+// GoalUpdatePage is a page of a goal's updates, newest first
+type GoalUpdatePage struct {
+	Items    []models.GoalUpdate
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// InternalGoal is the part of a goal other services need, e.g. payments-service checking
+// that a caller owns the goal whose payments they list
+type InternalGoal struct {
+	ID       uuid.UUID         `json:"id"`
+	OwnerID  uuid.UUID         `json:"owner_id"`
+	Status   models.GoalStatus `json:"status"`
+	Currency string            `json:"currency"`
+}
+
+// InternalOwnerGoals summarises a user's goals for other services (e.g. users-service
+// public profiles)
+type InternalOwnerGoals struct {
+	OwnerID         uuid.UUID `json:"owner_id"`
+	PublicGoalCount int64     `json:"public_goal_count"`
+}
+
+// GoalInvite is a signed invite link token for a goal. It lets its holder open and
+// contribute to the goal even when the goal is private.
+type GoalInvite struct {
+	GoalID    uuid.UUID `json:"goal_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GoalShareMeta is what link previews (Open Graph tags) need to render a shared goal
+type GoalShareMeta struct {
+	GoalID          uuid.UUID `json:"goal_id"`
+	Slug            string    `json:"slug,omitempty"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	CurrentAmount   int64     `json:"current_amount"`
+	TargetAmount    int64     `json:"target_amount"`
+	Currency        string    `json:"currency"`
+	ProgressPercent float64   `json:"progress_percent"`
+	// ImageURL is the first media URL of the goal's latest update or proof with media;
+	// empty when there is none
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+// GoalStats is the dashboard data on a goal for its owner. Amounts are in the goal
+// currency's smallest unit and days are UTC calendar days.
+type GoalStats struct {
+	GoalID   uuid.UUID `json:"goal_id"`
+	Currency string    `json:"currency"`
+	// Days is how many days Daily covers, ending today
+	Days  int              `json:"days"`
+	Daily []GoalDailyStats `json:"daily"`
+	// AverageAmount and MedianAmount are over all the goal's confirmed contributions
+	AverageAmount float64                `json:"average_amount"`
+	MedianAmount  float64                `json:"median_amount"`
+	Contributions GoalContributionCounts `json:"contributions"`
+	Refunds       GoalRefundStats        `json:"refunds"`
+	ComputedAt    time.Time              `json:"computed_at"`
+}
+
+// GoalDailyStats is one day of a goal's confirmed contributions. NewContributors counts
+// those whose first contribution was that day; TotalContributors everyone up to and
+// including it.
+type GoalDailyStats struct {
+	Date              string `json:"date"` // YYYY-MM-DD
+	Count             int64  `json:"count"`
+	Amount            int64  `json:"amount"`
+	NewContributors   int64  `json:"new_contributors"`
+	TotalContributors int64  `json:"total_contributors"`
+}
+
+// GoalContributionCounts counts a goal's contributions by status
+type GoalContributionCounts struct {
+	Pending   int64 `json:"pending"`
+	Confirmed int64 `json:"confirmed"`
+	Failed    int64 `json:"failed"`
+	Expired   int64 `json:"expired"`
+	Refunded  int64 `json:"refunded"`
+}
+
+// GoalRefundStats sums a goal's refunds: the amount paid back, still being paid out, and
+// whose payout failed
+type GoalRefundStats struct {
+	Count          int64 `json:"count"`
+	RefundedAmount int64 `json:"refunded_amount"`
+	PendingAmount  int64 `json:"pending_amount"`
+	FailedAmount   int64 `json:"failed_amount"`
+}
+
+// PlatformStats is the platform-wide totals shown on the homepage. Goals count as funded
+// once their confirmed contributions reach the target, and as active while OPEN.
+type PlatformStats struct {
+	// Raised is the confirmed contributions per currency, by currency code
+	Raised       []PlatformRaised `json:"raised"`
+	GoalsFunded  int64            `json:"goals_funded"`
+	ActiveGoals  int64            `json:"active_goals"`
+	Contributors int64            `json:"contributors"`
+	ComputedAt   time.Time        `json:"computed_at"`
+}
+
+// PlatformRaised is the amount raised in one currency, in its smallest unit and in major
+// units (e.g. kobo and naira)
+type PlatformRaised struct {
+	Currency    string  `json:"currency"`
+	Amount      int64   `json:"amount"`
+	AmountMajor float64 `json:"amount_major"`
 }
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// Bulk moderation actions
+const (
+	ModerationActionSuspend        = "suspend"
+	ModerationActionUnsuspend      = "unsuspend"
+	ModerationActionDismissReports = "dismiss_reports"
+)
+
+// ReportGoalRequest represents a user's report of a goal
+type ReportGoalRequest struct {
+	Category models.GoalReportCategory
+	Details  string
+}
+
+// ModerationQueueItem is a reported or automatically flagged goal awaiting moderation
+type ModerationQueueItem struct {
+	GoalID         uuid.UUID         `json:"goal_id"`
+	Title          string            `json:"title"`
+	OwnerID        uuid.UUID         `json:"owner_id"`
+	Status         models.GoalStatus `json:"status"`
+	CurrentAmount  int64             `json:"current_amount"` // the money at risk
+	Currency       string            `json:"currency"`
+	ReportCount    int64             `json:"report_count"` // open reports, automatic flags included
+	AutoFlags      int64             `json:"auto_flags"`
+	LastReportedAt time.Time         `json:"last_reported_at"`
+	PriorityScore  float64           `json:"priority_score"`
+}
+
+// ModerationQueueResponse is a page of the moderation queue, highest priority first
+type ModerationQueueResponse struct {
+	Data  []ModerationQueueItem `json:"data"`
+	Total int64                 `json:"total"`
+	Page  int                   `json:"page"`
+	Size  int                   `json:"size"`
+}
+
+// BulkModerationRequest applies one moderation action to up to 50 goals
+type BulkModerationRequest struct {
+	GoalIDs []string `json:"goal_ids" binding:"required,min=1,max=50,dive,uuid"`
+	Action  string   `json:"action" binding:"required,oneof=suspend unsuspend dismiss_reports"`
+	Reason  string   `json:"reason"` // recorded on suspensions
+}
+
+// BulkModerationResult is the outcome of a bulk moderation action on one goal
+type BulkModerationResult struct {
+	GoalID    string            `json:"goal_id"`
+	Success   bool              `json:"success"`
+	Status    models.GoalStatus `json:"status,omitempty"` // the goal's status afterwards
+	Dismissed int64             `json:"dismissed,omitempty"`
+	ErrorCode string            `json:"error_code,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// BulkModerationResponse reports a bulk moderation action goal by goal, in request order
+type BulkModerationResponse struct {
+	Results   []BulkModerationResult `json:"results"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+}
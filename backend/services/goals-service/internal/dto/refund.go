@@ -1,8 +1,72 @@
 package dto
 
-// InitiateRefundRequest represents a refund initiation request
+import "github.com/google/uuid"
+
+// Refund scopes: a refund covers every confirmed contribution of the goal, or only those
+// the request targets
+const (
+	RefundScopeGoal     = "goal"
+	RefundScopeTargeted = "targeted"
+)
+
+// InitiateRefundRequest represents a refund initiation request. Without ContributionIDs
+// or UserIDs it refunds every confirmed contribution; with them, only the contributions
+// listed and those of the users listed.
 type InitiateRefundRequest struct {
-	GoalID           string  `json:"goal_id" binding:"required,uuid"`
-	RefundPercentage float64 `json:"refund_percentage" binding:"required,min=1,max=100"`
-	Reason           string  `json:"reason"`
+	GoalID           string   `json:"goal_id" binding:"required,uuid"`
+	RefundPercentage float64  `json:"refund_percentage" binding:"required,min=1,max=100"`
+	Reason           string   `json:"reason"`
+	ContributionIDs  []string `json:"contribution_ids,omitempty" binding:"omitempty,max=500,dive,uuid"`
+	UserIDs          []string `json:"user_ids,omitempty" binding:"omitempty,max=500,dive,uuid"`
+}
+
+// Targeted reports whether the request refunds only some contributions
+func (r *InitiateRefundRequest) Targeted() bool {
+	return len(r.ContributionIDs) > 0 || len(r.UserIDs) > 0
+}
+
+// RequestRefundRequest is a contributor's request to have one of their contributions
+// refunded
+type RequestRefundRequest struct {
+	Reason string
+}
+
+// ReviewRefundRequestRequest carries the goal owner's note on approving or denying a
+// refund request
+type ReviewRefundRequestRequest struct {
+	Note string
+}
+
+// RefundPlan is the computed outcome of a refund request. Preview returns it as-is
+// and initiation persists it, so both always agree.
+type RefundPlan struct {
+	GoalID                    uuid.UUID             `json:"goal_id"`
+	RefundPercentage          float64               `json:"refund_percentage"`
+	Scope                     string                `json:"scope"`
+	Currency                  string                `json:"currency"`
+	TotalContributed          int64                 `json:"total_contributed"`
+	TotalFees                 int64                 `json:"total_fees"` // platform fees the goal absorbed
+	TotalWithdrawn            int64                 `json:"total_withdrawn"`
+	TotalReserved             int64                 `json:"total_reserved"`
+	TotalRefunded             int64                 `json:"total_refunded"`
+	AvailableBalance          int64                 `json:"available_balance"`
+	TotalRefundAmount         int64                 `json:"total_refund_amount"`
+	MissingSettlementAccounts int                   `json:"missing_settlement_accounts"`
+	Disbursements             []PlannedDisbursement `json:"disbursements"`
+}
+
+// PlannedDisbursement is a single contributor's share of a refund plan
+type PlannedDisbursement struct {
+	ContributionID       uuid.UUID `json:"contribution_id"`
+	UserID               uuid.UUID `json:"user_id"`
+	ContributionAmount   int64     `json:"contribution_amount"`
+	AlreadyRefunded      int64     `json:"already_refunded"`
+	Amount               int64     `json:"amount"`
+	Currency             string    `json:"currency"`
+	HasSettlementAccount bool      `json:"has_settlement_account"`
+
+	// Settlement account snapshot, persisted on initiation but never exposed in previews
+	SettlementBankName      string `json:"-"`
+	SettlementAccountNumber string `json:"-"`
+	SettlementAccountName   string `json:"-"`
 }
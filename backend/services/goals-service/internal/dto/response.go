@@ -0,0 +1,203 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// Typed bodies for the v1 responses that used to be built inline with gin.H. The JSON
+// tags keep the wire format those endpoints already had.
+
+// MessageResponse is a bare confirmation message
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// GoalListResponse is a page of goals, as served by the public, admin and watched goal lists
+type GoalListResponse struct {
+	Data  []models.Goal `json:"data"`
+	Total int64         `json:"total"`
+	Page  int           `json:"page"`
+	Size  int           `json:"size"`
+}
+
+// PublicGoalListResponse is a page of the public goal list, without banking details
+type PublicGoalListResponse struct {
+	Data  []PublicGoalResponse `json:"data"`
+	Total int64                `json:"total"`
+	Page  int                  `json:"page"`
+	Size  int                  `json:"size"`
+}
+
+// GoalSearchResponse is a page of goal search results, most relevant first
+type GoalSearchResponse struct {
+	Data  []GoalSearchResult `json:"data"`
+	Total int64              `json:"total"`
+	Page  int                `json:"page"`
+	Size  int                `json:"size"`
+}
+
+// GoalSearchResult is a goal matching a search. TitleHighlight and Snippet are
+// HTML-escaped, with the matched words wrapped in <mark>.
+type GoalSearchResult struct {
+	PublicGoalResponse
+	Score          float64 `json:"score"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+// OwnerGoalResponse is a goal's full detail, served only to its owner: deposit account,
+// withdrawals and every contribution
+type OwnerGoalResponse struct {
+	models.Goal
+}
+
+// PublicGoalResponse is a goal as anyone but its owner sees it. It carries no deposit
+// account or withdrawals, and only the amount, date and (unless anonymous) contributor of
+// each confirmed contribution.
+type PublicGoalResponse struct {
+	ID                        uuid.UUID                    `json:"id"`
+	OwnerID                   uuid.UUID                    `json:"owner_id"`
+	Title                     string                       `json:"title"`
+	Slug                      *string                      `json:"slug,omitempty"`
+	Description               string                       `json:"description"`
+	TargetAmount              int64                        `json:"target_amount"`
+	Currency                  string                       `json:"currency"`
+	Deadline                  *time.Time                   `json:"deadline,omitempty"`
+	Status                    models.GoalStatus            `json:"status"`
+	IsPublic                  bool                         `json:"is_public"`
+	Visibility                models.GoalVisibility        `json:"visibility"`
+	CloseOnTarget             bool                         `json:"close_on_target"`
+	FixedContributionAmount   int64                        `json:"fixed_contribution_amount"`
+	RequireProofForWithdrawal bool                         `json:"require_proof_for_withdrawal"`
+	WeightedVoting            bool                         `json:"weighted_voting"`
+	FeeMode                   models.FeeMode               `json:"fee_mode"`
+	RequiredApprovals         int                          `json:"required_approvals"`
+	SuspendedAt               *time.Time                   `json:"suspended_at,omitempty"`
+	SuspensionReason          string                       `json:"suspension_reason,omitempty"`
+	CurrentAmount             int64                        `json:"current_amount"`
+	ContributorCount          int64                        `json:"contributor_count"`
+	CreatedAt                 time.Time                    `json:"created_at"`
+	UpdatedAt                 time.Time                    `json:"updated_at"`
+	Milestones                []models.Milestone           `json:"milestones,omitempty"`
+	Contributions             []PublicContributionResponse `json:"contributions,omitempty"`
+	Proofs                    []models.Proof               `json:"proofs,omitempty"`
+}
+
+// PublicContributionResponse is a confirmed contribution as shown on a goal's public
+// detail. ContributorID is omitted for anonymous contributions.
+type PublicContributionResponse struct {
+	Amount        int64      `json:"amount"`
+	Currency      string     `json:"currency"`
+	ContributorID *uuid.UUID `json:"contributor_id,omitempty"`
+	IsAnonymous   bool       `json:"is_anonymous"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// AuditLogResponse is a page of a goal's audit log, newest first
+type AuditLogResponse struct {
+	Data  []models.AuditLog `json:"data"`
+	Total int64             `json:"total"`
+	Page  int               `json:"page"`
+	Size  int               `json:"size"`
+}
+
+// MyGoalsResponse is a page of the caller's own goals
+type MyGoalsResponse struct {
+	Goals []models.Goal `json:"goals"`
+	Total int64         `json:"total"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+}
+
+// DeleteGoalResponse confirms a deletion. Goal is set when the goal had contributions or
+// withdrawals and was archived instead.
+type DeleteGoalResponse struct {
+	Message string       `json:"message"`
+	Goal    *models.Goal `json:"goal,omitempty"`
+}
+
+// CreatedMilestoneResponse is a newly created milestone with the goal's allocation after it
+type CreatedMilestoneResponse struct {
+	Milestone  models.Milestone    `json:"milestone"`
+	Allocation MilestoneAllocation `json:"allocation"`
+}
+
+// UpdatedMilestoneResponse is an edited milestone, the later recurring instances the edit
+// was applied to, and the goal's allocation after it
+type UpdatedMilestoneResponse struct {
+	Milestone  models.Milestone    `json:"milestone"`
+	Propagated []models.Milestone  `json:"propagated,omitempty"`
+	Allocation MilestoneAllocation `json:"allocation"`
+}
+
+// DeletedMilestoneResponse reports a deleted milestone and how many of its contributions
+// were moved to the goal as a whole
+type DeletedMilestoneResponse struct {
+	Message                 string `json:"message"`
+	ReassignedContributions int64  `json:"reassigned_contributions"`
+}
+
+// CompletedMilestoneResponse is a completed milestone and, for recurring milestones,
+// the next one scheduled
+type CompletedMilestoneResponse struct {
+	Completed *models.Milestone `json:"completed"`
+	Next      *models.Milestone `json:"next"`
+}
+
+// MilestoneListResponse lists a goal's milestones
+type MilestoneListResponse struct {
+	Milestones []models.Milestone `json:"milestones"`
+}
+
+// ContributionListResponse lists the caller's contributions
+type ContributionListResponse struct {
+	Contributions []MyContribution `json:"contributions"`
+	Total         int              `json:"total"`
+}
+
+// RecurringContributionListResponse lists the caller's recurring contributions
+type RecurringContributionListResponse struct {
+	RecurringContributions []models.RecurringContribution `json:"recurring_contributions"`
+	Total                  int                            `json:"total"`
+}
+
+// RefundInitiatedResponse is a newly initiated refund
+type RefundInitiatedResponse struct {
+	Refund  *models.Refund `json:"refund"`
+	Message string         `json:"message"`
+}
+
+// RefundPreviewResponse is the refund a request would create
+type RefundPreviewResponse struct {
+	Preview *RefundPlan `json:"preview"`
+}
+
+// RefundResponse wraps a single refund
+type RefundResponse struct {
+	Refund *models.Refund `json:"refund"`
+}
+
+// RefundListResponse lists a goal's refunds
+type RefundListResponse struct {
+	Refunds []models.Refund `json:"refunds"`
+	Count   int             `json:"count"`
+}
+
+// RefundRequestResponse wraps a single refund request
+type RefundRequestResponse struct {
+	RefundRequest *models.RefundRequest `json:"refund_request"`
+}
+
+// RefundRequestListResponse lists a goal's refund requests
+type RefundRequestListResponse struct {
+	RefundRequests []models.RefundRequest `json:"refund_requests"`
+	Count          int                    `json:"count"`
+}
+
+// ReconcileResponse reports how many goals had their totals repaired
+type ReconcileResponse struct {
+	Reconciled int64 `json:"reconciled"`
+}
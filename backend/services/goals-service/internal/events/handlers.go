@@ -1,50 +1,49 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
-	"time"
 
 	"github.com/gofund/goals-service/internal/service"
 	"github.com/gofund/shared/events"
-	"github.com/gofund/shared/messaging"
+	"github.com/gofund/shared/logger"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/gofund/shared/validator"
 	"github.com/google/uuid"
 )
 
 // EventHandler handles incoming events from RabbitMQ
 type EventHandler struct {
 	contributionService *service.ContributionService
-	goalService         *service.GoalService
-	publisher           messaging.Publisher
+	withdrawalService   *service.WithdrawalService
+	refundService       *service.RefundService
 }
 
 // NewEventHandler creates a new event handler instance
 func NewEventHandler(
 	contributionService *service.ContributionService,
-	goalService *service.GoalService,
-	publisher messaging.Publisher,
+	withdrawalService *service.WithdrawalService,
+	refundService *service.RefundService,
 ) *EventHandler {
 	return &EventHandler{
 		contributionService: contributionService,
-		goalService:         goalService,
-		publisher:           publisher,
+		withdrawalService:   withdrawalService,
+		refundService:       refundService,
 	}
 }
 
 // HandlePaymentVerified handles the PaymentVerified event
-func (h *EventHandler) HandlePaymentVerified(data []byte) error {
+func (h *EventHandler) HandlePaymentVerified(ctx context.Context, data []byte) error {
 	var event events.PaymentVerified
 	if err := json.Unmarshal(data, &event); err != nil {
 		return fmt.Errorf("failed to unmarshal PaymentVerified event: %w", err)
 	}
 
-	log.Printf("Received PaymentVerified event: GoalID=%s, UserID=%s, Amount=%d", event.GoalID, event.UserID, event.Amount)
+	logger.Printf(ctx, "Received PaymentVerified event: GoalID=%s, UserID=%s, Amount=%d, Currency=%s", event.GoalID, event.UserID, event.Amount, event.Currency)
 
-	// In a real system, we'd have a contribution ID in the event metadata.
-	// Since it's missing from the contract, we'll try to find a matching pending contribution.
-	// Note: This is a simplified approach.
-	
 	goalID, err := uuid.Parse(event.GoalID)
 	if err != nil {
 		return fmt.Errorf("invalid goal ID in event: %w", err)
@@ -58,52 +57,188 @@ func (h *EventHandler) HandlePaymentVerified(data []byte) error {
 	paymentID, err := uuid.Parse(event.PaymentID)
 	if err != nil {
 		// Log error but maybe we can proceed if paymentID is not strictly needed for business logic
-		log.Printf("Warning: invalid payment ID in event: %v", err)
+		logger.Printf(ctx, "Warning: invalid payment ID in event: %v", err)
 	}
 
-	// Find contributions for this goal and user
-	contributions, err := h.contributionService.GetContributionsByGoal(goalID)
-	if err != nil {
-		return fmt.Errorf("failed to fetch contributions for goal: %w", err)
+	// Payments initialized for a contribution intent carry its ID; older ones are matched
+	// against a pending contribution by user, goal and amount
+	targetContributionID, settled := h.referencedContribution(ctx, event, goalID, userID)
+	if settled {
+		logger.Printf(ctx, "Contribution %s is already settled; ignoring redelivered payment %s", targetContributionID, event.PaymentID)
+		return nil
 	}
+	if targetContributionID == uuid.Nil {
+		contributions, err := h.contributionService.GetContributionsByGoal(ctx, goalID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch contributions for goal: %w", err)
+		}
 
-	var targetContributionID uuid.UUID
-	for _, c := range contributions {
-		if c.UserID == userID && c.Amount == event.Amount && c.Status == "PENDING" {
-			targetContributionID = c.ID
-			break
+		for _, c := range contributions {
+			if c.UserID == userID && c.ChargeAmount() == event.Amount && c.Status == "PENDING" && validator.SameCurrency(c.Currency, event.Currency) {
+				targetContributionID = c.ID
+				break
+			}
 		}
 	}
 
 	if targetContributionID == uuid.Nil {
-		log.Printf("No matching pending contribution found for GoalID=%s, UserID=%s, Amount=%d", event.GoalID, event.UserID, event.Amount)
+		logger.Printf(ctx, "No matching pending contribution found for GoalID=%s, UserID=%s, Amount=%d", event.GoalID, event.UserID, event.Amount)
 		return nil
 	}
 
-	// Confirm contribution
-	if err := h.contributionService.ConfirmContribution(targetContributionID, paymentID); err != nil {
+	// Confirm contribution. ContributionConfirmed, and GoalClosedEarly and GoalFunded when
+	// it filled the goal, are recorded with it.
+	if _, err := h.contributionService.ConfirmContribution(ctx, targetContributionID, paymentID); err != nil {
+		if errors.Is(err, service.ErrGoalNotFound) {
+			// The contribution outlived its goal (see /admin/data-quality/orphans)
+			logger.Printf(ctx, "Skipping payment %s: goal %s no longer exists for contribution %s", event.PaymentID, goalID, targetContributionID)
+			metrics.IncrementCounter("goals.orphan.skipped", "parent:goal")
+			return nil
+		}
 		return fmt.Errorf("failed to confirm contribution: %w", err)
 	}
 
-	log.Printf("Confirmed contribution %s for goal %s", targetContributionID, goalID)
+	logger.Printf(ctx, "Confirmed contribution %s for goal %s", targetContributionID, goalID)
 
-	// Check if goal reached its target and emit event if needed
-	progress, err := h.goalService.GetGoalProgress(goalID)
-	if err == nil && progress.ProgressPercent >= 100 && progress.Goal.Status == "OPEN" {
-		if h.publisher != nil {
-			goalFundedEvent := events.GoalFunded{
-				ID:        uuid.New().String(),
-				GoalID:    goalID.String(),
-				Amount:    progress.TotalContributions,
-				CreatedAt: time.Now().Unix(),
-			}
-			if err := h.publisher.Publish("GoalFunded", goalFundedEvent); err != nil {
-				log.Printf("Failed to publish GoalFunded event: %v", err)
-			} else {
-				log.Printf("Goal %s is now fully funded! GoalFunded event published.", goalID)
-			}
+	if event.AuthorizationCode != "" && paymentID != uuid.Nil {
+		if err := h.contributionService.SaveAuthorization(ctx, paymentID, event.AuthorizationCode, event.AuthorizationEmail); err != nil {
+			logger.Printf(ctx, "Failed to save card authorization for payment %s: %v", paymentID, err)
 		}
 	}
 
 	return nil
 }
+
+// HandleWithdrawalCompleted records a successful payout from payments-service
+func (h *EventHandler) HandleWithdrawalCompleted(ctx context.Context, data []byte) error {
+	var event events.WithdrawalCompleted
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal WithdrawalCompleted event: %w", err)
+	}
+
+	withdrawalID, err := uuid.Parse(event.WithdrawalID)
+	if err != nil {
+		return fmt.Errorf("invalid withdrawal ID in event: %w", err)
+	}
+
+	if err := h.withdrawalService.CompleteWithdrawal(ctx, withdrawalID, event.TransferReference, event.TransferCode); err != nil {
+		return fmt.Errorf("failed to complete withdrawal: %w", err)
+	}
+
+	logger.Printf(ctx, "Withdrawal %s completed (transfer %s)", withdrawalID, event.TransferReference)
+	return nil
+}
+
+// HandleWithdrawalFailed records a failed payout from payments-service
+func (h *EventHandler) HandleWithdrawalFailed(ctx context.Context, data []byte) error {
+	var event events.WithdrawalFailed
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal WithdrawalFailed event: %w", err)
+	}
+
+	withdrawalID, err := uuid.Parse(event.WithdrawalID)
+	if err != nil {
+		return fmt.Errorf("invalid withdrawal ID in event: %w", err)
+	}
+
+	if err := h.withdrawalService.FailWithdrawal(ctx, withdrawalID, event.TransferReference, event.Reason); err != nil {
+		return fmt.Errorf("failed to mark withdrawal failed: %w", err)
+	}
+
+	logger.Printf(ctx, "Withdrawal %s failed: %s", withdrawalID, event.Reason)
+	return nil
+}
+
+// HandleRefundDisbursementCompleted records a refund disbursement paid out by payments-service
+func (h *EventHandler) HandleRefundDisbursementCompleted(ctx context.Context, data []byte) error {
+	var event events.RefundDisbursementCompleted
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal RefundDisbursementCompleted event: %w", err)
+	}
+
+	disbursementID, err := uuid.Parse(event.DisbursementID)
+	if err != nil {
+		return fmt.Errorf("invalid disbursement ID in event: %w", err)
+	}
+
+	if err := h.refundService.RecordDisbursementTransfer(ctx, disbursementID, event.TransferReference, event.TransferCode, ""); err != nil {
+		return fmt.Errorf("failed to record refund disbursement transfer: %w", err)
+	}
+	if err := h.refundService.UpdateDisbursementStatus(ctx, disbursementID, models.RefundStatusCompleted, nil); err != nil {
+		return fmt.Errorf("failed to complete refund disbursement: %w", err)
+	}
+
+	metrics.IncrementCounter("goals.refund_disbursement.completed")
+	logger.Printf(ctx, "Refund disbursement %s completed (transfer %s)", disbursementID, event.TransferReference)
+	return nil
+}
+
+// HandleRefundDisbursementFailed records a refund disbursement payments-service could not pay out
+func (h *EventHandler) HandleRefundDisbursementFailed(ctx context.Context, data []byte) error {
+	var event events.RefundDisbursementFailed
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal RefundDisbursementFailed event: %w", err)
+	}
+
+	disbursementID, err := uuid.Parse(event.DisbursementID)
+	if err != nil {
+		return fmt.Errorf("invalid disbursement ID in event: %w", err)
+	}
+
+	if err := h.refundService.RecordDisbursementTransfer(ctx, disbursementID, event.TransferReference, "", event.Reason); err != nil {
+		return fmt.Errorf("failed to record refund disbursement transfer: %w", err)
+	}
+	if err := h.refundService.UpdateDisbursementStatus(ctx, disbursementID, models.RefundStatusFailed, nil); err != nil {
+		return fmt.Errorf("failed to mark refund disbursement failed: %w", err)
+	}
+
+	metrics.IncrementCounter("goals.refund_disbursement.failed")
+	logger.Printf(ctx, "Refund disbursement %s failed: %s", disbursementID, event.Reason)
+	return nil
+}
+
+// referencedContribution returns the contribution named by the event, or uuid.Nil when the
+// event names none or the contribution does not match what was actually paid. settled
+// reports that this payment already confirmed it, i.e. the event is a redelivery.
+func (h *EventHandler) referencedContribution(ctx context.Context, event events.PaymentVerified, goalID, userID uuid.UUID) (contributionID uuid.UUID, settled bool) {
+	if event.ContributionID == "" {
+		return uuid.Nil, false
+	}
+
+	contributionID, err := uuid.Parse(event.ContributionID)
+	if err != nil {
+		logger.Printf(ctx, "Warning: invalid contribution ID in event: %v", err)
+		return uuid.Nil, false
+	}
+
+	contribution, err := h.contributionService.GetContributionByID(ctx, contributionID, userID)
+	if err != nil {
+		logger.Printf(ctx, "Warning: contribution %s referenced by payment %s not found: %v", contributionID, event.PaymentID, err)
+		return uuid.Nil, false
+	}
+
+	// The ID comes from the client that initialized the payment, so it must agree with the
+	// payment, which includes the fee on add-on goals
+	if contribution.GoalID != goalID || contribution.UserID != userID || contribution.ChargeAmount() != event.Amount {
+		logger.Printf(ctx, "Warning: contribution %s does not match payment %s; ignoring the reference", contributionID, event.PaymentID)
+		metrics.IncrementCounter("goals.payment.contribution_mismatch")
+		return uuid.Nil, false
+	}
+	// An amount in another currency is a different amount, however equal the numbers
+	if !validator.SameCurrency(contribution.Currency, event.Currency) {
+		logger.Printf(ctx, "Warning: payment %s was made in %s but contribution %s is in %s; not confirming it",
+			event.PaymentID, event.Currency, contributionID, contribution.Currency)
+		metrics.IncrementCounter("goals.payment.currency_mismatch")
+		return uuid.Nil, false
+	}
+
+	switch contribution.Status {
+	case models.ContributionStatusPending, models.ContributionStatusExpired:
+		return contributionID, false
+	case models.ContributionStatusConfirmed, models.ContributionStatusRefunded:
+		if contribution.PaymentID != nil && contribution.PaymentID.String() == event.PaymentID {
+			return contributionID, true
+		}
+	}
+	return uuid.Nil, false
+}
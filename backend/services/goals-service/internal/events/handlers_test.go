@@ -0,0 +1,135 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+func TestPaymentVerifiedSkipsContributionOfDeletedGoal(t *testing.T) {
+	db := testdb.Open(t)
+	testdb.DropForeignKeys(t, db)
+	repo := repository.NewRepository(db)
+	h := NewEventHandler(service.NewContributionService(repo, nil, nil, nil, 0, 0), nil, nil)
+
+	// A pending intent whose goal was hard-deleted before its payment landed
+	contribution := &models.Contribution{
+		GoalID:    uuid.New(),
+		UserID:    uuid.New(),
+		Amount:    20_000,
+		Currency:  "NGN",
+		Status:    models.ContributionStatusPending,
+		NetAmount: 20_000,
+	}
+	if err := db.Omit(clause.Associations).Create(contribution).Error; err != nil {
+		t.Fatalf("failed to seed contribution: %v", err)
+	}
+
+	data, err := json.Marshal(events.PaymentVerified{
+		PaymentID:      uuid.NewString(),
+		ContributionID: contribution.ID.String(),
+		GoalID:         contribution.GoalID.String(),
+		UserID:         contribution.UserID.String(),
+		Amount:         contribution.Amount,
+		Currency:       "NGN",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Returning an error would redeliver the event forever
+	if err := h.HandlePaymentVerified(context.Background(), data); err != nil {
+		t.Fatalf("HandlePaymentVerified: %v", err)
+	}
+	stored, err := repo.Contribution.GetContributionByID(context.Background(), contribution.ID)
+	if err != nil {
+		t.Fatalf("GetContributionByID: %v", err)
+	}
+	if stored.Status != models.ContributionStatusPending {
+		t.Errorf("status = %s, want it left %s", stored.Status, models.ContributionStatusPending)
+	}
+}
+
+// TestPaymentVerifiedRequiresMatchingCurrency delivers payments whose amount matches a
+// pending contribution; only one in the contribution's currency confirms it
+func TestPaymentVerifiedRequiresMatchingCurrency(t *testing.T) {
+	tests := []struct {
+		name            string
+		currency        string
+		referenceIntent bool
+		wantStatus      models.ContributionStatus
+	}{
+		{name: "referenced, other currency", currency: "USD", referenceIntent: true, wantStatus: models.ContributionStatusPending},
+		{name: "matched by amount, other currency", currency: "USD", wantStatus: models.ContributionStatusPending},
+		{name: "referenced, same currency", currency: "NGN", referenceIntent: true, wantStatus: models.ContributionStatusConfirmed},
+		// Events published before they carried a currency are NGN
+		{name: "matched by amount, no currency", currency: "", wantStatus: models.ContributionStatusConfirmed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testdb.Open(t)
+			repo := repository.NewRepository(db)
+			h := NewEventHandler(service.NewContributionService(repo, nil, nil, nil, 0, 0), nil, nil)
+			ctx := context.Background()
+
+			goal := &models.Goal{
+				OwnerID:      uuid.New(),
+				Title:        "Test goal",
+				TargetAmount: 1_000_000,
+				Currency:     "NGN",
+				Status:       models.GoalStatusOpen,
+				Visibility:   models.GoalVisibilityPublic,
+				FeeMode:      models.FeeModeAbsorb,
+			}
+			if err := repo.Goal.CreateGoal(ctx, goal); err != nil {
+				t.Fatalf("CreateGoal: %v", err)
+			}
+			contribution := &models.Contribution{
+				GoalID:    goal.ID,
+				UserID:    uuid.New(),
+				Amount:    20_000,
+				Currency:  "NGN",
+				Status:    models.ContributionStatusPending,
+				NetAmount: 20_000,
+			}
+			if err := repo.Contribution.CreateContribution(ctx, contribution); err != nil {
+				t.Fatalf("CreateContribution: %v", err)
+			}
+
+			event := events.PaymentVerified{
+				PaymentID: uuid.NewString(),
+				GoalID:    goal.ID.String(),
+				UserID:    contribution.UserID.String(),
+				Amount:    contribution.Amount,
+				Currency:  tt.currency,
+			}
+			if tt.referenceIntent {
+				event.ContributionID = contribution.ID.String()
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := h.HandlePaymentVerified(ctx, data); err != nil {
+				t.Fatalf("HandlePaymentVerified: %v", err)
+			}
+			stored, err := repo.Contribution.GetContributionByID(ctx, contribution.ID)
+			if err != nil {
+				t.Fatalf("GetContributionByID: %v", err)
+			}
+			if stored.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", stored.Status, tt.wantStatus)
+			}
+		})
+	}
+}
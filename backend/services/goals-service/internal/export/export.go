@@ -0,0 +1,157 @@
+// Package export writes a goal's contributions and withdrawals for spreadsheets, one row at
+// a time so an export never has to be held in memory.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+)
+
+// Formats an export can be written in
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Header is the CSV header row
+var Header = []string{"type", "id", "date", "contributor", "amount", "currency", "status", "reference"}
+
+// RowWriter writes export rows to an underlying stream. Close finishes the document and
+// must be called once every row is written.
+type RowWriter interface {
+	WriteRow(row dto.ExportRow) error
+	Close() error
+}
+
+// NewRowWriter returns a writer for format, which must be FormatCSV or FormatJSON
+func NewRowWriter(format string, w io.Writer) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w)
+	case FormatJSON:
+		return NewJSONWriter(w)
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// ContentType returns the media type of format
+func ContentType(format string) string {
+	if format == FormatJSON {
+		return "application/json; charset=utf-8"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// CSVWriter writes rows as RFC 4180 CSV with a header row
+type CSVWriter struct {
+	w *csv.Writer
+}
+
+// NewCSVWriter writes the header row to w and returns a writer for the rows that follow
+func NewCSVWriter(w io.Writer) (*CSVWriter, error) {
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+	if err := writer.Write(Header); err != nil {
+		return nil, err
+	}
+	return &CSVWriter{w: writer}, nil
+}
+
+// WriteRow writes one row
+func (cw *CSVWriter) WriteRow(row dto.ExportRow) error {
+	return cw.w.Write([]string{
+		row.Type,
+		row.ID.String(),
+		row.Date.UTC().Format(time.RFC3339),
+		escapeFormula(row.Contributor),
+		FormatMajorUnits(row.Amount),
+		row.Currency,
+		row.Status,
+		escapeFormula(row.Reference),
+	})
+}
+
+// Close flushes any buffered rows
+func (cw *CSVWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// JSONWriter writes rows as a JSON array of objects keyed like the CSV header
+type JSONWriter struct {
+	w       io.Writer
+	enc     *json.Encoder
+	written bool
+}
+
+// jsonRow is the JSON shape of an export row
+type jsonRow struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Date        string `json:"date"`
+	Contributor string `json:"contributor"`
+	Amount      string `json:"amount"`
+	Currency    string `json:"currency"`
+	Status      string `json:"status"`
+	Reference   string `json:"reference"`
+}
+
+// NewJSONWriter opens the array on w and returns a writer for its elements
+func NewJSONWriter(w io.Writer) (*JSONWriter, error) {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return nil, err
+	}
+	return &JSONWriter{w: w, enc: json.NewEncoder(w)}, nil
+}
+
+// WriteRow writes one array element
+func (jw *JSONWriter) WriteRow(row dto.ExportRow) error {
+	if jw.written {
+		if _, err := io.WriteString(jw.w, ","); err != nil {
+			return err
+		}
+	}
+	jw.written = true
+	return jw.enc.Encode(jsonRow{
+		Type:        row.Type,
+		ID:          row.ID.String(),
+		Date:        row.Date.UTC().Format(time.RFC3339),
+		Contributor: row.Contributor,
+		Amount:      FormatMajorUnits(row.Amount),
+		Currency:    row.Currency,
+		Status:      row.Status,
+		Reference:   row.Reference,
+	})
+}
+
+// Close closes the array
+func (jw *JSONWriter) Close() error {
+	_, err := io.WriteString(jw.w, "]\n")
+	return err
+}
+
+// FormatMajorUnits renders an amount in the smallest currency unit (e.g. kobo) in major
+// units with two decimals and no grouping, e.g. 500050 as "5000.50"
+func FormatMajorUnits(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+// escapeFormula keeps spreadsheets from evaluating user-supplied text such as display
+// names as formulas by prefixing it with an apostrophe
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
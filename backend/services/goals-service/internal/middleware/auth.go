@@ -1,29 +1,81 @@
 package middleware
 
 import (
-	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/httperr"
+	"github.com/gofund/shared/identity"
+	"github.com/gofund/shared/models"
 	"github.com/google/uuid"
 )
 
+// IdentityGuard drops identity headers that were not signed by the gateway, so
+// callers that bypass Nginx cannot impersonate users. It must run before AuthMiddleware.
+func IdentityGuard(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity.Guard(c.Request.Header, secret)
+		c.Next()
+	}
+}
+
 // AuthMiddleware ensures the X-User-ID header is present and valid
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userIDStr := c.GetHeader("X-User-ID")
 		if userIDStr == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Missing X-User-ID header"})
-			c.Abort()
+			abortWithError(c, apperrors.Unauthenticated("unauthenticated", "Unauthorized: Missing X-User-ID header"))
 			return
 		}
 
 		_, err := uuid.Parse(userIDStr)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Invalid X-User-ID header"})
-			c.Abort()
+			abortWithError(c, apperrors.Unauthenticated("unauthenticated", "Unauthorized: Invalid X-User-ID header"))
 			return
 		}
 
 		c.Next()
 	}
 }
+
+// RequireRole ensures the X-User-Roles header (set by Nginx after auth verification)
+// includes at least one of roles
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, role := range strings.Split(c.GetHeader(identity.HeaderUserRoles), ",") {
+			for _, required := range roles {
+				if strings.TrimSpace(role) == required {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		abortWithError(c, apperrors.Forbidden("role_required", "Forbidden: "+strings.Join(roles, " or ")+" role required"))
+	}
+}
+
+// AdminMiddleware ensures the caller has the admin role
+func AdminMiddleware() gin.HandlerFunc {
+	return RequireRole(string(models.UserRoleAdmin))
+}
+
+// RequireVerifiedEmail refuses callers whose X-User-Email-Verified header (set by Nginx
+// after auth verification) is not "true". It lets every request through when disabled.
+func RequireVerifiedEmail(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled || c.GetHeader(identity.HeaderEmailVerified) == "true" {
+			c.Next()
+			return
+		}
+
+		abortWithError(c, apperrors.Forbidden("email_not_verified", "Forbidden: verified email required"))
+	}
+}
+
+// abortWithError writes err in the shared error format and stops the handler chain
+func abortWithError(c *gin.Context, err error) {
+	httperr.Respond(c.Writer, c.Request, err, nil)
+	c.Abort()
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/shared/ratelimit"
+)
+
+// ClientIP makes the caller's IP address, as seen by the gateway, available to the
+// service layer through the request context
+func ClientIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(service.WithClientIP(c.Request.Context(), ratelimit.ClientIP(c.Request)))
+		c.Next()
+	}
+}
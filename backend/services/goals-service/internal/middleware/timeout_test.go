@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeoutSetsRequestDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantDeadline bool
+	}{
+		{name: "default request timeout", timeout: 10 * time.Second, wantDeadline: true},
+		{name: "disabled", timeout: 0, wantDeadline: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadline time.Time
+			var hasDeadline bool
+			r := gin.New()
+			r.Use(Timeout(tt.timeout))
+			r.GET("/", func(c *gin.Context) {
+				deadline, hasDeadline = c.Request.Context().Deadline()
+			})
+
+			start := time.Now()
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			end := time.Now()
+
+			if hasDeadline != tt.wantDeadline {
+				t.Fatalf("request has a deadline: %v, want %v", hasDeadline, tt.wantDeadline)
+			}
+			if hasDeadline {
+				if deadline.Before(start.Add(tt.timeout)) || deadline.After(end.Add(tt.timeout)) {
+					t.Errorf("deadline %s after the request started, want %s", deadline.Sub(start), tt.timeout)
+				}
+			}
+		})
+	}
+}
+
+// TestTimeoutCancelsSlowHandlers checks a handler still working when the deadline passes
+// sees its context cancelled, as a GORM query run with it would
+func TestTimeoutCancelsSlowHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var err error
+	r := gin.New()
+	r.Use(Timeout(50 * time.Millisecond))
+	r.GET("/", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			err = c.Request.Context().Err()
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("handler context err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
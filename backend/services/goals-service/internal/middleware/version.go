@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/metrics"
+)
+
+// APIVersion labels responses with the API version that served them and counts requests
+// per version, so migration off older versions can be tracked
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Api-Version", version)
+		c.Next()
+		metrics.IncrementCounter("goals.api.requests", "version:"+version, fmt.Sprintf("status:%d", c.Writer.Status()))
+	}
+}
+
+// Deprecated marks responses of a deprecated API version and points clients at its successor
+func Deprecated(successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		c.Next()
+	}
+}
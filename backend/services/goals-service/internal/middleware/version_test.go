@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/metrics"
+)
+
+// requestRecorder counts the goals.api.requests metrics by tag
+type requestRecorder struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (r *requestRecorder) Incr(name string, tags []string, rate float64) error {
+	if name != "goals.api.requests" {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts[strings.Join(tags, ",")]++
+	return nil
+}
+
+func (r *requestRecorder) Histogram(string, float64, []string, float64) error { return nil }
+func (r *requestRecorder) Gauge(string, float64, []string, float64) error     { return nil }
+func (r *requestRecorder) Close() error                                       { return nil }
+
+func TestAPIVersionHeadersAndMetric(t *testing.T) {
+	recorder := &requestRecorder{counts: make(map[string]int)}
+	previous := metrics.SetClient(recorder)
+	t.Cleanup(func() { metrics.SetClient(previous) })
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v1 := r.Group("/api/v1/goals", APIVersion("v1"), Deprecated("/api/v2/goals"))
+	v1.GET("/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	v2 := r.Group("/api/v2/goals", APIVersion("v2"))
+	v2.GET("/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		path           string
+		wantVersion    string
+		wantDeprecated bool
+		wantTags       string
+	}{
+		{path: "/api/v1/goals/1", wantVersion: "v1", wantDeprecated: true, wantTags: "version:v1,status:404"},
+		{path: "/api/v2/goals/1", wantVersion: "v2", wantTags: "version:v2,status:200"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantVersion, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if got := w.Header().Get("Api-Version"); got != tt.wantVersion {
+				t.Errorf("Api-Version = %q, want %q", got, tt.wantVersion)
+			}
+			deprecated := w.Header().Get("Deprecation") == "true"
+			if deprecated != tt.wantDeprecated {
+				t.Errorf("Deprecation = %q, want deprecated %v", w.Header().Get("Deprecation"), tt.wantDeprecated)
+			}
+			if link := w.Header().Get("Link"); tt.wantDeprecated && link != `</api/v2/goals>; rel="successor-version"` {
+				t.Errorf("Link = %q, want the v2 successor", link)
+			}
+			if n := recorder.counts[tt.wantTags]; n != 1 {
+				t.Errorf("goals.api.requests{%s} = %d, want 1 (counts %v)", tt.wantTags, n, recorder.counts)
+			}
+		})
+	}
+}
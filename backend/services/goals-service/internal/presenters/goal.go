@@ -0,0 +1,202 @@
+// Package presenters shapes service results into the typed v2 API responses. Unlike v1,
+// which serialises models directly, presenters never expose relationships such as
+// contributions or withdrawals, and always render optional fields (as null).
+package presenters
+
+import (
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// Goal is the v2 representation of a goal
+type Goal struct {
+	ID                        uuid.UUID   `json:"id"`
+	OwnerID                   uuid.UUID   `json:"owner_id"`
+	Title                     string      `json:"title"`
+	Description               string      `json:"description"`
+	TargetAmount              int64       `json:"target_amount"`
+	Currency                  string      `json:"currency"`
+	Deadline                  *time.Time  `json:"deadline"`
+	Status                    string      `json:"status"`
+	IsPublic                  bool        `json:"is_public"`
+	Visibility                string      `json:"visibility"`
+	CloseOnTarget             bool        `json:"close_on_target"`
+	FixedContributionAmount   int64       `json:"fixed_contribution_amount"`
+	RequireProofForWithdrawal bool        `json:"require_proof_for_withdrawal"`
+	WeightedVoting            bool        `json:"weighted_voting"`
+	FeeMode                   string      `json:"fee_mode"`
+	CurrentAmount             int64       `json:"current_amount"`
+	ContributorCount          int64       `json:"contributor_count"`
+	Milestones                []Milestone `json:"milestones"`
+	CreatedAt                 time.Time   `json:"created_at"`
+	UpdatedAt                 time.Time   `json:"updated_at"`
+}
+
+// Milestone is the v2 representation of a milestone
+type Milestone struct {
+	ID                 uuid.UUID  `json:"id"`
+	Title              string     `json:"title"`
+	Description        string     `json:"description"`
+	TargetAmount       int64      `json:"target_amount"`
+	OrderIndex         int        `json:"order_index"`
+	IsRecurring        bool       `json:"is_recurring"`
+	RecurrenceType     *string    `json:"recurrence_type"`
+	RecurrenceInterval int        `json:"recurrence_interval"`
+	NextDueDate        *time.Time `json:"next_due_date"`
+	ParentMilestoneID  *uuid.UUID `json:"parent_milestone_id"`
+	Status             string     `json:"status"`
+	CompletedAt        *time.Time `json:"completed_at"`
+}
+
+// Pagination describes the page a list response holds
+type Pagination struct {
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+}
+
+// GoalList is a page of goals
+type GoalList struct {
+	Items      []Goal     `json:"items"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// GoalProgress is the v2 representation of a goal's funding progress
+type GoalProgress struct {
+	Goal               Goal                `json:"goal"`
+	TotalContributions int64               `json:"total_contributions"`
+	TotalWithdrawals   int64               `json:"total_withdrawals"`
+	TotalFees          int64               `json:"total_fees"`
+	AvailableBalance   int64               `json:"available_balance"`
+	ProgressPercent    float64             `json:"progress_percent"`
+	ContributorCount   int64               `json:"contributor_count"`
+	Milestones         []MilestoneProgress `json:"milestones"`
+	// ActiveMilestoneID is the milestone currently being funded, or null when there is none
+	ActiveMilestoneID *uuid.UUID `json:"active_milestone_id"`
+	// WithdrawalBlockedReason is the error code a goal-level withdrawal would be refused
+	// with (e.g. "proof_required"), or null when it is allowed
+	WithdrawalBlockedReason *string `json:"withdrawal_blocked_reason"`
+}
+
+// MilestoneProgress is a milestone's funding progress
+type MilestoneProgress struct {
+	Milestone               Milestone `json:"milestone"`
+	CurrentAmount           int64     `json:"current_amount"`
+	ProgressPercent         float64   `json:"progress_percent"`
+	WithdrawalBlockedReason *string   `json:"withdrawal_blocked_reason"`
+}
+
+// NewGoal presents a goal
+func NewGoal(goal *models.Goal) Goal {
+	presented := Goal{
+		ID:                        goal.ID,
+		OwnerID:                   goal.OwnerID,
+		Title:                     goal.Title,
+		Description:               goal.Description,
+		TargetAmount:              goal.TargetAmount,
+		Currency:                  goal.Currency,
+		Deadline:                  goal.Deadline,
+		Status:                    string(goal.Status),
+		IsPublic:                  goal.IsPublic,
+		Visibility:                string(goal.Visibility),
+		CloseOnTarget:             goal.CloseOnTarget,
+		FixedContributionAmount:   goal.FixedContributionAmount,
+		RequireProofForWithdrawal: goal.RequireProofForWithdrawal,
+		WeightedVoting:            goal.WeightedVoting,
+		FeeMode:                   string(goal.FeeMode),
+		CurrentAmount:             goal.CurrentAmount,
+		ContributorCount:          goal.ContributorCount,
+		Milestones:                make([]Milestone, 0, len(goal.Milestones)),
+		CreatedAt:                 goal.CreatedAt,
+		UpdatedAt:                 goal.UpdatedAt,
+	}
+	for i := range goal.Milestones {
+		presented.Milestones = append(presented.Milestones, NewMilestone(&goal.Milestones[i]))
+	}
+	return presented
+}
+
+// NewMilestone presents a milestone
+func NewMilestone(milestone *models.Milestone) Milestone {
+	presented := Milestone{
+		ID:                 milestone.ID,
+		Title:              milestone.Title,
+		Description:        milestone.Description,
+		TargetAmount:       milestone.TargetAmount,
+		OrderIndex:         milestone.OrderIndex,
+		IsRecurring:        milestone.IsRecurring,
+		RecurrenceInterval: milestone.RecurrenceInterval,
+		NextDueDate:        milestone.NextDueDate,
+		ParentMilestoneID:  milestone.ParentMilestoneID,
+		Status:             string(milestone.Status),
+		CompletedAt:        milestone.CompletedAt,
+	}
+	if milestone.RecurrenceType != nil {
+		recurrenceType := string(*milestone.RecurrenceType)
+		presented.RecurrenceType = &recurrenceType
+	}
+	return presented
+}
+
+// NewGoalList presents a page of goals
+func NewGoalList(goals []models.Goal, page, pageSize int, total int64) GoalList {
+	list := GoalList{
+		Items:      make([]Goal, 0, len(goals)),
+		Pagination: NewPagination(page, pageSize, total),
+	}
+	for i := range goals {
+		list.Items = append(list.Items, NewGoal(&goals[i]))
+	}
+	return list
+}
+
+// NewPagination describes a page of pageSize items out of total
+func NewPagination(page, pageSize int, total int64) Pagination {
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	}
+	return Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
+
+// NewGoalProgress presents a goal's funding progress
+func NewGoalProgress(progress *dto.GoalProgress) GoalProgress {
+	presented := GoalProgress{
+		Goal:                    NewGoal(&progress.Goal),
+		TotalContributions:      progress.TotalContributions,
+		TotalWithdrawals:        progress.TotalWithdrawals,
+		TotalFees:               progress.TotalFees,
+		AvailableBalance:        progress.AvailableBalance,
+		ProgressPercent:         progress.ProgressPercent,
+		ContributorCount:        progress.ContributorCount,
+		Milestones:              make([]MilestoneProgress, 0, len(progress.Milestones)),
+		ActiveMilestoneID:       progress.ActiveMilestoneID,
+		WithdrawalBlockedReason: optionalString(progress.GoalWithdrawalBlockedReason),
+	}
+	for i := range progress.Milestones {
+		presented.Milestones = append(presented.Milestones, MilestoneProgress{
+			Milestone:               NewMilestone(&progress.Milestones[i].Milestone),
+			CurrentAmount:           progress.Milestones[i].CurrentAmount,
+			ProgressPercent:         progress.Milestones[i].ProgressPercent,
+			WithdrawalBlockedReason: optionalString(progress.Milestones[i].WithdrawalBlockedReason),
+		})
+	}
+	return presented
+}
+
+// optionalString renders an empty string as null
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,144 @@
+// Package receipts renders contribution receipts as HTML or PDF. The PDF renderer is a
+// deliberately small single-page, text-only writer using the standard Helvetica fonts,
+// which every PDF reader provides, so no font files or PDF library are needed.
+package receipts
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/gofund/goals-service/internal/dto"
+)
+
+// Field is one labelled line of a receipt
+type Field struct {
+	Label string
+	Value string
+}
+
+// Fields returns the receipt's lines in display order
+func Fields(receipt *dto.ContributionReceipt) []Field {
+	return []Field{
+		{Label: "Receipt for", Value: receipt.ContributorName},
+		{Label: "Goal", Value: receipt.GoalTitle},
+		{Label: "Amount", Value: FormatAmount(receipt.Amount, receipt.Currency)},
+		{Label: "Payment reference", Value: receipt.PaymentReference},
+		{Label: "Date", Value: receipt.PaidAt.UTC().Format("2 January 2006, 15:04 MST")},
+		{Label: "Contribution ID", Value: receipt.ContributionID.String()},
+	}
+}
+
+// FormatAmount renders an amount in the smallest currency unit (e.g. kobo) as
+// "NGN 5,000.00"
+func FormatAmount(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	whole := fmt.Sprintf("%d", amount/100)
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%s %s%s.%02d", currency, sign, grouped.String(), amount%100)
+}
+
+var htmlTemplate = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>GoFund contribution receipt</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; max-width: 560px; margin: 40px auto; color: #222; }
+h1 { font-size: 22px; margin-bottom: 4px; }
+p.issuer { color: #666; margin-top: 0; }
+table { width: 100%; border-collapse: collapse; margin-top: 24px; }
+th, td { text-align: left; padding: 8px 0; border-bottom: 1px solid #eee; }
+th { color: #666; font-weight: normal; width: 40%; }
+</style>
+</head>
+<body>
+<h1>Contribution Receipt</h1>
+<p class="issuer">GoFund</p>
+<table>
+{{range .}}<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// RenderHTML renders a receipt as a standalone HTML page
+func RenderHTML(receipt *dto.ContributionReceipt) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, Fields(receipt)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderPDF renders a receipt as a single-page A4 PDF
+func RenderPDF(receipt *dto.ContributionReceipt) []byte {
+	var content bytes.Buffer
+	content.WriteString("BT\n/F2 20 Tf\n56 770 Td\n(Contribution Receipt) Tj\n")
+	content.WriteString("/F1 11 Tf\n0 -20 Td\n(GoFund) Tj\n0 -16 Td\n")
+	for _, field := range Fields(receipt) {
+		fmt.Fprintf(&content, "0 -24 Td\n/F1 11 Tf\n(%s) Tj\n", pdfString(field.Label))
+		fmt.Fprintf(&content, "170 0 Td\n/F2 11 Tf\n(%s) Tj\n-170 0 Td\n", pdfString(field.Value))
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	// Cross-reference entries are exactly 20 bytes each
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return out.Bytes()
+}
+
+// pdfString escapes text for a PDF literal string. Characters outside Latin-1 cannot be
+// shown in the standard fonts and are replaced with '?'.
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r <= 0x7e:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository handles database operations for the audit log
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// CreateAuditLog appends an entry to the audit log
+func (r *AuditLogRepository) CreateAuditLog(ctx context.Context, entry *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// GetAuditLogsByGoalID returns a page of a goal's audit log, newest first, with the total
+// count
+func (r *AuditLogRepository) GetAuditLogsByGoalID(ctx context.Context, goalID uuid.UUID, limit, offset int) ([]models.AuditLog, int64, error) {
+	var entries []models.AuditLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AuditLog{}).Where("goal_id = ?", goalID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Limit(limit).Offset(offset).
+		Order("created_at DESC").
+		Find(&entries).Error
+
+	return entries, total, err
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GoalCollaboratorRepository handles database operations for the collaborators of co-owned goals
+type GoalCollaboratorRepository struct {
+	db *gorm.DB
+}
+
+// NewGoalCollaboratorRepository creates a new goal collaborator repository
+func NewGoalCollaboratorRepository(db *gorm.DB) *GoalCollaboratorRepository {
+	return &GoalCollaboratorRepository{db: db}
+}
+
+// AddCollaborator adds a collaborator to a goal. It reports whether the collaborator was
+// added, i.e. false when the user already collaborates on the goal.
+func (r *GoalCollaboratorRepository) AddCollaborator(ctx context.Context, collaborator *models.GoalCollaborator) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "goal_id"}, {Name: "user_id"}}, DoNothing: true}).
+		Create(collaborator)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RemoveCollaborator removes a user from a goal's collaborators. It reports whether the
+// user was one.
+func (r *GoalCollaboratorRepository) RemoveCollaborator(ctx context.Context, goalID, userID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("goal_id = ? AND user_id = ?", goalID, userID).
+		Delete(&models.GoalCollaborator{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetCollaborator retrieves a user's collaborator record on a goal
+func (r *GoalCollaboratorRepository) GetCollaborator(ctx context.Context, goalID, userID uuid.UUID) (*models.GoalCollaborator, error) {
+	var collaborator models.GoalCollaborator
+	err := r.db.WithContext(ctx).First(&collaborator, "goal_id = ? AND user_id = ?", goalID, userID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+// GetCollaboratorsByGoalID retrieves a goal's collaborators in the order they were added
+func (r *GoalCollaboratorRepository) GetCollaboratorsByGoalID(ctx context.Context, goalID uuid.UUID) ([]models.GoalCollaborator, error) {
+	var collaborators []models.GoalCollaborator
+	err := r.db.WithContext(ctx).Where("goal_id = ?", goalID).
+		Order("created_at ASC").
+		Find(&collaborators).Error
+	return collaborators, err
+}
+
+// CountCollaborators counts a goal's collaborators, not including its owner
+func (r *GoalCollaboratorRepository) CountCollaborators(ctx context.Context, goalID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.GoalCollaborator{}).
+		Where("goal_id = ?", goalID).
+		Count(&count).Error
+	return count, err
+}
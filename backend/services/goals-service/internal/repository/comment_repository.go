@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CommentRepository handles database operations for goal comments
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository creates a new comment repository
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// CreateComment creates a new comment
+func (r *CommentRepository) CreateComment(ctx context.Context, comment *models.Comment) error {
+	return r.db.WithContext(ctx).Create(comment).Error
+}
+
+// GetCommentByID retrieves a comment by ID, including deleted ones
+func (r *CommentRepository) GetCommentByID(ctx context.Context, id uuid.UUID) (*models.Comment, error) {
+	var comment models.Comment
+	err := r.db.WithContext(ctx).First(&comment, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetTopLevelComments returns a page of a goal's top-level comments, newest first, with
+// the total number of top-level comments
+func (r *CommentRepository) GetTopLevelComments(ctx context.Context, goalID uuid.UUID, limit, offset int) ([]models.Comment, int64, error) {
+	var comments []models.Comment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Comment{}).Where("goal_id = ? AND parent_id IS NULL", goalID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Limit(limit).Offset(offset).
+		Order("created_at DESC").
+		Find(&comments).Error
+
+	return comments, total, err
+}
+
+// GetReplies returns the replies to the given comments, oldest first
+func (r *CommentRepository) GetReplies(ctx context.Context, parentIDs []uuid.UUID) ([]models.Comment, error) {
+	var replies []models.Comment
+	if len(parentIDs) == 0 {
+		return replies, nil
+	}
+
+	err := r.db.WithContext(ctx).Where("parent_id IN ?", parentIDs).
+		Order("created_at ASC").
+		Find(&replies).Error
+	return replies, err
+}
+
+// MarkCommentDeleted flags a comment as deleted, keeping its row for thread structure. It
+// reports whether this call deleted it.
+func (r *CommentRepository) MarkCommentDeleted(ctx context.Context, id uuid.UUID) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&models.Comment{}).
+		Where("id = ? AND is_deleted = ?", id, false).
+		Updates(map[string]interface{}{
+			"is_deleted": true,
+			"deleted_at": &now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
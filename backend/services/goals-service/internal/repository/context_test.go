@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// TestCancelledContextAbortsQuery checks that repository calls run their queries with the
+// caller's context: a call already cancelled never reaches the database, and one blocked
+// on a row lock gives up when its deadline passes instead of waiting for the lock
+func TestCancelledContextAbortsQuery(t *testing.T) {
+	repo := NewRepository(testdb.Open(t))
+	goal := &models.Goal{OwnerID: uuid.New(), Title: "Cancellation", TargetAmount: 1_000, Currency: "NGN"}
+	if err := repo.Goal.CreateGoal(context.Background(), goal); err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := repo.Goal.GetGoalByIDSimple(cancelled, goal.ID); !errors.Is(err, context.Canceled) {
+		t.Errorf("read with a cancelled context: err = %v, want %v", err, context.Canceled)
+	}
+
+	// Hold the goal's row lock in another transaction until the test is done
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- repo.Transaction(context.Background(), func(tx *Repository) error {
+			if _, err := tx.Goal.GetGoalByIDForUpdate(context.Background(), goal.ID); err != nil {
+				close(locked)
+				return err
+			}
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	<-locked
+	defer func() {
+		close(release)
+		if err := <-done; err != nil {
+			t.Errorf("locking transaction: %v", err)
+		}
+	}()
+
+	ctx, cancelTimeout := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancelTimeout()
+	start := time.Now()
+	goal.Title = "Blocked"
+	err := repo.Goal.UpdateGoal(ctx, goal)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("blocked update returned after %s, want it abandoned at its deadline", elapsed)
+	}
+	if err == nil {
+		t.Fatal("the update went through while another transaction held the row lock")
+	}
+	if ctx.Err() == nil {
+		t.Errorf("update failed before its deadline: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// OrphanCheck describes an anti-join that finds child rows whose parent is missing
+type OrphanCheck struct {
+	Name        string
+	Table       string
+	ParentTable string
+	ForeignKey  string
+	// Nullable foreign keys are only orphaned when set; they are repaired by
+	// clearing the reference instead of archiving the row
+	Nullable bool
+}
+
+// OrphanChecks lists every orphan class covered by the data-quality report
+var OrphanChecks = []OrphanCheck{
+	{Name: "contributions_without_goal", Table: "contributions", ParentTable: "goals", ForeignKey: "goal_id"},
+	{Name: "contributions_without_milestone", Table: "contributions", ParentTable: "milestones", ForeignKey: "milestone_id", Nullable: true},
+	{Name: "withdrawals_without_goal", Table: "withdrawals", ParentTable: "goals", ForeignKey: "goal_id"},
+	{Name: "votes_without_proof", Table: "votes", ParentTable: "proofs", ForeignKey: "proof_id"},
+	{Name: "disbursements_without_contribution", Table: "refund_disbursements", ParentTable: "contributions", ForeignKey: "contribution_id"},
+}
+
+// DataQualityRepository runs data-quality queries across the goals schema
+type DataQualityRepository struct {
+	db *gorm.DB
+}
+
+// NewDataQualityRepository creates a new data-quality repository
+func NewDataQualityRepository(db *gorm.DB) *DataQualityRepository {
+	return &DataQualityRepository{db: db}
+}
+
+// orphanWhere builds the FROM/WHERE clause of the anti-join for a check
+func orphanWhere(check OrphanCheck) string {
+	clause := fmt.Sprintf("FROM %s c LEFT JOIN %s p ON p.id = c.%s WHERE p.id IS NULL",
+		check.Table, check.ParentTable, check.ForeignKey)
+	if check.Nullable {
+		clause += fmt.Sprintf(" AND c.%s IS NOT NULL", check.ForeignKey)
+	}
+	return clause
+}
+
+// CountOrphans returns the number of orphaned rows for a check
+func (r *DataQualityRepository) CountOrphans(ctx context.Context, check OrphanCheck) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Raw("SELECT COUNT(*) " + orphanWhere(check)).Scan(&count).Error
+	return count, err
+}
+
+// SampleOrphanIDs returns up to limit orphaned row IDs for a check
+func (r *DataQualityRepository) SampleOrphanIDs(ctx context.Context, check OrphanCheck, limit int) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Raw(fmt.Sprintf("SELECT c.id::text %s ORDER BY c.id LIMIT ?", orphanWhere(check)), limit).
+		Scan(&ids).Error
+	return ids, err
+}
+
+// RepairOrphans archives (or, for nullable references, detaches) every orphan of a
+// check inside a transaction and returns the number of rows repaired
+func (r *DataQualityRepository) RepairOrphans(ctx context.Context, check OrphanCheck) (int64, error) {
+	var repaired int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if check.Nullable {
+			result := tx.Exec(fmt.Sprintf(
+				"UPDATE %s SET %s = NULL WHERE id IN (SELECT c.id %s)",
+				check.Table, check.ForeignKey, orphanWhere(check)))
+			repaired = result.RowsAffected
+			return result.Error
+		}
+
+		archiveTable := check.Table + "_archive"
+		if err := tx.Exec(fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s (LIKE %s INCLUDING DEFAULTS, archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW())",
+			archiveTable, check.Table)).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(fmt.Sprintf(
+			"INSERT INTO %s SELECT c.*, NOW() %s",
+			archiveTable, orphanWhere(check))).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(fmt.Sprintf(
+			"DELETE FROM %s WHERE id IN (SELECT c.id %s)",
+			check.Table, orphanWhere(check)))
+		repaired = result.RowsAffected
+		return result.Error
+	})
+
+	return repaired, err
+}
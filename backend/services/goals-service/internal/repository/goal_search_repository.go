@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gofund/shared/models"
+)
+
+// Markers ts_headline puts around matched words. They are private-use characters no
+// goal text contains, so the service can escape the text and then turn them into markup.
+const (
+	SearchHighlightStart = "\uE000"
+	SearchHighlightStop  = "\uE001"
+)
+
+// searchBoost scales a goal's text relevance so open goals, well-funded goals and new
+// goals rank above dead ones: up to half again for each of being open and before its
+// deadline, being funded (capped at the target), and being recent (halving at 30 days)
+const searchBoost = `(1
+	+ 0.5 * (CASE WHEN status = @open AND (deadline IS NULL OR deadline > now()) THEN 1 ELSE 0 END)
+	+ 0.5 * LEAST(COALESCE(current_amount::float8 / NULLIF(target_amount, 0), 0), 1)
+	+ 0.5 / (1 + EXTRACT(EPOCH FROM now() - created_at) / 2592000))`
+
+// searchableGoals limits search to listed goals that have not been suspended
+const searchableGoals = `is_public = @public AND status <> @suspended`
+
+// Weights of a naive search match, in line with the A and B weights GoalSearchVector
+// gives title and description
+const (
+	naiveTitleWeight       = 1.0
+	naiveDescriptionWeight = 0.4
+)
+
+// GoalSearchHit is a goal matching a search, with its relevance score and, for full-text
+// matches, its title and a description snippet with matches between
+// SearchHighlightStart and SearchHighlightStop
+type GoalSearchHit struct {
+	models.Goal
+	Score          float64
+	TitleHighlight string
+	Snippet        string
+}
+
+// SearchGoalsFullText ranks listed goals matching tsquery, a to_tsquery expression, by
+// relevance to it. It uses idx_goals_search.
+func (r *GoalRepository) SearchGoalsFullText(ctx context.Context, tsquery string, limit, offset int) ([]GoalSearchHit, int64, error) {
+	args := map[string]interface{}{
+		"query":     tsquery,
+		"open":      models.GoalStatusOpen,
+		"public":    true,
+		"suspended": models.GoalStatusSuspended,
+		"limit":     limit,
+		"offset":    offset,
+		"title_options": fmt.Sprintf("HighlightAll=true, StartSel=%s, StopSel=%s",
+			SearchHighlightStart, SearchHighlightStop),
+		"snippet_options": fmt.Sprintf("MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" … \", StartSel=%s, StopSel=%s",
+			SearchHighlightStart, SearchHighlightStop),
+	}
+	match := fmt.Sprintf("%s AND (%s) @@ to_tsquery('english', @query)", searchableGoals, models.GoalSearchVector)
+
+	var total int64
+	if err := r.db.WithContext(ctx).Raw("SELECT COUNT(*) FROM goals WHERE "+match, args).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	// Headlines are costly, so they are made for the page of results only
+	var hits []GoalSearchHit
+	err := r.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT g.*,
+			ts_headline('english', g.title, to_tsquery('english', @query), @title_options) AS title_highlight,
+			ts_headline('english', g.description, to_tsquery('english', @query), @snippet_options) AS snippet
+		FROM (
+			SELECT *, ts_rank_cd(%s, to_tsquery('english', @query)) * %s AS score
+			FROM goals
+			WHERE %s
+			ORDER BY score DESC, created_at DESC, id
+			LIMIT @limit OFFSET @offset
+		) g
+		ORDER BY g.score DESC, g.created_at DESC, g.id`, models.GoalSearchVector, searchBoost, match), args).
+		Scan(&hits).Error
+	return hits, total, err
+}
+
+// SearchGoalsNaive ranks listed goals whose title or description contains text, case
+// insensitively, title matches first. It scans every goal; it is the fallback for
+// searches that are not valid full-text queries.
+func (r *GoalRepository) SearchGoalsNaive(ctx context.Context, text string, limit, offset int) ([]GoalSearchHit, int64, error) {
+	args := map[string]interface{}{
+		"pattern":   "%" + escapeLike(text) + "%",
+		"open":      models.GoalStatusOpen,
+		"public":    true,
+		"suspended": models.GoalStatusSuspended,
+		"limit":     limit,
+		"offset":    offset,
+	}
+	match := searchableGoals + ` AND (title ILIKE @pattern OR description ILIKE @pattern)`
+
+	var total int64
+	if err := r.db.WithContext(ctx).Raw("SELECT COUNT(*) FROM goals WHERE "+match, args).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	var hits []GoalSearchHit
+	err := r.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT *, (CASE WHEN title ILIKE @pattern THEN %g ELSE %g END) * %s AS score
+		FROM goals
+		WHERE %s
+		ORDER BY score DESC, created_at DESC, id
+		LIMIT @limit OFFSET @offset`, naiveTitleWeight, naiveDescriptionWeight, searchBoost, match), args).
+		Scan(&hits).Error
+	return hits, total, err
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
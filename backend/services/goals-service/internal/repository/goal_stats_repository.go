@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// The stats queries below filter contributions on goal_id and created_at, which
+// idx_contributions_goal_created covers. Days are UTC calendar days.
+
+// DailyContributionTotal is the confirmed contributions to a goal made on one day
+type DailyContributionTotal struct {
+	Day    time.Time
+	Count  int64
+	Amount int64
+}
+
+// ContributionAmountStats is the average and median confirmed contribution to a goal
+type ContributionAmountStats struct {
+	Average float64
+	Median  float64
+}
+
+// NewContributorsByDay is how many contributors made their first confirmed contribution
+// to a goal on each day since a given time, and how many had contributed before it
+type NewContributorsByDay struct {
+	Before int64
+	Days   map[time.Time]int64
+}
+
+// RefundStats sums a goal's refund disbursements by outcome
+type RefundStats struct {
+	Refunds        int64
+	RefundedAmount int64
+	PendingAmount  int64
+	FailedAmount   int64
+}
+
+// GetDailyContributionTotals returns the confirmed contributions to a goal per day since
+// the given time, oldest first. Days without contributions are left out.
+func (r *GoalRepository) GetDailyContributionTotals(ctx context.Context, goalID uuid.UUID, since time.Time) ([]DailyContributionTotal, error) {
+	var totals []DailyContributionTotal
+	err := r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Select("(created_at AT TIME ZONE 'UTC')::date AS day, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount").
+		Where("goal_id = ? AND created_at >= ? AND status = ?", goalID, since, models.ContributionStatusConfirmed).
+		Group("day").
+		Order("day ASC").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// GetContributionAmountStats returns the average and median of a goal's confirmed
+// contributions, both zero when there are none
+func (r *GoalRepository) GetContributionAmountStats(ctx context.Context, goalID uuid.UUID) (*ContributionAmountStats, error) {
+	var stats ContributionAmountStats
+	err := r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Select("COALESCE(AVG(amount), 0) AS average, COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY amount), 0) AS median").
+		Where("goal_id = ? AND status = ?", goalID, models.ContributionStatusConfirmed).
+		Scan(&stats).Error
+	return &stats, err
+}
+
+// GetNewContributorsByDay counts a goal's contributors by the day of their first confirmed
+// contribution, for days since the given time, and those who first contributed before it
+func (r *GoalRepository) GetNewContributorsByDay(ctx context.Context, goalID uuid.UUID, since time.Time) (*NewContributorsByDay, error) {
+	firsts := r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Select("user_id, MIN(created_at) AS first_at").
+		Where("goal_id = ? AND status = ?", goalID, models.ContributionStatusConfirmed).
+		Group("user_id")
+
+	// Contributors from before since share the NULL day
+	var rows []struct {
+		Day   *time.Time
+		Count int64
+	}
+	err := r.db.WithContext(ctx).Table("(?) AS f", firsts).
+		Select("CASE WHEN first_at >= ? THEN (first_at AT TIME ZONE 'UTC')::date END AS day, COUNT(*) AS count", since).
+		Group("day").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	growth := &NewContributorsByDay{Days: make(map[time.Time]int64, len(rows))}
+	for _, row := range rows {
+		if row.Day == nil {
+			growth.Before = row.Count
+			continue
+		}
+		growth.Days[row.Day.UTC()] = row.Count
+	}
+	return growth, nil
+}
+
+// CountContributionsByStatus returns how many of a goal's contributions are in each status
+func (r *GoalRepository) CountContributionsByStatus(ctx context.Context, goalID uuid.UUID) (map[models.ContributionStatus]int64, error) {
+	var rows []struct {
+		Status models.ContributionStatus
+		Count  int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Select("status, COUNT(*) AS count").
+		Where("goal_id = ?", goalID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.ContributionStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// GetRefundStats returns the number of refunds started on a goal and the amounts of
+// their disbursements that completed, are still being paid out, or failed
+func (r *GoalRepository) GetRefundStats(ctx context.Context, goalID uuid.UUID) (*RefundStats, error) {
+	var stats RefundStats
+	err := r.db.WithContext(ctx).Table("refunds r").
+		Select(`COUNT(DISTINCT r.id) AS refunds,
+			COALESCE(SUM(d.amount) FILTER (WHERE d.status = ?), 0) AS refunded_amount,
+			COALESCE(SUM(d.amount) FILTER (WHERE d.status IN ?), 0) AS pending_amount,
+			COALESCE(SUM(d.amount) FILTER (WHERE d.status = ?), 0) AS failed_amount`,
+			models.RefundStatusCompleted,
+			[]models.RefundStatus{models.RefundStatusPending, models.RefundStatusProcessing},
+			models.RefundStatusFailed).
+		Joins("LEFT JOIN refund_disbursements d ON d.refund_id = r.id").
+		Where("r.goal_id = ?", goalID).
+		Scan(&stats).Error
+	return &stats, err
+}
+
+// PlatformStats is the headline totals across every goal
+type PlatformStats struct {
+	// Raised sums confirmed contributions per currency
+	Raised       map[string]int64
+	GoalsFunded  int64
+	ActiveGoals  int64
+	Contributors int64
+}
+
+// GetPlatformStats returns the confirmed contributions raised per currency, the number
+// of goals whose confirmed contributions reach their target, the number of open goals
+// and the number of distinct contributors, across the platform
+func (r *GoalRepository) GetPlatformStats(ctx context.Context) (*PlatformStats, error) {
+	db := r.db.WithContext(ctx)
+	confirmed := models.ContributionStatusConfirmed
+
+	var raised []struct {
+		Currency string
+		Total    int64
+	}
+	if err := db.Model(&models.Contribution{}).
+		Select("currency, COALESCE(SUM(amount), 0) AS total").
+		Where("status = ?", confirmed).
+		Group("currency").
+		Scan(&raised).Error; err != nil {
+		return nil, err
+	}
+
+	stats := &PlatformStats{Raised: make(map[string]int64, len(raised))}
+	for _, row := range raised {
+		stats.Raised[row.Currency] = row.Total
+	}
+
+	if err := db.Model(&models.Contribution{}).
+		Where("status = ?", confirmed).
+		Distinct("user_id").
+		Count(&stats.Contributors).Error; err != nil {
+		return nil, err
+	}
+
+	goalTotals := db.Model(&models.Contribution{}).
+		Select("goal_id, SUM(amount) AS total").
+		Where("status = ?", confirmed).
+		Group("goal_id")
+	if err := db.Table("goals g").
+		Joins("JOIN (?) AS t ON t.goal_id = g.id", goalTotals).
+		Where("t.total >= g.target_amount").
+		Count(&stats.GoalsFunded).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&models.Goal{}).
+		Where("status = ?", models.GoalStatusOpen).
+		Count(&stats.ActiveGoals).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofund/goals-service/internal/testdb"
+)
+
+// TestMigrationCreatesCompositeIndexes checks the migrated schema has the composite
+// indexes the hot contribution, vote and withdrawal queries filter on, over the columns
+// in the order they filter
+func TestMigrationCreatesCompositeIndexes(t *testing.T) {
+	db := testdb.Open(t)
+
+	want := map[string]string{
+		"idx_contributions_goal_status":      "contributions (goal_id, status)",
+		"idx_contributions_milestone_status": "contributions (milestone_id, status)",
+		"idx_contributions_user_status":      "contributions (user_id, status)",
+		"idx_votes_proof_satisfied":          "votes (proof_id, is_satisfied)",
+		"idx_withdrawals_goal_status":        "withdrawals (goal_id, status)",
+	}
+
+	var indexes []struct {
+		Tablename string
+		Indexname string
+		Indexdef  string
+	}
+	err := db.Raw(`SELECT tablename, indexname, indexdef FROM pg_indexes WHERE schemaname = current_schema()`).
+		Scan(&indexes).Error
+	if err != nil {
+		t.Fatalf("failed to list indexes: %v", err)
+	}
+
+	found := make(map[string]string, len(indexes))
+	for _, index := range indexes {
+		// indexdef reads e.g. CREATE INDEX name ON schema.table USING btree (a, b)
+		columns := index.Indexdef[strings.LastIndex(index.Indexdef, "("):]
+		found[index.Indexname] = index.Tablename + " " + columns
+	}
+	for name, definition := range want {
+		if found[name] != definition {
+			t.Errorf("index %s = %q, want %q", name, found[name], definition)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboxRepository handles database operations for outbox events
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// outboxDueCondition matches unsent outbox events due to be published at a given time
+const outboxDueCondition = "sent_at IS NULL AND next_attempt_at <= ?"
+
+// AddEvent records an event to be published. Call it on a repository built over the
+// transaction making the change the event announces.
+func (r *OutboxRepository) AddEvent(ctx context.Context, event *models.OutboxEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// GetDueEvents retrieves up to limit unsent events due to be published at now, oldest first
+func (r *OutboxRepository) GetDueEvents(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.WithContext(ctx).
+		Where(outboxDueCondition, now).
+		Order("created_at").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// ClaimEvent leases a due event until leaseUntil by pushing its next attempt out, so
+// concurrent dispatchers don't publish it twice. It reports whether the claim won.
+func (r *OutboxRepository) ClaimEvent(ctx context.Context, id uuid.UUID, now, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Where("id = ?", id).
+		Where(outboxDueCondition, now).
+		Update("next_attempt_at", leaseUntil)
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkEventSent records that an event was published
+func (r *OutboxRepository) MarkEventSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"sent_at":    sentAt,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": "",
+		}).Error
+}
+
+// MarkEventFailed records a failed attempt to publish an event and when to try it again
+func (r *OutboxRepository) MarkEventFailed(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, reason string) error {
+	return r.db.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Where("id = ? AND sent_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"next_attempt_at": nextAttemptAt,
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      reason,
+		}).Error
+}
+
+// CountUnsentEvents counts the events waiting to be published
+func (r *OutboxRepository) CountUnsentEvents(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Where("sent_at IS NULL").
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteSentEvents deletes events published before the given time
+func (r *OutboxRepository) DeleteSentEvents(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("sent_at < ?", before).
+		Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProofMediaRepository handles database operations for uploaded proof media
+type ProofMediaRepository struct {
+	db *gorm.DB
+}
+
+// NewProofMediaRepository creates a new proof media repository
+func NewProofMediaRepository(db *gorm.DB) *ProofMediaRepository {
+	return &ProofMediaRepository{db: db}
+}
+
+// CreateProofMedia records an uploaded file
+func (r *ProofMediaRepository) CreateProofMedia(ctx context.Context, media *models.ProofMedia) error {
+	return r.db.WithContext(ctx).Create(media).Error
+}
+
+// GetProofMediaByURLs retrieves the uploaded files served at any of urls
+func (r *ProofMediaRepository) GetProofMediaByURLs(ctx context.Context, urls []string) ([]models.ProofMedia, error) {
+	var media []models.ProofMedia
+	if len(urls) == 0 {
+		return media, nil
+	}
+	err := r.db.WithContext(ctx).Where("url IN ?", urls).Find(&media).Error
+	return media, err
+}
+
+// GetProofMediaByProofID retrieves the files a proof cites
+func (r *ProofMediaRepository) GetProofMediaByProofID(ctx context.Context, proofID uuid.UUID) ([]models.ProofMedia, error) {
+	var media []models.ProofMedia
+	err := r.db.WithContext(ctx).Where("proof_id = ?", proofID).Find(&media).Error
+	return media, err
+}
+
+// AttachToProof marks a user's uploads as cited by a proof. Uploads already cited by
+// another proof are left alone; it returns how many were attached.
+func (r *ProofMediaRepository) AttachToProof(ctx context.Context, ids []uuid.UUID, uploadedBy, proofID uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Model(&models.ProofMedia{}).
+		Where("id IN ? AND uploaded_by = ? AND proof_id IS NULL", ids, uploadedBy).
+		Update("proof_id", proofID)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteProofMediaByProofID deletes the records of the files a proof cites
+func (r *ProofMediaRepository) DeleteProofMediaByProofID(ctx context.Context, proofID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.ProofMedia{}, "proof_id = ?", proofID).Error
+}
+
+// DeleteProofMedia deletes the record of an uploaded file
+func (r *ProofMediaRepository) DeleteProofMedia(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.ProofMedia{}, "id = ?", id).Error
+}
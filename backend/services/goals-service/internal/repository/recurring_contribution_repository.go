@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecurringContributionRepository handles database operations for recurring contributions
+type RecurringContributionRepository struct {
+	db *gorm.DB
+}
+
+// NewRecurringContributionRepository creates a new recurring contribution repository
+func NewRecurringContributionRepository(db *gorm.DB) *RecurringContributionRepository {
+	return &RecurringContributionRepository{db: db}
+}
+
+// Create creates a new recurring contribution
+func (r *RecurringContributionRepository) Create(ctx context.Context, rc *models.RecurringContribution) error {
+	return r.db.WithContext(ctx).Create(rc).Error
+}
+
+// GetByID retrieves a recurring contribution by ID
+func (r *RecurringContributionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RecurringContribution, error) {
+	var rc models.RecurringContribution
+	if err := r.db.WithContext(ctx).First(&rc, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &rc, nil
+}
+
+// ListByUser retrieves a user's recurring contributions, newest first
+func (r *RecurringContributionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.RecurringContribution, error) {
+	var rcs []models.RecurringContribution
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&rcs).Error
+	return rcs, err
+}
+
+// Update saves the given columns of a recurring contribution
+func (r *RecurringContributionRepository) Update(ctx context.Context, rc *models.RecurringContribution, columns ...string) error {
+	return r.db.WithContext(ctx).Model(rc).Select(columns).Updates(rc).Error
+}
+
+// dueCondition matches active recurring contributions whose charge, or retry of a failed
+// charge, is due at the given time
+const dueCondition = "status = ? AND ((retry_at IS NULL AND next_charge_date <= ?) OR retry_at <= ?)"
+
+// GetDue retrieves up to limit active recurring contributions due to be charged at now
+func (r *RecurringContributionRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]models.RecurringContribution, error) {
+	var rcs []models.RecurringContribution
+	err := r.db.WithContext(ctx).
+		Where(dueCondition, models.RecurringContributionActive, now, now).
+		Order("next_charge_date").
+		Limit(limit).
+		Find(&rcs).Error
+	return rcs, err
+}
+
+// Claim leases a due recurring contribution until leaseUntil by pushing its retry time
+// out, so concurrent schedulers don't charge it twice. It reports whether the claim won.
+func (r *RecurringContributionRepository) Claim(ctx context.Context, id uuid.UUID, now, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.RecurringContribution{}).
+		Where("id = ?", id).
+		Where(dueCondition, models.RecurringContributionActive, now, now).
+		Update("retry_at", leaseUntil)
+	return result.RowsAffected > 0, result.Error
+}
+
+// SaveChargeOutcome records the scheduling state after a charge attempt. Recurring
+// contributions paused or cancelled while the charge ran are left as they are.
+func (r *RecurringContributionRepository) SaveChargeOutcome(ctx context.Context, rc *models.RecurringContribution) error {
+	return r.db.WithContext(ctx).Model(&models.RecurringContribution{}).
+		Where("id = ? AND status = ?", rc.ID, models.RecurringContributionActive).
+		Updates(map[string]interface{}{
+			"status":              rc.Status,
+			"next_charge_date":    rc.NextChargeDate,
+			"failed_attempts":     rc.FailedAttempts,
+			"retry_at":            rc.RetryAt,
+			"last_failure_reason": rc.LastFailureReason,
+			"last_charged_at":     rc.LastChargedAt,
+		}).Error
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RefundRepository handles database operations for refunds
+type RefundRepository struct {
+	db *gorm.DB
+}
+
+// NewRefundRepository creates a new refund repository
+func NewRefundRepository(db *gorm.DB) *RefundRepository {
+	return &RefundRepository{db: db}
+}
+
+// CreateRefund creates a new refund
+func (r *RefundRepository) CreateRefund(ctx context.Context, refund *models.Refund) error {
+	return r.db.WithContext(ctx).Create(refund).Error
+}
+
+// GetRefundByID retrieves a refund with its disbursements
+func (r *RefundRepository) GetRefundByID(ctx context.Context, id uuid.UUID) (*models.Refund, error) {
+	var refund models.Refund
+	err := r.db.WithContext(ctx).Preload("Disbursements").First(&refund, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// GetRefundByIDForUpdate retrieves a refund with its disbursements and locks the refund's
+// row until the transaction ends; call it on a repository built over a transaction
+func (r *RefundRepository) GetRefundByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Refund, error) {
+	var refund models.Refund
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Disbursements").First(&refund, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// GetRefundsByGoalID retrieves all refunds for a goal with their disbursements
+func (r *RefundRepository) GetRefundsByGoalID(ctx context.Context, goalID uuid.UUID) ([]models.Refund, error) {
+	var refunds []models.Refund
+	err := r.db.WithContext(ctx).Preload("Disbursements").Where("goal_id = ?", goalID).Find(&refunds).Error
+	return refunds, err
+}
+
+// UpdateRefund applies column updates to a refund
+func (r *RefundRepository) UpdateRefund(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&models.Refund{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// RefundDisbursementRepository handles database operations for refund disbursements
+type RefundDisbursementRepository struct {
+	db *gorm.DB
+}
+
+// NewRefundDisbursementRepository creates a new refund disbursement repository
+func NewRefundDisbursementRepository(db *gorm.DB) *RefundDisbursementRepository {
+	return &RefundDisbursementRepository{db: db}
+}
+
+// CreateDisbursement creates a new refund disbursement
+func (r *RefundDisbursementRepository) CreateDisbursement(ctx context.Context, disbursement *models.RefundDisbursement) error {
+	return r.db.WithContext(ctx).Create(disbursement).Error
+}
+
+// GetDisbursementByID retrieves a refund disbursement with its refund
+func (r *RefundDisbursementRepository) GetDisbursementByID(ctx context.Context, id uuid.UUID) (*models.RefundDisbursement, error) {
+	var disbursement models.RefundDisbursement
+	err := r.db.WithContext(ctx).Preload("Refund").First(&disbursement, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &disbursement, nil
+}
+
+// GetDisbursementByIDForUpdate retrieves a refund disbursement with its refund and locks
+// the disbursement's row until the transaction ends; call it on a repository built over a
+// transaction
+func (r *RefundDisbursementRepository) GetDisbursementByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.RefundDisbursement, error) {
+	var disbursement models.RefundDisbursement
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Refund").First(&disbursement, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &disbursement, nil
+}
+
+// UpdateDisbursement applies column updates to a refund disbursement
+func (r *RefundDisbursementRepository) UpdateDisbursement(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&models.RefundDisbursement{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// GetRefundedAmounts returns the amount refunded per contribution. Failed disbursements
+// are ignored; pending and processing ones count as they will pay out.
+func (r *RefundDisbursementRepository) GetRefundedAmounts(ctx context.Context, contributionIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		ContributionID uuid.UUID
+		Total          int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.RefundDisbursement{}).
+		Select("contribution_id, COALESCE(SUM(amount), 0) AS total").
+		Where("contribution_id IN ? AND status <> ?", contributionIDs, models.RefundStatusFailed).
+		Group("contribution_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	refunded := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		refunded[row.ContributionID] = row.Total
+	}
+	return refunded, nil
+}
+
+// GetLatestDisbursementByContributionID retrieves the most recent refund disbursement for
+// a contribution
+func (r *RefundDisbursementRepository) GetLatestDisbursementByContributionID(ctx context.Context, contributionID uuid.UUID) (*models.RefundDisbursement, error) {
+	var disbursement models.RefundDisbursement
+	err := r.db.WithContext(ctx).
+		Where("contribution_id = ?", contributionID).
+		Order("created_at DESC").
+		First(&disbursement).Error
+	if err != nil {
+		return nil, err
+	}
+	return &disbursement, nil
+}
+
+// GetLatestStatuses returns the status of each contribution's most recent refund
+// disbursement. Contributions never refunded are absent.
+func (r *RefundDisbursementRepository) GetLatestStatuses(ctx context.Context, contributionIDs []uuid.UUID) (map[uuid.UUID]models.RefundStatus, error) {
+	var rows []struct {
+		ContributionID uuid.UUID
+		Status         models.RefundStatus
+	}
+	err := r.db.WithContext(ctx).Model(&models.RefundDisbursement{}).
+		Select("DISTINCT ON (contribution_id) contribution_id, status").
+		Where("contribution_id IN ?", contributionIDs).
+		Order("contribution_id, created_at DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[uuid.UUID]models.RefundStatus, len(rows))
+	for _, row := range rows {
+		statuses[row.ContributionID] = row.Status
+	}
+	return statuses, nil
+}
+
+// MarkContributionRefunded moves a confirmed contribution to REFUNDED once its completed
+// refund disbursements cover its whole amount
+func (r *RefundDisbursementRepository) MarkContributionRefunded(ctx context.Context, contributionID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Where("id = ? AND status = ?", contributionID, models.ContributionStatusConfirmed).
+		Where("amount <= (SELECT COALESCE(SUM(amount), 0) FROM refund_disbursements WHERE contribution_id = ? AND status = ?)",
+			contributionID, models.RefundStatusCompleted).
+		Update("status", models.ContributionStatusRefunded).Error
+}
+
+// RefundRequestRepository handles database operations for contributors' refund requests
+type RefundRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewRefundRequestRepository creates a new refund request repository
+func NewRefundRequestRepository(db *gorm.DB) *RefundRequestRepository {
+	return &RefundRequestRepository{db: db}
+}
+
+// CreateRequest creates a new refund request
+func (r *RefundRequestRepository) CreateRequest(ctx context.Context, request *models.RefundRequest) error {
+	return r.db.WithContext(ctx).Create(request).Error
+}
+
+// GetRequestByID retrieves a refund request
+func (r *RefundRequestRepository) GetRequestByID(ctx context.Context, id uuid.UUID) (*models.RefundRequest, error) {
+	var request models.RefundRequest
+	if err := r.db.WithContext(ctx).First(&request, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetRequestsByGoalID retrieves a goal's refund requests, newest first, optionally only
+// those with status
+func (r *RefundRequestRepository) GetRequestsByGoalID(ctx context.Context, goalID uuid.UUID, status models.RefundRequestStatus) ([]models.RefundRequest, error) {
+	var requests []models.RefundRequest
+	query := r.db.WithContext(ctx).Where("goal_id = ?", goalID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Find(&requests).Error
+	return requests, err
+}
+
+// HasPendingRequest reports whether a contribution has a refund request awaiting review
+func (r *RefundRequestRepository) HasPendingRequest(ctx context.Context, contributionID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.RefundRequest{}).
+		Where("contribution_id = ? AND status = ?", contributionID, models.RefundRequestStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateRequest applies column updates to a refund request
+func (r *RefundRequestRepository) UpdateRequest(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&models.RefundRequest{}).Where("id = ?", id).Updates(updates).Error
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TestReplicaRouting checks which reads reach a lagging replica: a goal written to the
+// primary only is invisible to plain reads, and found by Primary(), locking reads and
+// reads inside a transaction
+func TestReplicaRouting(t *testing.T) {
+	primary, replica := testdb.OpenReplicated(t)
+	repo := NewRepository(primary)
+	ctx := context.Background()
+
+	goal := &models.Goal{OwnerID: uuid.New(), Title: "Routing", TargetAmount: 1_000, Currency: "NGN"}
+	if err := repo.Goal.CreateGoal(ctx, goal); err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+
+	if _, err := repo.Goal.GetGoalByIDSimple(ctx, goal.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("plain read of a goal the replica lacks: err = %v, want %v", err, gorm.ErrRecordNotFound)
+	}
+	if _, err := repo.Primary().Goal.GetGoalByIDSimple(ctx, goal.ID); err != nil {
+		t.Errorf("Primary() read: %v", err)
+	}
+	if _, err := repo.Goal.GetGoalByIDForUpdate(ctx, goal.ID); err != nil {
+		t.Errorf("locking read: %v", err)
+	}
+	err := repo.Transaction(ctx, func(tx *Repository) error {
+		_, err := tx.Goal.GetGoalByIDSimple(ctx, goal.ID)
+		return err
+	})
+	if err != nil {
+		t.Errorf("read in a transaction: %v", err)
+	}
+
+	// Once replicated, plain reads are served by the replica
+	if err := replica.Create(goal).Error; err != nil {
+		t.Fatalf("failed to replicate goal: %v", err)
+	}
+	if _, err := repo.Goal.GetGoalByIDSimple(ctx, goal.ID); err != nil {
+		t.Errorf("plain read of a replicated goal: %v", err)
+	}
+}
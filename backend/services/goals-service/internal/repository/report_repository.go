@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Weights of the moderation queue's priority score. Each open report adds
+// reportPriorityWeight, and an automatic flag autoFlagPriorityWeight on top; the money at
+// risk adds atRiskPriorityWeight per tenfold of the goal's current amount in major units;
+// and the latest report adds up to recencyPriorityWeight, halving every
+// recencyHalfLife.
+const (
+	reportPriorityWeight   = 10
+	autoFlagPriorityWeight = 15
+	atRiskPriorityWeight   = 10
+	recencyPriorityWeight  = 30
+	recencyHalfLife        = 24 * time.Hour
+)
+
+// GoalReportRepository handles database operations for goal reports
+type GoalReportRepository struct {
+	db *gorm.DB
+}
+
+// NewGoalReportRepository creates a new goal report repository
+func NewGoalReportRepository(db *gorm.DB) *GoalReportRepository {
+	return &GoalReportRepository{db: db}
+}
+
+// ModerationQueueFilter narrows the moderation queue. Zero values do not filter.
+type ModerationQueueFilter struct {
+	Category   models.GoalReportCategory // goals with an open report in this category
+	Status     models.GoalStatus
+	MinReports int64
+}
+
+// ModerationQueueItem is a goal in the moderation queue with its open reports aggregated
+type ModerationQueueItem struct {
+	GoalID         uuid.UUID
+	Title          string
+	OwnerID        uuid.UUID
+	Status         models.GoalStatus
+	CurrentAmount  int64
+	Currency       string
+	ReportCount    int64 // open reports, automatic flags included
+	AutoFlags      int64
+	LastReportedAt time.Time
+	PriorityScore  float64
+}
+
+// ErrDuplicateReport is returned when a user already has an open report on the goal
+var ErrDuplicateReport = errors.New("goal already reported by this user")
+
+// isDuplicateReport reports whether err is a violation of the goal reports' unique open
+// reporter index
+func isDuplicateReport(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "duplicate key") && strings.Contains(err.Error(), "idx_goal_reports_open_reporter")
+}
+
+// CreateReport creates a goal report. It returns ErrDuplicateReport when the reporter
+// already has an open report on the goal.
+func (r *GoalReportRepository) CreateReport(ctx context.Context, report *models.GoalReport) error {
+	err := r.db.WithContext(ctx).Create(report).Error
+	if isDuplicateReport(err) {
+		return ErrDuplicateReport
+	}
+	return err
+}
+
+// DismissOpenReports dismisses every open report on a goal on an admin's behalf. It
+// returns how many reports it dismissed.
+func (r *GoalReportRepository) DismissOpenReports(ctx context.Context, goalID, adminID uuid.UUID, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.GoalReport{}).
+		Where("goal_id = ? AND status = ?", goalID, models.GoalReportStatusOpen).
+		Updates(map[string]interface{}{
+			"status":       models.GoalReportStatusDismissed,
+			"dismissed_by": adminID,
+			"dismissed_at": now,
+			"updated_at":   now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// GetModerationQueue returns a page of the goals with open reports, highest priority
+// first, with the total count. Open reports are counted per goal in one aggregate over
+// idx_goal_reports_status_created, and the score is worked out as of now.
+func (r *GoalReportRepository) GetModerationQueue(ctx context.Context, filter ModerationQueueFilter, now time.Time, limit, offset int) ([]ModerationQueueItem, int64, error) {
+	reports := r.db.WithContext(ctx).Model(&models.GoalReport{}).
+		Select(`goal_id,
+			COUNT(*) AS report_count,
+			COUNT(*) FILTER (WHERE source = ?) AS auto_flags,
+			COUNT(*) FILTER (WHERE category = ?) AS category_reports,
+			MAX(created_at) AS last_reported_at`, models.GoalReportSourceAuto, filter.Category).
+		Where("status = ?", models.GoalReportStatusOpen).
+		Group("goal_id")
+
+	query := r.db.WithContext(ctx).Table("(?) AS reports", reports).
+		Joins("JOIN goals ON goals.id = reports.goal_id").
+		Where("reports.report_count >= ?", filter.MinReports)
+	if filter.Category != "" {
+		query = query.Where("reports.category_reports > 0")
+	}
+	if filter.Status != "" {
+		query = query.Where("goals.status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []ModerationQueueItem
+	err := query.Select(`goals.id AS goal_id, goals.title, goals.owner_id, goals.status,
+			goals.current_amount, goals.currency,
+			reports.report_count, reports.auto_flags, reports.last_reported_at,
+			(? * reports.report_count + ? * reports.auto_flags
+				+ ? * LOG(1 + GREATEST(goals.current_amount, 0) / 100.0)
+				+ ? * POWER(0.5, EXTRACT(EPOCH FROM (?::timestamptz - reports.last_reported_at)) / ?)
+			)::float8 AS priority_score`,
+		reportPriorityWeight, autoFlagPriorityWeight, atRiskPriorityWeight,
+		recencyPriorityWeight, now, recencyHalfLife.Seconds()).
+		Order("priority_score DESC, reports.last_reported_at DESC, goals.id").
+		Limit(limit).
+		Offset(offset).
+		Scan(&items).Error
+	return items, total, err
+}
@@ -1,9 +1,16 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/gofund/shared/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
 )
 
 // GoalRepository handles database operations for goals
@@ -16,15 +23,31 @@ func NewGoalRepository(db *gorm.DB) *GoalRepository {
 	return &GoalRepository{db: db}
 }
 
-// CreateGoal creates a new goal
-func (r *GoalRepository) CreateGoal(goal *models.Goal) error {
-	return r.db.Create(goal).Error
+// ErrDuplicateSlug is returned when a goal's slug is already taken by another goal
+var ErrDuplicateSlug = errors.New("goal slug already taken")
+
+// ErrOrphanedContribution is returned when a contribution's goal no longer exists, which
+// only hard-deleted legacy goals leave behind (see /admin/data-quality/orphans)
+var ErrOrphanedContribution = errors.New("contribution's goal no longer exists")
+
+// isDuplicateSlug reports whether err is a violation of the goals' unique slug index
+func isDuplicateSlug(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "duplicate key") && strings.Contains(err.Error(), "slug")
+}
+
+// CreateGoal creates a new goal. It returns ErrDuplicateSlug when the goal's slug is taken.
+func (r *GoalRepository) CreateGoal(ctx context.Context, goal *models.Goal) error {
+	err := r.db.WithContext(ctx).Create(goal).Error
+	if isDuplicateSlug(err) {
+		return ErrDuplicateSlug
+	}
+	return err
 }
 
 // GetGoalByID retrieves a goal by ID
-func (r *GoalRepository) GetGoalByID(id uuid.UUID) (*models.Goal, error) {
+func (r *GoalRepository) GetGoalByID(ctx context.Context, id uuid.UUID) (*models.Goal, error) {
 	var goal models.Goal
-	err := r.db.Preload("Milestones").
+	err := r.db.WithContext(ctx).Preload("Milestones").
 		Preload("Contributions").
 		Preload("Withdrawals").
 		Preload("Proofs").
@@ -35,10 +58,88 @@ func (r *GoalRepository) GetGoalByID(id uuid.UUID) (*models.Goal, error) {
 	return &goal, nil
 }
 
+// GetGoalBySlug retrieves a goal by its slug
+func (r *GoalRepository) GetGoalBySlug(ctx context.Context, slug string) (*models.Goal, error) {
+	var goal models.Goal
+	err := r.db.WithContext(ctx).Preload("Milestones").
+		Preload("Contributions").
+		Preload("Withdrawals").
+		Preload("Proofs").
+		First(&goal, "slug = ?", slug).Error
+	if err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// GetGoalsWithoutSlug retrieves up to limit goals created before slugs existed
+func (r *GoalRepository) GetGoalsWithoutSlug(ctx context.Context, limit int) ([]models.Goal, error) {
+	var goals []models.Goal
+	err := r.db.WithContext(ctx).Where("slug IS NULL").
+		Order("created_at").
+		Limit(limit).
+		Find(&goals).Error
+	return goals, err
+}
+
+// SetSlugIfMissing gives a goal without a slug the one passed. It reports whether the goal
+// took it, and returns ErrDuplicateSlug when another goal already has it.
+func (r *GoalRepository) SetSlugIfMissing(ctx context.Context, id uuid.UUID, slug string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Goal{}).
+		Where("id = ? AND slug IS NULL", id).
+		Update("slug", slug)
+	if isDuplicateSlug(result.Error) {
+		return false, ErrDuplicateSlug
+	}
+	return result.RowsAffected > 0, result.Error
+}
+
+// BackfillVisibility marks goals hidden before visibility levels existed as private. It
+// returns how many goals it updated.
+func (r *GoalRepository) BackfillVisibility(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Goal{}).
+		Where("is_public = ? AND visibility = ?", false, models.GoalVisibilityPublic).
+		Update("visibility", models.GoalVisibilityPrivate)
+	return result.RowsAffected, result.Error
+}
+
+// GetLatestMediaURL returns the first media URL of the goal's most recent update or proof
+// that carries media, or "" when none does
+func (r *GoalRepository) GetLatestMediaURL(ctx context.Context, goalID uuid.UUID) (string, error) {
+	var latest struct {
+		MediaURLs []string `gorm:"serializer:json"`
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT media_urls FROM (
+			SELECT media_urls, created_at AS posted_at FROM goal_updates
+			WHERE goal_id = ? AND jsonb_array_length(COALESCE(media_urls, '[]'::jsonb)) > 0
+			UNION ALL
+			SELECT media_urls, submitted_at AS posted_at FROM proofs
+			WHERE goal_id = ? AND jsonb_array_length(COALESCE(media_urls, '[]'::jsonb)) > 0
+		) media
+		ORDER BY posted_at DESC
+		LIMIT 1`, goalID, goalID).Scan(&latest).Error
+	if err != nil || len(latest.MediaURLs) == 0 {
+		return "", err
+	}
+	return latest.MediaURLs[0], nil
+}
+
 // GetGoalByIDSimple retrieves a goal without preloading relationships
-func (r *GoalRepository) GetGoalByIDSimple(id uuid.UUID) (*models.Goal, error) {
+func (r *GoalRepository) GetGoalByIDSimple(ctx context.Context, id uuid.UUID) (*models.Goal, error) {
 	var goal models.Goal
-	err := r.db.First(&goal, "id = ?", id).Error
+	err := r.db.WithContext(ctx).First(&goal, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// GetGoalByIDForUpdate retrieves a goal without relationships and locks its row until the
+// transaction ends; call it on a repository built over a transaction
+func (r *GoalRepository) GetGoalByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Goal, error) {
+	var goal models.Goal
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&goal, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -46,20 +147,30 @@ func (r *GoalRepository) GetGoalByIDSimple(id uuid.UUID) (*models.Goal, error) {
 }
 
 // GetGoalsByOwnerID retrieves all goals for a specific owner
-func (r *GoalRepository) GetGoalsByOwnerID(ownerID uuid.UUID) ([]models.Goal, error) {
+func (r *GoalRepository) GetGoalsByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]models.Goal, error) {
 	var goals []models.Goal
-	err := r.db.Where("owner_id = ?", ownerID).
+	err := r.db.WithContext(ctx).Where("owner_id = ?", ownerID).
 		Order("created_at DESC").
 		Find(&goals).Error
 	return goals, err
 }
 
+// CountPublicGoalsByOwnerID counts the public goals a user owns, leaving out suspended
+// goals
+func (r *GoalRepository) CountPublicGoalsByOwnerID(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Goal{}).
+		Where("owner_id = ? AND is_public = ? AND status <> ?", ownerID, true, models.GoalStatusSuspended).
+		Count(&count).Error
+	return count, err
+}
+
 // GetGoals retrieves goals with filters
-func (r *GoalRepository) GetGoals(status *models.GoalStatus, limit, offset int) ([]models.Goal, int64, error) {
+func (r *GoalRepository) GetGoals(ctx context.Context, status *models.GoalStatus, limit, offset int) ([]models.Goal, int64, error) {
 	var goals []models.Goal
 	var total int64
 
-	query := r.db.Model(&models.Goal{})
+	query := r.db.WithContext(ctx).Model(&models.Goal{})
 
 	if status != nil {
 		query = query.Where("status = ?", *status)
@@ -78,19 +189,67 @@ func (r *GoalRepository) GetGoals(status *models.GoalStatus, limit, offset int)
 	return goals, total, err
 }
 
-// GetPublicGoals retrieves only public goals with pagination
-func (r *GoalRepository) GetPublicGoals(limit, offset int) ([]models.Goal, int64, error) {
+// Public goal list orderings, selected with ?sort=
+const (
+	GoalSortNewest      = "newest"
+	GoalSortMostFunded  = "most_funded"
+	GoalSortMostPopular = "most_popular"
+	GoalSortEndingSoon  = "ending_soon"
+)
+
+// goalSortOrders maps each ordering to its ORDER BY; every leading column is indexed
+var goalSortOrders = map[string]string{
+	GoalSortNewest:      "created_at DESC",
+	GoalSortMostFunded:  "current_amount DESC, created_at DESC",
+	GoalSortMostPopular: "contributor_count DESC, created_at DESC",
+	GoalSortEndingSoon:  "deadline ASC NULLS LAST, created_at DESC",
+}
+
+// IsValidGoalSort reports whether sort is one of the GoalSort orderings
+func IsValidGoalSort(sort string) bool {
+	_, ok := goalSortOrders[sort]
+	return ok
+}
+
+// GetPublicGoals retrieves only public goals with pagination, in the given GoalSort order
+func (r *GoalRepository) GetPublicGoals(ctx context.Context, sort string, limit, offset int) ([]models.Goal, int64, error) {
 	var goals []models.Goal
 	var total int64
 
-	query := r.db.Model(&models.Goal{}).Where("is_public = ? AND status = ?", true, models.GoalStatusOpen)
+	query := r.db.WithContext(ctx).Model(&models.Goal{}).Where("is_public = ? AND status = ?", true, models.GoalStatusOpen)
 
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
+	order, ok := goalSortOrders[sort]
+	if !ok {
+		order = goalSortOrders[GoalSortNewest]
+	}
+
 	// Get paginated results
+	err := query.Limit(limit).Offset(offset).
+		Order(order).
+		Find(&goals).Error
+
+	return goals, total, err
+}
+
+// GetAllGoals retrieves goals of any visibility and status, optionally filtered by status
+func (r *GoalRepository) GetAllGoals(ctx context.Context, status models.GoalStatus, limit, offset int) ([]models.Goal, int64, error) {
+	var goals []models.Goal
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Goal{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	err := query.Limit(limit).Offset(offset).
 		Order("created_at DESC").
 		Find(&goals).Error
@@ -98,50 +257,257 @@ func (r *GoalRepository) GetPublicGoals(limit, offset int) ([]models.Goal, int64
 	return goals, total, err
 }
 
-// UpdateGoal updates a goal
-func (r *GoalRepository) UpdateGoal(goal *models.Goal) error {
-	return r.db.Save(goal).Error
+// UpdateGoal updates a goal. The funding totals are left out: they are maintained by
+// RecountTotals, and a goal loaded before a concurrent confirmation would overwrite them.
+func (r *GoalRepository) UpdateGoal(ctx context.Context, goal *models.Goal) error {
+	return r.db.WithContext(ctx).Omit("current_amount", "contributor_count").Save(goal).Error
+}
+
+// GetOpenGoalsPastDeadline retrieves open goals whose deadline is before now
+func (r *GoalRepository) GetOpenGoalsPastDeadline(ctx context.Context, now time.Time) ([]models.Goal, error) {
+	var goals []models.Goal
+	err := r.db.WithContext(ctx).Where("status = ? AND deadline IS NOT NULL AND deadline < ?", models.GoalStatusOpen, now).
+		Find(&goals).Error
+	return goals, err
+}
+
+// GetOpenGoalsDeadlineBefore retrieves open goals whose deadline falls before until and
+// whose watchers have not yet been reminded of it
+func (r *GoalRepository) GetOpenGoalsDeadlineBefore(ctx context.Context, until time.Time) ([]models.Goal, error) {
+	var goals []models.Goal
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND deadline IS NOT NULL AND deadline < ? AND deadline_reminder_sent_at IS NULL", models.GoalStatusOpen, until).
+		Find(&goals).Error
+	return goals, err
+}
+
+// MarkDeadlineReminderSent records that a goal's deadline reminder went out. It reports
+// whether this call marked it, so concurrent replicas send the reminder only once.
+func (r *GoalRepository) MarkDeadlineReminderSent(ctx context.Context, id uuid.UUID, sentAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Goal{}).
+		Where("id = ? AND deadline_reminder_sent_at IS NULL", id).
+		Update("deadline_reminder_sent_at", sentAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CloseGoalIfOpen closes a goal that is still open. It reports whether this call closed
+// it, so concurrent closers (an owner, another replica's job) act on it only once.
+func (r *GoalRepository) CloseGoalIfOpen(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Goal{}).
+		Where("id = ? AND status = ?", id, models.GoalStatusOpen).
+		Update("status", models.GoalStatusClosed)
+	return result.RowsAffected > 0, result.Error
 }
 
 // DeleteGoal deletes a goal
-func (r *GoalRepository) DeleteGoal(id uuid.UUID) error {
-	return r.db.Delete(&models.Goal{}, "id = ?", id).Error
+func (r *GoalRepository) DeleteGoal(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Goal{}, "id = ?", id).Error
+}
+
+// CountConfirmedContributions returns the number of confirmed contributions for a goal
+func (r *GoalRepository) CountConfirmedContributions(ctx context.Context, goalID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Where("goal_id = ? AND status = ?", goalID, models.ContributionStatusConfirmed).
+		Count(&count).Error
+	return count, err
+}
+
+// CountPendingContributions returns the number of a goal's contribution intents still
+// awaiting payment: pending or expired, as a late payment confirms either
+func (r *GoalRepository) CountPendingContributions(ctx context.Context, goalID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Where("goal_id = ? AND status IN ?", goalID, []models.ContributionStatus{models.ContributionStatusPending, models.ContributionStatusExpired}).
+		Count(&count).Error
+	return count, err
+}
+
+// CountContributions returns the number of contributions (in any status) for a goal
+func (r *GoalRepository) CountContributions(ctx context.Context, goalID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Where("goal_id = ?", goalID).
+		Count(&count).Error
+	return count, err
+}
+
+// CountWithdrawals returns the number of withdrawals (in any status) for a goal
+func (r *GoalRepository) CountWithdrawals(ctx context.Context, goalID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Withdrawal{}).
+		Where("goal_id = ?", goalID).
+		Count(&count).Error
+	return count, err
 }
 
 // GetTotalConfirmedContributions calculates total confirmed contributions for a goal
-func (r *GoalRepository) GetTotalConfirmedContributions(goalID uuid.UUID) (int64, error) {
+func (r *GoalRepository) GetTotalConfirmedContributions(ctx context.Context, goalID uuid.UUID) (int64, error) {
 	var total int64
-	err := r.db.Model(&models.Contribution{}).
+	err := r.db.WithContext(ctx).Model(&models.Contribution{}).
 		Where("goal_id = ? AND status = ?", goalID, models.ContributionStatusConfirmed).
 		Select("COALESCE(SUM(amount), 0)").
 		Scan(&total).Error
 	return total, err
 }
 
+// GetTotalAbsorbedFees sums the platform fees taken out of a goal's confirmed
+// contributions, i.e. those made while it absorbed the fee
+func (r *GoalRepository) GetTotalAbsorbedFees(ctx context.Context, goalID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Where("goal_id = ? AND status = ? AND fee_mode = ?", goalID, models.ContributionStatusConfirmed, models.FeeModeAbsorb).
+		Select("COALESCE(SUM(fee_amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
 // GetTotalCompletedWithdrawals calculates total completed withdrawals for a goal
-func (r *GoalRepository) GetTotalCompletedWithdrawals(goalID uuid.UUID) (int64, error) {
+func (r *GoalRepository) GetTotalCompletedWithdrawals(ctx context.Context, goalID uuid.UUID) (int64, error) {
 	var total int64
-	err := r.db.Model(&models.Withdrawal{}).
+	err := r.db.WithContext(ctx).Model(&models.Withdrawal{}).
 		Where("goal_id = ? AND status = ?", goalID, models.WithdrawalStatusCompleted).
 		Select("COALESCE(SUM(amount), 0)").
 		Scan(&total).Error
 	return total, err
 }
 
+// GetTotalCommittedWithdrawals sums the withdrawals for a goal that are completed, still
+// being paid out or awaiting approval, i.e. everything that is no longer available to withdraw
+func (r *GoalRepository) GetTotalCommittedWithdrawals(ctx context.Context, goalID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&models.Withdrawal{}).
+		Where("goal_id = ? AND status IN ?", goalID, []models.WithdrawalStatus{
+			models.WithdrawalStatusAwaitingApproval,
+			models.WithdrawalStatusPending,
+			models.WithdrawalStatusProcessing,
+			models.WithdrawalStatusCompleted,
+		}).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// GetTotalRefunded sums the refund disbursements of a goal's confirmed contributions, i.e.
+// the money refunded or being refunded out of them. Failed disbursements are ignored, as
+// are contributions refunded in full, which no longer count as confirmed.
+func (r *GoalRepository) GetTotalRefunded(ctx context.Context, goalID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&models.RefundDisbursement{}).
+		Where("contribution_id IN (?) AND status <> ?",
+			r.db.WithContext(ctx).Model(&models.Contribution{}).Select("id").
+				Where("goal_id = ? AND status = ?", goalID, models.ContributionStatusConfirmed),
+			models.RefundStatusFailed).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// GetCompletedRefundTotals returns the amount refunded so far per contribution of a goal,
+// counting completed disbursements only
+func (r *GoalRepository) GetCompletedRefundTotals(ctx context.Context, goalID uuid.UUID) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		ContributionID uuid.UUID
+		Total          int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.RefundDisbursement{}).
+		Select("contribution_id, COALESCE(SUM(amount), 0) AS total").
+		Where("contribution_id IN (?) AND status = ?",
+			r.db.WithContext(ctx).Model(&models.Contribution{}).Select("id").Where("goal_id = ?", goalID),
+			models.RefundStatusCompleted).
+		Group("contribution_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		totals[row.ContributionID] = row.Total
+	}
+	return totals, nil
+}
+
+// HasActiveRefund reports whether a goal has a refund that is pending or processing
+func (r *GoalRepository) HasActiveRefund(ctx context.Context, goalID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Refund{}).
+		Where("goal_id = ? AND status IN ?", goalID, []models.RefundStatus{
+			models.RefundStatusPending,
+			models.RefundStatusProcessing,
+		}).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // GetContributorCount returns the number of unique contributors for a goal
-func (r *GoalRepository) GetContributorCount(goalID uuid.UUID) (int64, error) {
+func (r *GoalRepository) GetContributorCount(ctx context.Context, goalID uuid.UUID) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.Contribution{}).
+	err := r.db.WithContext(ctx).Model(&models.Contribution{}).
 		Where("goal_id = ? AND status = ?", goalID, models.ContributionStatusConfirmed).
 		Distinct("user_id").
 		Count(&count).Error
 	return count, err
 }
 
+// goalTotalsSource is the net confirmed contributions of goals.id: each confirmed
+// contribution less its completed refund disbursements
+const goalTotalsSource = `FROM contributions c
+	LEFT JOIN (
+		SELECT contribution_id, SUM(amount) AS refunded FROM refund_disbursements
+		WHERE status = ? GROUP BY contribution_id
+	) d ON d.contribution_id = c.id
+	WHERE c.goal_id = goals.id AND c.status = ?`
+
+// goalTotalsColumns recomputes Goal.CurrentAmount and Goal.ContributorCount from contributions
+func goalTotalsColumns() map[string]interface{} {
+	completed, confirmed := models.RefundStatusCompleted, models.ContributionStatusConfirmed
+	return map[string]interface{}{
+		"current_amount":    gorm.Expr("(SELECT COALESCE(SUM(c.amount - COALESCE(d.refunded, 0)), 0) "+goalTotalsSource+")", completed, confirmed),
+		"contributor_count": gorm.Expr("(SELECT COUNT(DISTINCT c.user_id) "+goalTotalsSource+" AND c.amount > COALESCE(d.refunded, 0))", completed, confirmed),
+	}
+}
+
+// RecountTotals recomputes a goal's CurrentAmount and ContributorCount. Call it on a
+// repository built over the transaction that changed the goal's contributions or refunds.
+func (r *GoalRepository) RecountTotals(ctx context.Context, goalID uuid.UUID) error {
+	// UpdateColumns leaves updated_at alone: funding is not an edit of the goal
+	return r.db.WithContext(ctx).Model(&models.Goal{}).Where("id = ?", goalID).UpdateColumns(goalTotalsColumns()).Error
+}
+
+// ReconcileTotals recomputes CurrentAmount and ContributorCount for every goal whose stored
+// values have drifted from its contributions, returning how many goals were corrected
+func (r *GoalRepository) ReconcileTotals(ctx context.Context) (int64, error) {
+	columns := goalTotalsColumns()
+	result := r.db.WithContext(ctx).Model(&models.Goal{}).
+		Where("current_amount <> ? OR contributor_count <> ?", columns["current_amount"], columns["contributor_count"]).
+		UpdateColumns(columns)
+	return result.RowsAffected, result.Error
+}
+
+// GetContributorIDs returns the distinct users with confirmed contributions to a goal
+func (r *GoalRepository) GetContributorIDs(ctx context.Context, goalID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Where("goal_id = ? AND status = ?", goalID, models.ContributionStatusConfirmed).
+		Distinct().
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// GetWatcherIDs returns the users watching a goal
+func (r *GoalRepository) GetWatcherIDs(ctx context.Context, goalID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&models.GoalWatch{}).
+		Where("goal_id = ?", goalID).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
 // IsUserContributor checks if a user has contributed to a goal
-func (r *GoalRepository) IsUserContributor(goalID, userID uuid.UUID) (bool, error) {
+func (r *GoalRepository) IsUserContributor(ctx context.Context, goalID, userID uuid.UUID) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.Contribution{}).
+	err := r.db.WithContext(ctx).Model(&models.Contribution{}).
 		Where("goal_id = ? AND user_id = ? AND status = ?", goalID, userID, models.ContributionStatusConfirmed).
 		Count(&count).Error
 	return count > 0, err
@@ -158,14 +524,14 @@ func NewMilestoneRepository(db *gorm.DB) *MilestoneRepository {
 }
 
 // CreateMilestone creates a new milestone
-func (r *MilestoneRepository) CreateMilestone(milestone *models.Milestone) error {
-	return r.db.Create(milestone).Error
+func (r *MilestoneRepository) CreateMilestone(ctx context.Context, milestone *models.Milestone) error {
+	return r.db.WithContext(ctx).Create(milestone).Error
 }
 
 // GetMilestoneByID retrieves a milestone by ID
-func (r *MilestoneRepository) GetMilestoneByID(id uuid.UUID) (*models.Milestone, error) {
+func (r *MilestoneRepository) GetMilestoneByID(ctx context.Context, id uuid.UUID) (*models.Milestone, error) {
 	var milestone models.Milestone
-	err := r.db.First(&milestone, "id = ?", id).Error
+	err := r.db.WithContext(ctx).First(&milestone, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -173,44 +539,178 @@ func (r *MilestoneRepository) GetMilestoneByID(id uuid.UUID) (*models.Milestone,
 }
 
 // GetMilestonesByGoalID retrieves all milestones for a goal
-func (r *MilestoneRepository) GetMilestonesByGoalID(goalID uuid.UUID) ([]models.Milestone, error) {
+func (r *MilestoneRepository) GetMilestonesByGoalID(ctx context.Context, goalID uuid.UUID) ([]models.Milestone, error) {
 	var milestones []models.Milestone
-	err := r.db.Where("goal_id = ?", goalID).
+	err := r.db.WithContext(ctx).Where("goal_id = ?", goalID).
 		Order("order_index ASC").
 		Find(&milestones).Error
 	return milestones, err
 }
 
 // UpdateMilestone updates a milestone
-func (r *MilestoneRepository) UpdateMilestone(milestone *models.Milestone) error {
-	return r.db.Save(milestone).Error
+func (r *MilestoneRepository) UpdateMilestone(ctx context.Context, milestone *models.Milestone) error {
+	return r.db.WithContext(ctx).Save(milestone).Error
 }
 
 // DeleteMilestone deletes a milestone
-func (r *MilestoneRepository) DeleteMilestone(id uuid.UUID) error {
-	return r.db.Delete(&models.Milestone{}, "id = ?", id).Error
+func (r *MilestoneRepository) DeleteMilestone(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Milestone{}, "id = ?", id).Error
 }
 
 // GetTotalConfirmedContributionsByMilestone calculates total confirmed contributions for a milestone
-func (r *MilestoneRepository) GetTotalConfirmedContributionsByMilestone(milestoneID uuid.UUID) (int64, error) {
+func (r *MilestoneRepository) GetTotalConfirmedContributionsByMilestone(ctx context.Context, milestoneID uuid.UUID) (int64, error) {
 	var total int64
-	err := r.db.Model(&models.Contribution{}).
+	err := r.db.WithContext(ctx).Model(&models.Contribution{}).
 		Where("milestone_id = ? AND status = ?", milestoneID, models.ContributionStatusConfirmed).
 		Select("COALESCE(SUM(amount), 0)").
 		Scan(&total).Error
 	return total, err
 }
 
+// GetTotalRefundedByMilestone sums the refund disbursements of the contributions to a
+// milestone, as GoalRepository.GetTotalRefunded does for a goal
+func (r *MilestoneRepository) GetTotalRefundedByMilestone(ctx context.Context, milestoneID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&models.RefundDisbursement{}).
+		Where("contribution_id IN (?) AND status <> ?",
+			r.db.WithContext(ctx).Model(&models.Contribution{}).Select("id").
+				Where("milestone_id = ? AND status = ?", milestoneID, models.ContributionStatusConfirmed),
+			models.RefundStatusFailed).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// GetTotalMilestoneTargets sums the target amounts of a goal's milestones
+func (r *MilestoneRepository) GetTotalMilestoneTargets(ctx context.Context, goalID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&models.Milestone{}).
+		Where("goal_id = ?", goalID).
+		Select("COALESCE(SUM(target_amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
 // GetNextOrderIndex gets the next available order index for a goal's milestones
-func (r *MilestoneRepository) GetNextOrderIndex(goalID uuid.UUID) (int, error) {
+func (r *MilestoneRepository) GetNextOrderIndex(ctx context.Context, goalID uuid.UUID) (int, error) {
 	var maxOrder int
-	err := r.db.Model(&models.Milestone{}).
+	err := r.db.WithContext(ctx).Model(&models.Milestone{}).
 		Where("goal_id = ?", goalID).
 		Select("COALESCE(MAX(order_index), 0)").
 		Scan(&maxOrder).Error
 	return maxOrder + 1, err
 }
 
+// GetActiveMilestone retrieves a goal's active milestone
+func (r *MilestoneRepository) GetActiveMilestone(ctx context.Context, goalID uuid.UUID) (*models.Milestone, error) {
+	var milestone models.Milestone
+	err := r.db.WithContext(ctx).
+		Where("goal_id = ? AND status = ?", goalID, models.MilestoneStatusActive).
+		First(&milestone).Error
+	if err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+// ActivateNextMilestone makes the lowest-ordered milestone of a goal that is not completed
+// its only active milestone and returns it, or nil when every milestone is completed
+func (r *MilestoneRepository) ActivateNextMilestone(ctx context.Context, goalID uuid.UUID) (*models.Milestone, error) {
+	var next models.Milestone
+	err := r.db.WithContext(ctx).
+		Where("goal_id = ? AND status <> ?", goalID, models.MilestoneStatusCompleted).
+		Order("order_index ASC, created_at ASC").
+		First(&next).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	found := err == nil
+
+	demote := r.db.WithContext(ctx).Model(&models.Milestone{}).
+		Where("goal_id = ? AND status = ?", goalID, models.MilestoneStatusActive)
+	if found {
+		demote = demote.Where("id <> ?", next.ID)
+	}
+	if err := demote.Update("status", models.MilestoneStatusPending).Error; err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	if next.Status != models.MilestoneStatusActive {
+		next.Status = models.MilestoneStatusActive
+		err := r.db.WithContext(ctx).Model(&next).Update("status", next.Status).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &next, nil
+}
+
+// GetLaterRecurringInstances returns the not yet completed instances of a recurring
+// milestone that were created from it, directly or through later instances, in order
+func (r *MilestoneRepository) GetLaterRecurringInstances(ctx context.Context, milestone *models.Milestone) ([]models.Milestone, error) {
+	var milestones []models.Milestone
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE chain AS (
+			SELECT * FROM milestones WHERE parent_milestone_id = ?
+			UNION ALL
+			SELECT m.* FROM milestones m JOIN chain c ON m.parent_milestone_id = c.id
+		)
+		SELECT * FROM chain
+		WHERE status <> ?
+		ORDER BY order_index ASC`, milestone.ID, models.MilestoneStatusCompleted).Scan(&milestones).Error
+	return milestones, err
+}
+
+// GetRecurrenceCycle returns a milestone's place in its recurrence chain, counting the
+// milestone that started the chain as cycle 1
+func (r *MilestoneRepository) GetRecurrenceCycle(ctx context.Context, milestoneID uuid.UUID) (int, error) {
+	var cycle int
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE chain AS (
+			SELECT id, parent_milestone_id FROM milestones WHERE id = ?
+			UNION ALL
+			SELECT m.id, m.parent_milestone_id FROM milestones m JOIN chain c ON m.id = c.parent_milestone_id
+		)
+		SELECT COUNT(*) FROM chain`, milestoneID).Scan(&cycle).Error
+	return cycle, err
+}
+
+// ReparentChildren links the instances created from a milestone to that milestone's own
+// parent, so deleting it does not break the recurrence chain
+func (r *MilestoneRepository) ReparentChildren(ctx context.Context, milestone *models.Milestone) error {
+	return r.db.WithContext(ctx).Model(&models.Milestone{}).
+		Where("parent_milestone_id = ?", milestone.ID).
+		Update("parent_milestone_id", milestone.ParentMilestoneID).Error
+}
+
+// CountWithdrawalsByMilestone counts the withdrawals made against a milestone, whatever
+// their status
+func (r *MilestoneRepository) CountWithdrawalsByMilestone(ctx context.Context, milestoneID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Withdrawal{}).
+		Where("milestone_id = ?", milestoneID).
+		Count(&count).Error
+	return count, err
+}
+
+// DetachMilestone moves a milestone's contributions and proofs to its goal as a whole,
+// returning how many contributions were moved
+func (r *MilestoneRepository) DetachMilestone(ctx context.Context, milestoneID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Where("milestone_id = ?", milestoneID).
+		Update("milestone_id", nil)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	err := r.db.WithContext(ctx).Model(&models.Proof{}).
+		Where("milestone_id = ?", milestoneID).
+		Update("milestone_id", nil).Error
+	return result.RowsAffected, err
+}
+
 // ContributionRepository handles database operations for contributions
 type ContributionRepository struct {
 	db *gorm.DB
@@ -222,51 +722,243 @@ func NewContributionRepository(db *gorm.DB) *ContributionRepository {
 }
 
 // CreateContribution creates a new contribution
-func (r *ContributionRepository) CreateContribution(contribution *models.Contribution) error {
-	return r.db.Create(contribution).Error
+func (r *ContributionRepository) CreateContribution(ctx context.Context, contribution *models.Contribution) error {
+	return r.db.WithContext(ctx).Create(contribution).Error
 }
 
 // GetContributionByID retrieves a contribution by ID
-func (r *ContributionRepository) GetContributionByID(id uuid.UUID) (*models.Contribution, error) {
+func (r *ContributionRepository) GetContributionByID(ctx context.Context, id uuid.UUID) (*models.Contribution, error) {
 	var contribution models.Contribution
-	err := r.db.First(&contribution, "id = ?", id).Error
+	err := r.db.WithContext(ctx).First(&contribution, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &contribution, nil
 }
 
+// ConfirmContribution marks a contribution confirmed and, for close-on-target goals, closes the
+// goal in the same transaction once confirmed contributions reach the target. onConfirmed is
+// called in the transaction with the goal, the confirmed contribution and whether this
+// confirmation closed the goal, so events announcing it commit with it. It returns the goal and
+// whether this confirmation closed it. Confirming a contribution that is neither pending nor
+// an expired intent is a no-op that does not call onConfirmed; confirming one whose goal is
+// gone returns ErrOrphanedContribution.
+func (r *ContributionRepository) ConfirmContribution(ctx context.Context, contributionID, paymentID uuid.UUID, onConfirmed func(tx *Repository, goal *models.Goal, contribution *models.Contribution, closed bool) error) (*models.Goal, bool, error) {
+	var goal models.Goal
+	closed := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the contribution so a redelivered confirmation waits for this one and
+		// then sees the status it left
+		var contribution models.Contribution
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&contribution, "id = ?", contributionID).Error; err != nil {
+			return err
+		}
+
+		// Only a pending or expired intent is confirmed; a contribution already
+		// confirmed, refunded or failed is left as it is, so a redelivered payment
+		// event cannot undo a refund
+		if contribution.Status != models.ContributionStatusPending && !contribution.IsExpired(time.Now()) {
+			return nil
+		}
+
+		// Lock the goal so concurrent confirmations see each other's totals
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&goal, "id = ?", contribution.GoalID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrphanedContribution
+			}
+			return err
+		}
+
+		confirmed := &contribution
+		if contribution.IsExpired(time.Now()) {
+			// The intent expired before the payment landed; keep the money by recording
+			// a fresh confirmed contribution and leave the expired intent as history.
+			// A redelivered event finds the fresh contribution and does nothing.
+			var existing int64
+			if err := tx.Model(&models.Contribution{}).Where("payment_id = ?", paymentID).Count(&existing).Error; err != nil {
+				return err
+			}
+			if existing > 0 {
+				return nil
+			}
+
+			confirmed = &models.Contribution{
+				GoalID:                  contribution.GoalID,
+				MilestoneID:             contribution.MilestoneID,
+				UserID:                  contribution.UserID,
+				PaymentID:               &paymentID,
+				Amount:                  contribution.Amount,
+				Currency:                contribution.Currency,
+				Status:                  models.ContributionStatusConfirmed,
+				IsAnonymous:             contribution.IsAnonymous,
+				RecurringContributionID: contribution.RecurringContributionID,
+				FeeMode:                 contribution.FeeMode,
+				FeeAmount:               contribution.FeeAmount,
+				NetAmount:               contribution.NetAmount,
+			}
+			if err := tx.Create(confirmed).Error; err != nil {
+				return err
+			}
+		} else {
+			result := tx.Model(&contribution).
+				Where("status = ?", models.ContributionStatusPending).
+				Updates(map[string]interface{}{
+					"payment_id": paymentID,
+					"status":     models.ContributionStatusConfirmed,
+					"expires_at": nil,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return nil
+			}
+			contribution.PaymentID = &paymentID
+			contribution.Status = models.ContributionStatusConfirmed
+			contribution.ExpiresAt = nil
+		}
+
+		if err := NewGoalRepository(tx).RecountTotals(ctx, goal.ID); err != nil {
+			return err
+		}
+
+		var err error
+		if closed, err = closeGoalOnTarget(tx, &goal); err != nil {
+			return err
+		}
+		return onConfirmed(NewRepository(tx), &goal, confirmed, closed)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &goal, closed, nil
+}
+
+// closeGoalOnTarget closes an open close-on-target goal, locked by tx, once its confirmed
+// contributions reach the target, and reports whether it did
+func closeGoalOnTarget(tx *gorm.DB, goal *models.Goal) (bool, error) {
+	if !goal.CloseOnTarget || goal.Status != models.GoalStatusOpen {
+		return false, nil
+	}
+
+	var total int64
+	if err := tx.Model(&models.Contribution{}).
+		Where("goal_id = ? AND status = ?", goal.ID, models.ContributionStatusConfirmed).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error; err != nil {
+		return false, err
+	}
+
+	if total < goal.TargetAmount {
+		return false, nil
+	}
+
+	if err := tx.Model(goal).Update("status", models.GoalStatusClosed).Error; err != nil {
+		return false, err
+	}
+	goal.Status = models.GoalStatusClosed
+	return true, nil
+}
+
+// ExpirePendingContributions marks pending contributions whose intent has expired as EXPIRED
+func (r *ContributionRepository) ExpirePendingContributions(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at < ?", models.ContributionStatusPending, now).
+		Update("status", models.ContributionStatusExpired)
+	return result.RowsAffected, result.Error
+}
+
 // GetContributionByPaymentID retrieves a contribution by payment ID
-func (r *ContributionRepository) GetContributionByPaymentID(paymentID uuid.UUID) (*models.Contribution, error) {
+func (r *ContributionRepository) GetContributionByPaymentID(ctx context.Context, paymentID uuid.UUID) (*models.Contribution, error) {
 	var contribution models.Contribution
-	err := r.db.First(&contribution, "payment_id = ?", paymentID).Error
+	err := r.db.WithContext(ctx).First(&contribution, "payment_id = ?", paymentID).Error
 	if err != nil {
 		return nil, err
 	}
 	return &contribution, nil
 }
 
-// GetContributionsByGoalID retrieves all contributions for a goal
-func (r *ContributionRepository) GetContributionsByGoalID(goalID uuid.UUID) ([]models.Contribution, error) {
+// SetAuthorizationByPaymentID records the reusable card a contribution was paid with
+func (r *ContributionRepository) SetAuthorizationByPaymentID(ctx context.Context, paymentID uuid.UUID, code, email string) error {
+	return r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Where("payment_id = ?", paymentID).
+		Updates(map[string]interface{}{"authorization_code": code, "authorization_email": email}).Error
+}
+
+// GetContributionsByGoalID retrieves all contributions for a goal, excluding expired intents
+func (r *ContributionRepository) GetContributionsByGoalID(ctx context.Context, goalID uuid.UUID) ([]models.Contribution, error) {
 	var contributions []models.Contribution
-	err := r.db.Where("goal_id = ?", goalID).
+	err := r.db.WithContext(ctx).Where("goal_id = ? AND status <> ?", goalID, models.ContributionStatusExpired).
 		Order("created_at DESC").
 		Find(&contributions).Error
 	return contributions, err
 }
 
+// GetConfirmedContributionsByGoalID retrieves all of a goal's confirmed contributions, oldest first
+func (r *ContributionRepository) GetConfirmedContributionsByGoalID(ctx context.Context, goalID uuid.UUID) ([]models.Contribution, error) {
+	var contributions []models.Contribution
+	err := r.db.WithContext(ctx).Where("goal_id = ? AND status = ?", goalID, models.ContributionStatusConfirmed).
+		Order("created_at").
+		Find(&contributions).Error
+	return contributions, err
+}
+
+// GetConfirmedContributionsPage retrieves a page of a goal's confirmed contributions, newest first
+func (r *ContributionRepository) GetConfirmedContributionsPage(ctx context.Context, goalID uuid.UUID, limit, offset int) ([]models.Contribution, int64, error) {
+	var contributions []models.Contribution
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Contribution{}).Where("goal_id = ? AND status = ?", goalID, models.ContributionStatusConfirmed)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&contributions).Error
+	return contributions, total, err
+}
+
 // GetContributionsByUserID retrieves all contributions by a user
-func (r *ContributionRepository) GetContributionsByUserID(userID uuid.UUID) ([]models.Contribution, error) {
+func (r *ContributionRepository) GetContributionsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Contribution, error) {
 	var contributions []models.Contribution
-	err := r.db.Where("user_id = ?", userID).
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Find(&contributions).Error
 	return contributions, err
 }
 
+// EachContributionByGoalID calls fn with each of a goal's contributions, oldest first,
+// reading them through a cursor so large goals are never loaded into memory at once. It
+// stops at the first error fn returns.
+func (r *ContributionRepository) EachContributionByGoalID(ctx context.Context, goalID uuid.UUID, fn func(*models.Contribution) error) error {
+	rows, err := r.db.WithContext(ctx).Model(&models.Contribution{}).
+		Where("goal_id = ?", goalID).
+		Order("created_at ASC").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var contribution models.Contribution
+		if err := r.db.ScanRows(rows, &contribution); err != nil {
+			return err
+		}
+		if err := fn(&contribution); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 // UpdateContribution updates a contribution
-func (r *ContributionRepository) UpdateContribution(contribution *models.Contribution) error {
-	return r.db.Save(contribution).Error
+func (r *ContributionRepository) UpdateContribution(ctx context.Context, contribution *models.Contribution) error {
+	return r.db.WithContext(ctx).Save(contribution).Error
 }
 
 // WithdrawalRepository handles database operations for withdrawals
@@ -279,15 +971,31 @@ func NewWithdrawalRepository(db *gorm.DB) *WithdrawalRepository {
 	return &WithdrawalRepository{db: db}
 }
 
-// CreateWithdrawal creates a new withdrawal
-func (r *WithdrawalRepository) CreateWithdrawal(withdrawal *models.Withdrawal) error {
-	return r.db.Create(withdrawal).Error
+// CreateWithdrawal creates a new withdrawal with the first event of its status history
+func (r *WithdrawalRepository) CreateWithdrawal(ctx context.Context, withdrawal *models.Withdrawal) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(withdrawal).Error; err != nil {
+			return err
+		}
+		return tx.Create(newWithdrawalStatusEvent(withdrawal, "")).Error
+	})
 }
 
 // GetWithdrawalByID retrieves a withdrawal by ID
-func (r *WithdrawalRepository) GetWithdrawalByID(id uuid.UUID) (*models.Withdrawal, error) {
+func (r *WithdrawalRepository) GetWithdrawalByID(ctx context.Context, id uuid.UUID) (*models.Withdrawal, error) {
+	var withdrawal models.Withdrawal
+	err := r.db.WithContext(ctx).First(&withdrawal, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &withdrawal, nil
+}
+
+// GetWithdrawalByIDForUpdate retrieves a withdrawal and locks its row until the transaction
+// ends; call it on a repository built over a transaction
+func (r *WithdrawalRepository) GetWithdrawalByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Withdrawal, error) {
 	var withdrawal models.Withdrawal
-	err := r.db.First(&withdrawal, "id = ?", id).Error
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&withdrawal, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -295,17 +1003,140 @@ func (r *WithdrawalRepository) GetWithdrawalByID(id uuid.UUID) (*models.Withdraw
 }
 
 // GetWithdrawalsByGoalID retrieves all withdrawals for a goal
-func (r *WithdrawalRepository) GetWithdrawalsByGoalID(goalID uuid.UUID) ([]models.Withdrawal, error) {
+func (r *WithdrawalRepository) GetWithdrawalsByGoalID(ctx context.Context, goalID uuid.UUID) ([]models.Withdrawal, error) {
 	var withdrawals []models.Withdrawal
-	err := r.db.Where("goal_id = ?", goalID).
+	err := r.db.WithContext(ctx).Where("goal_id = ?", goalID).
 		Order("requested_at DESC").
 		Find(&withdrawals).Error
 	return withdrawals, err
 }
 
-// UpdateWithdrawal updates a withdrawal
-func (r *WithdrawalRepository) UpdateWithdrawal(withdrawal *models.Withdrawal) error {
-	return r.db.Save(withdrawal).Error
+// GetWithdrawalsPageByGoalID returns a page of a goal's withdrawals, newest first, with the
+// total count
+func (r *WithdrawalRepository) GetWithdrawalsPageByGoalID(ctx context.Context, goalID uuid.UUID, limit, offset int) ([]models.Withdrawal, int64, error) {
+	var withdrawals []models.Withdrawal
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Withdrawal{}).Where("goal_id = ?", goalID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("requested_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&withdrawals).Error
+	return withdrawals, total, err
+}
+
+// EachWithdrawalByGoalID calls fn with each of a goal's withdrawals, oldest first, reading
+// them through a cursor as ContributionRepository.EachContributionByGoalID does
+func (r *WithdrawalRepository) EachWithdrawalByGoalID(ctx context.Context, goalID uuid.UUID, fn func(*models.Withdrawal) error) error {
+	rows, err := r.db.WithContext(ctx).Model(&models.Withdrawal{}).
+		Where("goal_id = ?", goalID).
+		Order("requested_at ASC").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var withdrawal models.Withdrawal
+		if err := r.db.ScanRows(rows, &withdrawal); err != nil {
+			return err
+		}
+		if err := fn(&withdrawal); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetTotalCommittedWithdrawalsByMilestone sums the withdrawals against a milestone that are
+// no longer available to withdraw, as GoalRepository.GetTotalCommittedWithdrawals does for a goal
+func (r *WithdrawalRepository) GetTotalCommittedWithdrawalsByMilestone(ctx context.Context, milestoneID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&models.Withdrawal{}).
+		Where("milestone_id = ? AND status IN ?", milestoneID, []models.WithdrawalStatus{
+			models.WithdrawalStatusAwaitingApproval,
+			models.WithdrawalStatusPending,
+			models.WithdrawalStatusProcessing,
+			models.WithdrawalStatusCompleted,
+		}).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// UpdateWithdrawal updates a withdrawal, adding to its status history when the status
+// changed. The stored row is locked while it is compared, so concurrent updates record
+// their transitions in order.
+func (r *WithdrawalRepository) UpdateWithdrawal(ctx context.Context, withdrawal *models.Withdrawal) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var stored []models.WithdrawalStatus
+		err := tx.Model(&models.Withdrawal{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", withdrawal.ID).
+			Pluck("status", &stored).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Save(withdrawal).Error; err != nil {
+			return err
+		}
+		if len(stored) == 1 && stored[0] == withdrawal.Status {
+			return nil
+		}
+
+		var from models.WithdrawalStatus
+		if len(stored) == 1 {
+			from = stored[0]
+		}
+		return tx.Create(newWithdrawalStatusEvent(withdrawal, from)).Error
+	})
+}
+
+// GetStatusEvents retrieves a withdrawal's status history, oldest first
+func (r *WithdrawalRepository) GetStatusEvents(ctx context.Context, withdrawalID uuid.UUID) ([]models.WithdrawalStatusEvent, error) {
+	var statusEvents []models.WithdrawalStatusEvent
+	err := r.db.WithContext(ctx).Where("withdrawal_id = ?", withdrawalID).
+		Order("created_at ASC").
+		Find(&statusEvents).Error
+	return statusEvents, err
+}
+
+// newWithdrawalStatusEvent records a withdrawal entering its current status from from
+func newWithdrawalStatusEvent(withdrawal *models.Withdrawal, from models.WithdrawalStatus) *models.WithdrawalStatusEvent {
+	statusEvent := &models.WithdrawalStatusEvent{
+		WithdrawalID: withdrawal.ID,
+		FromStatus:   from,
+		Status:       withdrawal.Status,
+		CreatedAt:    time.Now(),
+	}
+	if withdrawal.Status == models.WithdrawalStatusFailed {
+		statusEvent.Reason = withdrawal.FailureReason
+	}
+	return statusEvent
+}
+
+// CreateApproval records a collaborator's approval of a withdrawal. It reports whether the
+// approval was recorded, i.e. false when the collaborator had already approved.
+func (r *WithdrawalRepository) CreateApproval(ctx context.Context, approval *models.WithdrawalApproval) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "withdrawal_id"}, {Name: "approver_id"}}, DoNothing: true}).
+		Create(approval)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetApprovals retrieves the approvals of a withdrawal, oldest first
+func (r *WithdrawalRepository) GetApprovals(ctx context.Context, withdrawalID uuid.UUID) ([]models.WithdrawalApproval, error) {
+	var approvals []models.WithdrawalApproval
+	err := r.db.WithContext(ctx).Where("withdrawal_id = ?", withdrawalID).
+		Order("approved_at ASC").
+		Find(&approvals).Error
+	return approvals, err
 }
 
 // ProofRepository handles database operations for proofs
@@ -319,14 +1150,14 @@ func NewProofRepository(db *gorm.DB) *ProofRepository {
 }
 
 // CreateProof creates a new proof
-func (r *ProofRepository) CreateProof(proof *models.Proof) error {
-	return r.db.Create(proof).Error
+func (r *ProofRepository) CreateProof(ctx context.Context, proof *models.Proof) error {
+	return r.db.WithContext(ctx).Create(proof).Error
 }
 
 // GetProofByID retrieves a proof by ID with votes
-func (r *ProofRepository) GetProofByID(id uuid.UUID) (*models.Proof, error) {
+func (r *ProofRepository) GetProofByID(ctx context.Context, id uuid.UUID) (*models.Proof, error) {
 	var proof models.Proof
-	err := r.db.Preload("Votes").First(&proof, "id = ?", id).Error
+	err := r.db.WithContext(ctx).Preload("Votes").First(&proof, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -334,22 +1165,61 @@ func (r *ProofRepository) GetProofByID(id uuid.UUID) (*models.Proof, error) {
 }
 
 // GetProofsByGoalID retrieves all proofs for a goal
-func (r *ProofRepository) GetProofsByGoalID(goalID uuid.UUID) ([]models.Proof, error) {
+func (r *ProofRepository) GetProofsByGoalID(ctx context.Context, goalID uuid.UUID) ([]models.Proof, error) {
 	var proofs []models.Proof
-	err := r.db.Where("goal_id = ?", goalID).
+	err := r.db.WithContext(ctx).Where("goal_id = ?", goalID).
 		Order("submitted_at DESC").
 		Find(&proofs).Error
 	return proofs, err
 }
 
+// HasVerifiedProof reports whether a milestone has a verified proof; a nil milestoneID
+// checks for a verified goal-level proof instead
+func (r *ProofRepository) HasVerifiedProof(ctx context.Context, goalID uuid.UUID, milestoneID *uuid.UUID) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&models.Proof{}).
+		Where("goal_id = ? AND status = ?", goalID, models.ProofStatusVerified)
+	if milestoneID != nil {
+		query = query.Where("milestone_id = ?", *milestoneID)
+	} else {
+		query = query.Where("milestone_id IS NULL")
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// HasUnresolvedRejection reports whether a milestone (or the goal itself, for a nil
+// milestoneID) has a rejected proof that no proof has been verified since
+func (r *ProofRepository) HasUnresolvedRejection(ctx context.Context, goalID uuid.UUID, milestoneID *uuid.UUID) (bool, error) {
+	scope := func() *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&models.Proof{}).Where("goal_id = ?", goalID)
+		if milestoneID != nil {
+			return query.Where("milestone_id = ?", *milestoneID)
+		}
+		return query.Where("milestone_id IS NULL")
+	}
+
+	var rejectedAt *time.Time
+	if err := scope().Where("status = ?", models.ProofStatusRejected).
+		Select("MAX(rejected_at)").Scan(&rejectedAt).Error; err != nil || rejectedAt == nil {
+		return false, err
+	}
+
+	var verified int64
+	err := scope().Where("status = ? AND verified_at > ?", models.ProofStatusVerified, *rejectedAt).
+		Count(&verified).Error
+	return verified == 0, err
+}
+
 // UpdateProof updates a proof
-func (r *ProofRepository) UpdateProof(proof *models.Proof) error {
-	return r.db.Save(proof).Error
+func (r *ProofRepository) UpdateProof(ctx context.Context, proof *models.Proof) error {
+	return r.db.WithContext(ctx).Save(proof).Error
 }
 
 // DeleteProof deletes a proof
-func (r *ProofRepository) DeleteProof(id uuid.UUID) error {
-	return r.db.Delete(&models.Proof{}, "id = ?", id).Error
+func (r *ProofRepository) DeleteProof(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Proof{}, "id = ?", id).Error
 }
 
 // VoteRepository handles database operations for votes
@@ -363,14 +1233,14 @@ func NewVoteRepository(db *gorm.DB) *VoteRepository {
 }
 
 // CreateVote creates a new vote
-func (r *VoteRepository) CreateVote(vote *models.Vote) error {
-	return r.db.Create(vote).Error
+func (r *VoteRepository) CreateVote(ctx context.Context, vote *models.Vote) error {
+	return r.db.WithContext(ctx).Create(vote).Error
 }
 
 // GetVoteByProofAndVoter retrieves a vote by proof ID and voter ID
-func (r *VoteRepository) GetVoteByProofAndVoter(proofID, voterID uuid.UUID) (*models.Vote, error) {
+func (r *VoteRepository) GetVoteByProofAndVoter(ctx context.Context, proofID, voterID uuid.UUID) (*models.Vote, error) {
 	var vote models.Vote
-	err := r.db.First(&vote, "proof_id = ? AND voter_id = ?", proofID, voterID).Error
+	err := r.db.WithContext(ctx).First(&vote, "proof_id = ? AND voter_id = ?", proofID, voterID).Error
 	if err != nil {
 		return nil, err
 	}
@@ -378,58 +1248,201 @@ func (r *VoteRepository) GetVoteByProofAndVoter(proofID, voterID uuid.UUID) (*mo
 }
 
 // GetVotesByProofID retrieves all votes for a proof
-func (r *VoteRepository) GetVotesByProofID(proofID uuid.UUID) ([]models.Vote, error) {
+func (r *VoteRepository) GetVotesByProofID(ctx context.Context, proofID uuid.UUID) ([]models.Vote, error) {
 	var votes []models.Vote
-	err := r.db.Where("proof_id = ?", proofID).
+	err := r.db.WithContext(ctx).Where("proof_id = ?", proofID).
 		Order("voted_at DESC").
 		Find(&votes).Error
 	return votes, err
 }
 
 // UpdateVote updates a vote
-func (r *VoteRepository) UpdateVote(vote *models.Vote) error {
-	return r.db.Save(vote).Error
+func (r *VoteRepository) UpdateVote(ctx context.Context, vote *models.Vote) error {
+	return r.db.WithContext(ctx).Save(vote).Error
+}
+
+// VoteTally is where voting on a proof stands, by head count and by money: the confirmed
+// contributions to the goal of the voters, of the satisfied voters, and of everyone
+type VoteTally struct {
+	TotalVotes         int64
+	SatisfiedVotes     int64
+	Contributors       int64
+	VotedAmount        int64
+	SatisfiedAmount    int64
+	TotalContributions int64
+}
+
+// SaveVoteAndEvaluate creates or updates a vote and, in the same transaction, settles the
+// proof once evaluate(tally) verifies or rejects it and calls onSettled with the status.
+// The proof row is locked, so concurrent votes serialise and the proof is settled exactly
+// once; settled is the status this vote moved it to, or "" if it did not.
+func (r *VoteRepository) SaveVoteAndEvaluate(ctx context.Context, vote *models.Vote, evaluate func(tally VoteTally) models.ProofStatus, onSettled func(tx *Repository, status models.ProofStatus) error) (settled models.ProofStatus, err error) {
+	return r.changeVotesAndEvaluate(ctx, vote.ProofID, func(tx *gorm.DB) error {
+		return tx.Save(vote).Error
+	}, evaluate, onSettled)
+}
+
+// DeleteVoteAndEvaluate deletes a vote and re-evaluates the proof like SaveVoteAndEvaluate.
+// A settled proof keeps its status whatever votes are withdrawn.
+func (r *VoteRepository) DeleteVoteAndEvaluate(ctx context.Context, vote *models.Vote, evaluate func(tally VoteTally) models.ProofStatus, onSettled func(tx *Repository, status models.ProofStatus) error) (settled models.ProofStatus, err error) {
+	return r.changeVotesAndEvaluate(ctx, vote.ProofID, func(tx *gorm.DB) error {
+		return tx.Delete(&models.Vote{}, "id = ?", vote.ID).Error
+	}, evaluate, onSettled)
+}
+
+// changeVotesAndEvaluate applies change to a proof's votes with the proof row locked, then
+// settles a pending proof as evaluate(tally) decides and calls onSettled in the transaction
+func (r *VoteRepository) changeVotesAndEvaluate(ctx context.Context, proofID uuid.UUID, change func(tx *gorm.DB) error, evaluate func(tally VoteTally) models.ProofStatus, onSettled func(tx *Repository, status models.ProofStatus) error) (settled models.ProofStatus, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var proof models.Proof
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&proof, "id = ?", proofID).Error; err != nil {
+			return err
+		}
+
+		if err := change(tx); err != nil {
+			return err
+		}
+
+		if proof.Status != models.ProofStatusPending {
+			return nil
+		}
+
+		tally, err := tallyVotes(tx, &proof)
+		if err != nil {
+			return err
+		}
+
+		status := evaluate(tally)
+		now := time.Now()
+		updates := map[string]interface{}{"status": status}
+		switch status {
+		case models.ProofStatusVerified:
+			updates["verified_at"] = &now
+		case models.ProofStatusRejected:
+			updates["rejected_at"] = &now
+		default:
+			return nil
+		}
+
+		if err := tx.Model(&proof).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := onSettled(NewRepository(tx), status); err != nil {
+			return err
+		}
+		settled = status
+		return nil
+	})
+	return settled, err
+}
+
+// GetVoteTally returns the vote tally for a proof
+func (r *VoteRepository) GetVoteTally(ctx context.Context, proof *models.Proof) (VoteTally, error) {
+	return tallyVotes(r.db.WithContext(ctx), proof)
 }
 
-// GetVoteStats returns vote statistics for a proof
-func (r *VoteRepository) GetVoteStats(proofID uuid.UUID) (total, satisfied int64, err error) {
-	err = r.db.Model(&models.Vote{}).
-		Where("proof_id = ?", proofID).
-		Count(&total).Error
+// tallyVotes counts a proof's votes and joins each voter to their confirmed contributions
+// to the proof's goal
+func tallyVotes(db *gorm.DB, proof *models.Proof) (VoteTally, error) {
+	var tally VoteTally
+
+	err := db.Model(&models.Vote{}).
+		Select("COUNT(*) AS total_votes, COUNT(*) FILTER (WHERE is_satisfied) AS satisfied_votes").
+		Where("proof_id = ?", proof.ID).
+		Scan(&tally).Error
 	if err != nil {
-		return 0, 0, err
+		return tally, err
 	}
 
-	err = r.db.Model(&models.Vote{}).
-		Where("proof_id = ? AND is_satisfied = ?", proofID, true).
-		Count(&satisfied).Error
+	var amounts struct {
+		VotedAmount     int64
+		SatisfiedAmount int64
+	}
+	err = db.Table("votes v").
+		Select("COALESCE(SUM(c.amount), 0) AS voted_amount, COALESCE(SUM(c.amount) FILTER (WHERE v.is_satisfied), 0) AS satisfied_amount").
+		Joins("JOIN contributions c ON c.user_id = v.voter_id AND c.goal_id = ? AND c.status = ?", proof.GoalID, models.ContributionStatusConfirmed).
+		Where("v.proof_id = ?", proof.ID).
+		Scan(&amounts).Error
+	if err != nil {
+		return tally, err
+	}
+	tally.VotedAmount = amounts.VotedAmount
+	tally.SatisfiedAmount = amounts.SatisfiedAmount
 
-	return total, satisfied, err
+	var contributors struct {
+		Contributors       int64
+		TotalContributions int64
+	}
+	err = db.Model(&models.Contribution{}).
+		Select("COUNT(DISTINCT user_id) AS contributors, COALESCE(SUM(amount), 0) AS total_contributions").
+		Where("goal_id = ? AND status = ?", proof.GoalID, models.ContributionStatusConfirmed).
+		Scan(&contributors).Error
+	if err != nil {
+		return tally, err
+	}
+	tally.Contributors = contributors.Contributors
+	tally.TotalContributions = contributors.TotalContributions
+
+	return tally, nil
 }
 
 // DeleteVote deletes a vote
-func (r *VoteRepository) DeleteVote(id uuid.UUID) error {
-	return r.db.Delete(&models.Vote{}, "id = ?", id).Error
+func (r *VoteRepository) DeleteVote(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Vote{}, "id = ?", id).Error
 }
 
 // Repository aggregates all repositories
 type Repository struct {
-	Goal         *GoalRepository
-	Milestone    *MilestoneRepository
-	Contribution *ContributionRepository
-	Withdrawal   *WithdrawalRepository
-	Proof        *ProofRepository
-	Vote         *VoteRepository
+	Goal               *GoalRepository
+	Milestone          *MilestoneRepository
+	Contribution       *ContributionRepository
+	Withdrawal         *WithdrawalRepository
+	Proof              *ProofRepository
+	ProofMedia         *ProofMediaRepository
+	Vote               *VoteRepository
+	Refund             *RefundRepository
+	RefundDisbursement *RefundDisbursementRepository
+	RefundRequest      *RefundRequestRepository
+	Collaborator       *GoalCollaboratorRepository
+	Report             *GoalReportRepository
+	Audit              *AuditLogRepository
+	Outbox             *OutboxRepository
+
+	db *gorm.DB
 }
 
 // NewRepository creates a new repository instance
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{
-		Goal:         NewGoalRepository(db),
-		Milestone:    NewMilestoneRepository(db),
-		Contribution: NewContributionRepository(db),
-		Withdrawal:   NewWithdrawalRepository(db),
-		Proof:        NewProofRepository(db),
-		Vote:         NewVoteRepository(db),
+		Goal:               NewGoalRepository(db),
+		Milestone:          NewMilestoneRepository(db),
+		Contribution:       NewContributionRepository(db),
+		Withdrawal:         NewWithdrawalRepository(db),
+		Proof:              NewProofRepository(db),
+		ProofMedia:         NewProofMediaRepository(db),
+		Vote:               NewVoteRepository(db),
+		Refund:             NewRefundRepository(db),
+		RefundDisbursement: NewRefundDisbursementRepository(db),
+		RefundRequest:      NewRefundRequestRepository(db),
+		Collaborator:       NewGoalCollaboratorRepository(db),
+		Report:             NewGoalReportRepository(db),
+		Audit:              NewAuditLogRepository(db),
+		Outbox:             NewOutboxRepository(db),
+		db:                 db,
 	}
 }
+
+// Transaction runs fn with a repository whose every query belongs to one transaction,
+// committed if fn returns nil and rolled back otherwise
+func (r *Repository) Transaction(ctx context.Context, fn func(tx *Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepository(tx))
+	})
+}
+
+// Primary returns a repository whose reads go to the primary database even when read
+// replicas are configured, for reads that must see a write just made. A replica may lag
+// the primary; queries in a transaction and locking reads use the primary anyway.
+func (r *Repository) Primary() *Repository {
+	return NewRepository(r.db.Clauses(dbresolver.Write).Session(&gorm.Session{}))
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TrendingCandidate is a confirmed contribution to a trending-eligible goal
+type TrendingCandidate struct {
+	GoalID       uuid.UUID
+	TargetAmount int64
+	UserID       uuid.UUID
+	Amount       int64
+	ConfirmedAt  time.Time
+}
+
+// TrendingRepository handles database operations for trending goals
+type TrendingRepository struct {
+	db *gorm.DB
+}
+
+// NewTrendingRepository creates a new trending repository
+func NewTrendingRepository(db *gorm.DB) *TrendingRepository {
+	return &TrendingRepository{db: db}
+}
+
+// GetCandidateContributions returns confirmed contributions since the given time for
+// goals that are eligible to trend (open and public)
+func (r *TrendingRepository) GetCandidateContributions(ctx context.Context, since time.Time) ([]TrendingCandidate, error) {
+	var candidates []TrendingCandidate
+	// Contributions have no confirmed_at column; updated_at is set when the status flips to CONFIRMED
+	err := r.db.WithContext(ctx).Table("contributions c").
+		Select("c.goal_id, g.target_amount, c.user_id, c.amount, c.updated_at AS confirmed_at").
+		Joins("JOIN goals g ON g.id = c.goal_id").
+		Where("c.status = ? AND c.updated_at >= ?", models.ContributionStatusConfirmed, since).
+		Where("g.status = ? AND g.is_public = ?", models.GoalStatusOpen, true).
+		Scan(&candidates).Error
+	return candidates, err
+}
+
+// ReplaceTrending swaps the stored trending scores for a freshly computed set
+func (r *TrendingRepository) ReplaceTrending(ctx context.Context, scores []models.GoalTrending) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.GoalTrending{}).Error; err != nil {
+			return err
+		}
+		if len(scores) == 0 {
+			return nil
+		}
+		return tx.Create(&scores).Error
+	})
+}
+
+// GetTrending returns the top stored trending scores whose goals are still open and public
+func (r *TrendingRepository) GetTrending(ctx context.Context, limit int) ([]models.GoalTrending, error) {
+	var scores []models.GoalTrending
+	err := r.db.WithContext(ctx).Table("goal_trending t").
+		Select("t.*").
+		Joins("JOIN goals g ON g.id = t.goal_id").
+		Where("g.status = ? AND g.is_public = ?", models.GoalStatusOpen, true).
+		Order("t.rank ASC").
+		Limit(limit).
+		Scan(&scores).Error
+	return scores, err
+}
+
+// GetRecentlyActiveGoals returns open public goals ordered by their latest confirmed contribution
+func (r *TrendingRepository) GetRecentlyActiveGoals(ctx context.Context, limit int) ([]models.Goal, error) {
+	var goals []models.Goal
+	err := r.db.WithContext(ctx).Table("goals g").
+		Select("g.*").
+		Joins("LEFT JOIN contributions c ON c.goal_id = g.id AND c.status = ?", models.ContributionStatusConfirmed).
+		Where("g.status = ? AND g.is_public = ?", models.GoalStatusOpen, true).
+		Group("g.id").
+		Order("MAX(c.updated_at) DESC NULLS LAST, g.created_at DESC").
+		Limit(limit).
+		Scan(&goals).Error
+	return goals, err
+}
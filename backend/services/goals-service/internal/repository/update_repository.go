@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GoalUpdateRepository handles database operations for goal updates
+type GoalUpdateRepository struct {
+	db *gorm.DB
+}
+
+// NewGoalUpdateRepository creates a new goal update repository
+func NewGoalUpdateRepository(db *gorm.DB) *GoalUpdateRepository {
+	return &GoalUpdateRepository{db: db}
+}
+
+// CreateUpdate creates a new goal update
+func (r *GoalUpdateRepository) CreateUpdate(ctx context.Context, update *models.GoalUpdate) error {
+	return r.db.WithContext(ctx).Create(update).Error
+}
+
+// GetUpdatesByGoalID returns a page of a goal's updates, newest first, with the total count
+func (r *GoalUpdateRepository) GetUpdatesByGoalID(ctx context.Context, goalID uuid.UUID, limit, offset int) ([]models.GoalUpdate, int64, error) {
+	var updates []models.GoalUpdate
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.GoalUpdate{}).Where("goal_id = ?", goalID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Limit(limit).Offset(offset).
+		Order("created_at DESC").
+		Find(&updates).Error
+
+	return updates, total, err
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GoalWatchRepository handles database operations for users' goal watch lists
+type GoalWatchRepository struct {
+	db *gorm.DB
+}
+
+// NewGoalWatchRepository creates a new goal watch repository
+func NewGoalWatchRepository(db *gorm.DB) *GoalWatchRepository {
+	return &GoalWatchRepository{db: db}
+}
+
+// Watch adds a goal to a user's watch list. It reports whether the goal was added, so
+// watching a goal twice is not an error.
+func (r *GoalWatchRepository) Watch(ctx context.Context, userID, goalID uuid.UUID) (bool, error) {
+	watch := &models.GoalWatch{UserID: userID, GoalID: goalID}
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}, {Name: "goal_id"}}, DoNothing: true}).
+		Create(watch)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Unwatch removes a goal from a user's watch list. It reports whether the goal was on it.
+func (r *GoalWatchRepository) Unwatch(ctx context.Context, userID, goalID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND goal_id = ?", userID, goalID).
+		Delete(&models.GoalWatch{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetWatchedGoals returns a page of the goals a user watches, most recently watched first,
+// with the total count. Private goals are left out unless the user owns them; unlisted
+// goals are kept, as the user has already found them.
+func (r *GoalWatchRepository) GetWatchedGoals(ctx context.Context, userID uuid.UUID, limit, offset int) ([]models.Goal, int64, error) {
+	var goals []models.Goal
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Goal{}).
+		Joins("JOIN goal_watches ON goal_watches.goal_id = goals.id").
+		Where("goal_watches.user_id = ? AND (goals.visibility <> ? OR goals.owner_id = ?)", userID, models.GoalVisibilityPrivate, userID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Limit(limit).Offset(offset).
+		Order("goal_watches.created_at DESC").
+		Find(&goals).Error
+
+	return goals, total, err
+}
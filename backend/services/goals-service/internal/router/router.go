@@ -0,0 +1,189 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/config"
+	"github.com/gofund/goals-service/internal/controllers"
+	"github.com/gofund/goals-service/internal/middleware"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/shared/apidocs"
+)
+
+// Services are the services the Goals Service routes are served by
+type Services struct {
+	Goals         *service.GoalService
+	Trending      *service.TrendingService
+	Contributions *service.ContributionService
+	Withdrawals   *service.WithdrawalService
+	Proofs        *service.ProofService
+	Votes         *service.VoteService
+	Refunds       *service.RefundService
+	Receipts      *service.ReceiptService
+	Comments      *service.CommentService
+	Updates       *service.GoalUpdateService
+	Watches       *service.WatchService
+	Collaborators *service.CollaboratorService
+	Exports       *service.ExportService
+	Audit         *service.AuditService
+	Stats         *service.GoalStatsService
+	PlatformStats *service.PlatformStatsService
+	Recurring     *service.RecurringContributionService
+	Moderation    *service.ModerationService
+	DataQuality   *service.DataQualityService
+	BalanceCheck  *service.BalanceCheckService
+}
+
+// SetupRoutes configures the Goals Service API routes
+func SetupRoutes(r *gin.Engine, s Services, cfg *config.Config) {
+	// Initialize controllers
+	goalController := controllers.NewGoalController(s.Goals, s.Trending)
+	goalControllerV2 := controllers.NewGoalControllerV2(s.Goals)
+	contributionController := controllers.NewContributionController(s.Contributions, s.Withdrawals, s.Proofs, s.Votes)
+	refundController := controllers.NewRefundController(s.Refunds)
+	receiptController := controllers.NewReceiptController(s.Receipts)
+	commentController := controllers.NewCommentController(s.Comments)
+	updateController := controllers.NewGoalUpdateController(s.Updates)
+	watchController := controllers.NewWatchController(s.Watches)
+	collaboratorController := controllers.NewCollaboratorController(s.Collaborators)
+	exportController := controllers.NewExportController(s.Exports)
+	auditController := controllers.NewAuditController(s.Audit)
+	statsController := controllers.NewGoalStatsController(s.Stats, s.PlatformStats)
+	recurringController := controllers.NewRecurringContributionController(s.Recurring)
+	reportController := controllers.NewReportController(s.Moderation)
+	adminController := controllers.NewAdminController(s.DataQuality, s.Goals, s.BalanceCheck, s.Moderation)
+
+	// Routes. v1 is frozen; new response shapes go to v2, which shares the service layer
+	api := r.Group("/api/v1/goals")
+	api.Use(middleware.APIVersion("v1"), middleware.Deprecated("/api/v2/goals"))
+	{
+		// Public routes (or read-only)
+		api.GET("", goalController.ListPublicGoals)
+		api.GET("/list", goalController.ListPublicGoals) // Alias for frontend compatibility
+		api.GET("/trending", goalController.GetTrendingGoals)
+		api.GET("/stats/platform", statsController.GetPlatformStats)
+		api.GET("/search", goalController.SearchGoals)
+		api.GET("/slug/:slug", goalController.GetGoalBySlug)
+		api.GET("/:id", goalController.GetGoal)
+		api.GET("/view/:id", goalController.GetGoal) // Alias for frontend compatibility
+		api.GET("/:id/progress", goalController.GetGoalProgress)
+		api.GET("/:id/share-meta", goalController.GetShareMeta)
+		api.GET("/:id/contributions", contributionController.GetContributionFeed)
+		api.GET("/:id/comments", commentController.ListComments)
+		api.GET("/:id/updates", updateController.ListUpdates)
+		api.GET("/proofs", contributionController.GetProofs)
+		api.GET("/proofs/:proofId", contributionController.GetProof)
+		api.GET("/proofs/:proofId/stats", contributionController.GetVoteStats)
+		api.GET("/proofs/:proofId/votes", contributionController.ListProofVotes)
+		api.Static("/proofs/media", cfg.Proofs.MediaDir)
+
+		// Protected routes
+		protected := api.Group("")
+		protected.Use(middleware.AuthMiddleware())
+		{
+			protected.GET("/my", goalController.GetMyGoals)
+			protected.GET("/watched", watchController.ListWatchedGoals)
+			protected.POST("", middleware.RequireVerifiedEmail(cfg.Goals.RequireVerifiedEmail), goalController.CreateGoal)
+			protected.PATCH("/:id", goalController.UpdateGoal)
+			protected.DELETE("/:id", goalController.DeleteGoal)
+			protected.POST("/:id/close", goalController.CloseGoal)
+			protected.POST("/:id/cancel", goalController.CancelGoal)
+			protected.POST("/:id/invites", goalController.CreateGoalInvite)
+			protected.POST("/:id/milestones", goalController.CreateMilestone)
+			protected.GET("/:id/milestones", goalController.GetGoalMilestones)
+			protected.GET("/:id/contributors", contributionController.GetOwnerContributions)
+			protected.GET("/:id/withdrawals", contributionController.GetWithdrawalHistory)
+			protected.POST("/:id/comments", commentController.CreateComment)
+			protected.DELETE("/:id/comments/:commentId", commentController.DeleteComment)
+			protected.POST("/:id/updates", updateController.PostUpdate)
+			protected.POST("/:id/watch", watchController.WatchGoal)
+			protected.DELETE("/:id/watch", watchController.UnwatchGoal)
+			protected.POST("/:id/reports", reportController.ReportGoal)
+			protected.GET("/:id/audit", auditController.ListGoalAuditLog)
+			protected.GET("/:id/export", exportController.ExportGoal)
+			protected.GET("/:id/stats", statsController.GetGoalStats)
+			protected.GET("/:id/collaborators", collaboratorController.ListCollaborators)
+			protected.POST("/:id/collaborators", collaboratorController.AddCollaborator)
+			protected.DELETE("/:id/collaborators/:userId", collaboratorController.RemoveCollaborator)
+			protected.POST("/milestones/:milestoneId/complete", goalController.CompleteMilestone)
+			protected.PATCH("/milestones/:milestoneId", goalController.UpdateMilestone)
+			protected.DELETE("/milestones/:milestoneId", goalController.DeleteMilestone)
+
+			protected.POST("/contribute", contributionController.CreateContribution)
+			protected.POST("/withdraw", contributionController.CreateWithdrawal)
+			protected.POST("/withdrawals/:id/approve", contributionController.ApproveWithdrawal)
+			protected.GET("/withdrawals/:id", contributionController.GetWithdrawal)
+			protected.POST("/proofs", contributionController.CreateProof)
+			protected.POST("/proofs/upload", contributionController.UploadProofMedia)
+			protected.DELETE("/proofs/:proofId", contributionController.DeleteProof)
+			protected.POST("/votes", contributionController.CreateVote)
+			protected.DELETE("/votes/:proofId", contributionController.RetractVote)
+
+			protected.POST("/refunds", refundController.InitiateRefund)
+			protected.POST("/refunds/preview", refundController.PreviewRefund)
+			protected.GET("/refunds/:id", refundController.GetRefund)
+			protected.GET("/goals/:goalId/refunds", refundController.GetGoalRefunds)
+			protected.GET("/goals/:goalId/refund-requests", refundController.GetGoalRefundRequests)
+			protected.POST("/refund-requests/:id/approve", refundController.ApproveRefundRequest)
+			protected.POST("/refund-requests/:id/deny", refundController.DenyRefundRequest)
+		}
+
+		// Admin moderation routes
+		moderation := api.Group("/admin")
+		moderation.Use(middleware.AuthMiddleware(), middleware.RequireRole("admin"))
+		{
+			moderation.GET("/all", adminController.ListAllGoals)
+			moderation.POST("/:id/suspend", adminController.SuspendGoal)
+			moderation.POST("/:id/unsuspend", adminController.UnsuspendGoal)
+			moderation.POST("/:id/verify-balance", adminController.VerifyGoalBalance)
+		}
+	}
+
+	apiV2 := r.Group("/api/v2/goals")
+	apiV2.Use(middleware.APIVersion("v2"))
+	{
+		apiV2.GET("", goalControllerV2.ListGoals)
+		apiV2.GET("/:id", goalControllerV2.GetGoal)
+		apiV2.GET("/:id/progress", goalControllerV2.GetGoalProgress)
+	}
+
+	// Contributions routes
+	contributions := r.Group("/api/v1/contributions")
+	contributions.Use(middleware.AuthMiddleware())
+	{
+		contributions.GET("/my", contributionController.GetMyContributions)
+		contributions.GET("/:id", contributionController.GetContribution)
+		contributions.GET("/:id/receipt", receiptController.GetReceipt)
+		contributions.GET("/:id/refund", refundController.GetContributionRefund)
+		contributions.POST("/:id/refund-request", refundController.RequestRefund)
+		contributions.POST("", contributionController.CreateContribution)
+
+		contributions.GET("/recurring", recurringController.ListRecurringContributions)
+		contributions.GET("/recurring/:id", recurringController.GetRecurringContribution)
+		contributions.POST("/recurring", recurringController.CreateRecurringContribution)
+		contributions.PATCH("/recurring/:id", recurringController.UpdateRecurringContribution)
+		contributions.DELETE("/recurring/:id", recurringController.CancelRecurringContribution)
+	}
+
+	// Internal routes (called by other services, not exposed through Nginx)
+	internal := r.Group("/internal")
+	{
+		internal.GET("/goals/:id", goalController.GetInternalGoal)
+		internal.GET("/users/:id/goals", goalController.GetInternalOwnerGoals)
+	}
+
+	// Admin routes
+	admin := r.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		admin.GET("/data-quality/orphans", adminController.GetOrphans)
+		admin.POST("/data-quality/goal-totals/reconcile", adminController.ReconcileGoalTotals)
+		admin.GET("/goals/moderation-queue", adminController.GetModerationQueue)
+		admin.POST("/goals/bulk", adminController.BulkModerate)
+	}
+
+	// API docs, generated at build time with `make docs`; never served in production
+	if apidocs.Enabled(cfg.Server.Env, cfg.Datadog.Env) {
+		r.GET("/api/v1/goals/docs", gin.WrapF(apidocs.UI("Goals Service API", "/api/v1/goals/docs/openapi.json")))
+		r.GET("/api/v1/goals/docs/openapi.json", gin.WrapF(apidocs.Spec(apidocs.DefaultSpecPath)))
+	}
+}
@@ -0,0 +1,1203 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/goals-service/internal/config"
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/middleware"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/service"
+	"github.com/gofund/goals-service/internal/storage"
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/sdk"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+const contractIdentitySecret = "contract-identity-secret"
+
+// pngHeader is enough of a PNG for proof media uploads to be sniffed as one
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x02\x00\x00\x00")
+
+// The users the contracts call as
+const (
+	asOwner = iota
+	asBacker
+	asApprover
+	asAdmin
+)
+
+// goalsFixture is the state the SDK contracts run against: an open goal with three
+// confirmed contributions from a backer and an approver sharing its withdrawals, and a
+// closed goal the backer contributed to. Contracts record what later ones need, such as
+// the goal the owner creates and the proof they submit.
+type goalsFixture struct {
+	ownerID, backerID, approverID, adminID, otherID uuid.UUID
+	goalID, closedGoalID                            uuid.UUID
+	slug                                            string
+	// c1 has a reusable card; c2 and c3 are refund requested; c4 is on the closed goal
+	c1, c2, c3, c4   uuid.UUID
+	paymentReference string
+
+	inviteToken                        string
+	commentID, contributionID          uuid.UUID
+	recurringID, withdrawalID          uuid.UUID
+	mediaURL                           string
+	proofID, refundID                  uuid.UUID
+	approvedRequestID, deniedRequestID uuid.UUID
+	newGoalID, milestoneID             uuid.UUID
+	newMilestoneID                     uuid.UUID
+}
+
+// sdkContract is one SDK method called against the goals routes as one of the fixture's
+// users. check, if any, inspects the result when the routes are backed by a database.
+type sdkContract struct {
+	name  string
+	as    int
+	call  func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error)
+	check func(t *testing.T, f *goalsFixture, result interface{})
+}
+
+// setID records id in dst when a call returned one
+func setID(dst *uuid.UUID, id string) {
+	if parsed, err := uuid.Parse(id); err == nil {
+		*dst = parsed
+	}
+}
+
+// sdkContracts covers every GoalsClient method. They run in order, each seeing what the
+// earlier ones changed.
+func sdkContracts() []sdkContract {
+	return []sdkContract{
+		// Public reads
+		{
+			name: "ListPublicGoals",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ListPublicGoals(ctx, "newest", 1, 10)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				page := result.(*sdk.PublicGoalsPage)
+				for _, goal := range page.Data {
+					if goal.ID == f.goalID.String() {
+						return
+					}
+				}
+				t.Errorf("page = %+v, want the open goal", page)
+			},
+		},
+		{
+			name: "SearchGoals",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.SearchGoals(ctx, "library", 1, 10)
+			},
+		},
+		{
+			name: "GetTrendingGoals",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetTrendingGoals(ctx, 5)
+			},
+		},
+		{
+			name: "GetGoal",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetGoal(ctx, f.goalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				goal := result.(*sdk.Goal)
+				if goal.ID != f.goalID.String() || goal.Slug != f.slug || goal.RequiredApprovals != 2 {
+					t.Errorf("goal = %+v, want %s needing two approvals", goal, f.goalID)
+				}
+			},
+		},
+		{
+			name: "GetGoalBySlug",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetGoalBySlug(ctx, f.slug)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if goal := result.(*sdk.Goal); goal.ID != f.goalID.String() {
+					t.Errorf("goal = %+v, want %s", goal, f.goalID)
+				}
+			},
+		},
+		{
+			name: "GetShareMeta",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetShareMeta(ctx, f.goalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				meta := result.(*sdk.GoalShareMeta)
+				if meta.GoalID != f.goalID.String() || meta.CurrentAmount != 500_000 {
+					t.Errorf("meta = %+v, want %s with 500000 raised", meta, f.goalID)
+				}
+			},
+		},
+		{
+			name: "GetGoalProgress",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetGoalProgress(ctx, f.goalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				progress := result.(*sdk.GoalProgress)
+				if progress.Goal.ID != f.goalID.String() || progress.TotalContributions != 500_000 {
+					t.Errorf("progress = %+v, want 500000 contributed", progress)
+				}
+			},
+		},
+		{
+			name: "GetPlatformStats",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetPlatformStats(ctx)
+			},
+		},
+		{
+			name: "GetGoalStats",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetGoalStats(ctx, f.goalID.String(), 30)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				stats := result.(*sdk.GoalStats)
+				if stats.GoalID != f.goalID.String() || stats.Contributions.Confirmed != 3 {
+					t.Errorf("stats = %+v, want three confirmed contributions", stats)
+				}
+			},
+		},
+		{
+			name: "GetContributionFeed",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetContributionFeed(ctx, f.goalID.String(), 1, 10)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if feed := result.(*sdk.ContributionFeed); feed.Total != 3 || len(feed.Items) != 3 {
+					t.Errorf("feed = %+v, want the three contributions", feed)
+				}
+			},
+		},
+		{
+			name: "GetOwnerContributions",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetOwnerContributions(ctx, f.goalID.String(), 1, 10)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if list := result.(*sdk.OwnerContributionList); list.Total != 3 || len(list.Items) != 3 {
+					t.Errorf("list = %+v, want the three contributions", list)
+				}
+			},
+		},
+		{
+			name: "ListMyGoals",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ListMyGoals(ctx, 1, 10)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if page := result.(*sdk.MyGoalsPage); page.Total != 2 || len(page.Goals) != 2 {
+					t.Errorf("page = %+v, want the owner's two goals", page)
+				}
+			},
+		},
+
+		// Collaborators and invites
+		{
+			name: "ListCollaborators",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ListCollaborators(ctx, f.goalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				collaborators := result.([]sdk.GoalCollaborator)
+				if len(collaborators) != 1 || collaborators[0].UserID != f.approverID.String() || collaborators[0].Role != "approver" {
+					t.Errorf("collaborators = %+v, want the approver", collaborators)
+				}
+			},
+		},
+		{
+			name: "AddCollaborator",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.AddCollaborator(ctx, f.goalID.String(), &sdk.AddCollaboratorRequest{UserID: f.otherID.String(), Role: "approver"})
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				collaborator := result.(*sdk.GoalCollaborator)
+				if collaborator.UserID != f.otherID.String() || collaborator.InvitedBy != f.ownerID.String() {
+					t.Errorf("collaborator = %+v, want %s invited by the owner", collaborator, f.otherID)
+				}
+			},
+		},
+		{
+			name: "RemoveCollaborator",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return nil, c.RemoveCollaborator(ctx, f.goalID.String(), f.otherID.String())
+			},
+		},
+		{
+			name: "CreateGoalInvite",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				invite, err := c.CreateGoalInvite(ctx, f.goalID.String())
+				if err == nil && invite != nil {
+					f.inviteToken = invite.Token
+				}
+				return invite, err
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if invite := result.(*sdk.GoalInvite); invite.GoalID != f.goalID.String() || invite.Token == "" {
+					t.Errorf("invite = %+v, want a token for %s", invite, f.goalID)
+				}
+			},
+		},
+		{
+			name: "GetInvitedGoal",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetInvitedGoal(ctx, f.goalID.String(), f.inviteToken)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if goal := result.(*sdk.Goal); goal.ID != f.goalID.String() {
+					t.Errorf("goal = %+v, want %s", goal, f.goalID)
+				}
+			},
+		},
+
+		// Watching, comments and updates
+		{
+			name: "WatchGoal",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return nil, c.WatchGoal(ctx, f.goalID.String())
+			},
+		},
+		{
+			name: "ListWatchedGoals",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ListWatchedGoals(ctx, 1, 10)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				page := result.(*sdk.PublicGoalsPage)
+				if page.Total != 1 || len(page.Data) != 1 || page.Data[0].ID != f.goalID.String() {
+					t.Errorf("page = %+v, want the watched goal", page)
+				}
+			},
+		},
+		{
+			name: "UnwatchGoal",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return nil, c.UnwatchGoal(ctx, f.goalID.String())
+			},
+		},
+		{
+			name: "CreateComment",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				comment, err := c.CreateComment(ctx, f.goalID.String(), &sdk.CreateCommentRequest{Body: "Glad to help"})
+				if err == nil && comment != nil {
+					setID(&f.commentID, comment.ID)
+				}
+				return comment, err
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if comment := result.(*sdk.Comment); comment.UserID != f.backerID.String() || comment.Body != "Glad to help" {
+					t.Errorf("comment = %+v, want the backer's", comment)
+				}
+			},
+		},
+		{
+			name: "ListComments",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ListComments(ctx, f.goalID.String(), 1, 10)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				threads := result.(*sdk.CommentThreads)
+				if threads.Total != 1 || len(threads.Items) != 1 || threads.Items[0].ID != f.commentID.String() {
+					t.Errorf("threads = %+v, want the backer's comment", threads)
+				}
+			},
+		},
+		{
+			name: "DeleteComment",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return nil, c.DeleteComment(ctx, f.goalID.String(), f.commentID.String())
+			},
+		},
+		{
+			name: "PostGoalUpdate",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.PostGoalUpdate(ctx, f.goalID.String(), &sdk.CreateGoalUpdateRequest{Title: "Halfway", Body: "Half the shelves are paid for"})
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if update := result.(*sdk.GoalUpdate); update.AuthorID != f.ownerID.String() || update.Title != "Halfway" {
+					t.Errorf("update = %+v, want the owner's", update)
+				}
+			},
+		},
+		{
+			name: "ListGoalUpdates",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ListGoalUpdates(ctx, f.goalID.String(), 1, 10)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if page := result.(*sdk.GoalUpdatePage); page.Total != 1 || len(page.Items) != 1 {
+					t.Errorf("page = %+v, want the owner's update", page)
+				}
+			},
+		},
+
+		// Contributions
+		{
+			name: "CreateContribution",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				contribution, err := c.CreateContribution(ctx, &sdk.CreateContributionRequest{GoalID: f.goalID.String(), Amount: 5_000, Currency: "NGN"})
+				if err == nil && contribution != nil {
+					setID(&f.contributionID, contribution.ID)
+				}
+				return contribution, err
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				contribution := result.(*sdk.Contribution)
+				if contribution.GoalID != f.goalID.String() || contribution.Amount != 5_000 || contribution.Status != string(models.ContributionStatusPending) {
+					t.Errorf("contribution = %+v, want a pending contribution of 5000", contribution)
+				}
+			},
+		},
+		{
+			name: "GetContribution",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetContribution(ctx, f.contributionID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if contribution := result.(*sdk.Contribution); contribution.ID != f.contributionID.String() {
+					t.Errorf("contribution = %+v, want %s", contribution, f.contributionID)
+				}
+			},
+		},
+		{
+			name: "GetContributionReceipt",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetContributionReceipt(ctx, f.c1.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				receipt := result.(*sdk.ContributionReceipt)
+				if receipt.ContributionID != f.c1.String() || receipt.Amount != 300_000 || receipt.PaymentReference != f.paymentReference {
+					t.Errorf("receipt = %+v, want 300000 paid with %s", receipt, f.paymentReference)
+				}
+			},
+		},
+		{
+			name: "ListMyContributions",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ListMyContributions(ctx)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if contributions := result.([]sdk.Contribution); len(contributions) < 4 {
+					t.Errorf("contributions = %+v, want at least the backer's four confirmed ones", contributions)
+				}
+			},
+		},
+		{
+			name: "CreateRecurringContribution",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				recurring, err := c.CreateRecurringContribution(ctx, &sdk.CreateRecurringContributionRequest{ContributionID: f.c1.String(), Interval: "MONTHLY", Amount: 10_000})
+				if err == nil && recurring != nil {
+					setID(&f.recurringID, recurring.ID)
+				}
+				return recurring, err
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				recurring := result.(*sdk.RecurringContribution)
+				if recurring.GoalID != f.goalID.String() || recurring.Amount != 10_000 || recurring.Status != string(models.RecurringContributionActive) {
+					t.Errorf("recurring = %+v, want an active monthly 10000", recurring)
+				}
+			},
+		},
+		{
+			name: "ListRecurringContributions",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ListRecurringContributions(ctx)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if recurring := result.([]sdk.RecurringContribution); len(recurring) != 1 || recurring[0].ID != f.recurringID.String() {
+					t.Errorf("recurring = %+v, want %s", recurring, f.recurringID)
+				}
+			},
+		},
+		{
+			name: "GetRecurringContribution",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetRecurringContribution(ctx, f.recurringID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if recurring := result.(*sdk.RecurringContribution); recurring.ID != f.recurringID.String() {
+					t.Errorf("recurring = %+v, want %s", recurring, f.recurringID)
+				}
+			},
+		},
+		{
+			name: "UpdateRecurringContribution",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				paused := string(models.RecurringContributionPaused)
+				return c.UpdateRecurringContribution(ctx, f.recurringID.String(), &sdk.UpdateRecurringContributionRequest{Status: &paused})
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if recurring := result.(*sdk.RecurringContribution); recurring.Status != string(models.RecurringContributionPaused) {
+					t.Errorf("recurring = %+v, want it paused", recurring)
+				}
+			},
+		},
+		{
+			name: "CancelRecurringContribution",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return nil, c.CancelRecurringContribution(ctx, f.recurringID.String())
+			},
+		},
+
+		// Withdrawals
+		{
+			name: "CreateWithdrawal",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				withdrawal, err := c.CreateWithdrawal(ctx, &sdk.CreateWithdrawalRequest{GoalID: f.goalID.String(), Amount: 100_000})
+				if err == nil && withdrawal != nil {
+					setID(&f.withdrawalID, withdrawal.ID)
+				}
+				return withdrawal, err
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				withdrawal := result.(*sdk.Withdrawal)
+				if withdrawal.Amount != 100_000 || withdrawal.Status != string(models.WithdrawalStatusAwaitingApproval) || len(withdrawal.Approvals) != 1 {
+					t.Errorf("withdrawal = %+v, want 100000 awaiting a second approval", withdrawal)
+				}
+			},
+		},
+		{
+			name: "ApproveWithdrawal",
+			as:   asApprover,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ApproveWithdrawal(ctx, f.withdrawalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				withdrawal := result.(*sdk.Withdrawal)
+				if withdrawal.Status == string(models.WithdrawalStatusAwaitingApproval) || len(withdrawal.Approvals) != 2 {
+					t.Errorf("withdrawal = %+v, want it released by the second approval", withdrawal)
+				}
+			},
+		},
+		{
+			name: "GetWithdrawalHistory",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetWithdrawalHistory(ctx, f.goalID.String(), 1, 10)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				history := result.(*sdk.WithdrawalHistory)
+				if history.Total != 1 || len(history.Items) != 1 || history.Items[0].AccountNumberLast4 != "6789" {
+					t.Errorf("history = %+v, want the withdrawal to the deposit account", history)
+				}
+			},
+		},
+		{
+			name: "GetWithdrawal",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetWithdrawal(ctx, f.withdrawalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if detail := result.(*sdk.WithdrawalDetail); detail.ID != f.withdrawalID.String() || len(detail.StatusHistory) == 0 {
+					t.Errorf("detail = %+v, want %s with its status history", detail, f.withdrawalID)
+				}
+			},
+		},
+
+		// Proofs and votes
+		{
+			name: "UploadProofMedia",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				media, err := c.UploadProofMedia(ctx, "shelves.png", bytes.NewReader(pngHeader))
+				if err == nil && media != nil {
+					f.mediaURL = media.URL
+				}
+				return media, err
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if media := result.(*sdk.ProofMedia); media.ContentType != "image/png" || media.URL == "" {
+					t.Errorf("media = %+v, want a stored PNG", media)
+				}
+			},
+		},
+		{
+			name: "CreateProof",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				proof, err := c.CreateProof(ctx, &sdk.CreateProofRequest{GoalID: f.goalID.String(), Title: "Shelves", Description: "Delivered", MediaURLs: []string{f.mediaURL}})
+				if err == nil && proof != nil {
+					setID(&f.proofID, proof.ID)
+				}
+				return proof, err
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if proof := result.(*sdk.Proof); proof.SubmittedBy != f.ownerID.String() || len(proof.MediaURLs) != 1 {
+					t.Errorf("proof = %+v, want the owner's, citing the upload", proof)
+				}
+			},
+		},
+		{
+			name: "GetProofs",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetProofs(ctx, f.goalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if proofs := result.([]sdk.Proof); len(proofs) != 1 || proofs[0].ID != f.proofID.String() {
+					t.Errorf("proofs = %+v, want %s", proofs, f.proofID)
+				}
+			},
+		},
+		{
+			name: "GetProof",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetProof(ctx, f.proofID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if proof := result.(*sdk.Proof); proof.ID != f.proofID.String() {
+					t.Errorf("proof = %+v, want %s", proof, f.proofID)
+				}
+			},
+		},
+		{
+			name: "CreateVote",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.CreateVote(ctx, &sdk.CreateVoteRequest{ProofID: f.proofID.String(), IsSatisfied: true, Comment: "Saw them"})
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if vote := result.(*sdk.Vote); vote.VoterID != f.backerID.String() || !vote.IsSatisfied {
+					t.Errorf("vote = %+v, want the backer satisfied", vote)
+				}
+			},
+		},
+		{
+			name: "GetVoteStats",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetVoteStats(ctx, f.proofID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if stats := result.(*sdk.VoteStats); stats.TotalVotes != 1 || stats.SatisfiedVotes != 1 {
+					t.Errorf("stats = %+v, want one satisfied vote", stats)
+				}
+			},
+		},
+		{
+			name: "ListProofVotes",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ListProofVotes(ctx, f.proofID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if votes := result.([]sdk.ProofVote); len(votes) != 1 || votes[0].Comment != "Saw them" {
+					t.Errorf("votes = %+v, want the backer's", votes)
+				}
+			},
+		},
+		{
+			name: "RetractVote",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.RetractVote(ctx, f.proofID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if stats := result.(*sdk.VoteStats); stats.TotalVotes != 0 {
+					t.Errorf("stats = %+v, want no votes", stats)
+				}
+			},
+		},
+		{
+			name: "DeleteProof",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return nil, c.DeleteProof(ctx, f.proofID.String())
+			},
+		},
+
+		// Audit log and export
+		{
+			name: "ListGoalAuditLog",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ListGoalAuditLog(ctx, f.goalID.String(), 1, 50)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				page := result.(*sdk.AuditLogPage)
+				for _, entry := range page.Data {
+					if entry.Action == string(models.AuditActionWithdrawalRequested) && entry.EntityID == f.withdrawalID.String() {
+						return
+					}
+				}
+				t.Errorf("audit log = %+v, want the withdrawal request", page)
+			},
+		},
+		{
+			name: "ExportGoal",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ExportGoal(ctx, f.goalID.String(), "all")
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if rows := result.([]sdk.ExportRow); len(rows) < 4 {
+					t.Errorf("rows = %+v, want the contributions and the withdrawal", rows)
+				}
+			},
+		},
+
+		// Refund requests, reviewed by the owner
+		{
+			name: "RequestContributionRefund",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				request, err := c.RequestContributionRefund(ctx, f.c2.String(), "Paid twice")
+				if err == nil && request != nil {
+					setID(&f.approvedRequestID, request.ID)
+				}
+				return request, err
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				request := result.(*sdk.RefundRequest)
+				if request.ContributionID != f.c2.String() || request.Status != string(models.RefundRequestStatusPending) {
+					t.Errorf("request = %+v, want a pending request for %s", request, f.c2)
+				}
+			},
+		},
+		{
+			name: "RequestContributionRefund again",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				request, err := c.RequestContributionRefund(ctx, f.c3.String(), "Changed my mind")
+				if err == nil && request != nil {
+					setID(&f.deniedRequestID, request.ID)
+				}
+				return request, err
+			},
+		},
+		{
+			name: "GetGoalRefundRequests",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetGoalRefundRequests(ctx, f.goalID.String(), string(models.RefundRequestStatusPending))
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if requests := result.([]sdk.RefundRequest); len(requests) != 2 {
+					t.Errorf("requests = %+v, want both pending requests", requests)
+				}
+			},
+		},
+		{
+			name: "ApproveRefundRequest",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ApproveRefundRequest(ctx, f.approvedRequestID.String(), "Refunding")
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				request := result.(*sdk.RefundRequest)
+				if request.Status != string(models.RefundRequestStatusApproved) || request.RefundID == nil {
+					t.Errorf("request = %+v, want it approved with a refund", request)
+				}
+			},
+		},
+		{
+			name: "DenyRefundRequest",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.DenyRefundRequest(ctx, f.deniedRequestID.String(), "Past the window")
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				request := result.(*sdk.RefundRequest)
+				if request.Status != string(models.RefundRequestStatusDenied) || request.ReviewNote != "Past the window" {
+					t.Errorf("request = %+v, want it denied with the note", request)
+				}
+			},
+		},
+
+		// Refunds of the closed goal
+		{
+			name: "PreviewRefund",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.PreviewRefund(ctx, &sdk.InitiateRefundRequest{GoalID: f.closedGoalID.String(), RefundPercentage: 50, Reason: "Venue fell through"})
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				plan := result.(*sdk.RefundPlan)
+				if plan.TotalRefundAmount != 100_000 || len(plan.Disbursements) != 1 || plan.MissingSettlementAccounts != 0 {
+					t.Errorf("plan = %+v, want half of the 200000 contribution", plan)
+				}
+			},
+		},
+		{
+			name: "InitiateRefund",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				refund, err := c.InitiateRefund(ctx, &sdk.InitiateRefundRequest{GoalID: f.closedGoalID.String(), RefundPercentage: 50, Reason: "Venue fell through"})
+				if err == nil && refund != nil {
+					setID(&f.refundID, refund.ID)
+				}
+				return refund, err
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if refund := result.(*sdk.Refund); refund.TotalRefundAmount != 100_000 || len(refund.Disbursements) != 1 {
+					t.Errorf("refund = %+v, want one disbursement of 100000", refund)
+				}
+			},
+		},
+		{
+			name: "GetRefund",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetRefund(ctx, f.refundID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if refund := result.(*sdk.Refund); refund.ID != f.refundID.String() {
+					t.Errorf("refund = %+v, want %s", refund, f.refundID)
+				}
+			},
+		},
+		{
+			name: "GetGoalRefunds",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetGoalRefunds(ctx, f.closedGoalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if refunds := result.([]sdk.Refund); len(refunds) != 1 || refunds[0].ID != f.refundID.String() {
+					t.Errorf("refunds = %+v, want %s", refunds, f.refundID)
+				}
+			},
+		},
+		{
+			name: "GetContributionRefund",
+			as:   asBacker,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.GetContributionRefund(ctx, f.c4.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if refund := result.(*sdk.ContributionRefund); refund.RefundID != f.refundID.String() || refund.Amount != 100_000 {
+					t.Errorf("refund = %+v, want 100000 from %s", refund, f.refundID)
+				}
+			},
+		},
+
+		// A goal the owner creates, edits, has suspended, cancels and deletes
+		{
+			name: "CreateGoal",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				goal, err := c.CreateGoal(ctx, &sdk.CreateGoalRequest{
+					Title:                "Reading room",
+					Description:          "Chairs and lamps",
+					TargetAmount:         500_000,
+					Currency:             "NGN",
+					DepositBankName:      "Test Bank",
+					DepositAccountNumber: "0123456789",
+					DepositAccountName:   "Goal Owner",
+					Milestones:           []sdk.CreateMilestoneRequest{{Title: "Chairs", TargetAmount: 200_000}},
+				})
+				if err == nil && goal != nil {
+					setID(&f.newGoalID, goal.ID)
+				}
+				return goal, err
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				goal := result.(*sdk.Goal)
+				if goal.OwnerID != f.ownerID.String() || goal.Status != string(models.GoalStatusOpen) || goal.Slug == "" {
+					t.Errorf("goal = %+v, want an open goal of the owner's with a slug", goal)
+				}
+			},
+		},
+		{
+			name: "UpdateGoal",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				title := "Quiet reading room"
+				return c.UpdateGoal(ctx, f.newGoalID.String(), &sdk.UpdateGoalRequest{Title: &title})
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if goal := result.(*sdk.Goal); goal.Title != "Quiet reading room" {
+					t.Errorf("goal = %+v, want the new title", goal)
+				}
+			},
+		},
+		{
+			name: "CreateMilestone",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				created, err := c.CreateMilestone(ctx, f.newGoalID.String(), &sdk.CreateMilestoneRequest{Title: "Lamps", TargetAmount: 100_000, OrderIndex: 1})
+				if err == nil && created.Milestone != nil {
+					setID(&f.newMilestoneID, created.Milestone.ID)
+				}
+				return created, err
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				created := result.(*sdk.CreatedMilestone)
+				if created.Milestone == nil || created.Allocation.AllocatedAmount != 300_000 {
+					t.Errorf("created = %+v, want 300000 of the target allocated", created)
+				}
+			},
+		},
+		{
+			name: "GetGoalMilestones",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				milestones, err := c.GetGoalMilestones(ctx, f.newGoalID.String())
+				for _, milestone := range milestones {
+					if milestone.ID != f.newMilestoneID.String() {
+						setID(&f.milestoneID, milestone.ID)
+					}
+				}
+				return milestones, err
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if milestones := result.([]sdk.Milestone); len(milestones) != 2 {
+					t.Errorf("milestones = %+v, want both", milestones)
+				}
+			},
+		},
+		{
+			name: "UpdateMilestone",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				title := "Reading lamps"
+				return c.UpdateMilestone(ctx, f.newMilestoneID.String(), &sdk.UpdateMilestoneRequest{Title: &title})
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if updated := result.(*sdk.UpdatedMilestone); updated.Milestone.Title != "Reading lamps" {
+					t.Errorf("updated = %+v, want the new title", updated)
+				}
+			},
+		},
+		{
+			name: "CompleteMilestone",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.CompleteMilestone(ctx, f.milestoneID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				completed := result.(*sdk.CompletedMilestone)
+				if completed.Completed == nil || completed.Completed.Status != string(models.MilestoneStatusCompleted) {
+					t.Errorf("completed = %+v, want the milestone completed", completed)
+				}
+			},
+		},
+		{
+			name: "DeleteMilestone",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.DeleteMilestone(ctx, f.newMilestoneID.String(), false)
+			},
+		},
+		{
+			name: "SuspendGoal",
+			as:   asAdmin,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.SuspendGoal(ctx, f.newGoalID.String(), "Reported")
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if goal := result.(*sdk.Goal); goal.Status != string(models.GoalStatusSuspended) || goal.SuspensionReason != "Reported" {
+					t.Errorf("goal = %+v, want it suspended", goal)
+				}
+			},
+		},
+		{
+			name: "UnsuspendGoal",
+			as:   asAdmin,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.UnsuspendGoal(ctx, f.newGoalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if goal := result.(*sdk.Goal); goal.Status != string(models.GoalStatusOpen) {
+					t.Errorf("goal = %+v, want it open again", goal)
+				}
+			},
+		},
+		{
+			name: "CancelGoal",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.CancelGoal(ctx, f.newGoalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if goal := result.(*sdk.Goal); goal.Status != string(models.GoalStatusCancelled) {
+					t.Errorf("goal = %+v, want it cancelled", goal)
+				}
+			},
+		},
+		{
+			name: "DeleteGoal",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.DeleteGoal(ctx, f.newGoalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if goal := result.(*sdk.Goal); goal != nil {
+					t.Errorf("goal = %+v, want it deleted rather than archived", goal)
+				}
+			},
+		},
+
+		// Administration
+		{
+			name: "ListAllGoals",
+			as:   asAdmin,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.ListAllGoals(ctx, "", 1, 10)
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if page := result.(*sdk.PublicGoalsPage); page.Total != 2 {
+					t.Errorf("page = %+v, want the two remaining goals", page)
+				}
+			},
+		},
+		{
+			name: "VerifyGoalBalance",
+			as:   asAdmin,
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.VerifyGoalBalance(ctx, f.goalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if check := result.(*sdk.GoalBalanceCheck); check.GoalID != f.goalID.String() || check.Currency != "NGN" {
+					t.Errorf("check = %+v, want %s in NGN", check, f.goalID)
+				}
+			},
+		},
+		{
+			name: "CloseGoal",
+			call: func(ctx context.Context, c *sdk.GoalsClient, f *goalsFixture) (interface{}, error) {
+				return c.CloseGoal(ctx, f.goalID.String())
+			},
+			check: func(t *testing.T, f *goalsFixture, result interface{}) {
+				if goal := result.(*sdk.Goal); goal.Status != string(models.GoalStatusClosed) {
+					t.Errorf("goal = %+v, want it closed", goal)
+				}
+			},
+		},
+	}
+}
+
+// routeProbe serves every route r has with an empty answer, so an SDK request succeeds
+// exactly when its method and path match one of r's routes
+func routeProbe(r *gin.Engine) http.Handler {
+	probe := gin.New()
+	for _, route := range r.Routes() {
+		probe.Handle(route.Method, route.Path, func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/json", []byte("null"))
+		})
+	}
+	return probe
+}
+
+// TestSDKRoutes checks every SDK method requests a route the service has; unlike
+// TestSDKContract it needs no database
+func TestSDKRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	cfg := &config.Config{}
+	cfg.Proofs.MediaDir = t.TempDir()
+	SetupRoutes(r, Services{}, cfg)
+	server := httptest.NewServer(routeProbe(r))
+	t.Cleanup(server.Close)
+
+	client := sdk.NewGoalsClient(server.URL)
+	f := &goalsFixture{slug: "community-library", inviteToken: "invite-token"}
+	for _, tc := range sdkContracts() {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.call(context.Background(), client, f); err != nil {
+				t.Errorf("no route for the request: %v", err)
+			}
+		})
+	}
+}
+
+// TestSDKContract calls every SDK method against the real routes over a seeded database,
+// as the fixture's users signed in through the gateway. users-, payments- and
+// ledger-service are stood in for by stubInternalServices.
+func TestSDKContract(t *testing.T) {
+	db := testdb.Open(t)
+	repo := repository.NewRepository(db)
+	downstream := stubInternalServices(t)
+	mediaDir := t.TempDir()
+
+	audit := service.NewAuditService(repository.NewAuditLogRepository(db), repo)
+	users := service.NewUsersClient(downstream.URL, time.Minute)
+	payments := service.NewPaymentsClient(downstream.URL)
+	invites := service.NewInviteTokens("contract-invite-secret", time.Hour)
+	outbox := service.NewOutboxPublisher(repo)
+	goals := service.NewGoalService(repo, audit, users, payments, invites)
+	contributions := service.NewContributionService(repo, users, invites, nil, time.Hour, 0)
+	balanceCheck := service.NewBalanceCheckService(repo, service.NewLedgerClient(downstream.URL), false, 0)
+	media, err := storage.NewLocalStore(mediaDir, "/api/v1/goals/proofs/media")
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	f := seedGoals(t, repo, goals)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.IdentityGuard(contractIdentitySecret))
+	cfg := &config.Config{}
+	cfg.Proofs.MediaDir = mediaDir
+	SetupRoutes(r, Services{
+		Goals:         goals,
+		Trending:      service.NewTrendingService(repo, repository.NewTrendingRepository(db), service.TrendingWeights{Window: 7 * 24 * time.Hour, HalfLife: 24 * time.Hour, AmountWeight: 1, ContributorWeight: 1}, 10),
+		Contributions: contributions,
+		Withdrawals:   service.NewWithdrawalService(repo, balanceCheck, audit, users, 0),
+		Proofs:        service.NewProofService(repo, media),
+		Votes:         service.NewVoteService(repo),
+		// No grace window, so refund requests wait for the owner's review
+		Refunds:       service.NewRefundService(repo, users, audit, 0),
+		Receipts:      service.NewReceiptService(repo, users, payments),
+		Comments:      service.NewCommentService(repository.NewCommentRepository(db), repo, outbox, users),
+		Updates:       service.NewGoalUpdateService(repository.NewGoalUpdateRepository(db), repo, outbox),
+		Watches:       service.NewWatchService(repository.NewGoalWatchRepository(db), repo),
+		Collaborators: service.NewCollaboratorService(repo),
+		Exports:       service.NewExportService(repo, users),
+		Audit:         audit,
+		Stats:         service.NewGoalStatsService(repo, 0),
+		PlatformStats: service.NewPlatformStatsService(repo, 0),
+		Recurring:     service.NewRecurringContributionService(repository.NewRecurringContributionRepository(db), repo, contributions, payments, outbox, 3),
+		Moderation:    service.NewModerationService(repo, goals),
+		DataQuality:   service.NewDataQualityService(repository.NewDataQualityRepository(db)),
+		BalanceCheck:  balanceCheck,
+	}, cfg)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	clients := map[int]*sdk.GoalsClient{
+		asOwner:    sdk.NewGoalsClient(server.URL, sdk.WithIdentity(contractIdentitySecret, f.ownerID.String(), "user", true)),
+		asBacker:   sdk.NewGoalsClient(server.URL, sdk.WithIdentity(contractIdentitySecret, f.backerID.String(), "user", true)),
+		asApprover: sdk.NewGoalsClient(server.URL, sdk.WithIdentity(contractIdentitySecret, f.approverID.String(), "user", true)),
+		asAdmin:    sdk.NewGoalsClient(server.URL, sdk.WithIdentity(contractIdentitySecret, f.adminID.String(), "user,admin", true)),
+	}
+	for _, tc := range sdkContracts() {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.call(context.Background(), clients[tc.as], f)
+			if err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			if tc.check != nil {
+				tc.check(t, f, result)
+			}
+		})
+	}
+}
+
+// seedGoals creates the owner's open goal, shared with an approver and backed three
+// times, and their closed goal with one contribution from the same backer
+func seedGoals(t *testing.T, repo *repository.Repository, goals *service.GoalService) *goalsFixture {
+	t.Helper()
+	ctx := context.Background()
+	f := &goalsFixture{ownerID: uuid.New(), backerID: uuid.New(), approverID: uuid.New(), adminID: uuid.New(), otherID: uuid.New()}
+
+	goal, err := goals.CreateGoal(ctx, f.ownerID, dto.CreateGoalRequest{
+		Title:                "Community library",
+		Description:          "Shelves and books for the library",
+		TargetAmount:         1_000_000,
+		Currency:             "NGN",
+		DepositBankName:      "Test Bank",
+		DepositAccountNumber: "0123456789",
+		DepositAccountName:   "Goal Owner",
+	})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	goal.RequiredApprovals = 2
+	if err := repo.Goal.UpdateGoal(ctx, goal); err != nil {
+		t.Fatalf("UpdateGoal: %v", err)
+	}
+	f.goalID, f.slug = goal.ID, *goal.Slug
+	if _, err := repo.Collaborator.AddCollaborator(ctx, &models.GoalCollaborator{GoalID: goal.ID, UserID: f.approverID, Role: models.CollaboratorRoleApprover, InvitedBy: f.ownerID, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddCollaborator: %v", err)
+	}
+
+	c1 := seedContribution(t, repo, goal, f.backerID, 300_000)
+	c1.AuthorizationCode = "AUTH_contract"
+	if err := repo.Contribution.UpdateContribution(ctx, c1); err != nil {
+		t.Fatalf("UpdateContribution: %v", err)
+	}
+	f.c1 = c1.ID
+	f.paymentReference = "ref-" + c1.PaymentID.String()
+	f.c2 = seedContribution(t, repo, goal, f.backerID, 100_000).ID
+	f.c3 = seedContribution(t, repo, goal, f.backerID, 100_000).ID
+
+	closed, err := goals.CreateGoal(ctx, f.ownerID, dto.CreateGoalRequest{
+		Title:                "Book fair",
+		TargetAmount:         400_000,
+		Currency:             "NGN",
+		DepositBankName:      "Test Bank",
+		DepositAccountNumber: "0123456789",
+		DepositAccountName:   "Goal Owner",
+	})
+	if err != nil {
+		t.Fatalf("CreateGoal: %v", err)
+	}
+	f.closedGoalID = closed.ID
+	f.c4 = seedContribution(t, repo, closed, f.backerID, 200_000).ID
+	closed.Status = models.GoalStatusClosed
+	if err := repo.Goal.UpdateGoal(ctx, closed); err != nil {
+		t.Fatalf("UpdateGoal: %v", err)
+	}
+	return f
+}
+
+// seedContribution records a confirmed contribution of amount to goal by userID
+func seedContribution(t *testing.T, repo *repository.Repository, goal *models.Goal, userID uuid.UUID, amount int64) *models.Contribution {
+	t.Helper()
+	paymentID := uuid.New()
+	contribution := &models.Contribution{
+		GoalID:    goal.ID,
+		UserID:    userID,
+		PaymentID: &paymentID,
+		Amount:    amount,
+		Currency:  goal.Currency,
+		Status:    models.ContributionStatusConfirmed,
+		FeeMode:   models.FeeModeAbsorb,
+		NetAmount: amount,
+	}
+	if err := repo.Contribution.CreateContribution(context.Background(), contribution); err != nil {
+		t.Fatalf("CreateContribution: %v", err)
+	}
+	return contribution
+}
+
+// stubInternalServices stands in for the internal endpoints of users-, payments- and
+// ledger-service the goals routes call: every user has a name, an email, a settlement
+// account and verified KYC, every payment was made an hour ago, and every goal's ledger
+// balance is empty
+func stubInternalServices(t *testing.T) *httptest.Server {
+	t.Helper()
+	usersByID := func(r *http.Request, user func(id string) map[string]interface{}) map[string]interface{} {
+		users := []map[string]interface{}{}
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			users = append(users, user(id))
+		}
+		return map[string]interface{}{"users": users}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch path := r.URL.Path; {
+		case path == "/internal/users/display-names":
+			body = usersByID(r, func(id string) map[string]interface{} {
+				return map[string]interface{}{"id": id, "username": "user-" + id[:8], "first_name": "Test"}
+			})
+		case path == "/internal/users/contacts":
+			body = usersByID(r, func(id string) map[string]interface{} {
+				return map[string]interface{}{"id": id, "first_name": "Test", "last_name": "User", "email": id[:8] + "@example.com"}
+			})
+		case path == "/internal/users/settlement-accounts":
+			body = usersByID(r, func(id string) map[string]interface{} {
+				return map[string]interface{}{"id": id, "settlement_bank_name": "Test Bank", "settlement_account_number": "0123456789", "settlement_account_name": "Test User"}
+			})
+		case strings.HasPrefix(path, "/internal/users/"):
+			body = map[string]interface{}{"id": strings.TrimPrefix(path, "/internal/users/"), "kyc_verified": true}
+		case strings.HasPrefix(path, "/internal/payments/"):
+			body = map[string]interface{}{"reference": "ref-" + strings.TrimPrefix(path, "/internal/payments/"), "paid_at": time.Now().Add(-time.Hour).Format(time.RFC3339)}
+		case strings.HasPrefix(path, "/internal/ledger/goals/"):
+			body = map[string]interface{}{"balance": 0, "snapshot_balance": nil, "entries": []interface{}{}}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
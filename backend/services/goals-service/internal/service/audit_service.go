@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/shared/logger"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/gofund/shared/requestid"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Entity types recorded in the audit log
+const (
+	auditEntityGoal          = "goal"
+	auditEntityWithdrawal    = "withdrawal"
+	auditEntityRefund        = "refund"
+	auditEntityRefundRequest = "refund_request"
+	auditEntityMilestone     = "milestone"
+)
+
+const maxAuditPageSize = 100
+
+type clientIPKey struct{}
+
+// WithClientIP returns a copy of ctx carrying the caller's IP address for the audit log
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// clientIPFromContext returns the caller's IP address carried by ctx, or ""
+func clientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+// AuditEntry is a sensitive action to record with AuditService.Record
+type AuditEntry struct {
+	GoalID     uuid.UUID
+	ActorID    uuid.UUID
+	Action     models.AuditAction
+	EntityType string
+	EntityID   uuid.UUID
+	Before     map[string]interface{}
+	After      map[string]interface{}
+}
+
+// AuditService keeps the audit trail of sensitive goal and financial actions
+type AuditService struct {
+	audits *repository.AuditLogRepository
+	repo   *repository.Repository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(audits *repository.AuditLogRepository, repo *repository.Repository) *AuditService {
+	return &AuditService{audits: audits, repo: repo}
+}
+
+// Record appends an entry to the audit log, stamped with the caller's IP address and
+// request ID from ctx. The action it records has already happened, so a failed write is
+// logged and counted rather than returned.
+func (s *AuditService) Record(ctx context.Context, entry AuditEntry) {
+	if s == nil {
+		return
+	}
+
+	if err := s.audits.CreateAuditLog(ctx, newAuditLog(ctx, entry)); err != nil {
+		logger.Printf(ctx, "Failed to record audit log %s for %s %s: %v", entry.Action, entry.EntityType, entry.EntityID, err)
+		metrics.IncrementCounter("goals.audit.write_failed", "action:"+string(entry.Action))
+		return
+	}
+	metrics.IncrementCounter("goals.audit.recorded", "action:"+string(entry.Action))
+}
+
+// recordAudit appends an entry to the audit log in tx, so the entry commits or rolls back
+// with the action it records
+func recordAudit(ctx context.Context, tx *repository.Repository, entry AuditEntry) error {
+	if err := tx.Audit.CreateAuditLog(ctx, newAuditLog(ctx, entry)); err != nil {
+		return err
+	}
+	metrics.IncrementCounter("goals.audit.recorded", "action:"+string(entry.Action))
+	return nil
+}
+
+// newAuditLog builds the audit log row for entry, stamped with the caller's IP address
+// and request ID from ctx
+func newAuditLog(ctx context.Context, entry AuditEntry) *models.AuditLog {
+	return &models.AuditLog{
+		GoalID:     entry.GoalID,
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Before:     entry.Before,
+		After:      entry.After,
+		IP:         clientIPFromContext(ctx),
+		RequestID:  requestid.FromContext(ctx),
+	}
+}
+
+// ListGoalAuditLog returns a page of a goal's audit log, newest first. Only the goal owner
+// and admins may read it.
+func (s *AuditService) ListGoalAuditLog(ctx context.Context, goalID, userID uuid.UUID, isAdmin bool, page, pageSize int) ([]models.AuditLog, int64, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, ErrGoalNotFound
+		}
+		return nil, 0, err
+	}
+	if goal.OwnerID != userID && !isAdmin {
+		return nil, 0, ErrUnauthorized
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > maxAuditPageSize {
+		pageSize = maxAuditPageSize
+	}
+	offset := (page - 1) * pageSize
+	return s.audits.GetAuditLogsByGoalID(ctx, goalID, pageSize, offset)
+}
+
+// bankDetailsSnapshot captures a goal's deposit bank details for the audit log
+func bankDetailsSnapshot(goal *models.Goal) map[string]interface{} {
+	return map[string]interface{}{
+		"bank_name":      goal.DepositBankName,
+		"account_number": goal.DepositAccountNumber,
+		"account_name":   goal.DepositAccountName,
+	}
+}
+
+// goalStatusSnapshot captures a goal's status for the audit log
+func goalStatusSnapshot(status models.GoalStatus) map[string]interface{} {
+	return map[string]interface{}{"status": status}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrLedgerBalanceMismatch = apperrors.Conflict("ledger_balance_mismatch", "goal balance does not match the ledger; withdrawals are paused until it is reconciled")
+
+// BalanceCheckService compares a goal's balance as goals-service computes it (confirmed
+// contributions less absorbed platform fees, completed refunds and withdrawals) with the
+// ledger's goal account
+type BalanceCheckService struct {
+	repo   *repository.Repository
+	ledger *LedgerClient
+	// blockWithdrawals refuses withdrawals while the balances differ by more than threshold (kobo)
+	blockWithdrawals bool
+	threshold        int64
+}
+
+// NewBalanceCheckService creates a new balance check service
+func NewBalanceCheckService(repo *repository.Repository, ledger *LedgerClient, blockWithdrawals bool, threshold int64) *BalanceCheckService {
+	return &BalanceCheckService{
+		repo:             repo,
+		ledger:           ledger,
+		blockWithdrawals: blockWithdrawals,
+		threshold:        threshold,
+	}
+}
+
+// VerifyGoalBalance fetches both balances and lists the contributions they disagree on
+func (s *BalanceCheckService) VerifyGoalBalance(ctx context.Context, goalID uuid.UUID) (*dto.GoalBalanceCheck, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	return s.verify(ctx, goal)
+}
+
+// CheckWithdrawal refuses a withdrawal when blocking is enabled and the goal's balance
+// differs from the ledger's by more than the threshold. An unreachable ledger does not
+// block withdrawals.
+func (s *BalanceCheckService) CheckWithdrawal(ctx context.Context, goal *models.Goal) error {
+	if !s.blockWithdrawals {
+		return nil
+	}
+
+	check, err := s.verify(ctx, goal)
+	if err != nil {
+		log.Printf("Skipping ledger balance check for goal %s: %v", goal.ID, err)
+		return nil
+	}
+
+	delta := check.Delta
+	if delta < 0 {
+		delta = -delta
+	}
+	if delta > s.threshold {
+		metrics.IncrementCounter("goals.withdrawal.ledger_mismatch_blocked", "goal_id:"+goal.ID.String())
+		return ErrLedgerBalanceMismatch
+	}
+	return nil
+}
+
+func (s *BalanceCheckService) verify(ctx context.Context, goal *models.Goal) (*dto.GoalBalanceCheck, error) {
+	ledgerBalance, err := s.ledger.GetGoalBalance(goal.ID, goal.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	contributions, err := s.repo.Contribution.GetConfirmedContributionsByGoalID(ctx, goal.ID)
+	if err != nil {
+		return nil, err
+	}
+	refunded, err := s.repo.Goal.GetCompletedRefundTotals(ctx, goal.ID)
+	if err != nil {
+		return nil, err
+	}
+	withdrawn, err := s.repo.Goal.GetTotalCompletedWithdrawals(ctx, goal.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &dto.GoalBalanceCheck{
+		GoalID:                goal.ID,
+		Currency:              goal.Currency,
+		LedgerBalance:         ledgerBalance.Balance,
+		LedgerSnapshotBalance: ledgerBalance.SnapshotBalance,
+		Discrepancies:         []dto.BalanceDiscrepancy{},
+		CheckedAt:             time.Now(),
+	}
+
+	// Net ledger amount and entries per contribution
+	type ledgerContribution struct {
+		net      int64
+		entryIDs []uuid.UUID
+	}
+	byContribution := make(map[uuid.UUID]*ledgerContribution)
+	for _, entry := range ledgerBalance.Entries {
+		if entry.ContributionID == nil {
+			if entry.EntryType == string(models.EntryTypeCredit) {
+				check.Discrepancies = append(check.Discrepancies, dto.BalanceDiscrepancy{
+					Kind:           dto.DiscrepancyUnlinkedEntry,
+					LedgerAmount:   entry.Amount,
+					LedgerEntryIDs: []uuid.UUID{entry.ID},
+				})
+			}
+			continue
+		}
+
+		lc, ok := byContribution[*entry.ContributionID]
+		if !ok {
+			lc = &ledgerContribution{}
+			byContribution[*entry.ContributionID] = lc
+		}
+		if entry.EntryType == string(models.EntryTypeCredit) {
+			lc.net += entry.Amount
+		} else {
+			lc.net -= entry.Amount
+		}
+		lc.entryIDs = append(lc.entryIDs, entry.ID)
+	}
+
+	for _, contribution := range contributions {
+		contributionID := contribution.ID
+		expected := contribution.GoalAmount() - refunded[contribution.ID]
+		check.GoalsBalance += expected
+
+		lc, ok := byContribution[contribution.ID]
+		delete(byContribution, contribution.ID)
+		switch {
+		case !ok:
+			check.Discrepancies = append(check.Discrepancies, dto.BalanceDiscrepancy{
+				Kind:           dto.DiscrepancyMissingFromLedger,
+				ContributionID: &contributionID,
+				GoalsAmount:    expected,
+				LedgerEntryIDs: []uuid.UUID{},
+			})
+		case lc.net != expected:
+			check.Discrepancies = append(check.Discrepancies, dto.BalanceDiscrepancy{
+				Kind:           dto.DiscrepancyAmountMismatch,
+				ContributionID: &contributionID,
+				GoalsAmount:    expected,
+				LedgerAmount:   lc.net,
+				LedgerEntryIDs: lc.entryIDs,
+			})
+		}
+	}
+	check.GoalsBalance -= withdrawn
+
+	// Whatever is left is held by the ledger for contributions goals-service has not confirmed
+	for contributionID, lc := range byContribution {
+		if lc.net == 0 {
+			continue
+		}
+		contributionID := contributionID
+		check.Discrepancies = append(check.Discrepancies, dto.BalanceDiscrepancy{
+			Kind:           dto.DiscrepancyMissingFromGoals,
+			ContributionID: &contributionID,
+			LedgerAmount:   lc.net,
+			LedgerEntryIDs: lc.entryIDs,
+		})
+	}
+
+	check.Delta = check.GoalsBalance - check.LedgerBalance
+	check.Consistent = check.Delta == 0 && len(check.Discrepancies) == 0
+	if !check.Consistent {
+		metrics.IncrementCounter("ledger.reconciliation.mismatch", "goal_id:"+goal.ID.String())
+		log.Printf("Goal %s balance differs from the ledger: goals %d, ledger %d (delta %d, %d discrepancies)",
+			goal.ID, check.GoalsBalance, check.LedgerBalance, check.Delta, len(check.Discrepancies))
+	}
+
+	return check, nil
+}
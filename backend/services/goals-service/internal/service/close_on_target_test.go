@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// closeOnTargetGoal creates an open close-on-target goal with a target of 100,000
+func closeOnTargetGoal(t *testing.T, repo *repository.Repository) *models.Goal {
+	t.Helper()
+	return createTestGoal(t, repo, uuid.New(), func(g *models.Goal) {
+		g.TargetAmount = 100_000
+		g.CloseOnTarget = true
+	})
+}
+
+// createIntent starts a contribution of amount to goal through the service
+func createIntent(t *testing.T, s *ContributionService, goal *models.Goal, amount int64) *models.Contribution {
+	t.Helper()
+	intent, err := s.CreateContribution(context.Background(), uuid.New(), dto.CreateContributionRequest{GoalID: goal.ID, Amount: amount})
+	if err != nil {
+		t.Fatalf("CreateContribution(%d): %v", amount, err)
+	}
+	return intent
+}
+
+// goalStatus reads goal's status from the primary
+func goalStatus(t *testing.T, repo *repository.Repository, goal *models.Goal) models.GoalStatus {
+	t.Helper()
+	stored, err := repo.Primary().Goal.GetGoalByIDSimple(context.Background(), goal.ID)
+	if err != nil {
+		t.Fatalf("GetGoalByIDSimple: %v", err)
+	}
+	return stored.Status
+}
+
+func TestCloseOnTargetCrossingClosesGoal(t *testing.T) {
+	repo := newTestRepo(t)
+	s := NewContributionService(repo, nil, nil, nil, 0, 0)
+	ctx := context.Background()
+	goal := closeOnTargetGoal(t, repo)
+
+	below := createIntent(t, s, goal, 60_000)
+	crossing := createIntent(t, s, goal, 50_000)
+
+	closed, err := s.ConfirmContribution(ctx, below.ID, uuid.New())
+	if err != nil {
+		t.Fatalf("ConfirmContribution below the target: %v", err)
+	}
+	if closed || goalStatus(t, repo, goal) != models.GoalStatusOpen {
+		t.Fatalf("a confirmation below the target closed the goal")
+	}
+
+	closed, err = s.ConfirmContribution(ctx, crossing.ID, uuid.New())
+	if err != nil {
+		t.Fatalf("ConfirmContribution crossing the target: %v", err)
+	}
+	if !closed {
+		t.Error("the crossing confirmation did not report closing the goal")
+	}
+	if status := goalStatus(t, repo, goal); status != models.GoalStatusClosed {
+		t.Errorf("goal status = %s, want %s", status, models.GoalStatusClosed)
+	}
+	// The crossing contribution is confirmed in full, over-funding the goal
+	total, err := repo.Goal.GetTotalConfirmedContributions(ctx, goal.ID)
+	if err != nil {
+		t.Fatalf("GetTotalConfirmedContributions: %v", err)
+	}
+	if total != 110_000 {
+		t.Errorf("confirmed total = %d, want 110000", total)
+	}
+	assertOutbox(t, repo, map[string]int{"ContributionConfirmed": 2, "GoalClosedEarly": 1, "GoalFunded": 1})
+}
+
+func TestCloseOnTargetRejectsLaterIntents(t *testing.T) {
+	repo := newTestRepo(t)
+	s := NewContributionService(repo, nil, nil, nil, 0, 0)
+	goal := closeOnTargetGoal(t, repo)
+
+	intent := createIntent(t, s, goal, 100_000)
+	if _, err := s.ConfirmContribution(context.Background(), intent.ID, uuid.New()); err != nil {
+		t.Fatalf("ConfirmContribution: %v", err)
+	}
+
+	_, err := s.CreateContribution(context.Background(), uuid.New(), dto.CreateContributionRequest{GoalID: goal.ID, Amount: 5_000})
+	if code := errorCode(err); code != "goal_target_reached" {
+		t.Fatalf("intent after the target: err = %v, want goal_target_reached", err)
+	}
+}
+
+func TestCloseOnTargetRejectsIntentsOnceReachedWhileOpen(t *testing.T) {
+	repo := newTestRepo(t)
+	s := NewContributionService(repo, nil, nil, nil, 0, 0)
+	goal := closeOnTargetGoal(t, repo)
+
+	// Contributions confirmed before the option was switched on already reach the target
+	createTestContribution(t, repo, goal, uuid.New(), 100_000)
+
+	_, err := s.CreateContribution(context.Background(), uuid.New(), dto.CreateContributionRequest{GoalID: goal.ID, Amount: 5_000})
+	if code := errorCode(err); code != "goal_target_reached" {
+		t.Fatalf("err = %v, want goal_target_reached", err)
+	}
+}
+
+func TestCloseOnTargetInFlightPaymentStillConfirms(t *testing.T) {
+	repo := newTestRepo(t)
+	s := NewContributionService(repo, nil, nil, nil, 0, 0)
+	ctx := context.Background()
+	goal := closeOnTargetGoal(t, repo)
+
+	crossing := createIntent(t, s, goal, 100_000)
+	inFlight := createIntent(t, s, goal, 30_000)
+
+	if closed, err := s.ConfirmContribution(ctx, crossing.ID, uuid.New()); err != nil || !closed {
+		t.Fatalf("ConfirmContribution crossing the target: closed %v, err %v", closed, err)
+	}
+
+	// The payment for the other intent was already under way and lands after the close
+	closed, err := s.ConfirmContribution(ctx, inFlight.ID, uuid.New())
+	if err != nil {
+		t.Fatalf("ConfirmContribution in flight: %v", err)
+	}
+	if closed {
+		t.Error("the in-flight confirmation reported closing an already closed goal")
+	}
+	confirmed, err := repo.Primary().Contribution.GetContributionByID(ctx, inFlight.ID)
+	if err != nil {
+		t.Fatalf("GetContributionByID: %v", err)
+	}
+	if confirmed.Status != models.ContributionStatusConfirmed {
+		t.Errorf("in-flight contribution status = %s, want %s", confirmed.Status, models.ContributionStatusConfirmed)
+	}
+	total, err := repo.Goal.GetTotalConfirmedContributions(ctx, goal.ID)
+	if err != nil {
+		t.Fatalf("GetTotalConfirmedContributions: %v", err)
+	}
+	if total != 130_000 {
+		t.Errorf("confirmed total = %d, want the over-funded 130000", total)
+	}
+	if status := goalStatus(t, repo, goal); status != models.GoalStatusClosed {
+		t.Errorf("goal status = %s, want %s", status, models.GoalStatusClosed)
+	}
+	// The goal closes and is funded once
+	assertOutbox(t, repo, map[string]int{"ContributionConfirmed": 2, "GoalClosedEarly": 1, "GoalFunded": 1})
+}
+
+func TestCrossingTargetLeavesOrdinaryGoalOpen(t *testing.T) {
+	repo := newTestRepo(t)
+	s := NewContributionService(repo, nil, nil, nil, 0, 0)
+	goal := createTestGoal(t, repo, uuid.New(), func(g *models.Goal) { g.TargetAmount = 100_000 })
+
+	intent := createIntent(t, s, goal, 120_000)
+	closed, err := s.ConfirmContribution(context.Background(), intent.ID, uuid.New())
+	if err != nil {
+		t.Fatalf("ConfirmContribution: %v", err)
+	}
+	if closed || goalStatus(t, repo, goal) != models.GoalStatusOpen {
+		t.Fatal("crossing the target closed a goal without CloseOnTarget")
+	}
+	createIntent(t, s, goal, 10_000)
+}
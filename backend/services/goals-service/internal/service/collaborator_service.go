@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Collaborator and withdrawal approval errors
+var (
+	ErrCollaboratorNotFound          = apperrors.NotFound("collaborator_not_found", "collaborator not found")
+	ErrAlreadyCollaborator           = apperrors.Conflict("already_collaborator", "user already collaborates on this goal")
+	ErrInvalidCollaboratorRole       = apperrors.Validation("invalid_collaborator_role", "role must be owner or approver")
+	ErrWithdrawalNotFound            = apperrors.NotFound("withdrawal_not_found", "withdrawal not found")
+	ErrWithdrawalNotAwaitingApproval = apperrors.Conflict("withdrawal_not_awaiting_approval", "withdrawal is not awaiting approval")
+	ErrAlreadyApproved               = apperrors.Conflict("already_approved", "you have already approved this withdrawal")
+)
+
+// CollaboratorService manages the people who organise a goal alongside its owner and
+// approve its withdrawals
+type CollaboratorService struct {
+	repo *repository.Repository
+}
+
+// NewCollaboratorService creates a new collaborator service
+func NewCollaboratorService(repo *repository.Repository) *CollaboratorService {
+	return &CollaboratorService{repo: repo}
+}
+
+// ListCollaborators returns a goal's collaborators. Only the goal owner and its
+// collaborators may see them.
+func (s *CollaboratorService) ListCollaborators(ctx context.Context, goalID, userID uuid.UUID) ([]models.GoalCollaborator, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+
+	if goal.OwnerID != userID {
+		if _, err := s.repo.Collaborator.GetCollaborator(ctx, goalID, userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrUnauthorized
+			}
+			return nil, err
+		}
+	}
+
+	return s.repo.Collaborator.GetCollaboratorsByGoalID(ctx, goalID)
+}
+
+// AddCollaborator adds a collaborator to a goal. Only the goal owner may add collaborators.
+func (s *CollaboratorService) AddCollaborator(ctx context.Context, goalID, userID uuid.UUID, req dto.AddCollaboratorRequest) (*models.GoalCollaborator, error) {
+	if req.Role != models.CollaboratorRoleOwner && req.Role != models.CollaboratorRoleApprover {
+		return nil, ErrInvalidCollaboratorRole
+	}
+	if req.UserID == uuid.Nil {
+		return nil, apperrors.Validation("invalid_user_id", "user ID is required")
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if goal.OwnerID != userID {
+		return nil, ErrUnauthorized
+	}
+	if req.UserID == goal.OwnerID {
+		return nil, apperrors.Validation("owner_not_collaborator", "the goal owner is already a collaborator")
+	}
+
+	collaborator := &models.GoalCollaborator{
+		GoalID:    goalID,
+		UserID:    req.UserID,
+		Role:      req.Role,
+		InvitedBy: userID,
+	}
+	added, err := s.repo.Collaborator.AddCollaborator(ctx, collaborator)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, ErrAlreadyCollaborator
+	}
+
+	metrics.IncrementCounter("goals.collaborator.added", "role:"+string(req.Role))
+	return collaborator, nil
+}
+
+// RemoveCollaborator removes a collaborator from a goal. Only the goal owner may remove
+// collaborators, and not so many that the goal's required approvals can no longer be met.
+func (s *CollaboratorService) RemoveCollaborator(ctx context.Context, goalID, userID, collaboratorID uuid.UUID) error {
+	return s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		goal, err := tx.Goal.GetGoalByIDForUpdate(ctx, goalID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrGoalNotFound
+			}
+			return err
+		}
+		if goal.OwnerID != userID {
+			return ErrUnauthorized
+		}
+
+		removed, err := tx.Collaborator.RemoveCollaborator(ctx, goalID, collaboratorID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return ErrCollaboratorNotFound
+		}
+
+		remaining, err := tx.Collaborator.CountCollaborators(ctx, goalID)
+		if err != nil {
+			return err
+		}
+		if err := validateRequiredApprovals(goal.RequiredApprovals, remaining); err != nil {
+			return apperrors.Conflict("required_approvals_unreachable",
+				fmt.Sprintf("goal requires %d approvals; lower required approvals before removing this collaborator", goal.RequiredApprovals))
+		}
+
+		metrics.IncrementCounter("goals.collaborator.removed")
+		return nil
+	})
+}
+
+// validateRequiredApprovals checks a goal's required approvals against the number of
+// people who can approve: its owner plus its collaborators
+func validateRequiredApprovals(required int, collaborators int64) error {
+	approvers := collaborators + 1
+	if required < 1 || int64(required) > approvers {
+		return apperrors.Validation("invalid_required_approvals",
+			fmt.Sprintf("required approvals must be between 1 and %d (the owner plus collaborators)", approvers))
+	}
+	return nil
+}
+
+// goalApproverIDs returns everyone who may approve a goal's withdrawals: the owner and
+// every collaborator
+func goalApproverIDs(ctx context.Context, repo *repository.Repository, goal *models.Goal) ([]uuid.UUID, error) {
+	collaborators, err := repo.Collaborator.GetCollaboratorsByGoalID(ctx, goal.ID)
+	if err != nil {
+		return nil, err
+	}
+	approvers := make([]uuid.UUID, 0, len(collaborators)+1)
+	approvers = append(approvers, goal.OwnerID)
+	for _, collaborator := range collaborators {
+		approvers = append(approvers, collaborator.UserID)
+	}
+	return approvers, nil
+}
+
+// canRequestWithdrawal reports whether userID may request withdrawals from a goal: its
+// owner or a collaborator with the owner role
+func canRequestWithdrawal(ctx context.Context, repo *repository.Repository, goal *models.Goal, userID uuid.UUID) (bool, error) {
+	if goal.OwnerID == userID {
+		return true, nil
+	}
+	collaborator, err := repo.Collaborator.GetCollaborator(ctx, goal.ID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return collaborator.Role == models.CollaboratorRoleOwner, nil
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/messaging"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxCommentLength   = 2000
+	commentExcerptSize = 140
+)
+
+var (
+	ErrCommentNotFound     = apperrors.NotFound("comment_not_found", "comment not found")
+	ErrInvalidComment      = apperrors.Validation("invalid_comment", "comment must be between 1 and 2000 characters")
+	ErrNestedReply         = apperrors.Validation("nested_reply", "replies can only be made to top-level comments")
+	ErrCommentGoalMismatch = apperrors.Validation("comment_goal_mismatch", "comment does not belong to this goal")
+)
+
+// CommentService handles comment threads on goals
+type CommentService struct {
+	comments    *repository.CommentRepository
+	repo        *repository.Repository
+	publisher   messaging.Publisher
+	usersClient *UsersClient
+}
+
+// NewCommentService creates a new comment service
+func NewCommentService(comments *repository.CommentRepository, repo *repository.Repository, publisher messaging.Publisher, usersClient *UsersClient) *CommentService {
+	return &CommentService{comments: comments, repo: repo, publisher: publisher, usersClient: usersClient}
+}
+
+// ListComments returns a page of a goal's top-level comments, newest first, each with its
+// replies nested oldest first
+func (s *CommentService) ListComments(ctx context.Context, goalID, viewerID uuid.UUID, page, pageSize int) (*dto.CommentThreads, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > maxFeedPageSize {
+		pageSize = maxFeedPageSize
+	}
+
+	if _, err := s.visibleGoal(ctx, goalID, viewerID); err != nil {
+		return nil, err
+	}
+
+	topLevel, total, err := s.comments.GetTopLevelComments(ctx, goalID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	parentIDs := make([]uuid.UUID, len(topLevel))
+	for i := range topLevel {
+		parentIDs[i] = topLevel[i].ID
+	}
+	replies, err := s.comments.GetReplies(ctx, parentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(topLevel)+len(replies))
+	for _, comment := range append(topLevel, replies...) {
+		if !comment.IsDeleted {
+			userIDs = append(userIDs, comment.UserID)
+		}
+	}
+	var names map[uuid.UUID]string
+	if s.usersClient != nil {
+		names = s.usersClient.DisplayNames(userIDs)
+	}
+
+	repliesByParent := make(map[uuid.UUID][]dto.CommentItem, len(topLevel))
+	for i := range replies {
+		parentID := *replies[i].ParentID
+		repliesByParent[parentID] = append(repliesByParent[parentID], commentItem(&replies[i], names))
+	}
+
+	threads := &dto.CommentThreads{
+		Items:    make([]dto.CommentItem, 0, len(topLevel)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for i := range topLevel {
+		item := commentItem(&topLevel[i], names)
+		if thread, ok := repliesByParent[topLevel[i].ID]; ok {
+			item.Replies = thread
+		}
+		threads.Items = append(threads.Items, item)
+	}
+
+	return threads, nil
+}
+
+// commentItem presents a comment, replacing a deleted one with a placeholder
+func commentItem(comment *models.Comment, names map[uuid.UUID]string) dto.CommentItem {
+	item := dto.CommentItem{
+		ID:        comment.ID,
+		GoalID:    comment.GoalID,
+		ParentID:  comment.ParentID,
+		IsDeleted: comment.IsDeleted,
+		CreatedAt: comment.CreatedAt,
+		Replies:   []dto.CommentItem{},
+	}
+	if comment.IsDeleted {
+		item.Body = models.CommentRemovedBody
+		return item
+	}
+
+	userID := comment.UserID
+	item.UserID = &userID
+	item.Body = comment.Body
+	item.DisplayName = AnonymousDisplayName
+	if name, ok := names[comment.UserID]; ok {
+		item.DisplayName = name
+	}
+	return item
+}
+
+// CreateComment adds a comment to a goal, or a reply to one of its top-level comments
+func (s *CommentService) CreateComment(ctx context.Context, goalID, userID uuid.UUID, req dto.CreateCommentRequest) (*models.Comment, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" || utf8.RuneCountInString(body) > maxCommentLength {
+		return nil, ErrInvalidComment
+	}
+
+	goal, err := s.visibleGoal(ctx, goalID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if goal.Status == models.GoalStatusSuspended {
+		return nil, ErrGoalSuspended
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.comments.GetCommentByID(ctx, *req.ParentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrCommentNotFound
+			}
+			return nil, err
+		}
+		if parent.GoalID != goalID {
+			return nil, ErrCommentGoalMismatch
+		}
+		if parent.ParentID != nil {
+			return nil, ErrNestedReply
+		}
+	}
+
+	comment := &models.Comment{
+		GoalID:   goalID,
+		UserID:   userID,
+		ParentID: req.ParentID,
+		Body:     body,
+	}
+	if err := s.comments.CreateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	if goal.OwnerID != userID {
+		s.publishGoalCommented(ctx, goal, comment)
+	}
+
+	return comment, nil
+}
+
+// publishGoalCommented tells the goal owner about a new comment
+func (s *CommentService) publishGoalCommented(ctx context.Context, goal *models.Goal, comment *models.Comment) {
+	if s.publisher == nil {
+		return
+	}
+
+	event := events.GoalCommented{
+		ID:          uuid.New().String(),
+		GoalID:      goal.ID.String(),
+		OwnerID:     goal.OwnerID.String(),
+		Title:       goal.Title,
+		CommentID:   comment.ID.String(),
+		CommenterID: comment.UserID.String(),
+		Excerpt:     excerpt(comment.Body, commentExcerptSize),
+		CreatedAt:   time.Now().Unix(),
+	}
+	if comment.ParentID != nil {
+		event.ParentID = comment.ParentID.String()
+	}
+
+	if err := s.publisher.PublishContext(ctx, "GoalCommented", event); err != nil {
+		log.Printf("Failed to publish GoalCommented event: %v", err)
+	}
+}
+
+// excerpt shortens text to at most size characters, marking a cut with an ellipsis
+func excerpt(text string, size int) string {
+	runes := []rune(text)
+	if len(runes) <= size {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:size-1])) + "…"
+}
+
+// DeleteComment removes a comment. Its author and the goal's owner may delete it; the
+// comment stays in its thread as a placeholder.
+func (s *CommentService) DeleteComment(ctx context.Context, goalID, commentID, userID uuid.UUID) error {
+	comment, err := s.comments.GetCommentByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCommentNotFound
+		}
+		return err
+	}
+	if comment.GoalID != goalID {
+		return ErrCommentNotFound
+	}
+
+	if comment.UserID != userID {
+		goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrGoalNotFound
+			}
+			return err
+		}
+		if goal.OwnerID != userID {
+			return ErrUnauthorized
+		}
+	}
+
+	// Deleting an already deleted comment is a no-op
+	_, err = s.comments.MarkCommentDeleted(ctx, commentID)
+	return err
+}
+
+// visibleGoal loads a goal, hiding private goals from everyone but their owner,
+// collaborators and contributors
+func (s *CommentService) visibleGoal(ctx context.Context, goalID, viewerID uuid.UUID) (*models.Goal, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if err := checkGoalVisible(ctx, s.repo, goal, viewerID); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
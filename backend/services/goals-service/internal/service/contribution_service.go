@@ -1,37 +1,120 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/gofund/goals-service/internal/dto"
 	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/storage"
 	"github.com/gofund/shared/events"
-	"github.com/gofund/shared/messaging"
+	"github.com/gofund/shared/fees"
+	"github.com/gofund/shared/metrics"
 	"github.com/gofund/shared/models"
+	"github.com/gofund/shared/validator"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+const maxFeedPageSize = 50
+
 // ContributionService handles business logic for contributions
 type ContributionService struct {
-	repo *repository.Repository
+	repo        *repository.Repository
+	usersClient *UsersClient
+	invites     *InviteTokens
+	fees        fees.Schedule
+	intentTTL   time.Duration
+
+	disclosureThreshold int64
+}
+
+// NewContributionService creates a new contribution service. Pending contribution
+// intents expire after intentTTL; zero disables expiry. Contributors of at least
+// disclosureThreshold are identified to the goal owner; zero disables disclosure. Invite
+// links checked with invites let invitees contribute to private goals. Each intent's
+// platform fee is taken from feeSchedule.
+func NewContributionService(repo *repository.Repository, usersClient *UsersClient, invites *InviteTokens, feeSchedule fees.Schedule, intentTTL time.Duration, disclosureThreshold int64) *ContributionService {
+	return &ContributionService{
+		repo:                repo,
+		usersClient:         usersClient,
+		invites:             invites,
+		fees:                feeSchedule,
+		intentTTL:           intentTTL,
+		disclosureThreshold: disclosureThreshold,
+	}
 }
 
-// NewContributionService creates a new contribution service
-func NewContributionService(repo *repository.Repository) *ContributionService {
-	return &ContributionService{repo: repo}
+// WillDiscloseIdentity reports whether a contribution of amount reveals the contributor's
+// real name and email to the goal owner, even when it is anonymous
+func (s *ContributionService) WillDiscloseIdentity(amount int64) bool {
+	return s.disclosureThreshold > 0 && amount >= s.disclosureThreshold
 }
 
 // CreateContribution creates a new contribution intent
-func (s *ContributionService) CreateContribution(userID uuid.UUID, req dto.CreateContributionRequest) (*models.Contribution, error) {
+func (s *ContributionService) CreateContribution(ctx context.Context, userID uuid.UUID, req dto.CreateContributionRequest) (*models.Contribution, error) {
+	return s.createIntent(ctx, userID, req, nil)
+}
+
+// createIntent creates a contribution intent, on behalf of the recurring contribution
+// recurringID when it is set
+func (s *ContributionService) createIntent(ctx context.Context, userID uuid.UUID, req dto.CreateContributionRequest, recurringID *uuid.UUID) (*models.Contribution, error) {
+	goal, err := s.checkContribution(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MilestoneID == nil && req.UseActiveMilestone {
+		active, err := s.repo.Milestone.GetActiveMilestone(ctx, goal.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if active != nil {
+			req.MilestoneID = &active.ID
+		}
+	}
+
+	// The fee is fixed with the intent, so the contributor pays what the intent shows
+	feeMode, fee, net := s.contributionFee(goal, req.Amount)
+
+	contribution := &models.Contribution{
+		GoalID:                  req.GoalID,
+		MilestoneID:             req.MilestoneID,
+		UserID:                  userID,
+		Amount:                  req.Amount,
+		Currency:                goal.Currency,
+		Status:                  models.ContributionStatusPending,
+		IsAnonymous:             req.IsAnonymous,
+		RecurringContributionID: recurringID,
+		FeeMode:                 feeMode,
+		FeeAmount:               fee,
+		NetAmount:               net,
+	}
+	if s.intentTTL > 0 {
+		expiresAt := time.Now().Add(s.intentTTL)
+		contribution.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.Contribution.CreateContribution(ctx, contribution); err != nil {
+		return nil, err
+	}
+
+	return contribution, nil
+}
+
+// checkContribution validates userID's contribution request against its goal, returning
+// the goal when it accepts the contribution
+func (s *ContributionService) checkContribution(ctx context.Context, userID uuid.UUID, req dto.CreateContributionRequest) (*models.Goal, error) {
 	// Validate amount
 	if req.Amount <= 0 {
-		return nil, errors.New("amount must be greater than 0")
+		return nil, ErrInvalidAmount
 	}
 
 	// Check if goal exists and is open
-	goal, err := s.repo.Goal.GetGoalByIDSimple(req.GoalID)
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, req.GoalID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrGoalNotFound
@@ -39,94 +122,505 @@ func (s *ContributionService) CreateContribution(userID uuid.UUID, req dto.Creat
 		return nil, err
 	}
 
+	// Only those who can see a private goal, or hold an invite to it, may contribute
+	if !s.invites.Valid(goal.ID, req.InviteToken, time.Now()) {
+		if err := checkGoalVisible(ctx, s.repo, goal, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if goal.Status == models.GoalStatusSuspended {
+		return nil, ErrGoalSuspended
+	}
+
+	// Close-on-target goals stop taking new intents once the target is reached
+	if goal.CloseOnTarget {
+		if goal.Status == models.GoalStatusClosed {
+			return nil, ErrGoalTargetReached
+		}
+		total, err := s.repo.Goal.GetTotalConfirmedContributions(ctx, goal.ID)
+		if err != nil {
+			return nil, err
+		}
+		if total >= goal.TargetAmount {
+			return nil, ErrGoalTargetReached
+		}
+	}
+
 	if goal.Status != models.GoalStatusOpen {
-		return nil, errors.New("goal is not accepting contributions")
+		return nil, ErrGoalNotOpen
+	}
+
+	// The deadline job closes the goal eventually; refuse late contributions in the meantime
+	if goal.Deadline != nil && time.Now().After(*goal.Deadline) {
+		return nil, ErrGoalDeadlinePassed
+	}
+
+	if req.Currency != "" && !validator.SameCurrency(req.Currency, goal.Currency) {
+		return nil, ErrCurrencyMismatch.WithDetail(fmt.Sprintf("goal accepts %s, got %s", goal.Currency, req.Currency))
+	}
+
+	if err := checkFixedAmount(goal, req); err != nil {
+		return nil, err
+	}
+
+	if _, fee, net := s.contributionFee(goal, req.Amount); net <= 0 {
+		return nil, ErrAmountBelowFee.WithDetail(fmt.Sprintf("the platform fee on %d is %d", req.Amount, fee))
 	}
 
 	// Validate milestone if provided
 	if req.MilestoneID != nil {
-		milestone, err := s.repo.Milestone.GetMilestoneByID(*req.MilestoneID)
+		milestone, err := s.repo.Milestone.GetMilestoneByID(ctx, *req.MilestoneID)
 		if err != nil {
-			return nil, errors.New("milestone not found")
+			return nil, ErrMilestoneNotFound
 		}
 		if milestone.GoalID != req.GoalID {
-			return nil, errors.New("milestone does not belong to this goal")
+			return nil, ErrMilestoneGoalMismatch
 		}
 	}
 
-	contribution := &models.Contribution{
-		GoalID:      req.GoalID,
-		MilestoneID: req.MilestoneID,
-		UserID:      userID,
-		Amount:      req.Amount,
-		Currency:    goal.Currency,
-		Status:      models.ContributionStatusPending,
+	return goal, nil
+}
+
+// checkFixedAmount enforces a goal's fixed contribution amount: exactly that amount, or an
+// integer multiple of it when the request allows multiples
+func checkFixedAmount(goal *models.Goal, req dto.CreateContributionRequest) error {
+	fixed := goal.FixedContributionAmount
+	if fixed <= 0 || req.Amount == fixed {
+		return nil
 	}
+	if req.AllowMultiples && req.Amount%fixed == 0 {
+		return nil
+	}
+	if req.AllowMultiples {
+		return ErrFixedAmountMismatch.WithDetail(fmt.Sprintf("must be a multiple of %d", fixed))
+	}
+	return ErrFixedAmountMismatch.WithDetail(fmt.Sprintf("must be exactly %d", fixed))
+}
 
-	if err := s.repo.Contribution.CreateContribution(contribution); err != nil {
-		return nil, err
+// contributionFee returns the fee mode a contribution of amount to goal is made under,
+// its platform fee and what reaches the goal
+func (s *ContributionService) contributionFee(goal *models.Goal, amount int64) (models.FeeMode, int64, int64) {
+	mode := goal.FeeMode
+	if mode == "" {
+		mode = models.FeeModeAbsorb
 	}
+	fee, _, net := s.fees.Split(mode, goal.Currency, amount)
+	return mode, fee, net
+}
 
-	return contribution, nil
+// RunExpiry expires abandoned contribution intents every interval until ctx is cancelled
+func (s *ContributionService) RunExpiry(ctx context.Context, interval time.Duration) {
+	if s.intentTTL <= 0 {
+		return
+	}
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.expireIntents(ctx, time.Now())
+		}
+	}
 }
 
-// ConfirmContribution confirms a contribution after payment verification
-func (s *ContributionService) ConfirmContribution(contributionID, paymentID uuid.UUID) error {
-	contribution, err := s.repo.Contribution.GetContributionByID(contributionID)
+// expireIntents marks pending contributions past their expiry as EXPIRED
+func (s *ContributionService) expireIntents(ctx context.Context, now time.Time) {
+	expired, err := s.repo.Contribution.ExpirePendingContributions(ctx, now)
+	if err != nil {
+		log.Printf("Failed to expire contribution intents: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("Expired %d abandoned contribution intents", expired)
+		metrics.IncrementCounter("goals.contributions.expired")
+		metrics.RecordGauge("goals.contributions.expired.batch", float64(expired))
+	}
+}
+
+// ConfirmContribution confirms a contribution after payment verification. An intent that
+// has already expired is not revived; a fresh confirmed contribution records the payment.
+// The events announcing the confirmation are recorded in its transaction. It reports
+// whether the confirmation closed a close-on-target goal, and returns ErrGoalNotFound when
+// the contribution outlived its goal.
+func (s *ContributionService) ConfirmContribution(ctx context.Context, contributionID, paymentID uuid.UUID) (bool, error) {
+	// Look the contributor's name up before the goal is locked, as it may call users-service
+	contribution, err := s.repo.Primary().Contribution.GetContributionByID(ctx, contributionID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrContributionNotFound
+			return false, ErrContributionNotFound
 		}
+		return false, err
+	}
+	contributorName := AnonymousDisplayName
+	if !contribution.IsAnonymous && s.usersClient != nil {
+		contributorName = s.usersClient.DisplayNames([]uuid.UUID{contribution.UserID})[contribution.UserID]
+	}
+
+	_, closed, err := s.repo.Contribution.ConfirmContribution(ctx, contributionID, paymentID,
+		func(tx *repository.Repository, goal *models.Goal, confirmed *models.Contribution, closed bool) error {
+			if err := recordContributionConfirmed(ctx, tx, goal, confirmed, contributorName); err != nil {
+				return err
+			}
+			if closed {
+				if err := recordGoalClosedEarly(ctx, tx, goal); err != nil {
+					return err
+				}
+			}
+			return recordGoalFunded(ctx, tx, goal, closed)
+		})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, ErrContributionNotFound
+		}
+		if errors.Is(err, repository.ErrOrphanedContribution) {
+			return false, ErrGoalNotFound
+		}
+		return false, err
+	}
+
+	return closed, nil
+}
+
+// SaveAuthorization remembers the reusable card a confirmed contribution was paid with,
+// so the contributor can set up a recurring contribution from it
+func (s *ContributionService) SaveAuthorization(ctx context.Context, paymentID uuid.UUID, code, email string) error {
+	return s.repo.Contribution.SetAuthorizationByPaymentID(ctx, paymentID, code, email)
+}
+
+// recordContributionConfirmed records the event that lets notifications tell the goal
+// owner about a new contribution. Anonymous contributors are masked here, before the event
+// leaves the service.
+func recordContributionConfirmed(ctx context.Context, tx *repository.Repository, goal *models.Goal, contribution *models.Contribution, contributorName string) error {
+	event := events.ContributionConfirmed{
+		ID:              uuid.New().String(),
+		ContributionID:  contribution.ID.String(),
+		GoalID:          goal.ID.String(),
+		GoalOwnerID:     goal.OwnerID.String(),
+		GoalTitle:       goal.Title,
+		Amount:          contribution.Amount,
+		ChargeAmount:    contribution.ChargeAmount(),
+		FeeAmount:       contribution.FeeAmount,
+		NetAmount:       contribution.NetAmount,
+		Currency:        contribution.Currency,
+		ContributorName: AnonymousDisplayName,
+		IsAnonymous:     contribution.IsAnonymous,
+		CreatedAt:       time.Now().Unix(),
+	}
+	if !contribution.IsAnonymous {
+		event.UserID = contribution.UserID.String()
+		event.ContributorName = contributorName
+	}
+
+	return recordEvent(ctx, tx, "ContributionConfirmed", event)
+}
+
+// recordGoalClosedEarly records the event that tells payments to stop accepting new
+// payments for the goal and lets notifications tell contributors the goal filled up.
+// Pending contributions are left untouched: payments already in flight still confirm and
+// over-fund the goal.
+func recordGoalClosedEarly(ctx context.Context, tx *repository.Repository, goal *models.Goal) error {
+	event := events.GoalClosedEarly{
+		ID:           uuid.New().String(),
+		GoalID:       goal.ID.String(),
+		OwnerID:      goal.OwnerID.String(),
+		Title:        goal.Title,
+		TargetAmount: goal.TargetAmount,
+		CreatedAt:    time.Now().Unix(),
+	}
+
+	total, err := tx.Goal.GetTotalConfirmedContributions(ctx, goal.ID)
+	if err != nil {
+		return err
+	}
+	event.TotalAmount = total
+
+	contributorIDs, err := tx.Goal.GetContributorIDs(ctx, goal.ID)
+	if err != nil {
 		return err
 	}
+	for _, id := range contributorIDs {
+		event.ContributorIDs = append(event.ContributorIDs, id.String())
+	}
+
+	contributions, err := tx.Contribution.GetContributionsByGoalID(ctx, goal.ID)
+	if err != nil {
+		return err
+	}
+	seen := make(map[uuid.UUID]bool)
+	for _, c := range contributions {
+		if c.Status != models.ContributionStatusPending {
+			continue
+		}
+		event.PendingContributionIDs = append(event.PendingContributionIDs, c.ID.String())
+		if !seen[c.UserID] {
+			seen[c.UserID] = true
+			event.PendingUserIDs = append(event.PendingUserIDs, c.UserID.String())
+		}
+	}
 
-	contribution.PaymentID = &paymentID
-	contribution.Status = models.ContributionStatusConfirmed
+	return recordEvent(ctx, tx, "GoalClosedEarly", event)
+}
+
+// recordGoalFunded records the event that lets notifications tell watchers a goal reached
+// its target, when confirmed contributions cover it and the goal was still open. A
+// close-on-target goal is already CLOSED by the confirmation that funded it.
+func recordGoalFunded(ctx context.Context, tx *repository.Repository, goal *models.Goal, closed bool) error {
+	if goal.Status != models.GoalStatusOpen && !closed {
+		return nil
+	}
 
-	return s.repo.Contribution.UpdateContribution(contribution)
+	total, err := tx.Goal.GetTotalConfirmedContributions(ctx, goal.ID)
+	if err != nil {
+		return err
+	}
+	if goal.TargetAmount <= 0 || total < goal.TargetAmount {
+		return nil
+	}
+
+	return recordEvent(ctx, tx, "GoalFunded", events.GoalFunded{
+		ID:         uuid.New().String(),
+		GoalID:     goal.ID.String(),
+		Title:      goal.Title,
+		Amount:     total,
+		WatcherIDs: watcherIDs(ctx, tx, goal.ID),
+		CreatedAt:  time.Now().Unix(),
+	})
 }
 
 // GetContributionsByGoal retrieves all contributions for a goal
-func (s *ContributionService) GetContributionsByGoal(goalID uuid.UUID) ([]models.Contribution, error) {
-	return s.repo.Contribution.GetContributionsByGoalID(goalID)
+func (s *ContributionService) GetContributionsByGoal(ctx context.Context, goalID uuid.UUID) ([]models.Contribution, error) {
+	return s.repo.Contribution.GetContributionsByGoalID(ctx, goalID)
+}
+
+// GetContributionFeed returns a page of a goal's confirmed contributions with contributor
+// display names resolved server-side. Private goals are only visible to their owner,
+// collaborators and contributors, and anonymous contributions are masked for everyone but the contributor.
+func (s *ContributionService) GetContributionFeed(ctx context.Context, goalID, viewerID uuid.UUID, page, pageSize int) (*dto.ContributionFeed, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > maxFeedPageSize {
+		pageSize = maxFeedPageSize
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if err := checkGoalVisible(ctx, s.repo, goal, viewerID); err != nil {
+		return nil, err
+	}
+
+	contributions, total, err := s.repo.Contribution.GetConfirmedContributionsPage(ctx, goalID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(contributions))
+	for i := range contributions {
+		contributions[i].MaskContributor(viewerID)
+		if contributions[i].UserID != uuid.Nil {
+			userIDs = append(userIDs, contributions[i].UserID)
+		}
+	}
+
+	var names map[uuid.UUID]string
+	if s.usersClient != nil {
+		names = s.usersClient.DisplayNames(userIDs)
+	}
+
+	feed := &dto.ContributionFeed{
+		Items:    make([]dto.ContributionFeedItem, 0, len(contributions)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for _, contribution := range contributions {
+		name, ok := names[contribution.UserID]
+		if !ok {
+			name = AnonymousDisplayName
+		}
+		feed.Items = append(feed.Items, dto.ContributionFeedItem{
+			ID:          contribution.ID,
+			UserID:      contribution.UserID,
+			DisplayName: name,
+			Amount:      contribution.Amount,
+			Currency:    contribution.Currency,
+			CreatedAt:   contribution.CreatedAt,
+		})
+	}
+
+	return feed, nil
+}
+
+// GetOwnerContributions returns a page of a goal's confirmed contributions for its owner.
+// Contributions at or above the disclosure threshold carry the contributor's real name and
+// email, anonymous or not; smaller ones follow the same anonymity rules as the public feed.
+func (s *ContributionService) GetOwnerContributions(ctx context.Context, goalID, ownerID uuid.UUID, page, pageSize int) (*dto.OwnerContributionList, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > maxFeedPageSize {
+		pageSize = maxFeedPageSize
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if goal.OwnerID != ownerID {
+		return nil, ErrUnauthorized
+	}
+
+	contributions, total, err := s.repo.Contribution.GetConfirmedContributionsPage(ctx, goalID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var named, disclosed []uuid.UUID
+	for i := range contributions {
+		if s.WillDiscloseIdentity(contributions[i].Amount) {
+			disclosed = append(disclosed, contributions[i].UserID)
+			continue
+		}
+		contributions[i].MaskContributor(ownerID)
+		if contributions[i].UserID != uuid.Nil {
+			named = append(named, contributions[i].UserID)
+		}
+	}
+
+	var names map[uuid.UUID]string
+	var contacts map[uuid.UUID]Contact
+	if s.usersClient != nil {
+		names = s.usersClient.DisplayNames(named)
+		if len(disclosed) > 0 {
+			contacts = s.usersClient.Contacts(disclosed)
+		}
+	}
+
+	list := &dto.OwnerContributionList{
+		Items:    make([]dto.OwnerContributionItem, 0, len(contributions)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for _, contribution := range contributions {
+		item := dto.OwnerContributionItem{
+			ID:          contribution.ID,
+			UserID:      contribution.UserID,
+			DisplayName: AnonymousDisplayName,
+			Amount:      contribution.Amount,
+			Currency:    contribution.Currency,
+			IsAnonymous: contribution.IsAnonymous,
+			CreatedAt:   contribution.CreatedAt,
+		}
+		if s.WillDiscloseIdentity(contribution.Amount) {
+			item.IdentityDisclosed = true
+			if contact, ok := contacts[contribution.UserID]; ok {
+				item.Name = contact.Name
+				item.Email = contact.Email
+				item.DisplayName = contact.Name
+			}
+		} else if name, ok := names[contribution.UserID]; ok {
+			item.DisplayName = name
+		}
+		list.Items = append(list.Items, item)
+	}
+
+	return list, nil
 }
 
-// GetContributionsByUser retrieves all contributions by a user
-func (s *ContributionService) GetContributionsByUser(userID uuid.UUID) ([]models.Contribution, error) {
-	return s.repo.Contribution.GetContributionsByUserID(userID)
+// GetContributionsByUser retrieves all contributions by a user, each with the status of
+// its latest refund disbursement when it has been refunded
+func (s *ContributionService) GetContributionsByUser(ctx context.Context, userID uuid.UUID) ([]dto.MyContribution, error) {
+	contributions, err := s.repo.Contribution.GetContributionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.MyContribution, len(contributions))
+	if len(contributions) == 0 {
+		return items, nil
+	}
+
+	contributionIDs := make([]uuid.UUID, len(contributions))
+	for i, contribution := range contributions {
+		contributionIDs[i] = contribution.ID
+	}
+	refundStatuses, err := s.repo.RefundDisbursement.GetLatestStatuses(ctx, contributionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, contribution := range contributions {
+		items[i].Contribution = contribution
+		if status, ok := refundStatuses[contribution.ID]; ok {
+			items[i].RefundStatus = &status
+		}
+	}
+	return items, nil
 }
 
-// GetContributionByID retrieves a single contribution by ID
-func (s *ContributionService) GetContributionByID(contributionID uuid.UUID) (*models.Contribution, error) {
-	contribution, err := s.repo.Contribution.GetContributionByID(contributionID)
+// GetContributionByID retrieves a single contribution by ID, masking the contributor
+// of an anonymous contribution unless viewerID made it
+func (s *ContributionService) GetContributionByID(ctx context.Context, contributionID, viewerID uuid.UUID) (*models.Contribution, error) {
+	contribution, err := s.repo.Contribution.GetContributionByID(ctx, contributionID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrContributionNotFound
 		}
 		return nil, err
 	}
+	contribution.MaskContributor(viewerID)
 	return contribution, nil
 }
 
-// WithdrawalService handles business logic for withdrawals
+// WithdrawalService handles business logic for withdrawals. Payouts are made by
+// payments-service: a request is handed off with a WithdrawalRequested event and settled
+// by the WithdrawalCompleted or WithdrawalFailed event that comes back.
 type WithdrawalService struct {
-	repo *repository.Repository
+	repo         *repository.Repository
+	balanceCheck *BalanceCheckService
+	audit        *AuditService
+	usersClient  *UsersClient
+	kycThreshold int64
 }
 
-// NewWithdrawalService creates a new withdrawal service
-func NewWithdrawalService(repo *repository.Repository) *WithdrawalService {
-	return &WithdrawalService{repo: repo}
+// NewWithdrawalService creates a new withdrawal service. Withdrawals above kycThreshold
+// (kobo) require the requester to be KYC verified; zero disables the check.
+func NewWithdrawalService(repo *repository.Repository, balanceCheck *BalanceCheckService, audit *AuditService, usersClient *UsersClient, kycThreshold int64) *WithdrawalService {
+	return &WithdrawalService{repo: repo, balanceCheck: balanceCheck, audit: audit, usersClient: usersClient, kycThreshold: kycThreshold}
 }
 
 // CreateWithdrawal creates a new withdrawal request
-func (s *WithdrawalService) CreateWithdrawal(userID uuid.UUID, req dto.CreateWithdrawalRequest) (*models.Withdrawal, error) {
+func (s *WithdrawalService) CreateWithdrawal(ctx context.Context, userID uuid.UUID, req dto.CreateWithdrawalRequest) (*models.Withdrawal, error) {
 	// Validate amount
 	if req.Amount <= 0 {
-		return nil, errors.New("amount must be greater than 0")
+		return nil, ErrInvalidAmount
 	}
 
 	// Get goal
-	goal, err := s.repo.Goal.GetGoalByIDSimple(req.GoalID)
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, req.GoalID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrGoalNotFound
@@ -134,14 +628,27 @@ func (s *WithdrawalService) CreateWithdrawal(userID uuid.UUID, req dto.CreateWit
 		return nil, err
 	}
 
-	// Check ownership
-	if goal.OwnerID != userID {
+	// The owner, or a collaborator sharing ownership, may request withdrawals
+	allowed, err := canRequestWithdrawal(ctx, s.repo, goal, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
 		return nil, ErrUnauthorized
 	}
 
-	// Check goal status
-	if goal.Status == models.GoalStatusCancelled {
-		return nil, ErrInvalidGoalStatus
+	if err := checkGoalWithdrawable(goal); err != nil {
+		return nil, err
+	}
+
+	// Optionally hold withdrawals while goals-service and the ledger disagree. This and the
+	// KYC check call other services, so they are made before the goal is locked.
+	if err := s.balanceCheck.CheckWithdrawal(ctx, goal); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkKYC(userID, req.Amount); err != nil {
+		return nil, err
 	}
 
 	// Determine bank details (use provided or fall back to goal's bank details)
@@ -164,89 +671,303 @@ func (s *WithdrawalService) CreateWithdrawal(userID uuid.UUID, req dto.CreateWit
 		return nil, ErrBankDetailsRequired
 	}
 
-	// Calculate available balance
-	totalContributions, err := s.repo.Goal.GetTotalConfirmedContributions(req.GoalID)
+	var withdrawal *models.Withdrawal
+	err = s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		// Lock the goal so withdrawals and refund initiations on it serialise: each sees
+		// the balance the ones before it left
+		goal, err = tx.Goal.GetGoalByIDForUpdate(ctx, req.GoalID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrGoalNotFound
+			}
+			return err
+		}
+		if err := checkGoalWithdrawable(goal); err != nil {
+			return err
+		}
+
+		// Funds promised to contributors must not be withdrawn before disbursement
+		refundActive, err := tx.Goal.HasActiveRefund(ctx, req.GoalID)
+		if err != nil {
+			return err
+		}
+		if refundActive {
+			return ErrRefundInProgress
+		}
+
+		if err := checkWithdrawalBalance(ctx, tx, req); err != nil {
+			return err
+		}
+
+		if err := checkProofsForWithdrawal(ctx, tx, goal, req.MilestoneID); err != nil {
+			return err
+		}
+
+		// On co-owned goals the withdrawal waits for the other collaborators; the requester's
+		// own approval counts towards the required number
+		status := models.WithdrawalStatusPending
+		if goal.RequiredApprovals > 1 {
+			status = models.WithdrawalStatusAwaitingApproval
+		}
+
+		now := time.Now()
+		withdrawal = &models.Withdrawal{
+			GoalID:        req.GoalID,
+			MilestoneID:   req.MilestoneID,
+			OwnerID:       goal.OwnerID,
+			RequestedBy:   &userID,
+			Amount:        req.Amount,
+			Currency:      goal.Currency,
+			BankName:      bankName,
+			AccountNumber: accountNumber,
+			AccountName:   accountName,
+			BankCode:      req.BankCode,
+			Status:        status,
+			RequestedAt:   now,
+		}
+
+		if err := tx.Withdrawal.CreateWithdrawal(ctx, withdrawal); err != nil {
+			return err
+		}
+		if status != models.WithdrawalStatusAwaitingApproval {
+			return recordWithdrawalRequested(ctx, tx, withdrawal, goal.Title)
+		}
+		approval := models.WithdrawalApproval{WithdrawalID: withdrawal.ID, ApproverID: userID, ApprovedAt: now}
+		if _, err := tx.Withdrawal.CreateApproval(ctx, &approval); err != nil {
+			return err
+		}
+		withdrawal.Approvals = []models.WithdrawalApproval{approval}
+		return recordApprovalRequested(ctx, tx, withdrawal, goal)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	totalWithdrawals, err := s.repo.Goal.GetTotalCompletedWithdrawals(req.GoalID)
+	s.audit.Record(ctx, AuditEntry{
+		GoalID:     withdrawal.GoalID,
+		ActorID:    userID,
+		Action:     models.AuditActionWithdrawalRequested,
+		EntityType: auditEntityWithdrawal,
+		EntityID:   withdrawal.ID,
+		After: map[string]interface{}{
+			"amount":         withdrawal.Amount,
+			"currency":       withdrawal.Currency,
+			"milestone_id":   withdrawal.MilestoneID,
+			"bank_name":      withdrawal.BankName,
+			"bank_code":      withdrawal.BankCode,
+			"account_number": withdrawal.AccountNumber,
+			"account_name":   withdrawal.AccountName,
+		},
+	})
+
+	metrics.IncrementCounter("goals.withdrawal.requested", "status:"+string(withdrawal.Status))
+	return withdrawal, nil
+}
+
+// checkGoalWithdrawable refuses withdrawals from suspended and cancelled goals
+func checkGoalWithdrawable(goal *models.Goal) error {
+	switch goal.Status {
+	case models.GoalStatusSuspended:
+		return ErrGoalSuspended
+	case models.GoalStatusCancelled:
+		return ErrInvalidGoalStatus
+	}
+	return nil
+}
+
+// checkWithdrawalBalance refuses a withdrawal larger than the goal's available balance:
+// confirmed contributions less absorbed fees, withdrawals that are paid out or still being
+// paid out, and refunds. A milestone withdrawal is also capped by what the milestone itself
+// has left. Call it on a transaction's repository that holds the goal's lock, so no other
+// withdrawal or refund changes the balance before the withdrawal is recorded.
+func checkWithdrawalBalance(ctx context.Context, tx *repository.Repository, req dto.CreateWithdrawalRequest) error {
+	totalContributions, err := tx.Goal.GetTotalConfirmedContributions(ctx, req.GoalID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	availableBalance := totalContributions - totalWithdrawals
+	totalWithdrawals, err := tx.Goal.GetTotalCommittedWithdrawals(ctx, req.GoalID)
+	if err != nil {
+		return err
+	}
 
-	if req.Amount > availableBalance {
-		return nil, ErrInsufficientBalance
+	// Fees the goal absorbed never reached it
+	totalFees, err := tx.Goal.GetTotalAbsorbedFees(ctx, req.GoalID)
+	if err != nil {
+		return err
 	}
 
-	// Validate milestone if provided
-	if req.MilestoneID != nil {
-		milestone, err := s.repo.Milestone.GetMilestoneByID(*req.MilestoneID)
-		if err != nil {
-			return nil, errors.New("milestone not found")
-		}
-		if milestone.GoalID != req.GoalID {
-			return nil, errors.New("milestone does not belong to this goal")
+	// Refunded money has gone back to contributors
+	totalRefunded, err := tx.Goal.GetTotalRefunded(ctx, req.GoalID)
+	if err != nil {
+		return err
+	}
+
+	availableBalance := totalContributions - totalFees - totalWithdrawals - totalRefunded
+
+	if req.MilestoneID == nil {
+		if req.Amount > availableBalance {
+			return ErrInsufficientBalance
 		}
+		return nil
 	}
 
-	withdrawal := &models.Withdrawal{
-		GoalID:        req.GoalID,
-		MilestoneID:   req.MilestoneID,
-		OwnerID:       userID,
-		Amount:        req.Amount,
-		Currency:      goal.Currency,
-		BankName:      bankName,
-		AccountNumber: accountNumber,
-		AccountName:   accountName,
-		Status:        models.WithdrawalStatusPending,
-		RequestedAt:   time.Now(),
+	milestone, err := tx.Milestone.GetMilestoneByID(ctx, *req.MilestoneID)
+	if err != nil {
+		return ErrMilestoneNotFound
+	}
+	if milestone.GoalID != req.GoalID {
+		return ErrMilestoneGoalMismatch
 	}
 
-	if err := s.repo.Withdrawal.CreateWithdrawal(withdrawal); err != nil {
-		return nil, err
+	// A milestone withdrawal is capped by what the milestone itself raised, as well as by the goal
+	milestoneContributions, err := tx.Milestone.GetTotalConfirmedContributionsByMilestone(ctx, milestone.ID)
+	if err != nil {
+		return err
 	}
+	milestoneWithdrawals, err := tx.Withdrawal.GetTotalCommittedWithdrawalsByMilestone(ctx, milestone.ID)
+	if err != nil {
+		return err
+	}
+	milestoneRefunded, err := tx.Milestone.GetTotalRefundedByMilestone(ctx, milestone.ID)
+	if err != nil {
+		return err
+	}
+	milestoneBalance := milestoneContributions - milestoneWithdrawals - milestoneRefunded
 
-	return withdrawal, nil
+	if req.Amount > availableBalance || req.Amount > milestoneBalance {
+		return ErrInsufficientBalance.WithDetail(fmt.Sprintf(
+			"milestone available balance is %d and goal available balance is %d", milestoneBalance, availableBalance))
+	}
+	return nil
+}
+
+// checkKYC refuses withdrawals above the KYC threshold unless the requester is KYC
+// verified. The status is fetched fresh from users-service; if it cannot be confirmed the
+// withdrawal is refused rather than let through.
+func (s *WithdrawalService) checkKYC(userID uuid.UUID, amount int64) error {
+	if s.kycThreshold <= 0 || amount <= s.kycThreshold {
+		return nil
+	}
+	if s.usersClient == nil {
+		return fmt.Errorf("failed to check KYC status: users-service client not configured")
+	}
+
+	verified, err := s.usersClient.KYCVerified(userID)
+	if err != nil {
+		return fmt.Errorf("failed to check KYC status: %w", err)
+	}
+	if !verified {
+		metrics.IncrementCounter("goals.withdrawal.kyc_required")
+		return ErrKYCRequired
+	}
+	return nil
+}
+
+// recordWithdrawalRequested hands a withdrawal to payments-service for payout: it moves
+// the withdrawal to PROCESSING and records the WithdrawalRequested event in the same
+// transaction
+func recordWithdrawalRequested(ctx context.Context, tx *repository.Repository, withdrawal *models.Withdrawal, goalTitle string) error {
+	withdrawal.Status = models.WithdrawalStatusProcessing
+	if err := tx.Withdrawal.UpdateWithdrawal(ctx, withdrawal); err != nil {
+		return err
+	}
+
+	event := events.WithdrawalRequested{
+		ID:            uuid.New().String(),
+		WithdrawalID:  withdrawal.ID.String(),
+		GoalID:        withdrawal.GoalID.String(),
+		OwnerID:       withdrawal.OwnerID.String(),
+		GoalTitle:     goalTitle,
+		Amount:        withdrawal.Amount,
+		Currency:      withdrawal.Currency,
+		BankName:      withdrawal.BankName,
+		BankCode:      withdrawal.BankCode,
+		AccountNumber: withdrawal.AccountNumber,
+		AccountName:   withdrawal.AccountName,
+		CreatedAt:     time.Now().Unix(),
+	}
+	return recordEvent(ctx, tx, "WithdrawalRequested", event)
 }
 
-// CompleteWithdrawal marks a withdrawal as completed
-func (s *WithdrawalService) CompleteWithdrawal(withdrawalID, ledgerTransactionID uuid.UUID) error {
-	withdrawal, err := s.repo.Withdrawal.GetWithdrawalByID(withdrawalID)
+// CompleteWithdrawal marks a withdrawal as completed once its transfer succeeded.
+// Redelivered events are ignored.
+func (s *WithdrawalService) CompleteWithdrawal(ctx context.Context, withdrawalID uuid.UUID, transferReference, transferCode string) error {
+	withdrawal, err := s.repo.Withdrawal.GetWithdrawalByID(ctx, withdrawalID)
 	if err != nil {
 		return err
 	}
 
+	if withdrawal.Status == models.WithdrawalStatusCompleted {
+		return nil
+	}
+
 	now := time.Now()
 	withdrawal.Status = models.WithdrawalStatusCompleted
-	withdrawal.LedgerTransactionID = &ledgerTransactionID
+	withdrawal.TransferReference = transferReference
+	withdrawal.TransferCode = transferCode
+	withdrawal.FailureReason = ""
 	withdrawal.CompletedAt = &now
 
-	return s.repo.Withdrawal.UpdateWithdrawal(withdrawal)
+	if err := s.repo.Withdrawal.UpdateWithdrawal(ctx, withdrawal); err != nil {
+		return err
+	}
+
+	metrics.IncrementCounter("goals.withdrawal.completed")
+	return nil
+}
+
+// FailWithdrawal marks a withdrawal as failed, returning its amount to the goal's available
+// balance. A withdrawal that already completed is left alone.
+func (s *WithdrawalService) FailWithdrawal(ctx context.Context, withdrawalID uuid.UUID, transferReference, reason string) error {
+	withdrawal, err := s.repo.Withdrawal.GetWithdrawalByID(ctx, withdrawalID)
+	if err != nil {
+		return err
+	}
+
+	switch withdrawal.Status {
+	case models.WithdrawalStatusCompleted:
+		log.Printf("Warning: ignoring failure for completed withdrawal %s: %s", withdrawalID, reason)
+		return nil
+	case models.WithdrawalStatusFailed:
+		return nil
+	}
+
+	withdrawal.Status = models.WithdrawalStatusFailed
+	withdrawal.TransferReference = transferReference
+	withdrawal.FailureReason = reason
+
+	if err := s.repo.Withdrawal.UpdateWithdrawal(ctx, withdrawal); err != nil {
+		return err
+	}
+
+	metrics.IncrementCounter("goals.withdrawal.failed")
+	return nil
 }
 
 // GetWithdrawalsByGoal retrieves all withdrawals for a goal
-func (s *WithdrawalService) GetWithdrawalsByGoal(goalID uuid.UUID) ([]models.Withdrawal, error) {
-	return s.repo.Withdrawal.GetWithdrawalsByGoalID(goalID)
+func (s *WithdrawalService) GetWithdrawalsByGoal(ctx context.Context, goalID uuid.UUID) ([]models.Withdrawal, error) {
+	return s.repo.Withdrawal.GetWithdrawalsByGoalID(ctx, goalID)
 }
 
 // ProofService handles business logic for proofs
 type ProofService struct {
-	repo      *repository.Repository
-	publisher messaging.Publisher
+	repo *repository.Repository
+	// media stores uploaded proof media; without it uploads are refused
+	media storage.BlobStore
 }
 
-// NewProofService creates a new proof service
-func NewProofService(repo *repository.Repository, publisher messaging.Publisher) *ProofService {
-	return &ProofService{repo: repo, publisher: publisher}
+// NewProofService creates a new proof service. Its events are recorded in the outbox in the
+// transaction that makes the change they announce.
+func NewProofService(repo *repository.Repository, media storage.BlobStore) *ProofService {
+	return &ProofService{repo: repo, media: media}
 }
 
-// CreateProof creates a new proof
-func (s *ProofService) CreateProof(userID uuid.UUID, req dto.CreateProofRequest) (*models.Proof, error) {
+// CreateProof creates a new proof. Its media URLs must be files the user uploaded with
+// UploadProofMedia that no other proof cites.
+func (s *ProofService) CreateProof(ctx context.Context, userID uuid.UUID, req dto.CreateProofRequest) (*models.Proof, error) {
 	// Get goal
-	goal, err := s.repo.Goal.GetGoalByIDSimple(req.GoalID)
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, req.GoalID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrGoalNotFound
@@ -261,15 +982,20 @@ func (s *ProofService) CreateProof(userID uuid.UUID, req dto.CreateProofRequest)
 
 	// Validate milestone if provided
 	if req.MilestoneID != nil {
-		milestone, err := s.repo.Milestone.GetMilestoneByID(*req.MilestoneID)
+		milestone, err := s.repo.Milestone.GetMilestoneByID(ctx, *req.MilestoneID)
 		if err != nil {
-			return nil, errors.New("milestone not found")
+			return nil, ErrMilestoneNotFound
 		}
 		if milestone.GoalID != req.GoalID {
-			return nil, errors.New("milestone does not belong to this goal")
+			return nil, ErrMilestoneGoalMismatch
 		}
 	}
 
+	media, err := s.checkProofMedia(ctx, userID, req.MediaURLs)
+	if err != nil {
+		return nil, err
+	}
+
 	proof := &models.Proof{
 		GoalID:      req.GoalID,
 		MilestoneID: req.MilestoneID,
@@ -280,56 +1006,67 @@ func (s *ProofService) CreateProof(userID uuid.UUID, req dto.CreateProofRequest)
 		SubmittedAt: time.Now(),
 	}
 
-	if err := s.repo.Proof.CreateProof(proof); err != nil {
-		return nil, err
-	}
-
-	// Emit event
-	if s.publisher != nil {
-		event := events.ProofSubmitted{
+	err = s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		if err := tx.Proof.CreateProof(ctx, proof); err != nil {
+			return err
+		}
+		if err := attachProofMedia(ctx, tx, media, userID, proof.ID); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, "ProofSubmitted", events.ProofSubmitted{
 			ID:        uuid.New().String(),
 			GoalID:    proof.GoalID.String(),
 			ProofID:   proof.ID.String(),
 			CreatedAt: time.Now().Unix(),
-		}
-		s.publisher.Publish("ProofSubmitted", event)
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return proof, nil
 }
 
-// GetProof retrieves a proof by ID
-func (s *ProofService) GetProof(proofID uuid.UUID) (*models.Proof, error) {
-	proof, err := s.repo.Proof.GetProofByID(proofID)
+// GetProof retrieves a proof by ID, with its voters masked as MaskVoter describes
+func (s *ProofService) GetProof(ctx context.Context, proofID, viewerID uuid.UUID) (*models.Proof, error) {
+	proof, err := s.repo.Proof.GetProofByID(ctx, proofID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrProofNotFound
 		}
 		return nil, err
 	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, proof.GoalID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range proof.Votes {
+		proof.Votes[i].MaskVoter(viewerID, goal.OwnerID)
+	}
 	return proof, nil
 }
 
 // GetProofsByGoal retrieves all proofs for a goal
-func (s *ProofService) GetProofsByGoal(goalID uuid.UUID) ([]models.Proof, error) {
-	return s.repo.Proof.GetProofsByGoalID(goalID)
+func (s *ProofService) GetProofsByGoal(ctx context.Context, goalID uuid.UUID) ([]models.Proof, error) {
+	return s.repo.Proof.GetProofsByGoalID(ctx, goalID)
 }
 
 // VoteService handles business logic for votes
 type VoteService struct {
-	repo      *repository.Repository
-	publisher messaging.Publisher
+	repo *repository.Repository
 }
 
-// NewVoteService creates a new vote service
-func NewVoteService(repo *repository.Repository, publisher messaging.Publisher) *VoteService {
-	return &VoteService{repo: repo, publisher: publisher}
+// NewVoteService creates a new vote service. The events announcing proofs that votes settle
+// are recorded in the outbox in the transaction that settles them.
+func NewVoteService(repo *repository.Repository) *VoteService {
+	return &VoteService{repo: repo}
 }
 
 // CreateVote creates a new vote or updates existing
-func (s *VoteService) CreateVote(userID uuid.UUID, req dto.CreateVoteRequest) (*models.Vote, error) {
+func (s *VoteService) CreateVote(ctx context.Context, userID uuid.UUID, req dto.CreateVoteRequest) (*models.Vote, error) {
 	// Get proof
-	proof, err := s.repo.Proof.GetProofByID(req.ProofID)
+	proof, err := s.repo.Proof.GetProofByID(ctx, req.ProofID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrProofNotFound
@@ -337,8 +1074,16 @@ func (s *VoteService) CreateVote(userID uuid.UUID, req dto.CreateVoteRequest) (*
 		return nil, err
 	}
 
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, proof.GoalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+
 	// Check if user is a contributor
-	isContributor, err := s.repo.Goal.IsUserContributor(proof.GoalID, userID)
+	isContributor, err := s.repo.Goal.IsUserContributor(ctx, proof.GoalID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -346,95 +1091,98 @@ func (s *VoteService) CreateVote(userID uuid.UUID, req dto.CreateVoteRequest) (*
 		return nil, ErrNotContributor
 	}
 
-	// Check if user already voted
-	existingVote, err := s.repo.Vote.GetVoteByProofAndVoter(req.ProofID, userID)
-	if err == nil {
-		// Update existing vote
-		existingVote.IsSatisfied = req.IsSatisfied
-		existingVote.Comment = req.Comment
-		existingVote.VotedAt = time.Now()
-
-		if err := s.repo.Vote.UpdateVote(existingVote); err != nil {
+	// A changed vote can settle the proof too, so both paths go through SaveVoteAndEvaluate
+	vote, err := s.repo.Vote.GetVoteByProofAndVoter(ctx, req.ProofID, userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, err
 		}
-		return existingVote, nil
-	}
-
-	// Create new vote
-	vote := &models.Vote{
-		ProofID:     req.ProofID,
-		VoterID:     userID,
-		IsSatisfied: req.IsSatisfied,
-		Comment:     req.Comment,
-		VotedAt:     time.Now(),
+		vote = &models.Vote{
+			ProofID: req.ProofID,
+			VoterID: userID,
+		}
 	}
+	vote.IsSatisfied = req.IsSatisfied
+	vote.Comment = req.Comment
+	vote.ShowIdentity = req.ShowIdentity
+	vote.VotedAt = time.Now()
 
-	if err := s.repo.Vote.CreateVote(vote); err != nil {
+	settled, err := s.repo.Vote.SaveVoteAndEvaluate(ctx, vote, proofEvaluation(goal), proofSettledEvents(ctx, goal, proof))
+	if err != nil {
 		return nil, err
 	}
-
-	// Check if proof is now verified
-	go s.checkProofVerification(proof.GoalID, req.ProofID)
+	trackProofSettled(settled)
 
 	return vote, nil
 }
 
-func (s *VoteService) checkProofVerification(goalID, proofID uuid.UUID) {
-	_, satisfied, err := s.repo.Vote.GetVoteStats(proofID)
-	if err != nil {
-		return
-	}
-
-	contributorCount, err := s.repo.Goal.GetContributorCount(goalID)
-	if err != nil {
-		return
-	}
+// recordProofVerified records the event announcing that votes verified a proof
+func recordProofVerified(ctx context.Context, tx *repository.Repository, proof *models.Proof) error {
+	return recordEvent(ctx, tx, "ProofVerified", events.ProofVerified{
+		ID:        uuid.New().String(),
+		GoalID:    proof.GoalID.String(),
+		ProofID:   proof.ID.String(),
+		CreatedAt: time.Now().Unix(),
+	})
+}
 
-	// Threshold: max(3, 5% of contributors)
+// proofVerificationThreshold is how many satisfied votes verify a proof: max(3, 5% of contributors)
+func proofVerificationThreshold(contributorCount int64) int64 {
 	threshold := int64(3)
 	fivePercent := int64(float64(contributorCount) * 0.05)
 	if fivePercent > threshold {
 		threshold = fivePercent
 	}
-
-	if satisfied >= threshold {
-		// Proof is verified!
-		// Update goal status if needed? Or just emit event.
-		// Usually Goal status updates to VERIFIED.
-		
-		if s.publisher != nil {
-			event := events.ProofVerified{
-				ID:        uuid.New().String(),
-				GoalID:    goalID.String(),
-				ProofID:   proofID.String(),
-				CreatedAt: time.Now().Unix(),
-			}
-			s.publisher.Publish("ProofVerified", event)
-		}
-	}
+	return threshold
 }
 
-// GetVotesByProof retrieves all votes for a proof
-func (s *VoteService) GetVotesByProof(proofID uuid.UUID) ([]models.Vote, error) {
-	return s.repo.Vote.GetVotesByProofID(proofID)
+// weightedVerificationPercent is the share of a goal's confirmed contributions whose
+// contributors must be satisfied to verify a proof under weighted voting
+const weightedVerificationPercent = 50.0
+
+// GetVoteStats retrieves vote statistics for a proof, with its verification status
+func (s *VoteService) GetVoteStats(ctx context.Context, proofID uuid.UUID) (*dto.VoteStats, error) {
+	return s.voteStats(ctx, s.repo, proofID)
 }
 
-// GetVoteStats retrieves vote statistics for a proof
-func (s *VoteService) GetVoteStats(proofID uuid.UUID) (*dto.VoteStats, error) {
-	total, satisfied, err := s.repo.Vote.GetVoteStats(proofID)
+// voteStats is GetVoteStats reading through repo, which lets a caller that just changed the
+// votes read them back from the primary
+func (s *VoteService) voteStats(ctx context.Context, repo *repository.Repository, proofID uuid.UUID) (*dto.VoteStats, error) {
+	proof, err := repo.Proof.GetProofByID(ctx, proofID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProofNotFound
+		}
+		return nil, err
+	}
+
+	goal, err := repo.Goal.GetGoalByIDSimple(ctx, proof.GoalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
 		return nil, err
 	}
 
-	satisfactionRate := float64(0)
-	if total > 0 {
-		satisfactionRate = (float64(satisfied) / float64(total)) * 100
+	tally, err := repo.Vote.GetVoteTally(ctx, proof)
+	if err != nil {
+		return nil, err
 	}
 
 	return &dto.VoteStats{
-		TotalVotes:       total,
-		SatisfiedVotes:   satisfied,
-		UnsatisfiedVotes: total - satisfied,
-		SatisfactionRate: satisfactionRate,
+		TotalVotes:               tally.TotalVotes,
+		SatisfiedVotes:           tally.SatisfiedVotes,
+		UnsatisfiedVotes:         tally.TotalVotes - tally.SatisfiedVotes,
+		SatisfactionRate:         calculatePercent(tally.SatisfiedVotes, tally.TotalVotes),
+		RequiredSatisfiedVotes:   proofVerificationThreshold(tally.Contributors),
+		WeightedVoting:           goal.WeightedVoting,
+		SatisfiedAmount:          tally.SatisfiedAmount,
+		UnsatisfiedAmount:        tally.VotedAmount - tally.SatisfiedAmount,
+		TotalContributions:       tally.TotalContributions,
+		WeightedSatisfactionRate: calculatePercent(tally.SatisfiedAmount, tally.TotalContributions),
+		RequiredWeightedRate:     weightedVerificationPercent,
+		Status:                   proof.Status,
+		VerifiedAt:               proof.VerifiedAt,
+		RejectedAt:               proof.RejectedAt,
 	}, nil
 }
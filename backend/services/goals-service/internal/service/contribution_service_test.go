@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+func TestConfirmContributionRedelivered(t *testing.T) {
+	repo := newTestRepo(t)
+	contributions := NewContributionService(repo, nil, nil, nil, 0, 0)
+	ctx := context.Background()
+
+	goal := createTestGoal(t, repo, uuid.New())
+	intent := &models.Contribution{GoalID: goal.ID, UserID: uuid.New(), Amount: 50_000, Currency: goal.Currency}
+	if err := repo.Contribution.CreateContribution(ctx, intent); err != nil {
+		t.Fatalf("CreateContribution: %v", err)
+	}
+
+	paymentID := uuid.New()
+	for i := 0; i < 2; i++ {
+		if _, err := contributions.ConfirmContribution(ctx, intent.ID, paymentID); err != nil {
+			t.Fatalf("ConfirmContribution delivery %d: %v", i+1, err)
+		}
+	}
+
+	total, err := repo.Goal.GetTotalConfirmedContributions(ctx, goal.ID)
+	if err != nil {
+		t.Fatalf("GetTotalConfirmedContributions: %v", err)
+	}
+	if total != 50_000 {
+		t.Errorf("confirmed total = %d, want 50000", total)
+	}
+	assertOutbox(t, repo, map[string]int{"ContributionConfirmed": 1})
+}
+
+// TestConfirmationRedeliveredAfterRefund redelivers the payment event of a contribution that
+// has since been refunded; the contribution must stay refunded
+func TestConfirmationRedeliveredAfterRefund(t *testing.T) {
+	repo := newTestRepo(t)
+	users := newTestUsersClient(t)
+	contributions := NewContributionService(repo, nil, nil, nil, 0, 0)
+	refunds := NewRefundService(repo, users, nil, time.Hour)
+	ctx := context.Background()
+
+	goal := createTestGoal(t, repo, uuid.New())
+	contribution := createTestContribution(t, repo, goal, uuid.New(), 100_000)
+	setGoalStatus(t, repo, goal, models.GoalStatusClosed)
+
+	refund, err := refunds.InitiateRefund(ctx, goal.OwnerID, &dto.InitiateRefundRequest{
+		GoalID:           goal.ID.String(),
+		RefundPercentage: 100,
+	})
+	if err != nil {
+		t.Fatalf("InitiateRefund: %v", err)
+	}
+	for _, disbursement := range refund.Disbursements {
+		if err := refunds.UpdateDisbursementStatus(ctx, disbursement.ID, models.RefundStatusCompleted, nil); err != nil {
+			t.Fatalf("UpdateDisbursementStatus: %v", err)
+		}
+	}
+
+	if _, err := contributions.ConfirmContribution(ctx, contribution.ID, *contribution.PaymentID); err != nil {
+		t.Fatalf("ConfirmContribution: %v", err)
+	}
+
+	after, err := repo.Contribution.GetContributionByID(ctx, contribution.ID)
+	if err != nil {
+		t.Fatalf("GetContributionByID: %v", err)
+	}
+	if after.Status != models.ContributionStatusRefunded {
+		t.Errorf("contribution status = %s, want %s", after.Status, models.ContributionStatusRefunded)
+	}
+	if got := outboxCounts(t, repo)["ContributionConfirmed"]; got != 0 {
+		t.Errorf("ContributionConfirmed events = %d, want 0", got)
+	}
+}
+
+func TestCheckFixedAmount(t *testing.T) {
+	tests := []struct {
+		name           string
+		fixed          int64
+		amount         int64
+		allowMultiples bool
+		wantErr        bool
+	}{
+		{name: "any amount without a fixed amount", fixed: 0, amount: 12_345},
+		{name: "exact amount", fixed: 5_000, amount: 5_000},
+		{name: "exact amount with multiples allowed", fixed: 5_000, amount: 5_000, allowMultiples: true},
+		{name: "multiple with multiples allowed", fixed: 5_000, amount: 15_000, allowMultiples: true},
+		{name: "multiple without the flag", fixed: 5_000, amount: 15_000, wantErr: true},
+		{name: "less than the fixed amount", fixed: 5_000, amount: 4_999, wantErr: true},
+		{name: "more than the fixed amount", fixed: 5_000, amount: 5_001, wantErr: true},
+		{name: "not a multiple", fixed: 5_000, amount: 12_500, allowMultiples: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := &models.Goal{FixedContributionAmount: tt.fixed}
+			err := checkFixedAmount(goal, dto.CreateContributionRequest{Amount: tt.amount, AllowMultiples: tt.allowMultiples})
+			if tt.wantErr {
+				if code := errorCode(err); code != "fixed_amount_mismatch" {
+					t.Errorf("err = %v, want fixed_amount_mismatch", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("err = %v, want none", err)
+			}
+		})
+	}
+}
+
+func TestCreateContributionEnforcesFixedAmount(t *testing.T) {
+	repo := newTestRepo(t)
+	s := NewContributionService(repo, nil, nil, nil, 0, 0)
+	ctx := context.Background()
+	goal := createTestGoal(t, repo, uuid.New(), func(g *models.Goal) { g.FixedContributionAmount = 5_000 })
+
+	if _, err := s.CreateContribution(ctx, uuid.New(), dto.CreateContributionRequest{GoalID: goal.ID, Amount: 7_000}); errorCode(err) != "fixed_amount_mismatch" {
+		t.Fatalf("mismatched amount: err = %v, want fixed_amount_mismatch", err)
+	}
+	for _, req := range []dto.CreateContributionRequest{
+		{GoalID: goal.ID, Amount: 5_000},
+		{GoalID: goal.ID, Amount: 10_000, AllowMultiples: true},
+	} {
+		intent, err := s.CreateContribution(ctx, uuid.New(), req)
+		if err != nil {
+			t.Fatalf("CreateContribution(%d): %v", req.Amount, err)
+		}
+		if intent.Amount != req.Amount {
+			t.Errorf("intent amount = %d, want %d", intent.Amount, req.Amount)
+		}
+	}
+
+	// Only the two accepted intents were recorded
+	intents, err := repo.Primary().Contribution.GetContributionsByGoalID(ctx, goal.ID)
+	if err != nil {
+		t.Fatalf("GetContributionsByGoalID: %v", err)
+	}
+	if len(intents) != 2 {
+		t.Errorf("%d intents recorded, want 2", len(intents))
+	}
+}
+
+func TestCreateContributionRejectsCurrencyMismatch(t *testing.T) {
+	repo := newTestRepo(t)
+	s := NewContributionService(repo, nil, nil, nil, 0, 0)
+	ctx := context.Background()
+	// A goal in a currency contributions are not requested in
+	goal := createTestGoal(t, repo, uuid.New(), func(g *models.Goal) { g.Currency = "GHS" })
+
+	for _, currency := range []string{"NGN", "USD"} {
+		_, err := s.CreateContribution(ctx, uuid.New(), dto.CreateContributionRequest{GoalID: goal.ID, Amount: 5_000, Currency: currency})
+		if code := errorCode(err); code != "currency_mismatch" {
+			t.Errorf("contribution in %s to a GHS goal: err = %v, want currency_mismatch", currency, err)
+		}
+	}
+	for _, currency := range []string{"", "ghs"} {
+		intent, err := s.CreateContribution(ctx, uuid.New(), dto.CreateContributionRequest{GoalID: goal.ID, Amount: 5_000, Currency: currency})
+		if err != nil {
+			t.Fatalf("contribution in %q: %v", currency, err)
+		}
+		if intent.Currency != "GHS" {
+			t.Errorf("intent currency = %s, want the goal's GHS", intent.Currency)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/metrics"
+)
+
+// RepairModeArchive moves orphaned rows into <table>_archive tables
+const RepairModeArchive = "archive"
+
+const orphanSampleSize = 10
+
+var ErrInvalidRepairMode = apperrors.Validation("invalid_repair_mode", "repair must be 'archive' when set")
+
+// DataQualityService runs data-quality checks over the goals schema
+type DataQualityService struct {
+	repo *repository.DataQualityRepository
+}
+
+// NewDataQualityService creates a new data-quality service
+func NewDataQualityService(repo *repository.DataQualityRepository) *DataQualityService {
+	return &DataQualityService{repo: repo}
+}
+
+// GetOrphanReport counts orphaned rows per check, optionally repairing them first
+func (s *DataQualityService) GetOrphanReport(ctx context.Context, repairMode string) (*dto.OrphanReport, error) {
+	if repairMode != "" && repairMode != RepairModeArchive {
+		return nil, ErrInvalidRepairMode
+	}
+
+	report := &dto.OrphanReport{
+		Checks:     make([]dto.OrphanReportEntry, 0, len(repository.OrphanChecks)),
+		RepairMode: repairMode,
+	}
+
+	for _, check := range repository.OrphanChecks {
+		entry := dto.OrphanReportEntry{Name: check.Name, Table: check.Table}
+
+		count, err := s.repo.CountOrphans(ctx, check)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", check.Name, err)
+		}
+		entry.Count = count
+
+		sampleIDs, err := s.repo.SampleOrphanIDs(ctx, check, orphanSampleSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample %s: %w", check.Name, err)
+		}
+		entry.SampleIDs = sampleIDs
+
+		if repairMode == RepairModeArchive && count > 0 {
+			repaired, err := s.repo.RepairOrphans(ctx, check)
+			if err != nil {
+				return nil, fmt.Errorf("failed to repair %s: %w", check.Name, err)
+			}
+			entry.Repaired = repaired
+			metrics.IncrementCounter("goals.orphan.repaired", "check:"+check.Name)
+		}
+
+		metrics.RecordGauge("goals.orphan.count", float64(count), "check:"+check.Name)
+		report.TotalOrphans += count
+		report.Checks = append(report.Checks, entry)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// orphanFixture is a schema seeded with one orphan of every class next to healthy rows of
+// the same tables
+type orphanFixture struct {
+	db   *gorm.DB
+	repo *repository.Repository
+
+	goal    *models.Goal
+	healthy *models.Contribution
+	// orphans maps each check name to the ID of the row seeded for it
+	orphans map[string]uuid.UUID
+}
+
+func newOrphanFixture(t *testing.T) *orphanFixture {
+	t.Helper()
+	db := testdb.Open(t)
+	testdb.DropForeignKeys(t, db)
+	f := &orphanFixture{db: db, repo: repository.NewRepository(db), orphans: make(map[string]uuid.UUID)}
+
+	ownerID := uuid.New()
+	f.goal = createTestGoal(t, f.repo, ownerID)
+	f.healthy = createTestContribution(t, f.repo, f.goal, uuid.New(), 50_000)
+	proof := &models.Proof{GoalID: f.goal.ID, SubmittedBy: ownerID, Title: "Receipt", SubmittedAt: time.Now()}
+	f.create(t, proof)
+	f.create(t, &models.Vote{ProofID: proof.ID, VoterID: f.healthy.UserID, IsSatisfied: true, VotedAt: time.Now()})
+	f.create(t, f.withdrawal(f.goal.ID))
+	f.create(t, f.disbursement(f.healthy.ID))
+
+	missingGoal := f.contribution(uuid.New(), nil)
+	f.create(t, missingGoal)
+	f.orphans["contributions_without_goal"] = missingGoal.ID
+
+	missingMilestoneID := uuid.New()
+	missingMilestone := f.contribution(f.goal.ID, &missingMilestoneID)
+	f.create(t, missingMilestone)
+	f.orphans["contributions_without_milestone"] = missingMilestone.ID
+
+	withdrawal := f.withdrawal(uuid.New())
+	f.create(t, withdrawal)
+	f.orphans["withdrawals_without_goal"] = withdrawal.ID
+
+	vote := &models.Vote{ProofID: uuid.New(), VoterID: uuid.New(), IsSatisfied: false, VotedAt: time.Now()}
+	f.create(t, vote)
+	f.orphans["votes_without_proof"] = vote.ID
+
+	disbursement := f.disbursement(uuid.New())
+	f.create(t, disbursement)
+	f.orphans["disbursements_without_contribution"] = disbursement.ID
+
+	return f
+}
+
+// create inserts row as it is, without touching its associations
+func (f *orphanFixture) create(t *testing.T, row interface{}) {
+	t.Helper()
+	if err := f.db.Omit(clause.Associations).Create(row).Error; err != nil {
+		t.Fatalf("failed to seed %T: %v", row, err)
+	}
+}
+
+func (f *orphanFixture) contribution(goalID uuid.UUID, milestoneID *uuid.UUID) *models.Contribution {
+	paymentID := uuid.New()
+	return &models.Contribution{
+		GoalID:      goalID,
+		MilestoneID: milestoneID,
+		UserID:      uuid.New(),
+		PaymentID:   &paymentID,
+		Amount:      20_000,
+		Currency:    "NGN",
+		Status:      models.ContributionStatusConfirmed,
+		NetAmount:   20_000,
+	}
+}
+
+func (f *orphanFixture) withdrawal(goalID uuid.UUID) *models.Withdrawal {
+	return &models.Withdrawal{
+		GoalID:        goalID,
+		OwnerID:       uuid.New(),
+		Amount:        10_000,
+		Currency:      "NGN",
+		BankName:      "Test Bank",
+		AccountNumber: "0123456789",
+		AccountName:   "Goal Owner",
+		Status:        models.WithdrawalStatusPending,
+		RequestedAt:   time.Now(),
+	}
+}
+
+func (f *orphanFixture) disbursement(contributionID uuid.UUID) *models.RefundDisbursement {
+	return &models.RefundDisbursement{
+		RefundID:       uuid.New(),
+		ContributionID: contributionID,
+		UserID:         uuid.New(),
+		Amount:         5_000,
+		Currency:       "NGN",
+		Status:         models.RefundStatusPending,
+		CreatedAt:      time.Now(),
+	}
+}
+
+func (f *orphanFixture) service() *DataQualityService {
+	return NewDataQualityService(repository.NewDataQualityRepository(f.db))
+}
+
+func TestOrphanReportCountsEachClass(t *testing.T) {
+	f := newOrphanFixture(t)
+
+	report, err := f.service().GetOrphanReport(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetOrphanReport: %v", err)
+	}
+
+	if len(report.Checks) != len(repository.OrphanChecks) {
+		t.Fatalf("report has %d checks, want %d", len(report.Checks), len(repository.OrphanChecks))
+	}
+	for _, entry := range report.Checks {
+		want, ok := f.orphans[entry.Name]
+		if !ok {
+			t.Errorf("check %s has no seeded orphan", entry.Name)
+			continue
+		}
+		if entry.Count != 1 {
+			t.Errorf("%s: count = %d, want 1", entry.Name, entry.Count)
+		}
+		if len(entry.SampleIDs) != 1 || entry.SampleIDs[0] != want.String() {
+			t.Errorf("%s: sample IDs = %v, want [%s]", entry.Name, entry.SampleIDs, want)
+		}
+		if entry.Repaired != 0 {
+			t.Errorf("%s: repaired %d rows without repair=archive", entry.Name, entry.Repaired)
+		}
+	}
+	if report.TotalOrphans != int64(len(f.orphans)) {
+		t.Errorf("total orphans = %d, want %d", report.TotalOrphans, len(f.orphans))
+	}
+
+	// Reporting alone leaves every row where it was
+	var contributions int64
+	f.db.Model(&models.Contribution{}).Count(&contributions)
+	if contributions != 3 {
+		t.Errorf("%d contributions after a report, want 3", contributions)
+	}
+}
+
+func TestOrphanReportArchivesOrphans(t *testing.T) {
+	f := newOrphanFixture(t)
+	s := f.service()
+
+	report, err := s.GetOrphanReport(context.Background(), RepairModeArchive)
+	if err != nil {
+		t.Fatalf("GetOrphanReport(archive): %v", err)
+	}
+	for _, entry := range report.Checks {
+		if entry.Count != 1 || entry.Repaired != 1 {
+			t.Errorf("%s: count %d, repaired %d; want 1 and 1", entry.Name, entry.Count, entry.Repaired)
+		}
+	}
+
+	report, err = s.GetOrphanReport(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetOrphanReport after repair: %v", err)
+	}
+	if report.TotalOrphans != 0 {
+		t.Errorf("%d orphans left after repair, want 0", report.TotalOrphans)
+	}
+
+	for table, id := range map[string]uuid.UUID{
+		"contributions_archive":        f.orphans["contributions_without_goal"],
+		"withdrawals_archive":          f.orphans["withdrawals_without_goal"],
+		"votes_archive":                f.orphans["votes_without_proof"],
+		"refund_disbursements_archive": f.orphans["disbursements_without_contribution"],
+	} {
+		var archived int64
+		if err := f.db.Table(table).Where("id = ?", id).Count(&archived).Error; err != nil {
+			t.Fatalf("failed to read %s: %v", table, err)
+		}
+		if archived != 1 {
+			t.Errorf("%s holds %d copies of %s, want 1", table, archived, id)
+		}
+	}
+
+	// A dangling milestone reference is cleared; the contribution itself stays
+	detached, err := f.repo.Contribution.GetContributionByID(context.Background(), f.orphans["contributions_without_milestone"])
+	if err != nil {
+		t.Fatalf("contribution with a missing milestone was removed: %v", err)
+	}
+	if detached.MilestoneID != nil {
+		t.Errorf("milestone reference = %s, want it cleared", detached.MilestoneID)
+	}
+	if _, err := f.repo.Contribution.GetContributionByID(context.Background(), f.healthy.ID); err != nil {
+		t.Errorf("healthy contribution was removed: %v", err)
+	}
+}
+
+func TestOrphanReportRejectsUnknownRepairMode(t *testing.T) {
+	s := NewDataQualityService(nil)
+
+	if _, err := s.GetOrphanReport(context.Background(), "delete"); !errors.Is(err, ErrInvalidRepairMode) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidRepairMode)
+	}
+}
+
+func TestMyContributionsIncludeOrphans(t *testing.T) {
+	f := newOrphanFixture(t)
+	orphanID := f.orphans["contributions_without_goal"]
+	orphan, err := f.repo.Contribution.GetContributionByID(context.Background(), orphanID)
+	if err != nil {
+		t.Fatalf("GetContributionByID: %v", err)
+	}
+	s := NewContributionService(f.repo, nil, nil, nil, 0, 0)
+
+	items, err := s.GetContributionsByUser(context.Background(), orphan.UserID)
+	if err != nil {
+		t.Fatalf("GetContributionsByUser with a deleted goal: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != orphanID {
+		t.Fatalf("got %d contributions, want only the orphaned %s", len(items), orphanID)
+	}
+}
+
+func TestConfirmContributionOfDeletedGoal(t *testing.T) {
+	f := newOrphanFixture(t)
+	pending := f.contribution(uuid.New(), nil)
+	pending.Status = models.ContributionStatusPending
+	pending.PaymentID = nil
+	f.create(t, pending)
+	s := NewContributionService(f.repo, nil, nil, nil, 0, 0)
+
+	_, err := s.ConfirmContribution(context.Background(), pending.ID, uuid.New())
+	if !errors.Is(err, ErrGoalNotFound) {
+		t.Fatalf("err = %v, want %v", err, ErrGoalNotFound)
+	}
+	stored, err := f.repo.Contribution.GetContributionByID(context.Background(), pending.ID)
+	if err != nil {
+		t.Fatalf("GetContributionByID: %v", err)
+	}
+	if stored.Status != models.ContributionStatusPending {
+		t.Errorf("status = %s, want it left %s", stored.Status, models.ContributionStatusPending)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+const testDisclosureThreshold = 50_000_000
+
+func TestWillDiscloseIdentity(t *testing.T) {
+	tests := []struct {
+		threshold int64
+		amount    int64
+		want      bool
+	}{
+		{threshold: testDisclosureThreshold, amount: testDisclosureThreshold - 1, want: false},
+		{threshold: testDisclosureThreshold, amount: testDisclosureThreshold, want: true},
+		{threshold: testDisclosureThreshold, amount: testDisclosureThreshold + 1, want: true},
+		{threshold: 0, amount: testDisclosureThreshold, want: false},
+	}
+	for _, tt := range tests {
+		s := NewContributionService(nil, nil, nil, nil, 0, tt.threshold)
+		if got := s.WillDiscloseIdentity(tt.amount); got != tt.want {
+			t.Errorf("threshold %d: WillDiscloseIdentity(%d) = %v, want %v", tt.threshold, tt.amount, got, tt.want)
+		}
+	}
+}
+
+// newContactsUsersClient returns a users-service client backed by a fake users-service that
+// names every user after their ID and gives them an example.com email
+func newContactsUsersClient(t *testing.T) *UsersClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		users := []map[string]interface{}{}
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			users = append(users, map[string]interface{}{
+				"id":         id,
+				"username":   "user-" + id[:8],
+				"first_name": "First " + id[:8],
+				"last_name":  "Last",
+				"email":      id[:8] + "@example.com",
+			})
+		}
+		switch r.URL.Path {
+		case "/internal/users/display-names", "/internal/users/contacts":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"users": users})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return NewUsersClient(server.URL, time.Minute)
+}
+
+func createAnonymousContribution(t *testing.T, s *ContributionService, goal *models.Goal, amount int64) *models.Contribution {
+	t.Helper()
+	contribution := createTestContribution(t, s.repo, goal, uuid.New(), amount)
+	contribution.IsAnonymous = true
+	if err := s.repo.Contribution.UpdateContribution(context.Background(), contribution); err != nil {
+		t.Fatalf("failed to make the contribution anonymous: %v", err)
+	}
+	return contribution
+}
+
+// TestOwnerListingDisclosesAtThreshold lists anonymous contributions either side of the
+// threshold to the goal owner: only the one at the threshold is identified
+func TestOwnerListingDisclosesAtThreshold(t *testing.T) {
+	repo := newTestRepo(t)
+	s := NewContributionService(repo, newContactsUsersClient(t), nil, nil, 0, testDisclosureThreshold)
+	ctx := context.Background()
+	goal := createTestGoal(t, repo, uuid.New(), func(g *models.Goal) { g.TargetAmount = 200_000_000 })
+	below := createAnonymousContribution(t, s, goal, testDisclosureThreshold-1)
+	at := createAnonymousContribution(t, s, goal, testDisclosureThreshold)
+
+	list, err := s.GetOwnerContributions(ctx, goal.ID, goal.OwnerID, 1, 20)
+	if err != nil {
+		t.Fatalf("GetOwnerContributions: %v", err)
+	}
+	items := make(map[uuid.UUID]int, len(list.Items))
+	for i, item := range list.Items {
+		items[item.ID] = i
+	}
+
+	masked := list.Items[items[below.ID]]
+	if masked.IdentityDisclosed || masked.UserID != uuid.Nil || masked.Name != "" || masked.Email != "" || masked.DisplayName != AnonymousDisplayName {
+		t.Errorf("contribution just below the threshold = %+v, want it masked", masked)
+	}
+
+	disclosed := list.Items[items[at.ID]]
+	prefix := at.UserID.String()[:8]
+	if !disclosed.IdentityDisclosed || disclosed.UserID != at.UserID || disclosed.Name != "First "+prefix+" Last" || disclosed.Email != prefix+"@example.com" {
+		t.Errorf("contribution at the threshold = %+v, want the contributor's name and email", disclosed)
+	}
+
+	if _, err := s.GetOwnerContributions(ctx, goal.ID, uuid.New(), 1, 20); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("GetOwnerContributions by someone else: err = %v, want %v", err, ErrUnauthorized)
+	}
+}
+
+// TestPublicFeedNeverDiscloses reads the public feed, as the owner and as anyone else,
+// for an anonymous contribution well above the threshold
+func TestPublicFeedNeverDiscloses(t *testing.T) {
+	repo := newTestRepo(t)
+	s := NewContributionService(repo, newContactsUsersClient(t), nil, nil, 0, testDisclosureThreshold)
+	goal := createTestGoal(t, repo, uuid.New(), func(g *models.Goal) { g.TargetAmount = 200_000_000 })
+	large := createAnonymousContribution(t, s, goal, 2*testDisclosureThreshold)
+
+	for name, viewerID := range map[string]uuid.UUID{"owner": goal.OwnerID, "stranger": uuid.New(), "anonymous": uuid.Nil} {
+		t.Run(name, func(t *testing.T) {
+			feed, err := s.GetContributionFeed(context.Background(), goal.ID, viewerID, 1, 20)
+			if err != nil {
+				t.Fatalf("GetContributionFeed: %v", err)
+			}
+			if len(feed.Items) != 1 {
+				t.Fatalf("feed has %d items, want 1", len(feed.Items))
+			}
+			item := feed.Items[0]
+			if item.UserID != uuid.Nil || item.DisplayName != AnonymousDisplayName {
+				t.Errorf("feed item = %+v, want contributor %s masked", item, large.UserID)
+			}
+		})
+	}
+}
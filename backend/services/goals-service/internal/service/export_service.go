@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// What a goal export may contain
+const (
+	ExportContributions = "contributions"
+	ExportWithdrawals   = "withdrawals"
+	ExportAll           = "all"
+)
+
+// exportNameBatch is how many contributions are held back at a time so their
+// contributors' names can be looked up in one request
+const exportNameBatch = 200
+
+// ErrInvalidExportType is returned for an export type other than contributions,
+// withdrawals or all
+var ErrInvalidExportType = apperrors.Validation("invalid_export_type", "type must be one of contributions, withdrawals, all")
+
+// ExportService streams a goal's contributions and withdrawals for its owner
+type ExportService struct {
+	repo        *repository.Repository
+	usersClient *UsersClient
+}
+
+// NewExportService creates a new export service
+func NewExportService(repo *repository.Repository, usersClient *UsersClient) *ExportService {
+	return &ExportService{repo: repo, usersClient: usersClient}
+}
+
+// PrepareExport checks that userID owns the goal and that exportType is valid, before
+// anything is written, and returns the goal
+func (s *ExportService) PrepareExport(ctx context.Context, goalID, userID uuid.UUID, exportType string) (*models.Goal, error) {
+	switch exportType {
+	case ExportContributions, ExportWithdrawals, ExportAll:
+	default:
+		return nil, ErrInvalidExportType
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if goal.OwnerID != userID {
+		return nil, ErrUnauthorized
+	}
+	return goal, nil
+}
+
+// StreamExport calls emit with each row of a goal's export, oldest first: contributions,
+// then withdrawals when exportType is ExportAll. Anonymous contributors are shown as
+// AnonymousDisplayName.
+func (s *ExportService) StreamExport(ctx context.Context, goal *models.Goal, exportType string, emit func(dto.ExportRow) error) error {
+	if exportType == ExportContributions || exportType == ExportAll {
+		if err := s.streamContributions(ctx, goal, emit); err != nil {
+			return err
+		}
+	}
+	if exportType == ExportWithdrawals || exportType == ExportAll {
+		return s.repo.Withdrawal.EachWithdrawalByGoalID(ctx, goal.ID, func(withdrawal *models.Withdrawal) error {
+			return emit(dto.ExportRow{
+				Type:      "withdrawal",
+				ID:        withdrawal.ID,
+				Date:      withdrawal.RequestedAt,
+				Amount:    withdrawal.Amount,
+				Currency:  withdrawal.Currency,
+				Status:    string(withdrawal.Status),
+				Reference: withdrawal.TransferReference,
+			})
+		})
+	}
+	return nil
+}
+
+// streamContributions emits a goal's contributions in batches of exportNameBatch, naming
+// each batch's contributors with one users-service lookup
+func (s *ExportService) streamContributions(ctx context.Context, goal *models.Goal, emit func(dto.ExportRow) error) error {
+	batch := make([]models.Contribution, 0, exportNameBatch)
+
+	flush := func() error {
+		var userIDs []uuid.UUID
+		for i := range batch {
+			batch[i].MaskContributor(goal.OwnerID)
+			if batch[i].UserID != uuid.Nil {
+				userIDs = append(userIDs, batch[i].UserID)
+			}
+		}
+		var names map[uuid.UUID]string
+		if s.usersClient != nil && len(userIDs) > 0 {
+			names = s.usersClient.DisplayNames(userIDs)
+		}
+
+		for _, contribution := range batch {
+			row := dto.ExportRow{
+				Type:        "contribution",
+				ID:          contribution.ID,
+				Date:        contribution.CreatedAt,
+				Contributor: AnonymousDisplayName,
+				Amount:      contribution.Amount,
+				Currency:    contribution.Currency,
+				Status:      string(contribution.Status),
+			}
+			if name, ok := names[contribution.UserID]; ok && contribution.UserID != uuid.Nil {
+				row.Contributor = name
+			}
+			if contribution.PaymentID != nil {
+				row.Reference = contribution.PaymentID.String()
+			}
+			if err := emit(row); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	err := s.repo.Contribution.EachContributionByGoalID(ctx, goal.ID, func(contribution *models.Contribution) error {
+		batch = append(batch, *contribution)
+		if len(batch) < exportNameBatch {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
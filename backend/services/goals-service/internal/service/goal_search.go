@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	maxSearchQueryLength = 200
+	maxSearchPageSize    = 50
+	// searchSnippetContext is how many runes of the description a naive search snippet
+	// keeps either side of the first match
+	searchSnippetContext = 80
+)
+
+// ErrInvalidSearchQuery is returned for an empty search or one over 200 characters
+var ErrInvalidSearchQuery = apperrors.Validation("invalid_query", "q is required and must be at most 200 characters")
+
+// highlightMarkup turns the repository's highlight markers into HTML
+var highlightMarkup = strings.NewReplacer(
+	repository.SearchHighlightStart, "<mark>",
+	repository.SearchHighlightStop, "</mark>",
+)
+
+// SearchGoals ranks listed goals by relevance to query, boosting open, well-funded and
+// recent goals. A query of plain words is a full-text search matching every word, the last
+// as a prefix; anything else (e.g. "c++" or "50%") falls back to a substring search.
+// Highlights and snippets are HTML-escaped text with matches wrapped in <mark>.
+func (s *GoalService) SearchGoals(ctx context.Context, query string, page, pageSize int) ([]dto.GoalSearchHit, int64, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return nil, 0, ErrInvalidSearchQuery
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > maxSearchPageSize {
+		pageSize = maxSearchPageSize
+	}
+	offset := (page - 1) * pageSize
+
+	var (
+		hits  []repository.GoalSearchHit
+		total int64
+		err   error
+	)
+	tsquery, fullText := fullTextQuery(query)
+	if fullText {
+		hits, total, err = s.repo.Goal.SearchGoalsFullText(ctx, tsquery, pageSize, offset)
+	} else {
+		hits, total, err = s.repo.Goal.SearchGoalsNaive(ctx, query, pageSize, offset)
+	}
+	if err != nil {
+		return nil, 0, errors.New("failed to search goals")
+	}
+
+	var match *regexp.Regexp
+	if !fullText {
+		match = regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+	}
+
+	results := make([]dto.GoalSearchHit, len(hits))
+	for i := range hits {
+		hit := &hits[i]
+		hideBankDetails(&hit.Goal, uuid.Nil)
+		if !fullText {
+			hit.TitleHighlight = markMatches(hit.Title, match)
+			hit.Snippet = naiveSnippet(hit.Description, match)
+		}
+		results[i] = dto.GoalSearchHit{
+			Goal:           hit.Goal,
+			Score:          hit.Score,
+			TitleHighlight: highlightMarkup.Replace(html.EscapeString(hit.TitleHighlight)),
+			Snippet:        highlightMarkup.Replace(html.EscapeString(hit.Snippet)),
+		}
+	}
+	return results, total, nil
+}
+
+// fullTextQuery turns a search of plain words into a to_tsquery expression matching every
+// word, the last as a prefix so results appear while the user is still typing. It reports
+// false when a word holds anything but letters (with their accents) and digits, which
+// to_tsquery could misread as operators.
+func fullTextQuery(query string) (string, bool) {
+	words := strings.Fields(query)
+	for _, word := range words {
+		for _, r := range word {
+			if !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r) {
+				return "", false
+			}
+		}
+	}
+	words[len(words)-1] += ":*"
+	return strings.Join(words, " & "), true
+}
+
+// markMatches puts the repository's highlight markers around each match in text
+func markMatches(text string, match *regexp.Regexp) string {
+	return match.ReplaceAllStringFunc(text, func(m string) string {
+		return repository.SearchHighlightStart + m + repository.SearchHighlightStop
+	})
+}
+
+// naiveSnippet returns the part of description around its first match, with the matches
+// marked, or its beginning when only the title matched
+func naiveSnippet(description string, match *regexp.Regexp) string {
+	runes := []rune(description)
+	start, end := 0, len(runes)
+	if loc := match.FindStringIndex(description); loc != nil {
+		start = utf8.RuneCountInString(description[:loc[0]]) - searchSnippetContext
+		end = utf8.RuneCountInString(description[:loc[1]]) + searchSnippetContext
+	} else {
+		end = 2 * searchSnippetContext
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	snippet := markMatches(strings.TrimSpace(string(runes[start:end])), match)
+	if start > 0 {
+		snippet = "… " + snippet
+	}
+	if end < len(runes) {
+		snippet += " …"
+	}
+	return snippet
+}
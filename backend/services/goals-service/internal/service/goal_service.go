@@ -1,44 +1,101 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"log"
+	"reflect"
 	"time"
 
 	"github.com/gofund/goals-service/internal/dto"
 	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/state"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/metrics"
 	"github.com/gofund/shared/models"
+	"github.com/gofund/shared/validator"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// Service errors carry their kind, which the controllers map to a status code; see
+// shared/errors for the mapping
 var (
-	ErrGoalNotFound          = errors.New("goal not found")
-	ErrMilestoneNotFound     = errors.New("milestone not found")
-	ErrUnauthorized          = errors.New("unauthorized")
-	ErrInsufficientBalance   = errors.New("insufficient balance")
-	ErrBankDetailsRequired   = errors.New("bank details required for withdrawal")
-	ErrInvalidGoalStatus     = errors.New("invalid goal status for this operation")
-	ErrContributionNotFound  = errors.New("contribution not found")
-	ErrProofNotFound         = errors.New("proof not found")
-	ErrNotContributor        = errors.New("only contributors can vote")
-	ErrAlreadyVoted          = errors.New("you have already voted on this proof")
+	ErrGoalNotFound          = apperrors.NotFound("goal_not_found", "goal not found")
+	ErrMilestoneNotFound     = apperrors.NotFound("milestone_not_found", "milestone not found")
+	ErrUnauthorized          = apperrors.Forbidden("forbidden", "unauthorized")
+	ErrInsufficientBalance   = apperrors.Conflict("insufficient_balance", "insufficient balance")
+	ErrBankDetailsRequired   = apperrors.Validation("bank_details_required", "bank details required for withdrawal")
+	ErrInvalidGoalStatus     = apperrors.Conflict("invalid_goal_status", "invalid goal status for this operation")
+	ErrContributionNotFound  = apperrors.NotFound("contribution_not_found", "contribution not found")
+	ErrProofNotFound         = apperrors.NotFound("proof_not_found", "proof not found")
+	ErrNotContributor        = apperrors.Forbidden("not_contributor", "only contributors can vote")
+	ErrAlreadyVoted          = apperrors.Conflict("already_voted", "you have already voted on this proof")
+	ErrGoalTargetReached     = apperrors.Conflict("goal_target_reached", "goal_target_reached")
+	ErrRefundInProgress      = apperrors.Conflict("refund_in_progress", "a refund is in progress for this goal")
+	ErrFixedAmountMismatch   = apperrors.Validation("fixed_amount_mismatch", "amount does not match the goal's fixed contribution amount")
+	ErrGoalDeadlinePassed    = apperrors.Conflict("goal_deadline_passed", "goal deadline has passed")
+	ErrGoalNotOpen           = apperrors.Conflict("goal_not_open", "goal is not accepting contributions")
+	ErrInvalidAmount         = apperrors.Validation("invalid_amount", "amount must be greater than 0")
+	ErrMilestoneGoalMismatch = apperrors.Validation("milestone_goal_mismatch", "milestone does not belong to this goal")
+	ErrProofRequired         = apperrors.Conflict("proof_required", "a verified proof is required before withdrawing")
+	ErrKYCRequired           = apperrors.Forbidden("kyc_required", "KYC verification is required for withdrawals of this size")
+	ErrGoalSuspended         = apperrors.Conflict("goal_suspended", "goal has been suspended by an administrator")
+	ErrInvalidGoalSort       = apperrors.Validation("invalid_sort", "sort must be one of newest, most_funded, most_popular, ending_soon")
+	ErrCurrencyMismatch      = apperrors.Validation("currency_mismatch", "currency does not match the goal's currency")
+	ErrInvalidFeeMode        = apperrors.Validation("invalid_fee_mode", "fee mode must be absorb or add_on")
+	ErrAmountBelowFee        = apperrors.Validation("amount_below_fee", "amount does not cover the platform fee")
 )
 
 // GoalService handles business logic for goals
 type GoalService struct {
-	repo *repository.Repository
+	repo           *repository.Repository
+	audit          *AuditService
+	usersClient    *UsersClient
+	paymentsClient *PaymentsClient
+	invites        *InviteTokens
 }
 
-// NewGoalService creates a new goal service
-func NewGoalService(repo *repository.Repository) *GoalService {
-	return &GoalService{repo: repo}
+// NewGoalService creates a new goal service. The users and payments clients verify new
+// deposit accounts on goals that already hold contributions; invites signs the invite
+// links that open private goals.
+func NewGoalService(repo *repository.Repository, audit *AuditService, usersClient *UsersClient, paymentsClient *PaymentsClient, invites *InviteTokens) *GoalService {
+	return &GoalService{
+		repo:           repo,
+		audit:          audit,
+		usersClient:    usersClient,
+		paymentsClient: paymentsClient,
+		invites:        invites,
+	}
 }
 
 // CreateGoal creates a new goal with optional milestones
-func (s *GoalService) CreateGoal(ownerID uuid.UUID, req dto.CreateGoalRequest) (*models.Goal, error) {
+func (s *GoalService) CreateGoal(ctx context.Context, ownerID uuid.UUID, req dto.CreateGoalRequest) (*models.Goal, error) {
 	// Validate
 	if req.TargetAmount <= 0 {
-		return nil, errors.New("target amount must be greater than 0")
+		return nil, apperrors.Validation("invalid_target_amount", "target amount must be greater than 0")
+	}
+	if req.FixedContributionAmount < 0 {
+		return nil, apperrors.Validation("invalid_fixed_amount", "fixed contribution amount cannot be negative")
+	}
+	currency, err := validator.NormalizeCurrency(req.Currency)
+	if err != nil {
+		return nil, apperrors.Validation("unsupported_currency", err.Error())
+	}
+	feeMode, err := resolveFeeMode(req.FeeMode)
+	if err != nil {
+		return nil, err
+	}
+	if err := allocateMilestoneTargets(req.TargetAmount, 0, req.Milestones); err != nil {
+		return nil, err
+	}
+	visibility, ok, err := resolveVisibility(&req.Visibility, req.IsPublic)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		visibility = models.GoalVisibilityPublic
 	}
 
 	goal := &models.Goal{
@@ -46,20 +103,22 @@ func (s *GoalService) CreateGoal(ownerID uuid.UUID, req dto.CreateGoalRequest) (
 		Title:         req.Title,
 		Description:   req.Description,
 		TargetAmount:  req.TargetAmount,
-		Currency:      req.Currency,
+		Currency:      currency,
 		Deadline:      req.Deadline,
 		Status:        models.GoalStatusOpen,
-		DepositBankName:      req.BankName,
-		DepositAccountNumber: req.AccountNumber,
-		DepositAccountName:   req.AccountName,
-		IsPublic:             true,
+		DepositBankName:      req.DepositBankName,
+		DepositAccountNumber: req.DepositAccountNumber,
+		DepositAccountName:   req.DepositAccountName,
+		CloseOnTarget:        req.CloseOnTarget,
+		FixedContributionAmount: req.FixedContributionAmount,
+		RequireProofForWithdrawal: req.RequireProofForWithdrawal,
+		WeightedVoting:            req.WeightedVoting,
+		FeeMode:                   feeMode,
 	}
 
-	if req.IsPublic != nil {
-		goal.IsPublic = *req.IsPublic
-	}
+	setVisibility(goal, visibility)
 
-	if err := s.repo.Goal.CreateGoal(goal); err != nil {
+	if err := s.createGoalWithSlug(ctx, goal); err != nil {
 		return nil, err
 	}
 
@@ -79,28 +138,91 @@ func (s *GoalService) CreateGoal(ownerID uuid.UUID, req dto.CreateGoalRequest) (
 				Status:             models.MilestoneStatusPending,
 			}
 
-			if err := s.repo.Milestone.CreateMilestone(milestone); err != nil {
+			if err := s.repo.Milestone.CreateMilestone(ctx, milestone); err != nil {
 				return nil, err
 			}
 		}
+
+		if _, err := s.repo.Milestone.ActivateNextMilestone(ctx, goal.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reload with relationships, from the primary as a replica may not have the goal yet
+	return s.repo.Primary().Goal.GetGoalByID(ctx, goal.ID)
+}
+
+// GetGoal retrieves a goal by ID as seen by viewerID. A private goal is only found by its
+// owner, collaborators and contributors, or with a valid inviteToken.
+func (s *GoalService) GetGoal(ctx context.Context, id, viewerID uuid.UUID, inviteToken string) (*models.Goal, error) {
+	return s.getGoal(ctx, s.repo, id, viewerID, inviteToken)
+}
+
+// getGoal is GetGoal reading the goal through repo, which lets a caller that just changed
+// the goal read it back from the primary
+func (s *GoalService) getGoal(ctx context.Context, repo *repository.Repository, id, viewerID uuid.UUID, inviteToken string) (*models.Goal, error) {
+	goal, err := repo.Goal.GetGoalByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if err := s.viewGoal(ctx, goal, viewerID, inviteToken); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// GetInternalGoal retrieves the owner and status of a goal for other services
+func (s *GoalService) GetInternalGoal(ctx context.Context, id uuid.UUID) (*dto.InternalGoal, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
 	}
+	return &dto.InternalGoal{
+		ID:       goal.ID,
+		OwnerID:  goal.OwnerID,
+		Status:   goal.Status,
+		Currency: goal.Currency,
+	}, nil
+}
 
-	// Reload with relationships
-	return s.repo.Goal.GetGoalByID(goal.ID)
+// GetInternalOwnerGoals summarises a user's goals for other services
+func (s *GoalService) GetInternalOwnerGoals(ctx context.Context, ownerID uuid.UUID) (*dto.InternalOwnerGoals, error) {
+	count, err := s.repo.Goal.CountPublicGoalsByOwnerID(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.InternalOwnerGoals{OwnerID: ownerID, PublicGoalCount: count}, nil
 }
 
-// GetGoal retrieves a goal by ID
-func (s *GoalService) GetGoal(id uuid.UUID) (*models.Goal, error) {
-	return s.repo.Goal.GetGoalByID(id)
+// maskContributors hides the contributors of the goal's anonymous contributions from
+// everyone but the contributors themselves
+func maskContributors(goal *models.Goal, viewerID uuid.UUID) {
+	for i := range goal.Contributions {
+		goal.Contributions[i].MaskContributor(viewerID)
+	}
 }
 
 // GetGoalsByOwner retrieves all goals for an owner
-func (s *GoalService) GetGoalsByOwner(ownerID uuid.UUID) ([]models.Goal, error) {
-	return s.repo.Goal.GetGoalsByOwnerID(ownerID)
+func (s *GoalService) GetGoalsByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.Goal, error) {
+	return s.repo.Goal.GetGoalsByOwnerID(ctx, ownerID)
 }
 
-// ListPublicGoals retrieves all public goals with pagination
-func (s *GoalService) ListPublicGoals(page, pageSize int) ([]models.Goal, int64, error) {
+// ListPublicGoals retrieves all public goals with pagination, ordered by one of the
+// repository.GoalSort orderings (newest when empty). Unlisted and private goals are left
+// out, and bank details are hidden.
+func (s *GoalService) ListPublicGoals(ctx context.Context, sort string, page, pageSize int) ([]models.Goal, int64, error) {
+	if sort == "" {
+		sort = repository.GoalSortNewest
+	}
+	if !repository.IsValidGoalSort(sort) {
+		return nil, 0, ErrInvalidGoalSort
+	}
 	if page <= 0 {
 		page = 1
 	}
@@ -108,11 +230,31 @@ func (s *GoalService) ListPublicGoals(page, pageSize int) ([]models.Goal, int64,
 		pageSize = 10
 	}
 	offset := (page - 1) * pageSize
-	return s.repo.Goal.GetPublicGoals(pageSize, offset)
+	goals, total, err := s.repo.Goal.GetPublicGoals(ctx, sort, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range goals {
+		hideBankDetails(&goals[i], uuid.Nil)
+	}
+	return goals, total, nil
+}
+
+// ListAllGoals retrieves goals regardless of visibility or status, for admins. An empty
+// status lists every goal.
+func (s *GoalService) ListAllGoals(ctx context.Context, status models.GoalStatus, page, pageSize int) ([]models.Goal, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	offset := (page - 1) * pageSize
+	return s.repo.Goal.GetAllGoals(ctx, status, pageSize, offset)
 }
 
 // ListUserGoals retrieves all goals created by a user with pagination
-func (s *GoalService) ListUserGoals(userID uuid.UUID, page, pageSize int) ([]models.Goal, int64, error) {
+func (s *GoalService) ListUserGoals(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]models.Goal, int64, error) {
 	if page <= 0 {
 		page = 1
 	}
@@ -120,7 +262,7 @@ func (s *GoalService) ListUserGoals(userID uuid.UUID, page, pageSize int) ([]mod
 		pageSize = 20
 	}
 	
-	goals, err := s.repo.Goal.GetGoalsByOwnerID(userID)
+	goals, err := s.repo.Goal.GetGoalsByOwnerID(ctx, userID)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -140,9 +282,23 @@ func (s *GoalService) ListUserGoals(userID uuid.UUID, page, pageSize int) ([]mod
 	return goals[offset:end], total, nil
 }
 
+// ReconcileGoalTotals recomputes every goal's stored CurrentAmount and ContributorCount
+// from its contributions and refunds, repairing drift. It returns how many goals changed.
+func (s *GoalService) ReconcileGoalTotals(ctx context.Context) (int64, error) {
+	reconciled, err := s.repo.Goal.ReconcileTotals(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if reconciled > 0 {
+		log.Printf("Reconciled funding totals for %d goals", reconciled)
+	}
+	metrics.RecordGauge("goals.totals.reconciled", float64(reconciled))
+	return reconciled, nil
+}
+
 // UpdateGoal updates a goal
-func (s *GoalService) UpdateGoal(goalID, userID uuid.UUID, req dto.UpdateGoalRequest) (*models.Goal, error) {
-	goal, err := s.repo.Goal.GetGoalByIDSimple(goalID)
+func (s *GoalService) UpdateGoal(ctx context.Context, goalID, userID uuid.UUID, req dto.UpdateGoalRequest) (*models.Goal, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrGoalNotFound
@@ -155,6 +311,36 @@ func (s *GoalService) UpdateGoal(goalID, userID uuid.UUID, req dto.UpdateGoalReq
 		return nil, ErrUnauthorized
 	}
 
+	bankDetailsBefore := bankDetailsSnapshot(goal)
+
+	confirmed, err := s.repo.Goal.GetTotalConfirmedContributions(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.TargetAmount != nil && *req.TargetAmount != goal.TargetAmount {
+		if err := s.checkTargetAmount(ctx, goal, *req.TargetAmount, confirmed); err != nil {
+			return nil, err
+		}
+		goal.TargetAmount = *req.TargetAmount
+	}
+	if req.Currency != nil {
+		currency, err := validator.NormalizeCurrency(*req.Currency)
+		if err != nil {
+			return nil, apperrors.Validation("unsupported_currency", err.Error())
+		}
+		if currency != goal.Currency {
+			contributions, err := s.repo.Goal.CountContributions(ctx, goalID)
+			if err != nil {
+				return nil, err
+			}
+			if contributions > 0 {
+				return nil, ErrCurrencyLocked
+			}
+			goal.Currency = currency
+		}
+	}
+
 	// Update fields
 	if req.Title != nil {
 		goal.Title = *req.Title
@@ -162,29 +348,120 @@ func (s *GoalService) UpdateGoal(goalID, userID uuid.UUID, req dto.UpdateGoalReq
 	if req.Description != nil {
 		goal.Description = *req.Description
 	}
-	if req.BankName != nil {
-		goal.DepositBankName = *req.BankName
+	if req.DepositBankName != nil {
+		goal.DepositBankName = *req.DepositBankName
+	}
+	if req.DepositAccountNumber != nil {
+		goal.DepositAccountNumber = *req.DepositAccountNumber
+	}
+	if req.DepositAccountName != nil {
+		goal.DepositAccountName = *req.DepositAccountName
+	}
+	visibility, ok, err := resolveVisibility(req.Visibility, req.IsPublic)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		setVisibility(goal, visibility)
+	}
+	if req.CloseOnTarget != nil {
+		goal.CloseOnTarget = *req.CloseOnTarget
+	}
+	if req.FixedContributionAmount != nil {
+		if *req.FixedContributionAmount < 0 {
+			return nil, apperrors.Validation("invalid_fixed_amount", "fixed contribution amount cannot be negative")
+		}
+		goal.FixedContributionAmount = *req.FixedContributionAmount
+	}
+	if req.RequireProofForWithdrawal != nil {
+		goal.RequireProofForWithdrawal = *req.RequireProofForWithdrawal
 	}
-	if req.AccountNumber != nil {
-		goal.DepositAccountNumber = *req.AccountNumber
+	if req.WeightedVoting != nil {
+		goal.WeightedVoting = *req.WeightedVoting
 	}
-	if req.AccountName != nil {
-		goal.DepositAccountName = *req.AccountName
+	if req.FeeMode != nil {
+		if goal.FeeMode, err = resolveFeeMode(*req.FeeMode); err != nil {
+			return nil, err
+		}
+	}
+	if req.RequiredApprovals != nil {
+		collaborators, err := s.repo.Collaborator.CountCollaborators(ctx, goalID)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateRequiredApprovals(*req.RequiredApprovals, collaborators); err != nil {
+			return nil, err
+		}
+		goal.RequiredApprovals = *req.RequiredApprovals
 	}
-	if req.IsPublic != nil {
-		goal.IsPublic = *req.IsPublic
+
+	// Contributors paid into the deposit account, so once any have, a new account must be
+	// one the bank confirms is held by the owner
+	bankDetailsChanged := !reflect.DeepEqual(bankDetailsBefore, bankDetailsSnapshot(goal))
+	if bankDetailsChanged && confirmed > 0 {
+		if err := ValidateBankDetails(goal.DepositBankName, goal.DepositAccountNumber, goal.DepositAccountName); err != nil {
+			return nil, err
+		}
+		accountName, err := s.resolveDepositAccount(ctx, goal.OwnerID, goal.DepositBankName, goal.DepositAccountNumber)
+		if err != nil {
+			metrics.IncrementCounter("goals.bank_details.rejected")
+			return nil, err
+		}
+		goal.DepositAccountName = accountName
 	}
 
-	if err := s.repo.Goal.UpdateGoal(goal); err != nil {
+	err = s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		if err := tx.Goal.UpdateGoal(ctx, goal); err != nil {
+			return err
+		}
+		if bankDetailsChanged && confirmed > 0 {
+			return recordBankDetailsChanged(ctx, tx, goal)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return s.repo.Goal.GetGoalByID(goalID)
+	// Payouts go to the deposit account, so every change to it is audited
+	if bankDetailsChanged {
+		s.audit.Record(ctx, AuditEntry{
+			GoalID:     goal.ID,
+			ActorID:    userID,
+			Action:     models.AuditActionBankDetailsChanged,
+			EntityType: auditEntityGoal,
+			EntityID:   goal.ID,
+			Before:     bankDetailsBefore,
+			After:      bankDetailsSnapshot(goal),
+		})
+	}
+
+	return s.getGoal(ctx, s.repo.Primary(), goalID, userID, "")
+}
+
+// recordBankDetailsChanged records the event telling a goal's contributors that its
+// deposit account changed, in the transaction that changed it
+func recordBankDetailsChanged(ctx context.Context, tx *repository.Repository, goal *models.Goal) error {
+	accountNumber := goal.DepositAccountNumber
+	if len(accountNumber) > 4 {
+		accountNumber = accountNumber[len(accountNumber)-4:]
+	}
+	event := events.GoalBankDetailsChanged{
+		ID:                 uuid.New().String(),
+		GoalID:             goal.ID.String(),
+		OwnerID:            goal.OwnerID.String(),
+		Title:              goal.Title,
+		BankName:           goal.DepositBankName,
+		AccountNumberLast4: accountNumber,
+		ContributorIDs:     contributorIDs(ctx, tx, goal.ID),
+		CreatedAt:          time.Now().Unix(),
+	}
+	return recordEvent(ctx, tx, "GoalBankDetailsChanged", event)
 }
 
 // CloseGoal closes a goal to new contributions
-func (s *GoalService) CloseGoal(goalID, userID uuid.UUID) (*models.Goal, error) {
-	goal, err := s.repo.Goal.GetGoalByIDSimple(goalID)
+func (s *GoalService) CloseGoal(ctx context.Context, goalID, userID uuid.UUID) (*models.Goal, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrGoalNotFound
@@ -203,16 +480,187 @@ func (s *GoalService) CloseGoal(goalID, userID uuid.UUID) (*models.Goal, error)
 	}
 
 	goal.Status = models.GoalStatusClosed
-	if err := s.repo.Goal.UpdateGoal(goal); err != nil {
+	err = s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		if err := tx.Goal.UpdateGoal(ctx, goal); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, "GoalClosed", events.GoalClosed{
+			ID:             uuid.New().String(),
+			GoalID:         goal.ID.String(),
+			OwnerID:        goal.OwnerID.String(),
+			Title:          goal.Title,
+			ContributorIDs: contributorIDs(ctx, tx, goal.ID),
+			CreatedAt:      time.Now().Unix(),
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.audit.Record(ctx, AuditEntry{
+		GoalID:     goal.ID,
+		ActorID:    userID,
+		Action:     models.AuditActionGoalClosed,
+		EntityType: auditEntityGoal,
+		EntityID:   goal.ID,
+		Before:     goalStatusSnapshot(models.GoalStatusOpen),
+		After:      goalStatusSnapshot(goal.Status),
+	})
+
 	return goal, nil
 }
 
+// deadlineReminderWindow is how long before its deadline a goal's watchers are reminded
+const deadlineReminderWindow = 48 * time.Hour
+
+// RunDeadlineEnforcement closes open goals past their deadline and reminds watchers of
+// goals nearing theirs every interval until ctx is cancelled
+func (s *GoalService) RunDeadlineEnforcement(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now()
+			s.closeGoalsPastDeadline(ctx, now)
+			s.remindGoalsNearDeadline(ctx, now)
+		}
+	}
+}
+
+// closeGoalsPastDeadline closes every open goal whose deadline is before now and announces
+// whether each one met its target
+func (s *GoalService) closeGoalsPastDeadline(ctx context.Context, now time.Time) {
+	goals, err := s.repo.Goal.GetOpenGoalsPastDeadline(ctx, now)
+	if err != nil {
+		log.Printf("Failed to load goals past their deadline: %v", err)
+		return
+	}
+
+	var closed int
+	for i := range goals {
+		goal := &goals[i]
+		var ok bool
+		err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+			var err error
+			if ok, err = tx.Goal.CloseGoalIfOpen(ctx, goal.ID); err != nil || !ok {
+				return err
+			}
+			return recordGoalDeadlineReached(ctx, tx, goal)
+		})
+		if err != nil {
+			log.Printf("Failed to close goal %s past its deadline: %v", goal.ID, err)
+			continue
+		}
+		if !ok {
+			// Closed or cancelled by someone else since it was loaded
+			continue
+		}
+		closed++
+	}
+
+	if closed > 0 {
+		log.Printf("Closed %d goals past their deadline", closed)
+		metrics.IncrementCounter("goals.deadline.closed")
+		metrics.RecordGauge("goals.deadline.closed.batch", float64(closed))
+	}
+}
+
+// recordGoalDeadlineReached records the event that lets notifications tell the owner and
+// contributors the goal closed on its deadline, in the transaction that closed it
+func recordGoalDeadlineReached(ctx context.Context, tx *repository.Repository, goal *models.Goal) error {
+	event := events.GoalDeadlineReached{
+		ID:             uuid.New().String(),
+		GoalID:         goal.ID.String(),
+		OwnerID:        goal.OwnerID.String(),
+		Title:          goal.Title,
+		TargetAmount:   goal.TargetAmount,
+		ContributorIDs: contributorIDs(ctx, tx, goal.ID),
+		Deadline:       goal.Deadline.Unix(),
+		CreatedAt:      time.Now().Unix(),
+	}
+
+	total, err := tx.Goal.GetTotalConfirmedContributions(ctx, goal.ID)
+	if err != nil {
+		return err
+	}
+	event.TotalAmount = total
+	event.TargetMet = total >= goal.TargetAmount
+
+	return recordEvent(ctx, tx, "GoalDeadlineReached", event)
+}
+
+// remindGoalsNearDeadline tells the watchers of every open goal whose deadline falls within
+// deadlineReminderWindow of now that it is about to close. Each goal is reminded once.
+func (s *GoalService) remindGoalsNearDeadline(ctx context.Context, now time.Time) {
+	goals, err := s.repo.Goal.GetOpenGoalsDeadlineBefore(ctx, now.Add(deadlineReminderWindow))
+	if err != nil {
+		log.Printf("Failed to load goals nearing their deadline: %v", err)
+		return
+	}
+
+	var reminded int
+	for i := range goals {
+		goal := &goals[i]
+		if !goal.Deadline.After(now) {
+			// Already past its deadline; closeGoalsPastDeadline handles it
+			continue
+		}
+		var ok bool
+		err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+			var err error
+			if ok, err = tx.Goal.MarkDeadlineReminderSent(ctx, goal.ID, now); err != nil || !ok {
+				return err
+			}
+			return recordGoalDeadlineApproaching(ctx, tx, goal)
+		})
+		if err != nil {
+			log.Printf("Failed to mark deadline reminder for goal %s: %v", goal.ID, err)
+			continue
+		}
+		if !ok {
+			// Another replica reminded it since it was loaded
+			continue
+		}
+		reminded++
+	}
+
+	if reminded > 0 {
+		log.Printf("Reminded watchers of %d goals nearing their deadline", reminded)
+		metrics.IncrementCounter("goals.deadline.reminded")
+	}
+}
+
+// recordGoalDeadlineApproaching records the event that lets notifications tell the goal's
+// watchers it is about to close, in the transaction that marks them reminded
+func recordGoalDeadlineApproaching(ctx context.Context, tx *repository.Repository, goal *models.Goal) error {
+	watchers := watcherIDs(ctx, tx, goal.ID)
+	if len(watchers) == 0 {
+		return nil
+	}
+
+	event := events.GoalDeadlineApproaching{
+		ID:         uuid.New().String(),
+		GoalID:     goal.ID.String(),
+		Title:      goal.Title,
+		Deadline:   goal.Deadline.Unix(),
+		WatcherIDs: watchers,
+		CreatedAt:  time.Now().Unix(),
+	}
+
+	return recordEvent(ctx, tx, "GoalDeadlineApproaching", event)
+}
+
 // CancelGoal cancels a goal
-func (s *GoalService) CancelGoal(goalID, userID uuid.UUID) (*models.Goal, error) {
-	goal, err := s.repo.Goal.GetGoalByIDSimple(goalID)
+func (s *GoalService) CancelGoal(ctx context.Context, goalID, userID uuid.UUID) (*models.Goal, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrGoalNotFound
@@ -225,66 +673,392 @@ func (s *GoalService) CancelGoal(goalID, userID uuid.UUID) (*models.Goal, error)
 		return nil, ErrUnauthorized
 	}
 
+	// Check status
+	if err := state.NewGoalStateMachine().ValidateTransition(goal.Status, models.GoalStatusCancelled); err != nil {
+		return nil, ErrInvalidGoalStatus
+	}
+
+	previousStatus := goal.Status
 	goal.Status = models.GoalStatusCancelled
-	if err := s.repo.Goal.UpdateGoal(goal); err != nil {
+	err = s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		if err := tx.Goal.UpdateGoal(ctx, goal); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, "GoalCancelled", events.GoalCancelled{
+			ID:             uuid.New().String(),
+			GoalID:         goal.ID.String(),
+			OwnerID:        goal.OwnerID.String(),
+			Title:          goal.Title,
+			ContributorIDs: contributorIDs(ctx, tx, goal.ID),
+			CreatedAt:      time.Now().Unix(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEntry{
+		GoalID:     goal.ID,
+		ActorID:    userID,
+		Action:     models.AuditActionGoalCancelled,
+		EntityType: auditEntityGoal,
+		EntityID:   goal.ID,
+		Before:     goalStatusSnapshot(previousStatus),
+		After:      goalStatusSnapshot(goal.Status),
+	})
+
+	return goal, nil
+}
+
+// SuspendGoal suspends a goal on an admin's behalf, blocking contributions and
+// withdrawals until it is unsuspended
+func (s *GoalService) SuspendGoal(ctx context.Context, goalID, adminID uuid.UUID, reason string) (*models.Goal, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+
+	if err := state.NewGoalStateMachine().ValidateTransition(goal.Status, models.GoalStatusSuspended); err != nil {
+		return nil, ErrInvalidGoalStatus
+	}
+
+	now := time.Now()
+	previousStatus := goal.Status
+	goal.SuspendedFromStatus = goal.Status
+	goal.Status = models.GoalStatusSuspended
+	goal.SuspendedAt = &now
+	goal.SuspensionReason = reason
+	after := goalStatusSnapshot(goal.Status)
+	after["reason"] = reason
+	err = s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		if err := tx.Goal.UpdateGoal(ctx, goal); err != nil {
+			return err
+		}
+		err := recordAudit(ctx, tx, AuditEntry{
+			GoalID:     goal.ID,
+			ActorID:    adminID,
+			Action:     models.AuditActionGoalSuspended,
+			EntityType: auditEntityGoal,
+			EntityID:   goal.ID,
+			Before:     goalStatusSnapshot(previousStatus),
+			After:      after,
+		})
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, "GoalSuspended", events.GoalSuspended{
+			ID:        uuid.New().String(),
+			GoalID:    goal.ID.String(),
+			OwnerID:   goal.OwnerID.String(),
+			Title:     goal.Title,
+			Reason:    reason,
+			CreatedAt: now.Unix(),
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return goal, nil
 }
 
-// GetGoalProgress returns progress information for a goal
-func (s *GoalService) GetGoalProgress(goalID uuid.UUID) (*dto.GoalProgress, error) {
-	goal, err := s.repo.Goal.GetGoalByID(goalID)
+// UnsuspendGoal lifts a suspension on an admin's behalf, restoring the status the goal
+// was suspended from
+func (s *GoalService) UnsuspendGoal(ctx context.Context, goalID, adminID uuid.UUID) (*models.Goal, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+
+	restored := goal.SuspendedFromStatus
+	if restored == "" {
+		restored = models.GoalStatusOpen
+	}
+	if goal.Status != models.GoalStatusSuspended || !state.NewGoalStateMachine().CanTransition(goal.Status, restored) {
+		return nil, ErrInvalidGoalStatus
+	}
+
+	goal.Status = restored
+	goal.SuspendedFromStatus = ""
+	goal.SuspendedAt = nil
+	goal.SuspensionReason = ""
+	err = s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		if err := tx.Goal.UpdateGoal(ctx, goal); err != nil {
+			return err
+		}
+		err := recordAudit(ctx, tx, AuditEntry{
+			GoalID:     goal.ID,
+			ActorID:    adminID,
+			Action:     models.AuditActionGoalUnsuspended,
+			EntityType: auditEntityGoal,
+			EntityID:   goal.ID,
+			Before:     goalStatusSnapshot(models.GoalStatusSuspended),
+			After:      goalStatusSnapshot(goal.Status),
+		})
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, "GoalUnsuspended", events.GoalUnsuspended{
+			ID:        uuid.New().String(),
+			GoalID:    goal.ID.String(),
+			OwnerID:   goal.OwnerID.String(),
+			Title:     goal.Title,
+			Status:    string(goal.Status),
+			CreatedAt: time.Now().Unix(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return goal, nil
+}
+
+// contributorIDs returns the goal's contributor IDs as strings for event payloads
+func contributorIDs(ctx context.Context, repo *repository.Repository, goalID uuid.UUID) []string {
+	userIDs, err := repo.Goal.GetContributorIDs(ctx, goalID)
+	if err != nil {
+		log.Printf("Failed to fetch contributors for goal %s: %v", goalID, err)
+		return nil
+	}
+
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+	return ids
+}
+
+// watcherIDs returns the IDs of the users watching a goal as strings for event payloads
+func watcherIDs(ctx context.Context, repo *repository.Repository, goalID uuid.UUID) []string {
+	userIDs, err := repo.Goal.GetWatcherIDs(ctx, goalID)
+	if err != nil {
+		log.Printf("Failed to fetch watchers for goal %s: %v", goalID, err)
+		return nil
+	}
+
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+	return ids
+}
+
+// DeleteGoal hard-deletes a goal that never received money. Goals with confirmed
+// contributions or withdrawals are archived instead so their financial history is kept,
+// as are goals with contribution intents a late payment could still confirm. It returns
+// the archived goal, or nil when the goal was deleted.
+func (s *GoalService) DeleteGoal(ctx context.Context, goalID, userID uuid.UUID) (*models.Goal, error) {
+	var archived *models.Goal
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		// Lock the goal so a contribution confirmed or a withdrawal requested meanwhile
+		// cannot land on a goal being deleted
+		goal, err := tx.Goal.GetGoalByIDForUpdate(ctx, goalID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrGoalNotFound
+			}
+			return err
+		}
+
+		// Check ownership
+		if goal.OwnerID != userID {
+			return ErrUnauthorized
+		}
+
+		confirmedContributions, err := tx.Goal.CountConfirmedContributions(ctx, goalID)
+		if err != nil {
+			return err
+		}
+		pendingContributions, err := tx.Goal.CountPendingContributions(ctx, goalID)
+		if err != nil {
+			return err
+		}
+		withdrawals, err := tx.Goal.CountWithdrawals(ctx, goalID)
+		if err != nil {
+			return err
+		}
+
+		if confirmedContributions == 0 && pendingContributions == 0 && withdrawals == 0 {
+			return s.deleteGoal(ctx, tx, goal, userID)
+		}
+
+		// Money has moved or may still move through this goal; archive it rather than
+		// cascade-deleting records
+		if err := state.NewGoalStateMachine().ValidateTransition(goal.Status, models.GoalStatusArchived); err != nil {
+			return ErrInvalidGoalStatus
+		}
+		previousStatus := goal.Status
+		goal.Status = models.GoalStatusArchived
+		if err := tx.Goal.UpdateGoal(ctx, goal); err != nil {
+			return err
+		}
+		err = recordAudit(ctx, tx, AuditEntry{
+			GoalID:     goal.ID,
+			ActorID:    userID,
+			Action:     models.AuditActionGoalArchived,
+			EntityType: auditEntityGoal,
+			EntityID:   goal.ID,
+			Before:     goalStatusSnapshot(previousStatus),
+			After:      goalStatusSnapshot(goal.Status),
+		})
+		if err != nil {
+			return err
+		}
+		archived = goal
+		return recordEvent(ctx, tx, "GoalArchived", events.GoalArchived{
+			ID:             uuid.New().String(),
+			GoalID:         goal.ID.String(),
+			OwnerID:        goal.OwnerID.String(),
+			Title:          goal.Title,
+			ContributorIDs: contributorIDs(ctx, tx, goal.ID),
+			CreatedAt:      time.Now().Unix(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return archived, nil
+}
+
+// deleteGoal hard-deletes goal in tx, recording who deleted it in the audit log, which
+// outlives the goal
+func (s *GoalService) deleteGoal(ctx context.Context, tx *repository.Repository, goal *models.Goal, userID uuid.UUID) error {
+	if err := tx.Goal.DeleteGoal(ctx, goal.ID); err != nil {
+		return err
+	}
+	err := recordAudit(ctx, tx, AuditEntry{
+		GoalID:     goal.ID,
+		ActorID:    userID,
+		Action:     models.AuditActionGoalDeleted,
+		EntityType: auditEntityGoal,
+		EntityID:   goal.ID,
+		Before: map[string]interface{}{
+			"status": goal.Status,
+			"title":  goal.Title,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return recordEvent(ctx, tx, "GoalDeleted", events.GoalDeleted{
+		ID:        uuid.New().String(),
+		GoalID:    goal.ID.String(),
+		OwnerID:   goal.OwnerID.String(),
+		Title:     goal.Title,
+		CreatedAt: time.Now().Unix(),
+	})
+}
+
+// GetGoalProgress returns progress information for a goal, visible to the same viewers
+// as GetGoal
+func (s *GoalService) GetGoalProgress(ctx context.Context, goalID, viewerID uuid.UUID, inviteToken string) (*dto.GoalProgress, error) {
+	goal, err := s.GetGoal(ctx, goalID, viewerID, inviteToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.goalProgress(ctx, goal)
+}
+
+// GetInternalGoalProgress returns progress information for a goal without checking who
+// may see it, for the service's own event handling. A missing goal is returned as
+// gorm.ErrRecordNotFound.
+func (s *GoalService) GetInternalGoalProgress(ctx context.Context, goalID uuid.UUID) (*dto.GoalProgress, error) {
+	goal, err := s.repo.Goal.GetGoalByID(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+	return s.goalProgress(ctx, goal)
+}
+
+// goalProgress computes a goal's funding and milestone progress
+func (s *GoalService) goalProgress(ctx context.Context, goal *models.Goal) (*dto.GoalProgress, error) {
+	goalID := goal.ID
+	totalContributions, err := s.repo.Goal.GetTotalConfirmedContributions(ctx, goalID)
 	if err != nil {
 		return nil, err
 	}
 
-	totalContributions, err := s.repo.Goal.GetTotalConfirmedContributions(goalID)
+	totalWithdrawals, err := s.repo.Goal.GetTotalCompletedWithdrawals(ctx, goalID)
 	if err != nil {
 		return nil, err
 	}
 
-	totalWithdrawals, err := s.repo.Goal.GetTotalCompletedWithdrawals(goalID)
+	totalFees, err := s.repo.Goal.GetTotalAbsorbedFees(ctx, goalID)
 	if err != nil {
 		return nil, err
 	}
 
-	contributorCount, err := s.repo.Goal.GetContributorCount(goalID)
+	contributorCount, err := s.repo.Goal.GetContributorCount(ctx, goalID)
 	if err != nil {
 		return nil, err
 	}
 
-	milestones, err := s.repo.Milestone.GetMilestonesByGoalID(goalID)
+	milestones, err := s.repo.Milestone.GetMilestonesByGoalID(ctx, goalID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Calculate milestone progress
+	var activeMilestoneID *uuid.UUID
 	milestoneProgress := make([]dto.MilestoneProgress, len(milestones))
 	for i, milestone := range milestones {
-		milestoneContributions, _ := s.repo.Milestone.GetTotalConfirmedContributionsByMilestone(milestone.ID)
+		if milestone.Status == models.MilestoneStatusActive {
+			activeMilestoneID = &milestones[i].ID
+		}
+		milestoneContributions, _ := s.repo.Milestone.GetTotalConfirmedContributionsByMilestone(ctx, milestone.ID)
 		milestoneProgress[i] = dto.MilestoneProgress{
 			Milestone:       milestone,
 			CurrentAmount:   milestoneContributions,
 			ProgressPercent: calculatePercent(milestoneContributions, milestone.TargetAmount),
 		}
+		milestoneID := milestone.ID
+		if milestoneProgress[i].WithdrawalBlockedReason, err = s.withdrawalBlockedReason(ctx, goal, &milestoneID); err != nil {
+			return nil, err
+		}
+	}
+
+	goalBlockedReason, err := s.withdrawalBlockedReason(ctx, goal, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	return &dto.GoalProgress{
-		Goal:               *goal,
-		TotalContributions: totalContributions,
-		TotalWithdrawals:   totalWithdrawals,
-		AvailableBalance:   totalContributions - totalWithdrawals,
-		ProgressPercent:    calculatePercent(totalContributions, goal.TargetAmount),
-		ContributorCount:   contributorCount,
-		Milestones:         milestoneProgress,
+		Goal:                        *goal,
+		TotalContributions:          totalContributions,
+		TotalWithdrawals:            totalWithdrawals,
+		TotalFees:                   totalFees,
+		AvailableBalance:            totalContributions - totalFees - totalWithdrawals,
+		ProgressPercent:             calculatePercent(totalContributions, goal.TargetAmount),
+		ContributorCount:            contributorCount,
+		Milestones:                  milestoneProgress,
+		ActiveMilestoneID:           activeMilestoneID,
+		GoalWithdrawalBlockedReason: goalBlockedReason,
 	}, nil
 }
 
+// withdrawalBlockedReason returns the error code WithdrawalService.CreateWithdrawal would
+// refuse a withdrawal against the milestone (or the goal itself, for a nil milestoneID)
+// with because of its proofs, or "" if they allow it
+func (s *GoalService) withdrawalBlockedReason(ctx context.Context, goal *models.Goal, milestoneID *uuid.UUID) (string, error) {
+	err := checkProofsForWithdrawal(ctx, s.repo, goal, milestoneID)
+	var domainErr *apperrors.DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Code, nil
+	}
+	return "", err
+}
+
 // CreateMilestone creates a new milestone for a goal
-func (s *GoalService) CreateMilestone(goalID, userID uuid.UUID, req dto.CreateMilestoneRequest) (*models.Milestone, error) {
-	goal, err := s.repo.Goal.GetGoalByIDSimple(goalID)
+func (s *GoalService) CreateMilestone(ctx context.Context, goalID, userID uuid.UUID, req dto.CreateMilestoneRequest) (*dto.CreatedMilestone, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrGoalNotFound
@@ -297,24 +1071,30 @@ func (s *GoalService) CreateMilestone(goalID, userID uuid.UUID, req dto.CreateMi
 		return nil, ErrUnauthorized
 	}
 
-	// Validate
-	if req.TargetAmount <= 0 {
-		return nil, errors.New("target amount must be greater than 0")
+	// Validate, defaulting an omitted target to the goal's unallocated remainder
+	allocated, err := s.repo.Milestone.GetTotalMilestoneTargets(ctx, goalID)
+	if err != nil {
+		return nil, err
 	}
+	reqs := []dto.CreateMilestoneRequest{req}
+	if err := allocateMilestoneTargets(goal.TargetAmount, allocated, reqs); err != nil {
+		return nil, err
+	}
+	req = reqs[0]
 
 	if req.IsRecurring {
 		if req.RecurrenceType == nil {
-			return nil, errors.New("recurrence type required for recurring milestones")
+			return nil, apperrors.Validation("recurrence_type_required", "recurrence type required for recurring milestones")
 		}
 		if req.NextDueDate == nil {
-			return nil, errors.New("next due date required for recurring milestones")
+			return nil, apperrors.Validation("next_due_date_required", "next due date required for recurring milestones")
 		}
 	}
 
 	// Get next order index if not provided
 	orderIndex := req.OrderIndex
 	if orderIndex == 0 {
-		orderIndex, err = s.repo.Milestone.GetNextOrderIndex(goalID)
+		orderIndex, err = s.repo.Milestone.GetNextOrderIndex(ctx, goalID)
 		if err != nil {
 			return nil, err
 		}
@@ -333,17 +1113,34 @@ func (s *GoalService) CreateMilestone(goalID, userID uuid.UUID, req dto.CreateMi
 		Status:             models.MilestoneStatusPending,
 	}
 
-	if err := s.repo.Milestone.CreateMilestone(milestone); err != nil {
+	// A milestone ordered before the active one takes its place
+	err = s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		if err := tx.Milestone.CreateMilestone(ctx, milestone); err != nil {
+			return err
+		}
+		active, err := tx.Milestone.ActivateNextMilestone(ctx, goalID)
+		if err != nil {
+			return err
+		}
+		if active != nil && active.ID == milestone.ID {
+			milestone.Status = models.MilestoneStatusActive
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return milestone, nil
+	return &dto.CreatedMilestone{
+		Milestone:  *milestone,
+		Allocation: milestoneAllocation(goal, allocated+milestone.TargetAmount),
+	}, nil
 }
 
 // GetGoalMilestones retrieves all milestones for a goal
-func (s *GoalService) GetGoalMilestones(goalID uuid.UUID) ([]models.Milestone, error) {
+func (s *GoalService) GetGoalMilestones(ctx context.Context, goalID uuid.UUID) ([]models.Milestone, error) {
 	// Verify goal exists
-	_, err := s.repo.Goal.GetGoalByIDSimple(goalID)
+	_, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrGoalNotFound
@@ -351,61 +1148,96 @@ func (s *GoalService) GetGoalMilestones(goalID uuid.UUID) ([]models.Milestone, e
 		return nil, err
 	}
 
-	return s.repo.Milestone.GetMilestonesByGoalID(goalID)
+	return s.repo.Milestone.GetMilestonesByGoalID(ctx, goalID)
 }
 
-// CompleteMilestone marks a milestone as completed and creates next if recurring
-func (s *GoalService) CompleteMilestone(milestoneID, userID uuid.UUID) (*models.Milestone, *models.Milestone, error) {
-	milestone, err := s.repo.Milestone.GetMilestoneByID(milestoneID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil, ErrMilestoneNotFound
+// CompleteMilestone marks a milestone as completed, creates the next instance if it is
+// recurring, and activates the goal's next milestone, all in one transaction
+func (s *GoalService) CompleteMilestone(ctx context.Context, milestoneID, userID uuid.UUID) (*models.Milestone, *models.Milestone, error) {
+	var milestone, nextMilestone *models.Milestone
+	var previousStatus models.MilestoneStatus
+
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		var err error
+		milestone, err = tx.Milestone.GetMilestoneByID(ctx, milestoneID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMilestoneNotFound
+			}
+			return err
 		}
-		return nil, nil, err
-	}
 
-	// Check goal ownership
-	goal, err := s.repo.Goal.GetGoalByIDSimple(milestone.GoalID)
-	if err != nil {
-		return nil, nil, err
-	}
-	if goal.OwnerID != userID {
-		return nil, nil, ErrUnauthorized
-	}
+		// Check goal ownership; the lock keeps concurrent completions from activating
+		// different milestones
+		goal, err := tx.Goal.GetGoalByIDForUpdate(ctx, milestone.GoalID)
+		if err != nil {
+			return err
+		}
+		if goal.OwnerID != userID {
+			return ErrUnauthorized
+		}
 
-	// Mark as completed
-	now := time.Now()
-	milestone.Status = models.MilestoneStatusCompleted
-	milestone.CompletedAt = &now
+		// Mark as completed
+		previousStatus = milestone.Status
+		now := time.Now()
+		milestone.Status = models.MilestoneStatusCompleted
+		milestone.CompletedAt = &now
 
-	if err := s.repo.Milestone.UpdateMilestone(milestone); err != nil {
-		return nil, nil, err
-	}
+		if err := tx.Milestone.UpdateMilestone(ctx, milestone); err != nil {
+			return err
+		}
 
-	// If recurring, create next milestone
-	var nextMilestone *models.Milestone
-	if milestone.IsRecurring && milestone.RecurrenceType != nil {
-		nextOrderIndex, _ := s.repo.Milestone.GetNextOrderIndex(milestone.GoalID)
-		nextDueDate := calculateNextDueDate(*milestone.NextDueDate, *milestone.RecurrenceType, milestone.RecurrenceInterval)
+		// If recurring, create next milestone
+		if milestone.IsRecurring && milestone.RecurrenceType != nil {
+			nextOrderIndex, _ := tx.Milestone.GetNextOrderIndex(ctx, milestone.GoalID)
+			nextDueDate := calculateNextDueDate(*milestone.NextDueDate, *milestone.RecurrenceType, milestone.RecurrenceInterval)
+			cycle, err := tx.Milestone.GetRecurrenceCycle(ctx, milestone.ID)
+			if err != nil {
+				return err
+			}
 
-		nextMilestone = &models.Milestone{
-			GoalID:             milestone.GoalID,
-			Title:              generateNextTitle(milestone.Title),
-			Description:        milestone.Description,
-			TargetAmount:       milestone.TargetAmount,
-			OrderIndex:         nextOrderIndex,
-			IsRecurring:        true,
-			RecurrenceType:     milestone.RecurrenceType,
-			RecurrenceInterval: milestone.RecurrenceInterval,
-			NextDueDate:        &nextDueDate,
-			Status:             models.MilestoneStatusPending,
+			nextMilestone = &models.Milestone{
+				GoalID:             milestone.GoalID,
+				Title:              generateNextTitle(milestone.Title, cycle+1, &nextDueDate, *milestone.RecurrenceType),
+				Description:        milestone.Description,
+				TargetAmount:       milestone.TargetAmount,
+				OrderIndex:         nextOrderIndex,
+				IsRecurring:        true,
+				RecurrenceType:     milestone.RecurrenceType,
+				RecurrenceInterval: milestone.RecurrenceInterval,
+				NextDueDate:        &nextDueDate,
+				ParentMilestoneID:  &milestone.ID,
+				Status:             models.MilestoneStatusPending,
+			}
+
+			if err := tx.Milestone.CreateMilestone(ctx, nextMilestone); err != nil {
+				return err
+			}
 		}
 
-		if err := s.repo.Milestone.CreateMilestone(nextMilestone); err != nil {
-			return milestone, nil, err
+		active, err := tx.Milestone.ActivateNextMilestone(ctx, milestone.GoalID)
+		if err != nil {
+			return err
 		}
+		if active != nil && nextMilestone != nil && active.ID == nextMilestone.ID {
+			nextMilestone.Status = models.MilestoneStatusActive
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
+	s.audit.Record(ctx, AuditEntry{
+		GoalID:     milestone.GoalID,
+		ActorID:    userID,
+		Action:     models.AuditActionMilestoneCompleted,
+		EntityType: auditEntityMilestone,
+		EntityID:   milestone.ID,
+		Before:     map[string]interface{}{"status": previousStatus},
+		After:      map[string]interface{}{"status": milestone.Status, "completed_at": milestone.CompletedAt},
+	})
+
 	return milestone, nextMilestone, nil
 }
 
@@ -433,25 +1265,19 @@ func calculateNextDueDate(current time.Time, recurrenceType models.RecurrenceTyp
 	}
 }
 
-func generateNextTitle(currentTitle string) string {
-	// Simple implementation - can be enhanced
-	// TODO: Extract number and increment (e.g., "Semester 1" -> "Semester 2")
-	return currentTitle + " (Next)"
-}
-
 // ValidateBankDetails validates bank account details
 func ValidateBankDetails(bankName, accountNumber, accountName string) error {
 	if bankName == "" {
-		return errors.New("bank name is required")
+		return apperrors.Validation("bank_name_required", "bank name is required")
 	}
 	if accountNumber == "" {
-		return errors.New("account number is required")
+		return apperrors.Validation("account_number_required", "account number is required")
 	}
 	if len(accountNumber) != 10 {
-		return errors.New("account number must be 10 digits")
+		return apperrors.Validation("invalid_account_number", "account number must be 10 digits")
 	}
 	if accountName == "" {
-		return errors.New("account name is required")
+		return apperrors.Validation("account_name_required", "account name is required")
 	}
 	return nil
 }
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestCreateGoalValidatesCurrency(t *testing.T) {
+	repo := newTestRepo(t)
+	s := NewGoalService(repo, nil, nil, nil, NewInviteTokens("secret", time.Hour))
+
+	tests := []struct {
+		currency string
+		want     string
+		wantCode string
+	}{
+		{currency: "NGN", want: "NGN"},
+		{currency: "ngn", want: "NGN"},
+		{currency: "", want: "NGN"},
+		{currency: "USD", wantCode: "unsupported_currency"},
+		{currency: "GHS", wantCode: "unsupported_currency"},
+	}
+	for _, tt := range tests {
+		goal, err := s.CreateGoal(context.Background(), uuid.New(), dto.CreateGoalRequest{
+			Title:                "Community borehole",
+			TargetAmount:         1_000_000,
+			Currency:             tt.currency,
+			DepositBankName:      "Test Bank",
+			DepositAccountNumber: "0123456789",
+			DepositAccountName:   "Ada Obi",
+		})
+		if tt.wantCode != "" {
+			if code := errorCode(err); code != tt.wantCode {
+				t.Errorf("CreateGoal in %q: err = %v, want %s", tt.currency, err, tt.wantCode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("CreateGoal in %q: %v", tt.currency, err)
+		}
+		if goal.Currency != tt.want {
+			t.Errorf("goal currency for %q = %s, want %s", tt.currency, goal.Currency, tt.want)
+		}
+	}
+}
+
+func TestDeleteGoalArchivesGoalsMoneyMayMoveThrough(t *testing.T) {
+	tests := []struct {
+		name        string
+		seed        func(t *testing.T, repo *repository.Repository, goal *models.Goal)
+		wantArchive bool
+	}{
+		{
+			name:        "untouched goal is deleted",
+			seed:        func(t *testing.T, repo *repository.Repository, goal *models.Goal) {},
+			wantArchive: false,
+		},
+		{
+			name: "pending contribution archives",
+			seed: func(t *testing.T, repo *repository.Repository, goal *models.Goal) {
+				intent := &models.Contribution{GoalID: goal.ID, UserID: uuid.New(), Amount: 10_000, Currency: goal.Currency}
+				if err := repo.Contribution.CreateContribution(context.Background(), intent); err != nil {
+					t.Fatalf("CreateContribution: %v", err)
+				}
+			},
+			wantArchive: true,
+		},
+		{
+			name: "confirmed contribution archives",
+			seed: func(t *testing.T, repo *repository.Repository, goal *models.Goal) {
+				createTestContribution(t, repo, goal, uuid.New(), 10_000)
+			},
+			wantArchive: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepo(t)
+			s := NewGoalService(repo, nil, nil, nil, NewInviteTokens("secret", time.Hour))
+			ctx := context.Background()
+			ownerID := uuid.New()
+			goal := createTestGoal(t, repo, ownerID)
+			tt.seed(t, repo, goal)
+
+			if _, err := s.DeleteGoal(ctx, goal.ID, uuid.New()); !errors.Is(err, ErrUnauthorized) {
+				t.Fatalf("deleting another owner's goal: err = %v, want %v", err, ErrUnauthorized)
+			}
+			archived, err := s.DeleteGoal(ctx, goal.ID, ownerID)
+			if err != nil {
+				t.Fatalf("DeleteGoal: %v", err)
+			}
+
+			wantAction, wantEvent := models.AuditActionGoalDeleted, "GoalDeleted"
+			if tt.wantArchive {
+				wantAction, wantEvent = models.AuditActionGoalArchived, "GoalArchived"
+				if archived == nil || archived.Status != models.GoalStatusArchived {
+					t.Fatalf("DeleteGoal = %+v, want the goal archived", archived)
+				}
+			} else {
+				if archived != nil {
+					t.Fatalf("DeleteGoal = %+v, want the goal deleted", archived)
+				}
+				if _, err := repo.Goal.GetGoalByIDSimple(ctx, goal.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+					t.Errorf("deleted goal lookup: err = %v, want %v", err, gorm.ErrRecordNotFound)
+				}
+			}
+
+			logs, _, err := repo.Audit.GetAuditLogsByGoalID(ctx, goal.ID, 10, 0)
+			if err != nil {
+				t.Fatalf("GetAuditLogsByGoalID: %v", err)
+			}
+			if len(logs) != 1 || logs[0].Action != wantAction || logs[0].ActorID != ownerID {
+				t.Errorf("audit log = %+v, want one %s by the owner", logs, wantAction)
+			}
+			assertOutbox(t, repo, map[string]int{wantEvent: 1})
+		})
+	}
+}
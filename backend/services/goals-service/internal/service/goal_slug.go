@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"log"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxSlugBaseLength caps the part of a slug taken from the title
+	maxSlugBaseLength = 60
+	slugSuffixLength  = 6
+	slugAlphabet      = "abcdefghijklmnopqrstuvwxyz0123456789"
+	// maxSlugAttempts bounds the retries with a fresh suffix when a slug is taken
+	maxSlugAttempts = 5
+	// maxShareDescriptionLength is the longest description share metadata carries, in runes
+	maxShareDescriptionLength = 200
+)
+
+// slugBase turns a title into lowercase words of ASCII letters and digits joined by
+// hyphens, e.g. "Bola's Wedding Fund!" becomes "bola-s-wedding-fund"
+func slugBase(title string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+
+	base := b.String()
+	if len(base) > maxSlugBaseLength {
+		base = strings.TrimRight(base[:maxSlugBaseLength], "-")
+	}
+	if base == "" {
+		base = "goal"
+	}
+	return base
+}
+
+// newGoalSlug returns the title's slug base followed by a short random suffix, so goals
+// with the same title still get distinct slugs
+func newGoalSlug(title string) (string, error) {
+	suffix := make([]byte, slugSuffixLength)
+	for i := range suffix {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(slugAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		suffix[i] = slugAlphabet[n.Int64()]
+	}
+	return slugBase(title) + "-" + string(suffix), nil
+}
+
+// createGoalWithSlug creates the goal under a fresh slug, trying another suffix when the
+// slug is already taken
+func (s *GoalService) createGoalWithSlug(ctx context.Context, goal *models.Goal) error {
+	for attempt := 1; ; attempt++ {
+		slug, err := newGoalSlug(goal.Title)
+		if err != nil {
+			return err
+		}
+		goal.Slug = &slug
+
+		err = s.repo.Goal.CreateGoal(ctx, goal)
+		if !errors.Is(err, repository.ErrDuplicateSlug) || attempt == maxSlugAttempts {
+			return err
+		}
+	}
+}
+
+// BackfillSlugs gives every goal created before slugs existed one derived from its title.
+// It returns how many goals it updated.
+func (s *GoalService) BackfillSlugs(ctx context.Context) (int64, error) {
+	const batchSize = 100
+
+	var backfilled int64
+	for {
+		goals, err := s.repo.Goal.GetGoalsWithoutSlug(ctx, batchSize)
+		if err != nil {
+			return backfilled, err
+		}
+
+		var progressed bool
+		for i := range goals {
+			ok, err := s.backfillSlug(ctx, &goals[i])
+			if err != nil {
+				log.Printf("Failed to backfill slug for goal %s: %v", goals[i].ID, err)
+				continue
+			}
+			progressed = true
+			if ok {
+				backfilled++
+			}
+		}
+
+		// Stop on the last batch, or when every goal in this one failed and would be
+		// fetched again
+		if len(goals) < batchSize || !progressed {
+			break
+		}
+	}
+
+	if backfilled > 0 {
+		log.Printf("Backfilled slugs for %d goals", backfilled)
+	}
+	return backfilled, nil
+}
+
+// backfillSlug sets a slug on a goal that has none, trying another suffix when the slug is
+// taken. It reports whether this call set it.
+func (s *GoalService) backfillSlug(ctx context.Context, goal *models.Goal) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		slug, err := newGoalSlug(goal.Title)
+		if err != nil {
+			return false, err
+		}
+
+		ok, err := s.repo.Goal.SetSlugIfMissing(ctx, goal.ID, slug)
+		if !errors.Is(err, repository.ErrDuplicateSlug) || attempt == maxSlugAttempts {
+			return ok, err
+		}
+	}
+}
+
+// GetGoalBySlug retrieves a goal by its slug as seen by viewerID, visible to the same
+// viewers as GetGoal
+func (s *GoalService) GetGoalBySlug(ctx context.Context, slug string, viewerID uuid.UUID, inviteToken string) (*models.Goal, error) {
+	goal, err := s.repo.Goal.GetGoalBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if err := s.viewGoal(ctx, goal, viewerID, inviteToken); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// GetShareMeta returns what link previews need to render a goal. A private goal's metadata
+// is visible only to its owner, collaborators and contributors.
+func (s *GoalService) GetShareMeta(ctx context.Context, goalID, viewerID uuid.UUID) (*dto.GoalShareMeta, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if err := checkGoalVisible(ctx, s.repo, goal, viewerID); err != nil {
+		return nil, err
+	}
+
+	imageURL, err := s.repo.Goal.GetLatestMediaURL(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &dto.GoalShareMeta{
+		GoalID:          goal.ID,
+		Title:           goal.Title,
+		Description:     truncateRunes(strings.TrimSpace(goal.Description), maxShareDescriptionLength),
+		CurrentAmount:   goal.CurrentAmount,
+		TargetAmount:    goal.TargetAmount,
+		Currency:        goal.Currency,
+		ProgressPercent: calculatePercent(goal.CurrentAmount, goal.TargetAmount),
+		ImageURL:        imageURL,
+	}
+	if goal.Slug != nil {
+		meta.Slug = *goal.Slug
+	}
+	return meta, nil
+}
+
+// truncateRunes shortens s to at most max runes, ending it with an ellipsis when cut
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
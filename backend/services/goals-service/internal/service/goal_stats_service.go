@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Bounds of the daily window of goal stats
+const (
+	DefaultGoalStatsDays = 30
+	maxGoalStatsDays     = 365
+)
+
+// ErrInvalidStatsDays is returned for a stats window outside 1 to 365 days
+var ErrInvalidStatsDays = apperrors.Validation("invalid_days", fmt.Sprintf("days must be between 1 and %d", maxGoalStatsDays))
+
+// GoalStatsService computes the dashboard stats of a goal for its owner. Dashboards poll,
+// so stats are cached for cacheTTL per goal and window.
+type GoalStatsService struct {
+	repo     *repository.Repository
+	cacheTTL time.Duration
+
+	mu    sync.RWMutex
+	cache map[goalStatsKey]cachedGoalStats
+}
+
+type goalStatsKey struct {
+	goalID uuid.UUID
+	days   int
+}
+
+type cachedGoalStats struct {
+	ownerID   uuid.UUID
+	stats     *dto.GoalStats
+	expiresAt time.Time
+}
+
+// NewGoalStatsService creates a new goal stats service
+func NewGoalStatsService(repo *repository.Repository, cacheTTL time.Duration) *GoalStatsService {
+	return &GoalStatsService{
+		repo:     repo,
+		cacheTTL: cacheTTL,
+		cache:    make(map[goalStatsKey]cachedGoalStats),
+	}
+}
+
+// GetGoalStats returns the stats of a goal over the last days days, including today.
+// Only the goal owner may view them.
+func (s *GoalStatsService) GetGoalStats(ctx context.Context, goalID, userID uuid.UUID, days int) (*dto.GoalStats, error) {
+	if days < 1 || days > maxGoalStatsDays {
+		return nil, ErrInvalidStatsDays
+	}
+
+	key := goalStatsKey{goalID: goalID, days: days}
+	now := time.Now()
+	s.mu.RLock()
+	cached, ok := s.cache[key]
+	s.mu.RUnlock()
+	if ok && now.Before(cached.expiresAt) {
+		if cached.ownerID != userID {
+			return nil, ErrUnauthorized
+		}
+		return cached.stats, nil
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if goal.OwnerID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	stats, err := s.computeStats(ctx, goal, days, now)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	for k, entry := range s.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(s.cache, k)
+		}
+	}
+	s.cache[key] = cachedGoalStats{ownerID: goal.OwnerID, stats: stats, expiresAt: now.Add(s.cacheTTL)}
+	s.mu.Unlock()
+
+	return stats, nil
+}
+
+// computeStats runs the stats queries for goal over the days days up to now
+func (s *GoalStatsService) computeStats(ctx context.Context, goal *models.Goal, days int, now time.Time) (*dto.GoalStats, error) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(days - 1))
+
+	totals, err := s.repo.Goal.GetDailyContributionTotals(ctx, goal.ID, since)
+	if err != nil {
+		return nil, errors.New("failed to fetch daily contribution totals")
+	}
+	growth, err := s.repo.Goal.GetNewContributorsByDay(ctx, goal.ID, since)
+	if err != nil {
+		return nil, errors.New("failed to fetch contributor growth")
+	}
+	amounts, err := s.repo.Goal.GetContributionAmountStats(ctx, goal.ID)
+	if err != nil {
+		return nil, errors.New("failed to fetch contribution amounts")
+	}
+	counts, err := s.repo.Goal.CountContributionsByStatus(ctx, goal.ID)
+	if err != nil {
+		return nil, errors.New("failed to count contributions")
+	}
+	refunds, err := s.repo.Goal.GetRefundStats(ctx, goal.ID)
+	if err != nil {
+		return nil, errors.New("failed to fetch refund totals")
+	}
+
+	byDay := make(map[time.Time]repository.DailyContributionTotal, len(totals))
+	for _, total := range totals {
+		byDay[total.Day.UTC()] = total
+	}
+
+	// Every day of the window is listed, with zeros on days nobody contributed
+	daily := make([]dto.GoalDailyStats, 0, days)
+	contributors := growth.Before
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		total := byDay[day]
+		contributors += growth.Days[day]
+		daily = append(daily, dto.GoalDailyStats{
+			Date:              day.Format("2006-01-02"),
+			Count:             total.Count,
+			Amount:            total.Amount,
+			NewContributors:   growth.Days[day],
+			TotalContributors: contributors,
+		})
+	}
+
+	return &dto.GoalStats{
+		GoalID:        goal.ID,
+		Currency:      goal.Currency,
+		Days:          days,
+		Daily:         daily,
+		AverageAmount: amounts.Average,
+		MedianAmount:  amounts.Median,
+		Contributions: dto.GoalContributionCounts{
+			Pending:   counts[models.ContributionStatusPending],
+			Confirmed: counts[models.ContributionStatusConfirmed],
+			Failed:    counts[models.ContributionStatusFailed],
+			Expired:   counts[models.ContributionStatusExpired],
+			Refunded:  counts[models.ContributionStatusRefunded],
+		},
+		Refunds: dto.GoalRefundStats{
+			Count:          refunds.Refunds,
+			RefundedAmount: refunds.RefundedAmount,
+			PendingAmount:  refunds.PendingAmount,
+			FailedAmount:   refunds.FailedAmount,
+		},
+		ComputedAt: now,
+	}, nil
+}
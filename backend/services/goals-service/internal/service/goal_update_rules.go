@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// Errors for goal updates that would compromise existing contributors
+var (
+	ErrTargetAmountDecrease    = apperrors.Conflict("target_amount_decrease", "target amount cannot be lowered once the goal has confirmed contributions")
+	ErrTargetBelowMilestones   = apperrors.Conflict("target_below_milestones", "target amount cannot be lower than the goal's milestone targets")
+	ErrCurrencyLocked          = apperrors.Conflict("currency_locked", "currency cannot be changed once the goal has contributions")
+	ErrBankAccountUnresolved   = apperrors.Validation("bank_account_unresolved", "the deposit account could not be resolved; check the bank name and account number")
+	ErrAccountNameMismatch     = apperrors.Conflict("account_name_mismatch", "the name on the deposit account does not match the goal owner's registered name")
+	ErrBankAccountUnverifiable = apperrors.NewDomainError("bank_account_unverifiable", "the deposit account could not be verified right now; try again later", nil)
+)
+
+// checkTargetAmount validates a new target amount: it may be lowered only while the goal
+// has no confirmed contributions, and never below what its milestones claim
+func (s *GoalService) checkTargetAmount(ctx context.Context, goal *models.Goal, target int64, confirmed int64) error {
+	if target <= 0 {
+		return apperrors.Validation("invalid_target_amount", "target amount must be greater than 0")
+	}
+	if target >= goal.TargetAmount {
+		return nil
+	}
+	if confirmed > 0 {
+		return ErrTargetAmountDecrease
+	}
+
+	allocated, err := s.repo.Milestone.GetTotalMilestoneTargets(ctx, goal.ID)
+	if err != nil {
+		return err
+	}
+	if target < allocated {
+		return ErrTargetBelowMilestones
+	}
+	return nil
+}
+
+// resolveDepositAccount resolves a new deposit account with the bank through
+// payments-service and checks that it is held in the owner's registered name. It returns
+// the name the bank holds for the account.
+func (s *GoalService) resolveDepositAccount(ctx context.Context, ownerID uuid.UUID, bankName, accountNumber string) (string, error) {
+	if s.paymentsClient == nil || s.usersClient == nil {
+		return "", ErrBankAccountUnverifiable
+	}
+
+	accountName, err := s.paymentsClient.ResolveAccount(ctx, accountNumber, bankName)
+	if err != nil {
+		if errors.Is(err, ErrAccountUnresolved) {
+			return "", ErrBankAccountUnresolved
+		}
+		return "", apperrors.NewDomainError(ErrBankAccountUnverifiable.Code, ErrBankAccountUnverifiable.Message, err)
+	}
+
+	owner, ok := s.usersClient.Contacts([]uuid.UUID{ownerID})[ownerID]
+	if !ok || strings.TrimSpace(owner.Name) == "" {
+		return "", ErrBankAccountUnverifiable
+	}
+	if !accountNameMatches(accountName, owner.Name) {
+		return "", ErrAccountNameMismatch
+	}
+	return accountName, nil
+}
+
+// accountNameMatches reports whether the name a bank holds for an account belongs to the
+// person registered as name. Word order, case and punctuation are ignored, and a long
+// name may be off by one letter; at least two of the registered names (or the only one)
+// must appear on the account.
+func accountNameMatches(accountName, name string) bool {
+	accountWords := nameWords(accountName)
+	words := nameWords(name)
+	if len(words) == 0 || len(accountWords) == 0 {
+		return false
+	}
+
+	matched := 0
+	for _, word := range words {
+		for _, accountWord := range accountWords {
+			if similarNameWords(word, accountWord) {
+				matched++
+				break
+			}
+		}
+	}
+
+	required := 2
+	if len(words) < required {
+		required = len(words)
+	}
+	return matched >= required
+}
+
+// nameWords splits a name into upper-case words of letters only
+func nameWords(name string) []string {
+	return strings.FieldsFunc(strings.ToUpper(name), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+// similarNameWords reports whether two name words are the same, allowing one edit in
+// words of five or more letters
+func similarNameWords(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len([]rune(a)) < 5 || len([]rune(b)) < 5 {
+		return false
+	}
+	return editDistance(a, b) <= 1
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+func TestAccountNameMatches(t *testing.T) {
+	tests := []struct {
+		accountName, name string
+		want              bool
+	}{
+		{accountName: "ADA OBI", name: "Ada Obi", want: true},
+		{accountName: "OBI ADA", name: "Ada Obi", want: true},
+		{accountName: "OBI, ADA CHIOMA", name: "Ada Obi", want: true},
+		{accountName: "ADAEZE OKAFOR", name: "Adaeze Okafur", want: true},
+		{accountName: "CHIDI", name: "Chidi", want: true},
+		// Short words must match exactly
+		{accountName: "ADA OBA", name: "Ada Obi", want: false},
+		{accountName: "ADA EZE", name: "Ada Obi", want: false},
+		{accountName: "JOHN DOE", name: "Ada Obi", want: false},
+		// Two edits are too many however long the word
+		{accountName: "ADAEZE OKEFUR", name: "Adaeze Okafor", want: false},
+		{accountName: "", name: "Ada Obi", want: false},
+		{accountName: "ADA OBI", name: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := accountNameMatches(tt.accountName, tt.name); got != tt.want {
+			t.Errorf("accountNameMatches(%q, %q) = %v, want %v", tt.accountName, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateGoalTargetAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		confirmed int64 // confirmed contributions to the goal
+		milestone int64 // target of the goal's milestone, if any
+		target    int64
+		wantCode  string
+	}{
+		{name: "raised with contributions", confirmed: 100_000, target: 2_000_000},
+		{name: "lowered with contributions", confirmed: 100_000, target: 900_000, wantCode: "target_amount_decrease"},
+		{name: "lowered without contributions", target: 500_000},
+		{name: "lowered to the milestones", milestone: 500_000, target: 500_000},
+		{name: "lowered below the milestones", milestone: 500_000, target: 400_000, wantCode: "target_below_milestones"},
+		{name: "zero", target: 0, wantCode: "invalid_target_amount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepo(t)
+			s := NewGoalService(repo, nil, nil, nil, NewInviteTokens("secret", time.Hour))
+			ctx := context.Background()
+			goal := createTestGoal(t, repo, uuid.New())
+			if tt.confirmed > 0 {
+				createTestContribution(t, repo, goal, uuid.New(), tt.confirmed)
+			}
+			if tt.milestone > 0 {
+				milestone := &models.Milestone{GoalID: goal.ID, Title: "Deposit", TargetAmount: tt.milestone, Status: models.MilestoneStatusActive}
+				if err := repo.Milestone.CreateMilestone(ctx, milestone); err != nil {
+					t.Fatalf("CreateMilestone: %v", err)
+				}
+			}
+
+			updated, err := s.UpdateGoal(ctx, goal.ID, goal.OwnerID, dto.UpdateGoalRequest{TargetAmount: &tt.target})
+			if tt.wantCode != "" {
+				if code := errorCode(err); code != tt.wantCode {
+					t.Fatalf("err = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateGoal: %v", err)
+			}
+			if updated.TargetAmount != tt.target {
+				t.Errorf("target = %d, want %d", updated.TargetAmount, tt.target)
+			}
+		})
+	}
+}
+
+func TestUpdateGoalCurrency(t *testing.T) {
+	ngn := "NGN"
+	tests := []struct {
+		name         string
+		contribution models.ContributionStatus // of the goal's only contribution, if any
+		wantCode     string
+	}{
+		{name: "no contributions"},
+		{name: "confirmed contribution", contribution: models.ContributionStatusConfirmed, wantCode: "currency_locked"},
+		// An intent may still be paid, in the currency it was created in
+		{name: "pending intent", contribution: models.ContributionStatusPending, wantCode: "currency_locked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepo(t)
+			s := NewGoalService(repo, nil, nil, nil, NewInviteTokens("secret", time.Hour))
+			ctx := context.Background()
+			goal := createTestGoal(t, repo, uuid.New(), func(g *models.Goal) { g.Currency = "GHS" })
+			if tt.contribution != "" {
+				contribution := createTestContribution(t, repo, goal, uuid.New(), 10_000)
+				contribution.Status = tt.contribution
+				if err := repo.Contribution.UpdateContribution(ctx, contribution); err != nil {
+					t.Fatalf("UpdateContribution: %v", err)
+				}
+			}
+
+			updated, err := s.UpdateGoal(ctx, goal.ID, goal.OwnerID, dto.UpdateGoalRequest{Currency: &ngn})
+			if tt.wantCode != "" {
+				if code := errorCode(err); code != tt.wantCode {
+					t.Fatalf("err = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateGoal: %v", err)
+			}
+			if updated.Currency != "NGN" {
+				t.Errorf("currency = %s, want NGN", updated.Currency)
+			}
+		})
+	}
+}
+
+// Account numbers the fake payments-service resolves in newBankDetailsGoalService
+const (
+	ownersAccount       = "1111111111"
+	someoneElsesAccount = "2222222222"
+	unknownAccount      = "3333333333"
+	unavailableAccount  = "4444444444"
+)
+
+// newBankDetailsGoalService returns a goal service whose payments-service resolves the
+// accounts above and whose users-service registers every user as Ada Obi
+func newBankDetailsGoalService(t *testing.T) *GoalService {
+	t.Helper()
+	payments := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		names := map[string]string{ownersAccount: "OBI ADA", someoneElsesAccount: "JOHN DOE"}
+		switch number := r.URL.Query().Get("account_number"); {
+		case number == unavailableAccount:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		case names[number] == "":
+			http.Error(w, "could not resolve account", http.StatusBadRequest)
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"account_name": names[number]}})
+		}
+	}))
+	t.Cleanup(payments.Close)
+
+	users := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"users": []map[string]string{
+			{"id": r.URL.Query().Get("ids"), "first_name": "Ada", "last_name": "Obi"},
+		}})
+	}))
+	t.Cleanup(users.Close)
+
+	return NewGoalService(newTestRepo(t), nil, NewUsersClient(users.URL, time.Minute), NewPaymentsClient(payments.URL), NewInviteTokens("secret", time.Hour))
+}
+
+func TestUpdateGoalBankDetails(t *testing.T) {
+	tests := []struct {
+		name          string
+		confirmed     bool
+		accountNumber string
+		wantCode      string
+		wantName      string // the deposit account name stored
+	}{
+		{name: "no contributions", accountNumber: unknownAccount, wantName: "Typed Name"},
+		{name: "owner's account", confirmed: true, accountNumber: ownersAccount, wantName: "OBI ADA"},
+		{name: "someone else's account", confirmed: true, accountNumber: someoneElsesAccount, wantCode: "account_name_mismatch"},
+		{name: "unresolvable account", confirmed: true, accountNumber: unknownAccount, wantCode: "bank_account_unresolved"},
+		{name: "provider unavailable", confirmed: true, accountNumber: unavailableAccount, wantCode: "bank_account_unverifiable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newBankDetailsGoalService(t)
+			ctx := context.Background()
+			goal := createTestGoal(t, s.repo, uuid.New())
+			if tt.confirmed {
+				createTestContribution(t, s.repo, goal, uuid.New(), 10_000)
+			}
+
+			name := "Typed Name"
+			updated, err := s.UpdateGoal(ctx, goal.ID, goal.OwnerID, dto.UpdateGoalRequest{
+				DepositAccountNumber: &tt.accountNumber,
+				DepositAccountName:   &name,
+			})
+			if tt.wantCode != "" {
+				if code := errorCode(err); code != tt.wantCode {
+					t.Fatalf("err = %v, want %s", err, tt.wantCode)
+				}
+				stored, err := s.repo.Goal.GetGoalByIDSimple(ctx, goal.ID)
+				if err != nil {
+					t.Fatalf("GetGoalByIDSimple: %v", err)
+				}
+				if stored.DepositAccountNumber != goal.DepositAccountNumber {
+					t.Errorf("deposit account = %s after a rejected change, want %s", stored.DepositAccountNumber, goal.DepositAccountNumber)
+				}
+				assertOutbox(t, s.repo, map[string]int{})
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateGoal: %v", err)
+			}
+
+			if updated.DepositAccountNumber != tt.accountNumber || updated.DepositAccountName != tt.wantName {
+				t.Errorf("deposit account = %s %q, want %s %q", updated.DepositAccountNumber, updated.DepositAccountName, tt.accountNumber, tt.wantName)
+			}
+			// Contributors are told only when they paid into the old account
+			want := map[string]int{}
+			if tt.confirmed {
+				want["GoalBankDetailsChanged"] = 1
+			}
+			assertOutbox(t, s.repo, want)
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidVisibility  = apperrors.Validation("invalid_visibility", "visibility must be one of PUBLIC, UNLISTED, PRIVATE")
+	ErrInvitesUnavailable = apperrors.NewDomainError("invites_unavailable", "invite links are not configured", nil)
+)
+
+// InviteTokens signs and checks goal invite link tokens. A token is "<expiry>.<signature>",
+// the signature an HMAC-SHA256 over the goal ID and the expiry, so invites need no storage
+// and cannot be moved to another goal. They cannot be revoked before they expire.
+type InviteTokens struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewInviteTokens creates an invite token signer. Tokens last ttl; without a secret no
+// invites can be issued and none are accepted.
+func NewInviteTokens(secret string, ttl time.Duration) *InviteTokens {
+	return &InviteTokens{secret: []byte(secret), ttl: ttl}
+}
+
+// Issue returns a new invite token for a goal and when it expires
+func (t *InviteTokens) Issue(goalID uuid.UUID, now time.Time) (string, time.Time, error) {
+	if t == nil || len(t.secret) == 0 || t.ttl <= 0 {
+		return "", time.Time{}, ErrInvitesUnavailable
+	}
+	expiresAt := now.Add(t.ttl).Truncate(time.Second)
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+	return expiry + "." + t.sign(goalID, expiry), expiresAt, nil
+}
+
+// Valid reports whether token is an unexpired invite to the goal
+func (t *InviteTokens) Valid(goalID uuid.UUID, token string, now time.Time) bool {
+	if t == nil || len(t.secret) == 0 || token == "" {
+		return false
+	}
+	expiry, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || !now.Before(time.Unix(unix, 0)) {
+		return false
+	}
+	return hmac.Equal([]byte(t.sign(goalID, expiry)), []byte(signature))
+}
+
+// sign returns the hex signature of a goal ID and an invite expiry
+func (t *InviteTokens) sign(goalID uuid.UUID, expiry string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(goalID.String() + "\n" + expiry))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// resolveVisibility returns the visibility a goal create or update asks for: visibility
+// when set, otherwise the one isPublic implies. It reports false when neither is set.
+func resolveVisibility(visibility *models.GoalVisibility, isPublic *bool) (models.GoalVisibility, bool, error) {
+	if visibility != nil && *visibility != "" {
+		v := models.GoalVisibility(strings.ToUpper(string(*visibility)))
+		switch v {
+		case models.GoalVisibilityPublic, models.GoalVisibilityUnlisted, models.GoalVisibilityPrivate:
+			return v, true, nil
+		}
+		return "", false, ErrInvalidVisibility
+	}
+	if isPublic != nil {
+		if *isPublic {
+			return models.GoalVisibilityPublic, true, nil
+		}
+		return models.GoalVisibilityPrivate, true, nil
+	}
+	return "", false, nil
+}
+
+// setVisibility sets a goal's visibility, keeping IsPublic (whether it is listed) in step
+func setVisibility(goal *models.Goal, visibility models.GoalVisibility) {
+	goal.Visibility = visibility
+	goal.IsPublic = visibility == models.GoalVisibilityPublic
+}
+
+// canViewGoal reports whether viewerID may open a goal. Public and unlisted goals are
+// open to everyone; a private goal only to its owner, its collaborators and its
+// contributors. Invite links are checked separately, by the callers that accept them.
+func canViewGoal(ctx context.Context, repo *repository.Repository, goal *models.Goal, viewerID uuid.UUID) (bool, error) {
+	if goal.Visibility != models.GoalVisibilityPrivate || goal.OwnerID == viewerID {
+		return true, nil
+	}
+	if viewerID == uuid.Nil {
+		return false, nil
+	}
+
+	if _, err := repo.Collaborator.GetCollaborator(ctx, goal.ID, viewerID); err == nil {
+		return true, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	return repo.Goal.IsUserContributor(ctx, goal.ID, viewerID)
+}
+
+// checkGoalVisible returns ErrGoalNotFound when viewerID may not open the goal, without an
+// invite, so a private goal's existence is not revealed
+func checkGoalVisible(ctx context.Context, repo *repository.Repository, goal *models.Goal, viewerID uuid.UUID) error {
+	ok, err := canViewGoal(ctx, repo, goal, viewerID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+// hideBankDetails clears a goal's deposit account, and the withdrawals that carry its
+// bank details, for anyone but its owner
+func hideBankDetails(goal *models.Goal, viewerID uuid.UUID) {
+	if goal.OwnerID != viewerID {
+		goal.DepositBankName = ""
+		goal.DepositAccountNumber = ""
+		goal.DepositAccountName = ""
+		goal.Withdrawals = nil
+	}
+}
+
+// viewGoal checks that viewerID, or the holder of inviteToken, may open a goal and hides
+// what they may not see of it
+func (s *GoalService) viewGoal(ctx context.Context, goal *models.Goal, viewerID uuid.UUID, inviteToken string) error {
+	if !s.invites.Valid(goal.ID, inviteToken, time.Now()) {
+		if err := checkGoalVisible(ctx, s.repo, goal, viewerID); err != nil {
+			return err
+		}
+	}
+	maskContributors(goal, viewerID)
+	hideBankDetails(goal, viewerID)
+	return nil
+}
+
+// CreateGoalInvite issues an invite link token for a goal. Invites are sent by the
+// goal's organisers: its owner and collaborators with the owner role.
+func (s *GoalService) CreateGoalInvite(ctx context.Context, goalID, userID uuid.UUID) (*dto.GoalInvite, error) {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	organiser, err := canRequestWithdrawal(ctx, s.repo, goal, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !organiser {
+		return nil, ErrUnauthorized
+	}
+
+	token, expiresAt, err := s.invites.Issue(goal.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.IncrementCounter("goals.invite.created", "visibility:"+string(goal.Visibility))
+
+	return &dto.GoalInvite{
+		GoalID:    goal.ID,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// BackfillVisibility makes goals that were hidden before visibility levels existed
+// private. It returns how many goals it updated.
+func (s *GoalService) BackfillVisibility(ctx context.Context) (int64, error) {
+	return s.repo.Goal.BackfillVisibility(ctx)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/testdb"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// newTestRepo returns a repository over a fresh test database schema
+func newTestRepo(t *testing.T) *repository.Repository {
+	t.Helper()
+	return repository.NewRepository(testdb.Open(t))
+}
+
+// newTestUsersClient returns a users-service client backed by a fake users-service that
+// gives every user a settlement account and reports every user KYC verified
+func newTestUsersClient(t *testing.T) *UsersClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/internal/users/settlement-accounts":
+			users := []map[string]interface{}{}
+			for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+				users = append(users, map[string]interface{}{
+					"id":                        id,
+					"settlement_bank_name":      "Test Bank",
+					"settlement_account_number": "0123456789",
+					"settlement_account_name":   "Test Contributor",
+				})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"users": users})
+		case strings.HasPrefix(r.URL.Path, "/internal/users/"):
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":           strings.TrimPrefix(r.URL.Path, "/internal/users/"),
+				"kyc_verified": true,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return NewUsersClient(server.URL, time.Minute)
+}
+
+// createTestGoal creates an open goal with a deposit account, after applying opts to it
+func createTestGoal(t *testing.T, repo *repository.Repository, ownerID uuid.UUID, opts ...func(*models.Goal)) *models.Goal {
+	t.Helper()
+	goal := &models.Goal{
+		OwnerID:              ownerID,
+		Title:                "Test goal",
+		TargetAmount:         1_000_000,
+		Currency:             "NGN",
+		Status:               models.GoalStatusOpen,
+		IsPublic:             true,
+		Visibility:           models.GoalVisibilityPublic,
+		RequiredApprovals:    1,
+		FeeMode:              models.FeeModeAbsorb,
+		DepositBankName:      "Test Bank",
+		DepositAccountNumber: "0123456789",
+		DepositAccountName:   "Goal Owner",
+	}
+	for _, opt := range opts {
+		opt(goal)
+	}
+	if err := repo.Goal.CreateGoal(context.Background(), goal); err != nil {
+		t.Fatalf("failed to create goal: %v", err)
+	}
+	return goal
+}
+
+// createTestContribution records a confirmed contribution of amount to goal by userID
+func createTestContribution(t *testing.T, repo *repository.Repository, goal *models.Goal, userID uuid.UUID, amount int64) *models.Contribution {
+	t.Helper()
+	paymentID := uuid.New()
+	contribution := &models.Contribution{
+		GoalID:    goal.ID,
+		UserID:    userID,
+		PaymentID: &paymentID,
+		Amount:    amount,
+		Currency:  goal.Currency,
+		Status:    models.ContributionStatusConfirmed,
+		NetAmount: amount,
+	}
+	if err := repo.Contribution.CreateContribution(context.Background(), contribution); err != nil {
+		t.Fatalf("failed to create contribution: %v", err)
+	}
+	return contribution
+}
+
+// setGoalStatus moves goal to status directly in the database
+func setGoalStatus(t *testing.T, repo *repository.Repository, goal *models.Goal, status models.GoalStatus) {
+	t.Helper()
+	goal.Status = status
+	if err := repo.Goal.UpdateGoal(context.Background(), goal); err != nil {
+		t.Fatalf("failed to update goal: %v", err)
+	}
+}
+
+// errorCode returns the code of the domain error in err's chain, or "" if there is none
+func errorCode(err error) string {
+	var domainErr *apperrors.DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofund/shared/metrics"
+	"github.com/google/uuid"
+)
+
+// LedgerClient reads goal balances from ledger-service
+type LedgerClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// LedgerGoalBalance is a goal account as the ledger sees it. Balance is computed from the
+// entries; SnapshotBalance is nil when the ledger has no snapshot for the account.
+type LedgerGoalBalance struct {
+	Balance         int64             `json:"balance"`
+	SnapshotBalance *int64            `json:"snapshot_balance"`
+	Entries         []LedgerGoalEntry `json:"entries"`
+}
+
+// LedgerGoalEntry is one entry on a goal account. ContributionID is nil for entries not
+// posted for a contribution.
+type LedgerGoalEntry struct {
+	ID             uuid.UUID  `json:"id"`
+	TransactionID  uuid.UUID  `json:"transaction_id"`
+	EntryType      string     `json:"entry_type"`
+	Amount         int64      `json:"amount"`
+	ContributionID *uuid.UUID `json:"contribution_id"`
+}
+
+// NewLedgerClient creates a ledger-service client
+func NewLedgerClient(baseURL string) *LedgerClient {
+	return &LedgerClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// GetGoalBalance calls GET /internal/ledger/goals/:goalId/balance on ledger-service
+func (lc *LedgerClient) GetGoalBalance(goalID uuid.UUID, currency string) (*LedgerGoalBalance, error) {
+	if lc.baseURL == "" {
+		return nil, fmt.Errorf("ledger-service URL not configured")
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/ledger/goals/%s/balance?currency=%s",
+		lc.baseURL, url.PathEscape(goalID.String()), url.QueryEscape(currency))
+
+	start := time.Now()
+	resp, err := lc.client.Get(endpoint)
+	metrics.RecordDuration("goals.ledger_client.duration", start)
+	if err != nil {
+		metrics.IncrementCounter("goals.ledger_client.error")
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		metrics.IncrementCounter("goals.ledger_client.error")
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var balance LedgerGoalBalance
+	if err := json.NewDecoder(resp.Body).Decode(&balance); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &balance, nil
+}
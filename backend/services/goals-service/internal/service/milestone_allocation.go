@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/gofund/goals-service/internal/dto"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/models"
+)
+
+var (
+	ErrNegativeMilestoneTarget  = apperrors.Validation("invalid_milestone_target", "milestone target amount cannot be negative")
+	ErrMilestoneTargetExhausted = apperrors.Validation("milestone_target_exhausted", "not enough unallocated goal target left for milestones without a target amount")
+	ErrMilestonesExceedTarget   = apperrors.Validation("milestone_targets_exceed_goal", "milestone targets exceed the goal target")
+)
+
+// MilestoneOverAllocationError is returned when explicit milestone targets add up to more
+// than the goal target
+type MilestoneOverAllocationError struct {
+	TargetAmount    int64
+	AllocatedAmount int64
+	Overage         int64
+}
+
+func (e *MilestoneOverAllocationError) Error() string {
+	return fmt.Sprintf("milestone targets exceed the goal target of %d by %d", e.TargetAmount, e.Overage)
+}
+
+// Unwrap classifies the error as ErrMilestonesExceedTarget
+func (e *MilestoneOverAllocationError) Unwrap() error {
+	return ErrMilestonesExceedTarget
+}
+
+// splitAmount divides total into parts integer shares that add up to exactly total.
+// The remainder goes one unit at a time to the first shares.
+func splitAmount(total int64, parts int) []int64 {
+	if parts <= 0 {
+		return nil
+	}
+
+	shares := make([]int64, parts)
+	base := total / int64(parts)
+	remainder := total % int64(parts)
+	for i := range shares {
+		shares[i] = base
+		if int64(i) < remainder {
+			shares[i]++
+		}
+	}
+	return shares
+}
+
+// allocateMilestoneTargets fills in the target of every milestone request that left it
+// unset (zero) with an even share of the goal target that the existing milestones and the
+// explicit targets leave unallocated. The requests are updated in place.
+func allocateMilestoneTargets(goalTarget, alreadyAllocated int64, reqs []dto.CreateMilestoneRequest) error {
+	allocated := alreadyAllocated
+	var unspecified []int
+	for i, req := range reqs {
+		if req.TargetAmount < 0 {
+			return ErrNegativeMilestoneTarget
+		}
+		if req.TargetAmount == 0 {
+			unspecified = append(unspecified, i)
+			continue
+		}
+		allocated += req.TargetAmount
+	}
+
+	if allocated > goalTarget {
+		return &MilestoneOverAllocationError{
+			TargetAmount:    goalTarget,
+			AllocatedAmount: allocated,
+			Overage:         allocated - goalTarget,
+		}
+	}
+
+	if len(unspecified) == 0 {
+		return nil
+	}
+
+	// Every defaulted milestone needs at least one unit of the remaining target
+	remaining := goalTarget - allocated
+	if remaining < int64(len(unspecified)) {
+		return ErrMilestoneTargetExhausted
+	}
+
+	for i, share := range splitAmount(remaining, len(unspecified)) {
+		reqs[unspecified[i]].TargetAmount = share
+	}
+	return nil
+}
+
+// milestoneAllocation summarises how much of the goal target the milestones claim
+func milestoneAllocation(goal *models.Goal, allocated int64) dto.MilestoneAllocation {
+	unallocated := goal.TargetAmount - allocated
+	if unallocated < 0 {
+		unallocated = 0
+	}
+	return dto.MilestoneAllocation{
+		TargetAmount:      goal.TargetAmount,
+		AllocatedAmount:   allocated,
+		UnallocatedAmount: unallocated,
+	}
+}
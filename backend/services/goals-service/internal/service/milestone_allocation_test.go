@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+func TestSplitAmount(t *testing.T) {
+	tests := []struct {
+		total int64
+		parts int
+		want  []int64
+	}{
+		{total: 90_000, parts: 3, want: []int64{30_000, 30_000, 30_000}},
+		{total: 100_000, parts: 3, want: []int64{33_334, 33_333, 33_333}},
+		{total: 5, parts: 5, want: []int64{1, 1, 1, 1, 1}},
+		{total: 7, parts: 1, want: []int64{7}},
+		{total: 7, parts: 0, want: nil},
+	}
+	for _, tt := range tests {
+		if got := splitAmount(tt.total, tt.parts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitAmount(%d, %d) = %v, want %v", tt.total, tt.parts, got, tt.want)
+		}
+	}
+}
+
+func TestAllocateMilestoneTargets(t *testing.T) {
+	tests := []struct {
+		name        string
+		goalTarget  int64
+		allocated   int64 // by the goal's existing milestones
+		targets     []int64
+		want        []int64
+		wantErr     error
+		wantOverage int64
+	}{
+		{
+			name:       "all unspecified split the whole target",
+			goalTarget: 100_000,
+			targets:    []int64{0, 0, 0},
+			want:       []int64{33_334, 33_333, 33_333},
+		},
+		{
+			name:       "mixed split what the explicit targets leave",
+			goalTarget: 100_000,
+			targets:    []int64{40_000, 0, 0},
+			want:       []int64{40_000, 30_000, 30_000},
+		},
+		{
+			name:       "existing milestones count as allocated",
+			goalTarget: 100_000,
+			allocated:  70_000,
+			targets:    []int64{0, 0},
+			want:       []int64{15_000, 15_000},
+		},
+		{
+			name:       "explicit targets fitting exactly",
+			goalTarget: 100_000,
+			targets:    []int64{60_000, 40_000},
+			want:       []int64{60_000, 40_000},
+		},
+		{
+			name:        "explicit targets over the goal target",
+			goalTarget:  100_000,
+			targets:     []int64{60_000, 50_000, 0},
+			wantErr:     ErrMilestonesExceedTarget,
+			wantOverage: 10_000,
+		},
+		{
+			name:        "existing milestones and a new target over the goal target",
+			goalTarget:  100_000,
+			allocated:   90_000,
+			targets:     []int64{20_000},
+			wantErr:     ErrMilestonesExceedTarget,
+			wantOverage: 10_000,
+		},
+		{
+			name:       "nothing left for an unspecified milestone",
+			goalTarget: 100_000,
+			targets:    []int64{100_000, 0},
+			wantErr:    ErrMilestoneTargetExhausted,
+		},
+		{
+			name:       "negative target",
+			goalTarget: 100_000,
+			targets:    []int64{-1, 0},
+			wantErr:    ErrNegativeMilestoneTarget,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs := make([]dto.CreateMilestoneRequest, len(tt.targets))
+			for i, target := range tt.targets {
+				reqs[i].TargetAmount = target
+			}
+
+			err := allocateMilestoneTargets(tt.goalTarget, tt.allocated, reqs)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				var overErr *MilestoneOverAllocationError
+				if tt.wantOverage != 0 && (!errors.As(err, &overErr) || overErr.Overage != tt.wantOverage) {
+					t.Errorf("err = %v, want an overage of %d", err, tt.wantOverage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("allocateMilestoneTargets: %v", err)
+			}
+
+			got := make([]int64, len(reqs))
+			var total int64
+			for i, req := range reqs {
+				got[i] = req.TargetAmount
+				total += req.TargetAmount
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("targets = %v, want %v", got, tt.want)
+			}
+			if tt.allocated+total != tt.goalTarget {
+				t.Errorf("milestones add up to %d, want the goal target %d", tt.allocated+total, tt.goalTarget)
+			}
+		})
+	}
+}
+
+func TestCreateMilestoneDefaultsTargetAndReportsAllocation(t *testing.T) {
+	repo := newTestRepo(t)
+	s := NewGoalService(repo, nil, nil, nil, NewInviteTokens("secret", time.Hour))
+	ctx := context.Background()
+	goal := createTestGoal(t, repo, uuid.New(), func(g *models.Goal) { g.TargetAmount = 100_000 })
+
+	explicit, err := s.CreateMilestone(ctx, goal.ID, goal.OwnerID, dto.CreateMilestoneRequest{Title: "Deposit", TargetAmount: 70_000})
+	if err != nil {
+		t.Fatalf("CreateMilestone with a target: %v", err)
+	}
+	if want := (dto.MilestoneAllocation{TargetAmount: 100_000, AllocatedAmount: 70_000, UnallocatedAmount: 30_000}); explicit.Allocation != want {
+		t.Errorf("allocation = %+v, want %+v", explicit.Allocation, want)
+	}
+
+	defaulted, err := s.CreateMilestone(ctx, goal.ID, goal.OwnerID, dto.CreateMilestoneRequest{Title: "Balance"})
+	if err != nil {
+		t.Fatalf("CreateMilestone without a target: %v", err)
+	}
+	if defaulted.Milestone.TargetAmount != 30_000 {
+		t.Errorf("defaulted target = %d, want the unallocated 30000", defaulted.Milestone.TargetAmount)
+	}
+	if defaulted.Allocation.UnallocatedAmount != 0 {
+		t.Errorf("unallocated after the defaulted milestone = %d, want 0", defaulted.Allocation.UnallocatedAmount)
+	}
+
+	_, err = s.CreateMilestone(ctx, goal.ID, goal.OwnerID, dto.CreateMilestoneRequest{Title: "Extra", TargetAmount: 1_000})
+	var overErr *MilestoneOverAllocationError
+	if !errors.As(err, &overErr) || overErr.Overage != 1_000 {
+		t.Fatalf("milestone over the target: err = %v, want an overage of 1000", err)
+	}
+}
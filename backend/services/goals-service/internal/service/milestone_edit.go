@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidMilestoneTitle     = apperrors.Validation("invalid_milestone_title", "milestone title cannot be empty or longer than 255 characters")
+	ErrInvalidMilestoneTarget    = apperrors.Validation("invalid_milestone_target", "milestone target amount must be greater than 0")
+	ErrMilestoneHasContributions = apperrors.Conflict("milestone_has_contributions", "milestone has confirmed contributions; delete it with mode=merge to move them to the goal")
+	ErrMilestoneHasWithdrawals   = apperrors.Conflict("milestone_has_withdrawals", "milestone has withdrawals and cannot be deleted")
+)
+
+// UpdateMilestone edits a milestone's title, description or target amount. The target
+// may change at any time, as long as the goal's milestones still fit within its target.
+// With ApplyToFuture, the later not yet completed instances of a recurring milestone are
+// edited too.
+func (s *GoalService) UpdateMilestone(ctx context.Context, milestoneID, userID uuid.UUID, req dto.UpdateMilestoneRequest) (*dto.UpdatedMilestone, error) {
+	var title string
+	if req.Title != nil {
+		title = strings.TrimSpace(*req.Title)
+		if title == "" || len(title) > 255 {
+			return nil, ErrInvalidMilestoneTitle
+		}
+	}
+	if req.TargetAmount != nil && *req.TargetAmount <= 0 {
+		return nil, ErrInvalidMilestoneTarget
+	}
+
+	var milestone *models.Milestone
+	var goal *models.Goal
+	var propagated []models.Milestone
+	var allocated int64
+	var before map[string]interface{}
+
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		var err error
+		milestone, err = tx.Milestone.GetMilestoneByID(ctx, milestoneID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMilestoneNotFound
+			}
+			return err
+		}
+
+		// The goal lock keeps concurrent milestone edits from jointly over-allocating it
+		goal, err = tx.Goal.GetGoalByIDForUpdate(ctx, milestone.GoalID)
+		if err != nil {
+			return err
+		}
+		if goal.OwnerID != userID {
+			return ErrUnauthorized
+		}
+
+		var cycle int
+		if req.ApplyToFuture && milestone.IsRecurring {
+			if propagated, err = tx.Milestone.GetLaterRecurringInstances(ctx, milestone); err != nil {
+				return err
+			}
+			if cycle, err = tx.Milestone.GetRecurrenceCycle(ctx, milestone.ID); err != nil {
+				return err
+			}
+		}
+
+		allocated, err = tx.Milestone.GetTotalMilestoneTargets(ctx, goal.ID)
+		if err != nil {
+			return err
+		}
+		if req.TargetAmount != nil {
+			allocated += *req.TargetAmount - milestone.TargetAmount
+			for _, later := range propagated {
+				allocated += *req.TargetAmount - later.TargetAmount
+			}
+			if allocated > goal.TargetAmount {
+				return &MilestoneOverAllocationError{
+					TargetAmount:    goal.TargetAmount,
+					AllocatedAmount: allocated,
+					Overage:         allocated - goal.TargetAmount,
+				}
+			}
+		}
+
+		before = milestoneSnapshot(milestone)
+		applyMilestoneEdit(milestone, req, title)
+		if err := tx.Milestone.UpdateMilestone(ctx, milestone); err != nil {
+			return err
+		}
+
+		// Later instances keep their place in the chain: a new title is carried forward
+		// the same way completing a recurring milestone names its successor
+		previousTitle := milestone.Title
+		for i := range propagated {
+			applyMilestoneEdit(&propagated[i], req, "")
+			if req.Title != nil && propagated[i].RecurrenceType != nil {
+				cycle++
+				propagated[i].Title = generateNextTitle(previousTitle, cycle, propagated[i].NextDueDate, *propagated[i].RecurrenceType)
+				previousTitle = propagated[i].Title
+			}
+			if err := tx.Milestone.UpdateMilestone(ctx, &propagated[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	after := milestoneSnapshot(milestone)
+	if len(propagated) > 0 {
+		after["propagated_to"] = len(propagated)
+	}
+	s.audit.Record(ctx, AuditEntry{
+		GoalID:     milestone.GoalID,
+		ActorID:    userID,
+		Action:     models.AuditActionMilestoneUpdated,
+		EntityType: auditEntityMilestone,
+		EntityID:   milestone.ID,
+		Before:     before,
+		After:      after,
+	})
+	metrics.IncrementCounter("goals.milestone.updated")
+
+	return &dto.UpdatedMilestone{
+		Milestone:  *milestone,
+		Propagated: propagated,
+		Allocation: milestoneAllocation(goal, allocated),
+	}, nil
+}
+
+// DeleteMilestone deletes a milestone and returns how many of its contributions were
+// moved to the goal as a whole. A milestone with confirmed contributions is only deleted
+// when merge is set; either way its contributions and proofs are reassigned to the goal
+// explicitly, in the same transaction, rather than left to the foreign key. Milestones
+// with withdrawals are never deleted.
+func (s *GoalService) DeleteMilestone(ctx context.Context, milestoneID, userID uuid.UUID, merge bool) (int64, error) {
+	var milestone *models.Milestone
+	var reassigned int64
+
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		var err error
+		milestone, err = tx.Milestone.GetMilestoneByID(ctx, milestoneID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMilestoneNotFound
+			}
+			return err
+		}
+
+		// Lock the goal like UpdateMilestone, so an edit and a delete cannot interleave
+		goal, err := tx.Goal.GetGoalByIDForUpdate(ctx, milestone.GoalID)
+		if err != nil {
+			return err
+		}
+		if goal.OwnerID != userID {
+			return ErrUnauthorized
+		}
+
+		withdrawals, err := tx.Milestone.CountWithdrawalsByMilestone(ctx, milestone.ID)
+		if err != nil {
+			return err
+		}
+		if withdrawals > 0 {
+			return ErrMilestoneHasWithdrawals
+		}
+
+		confirmed, err := tx.Milestone.GetTotalConfirmedContributionsByMilestone(ctx, milestone.ID)
+		if err != nil {
+			return err
+		}
+		if confirmed > 0 && !merge {
+			return ErrMilestoneHasContributions
+		}
+
+		if reassigned, err = tx.Milestone.DetachMilestone(ctx, milestone.ID); err != nil {
+			return err
+		}
+		if err := tx.Milestone.ReparentChildren(ctx, milestone); err != nil {
+			return err
+		}
+		if err := tx.Milestone.DeleteMilestone(ctx, milestone.ID); err != nil {
+			return err
+		}
+		_, err = tx.Milestone.ActivateNextMilestone(ctx, milestone.GoalID)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.audit.Record(ctx, AuditEntry{
+		GoalID:     milestone.GoalID,
+		ActorID:    userID,
+		Action:     models.AuditActionMilestoneDeleted,
+		EntityType: auditEntityMilestone,
+		EntityID:   milestone.ID,
+		Before:     milestoneSnapshot(milestone),
+		After: map[string]interface{}{
+			"merged_into_goal":         merge,
+			"reassigned_contributions": reassigned,
+		},
+	})
+	metrics.IncrementCounter("goals.milestone.deleted", "merged:"+strconv.FormatBool(merge))
+
+	return reassigned, nil
+}
+
+// applyMilestoneEdit copies the fields set in req onto milestone. title is the trimmed
+// req.Title, applied when not empty.
+func applyMilestoneEdit(milestone *models.Milestone, req dto.UpdateMilestoneRequest, title string) {
+	if title != "" {
+		milestone.Title = title
+	}
+	if req.Description != nil {
+		milestone.Description = *req.Description
+	}
+	if req.TargetAmount != nil {
+		milestone.TargetAmount = *req.TargetAmount
+	}
+}
+
+// milestoneSnapshot captures a milestone's editable fields for the audit log
+func milestoneSnapshot(milestone *models.Milestone) map[string]interface{} {
+	return map[string]interface{}{
+		"title":         milestone.Title,
+		"target_amount": milestone.TargetAmount,
+		"status":        milestone.Status,
+	}
+}
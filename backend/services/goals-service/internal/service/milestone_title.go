@@ -0,0 +1,130 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofund/shared/models"
+)
+
+// maxMilestoneTitleLength matches the size of the milestones.title column
+const maxMilestoneTitleLength = 255
+
+var (
+	// titleNumberPattern matches a number in a milestone title: an academic session such as
+	// "2024/2025" or "2024-25", or an integer with an optional ordinal suffix such as "12" or "3rd"
+	titleNumberPattern = regexp.MustCompile(`(?i)\b(?:(\d{4})([/-])(\d{4}|\d{2})|(\d+)(st|nd|rd|th)?)\b`)
+
+	// cycleSuffixPattern matches the suffix generateNextTitle adds to titles without a number
+	cycleSuffixPattern = regexp.MustCompile(` — cycle \d+(?: \([^)]*\))?$`)
+
+	// legacyNextSuffixPattern matches the " (Next)" suffixes successors used to be given
+	legacyNextSuffixPattern = regexp.MustCompile(`(?: \(Next\))+$`)
+)
+
+// generateNextTitle names the successor of a recurring milestone. The last number in the
+// title is incremented ("Semester 1" -> "Semester 2", "3rd Term" -> "4th Term",
+// "2024/2025 Session" -> "2025/2026 Session"). A title without one is given the
+// successor's cycle and the period it is due in ("Rent" -> "Rent — cycle 4 (March 2025)").
+func generateNextTitle(currentTitle string, cycle int, dueDate *time.Time, recurrenceType models.RecurrenceType) string {
+	base := legacyNextSuffixPattern.ReplaceAllString(currentTitle, "")
+	base = cycleSuffixPattern.ReplaceAllString(base, "")
+
+	if next, ok := incrementTitleNumber(base); ok {
+		return truncateTitle(next, "")
+	}
+
+	suffix := fmt.Sprintf(" — cycle %d", cycle)
+	if dueDate != nil {
+		suffix += " (" + recurrencePeriod(*dueDate, recurrenceType) + ")"
+	}
+	return truncateTitle(base, suffix)
+}
+
+// incrementTitleNumber increments the last number in title, keeping its width and any
+// ordinal suffix. It reports false when title has no number.
+func incrementTitleNumber(title string) (string, bool) {
+	matches := titleNumberPattern.FindAllStringSubmatchIndex(title, -1)
+	if len(matches) == 0 {
+		return "", false
+	}
+	m := matches[len(matches)-1]
+	group := func(i int) string {
+		if m[2*i] < 0 {
+			return ""
+		}
+		return title[m[2*i]:m[2*i+1]]
+	}
+
+	var replacement string
+	if group(1) != "" {
+		start, _ := strconv.Atoi(group(1))
+		end := group(3)
+		endYear, _ := strconv.Atoi(end)
+		if len(end) == 2 {
+			end = fmt.Sprintf("%02d", (endYear+1)%100)
+		} else {
+			end = strconv.Itoa(endYear + 1)
+		}
+		replacement = strconv.Itoa(start+1) + group(2) + end
+	} else {
+		digits := group(4)
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			return "", false
+		}
+		replacement = fmt.Sprintf("%0*d", len(digits), n+1)
+		if suffix := group(5); suffix != "" {
+			replacement += matchCase(ordinalSuffix(n+1), suffix)
+		}
+	}
+	return title[:m[0]] + replacement + title[m[1]:], true
+}
+
+// ordinalSuffix returns the English ordinal suffix of n
+func ordinalSuffix(n int) string {
+	if n%100 >= 11 && n%100 <= 13 {
+		return "th"
+	}
+	switch n % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	default:
+		return "th"
+	}
+}
+
+// matchCase upper-cases suffix when like is upper case, so "1ST" becomes "2ND"
+func matchCase(suffix, like string) string {
+	if like == strings.ToUpper(like) {
+		return strings.ToUpper(suffix)
+	}
+	return suffix
+}
+
+// recurrencePeriod describes when a milestone with the given recurrence is due
+func recurrencePeriod(dueDate time.Time, recurrenceType models.RecurrenceType) string {
+	switch recurrenceType {
+	case models.RecurrenceWeekly:
+		return "week of " + dueDate.Format("2 January 2006")
+	case models.RecurrenceYearly:
+		return dueDate.Format("2006")
+	default:
+		return dueDate.Format("January 2006")
+	}
+}
+
+// truncateTitle joins base and suffix, shortening base so the result fits the title column
+func truncateTitle(base, suffix string) string {
+	if limit := maxMilestoneTitleLength - len(suffix); len(base) > limit {
+		base = strings.TrimSpace(strings.ToValidUTF8(base[:limit], ""))
+	}
+	return base + suffix
+}
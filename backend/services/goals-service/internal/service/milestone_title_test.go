@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+func TestGenerateNextTitle(t *testing.T) {
+	march := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		title      string
+		cycle      int
+		dueDate    *time.Time
+		recurrence models.RecurrenceType
+		want       string
+	}{
+		{name: "trailing integer", title: "Semester 1", want: "Semester 2"},
+		{name: "two digits", title: "Week 12", want: "Week 13"},
+		{name: "zero padded", title: "Week 09", want: "Week 10"},
+		{name: "padding kept", title: "Batch 007", want: "Batch 008"},
+		{name: "ordinal", title: "3rd Term", want: "4th Term"},
+		{name: "ordinal into the teens", title: "10th Meeting", want: "11th Meeting"},
+		{name: "ordinal out of the teens", title: "20th Meeting", want: "21st Meeting"},
+		{name: "upper case ordinal", title: "1ST QUARTER", want: "2ND QUARTER"},
+		{name: "academic session", title: "2024/2025 Session", want: "2025/2026 Session"},
+		{name: "short academic session", title: "2024-25 Session", want: "2025-26 Session"},
+		{name: "session across a century", title: "1999/00 Session", want: "2000/01 Session"},
+		{name: "last number is incremented", title: "Levy 2 of Phase 3", want: "Levy 2 of Phase 4"},
+		{name: "legacy Next suffixes", title: "Semester 1 (Next) (Next) (Next)", want: "Semester 2"},
+		{
+			name: "no number gets a cycle and period", title: "Rent", cycle: 4, dueDate: &march,
+			recurrence: models.RecurrenceMonthly, want: "Rent — cycle 4 (March 2025)",
+		},
+		{
+			name: "cycle suffix is replaced", title: "Rent — cycle 3 (February 2025)", cycle: 4, dueDate: &march,
+			recurrence: models.RecurrenceMonthly, want: "Rent — cycle 4 (March 2025)",
+		},
+		{name: "no due date", title: "Rent (Next)", cycle: 2, recurrence: models.RecurrenceMonthly, want: "Rent — cycle 2"},
+		{
+			name: "weekly period", title: "Dues", cycle: 5, dueDate: &march,
+			recurrence: models.RecurrenceWeekly, want: "Dues — cycle 5 (week of 10 March 2025)",
+		},
+		{
+			name: "yearly period", title: "Subscription", cycle: 2, dueDate: &march,
+			recurrence: models.RecurrenceYearly, want: "Subscription — cycle 2 (2025)",
+		},
+		{
+			name: "semester period", title: "School fees", cycle: 3, dueDate: &march,
+			recurrence: models.RecurrenceSemester, want: "School fees — cycle 3 (March 2025)",
+		},
+		{
+			name: "digits inside a word are not a number", title: "Q1 dues", cycle: 2, dueDate: &march,
+			recurrence: models.RecurrenceMonthly, want: "Q1 dues — cycle 2 (March 2025)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := generateNextTitle(tt.title, tt.cycle, tt.dueDate, tt.recurrence); got != tt.want {
+				t.Errorf("generateNextTitle(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateNextTitleFitsTheColumn(t *testing.T) {
+	march := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		title      string
+		wantSuffix string
+	}{
+		{name: "cycle suffix is kept", title: strings.Repeat("a", 300), wantSuffix: " — cycle 2 (March 2025)"},
+		{name: "multi-byte characters are not split", title: strings.Repeat("é", 200)},
+		{name: "long title with a number", title: strings.Repeat("a", 300) + " 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateNextTitle(tt.title, 2, &march, models.RecurrenceMonthly)
+			if len(got) > maxMilestoneTitleLength {
+				t.Errorf("title is %d bytes, want at most %d", len(got), maxMilestoneTitleLength)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("title %q is not valid UTF-8", got)
+			}
+			if !strings.HasSuffix(got, tt.wantSuffix) {
+				t.Errorf("title %q does not end with %q", got, tt.wantSuffix)
+			}
+		})
+	}
+}
+
+func TestOrdinalSuffix(t *testing.T) {
+	for n, want := range map[int]string{
+		1: "st", 2: "nd", 3: "rd", 4: "th", 11: "th", 12: "th", 13: "th",
+		21: "st", 22: "nd", 23: "rd", 101: "st", 111: "th", 112: "th",
+	} {
+		if got := ordinalSuffix(n); got != want {
+			t.Errorf("ordinalSuffix(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+// TestRecurringSuccessorsChainToTheirParent completes a recurring milestone twice; each
+// successor links to the milestone it was created from and is titled for its cycle
+func TestRecurringSuccessorsChainToTheirParent(t *testing.T) {
+	repo := newTestRepo(t)
+	s := NewGoalService(repo, nil, nil, nil, NewInviteTokens("secret", time.Hour))
+	ctx := context.Background()
+	goal := createTestGoal(t, repo, uuid.New())
+
+	monthly := models.RecurrenceMonthly
+	due := time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC)
+	milestone := &models.Milestone{
+		GoalID:             goal.ID,
+		Title:              "Rent",
+		TargetAmount:       50_000,
+		IsRecurring:        true,
+		RecurrenceType:     &monthly,
+		RecurrenceInterval: 1,
+		NextDueDate:        &due,
+		Status:             models.MilestoneStatusActive,
+	}
+	if err := repo.Milestone.CreateMilestone(ctx, milestone); err != nil {
+		t.Fatalf("CreateMilestone: %v", err)
+	}
+
+	current := milestone
+	for _, want := range []string{"Rent — cycle 2 (March 2025)", "Rent — cycle 3 (April 2025)"} {
+		_, next, err := s.CompleteMilestone(ctx, current.ID, goal.OwnerID)
+		if err != nil {
+			t.Fatalf("CompleteMilestone: %v", err)
+		}
+		if next == nil {
+			t.Fatal("no successor was created")
+		}
+		if next.Title != want {
+			t.Errorf("successor title = %q, want %q", next.Title, want)
+		}
+		if next.ParentMilestoneID == nil || *next.ParentMilestoneID != current.ID {
+			t.Errorf("successor parent = %v, want %s", next.ParentMilestoneID, current.ID)
+		}
+		current = next
+	}
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/httperr"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidReportCategory   = apperrors.Validation("invalid_report_category", "category must be fraud, spam, misleading, inappropriate or other")
+	ErrCannotReportOwnGoal     = apperrors.Validation("cannot_report_own_goal", "you cannot report your own goal")
+	ErrAlreadyReported         = apperrors.Conflict("already_reported", "you have already reported this goal")
+	ErrNoOpenReports           = apperrors.Conflict("no_open_reports", "the goal has no open reports to dismiss")
+	ErrInvalidModerationAction = apperrors.Validation("invalid_moderation_action", "action must be suspend, unsuspend or dismiss_reports")
+	ErrTooManyModerationGoals  = apperrors.Validation("too_many_goals", "a bulk action takes between 1 and 50 goals")
+)
+
+const (
+	maxBulkModerationGoals = 50
+	maxModerationPageSize  = 100
+	maxReportDetailsLength = 1000
+)
+
+// userReportCategories are the categories a user may report a goal under; the rest are
+// reserved for automatic flags
+var userReportCategories = map[models.GoalReportCategory]bool{
+	models.GoalReportCategoryFraud:         true,
+	models.GoalReportCategorySpam:          true,
+	models.GoalReportCategoryMisleading:    true,
+	models.GoalReportCategoryInappropriate: true,
+	models.GoalReportCategoryOther:         true,
+}
+
+// ModerationService handles goal reports, the admin moderation queue and bulk moderation
+type ModerationService struct {
+	repo  *repository.Repository
+	goals *GoalService
+}
+
+// NewModerationService creates a new moderation service
+func NewModerationService(repo *repository.Repository, goals *GoalService) *ModerationService {
+	return &ModerationService{repo: repo, goals: goals}
+}
+
+// ReportGoal records a user's report of a goal they can see. A user has at most one open
+// report per goal.
+func (s *ModerationService) ReportGoal(ctx context.Context, goalID, reporterID uuid.UUID, req dto.ReportGoalRequest) (*models.GoalReport, error) {
+	category := models.GoalReportCategory(strings.ToLower(strings.TrimSpace(string(req.Category))))
+	if !userReportCategories[category] {
+		return nil, ErrInvalidReportCategory
+	}
+	details := strings.TrimSpace(req.Details)
+	if len(details) > maxReportDetailsLength {
+		return nil, apperrors.Validation("invalid_report_details", "details must be at most 1000 characters")
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if err := checkGoalVisible(ctx, s.repo, goal, reporterID); err != nil {
+		return nil, err
+	}
+	if goal.OwnerID == reporterID {
+		return nil, ErrCannotReportOwnGoal
+	}
+
+	report := &models.GoalReport{
+		GoalID:     goalID,
+		ReporterID: &reporterID,
+		Source:     models.GoalReportSourceUser,
+		Category:   category,
+		Details:    details,
+	}
+	// The unique open reporter index settles concurrent reports from the same user
+	if err := s.repo.Report.CreateReport(ctx, report); err != nil {
+		if errors.Is(err, repository.ErrDuplicateReport) {
+			return nil, ErrAlreadyReported
+		}
+		return nil, err
+	}
+
+	metrics.IncrementCounter("goals.report.created", "category:"+string(category))
+	return report, nil
+}
+
+// flagGoal raises an automatic flag on a goal in tx, putting it in the moderation queue
+func flagGoal(ctx context.Context, tx *repository.Repository, goalID uuid.UUID, category models.GoalReportCategory, details string) error {
+	err := tx.Report.CreateReport(ctx, &models.GoalReport{
+		GoalID:   goalID,
+		Source:   models.GoalReportSourceAuto,
+		Category: category,
+		Details:  details,
+	})
+	if err != nil {
+		return err
+	}
+	metrics.IncrementCounter("goals.report.flagged", "category:"+string(category))
+	return nil
+}
+
+// GetModerationQueue returns a page of the goals with open reports or flags, highest
+// priority first. An empty category or status and a zero minReports do not filter.
+func (s *ModerationService) GetModerationQueue(ctx context.Context, category models.GoalReportCategory, status models.GoalStatus, minReports int64, page, pageSize int) ([]dto.ModerationQueueItem, int64, error) {
+	filter := repository.ModerationQueueFilter{
+		Category:   models.GoalReportCategory(strings.ToLower(strings.TrimSpace(string(category)))),
+		Status:     models.GoalStatus(strings.ToUpper(strings.TrimSpace(string(status)))),
+		MinReports: minReports,
+	}
+	if filter.Category != "" && !userReportCategories[filter.Category] && filter.Category != models.GoalReportCategoryProofRejected {
+		return nil, 0, ErrInvalidReportCategory
+	}
+	if filter.MinReports < 0 {
+		return nil, 0, apperrors.Validation("invalid_min_reports", "min_reports must not be negative")
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > maxModerationPageSize {
+		pageSize = maxModerationPageSize
+	}
+	offset := (page - 1) * pageSize
+
+	rows, total, err := s.repo.Report.GetModerationQueue(ctx, filter, time.Now(), pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]dto.ModerationQueueItem, len(rows))
+	for i, row := range rows {
+		items[i] = dto.ModerationQueueItem{
+			GoalID:         row.GoalID,
+			Title:          row.Title,
+			OwnerID:        row.OwnerID,
+			Status:         row.Status,
+			CurrentAmount:  row.CurrentAmount,
+			Currency:       row.Currency,
+			ReportCount:    row.ReportCount,
+			AutoFlags:      row.AutoFlags,
+			LastReportedAt: row.LastReportedAt,
+			PriorityScore:  row.PriorityScore,
+		}
+	}
+	return items, total, nil
+}
+
+// DismissReports dismisses every open report and flag on a goal on an admin's behalf,
+// taking it off the moderation queue. It returns how many it dismissed.
+func (s *ModerationService) DismissReports(ctx context.Context, goalID, adminID uuid.UUID) (int64, error) {
+	if _, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrGoalNotFound
+		}
+		return 0, err
+	}
+
+	now := time.Now()
+	var dismissed int64
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		var err error
+		dismissed, err = tx.Report.DismissOpenReports(ctx, goalID, adminID, now)
+		if err != nil {
+			return err
+		}
+		if dismissed == 0 {
+			return ErrNoOpenReports
+		}
+		err = recordAudit(ctx, tx, AuditEntry{
+			GoalID:     goalID,
+			ActorID:    adminID,
+			Action:     models.AuditActionReportsDismissed,
+			EntityType: auditEntityGoal,
+			EntityID:   goalID,
+			Before:     map[string]interface{}{"open_reports": dismissed},
+			After:      map[string]interface{}{"open_reports": 0},
+		})
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, "GoalReportsDismissed", events.GoalReportsDismissed{
+			ID:          uuid.New().String(),
+			GoalID:      goalID.String(),
+			DismissedBy: adminID.String(),
+			Dismissed:   dismissed,
+			CreatedAt:   now.Unix(),
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return dismissed, nil
+}
+
+// BulkModerate applies one moderation action to each of up to 50 goals, each in its own
+// transaction, so one goal failing does not undo or stop the others. The response reports
+// every goal's outcome in request order; repeated IDs are acted on once.
+func (s *ModerationService) BulkModerate(ctx context.Context, adminID uuid.UUID, req dto.BulkModerationRequest) (*dto.BulkModerationResponse, error) {
+	if len(req.GoalIDs) == 0 || len(req.GoalIDs) > maxBulkModerationGoals {
+		return nil, ErrTooManyModerationGoals
+	}
+	switch req.Action {
+	case dto.ModerationActionSuspend, dto.ModerationActionUnsuspend, dto.ModerationActionDismissReports:
+	default:
+		return nil, ErrInvalidModerationAction
+	}
+
+	response := &dto.BulkModerationResponse{Results: make([]dto.BulkModerationResult, 0, len(req.GoalIDs))}
+	seen := make(map[string]bool, len(req.GoalIDs))
+	for _, rawID := range req.GoalIDs {
+		if seen[rawID] {
+			continue
+		}
+		seen[rawID] = true
+
+		result := s.moderateGoal(ctx, adminID, req, rawID)
+		if result.Success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	metrics.IncrementCounter("goals.moderation.bulk", "action:"+req.Action)
+	return response, nil
+}
+
+// moderateGoal applies a bulk moderation action to one goal and reports the outcome
+func (s *ModerationService) moderateGoal(ctx context.Context, adminID uuid.UUID, req dto.BulkModerationRequest, rawID string) dto.BulkModerationResult {
+	result := dto.BulkModerationResult{GoalID: rawID}
+
+	goalID, err := uuid.Parse(rawID)
+	if err != nil {
+		err = apperrors.Validation("invalid_id", "Invalid goal ID")
+	} else {
+		var goal *models.Goal
+		switch req.Action {
+		case dto.ModerationActionSuspend:
+			goal, err = s.goals.SuspendGoal(ctx, goalID, adminID, req.Reason)
+		case dto.ModerationActionUnsuspend:
+			goal, err = s.goals.UnsuspendGoal(ctx, goalID, adminID)
+		case dto.ModerationActionDismissReports:
+			result.Dismissed, err = s.DismissReports(ctx, goalID, adminID)
+		}
+		if goal != nil {
+			result.Status = goal.Status
+		}
+	}
+
+	if err == nil {
+		result.Success = true
+		return result
+	}
+
+	if domainErr := apperrors.As(err); domainErr != nil {
+		result.ErrorCode = domainErr.Code
+		result.Error = domainErr.Message
+	} else {
+		// Internal failures are logged, not passed on to the caller
+		log.Printf("Bulk %s failed for goal %s: %v", req.Action, rawID, err)
+		result.ErrorCode = httperr.CodeInternal
+		result.Error = "internal error"
+	}
+	metrics.IncrementCounter("goals.moderation.bulk.failed", "action:"+req.Action, "code:"+result.ErrorCode)
+	return result
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// newModerationTestService returns a moderation service over a fresh test database that
+// keeps an audit log, with the audit log repository to check it through
+func newModerationTestService(t *testing.T) (*ModerationService, *repository.Repository, *repository.AuditLogRepository) {
+	t.Helper()
+	db := testdb.Open(t)
+	repo := repository.NewRepository(db)
+	audits := repository.NewAuditLogRepository(db)
+	audit := NewAuditService(audits, repo)
+	goals := NewGoalService(repo, audit, nil, nil, NewInviteTokens("test-invite-secret", time.Hour))
+	return NewModerationService(repo, goals), repo, audits
+}
+
+// createTestReport files an open report of category on goal, made at createdAt
+func createTestReport(t *testing.T, repo *repository.Repository, goal *models.Goal, source models.GoalReportSource, category models.GoalReportCategory, createdAt time.Time) {
+	t.Helper()
+	report := &models.GoalReport{GoalID: goal.ID, Source: source, Category: category, CreatedAt: createdAt}
+	if source == models.GoalReportSourceUser {
+		reporterID := uuid.New()
+		report.ReporterID = &reporterID
+	}
+	if err := repo.Report.CreateReport(context.Background(), report); err != nil {
+		t.Fatalf("CreateReport: %v", err)
+	}
+}
+
+// queueTitles returns the titles of the goals on a moderation queue page, in order
+func queueTitles(items []dto.ModerationQueueItem) string {
+	titles := make([]string, len(items))
+	for i, item := range items {
+		titles[i] = item.Title
+	}
+	return strings.Join(titles, ",")
+}
+
+// TestModerationQueuePriority checks the queue weighs report count, money at risk, recency
+// and automatic flags, and filters and pages the result
+func TestModerationQueuePriority(t *testing.T) {
+	s, repo, _ := newModerationTestService(t)
+	ctx := context.Background()
+	now := time.Now()
+	old := now.Add(-72 * time.Hour) // three half-lives: worth 1/8 of the recency weight
+
+	titled := func(title string, currentAmount int64) func(*models.Goal) {
+		return func(g *models.Goal) {
+			g.Title = title
+			g.CurrentAmount = currentAmount
+		}
+	}
+
+	// 10 per report, 10 per tenfold of naira raised, up to 30 for recency, 15 per flag
+	rich := createTestGoal(t, repo, uuid.New(), titled("rich", 100_000_000)) // 10 + ~60 + 3.75
+	createTestReport(t, repo, rich, models.GoalReportSourceUser, models.GoalReportCategoryMisleading, old)
+
+	many := createTestGoal(t, repo, uuid.New(), titled("many", 0)) // 50 + 3.75
+	for i := 0; i < 5; i++ {
+		createTestReport(t, repo, many, models.GoalReportSourceUser, models.GoalReportCategoryFraud, old)
+	}
+
+	recent := createTestGoal(t, repo, uuid.New(), titled("recent", 0)) // 10 + ~30
+	createTestReport(t, repo, recent, models.GoalReportSourceUser, models.GoalReportCategorySpam, now)
+
+	flagged := createTestGoal(t, repo, uuid.New(), titled("flagged", 0)) // 10 + 15 + 3.75
+	createTestReport(t, repo, flagged, models.GoalReportSourceAuto, models.GoalReportCategoryProofRejected, old)
+
+	stale := createTestGoal(t, repo, uuid.New(), titled("stale", 0)) // 10 + 3.75
+	createTestReport(t, repo, stale, models.GoalReportSourceUser, models.GoalReportCategoryFraud, old)
+	setGoalStatus(t, repo, stale, models.GoalStatusClosed)
+
+	// Dismissed reports leave the queue
+	dismissed := createTestGoal(t, repo, uuid.New(), titled("dismissed", 0))
+	createTestReport(t, repo, dismissed, models.GoalReportSourceUser, models.GoalReportCategoryFraud, now)
+	if _, err := repo.Report.DismissOpenReports(ctx, dismissed.ID, uuid.New(), now); err != nil {
+		t.Fatalf("DismissOpenReports: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   repository.ModerationQueueFilter
+		page     int
+		pageSize int
+		want     string
+		total    int64
+	}{
+		{name: "priority order", want: "rich,many,recent,flagged,stale", total: 5},
+		{name: "second page", page: 2, pageSize: 2, want: "recent,flagged", total: 5},
+		{name: "category", filter: repository.ModerationQueueFilter{Category: "FRAUD"}, want: "many,stale", total: 2},
+		{name: "automatic flags", filter: repository.ModerationQueueFilter{Category: "proof_rejected"}, want: "flagged", total: 1},
+		{name: "status", filter: repository.ModerationQueueFilter{Status: "closed"}, want: "stale", total: 1},
+		{name: "min reports", filter: repository.ModerationQueueFilter{MinReports: 2}, want: "many", total: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, total, err := s.GetModerationQueue(ctx, tt.filter.Category, tt.filter.Status, tt.filter.MinReports, tt.page, tt.pageSize)
+			if err != nil {
+				t.Fatalf("GetModerationQueue: %v", err)
+			}
+			if got := queueTitles(items); got != tt.want || total != tt.total {
+				t.Errorf("queue = %s (total %d), want %s (total %d)", got, total, tt.want, tt.total)
+			}
+		})
+	}
+
+	items, _, err := s.GetModerationQueue(ctx, "", "", 0, 1, 20)
+	if err != nil {
+		t.Fatalf("GetModerationQueue: %v", err)
+	}
+	for _, item := range items {
+		if item.Title == "many" && item.ReportCount != 5 {
+			t.Errorf("many has %d reports, want 5", item.ReportCount)
+		}
+		if item.Title == "flagged" && (item.ReportCount != 1 || item.AutoFlags != 1) {
+			t.Errorf("flagged has %d reports and %d flags, want 1 and 1", item.ReportCount, item.AutoFlags)
+		}
+	}
+
+	if _, _, err := s.GetModerationQueue(ctx, "rude", "", 0, 1, 20); errorCode(err) != "invalid_report_category" {
+		t.Errorf("unknown category: err = %v, want invalid_report_category", err)
+	}
+}
+
+// TestBulkModerationReportsPartialFailures suspends a mix of goals that can and cannot be
+// suspended; the good ones are suspended, audited and announced regardless of the others
+func TestBulkModerationReportsPartialFailures(t *testing.T) {
+	s, repo, audits := newModerationTestService(t)
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	first := createTestGoal(t, repo, uuid.New())
+	second := createTestGoal(t, repo, uuid.New())
+	cancelled := createTestGoal(t, repo, uuid.New())
+	setGoalStatus(t, repo, cancelled, models.GoalStatusCancelled)
+	missing := uuid.New()
+
+	response, err := s.BulkModerate(ctx, adminID, dto.BulkModerationRequest{
+		GoalIDs: []string{first.ID.String(), cancelled.ID.String(), missing.String(), second.ID.String(), first.ID.String()},
+		Action:  dto.ModerationActionSuspend,
+		Reason:  "reported as fraudulent",
+	})
+	if err != nil {
+		t.Fatalf("BulkModerate: %v", err)
+	}
+
+	want := []dto.BulkModerationResult{
+		{GoalID: first.ID.String(), Success: true, Status: models.GoalStatusSuspended},
+		{GoalID: cancelled.ID.String(), ErrorCode: "invalid_goal_status"},
+		{GoalID: missing.String(), ErrorCode: "goal_not_found"},
+		{GoalID: second.ID.String(), Success: true, Status: models.GoalStatusSuspended},
+	}
+	if len(response.Results) != len(want) {
+		t.Fatalf("results = %+v, want one per distinct goal", response.Results)
+	}
+	for i, result := range response.Results {
+		result.Error = ""
+		if result != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, result, want[i])
+		}
+	}
+	if response.Succeeded != 2 || response.Failed != 2 {
+		t.Errorf("succeeded %d and failed %d, want 2 and 2", response.Succeeded, response.Failed)
+	}
+	assertOutbox(t, repo, map[string]int{"GoalSuspended": 2})
+
+	for _, goal := range []*models.Goal{first, second} {
+		logs, _, err := audits.GetAuditLogsByGoalID(ctx, goal.ID, 10, 0)
+		if err != nil {
+			t.Fatalf("GetAuditLogsByGoalID: %v", err)
+		}
+		if len(logs) != 1 || logs[0].Action != models.AuditActionGoalSuspended || logs[0].ActorID != adminID {
+			t.Errorf("audit log of %s = %+v, want one suspension by the admin", goal.ID, logs)
+		}
+	}
+
+	// Lifting the suspensions restores the goals and is audited and announced too
+	response, err = s.BulkModerate(ctx, adminID, dto.BulkModerationRequest{
+		GoalIDs: []string{first.ID.String(), cancelled.ID.String()},
+		Action:  dto.ModerationActionUnsuspend,
+	})
+	if err != nil {
+		t.Fatalf("BulkModerate: %v", err)
+	}
+	if !response.Results[0].Success || response.Results[0].Status != models.GoalStatusOpen || response.Results[1].ErrorCode != "invalid_goal_status" {
+		t.Errorf("unsuspend results = %+v, want the first goal reopened and the cancelled goal refused", response.Results)
+	}
+	assertOutbox(t, repo, map[string]int{"GoalSuspended": 2, "GoalUnsuspended": 1})
+	if logs, _, _ := audits.GetAuditLogsByGoalID(ctx, first.ID, 10, 0); len(logs) != 2 {
+		t.Errorf("first goal has %d audit entries, want the suspension and its lifting", len(logs))
+	}
+}
+
+func TestBulkDismissReports(t *testing.T) {
+	s, repo, audits := newModerationTestService(t)
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	reported := createTestGoal(t, repo, uuid.New())
+	createTestReport(t, repo, reported, models.GoalReportSourceUser, models.GoalReportCategorySpam, time.Now())
+	createTestReport(t, repo, reported, models.GoalReportSourceAuto, models.GoalReportCategoryProofRejected, time.Now())
+	unreported := createTestGoal(t, repo, uuid.New())
+
+	response, err := s.BulkModerate(ctx, adminID, dto.BulkModerationRequest{
+		GoalIDs: []string{reported.ID.String(), unreported.ID.String()},
+		Action:  dto.ModerationActionDismissReports,
+	})
+	if err != nil {
+		t.Fatalf("BulkModerate: %v", err)
+	}
+	if !response.Results[0].Success || response.Results[0].Dismissed != 2 {
+		t.Errorf("reported goal result = %+v, want 2 reports dismissed", response.Results[0])
+	}
+	if response.Results[1].ErrorCode != "no_open_reports" {
+		t.Errorf("unreported goal result = %+v, want no_open_reports", response.Results[1])
+	}
+	assertOutbox(t, repo, map[string]int{"GoalReportsDismissed": 1})
+
+	logs, _, err := audits.GetAuditLogsByGoalID(ctx, reported.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("GetAuditLogsByGoalID: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Action != models.AuditActionReportsDismissed {
+		t.Errorf("audit log = %+v, want the dismissal", logs)
+	}
+	if _, total, _ := s.GetModerationQueue(ctx, "", "", 0, 1, 20); total != 0 {
+		t.Errorf("%d goals left on the queue, want none", total)
+	}
+}
+
+func TestBulkModerationValidatesRequest(t *testing.T) {
+	s := NewModerationService(nil, nil)
+	tooMany := make([]string, maxBulkModerationGoals+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.NewString()
+	}
+
+	tests := []struct {
+		name     string
+		req      dto.BulkModerationRequest
+		wantCode string
+	}{
+		{name: "no goals", req: dto.BulkModerationRequest{Action: dto.ModerationActionSuspend}, wantCode: "too_many_goals"},
+		{name: "too many goals", req: dto.BulkModerationRequest{GoalIDs: tooMany, Action: dto.ModerationActionSuspend}, wantCode: "too_many_goals"},
+		{name: "unknown action", req: dto.BulkModerationRequest{GoalIDs: tooMany[:1], Action: "delete"}, wantCode: "invalid_moderation_action"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.BulkModerate(context.Background(), uuid.New(), tt.req); errorCode(err) != tt.wantCode {
+				t.Errorf("err = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestReportGoal(t *testing.T) {
+	s, repo, _ := newModerationTestService(t)
+	ctx := context.Background()
+	goal := createTestGoal(t, repo, uuid.New())
+	reporterID := uuid.New()
+
+	report, err := s.ReportGoal(ctx, goal.ID, reporterID, dto.ReportGoalRequest{Category: " Fraud ", Details: "the photos are stock images"})
+	if err != nil {
+		t.Fatalf("ReportGoal: %v", err)
+	}
+	if report.Category != models.GoalReportCategoryFraud || report.Source != models.GoalReportSourceUser || report.Status != models.GoalReportStatusOpen {
+		t.Errorf("report = %+v, want an open user report for fraud", report)
+	}
+
+	tests := []struct {
+		name       string
+		goalID     uuid.UUID
+		reporterID uuid.UUID
+		category   models.GoalReportCategory
+		wantCode   string
+	}{
+		{name: "reported twice", goalID: goal.ID, reporterID: reporterID, category: models.GoalReportCategorySpam, wantCode: "already_reported"},
+		{name: "own goal", goalID: goal.ID, reporterID: goal.OwnerID, category: models.GoalReportCategorySpam, wantCode: "cannot_report_own_goal"},
+		{name: "reserved category", goalID: goal.ID, reporterID: uuid.New(), category: models.GoalReportCategoryProofRejected, wantCode: "invalid_report_category"},
+		{name: "unknown goal", goalID: uuid.New(), reporterID: uuid.New(), category: models.GoalReportCategorySpam, wantCode: "goal_not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ReportGoal(ctx, tt.goalID, tt.reporterID, dto.ReportGoalRequest{Category: tt.category})
+			if code := errorCode(err); code != tt.wantCode {
+				t.Errorf("err = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+// TestConcurrentReportsFromOneUser files the same user's report many times at once; the
+// unique open reporter index lets exactly one through
+func TestConcurrentReportsFromOneUser(t *testing.T) {
+	s, repo, _ := newModerationTestService(t)
+	goal := createTestGoal(t, repo, uuid.New())
+	reporterID := uuid.New()
+
+	const attempts = 8
+	errs := make(chan error, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.ReportGoal(context.Background(), goal.ID, reporterID, dto.ReportGoalRequest{Category: models.GoalReportCategorySpam})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		switch code := errorCode(err); {
+		case err == nil:
+			created++
+		case code != "already_reported":
+			t.Errorf("err = %v, want already_reported", err)
+		}
+	}
+	if created != 1 {
+		t.Errorf("%d reports created, want 1", created)
+	}
+
+	// Once dismissed, the user may report the goal again
+	if _, err := repo.Report.DismissOpenReports(context.Background(), goal.ID, uuid.New(), time.Now()); err != nil {
+		t.Fatalf("DismissOpenReports: %v", err)
+	}
+	if _, err := s.ReportGoal(context.Background(), goal.ID, reporterID, dto.ReportGoalRequest{Category: models.GoalReportCategorySpam}); err != nil {
+		t.Errorf("reporting again after a dismissal: %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/shared/messaging"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/gofund/shared/requestid"
+)
+
+const (
+	outboxBatchSize = 100
+	// outboxLease keeps other dispatchers off an event while it is published; if the
+	// dispatcher dies mid-publish the event is tried again once the lease runs out
+	outboxLease = time.Minute
+	// outboxRetryBackoff is the wait before the first retry of a failed publish; each
+	// further retry waits twice as long, up to ten minutes
+	outboxRetryBackoff    = 5 * time.Second
+	outboxRetryBackoffMax = 10 * time.Minute
+	// outboxPruneInterval is how often events published longer ago than the retention
+	// period are deleted
+	outboxPruneInterval = time.Hour
+)
+
+// recordEvent records event in the outbox through repo. Given a repository built over a
+// transaction, the event is published only if the transaction commits.
+func recordEvent(ctx context.Context, repo *repository.Repository, eventType string, event interface{}) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+	return repo.Outbox.AddEvent(ctx, &models.OutboxEvent{
+		EventType:     eventType,
+		Payload:       string(payload),
+		RequestID:     requestid.FromContext(ctx),
+		NextAttemptAt: time.Now(),
+	})
+}
+
+// OutboxPublisher is the messaging.Publisher services publish events through. It records
+// each event in the outbox for OutboxDispatcher to publish, so an event is not lost when
+// RabbitMQ is down or not configured. Events announcing a change made in a transaction
+// are recorded in that transaction with recordEvent instead.
+type OutboxPublisher struct {
+	repo *repository.Repository
+}
+
+// NewOutboxPublisher creates a new outbox publisher
+func NewOutboxPublisher(repo *repository.Repository) *OutboxPublisher {
+	return &OutboxPublisher{repo: repo}
+}
+
+// Publish records an event in the outbox
+func (p *OutboxPublisher) Publish(eventType string, event interface{}) error {
+	return p.PublishContext(context.Background(), eventType, event)
+}
+
+// PublishContext records an event in the outbox with the request ID carried by ctx, which
+// it is published with
+func (p *OutboxPublisher) PublishContext(ctx context.Context, eventType string, event interface{}) error {
+	return recordEvent(ctx, p.repo, eventType, event)
+}
+
+// OutboxDispatcher publishes the events recorded in the outbox to RabbitMQ, retrying
+// failures with backoff, and deletes them retention after they were published
+type OutboxDispatcher struct {
+	repo      *repository.Repository
+	publisher messaging.Publisher
+	retention time.Duration
+}
+
+// NewOutboxDispatcher creates a new outbox dispatcher. Without a publisher events stay in
+// the outbox until a dispatcher with one runs.
+func NewOutboxDispatcher(repo *repository.Repository, publisher messaging.Publisher, retention time.Duration) *OutboxDispatcher {
+	return &OutboxDispatcher{repo: repo, publisher: publisher, retention: retention}
+}
+
+// Run publishes due outbox events every interval until ctx is cancelled
+func (d *OutboxDispatcher) Run(ctx context.Context, interval time.Duration) {
+	if d.publisher == nil {
+		log.Printf("Warning: no RabbitMQ publisher; outbox events will be published after a restart with one")
+		return
+	}
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	pruneTicker := time.NewTicker(outboxPruneInterval)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.dispatchDue(ctx, time.Now())
+		case <-pruneTicker.C:
+			d.prune(ctx, time.Now())
+		}
+	}
+}
+
+// dispatchDue publishes the events due at now that this dispatcher claims, oldest first and
+// a batch at a time, until none are left or one fails to publish. After a failure the broker
+// is likely down, so the rest wait for the next run.
+func (d *OutboxDispatcher) dispatchDue(ctx context.Context, now time.Time) {
+	defer d.trackUnsent(ctx)
+
+	for ctx.Err() == nil {
+		due, err := d.repo.Outbox.GetDueEvents(ctx, now, outboxBatchSize)
+		if err != nil {
+			log.Printf("Failed to load due outbox events: %v", err)
+			return
+		}
+
+		claimedAny := false
+		for i := range due {
+			event := &due[i]
+			claimed, err := d.repo.Outbox.ClaimEvent(ctx, event.ID, now, now.Add(outboxLease))
+			if err != nil {
+				log.Printf("Failed to claim outbox event %s: %v", event.ID, err)
+				continue
+			}
+			if !claimed {
+				continue
+			}
+			claimedAny = true
+			if !d.dispatch(ctx, event) {
+				return
+			}
+		}
+
+		if len(due) < outboxBatchSize || !claimedAny {
+			return
+		}
+	}
+}
+
+// trackUnsent reports how many events are waiting to be published
+func (d *OutboxDispatcher) trackUnsent(ctx context.Context) {
+	if unsent, err := d.repo.Outbox.CountUnsentEvents(ctx); err == nil {
+		metrics.RecordGauge("goals.outbox.unsent", float64(unsent))
+	}
+}
+
+// dispatch publishes a claimed event, records the outcome and reports whether it was
+// published
+func (d *OutboxDispatcher) dispatch(ctx context.Context, event *models.OutboxEvent) bool {
+	publishCtx := requestid.WithContext(ctx, event.RequestID)
+	err := d.publisher.PublishContext(publishCtx, event.EventType, json.RawMessage(event.Payload))
+	if err == nil {
+		if err := d.repo.Outbox.MarkEventSent(ctx, event.ID, time.Now()); err != nil {
+			// The event goes out again once the lease runs out
+			log.Printf("Failed to mark outbox event %s as sent: %v", event.ID, err)
+		}
+		metrics.IncrementCounter("goals.outbox.sent", "event_type:"+event.EventType)
+		return true
+	}
+
+	attempts := event.Attempts + 1
+	retryAt := time.Now().Add(outboxBackoff(attempts))
+	log.Printf("Failed to publish outbox event %s (%s, attempt %d), retrying at %s: %v",
+		event.ID, event.EventType, attempts, retryAt.Format(time.RFC3339), err)
+	metrics.IncrementCounter("goals.outbox.failed", "event_type:"+event.EventType)
+	if err := d.repo.Outbox.MarkEventFailed(ctx, event.ID, retryAt, err.Error()); err != nil {
+		log.Printf("Failed to record failed outbox event %s: %v", event.ID, err)
+	}
+	return false
+}
+
+// prune deletes events published longer than the retention period before now
+func (d *OutboxDispatcher) prune(ctx context.Context, now time.Time) {
+	if d.retention <= 0 {
+		return
+	}
+	deleted, err := d.repo.Outbox.DeleteSentEvents(ctx, now.Add(-d.retention))
+	if err != nil {
+		log.Printf("Failed to prune outbox events: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Pruned %d published outbox events", deleted)
+	}
+}
+
+// outboxBackoff returns how long to wait before retrying an event that failed to publish
+// attempts times
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxRetryBackoff
+	for i := 1; i < attempts && backoff < outboxRetryBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > outboxRetryBackoffMax {
+		return outboxRetryBackoffMax
+	}
+	return backoff
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// outboxCounts returns how many events of each type the outbox holds
+func outboxCounts(t *testing.T, repo *repository.Repository) map[string]int {
+	t.Helper()
+	recorded, err := repo.Outbox.GetDueEvents(context.Background(), time.Now().Add(time.Hour), 1000)
+	if err != nil {
+		t.Fatalf("GetDueEvents: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, event := range recorded {
+		counts[event.EventType]++
+	}
+	return counts
+}
+
+// assertOutbox fails unless the outbox holds exactly want, by event type
+func assertOutbox(t *testing.T, repo *repository.Repository, want map[string]int) {
+	t.Helper()
+	got := outboxCounts(t, repo)
+	for eventType, n := range want {
+		if got[eventType] != n {
+			t.Errorf("%s events = %d, want %d", eventType, got[eventType], n)
+		}
+	}
+	for eventType, n := range got {
+		if _, ok := want[eventType]; !ok {
+			t.Errorf("unexpected %d %s events", n, eventType)
+		}
+	}
+}
+
+func TestGoalStatusEventsCommitWithChange(t *testing.T) {
+	repo := newTestRepo(t)
+	goals := NewGoalService(repo, nil, nil, nil, NewInviteTokens("test-invite-secret", time.Hour))
+	ctx := context.Background()
+	ownerID := uuid.New()
+
+	closing := createTestGoal(t, repo, ownerID)
+	if _, err := goals.CloseGoal(ctx, closing.ID, ownerID); err != nil {
+		t.Fatalf("CloseGoal: %v", err)
+	}
+	// A refused change records nothing
+	if _, err := goals.CloseGoal(ctx, closing.ID, ownerID); !errors.Is(err, ErrInvalidGoalStatus) {
+		t.Fatalf("closing a closed goal: err = %v, want %v", err, ErrInvalidGoalStatus)
+	}
+
+	cancelling := createTestGoal(t, repo, ownerID)
+	if _, err := goals.CancelGoal(ctx, cancelling.ID, ownerID); err != nil {
+		t.Fatalf("CancelGoal: %v", err)
+	}
+
+	suspending := createTestGoal(t, repo, ownerID)
+	if _, err := goals.SuspendGoal(ctx, suspending.ID, uuid.New(), "reported"); err != nil {
+		t.Fatalf("SuspendGoal: %v", err)
+	}
+	if _, err := goals.UnsuspendGoal(ctx, suspending.ID, uuid.New()); err != nil {
+		t.Fatalf("UnsuspendGoal: %v", err)
+	}
+
+	assertOutbox(t, repo, map[string]int{"GoalClosed": 1, "GoalCancelled": 1, "GoalSuspended": 1, "GoalUnsuspended": 1})
+}
+
+func TestConfirmContributionRecordsEvents(t *testing.T) {
+	repo := newTestRepo(t)
+	contributions := NewContributionService(repo, nil, nil, nil, 0, 0)
+	ctx := context.Background()
+
+	goal := createTestGoal(t, repo, uuid.New(), func(g *models.Goal) {
+		g.TargetAmount = 100_000
+		g.CloseOnTarget = true
+	})
+	intent := &models.Contribution{GoalID: goal.ID, UserID: uuid.New(), Amount: 100_000, Currency: goal.Currency}
+	if err := repo.Contribution.CreateContribution(ctx, intent); err != nil {
+		t.Fatalf("CreateContribution: %v", err)
+	}
+
+	closed, err := contributions.ConfirmContribution(ctx, intent.ID, uuid.New())
+	if err != nil {
+		t.Fatalf("ConfirmContribution: %v", err)
+	}
+	if !closed {
+		t.Errorf("confirmation reaching the target did not close the close-on-target goal")
+	}
+
+	assertOutbox(t, repo, map[string]int{"ContributionConfirmed": 1, "GoalClosedEarly": 1, "GoalFunded": 1})
+}
+
+func TestRefundSettlementEvents(t *testing.T) {
+	f := newWithdrawalRefundFixture(t)
+
+	refund, err := f.refund(100)
+	if err != nil {
+		t.Fatalf("InitiateRefund: %v", err)
+	}
+	f.completeDisbursements(t, refund)
+	// A redelivered payout outcome records nothing more
+	f.completeDisbursements(t, refund)
+
+	assertOutbox(t, f.repo, map[string]int{"RefundInitiated": 1, "ContributionRefunded": 2, "RefundCompleted": 1})
+}
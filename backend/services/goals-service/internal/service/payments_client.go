@@ -0,0 +1,193 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofund/shared/metrics"
+	"github.com/google/uuid"
+)
+
+// PaymentsClient looks up payment details from payments-service
+type PaymentsClient struct {
+	baseURL      string
+	client       *http.Client
+	chargeClient *http.Client
+}
+
+// PaymentDetails is the part of a payment that goals-service needs for receipts
+type PaymentDetails struct {
+	Reference string
+	PaidAt    *time.Time
+}
+
+// NewPaymentsClient creates a payments-service client
+func NewPaymentsClient(baseURL string) *PaymentsClient {
+	return &PaymentsClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 3 * time.Second},
+		// Charging a card or resolving an account waits on Paystack, so it gets more time
+		// than a lookup
+		chargeClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GetPayment calls GET /internal/payments/:id on payments-service
+func (pc *PaymentsClient) GetPayment(paymentID uuid.UUID) (*PaymentDetails, error) {
+	if pc.baseURL == "" {
+		return nil, fmt.Errorf("payments-service URL not configured")
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/payments/%s", pc.baseURL, url.PathEscape(paymentID.String()))
+
+	start := time.Now()
+	resp, err := pc.client.Get(endpoint)
+	metrics.RecordDuration("goals.payments_client.duration", start)
+	if err != nil {
+		metrics.IncrementCounter("goals.payments_client.error")
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		metrics.IncrementCounter("goals.payments_client.error")
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Reference string  `json:"reference"`
+		PaidAt    *string `json:"paid_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	details := &PaymentDetails{Reference: body.Reference}
+	if body.PaidAt != nil {
+		// Paystack reports paid_at as RFC 3339; anything else is left for the caller to fill in
+		if paidAt, err := time.Parse(time.RFC3339, *body.PaidAt); err == nil {
+			details.PaidAt = &paidAt
+		}
+	}
+	return details, nil
+}
+
+// ChargeRequest asks payments-service to charge a contributor's saved card
+type ChargeRequest struct {
+	UserID            uuid.UUID  `json:"user_id"`
+	GoalID            uuid.UUID  `json:"goal_id"`
+	ContributionID    *uuid.UUID `json:"contribution_id,omitempty"`
+	Amount            int64      `json:"amount"`
+	Currency          string     `json:"currency"`
+	Email             string     `json:"email"`
+	AuthorizationCode string     `json:"authorization_code"`
+	IdempotencyKey    string     `json:"idempotency_key"`
+}
+
+// ChargeResult is the outcome of a charge: VERIFIED, FAILED, or PENDING until
+// Paystack settles it
+type ChargeResult struct {
+	PaymentID       string `json:"payment_id"`
+	Reference       string `json:"reference"`
+	Status          string `json:"status"`
+	GatewayResponse string `json:"gateway_response"`
+}
+
+// ChargeAuthorization calls POST /internal/payments/charge-authorization on payments-service
+func (pc *PaymentsClient) ChargeAuthorization(ctx context.Context, req ChargeRequest) (*ChargeResult, error) {
+	if pc.baseURL == "" {
+		return nil, fmt.Errorf("payments-service URL not configured")
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, pc.baseURL+"/internal/payments/charge-authorization", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := pc.chargeClient.Do(httpReq)
+	metrics.RecordDuration("goals.payments_client.duration", start, "op:charge")
+	if err != nil {
+		metrics.IncrementCounter("goals.payments_client.error", "op:charge")
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Status  string       `json:"status"`
+		Message string       `json:"message"`
+		Data    ChargeResult `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		metrics.IncrementCounter("goals.payments_client.error", "op:charge")
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body.Message)
+	}
+	return &body.Data, nil
+}
+
+// ErrAccountUnresolved is returned by ResolveAccount when payments-service cannot resolve
+// an account, e.g. because the bank is unknown or the account number is wrong
+var ErrAccountUnresolved = errors.New("account could not be resolved")
+
+// ResolveAccount calls GET /internal/payments/resolve-account on payments-service and
+// returns the name the bank holds for the account
+func (pc *PaymentsClient) ResolveAccount(ctx context.Context, accountNumber, bankName string) (string, error) {
+	if pc.baseURL == "" {
+		return "", fmt.Errorf("payments-service URL not configured")
+	}
+
+	query := url.Values{"account_number": {accountNumber}, "bank_name": {bankName}}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, pc.baseURL+"/internal/payments/resolve-account?"+query.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := pc.chargeClient.Do(httpReq)
+	metrics.RecordDuration("goals.payments_client.duration", start, "op:resolve_account")
+	if err != nil {
+		metrics.IncrementCounter("goals.payments_client.error", "op:resolve_account")
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// payments-service cannot tell a wrong account number from a failed lookup, so only an
+	// unavailable provider is treated as a transient failure
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		metrics.IncrementCounter("goals.payments_client.error", "op:resolve_account")
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", ErrAccountUnresolved
+	}
+
+	var body struct {
+		Data struct {
+			AccountName string `json:"account_name"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if body.Data.AccountName == "" {
+		return "", ErrAccountUnresolved
+	}
+	return body.Data.AccountName, nil
+}
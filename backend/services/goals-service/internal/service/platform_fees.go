@@ -0,0 +1,20 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/gofund/shared/models"
+)
+
+// resolveFeeMode validates the fee mode a goal create or update asks for. An empty mode
+// is FeeModeAbsorb, under which goals behave as they did before platform fees.
+func resolveFeeMode(mode models.FeeMode) (models.FeeMode, error) {
+	switch mode := models.FeeMode(strings.ToLower(strings.TrimSpace(string(mode)))); mode {
+	case "":
+		return models.FeeModeAbsorb, nil
+	case models.FeeModeAbsorb, models.FeeModeAddOn:
+		return mode, nil
+	default:
+		return "", ErrInvalidFeeMode
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/shared/fees"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+func TestResolveFeeMode(t *testing.T) {
+	tests := []struct {
+		mode    models.FeeMode
+		want    models.FeeMode
+		wantErr error
+	}{
+		{mode: "", want: models.FeeModeAbsorb},
+		{mode: "absorb", want: models.FeeModeAbsorb},
+		{mode: " ADD_ON ", want: models.FeeModeAddOn},
+		{mode: "split", wantErr: ErrInvalidFeeMode},
+	}
+	for _, tt := range tests {
+		got, err := resolveFeeMode(tt.mode)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("resolveFeeMode(%q) = %q, %v; want %q, %v", tt.mode, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// TestIntentFeeRounding creates intents for odd kobo amounts on add-on and absorbing goals
+// under a 1.5% + 100 kobo fee
+func TestIntentFeeRounding(t *testing.T) {
+	schedule := fees.Schedule{"NGN": {BasisPoints: 150, Fixed: 100}}
+	tests := []struct {
+		name     string
+		mode     models.FeeMode
+		amount   int64
+		fee      int64
+		net      int64
+		charge   int64
+		wantCode string
+	}{
+		{name: "add-on odd kobo", mode: models.FeeModeAddOn, amount: 4_999, fee: 175, net: 4_999, charge: 5_174},
+		{name: "absorb odd kobo", mode: models.FeeModeAbsorb, amount: 4_999, fee: 175, net: 4_824, charge: 4_999},
+		{name: "add-on half kobo", mode: models.FeeModeAddOn, amount: 100_100, fee: 1_602, net: 100_100, charge: 101_702},
+		{name: "absorb half kobo", mode: models.FeeModeAbsorb, amount: 100_100, fee: 1_602, net: 98_498, charge: 100_100},
+		{name: "add-on below the fee", mode: models.FeeModeAddOn, amount: 101, fee: 102, net: 101, charge: 203},
+		{name: "absorb below the fee", mode: models.FeeModeAbsorb, amount: 101, wantCode: "amount_below_fee"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepo(t)
+			s := NewContributionService(repo, nil, nil, schedule, 0, 0)
+			goal := createTestGoal(t, repo, uuid.New(), func(g *models.Goal) { g.FeeMode = tt.mode })
+
+			intent, err := s.CreateContribution(context.Background(), uuid.New(), dto.CreateContributionRequest{GoalID: goal.ID, Amount: tt.amount})
+			if tt.wantCode != "" {
+				if code := errorCode(err); code != tt.wantCode {
+					t.Fatalf("err = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateContribution: %v", err)
+			}
+
+			stored, err := repo.Primary().Contribution.GetContributionByID(context.Background(), intent.ID)
+			if err != nil {
+				t.Fatalf("GetContributionByID: %v", err)
+			}
+			if stored.FeeMode != tt.mode || stored.FeeAmount != tt.fee || stored.NetAmount != tt.net {
+				t.Errorf("intent = %s fee %d net %d, want %s fee %d net %d", stored.FeeMode, stored.FeeAmount, stored.NetAmount, tt.mode, tt.fee, tt.net)
+			}
+			if stored.ChargeAmount() != tt.charge || stored.GoalAmount() != tt.net {
+				t.Errorf("charge %d reaching the goal %d, want %d and %d", stored.ChargeAmount(), stored.GoalAmount(), tt.charge, tt.net)
+			}
+		})
+	}
+}
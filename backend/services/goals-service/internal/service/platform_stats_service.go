@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/shared/metrics"
+)
+
+// minorUnitsPerMajor is how many of a currency's smallest unit make one major unit; every
+// supported currency has two decimals
+const minorUnitsPerMajor = 100
+
+// PlatformStatsService computes the platform-wide totals for the homepage. Every homepage
+// load asks for them, so they are computed at most once per cacheTTL.
+type PlatformStatsService struct {
+	repo     *repository.Repository
+	cacheTTL time.Duration
+
+	mu        sync.RWMutex
+	stats     *dto.PlatformStats
+	expiresAt time.Time
+
+	// refreshMu lets a single caller recompute expired stats while the others wait for it
+	refreshMu sync.Mutex
+}
+
+// NewPlatformStatsService creates a new platform stats service
+func NewPlatformStatsService(repo *repository.Repository, cacheTTL time.Duration) *PlatformStatsService {
+	return &PlatformStatsService{repo: repo, cacheTTL: cacheTTL}
+}
+
+// GetPlatformStats returns the platform-wide totals, at most cacheTTL old
+func (s *PlatformStatsService) GetPlatformStats(ctx context.Context) (*dto.PlatformStats, error) {
+	if stats := s.cached(); stats != nil {
+		return stats, nil
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	// Another caller may have refreshed the stats while this one waited
+	if stats := s.cached(); stats != nil {
+		return stats, nil
+	}
+
+	totals, err := s.repo.Goal.GetPlatformStats(ctx)
+	if err != nil {
+		return nil, errors.New("failed to compute platform stats")
+	}
+
+	now := time.Now()
+	stats := &dto.PlatformStats{
+		Raised:       make([]dto.PlatformRaised, 0, len(totals.Raised)),
+		GoalsFunded:  totals.GoalsFunded,
+		ActiveGoals:  totals.ActiveGoals,
+		Contributors: totals.Contributors,
+		ComputedAt:   now,
+	}
+	raisedMajor := make(map[string]float64, len(totals.Raised))
+	for currency, amount := range totals.Raised {
+		major := float64(amount) / minorUnitsPerMajor
+		stats.Raised = append(stats.Raised, dto.PlatformRaised{Currency: currency, Amount: amount, AmountMajor: major})
+		raisedMajor[currency] = major
+	}
+	sort.Slice(stats.Raised, func(i, j int) bool { return stats.Raised[i].Currency < stats.Raised[j].Currency })
+
+	metrics.TrackPlatformStats(raisedMajor, stats.GoalsFunded, stats.ActiveGoals, stats.Contributors)
+
+	s.mu.Lock()
+	s.stats = stats
+	s.expiresAt = now.Add(s.cacheTTL)
+	s.mu.Unlock()
+
+	return stats, nil
+}
+
+// cached returns the cached stats, or nil once they have expired
+func (s *PlatformStatsService) cached() *dto.PlatformStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stats == nil || !time.Now().Before(s.expiresAt) {
+		return nil
+	}
+	return s.stats
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+var ErrProofRejected = apperrors.Conflict("proof_rejected", "contributors rejected the latest proof; a new proof must be verified before withdrawing")
+
+// maxRejectionComments is how many "not satisfied" comments a ProofRejected event carries
+const maxRejectionComments = 10
+
+// refundPath is the refund initiation endpoint ProofRejected notifications link to
+const refundPath = "/api/v1/goals/refunds"
+
+// proofEvaluation returns the rule that settles a pending proof on the goal from its vote
+// tally. Verification is checked first: by the share of contributed money behind
+// satisfied votes for weighted-voting goals, and by the head count of satisfied votes
+// otherwise. A proof is rejected when unsatisfied votes clear the same bar. Anything
+// short of either leaves it pending.
+func proofEvaluation(goal *models.Goal) func(tally repository.VoteTally) models.ProofStatus {
+	return func(tally repository.VoteTally) models.ProofStatus {
+		unsatisfiedVotes := tally.TotalVotes - tally.SatisfiedVotes
+		unsatisfiedAmount := tally.VotedAmount - tally.SatisfiedAmount
+
+		if goal.WeightedVoting {
+			switch {
+			case tally.TotalContributions <= 0:
+				return models.ProofStatusPending
+			case calculatePercent(tally.SatisfiedAmount, tally.TotalContributions) >= weightedVerificationPercent:
+				return models.ProofStatusVerified
+			case calculatePercent(unsatisfiedAmount, tally.TotalContributions) >= weightedVerificationPercent:
+				return models.ProofStatusRejected
+			}
+			return models.ProofStatusPending
+		}
+
+		threshold := proofVerificationThreshold(tally.Contributors)
+		switch {
+		case tally.SatisfiedVotes >= threshold:
+			return models.ProofStatusVerified
+		case unsatisfiedVotes >= threshold:
+			return models.ProofStatusRejected
+		}
+		return models.ProofStatusPending
+	}
+}
+
+// checkProofsForWithdrawal refuses a withdrawal against the milestone (or the goal itself,
+// for a nil milestoneID) while its latest rejected proof has not been followed by a
+// verified one, and, on goals that require proof, until a proof has been verified
+func checkProofsForWithdrawal(ctx context.Context, repo *repository.Repository, goal *models.Goal, milestoneID *uuid.UUID) error {
+	rejected, err := repo.Proof.HasUnresolvedRejection(ctx, goal.ID, milestoneID)
+	if err != nil {
+		return err
+	}
+	if rejected {
+		return ErrProofRejected
+	}
+
+	// Goals that opted in only release funds contributors have seen evidence for
+	if goal.RequireProofForWithdrawal {
+		verified, err := repo.Proof.HasVerifiedProof(ctx, goal.ID, milestoneID)
+		if err != nil {
+			return err
+		}
+		if !verified {
+			return ErrProofRequired
+		}
+	}
+	return nil
+}
+
+// proofSettledEvents returns the onSettled hook of a vote change, which records the event
+// announcing a proof the change verified or rejected in the transaction that settled it. A
+// rejection also flags the goal for moderation.
+func proofSettledEvents(ctx context.Context, goal *models.Goal, proof *models.Proof) func(tx *repository.Repository, status models.ProofStatus) error {
+	return func(tx *repository.Repository, status models.ProofStatus) error {
+		switch status {
+		case models.ProofStatusVerified:
+			return recordProofVerified(ctx, tx, proof)
+		case models.ProofStatusRejected:
+			if err := flagGoal(ctx, tx, goal.ID, models.GoalReportCategoryProofRejected, "contributors rejected proof "+proof.ID.String()); err != nil {
+				return err
+			}
+			return recordProofRejected(ctx, tx, goal, proof)
+		}
+		return nil
+	}
+}
+
+// trackProofSettled counts a proof that a vote change verified or rejected
+func trackProofSettled(status models.ProofStatus) {
+	switch status {
+	case models.ProofStatusVerified:
+		metrics.IncrementCounter("goals.proof.verified")
+	case models.ProofStatusRejected:
+		metrics.IncrementCounter("goals.proof.rejected")
+	}
+}
+
+// recordProofRejected records the event announcing that votes rejected a proof, with the
+// comments left alongside "not satisfied" votes for the owner and the refund path for
+// contributors
+func recordProofRejected(ctx context.Context, tx *repository.Repository, goal *models.Goal, proof *models.Proof) error {
+	votes, err := tx.Vote.GetVotesByProofID(ctx, proof.ID)
+	if err != nil {
+		return err
+	}
+	var satisfied, unsatisfied int64
+	var comments []string
+	for _, vote := range votes {
+		if vote.IsSatisfied {
+			satisfied++
+			continue
+		}
+		unsatisfied++
+		if comment := strings.TrimSpace(vote.Comment); comment != "" && len(comments) < maxRejectionComments {
+			comments = append(comments, comment)
+		}
+	}
+
+	ids, err := tx.Goal.GetContributorIDs(ctx, goal.ID)
+	if err != nil {
+		return err
+	}
+	contributorIDs := make([]string, len(ids))
+	for i, id := range ids {
+		contributorIDs[i] = id.String()
+	}
+
+	var milestoneID string
+	if proof.MilestoneID != nil {
+		milestoneID = proof.MilestoneID.String()
+	}
+
+	return recordEvent(ctx, tx, "ProofRejected", events.ProofRejected{
+		ID:               uuid.New().String(),
+		GoalID:           goal.ID.String(),
+		MilestoneID:      milestoneID,
+		ProofID:          proof.ID.String(),
+		OwnerID:          goal.OwnerID.String(),
+		Title:            goal.Title,
+		ProofTitle:       proof.Title,
+		SatisfiedVotes:   satisfied,
+		UnsatisfiedVotes: unsatisfied,
+		Comments:         comments,
+		ContributorIDs:   contributorIDs,
+		RefundPath:       refundPath,
+		CreatedAt:        time.Now().Unix(),
+	})
+}
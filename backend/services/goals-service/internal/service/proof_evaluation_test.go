@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+func TestProofEvaluation(t *testing.T) {
+	tests := []struct {
+		name     string
+		weighted bool
+		tally    repository.VoteTally
+		want     models.ProofStatus
+	}{
+		{
+			name:  "satisfied votes reach the threshold",
+			tally: repository.VoteTally{Contributors: 10, TotalVotes: 3, SatisfiedVotes: 3},
+			want:  models.ProofStatusVerified,
+		},
+		{
+			name:  "unsatisfied votes reach the threshold",
+			tally: repository.VoteTally{Contributors: 10, TotalVotes: 3, SatisfiedVotes: 0},
+			want:  models.ProofStatusRejected,
+		},
+		{
+			name:  "neither side reaches the threshold",
+			tally: repository.VoteTally{Contributors: 10, TotalVotes: 4, SatisfiedVotes: 2},
+			want:  models.ProofStatusPending,
+		},
+		{
+			name:  "verification wins when both sides reach it",
+			tally: repository.VoteTally{Contributors: 10, TotalVotes: 6, SatisfiedVotes: 3},
+			want:  models.ProofStatusVerified,
+		},
+		{
+			name:  "the threshold grows to 5% of contributors",
+			tally: repository.VoteTally{Contributors: 100, TotalVotes: 4, SatisfiedVotes: 0},
+			want:  models.ProofStatusPending,
+		},
+		{
+			name:  "5% of contributors unsatisfied",
+			tally: repository.VoteTally{Contributors: 100, TotalVotes: 5, SatisfiedVotes: 0},
+			want:  models.ProofStatusRejected,
+		},
+		{
+			name:     "weighted: half the money satisfied",
+			weighted: true,
+			tally:    repository.VoteTally{TotalVotes: 1, SatisfiedVotes: 1, VotedAmount: 500_000, SatisfiedAmount: 500_000, TotalContributions: 1_000_000},
+			want:     models.ProofStatusVerified,
+		},
+		{
+			name:     "weighted: half the money unsatisfied",
+			weighted: true,
+			tally:    repository.VoteTally{TotalVotes: 1, VotedAmount: 500_000, TotalContributions: 1_000_000},
+			want:     models.ProofStatusRejected,
+		},
+		{
+			name:     "weighted: many small unsatisfied votes",
+			weighted: true,
+			tally:    repository.VoteTally{TotalVotes: 10, VotedAmount: 100_000, TotalContributions: 1_000_000},
+			want:     models.ProofStatusPending,
+		},
+		{
+			name:     "weighted: nothing contributed",
+			weighted: true,
+			tally:    repository.VoteTally{TotalVotes: 3},
+			want:     models.ProofStatusPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluate := proofEvaluation(&models.Goal{WeightedVoting: tt.weighted})
+			if got := evaluate(tt.tally); got != tt.want {
+				t.Errorf("evaluation of %+v = %s, want %s", tt.tally, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRejectedProofBlocksWithdrawals has contributors vote a proof down; the proof is
+// rejected with a ProofRejected event for the owner and contributors, the goal is flagged
+// for moderation, and withdrawals are refused until a later proof is verified
+func TestRejectedProofBlocksWithdrawals(t *testing.T) {
+	repo := newTestRepo(t)
+	votes := NewVoteService(repo)
+	withdrawals := NewWithdrawalService(repo, NewBalanceCheckService(repo, NewLedgerClient(""), false, 0), nil, newTestUsersClient(t), 0)
+	ctx := context.Background()
+
+	goal := createTestGoal(t, repo, uuid.New())
+	contributors := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, contributor := range contributors {
+		createTestContribution(t, repo, goal, contributor, 100_000)
+	}
+	setGoalStatus(t, repo, goal, models.GoalStatusClosed)
+
+	submitProof := func(title string) *models.Proof {
+		t.Helper()
+		proof := &models.Proof{GoalID: goal.ID, SubmittedBy: goal.OwnerID, Title: title, SubmittedAt: time.Now()}
+		if err := repo.Proof.CreateProof(ctx, proof); err != nil {
+			t.Fatalf("CreateProof: %v", err)
+		}
+		return proof
+	}
+	voteOn := func(proof *models.Proof, satisfied bool, comment string) {
+		t.Helper()
+		for _, contributor := range contributors {
+			if _, err := votes.CreateVote(ctx, contributor, dto.CreateVoteRequest{ProofID: proof.ID, IsSatisfied: satisfied, Comment: comment}); err != nil {
+				t.Fatalf("CreateVote: %v", err)
+			}
+		}
+	}
+	withdraw := func() error {
+		_, err := withdrawals.CreateWithdrawal(ctx, goal.OwnerID, dto.CreateWithdrawalRequest{GoalID: goal.ID, Amount: 10_000})
+		return err
+	}
+
+	rejected := submitProof("Receipts")
+	voteOn(rejected, false, "the receipts are for another project")
+
+	stored, err := repo.Primary().Proof.GetProofByID(ctx, rejected.ID)
+	if err != nil {
+		t.Fatalf("GetProofByID: %v", err)
+	}
+	if stored.Status != models.ProofStatusRejected {
+		t.Fatalf("proof status = %s, want %s", stored.Status, models.ProofStatusRejected)
+	}
+
+	recorded, err := repo.Outbox.GetDueEvents(ctx, time.Now().Add(time.Hour), 100)
+	if err != nil {
+		t.Fatalf("GetDueEvents: %v", err)
+	}
+	var event *events.ProofRejected
+	for _, e := range recorded {
+		if e.EventType == "ProofRejected" {
+			event = &events.ProofRejected{}
+			if err := json.Unmarshal([]byte(e.Payload), event); err != nil {
+				t.Fatalf("failed to decode ProofRejected: %v", err)
+			}
+		}
+	}
+	if event == nil {
+		t.Fatal("no ProofRejected event was recorded")
+	}
+	if event.OwnerID != goal.OwnerID.String() || event.UnsatisfiedVotes != 3 || event.RefundPath != refundPath {
+		t.Errorf("event = %+v, want the owner, 3 unsatisfied votes and the refund path", event)
+	}
+	if len(event.Comments) != 3 || len(event.ContributorIDs) != len(contributors) {
+		t.Errorf("event carries %d comments and %d contributors, want 3 and %d", len(event.Comments), len(event.ContributorIDs), len(contributors))
+	}
+
+	// The rejection flags the goal for moderation
+	queue, _, err := repo.Report.GetModerationQueue(ctx, repository.ModerationQueueFilter{Category: models.GoalReportCategoryProofRejected}, time.Now(), 10, 0)
+	if err != nil {
+		t.Fatalf("GetModerationQueue: %v", err)
+	}
+	if len(queue) != 1 || queue[0].GoalID != goal.ID || queue[0].AutoFlags != 1 {
+		t.Errorf("moderation queue = %+v, want the goal with one automatic flag", queue)
+	}
+
+	if err := withdraw(); errorCode(err) != "proof_rejected" {
+		t.Fatalf("withdrawal after the rejection: err = %v, want proof_rejected", err)
+	}
+
+	verified := submitProof("Contractor invoice")
+	voteOn(verified, true, "")
+	if err := withdraw(); err != nil {
+		t.Errorf("withdrawal after a later proof was verified: %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxProofMediaPerProof is how many files a proof may cite
+const maxProofMediaPerProof = 10
+
+// proofMediaType is a kind of file accepted as proof media
+type proofMediaType struct {
+	ext     string
+	maxSize int64
+}
+
+// proofMediaTypes are the accepted proof media, by the content type sniffed from the file
+var proofMediaTypes = map[string]proofMediaType{
+	"image/jpeg":      {ext: ".jpg", maxSize: 10 << 20},
+	"image/png":       {ext: ".png", maxSize: 10 << 20},
+	"application/pdf": {ext: ".pdf", maxSize: 20 << 20},
+	"video/mp4":       {ext: ".mp4", maxSize: 100 << 20},
+}
+
+// MaxProofMediaSize is the size of the largest proof media file accepted
+const MaxProofMediaSize = 100 << 20
+
+var (
+	ErrUnsupportedMediaType = apperrors.Validation("unsupported_media_type", "proof media must be a JPEG or PNG image, a PDF or an MP4 video")
+	ErrMediaTooLarge        = apperrors.Validation("media_too_large", "proof media file is too large")
+	ErrMediaUnavailable     = apperrors.NewDomainError("media_unavailable", "proof media uploads are not configured", nil)
+	ErrInvalidProofMedia    = apperrors.Validation("invalid_proof_media", "proof media URLs must be files you uploaded for proofs")
+	ErrTooManyProofMedia    = apperrors.Validation("too_many_proof_media", fmt.Sprintf("a proof may cite at most %d files", maxProofMediaPerProof))
+	ErrProofHasVotes        = apperrors.Conflict("proof_has_votes", "a proof cannot be deleted once contributors have voted on it")
+)
+
+// InvalidMediaURL is a proof media URL that was refused, and why
+type InvalidMediaURL struct {
+	URL    string `json:"url"`
+	Reason string `json:"reason"`
+}
+
+// ProofMediaError is returned when some of a proof's media URLs are refused
+type ProofMediaError struct {
+	Invalid []InvalidMediaURL
+}
+
+func (e *ProofMediaError) Error() string {
+	return fmt.Sprintf("%d proof media URLs refused", len(e.Invalid))
+}
+
+// Unwrap classifies the error as ErrInvalidProofMedia
+func (e *ProofMediaError) Unwrap() error {
+	return ErrInvalidProofMedia
+}
+
+// UploadProofMedia stores a file for a user to cite in a proof. The content type is
+// sniffed from the file itself, whatever the client claims; size is the file's length.
+func (s *ProofService) UploadProofMedia(ctx context.Context, userID uuid.UUID, file io.Reader, size int64) (*models.ProofMedia, error) {
+	if s.media == nil {
+		return nil, ErrMediaUnavailable
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	mediaType, ok := proofMediaTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedMediaType
+	}
+	if size > mediaType.maxSize {
+		return nil, ErrMediaTooLarge.WithDetail(fmt.Sprintf("%s files may be at most %d MB", contentType, mediaType.maxSize>>20))
+	}
+
+	key := uuid.New().String() + mediaType.ext
+	url, err := s.media.Put(ctx, key, contentType, io.MultiReader(bytes.NewReader(head), file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to store proof media: %w", err)
+	}
+
+	media := &models.ProofMedia{
+		UploadedBy:  userID,
+		StorageKey:  key,
+		URL:         url,
+		ContentType: contentType,
+		Size:        size,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.repo.ProofMedia.CreateProofMedia(ctx, media); err != nil {
+		s.deleteStoredMedia(ctx, key)
+		return nil, err
+	}
+
+	metrics.IncrementCounter("goals.proof_media.uploaded", "content_type:"+contentType)
+	return media, nil
+}
+
+// checkProofMedia looks up the files behind a proof's media URLs, refusing any the user
+// did not upload or another proof already cites
+func (s *ProofService) checkProofMedia(ctx context.Context, userID uuid.UUID, urls []string) ([]models.ProofMedia, error) {
+	if len(urls) > maxProofMediaPerProof {
+		return nil, ErrTooManyProofMedia
+	}
+
+	found, err := s.repo.ProofMedia.GetProofMediaByURLs(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+	byURL := make(map[string]models.ProofMedia, len(found))
+	for _, media := range found {
+		byURL[media.URL] = media
+	}
+
+	var media []models.ProofMedia
+	var invalid []InvalidMediaURL
+	seen := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		uploaded, ok := byURL[url]
+		switch {
+		case seen[url]:
+			invalid = append(invalid, InvalidMediaURL{URL: url, Reason: "listed more than once"})
+		case !ok:
+			invalid = append(invalid, InvalidMediaURL{URL: url, Reason: "not an uploaded proof media file"})
+		case uploaded.UploadedBy != userID:
+			invalid = append(invalid, InvalidMediaURL{URL: url, Reason: "uploaded by another user"})
+		case uploaded.ProofID != nil:
+			invalid = append(invalid, InvalidMediaURL{URL: url, Reason: "already cited by another proof"})
+		default:
+			media = append(media, uploaded)
+		}
+		seen[url] = true
+	}
+	if len(invalid) > 0 {
+		return nil, &ProofMediaError{Invalid: invalid}
+	}
+	return media, nil
+}
+
+// attachProofMedia records that a new proof cites media. It fails if another proof
+// claimed any of the files since they were checked.
+func attachProofMedia(ctx context.Context, tx *repository.Repository, media []models.ProofMedia, userID, proofID uuid.UUID) error {
+	ids := make([]uuid.UUID, len(media))
+	for i := range media {
+		ids[i] = media[i].ID
+	}
+	attached, err := tx.ProofMedia.AttachToProof(ctx, ids, userID, proofID)
+	if err != nil {
+		return err
+	}
+	if attached != int64(len(ids)) {
+		return ErrInvalidProofMedia
+	}
+	return nil
+}
+
+// DeleteProof deletes a proof no one has voted on, and the media files it cites. Only the
+// goal's owner may delete its proofs.
+func (s *ProofService) DeleteProof(ctx context.Context, proofID, userID uuid.UUID) error {
+	proof, err := s.repo.Proof.GetProofByID(ctx, proofID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProofNotFound
+		}
+		return err
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, proof.GoalID)
+	if err != nil {
+		return err
+	}
+	if goal.OwnerID != userID {
+		return ErrUnauthorized
+	}
+
+	var media []models.ProofMedia
+	err = s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		votes, err := tx.Vote.GetVotesByProofID(ctx, proof.ID)
+		if err != nil {
+			return err
+		}
+		if len(votes) > 0 || proof.Status == models.ProofStatusVerified {
+			return ErrProofHasVotes
+		}
+
+		if media, err = tx.ProofMedia.GetProofMediaByProofID(ctx, proof.ID); err != nil {
+			return err
+		}
+		if err := tx.ProofMedia.DeleteProofMediaByProofID(ctx, proof.ID); err != nil {
+			return err
+		}
+		return tx.Proof.DeleteProof(ctx, proof.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// The files go once the records are gone; one left behind is only wasted space
+	for _, m := range media {
+		s.deleteStoredMedia(ctx, m.StorageKey)
+	}
+
+	metrics.IncrementCounter("goals.proof.deleted")
+	return nil
+}
+
+// deleteStoredMedia removes a file from the blob store, logging rather than failing
+func (s *ProofService) deleteStoredMedia(ctx context.Context, key string) {
+	if s.media == nil {
+		return
+	}
+	if err := s.media.Delete(ctx, key); err != nil {
+		log.Printf("Warning: failed to delete proof media %s: %v", key, err)
+	}
+}
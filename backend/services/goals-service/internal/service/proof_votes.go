@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofund/goals-service/internal/dto"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/metrics"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrVoteNotFound = apperrors.NotFound("vote_not_found", "you have not voted on this proof")
+
+// RetractVote withdraws a user's vote on a proof and returns the updated vote stats. The
+// proof is evaluated again as votes change, but a settled proof stays settled: withdrawals
+// may already have been released, or refunds started, on the strength of it.
+func (s *VoteService) RetractVote(ctx context.Context, proofID, userID uuid.UUID) (*dto.VoteStats, error) {
+	proof, err := s.repo.Proof.GetProofByID(ctx, proofID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProofNotFound
+		}
+		return nil, err
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, proof.GoalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+
+	vote, err := s.repo.Vote.GetVoteByProofAndVoter(ctx, proofID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVoteNotFound
+		}
+		return nil, err
+	}
+
+	settled, err := s.repo.Vote.DeleteVoteAndEvaluate(ctx, vote, proofEvaluation(goal), proofSettledEvents(ctx, goal, proof))
+	if err != nil {
+		return nil, err
+	}
+	trackProofSettled(settled)
+
+	metrics.IncrementCounter("goals.vote.retracted")
+	return s.voteStats(ctx, s.repo.Primary(), proofID)
+}
+
+// ListProofVotes returns a proof's votes with their comments, newest first, to anyone who
+// may view its goal. Voters are identified as ProofVote describes.
+func (s *VoteService) ListProofVotes(ctx context.Context, proofID, viewerID uuid.UUID) ([]dto.ProofVote, error) {
+	proof, err := s.repo.Proof.GetProofByID(ctx, proofID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProofNotFound
+		}
+		return nil, err
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, proof.GoalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if err := checkGoalVisible(ctx, s.repo, goal, viewerID); err != nil {
+		return nil, err
+	}
+
+	votes, err := s.repo.Vote.GetVotesByProofID(ctx, proofID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]dto.ProofVote, 0, len(votes))
+	for _, vote := range votes {
+		vote.MaskVoter(viewerID, goal.OwnerID)
+		item := dto.ProofVote{
+			ID:          vote.ID,
+			IsSatisfied: vote.IsSatisfied,
+			Comment:     vote.Comment,
+			VotedAt:     vote.VotedAt,
+		}
+		if vote.VoterID != uuid.Nil {
+			voterID := vote.VoterID
+			item.VoterID = &voterID
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrContributionNotConfirmed = apperrors.Conflict("contribution_not_confirmed", "receipts are only available for confirmed contributions")
+	ErrPaymentUnavailable       = apperrors.NewDomainError("payment_reference_unavailable", "payment details are temporarily unavailable", nil)
+)
+
+// ReceiptService assembles receipts for confirmed contributions. The payment reference
+// lives in payments-service and the contributor's name in users-service.
+type ReceiptService struct {
+	repo           *repository.Repository
+	usersClient    *UsersClient
+	paymentsClient *PaymentsClient
+}
+
+// NewReceiptService creates a new receipt service
+func NewReceiptService(repo *repository.Repository, usersClient *UsersClient, paymentsClient *PaymentsClient) *ReceiptService {
+	return &ReceiptService{repo: repo, usersClient: usersClient, paymentsClient: paymentsClient}
+}
+
+// GetReceipt returns the receipt for a contribution. Only the contributor may fetch it.
+func (s *ReceiptService) GetReceipt(ctx context.Context, contributionID, userID uuid.UUID) (*dto.ContributionReceipt, error) {
+	contribution, err := s.repo.Contribution.GetContributionByID(ctx, contributionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContributionNotFound
+		}
+		return nil, err
+	}
+
+	if contribution.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	// A refunded contribution was still paid for, so it keeps its receipt
+	paid := contribution.Status == models.ContributionStatusConfirmed || contribution.Status == models.ContributionStatusRefunded
+	if !paid || contribution.PaymentID == nil {
+		return nil, ErrContributionNotConfirmed
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, contribution.GoalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+
+	payment, err := s.paymentsClient.GetPayment(*contribution.PaymentID)
+	if err != nil {
+		log.Printf("Failed to fetch payment %s for receipt: %v", *contribution.PaymentID, err)
+		return nil, ErrPaymentUnavailable
+	}
+
+	// Confirmation follows payment closely, so it stands in if payments-service has no paid_at
+	paidAt := contribution.UpdatedAt
+	if payment.PaidAt != nil {
+		paidAt = *payment.PaidAt
+	}
+
+	return &dto.ContributionReceipt{
+		ContributionID:   contribution.ID,
+		GoalTitle:        goal.Title,
+		Amount:           contribution.Amount,
+		Currency:         contribution.Currency,
+		PaymentReference: payment.Reference,
+		PaidAt:           paidAt,
+		ContributorName:  s.contributorName(userID),
+	}, nil
+}
+
+// contributorName prefers the contributor's full name, falling back to their display name
+func (s *ReceiptService) contributorName(userID uuid.UUID) string {
+	if contact, ok := s.usersClient.Contacts([]uuid.UUID{userID})[userID]; ok && contact.Name != "" {
+		return contact.Name
+	}
+	return s.usersClient.DisplayNames([]uuid.UUID{userID})[userID]
+}
@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/messaging"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	recurringChargeBatchSize = 100
+	// recurringChargeLease keeps other schedulers off a recurring contribution while it is
+	// charged; if the charge never finishes, it is retried with the same idempotency key
+	// once the lease runs out
+	recurringChargeLease = 10 * time.Minute
+	// recurringRetryBackoff is the wait before the first retry of a failed charge; each
+	// further retry waits four times longer, up to a day
+	recurringRetryBackoff    = time.Hour
+	recurringRetryBackoffMax = 24 * time.Hour
+)
+
+var (
+	ErrRecurringContributionNotFound  = apperrors.NotFound("recurring_contribution_not_found", "recurring contribution not found")
+	ErrRecurringContributionCancelled = apperrors.Conflict("recurring_contribution_cancelled", "recurring contribution has been cancelled")
+	ErrNoReusableCard                 = apperrors.Validation("no_reusable_card", "the contribution was not paid with a card that can be charged again")
+	ErrInvalidRecurringInterval       = apperrors.Validation("invalid_recurring_interval", "interval must be WEEKLY or MONTHLY")
+	ErrInvalidRecurringStatus         = apperrors.Validation("invalid_recurring_status", "status must be ACTIVE or PAUSED")
+)
+
+// RecurringContributionService handles recurring contributions (standing orders) and
+// charges them when they fall due
+type RecurringContributionService struct {
+	recurring     *repository.RecurringContributionRepository
+	repo          *repository.Repository
+	contributions *ContributionService
+	payments      *PaymentsClient
+	publisher     messaging.Publisher
+	maxAttempts   int
+}
+
+// NewRecurringContributionService creates a new recurring contribution service. A charge
+// that fails maxAttempts times in a row pauses the recurring contribution.
+func NewRecurringContributionService(recurring *repository.RecurringContributionRepository, repo *repository.Repository, contributions *ContributionService, payments *PaymentsClient, publisher messaging.Publisher, maxAttempts int) *RecurringContributionService {
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	return &RecurringContributionService{
+		recurring:     recurring,
+		repo:          repo,
+		contributions: contributions,
+		payments:      payments,
+		publisher:     publisher,
+		maxAttempts:   maxAttempts,
+	}
+}
+
+// CreateRecurringContribution sets up a recurring contribution from one of the user's
+// confirmed contributions, charging the card it was paid with every interval. The first
+// charge is one interval from now, since the contribution itself covers this one.
+func (s *RecurringContributionService) CreateRecurringContribution(ctx context.Context, userID uuid.UUID, req dto.CreateRecurringContributionRequest) (*models.RecurringContribution, error) {
+	if !validRecurringInterval(req.Interval) {
+		return nil, ErrInvalidRecurringInterval
+	}
+
+	contribution, err := s.repo.Contribution.GetContributionByID(ctx, req.ContributionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContributionNotFound
+		}
+		return nil, err
+	}
+	if contribution.UserID != userID {
+		return nil, ErrContributionNotFound
+	}
+	if contribution.Status != models.ContributionStatusConfirmed || contribution.AuthorizationCode == "" {
+		return nil, ErrNoReusableCard
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = contribution.Amount
+	}
+
+	goal, err := s.contributions.checkContribution(ctx, userID, recurringIntent(contribution.GoalID, amount, contribution.IsAnonymous))
+	if err != nil {
+		return nil, err
+	}
+
+	rc := &models.RecurringContribution{
+		UserID:             userID,
+		GoalID:             goal.ID,
+		Amount:             amount,
+		Currency:           goal.Currency,
+		Interval:           req.Interval,
+		IsAnonymous:        contribution.IsAnonymous,
+		Status:             models.RecurringContributionActive,
+		NextChargeDate:     calculateNextDueDate(time.Now(), req.Interval, 1),
+		AuthorizationCode:  contribution.AuthorizationCode,
+		AuthorizationEmail: contribution.AuthorizationEmail,
+	}
+	if err := s.recurring.Create(ctx, rc); err != nil {
+		return nil, err
+	}
+
+	metrics.IncrementCounter("goals.recurring.created", "interval:"+string(rc.Interval))
+	return rc, nil
+}
+
+// ListRecurringContributions lists the user's recurring contributions
+func (s *RecurringContributionService) ListRecurringContributions(ctx context.Context, userID uuid.UUID) ([]models.RecurringContribution, error) {
+	return s.recurring.ListByUser(ctx, userID)
+}
+
+// GetRecurringContribution retrieves one of the user's recurring contributions
+func (s *RecurringContributionService) GetRecurringContribution(ctx context.Context, id, userID uuid.UUID) (*models.RecurringContribution, error) {
+	rc, err := s.recurring.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecurringContributionNotFound
+		}
+		return nil, err
+	}
+	if rc.UserID != userID {
+		return nil, ErrRecurringContributionNotFound
+	}
+	return rc, nil
+}
+
+// UpdateRecurringContribution changes the amount or interval of one of the user's recurring
+// contributions, or pauses or resumes it. Resuming starts over with the next charge date
+// still ahead, so charges missed while paused are not made up.
+func (s *RecurringContributionService) UpdateRecurringContribution(ctx context.Context, id, userID uuid.UUID, req dto.UpdateRecurringContributionRequest) (*models.RecurringContribution, error) {
+	rc, err := s.GetRecurringContribution(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rc.Status == models.RecurringContributionCancelled {
+		return nil, ErrRecurringContributionCancelled
+	}
+
+	var columns []string
+	if req.Interval != nil {
+		if !validRecurringInterval(*req.Interval) {
+			return nil, ErrInvalidRecurringInterval
+		}
+		rc.Interval = *req.Interval
+		columns = append(columns, "interval")
+	}
+	if req.Amount != nil {
+		rc.Amount = *req.Amount
+		columns = append(columns, "amount")
+	}
+	if req.Status != nil && *req.Status != rc.Status {
+		switch *req.Status {
+		case models.RecurringContributionActive:
+			rc.NextChargeDate = nextChargeDateAfter(rc.NextChargeDate, rc.Interval, time.Now())
+			rc.FailedAttempts = 0
+			rc.RetryAt = nil
+			columns = append(columns, "next_charge_date", "failed_attempts", "retry_at")
+		case models.RecurringContributionPaused:
+		default:
+			return nil, ErrInvalidRecurringStatus
+		}
+		rc.Status = *req.Status
+		columns = append(columns, "status")
+	}
+	if len(columns) == 0 {
+		return rc, nil
+	}
+
+	// A new amount, or a resumed recurring contribution, must still suit the goal
+	if req.Amount != nil || (req.Status != nil && rc.Status == models.RecurringContributionActive) {
+		if _, err := s.contributions.checkContribution(ctx, rc.UserID, recurringIntent(rc.GoalID, rc.Amount, rc.IsAnonymous)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.recurring.Update(ctx, rc, columns...); err != nil {
+		return nil, err
+	}
+	return rc, nil
+}
+
+// CancelRecurringContribution stops one of the user's recurring contributions for good
+func (s *RecurringContributionService) CancelRecurringContribution(ctx context.Context, id, userID uuid.UUID) error {
+	rc, err := s.GetRecurringContribution(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if rc.Status == models.RecurringContributionCancelled {
+		return nil
+	}
+
+	rc.Status = models.RecurringContributionCancelled
+	rc.RetryAt = nil
+	return s.recurring.Update(ctx, rc, "status", "retry_at")
+}
+
+// RunCharges charges due recurring contributions every interval until ctx is cancelled
+func (s *RecurringContributionService) RunCharges(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.chargeDue(ctx, time.Now())
+		}
+	}
+}
+
+// chargeDue charges every recurring contribution due at now that this instance claims
+func (s *RecurringContributionService) chargeDue(ctx context.Context, now time.Time) {
+	due, err := s.recurring.GetDue(ctx, now, recurringChargeBatchSize)
+	if err != nil {
+		log.Printf("Failed to load due recurring contributions: %v", err)
+		return
+	}
+
+	for i := range due {
+		rc := &due[i]
+		claimed, err := s.recurring.Claim(ctx, rc.ID, now, now.Add(recurringChargeLease))
+		if err != nil {
+			log.Printf("Failed to claim recurring contribution %s: %v", rc.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.charge(ctx, rc, now)
+	}
+}
+
+// charge makes one charge attempt for a claimed recurring contribution and records the
+// outcome. Errors that leave the outcome unknown keep the claim, so the same attempt is
+// retried, with the same idempotency key, when the lease runs out.
+func (s *RecurringContributionService) charge(ctx context.Context, rc *models.RecurringContribution, now time.Time) {
+	contribution, err := s.contributions.createIntent(ctx, rc.UserID, recurringIntent(rc.GoalID, rc.Amount, rc.IsAnonymous), &rc.ID)
+	if err != nil {
+		if apperrors.KindOf(err) == apperrors.KindInternal {
+			log.Printf("Failed to create contribution for recurring contribution %s: %v", rc.ID, err)
+			return
+		}
+		// The goal no longer accepts this contribution
+		s.stop(ctx, rc, err)
+		return
+	}
+
+	result, err := s.payments.ChargeAuthorization(ctx, ChargeRequest{
+		UserID:            rc.UserID,
+		GoalID:            rc.GoalID,
+		ContributionID:    &contribution.ID,
+		Amount:            contribution.ChargeAmount(), // with the fee, on add-on goals
+		Currency:          rc.Currency,
+		Email:             rc.AuthorizationEmail,
+		AuthorizationCode: rc.AuthorizationCode,
+		IdempotencyKey:    fmt.Sprintf("recurring:%s:%d:%d", rc.ID, rc.NextChargeDate.Unix(), rc.FailedAttempts),
+	})
+	if err != nil {
+		metrics.IncrementCounter("goals.recurring.charge", "outcome:error")
+		log.Printf("Failed to charge recurring contribution %s: %v", rc.ID, err)
+		return
+	}
+
+	if result.Status == "FAILED" {
+		metrics.IncrementCounter("goals.recurring.charge", "outcome:failed")
+		reason := result.GatewayResponse
+		if reason == "" {
+			reason = "the charge was declined"
+		}
+		s.recordFailure(ctx, rc, now, reason)
+		return
+	}
+
+	// VERIFIED, or PENDING until Paystack's webhook confirms the contribution
+	metrics.IncrementCounter("goals.recurring.charge", "outcome:"+result.Status)
+	rc.NextChargeDate = nextChargeDateAfter(rc.NextChargeDate, rc.Interval, now)
+	rc.FailedAttempts = 0
+	rc.RetryAt = nil
+	rc.LastFailureReason = ""
+	rc.LastChargedAt = &now
+	if err := s.recurring.SaveChargeOutcome(ctx, rc); err != nil {
+		log.Printf("Failed to record charge for recurring contribution %s: %v", rc.ID, err)
+	}
+}
+
+// recordFailure schedules a retry of a declined charge, or pauses the recurring
+// contribution once its attempts for this cycle are used up
+func (s *RecurringContributionService) recordFailure(ctx context.Context, rc *models.RecurringContribution, now time.Time, reason string) {
+	rc.FailedAttempts++
+	rc.LastFailureReason = reason
+
+	if rc.FailedAttempts < s.maxAttempts {
+		retryAt := now.Add(retryBackoff(rc.FailedAttempts))
+		rc.RetryAt = &retryAt
+		if err := s.recurring.SaveChargeOutcome(ctx, rc); err != nil {
+			log.Printf("Failed to schedule retry for recurring contribution %s: %v", rc.ID, err)
+		}
+		return
+	}
+
+	// Skip this cycle; resuming charges again from the next one
+	rc.Status = models.RecurringContributionPaused
+	rc.NextChargeDate = nextChargeDateAfter(rc.NextChargeDate, rc.Interval, now)
+	rc.FailedAttempts = 0
+	rc.RetryAt = nil
+	if err := s.recurring.SaveChargeOutcome(ctx, rc); err != nil {
+		log.Printf("Failed to pause recurring contribution %s: %v", rc.ID, err)
+		return
+	}
+	metrics.IncrementCounter("goals.recurring.stopped", "status:paused")
+	s.publishRecurringContributionFailed(ctx, rc)
+}
+
+// stop ends a recurring contribution whose goal refuses it: paused while the goal is
+// suspended, which may be lifted, and cancelled otherwise
+func (s *RecurringContributionService) stop(ctx context.Context, rc *models.RecurringContribution, cause error) {
+	rc.Status = models.RecurringContributionCancelled
+	if errors.Is(cause, ErrGoalSuspended) {
+		rc.Status = models.RecurringContributionPaused
+	}
+	rc.RetryAt = nil
+	rc.LastFailureReason = apperrors.Message(cause)
+
+	if err := s.recurring.SaveChargeOutcome(ctx, rc); err != nil {
+		log.Printf("Failed to stop recurring contribution %s: %v", rc.ID, err)
+		return
+	}
+	metrics.IncrementCounter("goals.recurring.stopped", "status:"+string(rc.Status))
+	s.publishRecurringContributionFailed(ctx, rc)
+}
+
+// publishRecurringContributionFailed lets notifications tell the contributor their
+// recurring contribution stopped charging
+func (s *RecurringContributionService) publishRecurringContributionFailed(ctx context.Context, rc *models.RecurringContribution) {
+	if s.publisher == nil {
+		return
+	}
+
+	event := events.RecurringContributionFailed{
+		ID:                      uuid.New().String(),
+		RecurringContributionID: rc.ID.String(),
+		UserID:                  rc.UserID.String(),
+		GoalID:                  rc.GoalID.String(),
+		Amount:                  rc.Amount,
+		Status:                  string(rc.Status),
+		Reason:                  rc.LastFailureReason,
+		CreatedAt:               time.Now().Unix(),
+	}
+	if goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, rc.GoalID); err == nil {
+		event.GoalTitle = goal.Title
+	}
+
+	if err := s.publisher.PublishContext(ctx, "RecurringContributionFailed", event); err != nil {
+		log.Printf("Failed to publish RecurringContributionFailed event: %v", err)
+	}
+}
+
+// recurringIntent is the contribution a recurring contribution makes each cycle. Goals with
+// a fixed contribution amount accept multiples of it, as when paying dues ahead.
+func recurringIntent(goalID uuid.UUID, amount int64, isAnonymous bool) dto.CreateContributionRequest {
+	return dto.CreateContributionRequest{
+		GoalID:         goalID,
+		Amount:         amount,
+		IsAnonymous:    isAnonymous,
+		AllowMultiples: true,
+	}
+}
+
+func validRecurringInterval(interval models.RecurrenceType) bool {
+	return interval == models.RecurrenceWeekly || interval == models.RecurrenceMonthly
+}
+
+// nextChargeDateAfter advances a charge date by whole intervals until it is after now
+func nextChargeDateAfter(date time.Time, interval models.RecurrenceType, now time.Time) time.Time {
+	for !date.After(now) {
+		next := calculateNextDueDate(date, interval, 1)
+		if !next.After(date) {
+			return now
+		}
+		date = next
+	}
+	return date
+}
+
+// retryBackoff is the wait before retrying a charge that has failed attempts times
+func retryBackoff(attempts int) time.Duration {
+	backoff := recurringRetryBackoff
+	for i := 1; i < attempts && backoff < recurringRetryBackoffMax; i++ {
+		backoff *= 4
+	}
+	if backoff > recurringRetryBackoffMax {
+		return recurringRetryBackoffMax
+	}
+	return backoff
+}
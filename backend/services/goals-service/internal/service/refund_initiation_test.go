@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+func TestInitiateRefundPersistsThroughRepositories(t *testing.T) {
+	f := newWithdrawalRefundFixture(t)
+	ctx := context.Background()
+
+	refund, err := f.refunds.InitiateRefund(ctx, f.goal.OwnerID, &dto.InitiateRefundRequest{
+		GoalID:           f.goal.ID.String(),
+		RefundPercentage: 50,
+		Reason:           "goal cancelled",
+	})
+	if err != nil {
+		t.Fatalf("InitiateRefund: %v", err)
+	}
+
+	if refund.Status != models.RefundStatusProcessing || refund.TotalRefundAmount != 100_000 || refund.Currency != "NGN" {
+		t.Errorf("refund = %s, %d %s; want processing, 100000 NGN", refund.Status, refund.TotalRefundAmount, refund.Currency)
+	}
+	if len(refund.Disbursements) != 2 {
+		t.Fatalf("disbursements = %d, want one per contribution", len(refund.Disbursements))
+	}
+	for _, disbursement := range refund.Disbursements {
+		if disbursement.Amount != 50_000 || disbursement.Status != models.RefundStatusProcessing {
+			t.Errorf("disbursement %s = %d, %s; want 50000, processing", disbursement.ID, disbursement.Amount, disbursement.Status)
+		}
+		// Settlement details come from users-service, not a users table in this database
+		if disbursement.SettlementBankName != "Test Bank" || disbursement.SettlementAccountNumber != "0123456789" ||
+			disbursement.SettlementAccountName != "Test Contributor" {
+			t.Errorf("disbursement %s settles to %q %q %q, want the users-service account", disbursement.ID,
+				disbursement.SettlementBankName, disbursement.SettlementAccountNumber, disbursement.SettlementAccountName)
+		}
+	}
+
+	stored, err := f.repo.Refund.GetRefundsByGoalID(ctx, f.goal.ID)
+	if err != nil {
+		t.Fatalf("GetRefundsByGoalID: %v", err)
+	}
+	if len(stored) != 1 || stored[0].ID != refund.ID || stored[0].Reason != "goal cancelled" {
+		t.Errorf("stored refunds = %+v, want the initiated refund", stored)
+	}
+	if n := outboxCounts(t, f.repo)["RefundInitiated"]; n != 1 {
+		t.Errorf("RefundInitiated events = %d, want 1", n)
+	}
+}
+
+func TestInitiateRefundIsRefused(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, f *withdrawalRefundFixture) (uuid.UUID, *dto.InitiateRefundRequest)
+		wantErr  error
+		wantCode string
+	}{
+		{
+			name: "malformed goal ID",
+			setup: func(t *testing.T, f *withdrawalRefundFixture) (uuid.UUID, *dto.InitiateRefundRequest) {
+				return f.goal.OwnerID, &dto.InitiateRefundRequest{GoalID: "not-a-uuid", RefundPercentage: 50}
+			},
+			wantCode: "invalid_goal_id",
+		},
+		{
+			name: "missing goal",
+			setup: func(t *testing.T, f *withdrawalRefundFixture) (uuid.UUID, *dto.InitiateRefundRequest) {
+				return f.goal.OwnerID, &dto.InitiateRefundRequest{GoalID: uuid.NewString(), RefundPercentage: 50}
+			},
+			wantErr: ErrGoalNotFound,
+		},
+		{
+			name: "initiator is not the owner",
+			setup: func(t *testing.T, f *withdrawalRefundFixture) (uuid.UUID, *dto.InitiateRefundRequest) {
+				return uuid.New(), &dto.InitiateRefundRequest{GoalID: f.goal.ID.String(), RefundPercentage: 50}
+			},
+			wantCode: "forbidden",
+		},
+		{
+			name: "goal still open",
+			setup: func(t *testing.T, f *withdrawalRefundFixture) (uuid.UUID, *dto.InitiateRefundRequest) {
+				setGoalStatus(t, f.repo, f.goal, models.GoalStatusOpen)
+				return f.goal.OwnerID, &dto.InitiateRefundRequest{GoalID: f.goal.ID.String(), RefundPercentage: 50}
+			},
+			wantCode: "invalid_goal_status",
+		},
+		{
+			name: "refund already in progress",
+			setup: func(t *testing.T, f *withdrawalRefundFixture) (uuid.UUID, *dto.InitiateRefundRequest) {
+				if _, err := f.refund(10); err != nil {
+					t.Fatalf("first refund: %v", err)
+				}
+				return f.goal.OwnerID, &dto.InitiateRefundRequest{GoalID: f.goal.ID.String(), RefundPercentage: 50}
+			},
+			wantCode: "refund_in_progress",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newWithdrawalRefundFixture(t)
+			initiatedBy, req := tt.setup(t, f)
+			before, err := f.repo.Refund.GetRefundsByGoalID(context.Background(), f.goal.ID)
+			if err != nil {
+				t.Fatalf("GetRefundsByGoalID: %v", err)
+			}
+
+			_, err = f.refunds.InitiateRefund(context.Background(), initiatedBy, req)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantCode != "" && errorCode(err) != tt.wantCode {
+				t.Fatalf("err = %v, want %s", err, tt.wantCode)
+			}
+
+			after, err := f.repo.Refund.GetRefundsByGoalID(context.Background(), f.goal.ID)
+			if err != nil {
+				t.Fatalf("GetRefundsByGoalID: %v", err)
+			}
+			if len(after) != len(before) {
+				t.Errorf("refunds went from %d to %d on a refused initiation", len(before), len(after))
+			}
+		})
+	}
+}
+
+// TestInitiateRefundNeedsSettlementAccounts initiates a refund while users-service is
+// failing; nothing is written rather than disbursements without accounts
+func TestInitiateRefundNeedsSettlementAccounts(t *testing.T) {
+	f := newWithdrawalRefundFixture(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	refunds := NewRefundService(f.repo, NewUsersClient(server.URL, time.Minute), nil, time.Hour)
+
+	_, err := refunds.InitiateRefund(context.Background(), f.goal.OwnerID, &dto.InitiateRefundRequest{
+		GoalID:           f.goal.ID.String(),
+		RefundPercentage: 50,
+	})
+	if err == nil {
+		t.Fatal("InitiateRefund succeeded without settlement accounts")
+	}
+
+	stored, err := f.repo.Refund.GetRefundsByGoalID(context.Background(), f.goal.ID)
+	if err != nil {
+		t.Fatalf("GetRefundsByGoalID: %v", err)
+	}
+	if len(stored) != 0 {
+		t.Errorf("%d refunds were written", len(stored))
+	}
+	if n := outboxCounts(t, f.repo)["RefundInitiated"]; n != 0 {
+		t.Errorf("RefundInitiated events = %d, want none", n)
+	}
+}
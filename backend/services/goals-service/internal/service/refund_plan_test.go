@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+func planContribution(userID uuid.UUID, amount int64) models.Contribution {
+	return models.Contribution{
+		ID:       uuid.New(),
+		UserID:   userID,
+		Amount:   amount,
+		Currency: "NGN",
+		Status:   models.ContributionStatusConfirmed,
+	}
+}
+
+func TestComputeDisbursementPlan(t *testing.T) {
+	goal := &models.Goal{ID: uuid.New(), Currency: "NGN"}
+	alice, bob := uuid.New(), uuid.New()
+	aliceFirst := planContribution(alice, 100_000)
+	aliceSecond := planContribution(alice, 333)
+	bobs := planContribution(bob, 50_000)
+	absorbed := planContribution(bob, 10_000)
+	absorbed.FeeMode = models.FeeModeAbsorb
+	absorbed.FeeAmount = 2_000
+	accounts := map[uuid.UUID]SettlementAccount{
+		alice: {BankName: "Test Bank", AccountNumber: "0123456789", AccountName: "Alice"},
+	}
+
+	tests := []struct {
+		name          string
+		contributions []models.Contribution
+		history       refundHistory
+		req           dto.InitiateRefundRequest
+		wantCode      string
+		wantScope     string
+		wantAmounts   map[uuid.UUID]int64 // planned disbursement per contribution
+		wantBalance   int64
+		wantMissing   int
+	}{
+		{
+			name:          "full goal refund takes the percentage of each contribution",
+			contributions: []models.Contribution{aliceFirst, bobs},
+			req:           dto.InitiateRefundRequest{RefundPercentage: 50},
+			wantScope:     dto.RefundScopeGoal,
+			wantAmounts:   map[uuid.UUID]int64{aliceFirst.ID: 50_000, bobs.ID: 25_000},
+			wantBalance:   150_000,
+			wantMissing:   1,
+		},
+		{
+			name:          "fractional kobo are rounded down",
+			contributions: []models.Contribution{aliceSecond},
+			req:           dto.InitiateRefundRequest{RefundPercentage: 50},
+			wantScope:     dto.RefundScopeGoal,
+			wantAmounts:   map[uuid.UUID]int64{aliceSecond.ID: 166},
+			wantBalance:   333,
+		},
+		{
+			name:          "absorbed fees, withdrawals and past refunds reduce the balance",
+			contributions: []models.Contribution{aliceFirst, absorbed},
+			history: refundHistory{
+				Refunded:       map[uuid.UUID]int64{aliceFirst.ID: 10_000},
+				TotalWithdrawn: 20_000,
+				TotalReserved:  5_000,
+			},
+			req:         dto.InitiateRefundRequest{RefundPercentage: 10},
+			wantScope:   dto.RefundScopeGoal,
+			wantAmounts: map[uuid.UUID]int64{aliceFirst.ID: 10_000, absorbed.ID: 1_000},
+			wantBalance: 110_000 - 2_000 - 20_000 - 5_000 - 10_000,
+			wantMissing: 1,
+		},
+		{
+			name:          "a refund beyond the balance is refused",
+			contributions: []models.Contribution{aliceFirst, bobs},
+			history:       refundHistory{TotalWithdrawn: 100_000},
+			req:           dto.InitiateRefundRequest{RefundPercentage: 50},
+			wantCode:      "insufficient_balance",
+		},
+		{
+			name:          "a percentage beyond what is left is refused",
+			contributions: []models.Contribution{aliceFirst, bobs},
+			history:       refundHistory{Refunded: map[uuid.UUID]int64{aliceFirst.ID: 60_000}},
+			req:           dto.InitiateRefundRequest{RefundPercentage: 50},
+			wantCode:      "refund_exceeds_remaining",
+		},
+		{
+			name:          "a full goal refund skips fully refunded contributions",
+			contributions: []models.Contribution{aliceFirst, bobs},
+			history:       refundHistory{Refunded: map[uuid.UUID]int64{aliceFirst.ID: 100_000}},
+			req:           dto.InitiateRefundRequest{RefundPercentage: 100},
+			wantScope:     dto.RefundScopeGoal,
+			wantAmounts:   map[uuid.UUID]int64{bobs.ID: 50_000},
+			wantBalance:   50_000,
+			wantMissing:   1,
+		},
+		{
+			name:          "nothing left to refund",
+			contributions: []models.Contribution{aliceFirst},
+			history:       refundHistory{Refunded: map[uuid.UUID]int64{aliceFirst.ID: 100_000}},
+			req:           dto.InitiateRefundRequest{RefundPercentage: 10},
+			wantCode:      "fully_refunded",
+		},
+		{
+			name:          "a targeted refund covers its contributions only",
+			contributions: []models.Contribution{aliceFirst, bobs},
+			req:           dto.InitiateRefundRequest{RefundPercentage: 100, ContributionIDs: []string{bobs.ID.String()}},
+			wantScope:     dto.RefundScopeTargeted,
+			wantAmounts:   map[uuid.UUID]int64{bobs.ID: 50_000},
+			wantBalance:   150_000,
+			wantMissing:   1,
+		},
+		{
+			name:          "a user target covers each of their contributions",
+			contributions: []models.Contribution{aliceFirst, aliceSecond, bobs},
+			req:           dto.InitiateRefundRequest{RefundPercentage: 100, UserIDs: []string{alice.String()}},
+			wantScope:     dto.RefundScopeTargeted,
+			wantAmounts:   map[uuid.UUID]int64{aliceFirst.ID: 100_000, aliceSecond.ID: 333},
+			wantBalance:   150_333,
+		},
+		{
+			name:          "a target outside the goal is refused",
+			contributions: []models.Contribution{aliceFirst},
+			req:           dto.InitiateRefundRequest{RefundPercentage: 10, ContributionIDs: []string{uuid.NewString()}},
+			wantCode:      "invalid_refund_target",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := tt.history
+			history.Accounts = accounts
+			plan, err := computeDisbursementPlan(goal, tt.contributions, &history, &tt.req)
+			if tt.wantCode != "" {
+				if code := errorCode(err); code != tt.wantCode {
+					t.Fatalf("err = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("computeDisbursementPlan: %v", err)
+			}
+
+			if plan.Scope != tt.wantScope {
+				t.Errorf("scope = %s, want %s", plan.Scope, tt.wantScope)
+			}
+			if plan.AvailableBalance != tt.wantBalance {
+				t.Errorf("available balance = %d, want %d", plan.AvailableBalance, tt.wantBalance)
+			}
+			if plan.MissingSettlementAccounts != tt.wantMissing {
+				t.Errorf("missing settlement accounts = %d, want %d", plan.MissingSettlementAccounts, tt.wantMissing)
+			}
+			if len(plan.Disbursements) != len(tt.wantAmounts) {
+				t.Fatalf("planned %d disbursements, want %d", len(plan.Disbursements), len(tt.wantAmounts))
+			}
+			var total int64
+			for _, planned := range plan.Disbursements {
+				want, ok := tt.wantAmounts[planned.ContributionID]
+				if !ok {
+					t.Errorf("unexpected disbursement for %s", planned.ContributionID)
+				} else if planned.Amount != want {
+					t.Errorf("disbursement for %s = %d, want %d", planned.ContributionID, planned.Amount, want)
+				}
+				if planned.HasSettlementAccount != (planned.UserID == alice) {
+					t.Errorf("disbursement for user %s: has settlement account %v", planned.UserID, planned.HasSettlementAccount)
+				}
+				total += planned.Amount
+			}
+			if plan.TotalRefundAmount != total {
+				t.Errorf("total refund amount = %d, want the disbursements' %d", plan.TotalRefundAmount, total)
+			}
+		})
+	}
+}
+
+// TestPreviewMatchesInitiation previews a refund and then initiates the same request over
+// the same data, after an earlier partial refund and a withdrawal, and expects the same
+// figures from both
+func TestPreviewMatchesInitiation(t *testing.T) {
+	f := newWithdrawalRefundFixture(t)
+	ctx := context.Background()
+
+	first, err := f.refund(25)
+	if err != nil {
+		t.Fatalf("first refund: %v", err)
+	}
+	f.completeDisbursements(t, first)
+	if _, err := f.withdraw(30_000); err != nil {
+		t.Fatalf("withdraw: %v", err)
+	}
+
+	req := &dto.InitiateRefundRequest{GoalID: f.goal.ID.String(), RefundPercentage: 40, Reason: "goal cancelled"}
+	preview, err := f.refunds.PreviewRefund(ctx, f.goal.OwnerID, req)
+	if err != nil {
+		t.Fatalf("PreviewRefund: %v", err)
+	}
+	refund, err := f.refunds.InitiateRefund(ctx, f.goal.OwnerID, req)
+	if err != nil {
+		t.Fatalf("InitiateRefund: %v", err)
+	}
+
+	if refund.TotalRefundAmount != preview.TotalRefundAmount || refund.RefundPercentage != preview.RefundPercentage {
+		t.Errorf("initiated %d at %v%%, previewed %d at %v%%", refund.TotalRefundAmount, refund.RefundPercentage, preview.TotalRefundAmount, preview.RefundPercentage)
+	}
+	for key, want := range map[string]interface{}{
+		"total_contributed": preview.TotalContributed,
+		"total_withdrawn":   preview.TotalWithdrawn,
+		"total_reserved":    preview.TotalReserved,
+		"total_refunded":    preview.TotalRefunded,
+		"available_balance": preview.AvailableBalance,
+		"scope":             preview.Scope,
+	} {
+		if got := refund.Metadata[key]; got != want {
+			t.Errorf("refund %s = %v, previewed %v", key, got, want)
+		}
+	}
+
+	previewed := make(map[uuid.UUID]dto.PlannedDisbursement, len(preview.Disbursements))
+	for _, planned := range preview.Disbursements {
+		previewed[planned.ContributionID] = planned
+	}
+	if len(refund.Disbursements) != len(previewed) {
+		t.Fatalf("initiated %d disbursements, previewed %d", len(refund.Disbursements), len(previewed))
+	}
+	for _, disbursement := range refund.Disbursements {
+		planned, ok := previewed[disbursement.ContributionID]
+		if !ok {
+			t.Errorf("disbursement for %s was not previewed", disbursement.ContributionID)
+			continue
+		}
+		if disbursement.Amount != planned.Amount || disbursement.UserID != planned.UserID ||
+			disbursement.SettlementAccountNumber != planned.SettlementAccountNumber {
+			t.Errorf("disbursement for %s: initiated %d to %s (%s), previewed %d to %s (%s)",
+				disbursement.ContributionID, disbursement.Amount, disbursement.UserID, disbursement.SettlementAccountNumber,
+				planned.Amount, planned.UserID, planned.SettlementAccountNumber)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const maxRefundRequestTextLength = 1000
+
+var (
+	ErrRefundRequestNotFound     = apperrors.NotFound("refund_request_not_found", "refund request not found")
+	ErrRefundRequestReviewed     = apperrors.Conflict("refund_request_reviewed", "refund request has already been reviewed")
+	ErrRefundRequestPending      = apperrors.Conflict("refund_request_pending", "a refund request for this contribution is awaiting review")
+	ErrSettlementAccountRequired = apperrors.Conflict("settlement_account_required", "the contributor needs a settlement account to receive the refund")
+)
+
+// RequestRefund records a contributor's request to have one of their contributions
+// refunded in full. On an open goal within the grace window after contributing the
+// request is approved at once and the refund started; otherwise the goal owner reviews
+// it. Either way the refund is planned now, so a request that could not be paid out is
+// refused up front.
+func (rs *RefundService) RequestRefund(ctx context.Context, contributionID, userID uuid.UUID, req *dto.RequestRefundRequest) (*models.RefundRequest, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxRefundRequestTextLength {
+		return nil, apperrors.Validation("invalid_reason", "reason is required and must be at most 1000 characters")
+	}
+
+	contribution, err := rs.repo.Contribution.GetContributionByID(ctx, contributionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContributionNotFound
+		}
+		return nil, errors.New("failed to fetch contribution")
+	}
+	if contribution.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+	if contribution.Status != models.ContributionStatusConfirmed {
+		return nil, apperrors.Conflict("contribution_not_refundable", "only confirmed contributions can be refunded")
+	}
+
+	// Fetched before locking the goal, as InitiateRefund does
+	accounts, err := rs.settlementAccounts([]models.Contribution{*contribution})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		goal    *models.Goal
+		request *models.RefundRequest
+		refund  *models.Refund
+	)
+	err = rs.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		// Lock the goal so requests and refunds against it serialise
+		goal, err = tx.Goal.GetGoalByIDForUpdate(ctx, contribution.GoalID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrGoalNotFound
+			}
+			return err
+		}
+
+		pending, err := tx.RefundRequest.HasPendingRequest(ctx, contributionID)
+		if err != nil {
+			return errors.New("failed to fetch refund requests")
+		}
+		if pending {
+			return ErrRefundRequestPending
+		}
+
+		plan, err := planContributionRefund(ctx, tx, goal, contribution, accounts)
+		if err != nil {
+			return err
+		}
+
+		request = &models.RefundRequest{
+			GoalID:         goal.ID,
+			ContributionID: contribution.ID,
+			UserID:         userID,
+			Amount:         plan.TotalRefundAmount,
+			Currency:       plan.Currency,
+			Reason:         reason,
+			Status:         models.RefundRequestStatusPending,
+		}
+
+		if goal.Status == models.GoalStatusOpen && time.Since(contribution.CreatedAt) <= rs.graceWindow {
+			refund, err = createRefund(ctx, tx, plan, userID, userID, reason)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			request.Status = models.RefundRequestStatusApproved
+			request.AutoApproved = true
+			request.RefundID = &refund.ID
+			request.ReviewedAt = &now
+		}
+
+		if err := tx.RefundRequest.CreateRequest(ctx, request); err != nil {
+			return errors.New("failed to create refund request")
+		}
+		return recordRefundRequested(ctx, tx, request, goal)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if refund != nil {
+		if _, err := rs.startRefund(ctx, refund); err != nil {
+			log.Printf("Failed to start refund %s for refund request %s: %v", refund.ID, request.ID, err)
+		}
+	}
+
+	return request, nil
+}
+
+// ReviewRefundRequest approves or denies a pending refund request. Only the goal owner
+// may review. Approving starts a refund of the contribution in full.
+func (rs *RefundService) ReviewRefundRequest(ctx context.Context, requestID, reviewerID uuid.UUID, approve bool, req *dto.ReviewRefundRequestRequest) (*models.RefundRequest, error) {
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > maxRefundRequestTextLength {
+		return nil, apperrors.Validation("invalid_note", "note must be at most 1000 characters")
+	}
+
+	request, err := rs.repo.RefundRequest.GetRequestByID(ctx, requestID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRefundRequestNotFound
+		}
+		return nil, errors.New("failed to fetch refund request")
+	}
+
+	contribution, err := rs.repo.Contribution.GetContributionByID(ctx, request.ContributionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContributionNotFound
+		}
+		return nil, errors.New("failed to fetch contribution")
+	}
+
+	var accounts map[uuid.UUID]SettlementAccount
+	if approve {
+		if accounts, err = rs.settlementAccounts([]models.Contribution{*contribution}); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		goal   *models.Goal
+		refund *models.Refund
+	)
+	err = rs.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		goal, err = tx.Goal.GetGoalByIDForUpdate(ctx, request.GoalID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrGoalNotFound
+			}
+			return err
+		}
+		if goal.OwnerID != reviewerID {
+			return apperrors.Forbidden("forbidden", "only goal owner can review refund requests")
+		}
+
+		// Re-read under the goal lock so concurrent reviews cannot both act
+		request, err = tx.RefundRequest.GetRequestByID(ctx, requestID)
+		if err != nil {
+			return errors.New("failed to fetch refund request")
+		}
+		if request.Status != models.RefundRequestStatusPending {
+			return ErrRefundRequestReviewed
+		}
+
+		updates := map[string]interface{}{
+			"status":      models.RefundRequestStatusDenied,
+			"reviewed_by": reviewerID,
+			"review_note": note,
+			"reviewed_at": time.Now(),
+		}
+		if approve {
+			plan, err := planContributionRefund(ctx, tx, goal, contribution, accounts)
+			if err != nil {
+				return err
+			}
+			refund, err = createRefund(ctx, tx, plan, reviewerID, request.UserID, request.Reason)
+			if err != nil {
+				return err
+			}
+			updates["status"] = models.RefundRequestStatusApproved
+			updates["refund_id"] = refund.ID
+			updates["amount"] = plan.TotalRefundAmount
+		}
+
+		if err := tx.RefundRequest.UpdateRequest(ctx, requestID, updates); err != nil {
+			return errors.New("failed to update refund request")
+		}
+		request, err = tx.RefundRequest.GetRequestByID(ctx, requestID)
+		if err != nil || approve {
+			return err
+		}
+		return recordRefundRequestDenied(ctx, tx, request, goal)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if refund != nil {
+		if _, err := rs.startRefund(ctx, refund); err != nil {
+			log.Printf("Failed to start refund %s for refund request %s: %v", refund.ID, request.ID, err)
+		}
+		return request, nil
+	}
+
+	rs.audit.Record(ctx, AuditEntry{
+		GoalID:     request.GoalID,
+		ActorID:    reviewerID,
+		Action:     models.AuditActionRefundRequestDenied,
+		EntityType: auditEntityRefundRequest,
+		EntityID:   request.ID,
+		After: map[string]interface{}{
+			"contribution_id": request.ContributionID,
+			"amount":          request.Amount,
+			"currency":        request.Currency,
+			"note":            request.ReviewNote,
+		},
+	})
+
+	return request, nil
+}
+
+// GetGoalRefundRequests lists a goal's refund requests for its owner, newest first,
+// optionally only those with status
+func (rs *RefundService) GetGoalRefundRequests(ctx context.Context, goalID, ownerID uuid.UUID, status string) ([]models.RefundRequest, error) {
+	requestStatus := models.RefundRequestStatus(strings.ToUpper(status))
+	switch requestStatus {
+	case "", models.RefundRequestStatusPending, models.RefundRequestStatusApproved, models.RefundRequestStatusDenied:
+	default:
+		return nil, apperrors.Validation("invalid_status", "status must be PENDING, APPROVED or DENIED")
+	}
+
+	goal, err := rs.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if goal.OwnerID != ownerID {
+		return nil, apperrors.Forbidden("forbidden", "only goal owner can view refund requests")
+	}
+
+	requests, err := rs.repo.RefundRequest.GetRequestsByGoalID(ctx, goalID, requestStatus)
+	if err != nil {
+		return nil, errors.New("failed to fetch refund requests")
+	}
+	return requests, nil
+}
+
+// planContributionRefund plans refunding what is left of a single contribution, which
+// must be paid to a settlement account. repo must be a transaction's holding the goal
+// lock.
+func planContributionRefund(ctx context.Context, repo *repository.Repository, goal *models.Goal, contribution *models.Contribution, accounts map[uuid.UUID]SettlementAccount) (*dto.RefundPlan, error) {
+	contributions, history, err := loadRefundHistory(ctx, repo, goal.ID)
+	if err != nil {
+		return nil, err
+	}
+	history.Accounts = accounts
+
+	plan, err := computeDisbursementPlan(goal, contributions, history, &dto.InitiateRefundRequest{
+		GoalID:           goal.ID.String(),
+		RefundPercentage: 100,
+		ContributionIDs:  []string{contribution.ID.String()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if plan.MissingSettlementAccounts > 0 {
+		return nil, ErrSettlementAccountRequired
+	}
+	return plan, nil
+}
+
+// recordRefundRequested records the event telling the goal owner about a refund request,
+// in the transaction that created it
+func recordRefundRequested(ctx context.Context, tx *repository.Repository, request *models.RefundRequest, goal *models.Goal) error {
+	event := events.RefundRequested{
+		ID:             uuid.New().String(),
+		RequestID:      request.ID.String(),
+		GoalID:         goal.ID.String(),
+		GoalTitle:      goal.Title,
+		OwnerID:        goal.OwnerID.String(),
+		ContributionID: request.ContributionID.String(),
+		UserID:         request.UserID.String(),
+		Amount:         request.Amount,
+		Currency:       request.Currency,
+		Reason:         request.Reason,
+		AutoApproved:   request.AutoApproved,
+		CreatedAt:      time.Now().Unix(),
+	}
+	return recordEvent(ctx, tx, "RefundRequested", event)
+}
+
+// recordRefundRequestDenied records the event telling the contributor their refund request
+// was denied, in the transaction that denied it
+func recordRefundRequestDenied(ctx context.Context, tx *repository.Repository, request *models.RefundRequest, goal *models.Goal) error {
+	event := events.RefundRequestDenied{
+		ID:             uuid.New().String(),
+		RequestID:      request.ID.String(),
+		GoalID:         goal.ID.String(),
+		GoalTitle:      goal.Title,
+		ContributionID: request.ContributionID.String(),
+		UserID:         request.UserID.String(),
+		Amount:         request.Amount,
+		Currency:       request.Currency,
+		Note:           request.ReviewNote,
+		CreatedAt:      time.Now().Unix(),
+	}
+	return recordEvent(ctx, tx, "RefundRequestDenied", event)
+}
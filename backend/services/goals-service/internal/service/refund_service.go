@@ -1,12 +1,18 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
 	"github.com/gofund/shared/events"
-	"github.com/gofund/shared/messaging"
 	"github.com/gofund/shared/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -14,258 +20,683 @@ import (
 
 // RefundService handles refund business logic
 type RefundService struct {
-	db        *gorm.DB
-	publisher messaging.Publisher
+	repo        *repository.Repository
+	usersClient *UsersClient
+	audit       *AuditService
+	graceWindow time.Duration // refund requests on open goals within it are approved automatically
 }
 
 // NewRefundService creates a new refund service instance
-func NewRefundService(db *gorm.DB, publisher messaging.Publisher) *RefundService {
+func NewRefundService(repo *repository.Repository, usersClient *UsersClient, audit *AuditService, graceWindow time.Duration) *RefundService {
 	return &RefundService{
-		db:        db,
-		publisher: publisher,
+		repo:        repo,
+		usersClient: usersClient,
+		audit:       audit,
+		graceWindow: graceWindow,
 	}
 }
 
+// refundHistory is what a goal has already paid out or reserved, read before planning a refund
+type refundHistory struct {
+	Refunded       map[uuid.UUID]int64 // per contribution, excluding failed disbursements
+	TotalWithdrawn int64               // completed withdrawals
+	TotalReserved  int64               // pending and processing withdrawals
+	Accounts       map[uuid.UUID]SettlementAccount
+}
+
 // InitiateRefund initiates a refund for a goal
-func (rs *RefundService) InitiateRefund(initiatedBy uuid.UUID, req *dto.InitiateRefundRequest) (*models.Refund, error) {
+func (rs *RefundService) InitiateRefund(ctx context.Context, initiatedBy uuid.UUID, req *dto.InitiateRefundRequest) (*models.Refund, error) {
 	goalID, err := uuid.Parse(req.GoalID)
 	if err != nil {
-		return nil, errors.New("invalid goal ID")
+		return nil, apperrors.Validation("invalid_goal_id", "invalid goal ID")
+	}
+
+	// Validate and fetch settlement accounts before locking the goal, so the users-service
+	// call is not made while holding the lock. A contributor confirmed in between is
+	// planned without an account.
+	_, contributions, _, err := loadRefundInputs(ctx, rs.repo, goalID, initiatedBy, false)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := rs.settlementAccounts(contributions)
+	if err != nil {
+		return nil, err
+	}
+
+	var refund *models.Refund
+	err = rs.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		// Lock the goal so concurrent refund initiations serialise
+		goal, contributions, history, err := loadRefundInputs(ctx, tx, goalID, initiatedBy, true)
+		if err != nil {
+			return err
+		}
+		history.Accounts = accounts
+
+		plan, err := computeDisbursementPlan(goal, contributions, history, req)
+		if err != nil {
+			return err
+		}
+
+		refund, err = createRefund(ctx, tx, plan, initiatedBy, uuid.Nil, req.Reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Start transaction
-	tx := rs.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	return rs.startRefund(ctx, refund)
+}
+
+// createRefund records a planned refund and its disbursements as PROCESSING, with the
+// RefundInitiated event that hands them to payments-service. requestedBy is the
+// contributor whose refund request it settles, if any.
+func createRefund(ctx context.Context, tx *repository.Repository, plan *dto.RefundPlan, initiatedBy, requestedBy uuid.UUID, reason string) (*models.Refund, error) {
+	refund := &models.Refund{
+		GoalID:            plan.GoalID,
+		InitiatedBy:       initiatedBy,
+		RefundPercentage:  plan.RefundPercentage,
+		TotalRefundAmount: plan.TotalRefundAmount,
+		Currency:          plan.Currency,
+		Reason:            reason,
+		Status:            models.RefundStatusProcessing,
+		Metadata: map[string]interface{}{
+			"total_contributed": plan.TotalContributed,
+			"total_withdrawn":   plan.TotalWithdrawn,
+			"total_reserved":    plan.TotalReserved,
+			"total_refunded":    plan.TotalRefunded,
+			"available_balance": plan.AvailableBalance,
+			"scope":             plan.Scope,
+		},
+	}
+	if plan.Scope == dto.RefundScopeTargeted {
+		contributionIDs := make([]string, len(plan.Disbursements))
+		for i, planned := range plan.Disbursements {
+			contributionIDs[i] = planned.ContributionID.String()
 		}
-	}()
+		refund.Metadata["contribution_ids"] = contributionIDs
+	}
+
+	if err := tx.Refund.CreateRefund(ctx, refund); err != nil {
+		return nil, errors.New("failed to create refund")
+	}
+
+	// Create refund disbursements for each contributor
+	for _, planned := range plan.Disbursements {
+		disbursement := &models.RefundDisbursement{
+			RefundID:                refund.ID,
+			ContributionID:          planned.ContributionID,
+			UserID:                  planned.UserID,
+			Amount:                  planned.Amount,
+			Currency:                planned.Currency,
+			SettlementBankName:      planned.SettlementBankName,
+			SettlementAccountNumber: planned.SettlementAccountNumber,
+			SettlementAccountName:   planned.SettlementAccountName,
+			Status:                  models.RefundStatusProcessing,
+		}
+
+		if err := tx.RefundDisbursement.CreateDisbursement(ctx, disbursement); err != nil {
+			return nil, errors.New("failed to create refund disbursement")
+		}
+		refund.Disbursements = append(refund.Disbursements, *disbursement)
+	}
+
+	if err := recordRefundInitiated(ctx, tx, refund, requestedBy); err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// startRefund audits a newly created refund and returns it with its disbursements
+func (rs *RefundService) startRefund(ctx context.Context, refund *models.Refund) (*models.Refund, error) {
+	rs.audit.Record(ctx, AuditEntry{
+		GoalID:     refund.GoalID,
+		ActorID:    refund.InitiatedBy,
+		Action:     models.AuditActionRefundInitiated,
+		EntityType: auditEntityRefund,
+		EntityID:   refund.ID,
+		After: map[string]interface{}{
+			"refund_percentage":   refund.RefundPercentage,
+			"total_refund_amount": refund.TotalRefundAmount,
+			"currency":            refund.Currency,
+			"reason":              refund.Reason,
+			"balances":            refund.Metadata,
+		},
+	})
+
+	// Load disbursements for response, from the primary as a replica may not have them yet
+	refund, err := rs.repo.Primary().Refund.GetRefundByID(ctx, refund.ID)
+	if err != nil {
+		return nil, errors.New("failed to load refund details")
+	}
+	return refund, nil
+}
+
+// recordRefundInitiated records a RefundInitiated event carrying the refund's disbursements
+func recordRefundInitiated(ctx context.Context, tx *repository.Repository, refund *models.Refund, requestedBy uuid.UUID) error {
+	event := events.RefundInitiated{
+		ID:                uuid.New().String(),
+		RefundID:          refund.ID.String(),
+		GoalID:            refund.GoalID.String(),
+		InitiatedBy:       refund.InitiatedBy.String(),
+		RefundPercentage:  refund.RefundPercentage,
+		TotalRefundAmount: refund.TotalRefundAmount,
+		Currency:          refund.Currency,
+		Disbursements:     make([]events.RefundDisbursementItem, 0, len(refund.Disbursements)),
+		CreatedAt:         time.Now().Unix(),
+	}
+	if requestedBy != uuid.Nil {
+		event.RequestedBy = requestedBy.String()
+	}
+	for _, disbursement := range refund.Disbursements {
+		event.Disbursements = append(event.Disbursements, events.RefundDisbursementItem{
+			DisbursementID: disbursement.ID.String(),
+			ContributionID: disbursement.ContributionID.String(),
+			UserID:         disbursement.UserID.String(),
+			Amount:         disbursement.Amount,
+			Currency:       disbursement.Currency,
+			BankName:       disbursement.SettlementBankName,
+			AccountNumber:  disbursement.SettlementAccountNumber,
+			AccountName:    disbursement.SettlementAccountName,
+		})
+	}
+	return recordEvent(ctx, tx, "RefundInitiated", event)
+}
 
-	// Get goal
-	var goal models.Goal
-	if err := tx.First(&goal, "id = ?", goalID).Error; err != nil {
-		tx.Rollback()
-		return nil, errors.New("goal not found")
+// PreviewRefund computes the disbursements a refund request would create without
+// writing anything. It uses plain reads outside any transaction.
+func (rs *RefundService) PreviewRefund(ctx context.Context, initiatedBy uuid.UUID, req *dto.InitiateRefundRequest) (*dto.RefundPlan, error) {
+	goalID, err := uuid.Parse(req.GoalID)
+	if err != nil {
+		return nil, apperrors.Validation("invalid_goal_id", "invalid goal ID")
+	}
+
+	goal, contributions, history, err := loadRefundInputs(ctx, rs.repo, goalID, initiatedBy, false)
+	if err != nil {
+		return nil, err
+	}
+	history.Accounts, err = rs.settlementAccounts(contributions)
+	if err != nil {
+		return nil, err
+	}
+
+	return computeDisbursementPlan(goal, contributions, history, req)
+}
+
+// loadRefundInputs validates that a refund may be initiated on the goal and reads
+// everything needed to plan it except settlement accounts. With lock set the goal row
+// is locked for update, so repo must be a transaction's.
+func loadRefundInputs(ctx context.Context, repo *repository.Repository, goalID, initiatedBy uuid.UUID, lock bool) (*models.Goal, []models.Contribution, *refundHistory, error) {
+	getGoal := repo.Goal.GetGoalByIDSimple
+	if lock {
+		getGoal = repo.Goal.GetGoalByIDForUpdate
+	}
+	goal, err := getGoal(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, ErrGoalNotFound
+		}
+		return nil, nil, nil, err
 	}
 
 	// Verify initiator is goal owner
 	if goal.OwnerID != initiatedBy {
-		tx.Rollback()
-		return nil, errors.New("only goal owner can initiate refunds")
+		return nil, nil, nil, apperrors.Forbidden("forbidden", "only goal owner can initiate refunds")
 	}
 
 	// Verify goal is cancelled or closed
 	if goal.Status != models.GoalStatusCancelled && goal.Status != models.GoalStatusClosed {
-		tx.Rollback()
-		return nil, errors.New("can only refund cancelled or closed goals")
+		return nil, nil, nil, apperrors.Conflict("invalid_goal_status", "can only refund cancelled or closed goals")
 	}
 
 	// Check if refund already exists for this goal
-	var existingRefund models.Refund
-	err = tx.Where("goal_id = ? AND status IN ?", goalID, []models.RefundStatus{
-		models.RefundStatusPending,
-		models.RefundStatusProcessing,
-	}).First(&existingRefund).Error
-	
-	if err == nil {
-		tx.Rollback()
-		return nil, errors.New("refund already in progress for this goal")
+	active, err := repo.Goal.HasActiveRefund(ctx, goalID)
+	if err != nil {
+		return nil, nil, nil, errors.New("failed to fetch refunds")
+	}
+	if active {
+		return nil, nil, nil, apperrors.Conflict("refund_in_progress", "refund already in progress for this goal")
+	}
+
+	contributions, history, err := loadRefundHistory(ctx, repo, goalID)
+	if err != nil {
+		return nil, nil, nil, err
 	}
+	return goal, contributions, history, nil
+}
 
+// loadRefundHistory reads a goal's confirmed contributions and what has already been
+// refunded from and withdrawn against them
+func loadRefundHistory(ctx context.Context, repo *repository.Repository, goalID uuid.UUID) ([]models.Contribution, *refundHistory, error) {
 	// Get all confirmed contributions
-	var contributions []models.Contribution
-	if err := tx.Where("goal_id = ? AND status = ?", goalID, models.ContributionStatusConfirmed).Find(&contributions).Error; err != nil {
-		tx.Rollback()
-		return nil, errors.New("failed to fetch contributions")
+	contributions, err := repo.Contribution.GetConfirmedContributionsByGoalID(ctx, goalID)
+	if err != nil {
+		return nil, nil, errors.New("failed to fetch contributions")
 	}
 
 	if len(contributions) == 0 {
-		tx.Rollback()
-		return nil, errors.New("no confirmed contributions to refund")
+		return nil, nil, apperrors.Conflict("nothing_to_refund", "no confirmed contributions to refund")
 	}
 
-	// Calculate total refund amount
-	var totalContributed int64
+	history := &refundHistory{}
+
+	contributionIDs := make([]uuid.UUID, len(contributions))
+	for i, contrib := range contributions {
+		contributionIDs[i] = contrib.ID
+	}
+	history.Refunded, err = repo.RefundDisbursement.GetRefundedAmounts(ctx, contributionIDs)
+	if err != nil {
+		return nil, nil, errors.New("failed to fetch previous refunds")
+	}
+
+	history.TotalWithdrawn, err = repo.Goal.GetTotalCompletedWithdrawals(ctx, goalID)
+	if err != nil {
+		return nil, nil, errors.New("failed to fetch withdrawals")
+	}
+	committed, err := repo.Goal.GetTotalCommittedWithdrawals(ctx, goalID)
+	if err != nil {
+		return nil, nil, errors.New("failed to fetch withdrawals")
+	}
+	history.TotalReserved = committed - history.TotalWithdrawn
+
+	return contributions, history, nil
+}
+
+// settlementAccounts fetches the settlement accounts of the contributors from users-service
+func (rs *RefundService) settlementAccounts(contributions []models.Contribution) (map[uuid.UUID]SettlementAccount, error) {
+	seen := make(map[uuid.UUID]bool, len(contributions))
+	userIDs := make([]uuid.UUID, 0, len(contributions))
 	for _, contrib := range contributions {
-		totalContributed += contrib.Amount
+		if !seen[contrib.UserID] {
+			seen[contrib.UserID] = true
+			userIDs = append(userIDs, contrib.UserID)
+		}
 	}
 
-	totalRefundAmount := int64(float64(totalContributed) * (req.RefundPercentage / 100.0))
+	accounts, err := rs.usersClient.SettlementAccounts(userIDs)
+	if err != nil {
+		log.Printf("Failed to fetch settlement accounts from users-service: %v", err)
+		return nil, errors.New("failed to fetch user settlement accounts")
+	}
+	return accounts, nil
+}
 
-	// Create refund record
-	refund := &models.Refund{
-		GoalID:            goalID,
-		InitiatedBy:       initiatedBy,
-		RefundPercentage:  req.RefundPercentage,
-		TotalRefundAmount: totalRefundAmount,
-		Currency:          goal.Currency,
-		Reason:            req.Reason,
-		Status:            models.RefundStatusPending,
+// computeDisbursementPlan works out each contributor's refund. It is pure so preview
+// and initiation produce identical figures over the same data.
+//
+// The requested percentage is capped so cumulative refunds never exceed 100% of any
+// contribution in scope, and the total must be covered by the goal's available balance:
+// confirmed contributions minus completed and reserved withdrawals and refunds
+// already paid out. A targeted refund plans disbursements for its targets only, and a
+// full-goal refund skips contributions a targeted one already refunded in full; the
+// balance is always the whole goal's.
+func computeDisbursementPlan(goal *models.Goal, contributions []models.Contribution, history *refundHistory, req *dto.InitiateRefundRequest) (*dto.RefundPlan, error) {
+	targets, err := refundTargets(contributions, history.Refunded, req)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := tx.Create(refund).Error; err != nil {
-		tx.Rollback()
-		return nil, errors.New("failed to create refund")
+	scope := dto.RefundScopeTargeted
+	if targets == nil {
+		scope = dto.RefundScopeGoal
+		targets = make(map[uuid.UUID]bool, len(contributions))
+		for _, contrib := range contributions {
+			targets[contrib.ID] = contrib.Amount > history.Refunded[contrib.ID]
+		}
 	}
 
-	// Create refund disbursements for each contributor
-	// First, get settlement account details for all users
-	userIDs := make([]uuid.UUID, len(contributions))
-	for i, contrib := range contributions {
-		userIDs[i] = contrib.UserID
+	scoped := make([]models.Contribution, 0, len(targets))
+	for _, contrib := range contributions {
+		if targets[contrib.ID] {
+			scoped = append(scoped, contrib)
+		}
+	}
+	if len(scoped) == 0 {
+		return nil, apperrors.Conflict("fully_refunded", "contributions have already been fully refunded")
 	}
 
-	var users []models.User
-	if err := tx.Where("id IN ?", userIDs).Find(&users).Error; err != nil {
-		tx.Rollback()
-		return nil, errors.New("failed to fetch user settlement accounts")
+	remaining := remainingRefundablePercent(scoped, history.Refunded)
+	if remaining <= 0 {
+		return nil, apperrors.Conflict("fully_refunded", "contributions have already been fully refunded")
+	}
+	if req.RefundPercentage > remaining {
+		return nil, apperrors.Conflict("refund_exceeds_remaining", fmt.Sprintf("only %s%% remaining refundable", strconv.FormatFloat(remaining, 'f', -1, 64)))
 	}
 
-	// Map users by ID for easy lookup
-	userMap := make(map[uuid.UUID]*models.User)
-	for i := range users {
-		userMap[users[i].ID] = &users[i]
+	plan := &dto.RefundPlan{
+		GoalID:           goal.ID,
+		RefundPercentage: req.RefundPercentage,
+		Scope:            scope,
+		Currency:         goal.Currency,
+		TotalWithdrawn:   history.TotalWithdrawn,
+		TotalReserved:    history.TotalReserved,
+		Disbursements:    make([]dto.PlannedDisbursement, 0, len(scoped)),
 	}
 
-	// Create disbursements
 	for _, contrib := range contributions {
+		alreadyRefunded := history.Refunded[contrib.ID]
+		plan.TotalContributed += contrib.Amount
+		plan.TotalRefunded += alreadyRefunded
+		if contrib.FeeMode == models.FeeModeAbsorb {
+			plan.TotalFees += contrib.FeeAmount
+		}
+		if !targets[contrib.ID] {
+			continue
+		}
+
+		// Never refund more than what is left of the contribution
 		refundAmount := int64(float64(contrib.Amount) * (req.RefundPercentage / 100.0))
-		
-		user := userMap[contrib.UserID]
-		disbursement := &models.RefundDisbursement{
-			RefundID:       refund.ID,
-			ContributionID: contrib.ID,
-			UserID:         contrib.UserID,
-			Amount:         refundAmount,
-			Currency:       contrib.Currency,
-			Status:         models.RefundStatusPending,
+		if left := contrib.Amount - alreadyRefunded; refundAmount > left {
+			refundAmount = left
 		}
 
-		// Include settlement account if available
-		if user != nil {
-			disbursement.SettlementBankName = user.SettlementBankName
-			disbursement.SettlementAccountNumber = user.SettlementAccountNumber
-			disbursement.SettlementAccountName = user.SettlementAccountName
+		planned := dto.PlannedDisbursement{
+			ContributionID:     contrib.ID,
+			UserID:             contrib.UserID,
+			ContributionAmount: contrib.Amount,
+			AlreadyRefunded:    alreadyRefunded,
+			Amount:             refundAmount,
+			Currency:           contrib.Currency,
 		}
 
-		if err := tx.Create(disbursement).Error; err != nil {
-			tx.Rollback()
-			return nil, errors.New("failed to create refund disbursement")
+		// Include settlement account if available
+		if account, ok := history.Accounts[contrib.UserID]; ok {
+			planned.SettlementBankName = account.BankName
+			planned.SettlementAccountNumber = account.AccountNumber
+			planned.SettlementAccountName = account.AccountName
+			planned.HasSettlementAccount = account.AccountNumber != ""
+		}
+		if !planned.HasSettlementAccount {
+			plan.MissingSettlementAccounts++
 		}
+
+		plan.TotalRefundAmount += refundAmount
+		plan.Disbursements = append(plan.Disbursements, planned)
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return nil, errors.New("failed to commit refund transaction")
+	plan.AvailableBalance = plan.TotalContributed - plan.TotalFees - plan.TotalWithdrawn - plan.TotalReserved - plan.TotalRefunded
+	if plan.TotalRefundAmount > plan.AvailableBalance {
+		return nil, apperrors.Conflict("insufficient_balance", fmt.Sprintf("refund of %d %s exceeds available balance of %d %s", plan.TotalRefundAmount, plan.Currency, plan.AvailableBalance, plan.Currency))
 	}
 
-	// Load disbursements for response
-	if err := rs.db.Preload("Disbursements").First(refund, refund.ID).Error; err != nil {
-		return nil, errors.New("failed to load refund details")
+	return plan, nil
+}
+
+// refundTargets resolves the contributions a targeted refund covers, or nil for a
+// full-goal refund. A listed contribution must be a confirmed contribution of the goal
+// with something left to refund. A listed user's confirmed contributions are all covered
+// except those already fully refunded.
+func refundTargets(contributions []models.Contribution, refunded map[uuid.UUID]int64, req *dto.InitiateRefundRequest) (map[uuid.UUID]bool, error) {
+	if !req.Targeted() {
+		return nil, nil
+	}
+
+	byID := make(map[uuid.UUID]models.Contribution, len(contributions))
+	byUser := make(map[uuid.UUID][]models.Contribution)
+	for _, contrib := range contributions {
+		byID[contrib.ID] = contrib
+		byUser[contrib.UserID] = append(byUser[contrib.UserID], contrib)
+	}
+	fullyRefunded := func(contrib models.Contribution) bool {
+		return contrib.Amount-refunded[contrib.ID] <= 0
 	}
 
-	// Emit RefundInitiated event
-	if rs.publisher != nil {
-		event := events.RefundInitiated{
-			ID:                uuid.New().String(),
-			RefundID:          refund.ID.String(),
-			GoalID:            refund.GoalID.String(),
-			InitiatedBy:       refund.InitiatedBy.String(),
-			RefundPercentage:  refund.RefundPercentage,
-			TotalRefundAmount: refund.TotalRefundAmount,
-			CreatedAt:         time.Now().Unix(),
+	targets := make(map[uuid.UUID]bool)
+	for _, raw := range req.ContributionIDs {
+		contributionID, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, apperrors.Validation("invalid_contribution_id", "invalid contribution ID")
+		}
+		contrib, ok := byID[contributionID]
+		if !ok {
+			return nil, apperrors.Validation("invalid_refund_target", fmt.Sprintf("contribution %s is not a confirmed contribution of this goal", contributionID))
 		}
-		rs.publisher.Publish("RefundInitiated", event)
+		if fullyRefunded(contrib) {
+			return nil, apperrors.Conflict("fully_refunded", fmt.Sprintf("contribution %s has already been fully refunded", contributionID))
+		}
+		targets[contributionID] = true
 	}
 
-	return refund, nil
+	for _, raw := range req.UserIDs {
+		userID, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, apperrors.Validation("invalid_user_id", "invalid user ID")
+		}
+		userContributions, ok := byUser[userID]
+		if !ok {
+			return nil, apperrors.Validation("invalid_refund_target", fmt.Sprintf("user %s has no confirmed contributions to this goal", userID))
+		}
+		refundable := false
+		for _, contrib := range userContributions {
+			if !fullyRefunded(contrib) {
+				targets[contrib.ID] = true
+				refundable = true
+			}
+		}
+		if !refundable {
+			return nil, apperrors.Conflict("fully_refunded", fmt.Sprintf("contributions of user %s have already been fully refunded", userID))
+		}
+	}
+
+	return targets, nil
+}
+
+// remainingRefundablePercent returns the largest percentage that can still be refunded
+// uniformly across the contributions, rounded down to two decimal places
+func remainingRefundablePercent(contributions []models.Contribution, refunded map[uuid.UUID]int64) float64 {
+	remaining := 100.0
+	for _, contrib := range contributions {
+		if contrib.Amount <= 0 {
+			continue
+		}
+		left := float64(contrib.Amount-refunded[contrib.ID]) / float64(contrib.Amount) * 100
+		if left < remaining {
+			remaining = left
+		}
+	}
+	if remaining < 0 {
+		return 0
+	}
+	return math.Floor(remaining*100) / 100
 }
 
 // GetRefund retrieves a refund by ID
-func (rs *RefundService) GetRefund(refundID uuid.UUID) (*models.Refund, error) {
-	var refund models.Refund
-	if err := rs.db.Preload("Disbursements").First(&refund, "id = ?", refundID).Error; err != nil {
-		return nil, errors.New("refund not found")
+func (rs *RefundService) GetRefund(ctx context.Context, refundID uuid.UUID) (*models.Refund, error) {
+	refund, err := rs.repo.Refund.GetRefundByID(ctx, refundID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NotFound("refund_not_found", "refund not found")
+		}
+		return nil, errors.New("failed to fetch refund")
 	}
-	return &refund, nil
+	return refund, nil
 }
 
 // GetGoalRefunds retrieves all refunds for a goal
-func (rs *RefundService) GetGoalRefunds(goalID uuid.UUID) ([]models.Refund, error) {
-	var refunds []models.Refund
-	if err := rs.db.Preload("Disbursements").Where("goal_id = ?", goalID).Find(&refunds).Error; err != nil {
+func (rs *RefundService) GetGoalRefunds(ctx context.Context, goalID uuid.UUID) ([]models.Refund, error) {
+	refunds, err := rs.repo.Refund.GetRefundsByGoalID(ctx, goalID)
+	if err != nil {
 		return nil, errors.New("failed to fetch refunds")
 	}
 	return refunds, nil
 }
 
-// UpdateRefundStatus updates the status of a refund
-func (rs *RefundService) UpdateRefundStatus(refundID uuid.UUID, status models.RefundStatus) error {
+// GetContributionRefund returns the latest refund disbursement of a contribution. Only the
+// contributor may fetch it.
+func (rs *RefundService) GetContributionRefund(ctx context.Context, contributionID, userID uuid.UUID) (*dto.ContributionRefund, error) {
+	contribution, err := rs.repo.Contribution.GetContributionByID(ctx, contributionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContributionNotFound
+		}
+		return nil, errors.New("failed to fetch contribution")
+	}
+	if contribution.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	disbursement, err := rs.repo.RefundDisbursement.GetLatestDisbursementByContributionID(ctx, contributionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NotFound("refund_not_found", "contribution has not been refunded")
+		}
+		return nil, errors.New("failed to fetch refund")
+	}
+
+	return &dto.ContributionRefund{
+		ContributionID:     contributionID,
+		RefundID:           disbursement.RefundID,
+		DisbursementID:     disbursement.ID,
+		Status:             disbursement.Status,
+		Amount:             disbursement.Amount,
+		Currency:           disbursement.Currency,
+		BankName:           disbursement.SettlementBankName,
+		AccountNumberLast4: lastFourDigits(disbursement.SettlementAccountNumber),
+		FailureReason:      disbursement.FailureReason,
+		CreatedAt:          disbursement.CreatedAt,
+		CompletedAt:        disbursement.CompletedAt,
+	}, nil
+}
+
+// UpdateRefundStatus updates the status of a refund, recording RefundCompleted when it
+// completes
+func (rs *RefundService) UpdateRefundStatus(ctx context.Context, refundID uuid.UUID, status models.RefundStatus) error {
+	return rs.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		refund, err := tx.Refund.GetRefundByIDForUpdate(ctx, refundID)
+		if err != nil {
+			return err
+		}
+		return updateRefundStatus(ctx, tx, refund, status)
+	})
+}
+
+// updateRefundStatus updates the status of a refund in a transaction, recording the
+// RefundCompleted event in it when the refund completes
+func updateRefundStatus(ctx context.Context, tx *repository.Repository, refund *models.Refund, status models.RefundStatus) error {
 	updates := map[string]interface{}{
 		"status": status,
 	}
-	
+
+	now := time.Now()
 	if status == models.RefundStatusCompleted {
-		now := time.Now()
 		updates["completed_at"] = &now
 	}
 
-	if err := rs.db.Model(&models.Refund{}).Where("id = ?", refundID).Updates(updates).Error; err != nil {
+	if err := tx.Refund.UpdateRefund(ctx, refund.ID, updates); err != nil {
 		return err
 	}
-
-	// Emit event if completed
-	if status == models.RefundStatusCompleted && rs.publisher != nil {
-		var refund models.Refund
-		if err := rs.db.First(&refund, "id = ?", refundID).Error; err == nil {
-			event := events.RefundCompleted{
-				ID:                uuid.New().String(),
-				RefundID:          refund.ID.String(),
-				GoalID:            refund.GoalID.String(),
-				TotalRefundAmount: refund.TotalRefundAmount,
-				CompletedAt:       time.Now().Unix(),
-			}
-			rs.publisher.Publish("RefundCompleted", event)
-		}
+	if status != models.RefundStatusCompleted {
+		return nil
 	}
 
-	return nil
+	return recordEvent(ctx, tx, "RefundCompleted", events.RefundCompleted{
+		ID:                uuid.New().String(),
+		RefundID:          refund.ID.String(),
+		GoalID:            refund.GoalID.String(),
+		TotalRefundAmount: refund.TotalRefundAmount,
+		CompletedAt:       now.Unix(),
+	})
 }
 
-// UpdateDisbursementStatus updates the status of a refund disbursement
-func (rs *RefundService) UpdateDisbursementStatus(disbursementID uuid.UUID, status models.RefundStatus, ledgerTxID *uuid.UUID) error {
+// RecordDisbursementTransfer stores the payout transfer of a refund disbursement, and the
+// reason when it failed
+func (rs *RefundService) RecordDisbursementTransfer(ctx context.Context, disbursementID uuid.UUID, transferReference, transferCode, failureReason string) error {
 	updates := map[string]interface{}{
-		"status": status,
+		"transfer_reference": transferReference,
+		"failure_reason":     failureReason,
 	}
-	
-	if ledgerTxID != nil {
-		updates["ledger_transaction_id"] = ledgerTxID
+	if transferCode != "" {
+		updates["transfer_code"] = transferCode
 	}
-	
-	if status == models.RefundStatusCompleted {
+
+	return rs.repo.RefundDisbursement.UpdateDisbursement(ctx, disbursementID, updates)
+}
+
+// UpdateDisbursementStatus updates the status of a refund disbursement. Once every
+// disbursement of the refund is COMPLETED or FAILED, the refund itself is settled in the
+// same transaction.
+func (rs *RefundService) UpdateDisbursementStatus(ctx context.Context, disbursementID uuid.UUID, status models.RefundStatus, ledgerTxID *uuid.UUID) error {
+	return rs.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		// Lock the disbursement so a redelivered outcome waits for the first to commit
+		disbursement, err := tx.RefundDisbursement.GetDisbursementByIDForUpdate(ctx, disbursementID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.NotFound("refund_disbursement_not_found", "refund disbursement not found")
+			}
+			return err
+		}
+
+		// Redelivered outcomes must not emit ContributionRefunded twice
+		if disbursement.Status == status {
+			return nil
+		}
+
+		updates := map[string]interface{}{
+			"status": status,
+		}
+
+		if ledgerTxID != nil {
+			updates["ledger_transaction_id"] = ledgerTxID
+		}
+
 		now := time.Now()
-		updates["completed_at"] = &now
-	}
+		if status == models.RefundStatusCompleted {
+			updates["completed_at"] = &now
+		}
 
-	if err := rs.db.Model(&models.RefundDisbursement{}).Where("id = ?", disbursementID).Updates(updates).Error; err != nil {
-		return err
-	}
+		if err := tx.RefundDisbursement.UpdateDisbursement(ctx, disbursementID, updates); err != nil {
+			return err
+		}
+		// A contribution is REFUNDED once completed disbursements cover all of it
+		if status == models.RefundStatusCompleted {
+			if err := tx.RefundDisbursement.MarkContributionRefunded(ctx, disbursement.ContributionID); err != nil {
+				return err
+			}
+		}
+		// Completed disbursements come off the goal's funding totals
+		if err := tx.Goal.RecountTotals(ctx, disbursement.Refund.GoalID); err != nil {
+			return err
+		}
 
-	// Emit event if completed
-	if status == models.RefundStatusCompleted && rs.publisher != nil {
-		var disbursement models.RefundDisbursement
-		if err := rs.db.Preload("Refund").First(&disbursement, "id = ?", disbursementID).Error; err == nil {
+		if status == models.RefundStatusCompleted {
 			event := events.ContributionRefunded{
 				ID:             uuid.New().String(),
 				ContributionID: disbursement.ContributionID.String(),
 				UserID:         disbursement.UserID.String(),
 				GoalID:         disbursement.Refund.GoalID.String(),
 				RefundAmount:   disbursement.Amount,
-				CreatedAt:      time.Now().Unix(),
+				CreatedAt:      now.Unix(),
+			}
+			if err := recordEvent(ctx, tx, "ContributionRefunded", event); err != nil {
+				return err
 			}
-			rs.publisher.Publish("ContributionRefunded", event)
+		}
+
+		return settleRefund(ctx, tx, disbursement.RefundID)
+	})
+}
+
+// settleRefund completes a refund whose disbursements all completed, or fails it once
+// every disbursement is terminal and at least one failed. The refund is locked, so when
+// two of its disbursements finish at once the second to commit sees the first.
+func settleRefund(ctx context.Context, tx *repository.Repository, refundID uuid.UUID) error {
+	refund, err := tx.Refund.GetRefundByIDForUpdate(ctx, refundID)
+	if err != nil {
+		return err
+	}
+	if refund.Status == models.RefundStatusCompleted || refund.Status == models.RefundStatusFailed {
+		return nil
+	}
+
+	status := models.RefundStatusCompleted
+	for _, disbursement := range refund.Disbursements {
+		switch disbursement.Status {
+		case models.RefundStatusCompleted:
+		case models.RefundStatusFailed:
+			status = models.RefundStatusFailed
+		default:
+			return nil // still in flight
 		}
 	}
 
-	return nil
+	return updateRefundStatus(ctx, tx, refund, status)
 }
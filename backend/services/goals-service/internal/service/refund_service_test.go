@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// TestRefundSettlesWithLaggingReplica pays out a refund while plain reads go to a replica
+// that has the goal and its contributions but none of the refund, as right after it was
+// initiated. Recording the payouts must read the refund from the primary.
+func TestRefundSettlesWithLaggingReplica(t *testing.T) {
+	primary, replica := testdb.OpenReplicated(t)
+	repo := repository.NewRepository(primary)
+	ctx := context.Background()
+
+	goal := createTestGoal(t, repo, uuid.New())
+	contributions := []*models.Contribution{
+		createTestContribution(t, repo, goal, uuid.New(), 40_000),
+		createTestContribution(t, repo, goal, uuid.New(), 60_000),
+	}
+	setGoalStatus(t, repo, goal, models.GoalStatusClosed)
+
+	// The goal and contributions have reached the replica
+	if err := replica.Create(goal).Error; err != nil {
+		t.Fatalf("failed to replicate goal: %v", err)
+	}
+	for _, contribution := range contributions {
+		if err := replica.Create(contribution).Error; err != nil {
+			t.Fatalf("failed to replicate contribution: %v", err)
+		}
+	}
+
+	refunds := NewRefundService(repo, newTestUsersClient(t), nil, time.Hour)
+	refund, err := refunds.InitiateRefund(ctx, goal.OwnerID, &dto.InitiateRefundRequest{
+		GoalID:           goal.ID.String(),
+		RefundPercentage: 100,
+	})
+	if err != nil {
+		t.Fatalf("InitiateRefund: %v", err)
+	}
+	if len(refund.Disbursements) != 2 {
+		t.Fatalf("disbursements = %d, want 2", len(refund.Disbursements))
+	}
+
+	for _, disbursement := range refund.Disbursements {
+		if err := refunds.UpdateDisbursementStatus(ctx, disbursement.ID, models.RefundStatusCompleted, nil); err != nil {
+			t.Fatalf("UpdateDisbursementStatus: %v", err)
+		}
+	}
+
+	settled, err := repo.Primary().Refund.GetRefundByID(ctx, refund.ID)
+	if err != nil {
+		t.Fatalf("GetRefundByID: %v", err)
+	}
+	if settled.Status != models.RefundStatusCompleted {
+		t.Errorf("refund status = %s, want %s", settled.Status, models.RefundStatusCompleted)
+	}
+	for _, contribution := range contributions {
+		refunded, err := repo.Primary().Contribution.GetContributionByID(ctx, contribution.ID)
+		if err != nil {
+			t.Fatalf("GetContributionByID: %v", err)
+		}
+		if refunded.Status != models.ContributionStatusRefunded {
+			t.Errorf("contribution %s status = %s, want %s", contribution.ID, refunded.Status, models.ContributionStatusRefunded)
+		}
+	}
+}
+
+// TestSequentialPartialRefunds refunds a goal in parts; the refunds together may never
+// pay back more than each contribution
+func TestSequentialPartialRefunds(t *testing.T) {
+	f := newWithdrawalRefundFixture(t)
+
+	first, err := f.refund(60)
+	if err != nil {
+		t.Fatalf("first refund of 60%%: %v", err)
+	}
+	f.completeDisbursements(t, first)
+
+	_, err = f.refund(60)
+	if code := errorCode(err); code != "refund_exceeds_remaining" {
+		t.Fatalf("second refund of 60%%: err = %v, want refund_exceeds_remaining", err)
+	}
+	if !strings.Contains(err.Error(), "only 40% remaining refundable") {
+		t.Errorf("error %q does not say how much is left", err)
+	}
+
+	second, err := f.refund(40)
+	if err != nil {
+		t.Fatalf("refund of the remaining 40%%: %v", err)
+	}
+	for _, disbursement := range second.Disbursements {
+		if disbursement.Amount != 40_000 {
+			t.Errorf("second disbursement = %d, want 40000", disbursement.Amount)
+		}
+	}
+	f.completeDisbursements(t, second)
+
+	_, err = f.refund(1)
+	if code := errorCode(err); code != "fully_refunded" {
+		t.Fatalf("refund after 100%%: err = %v, want fully_refunded", err)
+	}
+
+	refunds, err := f.refunds.GetGoalRefunds(context.Background(), f.goal.ID)
+	if err != nil {
+		t.Fatalf("GetGoalRefunds: %v", err)
+	}
+	paid := make(map[uuid.UUID]int64)
+	for _, refund := range refunds {
+		for _, disbursement := range refund.Disbursements {
+			paid[disbursement.ContributionID] += disbursement.Amount
+		}
+	}
+	for contributionID, amount := range paid {
+		if amount != 100_000 {
+			t.Errorf("contribution %s refunded %d in total, want exactly 100000", contributionID, amount)
+		}
+	}
+}
+
+// TestFailedDisbursementsFreeTheirShare lets a refund whose payouts failed be retried in full
+func TestFailedDisbursementsFreeTheirShare(t *testing.T) {
+	f := newWithdrawalRefundFixture(t)
+
+	failed, err := f.refund(60)
+	if err != nil {
+		t.Fatalf("refund of 60%%: %v", err)
+	}
+	for _, disbursement := range failed.Disbursements {
+		if err := f.refunds.RecordDisbursementTransfer(context.Background(), disbursement.ID, "", "", "account closed"); err != nil {
+			t.Fatalf("RecordDisbursementTransfer: %v", err)
+		}
+		if err := f.refunds.UpdateDisbursementStatus(context.Background(), disbursement.ID, models.RefundStatusFailed, nil); err != nil {
+			t.Fatalf("UpdateDisbursementStatus: %v", err)
+		}
+	}
+
+	if _, err := f.refund(100); err != nil {
+		t.Fatalf("refund of 100%% after the first one failed: %v", err)
+	}
+}
+
+func TestRemainingRefundablePercent(t *testing.T) {
+	a := models.Contribution{ID: uuid.New(), Amount: 100_000}
+	b := models.Contribution{ID: uuid.New(), Amount: 30_000}
+	empty := models.Contribution{ID: uuid.New(), Amount: 0}
+
+	tests := []struct {
+		name     string
+		refunded map[uuid.UUID]int64
+		want     float64
+	}{
+		{"nothing refunded", nil, 100},
+		{"one partial refund", map[uuid.UUID]int64{a.ID: 60_000, b.ID: 18_000}, 40},
+		{"the most refunded contribution limits the rest", map[uuid.UUID]int64{a.ID: 10_000, b.ID: 27_000}, 10},
+		{"fractions round down", map[uuid.UUID]int64{b.ID: 10_000}, 66.66},
+		{"fully refunded", map[uuid.UUID]int64{a.ID: 100_000}, 0},
+		{"over-refunded legacy data", map[uuid.UUID]int64{a.ID: 120_000}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := remainingRefundablePercent([]models.Contribution{a, b, empty}, tt.refunded); got != tt.want {
+				t.Errorf("remainingRefundablePercent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
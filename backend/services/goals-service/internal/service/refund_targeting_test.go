@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+func TestComputeDisbursementPlanTargets(t *testing.T) {
+	goal := &models.Goal{ID: uuid.New(), Currency: "NGN"}
+	ada, obi := uuid.New(), uuid.New()
+	contribution := func(userID uuid.UUID, amount int64) models.Contribution {
+		return models.Contribution{ID: uuid.New(), GoalID: goal.ID, UserID: userID, Amount: amount, Currency: "NGN", Status: models.ContributionStatusConfirmed}
+	}
+	adaFirst, adaSecond, obiOnly := contribution(ada, 100_000), contribution(ada, 50_000), contribution(obi, 200_000)
+	contributions := []models.Contribution{adaFirst, adaSecond, obiOnly}
+
+	tests := []struct {
+		name      string
+		refunded  map[uuid.UUID]int64
+		req       dto.InitiateRefundRequest
+		wantScope string
+		want      map[uuid.UUID]int64 // disbursement amount per contribution
+		wantCode  string
+	}{
+		{
+			name:      "whole goal",
+			req:       dto.InitiateRefundRequest{RefundPercentage: 50},
+			wantScope: dto.RefundScopeGoal,
+			want:      map[uuid.UUID]int64{adaFirst.ID: 50_000, adaSecond.ID: 25_000, obiOnly.ID: 100_000},
+		},
+		{
+			name:      "one contribution",
+			req:       dto.InitiateRefundRequest{RefundPercentage: 100, ContributionIDs: []string{adaSecond.ID.String()}},
+			wantScope: dto.RefundScopeTargeted,
+			want:      map[uuid.UUID]int64{adaSecond.ID: 50_000},
+		},
+		{
+			name:      "every contribution of a user",
+			req:       dto.InitiateRefundRequest{RefundPercentage: 100, UserIDs: []string{ada.String()}},
+			wantScope: dto.RefundScopeTargeted,
+			want:      map[uuid.UUID]int64{adaFirst.ID: 100_000, adaSecond.ID: 50_000},
+		},
+		{
+			name:      "a contribution and a user",
+			req:       dto.InitiateRefundRequest{RefundPercentage: 10, ContributionIDs: []string{adaFirst.ID.String()}, UserIDs: []string{obi.String()}},
+			wantScope: dto.RefundScopeTargeted,
+			want:      map[uuid.UUID]int64{adaFirst.ID: 10_000, obiOnly.ID: 20_000},
+		},
+		{
+			name:      "repeated target is capped at what is left",
+			refunded:  map[uuid.UUID]int64{adaFirst.ID: 60_000},
+			req:       dto.InitiateRefundRequest{RefundPercentage: 40, ContributionIDs: []string{adaFirst.ID.String()}},
+			wantScope: dto.RefundScopeTargeted,
+			want:      map[uuid.UUID]int64{adaFirst.ID: 40_000},
+		},
+		{
+			name:     "repeated target beyond what is left",
+			refunded: map[uuid.UUID]int64{adaFirst.ID: 60_000},
+			req:      dto.InitiateRefundRequest{RefundPercentage: 50, ContributionIDs: []string{adaFirst.ID.String()}},
+			wantCode: "refund_exceeds_remaining",
+		},
+		{
+			name:     "fully refunded target",
+			refunded: map[uuid.UUID]int64{adaFirst.ID: 100_000},
+			req:      dto.InitiateRefundRequest{RefundPercentage: 10, ContributionIDs: []string{adaFirst.ID.String()}},
+			wantCode: "fully_refunded",
+		},
+		{
+			name:     "user whose contributions are fully refunded",
+			refunded: map[uuid.UUID]int64{obiOnly.ID: 200_000},
+			req:      dto.InitiateRefundRequest{RefundPercentage: 10, UserIDs: []string{obi.String()}},
+			wantCode: "fully_refunded",
+		},
+		{
+			name:      "whole goal after a targeted refund skips the refunded contribution",
+			refunded:  map[uuid.UUID]int64{adaSecond.ID: 50_000},
+			req:       dto.InitiateRefundRequest{RefundPercentage: 100},
+			wantScope: dto.RefundScopeGoal,
+			want:      map[uuid.UUID]int64{adaFirst.ID: 100_000, obiOnly.ID: 200_000},
+		},
+		{
+			name:     "contribution of another goal",
+			req:      dto.InitiateRefundRequest{RefundPercentage: 10, ContributionIDs: []string{uuid.NewString()}},
+			wantCode: "invalid_refund_target",
+		},
+		{
+			name:     "user who did not contribute",
+			req:      dto.InitiateRefundRequest{RefundPercentage: 10, UserIDs: []string{uuid.NewString()}},
+			wantCode: "invalid_refund_target",
+		},
+		{
+			name:     "malformed contribution ID",
+			req:      dto.InitiateRefundRequest{RefundPercentage: 10, ContributionIDs: []string{"not-a-uuid"}},
+			wantCode: "invalid_contribution_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refunded := tt.refunded
+			if refunded == nil {
+				refunded = map[uuid.UUID]int64{}
+			}
+			plan, err := computeDisbursementPlan(goal, contributions, &refundHistory{Refunded: refunded}, &tt.req)
+			if tt.wantCode != "" {
+				if code := errorCode(err); code != tt.wantCode {
+					t.Fatalf("err = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("computeDisbursementPlan: %v", err)
+			}
+
+			if plan.Scope != tt.wantScope {
+				t.Errorf("scope = %s, want %s", plan.Scope, tt.wantScope)
+			}
+			got := make(map[uuid.UUID]int64, len(plan.Disbursements))
+			var total int64
+			for _, planned := range plan.Disbursements {
+				got[planned.ContributionID] = planned.Amount
+				total += planned.Amount
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("disbursements = %v, want %v", got, tt.want)
+			}
+			for id, amount := range tt.want {
+				if got[id] != amount {
+					t.Errorf("disbursement for %s = %d, want %d", id, got[id], amount)
+				}
+			}
+			if plan.TotalRefundAmount != total {
+				t.Errorf("total = %d, want the sum of the disbursements %d", plan.TotalRefundAmount, total)
+			}
+		})
+	}
+}
+
+// TestTargetedRefunds refunds one contributor of the fixture's two in full, tries to
+// refund them again, then refunds the rest of the goal
+func TestTargetedRefunds(t *testing.T) {
+	f := newWithdrawalRefundFixture(t)
+	ctx := context.Background()
+
+	contributions, err := f.repo.Contribution.GetConfirmedContributionsByGoalID(ctx, f.goal.ID)
+	if err != nil {
+		t.Fatalf("GetConfirmedContributionsByGoalID: %v", err)
+	}
+	target, other := contributions[0], contributions[1]
+	targeted := func(percentage float64) (*models.Refund, error) {
+		return f.refunds.InitiateRefund(ctx, f.goal.OwnerID, &dto.InitiateRefundRequest{
+			GoalID:           f.goal.ID.String(),
+			RefundPercentage: percentage,
+			Reason:           "duplicate payment",
+			ContributionIDs:  []string{target.ID.String()},
+		})
+	}
+
+	refund, err := targeted(100)
+	if err != nil {
+		t.Fatalf("targeted InitiateRefund: %v", err)
+	}
+	if len(refund.Disbursements) != 1 || refund.Disbursements[0].ContributionID != target.ID || refund.TotalRefundAmount != target.Amount {
+		t.Fatalf("targeted refund = %d in %d disbursements, want %d to contribution %s", refund.TotalRefundAmount, len(refund.Disbursements), target.Amount, target.ID)
+	}
+	stored, err := f.repo.Refund.GetRefundByID(ctx, refund.ID)
+	if err != nil {
+		t.Fatalf("GetRefundByID: %v", err)
+	}
+	if stored.Metadata["scope"] != dto.RefundScopeTargeted {
+		t.Errorf("scope = %v, want %s", stored.Metadata["scope"], dto.RefundScopeTargeted)
+	}
+	if ids, _ := stored.Metadata["contribution_ids"].([]interface{}); len(ids) != 1 || ids[0] != target.ID.String() {
+		t.Errorf("contribution_ids = %v, want [%s]", stored.Metadata["contribution_ids"], target.ID)
+	}
+	f.completeDisbursements(t, refund)
+
+	if _, err := targeted(10); errorCode(err) != "fully_refunded" {
+		t.Fatalf("repeated targeted refund: err = %v, want fully_refunded", err)
+	}
+
+	// The rest of the goal is refunded without touching the contribution refunded in full
+	rest, err := f.refund(100)
+	if err != nil {
+		t.Fatalf("whole-goal InitiateRefund: %v", err)
+	}
+	if len(rest.Disbursements) != 1 || rest.Disbursements[0].ContributionID != other.ID || rest.TotalRefundAmount != other.Amount {
+		t.Errorf("whole-goal refund = %d in %d disbursements, want %d to contribution %s", rest.TotalRefundAmount, len(rest.Disbursements), other.Amount, other.ID)
+	}
+	if rest.Metadata["scope"] != dto.RefundScopeGoal {
+		t.Errorf("scope = %v, want %s", rest.Metadata["scope"], dto.RefundScopeGoal)
+	}
+}
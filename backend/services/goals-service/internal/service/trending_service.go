@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultTrendingLimit = 10
+	maxTrendingLimit     = 50
+)
+
+// TrendingWeights configures the trending score
+type TrendingWeights struct {
+	Window            time.Duration // contributions older than this are ignored
+	HalfLife          time.Duration // a contribution's weight halves every HalfLife
+	AmountWeight      float64
+	ContributorWeight float64
+}
+
+// TrendingContribution is a single confirmed contribution used for scoring
+type TrendingContribution struct {
+	UserID      uuid.UUID
+	Amount      int64
+	ConfirmedAt time.Time
+}
+
+// ScoreTrending computes a goal's trending score from its recent confirmed contributions.
+// Each contribution is weighted by exp(-ln2 * age / HalfLife). The decayed amount is divided
+// by the goal target so small goals can trend, and each contributor counts once at the
+// weight of their most recent contribution.
+func ScoreTrending(contributions []TrendingContribution, targetAmount int64, now time.Time, w TrendingWeights) float64 {
+	if w.HalfLife <= 0 {
+		return 0
+	}
+
+	var decayedAmount float64
+	contributorDecay := make(map[uuid.UUID]float64)
+
+	for _, c := range contributions {
+		age := now.Sub(c.ConfirmedAt)
+		if age < 0 {
+			age = 0
+		}
+		if w.Window > 0 && age > w.Window {
+			continue
+		}
+
+		decay := math.Exp(-math.Ln2 * age.Hours() / w.HalfLife.Hours())
+		decayedAmount += float64(c.Amount) * decay
+		if decay > contributorDecay[c.UserID] {
+			contributorDecay[c.UserID] = decay
+		}
+	}
+
+	var decayedContributors float64
+	for _, decay := range contributorDecay {
+		decayedContributors += decay
+	}
+
+	target := float64(targetAmount)
+	if target < 1 {
+		target = 1
+	}
+
+	return w.AmountWeight*(decayedAmount/target) + w.ContributorWeight*math.Log1p(decayedContributors)
+}
+
+// TrendingService computes and serves trending goals
+type TrendingService struct {
+	repo         *repository.Repository
+	trendingRepo *repository.TrendingRepository
+	weights      TrendingWeights
+	topN         int
+}
+
+// NewTrendingService creates a new trending service
+func NewTrendingService(repo *repository.Repository, trendingRepo *repository.TrendingRepository, weights TrendingWeights, topN int) *TrendingService {
+	return &TrendingService{
+		repo:         repo,
+		trendingRepo: trendingRepo,
+		weights:      weights,
+		topN:         topN,
+	}
+}
+
+// Run recomputes trending scores every interval until ctx is cancelled
+func (s *TrendingService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+
+	if err := s.Recompute(ctx, time.Now()); err != nil {
+		log.Printf("Failed to compute trending goals: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Recompute(ctx, time.Now()); err != nil {
+				log.Printf("Failed to compute trending goals: %v", err)
+			}
+		}
+	}
+}
+
+// Recompute scores eligible goals and stores the top N
+func (s *TrendingService) Recompute(ctx context.Context, now time.Time) error {
+	start := time.Now()
+
+	candidates, err := s.trendingRepo.GetCandidateContributions(ctx, now.Add(-s.weights.Window))
+	if err != nil {
+		return err
+	}
+
+	contributionsByGoal := make(map[uuid.UUID][]TrendingContribution)
+	targets := make(map[uuid.UUID]int64)
+	for _, c := range candidates {
+		contributionsByGoal[c.GoalID] = append(contributionsByGoal[c.GoalID], TrendingContribution{
+			UserID:      c.UserID,
+			Amount:      c.Amount,
+			ConfirmedAt: c.ConfirmedAt,
+		})
+		targets[c.GoalID] = c.TargetAmount
+	}
+
+	scores := make([]models.GoalTrending, 0, len(contributionsByGoal))
+	for goalID, contributions := range contributionsByGoal {
+		score := ScoreTrending(contributions, targets[goalID], now, s.weights)
+		if score <= 0 {
+			continue
+		}
+		scores = append(scores, models.GoalTrending{GoalID: goalID, Score: score, ComputedAt: now})
+	}
+
+	sort.Slice(scores, func(i, j int) bool { return scores[i].Score > scores[j].Score })
+	if s.topN > 0 && len(scores) > s.topN {
+		scores = scores[:s.topN]
+	}
+	for i := range scores {
+		scores[i].Rank = i + 1
+	}
+
+	if err := s.trendingRepo.ReplaceTrending(ctx, scores); err != nil {
+		return err
+	}
+
+	metrics.RecordGauge("goals.trending.count", float64(len(scores)))
+	metrics.RecordDuration("goals.trending.compute.duration", start)
+	return nil
+}
+
+// GetTrendingGoals returns the stored trending goals, falling back to the most recently
+// active goals when no scores have been computed yet
+func (s *TrendingService) GetTrendingGoals(ctx context.Context, limit int) (*dto.TrendingGoals, error) {
+	if limit <= 0 {
+		limit = defaultTrendingLimit
+	}
+	if limit > maxTrendingLimit {
+		limit = maxTrendingLimit
+	}
+
+	scores, err := s.trendingRepo.GetTrending(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.TrendingGoals{Goals: []dto.TrendingGoal{}}
+
+	if len(scores) == 0 {
+		goals, err := s.trendingRepo.GetRecentlyActiveGoals(ctx, limit)
+		if err != nil {
+			return nil, err
+		}
+		for i, goal := range goals {
+			result.Goals = append(result.Goals, s.enrich(ctx, goal, 0, i+1))
+		}
+		return result, nil
+	}
+
+	computedAt := scores[0].ComputedAt
+	result.ComputedAt = &computedAt
+
+	for _, score := range scores {
+		goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, score.GoalID)
+		if err != nil {
+			// Goal deleted since the last computation
+			continue
+		}
+		result.Goals = append(result.Goals, s.enrich(ctx, *goal, score.Score, score.Rank))
+	}
+
+	return result, nil
+}
+
+// enrich attaches funding progress to a trending goal, hiding its bank details
+func (s *TrendingService) enrich(ctx context.Context, goal models.Goal, score float64, rank int) dto.TrendingGoal {
+	hideBankDetails(&goal, uuid.Nil)
+	totalContributions, _ := s.repo.Goal.GetTotalConfirmedContributions(ctx, goal.ID)
+	contributorCount, _ := s.repo.Goal.GetContributorCount(ctx, goal.ID)
+
+	return dto.TrendingGoal{
+		Goal:               goal,
+		Score:              score,
+		Rank:               rank,
+		TotalContributions: totalContributions,
+		ContributorCount:   contributorCount,
+		ProgressPercent:    calculatePercent(totalContributions, goal.TargetAmount),
+	}
+}
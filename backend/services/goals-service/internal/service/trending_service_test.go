@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/goals-service/internal/testdb"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+var testTrendingWeights = TrendingWeights{
+	Window:            72 * time.Hour,
+	HalfLife:          24 * time.Hour,
+	AmountWeight:      1,
+	ContributorWeight: 1,
+}
+
+func TestScoreTrending(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	alice, bob := uuid.New(), uuid.New()
+	amountOnly := TrendingWeights{Window: 72 * time.Hour, HalfLife: 24 * time.Hour, AmountWeight: 1}
+	contributorsOnly := TrendingWeights{Window: 72 * time.Hour, HalfLife: 24 * time.Hour, ContributorWeight: 1}
+
+	tests := []struct {
+		name          string
+		contributions []TrendingContribution
+		target        int64
+		weights       TrendingWeights
+		want          float64
+	}{
+		{
+			name:          "fresh contribution counts in full",
+			contributions: []TrendingContribution{{UserID: alice, Amount: 100_000, ConfirmedAt: now}},
+			target:        1_000_000,
+			weights:       amountOnly,
+			want:          0.1,
+		},
+		{
+			name:          "weight halves every half-life",
+			contributions: []TrendingContribution{{UserID: alice, Amount: 100_000, ConfirmedAt: now.Add(-24 * time.Hour)}},
+			target:        1_000_000,
+			weights:       amountOnly,
+			want:          0.05,
+		},
+		{
+			name:          "two half-lives quarter the weight",
+			contributions: []TrendingContribution{{UserID: alice, Amount: 100_000, ConfirmedAt: now.Add(-48 * time.Hour)}},
+			target:        1_000_000,
+			weights:       amountOnly,
+			want:          0.025,
+		},
+		{
+			name:          "contributions outside the window are ignored",
+			contributions: []TrendingContribution{{UserID: alice, Amount: 100_000, ConfirmedAt: now.Add(-73 * time.Hour)}},
+			target:        1_000_000,
+			weights:       testTrendingWeights,
+			want:          0,
+		},
+		{
+			name:          "a future timestamp counts as now",
+			contributions: []TrendingContribution{{UserID: alice, Amount: 100_000, ConfirmedAt: now.Add(time.Hour)}},
+			target:        1_000_000,
+			weights:       amountOnly,
+			want:          0.1,
+		},
+		{
+			name:          "amount is normalised by the target so small goals can trend",
+			contributions: []TrendingContribution{{UserID: alice, Amount: 10_000, ConfirmedAt: now}},
+			target:        100_000,
+			weights:       amountOnly,
+			want:          0.1,
+		},
+		{
+			name:          "a zero target does not divide by zero",
+			contributions: []TrendingContribution{{UserID: alice, Amount: 5, ConfirmedAt: now}},
+			target:        0,
+			weights:       amountOnly,
+			want:          5,
+		},
+		{
+			name: "a contributor counts once at their most recent weight",
+			contributions: []TrendingContribution{
+				{UserID: alice, Amount: 1, ConfirmedAt: now.Add(-48 * time.Hour)},
+				{UserID: alice, Amount: 1, ConfirmedAt: now.Add(-24 * time.Hour)},
+			},
+			target:  1_000_000,
+			weights: contributorsOnly,
+			want:    math.Log1p(0.5),
+		},
+		{
+			name: "each contributor adds to the log-scaled term",
+			contributions: []TrendingContribution{
+				{UserID: alice, Amount: 1, ConfirmedAt: now},
+				{UserID: bob, Amount: 1, ConfirmedAt: now},
+			},
+			target:  1_000_000,
+			weights: contributorsOnly,
+			want:    math.Log1p(2),
+		},
+		{
+			name: "weights scale their terms",
+			contributions: []TrendingContribution{
+				{UserID: alice, Amount: 100_000, ConfirmedAt: now},
+			},
+			target:  1_000_000,
+			weights: TrendingWeights{Window: 72 * time.Hour, HalfLife: 24 * time.Hour, AmountWeight: 2, ContributorWeight: 3},
+			want:    2*0.1 + 3*math.Log1p(1),
+		},
+		{
+			name:          "no contributions score zero",
+			contributions: nil,
+			target:        1_000_000,
+			weights:       testTrendingWeights,
+			want:          0,
+		},
+		{
+			name:          "a zero half-life scores zero",
+			contributions: []TrendingContribution{{UserID: alice, Amount: 100_000, ConfirmedAt: now}},
+			target:        1_000_000,
+			weights:       TrendingWeights{AmountWeight: 1, ContributorWeight: 1},
+			want:          0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScoreTrending(tt.contributions, tt.target, now, tt.weights)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ScoreTrending = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreTrendingFavoursRecentMomentum(t *testing.T) {
+	now := time.Now()
+	var recent, stale []TrendingContribution
+	for i := 0; i < 3; i++ {
+		recent = append(recent, TrendingContribution{UserID: uuid.New(), Amount: 50_000, ConfirmedAt: now.Add(-time.Hour)})
+		stale = append(stale, TrendingContribution{UserID: uuid.New(), Amount: 50_000, ConfirmedAt: now.Add(-60 * time.Hour)})
+	}
+
+	if r, s := ScoreTrending(recent, 1_000_000, now, testTrendingWeights), ScoreTrending(stale, 1_000_000, now, testTrendingWeights); r <= s {
+		t.Errorf("recent score %v is not above stale score %v for the same money", r, s)
+	}
+}
+
+func newTestTrendingService(t *testing.T) (*TrendingService, *repository.Repository) {
+	t.Helper()
+	db := testdb.Open(t)
+	repo := repository.NewRepository(db)
+	return NewTrendingService(repo, repository.NewTrendingRepository(db), testTrendingWeights, 10), repo
+}
+
+func TestRecomputeTrendingExcludesIneligibleGoals(t *testing.T) {
+	s, repo := newTestTrendingService(t)
+	goals := NewGoalService(repo, nil, nil, nil, NewInviteTokens("secret", time.Hour))
+	ctx := context.Background()
+
+	eligible := createTestGoal(t, repo, uuid.New())
+	suspended := createTestGoal(t, repo, uuid.New())
+	closed := createTestGoal(t, repo, uuid.New())
+	unlisted := createTestGoal(t, repo, uuid.New(), func(g *models.Goal) {
+		g.Visibility = models.GoalVisibilityUnlisted
+		g.IsPublic = false
+	})
+	private := createTestGoal(t, repo, uuid.New(), func(g *models.Goal) {
+		g.Visibility = models.GoalVisibilityPrivate
+		g.IsPublic = false
+	})
+	for _, goal := range []*models.Goal{eligible, suspended, closed, unlisted, private} {
+		createTestContribution(t, repo, goal, uuid.New(), 200_000)
+	}
+	if _, err := goals.SuspendGoal(ctx, suspended.ID, uuid.New(), "reported as fraudulent"); err != nil {
+		t.Fatalf("SuspendGoal: %v", err)
+	}
+	setGoalStatus(t, repo, closed, models.GoalStatusClosed)
+
+	if err := s.Recompute(ctx, time.Now()); err != nil {
+		t.Fatalf("Recompute: %v", err)
+	}
+	trending, err := s.GetTrendingGoals(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetTrendingGoals: %v", err)
+	}
+	if trending.ComputedAt == nil {
+		t.Error("ComputedAt is unset for a computed ranking")
+	}
+	if len(trending.Goals) != 1 || trending.Goals[0].Goal.ID != eligible.ID {
+		ids := make([]uuid.UUID, len(trending.Goals))
+		for i, g := range trending.Goals {
+			ids[i] = g.Goal.ID
+		}
+		t.Fatalf("trending goals = %v, want only the open public goal %s", ids, eligible.ID)
+	}
+	if got := trending.Goals[0]; got.Rank != 1 || got.Score <= 0 || got.TotalContributions != 200_000 {
+		t.Errorf("trending entry: rank %d, score %v, total %d; want rank 1, a positive score and 200000", got.Rank, got.Score, got.TotalContributions)
+	}
+}
+
+func TestTrendingGoalsDropGoalsSuspendedSinceComputation(t *testing.T) {
+	s, repo := newTestTrendingService(t)
+	goals := NewGoalService(repo, nil, nil, nil, NewInviteTokens("secret", time.Hour))
+	ctx := context.Background()
+
+	first := createTestGoal(t, repo, uuid.New())
+	second := createTestGoal(t, repo, uuid.New())
+	createTestContribution(t, repo, first, uuid.New(), 500_000)
+	createTestContribution(t, repo, second, uuid.New(), 100_000)
+	if err := s.Recompute(ctx, time.Now()); err != nil {
+		t.Fatalf("Recompute: %v", err)
+	}
+
+	if _, err := goals.SuspendGoal(ctx, first.ID, uuid.New(), "under review"); err != nil {
+		t.Fatalf("SuspendGoal: %v", err)
+	}
+	trending, err := s.GetTrendingGoals(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetTrendingGoals: %v", err)
+	}
+	if len(trending.Goals) != 1 || trending.Goals[0].Goal.ID != second.ID {
+		t.Fatalf("got %d trending goals, want only %s once %s was suspended", len(trending.Goals), second.ID, first.ID)
+	}
+}
+
+func TestTrendingGoalsFallBackToRecentlyActive(t *testing.T) {
+	s, repo := newTestTrendingService(t)
+	ctx := context.Background()
+
+	older := createTestGoal(t, repo, uuid.New())
+	newer := createTestGoal(t, repo, uuid.New())
+	createTestContribution(t, repo, older, uuid.New(), 10_000)
+	time.Sleep(10 * time.Millisecond)
+	createTestContribution(t, repo, newer, uuid.New(), 10_000)
+	createTestGoal(t, repo, uuid.New(), func(g *models.Goal) {
+		g.Visibility = models.GoalVisibilityPrivate
+		g.IsPublic = false
+	})
+
+	trending, err := s.GetTrendingGoals(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetTrendingGoals: %v", err)
+	}
+	if trending.ComputedAt != nil {
+		t.Errorf("ComputedAt = %v for the fallback, want it unset", trending.ComputedAt)
+	}
+	if len(trending.Goals) != 2 || trending.Goals[0].Goal.ID != newer.ID || trending.Goals[1].Goal.ID != older.ID {
+		t.Fatalf("fallback goals are not the public goals by latest contribution: %+v", trending.Goals)
+	}
+	for _, g := range trending.Goals {
+		if g.Score != 0 {
+			t.Errorf("fallback goal %s has score %v, want 0", g.Goal.ID, g.Score)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/messaging"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxUpdateTitleLength = 255
+	maxUpdateBodyLength  = 10000
+	maxUpdateMediaURLs   = 10
+)
+
+var (
+	ErrInvalidGoalUpdate = apperrors.Validation("invalid_goal_update", "update needs a title of up to 255 characters and a body of up to 10000 characters")
+	ErrTooManyMediaURLs  = apperrors.Validation("too_many_media_urls", "an update can carry at most 10 media URLs")
+)
+
+// GoalUpdateService handles updates owners post to their goals' contributors
+type GoalUpdateService struct {
+	updates   *repository.GoalUpdateRepository
+	repo      *repository.Repository
+	publisher messaging.Publisher
+}
+
+// NewGoalUpdateService creates a new goal update service
+func NewGoalUpdateService(updates *repository.GoalUpdateRepository, repo *repository.Repository, publisher messaging.Publisher) *GoalUpdateService {
+	return &GoalUpdateService{updates: updates, repo: repo, publisher: publisher}
+}
+
+// PostUpdate publishes an update on a goal and notifies its contributors and watchers.
+// Only the owner may post.
+func (s *GoalUpdateService) PostUpdate(ctx context.Context, goalID, userID uuid.UUID, req dto.CreateGoalUpdateRequest) (*models.GoalUpdate, error) {
+	title := strings.TrimSpace(req.Title)
+	body := strings.TrimSpace(req.Body)
+	if title == "" || body == "" ||
+		utf8.RuneCountInString(title) > maxUpdateTitleLength ||
+		utf8.RuneCountInString(body) > maxUpdateBodyLength {
+		return nil, ErrInvalidGoalUpdate
+	}
+	if len(req.MediaURLs) > maxUpdateMediaURLs {
+		return nil, ErrTooManyMediaURLs
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if goal.OwnerID != userID {
+		return nil, ErrUnauthorized
+	}
+	if goal.Status == models.GoalStatusSuspended {
+		return nil, ErrGoalSuspended
+	}
+
+	update := &models.GoalUpdate{
+		GoalID:    goalID,
+		AuthorID:  userID,
+		Title:     title,
+		Body:      body,
+		MediaURLs: req.MediaURLs,
+	}
+	if err := s.updates.CreateUpdate(ctx, update); err != nil {
+		return nil, err
+	}
+
+	s.publishUpdatePosted(ctx, goal, update)
+
+	return update, nil
+}
+
+// publishUpdatePosted tells the goal's contributors and watchers about a new update
+func (s *GoalUpdateService) publishUpdatePosted(ctx context.Context, goal *models.Goal, update *models.GoalUpdate) {
+	if s.publisher == nil {
+		return
+	}
+
+	userIDs, err := s.repo.Goal.GetContributorIDs(ctx, goal.ID)
+	if err != nil {
+		log.Printf("Failed to fetch contributors for goal %s: %v", goal.ID, err)
+		return
+	}
+	watcherIDs, err := s.repo.Goal.GetWatcherIDs(ctx, goal.ID)
+	if err != nil {
+		log.Printf("Failed to fetch watchers for goal %s: %v", goal.ID, err)
+		return
+	}
+	if len(userIDs) == 0 && len(watcherIDs) == 0 {
+		return
+	}
+
+	event := events.GoalUpdatePosted{
+		ID:             uuid.New().String(),
+		GoalID:         goal.ID.String(),
+		UpdateID:       update.ID.String(),
+		Title:          goal.Title,
+		UpdateTitle:    update.Title,
+		ContributorIDs: make([]string, len(userIDs)),
+		WatcherIDs:     make([]string, len(watcherIDs)),
+		CreatedAt:      time.Now().Unix(),
+	}
+	for i, id := range userIDs {
+		event.ContributorIDs[i] = id.String()
+	}
+	for i, id := range watcherIDs {
+		event.WatcherIDs[i] = id.String()
+	}
+
+	if err := s.publisher.PublishContext(ctx, "GoalUpdatePosted", event); err != nil {
+		log.Printf("Failed to publish GoalUpdatePosted event: %v", err)
+	}
+}
+
+// ListUpdates returns a page of a goal's updates, newest first. Updates on a private goal
+// are visible only to its owner, collaborators and contributors.
+func (s *GoalUpdateService) ListUpdates(ctx context.Context, goalID, viewerID uuid.UUID, page, pageSize int) (*dto.GoalUpdatePage, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > maxFeedPageSize {
+		pageSize = maxFeedPageSize
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if err := checkGoalVisible(ctx, s.repo, goal, viewerID); err != nil {
+		return nil, err
+	}
+
+	updates, total, err := s.updates.GetUpdatesByGoalID(ctx, goalID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.GoalUpdatePage{
+		Items:    updates,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofund/shared/metrics"
+	"github.com/google/uuid"
+)
+
+// AnonymousDisplayName is shown when a user's name cannot be resolved
+const AnonymousDisplayName = "Anonymous"
+
+// UsersClient resolves user display names and contacts from users-service, caching results
+type UsersClient struct {
+	baseURL  string
+	client   *http.Client
+	cacheTTL time.Duration
+
+	mu       sync.RWMutex
+	cache    map[uuid.UUID]cachedDisplayName
+	contacts map[uuid.UUID]cachedContact
+}
+
+type cachedDisplayName struct {
+	name      string
+	expiresAt time.Time
+}
+
+// Contact is a user's real name and email
+type Contact struct {
+	Name  string
+	Email string
+}
+
+type cachedContact struct {
+	contact   Contact
+	expiresAt time.Time
+}
+
+// NewUsersClient creates a users-service client. An empty baseURL disables lookups
+// and every user resolves to AnonymousDisplayName.
+func NewUsersClient(baseURL string, cacheTTL time.Duration) *UsersClient {
+	return &UsersClient{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		client:   &http.Client{Timeout: 3 * time.Second},
+		cacheTTL: cacheTTL,
+		cache:    make(map[uuid.UUID]cachedDisplayName),
+		contacts: make(map[uuid.UUID]cachedContact),
+	}
+}
+
+// DisplayNames returns a display name for every given user. Names missing from the
+// cache are fetched in a single request; on failure they degrade to AnonymousDisplayName.
+func (uc *UsersClient) DisplayNames(userIDs []uuid.UUID) map[uuid.UUID]string {
+	names := make(map[uuid.UUID]string, len(userIDs))
+	var missing []uuid.UUID
+
+	now := time.Now()
+	uc.mu.RLock()
+	for _, id := range userIDs {
+		if _, seen := names[id]; seen {
+			continue
+		}
+		if cached, ok := uc.cache[id]; ok && now.Before(cached.expiresAt) {
+			names[id] = cached.name
+			continue
+		}
+		names[id] = AnonymousDisplayName
+		missing = append(missing, id)
+	}
+	uc.mu.RUnlock()
+
+	if len(missing) == 0 || uc.baseURL == "" {
+		return names
+	}
+
+	fetched, err := uc.fetchDisplayNames(missing)
+	if err != nil {
+		log.Printf("Failed to resolve display names from users-service: %v", err)
+		metrics.IncrementCounter("goals.users_client.error")
+		return names
+	}
+
+	uc.mu.Lock()
+	for _, id := range missing {
+		// Unknown users are cached as anonymous too, so they don't trigger a lookup every time
+		name, ok := fetched[id]
+		if !ok {
+			name = AnonymousDisplayName
+		}
+		names[id] = name
+		uc.cache[id] = cachedDisplayName{name: name, expiresAt: now.Add(uc.cacheTTL)}
+	}
+	uc.mu.Unlock()
+
+	return names
+}
+
+// fetchDisplayNames calls GET /internal/users/display-names on users-service
+func (uc *UsersClient) fetchDisplayNames(userIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/users/display-names?ids=%s", uc.baseURL, url.QueryEscape(strings.Join(ids, ",")))
+
+	start := time.Now()
+	resp, err := uc.client.Get(endpoint)
+	metrics.RecordDuration("goals.users_client.duration", start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Users []struct {
+			ID        uuid.UUID `json:"id"`
+			Username  string    `json:"username"`
+			FirstName string    `json:"first_name"`
+		} `json:"users"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	names := make(map[uuid.UUID]string, len(body.Users))
+	for _, user := range body.Users {
+		switch {
+		case user.FirstName != "":
+			names[user.ID] = user.FirstName
+		case user.Username != "":
+			names[user.ID] = user.Username
+		}
+	}
+	return names, nil
+}
+
+// Contacts returns the real name and email of the given users. Contacts missing from the
+// cache are fetched in a single request; users that cannot be resolved are left out.
+func (uc *UsersClient) Contacts(userIDs []uuid.UUID) map[uuid.UUID]Contact {
+	contacts := make(map[uuid.UUID]Contact, len(userIDs))
+	var missing []uuid.UUID
+
+	now := time.Now()
+	uc.mu.RLock()
+	for _, id := range userIDs {
+		if cached, ok := uc.contacts[id]; ok && now.Before(cached.expiresAt) {
+			contacts[id] = cached.contact
+			continue
+		}
+		missing = append(missing, id)
+	}
+	uc.mu.RUnlock()
+
+	if len(missing) == 0 || uc.baseURL == "" {
+		return contacts
+	}
+
+	fetched, err := uc.fetchContacts(missing)
+	if err != nil {
+		log.Printf("Failed to resolve contacts from users-service: %v", err)
+		metrics.IncrementCounter("goals.users_client.error")
+		return contacts
+	}
+
+	uc.mu.Lock()
+	for id, contact := range fetched {
+		contacts[id] = contact
+		uc.contacts[id] = cachedContact{contact: contact, expiresAt: now.Add(uc.cacheTTL)}
+	}
+	uc.mu.Unlock()
+
+	return contacts
+}
+
+// fetchContacts calls GET /internal/users/contacts on users-service
+func (uc *UsersClient) fetchContacts(userIDs []uuid.UUID) (map[uuid.UUID]Contact, error) {
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/users/contacts?ids=%s", uc.baseURL, url.QueryEscape(strings.Join(ids, ",")))
+
+	start := time.Now()
+	resp, err := uc.client.Get(endpoint)
+	metrics.RecordDuration("goals.users_client.duration", start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Users []struct {
+			ID        uuid.UUID `json:"id"`
+			FirstName string    `json:"first_name"`
+			LastName  string    `json:"last_name"`
+			Email     string    `json:"email"`
+		} `json:"users"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	contacts := make(map[uuid.UUID]Contact, len(body.Users))
+	for _, user := range body.Users {
+		contacts[user.ID] = Contact{
+			Name:  strings.TrimSpace(user.FirstName + " " + user.LastName),
+			Email: user.Email,
+		}
+	}
+	return contacts, nil
+}
+
+// usersBatchSize is the most user IDs users-service accepts in one internal lookup
+const usersBatchSize = 100
+
+// SettlementAccount is the bank account a user is paid out to
+type SettlementAccount struct {
+	BankName      string
+	AccountNumber string
+	AccountName   string
+}
+
+// SettlementAccounts returns the settlement accounts of the given users, fetched fresh
+// from users-service since they decide where money is sent. Users without an account or
+// unknown to users-service are left out. Unlike the name lookups, failures are returned.
+func (uc *UsersClient) SettlementAccounts(userIDs []uuid.UUID) (map[uuid.UUID]SettlementAccount, error) {
+	if uc.baseURL == "" {
+		return nil, fmt.Errorf("users-service URL not configured")
+	}
+
+	accounts := make(map[uuid.UUID]SettlementAccount, len(userIDs))
+	for start := 0; start < len(userIDs); start += usersBatchSize {
+		end := start + usersBatchSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+		if err := uc.fetchSettlementAccounts(userIDs[start:end], accounts); err != nil {
+			metrics.IncrementCounter("goals.users_client.error")
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+// fetchSettlementAccounts calls GET /internal/users/settlement-accounts on users-service
+// and adds the accounts found to accounts
+func (uc *UsersClient) fetchSettlementAccounts(userIDs []uuid.UUID, accounts map[uuid.UUID]SettlementAccount) error {
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/users/settlement-accounts?ids=%s", uc.baseURL, url.QueryEscape(strings.Join(ids, ",")))
+
+	start := time.Now()
+	resp, err := uc.client.Get(endpoint)
+	metrics.RecordDuration("goals.users_client.duration", start)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Users []struct {
+			ID                      uuid.UUID `json:"id"`
+			SettlementBankName      string    `json:"settlement_bank_name"`
+			SettlementAccountNumber string    `json:"settlement_account_number"`
+			SettlementAccountName   string    `json:"settlement_account_name"`
+		} `json:"users"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	for _, user := range body.Users {
+		if user.SettlementAccountNumber == "" {
+			continue
+		}
+		accounts[user.ID] = SettlementAccount{
+			BankName:      user.SettlementBankName,
+			AccountNumber: user.SettlementAccountNumber,
+			AccountName:   user.SettlementAccountName,
+		}
+	}
+	return nil
+}
+
+// KYCVerified reports whether a user is KYC verified, fetched fresh from users-service
+// since it gates withdrawals. Unlike the name lookups, failures are returned.
+func (uc *UsersClient) KYCVerified(userID uuid.UUID) (bool, error) {
+	if uc.baseURL == "" {
+		return false, fmt.Errorf("users-service URL not configured")
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/users/%s", uc.baseURL, url.PathEscape(userID.String()))
+
+	start := time.Now()
+	resp, err := uc.client.Get(endpoint)
+	metrics.RecordDuration("goals.users_client.duration", start)
+	if err != nil {
+		metrics.IncrementCounter("goals.users_client.error")
+		return false, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		metrics.IncrementCounter("goals.users_client.error")
+		return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var user struct {
+		KYCVerified bool `json:"kyc_verified"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return user.KYCVerified, nil
+}
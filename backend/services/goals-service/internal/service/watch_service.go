@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WatchService handles users' goal watch lists
+type WatchService struct {
+	watches *repository.GoalWatchRepository
+	repo    *repository.Repository
+}
+
+// NewWatchService creates a new watch service
+func NewWatchService(watches *repository.GoalWatchRepository, repo *repository.Repository) *WatchService {
+	return &WatchService{watches: watches, repo: repo}
+}
+
+// WatchGoal adds a goal to the user's watch list; watching a goal twice is a no-op. A
+// private goal can only be watched by its owner, collaborators and contributors.
+func (s *WatchService) WatchGoal(ctx context.Context, goalID, userID uuid.UUID) error {
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrGoalNotFound
+		}
+		return err
+	}
+	if err := checkGoalVisible(ctx, s.repo, goal, userID); err != nil {
+		return err
+	}
+
+	_, err = s.watches.Watch(ctx, userID, goalID)
+	return err
+}
+
+// UnwatchGoal removes a goal from the user's watch list. Unwatching a goal that is not on
+// the list is a no-op.
+func (s *WatchService) UnwatchGoal(ctx context.Context, goalID, userID uuid.UUID) error {
+	_, err := s.watches.Unwatch(ctx, userID, goalID)
+	return err
+}
+
+// ListWatchedGoals returns a page of the goals the user watches, most recently watched
+// first. Bank details are hidden on goals the user does not own.
+func (s *WatchService) ListWatchedGoals(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]models.Goal, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > maxFeedPageSize {
+		pageSize = maxFeedPageSize
+	}
+	offset := (page - 1) * pageSize
+	goals, total, err := s.watches.GetWatchedGoals(ctx, userID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range goals {
+		hideBankDetails(&goals[i], userID)
+	}
+	return goals, total, nil
+}
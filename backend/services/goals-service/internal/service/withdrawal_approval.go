@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ApproveWithdrawal records a collaborator's approval of a withdrawal awaiting approval.
+// Once the goal's required approvals are met the withdrawal moves to PENDING and is handed
+// to payments-service like any other; until then the remaining approvers are reminded.
+func (s *WithdrawalService) ApproveWithdrawal(ctx context.Context, withdrawalID, userID uuid.UUID) (*models.Withdrawal, error) {
+	var withdrawal *models.Withdrawal
+	var goal *models.Goal
+	released := false
+
+	// The row lock keeps two last approvals from both releasing the withdrawal
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		var err error
+		withdrawal, err = tx.Withdrawal.GetWithdrawalByIDForUpdate(ctx, withdrawalID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrWithdrawalNotFound
+			}
+			return err
+		}
+
+		goal, err = tx.Goal.GetGoalByIDSimple(ctx, withdrawal.GoalID)
+		if err != nil {
+			return err
+		}
+
+		approvers, err := goalApproverIDs(ctx, tx, goal)
+		if err != nil {
+			return err
+		}
+		if !containsUUID(approvers, userID) {
+			return ErrUnauthorized
+		}
+
+		if withdrawal.Status != models.WithdrawalStatusAwaitingApproval {
+			return ErrWithdrawalNotAwaitingApproval
+		}
+		if goal.Status == models.GoalStatusSuspended {
+			return ErrGoalSuspended
+		}
+		if goal.Status == models.GoalStatusCancelled {
+			return ErrInvalidGoalStatus
+		}
+		refundActive, err := tx.Goal.HasActiveRefund(ctx, goal.ID)
+		if err != nil {
+			return err
+		}
+		if refundActive {
+			return ErrRefundInProgress
+		}
+
+		approval := &models.WithdrawalApproval{WithdrawalID: withdrawal.ID, ApproverID: userID, ApprovedAt: time.Now()}
+		recorded, err := tx.Withdrawal.CreateApproval(ctx, approval)
+		if err != nil {
+			return err
+		}
+		if !recorded {
+			return ErrAlreadyApproved
+		}
+
+		if withdrawal.Approvals, err = tx.Withdrawal.GetApprovals(ctx, withdrawal.ID); err != nil {
+			return err
+		}
+		if len(withdrawal.Approvals) < goal.RequiredApprovals {
+			return recordApprovalRequested(ctx, tx, withdrawal, goal)
+		}
+		withdrawal.Status = models.WithdrawalStatusPending
+		released = true
+		if err := tx.Withdrawal.UpdateWithdrawal(ctx, withdrawal); err != nil {
+			return err
+		}
+		return recordWithdrawalRequested(ctx, tx, withdrawal, goal.Title)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditEntry{
+		GoalID:     withdrawal.GoalID,
+		ActorID:    userID,
+		Action:     models.AuditActionWithdrawalApproved,
+		EntityType: auditEntityWithdrawal,
+		EntityID:   withdrawal.ID,
+		After: map[string]interface{}{
+			"approvals":          len(withdrawal.Approvals),
+			"required_approvals": goal.RequiredApprovals,
+			"status":             withdrawal.Status,
+		},
+	})
+	metrics.IncrementCounter("goals.withdrawal.approved", "released:"+strconv.FormatBool(released))
+	return withdrawal, nil
+}
+
+// recordApprovalRequested records the event asking the collaborators who have not yet
+// approved a withdrawal to review it, in the transaction that requested or approved it
+func recordApprovalRequested(ctx context.Context, tx *repository.Repository, withdrawal *models.Withdrawal, goal *models.Goal) error {
+	approvers, err := goalApproverIDs(ctx, tx, goal)
+	if err != nil {
+		return err
+	}
+	approved := make([]uuid.UUID, 0, len(withdrawal.Approvals))
+	for _, approval := range withdrawal.Approvals {
+		approved = append(approved, approval.ApproverID)
+	}
+	remaining := make([]string, 0, len(approvers))
+	for _, approverID := range approvers {
+		if !containsUUID(approved, approverID) {
+			remaining = append(remaining, approverID.String())
+		}
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+
+	requestedBy := withdrawal.OwnerID
+	if withdrawal.RequestedBy != nil {
+		requestedBy = *withdrawal.RequestedBy
+	}
+	event := events.WithdrawalApprovalRequested{
+		ID:                uuid.New().String(),
+		WithdrawalID:      withdrawal.ID.String(),
+		GoalID:            withdrawal.GoalID.String(),
+		GoalTitle:         goal.Title,
+		RequestedBy:       requestedBy.String(),
+		Amount:            withdrawal.Amount,
+		Currency:          withdrawal.Currency,
+		ApprovalsReceived: len(withdrawal.Approvals),
+		RequiredApprovals: goal.RequiredApprovals,
+		ApproverIDs:       remaining,
+		CreatedAt:         time.Now().Unix(),
+	}
+	return recordEvent(ctx, tx, "WithdrawalApprovalRequested", event)
+}
+
+// containsUUID reports whether ids contains id
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetWithdrawalHistory returns a page of a goal's withdrawals, newest first, to its owner
+func (s *WithdrawalService) GetWithdrawalHistory(ctx context.Context, goalID, ownerID uuid.UUID, page, pageSize int) (*dto.WithdrawalHistory, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > maxFeedPageSize {
+		pageSize = maxFeedPageSize
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, goalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+	if goal.OwnerID != ownerID {
+		return nil, ErrUnauthorized
+	}
+
+	withdrawals, total, err := s.repo.Withdrawal.GetWithdrawalsPageByGoalID(ctx, goalID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &dto.WithdrawalHistory{
+		Items:    make([]dto.WithdrawalHistoryItem, 0, len(withdrawals)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for i := range withdrawals {
+		history.Items = append(history.Items, withdrawalHistoryItem(&withdrawals[i]))
+	}
+	return history, nil
+}
+
+// GetWithdrawalDetail returns a withdrawal and its status history to the owner of its goal
+func (s *WithdrawalService) GetWithdrawalDetail(ctx context.Context, withdrawalID, ownerID uuid.UUID) (*dto.WithdrawalDetail, error) {
+	withdrawal, err := s.repo.Withdrawal.GetWithdrawalByID(ctx, withdrawalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWithdrawalNotFound
+		}
+		return nil, err
+	}
+
+	goal, err := s.repo.Goal.GetGoalByIDSimple(ctx, withdrawal.GoalID)
+	if err != nil {
+		return nil, err
+	}
+	if goal.OwnerID != ownerID {
+		return nil, ErrUnauthorized
+	}
+
+	statusEvents, err := s.repo.Withdrawal.GetStatusEvents(ctx, withdrawal.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.WithdrawalDetail{
+		WithdrawalHistoryItem: withdrawalHistoryItem(withdrawal),
+		StatusHistory:         statusEvents,
+	}, nil
+}
+
+// withdrawalHistoryItem presents a withdrawal for the history, masking its account number
+func withdrawalHistoryItem(withdrawal *models.Withdrawal) dto.WithdrawalHistoryItem {
+	return dto.WithdrawalHistoryItem{
+		ID:                 withdrawal.ID,
+		MilestoneID:        withdrawal.MilestoneID,
+		Amount:             withdrawal.Amount,
+		Currency:           withdrawal.Currency,
+		Status:             withdrawal.Status,
+		BankName:           withdrawal.BankName,
+		AccountName:        withdrawal.AccountName,
+		AccountNumberLast4: lastFourDigits(withdrawal.AccountNumber),
+		RequestedBy:        withdrawal.RequestedBy,
+		TransferReference:  withdrawal.TransferReference,
+		FailureReason:      withdrawal.FailureReason,
+		RequestedAt:        withdrawal.RequestedAt,
+		CompletedAt:        withdrawal.CompletedAt,
+	}
+}
+
+// lastFourDigits returns the last four characters of an account number
+func lastFourDigits(accountNumber string) string {
+	if len(accountNumber) <= 4 {
+		return accountNumber
+	}
+	return accountNumber[len(accountNumber)-4:]
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofund/goals-service/internal/dto"
+	"github.com/gofund/goals-service/internal/repository"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// withdrawalRefundFixture is a closed goal holding two confirmed contributions of 100,000,
+// with the withdrawal and refund services over the same database
+type withdrawalRefundFixture struct {
+	repo        *repository.Repository
+	goal        *models.Goal
+	withdrawals *WithdrawalService
+	refunds     *RefundService
+}
+
+func newWithdrawalRefundFixture(t *testing.T) *withdrawalRefundFixture {
+	t.Helper()
+	repo := newTestRepo(t)
+	users := newTestUsersClient(t)
+
+	goal := createTestGoal(t, repo, uuid.New())
+	createTestContribution(t, repo, goal, uuid.New(), 100_000)
+	createTestContribution(t, repo, goal, uuid.New(), 100_000)
+	setGoalStatus(t, repo, goal, models.GoalStatusClosed)
+
+	return &withdrawalRefundFixture{
+		repo:        repo,
+		goal:        goal,
+		withdrawals: NewWithdrawalService(repo, NewBalanceCheckService(repo, NewLedgerClient(""), false, 0), nil, users, 0),
+		refunds:     NewRefundService(repo, users, nil, time.Hour),
+	}
+}
+
+func (f *withdrawalRefundFixture) withdraw(amount int64) (*models.Withdrawal, error) {
+	return f.withdrawals.CreateWithdrawal(context.Background(), f.goal.OwnerID, dto.CreateWithdrawalRequest{
+		GoalID: f.goal.ID,
+		Amount: amount,
+	})
+}
+
+func (f *withdrawalRefundFixture) refund(percentage float64) (*models.Refund, error) {
+	return f.refunds.InitiateRefund(context.Background(), f.goal.OwnerID, &dto.InitiateRefundRequest{
+		GoalID:           f.goal.ID.String(),
+		RefundPercentage: percentage,
+	})
+}
+
+// completeDisbursements reports every disbursement of refund paid out
+func (f *withdrawalRefundFixture) completeDisbursements(t *testing.T, refund *models.Refund) {
+	t.Helper()
+	for _, disbursement := range refund.Disbursements {
+		if err := f.refunds.UpdateDisbursementStatus(context.Background(), disbursement.ID, models.RefundStatusCompleted, nil); err != nil {
+			t.Fatalf("failed to complete disbursement: %v", err)
+		}
+	}
+}
+
+func TestWithdrawalAfterRefund(t *testing.T) {
+	f := newWithdrawalRefundFixture(t)
+
+	refund, err := f.refund(50)
+	if err != nil {
+		t.Fatalf("InitiateRefund: %v", err)
+	}
+	if refund.TotalRefundAmount != 100_000 {
+		t.Fatalf("refund total = %d, want 100000", refund.TotalRefundAmount)
+	}
+
+	// Nothing can be withdrawn while the refund is being paid out
+	if _, err := f.withdraw(1_000); !errors.Is(err, ErrRefundInProgress) {
+		t.Fatalf("withdrawal during refund: err = %v, want %v", err, ErrRefundInProgress)
+	}
+
+	f.completeDisbursements(t, refund)
+	settled, err := f.repo.Refund.GetRefundByID(context.Background(), refund.ID)
+	if err != nil {
+		t.Fatalf("GetRefundByID: %v", err)
+	}
+	if settled.Status != models.RefundStatusCompleted {
+		t.Fatalf("refund status = %s, want %s", settled.Status, models.RefundStatusCompleted)
+	}
+
+	// The refunded half is gone: only the other 100,000 can be withdrawn
+	if _, err := f.withdraw(100_001); !errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("withdrawal above the balance left by the refund: err = %v, want %v", err, ErrInsufficientBalance)
+	}
+	withdrawal, err := f.withdraw(100_000)
+	if err != nil {
+		t.Fatalf("withdrawal of the balance left by the refund: %v", err)
+	}
+	if withdrawal.Amount != 100_000 {
+		t.Errorf("withdrawal amount = %d, want 100000", withdrawal.Amount)
+	}
+	if _, err := f.withdraw(1); !errors.Is(err, ErrInsufficientBalance) {
+		t.Errorf("withdrawal from an empty goal: err = %v, want %v", err, ErrInsufficientBalance)
+	}
+}
+
+func TestRefundAfterWithdrawal(t *testing.T) {
+	f := newWithdrawalRefundFixture(t)
+
+	// The pending withdrawal reserves 150,000 of the 200,000
+	if _, err := f.withdraw(150_000); err != nil {
+		t.Fatalf("CreateWithdrawal: %v", err)
+	}
+
+	if _, err := f.refund(50); errorCode(err) != "insufficient_balance" {
+		t.Fatalf("refund above the balance left by the withdrawal: err = %v, want insufficient_balance", err)
+	}
+	refund, err := f.refund(25)
+	if err != nil {
+		t.Fatalf("refund within the balance left by the withdrawal: %v", err)
+	}
+	f.completeDisbursements(t, refund)
+
+	// Withdrawal and refund have used up the goal between them
+	if _, err := f.withdraw(1); !errors.Is(err, ErrInsufficientBalance) {
+		t.Errorf("withdrawal after withdrawal and refund: err = %v, want %v", err, ErrInsufficientBalance)
+	}
+}
+
+// TestConcurrentWithdrawalsAndRefund races a refund against two withdrawals, each of which
+// alone the goal can cover but which together it cannot; the goal lock lets only one win
+func TestConcurrentWithdrawalsAndRefund(t *testing.T) {
+	f := newWithdrawalRefundFixture(t)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		_, errs[0] = f.withdraw(150_000)
+	}()
+	go func() {
+		defer wg.Done()
+		_, errs[1] = f.withdraw(150_000)
+	}()
+	go func() {
+		defer wg.Done()
+		_, errs[2] = f.refund(75)
+	}()
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("%d of the withdrawals and refund succeeded, want 1: %v", succeeded, errs)
+	}
+
+	committed, err := f.repo.Goal.GetTotalCommittedWithdrawals(context.Background(), f.goal.ID)
+	if err != nil {
+		t.Fatalf("GetTotalCommittedWithdrawals: %v", err)
+	}
+	refunded, err := f.repo.Goal.GetTotalRefunded(context.Background(), f.goal.ID)
+	if err != nil {
+		t.Fatalf("GetTotalRefunded: %v", err)
+	}
+	if committed+refunded > 200_000 {
+		t.Errorf("withdrawn %d and refunded %d out of 200000", committed, refunded)
+	}
+}
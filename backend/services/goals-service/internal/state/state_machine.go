@@ -20,9 +20,14 @@ func NewGoalStateMachine() *GoalStateMachine {
 
 // CanTransition checks if a transition from current to next is valid
 func (sm *GoalStateMachine) CanTransition(current, next models.GoalStatus) bool {
+	// Any live goal can be suspended; unsuspending restores the status it was suspended from
+	if next == models.GoalStatusSuspended {
+		return current != models.GoalStatusSuspended && current != models.GoalStatusCancelled && current != models.GoalStatusArchived
+	}
+
 	switch current {
 	case models.GoalStatusOpen:
-		return next == models.GoalStatusFunded || next == models.GoalStatusCancelled || next == models.GoalStatusClosed
+		return next == models.GoalStatusFunded || next == models.GoalStatusCancelled || next == models.GoalStatusClosed || next == models.GoalStatusArchived
 	case models.GoalStatusFunded:
 		return next == models.GoalStatusWithdrawn || next == models.GoalStatusCancelled
 	case models.GoalStatusWithdrawn:
@@ -30,11 +35,15 @@ func (sm *GoalStateMachine) CanTransition(current, next models.GoalStatus) bool
 	case models.GoalStatusProofSubmitted:
 		return next == models.GoalStatusVerified || next == models.GoalStatusOpen // If proof rejected, maybe back to open or funded
 	case models.GoalStatusVerified:
-		return false // Terminal state
+		return next == models.GoalStatusArchived
 	case models.GoalStatusCancelled:
-		return false // Terminal state
+		return next == models.GoalStatusArchived
 	case models.GoalStatusClosed:
-		return next == models.GoalStatusOpen || next == models.GoalStatusCancelled
+		return next == models.GoalStatusOpen || next == models.GoalStatusCancelled || next == models.GoalStatusArchived
+	case models.GoalStatusSuspended:
+		return next != models.GoalStatusCancelled && next != models.GoalStatusArchived
+	case models.GoalStatusArchived:
+		return false // Terminal state
 	default:
 		return false
 	}
@@ -0,0 +1,83 @@
+// Package storage keeps uploaded files in a blob store. The local disk store is used for
+// now; an S3-compatible store only has to implement BlobStore.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BlobStore stores files under keys and serves them at public URLs
+type BlobStore interface {
+	// Put stores the contents of r under key and returns the URL it is served at
+	Put(ctx context.Context, key, contentType string, r io.Reader) (string, error)
+	// Delete removes the file stored under key. Deleting a missing file is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// LocalStore is a BlobStore on the local disk. Files are written below dir and served at
+// baseURL joined with their key, by whatever serves dir (see cmd/main.go).
+type LocalStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStore creates a local disk store, creating dir if needed
+func NewLocalStore(dir, baseURL string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %w", err)
+	}
+	return &LocalStore{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+// Put writes r to a temporary file and renames it into place, so a failed upload never
+// leaves a partial file under key
+func (s *LocalStore) Put(ctx context.Context, key, contentType string, r io.Reader) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return s.baseURL + "/" + key, nil
+}
+
+// Delete removes the file stored under key
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path returns the file a key is stored in. Keys are single file names.
+func (s *LocalStore) path(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || strings.HasPrefix(key, ".") {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
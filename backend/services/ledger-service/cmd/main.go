@@ -1,13 +1,29 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gofund/ledger-service/internal/config"
+	"github.com/gofund/ledger-service/internal/middleware"
+	"github.com/gofund/ledger-service/internal/repository"
+	"github.com/gofund/ledger-service/internal/router"
+	"github.com/gofund/ledger-service/internal/service"
+	"github.com/gofund/shared/buildinfo"
+	"github.com/gofund/shared/database"
+	"github.com/gofund/shared/health"
+	"github.com/gofund/shared/messaging"
 	"github.com/gofund/shared/metrics"
 	"github.com/joho/godotenv"
 	gintrace "gopkg.in/DataDog/dd-trace-go.v1/contrib/gin-gonic/gin"
+	"gorm.io/gorm/logger"
 )
 
 func main() {
@@ -16,48 +32,137 @@ func main() {
 		log.Printf("No .env file found, using system environment variables")
 	}
 
+	// Load config
+	cfg := config.LoadConfig()
+
+	buildinfo.LogStartup(cfg.Datadog.Service, cfg.Datadog.Version)
+
 	// Initialize Datadog tracing and metrics
-	serviceName := getEnv("DD_SERVICE", "ledger-service")
-	env := getEnv("DD_ENV", "dev")
-	version := getEnv("DD_VERSION", "1.0.0")
-	
-	if err := metrics.InitDatadog(serviceName, env, version); err != nil {
+	if err := metrics.InitDatadog(cfg.Datadog.Service, cfg.Datadog.Env, cfg.Datadog.Version); err != nil {
 		log.Printf("Warning: Failed to initialize Datadog: %v", err)
 	} else {
-		log.Printf("Datadog initialized successfully for %s", serviceName)
+		log.Printf("Datadog initialized successfully for %s", cfg.Datadog.Service)
 	}
 	defer metrics.StopDatadog()
 
+	// Initialize Database
+	db, err := database.SetupDatabase(database.Config{
+		Host:     cfg.Database.Host,
+		Port:     stringToInt(cfg.Database.Port),
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.DBName,
+		SSLMode:  cfg.Database.SSLMode,
+		LogLevel: logger.Warn,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	// Initialize RabbitMQ
+	rabbitConn, err := messaging.NewRabbitMQConnection(cfg.RabbitMQ.URL)
+	if err != nil {
+		log.Printf("Warning: Failed to connect to RabbitMQ: %v", err)
+	} else {
+		defer rabbitConn.Close()
+	}
+
+	// Initialize Messaging
+	var publisher messaging.Publisher
+	if rabbitConn != nil {
+		publisher, err = messaging.NewRabbitMQPublisher(rabbitConn, cfg.RabbitMQ.Exchange)
+		if err != nil {
+			log.Printf("Warning: Failed to create RabbitMQ publisher: %v", err)
+		}
+	}
+
+	// Initialize Repositories
+	ledgerRepo := repository.NewLedgerRepository(db)
+
+	// Initialize Services
+	contributionLedgerService := service.NewContributionLedgerService(ledgerRepo, publisher)
+	queryService := service.NewLedgerQueryService(ledgerRepo)
+	reconciliationService := service.NewReconciliationService(ledgerRepo, cfg.Reconcile.AutoCorrect)
+
+	// Start scheduled reconciliation
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go reconciliationService.Run(jobCtx, cfg.Reconcile.Interval)
+
+	// Start consuming events if RabbitMQ is connected
+	if rabbitConn != nil {
+		consumer, err := messaging.NewRabbitMQConsumer(rabbitConn, cfg.RabbitMQ.Exchange, cfg.RabbitMQ.QueueName)
+		if err != nil {
+			log.Printf("Failed to create RabbitMQ consumer: %v", err)
+		} else {
+			if err := consumer.Consume("PaymentVerified", contributionLedgerService.HandlePaymentVerified); err != nil {
+				log.Printf("Failed to start consuming PaymentVerified: %v", err)
+			}
+			if err := consumer.Consume("ContributionConfirmed", contributionLedgerService.HandleContributionConfirmed); err != nil {
+				log.Printf("Failed to start consuming ContributionConfirmed: %v", err)
+			}
+		}
+	}
+
 	// Initialize router
 	r := gin.Default()
 
+	// Tag every request with an ID that logs and published events carry
+	r.Use(middleware.RequestID())
+
 	// Add Datadog APM middleware
-	r.Use(gintrace.Middleware(serviceName))
+	r.Use(gintrace.Middleware(cfg.Datadog.Service))
+
+	// Drop identity headers that did not come from the gateway
+	if cfg.Identity.HeaderSecret == "" {
+		log.Printf("Warning: IDENTITY_HEADER_SECRET not set; X-User-* headers cannot be verified")
+	}
+	r.Use(middleware.IdentityGuard(cfg.Identity.HeaderSecret))
+
+	// Health checks: live only says the process is up, ready probes dependencies
+	r.GET("/health/live", gin.WrapF(health.LiveHandler(cfg.Datadog.Service)))
+	r.GET("/health/ready", gin.WrapF(health.ReadyHandler(health.DefaultTimeout,
+		health.Gorm("postgres", db),
+		health.RabbitMQ("rabbitmq", rabbitConn),
+	)))
+
+	// Build info
+	r.GET("/version", gin.WrapF(buildinfo.Handler(cfg.Datadog.Service, cfg.Datadog.Version)))
 
 	// Setup routes
-	setupRoutes(r)
+	router.SetupRoutes(r, queryService, reconciliationService)
+	log.Printf("Routes configured successfully")
 
-	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8082"
+	// Start Server with Graceful Shutdown
+	srv := &http.Server{
+		Addr:    ":" + cfg.Server.Port,
+		Handler: r,
 	}
 
-	log.Printf("Ledger Service starting on port %s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
-	}
-}
+	go func() {
+		log.Printf("Ledger Service starting on port %s", cfg.Server.Port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
 
-// getEnv gets environment variable with fallback
-func getEnv(key, fallback string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+	stopJobs()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatal("Server forced to shutdown:", err)
 	}
-	return fallback
+
+	log.Println("Server exiting")
 }
 
-// setupRoutes configures all Ledger Service routes
-func setupRoutes(r *gin.Engine) {
-	// Routes will be configured here
+func stringToInt(s string) int {
+	var n int
+	fmt.Sscanf(s, "%d", &n)
+	return n
 }
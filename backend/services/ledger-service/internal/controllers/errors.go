@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/ledger-service/internal/service"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/identity"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	errUnauthenticated = apperrors.Unauthenticated("unauthenticated", "User not authenticated")
+	errNotFound        = apperrors.NotFound("not_found", "record not found")
+	errInvalidDate     = apperrors.Validation("invalid_date", "dates must be YYYY-MM-DD or RFC 3339")
+)
+
+// respondError writes err as a JSON error response. The status comes from the error's kind
+// (see shared/errors.HTTPStatus), so controllers never pick one themselves.
+func respondError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = errNotFound
+	}
+
+	body := gin.H{"error": apperrors.Message(err)}
+	if code := apperrors.Code(err); code != "" {
+		body["code"] = code
+	}
+
+	c.JSON(apperrors.HTTPStatus(err), body)
+}
+
+// parseID parses a UUID path or query parameter, naming it in the error when it is malformed
+func parseID(value, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, apperrors.Validation("invalid_id", "Invalid "+name+" ID")
+	}
+	return id, nil
+}
+
+// requireViewer returns the authenticated caller from the identity headers
+func requireViewer(c *gin.Context) (service.Viewer, error) {
+	userID, err := uuid.Parse(c.GetHeader(identity.HeaderUserID))
+	if err != nil {
+		return service.Viewer{}, errUnauthenticated
+	}
+
+	viewer := service.Viewer{UserID: userID}
+	for _, role := range strings.Split(c.GetHeader(identity.HeaderUserRoles), ",") {
+		if strings.TrimSpace(role) == string(models.UserRoleAdmin) {
+			viewer.IsAdmin = true
+		}
+	}
+	return viewer, nil
+}
+
+// parseDate parses a date filter. A bare date is midnight UTC; as an upper bound it
+// means the end of that day.
+func parseDate(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, errInvalidDate
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
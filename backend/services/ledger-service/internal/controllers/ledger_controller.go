@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/ledger-service/internal/dto"
+	"github.com/gofund/ledger-service/internal/service"
+)
+
+// LedgerController handles ledger balance, entry and transaction queries
+type LedgerController struct {
+	queryService *service.LedgerQueryService
+}
+
+// NewLedgerController creates a new ledger controller instance
+func NewLedgerController(queryService *service.LedgerQueryService) *LedgerController {
+	return &LedgerController{
+		queryService: queryService,
+	}
+}
+
+// GetBalance handles GET /api/v1/ledger/accounts/:entityId/balance?type=GOAL|USER&currency=NGN
+func (lc *LedgerController) GetBalance(c *gin.Context) {
+	viewer, err := requireViewer(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	entityID, err := parseID(c.Param("entityId"), "entity")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	accountType, err := service.ParseAccountType(c.Query("type"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	currency, err := service.ParseCurrency(c.Query("currency"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	balance, err := lc.queryService.GetBalance(viewer, entityID, accountType, currency)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// ListEntries handles GET /api/v1/ledger/accounts/:entityId/entries
+func (lc *LedgerController) ListEntries(c *gin.Context) {
+	viewer, err := requireViewer(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	entityID, err := parseID(c.Param("entityId"), "entity")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	query := dto.ListEntriesQuery{EntityID: entityID}
+	if query.AccountType, err = service.ParseAccountType(c.Query("type")); err != nil {
+		respondError(c, err)
+		return
+	}
+	if query.Currency, err = service.ParseCurrency(c.Query("currency")); err != nil {
+		respondError(c, err)
+		return
+	}
+	if query.From, err = parseDate(c.Query("from"), false); err != nil {
+		respondError(c, err)
+		return
+	}
+	if query.To, err = parseDate(c.Query("to"), true); err != nil {
+		respondError(c, err)
+		return
+	}
+	query.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	query.PageSize, _ = strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	page, err := lc.queryService.ListEntries(viewer, query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetTransaction handles GET /api/v1/ledger/transactions/:id
+func (lc *LedgerController) GetTransaction(c *gin.Context) {
+	viewer, err := requireViewer(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	id, err := parseID(c.Param("id"), "transaction")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	transaction, err := lc.queryService.GetTransaction(viewer, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// GetGoalLedgerBalance handles GET /internal/ledger/goals/:goalId/balance, used by
+// goals-service to check its balance against the ledger
+func (lc *LedgerController) GetGoalLedgerBalance(c *gin.Context) {
+	goalID, err := parseID(c.Param("goalId"), "goal")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	currency, err := service.ParseCurrency(c.Query("currency"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	balance, err := lc.queryService.GetGoalLedgerBalance(goalID, currency)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/ledger-service/internal/service"
+)
+
+// ReconciliationController handles ledger reconciliation runs
+type ReconciliationController struct {
+	reconciliationService *service.ReconciliationService
+}
+
+// NewReconciliationController creates a new reconciliation controller instance
+func NewReconciliationController(reconciliationService *service.ReconciliationService) *ReconciliationController {
+	return &ReconciliationController{
+		reconciliationService: reconciliationService,
+	}
+}
+
+// Reconcile handles POST /api/v1/ledger/reconcile. It is a dry run unless dry_run=false.
+func (rc *ReconciliationController) Reconcile(c *gin.Context) {
+	dryRun := c.DefaultQuery("dry_run", "true") != "false"
+
+	result, err := rc.reconciliationService.Reconcile(dryRun)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
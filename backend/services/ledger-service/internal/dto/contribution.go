@@ -0,0 +1,23 @@
+package dto
+
+import "github.com/google/uuid"
+
+// PostContributionRequest represents a contribution to record in the ledger. PaymentID
+// and ContributionID identify it for idempotency; either may be nil.
+type PostContributionRequest struct {
+	PaymentID      *uuid.UUID
+	ContributionID *uuid.UUID
+	UserID         uuid.UUID
+	GoalID         uuid.UUID
+	Amount         int64
+	Currency       string
+}
+
+// PostFeeRequest represents a contribution's platform fee to move from the goal's account
+// to the platform's revenue account. ContributionID identifies it for idempotency.
+type PostFeeRequest struct {
+	ContributionID uuid.UUID
+	GoalID         uuid.UUID
+	Amount         int64
+	Currency       string
+}
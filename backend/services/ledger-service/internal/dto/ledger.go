@@ -0,0 +1,134 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// Balance sources
+const (
+	BalanceSourceSnapshot = "snapshot"
+	BalanceSourceEntries  = "entries"
+)
+
+// AccountBalance is an entity's balance in one currency. AccountID is nil when the entity
+// has no account yet, i.e. nothing was ever posted for it.
+type AccountBalance struct {
+	AccountID   *uuid.UUID         `json:"account_id"`
+	EntityID    uuid.UUID          `json:"entity_id"`
+	AccountType models.AccountType `json:"account_type"`
+	Currency    string             `json:"currency"`
+	Balance     int64              `json:"balance"`
+	// Source is "snapshot" when read from the balance snapshot and "entries" when
+	// computed from the ledger entries
+	Source    string     `json:"source"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// ListEntriesQuery selects a page of an account's entries. From and To bound the entry
+// creation time and are optional.
+type ListEntriesQuery struct {
+	EntityID    uuid.UUID
+	AccountType models.AccountType
+	Currency    string
+	From        *time.Time
+	To          *time.Time
+	Page        int
+	PageSize    int
+}
+
+// TransactionSummary is the transaction an entry belongs to
+type TransactionSummary struct {
+	ID              uuid.UUID                `json:"id"`
+	Type            models.TransactionType   `json:"type"`
+	Description     string                   `json:"description"`
+	Status          models.TransactionStatus `json:"status"`
+	TransactionDate time.Time                `json:"transaction_date"`
+	Metadata        map[string]interface{}   `json:"metadata"`
+}
+
+// LedgerEntryItem is a ledger entry with its transaction
+type LedgerEntryItem struct {
+	ID          uuid.UUID              `json:"id"`
+	AccountID   uuid.UUID              `json:"account_id"`
+	EntryType   models.EntryType       `json:"entry_type"`
+	Amount      int64                  `json:"amount"`
+	Currency    string                 `json:"currency"`
+	Description string                 `json:"description"`
+	Metadata    map[string]interface{} `json:"metadata"`
+	CreatedAt   time.Time              `json:"created_at"`
+	Transaction *TransactionSummary    `json:"transaction"`
+}
+
+// LedgerEntryPage is a page of an account's entries, newest first
+type LedgerEntryPage struct {
+	Items    []LedgerEntryItem `json:"items"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+}
+
+// TransactionDetail is a transaction with both of its legs
+type TransactionDetail struct {
+	ID              uuid.UUID                `json:"id"`
+	Type            models.TransactionType   `json:"type"`
+	Description     string                   `json:"description"`
+	Amount          int64                    `json:"amount"`
+	Currency        string                   `json:"currency"`
+	Status          models.TransactionStatus `json:"status"`
+	Metadata        map[string]interface{}   `json:"metadata"`
+	TransactionDate time.Time                `json:"transaction_date"`
+	CreatedAt       time.Time                `json:"created_at"`
+	Entries         []TransactionLeg         `json:"entries"`
+}
+
+// TransactionLeg is one entry of a transaction, with the account it posts to
+type TransactionLeg struct {
+	ID          uuid.UUID          `json:"id"`
+	AccountID   uuid.UUID          `json:"account_id"`
+	AccountType models.AccountType `json:"account_type"`
+	EntityID    uuid.UUID          `json:"entity_id"`
+	EntryType   models.EntryType   `json:"entry_type"`
+	Amount      int64              `json:"amount"`
+	Currency    string             `json:"currency"`
+	Description string             `json:"description"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+// ReconciliationResult is the outcome of a reconciliation run. With DryRun, mismatched
+// snapshots are reported but left as they are.
+type ReconciliationResult struct {
+	RunID           uuid.UUID                     `json:"run_id"`
+	DryRun          bool                          `json:"dry_run"`
+	AccountsChecked int                           `json:"accounts_checked"`
+	MismatchCount   int                           `json:"mismatch_count"`
+	CorrectedCount  int                           `json:"corrected_count"`
+	Mismatches      []models.ReconciliationReport `json:"mismatches"`
+	StartedAt       time.Time                     `json:"started_at"`
+	CompletedAt     time.Time                     `json:"completed_at"`
+}
+
+// GoalLedgerBalance is a goal account's balance as the ledger sees it, for services
+// checking their own figures against the ledger. Balance is always computed from the
+// entries; SnapshotBalance is nil when the account has no snapshot.
+type GoalLedgerBalance struct {
+	GoalID          uuid.UUID         `json:"goal_id"`
+	AccountID       *uuid.UUID        `json:"account_id"`
+	Currency        string            `json:"currency"`
+	Balance         int64             `json:"balance"`
+	SnapshotBalance *int64            `json:"snapshot_balance"`
+	Entries         []GoalLedgerEntry `json:"entries"`
+}
+
+// GoalLedgerEntry is one entry on a goal account. ContributionID is set for entries
+// posted for a contribution.
+type GoalLedgerEntry struct {
+	ID             uuid.UUID        `json:"id"`
+	TransactionID  uuid.UUID        `json:"transaction_id"`
+	EntryType      models.EntryType `json:"entry_type"`
+	Amount         int64            `json:"amount"`
+	ContributionID *string          `json:"contribution_id"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/identity"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// IdentityGuard drops identity headers that were not signed by the gateway, so
+// callers that bypass Nginx cannot impersonate users. It must run before AuthMiddleware.
+func IdentityGuard(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity.Guard(c.Request.Header, secret)
+		c.Next()
+	}
+}
+
+// AuthMiddleware ensures the X-User-ID header is present and valid
+func AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userIDStr := c.GetHeader("X-User-ID")
+		if userIDStr == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Missing X-User-ID header"})
+			c.Abort()
+			return
+		}
+
+		if _, err := uuid.Parse(userIDStr); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Invalid X-User-ID header"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRole ensures the X-User-Roles header (set by Nginx after auth verification)
+// includes at least one of roles
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, role := range strings.Split(c.GetHeader(identity.HeaderUserRoles), ",") {
+			for _, required := range roles {
+				if strings.TrimSpace(role) == required {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: " + strings.Join(roles, " or ") + " role required"})
+		c.Abort()
+	}
+}
+
+// AdminMiddleware ensures the caller has the admin role
+func AdminMiddleware() gin.HandlerFunc {
+	return RequireRole(string(models.UserRoleAdmin))
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/requestid"
+)
+
+// RequestIDKey is the gin context key holding the request ID
+const RequestIDKey = "request_id"
+
+// RequestID takes the request ID from X-Request-ID, or generates one, and makes it
+// available to handlers through the gin context and the request context. It is echoed on
+// the response so clients can quote it when reporting errors.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := requestid.FromHeader(c.GetHeader(requestid.Header))
+		c.Set(RequestIDKey, id)
+		c.Request = c.Request.WithContext(requestid.WithContext(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+		c.Next()
+	}
+}
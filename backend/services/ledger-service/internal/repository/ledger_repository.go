@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LedgerRepository handles database operations for accounts, transactions and entries
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+// NewLedgerRepository creates a new ledger repository
+func NewLedgerRepository(db *gorm.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// Transaction runs fn with a repository bound to a database transaction, committing when
+// fn returns nil and rolling back otherwise
+func (r *LedgerRepository) Transaction(fn func(repo *LedgerRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&LedgerRepository{db: tx})
+	})
+}
+
+// GetOrCreateAccount returns the entity's account in the currency, creating it if it does
+// not exist yet. With lock the account row is locked until the transaction ends, which
+// serialises postings to it.
+func (r *LedgerRepository) GetOrCreateAccount(accountType models.AccountType, entityID uuid.UUID, currency string, lock bool) (*models.Account, error) {
+	account := &models.Account{
+		AccountType: accountType,
+		EntityID:    entityID,
+		Currency:    currency,
+		CreatedAt:   time.Now(),
+	}
+	// A concurrent creation of the same account wins; the lookup below picks it up
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(account).Error; err != nil {
+		return nil, err
+	}
+
+	query := r.db
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var existing models.Account
+	err := query.Where("account_type = ? AND entity_id = ? AND currency = ?", accountType, entityID, currency).
+		First(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// FindTransactionByMetadata returns the transaction of the type whose metadata has key set
+// to value, or nil when there is none
+func (r *LedgerRepository) FindTransactionByMetadata(txType models.TransactionType, key, value string) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := r.db.Where("type = ? AND metadata->>? = ?", txType, key, value).
+		First(&transaction).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// FindUnlinkedContribution returns a contribution transaction for the goal, user and
+// amount that was posted without a contribution ID, or nil when there is none
+func (r *LedgerRepository) FindUnlinkedContribution(goalID, userID uuid.UUID, amount int64) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := r.db.Where("type = ? AND metadata->>'goal_id' = ? AND metadata->>'user_id' = ? AND amount = ?",
+		models.TransactionTypeContribution, goalID.String(), userID.String(), amount).
+		Where("COALESCE(metadata->>'contribution_id', '') = ''").
+		Order("transaction_date").
+		First(&transaction).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// UpdateTransactionMetadata replaces a transaction's metadata
+func (r *LedgerRepository) UpdateTransactionMetadata(id uuid.UUID, metadata map[string]interface{}) error {
+	return r.db.Model(&models.Transaction{ID: id}).Update("metadata", metadata).Error
+}
+
+// CreateTransaction records a transaction together with its ledger entries
+func (r *LedgerRepository) CreateTransaction(transaction *models.Transaction, entries []models.LedgerEntry) error {
+	if err := r.db.Create(transaction).Error; err != nil {
+		return err
+	}
+	for i := range entries {
+		entries[i].TransactionID = transaction.ID
+	}
+	return r.db.Omit("Account").Create(&entries).Error
+}
+
+// GetAccount returns the entity's account in the currency
+func (r *LedgerRepository) GetAccount(accountType models.AccountType, entityID uuid.UUID, currency string) (*models.Account, error) {
+	var account models.Account
+	err := r.db.Where("account_type = ? AND entity_id = ? AND currency = ?", accountType, entityID, currency).
+		First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// GetAccountsByIDs returns the accounts with the given IDs, keyed by ID
+func (r *LedgerRepository) GetAccountsByIDs(ids []uuid.UUID) (map[uuid.UUID]models.Account, error) {
+	accounts := make(map[uuid.UUID]models.Account, len(ids))
+	if len(ids) == 0 {
+		return accounts, nil
+	}
+
+	var found []models.Account
+	if err := r.db.Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	for _, account := range found {
+		accounts[account.ID] = account
+	}
+	return accounts, nil
+}
+
+// GetBalanceSnapshot returns an account's balance snapshot
+func (r *LedgerRepository) GetBalanceSnapshot(accountID uuid.UUID) (*models.BalanceSnapshot, error) {
+	var snapshot models.BalanceSnapshot
+	if err := r.db.Where("account_id = ?", accountID).First(&snapshot).Error; err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// ComputeBalance sums an account's entries: credits minus debits
+func (r *LedgerRepository) ComputeBalance(accountID uuid.UUID) (int64, error) {
+	var balance int64
+	err := r.db.Model(&models.LedgerEntry{}).
+		Where("account_id = ?", accountID).
+		Select("COALESCE(SUM(CASE WHEN entry_type = ? THEN amount ELSE -amount END), 0)", models.EntryTypeCredit).
+		Scan(&balance).Error
+	return balance, err
+}
+
+// ListEntries returns a page of an account's entries, newest first, optionally bounded by
+// creation time, along with the total number of matching entries
+func (r *LedgerRepository) ListEntries(accountID uuid.UUID, from, to *time.Time, limit, offset int) ([]models.LedgerEntry, int64, error) {
+	query := r.db.Model(&models.LedgerEntry{}).Where("account_id = ?", accountID)
+	if from != nil {
+		query = query.Where("created_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("created_at < ?", *to)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.LedgerEntry
+	err := query.Order("created_at DESC, id").Limit(limit).Offset(offset).Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// GetAllEntries returns every entry of an account, oldest first
+func (r *LedgerRepository) GetAllEntries(accountID uuid.UUID) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := r.db.Where("account_id = ?", accountID).Order("created_at, id").Find(&entries).Error
+	return entries, err
+}
+
+// GetTransactionsByIDs returns the transactions with the given IDs, keyed by ID
+func (r *LedgerRepository) GetTransactionsByIDs(ids []uuid.UUID) (map[uuid.UUID]models.Transaction, error) {
+	transactions := make(map[uuid.UUID]models.Transaction, len(ids))
+	if len(ids) == 0 {
+		return transactions, nil
+	}
+
+	var found []models.Transaction
+	if err := r.db.Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	for _, transaction := range found {
+		transactions[transaction.ID] = transaction
+	}
+	return transactions, nil
+}
+
+// GetTransactionWithEntries returns a transaction with its ledger entries, debits first
+func (r *LedgerRepository) GetTransactionWithEntries(id uuid.UUID) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := r.db.Preload("LedgerEntries", func(db *gorm.DB) *gorm.DB {
+		return db.Order("entry_type DESC, created_at")
+	}).First(&transaction, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// RefreshBalanceSnapshot recomputes an account's balance from its entries (credits minus
+// debits) and stores it as the account's snapshot
+func (r *LedgerRepository) RefreshBalanceSnapshot(account *models.Account) (int64, error) {
+	balance, err := r.ComputeBalance(account.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	snapshot := models.BalanceSnapshot{
+		AccountID: account.ID,
+		Balance:   balance,
+		Currency:  account.Currency,
+		UpdatedAt: time.Now(),
+	}
+	err = r.db.Omit("Account").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"balance", "updated_at"}),
+	}).Create(&snapshot).Error
+	return balance, err
+}
+
+// AccountBalanceCheck is an account's snapshot balance beside the sum of its entries.
+// SnapshotBalance is nil when the account has no snapshot.
+type AccountBalanceCheck struct {
+	AccountID       uuid.UUID
+	Currency        string
+	EntrySum        int64
+	SnapshotBalance *int64
+}
+
+// GetAccountBalanceChecks returns every account's entry sum (credits minus debits) and
+// snapshot balance, read in one statement so both come from the same point in time
+func (r *LedgerRepository) GetAccountBalanceChecks() ([]AccountBalanceCheck, error) {
+	query := `
+		SELECT a.id AS account_id, a.currency, COALESCE(e.total, 0) AS entry_sum, s.balance AS snapshot_balance
+		FROM accounts a
+		LEFT JOIN (
+			SELECT account_id, SUM(CASE WHEN entry_type = ? THEN amount ELSE -amount END) AS total
+			FROM ledger_entries
+			GROUP BY account_id
+		) e ON e.account_id = a.id
+		LEFT JOIN balance_snapshots s ON s.account_id = a.id
+		ORDER BY a.created_at, a.id
+	`
+
+	var checks []AccountBalanceCheck
+	if err := r.db.Raw(query, models.EntryTypeCredit).Scan(&checks).Error; err != nil {
+		return nil, err
+	}
+	return checks, nil
+}
+
+// CreateReconciliationReports records the mismatches found by a reconciliation run
+func (r *LedgerRepository) CreateReconciliationReports(reports []models.ReconciliationReport) error {
+	if len(reports) == 0 {
+		return nil
+	}
+	return r.db.Create(&reports).Error
+}
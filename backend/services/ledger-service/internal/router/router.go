@@ -0,0 +1,32 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/ledger-service/internal/controllers"
+	"github.com/gofund/ledger-service/internal/middleware"
+	"github.com/gofund/ledger-service/internal/service"
+)
+
+// SetupRoutes configures all Ledger Service routes
+func SetupRoutes(r *gin.Engine, queryService *service.LedgerQueryService, reconciliationService *service.ReconciliationService) {
+	// Initialize controllers
+	ledgerController := controllers.NewLedgerController(queryService)
+	reconciliationController := controllers.NewReconciliationController(reconciliationService)
+
+	// Internal routes (called by other services, not exposed through Nginx)
+	internal := r.Group("/internal/ledger")
+	{
+		internal.GET("/goals/:goalId/balance", ledgerController.GetGoalLedgerBalance)
+	}
+
+	api := r.Group("/api/v1/ledger")
+	api.Use(middleware.AuthMiddleware())
+	{
+		api.GET("/accounts/:entityId/balance", ledgerController.GetBalance)
+		api.GET("/accounts/:entityId/entries", ledgerController.ListEntries)
+		api.GET("/transactions/:id", ledgerController.GetTransaction)
+
+		// Admin routes
+		api.POST("/reconcile", middleware.AdminMiddleware(), reconciliationController.Reconcile)
+	}
+}
@@ -0,0 +1,184 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/ledger-service/internal/middleware"
+	"github.com/gofund/ledger-service/internal/repository"
+	"github.com/gofund/ledger-service/internal/service"
+	"github.com/gofund/ledger-service/internal/testdb"
+	"github.com/gofund/sdk"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+const contractIdentitySecret = "contract-identity-secret"
+
+// ledgerFixture is the ledger the SDK contracts run against: a contribution from a user
+// to a goal
+type ledgerFixture struct {
+	adminID, userID, goalID, transactionID uuid.UUID
+	amount                                 int64
+}
+
+// sdkContract is one SDK method called against the ledger routes. check inspects the
+// result against the fixture when the routes are backed by a database.
+type sdkContract struct {
+	name  string
+	call  func(ctx context.Context, c *sdk.LedgerClient, f *ledgerFixture) (interface{}, error)
+	check func(t *testing.T, f *ledgerFixture, result interface{})
+}
+
+// sdkContracts covers every LedgerClient method
+func sdkContracts() []sdkContract {
+	return []sdkContract{
+		{
+			name: "GetBalance",
+			call: func(ctx context.Context, c *sdk.LedgerClient, f *ledgerFixture) (interface{}, error) {
+				return c.GetBalance(ctx, f.goalID.String(), "GOAL", "NGN")
+			},
+			check: func(t *testing.T, f *ledgerFixture, result interface{}) {
+				balance := result.(*sdk.AccountBalance)
+				if balance.EntityID != f.goalID.String() || balance.AccountType != "GOAL" || balance.Balance != f.amount || balance.AccountID == nil {
+					t.Errorf("balance = %+v, want goal %s holding %d", balance, f.goalID, f.amount)
+				}
+			},
+		},
+		{
+			name: "ListEntries",
+			call: func(ctx context.Context, c *sdk.LedgerClient, f *ledgerFixture) (interface{}, error) {
+				from := time.Now().Add(-time.Hour)
+				return c.ListEntries(ctx, f.userID.String(), sdk.LedgerEntriesQuery{AccountType: "USER", Currency: "NGN", From: &from, Page: 1, PageSize: 10})
+			},
+			check: func(t *testing.T, f *ledgerFixture, result interface{}) {
+				page := result.(*sdk.LedgerEntriesPage)
+				if page.Total != 1 || len(page.Items) != 1 || page.PageSize != 10 {
+					t.Fatalf("page = %+v, want the user's one entry", page)
+				}
+				entry := page.Items[0]
+				if entry.EntryType != string(models.EntryTypeDebit) || entry.Amount != f.amount || entry.Transaction == nil || entry.Transaction.ID != f.transactionID.String() {
+					t.Errorf("entry = %+v, want a debit of %d in transaction %s", entry, f.amount, f.transactionID)
+				}
+			},
+		},
+		{
+			name: "GetTransaction",
+			call: func(ctx context.Context, c *sdk.LedgerClient, f *ledgerFixture) (interface{}, error) {
+				return c.GetTransaction(ctx, f.transactionID.String())
+			},
+			check: func(t *testing.T, f *ledgerFixture, result interface{}) {
+				transaction := result.(*sdk.LedgerTransaction)
+				if transaction.ID != f.transactionID.String() || transaction.Amount != f.amount || len(transaction.Entries) != 2 {
+					t.Errorf("transaction = %+v, want %s with both legs", transaction, f.transactionID)
+				}
+			},
+		},
+		{
+			name: "Reconcile",
+			call: func(ctx context.Context, c *sdk.LedgerClient, f *ledgerFixture) (interface{}, error) {
+				return c.Reconcile(ctx, true)
+			},
+			check: func(t *testing.T, f *ledgerFixture, result interface{}) {
+				reconciliation := result.(*sdk.ReconciliationResult)
+				if !reconciliation.DryRun || reconciliation.RunID == "" || reconciliation.AccountsChecked != 2 {
+					t.Errorf("result = %+v, want a dry run over both accounts", reconciliation)
+				}
+			},
+		},
+	}
+}
+
+// routeProbe serves every route r has with an empty answer, so an SDK request succeeds
+// exactly when its method and path match one of r's routes
+func routeProbe(r *gin.Engine) http.Handler {
+	probe := gin.New()
+	for _, route := range r.Routes() {
+		probe.Handle(route.Method, route.Path, func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/json", []byte("null"))
+		})
+	}
+	return probe
+}
+
+// TestSDKRoutes checks every SDK method requests a route the service has; unlike
+// TestSDKContract it needs no database
+func TestSDKRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	SetupRoutes(r, nil, nil)
+	server := httptest.NewServer(routeProbe(r))
+	t.Cleanup(server.Close)
+
+	client := sdk.NewLedgerClient(server.URL)
+	f := &ledgerFixture{adminID: uuid.New(), userID: uuid.New(), goalID: uuid.New(), transactionID: uuid.New()}
+	for _, tc := range sdkContracts() {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.call(context.Background(), client, f); err != nil {
+				t.Errorf("no route for the request: %v", err)
+			}
+		})
+	}
+}
+
+// TestSDKContract calls every SDK method against the real routes over a seeded ledger,
+// as an admin signed in through the gateway
+func TestSDKContract(t *testing.T) {
+	db := testdb.Open(t)
+	repo := repository.NewLedgerRepository(db)
+	f := seedLedger(t, repo)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.IdentityGuard(contractIdentitySecret))
+	SetupRoutes(r, service.NewLedgerQueryService(repo), service.NewReconciliationService(repo, false))
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	client := sdk.NewLedgerClient(server.URL, sdk.WithIdentity(contractIdentitySecret, f.adminID.String(), "user,admin", true))
+	for _, tc := range sdkContracts() {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.call(context.Background(), client, f)
+			if err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			tc.check(t, f, result)
+		})
+	}
+}
+
+// seedLedger posts a contribution from a user to a goal
+func seedLedger(t *testing.T, repo *repository.LedgerRepository) *ledgerFixture {
+	t.Helper()
+	f := &ledgerFixture{adminID: uuid.New(), userID: uuid.New(), goalID: uuid.New(), amount: 25_000}
+
+	userAccount, err := repo.GetOrCreateAccount(models.AccountTypeUser, f.userID, "NGN", false)
+	if err != nil {
+		t.Fatalf("GetOrCreateAccount: %v", err)
+	}
+	goalAccount, err := repo.GetOrCreateAccount(models.AccountTypeGoal, f.goalID, "NGN", false)
+	if err != nil {
+		t.Fatalf("GetOrCreateAccount: %v", err)
+	}
+
+	transaction := &models.Transaction{
+		Type:            models.TransactionTypeContribution,
+		Description:     "seeded contribution",
+		Amount:          f.amount,
+		Currency:        "NGN",
+		TransactionDate: time.Now(),
+	}
+	entries := []models.LedgerEntry{
+		{AccountID: userAccount.ID, EntryType: models.EntryTypeDebit, Amount: f.amount, Currency: "NGN", Description: "seeded"},
+		{AccountID: goalAccount.ID, EntryType: models.EntryTypeCredit, Amount: f.amount, Currency: "NGN", Description: "seeded"},
+	}
+	if err := repo.CreateTransaction(transaction, entries); err != nil {
+		t.Fatalf("CreateTransaction: %v", err)
+	}
+	f.transactionID = transaction.ID
+	return f
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofund/ledger-service/internal/dto"
+	"github.com/gofund/ledger-service/internal/repository"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/messaging"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// DefaultCurrency is the currency of postings whose event carries none
+const DefaultCurrency = "NGN"
+
+// PlatformAccountID is the entity of the platform's revenue account, which platform
+// fees are credited to
+var PlatformAccountID = uuid.Nil
+
+// ContributionLedgerService records contributions in the double-entry ledger. Each
+// contribution debits the contributor's account and credits the goal's account; its
+// platform fee then moves from the goal's account to the platform's.
+type ContributionLedgerService struct {
+	repo      *repository.LedgerRepository
+	publisher messaging.Publisher
+}
+
+// NewContributionLedgerService creates a new contribution ledger service instance
+func NewContributionLedgerService(repo *repository.LedgerRepository, publisher messaging.Publisher) *ContributionLedgerService {
+	return &ContributionLedgerService{repo: repo, publisher: publisher}
+}
+
+// HandlePaymentVerified posts the contribution a verified payment pays for
+func (s *ContributionLedgerService) HandlePaymentVerified(data []byte) error {
+	var event events.PaymentVerified
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal PaymentVerified event: %w", err)
+	}
+
+	req, err := postContributionRequest(event.UserID, event.GoalID, event.Amount, event.Currency)
+	if err != nil {
+		return fmt.Errorf("invalid PaymentVerified event %s: %w", event.ID, err)
+	}
+	if paymentID, err := uuid.Parse(event.PaymentID); err == nil {
+		req.PaymentID = &paymentID
+	}
+	if contributionID, err := uuid.Parse(event.ContributionID); err == nil {
+		req.ContributionID = &contributionID
+	}
+
+	_, err = s.PostContribution(req)
+	return err
+}
+
+// HandleContributionConfirmed posts a confirmed contribution unless its payment already
+// did, then its platform fee. Payments made before contribution intents carry no
+// contribution ID; their posting is matched by goal, user and amount and linked to the
+// contribution instead.
+func (s *ContributionLedgerService) HandleContributionConfirmed(data []byte) error {
+	var event events.ContributionConfirmed
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal ContributionConfirmed event: %w", err)
+	}
+
+	contributionID, err := uuid.Parse(event.ContributionID)
+	if err != nil {
+		return fmt.Errorf("invalid contribution ID in ContributionConfirmed event %s: %w", event.ID, err)
+	}
+
+	if event.UserID == "" {
+		// Anonymous contributions don't name the contributor; their PaymentVerified does
+		log.Printf("Contribution %s is anonymous; leaving its posting to PaymentVerified", contributionID)
+	} else {
+		// The contributor paid the fee on top on add-on goals; events from before fees
+		// carry only the amount
+		paid := event.ChargeAmount
+		if paid == 0 {
+			paid = event.Amount
+		}
+		req, err := postContributionRequest(event.UserID, event.GoalID, paid, event.Currency)
+		if err != nil {
+			return fmt.Errorf("invalid ContributionConfirmed event %s: %w", event.ID, err)
+		}
+		req.ContributionID = &contributionID
+
+		if _, err := s.PostContribution(req); err != nil {
+			return err
+		}
+	}
+
+	if event.FeeAmount <= 0 {
+		return nil
+	}
+	goalID, err := uuid.Parse(event.GoalID)
+	if err != nil {
+		return fmt.Errorf("invalid goal ID in ContributionConfirmed event %s: %w", event.ID, err)
+	}
+	_, err = s.PostFee(dto.PostFeeRequest{
+		ContributionID: contributionID,
+		GoalID:         goalID,
+		Amount:         event.FeeAmount,
+		Currency:       event.Currency,
+	})
+	return err
+}
+
+// postContributionRequest builds a posting from event fields. Events from before the
+// currency was carried have none and are posted in DefaultCurrency.
+func postContributionRequest(userID, goalID string, amount int64, currency string) (dto.PostContributionRequest, error) {
+	var req dto.PostContributionRequest
+	var err error
+	if req.UserID, err = uuid.Parse(userID); err != nil {
+		return req, fmt.Errorf("invalid user ID: %w", err)
+	}
+	if req.GoalID, err = uuid.Parse(goalID); err != nil {
+		return req, fmt.Errorf("invalid goal ID: %w", err)
+	}
+	if amount <= 0 {
+		return req, fmt.Errorf("invalid amount %d", amount)
+	}
+	req.Amount = amount
+	req.Currency = currency
+	if req.Currency == "" {
+		req.Currency = DefaultCurrency
+	}
+	return req, nil
+}
+
+// PostContribution records a contribution, creating the user and goal accounts if
+// needed, and refreshes both balance snapshots. A contribution or payment that is already
+// posted is not posted again; nil is returned for it.
+func (s *ContributionLedgerService) PostContribution(req dto.PostContributionRequest) (*models.Transaction, error) {
+	if req.Currency == "" {
+		req.Currency = DefaultCurrency
+	}
+
+	var transaction *models.Transaction
+	var entries []models.LedgerEntry
+	accountTypes := make(map[uuid.UUID]models.AccountType, 2)
+	err := s.repo.Transaction(func(repo *repository.LedgerRepository) error {
+		// Locking the goal account serialises postings to the goal, so a contribution
+		// delivered through both events can't be posted twice
+		goalAccount, err := repo.GetOrCreateAccount(models.AccountTypeGoal, req.GoalID, req.Currency, true)
+		if err != nil {
+			return fmt.Errorf("failed to get goal account: %w", err)
+		}
+
+		posted, err := s.alreadyPosted(repo, req)
+		if err != nil || posted {
+			return err
+		}
+
+		userAccount, err := repo.GetOrCreateAccount(models.AccountTypeUser, req.UserID, req.Currency, false)
+		if err != nil {
+			return fmt.Errorf("failed to get user account: %w", err)
+		}
+		accountTypes[goalAccount.ID] = models.AccountTypeGoal
+		accountTypes[userAccount.ID] = models.AccountTypeUser
+
+		now := time.Now()
+		metadata := map[string]interface{}{
+			"goal_id": req.GoalID.String(),
+			"user_id": req.UserID.String(),
+		}
+		description := fmt.Sprintf("Contribution to goal %s", req.GoalID)
+		if req.ContributionID != nil {
+			metadata["contribution_id"] = req.ContributionID.String()
+			description = fmt.Sprintf("Contribution %s to goal %s", req.ContributionID, req.GoalID)
+		}
+		if req.PaymentID != nil {
+			metadata["payment_id"] = req.PaymentID.String()
+		}
+
+		transaction = &models.Transaction{
+			ID:              uuid.New(),
+			Type:            models.TransactionTypeContribution,
+			Description:     description,
+			Amount:          req.Amount,
+			Currency:        req.Currency,
+			Metadata:        metadata,
+			Status:          models.TransactionStatusCompleted,
+			TransactionDate: now,
+		}
+		entryMetadata := map[string]interface{}{}
+		for _, key := range []string{"contribution_id", "payment_id"} {
+			if value, ok := metadata[key]; ok {
+				entryMetadata[key] = value
+			}
+		}
+		entries = []models.LedgerEntry{
+			{
+				// Debit user account (money leaves the contributor)
+				ID:          uuid.New(),
+				AccountID:   userAccount.ID,
+				EntryType:   models.EntryTypeDebit,
+				Amount:      req.Amount,
+				Currency:    req.Currency,
+				Description: fmt.Sprintf("Contribution to goal %s", req.GoalID),
+				Metadata:    entryMetadata,
+				CreatedAt:   now,
+			},
+			{
+				// Credit goal account (increase goal balance)
+				ID:          uuid.New(),
+				AccountID:   goalAccount.ID,
+				EntryType:   models.EntryTypeCredit,
+				Amount:      req.Amount,
+				Currency:    req.Currency,
+				Description: fmt.Sprintf("Contribution from user %s", req.UserID),
+				Metadata:    entryMetadata,
+				CreatedAt:   now,
+			},
+		}
+		if err := repo.CreateTransaction(transaction, entries); err != nil {
+			return fmt.Errorf("failed to create contribution transaction: %w", err)
+		}
+
+		if _, err := repo.RefreshBalanceSnapshot(userAccount); err != nil {
+			return fmt.Errorf("failed to update user balance snapshot: %w", err)
+		}
+		if _, err := repo.RefreshBalanceSnapshot(goalAccount); err != nil {
+			return fmt.Errorf("failed to update goal balance snapshot: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return nil, nil
+	}
+
+	log.Printf("Posted contribution of %d %s to goal %s (transaction %s)", req.Amount, req.Currency, req.GoalID, transaction.ID)
+	s.publishEntriesCreated(entries, accountTypes)
+	return transaction, nil
+}
+
+// PostFee moves a contribution's platform fee from the goal's account to the platform's
+// revenue account, creating either if needed, and refreshes both balance snapshots. A fee
+// that is already posted is not posted again; nil is returned for it.
+func (s *ContributionLedgerService) PostFee(req dto.PostFeeRequest) (*models.Transaction, error) {
+	if req.Currency == "" {
+		req.Currency = DefaultCurrency
+	}
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("invalid fee amount %d", req.Amount)
+	}
+
+	var transaction *models.Transaction
+	var entries []models.LedgerEntry
+	accountTypes := make(map[uuid.UUID]models.AccountType, 2)
+	err := s.repo.Transaction(func(repo *repository.LedgerRepository) error {
+		// Locked as for contributions, so a redelivered event can't post the fee twice
+		goalAccount, err := repo.GetOrCreateAccount(models.AccountTypeGoal, req.GoalID, req.Currency, true)
+		if err != nil {
+			return fmt.Errorf("failed to get goal account: %w", err)
+		}
+
+		existing, err := repo.FindTransactionByMetadata(models.TransactionTypeFee, "contribution_id", req.ContributionID.String())
+		if err != nil || existing != nil {
+			return err
+		}
+
+		platformAccount, err := repo.GetOrCreateAccount(models.AccountTypeRevenue, PlatformAccountID, req.Currency, false)
+		if err != nil {
+			return fmt.Errorf("failed to get platform account: %w", err)
+		}
+		accountTypes[goalAccount.ID] = models.AccountTypeGoal
+		accountTypes[platformAccount.ID] = models.AccountTypeRevenue
+
+		now := time.Now()
+		transaction = &models.Transaction{
+			ID:          uuid.New(),
+			Type:        models.TransactionTypeFee,
+			Description: fmt.Sprintf("Platform fee on contribution %s to goal %s", req.ContributionID, req.GoalID),
+			Amount:      req.Amount,
+			Currency:    req.Currency,
+			Metadata: map[string]interface{}{
+				"goal_id":         req.GoalID.String(),
+				"contribution_id": req.ContributionID.String(),
+			},
+			Status:          models.TransactionStatusCompleted,
+			TransactionDate: now,
+		}
+		// The goal's entry carries the contribution ID so balance checks net it against
+		// the contribution it was taken from
+		entryMetadata := map[string]interface{}{"contribution_id": req.ContributionID.String()}
+		entries = []models.LedgerEntry{
+			{
+				// Debit goal account (the fee never reaches the goal)
+				ID:          uuid.New(),
+				AccountID:   goalAccount.ID,
+				EntryType:   models.EntryTypeDebit,
+				Amount:      req.Amount,
+				Currency:    req.Currency,
+				Description: fmt.Sprintf("Platform fee on contribution %s", req.ContributionID),
+				Metadata:    entryMetadata,
+				CreatedAt:   now,
+			},
+			{
+				// Credit platform account
+				ID:          uuid.New(),
+				AccountID:   platformAccount.ID,
+				EntryType:   models.EntryTypeCredit,
+				Amount:      req.Amount,
+				Currency:    req.Currency,
+				Description: fmt.Sprintf("Platform fee from goal %s", req.GoalID),
+				Metadata:    entryMetadata,
+				CreatedAt:   now,
+			},
+		}
+		if err := repo.CreateTransaction(transaction, entries); err != nil {
+			return fmt.Errorf("failed to create fee transaction: %w", err)
+		}
+
+		if _, err := repo.RefreshBalanceSnapshot(goalAccount); err != nil {
+			return fmt.Errorf("failed to update goal balance snapshot: %w", err)
+		}
+		if _, err := repo.RefreshBalanceSnapshot(platformAccount); err != nil {
+			return fmt.Errorf("failed to update platform balance snapshot: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return nil, nil
+	}
+
+	log.Printf("Posted platform fee of %d %s on contribution %s (transaction %s)", req.Amount, req.Currency, req.ContributionID, transaction.ID)
+	s.publishEntriesCreated(entries, accountTypes)
+	return transaction, nil
+}
+
+// alreadyPosted reports whether the contribution or payment has a posting. A posting made
+// without a contribution ID is linked to the contribution when it matches.
+func (s *ContributionLedgerService) alreadyPosted(repo *repository.LedgerRepository, req dto.PostContributionRequest) (bool, error) {
+	if req.ContributionID != nil {
+		existing, err := repo.FindTransactionByMetadata(models.TransactionTypeContribution, "contribution_id", req.ContributionID.String())
+		if err != nil || existing != nil {
+			return existing != nil, err
+		}
+	}
+	if req.PaymentID != nil {
+		existing, err := repo.FindTransactionByMetadata(models.TransactionTypeContribution, "payment_id", req.PaymentID.String())
+		if err != nil || existing != nil {
+			return existing != nil, err
+		}
+	}
+
+	// A contribution confirmed without its payment's ID may already be posted by that
+	// payment, before contribution IDs were carried on PaymentVerified
+	if req.ContributionID == nil || req.PaymentID != nil {
+		return false, nil
+	}
+	unlinked, err := repo.FindUnlinkedContribution(req.GoalID, req.UserID, req.Amount)
+	if err != nil || unlinked == nil {
+		return false, err
+	}
+
+	metadata := unlinked.Metadata
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["contribution_id"] = req.ContributionID.String()
+	if err := repo.UpdateTransactionMetadata(unlinked.ID, metadata); err != nil {
+		return false, fmt.Errorf("failed to link contribution transaction: %w", err)
+	}
+	return true, nil
+}
+
+// publishEntriesCreated announces committed ledger entries
+func (s *ContributionLedgerService) publishEntriesCreated(entries []models.LedgerEntry, accountTypes map[uuid.UUID]models.AccountType) {
+	for _, entry := range entries {
+		metrics.TrackLedgerEntryCreated(string(accountTypes[entry.AccountID]), float64(entry.Amount), entry.Currency)
+
+		if s.publisher == nil {
+			continue
+		}
+		event := events.LedgerEntryCreated{
+			ID:            uuid.New().String(),
+			LedgerEntryID: entry.ID.String(),
+			TransactionID: entry.TransactionID.String(),
+			AccountID:     entry.AccountID.String(),
+			Amount:        entry.Amount,
+			EntryType:     string(entry.EntryType),
+			CreatedAt:     entry.CreatedAt.Unix(),
+		}
+		if err := s.publisher.Publish("LedgerEntryCreated", event); err != nil {
+			log.Printf("Failed to publish LedgerEntryCreated event: %v", err)
+		}
+	}
+}
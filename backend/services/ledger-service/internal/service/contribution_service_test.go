@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPostContributionRequestCurrency(t *testing.T) {
+	userID, goalID := uuid.NewString(), uuid.NewString()
+	tests := []struct {
+		name     string
+		currency string
+		want     string
+	}{
+		{"event currency", "GHS", "GHS"},
+		{"event without currency", "", DefaultCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := postContributionRequest(userID, goalID, 5_000, tt.currency)
+			if err != nil {
+				t.Fatalf("postContributionRequest: %v", err)
+			}
+			if req.Currency != tt.want {
+				t.Errorf("currency = %q, want %q", req.Currency, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostContributionRequestRejectsInvalidFields(t *testing.T) {
+	valid := uuid.NewString()
+	tests := []struct {
+		name           string
+		userID, goalID string
+		amount         int64
+	}{
+		{"user ID", "not-a-uuid", valid, 5_000},
+		{"goal ID", valid, "not-a-uuid", 5_000},
+		{"zero amount", valid, valid, 0},
+		{"negative amount", valid, valid, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := postContributionRequest(tt.userID, tt.goalID, tt.amount, "NGN"); err == nil {
+				t.Errorf("postContributionRequest accepted an invalid %s", tt.name)
+			}
+		})
+	}
+}
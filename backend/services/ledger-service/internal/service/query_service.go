@@ -0,0 +1,292 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofund/ledger-service/internal/dto"
+	"github.com/gofund/ledger-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultEntriesPageSize = 20
+	maxEntriesPageSize     = 100
+)
+
+var (
+	ErrInvalidAccountType  = apperrors.Validation("invalid_account_type", "type must be GOAL or USER")
+	ErrInvalidCurrency     = apperrors.Validation("invalid_currency", "currency must be a 3-letter code")
+	ErrInvalidDateRange    = apperrors.Validation("invalid_date_range", "from must be before to")
+	ErrTransactionNotFound = apperrors.NotFound("transaction_not_found", "transaction not found")
+	ErrLedgerForbidden     = apperrors.Forbidden("forbidden", "not allowed to view this account")
+)
+
+// Viewer is the caller of a ledger query
+type Viewer struct {
+	UserID  uuid.UUID
+	IsAdmin bool
+}
+
+// LedgerQueryService reads balances, entries and transactions from the ledger
+type LedgerQueryService struct {
+	repo *repository.LedgerRepository
+}
+
+// NewLedgerQueryService creates a new ledger query service instance
+func NewLedgerQueryService(repo *repository.LedgerRepository) *LedgerQueryService {
+	return &LedgerQueryService{repo: repo}
+}
+
+// ParseAccountType validates an account type query value; only goal and user accounts
+// can be queried
+func ParseAccountType(value string) (models.AccountType, error) {
+	switch accountType := models.AccountType(strings.ToUpper(value)); accountType {
+	case models.AccountTypeGoal, models.AccountTypeUser:
+		return accountType, nil
+	default:
+		return "", ErrInvalidAccountType
+	}
+}
+
+// ParseCurrency validates a currency query value, defaulting to NGN
+func ParseCurrency(value string) (string, error) {
+	if value == "" {
+		return DefaultCurrency, nil
+	}
+	if len(value) != 3 {
+		return "", ErrInvalidCurrency
+	}
+	return strings.ToUpper(value), nil
+}
+
+// GetBalance returns an entity's balance from its snapshot, or computed from its entries
+// when there is no snapshot. Anyone signed in may see a goal's balance; a user's balance
+// is visible to that user and admins.
+func (s *LedgerQueryService) GetBalance(viewer Viewer, entityID uuid.UUID, accountType models.AccountType, currency string) (*dto.AccountBalance, error) {
+	if accountType == models.AccountTypeUser && !viewer.IsAdmin && viewer.UserID != entityID {
+		return nil, ErrLedgerForbidden
+	}
+
+	balance := &dto.AccountBalance{
+		EntityID:    entityID,
+		AccountType: accountType,
+		Currency:    currency,
+		Source:      dto.BalanceSourceEntries,
+	}
+
+	account, err := s.repo.GetAccount(accountType, entityID, currency)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Nothing posted yet
+			return balance, nil
+		}
+		return nil, err
+	}
+	balance.AccountID = &account.ID
+
+	snapshot, err := s.repo.GetBalanceSnapshot(account.ID)
+	switch {
+	case err == nil:
+		balance.Balance = snapshot.Balance
+		balance.Source = dto.BalanceSourceSnapshot
+		balance.UpdatedAt = &snapshot.UpdatedAt
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if balance.Balance, err = s.repo.ComputeBalance(account.ID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	return balance, nil
+}
+
+// ListEntries returns a page of an entity's ledger entries with their transactions. A
+// user's entries are visible to that user and admins; a goal's entries name its
+// contributors, so only admins see them.
+func (s *LedgerQueryService) ListEntries(viewer Viewer, query dto.ListEntriesQuery) (*dto.LedgerEntryPage, error) {
+	if !viewer.IsAdmin && (query.AccountType != models.AccountTypeUser || viewer.UserID != query.EntityID) {
+		return nil, ErrLedgerForbidden
+	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, ErrInvalidDateRange
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = defaultEntriesPageSize
+	}
+	if query.PageSize > maxEntriesPageSize {
+		query.PageSize = maxEntriesPageSize
+	}
+
+	page := &dto.LedgerEntryPage{
+		Items:    []dto.LedgerEntryItem{},
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}
+
+	account, err := s.repo.GetAccount(query.AccountType, query.EntityID, query.Currency)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return page, nil
+		}
+		return nil, err
+	}
+
+	entries, total, err := s.repo.ListEntries(account.ID, query.From, query.To, query.PageSize, (query.Page-1)*query.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	page.Total = total
+
+	transactionIDs := make([]uuid.UUID, 0, len(entries))
+	for _, entry := range entries {
+		transactionIDs = append(transactionIDs, entry.TransactionID)
+	}
+	transactions, err := s.repo.GetTransactionsByIDs(transactionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		item := dto.LedgerEntryItem{
+			ID:          entry.ID,
+			AccountID:   entry.AccountID,
+			EntryType:   entry.EntryType,
+			Amount:      entry.Amount,
+			Currency:    entry.Currency,
+			Description: entry.Description,
+			Metadata:    entry.Metadata,
+			CreatedAt:   entry.CreatedAt,
+		}
+		if transaction, ok := transactions[entry.TransactionID]; ok {
+			item.Transaction = &dto.TransactionSummary{
+				ID:              transaction.ID,
+				Type:            transaction.Type,
+				Description:     transaction.Description,
+				Status:          transaction.Status,
+				TransactionDate: transaction.TransactionDate,
+				Metadata:        transaction.Metadata,
+			}
+		}
+		page.Items = append(page.Items, item)
+	}
+
+	return page, nil
+}
+
+// GetTransaction returns a transaction with both legs. Admins may see any transaction;
+// a user only those with a leg on their own account.
+func (s *LedgerQueryService) GetTransaction(viewer Viewer, id uuid.UUID) (*dto.TransactionDetail, error) {
+	transaction, err := s.repo.GetTransactionWithEntries(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, err
+	}
+
+	accountIDs := make([]uuid.UUID, 0, len(transaction.LedgerEntries))
+	for _, entry := range transaction.LedgerEntries {
+		accountIDs = append(accountIDs, entry.AccountID)
+	}
+	accounts, err := s.repo.GetAccountsByIDs(accountIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &dto.TransactionDetail{
+		ID:              transaction.ID,
+		Type:            transaction.Type,
+		Description:     transaction.Description,
+		Amount:          transaction.Amount,
+		Currency:        transaction.Currency,
+		Status:          transaction.Status,
+		Metadata:        transaction.Metadata,
+		TransactionDate: transaction.TransactionDate,
+		CreatedAt:       transaction.CreatedAt,
+		Entries:         make([]dto.TransactionLeg, 0, len(transaction.LedgerEntries)),
+	}
+	involved := false
+	for _, entry := range transaction.LedgerEntries {
+		account := accounts[entry.AccountID]
+		if account.AccountType == models.AccountTypeUser && account.EntityID == viewer.UserID {
+			involved = true
+		}
+		detail.Entries = append(detail.Entries, dto.TransactionLeg{
+			ID:          entry.ID,
+			AccountID:   entry.AccountID,
+			AccountType: account.AccountType,
+			EntityID:    account.EntityID,
+			EntryType:   entry.EntryType,
+			Amount:      entry.Amount,
+			Currency:    entry.Currency,
+			Description: entry.Description,
+			CreatedAt:   entry.CreatedAt,
+		})
+	}
+	if !viewer.IsAdmin && !involved {
+		// Don't reveal that the transaction exists
+		return nil, ErrTransactionNotFound
+	}
+
+	return detail, nil
+}
+
+// GetGoalLedgerBalance returns a goal's computed balance, snapshot and entries. It is
+// served on the internal API only, so it does no access checks.
+func (s *LedgerQueryService) GetGoalLedgerBalance(goalID uuid.UUID, currency string) (*dto.GoalLedgerBalance, error) {
+	balance := &dto.GoalLedgerBalance{
+		GoalID:   goalID,
+		Currency: currency,
+		Entries:  []dto.GoalLedgerEntry{},
+	}
+
+	account, err := s.repo.GetAccount(models.AccountTypeGoal, goalID, currency)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return balance, nil
+		}
+		return nil, err
+	}
+	balance.AccountID = &account.ID
+
+	entries, err := s.repo.GetAllEntries(account.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		item := dto.GoalLedgerEntry{
+			ID:            entry.ID,
+			TransactionID: entry.TransactionID,
+			EntryType:     entry.EntryType,
+			Amount:        entry.Amount,
+			CreatedAt:     entry.CreatedAt,
+		}
+		if contributionID, ok := entry.Metadata["contribution_id"].(string); ok {
+			item.ContributionID = &contributionID
+		}
+		if entry.EntryType == models.EntryTypeCredit {
+			balance.Balance += entry.Amount
+		} else {
+			balance.Balance -= entry.Amount
+		}
+		balance.Entries = append(balance.Entries, item)
+	}
+
+	snapshot, err := s.repo.GetBalanceSnapshot(account.ID)
+	switch {
+	case err == nil:
+		balance.SnapshotBalance = &snapshot.Balance
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gofund/ledger-service/internal/dto"
+	"github.com/gofund/ledger-service/internal/repository"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+var ErrReconciliationRunning = apperrors.Conflict("reconciliation_running", "a reconciliation run is already in progress")
+
+// ReconciliationService compares every account's balance snapshot with the sum of its
+// ledger entries, which are the source of truth
+type ReconciliationService struct {
+	repo *repository.LedgerRepository
+	// autoCorrect lets scheduled runs rewrite mismatched snapshots
+	autoCorrect bool
+
+	running sync.Mutex
+}
+
+// NewReconciliationService creates a new reconciliation service instance
+func NewReconciliationService(repo *repository.LedgerRepository, autoCorrect bool) *ReconciliationService {
+	return &ReconciliationService{repo: repo, autoCorrect: autoCorrect}
+}
+
+// Run reconciles on every tick until ctx is cancelled. A non-positive interval disables
+// scheduled runs.
+func (s *ReconciliationService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Reconcile(!s.autoCorrect); err != nil {
+				log.Printf("Failed to reconcile ledger balances: %v", err)
+			}
+		}
+	}
+}
+
+// Reconcile checks every account and records a ReconciliationReport for each mismatch.
+// Unless dryRun, mismatched snapshots are recomputed from the entries.
+func (s *ReconciliationService) Reconcile(dryRun bool) (*dto.ReconciliationResult, error) {
+	if !s.running.TryLock() {
+		return nil, ErrReconciliationRunning
+	}
+	defer s.running.Unlock()
+
+	result := &dto.ReconciliationResult{
+		RunID:      uuid.New(),
+		DryRun:     dryRun,
+		Mismatches: []models.ReconciliationReport{},
+		StartedAt:  time.Now(),
+	}
+
+	checks, err := s.repo.GetAccountBalanceChecks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load account balances: %w", err)
+	}
+	result.AccountsChecked = len(checks)
+
+	for _, check := range checks {
+		report, ok := mismatch(check)
+		if !ok {
+			continue
+		}
+		report.RunID = result.RunID
+		report.CreatedAt = time.Now()
+
+		metrics.TrackReconciliationMismatch(check.AccountID.String(), float64(report.ExpectedBalance), float64(report.ActualBalance))
+		log.Printf("Ledger reconciliation mismatch: account %s expected %d, snapshot %d (diff %d, snapshot missing: %t)",
+			report.AccountID, report.ExpectedBalance, report.ActualBalance, report.Difference, report.SnapshotMissing)
+
+		if !dryRun {
+			account := &models.Account{ID: check.AccountID, Currency: check.Currency}
+			if _, err := s.repo.RefreshBalanceSnapshot(account); err != nil {
+				log.Printf("Failed to correct balance snapshot for account %s: %v", check.AccountID, err)
+			} else {
+				report.Corrected = true
+				result.CorrectedCount++
+			}
+		}
+
+		result.Mismatches = append(result.Mismatches, report)
+	}
+	result.MismatchCount = len(result.Mismatches)
+
+	if err := s.repo.CreateReconciliationReports(result.Mismatches); err != nil {
+		return nil, fmt.Errorf("failed to record reconciliation reports: %w", err)
+	}
+
+	result.CompletedAt = time.Now()
+	log.Printf("Ledger reconciliation %s checked %d accounts: %d mismatches, %d corrected",
+		result.RunID, result.AccountsChecked, result.MismatchCount, result.CorrectedCount)
+	return result, nil
+}
+
+// mismatch reports whether an account's snapshot disagrees with its entries. A missing
+// snapshot only disagrees when the entries don't sum to zero.
+func mismatch(check repository.AccountBalanceCheck) (models.ReconciliationReport, bool) {
+	report := models.ReconciliationReport{
+		AccountID:       check.AccountID,
+		Currency:        check.Currency,
+		ExpectedBalance: check.EntrySum,
+		SnapshotMissing: check.SnapshotBalance == nil,
+	}
+	if check.SnapshotBalance != nil {
+		report.ActualBalance = *check.SnapshotBalance
+	}
+	report.Difference = report.ActualBalance - report.ExpectedBalance
+
+	return report, report.Difference != 0
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofund/ledger-service/internal/repository"
+	"github.com/gofund/ledger-service/internal/testdb"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// reconciliationFixture is a ledger seeded with one account of each kind reconciliation
+// has to tell apart
+type reconciliationFixture struct {
+	db   *gorm.DB
+	repo *repository.LedgerRepository
+	// consistent has a snapshot matching its entries; empty has neither entries nor a
+	// snapshot
+	consistent, empty *models.Account
+	// drifted's snapshot is 1000 above its entries; unsnapshotted has entries but no snapshot
+	drifted, unsnapshotted *models.Account
+}
+
+func newReconciliationFixture(t *testing.T) *reconciliationFixture {
+	t.Helper()
+	db := testdb.Open(t)
+	f := &reconciliationFixture{db: db, repo: repository.NewLedgerRepository(db)}
+	f.consistent = f.account(t, 10_000, -3_000)
+	f.empty = f.account(t)
+	f.drifted = f.account(t, 5_000)
+	f.unsnapshotted = f.account(t, 2_000)
+
+	for _, account := range []*models.Account{f.consistent, f.drifted} {
+		if _, err := f.repo.RefreshBalanceSnapshot(account); err != nil {
+			t.Fatalf("RefreshBalanceSnapshot: %v", err)
+		}
+	}
+	// Something updated the snapshot without writing entries
+	if err := f.db.Model(&models.BalanceSnapshot{}).Where("account_id = ?", f.drifted.ID).
+		Update("balance", 6_000).Error; err != nil {
+		t.Fatalf("failed to corrupt the snapshot: %v", err)
+	}
+	return f
+}
+
+// account creates a goal account with an entry per amount: credits for positive amounts,
+// debits for negative ones
+func (f *reconciliationFixture) account(t *testing.T, amounts ...int64) *models.Account {
+	t.Helper()
+	account, err := f.repo.GetOrCreateAccount(models.AccountTypeGoal, uuid.New(), "NGN", false)
+	if err != nil {
+		t.Fatalf("GetOrCreateAccount: %v", err)
+	}
+	for _, amount := range amounts {
+		entry := models.LedgerEntry{AccountID: account.ID, EntryType: models.EntryTypeCredit, Amount: amount, Currency: "NGN", Description: "seeded"}
+		if amount < 0 {
+			entry.EntryType, entry.Amount = models.EntryTypeDebit, -amount
+		}
+		transaction := &models.Transaction{
+			Type:            models.TransactionTypeContribution,
+			Description:     "seeded",
+			Amount:          entry.Amount,
+			Currency:        "NGN",
+			TransactionDate: time.Now(),
+		}
+		if err := f.repo.CreateTransaction(transaction, []models.LedgerEntry{entry}); err != nil {
+			t.Fatalf("CreateTransaction: %v", err)
+		}
+	}
+	return account
+}
+
+func (f *reconciliationFixture) snapshot(t *testing.T, account *models.Account) *models.BalanceSnapshot {
+	t.Helper()
+	snapshot, err := f.repo.GetBalanceSnapshot(account.ID)
+	if err != nil {
+		return nil
+	}
+	return snapshot
+}
+
+// storedReports returns the reports a run recorded, by account
+func (f *reconciliationFixture) storedReports(t *testing.T, runID uuid.UUID) map[uuid.UUID]models.ReconciliationReport {
+	t.Helper()
+	var reports []models.ReconciliationReport
+	if err := f.db.Where("run_id = ?", runID).Find(&reports).Error; err != nil {
+		t.Fatalf("failed to load reports: %v", err)
+	}
+	byAccount := make(map[uuid.UUID]models.ReconciliationReport, len(reports))
+	for _, report := range reports {
+		byAccount[report.AccountID] = report
+	}
+	return byAccount
+}
+
+func TestReconcileDryRunReportsMismatches(t *testing.T) {
+	f := newReconciliationFixture(t)
+	s := NewReconciliationService(f.repo, false)
+
+	result, err := s.Reconcile(true)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if !result.DryRun || result.AccountsChecked != 4 || result.MismatchCount != 2 || result.CorrectedCount != 0 {
+		t.Errorf("result = %+v, want 4 accounts checked and 2 uncorrected mismatches", result)
+	}
+
+	stored := f.storedReports(t, result.RunID)
+	if len(stored) != 2 {
+		t.Fatalf("recorded %d reports, want 2", len(stored))
+	}
+	drifted := stored[f.drifted.ID]
+	if drifted.ExpectedBalance != 5_000 || drifted.ActualBalance != 6_000 || drifted.Difference != 1_000 || drifted.SnapshotMissing || drifted.Corrected {
+		t.Errorf("drifted account report = %+v, want expected 5000, actual 6000, diff 1000", drifted)
+	}
+	missing := stored[f.unsnapshotted.ID]
+	if missing.ExpectedBalance != 2_000 || missing.ActualBalance != 0 || missing.Difference != -2_000 || !missing.SnapshotMissing {
+		t.Errorf("unsnapshotted account report = %+v, want expected 2000 with the snapshot missing", missing)
+	}
+
+	if snapshot := f.snapshot(t, f.drifted); snapshot == nil || snapshot.Balance != 6_000 {
+		t.Errorf("dry run changed the drifted snapshot to %+v", snapshot)
+	}
+	if snapshot := f.snapshot(t, f.unsnapshotted); snapshot != nil {
+		t.Errorf("dry run created a snapshot %+v", snapshot)
+	}
+}
+
+func TestReconcileCorrectsSnapshots(t *testing.T) {
+	f := newReconciliationFixture(t)
+	s := NewReconciliationService(f.repo, false)
+
+	result, err := s.Reconcile(false)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if result.MismatchCount != 2 || result.CorrectedCount != 2 {
+		t.Errorf("result = %+v, want 2 mismatches corrected", result)
+	}
+	for accountID, report := range f.storedReports(t, result.RunID) {
+		if !report.Corrected {
+			t.Errorf("report for %s not marked corrected", accountID)
+		}
+	}
+
+	for account, want := range map[*models.Account]int64{f.consistent: 7_000, f.drifted: 5_000, f.unsnapshotted: 2_000} {
+		if snapshot := f.snapshot(t, account); snapshot == nil || snapshot.Balance != want {
+			t.Errorf("snapshot of %s = %+v, want balance %d", account.ID, snapshot, want)
+		}
+	}
+
+	again, err := s.Reconcile(true)
+	if err != nil {
+		t.Fatalf("second Reconcile: %v", err)
+	}
+	if again.MismatchCount != 0 {
+		t.Errorf("second run found %d mismatches after correction", again.MismatchCount)
+	}
+}
+
+func TestReconcileRefusesOverlappingRuns(t *testing.T) {
+	s := NewReconciliationService(nil, false)
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	if _, err := s.Reconcile(true); !errors.Is(err, ErrReconciliationRunning) {
+		t.Errorf("err = %v, want %v", err, ErrReconciliationRunning)
+	}
+}
@@ -1,14 +1,23 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofund/notifications-service/internal/config"
 	"github.com/gofund/notifications-service/internal/handlers"
+	"github.com/gofund/notifications-service/internal/middleware"
 	"github.com/gofund/notifications-service/internal/repository"
+	"github.com/gofund/notifications-service/internal/router"
 	"github.com/gofund/notifications-service/internal/service"
+	"github.com/gofund/shared/buildinfo"
 	"github.com/gofund/shared/database"
+	"github.com/gofund/shared/health"
 	"github.com/gofund/shared/messaging"
 	"github.com/gofund/shared/metrics"
 	"github.com/joho/godotenv"
@@ -24,6 +33,8 @@ func main() {
 	// Load configuration
 	cfg := config.LoadConfig()
 
+	buildinfo.LogStartup(cfg.DDService, cfg.DDVersion)
+
 	// Initialize Datadog tracing and metrics
 	if err := metrics.InitDatadog(cfg.DDService, cfg.DDEnv, cfg.DDVersion); err != nil {
 		log.Printf("Warning: Failed to initialize Datadog: %v", err)
@@ -45,14 +56,34 @@ func main() {
 	preferenceRepo := repository.NewPreferenceRepository(db)
 
 	// Initialize services
-	renderService := service.NewRenderService("internal/templates/emails")
-	emailService := service.NewEmailService(cfg, renderService)
+	renderer, err := service.NewEmailTemplateRenderer(cfg.FrontendURL)
+	if err != nil {
+		log.Fatalf("Failed to load email templates: %v", err)
+	}
+	emailSender, err := service.NewEmailSender(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure email provider: %v", err)
+	}
+	emailService := service.NewEmailService(emailSender, renderer)
+	userClient := service.NewUserClient(cfg.UsersServiceURL, cfg.UsersCacheTTL)
+	unsubscribeLinks := service.NewUnsubscribeLinks(cfg.UnsubscribeSecret, cfg.UnsubscribeBaseURL)
 
 	// Initialize notification service
 	notificationService := service.NewNotificationService(
 		notificationRepo,
 		preferenceRepo,
 		emailService,
+		userClient,
+		unsubscribeLinks,
+		cfg.SuppressDisabledInApp,
+		cfg.MaxStreamsPerUser,
+		service.EmailDispatcherConfig{
+			Workers:        cfg.EmailWorkers,
+			QueueSize:      cfg.EmailQueueSize,
+			RetryInterval:  cfg.EmailRetryInterval,
+			MaxRetries:     cfg.EmailMaxRetries,
+			DigestInterval: cfg.DigestInterval,
+		},
 	)
 
 	// Initialize event handler
@@ -78,17 +109,68 @@ func main() {
 	// Initialize HTTP router
 	r := gin.Default()
 
+	// Tag every request with an ID that logs and published events carry
+	r.Use(middleware.RequestID())
+
 	// Add Datadog APM middleware
 	r.Use(gintrace.Middleware(cfg.DDService))
 
+	// Drop identity headers that did not come from the gateway
+	if cfg.IdentityHeaderSecret == "" {
+		log.Printf("Warning: IDENTITY_HEADER_SECRET not set; X-User-* headers cannot be verified")
+	}
+	r.Use(middleware.IdentityGuard(cfg.IdentityHeaderSecret))
+
+	// Health checks: live only says the process is up, ready probes dependencies
+	readiness := []health.Checker{
+		health.SQLX("postgres", db),
+		health.RabbitMQ("rabbitmq", rabbitConn),
+	}
+	if cfg.QueueDepthThreshold > 0 {
+		readiness = append(readiness, health.QueueDepth(rabbitConn, cfg.RabbitMQQueue, cfg.QueueDepthThreshold))
+	}
+	r.GET("/health/live", gin.WrapF(health.LiveHandler(cfg.DDService)))
+	r.GET("/health/ready", gin.WrapF(health.ReadyHandler(health.DefaultTimeout, readiness...)))
+
+	// Build info
+	r.GET("/version", gin.WrapF(buildinfo.Handler(cfg.DDService, cfg.DDVersion)))
+
 	// Setup HTTP routes
-	setupRoutes(r, notificationService)
+	router.SetupRoutes(r, notificationService)
+	log.Println("HTTP routes configured")
+
+	// Start server with graceful shutdown
+	srv := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: r,
+	}
+	// Shutdown waits for open requests, so end the long-lived notification streams
+	srv.RegisterOnShutdown(notificationService.CloseStreams)
+
+	go func() {
+		log.Printf("Notifications Service starting on port %s", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.EmailDrainTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
 
-	// Start server
-	log.Printf("Notifications Service starting on port %s", cfg.Port)
-	if err := r.Run(":" + cfg.Port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	// Stop accepting new emails and let in-flight sends finish
+	if err := notificationService.Close(ctx); err != nil {
+		log.Printf("Email workers did not drain before timeout: %v", err)
 	}
+
+	log.Println("Server exiting")
 }
 
 // startEventConsumers starts consuming events from RabbitMQ
@@ -96,24 +178,36 @@ func startEventConsumers(consumer *messaging.RabbitMQConsumer, eventHandler *han
 	log.Println("Starting event consumers...")
 
 	// Payment events
-	if err := consumer.Consume("PaymentVerified", eventHandler.HandlePaymentVerified); err != nil {
+	if err := consumer.ConsumeContext("PaymentVerified", eventHandler.HandlePaymentVerified); err != nil {
 		log.Printf("Failed to consume PaymentVerified events: %v", err)
 	}
 
 	// Contribution events
-	if err := consumer.Consume("ContributionConfirmed", eventHandler.HandleContributionConfirmed); err != nil {
+	if err := consumer.ConsumeContext("ContributionConfirmed", eventHandler.HandleContributionConfirmed); err != nil {
 		log.Printf("Failed to consume ContributionConfirmed events: %v", err)
 	}
 
+	if err := consumer.Consume("RecurringContributionFailed", eventHandler.HandleRecurringContributionFailed); err != nil {
+		log.Printf("Failed to consume RecurringContributionFailed events: %v", err)
+	}
+
 	// Withdrawal events
 	if err := consumer.Consume("WithdrawalRequested", eventHandler.HandleWithdrawalRequested); err != nil {
 		log.Printf("Failed to consume WithdrawalRequested events: %v", err)
 	}
 
+	if err := consumer.Consume("WithdrawalApprovalRequested", eventHandler.HandleWithdrawalApprovalRequested); err != nil {
+		log.Printf("Failed to consume WithdrawalApprovalRequested events: %v", err)
+	}
+
 	if err := consumer.Consume("WithdrawalCompleted", eventHandler.HandleWithdrawalCompleted); err != nil {
 		log.Printf("Failed to consume WithdrawalCompleted events: %v", err)
 	}
 
+	if err := consumer.Consume("WithdrawalFailed", eventHandler.HandleWithdrawalFailed); err != nil {
+		log.Printf("Failed to consume WithdrawalFailed events: %v", err)
+	}
+
 	// Proof events
 	if err := consumer.Consume("ProofSubmitted", eventHandler.HandleProofSubmitted); err != nil {
 		log.Printf("Failed to consume ProofSubmitted events: %v", err)
@@ -123,11 +217,51 @@ func startEventConsumers(consumer *messaging.RabbitMQConsumer, eventHandler *han
 		log.Printf("Failed to consume ProofVoted events: %v", err)
 	}
 
+	if err := consumer.Consume("ProofRejected", eventHandler.HandleProofRejected); err != nil {
+		log.Printf("Failed to consume ProofRejected events: %v", err)
+	}
+
 	// Goal events
-	if err := consumer.Consume("GoalFunded", eventHandler.HandleGoalFunded); err != nil {
+	if err := consumer.ConsumeContext("GoalFunded", eventHandler.HandleGoalFunded); err != nil {
 		log.Printf("Failed to consume GoalFunded events: %v", err)
 	}
 
+	if err := consumer.Consume("GoalClosed", eventHandler.HandleGoalClosed); err != nil {
+		log.Printf("Failed to consume GoalClosed events: %v", err)
+	}
+
+	if err := consumer.Consume("GoalCancelled", eventHandler.HandleGoalCancelled); err != nil {
+		log.Printf("Failed to consume GoalCancelled events: %v", err)
+	}
+
+	if err := consumer.Consume("GoalBankDetailsChanged", eventHandler.HandleGoalBankDetailsChanged); err != nil {
+		log.Printf("Failed to consume GoalBankDetailsChanged events: %v", err)
+	}
+
+	if err := consumer.Consume("GoalClosedEarly", eventHandler.HandleGoalClosedEarly); err != nil {
+		log.Printf("Failed to consume GoalClosedEarly events: %v", err)
+	}
+
+	if err := consumer.Consume("GoalSuspended", eventHandler.HandleGoalSuspended); err != nil {
+		log.Printf("Failed to consume GoalSuspended events: %v", err)
+	}
+
+	if err := consumer.Consume("GoalCommented", eventHandler.HandleGoalCommented); err != nil {
+		log.Printf("Failed to consume GoalCommented events: %v", err)
+	}
+
+	if err := consumer.Consume("GoalUpdatePosted", eventHandler.HandleGoalUpdatePosted); err != nil {
+		log.Printf("Failed to consume GoalUpdatePosted events: %v", err)
+	}
+
+	if err := consumer.Consume("GoalDeadlineReached", eventHandler.HandleGoalDeadlineReached); err != nil {
+		log.Printf("Failed to consume GoalDeadlineReached events: %v", err)
+	}
+
+	if err := consumer.Consume("GoalDeadlineApproaching", eventHandler.HandleGoalDeadlineApproaching); err != nil {
+		log.Printf("Failed to consume GoalDeadlineApproaching events: %v", err)
+	}
+
 	// User events
 	if err := consumer.Consume("UserSignedUp", eventHandler.HandleUserSignedUp); err != nil {
 		log.Printf("Failed to consume UserSignedUp events: %v", err)
@@ -137,6 +271,10 @@ func startEventConsumers(consumer *messaging.RabbitMQConsumer, eventHandler *han
 		log.Printf("Failed to consume PasswordResetRequested events: %v", err)
 	}
 
+	if err := consumer.Consume("PasswordChanged", eventHandler.HandlePasswordChanged); err != nil {
+		log.Printf("Failed to consume PasswordChanged events: %v", err)
+	}
+
 	if err := consumer.Consume("EmailVerificationRequested", eventHandler.HandleEmailVerificationRequested); err != nil {
 		log.Printf("Failed to consume EmailVerificationRequested events: %v", err)
 	}
@@ -144,6 +282,10 @@ func startEventConsumers(consumer *messaging.RabbitMQConsumer, eventHandler *han
 	if err := consumer.Consume("KYCVerified", eventHandler.HandleKYCVerified); err != nil {
 		log.Printf("Failed to consume KYCVerified events: %v", err)
 	}
+
+	if err := consumer.Consume("KYCRejected", eventHandler.HandleKYCRejected); err != nil {
+		log.Printf("Failed to consume KYCRejected events: %v", err)
+	}
 	
 	// Refund events
 	if err := consumer.Consume("ContributionRefunded", eventHandler.HandleContributionRefunded); err != nil {
@@ -154,35 +296,17 @@ func startEventConsumers(consumer *messaging.RabbitMQConsumer, eventHandler *han
 		log.Printf("Failed to consume RefundInitiated events: %v", err)
 	}
 
-	if err := consumer.Consume("RefundCompleted", eventHandler.HandleRefundCompleted); err != nil {
-		log.Printf("Failed to consume RefundCompleted events: %v", err)
+	if err := consumer.Consume("RefundRequested", eventHandler.HandleRefundRequested); err != nil {
+		log.Printf("Failed to consume RefundRequested events: %v", err)
 	}
 
-	log.Println("Event consumers started successfully")
-}
-
-// setupRoutes configures all HTTP routes
-func setupRoutes(r *gin.Engine, notificationService service.NotificationService) {
-	// Initialize HTTP handler
-	notificationHandler := handlers.NewNotificationHandler(notificationService)
-
-	// Health check
-	r.GET("/api/v1/notifications/health", notificationHandler.HealthCheck)
-
-	// API routes (require authentication in production)
-	api := r.Group("/api/v1/notifications")
-	{
-		// Notification endpoints
-		api.GET("", notificationHandler.GetNotifications)
-		api.GET("/:id", notificationHandler.GetNotification)
-		api.PUT("/:id/read", notificationHandler.MarkAsRead)
-		api.DELETE("/:id", notificationHandler.DeleteNotification)
-		api.GET("/unread/count", notificationHandler.GetUnreadCount)
+	if err := consumer.Consume("RefundRequestDenied", eventHandler.HandleRefundRequestDenied); err != nil {
+		log.Printf("Failed to consume RefundRequestDenied events: %v", err)
+	}
 
-		// Preference endpoints
-		api.GET("/preferences", notificationHandler.GetPreferences)
-		api.PUT("/preferences", notificationHandler.UpdatePreferences)
+	if err := consumer.Consume("RefundCompleted", eventHandler.HandleRefundCompleted); err != nil {
+		log.Printf("Failed to consume RefundCompleted events: %v", err)
 	}
 
-	log.Println("HTTP routes configured")
+	log.Println("Event consumers started successfully")
 }
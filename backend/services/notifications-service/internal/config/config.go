@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
 // Config holds all configuration for the notifications service
@@ -22,7 +24,18 @@ type Config struct {
 	RabbitMQExchange string
 	RabbitMQQueue    string
 
-	// Email (SMTP)
+	// Readiness reports degraded once this many events wait in the queue; 0 disables the check
+	QueueDepthThreshold int
+
+	// Email provider: smtp, sendgrid, resend or noop
+	EmailProvider    string
+	EmailSendTimeout time.Duration
+	SendGridAPIKey   string
+	SendGridAPIURL   string
+	ResendAPIKey     string
+	ResendAPIURL     string
+
+	// Email (SMTP); SMTPFrom and SMTPFromName are the sender for every provider
 	SMTPHost     string
 	SMTPPort     string
 	SMTPUsername string
@@ -30,6 +43,34 @@ type Config struct {
 	SMTPFrom     string
 	SMTPFromName string
 
+	// Frontend base URL for links in emails
+	FrontendURL string
+
+	// Unsubscribe links: the token signing secret and the public unsubscribe endpoint
+	UnsubscribeSecret  string
+	UnsubscribeBaseURL string
+
+	// Email dispatcher
+	EmailWorkers       int
+	EmailQueueSize     int
+	EmailDrainTimeout  time.Duration
+	EmailRetryInterval time.Duration
+	EmailMaxRetries    int
+	DigestInterval     time.Duration
+
+	// Also skip the in-app record, not just the email, for categories a user turned off
+	SuppressDisabledInApp bool
+
+	// Notification streams
+	MaxStreamsPerUser int
+
+	// Users service, for resolving recipient emails
+	UsersServiceURL string
+	UsersCacheTTL   time.Duration
+
+	// Identity headers
+	IdentityHeaderSecret string
+
 	// Datadog
 	DDService string
 	DDEnv     string
@@ -55,13 +96,46 @@ func LoadConfig() *Config {
 		RabbitMQExchange: getEnv("RABBITMQ_EXCHANGE", "gofund_events"),
 		RabbitMQQueue:    getEnv("RABBITMQ_QUEUE", "notifications_queue"),
 
+		QueueDepthThreshold: getEnvInt("QUEUE_DEPTH_THRESHOLD", 1000),
+
 		// Email
+		EmailProvider:    getEnv("EMAIL_PROVIDER", "smtp"),
+		EmailSendTimeout: time.Duration(getEnvInt("EMAIL_SEND_TIMEOUT_SECONDS", 10)) * time.Second,
+		SendGridAPIKey:   getEnv("SENDGRID_API_KEY", ""),
+		SendGridAPIURL:   getEnv("SENDGRID_API_URL", "https://api.sendgrid.com/v3/mail/send"),
+		ResendAPIKey:     getEnv("RESEND_API_KEY", ""),
+		ResendAPIURL:     getEnv("RESEND_API_URL", "https://api.resend.com/emails"),
+
 		SMTPHost:     getEnv("SMTP_HOST", "smtp.gmail.com"),
 		SMTPPort:     getEnv("SMTP_PORT", "587"),
 		SMTPUsername: getEnv("SMTP_USERNAME", ""),
 		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
 		SMTPFrom:     getEnv("SMTP_FROM", "noreply@gofund.com"),
 		SMTPFromName: getEnv("SMTP_FROM_NAME", "GoFund"),
+		FrontendURL:  getEnv("FRONTEND_URL", "http://localhost:5173"),
+
+		UnsubscribeSecret:  getEnv("UNSUBSCRIBE_SECRET", ""),
+		UnsubscribeBaseURL: getEnv("UNSUBSCRIBE_BASE_URL", "http://localhost:8085/api/v1/notifications/unsubscribe"),
+
+		// Email dispatcher
+		EmailWorkers:       getEnvInt("EMAIL_WORKERS", 5),
+		EmailQueueSize:     getEnvInt("EMAIL_QUEUE_SIZE", 100),
+		EmailDrainTimeout:  time.Duration(getEnvInt("EMAIL_DRAIN_TIMEOUT_SECONDS", 10)) * time.Second,
+		EmailRetryInterval: time.Duration(getEnvInt("EMAIL_RETRY_INTERVAL_SECONDS", 60)) * time.Second,
+		EmailMaxRetries:    getEnvInt("EMAIL_MAX_RETRIES", 3),
+		DigestInterval:     time.Duration(getEnvInt("DIGEST_INTERVAL_SECONDS", 300)) * time.Second,
+
+		SuppressDisabledInApp: getEnv("SUPPRESS_DISABLED_IN_APP", "false") == "true",
+
+		// Notification streams
+		MaxStreamsPerUser: getEnvInt("MAX_STREAMS_PER_USER", 5),
+
+		// Users service
+		UsersServiceURL: getEnv("USERS_SERVICE_URL", "http://localhost:8084"),
+		UsersCacheTTL:   time.Duration(getEnvInt("USERS_CACHE_TTL_MINUTES", 10)) * time.Minute,
+
+		// Identity headers
+		IdentityHeaderSecret: getEnv("IDENTITY_HEADER_SECRET", ""),
 
 		// Datadog
 		DDService: getEnv("DD_SERVICE", "notifications-service"),
@@ -84,3 +158,12 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return fallback
+}
@@ -2,6 +2,7 @@ package dto
 
 import (
 	"github.com/gofund/notifications-service/internal/models"
+	"github.com/google/uuid"
 )
 
 // CreateNotificationRequest represents a request to create a notification
@@ -11,6 +12,16 @@ type CreateNotificationRequest struct {
 	Title   string                  `json:"title" binding:"required"`
 	Message string                  `json:"message" binding:"required"`
 	Data    map[string]interface{}  `json:"data"`
+	// Link is stored in the data as "link"; a malformed one fails the request
+	Link *models.NotificationLink `json:"link"`
+}
+
+// MaxBulkDelete caps how many notifications one bulk delete may name
+const MaxBulkDelete = 500
+
+// BulkDeleteRequest represents a request to delete several notifications at once
+type BulkDeleteRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required,min=1"`
 }
 
 // UpdatePreferencesRequest represents a request to update notification preferences
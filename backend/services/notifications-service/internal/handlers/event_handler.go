@@ -1,14 +1,18 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/gofund/notifications-service/internal/dto"
 	"github.com/gofund/notifications-service/internal/models"
 	"github.com/gofund/notifications-service/internal/service"
 	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/logger"
 )
 
 // EventHandler handles events from RabbitMQ
@@ -24,13 +28,13 @@ func NewEventHandler(notificationService service.NotificationService) *EventHand
 }
 
 // HandlePaymentVerified handles PaymentVerified events
-func (h *EventHandler) HandlePaymentVerified(data []byte) error {
+func (h *EventHandler) HandlePaymentVerified(ctx context.Context, data []byte) error {
 	var event events.PaymentVerified
 	if err := json.Unmarshal(data, &event); err != nil {
 		return fmt.Errorf("failed to unmarshal event: %w", err)
 	}
 
-	log.Printf("Processing PaymentVerified event: %s for user %s", event.ID, event.UserID)
+	logger.Printf(ctx, "Processing PaymentVerified event: %s for user %s", event.ID, event.UserID)
 
 	// Create notification
 	req := dto.CreateNotificationRequest{
@@ -44,6 +48,7 @@ func (h *EventHandler) HandlePaymentVerified(data []byte) error {
 			"amount":     event.Amount,
 			"email":      "", // This should be fetched from user service
 		},
+		Link: models.PaymentLink(event.PaymentID),
 	}
 
 	_, err := h.notificationService.CreateNotification(req)
@@ -51,29 +56,18 @@ func (h *EventHandler) HandlePaymentVerified(data []byte) error {
 		return fmt.Errorf("failed to create notification: %w", err)
 	}
 
-	log.Printf("PaymentVerified notification created for user %s", event.UserID)
+	logger.Printf(ctx, "PaymentVerified notification created for user %s", event.UserID)
 	return nil
 }
 
 // HandleContributionConfirmed handles ContributionConfirmed events
-func (h *EventHandler) HandleContributionConfirmed(data []byte) error {
-	// Define a simple event structure for contribution confirmed
-	var event struct {
-		ID            string `json:"id"`
-		UserID        string `json:"user_id"`
-		GoalID        string `json:"goal_id"`
-		GoalOwnerID   string `json:"goal_owner_id"`
-		Amount        int64  `json:"amount"`
-		ContributorName string `json:"contributor_name"`
-		GoalTitle     string `json:"goal_title"`
-		CreatedAt     int64  `json:"created_at"`
-	}
-
+func (h *EventHandler) HandleContributionConfirmed(ctx context.Context, data []byte) error {
+	var event events.ContributionConfirmed
 	if err := json.Unmarshal(data, &event); err != nil {
 		return fmt.Errorf("failed to unmarshal event: %w", err)
 	}
 
-	log.Printf("Processing ContributionConfirmed event: %s", event.ID)
+	logger.Printf(ctx, "Processing ContributionConfirmed event: %s", event.ID)
 
 	// Notify goal owner
 	ownerReq := dto.CreateNotificationRequest{
@@ -85,9 +79,18 @@ func (h *EventHandler) HandleContributionConfirmed(data []byte) error {
 			"goal_id":          event.GoalID,
 			"contributor_id":   event.UserID,
 			"contributor_name": event.ContributorName,
+			"is_anonymous":     event.IsAnonymous,
+			"goal_title":       event.GoalTitle,
 			"amount":           event.Amount,
 			"email":            "", // Should be fetched from user service
 		},
+		Link: models.GoalLink(event.GoalID),
+	}
+
+	// Never reveal an anonymous contributor, even if the producer left their details in
+	if event.IsAnonymous {
+		ownerReq.Data["contributor_id"] = ""
+		ownerReq.Data["contributor_name"] = "Anonymous"
 	}
 
 	_, err := h.notificationService.CreateNotification(ownerReq)
@@ -95,21 +98,13 @@ func (h *EventHandler) HandleContributionConfirmed(data []byte) error {
 		return fmt.Errorf("failed to create notification for goal owner: %w", err)
 	}
 
-	log.Printf("ContributionConfirmed notification created for goal owner %s", event.GoalOwnerID)
+	logger.Printf(ctx, "ContributionConfirmed notification created for goal owner %s", event.GoalOwnerID)
 	return nil
 }
 
 // HandleWithdrawalRequested handles WithdrawalRequested events
 func (h *EventHandler) HandleWithdrawalRequested(data []byte) error {
-	var event struct {
-		ID        string `json:"id"`
-		GoalID    string `json:"goal_id"`
-		OwnerID   string `json:"owner_id"`
-		Amount    int64  `json:"amount"`
-		GoalTitle string `json:"goal_title"`
-		CreatedAt int64  `json:"created_at"`
-	}
-
+	var event events.WithdrawalRequested
 	if err := json.Unmarshal(data, &event); err != nil {
 		return fmt.Errorf("failed to unmarshal event: %w", err)
 	}
@@ -127,6 +122,7 @@ func (h *EventHandler) HandleWithdrawalRequested(data []byte) error {
 			"amount":  event.Amount,
 			"email":   "", // Should be fetched from user service
 		},
+		Link: models.GoalLink(event.GoalID),
 	}
 
 	_, err := h.notificationService.CreateNotification(req)
@@ -138,17 +134,53 @@ func (h *EventHandler) HandleWithdrawalRequested(data []byte) error {
 	return nil
 }
 
-// HandleWithdrawalCompleted handles WithdrawalCompleted events
-func (h *EventHandler) HandleWithdrawalCompleted(data []byte) error {
-	var event struct {
-		ID        string `json:"id"`
-		GoalID    string `json:"goal_id"`
-		OwnerID   string `json:"owner_id"`
-		Amount    int64  `json:"amount"`
-		GoalTitle string `json:"goal_title"`
-		CreatedAt int64  `json:"created_at"`
+// HandleWithdrawalApprovalRequested handles WithdrawalApprovalRequested events, asking
+// each collaborator who has not yet approved the withdrawal to review it
+func (h *EventHandler) HandleWithdrawalApprovalRequested(data []byte) error {
+	var event events.WithdrawalApprovalRequested
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing WithdrawalApprovalRequested event: %s", event.ID)
+
+	message := fmt.Sprintf("A withdrawal of ₦%.2f from '%s' needs your approval (%d of %d approvals so far).",
+		float64(event.Amount)/100, event.GoalTitle, event.ApprovalsReceived, event.RequiredApprovals)
+
+	var failed int
+	for _, userID := range event.ApproverIDs {
+		req := dto.CreateNotificationRequest{
+			UserID:  userID,
+			Type:    models.NotificationTypeWithdrawalApprovalRequested,
+			Title:   "Withdrawal Awaiting Your Approval",
+			Message: message,
+			Data: map[string]interface{}{
+				"goal_id":       event.GoalID,
+				"withdrawal_id": event.WithdrawalID,
+				"requested_by":  event.RequestedBy,
+				"amount":        event.Amount,
+				"email":         "", // Should be fetched from user service
+			},
+			Link: models.GoalLink(event.GoalID),
+		}
+
+		if _, err := h.notificationService.CreateNotification(req); err != nil {
+			log.Printf("Failed to create %s notification for user %s: %v", req.Type, userID, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to create %d of %d %s notifications", failed, len(event.ApproverIDs), models.NotificationTypeWithdrawalApprovalRequested)
 	}
 
+	log.Printf("WithdrawalApprovalRequested notifications created for %d approvers of withdrawal %s", len(event.ApproverIDs), event.WithdrawalID)
+	return nil
+}
+
+// HandleWithdrawalCompleted handles WithdrawalCompleted events
+func (h *EventHandler) HandleWithdrawalCompleted(data []byte) error {
+	var event events.WithdrawalCompleted
 	if err := json.Unmarshal(data, &event); err != nil {
 		return fmt.Errorf("failed to unmarshal event: %w", err)
 	}
@@ -166,6 +198,7 @@ func (h *EventHandler) HandleWithdrawalCompleted(data []byte) error {
 			"amount":  event.Amount,
 			"email":   "", // Should be fetched from user service
 		},
+		Link: models.GoalLink(event.GoalID),
 	}
 
 	_, err := h.notificationService.CreateNotification(req)
@@ -177,6 +210,39 @@ func (h *EventHandler) HandleWithdrawalCompleted(data []byte) error {
 	return nil
 }
 
+// HandleWithdrawalFailed handles WithdrawalFailed events
+func (h *EventHandler) HandleWithdrawalFailed(data []byte) error {
+	var event events.WithdrawalFailed
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing WithdrawalFailed event: %s", event.ID)
+
+	// Notify goal owner
+	req := dto.CreateNotificationRequest{
+		UserID:  event.OwnerID,
+		Type:    models.NotificationTypeWithdrawalFailed,
+		Title:   "Withdrawal Failed",
+		Message: fmt.Sprintf("Your withdrawal of ₦%.2f for '%s' could not be completed. The funds remain available in your goal.", float64(event.Amount)/100, event.GoalTitle),
+		Data: map[string]interface{}{
+			"goal_id":       event.GoalID,
+			"withdrawal_id": event.WithdrawalID,
+			"amount":        event.Amount,
+			"reason":        event.Reason,
+		},
+		Link: models.GoalLink(event.GoalID),
+	}
+
+	_, err := h.notificationService.CreateNotification(req)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	log.Printf("WithdrawalFailed notification created for user %s", event.OwnerID)
+	return nil
+}
+
 // HandleProofSubmitted handles ProofSubmitted events
 func (h *EventHandler) HandleProofSubmitted(data []byte) error {
 	var event events.ProofSubmitted
@@ -229,6 +295,7 @@ func (h *EventHandler) HandleProofVoted(data []byte) error {
 			"vote":     event.Vote,
 			"email":    "", // Should be fetched from user service
 		},
+		Link: models.ProofLink(event.ProofID),
 	}
 
 	_, err := h.notificationService.CreateNotification(req)
@@ -241,18 +308,386 @@ func (h *EventHandler) HandleProofVoted(data []byte) error {
 }
 
 // HandleGoalFunded handles GoalFunded events
-func (h *EventHandler) HandleGoalFunded(data []byte) error {
+func (h *EventHandler) HandleGoalFunded(ctx context.Context, data []byte) error {
 	var event events.GoalFunded
 	if err := json.Unmarshal(data, &event); err != nil {
 		return fmt.Errorf("failed to unmarshal event: %w", err)
 	}
 
-	log.Printf("Processing GoalFunded event: %s", event.ID)
+	logger.Printf(ctx, "Processing GoalFunded event: %s", event.ID)
 
 	// Note: In a real implementation, you'd fetch the goal owner and contributors
-	// For now, we'll just log this
-	log.Printf("GoalFunded for goal %s - owner and contributors should be notified", event.GoalID)
-	
+	// For now, only the goal's watchers are notified
+	logger.Printf(ctx, "GoalFunded for goal %s - owner and contributors should be notified", event.GoalID)
+
+	return h.notifyWatchers(event.WatcherIDs, models.NotificationTypeGoalFunded,
+		"Goal Fully Funded",
+		fmt.Sprintf("\"%s\", a goal you are watching, has reached its target.", event.Title),
+		event.GoalID)
+}
+
+// HandleGoalClosed handles GoalClosed events
+func (h *EventHandler) HandleGoalClosed(data []byte) error {
+	var event events.GoalClosed
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing GoalClosed event: %s", event.ID)
+
+	return h.notifyContributors(event.ContributorIDs, models.NotificationTypeGoalClosed,
+		"Goal Closed",
+		fmt.Sprintf("\"%s\", a goal you contributed to, is no longer accepting contributions.", event.Title),
+		event.GoalID)
+}
+
+// HandleProofRejected handles ProofRejected events: the goal owner hears why contributors
+// were not satisfied, and contributors are pointed at the refund flow
+func (h *EventHandler) HandleProofRejected(data []byte) error {
+	var event events.ProofRejected
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing ProofRejected event: %s", event.ID)
+
+	message := fmt.Sprintf("Contributors rejected your proof \"%s\" for \"%s\" (%d not satisfied, %d satisfied). Withdrawals against it are on hold until a new proof is verified.",
+		event.ProofTitle, event.Title, event.UnsatisfiedVotes, event.SatisfiedVotes)
+	if len(event.Comments) > 0 {
+		message += "\n\nWhat contributors said:\n- " + strings.Join(event.Comments, "\n- ")
+	}
+
+	req := dto.CreateNotificationRequest{
+		UserID:  event.OwnerID,
+		Type:    models.NotificationTypeProofRejected,
+		Title:   "Your Proof Was Rejected",
+		Message: message,
+		Data: map[string]interface{}{
+			"goal_id":           event.GoalID,
+			"milestone_id":      event.MilestoneID,
+			"proof_id":          event.ProofID,
+			"satisfied_votes":   event.SatisfiedVotes,
+			"unsatisfied_votes": event.UnsatisfiedVotes,
+			"comments":          event.Comments,
+			"email":             "", // Should be fetched from user service
+		},
+		Link: models.ProofLink(event.ProofID),
+	}
+	if _, err := h.notificationService.CreateNotification(req); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	// The refund itself is still issued by the owner; the link takes contributors to it
+	return h.notifyGoalUsers(event.ContributorIDs, "contributors", models.NotificationTypeProofRejected,
+		"Proof Rejected",
+		fmt.Sprintf("Contributors rejected the proof \"%s\" for \"%s\", a goal you contributed to. Withdrawals are on hold until a new proof is verified, and you can request a refund.", event.ProofTitle, event.Title),
+		event.GoalID,
+		&models.NotificationLink{
+			Type: models.LinkTypeProof,
+			ID:   event.ProofID,
+			Secondary: &models.NotificationAction{
+				Action: models.ActionRequestRefund,
+				Type:   models.LinkTypeGoal,
+				ID:     event.GoalID,
+			},
+		},
+		map[string]interface{}{
+			"proof_id":    event.ProofID,
+			"refund_path": event.RefundPath,
+		})
+}
+
+// HandleGoalCancelled handles GoalCancelled events
+func (h *EventHandler) HandleGoalCancelled(data []byte) error {
+	var event events.GoalCancelled
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing GoalCancelled event: %s", event.ID)
+
+	return h.notifyContributors(event.ContributorIDs, models.NotificationTypeGoalCancelled,
+		"Goal Cancelled",
+		fmt.Sprintf("\"%s\", a goal you contributed to, has been cancelled by its owner. You will be notified if a refund is issued.", event.Title),
+		event.GoalID)
+}
+
+// HandleGoalBankDetailsChanged handles GoalBankDetailsChanged events
+func (h *EventHandler) HandleGoalBankDetailsChanged(data []byte) error {
+	var event events.GoalBankDetailsChanged
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing GoalBankDetailsChanged event: %s", event.ID)
+
+	return h.notifyContributors(event.ContributorIDs, models.NotificationTypeGoalBankDetailsChanged,
+		"Goal Deposit Account Changed",
+		fmt.Sprintf("The owner of \"%s\", a goal you contributed to, changed its deposit account to %s account ending %s. The bank confirmed the account is in the owner's name.", event.Title, event.BankName, event.AccountNumberLast4),
+		event.GoalID)
+}
+
+// HandleGoalClosedEarly handles GoalClosedEarly events
+func (h *EventHandler) HandleGoalClosedEarly(data []byte) error {
+	var event events.GoalClosedEarly
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing GoalClosedEarly event: %s", event.ID)
+
+	// Notify confirmed contributors and anyone with a payment still in flight
+	recipients := make([]string, 0, len(event.ContributorIDs)+len(event.PendingUserIDs))
+	seen := make(map[string]bool)
+	for _, userID := range append(event.ContributorIDs, event.PendingUserIDs...) {
+		if !seen[userID] {
+			seen[userID] = true
+			recipients = append(recipients, userID)
+		}
+	}
+
+	return h.notifyContributors(recipients, models.NotificationTypeGoalClosed,
+		"Goal Fully Funded",
+		fmt.Sprintf("\"%s\" has reached its target and is no longer accepting contributions. Payments already in progress will still be counted.", event.Title),
+		event.GoalID)
+}
+
+// HandleGoalSuspended handles GoalSuspended events
+func (h *EventHandler) HandleGoalSuspended(data []byte) error {
+	var event events.GoalSuspended
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing GoalSuspended event: %s", event.ID)
+
+	message := fmt.Sprintf("\"%s\" has been suspended by an administrator. It cannot receive contributions or withdrawals until the suspension is lifted.", event.Title)
+	if event.Reason != "" {
+		message += " Reason: " + event.Reason
+	}
+
+	// Notify goal owner
+	req := dto.CreateNotificationRequest{
+		UserID:  event.OwnerID,
+		Type:    models.NotificationTypeGoalSuspended,
+		Title:   "Goal Suspended",
+		Message: message,
+		Data: map[string]interface{}{
+			"goal_id": event.GoalID,
+			"reason":  event.Reason,
+		},
+		Link: models.GoalLink(event.GoalID),
+	}
+
+	if _, err := h.notificationService.CreateNotification(req); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	log.Printf("GoalSuspended notification created for user %s", event.OwnerID)
+	return nil
+}
+
+// HandleGoalCommented handles GoalCommented events
+func (h *EventHandler) HandleGoalCommented(data []byte) error {
+	var event events.GoalCommented
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing GoalCommented event: %s", event.ID)
+
+	title := "New Comment"
+	message := fmt.Sprintf("Someone commented on \"%s\": %s", event.Title, event.Excerpt)
+	if event.ParentID != "" {
+		title = "New Reply"
+		message = fmt.Sprintf("Someone replied to a comment on \"%s\": %s", event.Title, event.Excerpt)
+	}
+
+	// Notify goal owner
+	req := dto.CreateNotificationRequest{
+		UserID:  event.OwnerID,
+		Type:    models.NotificationTypeGoalCommented,
+		Title:   title,
+		Message: message,
+		Data: map[string]interface{}{
+			"goal_id":      event.GoalID,
+			"comment_id":   event.CommentID,
+			"parent_id":    event.ParentID,
+			"commenter_id": event.CommenterID,
+		},
+		Link: models.GoalLink(event.GoalID),
+	}
+
+	if _, err := h.notificationService.CreateNotification(req); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	log.Printf("GoalCommented notification created for user %s", event.OwnerID)
+	return nil
+}
+
+// HandleGoalUpdatePosted handles GoalUpdatePosted events. Contributors who turned off goal
+// notifications are skipped.
+func (h *EventHandler) HandleGoalUpdatePosted(data []byte) error {
+	var event events.GoalUpdatePosted
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing GoalUpdatePosted event: %s", event.ID)
+
+	contributorMessage := fmt.Sprintf("\"%s\", a goal you contributed to, posted an update: %s", event.Title, event.UpdateTitle)
+	watcherMessage := fmt.Sprintf("\"%s\", a goal you are watching, posted an update: %s", event.Title, event.UpdateTitle)
+
+	// Contributors who also watch the goal hear about the update once
+	recipients := make(map[string]string, len(event.ContributorIDs)+len(event.WatcherIDs))
+	for _, userID := range event.WatcherIDs {
+		recipients[userID] = watcherMessage
+	}
+	for _, userID := range event.ContributorIDs {
+		recipients[userID] = contributorMessage
+	}
+
+	var failed, sent int
+	for userID, message := range recipients {
+		preferences, err := h.notificationService.GetUserPreferences(userID)
+		if err != nil {
+			log.Printf("Failed to get preferences for user %s, notifying anyway: %v", userID, err)
+		} else if !preferences.Allows(models.NotificationTypeGoalUpdatePosted) {
+			continue
+		}
+
+		req := dto.CreateNotificationRequest{
+			UserID:  userID,
+			Type:    models.NotificationTypeGoalUpdatePosted,
+			Title:   "New Goal Update",
+			Message: message,
+			Data: map[string]interface{}{
+				"goal_id":   event.GoalID,
+				"update_id": event.UpdateID,
+				"email":     "", // Should be fetched from user service
+			},
+			Link: models.GoalLink(event.GoalID),
+		}
+
+		if _, err := h.notificationService.CreateNotification(req); err != nil {
+			log.Printf("Failed to create %s notification for user %s: %v", req.Type, userID, err)
+			failed++
+			continue
+		}
+		sent++
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to create %d of %d %s notifications", failed, len(recipients), models.NotificationTypeGoalUpdatePosted)
+	}
+
+	log.Printf("GoalUpdatePosted notifications created for %d contributors and watchers of goal %s", sent, event.GoalID)
+	return nil
+}
+
+// HandleGoalDeadlineReached handles GoalDeadlineReached events
+func (h *EventHandler) HandleGoalDeadlineReached(data []byte) error {
+	var event events.GoalDeadlineReached
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing GoalDeadlineReached event: %s", event.ID)
+
+	ownerMessage := fmt.Sprintf("\"%s\" has reached its deadline and met its target of %d. It is now closed to new contributions.", event.Title, event.TargetAmount)
+	contributorMessage := fmt.Sprintf("\"%s\", a goal you contributed to, has reached its deadline and met its target.", event.Title)
+	if !event.TargetMet {
+		ownerMessage = fmt.Sprintf("\"%s\" has reached its deadline having raised %d of its %d target. It is now closed to new contributions.", event.Title, event.TotalAmount, event.TargetAmount)
+		contributorMessage = fmt.Sprintf("\"%s\", a goal you contributed to, has reached its deadline without meeting its target. You will be notified if a refund is issued.", event.Title)
+	}
+
+	// Notify goal owner
+	req := dto.CreateNotificationRequest{
+		UserID:  event.OwnerID,
+		Type:    models.NotificationTypeGoalDeadlineReached,
+		Title:   "Goal Deadline Reached",
+		Message: ownerMessage,
+		Data: map[string]interface{}{
+			"goal_id":       event.GoalID,
+			"target_amount": event.TargetAmount,
+			"total_amount":  event.TotalAmount,
+			"target_met":    event.TargetMet,
+			"email":         "", // Should be fetched from user service
+		},
+		Link: models.GoalLink(event.GoalID),
+	}
+
+	if _, err := h.notificationService.CreateNotification(req); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return h.notifyContributors(event.ContributorIDs, models.NotificationTypeGoalDeadlineReached,
+		"Goal Deadline Reached", contributorMessage, event.GoalID)
+}
+
+// HandleGoalDeadlineApproaching handles GoalDeadlineApproaching events
+func (h *EventHandler) HandleGoalDeadlineApproaching(data []byte) error {
+	var event events.GoalDeadlineApproaching
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing GoalDeadlineApproaching event: %s", event.ID)
+
+	deadline := time.Unix(event.Deadline, 0).UTC().Format("Jan 2, 2006 15:04 MST")
+	return h.notifyWatchers(event.WatcherIDs, models.NotificationTypeGoalDeadlineApproaching,
+		"Goal Closing Soon",
+		fmt.Sprintf("\"%s\", a goal you are watching, closes on %s. Contribute before it ends.", event.Title, deadline),
+		event.GoalID)
+}
+
+// notifyContributors creates the same goal notification for every contributor
+func (h *EventHandler) notifyContributors(contributorIDs []string, notificationType models.NotificationType, title, message, goalID string) error {
+	return h.notifyGoalUsers(contributorIDs, "contributors", notificationType, title, message, goalID, nil, nil)
+}
+
+// notifyWatchers creates the same goal notification for every watcher
+func (h *EventHandler) notifyWatchers(watcherIDs []string, notificationType models.NotificationType, title, message, goalID string) error {
+	return h.notifyGoalUsers(watcherIDs, "watchers", notificationType, title, message, goalID, nil, nil)
+}
+
+// notifyGoalUsers creates the same goal notification for every user; audience names them
+// in the log. extra is added to each notification's data, and link replaces the default
+// link to the goal.
+func (h *EventHandler) notifyGoalUsers(userIDs []string, audience string, notificationType models.NotificationType, title, message, goalID string, link *models.NotificationLink, extra map[string]interface{}) error {
+	if link == nil {
+		link = models.GoalLink(goalID)
+	}
+
+	var failed int
+	for _, userID := range userIDs {
+		req := dto.CreateNotificationRequest{
+			UserID:  userID,
+			Type:    notificationType,
+			Title:   title,
+			Message: message,
+			Data: map[string]interface{}{
+				"goal_id": goalID,
+				"email":   "", // Should be fetched from user service
+			},
+			Link: link,
+		}
+		for key, value := range extra {
+			req.Data[key] = value
+		}
+
+		if _, err := h.notificationService.CreateNotification(req); err != nil {
+			log.Printf("Failed to create %s notification for user %s: %v", notificationType, userID, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to create %d of %d %s notifications", failed, len(userIDs), notificationType)
+	}
+
+	log.Printf("%s notifications created for %d %s of goal %s", notificationType, len(userIDs), audience, goalID)
 	return nil
 }
 
@@ -321,6 +756,35 @@ func (h *EventHandler) HandlePasswordResetRequested(data []byte) error {
 	return nil
 }
 
+// HandlePasswordChanged handles PasswordChanged events
+func (h *EventHandler) HandlePasswordChanged(data []byte) error {
+	var event events.PasswordChanged
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing PasswordChanged event: %s for user %s", event.ID, event.UserID)
+
+	// Create notification
+	req := dto.CreateNotificationRequest{
+		UserID:  event.UserID,
+		Type:    models.NotificationTypePasswordChanged,
+		Title:   "Your Password Was Changed",
+		Message: "The password for your account was just changed and your other sessions were signed out. If this wasn't you, reset your password immediately.",
+		Data: map[string]interface{}{
+			"email": event.Email,
+		},
+	}
+
+	_, err := h.notificationService.CreateNotification(req)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	log.Printf("PasswordChanged notification created for user %s", event.UserID)
+	return nil
+}
+
 // HandleEmailVerificationRequested handles EmailVerificationRequested events
 func (h *EventHandler) HandleEmailVerificationRequested(data []byte) error {
 	var event events.EmailVerificationRequested
@@ -381,6 +845,36 @@ func (h *EventHandler) HandleKYCVerified(data []byte) error {
 	return nil
 }
 
+// HandleKYCRejected handles KYCRejected events
+func (h *EventHandler) HandleKYCRejected(data []byte) error {
+	var event events.KYCRejected
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing KYCRejected event: %s for user %s", event.ID, event.UserID)
+
+	// Create notification
+	req := dto.CreateNotificationRequest{
+		UserID:  event.UserID,
+		Type:    models.NotificationTypeKYCRejected,
+		Title:   "KYC Verification Unsuccessful",
+		Message: fmt.Sprintf("We could not verify your identity: %s. Please submit your documents again.", event.Reason),
+		Data: map[string]interface{}{
+			"submission_id": event.SubmissionID,
+			"reason":        event.Reason,
+		},
+	}
+
+	_, err := h.notificationService.CreateNotification(req)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	log.Printf("KYCRejected notification created for user %s", event.UserID)
+	return nil
+}
+
 // HandleContributionRefunded handles ContributionRefunded events
 func (h *EventHandler) HandleContributionRefunded(data []byte) error {
 	var event events.ContributionRefunded
@@ -394,14 +888,15 @@ func (h *EventHandler) HandleContributionRefunded(data []byte) error {
 	req := dto.CreateNotificationRequest{
 		UserID:  event.UserID,
 		Type:    models.NotificationTypeRefundCompleted,
-		Title:   "Refund Processed",
-		Message: fmt.Sprintf("Your refund of ₦%.2f for your contribution has been processed successfully.", float64(event.RefundAmount)/100),
+		Title:   "Refund Completed",
+		Message: fmt.Sprintf("Your refund of ₦%.2f has been paid to your bank account.", float64(event.RefundAmount)/100),
 		Data: map[string]interface{}{
 			"contribution_id": event.ContributionID,
 			"goal_id":         event.GoalID,
 			"amount":          event.RefundAmount,
 			"email":           "", // This should be fetched from user service if needed for email
 		},
+		Link: models.GoalLink(event.GoalID),
 	}
 
 	_, err := h.notificationService.CreateNotification(req)
@@ -413,6 +908,44 @@ func (h *EventHandler) HandleContributionRefunded(data []byte) error {
 	return nil
 }
 
+// HandleRecurringContributionFailed handles RecurringContributionFailed events
+func (h *EventHandler) HandleRecurringContributionFailed(data []byte) error {
+	var event events.RecurringContributionFailed
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing RecurringContributionFailed event: %s for user %s", event.ID, event.UserID)
+
+	title := "Recurring Contribution Paused"
+	next := "Resume it once the problem is fixed to keep contributing."
+	if event.Status == "CANCELLED" {
+		title = "Recurring Contribution Cancelled"
+		next = "The goal no longer accepts contributions, so it will not be charged again."
+	}
+
+	req := dto.CreateNotificationRequest{
+		UserID:  event.UserID,
+		Type:    models.NotificationTypeRecurringChargeFailed,
+		Title:   title,
+		Message: fmt.Sprintf("Your recurring contribution of ₦%.2f to \"%s\" was stopped: %s. %s", float64(event.Amount)/100, event.GoalTitle, event.Reason, next),
+		Data: map[string]interface{}{
+			"recurring_contribution_id": event.RecurringContributionID,
+			"goal_id":                   event.GoalID,
+			"amount":                    event.Amount,
+			"email":                     "", // This should be fetched from user service if needed for email
+		},
+		Link: models.GoalLink(event.GoalID),
+	}
+
+	if _, err := h.notificationService.CreateNotification(req); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	log.Printf("RecurringContributionFailed notification created for user %s", event.UserID)
+	return nil
+}
+
 // HandleRefundInitiated handles RefundInitiated events
 func (h *EventHandler) HandleRefundInitiated(data []byte) error {
 	var event events.RefundInitiated
@@ -422,6 +955,12 @@ func (h *EventHandler) HandleRefundInitiated(data []byte) error {
 
 	log.Printf("Processing RefundInitiated event: %s for goal %s", event.ID, event.GoalID)
 
+	// A refund settling a contributor's request is news to the contributor; the owner
+	// either approved it or was told when it was requested
+	if event.RequestedBy != "" {
+		return h.notifyRefundRequestApproved(event)
+	}
+
 	// Notify goal owner
 	req := dto.CreateNotificationRequest{
 		UserID:  event.InitiatedBy,
@@ -434,6 +973,7 @@ func (h *EventHandler) HandleRefundInitiated(data []byte) error {
 			"amount":    event.TotalRefundAmount,
 			"email":     "", 
 		},
+		Link: models.RefundLink(event.RefundID),
 	}
 
 	_, err := h.notificationService.CreateNotification(req)
@@ -445,6 +985,104 @@ func (h *EventHandler) HandleRefundInitiated(data []byte) error {
 	return nil
 }
 
+// notifyRefundRequestApproved tells a contributor the refund they requested is on its way
+func (h *EventHandler) notifyRefundRequestApproved(event events.RefundInitiated) error {
+	req := dto.CreateNotificationRequest{
+		UserID:  event.RequestedBy,
+		Type:    models.NotificationTypeRefundInitiated,
+		Title:   "Refund Request Approved",
+		Message: fmt.Sprintf("Your refund request was approved. ₦%.2f is being returned to your bank account.", float64(event.TotalRefundAmount)/100),
+		Data: map[string]interface{}{
+			"refund_id": event.RefundID,
+			"goal_id":   event.GoalID,
+			"amount":    event.TotalRefundAmount,
+		},
+		Link: models.RefundLink(event.RefundID),
+	}
+
+	if _, err := h.notificationService.CreateNotification(req); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	log.Printf("RefundInitiated notification created for requester %s", event.RequestedBy)
+	return nil
+}
+
+// HandleRefundRequested tells a goal owner that a contributor asked for a refund, and
+// whether it was approved automatically within the grace window or awaits their review
+func (h *EventHandler) HandleRefundRequested(data []byte) error {
+	var event events.RefundRequested
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing RefundRequested event: %s for goal %s", event.ID, event.GoalID)
+
+	amount := float64(event.Amount) / 100
+	message := fmt.Sprintf("A contributor asked for their ₦%.2f contribution to '%s' to be refunded: \"%s\". Approve or deny the request.", amount, event.GoalTitle, event.Reason)
+	if event.AutoApproved {
+		message = fmt.Sprintf("A contributor's ₦%.2f contribution to '%s' is being refunded. They asked within the refund grace period, so it was approved automatically: \"%s\"", amount, event.GoalTitle, event.Reason)
+	}
+
+	req := dto.CreateNotificationRequest{
+		UserID:  event.OwnerID,
+		Type:    models.NotificationTypeRefundRequested,
+		Title:   "Refund Requested",
+		Message: message,
+		Data: map[string]interface{}{
+			"refund_request_id": event.RequestID,
+			"contribution_id":   event.ContributionID,
+			"goal_id":           event.GoalID,
+			"amount":            event.Amount,
+			"auto_approved":     event.AutoApproved,
+		},
+		Link: models.GoalLink(event.GoalID),
+	}
+
+	if _, err := h.notificationService.CreateNotification(req); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	log.Printf("RefundRequested notification created for user %s", event.OwnerID)
+	return nil
+}
+
+// HandleRefundRequestDenied tells a contributor the goal owner denied their refund request
+func (h *EventHandler) HandleRefundRequestDenied(data []byte) error {
+	var event events.RefundRequestDenied
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Printf("Processing RefundRequestDenied event: %s for user %s", event.ID, event.UserID)
+
+	message := fmt.Sprintf("The owner of '%s' denied your request to refund your ₦%.2f contribution.", event.GoalTitle, float64(event.Amount)/100)
+	if event.Note != "" {
+		message += fmt.Sprintf(" Their note: \"%s\"", event.Note)
+	}
+
+	req := dto.CreateNotificationRequest{
+		UserID:  event.UserID,
+		Type:    models.NotificationTypeRefundRequestDenied,
+		Title:   "Refund Request Denied",
+		Message: message,
+		Data: map[string]interface{}{
+			"refund_request_id": event.RequestID,
+			"contribution_id":   event.ContributionID,
+			"goal_id":           event.GoalID,
+			"amount":            event.Amount,
+		},
+		Link: models.GoalLink(event.GoalID),
+	}
+
+	if _, err := h.notificationService.CreateNotification(req); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	log.Printf("RefundRequestDenied notification created for user %s", event.UserID)
+	return nil
+}
+
 // HandleRefundCompleted handles RefundCompleted events
 func (h *EventHandler) HandleRefundCompleted(data []byte) error {
 	var event events.RefundCompleted
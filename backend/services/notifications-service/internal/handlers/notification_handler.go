@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofund/notifications-service/internal/dto"
+	"github.com/gofund/notifications-service/internal/repository"
 	"github.com/gofund/notifications-service/internal/service"
+	"github.com/google/uuid"
 )
 
 // NotificationHandler handles HTTP requests for notifications
@@ -63,24 +69,21 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 // @Tags notifications
 // @Accept json
 // @Produce json
-// @Param id path string true "Notification ID"
+// @Param id path string true "Notification ID (UUID)"
 // @Success 200 {object} models.Notification
+// @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/notifications/{id} [get]
 func (h *NotificationHandler) GetNotification(c *gin.Context) {
-	id := c.Param("id")
-
-	notification, err := h.notificationService.GetNotification(id)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+	id, userID, ok := notificationParams(c)
+	if !ok {
 		return
 	}
 
-	// Verify user owns this notification
-	userID, _ := c.Get("user_id")
-	if notification.UserID != userID.(string) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	notification, err := h.notificationService.GetNotification(id, userID)
+	if err != nil {
+		respondNotificationError(c, err)
 		return
 	}
 
@@ -99,24 +102,13 @@ func (h *NotificationHandler) GetNotification(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/notifications/{id}/read [put]
 func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
-	id := c.Param("id")
-
-	// Verify user owns this notification
-	notification, err := h.notificationService.GetNotification(id)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
-		return
-	}
-
-	userID, _ := c.Get("user_id")
-	if notification.UserID != userID.(string) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	id, userID, ok := notificationParams(c)
+	if !ok {
 		return
 	}
 
-	// Mark as read
-	if err := h.notificationService.MarkAsRead(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.notificationService.MarkAsRead(id, userID); err != nil {
+		respondNotificationError(c, err)
 		return
 	}
 
@@ -135,28 +127,140 @@ func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/notifications/{id} [delete]
 func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
-	id := c.Param("id")
+	id, userID, ok := notificationParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.DeleteNotification(id, userID); err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification deleted"})
+}
 
-	// Verify user owns this notification
-	notification, err := h.notificationService.GetNotification(id)
+// MarkAllAsRead godoc
+// @Summary Mark all notifications as read
+// @Description Mark all of the authenticated user's unread notifications as read, optionally only one type
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param type query string false "Only mark notifications of this type"
+// @Success 200 {object} map[string]int64
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/notifications/read-all [put]
+func (h *NotificationHandler) MarkAllAsRead(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.notificationService.MarkAllAsRead(userID, c.Query("type"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+		respondNotificationError(c, err)
 		return
 	}
 
-	userID, _ := c.Get("user_id")
-	if notification.UserID != userID.(string) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+// DeleteNotifications godoc
+// @Summary Delete several notifications
+// @Description Delete the listed notifications; IDs the user does not own are skipped
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body dto.BulkDeleteRequest true "Notification IDs"
+// @Success 200 {object} map[string]int64
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/notifications [delete]
+func (h *NotificationHandler) DeleteNotifications(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
 		return
 	}
 
-	// Delete notification
-	if err := h.notificationService.DeleteNotification(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	var req dto.BulkDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.IDs) > dto.MaxBulkDelete {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d notifications can be deleted at once", dto.MaxBulkDelete)})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "notification deleted"})
+	deleted, err := h.notificationService.DeleteNotifications(userID, req.IDs)
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted, "requested": len(req.IDs)})
+}
+
+// streamHeartbeat is how often an idle notification stream is pinged
+const streamHeartbeat = 30 * time.Second
+
+// StreamNotifications godoc
+// @Summary Stream notifications
+// @Description Server-Sent Events stream pushing each notification created for the authenticated user
+// @Tags notifications
+// @Produce text/event-stream
+// @Success 200 {object} models.Notification
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /api/v1/notifications/stream [get]
+func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	notifications, unsubscribe, err := h.notificationService.Subscribe(userID.String())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTooManyStreams):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many open notification streams"})
+		case errors.Is(err, service.ErrStreamsClosed):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "service shutting down"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case notification, open := <-notifications:
+			if !open {
+				return false
+			}
+			c.SSEvent("notification", notification)
+			return true
+		case <-heartbeat.C:
+			// A comment line keeps proxies from timing the connection out
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		}
+	})
 }
 
 // GetUnreadCount godoc
@@ -244,17 +348,82 @@ func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "preferences updated successfully"})
 }
 
-// HealthCheck godoc
-// @Summary Health check
-// @Description Check if the service is healthy
-// @Tags health
-// @Accept json
+// Unsubscribe godoc
+// @Summary Unsubscribe from notification emails
+// @Description Turn off the email an unsubscribe link names: one category, or all email. No login is needed; the signed token identifies the user. POST supports one-click unsubscribe (RFC 8058).
+// @Tags preferences
 // @Produce json
+// @Param token query string true "Unsubscribe token from an email"
 // @Success 200 {object} map[string]string
-// @Router /api/v1/notifications/health [get]
-func (h *NotificationHandler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "healthy",
-		"service": "notifications-service",
-	})
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/notifications/unsubscribe [get]
+// @Router /api/v1/notifications/unsubscribe [post]
+func (h *NotificationHandler) Unsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		token = c.PostForm("token")
+	}
+
+	category, err := h.notificationService.Unsubscribe(token)
+	if err != nil {
+		// Every token failure reads the same, so the response reveals nothing about users
+		if errors.Is(err, service.ErrInvalidUnsubscribeToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid unsubscribe link"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update preferences"})
+		return
+	}
+
+	message := fmt.Sprintf("you will no longer receive %s emails", category)
+	if category == service.UnsubscribeAll {
+		message = "you will no longer receive notification emails"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// authenticatedUserID returns the authenticated user ID, writing a 401 itself when it is
+// missing or invalid
+func authenticatedUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+// notificationParams parses the :id path param and the authenticated user ID,
+// writing the error response itself when either is invalid
+func notificationParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, userID, true
+}
+
+// respondNotificationError maps a service error to a response. Missing and
+// foreign notifications share one 404 body so existence is not leaked.
+func respondNotificationError(c *gin.Context, err error) {
+	if errors.Is(err, repository.ErrNotificationNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 }
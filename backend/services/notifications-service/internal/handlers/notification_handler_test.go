@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/notifications-service/internal/models"
+	"github.com/gofund/notifications-service/internal/repository"
+	"github.com/gofund/notifications-service/internal/service"
+	"github.com/google/uuid"
+)
+
+// ownedNotifications answers the single-notification calls from notifications keyed by
+// ID, scoped to their owner the way the repository is. Other calls panic on the nil
+// embedded interface.
+type ownedNotifications struct {
+	service.NotificationService
+
+	notifications map[uuid.UUID]*models.Notification
+	calls         int
+}
+
+func (s *ownedNotifications) lookup(id, userID uuid.UUID) (*models.Notification, error) {
+	s.calls++
+	notification, ok := s.notifications[id]
+	if !ok || notification.UserID != userID.String() {
+		return nil, repository.ErrNotificationNotFound
+	}
+	return notification, nil
+}
+
+func (s *ownedNotifications) GetNotification(id, userID uuid.UUID) (*models.Notification, error) {
+	return s.lookup(id, userID)
+}
+
+func (s *ownedNotifications) MarkAsRead(id, userID uuid.UUID) error {
+	notification, err := s.lookup(id, userID)
+	if err != nil {
+		return err
+	}
+	notification.IsRead = true
+	return nil
+}
+
+func (s *ownedNotifications) DeleteNotification(id, userID uuid.UUID) error {
+	if _, err := s.lookup(id, userID); err != nil {
+		return err
+	}
+	delete(s.notifications, id)
+	return nil
+}
+
+// newOwnershipTestRouter mounts the single-notification routes as the service does, with
+// the caller's ID set the way the auth middleware sets it
+func newOwnershipTestRouter(s service.NotificationService, callerID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewNotificationHandler(s)
+	r := gin.New()
+	api := r.Group("/api/v1/notifications", func(c *gin.Context) {
+		c.Set("user_id", callerID.String())
+	})
+	api.GET("/:id", h.GetNotification)
+	api.PUT("/:id/read", h.MarkAsRead)
+	api.DELETE("/:id", h.DeleteNotification)
+	return r
+}
+
+var singleNotificationRoutes = []struct {
+	method, suffix string
+}{
+	{http.MethodGet, ""},
+	{http.MethodPut, "/read"},
+	{http.MethodDelete, ""},
+}
+
+func TestForeignAndMissingNotificationsLookTheSame(t *testing.T) {
+	owner, caller := uuid.New(), uuid.New()
+	foreign := &models.Notification{ID: uuid.New(), UserID: owner.String(), Title: "Payment verified"}
+
+	for _, route := range singleNotificationRoutes {
+		t.Run(route.method+route.suffix, func(t *testing.T) {
+			s := &ownedNotifications{notifications: map[uuid.UUID]*models.Notification{foreign.ID: foreign}}
+			r := newOwnershipTestRouter(s, caller)
+
+			serve := func(id uuid.UUID) *httptest.ResponseRecorder {
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httptest.NewRequest(route.method, "/api/v1/notifications/"+id.String()+route.suffix, nil))
+				return w
+			}
+			missing := serve(uuid.New())
+			// A valid UUID of a notification that exists, owned by someone else
+			other := serve(foreign.ID)
+
+			if missing.Code != http.StatusNotFound {
+				t.Fatalf("missing notification: status = %d, want %d", missing.Code, http.StatusNotFound)
+			}
+			if other.Code != missing.Code || other.Body.String() != missing.Body.String() {
+				t.Errorf("foreign notification answered %d %s, missing one %d %s; want them identical",
+					other.Code, other.Body.String(), missing.Code, missing.Body.String())
+			}
+			if _, ok := s.notifications[foreign.ID]; !ok || foreign.IsRead {
+				t.Error("another user's request changed the notification")
+			}
+		})
+	}
+}
+
+func TestOwnerCanReachTheirNotification(t *testing.T) {
+	owner := uuid.New()
+
+	for _, route := range singleNotificationRoutes {
+		t.Run(route.method+route.suffix, func(t *testing.T) {
+			notification := &models.Notification{ID: uuid.New(), UserID: owner.String()}
+			s := &ownedNotifications{notifications: map[uuid.UUID]*models.Notification{notification.ID: notification}}
+
+			w := httptest.NewRecorder()
+			newOwnershipTestRouter(s, owner).ServeHTTP(w, httptest.NewRequest(route.method, "/api/v1/notifications/"+notification.ID.String()+route.suffix, nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMalformedNotificationIDIsRejected(t *testing.T) {
+	for _, route := range singleNotificationRoutes {
+		t.Run(route.method+route.suffix, func(t *testing.T) {
+			s := &ownedNotifications{notifications: map[uuid.UUID]*models.Notification{}}
+
+			w := httptest.NewRecorder()
+			newOwnershipTestRouter(s, uuid.New()).ServeHTTP(w, httptest.NewRequest(route.method, "/api/v1/notifications/not-a-uuid"+route.suffix, nil))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+			if s.calls != 0 {
+				t.Errorf("the service was called %d times for a malformed ID", s.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/identity"
+	"github.com/google/uuid"
+)
+
+// IdentityGuard drops X-User-* headers that were not signed by the gateway and
+// strips X-Internal-* headers, so callers that bypass Nginx cannot inject them.
+// It must run before AuthMiddleware.
+func IdentityGuard(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity.Guard(c.Request.Header, secret)
+		c.Next()
+	}
+}
+
+// AuthMiddleware requires a valid X-User-ID header and exposes it to handlers as "user_id"
+func AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetHeader(identity.HeaderUserID)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+
+		if _, err := uuid.Parse(userID); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", userID)
+		c.Next()
+	}
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+// LinkType is the kind of page a notification link opens
+type LinkType string
+
+const (
+	LinkTypeGoal    LinkType = "goal"
+	LinkTypeProof   LinkType = "proof"
+	LinkTypePayment LinkType = "payment"
+	LinkTypeRefund  LinkType = "refund"
+)
+
+// ErrInvalidLink is returned for a notification link that clients could not route
+var ErrInvalidLink = errors.New("invalid notification link")
+
+// NotificationLink is where tapping a notification leads: the page for the entity of
+// Type with ID. It is stored in the notification's data under "link".
+type NotificationLink struct {
+	Type LinkType `json:"type"`
+	ID   string   `json:"id"`
+	// Secondary is an optional second action offered alongside the link
+	Secondary *NotificationAction `json:"secondary,omitempty"`
+}
+
+// NotificationAction is a named action on an entity, such as requesting a refund from a
+// goal
+type NotificationAction struct {
+	Action string   `json:"action"`
+	Type   LinkType `json:"type"`
+	ID     string   `json:"id"`
+}
+
+// Notification actions offered as a link's secondary action
+const (
+	ActionRequestRefund = "request_refund"
+)
+
+var actionNamePattern = regexp.MustCompile(`^[a-z][a-z_]{0,49}$`)
+
+// Validate reports ErrInvalidLink unless the link, and its secondary action, name a known
+// entity type and a UUID
+func (l *NotificationLink) Validate() error {
+	if !validLinkTarget(l.Type, l.ID) {
+		return ErrInvalidLink
+	}
+	if s := l.Secondary; s != nil && (!actionNamePattern.MatchString(s.Action) || !validLinkTarget(s.Type, s.ID)) {
+		return ErrInvalidLink
+	}
+	return nil
+}
+
+// validLinkTarget reports whether a link type and ID name an entity clients can open
+func validLinkTarget(linkType LinkType, id string) bool {
+	switch linkType {
+	case LinkTypeGoal, LinkTypeProof, LinkTypePayment, LinkTypeRefund:
+	default:
+		return false
+	}
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+// GoalLink links to a goal's detail page
+func GoalLink(goalID string) *NotificationLink {
+	return &NotificationLink{Type: LinkTypeGoal, ID: goalID}
+}
+
+// ProofLink links to a proof's detail page
+func ProofLink(proofID string) *NotificationLink {
+	return &NotificationLink{Type: LinkTypeProof, ID: proofID}
+}
+
+// PaymentLink links to a payment's detail page
+func PaymentLink(paymentID string) *NotificationLink {
+	return &NotificationLink{Type: LinkTypePayment, ID: paymentID}
+}
+
+// RefundLink links to a refund's detail page
+func RefundLink(refundID string) *NotificationLink {
+	return &NotificationLink{Type: LinkTypeRefund, ID: refundID}
+}
+
+// DecodeLink sets Link from the notification's data. Links that do not decode or validate
+// are left out rather than failing the read.
+func (n *Notification) DecodeLink() {
+	n.Link = nil
+	raw, ok := n.Data["link"]
+	if !ok {
+		return
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return
+	}
+	var link NotificationLink
+	if json.Unmarshal(encoded, &link) != nil || link.Validate() != nil {
+		return
+	}
+	n.Link = &link
+}
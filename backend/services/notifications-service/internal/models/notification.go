@@ -2,58 +2,179 @@ package models
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // NotificationType represents the type of notification
 type NotificationType string
 
 const (
-	NotificationTypePaymentVerified       NotificationType = "payment_verified"
-	NotificationTypeContributionConfirmed NotificationType = "contribution_confirmed"
-	NotificationTypeWithdrawalRequested   NotificationType = "withdrawal_requested"
-	NotificationTypeWithdrawalCompleted   NotificationType = "withdrawal_completed"
-	NotificationTypeProofSubmitted        NotificationType = "proof_submitted"
-	NotificationTypeProofVoted            NotificationType = "proof_voted"
-	NotificationTypeGoalFunded            NotificationType = "goal_funded"
-	NotificationTypeUserSignedUp          NotificationType = "user_signed_up"
-	NotificationTypePasswordReset         NotificationType = "password_reset"
-	NotificationTypeEmailVerification     NotificationType = "email_verification"
-	NotificationTypeKYCVerified           NotificationType = "kyc_verified"
-	NotificationTypeRefundCompleted       NotificationType = "refund_completed"
-	NotificationTypeRefundInitiated       NotificationType = "refund_initiated"
+	NotificationTypePaymentVerified             NotificationType = "payment_verified"
+	NotificationTypeContributionConfirmed       NotificationType = "contribution_confirmed"
+	NotificationTypeRecurringChargeFailed       NotificationType = "recurring_charge_failed"
+	NotificationTypeWithdrawalRequested         NotificationType = "withdrawal_requested"
+	NotificationTypeWithdrawalApprovalRequested NotificationType = "withdrawal_approval_requested"
+	NotificationTypeWithdrawalCompleted         NotificationType = "withdrawal_completed"
+	NotificationTypeWithdrawalFailed            NotificationType = "withdrawal_failed"
+	NotificationTypeProofSubmitted              NotificationType = "proof_submitted"
+	NotificationTypeProofVoted                  NotificationType = "proof_voted"
+	NotificationTypeProofRejected               NotificationType = "proof_rejected"
+	NotificationTypeGoalFunded                  NotificationType = "goal_funded"
+	NotificationTypeGoalClosed                  NotificationType = "goal_closed"
+	NotificationTypeGoalCancelled               NotificationType = "goal_cancelled"
+	NotificationTypeGoalDeadlineReached         NotificationType = "goal_deadline_reached"
+	NotificationTypeGoalDeadlineApproaching     NotificationType = "goal_deadline_approaching"
+	NotificationTypeGoalSuspended               NotificationType = "goal_suspended"
+	NotificationTypeGoalCommented               NotificationType = "goal_commented"
+	NotificationTypeGoalUpdatePosted            NotificationType = "goal_update_posted"
+	NotificationTypeGoalBankDetailsChanged      NotificationType = "goal_bank_details_changed"
+	NotificationTypeUserSignedUp                NotificationType = "user_signed_up"
+	NotificationTypePasswordReset               NotificationType = "password_reset"
+	NotificationTypePasswordChanged             NotificationType = "password_changed"
+	NotificationTypeEmailVerification           NotificationType = "email_verification"
+	NotificationTypeKYCVerified                 NotificationType = "kyc_verified"
+	NotificationTypeKYCRejected                 NotificationType = "kyc_rejected"
+	NotificationTypeRefundCompleted             NotificationType = "refund_completed"
+	NotificationTypeRefundInitiated             NotificationType = "refund_initiated"
+	NotificationTypeRefundRequested             NotificationType = "refund_requested"
+	NotificationTypeRefundRequestDenied         NotificationType = "refund_request_denied"
+)
+
+// PreferenceCategory is the preference switch that governs a notification type
+type PreferenceCategory string
+
+const (
+	PreferenceCategoryPayment      PreferenceCategory = "payment"
+	PreferenceCategoryContribution PreferenceCategory = "contribution"
+	PreferenceCategoryWithdrawal   PreferenceCategory = "withdrawal"
+	PreferenceCategoryProof        PreferenceCategory = "proof"
+	PreferenceCategoryGoal         PreferenceCategory = "goal"
+	// PreferenceCategoryAccount covers account and security messages, which cannot be
+	// turned off
+	PreferenceCategoryAccount PreferenceCategory = "account"
 )
 
+// notificationCategories maps every NotificationType to its preference category. A new
+// type must be added here; until it is, it is treated as an account message.
+var notificationCategories = map[NotificationType]PreferenceCategory{
+	NotificationTypePaymentVerified:             PreferenceCategoryPayment,
+	NotificationTypeRefundInitiated:             PreferenceCategoryPayment,
+	NotificationTypeRefundCompleted:             PreferenceCategoryPayment,
+	NotificationTypeRefundRequested:             PreferenceCategoryPayment,
+	NotificationTypeRefundRequestDenied:         PreferenceCategoryPayment,
+	NotificationTypeContributionConfirmed:       PreferenceCategoryContribution,
+	NotificationTypeRecurringChargeFailed:       PreferenceCategoryContribution,
+	NotificationTypeWithdrawalRequested:         PreferenceCategoryWithdrawal,
+	NotificationTypeWithdrawalApprovalRequested: PreferenceCategoryWithdrawal,
+	NotificationTypeWithdrawalCompleted:         PreferenceCategoryWithdrawal,
+	NotificationTypeWithdrawalFailed:            PreferenceCategoryWithdrawal,
+	NotificationTypeProofSubmitted:              PreferenceCategoryProof,
+	NotificationTypeProofVoted:                  PreferenceCategoryProof,
+	NotificationTypeProofRejected:               PreferenceCategoryProof,
+	NotificationTypeGoalFunded:                  PreferenceCategoryGoal,
+	NotificationTypeGoalClosed:                  PreferenceCategoryGoal,
+	NotificationTypeGoalCancelled:               PreferenceCategoryGoal,
+	NotificationTypeGoalDeadlineReached:         PreferenceCategoryGoal,
+	NotificationTypeGoalDeadlineApproaching:     PreferenceCategoryGoal,
+	NotificationTypeGoalSuspended:               PreferenceCategoryGoal,
+	NotificationTypeGoalCommented:               PreferenceCategoryGoal,
+	NotificationTypeGoalUpdatePosted:            PreferenceCategoryGoal,
+	NotificationTypeUserSignedUp:                PreferenceCategoryAccount,
+	NotificationTypePasswordReset:               PreferenceCategoryAccount,
+	NotificationTypePasswordChanged:             PreferenceCategoryAccount,
+	NotificationTypeEmailVerification:           PreferenceCategoryAccount,
+	NotificationTypeKYCVerified:                 PreferenceCategoryAccount,
+	NotificationTypeKYCRejected:                 PreferenceCategoryAccount,
+	NotificationTypeGoalBankDetailsChanged:      PreferenceCategoryAccount,
+}
+
+// Category returns the preference category that governs the notification type
+func (t NotificationType) Category() PreferenceCategory {
+	if category, ok := notificationCategories[t]; ok {
+		return category
+	}
+	return PreferenceCategoryAccount
+}
+
 // Notification represents a notification record
 type Notification struct {
-	ID                 string                 `json:"id" db:"id"`
+	ID                 uuid.UUID              `json:"id" db:"id"`
 	UserID             string                 `json:"user_id" db:"user_id"`
 	Type               NotificationType       `json:"type" db:"type"`
 	Title              string                 `json:"title" db:"title"`
 	Message            string                 `json:"message" db:"message"`
 	Data               map[string]interface{} `json:"data" db:"data"`
-	EmailSent          bool                   `json:"email_sent" db:"email_sent"`
-	EmailSentAt        *time.Time             `json:"email_sent_at,omitempty" db:"email_sent_at"`
-	EmailFailedReason  *string                `json:"email_failed_reason,omitempty" db:"email_failed_reason"`
-	RetryCount         int                    `json:"retry_count" db:"retry_count"`
-	IsRead             bool                   `json:"is_read" db:"is_read"`
-	ReadAt             *time.Time             `json:"read_at,omitempty" db:"read_at"`
-	CreatedAt          time.Time              `json:"created_at" db:"created_at"`
-	UpdatedAt          time.Time              `json:"updated_at" db:"updated_at"`
+	EmailSent         bool                   `json:"email_sent" db:"email_sent"`
+	EmailSentAt       *time.Time             `json:"email_sent_at,omitempty" db:"email_sent_at"`
+	EmailFailedReason *string                `json:"email_failed_reason,omitempty" db:"email_failed_reason"`
+	EmailPending      bool                   `json:"email_pending" db:"email_pending"`
+	RetryCount        int                    `json:"retry_count" db:"retry_count"`
+	IsRead            bool                   `json:"is_read" db:"is_read"`
+	ReadAt            *time.Time             `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt         time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at" db:"updated_at"`
+
+	// Link is where the notification leads, decoded from Data["link"]
+	Link *NotificationLink `json:"link,omitempty" db:"-"`
+}
+
+// EmailFrequency is how often a user receives notification emails
+type EmailFrequency string
+
+const (
+	EmailFrequencyInstant EmailFrequency = "instant"
+	EmailFrequencyHourly  EmailFrequency = "hourly"
+	EmailFrequencyDaily   EmailFrequency = "daily"
+)
+
+// DigestWindow is how long notifications are held before going out in one digest email
+func (f EmailFrequency) DigestWindow() time.Duration {
+	switch f {
+	case EmailFrequencyHourly:
+		return time.Hour
+	case EmailFrequencyDaily:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
 }
 
 // NotificationPreferences represents user notification preferences
 type NotificationPreferences struct {
-	ID                        string    `json:"id" db:"id"`
-	UserID                    string    `json:"user_id" db:"user_id"`
-	EmailEnabled              bool      `json:"email_enabled" db:"email_enabled"`
-	PaymentNotifications      bool      `json:"payment_notifications" db:"payment_notifications"`
-	ContributionNotifications bool      `json:"contribution_notifications" db:"contribution_notifications"`
-	WithdrawalNotifications   bool      `json:"withdrawal_notifications" db:"withdrawal_notifications"`
-	ProofNotifications        bool      `json:"proof_notifications" db:"proof_notifications"`
-	GoalNotifications         bool      `json:"goal_notifications" db:"goal_notifications"`
-	MarketingEmails           bool      `json:"marketing_emails" db:"marketing_emails"`
-	CreatedAt                 time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt                 time.Time `json:"updated_at" db:"updated_at"`
+	ID                        string `json:"id" db:"id"`
+	UserID                    string `json:"user_id" db:"user_id"`
+	EmailEnabled              bool   `json:"email_enabled" db:"email_enabled"`
+	PaymentNotifications      bool   `json:"payment_notifications" db:"payment_notifications"`
+	ContributionNotifications bool   `json:"contribution_notifications" db:"contribution_notifications"`
+	WithdrawalNotifications   bool   `json:"withdrawal_notifications" db:"withdrawal_notifications"`
+	ProofNotifications        bool   `json:"proof_notifications" db:"proof_notifications"`
+	GoalNotifications         bool   `json:"goal_notifications" db:"goal_notifications"`
+	MarketingEmails           bool   `json:"marketing_emails" db:"marketing_emails"`
+	// EmailFrequency batches emails into hourly or daily digests; account and security
+	// emails are always sent immediately
+	EmailFrequency EmailFrequency `json:"email_frequency" db:"email_frequency"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// Allows reports whether the user wants notifications of the given type. It does not
+// consider EmailEnabled, which only governs email delivery.
+func (p *NotificationPreferences) Allows(t NotificationType) bool {
+	switch t.Category() {
+	case PreferenceCategoryPayment:
+		return p.PaymentNotifications
+	case PreferenceCategoryContribution:
+		return p.ContributionNotifications
+	case PreferenceCategoryWithdrawal:
+		return p.WithdrawalNotifications
+	case PreferenceCategoryProof:
+		return p.ProofNotifications
+	case PreferenceCategoryGoal:
+		return p.GoalNotifications
+	default:
+		return true
+	}
 }
 
 // ListNotificationsQuery represents query parameters for listing notifications
@@ -67,12 +188,12 @@ type ListNotificationsQuery struct {
 
 // UpdatePreferencesRequest represents a request to update notification preferences
 type UpdatePreferencesRequest struct {
-	EmailEnabled              *bool `json:"email_enabled"`
-	PaymentNotifications      *bool `json:"payment_notifications"`
-	ContributionNotifications *bool `json:"contribution_notifications"`
-	WithdrawalNotifications   *bool `json:"withdrawal_notifications"`
-	ProofNotifications        *bool `json:"proof_notifications"`
-	GoalNotifications         *bool `json:"goal_notifications"`
-	MarketingEmails           *bool `json:"marketing_emails"`
+	EmailEnabled              *bool           `json:"email_enabled"`
+	PaymentNotifications      *bool           `json:"payment_notifications"`
+	ContributionNotifications *bool           `json:"contribution_notifications"`
+	WithdrawalNotifications   *bool           `json:"withdrawal_notifications"`
+	ProofNotifications        *bool           `json:"proof_notifications"`
+	GoalNotifications         *bool           `json:"goal_notifications"`
+	MarketingEmails           *bool           `json:"marketing_emails"`
+	EmailFrequency            *EmailFrequency `json:"email_frequency" binding:"omitempty,oneof=instant hourly daily"`
 }
-
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+)
+
+// notificationTypeConstants returns the value of each NotificationType constant declared
+// in notification.go by name, read from the source so a new one cannot be missed
+func notificationTypeConstants(t *testing.T) map[string]NotificationType {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "notification.go", nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse notification.go: %v", err)
+	}
+
+	constants := make(map[string]NotificationType)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			if ident, ok := value.Type.(*ast.Ident); !ok || ident.Name != "NotificationType" {
+				continue
+			}
+			for i, name := range value.Names {
+				lit, ok := value.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					t.Fatalf("%s is not a string literal", name.Name)
+				}
+				unquoted, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					t.Fatalf("%s: %v", name.Name, err)
+				}
+				constants[name.Name] = NotificationType(unquoted)
+			}
+		}
+	}
+	if len(constants) == 0 {
+		t.Fatal("found no NotificationType constants in notification.go")
+	}
+	return constants
+}
+
+func TestEveryNotificationTypeHasACategory(t *testing.T) {
+	constants := notificationTypeConstants(t)
+	for name, notificationType := range constants {
+		if _, ok := notificationCategories[notificationType]; !ok {
+			t.Errorf("%s (%q) has no entry in notificationCategories", name, notificationType)
+		}
+	}
+	if len(notificationCategories) != len(constants) {
+		t.Errorf("notificationCategories has %d entries for %d NotificationType constants", len(notificationCategories), len(constants))
+	}
+}
+
+func TestAllowsFollowsCategorySwitches(t *testing.T) {
+	all := NotificationPreferences{
+		PaymentNotifications:      true,
+		ContributionNotifications: true,
+		WithdrawalNotifications:   true,
+		ProofNotifications:        true,
+		GoalNotifications:         true,
+	}
+
+	tests := []struct {
+		notificationType NotificationType
+		turnOff          func(*NotificationPreferences)
+	}{
+		{NotificationTypePaymentVerified, func(p *NotificationPreferences) { p.PaymentNotifications = false }},
+		{NotificationTypeContributionConfirmed, func(p *NotificationPreferences) { p.ContributionNotifications = false }},
+		{NotificationTypeWithdrawalCompleted, func(p *NotificationPreferences) { p.WithdrawalNotifications = false }},
+		{NotificationTypeProofSubmitted, func(p *NotificationPreferences) { p.ProofNotifications = false }},
+		{NotificationTypeGoalFunded, func(p *NotificationPreferences) { p.GoalNotifications = false }},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.notificationType), func(t *testing.T) {
+			preferences := all
+			if !preferences.Allows(tt.notificationType) {
+				t.Fatal("not allowed with every category on")
+			}
+			tt.turnOff(&preferences)
+			if preferences.Allows(tt.notificationType) {
+				t.Errorf("allowed with its %s category off", tt.notificationType.Category())
+			}
+		})
+	}
+
+	// Account and security messages cannot be turned off
+	var none NotificationPreferences
+	for _, notificationType := range []NotificationType{NotificationTypePasswordReset, NotificationTypeKYCVerified, "some_future_type"} {
+		if !none.Allows(notificationType) {
+			t.Errorf("%s blocked with every category off", notificationType)
+		}
+	}
+}
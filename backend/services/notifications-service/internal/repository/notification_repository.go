@@ -3,24 +3,39 @@ package repository
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/gofund/notifications-service/internal/models"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
+// ErrNotificationNotFound is returned when a notification does not exist or belongs to another user
+var ErrNotificationNotFound = errors.New("notification not found")
+
 // NotificationRepository handles database operations for notifications
 type NotificationRepository interface {
 	Create(notification *models.Notification) error
-	GetByID(id string) (*models.Notification, error)
+	GetByID(id, userID uuid.UUID) (*models.Notification, error)
 	GetByUserID(userID string, page, pageSize int) ([]models.Notification, int64, error)
 	List(query models.ListNotificationsQuery) ([]models.Notification, int64, error)
-	MarkAsRead(id string) error
-	MarkAsEmailSent(id string) error
-	MarkAsEmailFailed(id string, reason string) error
-	IncrementRetryCount(id string) error
-	Delete(id string) error
+	MarkAsRead(id, userID uuid.UUID) error
+	MarkAllAsRead(userID uuid.UUID, notificationType string) (int64, error)
+	MarkAsEmailSent(id uuid.UUID) error
+	MarkAsEmailFailed(id uuid.UUID, reason string) error
+	IncrementRetryCount(id uuid.UUID) error
+	MarkAsEmailPending(id uuid.UUID) error
+	ClaimPendingEmails(limit, maxRetries int) ([]models.Notification, error)
+	MarkForDigest(id uuid.UUID) error
+	GetDigestDueUsers(frequency models.EmailFrequency, olderThan time.Time, limit int) ([]string, error)
+	ClaimDigestNotifications(userID string) ([]models.Notification, error)
+	MarkManyAsEmailSent(ids []uuid.UUID) error
+	RequeueForDigest(ids []uuid.UUID) error
+	Delete(id, userID uuid.UUID) error
+	DeleteMany(userID uuid.UUID, ids []uuid.UUID) (int64, error)
 	GetUnreadCount(userID string) (int64, error)
 }
 
@@ -67,19 +82,19 @@ func (r *notificationRepository) Create(notification *models.Notification) error
 	return nil
 }
 
-// GetByID retrieves a notification by ID
-func (r *notificationRepository) GetByID(id string) (*models.Notification, error) {
+// GetByID retrieves a notification by ID, scoped to its owner
+func (r *notificationRepository) GetByID(id, userID uuid.UUID) (*models.Notification, error) {
 	query := `
 		SELECT id, user_id, type, title, message, data, email_sent, email_sent_at, 
-		       email_failed_reason, retry_count, is_read, read_at, created_at, updated_at
+		       email_failed_reason, email_pending, retry_count, is_read, read_at, created_at, updated_at
 		FROM notifications
-		WHERE id = $1
+		WHERE id = $1 AND user_id = $2
 	`
 
 	var notification models.Notification
 	var dataJSON []byte
 
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRow(query, id, userID).Scan(
 		&notification.ID,
 		&notification.UserID,
 		&notification.Type,
@@ -89,6 +104,7 @@ func (r *notificationRepository) GetByID(id string) (*models.Notification, error
 		&notification.EmailSent,
 		&notification.EmailSentAt,
 		&notification.EmailFailedReason,
+		&notification.EmailPending,
 		&notification.RetryCount,
 		&notification.IsRead,
 		&notification.ReadAt,
@@ -97,7 +113,7 @@ func (r *notificationRepository) GetByID(id string) (*models.Notification, error
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("notification not found")
+		return nil, ErrNotificationNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get notification: %w", err)
@@ -106,6 +122,7 @@ func (r *notificationRepository) GetByID(id string) (*models.Notification, error
 	if err := json.Unmarshal(dataJSON, &notification.Data); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal data: %w", err)
 	}
+	notification.DecodeLink()
 
 	return &notification, nil
 }
@@ -124,7 +141,7 @@ func (r *notificationRepository) GetByUserID(userID string, page, pageSize int)
 	// Get notifications
 	query := `
 		SELECT id, user_id, type, title, message, data, email_sent, email_sent_at, 
-		       email_failed_reason, retry_count, is_read, read_at, created_at, updated_at
+		       email_failed_reason, email_pending, retry_count, is_read, read_at, created_at, updated_at
 		FROM notifications
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -152,6 +169,7 @@ func (r *notificationRepository) GetByUserID(userID string, page, pageSize int)
 			&notification.EmailSent,
 			&notification.EmailSentAt,
 			&notification.EmailFailedReason,
+			&notification.EmailPending,
 			&notification.RetryCount,
 			&notification.IsRead,
 			&notification.ReadAt,
@@ -165,6 +183,7 @@ func (r *notificationRepository) GetByUserID(userID string, page, pageSize int)
 		if err := json.Unmarshal(dataJSON, &notification.Data); err != nil {
 			return nil, 0, fmt.Errorf("failed to unmarshal data: %w", err)
 		}
+		notification.DecodeLink()
 
 		notifications = append(notifications, notification)
 	}
@@ -217,7 +236,7 @@ func (r *notificationRepository) List(query models.ListNotificationsQuery) ([]mo
 	// Get notifications
 	sqlQuery := fmt.Sprintf(`
 		SELECT id, user_id, type, title, message, data, email_sent, email_sent_at, 
-		       email_failed_reason, retry_count, is_read, read_at, created_at, updated_at
+		       email_failed_reason, email_pending, retry_count, is_read, read_at, created_at, updated_at
 		FROM notifications
 		%s
 		ORDER BY created_at DESC
@@ -247,6 +266,7 @@ func (r *notificationRepository) List(query models.ListNotificationsQuery) ([]mo
 			&notification.EmailSent,
 			&notification.EmailSentAt,
 			&notification.EmailFailedReason,
+			&notification.EmailPending,
 			&notification.RetryCount,
 			&notification.IsRead,
 			&notification.ReadAt,
@@ -260,6 +280,7 @@ func (r *notificationRepository) List(query models.ListNotificationsQuery) ([]mo
 		if err := json.Unmarshal(dataJSON, &notification.Data); err != nil {
 			return nil, 0, fmt.Errorf("failed to unmarshal data: %w", err)
 		}
+		notification.DecodeLink()
 
 		notifications = append(notifications, notification)
 	}
@@ -267,28 +288,45 @@ func (r *notificationRepository) List(query models.ListNotificationsQuery) ([]mo
 	return notifications, total, nil
 }
 
-// MarkAsRead marks a notification as read
-func (r *notificationRepository) MarkAsRead(id string) error {
+// MarkAsRead marks a notification as read, scoped to its owner
+func (r *notificationRepository) MarkAsRead(id, userID uuid.UUID) error {
 	query := `
 		UPDATE notifications
 		SET is_read = true, read_at = $1, updated_at = $2
-		WHERE id = $3
+		WHERE id = $3 AND user_id = $4
 	`
 
 	now := time.Now()
-	_, err := r.db.Exec(query, now, now, id)
+	result, err := r.db.Exec(query, now, now, id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to mark notification as read: %w", err)
 	}
 
-	return nil
+	return requireAffected(result)
+}
+
+// MarkAllAsRead marks every unread notification of the user as read, limited to one
+// type when notificationType is not empty. It returns how many were marked.
+func (r *notificationRepository) MarkAllAsRead(userID uuid.UUID, notificationType string) (int64, error) {
+	query := `
+		UPDATE notifications
+		SET is_read = true, read_at = $1, updated_at = $1
+		WHERE user_id = $2 AND is_read = false AND ($3 = '' OR type = $3)
+	`
+
+	result, err := r.db.Exec(query, time.Now(), userID, notificationType)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+
+	return result.RowsAffected()
 }
 
 // MarkAsEmailSent marks a notification as email sent
-func (r *notificationRepository) MarkAsEmailSent(id string) error {
+func (r *notificationRepository) MarkAsEmailSent(id uuid.UUID) error {
 	query := `
 		UPDATE notifications
-		SET email_sent = true, email_sent_at = $1, updated_at = $2
+		SET email_sent = true, email_sent_at = $1, email_pending = false, updated_at = $2
 		WHERE id = $3
 	`
 
@@ -302,7 +340,7 @@ func (r *notificationRepository) MarkAsEmailSent(id string) error {
 }
 
 // MarkAsEmailFailed marks a notification as email failed
-func (r *notificationRepository) MarkAsEmailFailed(id string, reason string) error {
+func (r *notificationRepository) MarkAsEmailFailed(id uuid.UUID, reason string) error {
 	query := `
 		UPDATE notifications
 		SET email_failed_reason = $1, updated_at = $2
@@ -319,7 +357,7 @@ func (r *notificationRepository) MarkAsEmailFailed(id string, reason string) err
 }
 
 // IncrementRetryCount increments the retry count
-func (r *notificationRepository) IncrementRetryCount(id string) error {
+func (r *notificationRepository) IncrementRetryCount(id uuid.UUID) error {
 	query := `
 		UPDATE notifications
 		SET retry_count = retry_count + 1, updated_at = $1
@@ -335,15 +373,233 @@ func (r *notificationRepository) IncrementRetryCount(id string) error {
 	return nil
 }
 
-// Delete deletes a notification
-func (r *notificationRepository) Delete(id string) error {
-	query := `DELETE FROM notifications WHERE id = $1`
+// MarkAsEmailPending flags a notification for the email retry worker
+func (r *notificationRepository) MarkAsEmailPending(id uuid.UUID) error {
+	query := `
+		UPDATE notifications
+		SET email_pending = true, updated_at = $1
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as email pending: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimPendingEmails atomically clears the pending flag on up to limit notifications
+// that still have retries left and returns them for re-dispatch
+func (r *notificationRepository) ClaimPendingEmails(limit, maxRetries int) ([]models.Notification, error) {
+	query := `
+		UPDATE notifications
+		SET email_pending = false, updated_at = $1
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE email_pending = true AND email_sent = false AND retry_count < $2
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, type, title, message, data, retry_count, created_at
+	`
+
+	rows, err := r.db.Query(query, time.Now(), maxRetries, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending emails: %w", err)
+	}
+	defer rows.Close()
+
+	return scanClaimedNotifications(rows)
+}
+
+// MarkForDigest holds a notification's email for the user's next digest
+func (r *notificationRepository) MarkForDigest(id uuid.UUID) error {
+	query := `
+		UPDATE notifications
+		SET email_digest_pending = true, updated_at = $1
+		WHERE id = $2
+	`
+
+	_, err := r.db.Exec(query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification for digest: %w", err)
+	}
+
+	return nil
+}
+
+// GetDigestDueUsers returns up to limit users on the given email frequency whose oldest
+// notification held for a digest was created before olderThan
+func (r *notificationRepository) GetDigestDueUsers(frequency models.EmailFrequency, olderThan time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT n.user_id
+		FROM notifications n
+		JOIN notification_preferences p ON p.user_id = n.user_id
+		WHERE n.email_digest_pending = true AND p.email_frequency = $1
+		GROUP BY n.user_id
+		HAVING MIN(n.created_at) <= $2
+		ORDER BY MIN(n.created_at)
+		LIMIT $3
+	`
+
+	var userIDs []string
+	if err := r.db.Select(&userIDs, query, frequency, olderThan, limit); err != nil {
+		return nil, fmt.Errorf("failed to get users due a digest: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+// ClaimDigestNotifications atomically clears the digest flag on all of a user's held
+// notifications and returns them, oldest first
+func (r *notificationRepository) ClaimDigestNotifications(userID string) ([]models.Notification, error) {
+	query := `
+		UPDATE notifications
+		SET email_digest_pending = false, digested_at = $1, updated_at = $1
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE user_id = $2 AND email_digest_pending = true
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, type, title, message, data, retry_count, created_at
+	`
+
+	rows, err := r.db.Query(query, time.Now(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim digest notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications, err := scanClaimedNotifications(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.Before(notifications[j].CreatedAt)
+	})
+	return notifications, nil
+}
+
+// MarkManyAsEmailSent marks notifications delivered together, e.g. in a digest, as sent
+func (r *notificationRepository) MarkManyAsEmailSent(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	query, args, err := sqlx.In(`
+		UPDATE notifications
+		SET email_sent = true, email_sent_at = ?, email_pending = false, updated_at = ?
+		WHERE id IN (?)
+	`, now, now, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	if _, err := r.db.Exec(r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to mark notifications as email sent: %w", err)
+	}
+
+	return nil
+}
+
+// RequeueForDigest puts claimed notifications back for the next digest after a failed send
+func (r *notificationRepository) RequeueForDigest(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(`
+		UPDATE notifications
+		SET email_digest_pending = true, digested_at = NULL, updated_at = ?
+		WHERE id IN (?)
+	`, time.Now(), ids)
+	if err != nil {
+		return fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	if _, err := r.db.Exec(r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to requeue notifications for digest: %w", err)
+	}
+
+	return nil
+}
+
+// scanClaimedNotifications reads the rows returned by a claim query
+func scanClaimedNotifications(rows *sql.Rows) ([]models.Notification, error) {
+	var notifications []models.Notification
+	for rows.Next() {
+		var notification models.Notification
+		var dataJSON []byte
+
+		if err := rows.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.Type,
+			&notification.Title,
+			&notification.Message,
+			&dataJSON,
+			&notification.RetryCount,
+			&notification.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+
+		if err := json.Unmarshal(dataJSON, &notification.Data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+		}
+		notification.DecodeLink()
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+// Delete deletes a notification, scoped to its owner
+func (r *notificationRepository) Delete(id, userID uuid.UUID) error {
+	query := `DELETE FROM notifications WHERE id = $1 AND user_id = $2`
 
-	_, err := r.db.Exec(query, id)
+	result, err := r.db.Exec(query, id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete notification: %w", err)
 	}
 
+	return requireAffected(result)
+}
+
+// DeleteMany deletes the given notifications that belong to the user; IDs of missing or
+// foreign notifications are ignored. It returns how many were deleted.
+func (r *notificationRepository) DeleteMany(userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query, args, err := sqlx.In(`DELETE FROM notifications WHERE user_id = ? AND id IN (?)`, userID, ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build delete query: %w", err)
+	}
+
+	result, err := r.db.Exec(r.db.Rebind(query), args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete notifications: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// requireAffected returns ErrNotificationNotFound when a scoped write matched no rows
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotificationNotFound
+	}
 	return nil
 }
 
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gofund/notifications-service/internal/models"
+	"github.com/gofund/notifications-service/internal/testdb"
+	"github.com/google/uuid"
+)
+
+func createTestNotification(t *testing.T, repo NotificationRepository, userID uuid.UUID) *models.Notification {
+	t.Helper()
+	notification := &models.Notification{
+		UserID:  userID.String(),
+		Type:    models.NotificationTypePaymentVerified,
+		Title:   "Payment verified",
+		Message: "Your payment was verified",
+		Data:    map[string]interface{}{},
+	}
+	if err := repo.Create(notification); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return notification
+}
+
+func TestNotificationsAreScopedToTheirOwner(t *testing.T) {
+	repo := NewNotificationRepository(testdb.Open(t))
+	owner, other := uuid.New(), uuid.New()
+	notification := createTestNotification(t, repo, owner)
+
+	// The ID is valid and exists, but belongs to someone else
+	if _, err := repo.GetByID(notification.ID, other); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("GetByID by another user: err = %v, want %v", err, ErrNotificationNotFound)
+	}
+	if err := repo.MarkAsRead(notification.ID, other); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("MarkAsRead by another user: err = %v, want %v", err, ErrNotificationNotFound)
+	}
+	if err := repo.Delete(notification.ID, other); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("Delete by another user: err = %v, want %v", err, ErrNotificationNotFound)
+	}
+	if deleted, err := repo.DeleteMany(other, []uuid.UUID{notification.ID}); err != nil || deleted != 0 {
+		t.Errorf("DeleteMany by another user: deleted %d, err %v; want 0 and no error", deleted, err)
+	}
+
+	stored, err := repo.GetByID(notification.ID, owner)
+	if err != nil {
+		t.Fatalf("GetByID by the owner: %v", err)
+	}
+	if stored.IsRead {
+		t.Error("another user marked the notification as read")
+	}
+
+	if err := repo.MarkAsRead(notification.ID, owner); err != nil {
+		t.Errorf("MarkAsRead by the owner: %v", err)
+	}
+	if err := repo.Delete(notification.ID, owner); err != nil {
+		t.Errorf("Delete by the owner: %v", err)
+	}
+}
+
+func TestMissingNotificationIsNotFound(t *testing.T) {
+	repo := NewNotificationRepository(testdb.Open(t))
+	owner := uuid.New()
+	missing := uuid.New()
+
+	if _, err := repo.GetByID(missing, owner); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("GetByID: err = %v, want %v", err, ErrNotificationNotFound)
+	}
+	if err := repo.MarkAsRead(missing, owner); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("MarkAsRead: err = %v, want %v", err, ErrNotificationNotFound)
+	}
+	if err := repo.Delete(missing, owner); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("Delete: err = %v, want %v", err, ErrNotificationNotFound)
+	}
+}
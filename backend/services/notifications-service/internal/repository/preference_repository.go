@@ -32,9 +32,9 @@ func (r *preferenceRepository) Create(preferences *models.NotificationPreference
 		INSERT INTO notification_preferences (
 			user_id, email_enabled, payment_notifications, contribution_notifications,
 			withdrawal_notifications, proof_notifications, goal_notifications,
-			marketing_emails, created_at, updated_at
+			marketing_emails, email_frequency, created_at, updated_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id
 	`
 
@@ -49,6 +49,7 @@ func (r *preferenceRepository) Create(preferences *models.NotificationPreference
 		preferences.ProofNotifications,
 		preferences.GoalNotifications,
 		preferences.MarketingEmails,
+		preferences.EmailFrequency,
 		now,
 		now,
 	).Scan(&preferences.ID)
@@ -67,7 +68,7 @@ func (r *preferenceRepository) GetByUserID(userID string) (*models.NotificationP
 	query := `
 		SELECT id, user_id, email_enabled, payment_notifications, contribution_notifications,
 		       withdrawal_notifications, proof_notifications, goal_notifications,
-		       marketing_emails, created_at, updated_at
+		       marketing_emails, email_frequency, created_at, updated_at
 		FROM notification_preferences
 		WHERE user_id = $1
 	`
@@ -83,6 +84,7 @@ func (r *preferenceRepository) GetByUserID(userID string) (*models.NotificationP
 		&preferences.ProofNotifications,
 		&preferences.GoalNotifications,
 		&preferences.MarketingEmails,
+		&preferences.EmailFrequency,
 		&preferences.CreatedAt,
 		&preferences.UpdatedAt,
 	)
@@ -109,8 +111,9 @@ func (r *preferenceRepository) Update(userID string, updates models.UpdatePrefer
 			proof_notifications = COALESCE($5, proof_notifications),
 			goal_notifications = COALESCE($6, goal_notifications),
 			marketing_emails = COALESCE($7, marketing_emails),
-			updated_at = $8
-		WHERE user_id = $9
+			email_frequency = COALESCE($8, email_frequency),
+			updated_at = $9
+		WHERE user_id = $10
 	`
 
 	now := time.Now()
@@ -123,6 +126,7 @@ func (r *preferenceRepository) Update(userID string, updates models.UpdatePrefer
 		updates.ProofNotifications,
 		updates.GoalNotifications,
 		updates.MarketingEmails,
+		updates.EmailFrequency,
 		now,
 		userID,
 	)
@@ -145,6 +149,7 @@ func (r *preferenceRepository) CreateDefault(userID string) error {
 		ProofNotifications:        true,
 		GoalNotifications:         true,
 		MarketingEmails:           false,
+		EmailFrequency:            models.EmailFrequencyInstant,
 	}
 
 	return r.Create(preferences)
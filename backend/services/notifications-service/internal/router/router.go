@@ -0,0 +1,37 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/notifications-service/internal/handlers"
+	"github.com/gofund/notifications-service/internal/middleware"
+	"github.com/gofund/notifications-service/internal/service"
+)
+
+// SetupRoutes configures all HTTP routes
+func SetupRoutes(r *gin.Engine, notificationService service.NotificationService) {
+	// Initialize HTTP handler
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+
+	// Unsubscribe links in emails work without logging in; the token identifies the user
+	r.GET("/api/v1/notifications/unsubscribe", notificationHandler.Unsubscribe)
+	r.POST("/api/v1/notifications/unsubscribe", notificationHandler.Unsubscribe)
+
+	// API routes
+	api := r.Group("/api/v1/notifications")
+	api.Use(middleware.AuthMiddleware())
+	{
+		// Notification endpoints
+		api.GET("", notificationHandler.GetNotifications)
+		api.DELETE("", notificationHandler.DeleteNotifications)
+		api.PUT("/read-all", notificationHandler.MarkAllAsRead)
+		api.GET("/:id", notificationHandler.GetNotification)
+		api.PUT("/:id/read", notificationHandler.MarkAsRead)
+		api.DELETE("/:id", notificationHandler.DeleteNotification)
+		api.GET("/unread/count", notificationHandler.GetUnreadCount)
+		api.GET("/stream", notificationHandler.StreamNotifications)
+
+		// Preference endpoints
+		api.GET("/preferences", notificationHandler.GetPreferences)
+		api.PUT("/preferences", notificationHandler.UpdatePreferences)
+	}
+}
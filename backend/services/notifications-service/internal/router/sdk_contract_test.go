@@ -0,0 +1,429 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/notifications-service/internal/middleware"
+	"github.com/gofund/notifications-service/internal/models"
+	"github.com/gofund/notifications-service/internal/repository"
+	"github.com/gofund/notifications-service/internal/service"
+	"github.com/gofund/sdk"
+	"github.com/google/uuid"
+)
+
+const contractIdentitySecret = "contract-identity-secret"
+
+// notificationsFixture is the inbox the SDK contracts run against: an unread payment
+// notification, an unread goal notification and a read proof notification for the user,
+// and an unread notification of another user's
+type notificationsFixture struct {
+	userID                                   uuid.UUID
+	paymentID, goalID, proofID, otherUsersID uuid.UUID
+	notifications                            *fakeNotificationRepo
+	preferences                              *fakePreferenceRepo
+}
+
+// sdkContract is one SDK method called against the notification routes. check inspects
+// the result and the stored state against the fixture; the contracts run in order and
+// later ones see the changes of earlier ones.
+type sdkContract struct {
+	name  string
+	call  func(ctx context.Context, c *sdk.NotificationsClient, f *notificationsFixture) (interface{}, error)
+	check func(t *testing.T, f *notificationsFixture, result interface{})
+}
+
+// sdkContracts covers every NotificationsClient method
+func sdkContracts() []sdkContract {
+	return []sdkContract{
+		{
+			name: "ListNotifications",
+			call: func(ctx context.Context, c *sdk.NotificationsClient, f *notificationsFixture) (interface{}, error) {
+				unread := false
+				return c.ListNotifications(ctx, sdk.ListNotificationsQuery{IsRead: &unread, Type: string(models.NotificationTypePaymentVerified), Page: 1, PageSize: 10})
+			},
+			check: func(t *testing.T, f *notificationsFixture, result interface{}) {
+				page := result.(*sdk.NotificationsPage)
+				if page.Total != 1 || len(page.Notifications) != 1 || page.PageSize != 10 || page.TotalPages != 1 {
+					t.Fatalf("page = %+v, want the one unread payment notification", page)
+				}
+				if notification := page.Notifications[0]; notification.ID != f.paymentID.String() || notification.UserID != f.userID.String() {
+					t.Errorf("notification = %+v, want %s", notification, f.paymentID)
+				}
+			},
+		},
+		{
+			name: "GetNotification",
+			call: func(ctx context.Context, c *sdk.NotificationsClient, f *notificationsFixture) (interface{}, error) {
+				return c.GetNotification(ctx, f.goalID.String())
+			},
+			check: func(t *testing.T, f *notificationsFixture, result interface{}) {
+				notification := result.(*sdk.Notification)
+				if notification.ID != f.goalID.String() || notification.Type != string(models.NotificationTypeGoalFunded) || notification.IsRead {
+					t.Errorf("notification = %+v, want the unread goal notification", notification)
+				}
+			},
+		},
+		{
+			name: "GetUnreadCount",
+			call: func(ctx context.Context, c *sdk.NotificationsClient, f *notificationsFixture) (interface{}, error) {
+				return c.GetUnreadCount(ctx)
+			},
+			check: func(t *testing.T, f *notificationsFixture, result interface{}) {
+				if count := result.(int64); count != 2 {
+					t.Errorf("unread count = %d, want 2", count)
+				}
+			},
+		},
+		{
+			name: "MarkAsRead",
+			call: func(ctx context.Context, c *sdk.NotificationsClient, f *notificationsFixture) (interface{}, error) {
+				return nil, c.MarkAsRead(ctx, f.paymentID.String())
+			},
+			check: func(t *testing.T, f *notificationsFixture, result interface{}) {
+				if notification, ok := f.notifications.get(f.paymentID); !ok || !notification.IsRead || notification.ReadAt == nil {
+					t.Errorf("payment notification = %+v, want it read", notification)
+				}
+			},
+		},
+		{
+			name: "MarkAllAsRead",
+			call: func(ctx context.Context, c *sdk.NotificationsClient, f *notificationsFixture) (interface{}, error) {
+				return c.MarkAllAsRead(ctx, string(models.NotificationTypeGoalFunded))
+			},
+			check: func(t *testing.T, f *notificationsFixture, result interface{}) {
+				if updated := result.(int64); updated != 1 {
+					t.Errorf("updated = %d, want the goal notification", updated)
+				}
+				if notification, _ := f.notifications.get(f.otherUsersID); notification.IsRead {
+					t.Error("another user's notification was marked read")
+				}
+			},
+		},
+		{
+			name: "DeleteNotification",
+			call: func(ctx context.Context, c *sdk.NotificationsClient, f *notificationsFixture) (interface{}, error) {
+				return nil, c.DeleteNotification(ctx, f.proofID.String())
+			},
+			check: func(t *testing.T, f *notificationsFixture, result interface{}) {
+				if _, ok := f.notifications.get(f.proofID); ok {
+					t.Error("proof notification was not deleted")
+				}
+			},
+		},
+		{
+			name: "DeleteNotifications",
+			call: func(ctx context.Context, c *sdk.NotificationsClient, f *notificationsFixture) (interface{}, error) {
+				return c.DeleteNotifications(ctx, []string{f.paymentID.String(), f.otherUsersID.String()})
+			},
+			check: func(t *testing.T, f *notificationsFixture, result interface{}) {
+				if deleted := result.(int64); deleted != 1 {
+					t.Errorf("deleted = %d, want only the user's own notification", deleted)
+				}
+				if _, ok := f.notifications.get(f.otherUsersID); !ok {
+					t.Error("another user's notification was deleted")
+				}
+			},
+		},
+		{
+			name: "GetPreferences",
+			call: func(ctx context.Context, c *sdk.NotificationsClient, f *notificationsFixture) (interface{}, error) {
+				return c.GetPreferences(ctx)
+			},
+			check: func(t *testing.T, f *notificationsFixture, result interface{}) {
+				preferences := result.(*sdk.NotificationPreferences)
+				if preferences.UserID != f.userID.String() || !preferences.EmailEnabled || preferences.MarketingEmails || preferences.EmailFrequency != string(models.EmailFrequencyInstant) {
+					t.Errorf("preferences = %+v, want the defaults", preferences)
+				}
+			},
+		},
+		{
+			name: "UpdatePreferences",
+			call: func(ctx context.Context, c *sdk.NotificationsClient, f *notificationsFixture) (interface{}, error) {
+				marketing, daily := true, string(models.EmailFrequencyDaily)
+				return nil, c.UpdatePreferences(ctx, &sdk.UpdatePreferencesRequest{MarketingEmails: &marketing, EmailFrequency: &daily})
+			},
+			check: func(t *testing.T, f *notificationsFixture, result interface{}) {
+				preferences, err := f.preferences.GetByUserID(f.userID.String())
+				if err != nil {
+					t.Fatalf("GetByUserID: %v", err)
+				}
+				if !preferences.MarketingEmails || preferences.EmailFrequency != models.EmailFrequencyDaily || !preferences.EmailEnabled {
+					t.Errorf("preferences = %+v, want marketing on and daily digests", preferences)
+				}
+			},
+		},
+	}
+}
+
+// TestSDKContract calls every SDK method against the real routes and notification
+// service over an in-memory inbox, as a user signed in through the gateway
+func TestSDKContract(t *testing.T) {
+	f := seedInbox()
+	notificationService := service.NewNotificationService(f.notifications, f.preferences, nil, nil, nil, false, 0, service.EmailDispatcherConfig{Workers: 1, QueueSize: 1})
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := notificationService.Close(ctx); err != nil {
+			t.Errorf("Close: %v", err)
+		}
+	})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.IdentityGuard(contractIdentitySecret))
+	SetupRoutes(r, notificationService)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	client := sdk.NewNotificationsClient(server.URL, sdk.WithIdentity(contractIdentitySecret, f.userID.String(), "user", true))
+	for _, tc := range sdkContracts() {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.call(context.Background(), client, f)
+			if err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			tc.check(t, f, result)
+		})
+	}
+}
+
+// seedInbox stores the fixture's notifications; the user has no preferences yet
+func seedInbox() *notificationsFixture {
+	f := &notificationsFixture{
+		userID:        uuid.New(),
+		notifications: &fakeNotificationRepo{notifications: make(map[uuid.UUID]*models.Notification)},
+		preferences:   &fakePreferenceRepo{preferences: make(map[string]*models.NotificationPreferences)},
+	}
+	now := time.Now()
+	f.paymentID = f.notifications.add(f.userID.String(), models.NotificationTypePaymentVerified, nil, now.Add(-3*time.Minute))
+	f.goalID = f.notifications.add(f.userID.String(), models.NotificationTypeGoalFunded, nil, now.Add(-2*time.Minute))
+	f.proofID = f.notifications.add(f.userID.String(), models.NotificationTypeProofSubmitted, &now, now.Add(-time.Minute))
+	f.otherUsersID = f.notifications.add(uuid.NewString(), models.NotificationTypeGoalFunded, nil, now)
+	return f
+}
+
+// fakeNotificationRepo keeps notifications in memory. It implements the calls the HTTP
+// routes make; any other call panics on the nil embedded interface.
+type fakeNotificationRepo struct {
+	repository.NotificationRepository
+
+	mu            sync.Mutex
+	notifications map[uuid.UUID]*models.Notification
+}
+
+// add stores a notification for the user, read at readAt when it is set
+func (r *fakeNotificationRepo) add(userID string, notificationType models.NotificationType, readAt *time.Time, createdAt time.Time) uuid.UUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := uuid.New()
+	r.notifications[id] = &models.Notification{
+		ID:        id,
+		UserID:    userID,
+		Type:      notificationType,
+		Title:     string(notificationType),
+		Message:   "seeded",
+		Data:      map[string]interface{}{},
+		IsRead:    readAt != nil,
+		ReadAt:    readAt,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	return id
+}
+
+// get returns a copy of the stored notification
+func (r *fakeNotificationRepo) get(id uuid.UUID) (models.Notification, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	notification, ok := r.notifications[id]
+	if !ok {
+		return models.Notification{}, false
+	}
+	return *notification, true
+}
+
+// owned returns the user's notification, or ErrNotificationNotFound
+func (r *fakeNotificationRepo) owned(id, userID uuid.UUID) (*models.Notification, error) {
+	notification, ok := r.notifications[id]
+	if !ok || notification.UserID != userID.String() {
+		return nil, repository.ErrNotificationNotFound
+	}
+	return notification, nil
+}
+
+func (r *fakeNotificationRepo) GetByID(id, userID uuid.UUID) (*models.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	notification, err := r.owned(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	stored := *notification
+	return &stored, nil
+}
+
+func (r *fakeNotificationRepo) List(query models.ListNotificationsQuery) ([]models.Notification, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var matched []models.Notification
+	for _, notification := range r.notifications {
+		if notification.UserID != query.UserID ||
+			(query.IsRead != nil && notification.IsRead != *query.IsRead) ||
+			(query.Type != "" && string(notification.Type) != query.Type) {
+			continue
+		}
+		matched = append(matched, *notification)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].CreatedAt.After(matched[j].CreatedAt) })
+
+	total := int64(len(matched))
+	start := (query.Page - 1) * query.PageSize
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := start + query.PageSize
+	if end > len(matched) {
+		end = len(matched)
+	}
+	return matched[start:end], total, nil
+}
+
+func (r *fakeNotificationRepo) MarkAsRead(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	notification, err := r.owned(id, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	notification.IsRead = true
+	notification.ReadAt = &now
+	return nil
+}
+
+func (r *fakeNotificationRepo) MarkAllAsRead(userID uuid.UUID, notificationType string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var updated int64
+	now := time.Now()
+	for _, notification := range r.notifications {
+		if notification.UserID != userID.String() || notification.IsRead ||
+			(notificationType != "" && string(notification.Type) != notificationType) {
+			continue
+		}
+		notification.IsRead = true
+		notification.ReadAt = &now
+		updated++
+	}
+	return updated, nil
+}
+
+func (r *fakeNotificationRepo) Delete(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.owned(id, userID); err != nil {
+		return err
+	}
+	delete(r.notifications, id)
+	return nil
+}
+
+func (r *fakeNotificationRepo) DeleteMany(userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for _, id := range ids {
+		if _, err := r.owned(id, userID); err == nil {
+			delete(r.notifications, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *fakeNotificationRepo) GetUnreadCount(userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, notification := range r.notifications {
+		if notification.UserID == userID && !notification.IsRead {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// fakePreferenceRepo keeps preferences in memory; users without stored preferences get
+// an error, as they do from the database
+type fakePreferenceRepo struct {
+	mu          sync.Mutex
+	preferences map[string]*models.NotificationPreferences
+}
+
+func (r *fakePreferenceRepo) Create(preferences *models.NotificationPreferences) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *preferences
+	stored.ID = uuid.NewString()
+	stored.CreatedAt = time.Now()
+	stored.UpdatedAt = stored.CreatedAt
+	r.preferences[preferences.UserID] = &stored
+	return nil
+}
+
+func (r *fakePreferenceRepo) GetByUserID(userID string) (*models.NotificationPreferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	preferences, ok := r.preferences[userID]
+	if !ok {
+		return nil, errors.New("preferences not found")
+	}
+	stored := *preferences
+	return &stored, nil
+}
+
+func (r *fakePreferenceRepo) Update(userID string, updates models.UpdatePreferencesRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	preferences, ok := r.preferences[userID]
+	if !ok {
+		return nil // an UPDATE matching no rows
+	}
+	setBool := func(field *bool, update *bool) {
+		if update != nil {
+			*field = *update
+		}
+	}
+	setBool(&preferences.EmailEnabled, updates.EmailEnabled)
+	setBool(&preferences.PaymentNotifications, updates.PaymentNotifications)
+	setBool(&preferences.ContributionNotifications, updates.ContributionNotifications)
+	setBool(&preferences.WithdrawalNotifications, updates.WithdrawalNotifications)
+	setBool(&preferences.ProofNotifications, updates.ProofNotifications)
+	setBool(&preferences.GoalNotifications, updates.GoalNotifications)
+	setBool(&preferences.MarketingEmails, updates.MarketingEmails)
+	if updates.EmailFrequency != nil {
+		preferences.EmailFrequency = *updates.EmailFrequency
+	}
+	preferences.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *fakePreferenceRepo) CreateDefault(userID string) error {
+	return r.Create(&models.NotificationPreferences{
+		UserID:                    userID,
+		EmailEnabled:              true,
+		PaymentNotifications:      true,
+		ContributionNotifications: true,
+		WithdrawalNotifications:   true,
+		ProofNotifications:        true,
+		GoalNotifications:         true,
+		EmailFrequency:            models.EmailFrequencyInstant,
+	})
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofund/notifications-service/internal/models"
+	"github.com/gofund/shared/metrics"
+	shared "github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// digestBatchSize bounds how many users of each frequency get a digest per tick
+const digestBatchSize = 100
+
+// digestFrequencies are checked for held notifications on every tick. Instant is included
+// so that users who switched back from a digest still receive what was held for them.
+var digestFrequencies = []models.EmailFrequency{
+	models.EmailFrequencyInstant,
+	models.EmailFrequencyHourly,
+	models.EmailFrequencyDaily,
+}
+
+// digestLabels names a notification type in digest lines, singular then plural
+var digestLabels = map[models.NotificationType][2]string{
+	models.NotificationTypeContributionConfirmed: {"contribution", "contributions"},
+	models.NotificationTypePaymentVerified:       {"payment", "payments"},
+	models.NotificationTypeRefundInitiated:       {"refund started", "refunds started"},
+	models.NotificationTypeRefundCompleted:       {"refund completed", "refunds completed"},
+	models.NotificationTypeRefundRequested:       {"refund requested", "refunds requested"},
+	models.NotificationTypeRefundRequestDenied:   {"refund request denied", "refund requests denied"},
+	models.NotificationTypeWithdrawalRequested:   {"withdrawal requested", "withdrawals requested"},
+	models.NotificationTypeWithdrawalCompleted:   {"withdrawal completed", "withdrawals completed"},
+	models.NotificationTypeWithdrawalFailed:      {"failed withdrawal", "failed withdrawals"},
+	models.NotificationTypeProofSubmitted:        {"proof submitted", "proofs submitted"},
+	models.NotificationTypeProofVoted:            {"proof vote", "proof votes"},
+	models.NotificationTypeProofRejected:         {"proof rejected", "proofs rejected"},
+	models.NotificationTypeGoalCommented:         {"comment", "comments"},
+	models.NotificationTypeGoalUpdatePosted:      {"goal update", "goal updates"},
+	models.NotificationTypeGoalFunded:            {"goal funded", "goals funded"},
+	models.NotificationTypeGoalDeadlineReached:   {"goal deadline reached", "goal deadlines reached"},
+}
+
+// runDigestWorker periodically sends digests to users whose window has elapsed
+func (s *notificationService) runDigestWorker() {
+	ticker := time.NewTicker(s.dispatcherCfg.DigestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopRetry:
+			return
+		case <-ticker.C:
+			s.sendDueDigests()
+		}
+	}
+}
+
+// sendDueDigests sends a digest to every user whose oldest held notification is older
+// than their frequency's window
+func (s *notificationService) sendDueDigests() {
+	now := time.Now()
+	for _, frequency := range digestFrequencies {
+		userIDs, err := s.notificationRepo.GetDigestDueUsers(frequency, now.Add(-frequency.DigestWindow()), digestBatchSize)
+		if err != nil {
+			log.Printf("Failed to get users due a %s digest: %v", frequency, err)
+			continue
+		}
+
+		for _, userID := range userIDs {
+			s.sendDigest(userID, frequency)
+		}
+	}
+}
+
+// sendDigest claims a user's held notifications and emails them as one summary
+func (s *notificationService) sendDigest(userID string, frequency models.EmailFrequency) {
+	notifications, err := s.notificationRepo.ClaimDigestNotifications(userID)
+	if err != nil {
+		log.Printf("Failed to claim digest notifications for user %s: %v", userID, err)
+		return
+	}
+
+	// Preferences may have changed since the notifications were held
+	if preferences, err := s.preferenceRepo.GetByUserID(userID); err == nil {
+		if !preferences.EmailEnabled {
+			log.Printf("Email notifications disabled for user %s, dropping digest", userID)
+			return
+		}
+		allowed := notifications[:0]
+		for _, notification := range notifications {
+			if preferences.Allows(notification.Type) {
+				allowed = append(allowed, notification)
+			}
+		}
+		notifications = allowed
+	}
+	if len(notifications) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, len(notifications))
+	for i := range notifications {
+		ids[i] = notifications[i].ID
+	}
+
+	email, err := s.lookupEmail(userID)
+	if err != nil {
+		s.requeueDigest(userID, ids)
+		return
+	}
+	for i := 0; email == "" && i < len(notifications); i++ {
+		email, _ = notifications[i].Data["email"].(string)
+	}
+	if email == "" {
+		log.Printf("No email address for user %s, dropping digest", userID)
+		return
+	}
+
+	payload := shared.EmailPayload{
+		Type:      shared.EmailTypeDigest,
+		Recipient: email,
+		Subject:   digestTitle(frequency),
+		Data:      digestData(notifications, frequency),
+	}
+	payload.Data["unsubscribe_url"] = s.unsubscribe.URL(userID, UnsubscribeAll)
+	if err := s.emailService.Send(payload); err != nil {
+		if errors.Is(err, ErrPermanentEmailFailure) {
+			log.Printf("Digest to user %s refused, dropping it: %v", userID, err)
+			metrics.IncrementCounter("notification.email.permanent_failure")
+			return
+		}
+		log.Printf("Failed to send digest to user %s: %v", userID, err)
+		s.requeueDigest(userID, ids)
+		return
+	}
+
+	if err := s.notificationRepo.MarkManyAsEmailSent(ids); err != nil {
+		log.Printf("Failed to mark digest notifications as sent for user %s: %v", userID, err)
+	}
+}
+
+// requeueDigest puts a user's claimed notifications back for the next tick
+func (s *notificationService) requeueDigest(userID string, ids []uuid.UUID) {
+	if err := s.notificationRepo.RequeueForDigest(ids); err != nil {
+		log.Printf("Failed to requeue digest notifications for user %s: %v", userID, err)
+	}
+}
+
+// digestTitle is the subject and heading of a digest email
+func digestTitle(frequency models.EmailFrequency) string {
+	if frequency == models.EmailFrequencyInstant {
+		return "Your GoFund digest"
+	}
+	return fmt.Sprintf("Your %s GoFund digest", frequency)
+}
+
+// digestPeriod describes the window a digest covers
+func digestPeriod(frequency models.EmailFrequency) string {
+	switch frequency {
+	case models.EmailFrequencyHourly:
+		return "in the last hour"
+	case models.EmailFrequencyDaily:
+		return "today"
+	default:
+		return "since your last email"
+	}
+}
+
+// digestData summarises notifications one line per type, in order of first appearance,
+// e.g. "12 contributions totalling ₦45,000.00"
+func digestData(notifications []models.Notification, frequency models.EmailFrequency) map[string]interface{} {
+	type group struct {
+		count    int
+		total    int64
+		currency string
+		// summable is false once a notification lacks an amount or is in another currency
+		summable bool
+	}
+
+	var order []models.NotificationType
+	groups := make(map[models.NotificationType]*group)
+	for _, notification := range notifications {
+		g, ok := groups[notification.Type]
+		if !ok {
+			g = &group{summable: true, currency: stringValue(notification.Data, "currency")}
+			groups[notification.Type] = g
+			order = append(order, notification.Type)
+		}
+
+		g.count++
+		amount, ok := int64Value(notification.Data, "amount")
+		if !ok || stringValue(notification.Data, "currency") != g.currency {
+			g.summable = false
+		}
+		g.total += amount
+	}
+
+	lines := make([]string, 0, len(order))
+	for _, notificationType := range order {
+		g := groups[notificationType]
+
+		label, ok := digestLabels[notificationType]
+		if !ok {
+			label = [2]string{"other notification", "other notifications"}
+		}
+		line := fmt.Sprintf("%d %s", g.count, label[0])
+		if g.count > 1 {
+			line = fmt.Sprintf("%d %s", g.count, label[1])
+		}
+		if g.summable {
+			line += " totalling " + FormatAmount(g.total, g.currency)
+		}
+		lines = append(lines, line)
+	}
+
+	period := digestPeriod(frequency)
+	return map[string]interface{}{
+		"title":   digestTitle(frequency),
+		"message": fmt.Sprintf("Here's what happened on GoFund %s.", period),
+		"lines":   lines,
+	}
+}
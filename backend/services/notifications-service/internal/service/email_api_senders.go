@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// apiSender sends emails through a provider's HTTP API. Each provider supplies the
+// request body, how to read its error responses, and which refusals are permanent.
+type apiSender struct {
+	provider string
+	endpoint string
+	apiKey   string
+	client   *http.Client
+
+	body      func(to, subject, html, text string, headers map[string]string) interface{}
+	errorText func(body []byte) string
+	permanent map[int]bool
+}
+
+// SendEmail posts the provider's request body and classifies a refusal
+func (s *apiSender) SendEmail(to, subject, html, text string, headers map[string]string) error {
+	payload, err := json.Marshal(s.body(to, subject, html, text, headers))
+	if err != nil {
+		return permanentEmailError(fmt.Errorf("failed to encode %s request: %w", s.provider, err))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", s.provider, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send email via %s: %w", s.provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	detail := s.errorText(respBody)
+	if detail == "" {
+		detail = http.StatusText(resp.StatusCode)
+	}
+	err = fmt.Errorf("%s rejected email with status %d: %s", s.provider, resp.StatusCode, detail)
+	if s.permanent[resp.StatusCode] {
+		return permanentEmailError(err)
+	}
+	return err
+}
+
+// newSendGridSender creates a sender for the SendGrid v3 mail send API. SendGrid refuses
+// malformed messages and bad recipients with 400 and oversized ones with 413; those are
+// permanent. Authentication failures (401, 403) are a configuration problem a retry after
+// fixing it can get past, and rate limits (429) and server errors are transient.
+func newSendGridSender(endpoint, apiKey string, from emailAddress, timeout time.Duration) EmailSender {
+	return &apiSender{
+		provider: EmailProviderSendGrid,
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+		body: func(to, subject, html, text string, headers map[string]string) interface{} {
+			return sendGridMail{
+				Personalizations: []sendGridPersonalization{{To: []emailAddress{{Email: to}}}},
+				From:             from,
+				Subject:          subject,
+				Content: []sendGridContent{
+					{Type: "text/plain", Value: text},
+					{Type: "text/html", Value: html},
+				},
+				Headers: headers,
+			}
+		},
+		errorText: func(body []byte) string {
+			var resp struct {
+				Errors []struct {
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			if json.Unmarshal(body, &resp) != nil {
+				return ""
+			}
+			messages := make([]string, 0, len(resp.Errors))
+			for _, e := range resp.Errors {
+				messages = append(messages, e.Message)
+			}
+			return strings.Join(messages, "; ")
+		},
+		permanent: map[int]bool{
+			http.StatusBadRequest:            true,
+			http.StatusRequestEntityTooLarge: true,
+		},
+	}
+}
+
+// sendGridMail is a SendGrid v3 mail send request
+type sendGridMail struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             emailAddress              `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+	Headers          map[string]string         `json:"headers,omitempty"`
+}
+
+type sendGridPersonalization struct {
+	To []emailAddress `json:"to"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// newResendSender creates a sender for the Resend emails API. Resend refuses invalid
+// messages with 400 and 422; those are permanent. As with SendGrid, authentication
+// failures, rate limits and server errors may be retried.
+func newResendSender(endpoint, apiKey string, from emailAddress, timeout time.Duration) EmailSender {
+	return &apiSender{
+		provider: EmailProviderResend,
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+		body: func(to, subject, html, text string, headers map[string]string) interface{} {
+			return resendEmail{
+				From:    from.String(),
+				To:      []string{to},
+				Subject: subject,
+				HTML:    html,
+				Text:    text,
+				Headers: headers,
+			}
+		},
+		errorText: func(body []byte) string {
+			var resp struct {
+				Message string `json:"message"`
+			}
+			if json.Unmarshal(body, &resp) != nil {
+				return ""
+			}
+			return resp.Message
+		},
+		permanent: map[int]bool{
+			http.StatusBadRequest:          true,
+			http.StatusUnprocessableEntity: true,
+		},
+	}
+}
+
+// resendEmail is a Resend send email request
+type resendEmail struct {
+	From    string            `json:"from"`
+	To      []string          `json:"to"`
+	Subject string            `json:"subject"`
+	HTML    string            `json:"html"`
+	Text    string            `json:"text,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gofund/notifications-service/internal/models"
+	"github.com/gofund/shared/metrics"
+)
+
+// emailDispatcher runs a bounded pool of workers that send notification emails
+type emailDispatcher struct {
+	jobs    chan *models.Notification
+	send    func(*models.Notification)
+	workers int
+
+	mu     sync.RWMutex
+	closed bool
+	once   sync.Once
+	wg     sync.WaitGroup
+	done   chan struct{}
+	busy   int64
+}
+
+// newEmailDispatcher creates the dispatcher and starts its workers
+func newEmailDispatcher(workers, queueSize int, send func(*models.Notification)) *emailDispatcher {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	d := &emailDispatcher{
+		jobs:    make(chan *models.Notification, queueSize),
+		send:    send,
+		workers: workers,
+		done:    make(chan struct{}),
+	}
+
+	for i := 0; i < workers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+
+	go func() {
+		d.wg.Wait()
+		close(d.done)
+	}()
+
+	return d
+}
+
+// worker sends queued emails until the jobs channel is closed
+func (d *emailDispatcher) worker() {
+	defer d.wg.Done()
+
+	for notification := range d.jobs {
+		busy := atomic.AddInt64(&d.busy, 1)
+		metrics.RecordGauge("notification.email.pool.busy", float64(busy))
+		metrics.RecordGauge("notification.email.pool.utilization", float64(busy)/float64(d.workers))
+
+		d.send(notification)
+
+		busy = atomic.AddInt64(&d.busy, -1)
+		metrics.RecordGauge("notification.email.pool.busy", float64(busy))
+		metrics.RecordGauge("notification.email.pool.utilization", float64(busy)/float64(d.workers))
+	}
+}
+
+// Dispatch queues a notification without blocking. It returns false when the
+// queue is full or the dispatcher is shutting down.
+func (d *emailDispatcher) Dispatch(notification *models.Notification) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return false
+	}
+
+	select {
+	case d.jobs <- notification:
+		metrics.RecordGauge("notification.email.pool.queued", float64(len(d.jobs)))
+		return true
+	default:
+		metrics.IncrementCounter("notification.email.pool.overflow")
+		return false
+	}
+}
+
+// Close stops accepting new emails and waits for queued and in-flight sends to
+// finish or for ctx to expire. It is safe to call more than once.
+func (d *emailDispatcher) Close(ctx context.Context) error {
+	d.once.Do(func() {
+		d.mu.Lock()
+		d.closed = true
+		close(d.jobs)
+		d.mu.Unlock()
+	})
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
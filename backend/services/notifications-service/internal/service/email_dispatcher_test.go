@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofund/notifications-service/internal/dto"
+	"github.com/gofund/notifications-service/internal/models"
+	shared "github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// gatedSender records the notifications it sends; each send reports on started and
+// then waits for release to close
+type gatedSender struct {
+	started chan *models.Notification
+	release chan struct{}
+
+	mu   sync.Mutex
+	sent []*models.Notification
+}
+
+func newGatedSender() *gatedSender {
+	return &gatedSender{
+		started: make(chan *models.Notification, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (s *gatedSender) send(notification *models.Notification) {
+	s.started <- notification
+	<-s.release
+	s.mu.Lock()
+	s.sent = append(s.sent, notification)
+	s.mu.Unlock()
+}
+
+func (s *gatedSender) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sent)
+}
+
+// waitStarted waits for the sender to pick up a notification
+func (s *gatedSender) waitStarted(t *testing.T) *models.Notification {
+	t.Helper()
+	select {
+	case notification := <-s.started:
+		return notification
+	case <-time.After(5 * time.Second):
+		t.Fatal("no send started")
+		return nil
+	}
+}
+
+func newNotification() *models.Notification {
+	return &models.Notification{ID: uuid.New()}
+}
+
+func TestEmailDispatcherOverflow(t *testing.T) {
+	sender := newGatedSender()
+	d := newEmailDispatcher(1, 1, sender.send)
+
+	first := newNotification()
+	if !d.Dispatch(first) {
+		t.Fatal("Dispatch refused the first email with an idle worker")
+	}
+	if got := sender.waitStarted(t); got != first {
+		t.Fatalf("worker started %s, want %s", got.ID, first.ID)
+	}
+	// The only worker is busy; one email fits in the queue and the next overflows
+	if !d.Dispatch(newNotification()) {
+		t.Fatal("Dispatch refused an email with room in the queue")
+	}
+	if d.Dispatch(newNotification()) {
+		t.Fatal("Dispatch accepted an email with the queue full")
+	}
+
+	close(sender.release)
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if n := sender.count(); n != 2 {
+		t.Errorf("sent %d emails, want the 2 accepted", n)
+	}
+}
+
+func TestEmailDispatcherCloseDrainsQueue(t *testing.T) {
+	sender := newGatedSender()
+	d := newEmailDispatcher(2, 10, sender.send)
+
+	for i := 0; i < 8; i++ {
+		if !d.Dispatch(newNotification()) {
+			t.Fatalf("Dispatch refused email %d", i+1)
+		}
+	}
+	sender.waitStarted(t)
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- d.Close(context.Background())
+	}()
+
+	select {
+	case err := <-closed:
+		t.Fatalf("Close returned %v with sends still pending", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(sender.release)
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return after the sends finished")
+	}
+	if n := sender.count(); n != 8 {
+		t.Errorf("sent %d emails before Close returned, want 8", n)
+	}
+}
+
+func TestEmailDispatcherCloseIsReentrant(t *testing.T) {
+	sender := newGatedSender()
+	d := newEmailDispatcher(1, 1, sender.send)
+
+	if !d.Dispatch(newNotification()) {
+		t.Fatal("Dispatch refused the first email")
+	}
+	sender.waitStarted(t)
+
+	// A send still in flight outlives a short shutdown deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := d.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close with a send in flight: err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if d.Dispatch(newNotification()) {
+		t.Error("Dispatch accepted an email after Close")
+	}
+
+	close(sender.release)
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("third Close: %v", err)
+	}
+	if n := sender.count(); n != 1 {
+		t.Errorf("sent %d emails, want 1", n)
+	}
+}
+
+func TestCreateNotificationDefersEmailWhenQueueFull(t *testing.T) {
+	notifications := newFakeNotificationRepo()
+	preferences := newFakePreferenceRepo()
+	email := &recordingEmailService{started: make(chan shared.EmailPayload, 10), gate: make(chan struct{})}
+	s := newTestNotificationService(t, notifications, preferences, email, nil, EmailDispatcherConfig{Workers: 1, QueueSize: 1})
+
+	userID := uuid.NewString()
+	preferences.Create(defaultPreferences(userID))
+	create := func() *models.Notification {
+		t.Helper()
+		notification, err := s.CreateNotification(dto.CreateNotificationRequest{
+			UserID:  userID,
+			Type:    models.NotificationTypeGoalFunded,
+			Title:   "Goal funded",
+			Message: "Your goal reached its target",
+			Data:    map[string]interface{}{"email": "owner@example.com"},
+		})
+		if err != nil {
+			t.Fatalf("CreateNotification: %v", err)
+		}
+		return notification
+	}
+
+	sending := create()
+	select {
+	case <-email.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the first email was not sent")
+	}
+	queued := create()
+	deferred := create()
+	close(email.gate)
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	for _, notification := range []*models.Notification{sending, queued} {
+		if stored := notifications.get(t, notification.ID); !stored.EmailSent {
+			t.Errorf("notification %s: email not sent", notification.ID)
+		}
+	}
+	stored := notifications.get(t, deferred.ID)
+	if stored.EmailSent || !stored.EmailPending {
+		t.Errorf("overflowing notification: sent %v, pending %v; want it left pending for the retry worker", stored.EmailSent, stored.EmailPending)
+	}
+	if n := len(email.emails()); n != 2 {
+		t.Errorf("sent %d emails, want 2", n)
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofund/notifications-service/internal/config"
+)
+
+// Email providers selectable with EMAIL_PROVIDER
+const (
+	EmailProviderSMTP     = "smtp"
+	EmailProviderSendGrid = "sendgrid"
+	EmailProviderResend   = "resend"
+	EmailProviderNoop     = "noop"
+)
+
+// ErrPermanentEmailFailure classifies a send the provider refused for good, such as an
+// invalid recipient. Retrying it cannot succeed; any other send error may be retried.
+var ErrPermanentEmailFailure = errors.New("permanent email failure")
+
+// EmailSender delivers a rendered email through a provider. headers are extra message
+// headers, such as List-Unsubscribe.
+type EmailSender interface {
+	SendEmail(to, subject, html, text string, headers map[string]string) error
+}
+
+// NewEmailSender creates the sender cfg.EmailProvider selects
+func NewEmailSender(cfg *config.Config) (EmailSender, error) {
+	from := emailAddress{Name: cfg.SMTPFromName, Email: cfg.SMTPFrom}
+	timeout := cfg.EmailSendTimeout
+
+	switch strings.ToLower(cfg.EmailProvider) {
+	case EmailProviderSMTP, "":
+		return &smtpSender{
+			host:     cfg.SMTPHost,
+			port:     cfg.SMTPPort,
+			username: cfg.SMTPUsername,
+			password: cfg.SMTPPassword,
+			from:     from,
+		}, nil
+	case EmailProviderSendGrid:
+		if cfg.SendGridAPIKey == "" {
+			return nil, fmt.Errorf("SENDGRID_API_KEY is required for the sendgrid email provider")
+		}
+		return newSendGridSender(cfg.SendGridAPIURL, cfg.SendGridAPIKey, from, timeout), nil
+	case EmailProviderResend:
+		if cfg.ResendAPIKey == "" {
+			return nil, fmt.Errorf("RESEND_API_KEY is required for the resend email provider")
+		}
+		return newResendSender(cfg.ResendAPIURL, cfg.ResendAPIKey, from, timeout), nil
+	case EmailProviderNoop:
+		return noopSender{}, nil
+	default:
+		return nil, fmt.Errorf("unknown email provider %q: use smtp, sendgrid, resend or noop", cfg.EmailProvider)
+	}
+}
+
+// permanentEmailError marks err as ErrPermanentEmailFailure
+func permanentEmailError(err error) error {
+	return fmt.Errorf("%w: %w", ErrPermanentEmailFailure, err)
+}
+
+// emailAddress is a sender or recipient with an optional display name
+type emailAddress struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email"`
+}
+
+// String formats the address for a mail header
+func (a emailAddress) String() string {
+	if a.Name == "" {
+		return a.Email
+	}
+	return fmt.Sprintf("%s <%s>", mime.QEncoding.Encode("utf-8", a.Name), a.Email)
+}
+
+// noopSender logs emails instead of sending them, for local development
+type noopSender struct{}
+
+// SendEmail logs the email and reports it sent
+func (noopSender) SendEmail(to, subject, html, text string, headers map[string]string) error {
+	log.Printf("Email to %s not sent (noop provider): %s", to, subject)
+	return nil
+}
+
+// smtpSender sends emails through an SMTP server with PLAIN authentication
+type smtpSender struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     emailAddress
+}
+
+// SendEmail sends a multipart/alternative message with text and HTML parts
+func (s *smtpSender) SendEmail(to, subject, html, text string, headers map[string]string) error {
+	message, err := buildMIMEMessage(s.from, to, subject, html, text, headers)
+	if err != nil {
+		return permanentEmailError(err)
+	}
+
+	auth := smtp.PlainAuth("", s.username, s.password, s.host)
+	addr := fmt.Sprintf("%s:%s", s.host, s.port)
+	if err := smtp.SendMail(addr, auth, s.from.Email, []string{to}, message); err != nil {
+		return classifySMTPError(err)
+	}
+	return nil
+}
+
+// classifySMTPError treats 5xx replies about the message or its recipient as permanent.
+// Authentication replies (530, 534, 535) and everything else, including 4xx replies and
+// connection failures, may succeed later.
+func classifySMTPError(err error) error {
+	err = fmt.Errorf("failed to send email via SMTP: %w", err)
+
+	var reply *textproto.Error
+	if !errors.As(err, &reply) || reply.Code < 500 {
+		return err
+	}
+	switch reply.Code {
+	case 530, 534, 535:
+		return err
+	}
+	return permanentEmailError(err)
+}
+
+// buildMIMEMessage builds an email with a plain-text and an HTML alternative
+func buildMIMEMessage(from emailAddress, to, subject, html, text string, headers map[string]string) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"8bit"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	lines := [][2]string{
+		{"From", from.String()},
+		{"To", to},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+	}
+	for _, name := range sortedKeys(headers) {
+		lines = append(lines, [2]string{name, headers[name]})
+	}
+	lines = append(lines,
+		[2]string{"MIME-Version", "1.0"},
+		[2]string{"Content-Type", "multipart/alternative; boundary=" + parts.Boundary()},
+	)
+
+	var message bytes.Buffer
+	for _, header := range lines {
+		if strings.ContainsAny(header[1], "\r\n") {
+			return nil, fmt.Errorf("invalid %s header", header[0])
+		}
+		fmt.Fprintf(&message, "%s: %s\r\n", header[0], header[1])
+	}
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
+
+// sortedKeys returns a map's keys in order, so headers are written deterministically
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
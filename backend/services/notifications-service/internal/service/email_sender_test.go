@@ -0,0 +1,255 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofund/notifications-service/internal/config"
+)
+
+func TestNewEmailSender(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		want    string // the provider of an API sender, or smtp or noop
+		wantErr string
+	}{
+		{name: "smtp", cfg: config.Config{EmailProvider: "smtp"}, want: EmailProviderSMTP},
+		{name: "default is smtp", cfg: config.Config{}, want: EmailProviderSMTP},
+		{name: "sendgrid", cfg: config.Config{EmailProvider: "SendGrid", SendGridAPIKey: "key"}, want: EmailProviderSendGrid},
+		{name: "resend", cfg: config.Config{EmailProvider: "resend", ResendAPIKey: "key"}, want: EmailProviderResend},
+		{name: "noop", cfg: config.Config{EmailProvider: "noop"}, want: EmailProviderNoop},
+		{name: "sendgrid without a key", cfg: config.Config{EmailProvider: "sendgrid"}, wantErr: "SENDGRID_API_KEY"},
+		{name: "resend without a key", cfg: config.Config{EmailProvider: "resend"}, wantErr: "RESEND_API_KEY"},
+		{name: "unknown provider", cfg: config.Config{EmailProvider: "mailgun"}, wantErr: "unknown email provider"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, err := NewEmailSender(&tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want one mentioning %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewEmailSender: %v", err)
+			}
+
+			var got string
+			switch s := sender.(type) {
+			case *smtpSender:
+				got = EmailProviderSMTP
+			case noopSender:
+				got = EmailProviderNoop
+			case *apiSender:
+				got = s.provider
+			}
+			if got != tt.want {
+				t.Errorf("sender = %T (%s), want %s", sender, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoopSenderSucceeds(t *testing.T) {
+	if err := (noopSender{}).SendEmail("ada@example.com", "Hello", "<p>Hi</p>", "Hi", nil); err != nil {
+		t.Errorf("SendEmail: %v", err)
+	}
+}
+
+// newProviderServer answers every request with status and body, and hands each request
+// it gets to inspect
+func newProviderServer(t *testing.T, status int, body string, inspect func(r *http.Request, payload map[string]interface{})) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		if inspect != nil {
+			inspect(r, payload)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAPISenderClassifiesErrors(t *testing.T) {
+	from := emailAddress{Name: "GoFund", Email: "no-reply@gofund.test"}
+	senders := map[string]func(endpoint string) EmailSender{
+		EmailProviderSendGrid: func(endpoint string) EmailSender {
+			return newSendGridSender(endpoint, "sg-key", from, time.Second)
+		},
+		EmailProviderResend: func(endpoint string) EmailSender {
+			return newResendSender(endpoint, "re-key", from, time.Second)
+		},
+	}
+
+	tests := []struct {
+		provider      string
+		status        int
+		body          string
+		wantErr       bool
+		wantPermanent bool
+		wantDetail    string
+	}{
+		{provider: EmailProviderSendGrid, status: http.StatusAccepted},
+		{provider: EmailProviderSendGrid, status: http.StatusBadRequest, body: `{"errors":[{"message":"invalid email"},{"message":"bad subject"}]}`, wantErr: true, wantPermanent: true, wantDetail: "invalid email; bad subject"},
+		{provider: EmailProviderSendGrid, status: http.StatusRequestEntityTooLarge, wantErr: true, wantPermanent: true, wantDetail: "Request Entity Too Large"},
+		{provider: EmailProviderSendGrid, status: http.StatusUnauthorized, body: `{"errors":[{"message":"bad key"}]}`, wantErr: true, wantDetail: "bad key"},
+		{provider: EmailProviderSendGrid, status: http.StatusTooManyRequests, wantErr: true},
+		{provider: EmailProviderSendGrid, status: http.StatusInternalServerError, body: "not json", wantErr: true, wantDetail: "Internal Server Error"},
+		{provider: EmailProviderResend, status: http.StatusOK, body: `{"id":"abc"}`},
+		{provider: EmailProviderResend, status: http.StatusBadRequest, body: `{"message":"invalid from"}`, wantErr: true, wantPermanent: true, wantDetail: "invalid from"},
+		{provider: EmailProviderResend, status: http.StatusUnprocessableEntity, body: `{"message":"invalid to"}`, wantErr: true, wantPermanent: true, wantDetail: "invalid to"},
+		{provider: EmailProviderResend, status: http.StatusForbidden, body: `{"message":"domain not verified"}`, wantErr: true, wantDetail: "domain not verified"},
+		{provider: EmailProviderResend, status: http.StatusTooManyRequests, wantErr: true},
+		{provider: EmailProviderResend, status: http.StatusBadGateway, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider+" "+http.StatusText(tt.status), func(t *testing.T) {
+			server := newProviderServer(t, tt.status, tt.body, nil)
+			err := senders[tt.provider](server.URL).SendEmail("ada@example.com", "Hello", "<p>Hi</p>", "Hi", nil)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("SendEmail: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("SendEmail succeeded, want an error for status %d", tt.status)
+			}
+			if permanent := errors.Is(err, ErrPermanentEmailFailure); permanent != tt.wantPermanent {
+				t.Errorf("permanent = %v, want %v: %v", permanent, tt.wantPermanent, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantDetail) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestAPISenderUnreachableIsTransient(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	err := newResendSender(server.URL, "key", emailAddress{Email: "no-reply@gofund.test"}, time.Second).
+		SendEmail("ada@example.com", "Hello", "<p>Hi</p>", "Hi", nil)
+	if err == nil {
+		t.Fatal("SendEmail to a closed server succeeded")
+	}
+	if errors.Is(err, ErrPermanentEmailFailure) {
+		t.Errorf("connection failure is permanent, want it retried: %v", err)
+	}
+}
+
+func TestSendGridRequest(t *testing.T) {
+	from := emailAddress{Name: "GoFund", Email: "no-reply@gofund.test"}
+	server := newProviderServer(t, http.StatusAccepted, "", func(r *http.Request, payload map[string]interface{}) {
+		if got := r.Header.Get("Authorization"); got != "Bearer sg-key" {
+			t.Errorf("Authorization = %q, want Bearer sg-key", got)
+		}
+		personalizations := payload["personalizations"].([]interface{})
+		to := personalizations[0].(map[string]interface{})["to"].([]interface{})
+		if email := to[0].(map[string]interface{})["email"]; email != "ada@example.com" {
+			t.Errorf("recipient = %v, want ada@example.com", email)
+		}
+		if sender := payload["from"].(map[string]interface{}); sender["email"] != from.Email || sender["name"] != from.Name {
+			t.Errorf("from = %v, want %v", sender, from)
+		}
+		if content := payload["content"].([]interface{}); len(content) != 2 {
+			t.Errorf("content has %d parts, want text and HTML", len(content))
+		}
+		if headers := payload["headers"].(map[string]interface{}); headers["List-Unsubscribe"] != "<https://gofund.test/u>" {
+			t.Errorf("headers = %v, want List-Unsubscribe", headers)
+		}
+	})
+
+	err := newSendGridSender(server.URL, "sg-key", from, time.Second).SendEmail(
+		"ada@example.com", "Hello", "<p>Hi</p>", "Hi", map[string]string{"List-Unsubscribe": "<https://gofund.test/u>"})
+	if err != nil {
+		t.Fatalf("SendEmail: %v", err)
+	}
+}
+
+func TestResendRequest(t *testing.T) {
+	server := newProviderServer(t, http.StatusOK, `{"id":"abc"}`, func(r *http.Request, payload map[string]interface{}) {
+		if got := r.Header.Get("Authorization"); got != "Bearer re-key" {
+			t.Errorf("Authorization = %q, want Bearer re-key", got)
+		}
+		if payload["from"] != "GoFund <no-reply@gofund.test>" {
+			t.Errorf("from = %v, want GoFund <no-reply@gofund.test>", payload["from"])
+		}
+		if to := payload["to"].([]interface{}); len(to) != 1 || to[0] != "ada@example.com" {
+			t.Errorf("to = %v, want [ada@example.com]", to)
+		}
+		if payload["html"] != "<p>Hi</p>" || payload["text"] != "Hi" || payload["subject"] != "Hello" {
+			t.Errorf("payload = %v, want the subject and both bodies", payload)
+		}
+	})
+
+	err := newResendSender(server.URL, "re-key", emailAddress{Name: "GoFund", Email: "no-reply@gofund.test"}, time.Second).
+		SendEmail("ada@example.com", "Hello", "<p>Hi</p>", "Hi", nil)
+	if err != nil {
+		t.Fatalf("SendEmail: %v", err)
+	}
+}
+
+func TestClassifySMTPError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantPermanent bool
+	}{
+		{name: "mailbox unavailable", err: &textproto.Error{Code: 550, Msg: "no such user"}, wantPermanent: true},
+		{name: "mailbox name invalid", err: &textproto.Error{Code: 553, Msg: "bad address"}, wantPermanent: true},
+		{name: "message too large", err: &textproto.Error{Code: 552, Msg: "too large"}, wantPermanent: true},
+		{name: "authentication required", err: &textproto.Error{Code: 530, Msg: "auth required"}},
+		{name: "authentication failed", err: &textproto.Error{Code: 535, Msg: "bad credentials"}},
+		{name: "service unavailable", err: &textproto.Error{Code: 421, Msg: "try later"}},
+		{name: "mailbox busy", err: &textproto.Error{Code: 450, Msg: "busy"}},
+		{name: "connection failure", err: errors.New("dial tcp: connection refused")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifySMTPError(tt.err)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("classified error %v does not wrap %v", err, tt.err)
+			}
+			if permanent := errors.Is(err, ErrPermanentEmailFailure); permanent != tt.wantPermanent {
+				t.Errorf("permanent = %v, want %v", permanent, tt.wantPermanent)
+			}
+		})
+	}
+}
+
+func TestBuildMIMEMessageRejectsHeaderInjection(t *testing.T) {
+	from := emailAddress{Email: "no-reply@gofund.test"}
+	if _, err := buildMIMEMessage(from, "ada@example.com\r\nBcc: eve@example.com", "Hello", "<p>Hi</p>", "Hi", nil); err == nil {
+		t.Error("recipient with CRLF was accepted")
+	}
+	if _, err := buildMIMEMessage(from, "ada@example.com", "Hello", "<p>Hi</p>", "Hi", map[string]string{"List-Unsubscribe": "<u>\nBcc: eve@example.com"}); err == nil {
+		t.Error("header with LF was accepted")
+	}
+
+	message, err := buildMIMEMessage(from, "ada@example.com", "Hello", "<p>Hi</p>", "Hi", map[string]string{"List-Unsubscribe": "<https://gofund.test/u>"})
+	if err != nil {
+		t.Fatalf("buildMIMEMessage: %v", err)
+	}
+	for _, want := range []string{"To: ada@example.com\r\n", "List-Unsubscribe: <https://gofund.test/u>\r\n", "multipart/alternative", "<p>Hi</p>"} {
+		if !strings.Contains(string(message), want) {
+			t.Errorf("message does not contain %q", want)
+		}
+	}
+}
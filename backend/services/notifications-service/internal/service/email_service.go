@@ -2,11 +2,12 @@ package service
 
 import (
 	"fmt"
+	"html"
 	"log"
-	"net/smtp"
+	"regexp"
+	"strings"
 	"time"
 
-	"github.com/gofund/notifications-service/internal/config"
 	"github.com/gofund/shared/metrics"
 	"github.com/gofund/shared/models"
 )
@@ -17,70 +18,77 @@ type EmailService interface {
 }
 
 type emailService struct {
-	config        *config.Config
-	renderService RenderService
+	sender   EmailSender
+	renderer EmailTemplateRenderer
 }
 
-// NewEmailService creates a new email service
-func NewEmailService(cfg *config.Config, renderService RenderService) EmailService {
+// NewEmailService creates a new email service that delivers through sender
+func NewEmailService(sender EmailSender, renderer EmailTemplateRenderer) EmailService {
 	return &emailService{
-		config:        cfg,
-		renderService: renderService,
+		sender:   sender,
+		renderer: renderer,
 	}
 }
 
-// Send renders and sends an email
+// Send renders and sends an email. Errors wrapping ErrPermanentEmailFailure should not
+// be retried.
 func (s *emailService) Send(payload models.EmailPayload) error {
 	start := time.Now()
 
-	// 1. Render HTML Body
-	htmlBody, err := s.renderService.Render(payload.Type, payload.Data)
+	htmlBody, err := s.renderer.Render(payload.Type, payload.Data)
 	if err != nil {
 		return fmt.Errorf("failed to render email: %w", err)
 	}
 
-	// 2. Build email message
-	from := fmt.Sprintf("%s <%s>", s.config.SMTPFromName, s.config.SMTPFrom)
-	
-	headers := make(map[string]string)
-	headers["From"] = from
-	headers["To"] = payload.Recipient
-	headers["Subject"] = payload.Subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = "text/html; charset=UTF-8"
-
-	message := ""
-	for k, v := range headers {
-		message += fmt.Sprintf("%s: %s\r\n", k, v)
-	}
-	message += "\r\n" + htmlBody
-
-	// 3. SMTP authentication (Gmail App Password)
-	auth := smtp.PlainAuth(
-		"",
-		s.config.SMTPUsername,
-		s.config.SMTPPassword,
-		s.config.SMTPHost,
-	)
-
-	// 4. Send email
-	addr := fmt.Sprintf("%s:%s", s.config.SMTPHost, s.config.SMTPPort)
-	err = smtp.SendMail(
-		addr,
-		auth,
-		s.config.SMTPFrom,
-		[]string{payload.Recipient},
-		[]byte(message),
-	)
+	err = s.sender.SendEmail(payload.Recipient, payload.Subject, htmlBody, htmlToText(htmlBody), emailHeaders(payload.Data))
 
 	duration := time.Since(start)
 
 	if err != nil {
 		metrics.TrackEmailSent(false, duration)
-		return fmt.Errorf("failed to send email via SMTP: %w", err)
+		return err
 	}
 
 	metrics.TrackEmailSent(true, duration)
 	log.Printf("Email [%s] sent to %s (duration: %v)", payload.Type, payload.Recipient, duration)
 	return nil
 }
+
+// emailHeaders returns the extra headers for an email: List-Unsubscribe, with one-click
+// unsubscribe (RFC 8058), when the email carries an unsubscribe link
+func emailHeaders(data map[string]interface{}) map[string]string {
+	unsubscribeURL := stringValue(data, "unsubscribe_url")
+	if unsubscribeURL == "" {
+		return nil
+	}
+	return map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+}
+
+var (
+	htmlHiddenPattern  = regexp.MustCompile(`(?is)<(head|style|script)\b.*?</(head|style|script)>`)
+	htmlLinkPattern    = regexp.MustCompile(`(?is)<a\b[^>]*\bhref="([^"]*)"[^>]*>(.*?)</a>`)
+	htmlBreakPattern   = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|h[1-6]|li|tr|table)>`)
+	htmlTagPattern     = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLinesPattern  = regexp.MustCompile(`\n{3,}`)
+	lineSpacingPattern = regexp.MustCompile(`[ \t]+`)
+)
+
+// htmlToText derives the plain-text alternative of a rendered email: its visible text,
+// one block per line, with each link's URL after its label
+func htmlToText(body string) string {
+	text := htmlHiddenPattern.ReplaceAllString(body, "")
+	text = htmlLinkPattern.ReplaceAllString(text, "$2 ($1)")
+	text = htmlBreakPattern.ReplaceAllString(text, "\n")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(lineSpacingPattern.ReplaceAllString(line, " "))
+	}
+	text = strings.Join(lines, "\n")
+	return strings.TrimSpace(blankLinesPattern.ReplaceAllString(text, "\n\n"))
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofund/notifications-service/internal/models"
+	"github.com/gofund/notifications-service/internal/repository"
+	shared "github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// fakeNotificationRepo keeps notifications in memory. It implements the calls the
+// create and send paths make; any other call panics on the nil embedded interface.
+type fakeNotificationRepo struct {
+	repository.NotificationRepository
+
+	mu            sync.Mutex
+	notifications map[uuid.UUID]*models.Notification
+	digest        map[uuid.UUID]bool
+}
+
+func newFakeNotificationRepo() *fakeNotificationRepo {
+	return &fakeNotificationRepo{
+		notifications: make(map[uuid.UUID]*models.Notification),
+		digest:        make(map[uuid.UUID]bool),
+	}
+}
+
+func (r *fakeNotificationRepo) Create(notification *models.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	notification.ID = uuid.New()
+	notification.CreatedAt = time.Now()
+	notification.UpdatedAt = notification.CreatedAt
+	stored := *notification
+	r.notifications[notification.ID] = &stored
+	return nil
+}
+
+func (r *fakeNotificationRepo) update(id uuid.UUID, apply func(*models.Notification)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	notification, ok := r.notifications[id]
+	if !ok {
+		return repository.ErrNotificationNotFound
+	}
+	apply(notification)
+	return nil
+}
+
+func (r *fakeNotificationRepo) MarkAsEmailSent(id uuid.UUID) error {
+	return r.update(id, func(n *models.Notification) {
+		now := time.Now()
+		n.EmailSent = true
+		n.EmailSentAt = &now
+		n.EmailPending = false
+	})
+}
+
+func (r *fakeNotificationRepo) MarkAsEmailFailed(id uuid.UUID, reason string) error {
+	return r.update(id, func(n *models.Notification) {
+		n.EmailFailedReason = &reason
+	})
+}
+
+func (r *fakeNotificationRepo) IncrementRetryCount(id uuid.UUID) error {
+	return r.update(id, func(n *models.Notification) {
+		n.RetryCount++
+	})
+}
+
+func (r *fakeNotificationRepo) MarkAsEmailPending(id uuid.UUID) error {
+	return r.update(id, func(n *models.Notification) {
+		n.EmailPending = true
+	})
+}
+
+func (r *fakeNotificationRepo) MarkForDigest(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.digest[id] = true
+	return nil
+}
+
+// get returns a copy of the stored notification
+func (r *fakeNotificationRepo) get(t *testing.T, id uuid.UUID) models.Notification {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	notification, ok := r.notifications[id]
+	if !ok {
+		t.Fatalf("notification %s was not stored", id)
+	}
+	return *notification
+}
+
+// fakePreferenceRepo keeps preferences in memory; users without stored preferences get
+// an error, as they do from the database
+type fakePreferenceRepo struct {
+	mu          sync.Mutex
+	preferences map[string]*models.NotificationPreferences
+}
+
+func newFakePreferenceRepo() *fakePreferenceRepo {
+	return &fakePreferenceRepo{preferences: make(map[string]*models.NotificationPreferences)}
+}
+
+func (r *fakePreferenceRepo) Create(preferences *models.NotificationPreferences) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *preferences
+	r.preferences[preferences.UserID] = &stored
+	return nil
+}
+
+func (r *fakePreferenceRepo) GetByUserID(userID string) (*models.NotificationPreferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	preferences, ok := r.preferences[userID]
+	if !ok {
+		return nil, errors.New("preferences not found")
+	}
+	stored := *preferences
+	return &stored, nil
+}
+
+func (r *fakePreferenceRepo) Update(userID string, updates models.UpdatePreferencesRequest) error {
+	return errors.New("not implemented")
+}
+
+func (r *fakePreferenceRepo) CreateDefault(userID string) error {
+	return r.Create(defaultPreferences(userID))
+}
+
+// defaultPreferences turns every category on with instant email
+func defaultPreferences(userID string) *models.NotificationPreferences {
+	return &models.NotificationPreferences{
+		UserID:                    userID,
+		EmailEnabled:              true,
+		PaymentNotifications:      true,
+		ContributionNotifications: true,
+		WithdrawalNotifications:   true,
+		ProofNotifications:        true,
+		GoalNotifications:         true,
+		EmailFrequency:            models.EmailFrequencyInstant,
+	}
+}
+
+// recordingEmailService records the emails it is asked to send. When gate is set each
+// send reports on started and then waits for gate to close.
+type recordingEmailService struct {
+	mu      sync.Mutex
+	sent    []shared.EmailPayload
+	err     error
+	started chan shared.EmailPayload
+	gate    chan struct{}
+}
+
+func (s *recordingEmailService) Send(payload shared.EmailPayload) error {
+	if s.gate != nil {
+		s.started <- payload
+		<-s.gate
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, payload)
+	return nil
+}
+
+func (s *recordingEmailService) emails() []shared.EmailPayload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]shared.EmailPayload(nil), s.sent...)
+}
+
+// stubUserClient answers user lookups from users, or with err when it is set
+type stubUserClient struct {
+	mu    sync.Mutex
+	users map[string]*UserInfo
+	err   error
+	calls int
+}
+
+func (c *stubUserClient) GetUser(userID string) (*UserInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	user, ok := c.users[userID]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// newTestNotificationService builds a service over the fakes with no retry or digest
+// workers, and closes it when the test ends
+func newTestNotificationService(t *testing.T, notifications *fakeNotificationRepo, preferences *fakePreferenceRepo, email EmailService, users UserClient, cfg EmailDispatcherConfig) *notificationService {
+	t.Helper()
+	s := NewNotificationService(notifications, preferences, email, users, nil, false, 0, cfg).(*notificationService)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.Close(ctx); err != nil {
+			t.Errorf("Close: %v", err)
+		}
+	})
+	return s
+}
@@ -1,72 +1,208 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
+	"sync"
+	"time"
 
 	"github.com/gofund/notifications-service/internal/dto"
 	"github.com/gofund/notifications-service/internal/models"
 	"github.com/gofund/notifications-service/internal/repository"
+	"github.com/gofund/shared/metrics"
 	shared "github.com/gofund/shared/models"
+	"github.com/google/uuid"
 )
 
 
 // NotificationService handles notification business logic
 type NotificationService interface {
 	CreateNotification(req dto.CreateNotificationRequest) (*models.Notification, error)
-	GetNotification(id string) (*models.Notification, error)
+	GetNotification(id, userID uuid.UUID) (*models.Notification, error)
 	GetUserNotifications(userID string, page, pageSize int) (*dto.PaginatedNotifications, error)
 	ListNotifications(query dto.ListNotificationsQuery) (*dto.PaginatedNotifications, error)
-	MarkAsRead(id string) error
-	DeleteNotification(id string) error
+	MarkAsRead(id, userID uuid.UUID) error
+	MarkAllAsRead(userID uuid.UUID, notificationType string) (int64, error)
+	DeleteNotification(id, userID uuid.UUID) error
+	DeleteNotifications(userID uuid.UUID, ids []uuid.UUID) (int64, error)
 	GetUnreadCount(userID string) (int64, error)
+
+	// Subscribe opens a stream of the user's notifications as they are created; the
+	// returned function closes it
+	Subscribe(userID string) (<-chan *models.Notification, func(), error)
+	// CloseStreams ends all open streams; called when the server shuts down
+	CloseStreams()
 	
 	// Preference methods
 	GetUserPreferences(userID string) (*models.NotificationPreferences, error)
+	Unsubscribe(token string) (models.PreferenceCategory, error)
 	UpdateUserPreferences(userID string, updates dto.UpdatePreferencesRequest) error
 	CreateDefaultPreferences(userID string) error
+
+	// Close stops the email workers, waiting for in-flight sends until ctx expires
+	Close(ctx context.Context) error
+}
+
+// EmailDispatcherConfig configures the background email worker pool
+type EmailDispatcherConfig struct {
+	Workers       int
+	QueueSize     int
+	RetryInterval time.Duration
+	MaxRetries    int
+	// DigestInterval is how often hourly and daily digests are checked for; zero disables
+	// sending them
+	DigestInterval time.Duration
 }
 
 type notificationService struct {
 	notificationRepo repository.NotificationRepository
 	preferenceRepo   repository.PreferenceRepository
 	emailService     EmailService
+	userClient       UserClient
+	unsubscribe      *UnsubscribeLinks
+	suppressInApp    bool
+	hub              *streamHub
+	dispatcher       *emailDispatcher
+	dispatcherCfg    EmailDispatcherConfig
+	stopRetry        chan struct{}
+	stopOnce         sync.Once
 }
 
-// NewNotificationService creates a new notification service
+// NewNotificationService creates a new notification service and starts its email workers.
+// Notifications in a category the user turned off never get an email; with suppressInApp
+// they are not recorded in-app either. Emails carry unsubscribe links from unsubscribe.
 func NewNotificationService(
 	notificationRepo repository.NotificationRepository,
 	preferenceRepo repository.PreferenceRepository,
 	emailService EmailService,
+	userClient UserClient,
+	unsubscribe *UnsubscribeLinks,
+	suppressInApp bool,
+	maxStreamsPerUser int,
+	dispatcherCfg EmailDispatcherConfig,
 ) NotificationService {
-	return &notificationService{
+	s := &notificationService{
 		notificationRepo: notificationRepo,
 		preferenceRepo:   preferenceRepo,
 		emailService:     emailService,
+		userClient:       userClient,
+		unsubscribe:      unsubscribe,
+		suppressInApp:    suppressInApp,
+		hub:              newStreamHub(maxStreamsPerUser),
+		dispatcherCfg:    dispatcherCfg,
+		stopRetry:        make(chan struct{}),
 	}
+	s.dispatcher = newEmailDispatcher(dispatcherCfg.Workers, dispatcherCfg.QueueSize, s.sendEmailNotification)
+
+	if dispatcherCfg.RetryInterval > 0 {
+		go s.runRetryWorker()
+	}
+	if dispatcherCfg.DigestInterval > 0 {
+		go s.runDigestWorker()
+	}
+
+	return s
 }
 
-// CreateNotification creates a new notification
+// CreateNotification creates a new notification. When in-app suppression is on and the
+// user turned off the notification's category, nothing is created and it returns nil.
 func (s *notificationService) CreateNotification(req dto.CreateNotificationRequest) (*models.Notification, error) {
+	if s.suppressInApp {
+		preferences, err := s.preferenceRepo.GetByUserID(req.UserID)
+		if err == nil && !preferences.Allows(req.Type) {
+			log.Printf("%s notifications disabled for user %s, skipping %s", req.Type.Category(), req.UserID, req.Type)
+			return nil, nil
+		}
+	}
+
+	data := req.Data
+	if req.Link != nil {
+		if err := req.Link.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s %q", err, req.Link.Type, req.Link.ID)
+		}
+		if data == nil {
+			data = make(map[string]interface{}, 1)
+		}
+		data["link"] = req.Link
+	}
+
 	notification := &models.Notification{
 		UserID:  req.UserID,
 		Type:    req.Type,
 		Title:   req.Title,
 		Message: req.Message,
-		Data:    req.Data,
+		Data:    data,
+		Link:    req.Link,
 	}
 
 	if err := s.notificationRepo.Create(notification); err != nil {
 		return nil, fmt.Errorf("failed to create notification: %w", err)
 	}
 
-	// Try to send email asynchronously
-	go s.sendEmailNotification(notification)
+	s.hub.publish(notification)
+
+	// Hand the email to the worker pool; if it is saturated, leave it for the retry worker
+	if !s.dispatcher.Dispatch(notification) {
+		log.Printf("Email queue full, deferring email for notification %s", notification.ID)
+		if err := s.notificationRepo.MarkAsEmailPending(notification.ID); err != nil {
+			log.Printf("Failed to mark notification %s as email pending: %v", notification.ID, err)
+		}
+	}
 
 	return notification, nil
 }
 
+// runRetryWorker periodically re-dispatches notifications whose email is still pending
+func (s *notificationService) runRetryWorker() {
+	ticker := time.NewTicker(s.dispatcherCfg.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopRetry:
+			return
+		case <-ticker.C:
+			s.retryPendingEmails()
+		}
+	}
+}
+
+// retryPendingEmails claims pending notifications and queues them on the dispatcher
+func (s *notificationService) retryPendingEmails() {
+	limit := s.dispatcherCfg.QueueSize
+	if limit <= 0 {
+		limit = s.dispatcherCfg.Workers
+	}
+
+	notifications, err := s.notificationRepo.ClaimPendingEmails(limit, s.dispatcherCfg.MaxRetries)
+	if err != nil {
+		log.Printf("Failed to claim pending emails: %v", err)
+		return
+	}
+
+	for i := range notifications {
+		notification := &notifications[i]
+		if !s.dispatcher.Dispatch(notification) {
+			// Still saturated (or shutting down) - put it back for the next tick
+			if err := s.notificationRepo.MarkAsEmailPending(notification.ID); err != nil {
+				log.Printf("Failed to mark notification %s as email pending: %v", notification.ID, err)
+			}
+		}
+	}
+}
+
+// Close stops the retry and digest workers and drains the email worker pool
+func (s *notificationService) Close(ctx context.Context) error {
+	s.stopOnce.Do(func() {
+		close(s.stopRetry)
+	})
+
+	return s.dispatcher.Close(ctx)
+}
+
 // sendEmailNotification sends an email for a notification
 func (s *notificationService) sendEmailNotification(notification *models.Notification) {
 	// 1. Check user preferences
@@ -79,12 +215,31 @@ func (s *notificationService) sendEmailNotification(notification *models.Notific
 	} else if !preferences.EmailEnabled {
 		log.Printf("Email notifications disabled for user %s", notification.UserID)
 		return
+	} else if !preferences.Allows(notification.Type) {
+		log.Printf("%s notifications disabled for user %s, skipping email for %s", notification.Type.Category(), notification.UserID, notification.Type)
+		return
+	} else if preferences.EmailFrequency.DigestWindow() > 0 && notification.Type.Category() != models.PreferenceCategoryAccount {
+		// Account and security emails can't wait; everything else goes in the digest
+		if err := s.notificationRepo.MarkForDigest(notification.ID); err != nil {
+			log.Printf("Failed to hold notification %s for digest: %v", notification.ID, err)
+		}
+		return
 	}
 
-	// 2. Get user email from notification data
-	email, ok := notification.Data["email"].(string)
-	if !ok || email == "" {
-		log.Printf("No email found in notification data for user %s", notification.UserID)
+	// 2. Get user email from notification data, falling back to users-service
+	email, _ := notification.Data["email"].(string)
+	if email == "" {
+		var err error
+		if email, err = s.lookupEmail(notification.UserID); err != nil {
+			// Possibly transient - leave it for the retry worker like a failed send
+			s.notificationRepo.MarkAsEmailFailed(notification.ID, "user lookup failed: "+err.Error())
+			s.notificationRepo.IncrementRetryCount(notification.ID)
+			s.notificationRepo.MarkAsEmailPending(notification.ID)
+			return
+		}
+	}
+	if email == "" {
+		log.Printf("No email address for user %s", notification.UserID)
 		s.notificationRepo.MarkAsEmailFailed(notification.ID, "no email address")
 		return
 	}
@@ -92,12 +247,20 @@ func (s *notificationService) sendEmailNotification(notification *models.Notific
 	// 3. Map NotificationType to EmailType (Mapping is 1-to-1 as requested)
 	emailType := shared.EmailType(notification.Type)
 
-	// 4. Prepare Payload
+	// 4. Prepare Payload; templates also show the notification's title and message
+	data := make(map[string]interface{}, len(notification.Data)+2)
+	for key, value := range notification.Data {
+		data[key] = value
+	}
+	data["title"] = notification.Title
+	data["message"] = notification.Message
+	data["unsubscribe_url"] = s.unsubscribe.URL(notification.UserID, unsubscribeCategory(notification.Type))
+
 	payload := shared.EmailPayload{
 		Type:      emailType,
 		Recipient: email,
 		Subject:   notification.Title,
-		Data:      notification.Data,
+		Data:      data,
 	}
 
 
@@ -105,7 +268,13 @@ func (s *notificationService) sendEmailNotification(notification *models.Notific
 	if err := s.emailService.Send(payload); err != nil {
 		log.Printf("Failed to send email for notification %s: %v", notification.ID, err)
 		s.notificationRepo.MarkAsEmailFailed(notification.ID, err.Error())
+		if errors.Is(err, ErrPermanentEmailFailure) {
+			// The provider refused it for good; retrying would only be refused again
+			metrics.IncrementCounter("notification.email.permanent_failure")
+			return
+		}
 		s.notificationRepo.IncrementRetryCount(notification.ID)
+		s.notificationRepo.MarkAsEmailPending(notification.ID)
 		return
 	}
 
@@ -115,9 +284,29 @@ func (s *notificationService) sendEmailNotification(notification *models.Notific
 	}
 }
 
-// GetNotification retrieves a notification by ID
-func (s *notificationService) GetNotification(id string) (*models.Notification, error) {
-	return s.notificationRepo.GetByID(id)
+// lookupEmail resolves a user's email address through users-service. An unknown user, or
+// no users-service client, gives an empty address; an error means the lookup may succeed
+// later.
+func (s *notificationService) lookupEmail(userID string) (string, error) {
+	if s.userClient == nil {
+		return "", nil
+	}
+
+	user, err := s.userClient.GetUser(userID)
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		log.Printf("User %s not found while resolving email", userID)
+		return "", nil
+	case err != nil:
+		log.Printf("Failed to resolve email for user %s: %v", userID, err)
+		return "", err
+	}
+	return user.Email, nil
+}
+
+// GetNotification retrieves a notification owned by the user
+func (s *notificationService) GetNotification(id, userID uuid.UUID) (*models.Notification, error) {
+	return s.notificationRepo.GetByID(id, userID)
 }
 
 // GetUserNotifications retrieves notifications for a user with pagination
@@ -166,14 +355,35 @@ func (s *notificationService) ListNotifications(query dto.ListNotificationsQuery
 	}, nil
 }
 
-// MarkAsRead marks a notification as read
-func (s *notificationService) MarkAsRead(id string) error {
-	return s.notificationRepo.MarkAsRead(id)
+// MarkAsRead marks a notification owned by the user as read
+func (s *notificationService) MarkAsRead(id, userID uuid.UUID) error {
+	return s.notificationRepo.MarkAsRead(id, userID)
 }
 
-// DeleteNotification deletes a notification
-func (s *notificationService) DeleteNotification(id string) error {
-	return s.notificationRepo.Delete(id)
+// DeleteNotification deletes a notification owned by the user
+func (s *notificationService) DeleteNotification(id, userID uuid.UUID) error {
+	return s.notificationRepo.Delete(id, userID)
+}
+
+// MarkAllAsRead marks all of the user's unread notifications as read, optionally only
+// those of one type
+func (s *notificationService) MarkAllAsRead(userID uuid.UUID, notificationType string) (int64, error) {
+	return s.notificationRepo.MarkAllAsRead(userID, notificationType)
+}
+
+// DeleteNotifications deletes those of the given notifications the user owns
+func (s *notificationService) DeleteNotifications(userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	return s.notificationRepo.DeleteMany(userID, ids)
+}
+
+// Subscribe opens a stream of the user's notifications created by this instance
+func (s *notificationService) Subscribe(userID string) (<-chan *models.Notification, func(), error) {
+	return s.hub.subscribe(userID)
+}
+
+// CloseStreams ends all open notification streams
+func (s *notificationService) CloseStreams() {
+	s.hub.closeAll()
 }
 
 // GetUnreadCount gets the count of unread notifications
@@ -199,6 +409,25 @@ func (s *notificationService) UpdateUserPreferences(userID string, updates dto.U
 	return s.preferenceRepo.Update(userID, updates)
 }
 
+// Unsubscribe turns off the email an unsubscribe token names and returns its category.
+// A user without preferences gets the defaults first, so the change still sticks.
+func (s *notificationService) Unsubscribe(token string) (models.PreferenceCategory, error) {
+	userID, category, err := s.unsubscribe.Parse(token)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.GetUserPreferences(userID); err != nil {
+		return "", err
+	}
+	if err := s.preferenceRepo.Update(userID, *unsubscribeUpdate(category)); err != nil {
+		return "", err
+	}
+
+	metrics.IncrementCounter("notification.unsubscribed", "category:"+string(category))
+	return category, nil
+}
+
 // CreateDefaultPreferences creates default preferences for a user
 func (s *notificationService) CreateDefaultPreferences(userID string) error {
 	return s.preferenceRepo.CreateDefault(userID)
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gofund/notifications-service/internal/dto"
+	"github.com/gofund/notifications-service/internal/models"
+	shared "github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// storeNotification stores a payment notification for userID with data, as the event
+// handlers build them, ready to be sent
+func storeNotification(t *testing.T, notifications *fakeNotificationRepo, userID string, data map[string]interface{}) *models.Notification {
+	t.Helper()
+	notification := &models.Notification{
+		UserID:  userID,
+		Type:    models.NotificationTypePaymentVerified,
+		Title:   "Payment verified",
+		Message: "Your payment was verified",
+		Data:    data,
+	}
+	if err := notifications.Create(notification); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return notification
+}
+
+func TestSendEmailResolvesRecipient(t *testing.T) {
+	userID := uuid.NewString()
+
+	tests := []struct {
+		name      string
+		data      map[string]interface{}
+		users     *stubUserClient
+		wantTo    string
+		wantCalls int
+	}{
+		{
+			name:      "email in the event data",
+			data:      map[string]interface{}{"email": "event@example.com"},
+			users:     &stubUserClient{users: map[string]*UserInfo{userID: {ID: userID, Email: "users@example.com"}}},
+			wantTo:    "event@example.com",
+			wantCalls: 0,
+		},
+		{
+			name:      "empty email in the event data",
+			data:      map[string]interface{}{"email": ""},
+			users:     &stubUserClient{users: map[string]*UserInfo{userID: {ID: userID, Email: "users@example.com"}}},
+			wantTo:    "users@example.com",
+			wantCalls: 1,
+		},
+		{
+			name:      "no email in the event data",
+			data:      map[string]interface{}{},
+			users:     &stubUserClient{users: map[string]*UserInfo{userID: {ID: userID, Email: "users@example.com"}}},
+			wantTo:    "users@example.com",
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifications, preferences := newFakeNotificationRepo(), newFakePreferenceRepo()
+			preferences.Create(defaultPreferences(userID))
+			email := &recordingEmailService{}
+			s := newTestNotificationService(t, notifications, preferences, email, tt.users, EmailDispatcherConfig{Workers: 1, QueueSize: 1})
+
+			notification := storeNotification(t, notifications, userID, tt.data)
+			s.sendEmailNotification(notification)
+
+			sent := email.emails()
+			if len(sent) != 1 || sent[0].Recipient != tt.wantTo {
+				t.Fatalf("sent %+v, want one email to %s", sent, tt.wantTo)
+			}
+			if tt.users.calls != tt.wantCalls {
+				t.Errorf("users-service lookups = %d, want %d", tt.users.calls, tt.wantCalls)
+			}
+			if stored := notifications.get(t, notification.ID); !stored.EmailSent {
+				t.Error("notification not marked sent")
+			}
+		})
+	}
+}
+
+// TestSendEmailLookupFailureIsRetried fails the users-service lookup: the email is left
+// for the retry worker with its retry counter raised, as a failed send would be
+func TestSendEmailLookupFailureIsRetried(t *testing.T) {
+	notifications, preferences := newFakeNotificationRepo(), newFakePreferenceRepo()
+	userID := uuid.NewString()
+	preferences.Create(defaultPreferences(userID))
+	email := &recordingEmailService{}
+	users := &stubUserClient{err: errors.New("unexpected status 503")}
+	s := newTestNotificationService(t, notifications, preferences, email, users, EmailDispatcherConfig{Workers: 1, QueueSize: 1})
+
+	notification := storeNotification(t, notifications, userID, map[string]interface{}{})
+	s.sendEmailNotification(notification)
+	s.sendEmailNotification(notification)
+
+	if sent := email.emails(); len(sent) != 0 {
+		t.Fatalf("sent %+v without a recipient", sent)
+	}
+	stored := notifications.get(t, notification.ID)
+	if stored.RetryCount != 2 || !stored.EmailPending || stored.EmailSent {
+		t.Errorf("retry count %d, pending %v, sent %v; want 2 retries still pending", stored.RetryCount, stored.EmailPending, stored.EmailSent)
+	}
+	if stored.EmailFailedReason == nil || !strings.HasPrefix(*stored.EmailFailedReason, "user lookup failed") {
+		t.Errorf("failed reason = %v, want the lookup failure", stored.EmailFailedReason)
+	}
+
+	// Once users-service recovers the retry goes out
+	users.mu.Lock()
+	users.err = nil
+	users.users = map[string]*UserInfo{userID: {ID: userID, Email: "users@example.com"}}
+	users.mu.Unlock()
+	s.sendEmailNotification(notification)
+	if sent := email.emails(); len(sent) != 1 || sent[0].Recipient != "users@example.com" {
+		t.Errorf("sent %+v after users-service recovered, want one email to users@example.com", sent)
+	}
+}
+
+// TestSendEmailUnknownUserFails gives up on a user users-service does not know: there is
+// no address to retry with
+func TestSendEmailUnknownUserFails(t *testing.T) {
+	for name, users := range map[string]UserClient{
+		"unknown user":         &stubUserClient{users: map[string]*UserInfo{}},
+		"no users-service URL": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			notifications, preferences := newFakeNotificationRepo(), newFakePreferenceRepo()
+			userID := uuid.NewString()
+			preferences.Create(defaultPreferences(userID))
+			email := &recordingEmailService{}
+			s := newTestNotificationService(t, notifications, preferences, email, users, EmailDispatcherConfig{Workers: 1, QueueSize: 1})
+
+			notification := storeNotification(t, notifications, userID, map[string]interface{}{})
+			s.sendEmailNotification(notification)
+
+			if sent := email.emails(); len(sent) != 0 {
+				t.Fatalf("sent %+v without a recipient", sent)
+			}
+			stored := notifications.get(t, notification.ID)
+			if stored.RetryCount != 0 || stored.EmailPending {
+				t.Errorf("retry count %d, pending %v; want no retry", stored.RetryCount, stored.EmailPending)
+			}
+			if stored.EmailFailedReason == nil || *stored.EmailFailedReason != "no email address" {
+				t.Errorf("failed reason = %v, want no email address", stored.EmailFailedReason)
+			}
+		})
+	}
+}
+
+// TestDisabledCategorySuppressesNotifications turns off the payment category: the email
+// is never sent, and with in-app suppression the notification is not recorded either
+func TestDisabledCategorySuppressesNotifications(t *testing.T) {
+	for _, suppressInApp := range []bool{false, true} {
+		t.Run(fmt.Sprintf("suppress in-app %v", suppressInApp), func(t *testing.T) {
+			notifications, preferences := newFakeNotificationRepo(), newFakePreferenceRepo()
+			userID := uuid.NewString()
+			disabled := defaultPreferences(userID)
+			disabled.PaymentNotifications = false
+			preferences.Create(disabled)
+			email := &recordingEmailService{}
+			s := newTestNotificationService(t, notifications, preferences, email, nil, EmailDispatcherConfig{Workers: 1, QueueSize: 10})
+			s.suppressInApp = suppressInApp
+
+			create := func(notificationType models.NotificationType) *models.Notification {
+				t.Helper()
+				notification, err := s.CreateNotification(dto.CreateNotificationRequest{
+					UserID:  userID,
+					Type:    notificationType,
+					Title:   "Title",
+					Message: "Message",
+					Data:    map[string]interface{}{"email": "user@example.com"},
+				})
+				if err != nil {
+					t.Fatalf("CreateNotification(%s): %v", notificationType, err)
+				}
+				return notification
+			}
+			payment := create(models.NotificationTypePaymentVerified)
+			goal := create(models.NotificationTypeGoalFunded)
+			if err := s.Close(context.Background()); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			if suppressInApp != (payment == nil) {
+				t.Errorf("payment notification recorded = %v with in-app suppression %v", payment != nil, suppressInApp)
+			}
+			if goal == nil {
+				t.Fatal("goal notification, in an enabled category, was not recorded")
+			}
+			sent := email.emails()
+			if len(sent) != 1 || sent[0].Type != shared.EmailType(models.NotificationTypeGoalFunded) {
+				t.Errorf("sent %+v, want only the goal email", sent)
+			}
+		})
+	}
+}
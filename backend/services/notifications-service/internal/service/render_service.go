@@ -4,47 +4,194 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
-	"path/filepath"
+	"io/fs"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/gofund/notifications-service/internal/templates"
 	"github.com/gofund/shared/models"
 )
 
-// RenderService handles email template rendering
-type RenderService interface {
+// genericTemplate renders any email type without a template of its own
+const genericTemplate = "generic"
+
+// EmailTemplateRenderer renders notification emails
+type EmailTemplateRenderer interface {
 	Render(emailType models.EmailType, data map[string]interface{}) (string, error)
 }
 
-type renderService struct {
-	templateDir string
+type emailTemplateRenderer struct {
+	frontendURL string
+	templates   map[string]*template.Template
+	now         func() time.Time
 }
 
-// NewRenderService creates a new render service
-func NewRenderService(templateDir string) RenderService {
-	return &renderService{
-		templateDir: templateDir,
-	}
+// NewEmailTemplateRenderer parses the embedded email templates. Each content template is
+// paired with layout.html; action links point into the frontend at frontendURL.
+func NewEmailTemplateRenderer(frontendURL string) (EmailTemplateRenderer, error) {
+	return newEmailTemplateRenderer(templates.Emails, frontendURL)
 }
 
-// Render renders an email template with the given data
-func (s *renderService) Render(emailType models.EmailType, data map[string]interface{}) (string, error) {
-	// Add common data
-	if _, ok := data["Year"]; !ok {
-		data["Year"] = time.Now().Year()
+func newEmailTemplateRenderer(files fs.FS, frontendURL string) (*emailTemplateRenderer, error) {
+	names, err := fs.Glob(files, "emails/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list email templates: %w", err)
 	}
 
-	layoutPath := filepath.Join(s.templateDir, "layout.html")
-	templatePath := filepath.Join(s.templateDir, fmt.Sprintf("%s.html", emailType))
+	r := &emailTemplateRenderer{
+		frontendURL: strings.TrimRight(frontendURL, "/"),
+		templates:   make(map[string]*template.Template, len(names)),
+		now:         time.Now,
+	}
+	for _, name := range names {
+		base := strings.TrimSuffix(strings.TrimPrefix(name, "emails/"), ".html")
+		if base == "layout" {
+			continue
+		}
 
-	tmpl, err := template.ParseFiles(layoutPath, templatePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse templates: %w", err)
+		tmpl, err := template.ParseFS(files, "emails/layout.html", name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse email template %s: %w", base, err)
+		}
+		r.templates[base] = tmpl
+	}
+
+	if _, ok := r.templates[genericTemplate]; !ok {
+		return nil, fmt.Errorf("missing %s email template", genericTemplate)
+	}
+	return r, nil
+}
+
+// Render renders the email for the type, falling back to the generic template for types
+// that have none
+func (r *emailTemplateRenderer) Render(emailType models.EmailType, data map[string]interface{}) (string, error) {
+	tmpl, ok := r.templates[string(emailType)]
+	if !ok {
+		tmpl = r.templates[genericTemplate]
 	}
 
 	var buf bytes.Buffer
-	if err := tmpl.ExecuteTemplate(&buf, "layout.html", data); err != nil {
+	if err := tmpl.ExecuteTemplate(&buf, "layout.html", r.templateData(emailType, data)); err != nil {
 		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
 	return buf.String(), nil
 }
+
+// templateData turns notification data, whose keys are snake_case and whose amounts are
+// in kobo, into the fields the templates use
+func (r *emailTemplateRenderer) templateData(emailType models.EmailType, data map[string]interface{}) map[string]interface{} {
+	view := map[string]interface{}{
+		"Title":           stringValue(data, "title"),
+		"Message":         stringValue(data, "message"),
+		"Name":            firstString(data, "name", "first_name", "username"),
+		"GoalTitle":       stringValue(data, "goal_title"),
+		"ContributorName": firstString(data, "contributor_name"),
+		"Reference":       stringValue(data, "reference"),
+		"Token":           stringValue(data, "token"),
+		"ActionURL":       r.actionURL(emailType, data),
+		"UnsubscribeURL":  stringValue(data, "unsubscribe_url"),
+		"Year":            r.now().Year(),
+	}
+	if view["Name"] == "" {
+		view["Name"] = "there"
+	}
+	if view["ContributorName"] == "" {
+		view["ContributorName"] = "Anonymous"
+	}
+	if amount, ok := int64Value(data, "amount"); ok {
+		view["Amount"] = FormatAmount(amount, stringValue(data, "currency"))
+	} else {
+		view["Amount"] = ""
+	}
+	// Digests list one summary line per notification type
+	if lines, ok := data["lines"].([]string); ok {
+		view["Lines"] = lines
+	}
+	return view
+}
+
+// actionURL is where the email's button leads
+func (r *emailTemplateRenderer) actionURL(emailType models.EmailType, data map[string]interface{}) string {
+	token := stringValue(data, "token")
+	switch {
+	case emailType == models.EmailTypePasswordReset && token != "":
+		return r.frontendURL + "/reset-password?token=" + url.QueryEscape(token)
+	case emailType == models.EmailTypeEmailVerification && token != "":
+		return r.frontendURL + "/verify-email?token=" + url.QueryEscape(token)
+	}
+	if goalID := stringValue(data, "goal_id"); goalID != "" {
+		return r.frontendURL + "/dashboard/goals/" + url.PathEscape(goalID)
+	}
+	return r.frontendURL + "/dashboard"
+}
+
+// currencySymbols maps ISO currency codes to the symbol shown in emails
+var currencySymbols = map[string]string{
+	"NGN": "₦",
+	"USD": "$",
+	"GBP": "£",
+	"EUR": "€",
+}
+
+// FormatAmount formats an amount in the currency's smallest unit, e.g. 1234550 NGN as
+// ₦12,345.50. An empty currency means NGN.
+func FormatAmount(amount int64, currency string) string {
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		currency = "NGN"
+	}
+	symbol, ok := currencySymbols[currency]
+	if !ok {
+		symbol = currency + " "
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	whole := fmt.Sprintf("%d", amount/100)
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%s%s%s.%02d", sign, symbol, grouped.String(), amount%100)
+}
+
+// stringValue returns data[key] when it is a string
+func stringValue(data map[string]interface{}, key string) string {
+	value, _ := data[key].(string)
+	return value
+}
+
+// firstString returns the first non-empty string among the keys
+func firstString(data map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value := stringValue(data, key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// int64Value returns data[key] as an integer. Data read back from the database holds
+// JSON numbers as float64.
+func int64Value(data map[string]interface{}, key string) (int64, bool) {
+	switch value := data[key].(type) {
+	case int64:
+		return value, true
+	case int:
+		return int64(value), true
+	case float64:
+		return int64(value), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofund/notifications-service/internal/templates"
+	"github.com/gofund/shared/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/emails")
+
+// goldenEmails are the emails rendered against testdata/emails/<name>.golden.html: one
+// per template, plus a type without one that falls back to the generic template
+var goldenEmails = []struct {
+	name      string
+	emailType models.EmailType
+	data      map[string]interface{}
+}{
+	{
+		name:      "contribution_confirmed",
+		emailType: models.EmailTypeContributionConfirmed,
+		data: map[string]interface{}{
+			"first_name": "Ada", "goal_id": "9b1f0c1e-7a4e-4a55-8f2e-0c8f3c8b2a10", "goal_title": "Community borehole",
+			"contributor_name": "Tunde", "amount": float64(1234550), "currency": "NGN",
+		},
+	},
+	{
+		name:      "digest",
+		emailType: models.EmailTypeDigest,
+		data: map[string]interface{}{
+			"username": "ada", "title": "Your daily GoFund digest", "message": "Here is what happened today.",
+			"lines": []string{"3 new contributions", "1 proof submitted"},
+		},
+	},
+	{
+		name:      "email_verification",
+		emailType: models.EmailTypeEmailVerification,
+		data:      map[string]interface{}{"first_name": "Ada", "token": "verify+token/1"},
+	},
+	{
+		name:      "generic",
+		emailType: models.EmailType("generic"),
+		data:      map[string]interface{}{"title": "Something happened", "message": "Details inside."},
+	},
+	{
+		name:      "goal_funded",
+		emailType: models.EmailTypeGoalFunded,
+		data: map[string]interface{}{
+			"name": "Ada Obi", "goal_id": "9b1f0c1e-7a4e-4a55-8f2e-0c8f3c8b2a10", "goal_title": "Community borehole",
+			"amount": int64(100000000), "currency": "usd",
+		},
+	},
+	{
+		name:      "kyc_verified",
+		emailType: models.EmailTypeKYCVerified,
+		data:      map[string]interface{}{"first_name": "Ada", "unsubscribe_url": "https://gofund.test/unsubscribe?token=abc"},
+	},
+	{
+		name:      "password_reset",
+		emailType: models.EmailTypePasswordReset,
+		data:      map[string]interface{}{"first_name": "Ada", "token": "reset token&1"},
+	},
+	{
+		name:      "payment_verified",
+		emailType: models.EmailTypePaymentVerified,
+		data:      map[string]interface{}{"first_name": "Ada", "reference": "GOFUND-re4lyvq3s3", "amount": 507500, "currency": "NGN"},
+	},
+	{
+		name:      "user_signed_up",
+		emailType: models.EmailTypeUserSignedUp,
+		data:      map[string]interface{}{"username": "ada"},
+	},
+	{
+		name:      "unknown_type_uses_generic",
+		emailType: models.EmailType("goal_comment_liked"),
+		data: map[string]interface{}{
+			"title": "<b>New like</b>", "message": "Someone liked your comment", "amount": float64(-250), "currency": "GBP",
+		},
+	},
+}
+
+func newTestRenderer(t *testing.T) *emailTemplateRenderer {
+	t.Helper()
+	r, err := newEmailTemplateRenderer(templates.Emails, "https://gofund.test/")
+	if err != nil {
+		t.Fatalf("newEmailTemplateRenderer: %v", err)
+	}
+	r.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	return r
+}
+
+// TestRenderedEmailsMatchGolden renders each email and compares it with its golden file.
+// After an intended template change, regenerate them with go test -run Golden -update.
+func TestRenderedEmailsMatchGolden(t *testing.T) {
+	r := newTestRenderer(t)
+
+	for _, tt := range goldenEmails {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Render(tt.emailType, tt.data)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+
+			golden := filepath.Join("testdata", "emails", tt.name+".golden.html")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("%s rendered differently from %s:\n%s", tt.name, golden, got)
+			}
+		})
+	}
+}
+
+// TestEveryTemplateHasAGoldenFile keeps the golden list in step with the embedded templates
+func TestEveryTemplateHasAGoldenFile(t *testing.T) {
+	covered := make(map[string]bool, len(goldenEmails))
+	for _, tt := range goldenEmails {
+		covered[tt.name] = true
+	}
+	for name := range newTestRenderer(t).templates {
+		if !covered[name] {
+			t.Errorf("email template %s has no golden test", name)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{1234550, "NGN", "₦12,345.50"},
+		{1234550, "", "₦12,345.50"},
+		{100000000, "usd", "$1,000,000.00"},
+		{5, "GBP", "£0.05"},
+		{-250, "EUR", "-€2.50"},
+		{99900, "KES", "KES 999.00"},
+	}
+	for _, tt := range tests {
+		if got := FormatAmount(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatAmount(%d, %q) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/gofund/notifications-service/internal/models"
+)
+
+// ErrTooManyStreams is returned when a user already has the maximum number of open streams
+var ErrTooManyStreams = errors.New("too many notification streams")
+
+// ErrStreamsClosed is returned when the service is shutting down
+var ErrStreamsClosed = errors.New("notification streams closed")
+
+// streamBuffer is how many notifications a slow stream may lag behind before pushes to it
+// are dropped; the client still finds them through the list endpoint
+const streamBuffer = 16
+
+// streamHub fans newly created notifications out to the open streams of their user. It
+// is in-process only: a stream sees notifications created by the same instance.
+type streamHub struct {
+	maxPerUser int
+
+	mu      sync.Mutex
+	closed  bool
+	streams map[string]map[chan *models.Notification]struct{}
+}
+
+// newStreamHub creates a hub allowing at most maxPerUser streams per user (unlimited when
+// maxPerUser is not positive)
+func newStreamHub(maxPerUser int) *streamHub {
+	return &streamHub{
+		maxPerUser: maxPerUser,
+		streams:    make(map[string]map[chan *models.Notification]struct{}),
+	}
+}
+
+// subscribe opens a stream for the user. The returned function closes it and must be
+// called when the client disconnects.
+func (h *streamHub) subscribe(userID string) (<-chan *models.Notification, func(), error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, nil, ErrStreamsClosed
+	}
+
+	userStreams := h.streams[userID]
+	if h.maxPerUser > 0 && len(userStreams) >= h.maxPerUser {
+		return nil, nil, ErrTooManyStreams
+	}
+	if userStreams == nil {
+		userStreams = make(map[chan *models.Notification]struct{})
+		h.streams[userID] = userStreams
+	}
+
+	ch := make(chan *models.Notification, streamBuffer)
+	userStreams[ch] = struct{}{}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			if _, open := userStreams[ch]; !open {
+				// Already closed by closeAll
+				return
+			}
+			delete(userStreams, ch)
+			if len(userStreams) == 0 {
+				delete(h.streams, userID)
+			}
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe, nil
+}
+
+// publish pushes a notification to every open stream of its user without blocking
+func (h *streamHub) publish(notification *models.Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.streams[notification.UserID] {
+		select {
+		case ch <- notification:
+		default:
+			// Stream is not keeping up; drop rather than stall notification creation
+		}
+	}
+}
+
+// closeAll ends every open stream and refuses new ones, so streaming handlers return and
+// the server can shut down
+func (h *streamHub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for userID, userStreams := range h.streams {
+		for ch := range userStreams {
+			delete(userStreams, ch)
+			close(ch)
+		}
+		delete(h.streams, userID)
+	}
+}
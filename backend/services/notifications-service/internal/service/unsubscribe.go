@@ -0,0 +1,106 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/gofund/notifications-service/internal/models"
+)
+
+// UnsubscribeAll is the unsubscribe category that turns off all email
+const UnsubscribeAll models.PreferenceCategory = "all"
+
+// ErrInvalidUnsubscribeToken is returned for any unsubscribe token that does not check
+// out, whatever the reason, so a token reveals nothing about the user it names
+var ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe link")
+
+// UnsubscribeLinks signs and checks email unsubscribe tokens. A token is
+// "<payload>.<signature>": the payload the base64url user ID and preference category, the
+// signature an HMAC-SHA256 over them. Tokens need no storage and do not expire, so old
+// emails keep working.
+type UnsubscribeLinks struct {
+	secret  []byte
+	baseURL string
+}
+
+// NewUnsubscribeLinks creates an unsubscribe link signer for the endpoint at baseURL.
+// Without a secret no links are issued and no tokens are accepted.
+func NewUnsubscribeLinks(secret, baseURL string) *UnsubscribeLinks {
+	return &UnsubscribeLinks{secret: []byte(secret), baseURL: baseURL}
+}
+
+// URL returns the link that turns off a user's email in a category, or "" when links
+// are not configured
+func (l *UnsubscribeLinks) URL(userID string, category models.PreferenceCategory) string {
+	if l == nil || len(l.secret) == 0 || l.baseURL == "" || userID == "" {
+		return ""
+	}
+	payload := base64.RawURLEncoding.EncodeToString([]byte(userID + "\n" + string(category)))
+	return l.baseURL + "?token=" + url.QueryEscape(payload+"."+l.sign(payload))
+}
+
+// Parse returns the user and category a token unsubscribes
+func (l *UnsubscribeLinks) Parse(token string) (string, models.PreferenceCategory, error) {
+	if l == nil || len(l.secret) == 0 {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(l.sign(payload)), []byte(signature)) {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+	userID, category, ok := strings.Cut(string(decoded), "\n")
+	if !ok || userID == "" || unsubscribeUpdate(models.PreferenceCategory(category)) == nil {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+	return userID, models.PreferenceCategory(category), nil
+}
+
+// sign returns the hex signature of a token payload
+func (l *UnsubscribeLinks) sign(payload string) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// unsubscribeCategory is the category an email's unsubscribe link turns off: the
+// notification type's own, or all email for account messages, which have no switch
+func unsubscribeCategory(t models.NotificationType) models.PreferenceCategory {
+	if category := t.Category(); category != models.PreferenceCategoryAccount {
+		return category
+	}
+	return UnsubscribeAll
+}
+
+// unsubscribeUpdate is the preference update that unsubscribes from a category, or nil
+// for a category that cannot be unsubscribed from
+func unsubscribeUpdate(category models.PreferenceCategory) *models.UpdatePreferencesRequest {
+	off := false
+	var update models.UpdatePreferencesRequest
+	switch category {
+	case UnsubscribeAll:
+		update.EmailEnabled = &off
+	case models.PreferenceCategoryPayment:
+		update.PaymentNotifications = &off
+	case models.PreferenceCategoryContribution:
+		update.ContributionNotifications = &off
+	case models.PreferenceCategoryWithdrawal:
+		update.WithdrawalNotifications = &off
+	case models.PreferenceCategoryProof:
+		update.ProofNotifications = &off
+	case models.PreferenceCategoryGoal:
+		update.GoalNotifications = &off
+	default:
+		return nil
+	}
+	return &update
+}
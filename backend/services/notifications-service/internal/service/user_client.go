@@ -0,0 +1,111 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofund/shared/metrics"
+)
+
+// ErrUserNotFound is returned when users-service has no user with the requested ID
+var ErrUserNotFound = errors.New("user not found")
+
+// UserInfo is the part of a user notifications need to address them
+type UserInfo struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+}
+
+// UserClient looks up users in users-service
+type UserClient interface {
+	GetUser(userID string) (*UserInfo, error)
+}
+
+type cachedUser struct {
+	user      UserInfo
+	expiresAt time.Time
+}
+
+type userClient struct {
+	baseURL  string
+	client   *http.Client
+	cacheTTL time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]cachedUser
+}
+
+// NewUserClient creates a users-service client that caches users for cacheTTL. An empty
+// baseURL disables lookups.
+func NewUserClient(baseURL string, cacheTTL time.Duration) UserClient {
+	return &userClient{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		client:   &http.Client{Timeout: 3 * time.Second},
+		cacheTTL: cacheTTL,
+		cache:    make(map[string]cachedUser),
+	}
+}
+
+// GetUser returns a user from the cache, or from GET /internal/users/:id on users-service
+func (c *userClient) GetUser(userID string) (*UserInfo, error) {
+	now := time.Now()
+	c.mu.RLock()
+	cached, ok := c.cache[userID]
+	c.mu.RUnlock()
+	if ok && now.Before(cached.expiresAt) {
+		user := cached.user
+		return &user, nil
+	}
+
+	if c.baseURL == "" {
+		return nil, fmt.Errorf("users service URL not configured")
+	}
+
+	user, err := c.fetchUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[userID] = cachedUser{user: *user, expiresAt: now.Add(c.cacheTTL)}
+	c.mu.Unlock()
+
+	return user, nil
+}
+
+// fetchUser calls GET /internal/users/:id on users-service
+func (c *userClient) fetchUser(userID string) (*UserInfo, error) {
+	endpoint := fmt.Sprintf("%s/internal/users/%s", c.baseURL, url.PathEscape(userID))
+
+	start := time.Now()
+	resp, err := c.client.Get(endpoint)
+	metrics.RecordDuration("notifications.users_client.duration", start)
+	if err != nil {
+		metrics.IncrementCounter("notifications.users_client.error")
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusBadRequest:
+		return nil, ErrUserNotFound
+	default:
+		metrics.IncrementCounter("notifications.users_client.error")
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var user UserInfo
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &user, nil
+}
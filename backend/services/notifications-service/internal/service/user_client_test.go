@@ -0,0 +1,90 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newUsersServer fakes GET /internal/users/:id: "missing" is not found, "broken" fails and
+// any other ID is a user. It counts the requests it gets.
+func newUsersServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		id := strings.TrimPrefix(r.URL.Path, "/internal/users/")
+		switch id {
+		case "missing":
+			http.Error(w, `{"error":"user not found"}`, http.StatusNotFound)
+		case "broken":
+			http.Error(w, `{"error":"database unavailable"}`, http.StatusInternalServerError)
+		default:
+			json.NewEncoder(w).Encode(UserInfo{ID: id, Email: id + "@example.com", Username: id})
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestUserClientCachesUsers(t *testing.T) {
+	server, requests := newUsersServer(t)
+	client := NewUserClient(server.URL, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		user, err := client.GetUser("ada")
+		if err != nil {
+			t.Fatalf("GetUser: %v", err)
+		}
+		if user.Email != "ada@example.com" {
+			t.Errorf("email = %q, want ada@example.com", user.Email)
+		}
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("users-service got %d requests, want 1 with the rest from the cache", n)
+	}
+}
+
+func TestUserClientRefetchesExpiredUsers(t *testing.T) {
+	server, requests := newUsersServer(t)
+	client := NewUserClient(server.URL, time.Millisecond)
+
+	if _, err := client.GetUser("ada"); err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := client.GetUser("ada"); err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("users-service got %d requests, want 2 once the cached user expired", n)
+	}
+}
+
+func TestUserClientFailures(t *testing.T) {
+	server, requests := newUsersServer(t)
+	client := NewUserClient(server.URL, time.Minute)
+
+	if _, err := client.GetUser("missing"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("unknown user: err = %v, want %v", err, ErrUserNotFound)
+	}
+
+	// A server error may clear up, so it is neither "not found" nor cached
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetUser("broken"); err == nil || errors.Is(err, ErrUserNotFound) {
+			t.Errorf("server error: err = %v, want a retryable error", err)
+		}
+	}
+	if n := atomic.LoadInt32(requests); n != 3 {
+		t.Errorf("users-service got %d requests, want 3 with no failure cached", n)
+	}
+
+	if _, err := NewUserClient("", time.Minute).GetUser("ada"); err == nil {
+		t.Error("a client without a users-service URL returned a user")
+	}
+}
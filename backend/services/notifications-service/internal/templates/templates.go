@@ -0,0 +1,10 @@
+// Package templates embeds the email templates so the binary does not depend on its
+// working directory
+package templates
+
+import "embed"
+
+// Emails holds layout.html, generic.html and one content template per notification type
+//
+//go:embed emails/*.html
+var Emails embed.FS
@@ -0,0 +1,4 @@
+-- Track notifications whose email is waiting to be (re)sent by the retry worker
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS email_pending BOOLEAN DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_email_pending ON notifications(email_pending) WHERE email_pending = TRUE;
@@ -0,0 +1,8 @@
+-- How often a user wants notification emails: 'instant', 'hourly' or 'daily'
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS email_frequency VARCHAR(10) NOT NULL DEFAULT 'instant';
+
+-- Notifications whose email is held for the user's next digest, and when they were digested
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS email_digest_pending BOOLEAN DEFAULT FALSE;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS digested_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_email_digest_pending ON notifications(user_id, created_at) WHERE email_digest_pending = TRUE;
@@ -7,10 +7,12 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofund/payments-service/internal/config"
-	"github.com/gofund/payments-service/internal/controller"
 	"github.com/gofund/payments-service/internal/middleware"
 	"github.com/gofund/payments-service/internal/repository"
+	"github.com/gofund/payments-service/internal/router"
 	"github.com/gofund/payments-service/internal/service"
+	"github.com/gofund/shared/buildinfo"
+	"github.com/gofund/shared/health"
 	"github.com/gofund/shared/messaging"
 	"github.com/gofund/shared/metrics"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -18,6 +20,13 @@ import (
 	gintrace "gopkg.in/DataDog/dd-trace-go.v1/contrib/gin-gonic/gin"
 )
 
+// @title Payments Service API
+// @version 1.0
+// @description Payment initialization and verification, payment history, bank lookups and Paystack webhooks.
+// @description Protected routes are called through the gateway, which verifies the bearer token and forwards the caller's identity.
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	// Load configuration
 	cfg, err := config.LoadConfig()
@@ -25,6 +34,8 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	buildinfo.LogStartup(cfg.ServiceName, cfg.DatadogVersion)
+
 	// Initialize Datadog tracing and metrics
 	if err := metrics.InitDatadog(cfg.ServiceName, cfg.DatadogEnv, cfg.DatadogVersion); err != nil {
 		log.Printf("Warning: Failed to initialize Datadog: %v", err)
@@ -56,6 +67,9 @@ func main() {
 	paymentRepo := repository.NewPaymentRepository(db)
 	webhookRepo := repository.NewWebhookRepository(db)
 	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	goalStateRepo := repository.NewGoalStateRepository(db)
+	transferRepo := repository.NewTransferRepository(db)
+	methodRepo := repository.NewPaymentMethodRepository(db)
 
 	// Ensure indexes
 	if err := paymentRepo.EnsureIndexes(context.Background()); err != nil {
@@ -67,6 +81,15 @@ func main() {
 	if err := idempotencyRepo.EnsureIndexes(context.Background()); err != nil {
 		log.Printf("Warning: Failed to create idempotency indexes: %v", err)
 	}
+	if err := goalStateRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create goal state indexes: %v", err)
+	}
+	if err := transferRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create transfer indexes: %v", err)
+	}
+	if err := methodRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("Warning: Failed to create payment method indexes: %v", err)
+	}
 
 	// Initialize RabbitMQ connection
 	rabbitConn, err := messaging.NewRabbitMQConnection(cfg.RabbitMQURL)
@@ -83,42 +106,90 @@ func main() {
 	log.Printf("Connected to RabbitMQ successfully")
 
 	// Initialize Paystack client
-	paystackClient := service.NewPaystackClient(cfg.PaystackSecretKey, cfg.PaystackBaseURL)
+	paystackClient := service.NewPaystackClient(cfg.PaystackSecretKey, cfg.PaystackBaseURL, service.PaystackRetryPolicy{
+		MaxAttempts:      cfg.PaystackMaxAttempts,
+		BaseDelay:        time.Duration(cfg.PaystackRetryBaseDelayMs) * time.Millisecond,
+		MaxDelay:         time.Duration(cfg.PaystackRetryMaxDelayMs) * time.Millisecond,
+		BreakerThreshold: cfg.PaystackBreakerThreshold,
+		BreakerCooldown:  time.Duration(cfg.PaystackBreakerCooldownSeconds) * time.Second,
+	})
+	goalsClient := service.NewGoalsClient(cfg.GoalsServiceURL)
 
 	// Initialize services
 	paymentService := service.NewPaymentService(
 		paymentRepo,
 		idempotencyRepo,
+		goalStateRepo,
+		methodRepo,
 		paystackClient,
+		goalsClient,
 		eventPublisher,
+		time.Duration(cfg.BankListCacheTTLMinutes)*time.Minute,
+		cfg.PlatformFees,
 	)
 
+	// Pre-warm the bank list without holding up startup
+	go paymentService.WarmBankCache(context.Background())
+
+	transferService := service.NewTransferService(paystackClient)
+	refundDisbursementService := service.NewRefundDisbursementService(paystackClient, transferService)
+	payoutService := service.NewPayoutService(transferRepo, transferService, refundDisbursementService, eventPublisher)
+
+	consumer, err := messaging.NewRabbitMQConsumer(rabbitConn, cfg.RabbitMQExchange, cfg.RabbitMQQueue)
+	if err != nil {
+		log.Printf("Warning: Failed to create RabbitMQ consumer: %v", err)
+	} else {
+		// Stop accepting payments for goals that closed on reaching their target
+		if err := consumer.ConsumeContext("GoalClosedEarly", paymentService.HandleGoalClosedEarly); err != nil {
+			log.Printf("Warning: Failed to consume GoalClosedEarly events: %v", err)
+		}
+		// Pay out withdrawals requested in goals-service
+		if err := consumer.ConsumeContext("WithdrawalRequested", payoutService.HandleWithdrawalRequested); err != nil {
+			log.Printf("Warning: Failed to consume WithdrawalRequested events: %v", err)
+		}
+		// Pay out refund disbursements
+		if err := consumer.ConsumeContext("RefundInitiated", payoutService.HandleRefundInitiated); err != nil {
+			log.Printf("Warning: Failed to consume RefundInitiated events: %v", err)
+		}
+	}
+
 	webhookService := service.NewWebhookService(
 		webhookRepo,
 		paymentRepo,
+		methodRepo,
 		eventPublisher,
+		payoutService,
+		time.Duration(cfg.WebhookReplayWindowMinutes)*time.Minute,
 	)
 
-	// Initialize controllers
-	paymentController := controller.NewPaymentController(paymentService)
-	webhookController := controller.NewWebhookController(webhookService)
-
 	// Initialize router
 	r := gin.Default()
 
+	// Tag every request with an ID that logs and published events carry
+	r.Use(middleware.RequestID())
+
 	// Add Datadog APM middleware
 	r.Use(gintrace.Middleware(cfg.ServiceName))
 
-	// Health check endpoint
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "healthy",
-			"service": cfg.ServiceName,
-		})
-	})
+	// Drop identity headers that did not come from the gateway
+	if cfg.IdentityHeaderSecret == "" {
+		log.Printf("Warning: IDENTITY_HEADER_SECRET not set; X-User-* headers cannot be verified")
+	}
+	r.Use(middleware.IdentityGuard(cfg.IdentityHeaderSecret))
+
+	// Health checks: live only says the process is up, ready probes dependencies
+	r.GET("/health/live", gin.WrapF(health.LiveHandler(cfg.ServiceName)))
+	r.GET("/health/ready", gin.WrapF(health.ReadyHandler(health.DefaultTimeout,
+		health.Mongo("mongodb", db),
+		health.RabbitMQ("rabbitmq", rabbitConn),
+	)))
+
+	// Build info
+	r.GET("/version", gin.WrapF(buildinfo.Handler(cfg.ServiceName, cfg.DatadogVersion)))
 
 	// Setup routes
-	setupRoutes(r, paymentController, webhookController, cfg)
+	router.SetupRoutes(r, paymentService, webhookService, cfg)
+	log.Printf("Routes configured successfully")
 
 	// Start server
 	log.Printf("Payments Service starting on port %s", cfg.ServicePort)
@@ -126,31 +197,3 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
-
-// setupRoutes configures all Payments Service routes
-func setupRoutes(
-	r *gin.Engine,
-	paymentController *controller.PaymentController,
-	webhookController *controller.WebhookController,
-	cfg *config.Config,
-) {
-	// API v1 routes
-	v1 := r.Group("/api/v1/payments")
-	{
-		// Payment routes
-		v1.POST("/initialize", paymentController.InitializePayment)
-		v1.GET("/verify/:reference", paymentController.VerifyPayment)
-		v1.GET("/:paymentId/status", paymentController.GetPaymentStatus)
-		v1.GET("/banks", paymentController.ListBanks)
-		v1.GET("/resolve-account", paymentController.ResolveAccount)
-
-		// Webhook route (with signature verification middleware)
-		webhookSecret := cfg.PaystackSecretKey
-		if cfg.PaystackWebhookSecret != "" {
-			webhookSecret = cfg.PaystackWebhookSecret
-		}
-		v1.POST("/webhook", middleware.WebhookAuthMiddleware(webhookSecret), webhookController.HandleWebhook)
-	}
-
-	log.Printf("Routes configured successfully")
-}
@@ -1,12 +1,17 @@
 package controller
 
 import (
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofund/payments-service/internal/dto"
 	"github.com/gofund/payments-service/internal/service"
+	"github.com/gofund/shared/identity"
+	"github.com/gofund/shared/models"
 )
 
 // PaymentController handles payment-related HTTP requests
@@ -22,169 +27,506 @@ func NewPaymentController(paymentService *service.PaymentService) *PaymentContro
 }
 
 // InitializePayment handles POST /api/v1/payments/initialize
+//
+// @Summary Initialize a payment
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.InitializePaymentRequest true "Payment to initialize"
+// @Param Idempotency-Key header string false "Replays the original response for a repeated request; required with payment_method_id"
+// @Success 200 {object} dto.SuccessResponse{data=dto.InitializePaymentResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 429 {object} httperr.Response
+// @Failure 500 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/payments/initialize [post]
 func (pc *PaymentController) InitializePayment(c *gin.Context) {
 	var req dto.InitializePaymentRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Printf("[INFO] Invalid payment initialization request", map[string]interface{}{
+		log.Printf("[INFO] Invalid payment initialization request: %v", map[string]interface{}{
 			"error": err.Error(),
 		})
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid request body",
-			"error":   err.Error(),
-		})
+		respondFailure(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	req.IdempotencyKey = c.GetHeader("Idempotency-Key")
+
+	// Only the signed-in owner of a saved payment method may charge it
+	if req.PaymentMethodID != "" && c.GetHeader(identity.HeaderUserID) != req.UserID.String() {
+		respondFailure(c, http.StatusForbidden, "Saved payment methods can only be used by their owner", nil)
 		return
 	}
 
 	// Initialize payment
 	resp, err := pc.paymentService.InitializePayment(c.Request.Context(), &req)
 	if err != nil {
-		log.Printf("[INFO] Failed to initialize payment", map[string]interface{}{
+		log.Printf("[INFO] Failed to initialize payment: %v", map[string]interface{}{
 			"error":   err.Error(),
 			"user_id": req.UserID.String(),
 			"goal_id": req.GoalID.String(),
 			"amount":  req.Amount,
 		})
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to initialize payment",
-			"error":   err.Error(),
-		})
+		if errors.Is(err, service.ErrGoalTargetReached) {
+			respondFailure(c, http.StatusConflict, "Goal has reached its target and is closed", err)
+			return
+		}
+		if errors.Is(err, service.ErrPaymentInProgress) {
+			respondFailure(c, http.StatusConflict, "An identical payment is already being initialized", err)
+			return
+		}
+		if errors.Is(err, service.ErrIdempotencyKeyReused) {
+			respondFailure(c, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different payment", err)
+			return
+		}
+		if errors.Is(err, service.ErrUnsupportedCurrency) || errors.Is(err, service.ErrCurrencyMismatch) {
+			respondFailure(c, http.StatusBadRequest, "Currency is not accepted for this goal", err)
+			return
+		}
+		if errors.Is(err, service.ErrGoalNotFound) {
+			respondFailure(c, http.StatusNotFound, "Goal not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrIdempotencyKeyRequired) {
+			respondFailure(c, http.StatusBadRequest, "Idempotency-Key is required to pay with a saved payment method", err)
+			return
+		}
+		if errors.Is(err, service.ErrPaymentMethodNotFound) {
+			respondFailure(c, http.StatusNotFound, "Payment method not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrPaymentMethodNotReusable) {
+			respondFailure(c, http.StatusBadRequest, "This payment method cannot be charged again; pay through checkout instead", err)
+			return
+		}
+		if errors.Is(err, service.ErrPaystackUnavailable) {
+			respondFailure(c, http.StatusServiceUnavailable, "Payment provider is temporarily unavailable, please retry shortly", err)
+			return
+		}
+		respondFailure(c, http.StatusInternalServerError, "Failed to initialize payment", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   resp,
-	})
+	respondSuccess(c, resp)
 }
 
 // VerifyPayment handles GET /api/v1/payments/verify/:reference
+//
+// @Summary Verify a payment with the provider
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param reference path string true "Provider reference"
+// @Success 200 {object} dto.SuccessResponse{data=dto.VerifyPaymentResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/payments/verify/{reference} [get]
 func (pc *PaymentController) VerifyPayment(c *gin.Context) {
 	reference := c.Param("reference")
 	if reference == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Reference is required",
-		})
+		respondFailure(c, http.StatusBadRequest, "Reference is required", nil)
 		return
 	}
 
 	// Verify payment
 	resp, err := pc.paymentService.VerifyPayment(c.Request.Context(), reference)
 	if err != nil {
-		log.Printf("[INFO] Failed to verify payment", map[string]interface{}{
+		log.Printf("[INFO] Failed to verify payment: %v", map[string]interface{}{
 			"error":     err.Error(),
 			"reference": reference,
 		})
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to verify payment",
-			"error":   err.Error(),
-		})
+		if errors.Is(err, service.ErrPaystackUnavailable) {
+			respondFailure(c, http.StatusServiceUnavailable, "Payment provider is temporarily unavailable, please retry shortly", err)
+			return
+		}
+		if errors.Is(err, service.ErrChargeMismatch) {
+			respondFailure(c, http.StatusConflict, "The charge does not match the payment", err)
+			return
+		}
+		respondFailure(c, http.StatusInternalServerError, "Failed to verify payment", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   resp,
-	})
+	respondSuccess(c, resp)
 }
 
 // GetPaymentStatus handles GET /api/v1/payments/:paymentId/status
+//
+// @Summary Get a payment's status
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param paymentId path string true "Payment ID"
+// @Success 200 {object} dto.SuccessResponse{data=dto.PaymentStatusResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/payments/{paymentId}/status [get]
 func (pc *PaymentController) GetPaymentStatus(c *gin.Context) {
 	paymentID := c.Param("paymentId")
 	if paymentID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Payment ID is required",
-		})
+		respondFailure(c, http.StatusBadRequest, "Payment ID is required", nil)
 		return
 	}
 
 	// Get payment status
 	resp, err := pc.paymentService.GetPaymentStatus(c.Request.Context(), paymentID)
 	if err != nil {
-		log.Printf("[INFO] Failed to get payment status", map[string]interface{}{
+		log.Printf("[INFO] Failed to get payment status: %v", map[string]interface{}{
 			"error":      err.Error(),
 			"payment_id": paymentID,
 		})
-		c.JSON(http.StatusNotFound, gin.H{
-			"status":  "error",
-			"message": "Payment not found",
-			"error":   err.Error(),
-		})
+		respondFailure(c, http.StatusNotFound, "Payment not found", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   resp,
-	})
+	respondSuccess(c, resp)
 }
 
-// ListBanks handles GET /api/v1/payments/banks
+// ListMyPayments handles GET /api/v1/payments/my
+//
+// @Summary List the caller's payments
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status" Enums(INITIATED, PENDING, VERIFIED, FAILED)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} dto.SuccessResponse{data=dto.PaymentListResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/payments/my [get]
+func (pc *PaymentController) ListMyPayments(c *gin.Context) {
+	userID := c.GetHeader(identity.HeaderUserID)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	resp, err := pc.paymentService.ListUserPayments(c.Request.Context(), userID, c.Query("status"), page, pageSize)
+	if err != nil {
+		pc.respondListError(c, err)
+		return
+	}
+
+	respondSuccess(c, resp)
+}
+
+// ListPaymentMethods handles GET /api/v1/payments/methods
+//
+// @Summary List the caller's saved payment methods
+// @Tags payment-methods
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.SuccessResponse{data=[]models.SavedPaymentMethod}
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/payments/methods [get]
+func (pc *PaymentController) ListPaymentMethods(c *gin.Context) {
+	userID := c.GetHeader(identity.HeaderUserID)
+
+	methods, err := pc.paymentService.ListPaymentMethods(c.Request.Context(), userID)
+	if err != nil {
+		respondFailure(c, http.StatusInternalServerError, "Failed to list payment methods", err)
+		return
+	}
+
+	respondSuccess(c, methods)
+}
+
+// DeletePaymentMethod handles DELETE /api/v1/payments/methods/:methodId
+//
+// @Summary Delete a saved payment method
+// @Tags payment-methods
+// @Produce json
+// @Security BearerAuth
+// @Param methodId path string true "Payment method ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/payments/methods/{methodId} [delete]
+func (pc *PaymentController) DeletePaymentMethod(c *gin.Context) {
+	userID := c.GetHeader(identity.HeaderUserID)
+
+	if err := pc.paymentService.DeletePaymentMethod(c.Request.Context(), userID, c.Param("methodId")); err != nil {
+		if errors.Is(err, service.ErrPaymentMethodNotFound) {
+			respondFailure(c, http.StatusNotFound, "Payment method not found", err)
+			return
+		}
+		respondFailure(c, http.StatusInternalServerError, "Failed to delete payment method", err)
+		return
+	}
+
+	respondSuccess(c, nil)
+}
+
+// ListGoalPayments handles GET /api/v1/payments/goal/:goalId
+//
+// @Summary List a goal's payments (owner only)
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param goalId path string true "Goal ID"
+// @Param status query string false "Filter by status" Enums(INITIATED, PENDING, VERIFIED, FAILED)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} dto.SuccessResponse{data=dto.PaymentListResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/payments/goal/{goalId} [get]
+func (pc *PaymentController) ListGoalPayments(c *gin.Context) {
+	userID := c.GetHeader(identity.HeaderUserID)
+	goalID := c.Param("goalId")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	resp, err := pc.paymentService.ListGoalPayments(c.Request.Context(), userID, goalID, c.Query("status"), page, pageSize)
+	if err != nil {
+		log.Printf("[INFO] Failed to list goal payments (goal_id: %s, user_id: %s): %v", goalID, userID, err)
+		pc.respondListError(c, err)
+		return
+	}
+
+	respondSuccess(c, resp)
+}
+
+// respondListError maps a payment listing error to its HTTP response
+func (pc *PaymentController) respondListError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPaymentStatus):
+		respondFailure(c, http.StatusBadRequest, "status must be one of INITIATED, PENDING, VERIFIED or FAILED", err)
+	case errors.Is(err, service.ErrGoalNotFound):
+		respondFailure(c, http.StatusNotFound, "Goal not found", err)
+	case errors.Is(err, service.ErrNotGoalOwner):
+		respondFailure(c, http.StatusForbidden, "Only the goal owner can list its payments", err)
+	default:
+		respondFailure(c, http.StatusInternalServerError, "Failed to list payments", err)
+	}
+}
+
+// GetInternalPayment handles GET /internal/payments/:paymentId, used by other services
+// (e.g. goals-service receipts) to look up a payment's Paystack reference
+//
+// @Summary Get a payment (service to service)
+// @Tags internal
+// @Produce json
+// @Param paymentId path string true "Payment ID"
+// @Success 200 {object} dto.PaymentStatusResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /internal/payments/{paymentId} [get]
+func (pc *PaymentController) GetInternalPayment(c *gin.Context) {
+	paymentID := c.Param("paymentId")
+
+	resp, err := pc.paymentService.GetPaymentStatus(c.Request.Context(), paymentID)
+	if err != nil {
+		respondFailure(c, http.StatusNotFound, "Payment not found", nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ChargeAuthorization handles POST /internal/payments/charge-authorization, used by
+// goals-service to charge a contributor's saved card for a recurring contribution
+//
+// @Summary Charge a saved card (service to service)
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param request body dto.ChargeAuthorizationRequest true "Charge to make"
+// @Success 200 {object} dto.SuccessResponse{data=dto.ChargeAuthorizationResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /internal/payments/charge-authorization [post]
+func (pc *PaymentController) ChargeAuthorization(c *gin.Context) {
+	var req dto.ChargeAuthorizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFailure(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	resp, err := pc.paymentService.ChargeAuthorization(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrGoalTargetReached):
+			respondFailure(c, http.StatusConflict, "Goal has reached its target and is closed", err)
+		case errors.Is(err, service.ErrPaymentInProgress):
+			respondFailure(c, http.StatusConflict, "This charge is already being made", err)
+		case errors.Is(err, service.ErrIdempotencyKeyReused):
+			respondFailure(c, http.StatusUnprocessableEntity, "idempotency_key was already used for a different charge", err)
+		case errors.Is(err, service.ErrUnsupportedCurrency):
+			respondFailure(c, http.StatusBadRequest, "Currency is not supported", err)
+		case errors.Is(err, service.ErrPaystackUnavailable):
+			respondFailure(c, http.StatusServiceUnavailable, "Payment provider is temporarily unavailable, please retry shortly", err)
+		default:
+			respondFailure(c, http.StatusInternalServerError, "Failed to charge authorization", err)
+		}
+		return
+	}
+
+	respondSuccess(c, resp)
+}
+
+// ListBanks handles GET /api/v1/payments/banks. Admins may pass refresh=true to bypass
+// the cache and refetch the list from Paystack.
+//
+// @Summary List banks
+// @Tags banks
+// @Produce json
+// @Security BearerAuth
+// @Param country query string false "Country" default(nigeria)
+// @Param refresh query bool false "Refetch from the provider (admins only)"
+// @Success 200 {object} dto.SuccessResponse{data=[]dto.Bank}
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/payments/banks [get]
 func (pc *PaymentController) ListBanks(c *gin.Context) {
 	country := c.DefaultQuery("country", "nigeria")
 
+	refresh := c.Query("refresh") == "true"
+	if refresh && !isAdmin(c) {
+		respondFailure(c, http.StatusForbidden, "Only admins can refresh the bank list", nil)
+		return
+	}
+
 	// Get bank list
-	banks, err := pc.paymentService.ListBanks(c.Request.Context(), country)
+	banks, err := pc.paymentService.ListBanks(c.Request.Context(), country, refresh)
 	if err != nil {
-		log.Printf("[INFO] Failed to list banks", map[string]interface{}{
+		log.Printf("[INFO] Failed to list banks: %v", map[string]interface{}{
 			"error":   err.Error(),
 			"country": country,
 		})
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to list banks",
-			"error":   err.Error(),
-		})
+		if errors.Is(err, service.ErrPaystackUnavailable) {
+			respondFailure(c, http.StatusServiceUnavailable, "Payment provider is temporarily unavailable, please retry shortly", err)
+			return
+		}
+		respondFailure(c, http.StatusInternalServerError, "Failed to list banks", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   banks,
-	})
+	respondSuccess(c, banks)
 }
 
-// ResolveAccount handles GET /api/v1/payments/resolve-account
+// ResolveAccount handles GET /api/v1/payments/resolve-account, and GET
+// /internal/payments/resolve-account for goals-service
+//
+// @Summary Resolve a bank account's name
+// @Tags banks
+// @Produce json
+// @Security BearerAuth
+// @Param account_number query string true "Account number"
+// @Param bank_code query string false "Bank code; required unless bank_name is given"
+// @Param bank_name query string false "Bank name, looked up when bank_code is omitted"
+// @Success 200 {object} dto.SuccessResponse{data=dto.ResolveAccountResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/payments/resolve-account [get]
 func (pc *PaymentController) ResolveAccount(c *gin.Context) {
 	accountNumber := c.Query("account_number")
 	bankCode := c.Query("bank_code")
+	bankName := c.Query("bank_name")
 
-	if accountNumber == "" || bankCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "account_number and bank_code are required",
-		})
+	if accountNumber == "" || (bankCode == "" && bankName == "") {
+		respondFailure(c, http.StatusBadRequest, "account_number and bank_code (or bank_name) are required", nil)
 		return
 	}
 
 	req := &dto.ResolveAccountRequest{
 		AccountNumber: accountNumber,
 		BankCode:      bankCode,
+		BankName:      bankName,
 	}
 
 	// Resolve account
 	resp, err := pc.paymentService.ResolveAccount(c.Request.Context(), req)
 	if err != nil {
-		log.Printf("[INFO] Failed to resolve account", map[string]interface{}{
+		log.Printf("[INFO] Failed to resolve account: %v", map[string]interface{}{
 			"error":          err.Error(),
 			"account_number": accountNumber,
 			"bank_code":      bankCode,
 		})
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status":  "error",
-			"message": "Failed to resolve account",
-			"error":   err.Error(),
-		})
+		if errors.Is(err, service.ErrPaystackUnavailable) {
+			respondFailure(c, http.StatusServiceUnavailable, "Payment provider is temporarily unavailable, please retry shortly", err)
+			return
+		}
+		if errors.Is(err, service.ErrUnknownBank) {
+			respondFailure(c, http.StatusBadRequest, "Unknown bank", err)
+			return
+		}
+		respondFailure(c, http.StatusInternalServerError, "Failed to resolve account", err)
+		return
+	}
+
+	respondSuccess(c, resp)
+}
+
+// CompleteMockPayment handles POST /api/v1/payments/mock/complete (non-production only)
+//
+// @Summary Complete a payment through the mock provider (non-production only)
+// @Tags testing
+// @Accept json
+// @Produce json
+// @Param request body dto.CompleteMockPaymentRequest true "Payment to complete"
+// @Success 200 {object} dto.SuccessResponse{data=dto.VerifyPaymentResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/payments/mock/complete [post]
+func (pc *PaymentController) CompleteMockPayment(c *gin.Context) {
+	var req dto.CompleteMockPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFailure(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	resp, err := pc.paymentService.CompleteMockPayment(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrGoalTargetReached) {
+			respondFailure(c, http.StatusConflict, "Goal has reached its target and is closed", err)
+			return
+		}
+		respondFailure(c, http.StatusInternalServerError, "Failed to complete mock payment", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   resp,
-	})
+	respondSuccess(c, resp)
+}
+
+// respondSuccess wraps data in the success envelope
+func respondSuccess(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusOK, dto.SuccessResponse{Status: "success", Data: data})
+}
+
+// respondFailure writes the error envelope; err may be nil when there is no underlying error
+func respondFailure(c *gin.Context, status int, message string, err error) {
+	body := dto.ErrorResponse{Status: "error", Message: message}
+	if err != nil {
+		body.Error = err.Error()
+	}
+	c.JSON(status, body)
+}
+
+// isAdmin reports whether the X-User-Roles header (set by Nginx after auth verification and
+// vetted by IdentityGuard) includes the admin role
+func isAdmin(c *gin.Context) bool {
+	for _, role := range strings.Split(c.GetHeader(identity.HeaderUserRoles), ",") {
+		if strings.TrimSpace(role) == string(models.UserRoleAdmin) {
+			return true
+		}
+	}
+	return false
 }
@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"log"
 	"net/http"
@@ -23,52 +25,56 @@ func NewWebhookController(webhookService *service.WebhookService) *WebhookContro
 }
 
 // HandleWebhook handles POST /api/v1/payments/webhook
+//
+// @Summary Receive a Paystack webhook
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param x-paystack-signature header string true "HMAC-SHA512 of the body"
+// @Param payload body dto.WebhookPayload true "Webhook event"
+// @Success 200 {object} dto.WebhookResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/v1/payments/webhook [post]
 func (wc *WebhookController) HandleWebhook(c *gin.Context) {
 	// Get webhook body from context (set by middleware)
 	bodyInterface, exists := c.Get("webhook_body")
 	if !exists {
-		log.Printf("[INFO] Webhook body not found in context", nil)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid webhook request",
-		})
+		log.Printf("[INFO] Webhook body not found in context")
+		respondFailure(c, http.StatusBadRequest, "Invalid webhook request", nil)
 		return
 	}
 
 	body, ok := bodyInterface.([]byte)
 	if !ok {
-		log.Printf("[INFO] Invalid webhook body type", nil)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid webhook request",
-		})
+		log.Printf("[INFO] Invalid webhook body type")
+		respondFailure(c, http.StatusBadRequest, "Invalid webhook request", nil)
 		return
 	}
 
-	// Get signature from header
+	// Get signature from header, and hash the exact bytes it was computed over
 	signature := c.GetHeader("x-paystack-signature")
+	sum := sha256.Sum256(body)
+	bodyHash := hex.EncodeToString(sum[:])
 
 	// Parse webhook payload
 	var payload dto.WebhookPayload
 	if err := json.Unmarshal(body, &payload); err != nil {
-		log.Printf("[INFO] Failed to parse webhook payload", map[string]interface{}{
+		log.Printf("[INFO] Failed to parse webhook payload: %v", map[string]interface{}{
 			"error": err.Error(),
 		})
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  "error",
-			"message": "Invalid webhook payload",
-		})
+		respondFailure(c, http.StatusBadRequest, "Invalid webhook payload", nil)
 		return
 	}
 
-	log.Printf("[INFO] Received webhook", map[string]interface{}{
+	log.Printf("[INFO] Received webhook: %v", map[string]interface{}{
 		"event": payload.Event,
 	})
 
 	// Process webhook asynchronously (return 200 immediately)
 	go func() {
-		if err := wc.webhookService.ProcessWebhook(c.Request.Context(), &payload, signature); err != nil {
-			log.Printf("[INFO] Failed to process webhook", map[string]interface{}{
+		if err := wc.webhookService.ProcessWebhook(c.Request.Context(), &payload, signature, bodyHash); err != nil {
+			log.Printf("[INFO] Failed to process webhook: %v", map[string]interface{}{
 				"error": err.Error(),
 				"event": payload.Event,
 			})
@@ -76,7 +82,5 @@ func (wc *WebhookController) HandleWebhook(c *gin.Context) {
 	}()
 
 	// Return 200 OK immediately to Paystack
-	c.JSON(http.StatusOK, gin.H{
-		"status": "received",
-	})
+	c.JSON(http.StatusOK, dto.WebhookResponse{Status: "received"})
 }
@@ -20,3 +20,22 @@ type DisbursementResponse struct {
 	Reference    string
 	Status       string
 }
+
+// TransferRequest represents a payout to a bank account through the provider's Transfer API
+type TransferRequest struct {
+	Reference     string
+	Amount        int64
+	Currency      string
+	BankCode      string
+	AccountNumber string
+	AccountName   string
+	Reason        string
+}
+
+// TransferResponse represents a transfer the provider accepted
+type TransferResponse struct {
+	RecipientCode string
+	TransferCode  string
+	Reference     string
+	Status        string // Provider status, e.g. "pending" or "success"
+}
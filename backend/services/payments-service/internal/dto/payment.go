@@ -1,49 +1,104 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
 
 // InitializePaymentRequest represents a request to initialize a payment
 type InitializePaymentRequest struct {
-	UserID      uuid.UUID              `json:"user_id" binding:"required"`
-	GoalID      uuid.UUID              `json:"goal_id" binding:"required"`
-	Amount      int64                  `json:"amount" binding:"required,min=100"` // Minimum 1 NGN (100 kobo)
-	Currency    string                 `json:"currency" binding:"required"`
-	Email       string                 `json:"email" binding:"required,email"`
-	CallbackURL string                 `json:"callback_url"`
-	Metadata    map[string]interface{} `json:"metadata"`
+	UserID         uuid.UUID              `json:"user_id" binding:"required"`
+	GoalID         uuid.UUID              `json:"goal_id" binding:"required"`
+	ContributionID *uuid.UUID             `json:"contribution_id"`                   // Intent from goals-service, amount already validated
+	Amount         int64                  `json:"amount" binding:"required,min=100"` // Minimum 1 NGN (100 kobo); the platform fee is added on add-on goals
+	Currency       string                 `json:"currency" binding:"required"`
+	Email          string                 `json:"email" binding:"required,email"`
+	CallbackURL    string                 `json:"callback_url"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	IdempotencyKey string                 `json:"-"` // From the Idempotency-Key header
+	// PaymentMethodID charges one of the payer's saved payment methods directly instead
+	// of creating a checkout; it requires an Idempotency-Key
+	PaymentMethodID string `json:"payment_method_id"`
+}
+
+// ChargeAuthorizationRequest charges a contributor's saved card for a contribution intent,
+// e.g. a recurring contribution falling due. Sent by goals-service; a repeated
+// IdempotencyKey returns the original charge instead of charging again. Amount is charged
+// as is: goals-service has already added any platform fee.
+type ChargeAuthorizationRequest struct {
+	UserID            uuid.UUID  `json:"user_id" binding:"required"`
+	GoalID            uuid.UUID  `json:"goal_id" binding:"required"`
+	ContributionID    *uuid.UUID `json:"contribution_id"`
+	Amount            int64      `json:"amount" binding:"required,min=100"`
+	Currency          string     `json:"currency" binding:"required"`
+	Email             string     `json:"email" binding:"required,email"`
+	AuthorizationCode string     `json:"authorization_code" binding:"required"`
+	IdempotencyKey    string     `json:"idempotency_key" binding:"required"`
+}
+
+// ChargeAuthorizationResponse is the outcome of charging a saved card: VERIFIED, FAILED,
+// or PENDING until Paystack's webhook settles it
+type ChargeAuthorizationResponse struct {
+	PaymentID       string `json:"payment_id"`
+	Reference       string `json:"reference"`
+	Status          string `json:"status"`
+	GatewayResponse string `json:"gateway_response,omitempty"`
+}
+
+// CompleteMockPaymentRequest records a payment as if the provider had completed it.
+// Only accepted by the non-production mock provider hook.
+type CompleteMockPaymentRequest struct {
+	UserID         uuid.UUID  `json:"user_id" binding:"required"`
+	GoalID         uuid.UUID  `json:"goal_id" binding:"required"`
+	ContributionID *uuid.UUID `json:"contribution_id"`
+	Amount         int64      `json:"amount" binding:"required,min=100"`
+	Currency       string     `json:"currency"`
 }
 
 // InitializePaymentResponse represents the response from payment initialization
 type InitializePaymentResponse struct {
 	PaymentID        string `json:"payment_id"`
+	Amount           int64  `json:"amount"`     // what checkout charges, the fee included
+	FeeAmount        int64  `json:"fee_amount"` // platform fee added on top of the requested amount
 	AuthorizationURL string `json:"authorization_url"`
 	AccessCode       string `json:"access_code"`
 	Reference        string `json:"reference"`
+	Status           string `json:"status,omitempty"` // set instead of AuthorizationURL when a saved payment method was charged
 }
 
 // PaymentStatusResponse represents the payment status
 type PaymentStatusResponse struct {
-	PaymentID string                 `json:"payment_id"`
-	Reference string                 `json:"reference"`
-	Status    string                 `json:"status"`
-	Amount    int64                  `json:"amount"`
-	Currency  string                 `json:"currency"`
-	PaidAt    *string                `json:"paid_at,omitempty"`
-	CreatedAt string                 `json:"created_at"`
-	UpdatedAt string                 `json:"updated_at"`
-	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	PaymentID   string                     `json:"payment_id"`
+	Reference   string                     `json:"reference"`
+	Status      string                     `json:"status"`
+	Amount      int64                      `json:"amount"`
+	Currency    string                     `json:"currency"`
+	PaidAt      *string                    `json:"paid_at,omitempty"`
+	CreatedAt   string                     `json:"created_at"`
+	UpdatedAt   string                     `json:"updated_at"`
+	Metadata    map[string]interface{}     `json:"metadata,omitempty"`
+	AppMetadata *models.PaymentAppMetadata `json:"app_metadata,omitempty"`
+}
+
+// PaymentListResponse is a page of payments, newest first
+type PaymentListResponse struct {
+	Items    []*PaymentStatusResponse `json:"items"`
+	Total    int64                    `json:"total"`
+	Page     int                      `json:"page"`
+	PageSize int                      `json:"page_size"`
 }
 
 // VerifyPaymentResponse represents the response from payment verification
 type VerifyPaymentResponse struct {
-	PaymentID string                 `json:"payment_id"`
-	Reference string                 `json:"reference"`
-	Status    string                 `json:"status"`
-	Amount    int64                  `json:"amount"`
-	Currency  string                 `json:"currency"`
-	PaidAt    *string                `json:"paid_at,omitempty"`
-	Channel   string                 `json:"channel,omitempty"`
-	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	PaymentID   string                     `json:"payment_id"`
+	Reference   string                     `json:"reference"`
+	Status      string                     `json:"status"`
+	Amount      int64                      `json:"amount"`
+	Currency    string                     `json:"currency"`
+	PaidAt      *string                    `json:"paid_at,omitempty"`
+	Channel     string                     `json:"channel,omitempty"`
+	Metadata    map[string]interface{}     `json:"metadata,omitempty"`
+	AppMetadata *models.PaymentAppMetadata `json:"app_metadata,omitempty"`
 }
 
 // PaystackInitializeRequest represents Paystack transaction initialization request
@@ -73,27 +128,51 @@ type PaystackVerifyResponse struct {
 	Status  bool   `json:"status"`
 	Message string `json:"message"`
 	Data    struct {
-		ID              int64                  `json:"id"`
-		Domain          string                 `json:"domain"`
-		Status          string                 `json:"status"`
-		Reference       string                 `json:"reference"`
-		Amount          int64                  `json:"amount"`
-		Message         string                 `json:"message"`
-		GatewayResponse string                 `json:"gateway_response"`
-		PaidAt          string                 `json:"paid_at"`
-		CreatedAt       string                 `json:"created_at"`
-		Channel         string                 `json:"channel"`
-		Currency        string                 `json:"currency"`
-		IPAddress       string                 `json:"ip_address"`
-		Metadata        map[string]interface{} `json:"metadata"`
+		ID              int64       `json:"id"`
+		Domain          string      `json:"domain"`
+		Status          string      `json:"status"`
+		Reference       string      `json:"reference"`
+		Amount          int64       `json:"amount"`
+		Message         string      `json:"message"`
+		GatewayResponse string      `json:"gateway_response"`
+		PaidAt          string      `json:"paid_at"`
+		CreatedAt       string      `json:"created_at"`
+		Channel         string      `json:"channel"`
+		Currency        string      `json:"currency"`
+		IPAddress       string      `json:"ip_address"`
+		Metadata        interface{} `json:"metadata"` // An object, or a string when sent as one
 		Customer        struct {
 			ID           int64  `json:"id"`
 			Email        string `json:"email"`
 			CustomerCode string `json:"customer_code"`
 		} `json:"customer"`
+		Authorization PaystackAuthorization `json:"authorization"`
 	} `json:"data"`
 }
 
+// PaystackAuthorization is the card a transaction was paid with. A reusable authorization
+// can be charged again without the customer, see PaystackChargeAuthorizationRequest.
+type PaystackAuthorization struct {
+	AuthorizationCode string `json:"authorization_code"`
+	CardType          string `json:"card_type"`
+	Last4             string `json:"last4"`
+	ExpMonth          string `json:"exp_month"`
+	ExpYear           string `json:"exp_year"`
+	Bank              string `json:"bank"`
+	Reusable          bool   `json:"reusable"`
+}
+
+// PaystackChargeAuthorizationRequest charges a saved authorization. Paystack answers with
+// the same transaction shape as a verification.
+type PaystackChargeAuthorizationRequest struct {
+	Email             string                 `json:"email"`
+	Amount            int64                  `json:"amount"`
+	Currency          string                 `json:"currency,omitempty"`
+	AuthorizationCode string                 `json:"authorization_code"`
+	Reference         string                 `json:"reference"`
+	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+}
+
 // PaystackBankListResponse represents Paystack bank list response
 type PaystackBankListResponse struct {
 	Status  bool   `json:"status"`
@@ -124,10 +203,12 @@ type PaystackResolveAccountResponse struct {
 	} `json:"data"`
 }
 
-// ResolveAccountRequest represents account resolution request
+// ResolveAccountRequest represents account resolution request. BankName is looked up
+// when BankCode is empty.
 type ResolveAccountRequest struct {
 	AccountNumber string `json:"account_number" binding:"required"`
-	BankCode      string `json:"bank_code" binding:"required"`
+	BankCode      string `json:"bank_code"`
+	BankName      string `json:"bank_name"`
 }
 
 // ResolveAccountResponse represents account resolution response
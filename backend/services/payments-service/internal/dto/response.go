@@ -0,0 +1,15 @@
+package dto
+
+// SuccessResponse is the envelope of every successful public payments response
+type SuccessResponse struct {
+	Status string      `json:"status" example:"success"`
+	Data   interface{} `json:"data"`
+}
+
+// ErrorResponse is the body of every failed payments response. Error carries the
+// underlying error, when there is one, for debugging; clients should show Message.
+type ErrorResponse struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/identity"
+	"github.com/google/uuid"
+)
+
+// AuthMiddleware ensures the X-User-ID header (set by Nginx after auth verification and
+// vetted by IdentityGuard) is present and valid
+func AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetHeader(identity.HeaderUserID)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Unauthorized: Missing X-User-ID header",
+			})
+			c.Abort()
+			return
+		}
+
+		if _, err := uuid.Parse(userID); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Unauthorized: Invalid X-User-ID header",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
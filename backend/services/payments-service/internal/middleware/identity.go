@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/identity"
+)
+
+// IdentityGuard drops X-User-* headers that were not signed by the gateway and
+// strips X-Internal-* headers, so callers that bypass Nginx cannot inject them
+func IdentityGuard(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity.Guard(c.Request.Header, secret)
+		c.Next()
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/identity"
+	"github.com/google/uuid"
+)
+
+const testIdentitySecret = "test-identity-secret"
+
+// newBypassTestRouter mounts the middleware chains of three representative payments
+// routes. Each handler answers with the user it was served for.
+func newBypassTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(IdentityGuard(testIdentitySecret))
+
+	handler := func(c *gin.Context) {
+		for name := range c.Request.Header {
+			if strings.HasPrefix(name, "X-Internal-") {
+				c.String(http.StatusInternalServerError, "internal header %s reached the handler", name)
+				return
+			}
+		}
+		c.String(http.StatusOK, c.GetHeader(identity.HeaderUserID))
+	}
+	v1 := r.Group("/api/v1/payments")
+	v1.GET("/my", AuthMiddleware(), handler)
+	v1.GET("/goal/:goalId", AuthMiddleware(), handler)
+	v1.DELETE("/methods/:methodId", AuthMiddleware(), handler)
+	return r
+}
+
+// gatewayHeaders returns the identity headers Nginx forwards for a verified caller
+func gatewayHeaders(userID, roles string) map[string]string {
+	return map[string]string{
+		identity.HeaderUserID:        userID,
+		identity.HeaderUserRoles:     roles,
+		identity.HeaderEmailVerified: "true",
+		identity.HeaderSignature:     identity.Sign(testIdentitySecret, userID, roles, "true"),
+	}
+}
+
+func TestGatewayBypass(t *testing.T) {
+	userID := uuid.NewString()
+	routes := []struct {
+		method, path string
+		roles        string // roles a legitimate caller of the route has
+	}{
+		{http.MethodGet, "/api/v1/payments/my", "user"},
+		{http.MethodGet, "/api/v1/payments/goal/" + uuid.NewString(), "user"},
+		{http.MethodDelete, "/api/v1/payments/methods/" + uuid.NewString(), "user"},
+	}
+
+	forgedSignature := gatewayHeaders(userID, "user,admin")
+	forgedSignature[identity.HeaderSignature] = "0123456789abcdef"
+	escalatedRoles := gatewayHeaders(userID, "user")
+	escalatedRoles[identity.HeaderUserRoles] = "user,admin"
+	unverifiedEmail := gatewayHeaders(userID, "user,admin")
+	unverifiedEmail[identity.HeaderSignature] = identity.Sign(testIdentitySecret, userID, "user,admin", "false")
+
+	for _, route := range routes {
+		tests := []struct {
+			name    string
+			headers map[string]string
+			want    int
+		}{
+			{name: "signed by the gateway", headers: gatewayHeaders(userID, route.roles), want: http.StatusOK},
+			{name: "signed with an injected internal header", headers: withHeader(gatewayHeaders(userID, route.roles), "X-Internal-Service", "payments"), want: http.StatusOK},
+			{name: "forged signature", headers: forgedSignature, want: http.StatusUnauthorized},
+			{name: "roles added after signing", headers: escalatedRoles, want: http.StatusUnauthorized},
+			{name: "email verification added after signing", headers: unverifiedEmail, want: http.StatusUnauthorized},
+			{name: "unsigned", headers: map[string]string{identity.HeaderUserID: userID, identity.HeaderUserRoles: "user,admin", identity.HeaderEmailVerified: "true"}, want: http.StatusUnauthorized},
+			{name: "anonymous", headers: map[string]string{}, want: http.StatusUnauthorized},
+		}
+
+		for _, tt := range tests {
+			t.Run(route.method+" "+route.path+"/"+tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(route.method, route.path, nil)
+				for name, value := range tt.headers {
+					req.Header.Set(name, value)
+				}
+				w := httptest.NewRecorder()
+				newBypassTestRouter().ServeHTTP(w, req)
+
+				if w.Code != tt.want {
+					t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+				}
+				if tt.want == http.StatusOK && w.Body.String() != userID {
+					t.Errorf("served for %q, want %s", w.Body.String(), userID)
+				}
+			})
+		}
+	}
+}
+
+func withHeader(headers map[string]string, name, value string) map[string]string {
+	headers[name] = value
+	return headers
+}
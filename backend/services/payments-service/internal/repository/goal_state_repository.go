@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GoalStateRepository tracks goals that no longer accept new payments
+type GoalStateRepository struct {
+	collection *mongo.Collection
+}
+
+// NewGoalStateRepository creates a new goal state repository
+func NewGoalStateRepository(db *mongo.Database) *GoalStateRepository {
+	return &GoalStateRepository{
+		collection: db.Collection("closed_goals"),
+	}
+}
+
+// MarkGoalClosed records that a goal stopped accepting payments
+func (r *GoalStateRepository) MarkGoalClosed(ctx context.Context, goalID, reason string) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"goalId": goalID},
+		bson.M{"$setOnInsert": bson.M{"goalId": goalID, "reason": reason, "closedAt": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark goal closed: %w", err)
+	}
+	return nil
+}
+
+// IsGoalClosed reports whether a goal stopped accepting payments
+func (r *GoalStateRepository) IsGoalClosed(ctx context.Context, goalID string) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"goalId": goalID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to check goal state: %w", err)
+	}
+	return count > 0, nil
+}
+
+// EnsureIndexes creates necessary indexes for the closed_goals collection
+func (r *GoalStateRepository) EnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "goalId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	return nil
+}
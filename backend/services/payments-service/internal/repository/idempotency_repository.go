@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,6 +12,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrIdempotencyKeyExists is returned when an idempotency key has already been claimed
+var ErrIdempotencyKeyExists = errors.New("idempotency key already exists")
+
 // IdempotencyRepository handles idempotency key operations
 type IdempotencyRepository struct {
 	collection *mongo.Collection
@@ -38,7 +42,9 @@ func (r *IdempotencyRepository) CheckIdempotencyKey(ctx context.Context, key str
 	return true, idempotency.PaymentID, nil
 }
 
-// SaveIdempotencyKey saves an idempotency key
+// SaveIdempotencyKey claims an idempotency key for a payment. The unique index makes the
+// insert the claim: of two concurrent requests with the same key only one succeeds, and
+// the other gets ErrIdempotencyKeyExists.
 func (r *IdempotencyRepository) SaveIdempotencyKey(ctx context.Context, key, paymentID string) error {
 	idempotency := &models.IdempotencyKey{
 		Key:       key,
@@ -49,6 +55,9 @@ func (r *IdempotencyRepository) SaveIdempotencyKey(ctx context.Context, key, pay
 
 	_, err := r.collection.InsertOne(ctx, idempotency)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrIdempotencyKeyExists
+		}
 		return fmt.Errorf("failed to save idempotency key: %w", err)
 	}
 
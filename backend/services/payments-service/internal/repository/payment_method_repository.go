@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofund/shared/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrPaymentMethodNotFound is returned when a user has no saved payment method with the ID
+var ErrPaymentMethodNotFound = errors.New("payment method not found")
+
+// PaymentMethodRepository handles users' saved payment methods
+type PaymentMethodRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPaymentMethodRepository creates a new payment method repository
+func NewPaymentMethodRepository(db *mongo.Database) *PaymentMethodRepository {
+	return &PaymentMethodRepository{
+		collection: db.Collection("payment_methods"),
+	}
+}
+
+// SavePaymentMethod records a payment method, refreshing the existing one when the user
+// has paid with the same card before
+func (r *PaymentMethodRepository) SavePaymentMethod(ctx context.Context, method *models.SavedPaymentMethod) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"userId": method.UserID, "fingerprint": method.Fingerprint},
+		bson.M{
+			"$set": bson.M{
+				"authorizationCode": method.AuthorizationCode,
+				"email":             method.Email,
+				"channel":           method.Channel,
+				"cardType":          method.CardType,
+				"last4":             method.Last4,
+				"expMonth":          method.ExpMonth,
+				"expYear":           method.ExpYear,
+				"bank":              method.Bank,
+				"isReusable":        method.IsReusable,
+				"lastUsedAt":        now,
+			},
+			"$setOnInsert": bson.M{"createdAt": now},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save payment method: %w", err)
+	}
+	return nil
+}
+
+// ListPaymentMethods returns a user's saved payment methods, most recently used first
+func (r *PaymentMethodRepository) ListPaymentMethods(ctx context.Context, userID string) ([]models.SavedPaymentMethod, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID},
+		options.Find().SetSort(bson.D{{Key: "lastUsedAt", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment methods: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	methods := []models.SavedPaymentMethod{}
+	if err := cursor.All(ctx, &methods); err != nil {
+		return nil, fmt.Errorf("failed to decode payment methods: %w", err)
+	}
+	return methods, nil
+}
+
+// GetPaymentMethod retrieves one of a user's saved payment methods
+func (r *PaymentMethodRepository) GetPaymentMethod(ctx context.Context, userID, methodID string) (*models.SavedPaymentMethod, error) {
+	id, err := primitive.ObjectIDFromHex(methodID)
+	if err != nil {
+		return nil, ErrPaymentMethodNotFound
+	}
+
+	var method models.SavedPaymentMethod
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id, "userId": userID}).Decode(&method); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrPaymentMethodNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment method: %w", err)
+	}
+	return &method, nil
+}
+
+// DeletePaymentMethod removes one of a user's saved payment methods
+func (r *PaymentMethodRepository) DeletePaymentMethod(ctx context.Context, userID, methodID string) error {
+	id, err := primitive.ObjectIDFromHex(methodID)
+	if err != nil {
+		return ErrPaymentMethodNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "userId": userID})
+	if err != nil {
+		return fmt.Errorf("failed to delete payment method: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrPaymentMethodNotFound
+	}
+	return nil
+}
+
+// EnsureIndexes creates necessary indexes for the payment_methods collection
+func (r *PaymentMethodRepository) EnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "fingerprint", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "lastUsedAt", Value: -1}},
+		},
+	}
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	return nil
+}
@@ -48,6 +48,7 @@ func (r *PaymentRepository) GetPaymentByID(ctx context.Context, paymentID string
 		}
 		return nil, fmt.Errorf("failed to get payment: %w", err)
 	}
+	payment.MigrateLegacyPaystackData()
 	return &payment, nil
 }
 
@@ -61,21 +62,37 @@ func (r *PaymentRepository) GetPaymentByReference(ctx context.Context, reference
 		}
 		return nil, fmt.Errorf("failed to get payment: %w", err)
 	}
+	payment.MigrateLegacyPaystackData()
 	return &payment, nil
 }
 
-// UpdatePayment updates an existing payment
+// UpdatePayment updates an existing payment. Paystack sections are set individually and
+// webhook events are left to AppendWebhookEvent, so concurrent verify and webhook updates
+// never overwrite each other's data.
 func (r *PaymentRepository) UpdatePayment(ctx context.Context, payment *models.Payment) error {
 	payment.UpdatedAt = time.Now()
 
+	set := bson.M{
+		"status":            payment.Status,
+		"paystackReference": payment.PaystackReference,
+		"updatedAt":         payment.UpdatedAt,
+	}
+	if record := payment.Paystack; record != nil {
+		if record.Initialization != nil {
+			set["paystack.initialization"] = record.Initialization
+		}
+		if record.Verification != nil {
+			set["paystack.verification"] = record.Verification
+		}
+		if record.AppMetadata != nil {
+			set["paystack.appMetadata"] = record.AppMetadata
+		}
+	}
+
 	filter := bson.M{"paymentId": payment.PaymentID}
 	update := bson.M{
-		"$set": bson.M{
-			"status":             payment.Status,
-			"paystackReference":  payment.PaystackReference,
-			"paystackData":       payment.PaystackData,
-			"updatedAt":          payment.UpdatedAt,
-		},
+		"$set":   set,
+		"$unset": bson.M{"paystackData": ""},
 	}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
@@ -90,6 +107,27 @@ func (r *PaymentRepository) UpdatePayment(ctx context.Context, payment *models.P
 	return nil
 }
 
+// AppendWebhookEvent records a webhook delivery on a payment without touching anything
+// already stored
+func (r *PaymentRepository) AppendWebhookEvent(ctx context.Context, paymentID string, event models.PaystackWebhookEvent) error {
+	filter := bson.M{"paymentId": paymentID}
+	update := bson.M{
+		"$push": bson.M{"paystack.webhookEvents": event},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to append webhook event: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("payment not found")
+	}
+
+	return nil
+}
+
 // UpdatePaymentStatus updates only the payment status
 func (r *PaymentRepository) UpdatePaymentStatus(ctx context.Context, paymentID string, status models.PaymentStatus) error {
 	filter := bson.M{"paymentId": paymentID}
@@ -112,14 +150,15 @@ func (r *PaymentRepository) UpdatePaymentStatus(ctx context.Context, paymentID s
 	return nil
 }
 
-// ListPaymentsByUser retrieves all payments for a user
-func (r *PaymentRepository) ListPaymentsByUser(ctx context.Context, userID string, limit, offset int64) ([]*models.Payment, error) {
+// ListPaymentsByUser retrieves a user's payments, newest first. An empty status lists
+// payments in every status.
+func (r *PaymentRepository) ListPaymentsByUser(ctx context.Context, userID string, status models.PaymentStatus, limit, offset int64) ([]*models.Payment, error) {
 	opts := options.Find().
 		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
 		SetLimit(limit).
 		SetSkip(offset)
 
-	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	cursor, err := r.collection.Find(ctx, paymentFilter("userId", userID, status), opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list payments: %w", err)
 	}
@@ -129,18 +168,22 @@ func (r *PaymentRepository) ListPaymentsByUser(ctx context.Context, userID strin
 	if err := cursor.All(ctx, &payments); err != nil {
 		return nil, fmt.Errorf("failed to decode payments: %w", err)
 	}
+	for _, payment := range payments {
+		payment.MigrateLegacyPaystackData()
+	}
 
 	return payments, nil
 }
 
-// ListPaymentsByGoal retrieves all payments for a goal
-func (r *PaymentRepository) ListPaymentsByGoal(ctx context.Context, goalID string, limit, offset int64) ([]*models.Payment, error) {
+// ListPaymentsByGoal retrieves a goal's payments, newest first. An empty status lists
+// payments in every status.
+func (r *PaymentRepository) ListPaymentsByGoal(ctx context.Context, goalID string, status models.PaymentStatus, limit, offset int64) ([]*models.Payment, error) {
 	opts := options.Find().
 		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
 		SetLimit(limit).
 		SetSkip(offset)
 
-	cursor, err := r.collection.Find(ctx, bson.M{"goalId": goalID}, opts)
+	cursor, err := r.collection.Find(ctx, paymentFilter("goalId", goalID, status), opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list payments: %w", err)
 	}
@@ -150,10 +193,40 @@ func (r *PaymentRepository) ListPaymentsByGoal(ctx context.Context, goalID strin
 	if err := cursor.All(ctx, &payments); err != nil {
 		return nil, fmt.Errorf("failed to decode payments: %w", err)
 	}
+	for _, payment := range payments {
+		payment.MigrateLegacyPaystackData()
+	}
 
 	return payments, nil
 }
 
+// CountPaymentsByUser counts a user's payments, optionally in one status
+func (r *PaymentRepository) CountPaymentsByUser(ctx context.Context, userID string, status models.PaymentStatus) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, paymentFilter("userId", userID, status))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count payments: %w", err)
+	}
+	return count, nil
+}
+
+// CountPaymentsByGoal counts a goal's payments, optionally in one status
+func (r *PaymentRepository) CountPaymentsByGoal(ctx context.Context, goalID string, status models.PaymentStatus) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, paymentFilter("goalId", goalID, status))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count payments: %w", err)
+	}
+	return count, nil
+}
+
+// paymentFilter matches payments whose field equals value, narrowed to status when set
+func paymentFilter(field, value string, status models.PaymentStatus) bson.M {
+	filter := bson.M{field: value}
+	if status != "" {
+		filter["status"] = status
+	}
+	return filter
+}
+
 // CountPaymentsByStatus counts payments by status
 func (r *PaymentRepository) CountPaymentsByStatus(ctx context.Context, status models.PaymentStatus) (int64, error) {
 	count, err := r.collection.CountDocuments(ctx, bson.M{"status": status})
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofund/shared/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrTransferExists is returned when a transfer with the same reference was already recorded
+var ErrTransferExists = errors.New("transfer already exists")
+
+// TransferRepository handles outgoing transfer database operations
+type TransferRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTransferRepository creates a new transfer repository
+func NewTransferRepository(db *mongo.Database) *TransferRepository {
+	return &TransferRepository{
+		collection: db.Collection("transfers"),
+	}
+}
+
+// CreateTransfer records a transfer before it is sent to the provider. A second transfer
+// with the same reference is refused with ErrTransferExists.
+func (r *TransferRepository) CreateTransfer(ctx context.Context, transfer *models.Transfer) error {
+	transfer.CreatedAt = time.Now()
+	transfer.UpdatedAt = transfer.CreatedAt
+
+	result, err := r.collection.InsertOne(ctx, transfer)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrTransferExists
+		}
+		return fmt.Errorf("failed to create transfer: %w", err)
+	}
+
+	transfer.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetTransferByReference retrieves a transfer by its reference
+func (r *TransferRepository) GetTransferByReference(ctx context.Context, reference string) (*models.Transfer, error) {
+	var transfer models.Transfer
+	err := r.collection.FindOne(ctx, bson.M{"reference": reference}).Decode(&transfer)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil // Not found, not an error
+		}
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+	return &transfer, nil
+}
+
+// UpdateTransfer saves the provider codes, status and failure reason of a transfer
+func (r *TransferRepository) UpdateTransfer(ctx context.Context, transfer *models.Transfer) error {
+	transfer.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"bankCode":      transfer.BankCode,
+			"recipientCode": transfer.RecipientCode,
+			"transferCode":  transfer.TransferCode,
+			"status":        transfer.Status,
+			"failureReason": transfer.FailureReason,
+			"updatedAt":     transfer.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"reference": transfer.Reference}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update transfer: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("transfer not found")
+	}
+	return nil
+}
+
+// EnsureIndexes creates necessary indexes for the transfers collection
+func (r *TransferRepository) EnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "reference", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "sourceId", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}},
+		},
+	}
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	return nil
+}
@@ -123,6 +123,9 @@ func (r *WebhookRepository) EnsureIndexes(ctx context.Context) error {
 		{
 			Keys: bson.D{{Key: "processed", Value: 1}},
 		},
+		{
+			Keys: bson.D{{Key: "bodyHash", Value: 1}},
+		},
 		{
 			Keys: bson.D{{Key: "receivedAt", Value: -1}},
 		},
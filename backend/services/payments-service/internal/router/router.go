@@ -0,0 +1,68 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/payments-service/internal/config"
+	"github.com/gofund/payments-service/internal/controller"
+	"github.com/gofund/payments-service/internal/middleware"
+	"github.com/gofund/payments-service/internal/service"
+	"github.com/gofund/shared/apidocs"
+	"github.com/gofund/shared/ratelimit"
+	"github.com/gofund/shared/testhooks"
+)
+
+// SetupRoutes configures all Payments Service routes
+func SetupRoutes(
+	r *gin.Engine,
+	paymentService *service.PaymentService,
+	webhookService *service.WebhookService,
+	cfg *config.Config,
+) {
+	// Initialize controllers
+	paymentController := controller.NewPaymentController(paymentService)
+	webhookController := controller.NewWebhookController(webhookService)
+
+	// Internal routes (called by other services, not exposed through Nginx)
+	internal := r.Group("/internal")
+	{
+		internal.GET("/payments/resolve-account", paymentController.ResolveAccount)
+		internal.GET("/payments/:paymentId", paymentController.GetInternalPayment)
+		internal.POST("/payments/charge-authorization", paymentController.ChargeAuthorization)
+	}
+
+	// Each initialization creates a Paystack transaction, so cap how fast one caller can make them
+	initializeLimiter := ratelimit.New("payment_initialize", ratelimit.PerMinute(cfg.InitializeRateLimitPerMinute), ratelimit.NewMemoryStore())
+
+	// API v1 routes
+	v1 := r.Group("/api/v1/payments")
+	{
+		// Payment routes
+		v1.POST("/initialize", ratelimit.Middleware(initializeLimiter, ratelimit.ByUser), paymentController.InitializePayment)
+		v1.GET("/verify/:reference", paymentController.VerifyPayment)
+		v1.GET("/:paymentId/status", paymentController.GetPaymentStatus)
+		v1.GET("/my", middleware.AuthMiddleware(), paymentController.ListMyPayments)
+		v1.GET("/goal/:goalId", middleware.AuthMiddleware(), paymentController.ListGoalPayments)
+		v1.GET("/methods", middleware.AuthMiddleware(), paymentController.ListPaymentMethods)
+		v1.DELETE("/methods/:methodId", middleware.AuthMiddleware(), paymentController.DeletePaymentMethod)
+		v1.GET("/banks", paymentController.ListBanks)
+		v1.GET("/resolve-account", paymentController.ResolveAccount)
+
+		// Webhook route (with signature verification middleware)
+		webhookSecret := cfg.PaystackSecretKey
+		if cfg.PaystackWebhookSecret != "" {
+			webhookSecret = cfg.PaystackWebhookSecret
+		}
+		v1.POST("/webhook", middleware.WebhookAuthMiddleware(webhookSecret), webhookController.HandleWebhook)
+
+		// Mock provider hook for the e2e suite (refused in production)
+		if testhooks.Enabled(cfg.Environment, cfg.DatadogEnv) {
+			v1.POST("/mock/complete", paymentController.CompleteMockPayment)
+		}
+
+		// API docs, generated at build time with `make docs`; never served in production
+		if apidocs.Enabled(cfg.Environment, cfg.DatadogEnv) {
+			v1.GET("/docs", gin.WrapF(apidocs.UI("Payments Service API", "/api/v1/payments/docs/openapi.json")))
+			v1.GET("/docs/openapi.json", gin.WrapF(apidocs.Spec(apidocs.DefaultSpecPath)))
+		}
+	}
+}
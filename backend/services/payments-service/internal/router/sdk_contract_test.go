@@ -0,0 +1,386 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/payments-service/internal/config"
+	"github.com/gofund/payments-service/internal/middleware"
+	"github.com/gofund/payments-service/internal/repository"
+	"github.com/gofund/payments-service/internal/service"
+	"github.com/gofund/payments-service/internal/testmongo"
+	"github.com/gofund/sdk"
+	"github.com/gofund/shared/messaging"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const contractIdentitySecret = "contract-identity-secret"
+
+// paymentsFixture is the state the SDK contracts run against: a pending checkout by a user
+// towards their own goal, which Paystack reports paid by card, and a card the user saved
+// earlier
+type paymentsFixture struct {
+	userID, goalID              uuid.UUID
+	paymentID, reference        string
+	methodID                    string
+	amount                      int64
+	paymentMethods              *repository.PaymentMethodRepository
+	accountNumber, bankCode     string
+	accountName, bankName       string
+	authorizationURL, cardLast4 string
+}
+
+// sdkContract is one SDK method called against the payment routes. check inspects the
+// result against the fixture when the routes are backed by a database; the contracts run
+// in order and later ones see the changes of earlier ones.
+type sdkContract struct {
+	name  string
+	call  func(ctx context.Context, c *sdk.PaymentsClient, f *paymentsFixture) (interface{}, error)
+	check func(t *testing.T, f *paymentsFixture, result interface{})
+}
+
+// sdkContracts covers every PaymentsClient method
+func sdkContracts() []sdkContract {
+	return []sdkContract{
+		{
+			name: "InitializePayment",
+			call: func(ctx context.Context, c *sdk.PaymentsClient, f *paymentsFixture) (interface{}, error) {
+				return c.InitializePayment(ctx, &sdk.InitializePaymentRequest{
+					UserID:   f.userID.String(),
+					GoalID:   f.goalID.String(),
+					Amount:   f.amount,
+					Currency: "NGN",
+					Email:    "contributor@example.com",
+				})
+			},
+			check: func(t *testing.T, f *paymentsFixture, result interface{}) {
+				resp := result.(*sdk.InitializePaymentResponse)
+				if resp.PaymentID == "" || resp.Reference == "" || resp.Amount != f.amount || resp.AuthorizationURL != f.authorizationURL {
+					t.Errorf("response = %+v, want a checkout for %d", resp, f.amount)
+				}
+			},
+		},
+		{
+			name: "VerifyPayment",
+			call: func(ctx context.Context, c *sdk.PaymentsClient, f *paymentsFixture) (interface{}, error) {
+				return c.VerifyPayment(ctx, f.reference)
+			},
+			check: func(t *testing.T, f *paymentsFixture, result interface{}) {
+				resp := result.(*sdk.VerifyPaymentResponse)
+				if resp.PaymentID != f.paymentID || resp.Status != string(models.PaymentStatusVerified) || resp.Amount != f.amount || resp.Channel != "card" {
+					t.Errorf("response = %+v, want %s verified", resp, f.paymentID)
+				}
+			},
+		},
+		{
+			name: "GetPaymentStatus",
+			call: func(ctx context.Context, c *sdk.PaymentsClient, f *paymentsFixture) (interface{}, error) {
+				return c.GetPaymentStatus(ctx, f.paymentID)
+			},
+			check: func(t *testing.T, f *paymentsFixture, result interface{}) {
+				status := result.(*sdk.PaymentStatus)
+				if status.PaymentID != f.paymentID || status.Reference != f.reference || status.Status != string(models.PaymentStatusVerified) || status.CreatedAt == "" {
+					t.Errorf("status = %+v, want %s verified", status, f.paymentID)
+				}
+			},
+		},
+		{
+			name: "ListMyPayments",
+			call: func(ctx context.Context, c *sdk.PaymentsClient, f *paymentsFixture) (interface{}, error) {
+				return c.ListMyPayments(ctx, string(models.PaymentStatusVerified), 1, 10)
+			},
+			check: func(t *testing.T, f *paymentsFixture, result interface{}) {
+				list := result.(*sdk.PaymentList)
+				if list.Total != 1 || len(list.Items) != 1 || list.Items[0].PaymentID != f.paymentID || list.PageSize != 10 {
+					t.Errorf("list = %+v, want the verified payment", list)
+				}
+			},
+		},
+		{
+			name: "ListGoalPayments",
+			call: func(ctx context.Context, c *sdk.PaymentsClient, f *paymentsFixture) (interface{}, error) {
+				return c.ListGoalPayments(ctx, f.goalID.String(), "", 1, 10)
+			},
+			check: func(t *testing.T, f *paymentsFixture, result interface{}) {
+				list := result.(*sdk.PaymentList)
+				if list.Total != 2 || len(list.Items) != 2 {
+					t.Fatalf("list = %+v, want the seeded and the initialized payment", list)
+				}
+				for _, item := range list.Items {
+					if item.Metadata != nil || item.AppMetadata != nil {
+						t.Errorf("payment %s shows the payer's metadata to the goal owner", item.PaymentID)
+					}
+				}
+			},
+		},
+		{
+			name: "ListPaymentMethods",
+			call: func(ctx context.Context, c *sdk.PaymentsClient, f *paymentsFixture) (interface{}, error) {
+				return c.ListPaymentMethods(ctx)
+			},
+			check: func(t *testing.T, f *paymentsFixture, result interface{}) {
+				methods := result.([]sdk.PaymentMethod)
+				if len(methods) != 2 {
+					t.Fatalf("methods = %+v, want the saved card and the one just paid with", methods)
+				}
+				// The card just paid with was used last
+				if latest := methods[0]; latest.Last4 != f.cardLast4 || !latest.IsReusable || latest.UserID != f.userID.String() || latest.ID == "" {
+					t.Errorf("latest method = %+v, want the reusable card ending %s", latest, f.cardLast4)
+				}
+			},
+		},
+		{
+			name: "DeletePaymentMethod",
+			call: func(ctx context.Context, c *sdk.PaymentsClient, f *paymentsFixture) (interface{}, error) {
+				return nil, c.DeletePaymentMethod(ctx, f.methodID)
+			},
+			check: func(t *testing.T, f *paymentsFixture, result interface{}) {
+				methods, err := f.paymentMethods.ListPaymentMethods(context.Background(), f.userID.String())
+				if err != nil {
+					t.Fatalf("ListPaymentMethods: %v", err)
+				}
+				if len(methods) != 1 || methods[0].ID.Hex() == f.methodID {
+					t.Errorf("methods = %+v, want only the card just paid with", methods)
+				}
+			},
+		},
+		{
+			name: "ListBanks",
+			call: func(ctx context.Context, c *sdk.PaymentsClient, f *paymentsFixture) (interface{}, error) {
+				return c.ListBanks(ctx, "nigeria")
+			},
+			check: func(t *testing.T, f *paymentsFixture, result interface{}) {
+				banks := result.([]sdk.Bank)
+				if len(banks) != 1 || banks[0].Code != f.bankCode || banks[0].Name != f.bankName {
+					t.Errorf("banks = %+v, want the one active bank", banks)
+				}
+			},
+		},
+		{
+			name: "ResolveAccount",
+			call: func(ctx context.Context, c *sdk.PaymentsClient, f *paymentsFixture) (interface{}, error) {
+				return c.ResolveAccount(ctx, f.accountNumber, f.bankCode)
+			},
+			check: func(t *testing.T, f *paymentsFixture, result interface{}) {
+				account := result.(*sdk.ResolvedAccount)
+				if account.AccountNumber != f.accountNumber || account.AccountName != f.accountName || account.BankCode != f.bankCode || account.BankName != f.bankName {
+					t.Errorf("account = %+v, want %s at %s", account, f.accountName, f.bankName)
+				}
+			},
+		},
+	}
+}
+
+// routeProbe serves every route r has with an empty answer, so an SDK request succeeds
+// exactly when its method and path match one of r's routes
+func routeProbe(r *gin.Engine) http.Handler {
+	probe := gin.New()
+	for _, route := range r.Routes() {
+		probe.Handle(route.Method, route.Path, func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/json", []byte("null"))
+		})
+	}
+	return probe
+}
+
+// newFixture returns a fixture with fresh IDs and the details the Paystack stub answers with
+func newFixture() *paymentsFixture {
+	return &paymentsFixture{
+		userID:           uuid.New(),
+		goalID:           uuid.New(),
+		paymentID:        uuid.NewString(),
+		reference:        "PAY-" + uuid.NewString()[:13],
+		methodID:         primitive.NewObjectID().Hex(),
+		amount:           5_000,
+		accountNumber:    "0123456789",
+		bankCode:         "058",
+		accountName:      "ADA OBI",
+		bankName:         "Guaranty Trust Bank",
+		authorizationURL: "https://checkout.paystack.test/contract",
+		cardLast4:        "4081",
+	}
+}
+
+// TestSDKRoutes checks every SDK method requests a route the service has; unlike
+// TestSDKContract it needs no database
+func TestSDKRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	SetupRoutes(r, nil, nil, &config.Config{})
+	server := httptest.NewServer(routeProbe(r))
+	t.Cleanup(server.Close)
+
+	client := sdk.NewPaymentsClient(server.URL)
+	f := newFixture()
+	for _, tc := range sdkContracts() {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.call(context.Background(), client, f); err != nil {
+				t.Errorf("no route for the request: %v", err)
+			}
+		})
+	}
+}
+
+// TestSDKContract calls every SDK method against the real routes over a seeded database,
+// with goals-service and Paystack stubbed, as a user signed in through the gateway
+func TestSDKContract(t *testing.T) {
+	db := testmongo.Open(t)
+	f := newFixture()
+	paymentRepo := repository.NewPaymentRepository(db)
+	f.paymentMethods = repository.NewPaymentMethodRepository(db)
+	seedPayments(t, f, paymentRepo)
+
+	goals := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(service.GoalInfo{OwnerID: f.userID.String(), Currency: "NGN", FeeMode: models.FeeModeAbsorb})
+	}))
+	t.Cleanup(goals.Close)
+	paystack := httptest.NewServer(paystackStub(t, f))
+	t.Cleanup(paystack.Close)
+
+	paymentService := service.NewPaymentService(
+		paymentRepo,
+		repository.NewIdempotencyRepository(db),
+		repository.NewGoalStateRepository(db),
+		f.paymentMethods,
+		service.NewPaystackClient("sk_test", paystack.URL, service.PaystackRetryPolicy{}),
+		service.NewGoalsClient(goals.URL),
+		discardPublisher{},
+		time.Minute,
+		nil,
+	)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.IdentityGuard(contractIdentitySecret))
+	SetupRoutes(r, paymentService, nil, &config.Config{})
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	client := sdk.NewPaymentsClient(server.URL, sdk.WithIdentity(contractIdentitySecret, f.userID.String(), "user", true))
+	for _, tc := range sdkContracts() {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.call(context.Background(), client, f)
+			if err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			tc.check(t, f, result)
+		})
+	}
+}
+
+// seedPayments stores the pending checkout and the user's saved card
+func seedPayments(t *testing.T, f *paymentsFixture, paymentRepo *repository.PaymentRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	err := paymentRepo.CreatePayment(ctx, &models.Payment{
+		PaymentID:         f.paymentID,
+		PaystackReference: f.reference,
+		UserID:            f.userID.String(),
+		GoalID:            f.goalID.String(),
+		Amount:            f.amount,
+		Currency:          "NGN",
+		Status:            models.PaymentStatusPending,
+	})
+	if err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+
+	err = f.paymentMethods.SavePaymentMethod(ctx, &models.SavedPaymentMethod{
+		UserID:            f.userID.String(),
+		Fingerprint:       "AUTH_saved",
+		AuthorizationCode: "AUTH_saved",
+		Email:             "contributor@example.com",
+		Channel:           "card",
+		Last4:             "1234",
+		IsReusable:        true,
+	})
+	if err != nil {
+		t.Fatalf("SavePaymentMethod: %v", err)
+	}
+	methods, err := f.paymentMethods.ListPaymentMethods(ctx, f.userID.String())
+	if err != nil || len(methods) != 1 {
+		t.Fatalf("ListPaymentMethods = %v, %v; want the saved card", methods, err)
+	}
+	f.methodID = methods[0].ID.Hex()
+}
+
+// paystackStub answers the Paystack calls the routes make: checkout initialization,
+// verification of the fixture's payment as paid by a reusable card, the bank list and
+// account resolution
+func paystackStub(t *testing.T, f *paymentsFixture) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/transaction/initialize", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Reference string `json:"reference"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode the Paystack request: %v", err)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": true,
+			"data":   map[string]string{"authorization_url": f.authorizationURL, "access_code": "contract", "reference": req.Reference},
+		})
+	})
+	mux.HandleFunc("/transaction/verify/", func(w http.ResponseWriter, r *http.Request) {
+		if reference := strings.TrimPrefix(r.URL.Path, "/transaction/verify/"); reference != f.reference {
+			t.Errorf("verified reference %q, want %q", reference, f.reference)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": true,
+			"data": map[string]interface{}{
+				"id":        1,
+				"status":    "success",
+				"reference": f.reference,
+				"amount":    f.amount,
+				"currency":  "NGN",
+				"channel":   "card",
+				"paid_at":   time.Now().UTC().Format(time.RFC3339),
+				"metadata":  map[string]string{"payment_id": f.paymentID, "user_id": f.userID.String(), "goal_id": f.goalID.String()},
+				"customer":  map[string]string{"email": "contributor@example.com"},
+				"authorization": map[string]interface{}{
+					"authorization_code": "AUTH_contract",
+					"card_type":          "visa",
+					"last4":              f.cardLast4,
+					"exp_month":          "12",
+					"exp_year":           "2030",
+					"bank":               "TEST BANK",
+					"reusable":           true,
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/bank", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": true,
+			"data": []map[string]interface{}{
+				{"id": 9, "name": f.bankName, "code": f.bankCode, "active": true},
+				{"id": 10, "name": "Closed Bank", "code": "999", "active": false},
+			},
+		})
+	})
+	mux.HandleFunc("/bank/resolve", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": true,
+			"data":   map[string]interface{}{"account_number": r.URL.Query().Get("account_number"), "account_name": f.accountName, "bank_id": 9},
+		})
+	})
+	return mux
+}
+
+// discardPublisher is a messaging.Publisher that drops every event
+type discardPublisher struct{}
+
+var _ messaging.Publisher = discardPublisher{}
+
+func (discardPublisher) Publish(string, interface{}) error { return nil }
+
+func (discardPublisher) PublishContext(context.Context, string, interface{}) error { return nil }
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofund/payments-service/internal/dto"
+)
+
+// bankCache holds the Paystack bank list per country. Paystack's list rarely changes, so
+// entries are served until the TTL passes and kept after that as a fallback for when
+// Paystack cannot be reached.
+type bankCache struct {
+	mu      sync.RWMutex
+	entries map[string]bankCacheEntry
+	ttl     time.Duration
+	// refresh serializes fetches so concurrent misses make a single Paystack call
+	refresh sync.Mutex
+}
+
+type bankCacheEntry struct {
+	banks     []dto.Bank
+	fetchedAt time.Time
+}
+
+func newBankCache(ttl time.Duration) *bankCache {
+	return &bankCache{
+		entries: make(map[string]bankCacheEntry),
+		ttl:     ttl,
+	}
+}
+
+// get returns the country's bank list if it is younger than the TTL
+func (c *bankCache) get(country string) ([]dto.Bank, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[country]
+	if !ok || time.Since(entry.fetchedAt) >= c.ttl {
+		return nil, false
+	}
+	return entry.banks, true
+}
+
+// stale returns the country's bank list however old it is
+func (c *bankCache) stale(country string) ([]dto.Bank, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[country]
+	return entry.banks, ok
+}
+
+func (c *bankCache) set(country string, banks []dto.Bank) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[country] = bankCacheEntry{banks: banks, fetchedAt: time.Now()}
+}
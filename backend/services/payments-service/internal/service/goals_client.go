@@ -0,0 +1,81 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+)
+
+// ErrGoalNotFound is returned when goals-service does not know the goal
+var ErrGoalNotFound = errors.New("goal_not_found")
+
+// GoalsClient looks up goal details from goals-service
+type GoalsClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewGoalsClient creates a goals-service client
+func NewGoalsClient(baseURL string) *GoalsClient {
+	return &GoalsClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+// GoalInfo is the part of a goal payments-service checks payments against
+type GoalInfo struct {
+	OwnerID  string         `json:"owner_id"`
+	Currency string         `json:"currency"`
+	FeeMode  models.FeeMode `json:"fee_mode"`
+}
+
+// GetGoal calls GET /internal/goals/:id on goals-service
+func (gc *GoalsClient) GetGoal(goalID string) (*GoalInfo, error) {
+	if gc.baseURL == "" {
+		return nil, fmt.Errorf("goals-service URL not configured")
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/goals/%s", gc.baseURL, url.PathEscape(goalID))
+
+	start := time.Now()
+	resp, err := gc.client.Get(endpoint)
+	metrics.RecordDuration("payments.goals_client.duration", start)
+	if err != nil {
+		metrics.IncrementCounter("payments.goals_client.error")
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusBadRequest:
+		// goals-service rejects malformed IDs with 400; either way there is no such goal
+		return nil, ErrGoalNotFound
+	default:
+		metrics.IncrementCounter("payments.goals_client.error")
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var goal GoalInfo
+	if err := json.NewDecoder(resp.Body).Decode(&goal); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &goal, nil
+}
+
+// GetGoalOwner returns the goal owner's ID
+func (gc *GoalsClient) GetGoalOwner(goalID string) (string, error) {
+	goal, err := gc.GetGoal(goalID)
+	if err != nil {
+		return "", err
+	}
+	return goal.OwnerID, nil
+}
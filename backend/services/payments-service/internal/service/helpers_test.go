@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gofund/payments-service/internal/dto"
+)
+
+// publishedEvent is one event handed to a recordingPublisher
+type publishedEvent struct {
+	eventType string
+	event     interface{}
+}
+
+// recordingPublisher is a messaging.Publisher that keeps what it is given
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []publishedEvent
+}
+
+func (p *recordingPublisher) Publish(eventType string, event interface{}) error {
+	return p.PublishContext(context.Background(), eventType, event)
+}
+
+func (p *recordingPublisher) PublishContext(_ context.Context, eventType string, event interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, publishedEvent{eventType: eventType, event: event})
+	return nil
+}
+
+// published returns the events of eventType published so far
+func (p *recordingPublisher) published(eventType string) []interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var matched []interface{}
+	for _, e := range p.events {
+		if e.eventType == eventType {
+			matched = append(matched, e.event)
+		}
+	}
+	return matched
+}
+
+// loadWebhook reads a recorded Paystack webhook delivery from testdata/webhooks
+func loadWebhook(t *testing.T, name string) *dto.WebhookPayload {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "webhooks", name))
+	if err != nil {
+		t.Fatalf("failed to read recorded webhook: %v", err)
+	}
+	var payload dto.WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("failed to parse recorded webhook %s: %v", name, err)
+	}
+	return &payload
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gofund/payments-service/internal/repository"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+)
+
+var (
+	// ErrPaymentMethodNotFound is returned when the caller has no saved payment method with the ID
+	ErrPaymentMethodNotFound = repository.ErrPaymentMethodNotFound
+	// ErrPaymentMethodNotReusable is returned when a saved card cannot be charged again
+	// and the payer has to go through checkout
+	ErrPaymentMethodNotReusable = errors.New("payment_method_not_reusable")
+	// ErrIdempotencyKeyRequired is returned when a saved payment method is charged without
+	// an Idempotency-Key; the charge is immediate, so a retry must not make a second one
+	ErrIdempotencyKeyRequired = errors.New("idempotency_key_required")
+)
+
+// savePaymentMethod adds the card a verified payment was made with to the payer's saved
+// payment methods. Failing to save it does not fail the payment.
+func savePaymentMethod(ctx context.Context, methodRepo *repository.PaymentMethodRepository, payment *models.Payment) {
+	method := savedPaymentMethod(payment)
+	if method == nil {
+		return
+	}
+
+	if err := methodRepo.SavePaymentMethod(ctx, method); err != nil {
+		log.Printf("[ERROR] Failed to save payment method: %v (payment_id: %s)", err, payment.PaymentID)
+		metrics.IncrementCounter("payment.method.save_failed")
+		return
+	}
+	metrics.IncrementCounter("payment.method.saved", "channel:"+method.Channel)
+}
+
+// ListPaymentMethods returns the user's saved payment methods, most recently used first
+func (ps *PaymentService) ListPaymentMethods(ctx context.Context, userID string) ([]models.SavedPaymentMethod, error) {
+	return ps.methodRepo.ListPaymentMethods(ctx, userID)
+}
+
+// DeletePaymentMethod removes one of the user's saved payment methods
+func (ps *PaymentService) DeletePaymentMethod(ctx context.Context, userID, methodID string) error {
+	if err := ps.methodRepo.DeletePaymentMethod(ctx, userID, methodID); err != nil {
+		return err
+	}
+	metrics.IncrementCounter("payment.method.deleted")
+	return nil
+}
+
+// reusablePaymentMethod returns one of the user's saved payment methods that can be
+// charged without checkout
+func (ps *PaymentService) reusablePaymentMethod(ctx context.Context, userID, methodID string) (*models.SavedPaymentMethod, error) {
+	method, err := ps.methodRepo.GetPaymentMethod(ctx, userID, methodID)
+	if err != nil {
+		return nil, err
+	}
+	if !method.IsReusable || method.Email == "" {
+		return nil, ErrPaymentMethodNotReusable
+	}
+	return method, nil
+}
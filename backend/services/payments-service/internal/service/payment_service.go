@@ -2,58 +2,181 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/gofund/payments-service/internal/dto"
 	"github.com/gofund/payments-service/internal/repository"
 	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/fees"
+	"github.com/gofund/shared/logger"
 	"github.com/gofund/shared/messaging"
 	"github.com/gofund/shared/metrics"
 	"github.com/gofund/shared/models"
+	"github.com/gofund/shared/validator"
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrGoalTargetReached is returned when a close-on-target goal no longer accepts payments
+	ErrGoalTargetReached = errors.New("goal_target_reached")
+	// ErrPaymentInProgress is returned when another request with the same idempotency key
+	// is still initializing its payment
+	ErrPaymentInProgress = errors.New("payment_in_progress")
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent again with a
+	// different payment
+	ErrIdempotencyKeyReused = errors.New("idempotency_key_reused")
+	// ErrInvalidPaymentStatus is returned when a listing is filtered by an unknown status
+	ErrInvalidPaymentStatus = errors.New("invalid_payment_status")
+	// ErrNotGoalOwner is returned when someone other than the goal owner lists its payments
+	ErrNotGoalOwner = errors.New("not_goal_owner")
+	// ErrUnsupportedCurrency is returned for a currency GoFund does not take payments in
+	ErrUnsupportedCurrency = validator.ErrUnsupportedCurrency
+	// ErrCurrencyMismatch is returned when a payment's currency is not its goal's
+	ErrCurrencyMismatch = errors.New("currency_mismatch")
+)
+
+const (
+	defaultPaymentsPageSize = 20
+	maxPaymentsPageSize     = 100
+)
+
 // PaymentService handles payment business logic
 type PaymentService struct {
 	paymentRepo     *repository.PaymentRepository
 	idempotencyRepo *repository.IdempotencyRepository
+	goalStateRepo   *repository.GoalStateRepository
+	methodRepo      *repository.PaymentMethodRepository
 	paystackClient  *PaystackClient
+	goalsClient     *GoalsClient
 	eventPublisher  messaging.Publisher
+	bankCache       *bankCache
+	platformFees    fees.Schedule
 }
 
-// NewPaymentService creates a new payment service
+// NewPaymentService creates a new payment service. Checkouts for goals whose
+// contributors pay the platform fee add it from platformFees.
 func NewPaymentService(
 	paymentRepo *repository.PaymentRepository,
 	idempotencyRepo *repository.IdempotencyRepository,
+	goalStateRepo *repository.GoalStateRepository,
+	methodRepo *repository.PaymentMethodRepository,
 	paystackClient *PaystackClient,
+	goalsClient *GoalsClient,
 	eventPublisher messaging.Publisher,
+	bankCacheTTL time.Duration,
+	platformFees fees.Schedule,
 ) *PaymentService {
 	return &PaymentService{
 		paymentRepo:     paymentRepo,
 		idempotencyRepo: idempotencyRepo,
+		goalStateRepo:   goalStateRepo,
+		methodRepo:      methodRepo,
 		paystackClient:  paystackClient,
+		goalsClient:     goalsClient,
 		eventPublisher:  eventPublisher,
+		bankCache:       newBankCache(bankCacheTTL),
+		platformFees:    platformFees,
+	}
+}
+
+// HandleGoalClosedEarly stops new payments for a goal that closed on reaching its target.
+// Payments already initialized are left alone so money in flight still lands.
+func (ps *PaymentService) HandleGoalClosedEarly(ctx context.Context, data []byte) error {
+	var event events.GoalClosedEarly
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal GoalClosedEarly event: %w", err)
+	}
+
+	if err := ps.goalStateRepo.MarkGoalClosed(ctx, event.GoalID, "target_reached"); err != nil {
+		return err
 	}
+
+	log.Printf("[INFO] Goal closed early, rejecting new payments (goal_id: %s, pending_contributions: %d)",
+		event.GoalID, len(event.PendingContributionIDs))
+	return nil
 }
 
-// InitializePayment initializes a new payment with Paystack
+// InitializePayment initializes a new payment with Paystack. With a saved payment method
+// the card is charged at once instead, and the response carries the charge's status in
+// place of a checkout URL.
 func (ps *PaymentService) InitializePayment(ctx context.Context, req *dto.InitializePaymentRequest) (*dto.InitializePaymentResponse, error) {
+	// Refuse new payments for goals that closed on reaching their target
+	closed, err := ps.goalStateRepo.IsGoalClosed(ctx, req.GoalID.String())
+	if err != nil {
+		return nil, err
+	}
+	if closed {
+		metrics.IncrementCounter("payment.initialization.goal_closed")
+		return nil, ErrGoalTargetReached
+	}
+
+	goal, err := ps.checkoutGoal(req.GoalID.String(), req.Currency)
+	if err != nil {
+		metrics.IncrementCounter("payment.initialization.currency_rejected")
+		return nil, err
+	}
+	req.Currency = goal.Currency
+
+	// On add-on goals the contributor pays the platform fee on top, so the goal receives
+	// the amount asked for; goals-service works out the same fee for the intent
+	var fee int64
+	if goal.FeeMode == models.FeeModeAddOn {
+		fee = ps.platformFees.Fee(req.Currency, req.Amount)
+	}
+
+	var method *models.SavedPaymentMethod
+	if req.PaymentMethodID != "" {
+		if req.IdempotencyKey == "" {
+			return nil, ErrIdempotencyKeyRequired
+		}
+		if method, err = ps.reusablePaymentMethod(ctx, req.UserID.String(), req.PaymentMethodID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Generate unique payment ID and reference
 	paymentID := uuid.New().String()
 	reference := fmt.Sprintf("PAY-%s", uuid.New().String()[:13])
 
+	// Claim the idempotency key before anything reaches Paystack; a repeated request gets
+	// the original payment back instead of a second checkout
+	idempotencyKey, derived := initializeIdempotencyKey(req)
+	resp, claimed, err := ps.claimInitialization(ctx, idempotencyKey, derived, paymentID, req)
+	if err != nil || !claimed {
+		return resp, err
+	}
+	// Release the key on failure so the client can retry
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			if err := ps.idempotencyRepo.DeleteIdempotencyKey(context.Background(), idempotencyKey); err != nil {
+				log.Printf("[ERROR] Failed to release idempotency key: %v (payment_id: %s)", err, paymentID)
+			}
+		}
+	}()
+
 	// Create payment record with INITIATED status
 	payment := &models.Payment{
 		PaymentID:         paymentID,
 		PaystackReference: reference,
 		UserID:            req.UserID.String(),
 		GoalID:            req.GoalID.String(),
-		Amount:            req.Amount,
+		Amount:            req.Amount + fee,
+		FeeAmount:         fee,
 		Currency:          req.Currency,
 		Status:            models.PaymentStatusInitiated,
 	}
+	if req.ContributionID != nil {
+		payment.ContributionID = req.ContributionID.String()
+	}
+	paystackRecord(payment)
 
 	if err := ps.paymentRepo.CreatePayment(ctx, payment); err != nil {
 		log.Printf("[ERROR] Failed to create payment record: %v (user_id: %s, goal_id: %s)",
@@ -65,23 +188,37 @@ func (ps *PaymentService) InitializePayment(ctx context.Context, req *dto.Initia
 	// Prepare Paystack initialization request
 	paystackReq := &dto.PaystackInitializeRequest{
 		Email:       req.Email,
-		Amount:      req.Amount,
+		Amount:      payment.Amount,
 		Currency:    req.Currency,
 		Reference:   reference,
 		CallbackURL: req.CallbackURL,
-		Metadata: map[string]interface{}{
-			"payment_id": paymentID,
-			"user_id":    req.UserID.String(),
-			"goal_id":    req.GoalID.String(),
-		},
-		Channels: []string{"card", "bank", "ussd", "qr", "mobile_money", "bank_transfer"},
-	}
-
-	// Add custom metadata if provided
-	if req.Metadata != nil {
-		for k, v := range req.Metadata {
-			paystackReq.Metadata[k] = v
+		Metadata:    make(map[string]interface{}),
+		Channels:    []string{"card", "bank", "ussd", "qr", "mobile_money", "bank_transfer"},
+	}
+
+	// Custom metadata goes first so it can never overwrite our own fields
+	for k, v := range req.Metadata {
+		paystackReq.Metadata[k] = v
+	}
+	paystackReq.Metadata[metaPaymentID] = paymentID
+	paystackReq.Metadata[metaUserID] = req.UserID.String()
+	paystackReq.Metadata[metaGoalID] = req.GoalID.String()
+	if payment.ContributionID != "" {
+		paystackReq.Metadata[metaContributionID] = payment.ContributionID
+	}
+	reconcileAppMetadata(payment, paystackReq.Metadata, "initialize")
+
+	if method != nil {
+		// The key stays claimed even when the charge fails, as Paystack may still settle it
+		succeeded = true
+		if err := ps.chargeAuthorization(ctx, payment, method.Email, method.AuthorizationCode, paystackReq.Metadata); err != nil {
+			return nil, err
 		}
+
+		metrics.IncrementCounter("payment.initialized.count", "method:saved")
+		log.Printf("[INFO] Payment charged to saved payment method (payment_id: %s, reference: %s, status: %s)",
+			paymentID, reference, payment.Status)
+		return initializeResponse(payment), nil
 	}
 
 	// Initialize transaction with Paystack
@@ -99,9 +236,10 @@ func (ps *PaymentService) InitializePayment(ctx context.Context, req *dto.Initia
 
 	// Update payment with Paystack data
 	payment.Status = models.PaymentStatusPending
-	payment.PaystackData = map[string]interface{}{
+	paystackRecord(payment).Initialization = map[string]interface{}{
 		"authorization_url": paystackResp.Data.AuthorizationURL,
 		"access_code":       paystackResp.Data.AccessCode,
+		"reference":         paystackResp.Data.Reference,
 	}
 
 	if err := ps.paymentRepo.UpdatePayment(ctx, payment); err != nil {
@@ -110,6 +248,8 @@ func (ps *PaymentService) InitializePayment(ctx context.Context, req *dto.Initia
 		return nil, fmt.Errorf("failed to update payment: %w", err)
 	}
 
+	succeeded = true
+
 	// Track metrics
 	metrics.IncrementCounter("payment.initialized.count")
 
@@ -118,12 +258,107 @@ func (ps *PaymentService) InitializePayment(ctx context.Context, req *dto.Initia
 
 	return &dto.InitializePaymentResponse{
 		PaymentID:        paymentID,
+		Amount:           payment.Amount,
+		FeeAmount:        payment.FeeAmount,
 		AuthorizationURL: paystackResp.Data.AuthorizationURL,
 		AccessCode:       paystackResp.Data.AccessCode,
 		Reference:        reference,
 	}, nil
 }
 
+// initializeIdempotencyKey returns the key that deduplicates an initialization, scoped to
+// the user. Without an Idempotency-Key header it is derived from the payment itself, and
+// derived is true.
+func initializeIdempotencyKey(req *dto.InitializePaymentRequest) (key string, derived bool) {
+	if req.IdempotencyKey != "" {
+		return fmt.Sprintf("initialize:%s:%s", req.UserID, req.IdempotencyKey), false
+	}
+
+	contributionID := ""
+	if req.ContributionID != nil {
+		contributionID = req.ContributionID.String()
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%d|%s", req.UserID, req.GoalID, contributionID, req.Amount, req.Currency)))
+	return fmt.Sprintf("initialize:%s:derived:%s", req.UserID, hex.EncodeToString(sum[:])), true
+}
+
+// claimInitialization claims idempotencyKey for paymentID. When the key is already taken
+// it returns the original payment's response with claimed false, or ErrPaymentInProgress
+// while that payment is still being set up. A derived key whose payment has already
+// settled is released and claimed again, so paying the same amount twice still works.
+func (ps *PaymentService) claimInitialization(ctx context.Context, idempotencyKey string, derived bool, paymentID string, req *dto.InitializePaymentRequest) (*dto.InitializePaymentResponse, bool, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		err := ps.idempotencyRepo.SaveIdempotencyKey(ctx, idempotencyKey, paymentID)
+		if err == nil {
+			return nil, true, nil
+		}
+		if !errors.Is(err, repository.ErrIdempotencyKeyExists) {
+			return nil, false, err
+		}
+
+		exists, originalID, err := ps.idempotencyRepo.CheckIdempotencyKey(ctx, idempotencyKey)
+		if err != nil {
+			return nil, false, err
+		}
+		if !exists {
+			// Released or expired since the claim; try again
+			continue
+		}
+
+		original, err := ps.paymentRepo.GetPaymentByID(ctx, originalID)
+		if err != nil {
+			// Claimed but the payment record is not written yet
+			metrics.IncrementCounter("payment.initialization.in_progress")
+			return nil, false, ErrPaymentInProgress
+		}
+		// The original's amount includes any platform fee it added
+		if original.UserID != req.UserID.String() || original.GoalID != req.GoalID.String() || original.Amount-original.FeeAmount != req.Amount {
+			metrics.IncrementCounter("payment.initialization.key_reused")
+			return nil, false, ErrIdempotencyKeyReused
+		}
+
+		switch original.Status {
+		case models.PaymentStatusPending:
+			metrics.IncrementCounter("payment.initialization.replayed")
+			log.Printf("[INFO] Returning existing payment for repeated initialization (payment_id: %s)", original.PaymentID)
+			return initializeResponse(original), false, nil
+		case models.PaymentStatusInitiated:
+			metrics.IncrementCounter("payment.initialization.in_progress")
+			return nil, false, ErrPaymentInProgress
+		}
+
+		// The original payment has settled or failed
+		if !derived {
+			metrics.IncrementCounter("payment.initialization.replayed")
+			return initializeResponse(original), false, nil
+		}
+		if err := ps.idempotencyRepo.DeleteIdempotencyKey(ctx, idempotencyKey); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return nil, false, ErrPaymentInProgress
+}
+
+// initializeResponse rebuilds the initialization response of an existing payment
+func initializeResponse(payment *models.Payment) *dto.InitializePaymentResponse {
+	resp := &dto.InitializePaymentResponse{
+		PaymentID: payment.PaymentID,
+		Amount:    payment.Amount,
+		FeeAmount: payment.FeeAmount,
+		Reference: payment.PaystackReference,
+	}
+	if payment.Paystack != nil && payment.Paystack.Initialization != nil {
+		resp.AuthorizationURL, _ = payment.Paystack.Initialization["authorization_url"].(string)
+		resp.AccessCode, _ = payment.Paystack.Initialization["access_code"].(string)
+	}
+	// A saved payment method was charged directly, without a checkout
+	if resp.AuthorizationURL == "" {
+		resp.Status = string(payment.Status)
+	}
+	return resp
+}
+
 // VerifyPayment verifies a payment with Paystack (instant verification)
 func (ps *PaymentService) VerifyPayment(ctx context.Context, reference string) (*dto.VerifyPaymentResponse, error) {
 	// Step 1: Get payment by reference
@@ -149,20 +384,20 @@ func (ps *PaymentService) VerifyPayment(ctx context.Context, reference string) (
 		return nil, fmt.Errorf("failed to verify payment with Paystack: %w", err)
 	}
 
+	// A charge for another amount or currency must not verify the payment
+	if paystackResp.Data.Status == "success" {
+		if err := checkChargeMatchesPayment(payment, paystackResp.Data.Amount, paystackResp.Data.Currency); err != nil {
+			metrics.IncrementCounter("payment.verification.mismatch")
+			log.Printf("[ERROR] Charge does not match payment: %v (payment_id: %s, reference: %s)",
+				err, payment.PaymentID, reference)
+			return nil, err
+		}
+	}
+
 	// Step 4: Update payment status based on Paystack response
 	if paystackResp.Data.Status == "success" {
 		payment.Status = models.PaymentStatusVerified
-		payment.PaystackData = map[string]interface{}{
-			"id":               paystackResp.Data.ID,
-			"status":           paystackResp.Data.Status,
-			"reference":        paystackResp.Data.Reference,
-			"amount":           paystackResp.Data.Amount,
-			"paid_at":          paystackResp.Data.PaidAt,
-			"channel":          paystackResp.Data.Channel,
-			"currency":         paystackResp.Data.Currency,
-			"gateway_response": paystackResp.Data.GatewayResponse,
-			"customer":         paystackResp.Data.Customer,
-		}
+		recordVerification(payment, paystackResp.Data)
 
 		if err := ps.paymentRepo.UpdatePayment(ctx, payment); err != nil {
 			log.Printf("[ERROR] Failed to update payment status: %v (payment_id: %s)",
@@ -170,8 +405,10 @@ func (ps *PaymentService) VerifyPayment(ctx context.Context, reference string) (
 			return nil, fmt.Errorf("failed to update payment: %w", err)
 		}
 
+		savePaymentMethod(ctx, ps.methodRepo, payment)
+
 		// Step 5: Emit PaymentVerified event
-		if err := ps.emitPaymentVerifiedEvent(payment); err != nil {
+		if err := ps.emitPaymentVerifiedEvent(ctx, payment); err != nil {
 			// Log error but don't fail the request
 			log.Printf("[ERROR] Failed to emit PaymentVerified event: %v (payment_id: %s)",
 				err, payment.PaymentID)
@@ -186,10 +423,7 @@ func (ps *PaymentService) VerifyPayment(ctx context.Context, reference string) (
 	} else {
 		// Payment failed
 		payment.Status = models.PaymentStatusFailed
-		payment.PaystackData = map[string]interface{}{
-			"status":           paystackResp.Data.Status,
-			"gateway_response": paystackResp.Data.GatewayResponse,
-		}
+		recordVerification(payment, paystackResp.Data)
 
 		ps.paymentRepo.UpdatePayment(ctx, payment)
 
@@ -202,6 +436,210 @@ func (ps *PaymentService) VerifyPayment(ctx context.Context, reference string) (
 	return ps.mapPaymentToVerifyResponse(payment), nil
 }
 
+// ChargeAuthorization charges a contributor's saved card for a contribution intent. Paystack
+// settles most charges at once; a charge it leaves pending is settled by its webhook. A
+// repeated idempotency key returns the original charge, so a caller retrying after a lost
+// response never charges twice.
+func (ps *PaymentService) ChargeAuthorization(ctx context.Context, req *dto.ChargeAuthorizationRequest) (*dto.ChargeAuthorizationResponse, error) {
+	closed, err := ps.goalStateRepo.IsGoalClosed(ctx, req.GoalID.String())
+	if err != nil {
+		return nil, err
+	}
+	if closed {
+		metrics.IncrementCounter("payment.charge_authorization.goal_closed")
+		return nil, ErrGoalTargetReached
+	}
+
+	if req.Currency, err = validator.NormalizeCurrency(req.Currency); err != nil {
+		return nil, err
+	}
+
+	paymentID := uuid.New().String()
+	reference := fmt.Sprintf("PAY-%s", uuid.New().String()[:13])
+
+	// The key stays claimed even when the charge fails: Paystack may still settle it, and
+	// the caller retries a failure under a new key
+	idempotencyKey := fmt.Sprintf("charge:%s:%s", req.UserID, req.IdempotencyKey)
+	if err := ps.idempotencyRepo.SaveIdempotencyKey(ctx, idempotencyKey, paymentID); err != nil {
+		if !errors.Is(err, repository.ErrIdempotencyKeyExists) {
+			return nil, err
+		}
+		return ps.replayCharge(ctx, idempotencyKey, req)
+	}
+
+	payment := &models.Payment{
+		PaymentID:         paymentID,
+		PaystackReference: reference,
+		UserID:            req.UserID.String(),
+		GoalID:            req.GoalID.String(),
+		Amount:            req.Amount,
+		Currency:          req.Currency,
+		Status:            models.PaymentStatusInitiated,
+	}
+	if req.ContributionID != nil {
+		payment.ContributionID = req.ContributionID.String()
+	}
+	paystackRecord(payment)
+
+	if err := ps.paymentRepo.CreatePayment(ctx, payment); err != nil {
+		metrics.IncrementCounter("payment.creation.failed")
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	metadata := map[string]interface{}{
+		metaPaymentID: paymentID,
+		metaUserID:    req.UserID.String(),
+		metaGoalID:    req.GoalID.String(),
+	}
+	if payment.ContributionID != "" {
+		metadata[metaContributionID] = payment.ContributionID
+	}
+
+	if err := ps.chargeAuthorization(ctx, payment, req.Email, req.AuthorizationCode, metadata); err != nil {
+		return nil, err
+	}
+
+	metrics.IncrementCounter("payment.charge_authorization.count", "status:"+strings.ToLower(string(payment.Status)))
+	log.Printf("[INFO] Authorization charged (payment_id: %s, reference: %s, status: %s)",
+		paymentID, reference, payment.Status)
+
+	return chargeResponse(payment), nil
+}
+
+// chargeAuthorization charges a card authorization for a payment that has been created,
+// recording the outcome on the payment: VERIFIED, FAILED, or PENDING until Paystack's
+// webhook settles it. A verified charge emits PaymentVerified.
+func (ps *PaymentService) chargeAuthorization(ctx context.Context, payment *models.Payment, email, authorizationCode string, metadata map[string]interface{}) error {
+	paystackResp, err := ps.paystackClient.ChargeAuthorization(&dto.PaystackChargeAuthorizationRequest{
+		Email:             email,
+		Amount:            payment.Amount,
+		Currency:          payment.Currency,
+		AuthorizationCode: authorizationCode,
+		Reference:         payment.PaystackReference,
+		Metadata:          metadata,
+	})
+	if err != nil {
+		log.Printf("[ERROR] Paystack authorization charge failed: %v (payment_id: %s)", err, payment.PaymentID)
+		metrics.IncrementCounter("payment.charge_authorization.failed")
+
+		payment.Status = models.PaymentStatusFailed
+		ps.paymentRepo.UpdatePayment(ctx, payment)
+
+		return fmt.Errorf("failed to charge authorization with Paystack: %w", err)
+	}
+
+	recordVerification(payment, paystackResp.Data)
+	switch paystackResp.Data.Status {
+	case "success":
+		payment.Status = models.PaymentStatusVerified
+	case "failed", "abandoned", "reversed":
+		payment.Status = models.PaymentStatusFailed
+	default:
+		payment.Status = models.PaymentStatusPending
+	}
+
+	if err := ps.paymentRepo.UpdatePayment(ctx, payment); err != nil {
+		log.Printf("[ERROR] Failed to update charged payment: %v (payment_id: %s)", err, payment.PaymentID)
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	if payment.Status == models.PaymentStatusVerified {
+		savePaymentMethod(ctx, ps.methodRepo, payment)
+		if err := ps.emitPaymentVerifiedEvent(ctx, payment); err != nil {
+			log.Printf("[ERROR] Failed to emit PaymentVerified event: %v (payment_id: %s)",
+				err, payment.PaymentID)
+		}
+	}
+	return nil
+}
+
+// replayCharge returns the charge an idempotency key was first used for, or
+// ErrPaymentInProgress while it is still being made
+func (ps *PaymentService) replayCharge(ctx context.Context, idempotencyKey string, req *dto.ChargeAuthorizationRequest) (*dto.ChargeAuthorizationResponse, error) {
+	exists, originalID, err := ps.idempotencyRepo.CheckIdempotencyKey(ctx, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrPaymentInProgress
+	}
+
+	original, err := ps.paymentRepo.GetPaymentByID(ctx, originalID)
+	if err != nil || original.Status == models.PaymentStatusInitiated {
+		metrics.IncrementCounter("payment.charge_authorization.in_progress")
+		return nil, ErrPaymentInProgress
+	}
+	if original.UserID != req.UserID.String() || original.GoalID != req.GoalID.String() || original.Amount != req.Amount {
+		metrics.IncrementCounter("payment.charge_authorization.key_reused")
+		return nil, ErrIdempotencyKeyReused
+	}
+
+	metrics.IncrementCounter("payment.charge_authorization.replayed")
+	return chargeResponse(original), nil
+}
+
+// chargeResponse describes the outcome of an authorization charge
+func chargeResponse(payment *models.Payment) *dto.ChargeAuthorizationResponse {
+	return &dto.ChargeAuthorizationResponse{
+		PaymentID:       payment.PaymentID,
+		Reference:       payment.PaystackReference,
+		Status:          string(payment.Status),
+		GatewayResponse: paystackField(payment, "gateway_response"),
+	}
+}
+
+// CompleteMockPayment records a verified payment without going through Paystack and emits
+// PaymentVerified, exactly as a successful verification would. It backs the mock provider
+// test hook and must never be reachable in production.
+func (ps *PaymentService) CompleteMockPayment(ctx context.Context, req *dto.CompleteMockPaymentRequest) (*dto.VerifyPaymentResponse, error) {
+	closed, err := ps.goalStateRepo.IsGoalClosed(ctx, req.GoalID.String())
+	if err != nil {
+		return nil, err
+	}
+	if closed {
+		return nil, ErrGoalTargetReached
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = "NGN"
+	}
+
+	payment := &models.Payment{
+		PaymentID:         uuid.New().String(),
+		PaystackReference: fmt.Sprintf("MOCK-%s", uuid.New().String()[:13]),
+		UserID:            req.UserID.String(),
+		GoalID:            req.GoalID.String(),
+		Amount:            req.Amount,
+		Currency:          currency,
+		Status:            models.PaymentStatusVerified,
+	}
+	if req.ContributionID != nil {
+		payment.ContributionID = req.ContributionID.String()
+	}
+	paystackRecord(payment).Verification = map[string]interface{}{
+		"status":  "success",
+		"channel": "mock",
+		"paid_at": time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if err := ps.paymentRepo.CreatePayment(ctx, payment); err != nil {
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	if err := ps.emitPaymentVerifiedEvent(ctx, payment); err != nil {
+		log.Printf("[ERROR] Failed to emit PaymentVerified event: %v (payment_id: %s)",
+			err, payment.PaymentID)
+	}
+
+	metrics.IncrementCounter("payment.mock.completed")
+
+	log.Printf("[INFO] Mock payment completed (payment_id: %s, goal_id: %s, amount: %d)",
+		payment.PaymentID, payment.GoalID, payment.Amount)
+
+	return ps.mapPaymentToVerifyResponse(payment), nil
+}
+
 // GetPaymentStatus retrieves the current status of a payment
 func (ps *PaymentService) GetPaymentStatus(ctx context.Context, paymentID string) (*dto.PaymentStatusResponse, error) {
 	payment, err := ps.paymentRepo.GetPaymentByID(ctx, paymentID)
@@ -212,14 +650,159 @@ func (ps *PaymentService) GetPaymentStatus(ctx context.Context, paymentID string
 	return ps.mapPaymentToStatusResponse(payment), nil
 }
 
-// ListBanks retrieves the list of supported banks
-func (ps *PaymentService) ListBanks(ctx context.Context, country string) ([]dto.Bank, error) {
+// ListUserPayments returns a page of the user's own payments, optionally in one status
+func (ps *PaymentService) ListUserPayments(ctx context.Context, userID, status string, page, pageSize int) (*dto.PaymentListResponse, error) {
+	paymentStatus, err := parsePaymentStatus(status)
+	if err != nil {
+		return nil, err
+	}
+	page, pageSize = normalizePage(page, pageSize)
+	limit, offset := int64(pageSize), int64((page-1)*pageSize)
+
+	payments, err := ps.paymentRepo.ListPaymentsByUser(ctx, userID, paymentStatus, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	total, err := ps.paymentRepo.CountPaymentsByUser(ctx, userID, paymentStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.PaymentListResponse{
+		Items:    make([]*dto.PaymentStatusResponse, 0, len(payments)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for _, payment := range payments {
+		resp.Items = append(resp.Items, ps.mapPaymentToStatusResponse(payment))
+	}
+	return resp, nil
+}
+
+// ListGoalPayments returns a page of the payments made to a goal, optionally in one
+// status. Only the goal owner may list them; ownership is checked with goals-service.
+func (ps *PaymentService) ListGoalPayments(ctx context.Context, userID, goalID, status string, page, pageSize int) (*dto.PaymentListResponse, error) {
+	paymentStatus, err := parsePaymentStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID, err := ps.goalsClient.GetGoalOwner(goalID)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID != userID {
+		return nil, ErrNotGoalOwner
+	}
+
+	page, pageSize = normalizePage(page, pageSize)
+	limit, offset := int64(pageSize), int64((page-1)*pageSize)
+
+	payments, err := ps.paymentRepo.ListPaymentsByGoal(ctx, goalID, paymentStatus, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	total, err := ps.paymentRepo.CountPaymentsByGoal(ctx, goalID, paymentStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.PaymentListResponse{
+		Items:    make([]*dto.PaymentStatusResponse, 0, len(payments)),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for _, payment := range payments {
+		item := ps.mapPaymentToStatusResponse(payment)
+		// The Paystack data and app metadata identify the payer (customer email, card,
+		// user ID), which the goal owner must not see for anonymous contributions
+		item.Metadata = nil
+		item.AppMetadata = nil
+		resp.Items = append(resp.Items, item)
+	}
+	return resp, nil
+}
+
+// checkoutGoal fetches the goal a payment is for. It normalizes the payment's currency and
+// checks that it is supported and is the goal's currency, so a goal never collects amounts
+// in two different units; the goal is returned with that currency.
+func (ps *PaymentService) checkoutGoal(goalID, currency string) (*GoalInfo, error) {
+	currency, err := validator.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	goal, err := ps.goalsClient.GetGoal(goalID)
+	if err != nil {
+		return nil, err
+	}
+	if !validator.SameCurrency(goal.Currency, currency) {
+		return nil, fmt.Errorf("%w: goal accepts %s, got %s", ErrCurrencyMismatch, goal.Currency, currency)
+	}
+	goal.Currency = currency
+	return goal, nil
+}
+
+// parsePaymentStatus validates a status filter; an empty value matches every status
+func parsePaymentStatus(value string) (models.PaymentStatus, error) {
+	switch status := models.PaymentStatus(strings.ToUpper(value)); status {
+	case "", models.PaymentStatusInitiated, models.PaymentStatusPending,
+		models.PaymentStatusVerified, models.PaymentStatusFailed:
+		return status, nil
+	default:
+		return "", ErrInvalidPaymentStatus
+	}
+}
+
+// normalizePage applies the default and maximum page size to a listing request
+func normalizePage(page, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPaymentsPageSize
+	}
+	if pageSize > maxPaymentsPageSize {
+		pageSize = maxPaymentsPageSize
+	}
+	return page, pageSize
+}
+
+// ListBanks retrieves the list of supported banks, from the cache unless it has expired or
+// forceRefresh is set. If Paystack cannot be reached, an expired list is served instead.
+func (ps *PaymentService) ListBanks(ctx context.Context, country string, forceRefresh bool) ([]dto.Bank, error) {
 	if country == "" {
 		country = "nigeria" // Default to Nigeria
 	}
+	cacheKey := "paystack_banks:" + country
+
+	if !forceRefresh {
+		if banks, ok := ps.bankCache.get(country); ok {
+			metrics.TrackCacheHit(cacheKey)
+			return banks, nil
+		}
+	}
+
+	ps.bankCache.refresh.Lock()
+	defer ps.bankCache.refresh.Unlock()
+
+	// Another request may have fetched the list while we waited
+	if !forceRefresh {
+		if banks, ok := ps.bankCache.get(country); ok {
+			metrics.TrackCacheHit(cacheKey)
+			return banks, nil
+		}
+	}
+	metrics.TrackCacheMiss(cacheKey)
 
 	paystackResp, err := ps.paystackClient.ListBanks(country)
 	if err != nil {
+		if banks, ok := ps.bankCache.stale(country); ok {
+			log.Printf("[WARN] Failed to refresh banks, serving cached list: %v (country: %s)", err, country)
+			return banks, nil
+		}
 		log.Printf("[ERROR] Failed to list banks: %v (country: %s)", err, country)
 		return nil, fmt.Errorf("failed to list banks: %w", err)
 	}
@@ -235,12 +818,31 @@ func (ps *PaymentService) ListBanks(ctx context.Context, country string) ([]dto.
 			})
 		}
 	}
+	ps.bankCache.set(country, banks)
 
 	return banks, nil
 }
 
+// WarmBankCache fetches the default bank list so the first requests are served from the cache
+func (ps *PaymentService) WarmBankCache(ctx context.Context) {
+	banks, err := ps.ListBanks(ctx, "nigeria", true)
+	if err != nil {
+		log.Printf("[WARN] Failed to pre-warm bank cache: %v", err)
+		return
+	}
+	log.Printf("[INFO] Bank cache warmed (country: nigeria, count: %d)", len(banks))
+}
+
 // ResolveAccount resolves an account number to get account name
 func (ps *PaymentService) ResolveAccount(ctx context.Context, req *dto.ResolveAccountRequest) (*dto.ResolveAccountResponse, error) {
+	if req.BankCode == "" {
+		bankCode, err := ps.bankCodeByName(ctx, req.BankName)
+		if err != nil {
+			return nil, err
+		}
+		req.BankCode = bankCode
+	}
+
 	paystackResp, err := ps.paystackClient.ResolveAccountNumber(req.AccountNumber, req.BankCode)
 	if err != nil {
 		log.Printf("[ERROR] Failed to resolve account: %v (account: %s, bank: %s)",
@@ -249,7 +851,7 @@ func (ps *PaymentService) ResolveAccount(ctx context.Context, req *dto.ResolveAc
 	}
 
 	// Get bank name from bank list
-	banks, _ := ps.ListBanks(ctx, "nigeria")
+	banks, _ := ps.ListBanks(ctx, "nigeria", false)
 	bankName := ""
 	for _, bank := range banks {
 		if bank.Code == req.BankCode {
@@ -266,22 +868,41 @@ func (ps *PaymentService) ResolveAccount(ctx context.Context, req *dto.ResolveAc
 	}, nil
 }
 
+// bankCodeByName finds the code of a bank in the cached bank list by name, ignoring case
+func (ps *PaymentService) bankCodeByName(ctx context.Context, bankName string) (string, error) {
+	banks, err := ps.ListBanks(ctx, "nigeria", false)
+	if err != nil {
+		return "", fmt.Errorf("failed to list banks: %w", err)
+	}
+
+	name := strings.TrimSpace(bankName)
+	for _, bank := range banks {
+		if strings.EqualFold(bank.Name, name) {
+			return bank.Code, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownBank, bankName)
+}
+
 // emitPaymentVerifiedEvent emits a PaymentVerified event
-func (ps *PaymentService) emitPaymentVerifiedEvent(payment *models.Payment) error {
+func (ps *PaymentService) emitPaymentVerifiedEvent(ctx context.Context, payment *models.Payment) error {
 	event := events.PaymentVerified{
-		ID:        uuid.New().String(),
-		PaymentID: payment.PaymentID,
-		UserID:    payment.UserID,
-		GoalID:    payment.GoalID,
-		Amount:    payment.Amount,
-		CreatedAt: time.Now().Unix(),
+		ID:             uuid.New().String(),
+		PaymentID:      payment.PaymentID,
+		UserID:         payment.UserID,
+		GoalID:         payment.GoalID,
+		ContributionID: payment.ContributionID,
+		Amount:         payment.Amount,
+		Currency:       payment.Currency,
+		CreatedAt:      time.Now().Unix(),
 	}
+	event.AuthorizationCode, event.AuthorizationEmail = reusableAuthorization(payment)
 
-	if err := ps.eventPublisher.Publish("PaymentVerified", event); err != nil {
+	if err := ps.eventPublisher.PublishContext(ctx, "PaymentVerified", event); err != nil {
 		return fmt.Errorf("failed to publish event: %w", err)
 	}
 
-	log.Printf("[INFO] PaymentVerified event emitted (event_id: %s, payment_id: %s, user_id: %s, goal_id: %s, amount: %d)",
+	logger.Printf(ctx, "[INFO] PaymentVerified event emitted (event_id: %s, payment_id: %s, user_id: %s, goal_id: %s, amount: %d)",
 		event.ID, payment.PaymentID, payment.UserID, payment.GoalID, payment.Amount)
 
 	return nil
@@ -295,17 +916,16 @@ func (ps *PaymentService) mapPaymentToVerifyResponse(payment *models.Payment) *d
 		Status:    string(payment.Status),
 		Amount:    payment.Amount,
 		Currency:  payment.Currency,
-		Metadata:  payment.PaystackData,
+		Channel:   paystackField(payment, "channel"),
+		Metadata:  paystackSummary(payment),
+	}
+	if payment.Paystack != nil {
+		resp.AppMetadata = payment.Paystack.AppMetadata
 	}
 
 	// Extract paid_at if available
-	if payment.PaystackData != nil {
-		if paidAt, ok := payment.PaystackData["paid_at"].(string); ok && paidAt != "" {
-			resp.PaidAt = &paidAt
-		}
-		if channel, ok := payment.PaystackData["channel"].(string); ok {
-			resp.Channel = channel
-		}
+	if paidAt := paystackField(payment, "paid_at"); paidAt != "" {
+		resp.PaidAt = &paidAt
 	}
 
 	return resp
@@ -321,14 +941,15 @@ func (ps *PaymentService) mapPaymentToStatusResponse(payment *models.Payment) *d
 		Currency:  payment.Currency,
 		CreatedAt: payment.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: payment.UpdatedAt.Format(time.RFC3339),
-		Metadata:  payment.PaystackData,
+		Metadata:  paystackSummary(payment),
+	}
+	if payment.Paystack != nil {
+		resp.AppMetadata = payment.Paystack.AppMetadata
 	}
 
 	// Extract paid_at if available
-	if payment.PaystackData != nil {
-		if paidAt, ok := payment.PaystackData["paid_at"].(string); ok && paidAt != "" {
-			resp.PaidAt = &paidAt
-		}
+	if paidAt := paystackField(payment, "paid_at"); paidAt != "" {
+		resp.PaidAt = &paidAt
 	}
 
 	return resp
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofund/payments-service/internal/dto"
+	"github.com/gofund/payments-service/internal/repository"
+	"github.com/gofund/payments-service/internal/testmongo"
+	"github.com/gofund/shared/events"
+	"github.com/google/uuid"
+)
+
+func TestInitializePaymentRefusedAfterGoalClosedEarly(t *testing.T) {
+	db := testmongo.Open(t)
+	payments := NewPaymentService(repository.NewPaymentRepository(db), nil, repository.NewGoalStateRepository(db), nil, nil, nil, &recordingPublisher{}, 0, nil)
+	ctx := context.Background()
+	goalID := uuid.New()
+
+	data, err := json.Marshal(events.GoalClosedEarly{
+		ID:                     uuid.NewString(),
+		GoalID:                 goalID.String(),
+		PendingContributionIDs: []string{uuid.NewString()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := payments.HandleGoalClosedEarly(ctx, data); err != nil {
+		t.Fatalf("HandleGoalClosedEarly: %v", err)
+	}
+	// A redelivered event changes nothing
+	if err := payments.HandleGoalClosedEarly(ctx, data); err != nil {
+		t.Fatalf("HandleGoalClosedEarly redelivered: %v", err)
+	}
+
+	_, err = payments.InitializePayment(ctx, &dto.InitializePaymentRequest{
+		UserID:   uuid.New(),
+		GoalID:   goalID,
+		Amount:   10_000,
+		Currency: "NGN",
+		Email:    "contributor@example.com",
+	})
+	if !errors.Is(err, ErrGoalTargetReached) {
+		t.Fatalf("InitializePayment for a goal closed on target: err = %v, want %v", err, ErrGoalTargetReached)
+	}
+}
+
+// TestInitializePaymentCarriesContributionID checks the contribution intent goals-service
+// validated reaches Paystack's metadata and is stored on the payment
+func TestInitializePaymentCarriesContributionID(t *testing.T) {
+	db := testmongo.Open(t)
+
+	goals := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(GoalInfo{OwnerID: uuid.NewString(), Currency: "NGN", FeeMode: "absorb"})
+	}))
+	t.Cleanup(goals.Close)
+
+	var sent dto.PaystackInitializeRequest
+	paystack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Errorf("failed to decode the Paystack request: %v", err)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": true,
+			"data":   map[string]string{"authorization_url": "https://checkout.paystack.test/abc", "access_code": "abc", "reference": sent.Reference},
+		})
+	}))
+	t.Cleanup(paystack.Close)
+
+	paymentRepo := repository.NewPaymentRepository(db)
+	payments := NewPaymentService(paymentRepo, repository.NewIdempotencyRepository(db), repository.NewGoalStateRepository(db), nil,
+		NewPaystackClient("sk_test", paystack.URL, PaystackRetryPolicy{}), NewGoalsClient(goals.URL), &recordingPublisher{}, 0, nil)
+
+	contributionID := uuid.New()
+	resp, err := payments.InitializePayment(context.Background(), &dto.InitializePaymentRequest{
+		UserID:         uuid.New(),
+		GoalID:         uuid.New(),
+		ContributionID: &contributionID,
+		Amount:         5_000,
+		Currency:       "NGN",
+		Email:          "contributor@example.com",
+		Metadata:       map[string]interface{}{"contribution_id": "spoofed"},
+	})
+	if err != nil {
+		t.Fatalf("InitializePayment: %v", err)
+	}
+
+	if got := sent.Metadata["contribution_id"]; got != contributionID.String() {
+		t.Errorf("Paystack metadata contribution_id = %v, want %s", got, contributionID)
+	}
+	payment, err := paymentRepo.GetPaymentByID(context.Background(), resp.PaymentID)
+	if err != nil {
+		t.Fatalf("GetPaymentByID: %v", err)
+	}
+	if payment.ContributionID != contributionID.String() {
+		t.Errorf("stored contribution ID = %q, want %s", payment.ContributionID, contributionID)
+	}
+}
+
+func TestCheckoutGoalCurrency(t *testing.T) {
+	goalCurrency := "NGN"
+	goals := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(GoalInfo{OwnerID: uuid.NewString(), Currency: goalCurrency})
+	}))
+	t.Cleanup(goals.Close)
+	payments := NewPaymentService(nil, nil, nil, nil, nil, NewGoalsClient(goals.URL), &recordingPublisher{}, 0, nil)
+
+	tests := []struct {
+		name         string
+		goalCurrency string
+		currency     string
+		want         string
+		wantErr      error
+	}{
+		{name: "goal's currency", goalCurrency: "NGN", currency: "NGN", want: "NGN"},
+		{name: "lower case", goalCurrency: "NGN", currency: "ngn", want: "NGN"},
+		{name: "defaulted", goalCurrency: "NGN", currency: "", want: "NGN"},
+		{name: "unsupported", goalCurrency: "NGN", currency: "USD", wantErr: ErrUnsupportedCurrency},
+		{name: "supported but not the goal's", goalCurrency: "GHS", currency: "NGN", wantErr: ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goalCurrency = tt.goalCurrency
+			goal, err := payments.checkoutGoal(uuid.NewString(), tt.currency)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkoutGoal: %v", err)
+			}
+			if goal.Currency != tt.want {
+				t.Errorf("currency = %s, want %s", goal.Currency, tt.want)
+			}
+		})
+	}
+}
+
+// TestInitializePaymentRejectsCurrencyMismatch pays an NGN amount towards a goal in another
+// currency; nothing reaches Paystack and no payment is stored
+func TestInitializePaymentRejectsCurrencyMismatch(t *testing.T) {
+	db := testmongo.Open(t)
+
+	goals := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(GoalInfo{OwnerID: uuid.NewString(), Currency: "GHS", FeeMode: "absorb"})
+	}))
+	t.Cleanup(goals.Close)
+	paystackCalls := 0
+	paystack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paystackCalls++
+		http.Error(w, "unexpected call", http.StatusInternalServerError)
+	}))
+	t.Cleanup(paystack.Close)
+
+	paymentRepo := repository.NewPaymentRepository(db)
+	payments := NewPaymentService(paymentRepo, repository.NewIdempotencyRepository(db), repository.NewGoalStateRepository(db), nil,
+		NewPaystackClient("sk_test", paystack.URL, PaystackRetryPolicy{}), NewGoalsClient(goals.URL), &recordingPublisher{}, 0, nil)
+
+	userID := uuid.New()
+	_, err := payments.InitializePayment(context.Background(), &dto.InitializePaymentRequest{
+		UserID:   userID,
+		GoalID:   uuid.New(),
+		Amount:   5_000,
+		Currency: "NGN",
+		Email:    "contributor@example.com",
+	})
+	if !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("err = %v, want %v", err, ErrCurrencyMismatch)
+	}
+	if paystackCalls != 0 {
+		t.Errorf("Paystack was called %d times", paystackCalls)
+	}
+	stored, err := paymentRepo.CountPaymentsByUser(context.Background(), userID.String(), "")
+	if err != nil {
+		t.Fatalf("CountPaymentsByUser: %v", err)
+	}
+	if stored != 0 {
+		t.Errorf("%d payments were stored", stored)
+	}
+}
@@ -0,0 +1,445 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofund/payments-service/internal/dto"
+	"github.com/gofund/payments-service/internal/repository"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/logger"
+	"github.com/gofund/shared/messaging"
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// maxFailureReasonLength matches the size of the failure reason column in goals-service
+const maxFailureReasonLength = 255
+
+// PayoutService pays out goal withdrawals and refund disbursements. It consumes
+// WithdrawalRequested and RefundInitiated, sends the money with Paystack transfers and
+// reports the outcome of the transfer webhooks back to goals-service.
+type PayoutService struct {
+	transferRepo              *repository.TransferRepository
+	transferService           *TransferService
+	refundDisbursementService *RefundDisbursementService
+	eventPublisher            messaging.Publisher
+}
+
+// NewPayoutService creates a new payout service
+func NewPayoutService(
+	transferRepo *repository.TransferRepository,
+	transferService *TransferService,
+	refundDisbursementService *RefundDisbursementService,
+	eventPublisher messaging.Publisher,
+) *PayoutService {
+	return &PayoutService{
+		transferRepo:              transferRepo,
+		transferService:           transferService,
+		refundDisbursementService: refundDisbursementService,
+		eventPublisher:            eventPublisher,
+	}
+}
+
+// withdrawalReference is the transfer reference for a withdrawal. It is derived from the
+// withdrawal ID so a redelivered request maps to the transfer already made for it.
+func withdrawalReference(withdrawalID string) string {
+	return fmt.Sprintf("WITHDRAWAL-%s", withdrawalID)
+}
+
+// HandleWithdrawalRequested starts the payout for a withdrawal
+func (ps *PayoutService) HandleWithdrawalRequested(ctx context.Context, data []byte) error {
+	var event events.WithdrawalRequested
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal WithdrawalRequested event: %w", err)
+	}
+
+	transfer := &models.Transfer{
+		Reference:     withdrawalReference(event.WithdrawalID),
+		Purpose:       models.TransferPurposeWithdrawal,
+		SourceID:      event.WithdrawalID,
+		GoalID:        event.GoalID,
+		OwnerID:       event.OwnerID,
+		GoalTitle:     event.GoalTitle,
+		Amount:        event.Amount,
+		Currency:      event.Currency,
+		BankCode:      event.BankCode,
+		AccountNumber: event.AccountNumber,
+		AccountName:   event.AccountName,
+		Status:        models.TransferStatusPending,
+	}
+
+	transfer, sent, err := ps.recordTransfer(ctx, transfer)
+	if err != nil {
+		return err
+	}
+	if sent {
+		log.Printf("[INFO] Withdrawal already paid out, skipping (withdrawal_id: %s, reference: %s)",
+			event.WithdrawalID, withdrawalReference(event.WithdrawalID))
+		return nil
+	}
+
+	if transfer.BankCode == "" {
+		bankCode, err := ps.transferService.ResolveBankCode(event.BankName)
+		if err != nil {
+			if errors.Is(err, ErrUnknownBank) {
+				return ps.failTransfer(ctx, transfer, err.Error())
+			}
+			return err
+		}
+		transfer.BankCode = bankCode
+	}
+
+	err = ps.sendTransfer(ctx, transfer, func() (*dto.TransferResponse, error) {
+		return ps.transferService.Transfer(&dto.TransferRequest{
+			Reference:     transfer.Reference,
+			Amount:        transfer.Amount,
+			Currency:      transfer.Currency,
+			BankCode:      transfer.BankCode,
+			AccountNumber: transfer.AccountNumber,
+			AccountName:   transfer.AccountName,
+			Reason:        fmt.Sprintf("Withdrawal from %s", event.GoalTitle),
+		})
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to start withdrawal transfer: %v (withdrawal_id: %s)", err, event.WithdrawalID)
+		return err
+	}
+	if transfer.Status != models.TransferStatusPending {
+		return nil
+	}
+
+	metrics.IncrementCounter("payout.withdrawal.initiated")
+	log.Printf("[INFO] Withdrawal transfer initiated (withdrawal_id: %s, transfer_code: %s, amount: %d)",
+		event.WithdrawalID, transfer.TransferCode, transfer.Amount)
+	return nil
+}
+
+// HandleRefundInitiated pays out every disbursement of a refund. Each disbursement is its
+// own transfer, so one that cannot be paid does not hold up the others.
+func (ps *PayoutService) HandleRefundInitiated(ctx context.Context, data []byte) error {
+	var event events.RefundInitiated
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal RefundInitiated event: %w", err)
+	}
+
+	log.Printf("[INFO] Paying out refund (refund_id: %s, disbursements: %d)", event.RefundID, len(event.Disbursements))
+
+	for _, item := range event.Disbursements {
+		if err := ps.payRefundDisbursement(ctx, event, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// payRefundDisbursement starts the transfer for one refund disbursement
+func (ps *PayoutService) payRefundDisbursement(ctx context.Context, event events.RefundInitiated, item events.RefundDisbursementItem) error {
+	disbursementID, err := uuid.Parse(item.DisbursementID)
+	if err != nil {
+		log.Printf("[ERROR] Invalid disbursement ID in RefundInitiated event (refund_id: %s, disbursement_id: %s)",
+			event.RefundID, item.DisbursementID)
+		return nil
+	}
+	userID, _ := uuid.Parse(item.UserID)
+
+	transfer := &models.Transfer{
+		Reference:     fmt.Sprintf("REFUND-%s", disbursementID),
+		Purpose:       models.TransferPurposeRefund,
+		SourceID:      item.DisbursementID,
+		RefundID:      event.RefundID,
+		GoalID:        event.GoalID,
+		UserID:        item.UserID,
+		Amount:        item.Amount,
+		Currency:      item.Currency,
+		AccountNumber: item.AccountNumber,
+		AccountName:   item.AccountName,
+		Status:        models.TransferStatusPending,
+	}
+
+	transfer, sent, err := ps.recordTransfer(ctx, transfer)
+	if err != nil {
+		return err
+	}
+	if sent {
+		log.Printf("[INFO] Refund disbursement already paid out, skipping (disbursement_id: %s, reference: %s)",
+			item.DisbursementID, fmt.Sprintf("REFUND-%s", disbursementID))
+		return nil
+	}
+
+	if item.AccountNumber == "" {
+		return ps.failTransfer(ctx, transfer, "contributor has no settlement account")
+	}
+
+	bankCode, err := ps.transferService.ResolveBankCode(item.BankName)
+	if err != nil {
+		if errors.Is(err, ErrUnknownBank) {
+			return ps.failTransfer(ctx, transfer, err.Error())
+		}
+		return err
+	}
+	transfer.BankCode = bankCode
+
+	err = ps.sendTransfer(ctx, transfer, func() (*dto.TransferResponse, error) {
+		resp, err := ps.refundDisbursementService.InitiateDisbursement(&dto.DisbursementRequest{
+			DisbursementID: disbursementID,
+			UserID:         userID,
+			Amount:         item.Amount,
+			Currency:       item.Currency,
+			BankCode:       bankCode,
+			AccountNumber:  item.AccountNumber,
+			AccountName:    item.AccountName,
+			Reason:         "GoFund contribution refund",
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &dto.TransferResponse{TransferCode: resp.TransferCode, Reference: resp.Reference}, nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to start refund disbursement: %v (disbursement_id: %s)", err, item.DisbursementID)
+		return err
+	}
+	if transfer.Status != models.TransferStatusPending {
+		return nil
+	}
+
+	metrics.IncrementCounter("payout.refund.initiated")
+	return nil
+}
+
+// recordTransfer records a payout before it is sent to Paystack. When an earlier delivery
+// of the same request recorded it, the earlier record is returned instead so a payout that
+// never reached Paystack is resumed rather than skipped; sent reports that the earlier
+// delivery got a transfer code from Paystack or settled the payout, so there is nothing
+// left to do.
+func (ps *PayoutService) recordTransfer(ctx context.Context, transfer *models.Transfer) (*models.Transfer, bool, error) {
+	err := ps.transferRepo.CreateTransfer(ctx, transfer)
+	if err == nil {
+		return transfer, false, nil
+	}
+	if !errors.Is(err, repository.ErrTransferExists) {
+		return nil, false, err
+	}
+
+	existing, err := ps.transferRepo.GetTransferByReference(ctx, transfer.Reference)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing == nil || existing.Status != models.TransferStatusPending || existing.TransferCode != "" {
+		return nil, true, nil
+	}
+
+	// The earlier delivery may have reached Paystack before it lost track of the outcome
+	found, err := ps.transferService.FindTransfer(existing.Reference)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to look up transfer %s: %w", existing.Reference, err)
+	}
+	if found != nil {
+		existing.TransferCode = found.TransferCode
+		return nil, true, ps.transferRepo.UpdateTransfer(ctx, existing)
+	}
+
+	log.Printf("[INFO] Resuming payout that never reached Paystack (reference: %s)", existing.Reference)
+	metrics.IncrementCounter("payout.transfer.resumed", "purpose:"+string(existing.Purpose))
+	return existing, false, nil
+}
+
+// sendTransfer hands a recorded payout to Paystack with send and saves the codes it
+// returns; the outcome then arrives with the transfer.success or transfer.failed webhook.
+// Only a definitive rejection fails the payout. After any other error it is unknown whether
+// Paystack made the transfer, so it is looked up by reference: one Paystack has is kept,
+// and one it lacks stays pending without a transfer code for a redelivered request to
+// resume.
+func (ps *PayoutService) sendTransfer(ctx context.Context, transfer *models.Transfer, send func() (*dto.TransferResponse, error)) error {
+	resp, err := send()
+	if err != nil {
+		if errors.Is(err, ErrTransferRejected) {
+			return ps.failTransfer(ctx, transfer, err.Error())
+		}
+
+		found, findErr := ps.transferService.FindTransfer(transfer.Reference)
+		if findErr != nil || found == nil {
+			metrics.IncrementCounter("payout.transfer.unresolved", "purpose:"+string(transfer.Purpose))
+			return fmt.Errorf("transfer %s left pending, outcome unknown: %w", transfer.Reference, err)
+		}
+		log.Printf("[INFO] Transfer found after an ambiguous error (reference: %s, error: %v)", transfer.Reference, err)
+		resp = found
+	}
+
+	if resp.RecipientCode != "" {
+		transfer.RecipientCode = resp.RecipientCode
+	}
+	transfer.TransferCode = resp.TransferCode
+	return ps.transferRepo.UpdateTransfer(ctx, transfer)
+}
+
+// IsTransferPending reports whether the reference belongs to a payout still awaiting its
+// webhook
+func (ps *PayoutService) IsTransferPending(ctx context.Context, reference string) (bool, error) {
+	transfer, err := ps.transferRepo.GetTransferByReference(ctx, reference)
+	if err != nil || transfer == nil {
+		return false, err
+	}
+	return transfer.Status == models.TransferStatusPending, nil
+}
+
+// HandleTransferSuccess settles the transfer named by a transfer.success webhook. It
+// reports whether the reference belongs to a payout this service made.
+func (ps *PayoutService) HandleTransferSuccess(ctx context.Context, reference string) (bool, error) {
+	transfer, err := ps.transferRepo.GetTransferByReference(ctx, reference)
+	if err != nil || transfer == nil {
+		return false, err
+	}
+
+	if transfer.Status == models.TransferStatusSuccess {
+		return true, nil
+	}
+
+	transfer.Status = models.TransferStatusSuccess
+	transfer.FailureReason = ""
+	if err := ps.transferRepo.UpdateTransfer(ctx, transfer); err != nil {
+		return true, err
+	}
+
+	metrics.IncrementCounter("payout.transfer.completed", "purpose:"+string(transfer.Purpose))
+	if transfer.Purpose == models.TransferPurposeRefund {
+		return true, ps.publishRefundDisbursementCompleted(ctx, transfer)
+	}
+	return true, ps.publishWithdrawalCompleted(ctx, transfer)
+}
+
+// HandleTransferFailed records the failure named by a transfer.failed or transfer.reversed
+// webhook. It reports whether the reference belongs to a payout this service made.
+func (ps *PayoutService) HandleTransferFailed(ctx context.Context, reference, reason string) (bool, error) {
+	transfer, err := ps.transferRepo.GetTransferByReference(ctx, reference)
+	if err != nil || transfer == nil {
+		return false, err
+	}
+
+	if transfer.Status == models.TransferStatusFailed {
+		return true, nil
+	}
+
+	return true, ps.failTransfer(ctx, transfer, reason)
+}
+
+// failTransfer marks a transfer failed and tells goals-service the payout did not go through
+func (ps *PayoutService) failTransfer(ctx context.Context, transfer *models.Transfer, reason string) error {
+	if len(reason) > maxFailureReasonLength {
+		reason = reason[:maxFailureReasonLength]
+	}
+
+	transfer.Status = models.TransferStatusFailed
+	transfer.FailureReason = reason
+	if err := ps.transferRepo.UpdateTransfer(ctx, transfer); err != nil {
+		return err
+	}
+
+	metrics.IncrementCounter("payout.transfer.failed", "purpose:"+string(transfer.Purpose))
+	if transfer.Purpose == models.TransferPurposeRefund {
+		return ps.publishRefundDisbursementFailed(ctx, transfer)
+	}
+	return ps.publishWithdrawalFailed(ctx, transfer)
+}
+
+// publishWithdrawalCompleted emits a WithdrawalCompleted event
+func (ps *PayoutService) publishWithdrawalCompleted(ctx context.Context, transfer *models.Transfer) error {
+	event := events.WithdrawalCompleted{
+		ID:                uuid.New().String(),
+		WithdrawalID:      transfer.SourceID,
+		GoalID:            transfer.GoalID,
+		OwnerID:           transfer.OwnerID,
+		GoalTitle:         transfer.GoalTitle,
+		Amount:            transfer.Amount,
+		Currency:          transfer.Currency,
+		TransferReference: transfer.Reference,
+		TransferCode:      transfer.TransferCode,
+		CompletedAt:       time.Now().Unix(),
+	}
+
+	if err := ps.eventPublisher.PublishContext(ctx, "WithdrawalCompleted", event); err != nil {
+		return fmt.Errorf("failed to publish WithdrawalCompleted event: %w", err)
+	}
+
+	logger.Printf(ctx, "[INFO] WithdrawalCompleted event emitted (withdrawal_id: %s, reference: %s)",
+		transfer.SourceID, transfer.Reference)
+	return nil
+}
+
+// publishWithdrawalFailed emits a WithdrawalFailed event
+func (ps *PayoutService) publishWithdrawalFailed(ctx context.Context, transfer *models.Transfer) error {
+	event := events.WithdrawalFailed{
+		ID:                uuid.New().String(),
+		WithdrawalID:      transfer.SourceID,
+		GoalID:            transfer.GoalID,
+		OwnerID:           transfer.OwnerID,
+		GoalTitle:         transfer.GoalTitle,
+		Amount:            transfer.Amount,
+		Currency:          transfer.Currency,
+		TransferReference: transfer.Reference,
+		Reason:            transfer.FailureReason,
+		CreatedAt:         time.Now().Unix(),
+	}
+
+	if err := ps.eventPublisher.PublishContext(ctx, "WithdrawalFailed", event); err != nil {
+		return fmt.Errorf("failed to publish WithdrawalFailed event: %w", err)
+	}
+
+	log.Printf("[INFO] WithdrawalFailed event emitted (withdrawal_id: %s, reason: %s)",
+		transfer.SourceID, transfer.FailureReason)
+	return nil
+}
+
+// publishRefundDisbursementCompleted emits a RefundDisbursementCompleted event
+func (ps *PayoutService) publishRefundDisbursementCompleted(ctx context.Context, transfer *models.Transfer) error {
+	event := events.RefundDisbursementCompleted{
+		ID:                uuid.New().String(),
+		RefundID:          transfer.RefundID,
+		DisbursementID:    transfer.SourceID,
+		GoalID:            transfer.GoalID,
+		UserID:            transfer.UserID,
+		Amount:            transfer.Amount,
+		Currency:          transfer.Currency,
+		TransferReference: transfer.Reference,
+		TransferCode:      transfer.TransferCode,
+		CompletedAt:       time.Now().Unix(),
+	}
+
+	if err := ps.eventPublisher.PublishContext(ctx, "RefundDisbursementCompleted", event); err != nil {
+		return fmt.Errorf("failed to publish RefundDisbursementCompleted event: %w", err)
+	}
+
+	log.Printf("[INFO] RefundDisbursementCompleted event emitted (disbursement_id: %s, reference: %s)",
+		transfer.SourceID, transfer.Reference)
+	return nil
+}
+
+// publishRefundDisbursementFailed emits a RefundDisbursementFailed event
+func (ps *PayoutService) publishRefundDisbursementFailed(ctx context.Context, transfer *models.Transfer) error {
+	event := events.RefundDisbursementFailed{
+		ID:                uuid.New().String(),
+		RefundID:          transfer.RefundID,
+		DisbursementID:    transfer.SourceID,
+		GoalID:            transfer.GoalID,
+		UserID:            transfer.UserID,
+		Amount:            transfer.Amount,
+		Currency:          transfer.Currency,
+		TransferReference: transfer.Reference,
+		Reason:            transfer.FailureReason,
+		CreatedAt:         time.Now().Unix(),
+	}
+
+	if err := ps.eventPublisher.PublishContext(ctx, "RefundDisbursementFailed", event); err != nil {
+		return fmt.Errorf("failed to publish RefundDisbursementFailed event: %w", err)
+	}
+
+	log.Printf("[INFO] RefundDisbursementFailed event emitted (disbursement_id: %s, reason: %s)",
+		transfer.SourceID, transfer.FailureReason)
+	return nil
+}
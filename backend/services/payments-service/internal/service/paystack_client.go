@@ -18,16 +18,22 @@ type PaystackClient struct {
 	secretKey string
 	baseURL   string
 	client    *http.Client
+	retry     PaystackRetryPolicy
+	breaker   *paystackBreaker
 }
 
-// NewPaystackClient creates a new Paystack API client
-func NewPaystackClient(secretKey, baseURL string) *PaystackClient {
+// NewPaystackClient creates a new Paystack API client. Unset fields of retry fall back to
+// DefaultPaystackRetryPolicy.
+func NewPaystackClient(secretKey, baseURL string, retry PaystackRetryPolicy) *PaystackClient {
+	retry = retry.withDefaults()
 	return &PaystackClient{
 		secretKey: secretKey,
 		baseURL:   baseURL,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retry:   retry,
+		breaker: newPaystackBreaker(retry.BreakerThreshold, retry.BreakerCooldown),
 	}
 }
 
@@ -57,7 +63,7 @@ func (pc *PaystackClient) InitializeTransaction(req *dto.PaystackInitializeReque
 
 	// Send request
 	startTime := time.Now()
-	resp, err := pc.client.Do(httpReq)
+	resp, err := pc.do(httpReq, "initialize")
 	duration := time.Since(startTime).Milliseconds()
 
 	// Track metrics
@@ -119,7 +125,7 @@ func (pc *PaystackClient) VerifyTransaction(reference string) (*dto.PaystackVeri
 
 	// Send request
 	startTime := time.Now()
-	resp, err := pc.client.Do(httpReq)
+	resp, err := pc.do(httpReq, "verify")
 	duration := time.Since(startTime).Milliseconds()
 
 	// Track metrics
@@ -163,6 +169,77 @@ func (pc *PaystackClient) VerifyTransaction(reference string) (*dto.PaystackVeri
 	return &paystackResp, nil
 }
 
+// ChargeAuthorization charges a saved card authorization. The transaction it returns may
+// still be pending; Paystack's webhook settles it then.
+func (pc *PaystackClient) ChargeAuthorization(req *dto.PaystackChargeAuthorizationRequest) (*dto.PaystackVerifyResponse, error) {
+	url := fmt.Sprintf("%s/transaction/charge_authorization", pc.baseURL)
+
+	// Marshal request body
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Create HTTP request
+	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", pc.secretKey))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	// Log request
+	log.Printf("[INFO] Charging Paystack authorization (reference: %s, amount: %d, email: %s)",
+		req.Reference, req.Amount, req.Email)
+
+	// Send request
+	startTime := time.Now()
+	resp, err := pc.do(httpReq, "charge_authorization")
+	duration := time.Since(startTime).Milliseconds()
+
+	// Track metrics
+	metrics.RecordHistogram("paystack.api.charge_authorization.duration", float64(duration))
+
+	if err != nil {
+		metrics.IncrementCounter("paystack.api.charge_authorization.error")
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Check status code
+	if resp.StatusCode != http.StatusOK {
+		metrics.IncrementCounter("paystack.api.charge_authorization.failed")
+		log.Printf("[ERROR] Paystack authorization charge failed (status: %d, response: %s)",
+			resp.StatusCode, string(respBody))
+		return nil, fmt.Errorf("paystack API error: status %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	// Parse response
+	var paystackResp dto.PaystackVerifyResponse
+	if err := json.Unmarshal(respBody, &paystackResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if !paystackResp.Status {
+		metrics.IncrementCounter("paystack.api.charge_authorization.failed")
+		return nil, fmt.Errorf("paystack authorization charge failed: %s", paystackResp.Message)
+	}
+
+	metrics.IncrementCounter("paystack.api.charge_authorization.success")
+	log.Printf("[INFO] Paystack authorization charged (reference: %s, status: %s, amount: %d)",
+		req.Reference, paystackResp.Data.Status, paystackResp.Data.Amount)
+
+	return &paystackResp, nil
+}
+
 // ListBanks retrieves the list of supported banks from Paystack
 func (pc *PaystackClient) ListBanks(country string) (*dto.PaystackBankListResponse, error) {
 	url := fmt.Sprintf("%s/bank?country=%s&perPage=100", pc.baseURL, country)
@@ -177,7 +254,7 @@ func (pc *PaystackClient) ListBanks(country string) (*dto.PaystackBankListRespon
 	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", pc.secretKey))
 
 	// Send request
-	resp, err := pc.client.Do(httpReq)
+	resp, err := pc.do(httpReq, "list_banks")
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -229,7 +306,7 @@ func (pc *PaystackClient) ResolveAccountNumber(accountNumber, bankCode string) (
 
 	// Send request
 	startTime := time.Now()
-	resp, err := pc.client.Do(httpReq)
+	resp, err := pc.do(httpReq, "resolve_account")
 	duration := time.Since(startTime).Milliseconds()
 
 	// Track metrics
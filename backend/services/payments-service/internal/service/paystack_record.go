@@ -0,0 +1,304 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// Metadata keys we set ourselves when initializing a transaction
+const (
+	metaPaymentID      = "payment_id"
+	metaUserID         = "user_id"
+	metaGoalID         = "goal_id"
+	metaContributionID = "contribution_id"
+	metaMilestoneID    = "milestone_id"
+)
+
+// paystackRecord returns the payment's Paystack record, creating it on first use
+func paystackRecord(payment *models.Payment) *models.PaystackRecord {
+	if payment.Paystack == nil {
+		payment.Paystack = &models.PaystackRecord{}
+	}
+	if payment.Paystack.AppMetadata == nil {
+		payment.Paystack.AppMetadata = &models.PaymentAppMetadata{
+			PaymentID:      payment.PaymentID,
+			UserID:         payment.UserID,
+			GoalID:         payment.GoalID,
+			ContributionID: payment.ContributionID,
+		}
+	}
+	return payment.Paystack
+}
+
+// recordVerification merges a verify response into the payment's verification section and
+// checks the metadata it carried
+func recordVerification(payment *models.Payment, data interface{}) {
+	section := toMap(data)
+	record := paystackRecord(payment)
+	if record.Verification == nil {
+		record.Verification = make(map[string]interface{})
+	}
+	for k, v := range section {
+		record.Verification[k] = v
+	}
+	reconcileAppMetadata(payment, section["metadata"], "verify")
+}
+
+// hasWebhookEvent reports whether an identical delivery was already recorded, so a
+// webhook Paystack retries after a failed update is not stored twice
+func hasWebhookEvent(payment *models.Payment, event string, data map[string]interface{}) bool {
+	if payment.Paystack == nil {
+		return false
+	}
+	for _, recorded := range payment.Paystack.WebhookEvents {
+		if recorded.Event == event && fmt.Sprint(recorded.Data["id"]) == fmt.Sprint(data["id"]) {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileAppMetadata checks the metadata Paystack returned (or that we are about to send)
+// against the fields we already hold. Stored values always win: a mismatch is logged and
+// counted, never written. Missing fields are filled in and unknown fields are kept as custom.
+func reconcileAppMetadata(payment *models.Payment, raw interface{}, source string) {
+	if raw == nil {
+		return
+	}
+
+	metadata, err := parseMetadata(raw)
+	if err != nil {
+		log.Printf("[WARN] Unreadable Paystack metadata: %v (payment_id: %s, source: %s)",
+			err, payment.PaymentID, source)
+		metrics.IncrementCounter("payment.metadata.invalid", "source:"+source)
+		return
+	}
+
+	app := paystackRecord(payment).AppMetadata
+	fields := map[string]*string{
+		metaPaymentID:      &app.PaymentID,
+		metaUserID:         &app.UserID,
+		metaGoalID:         &app.GoalID,
+		metaContributionID: &app.ContributionID,
+		metaMilestoneID:    &app.MilestoneID,
+	}
+
+	for key, value := range metadata {
+		stored, known := fields[key]
+		if !known {
+			if app.Custom == nil {
+				app.Custom = make(map[string]interface{})
+			}
+			if _, exists := app.Custom[key]; !exists {
+				app.Custom[key] = value
+			}
+			continue
+		}
+
+		id, err := metadataID(value)
+		if err != nil {
+			log.Printf("[WARN] Invalid %s in Paystack metadata: %v (payment_id: %s, source: %s)",
+				key, err, payment.PaymentID, source)
+			metrics.IncrementCounter("payment.metadata.invalid", "source:"+source)
+			continue
+		}
+		if id == "" {
+			continue
+		}
+
+		switch {
+		case *stored == "":
+			*stored = id
+		case *stored != id:
+			log.Printf("[WARN] Paystack metadata %s does not match the payment: got %s, kept %s (payment_id: %s, source: %s)",
+				key, id, *stored, payment.PaymentID, source)
+			metrics.IncrementCounter("payment.metadata.mismatch", "source:"+source, "field:"+key)
+		}
+	}
+}
+
+// parseMetadata accepts metadata in either shape Paystack returns it: an object, or the
+// JSON string it was sent as
+func parseMetadata(raw interface{}) (map[string]interface{}, error) {
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		var metadata map[string]interface{}
+		if err := json.Unmarshal([]byte(v), &metadata); err != nil {
+			return nil, fmt.Errorf("metadata string is not a JSON object: %w", err)
+		}
+		return metadata, nil
+	default:
+		return nil, fmt.Errorf("unexpected metadata type %T", raw)
+	}
+}
+
+// metadataID reads one of our ID fields. Paystack may hand numbers back as floats, so
+// whole floats are accepted and printed without an exponent; anything else must be a UUID.
+func metadataID(value interface{}) (string, error) {
+	var id string
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		id = v
+	case float64:
+		if v != float64(int64(v)) {
+			return "", fmt.Errorf("expected an ID, got %v", v)
+		}
+		id = strconv.FormatInt(int64(v), 10)
+	default:
+		return "", fmt.Errorf("expected a string, got %T", value)
+	}
+
+	if id == "" {
+		return "", nil
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return "", fmt.Errorf("expected a UUID, got %q", id)
+	}
+	return id, nil
+}
+
+// paystackSummary is the flat view of a payment's Paystack data returned as response
+// metadata: initialization details overlaid with the verification result, without the
+// card authorization
+func paystackSummary(payment *models.Payment) map[string]interface{} {
+	if payment.Paystack == nil {
+		return nil
+	}
+
+	summary := make(map[string]interface{})
+	for k, v := range payment.Paystack.Initialization {
+		summary[k] = v
+	}
+	for k, v := range payment.Paystack.Verification {
+		summary[k] = v
+	}
+	// A reusable authorization can charge the card again; it never leaves the service
+	delete(summary, "authorization")
+	if len(summary) == 0 {
+		return nil
+	}
+	return summary
+}
+
+// paystackField reads a string field from the verification section, falling back to the
+// most recent webhook that carried it
+func paystackField(payment *models.Payment, key string) string {
+	if payment.Paystack == nil {
+		return ""
+	}
+	if v, ok := payment.Paystack.Verification[key].(string); ok && v != "" {
+		return v
+	}
+	events := payment.Paystack.WebhookEvents
+	for i := len(events) - 1; i >= 0; i-- {
+		if v, ok := events[i].Data[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// paymentAuthorization returns the card authorization a payment was made with, and the
+// customer Paystack ties it to, from the verification or the most recent webhook that
+// carried one
+func paymentAuthorization(payment *models.Payment) (authorization, customer map[string]interface{}) {
+	if payment.Paystack == nil {
+		return nil, nil
+	}
+
+	sections := []map[string]interface{}{payment.Paystack.Verification}
+	events := payment.Paystack.WebhookEvents
+	for i := len(events) - 1; i >= 0; i-- {
+		sections = append(sections, events[i].Data)
+	}
+
+	for _, section := range sections {
+		authorization, _ := section["authorization"].(map[string]interface{})
+		if code, _ := authorization["authorization_code"].(string); code != "" {
+			customer, _ := section["customer"].(map[string]interface{})
+			return authorization, customer
+		}
+	}
+	return nil, nil
+}
+
+// reusableAuthorization returns the reusable card authorization a payment was made with
+// and the customer email Paystack ties it to. Both are empty when the card cannot be
+// charged again.
+func reusableAuthorization(payment *models.Payment) (code, email string) {
+	authorization, customer := paymentAuthorization(payment)
+	if reusable, _ := authorization["reusable"].(bool); !reusable {
+		return "", ""
+	}
+	code, _ = authorization["authorization_code"].(string)
+	email, _ = customer["email"].(string)
+	if email == "" {
+		return "", ""
+	}
+	return code, email
+}
+
+// savedPaymentMethod describes the card a payment was made with for the payer's saved
+// payment methods, or returns nil when Paystack reported no authorization
+func savedPaymentMethod(payment *models.Payment) *models.SavedPaymentMethod {
+	authorization, customer := paymentAuthorization(payment)
+	if authorization == nil {
+		return nil
+	}
+
+	field := func(key string) string {
+		v, _ := authorization[key].(string)
+		return v
+	}
+	method := &models.SavedPaymentMethod{
+		UserID:            payment.UserID,
+		AuthorizationCode: field("authorization_code"),
+		Channel:           field("channel"),
+		CardType:          strings.TrimSpace(field("card_type")),
+		Last4:             field("last4"),
+		ExpMonth:          field("exp_month"),
+		ExpYear:           field("exp_year"),
+		Bank:              field("bank"),
+	}
+	method.Email, _ = customer["email"].(string)
+	method.IsReusable, _ = authorization["reusable"].(bool)
+
+	// The signature identifies the card across authorizations, so paying with it again
+	// refreshes the saved method instead of adding a duplicate
+	method.Fingerprint = field("signature")
+	if method.Fingerprint == "" {
+		method.Fingerprint = method.AuthorizationCode
+	}
+	return method
+}
+
+// toMap converts a typed Paystack response section to the generic form it is stored in
+func toMap(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
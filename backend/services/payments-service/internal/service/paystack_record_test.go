@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+func TestMetadataID(t *testing.T) {
+	id := uuid.NewString()
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "UUID string", value: id, want: id},
+		{name: "missing", value: nil, want: ""},
+		{name: "empty string", value: "", want: ""},
+		{name: "whole number Paystack returned as a float", value: float64(12345678901), wantErr: true},
+		{name: "fractional float", value: 1.5, wantErr: true},
+		{name: "not a UUID", value: "goal-1", wantErr: true},
+		{name: "object", value: map[string]interface{}{"id": id}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := metadataID(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("metadataID(%v) err = %v, want error %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("metadataID(%v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMetadataAcceptsBothShapes(t *testing.T) {
+	for _, raw := range []interface{}{
+		map[string]interface{}{"goal_id": "g"},
+		`{"goal_id":"g"}`,
+	} {
+		metadata, err := parseMetadata(raw)
+		if err != nil || metadata["goal_id"] != "g" {
+			t.Errorf("parseMetadata(%v) = %v, %v", raw, metadata, err)
+		}
+	}
+	if _, err := parseMetadata("not json"); err == nil {
+		t.Error("parseMetadata accepted a string that is not a JSON object")
+	}
+	if _, err := parseMetadata(42.0); err == nil {
+		t.Error("parseMetadata accepted a number")
+	}
+}
+
+func TestReconcileAppMetadata(t *testing.T) {
+	payment := &models.Payment{PaymentID: uuid.NewString(), UserID: uuid.NewString(), GoalID: uuid.NewString()}
+	milestoneID := uuid.NewString()
+
+	reconcileAppMetadata(payment, map[string]interface{}{
+		metaPaymentID:   payment.PaymentID,
+		metaGoalID:      uuid.NewString(), // disagrees with the payment
+		metaUserID:      42.0,             // not an ID at all
+		metaMilestoneID: milestoneID,      // not stored yet
+		"custom_fields": []interface{}{map[string]interface{}{"display_name": "Team"}},
+	}, "verify")
+	// A later response cannot replace a custom field either
+	reconcileAppMetadata(payment, map[string]interface{}{"custom_fields": "changed"}, "webhook")
+
+	app := payment.Paystack.AppMetadata
+	if app.PaymentID != payment.PaymentID || app.GoalID != payment.GoalID || app.UserID != payment.UserID {
+		t.Errorf("stored IDs were overwritten: %+v", app)
+	}
+	if app.MilestoneID != milestoneID {
+		t.Errorf("milestone ID = %q, want the returned %s filled in", app.MilestoneID, milestoneID)
+	}
+	if _, ok := app.Custom["custom_fields"].([]interface{}); !ok {
+		t.Errorf("custom fields = %v, want the first value kept", app.Custom["custom_fields"])
+	}
+}
+
+func TestMigrateLegacyPaystackData(t *testing.T) {
+	payment := &models.Payment{
+		PaymentID: uuid.NewString(),
+		GoalID:    uuid.NewString(),
+		PaystackData: map[string]interface{}{
+			"authorization_url": "https://checkout.paystack.com/abc",
+			"access_code":       "abc",
+			"gateway_response":  "Successful",
+		},
+	}
+
+	payment.MigrateLegacyPaystackData()
+
+	record := payment.Paystack
+	if record.Initialization["authorization_url"] != "https://checkout.paystack.com/abc" || record.Initialization["access_code"] != "abc" {
+		t.Errorf("initialization = %v, want the checkout fields", record.Initialization)
+	}
+	if record.Verification["gateway_response"] != "Successful" || record.Verification["access_code"] != nil {
+		t.Errorf("verification = %v, want only the verify fields", record.Verification)
+	}
+	if record.AppMetadata == nil || record.AppMetadata.GoalID != payment.GoalID {
+		t.Errorf("app metadata = %+v, want it built from the payment", record.AppMetadata)
+	}
+	if payment.PaystackData != nil {
+		t.Error("the legacy field was kept")
+	}
+}
+
+// TestWebhookAfterVerificationKeepsStoredData delivers charge.success for a payment the API
+// verify already confirmed; the webhook is added to the history and nothing recorded at
+// initialization or verification is lost
+func TestWebhookAfterVerificationKeepsStoredData(t *testing.T) {
+	f := newWebhookFixture(t)
+	ctx := context.Background()
+	payload := loadWebhook(t, "charge_success.json")
+	metadata := payload.Data["metadata"].(map[string]interface{})
+	contributionID := uuid.NewString()
+
+	payment := &models.Payment{
+		PaymentID:         metadata[metaPaymentID].(string),
+		PaystackReference: "GOFUND-re4lyvq3s3",
+		UserID:            metadata[metaUserID].(string),
+		GoalID:            metadata[metaGoalID].(string),
+		ContributionID:    contributionID,
+		Amount:            507_500,
+		Currency:          "NGN",
+		Status:            models.PaymentStatusPending,
+	}
+	record := paystackRecord(payment)
+	record.Initialization = map[string]interface{}{
+		"authorization_url": "https://checkout.paystack.com/re4lyvq3s3",
+		"access_code":       "re4lyvq3s3",
+	}
+	record.AppMetadata.Custom = map[string]interface{}{"campaign": "launch"}
+	if err := f.payments.CreatePayment(ctx, payment); err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+
+	// The API verify returns the same transaction the webhook later describes
+	verified, err := f.payments.GetPaymentByReference(ctx, payment.PaystackReference)
+	if err != nil {
+		t.Fatalf("GetPaymentByReference: %v", err)
+	}
+	recordVerification(verified, payload.Data)
+	verified.Status = models.PaymentStatusVerified
+	if err := f.payments.UpdatePayment(ctx, verified); err != nil {
+		t.Fatalf("UpdatePayment: %v", err)
+	}
+
+	if err := f.webhooks.ProcessWebhook(ctx, payload, "sig", "hash"); err != nil {
+		t.Fatalf("ProcessWebhook: %v", err)
+	}
+
+	stored, err := f.payments.GetPaymentByReference(ctx, payment.PaystackReference)
+	if err != nil {
+		t.Fatalf("GetPaymentByReference: %v", err)
+	}
+	got := stored.Paystack
+	if got == nil {
+		t.Fatal("the Paystack record was lost")
+	}
+	if got.Initialization["authorization_url"] != "https://checkout.paystack.com/re4lyvq3s3" || got.Initialization["access_code"] != "re4lyvq3s3" {
+		t.Errorf("initialization = %v, want the checkout fields kept", got.Initialization)
+	}
+	if got.Verification["gateway_response"] != "Successful" {
+		t.Errorf("verification = %v, want the verify response kept", got.Verification)
+	}
+	if len(got.WebhookEvents) != 1 || got.WebhookEvents[0].Event != "charge.success" {
+		t.Errorf("webhook events = %+v, want the charge.success delivery appended", got.WebhookEvents)
+	}
+	app := got.AppMetadata
+	if app == nil || app.ContributionID != contributionID || app.PaymentID != payment.PaymentID || app.Custom["campaign"] != "launch" {
+		t.Errorf("app metadata = %+v, want the stored fields kept", app)
+	}
+	if stored.Status != models.PaymentStatusVerified {
+		t.Errorf("status = %s, want %s", stored.Status, models.PaymentStatusVerified)
+	}
+	if n := len(f.publisher.published("PaymentVerified")); n != 0 {
+		t.Errorf("PaymentVerified events = %d, want none for a payment already verified", n)
+	}
+}
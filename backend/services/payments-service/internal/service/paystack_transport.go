@@ -0,0 +1,213 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofund/shared/metrics"
+)
+
+// ErrPaystackUnavailable is returned without calling Paystack while the circuit breaker is
+// open, i.e. after too many consecutive failures and until the cool-down has passed
+var ErrPaystackUnavailable = errors.New("paystack_unavailable")
+
+// PaystackRetryPolicy controls how Paystack calls are retried and when the circuit
+// breaker opens. Only connection errors and 5xx responses are retried; 4xx responses
+// are Paystack rejecting the request and are returned as they are.
+type PaystackRetryPolicy struct {
+	// MaxAttempts is the number of tries per call, including the first
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles on each further retry
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff
+	MaxDelay time.Duration
+	// BreakerThreshold is the number of consecutive failed attempts that opens the breaker
+	BreakerThreshold int
+	// BreakerCooldown is how long the breaker stays open before letting a trial call through
+	BreakerCooldown time.Duration
+}
+
+// DefaultPaystackRetryPolicy is used for any zero field of the configured policy
+var DefaultPaystackRetryPolicy = PaystackRetryPolicy{
+	MaxAttempts:      3,
+	BaseDelay:        200 * time.Millisecond,
+	MaxDelay:         2 * time.Second,
+	BreakerThreshold: 5,
+	BreakerCooldown:  30 * time.Second,
+}
+
+// withDefaults fills the unset fields of the policy from DefaultPaystackRetryPolicy
+func (p PaystackRetryPolicy) withDefaults() PaystackRetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultPaystackRetryPolicy.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = DefaultPaystackRetryPolicy.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultPaystackRetryPolicy.MaxDelay
+	}
+	if p.BreakerThreshold <= 0 {
+		p.BreakerThreshold = DefaultPaystackRetryPolicy.BreakerThreshold
+	}
+	if p.BreakerCooldown <= 0 {
+		p.BreakerCooldown = DefaultPaystackRetryPolicy.BreakerCooldown
+	}
+	return p
+}
+
+// backoff returns the delay before the given retry (1 for the first), with full jitter
+// so callers retrying together do not hit Paystack in step
+func (p PaystackRetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay << (retry - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+// Circuit breaker states
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half_open"
+)
+
+// paystackBreaker is a consecutive-failure circuit breaker. Once open it fails calls
+// fast until the cool-down passes, then lets a single trial call through: success
+// closes it again, failure reopens it for another cool-down.
+type paystackBreaker struct {
+	mu          sync.Mutex
+	state       string
+	failures    int
+	openedAt    time.Time
+	trialActive bool
+	threshold   int
+	cooldown    time.Duration
+}
+
+func newPaystackBreaker(threshold int, cooldown time.Duration) *paystackBreaker {
+	return &paystackBreaker{state: breakerClosed, threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports whether a call may go to Paystack
+func (b *paystackBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.setState(breakerHalfOpen)
+		b.trialActive = true
+		return true
+	case breakerHalfOpen:
+		// Only one trial call at a time
+		if b.trialActive {
+			return false
+		}
+		b.trialActive = true
+		return true
+	default:
+		return true
+	}
+}
+
+// success records a call that reached Paystack and got a non-5xx answer
+func (b *paystackBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trialActive = false
+	if b.state != breakerClosed {
+		b.setState(breakerClosed)
+	}
+}
+
+// failure records a connection error or 5xx response
+func (b *paystackBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trialActive = false
+	if b.state == breakerHalfOpen || (b.state == breakerClosed && b.failures >= b.threshold) {
+		b.openedAt = time.Now()
+		b.setState(breakerOpen)
+	}
+}
+
+// setState changes the breaker state and reports the change; callers hold b.mu
+func (b *paystackBreaker) setState(state string) {
+	log.Printf("[WARN] Paystack circuit breaker %s -> %s (consecutive_failures: %d)", b.state, state, b.failures)
+	b.state = state
+
+	metrics.IncrementCounter("paystack.circuit_breaker.state_change", "state:"+state)
+	open := 0.0
+	if state == breakerOpen {
+		open = 1
+	}
+	metrics.RecordGauge("paystack.circuit_breaker.open", open)
+}
+
+// do sends a Paystack request through the retry policy and circuit breaker; every
+// PaystackClient and TransferService call goes through it. The returned response is the
+// last attempt's, so a 5xx that outlasted its retries is returned like any other status.
+// Requests with a body must be built with http.NewRequest from a bytes buffer so the
+// body can be replayed. operation names the call in metrics and logs.
+func (pc *PaystackClient) do(httpReq *http.Request, operation string) (*http.Response, error) {
+	var lastErr error
+	for attempt := 1; attempt <= pc.retry.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			delay := pc.retry.backoff(attempt - 1)
+			metrics.IncrementCounter("paystack.api.retry", "operation:"+operation)
+			log.Printf("[WARN] Retrying Paystack %s in %s (attempt: %d/%d, error: %v)",
+				operation, delay, attempt, pc.retry.MaxAttempts, lastErr)
+			time.Sleep(delay)
+		}
+
+		if !pc.breaker.allow() {
+			metrics.IncrementCounter("paystack.api.rejected", "operation:"+operation)
+			return nil, ErrPaystackUnavailable
+		}
+
+		req := httpReq
+		if attempt > 1 && httpReq.GetBody != nil {
+			body, err := httpReq.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to replay request body: %w", err)
+			}
+			req = httpReq.Clone(httpReq.Context())
+			req.Body = body
+		}
+
+		resp, err := pc.client.Do(req)
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			pc.breaker.success()
+			return resp, nil
+		}
+		pc.breaker.failure()
+
+		if err != nil {
+			lastErr = err
+		} else {
+			lastErr = fmt.Errorf("status %d", resp.StatusCode)
+			if attempt == pc.retry.MaxAttempts {
+				return resp, nil
+			}
+			// Drain so the connection can be reused by the retry
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	}
+	return nil, lastErr
+}
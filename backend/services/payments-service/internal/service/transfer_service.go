@@ -0,0 +1,367 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofund/payments-service/internal/dto"
+	"github.com/gofund/shared/metrics"
+)
+
+// ErrUnknownBank is returned when a bank name matches no bank Paystack supports
+var ErrUnknownBank = errors.New("unknown bank")
+
+// ErrTransferRejected is wrapped by Transfer errors where Paystack answered and refused the
+// recipient or the transfer, so no money was sent. Any other Transfer error, such as a
+// timeout or a 5xx, leaves it unknown whether Paystack made the transfer.
+var ErrTransferRejected = errors.New("paystack rejected the transfer")
+
+// TransferService sends money to bank accounts through Paystack's Transfer API. It backs
+// both refund disbursements and goal withdrawals.
+type TransferService struct {
+	paystackClient *PaystackClient
+}
+
+// NewTransferService creates a new transfer service instance
+func NewTransferService(paystackClient *PaystackClient) *TransferService {
+	return &TransferService{
+		paystackClient: paystackClient,
+	}
+}
+
+// PaystackTransferRecipientRequest represents the request to create a transfer recipient
+type PaystackTransferRecipientRequest struct {
+	Type          string `json:"type"`
+	Name          string `json:"name"`
+	AccountNumber string `json:"account_number"`
+	BankCode      string `json:"bank_code"`
+	Currency      string `json:"currency"`
+}
+
+// PaystackTransferRecipientResponse represents the response from creating a transfer recipient
+type PaystackTransferRecipientResponse struct {
+	Status  bool   `json:"status"`
+	Message string `json:"message"`
+	Data    struct {
+		RecipientCode string `json:"recipient_code"`
+		Type          string `json:"type"`
+		Name          string `json:"name"`
+		AccountNumber string `json:"account_number"`
+		BankCode      string `json:"bank_code"`
+	} `json:"data"`
+}
+
+// PaystackTransferRequest represents the request to initiate a transfer
+type PaystackTransferRequest struct {
+	Source    string `json:"source"`
+	Amount    int64  `json:"amount"`
+	Recipient string `json:"recipient"`
+	Reason    string `json:"reason"`
+	Reference string `json:"reference"`
+	Currency  string `json:"currency"`
+}
+
+// PaystackTransferResponse represents the response from initiating a transfer
+type PaystackTransferResponse struct {
+	Status  bool   `json:"status"`
+	Message string `json:"message"`
+	Data    struct {
+		TransferCode string `json:"transfer_code"`
+		Reference    string `json:"reference"`
+		Status       string `json:"status"`
+		Amount       int64  `json:"amount"`
+		CreatedAt    string `json:"created_at"`
+	} `json:"data"`
+}
+
+// PaystackVerifyTransferResponse represents the response from verifying a transfer
+type PaystackVerifyTransferResponse struct {
+	Status  bool   `json:"status"`
+	Message string `json:"message"`
+	Data    struct {
+		TransferCode string `json:"transfer_code"`
+		Reference    string `json:"reference"`
+		Status       string `json:"status"`
+		Amount       int64  `json:"amount"`
+		Reason       string `json:"reason"`
+		CreatedAt    string `json:"created_at"`
+		UpdatedAt    string `json:"updated_at"`
+	} `json:"data"`
+}
+
+// Transfer creates a transfer recipient for the account and sends it the amount. The
+// reference identifies the payout in Paystack's transfer webhooks.
+func (ts *TransferService) Transfer(req *dto.TransferRequest) (*dto.TransferResponse, error) {
+	log.Printf("[INFO] Initiating transfer (reference: %s, amount: %d, bank_code: %s)",
+		req.Reference, req.Amount, req.BankCode)
+
+	recipientCode, err := ts.createTransferRecipient(req.AccountName, req.AccountNumber, req.BankCode, req.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transfer recipient: %w", err)
+	}
+
+	transfer, err := ts.initiateTransfer(recipientCode, req.Amount, req.Reference, req.Reason, req.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initiate transfer: %w", err)
+	}
+
+	return &dto.TransferResponse{
+		RecipientCode: recipientCode,
+		TransferCode:  transfer.Data.TransferCode,
+		Reference:     req.Reference,
+		Status:        transfer.Data.Status,
+	}, nil
+}
+
+// ResolveBankCode finds the Paystack code for a bank by name, ignoring case
+func (ts *TransferService) ResolveBankCode(bankName string) (string, error) {
+	banks, err := ts.paystackClient.ListBanks("nigeria")
+	if err != nil {
+		return "", fmt.Errorf("failed to list banks: %w", err)
+	}
+
+	name := strings.TrimSpace(bankName)
+	for _, bank := range banks.Data {
+		if bank.Active && !bank.IsDeleted && strings.EqualFold(bank.Name, name) {
+			return bank.Code, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownBank, bankName)
+}
+
+// createTransferRecipient creates a transfer recipient on Paystack
+func (ts *TransferService) createTransferRecipient(name, accountNumber, bankCode, currency string) (string, error) {
+	url := fmt.Sprintf("%s/transferrecipient", ts.paystackClient.baseURL)
+
+	reqBody := PaystackTransferRecipientRequest{
+		Type:          "nuban",
+		Name:          name,
+		AccountNumber: accountNumber,
+		BankCode:      bankCode,
+		Currency:      currency,
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", ts.paystackClient.secretKey))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	startTime := time.Now()
+	resp, err := ts.paystackClient.do(httpReq, "create_recipient")
+	duration := time.Since(startTime).Milliseconds()
+
+	metrics.RecordHistogram("paystack.api.create_recipient.duration", float64(duration))
+
+	if err != nil {
+		metrics.IncrementCounter("paystack.api.create_recipient.error")
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		metrics.IncrementCounter("paystack.api.create_recipient.failed")
+		log.Printf("[ERROR] Failed to create transfer recipient (status: %d, response: %s)",
+			resp.StatusCode, string(respBody))
+		if resp.StatusCode < http.StatusInternalServerError {
+			return "", fmt.Errorf("%w: status %d, body: %s", ErrTransferRejected, resp.StatusCode, string(respBody))
+		}
+		return "", fmt.Errorf("paystack API error: status %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	var paystackResp PaystackTransferRecipientResponse
+	if err := json.Unmarshal(respBody, &paystackResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if !paystackResp.Status {
+		metrics.IncrementCounter("paystack.api.create_recipient.failed")
+		return "", fmt.Errorf("%w: failed to create recipient: %s", ErrTransferRejected, paystackResp.Message)
+	}
+
+	metrics.IncrementCounter("paystack.api.create_recipient.success")
+	log.Printf("[INFO] Transfer recipient created successfully (recipient_code: %s)",
+		paystackResp.Data.RecipientCode)
+
+	return paystackResp.Data.RecipientCode, nil
+}
+
+// initiateTransfer initiates a transfer on Paystack
+func (ts *TransferService) initiateTransfer(recipientCode string, amount int64, reference, reason, currency string) (*PaystackTransferResponse, error) {
+	url := fmt.Sprintf("%s/transfer", ts.paystackClient.baseURL)
+
+	reqBody := PaystackTransferRequest{
+		Source:    "balance",
+		Amount:    amount,
+		Recipient: recipientCode,
+		Reason:    reason,
+		Reference: reference,
+		Currency:  currency,
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", ts.paystackClient.secretKey))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	startTime := time.Now()
+	resp, err := ts.paystackClient.do(httpReq, "initiate_transfer")
+	duration := time.Since(startTime).Milliseconds()
+
+	metrics.RecordHistogram("paystack.api.initiate_transfer.duration", float64(duration))
+
+	if err != nil {
+		metrics.IncrementCounter("paystack.api.initiate_transfer.error")
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		metrics.IncrementCounter("paystack.api.initiate_transfer.failed")
+		log.Printf("[ERROR] Failed to initiate transfer (status: %d, response: %s)",
+			resp.StatusCode, string(respBody))
+		if resp.StatusCode < http.StatusInternalServerError {
+			return nil, fmt.Errorf("%w: status %d, body: %s", ErrTransferRejected, resp.StatusCode, string(respBody))
+		}
+		return nil, fmt.Errorf("paystack API error: status %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	var paystackResp PaystackTransferResponse
+	if err := json.Unmarshal(respBody, &paystackResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if !paystackResp.Status {
+		metrics.IncrementCounter("paystack.api.initiate_transfer.failed")
+		return nil, fmt.Errorf("%w: %s", ErrTransferRejected, paystackResp.Message)
+	}
+
+	metrics.IncrementCounter("paystack.api.initiate_transfer.success")
+	log.Printf("[INFO] Transfer initiated successfully (transfer_code: %s, reference: %s, amount: %d)",
+		paystackResp.Data.TransferCode, reference, amount)
+
+	return &paystackResp, nil
+}
+
+// FindTransfer looks up the transfer Paystack holds for a reference. It returns nil when
+// Paystack has no transfer with the reference, which after an ambiguous Transfer error means
+// the transfer was never made.
+func (ts *TransferService) FindTransfer(reference string) (*dto.TransferResponse, error) {
+	url := fmt.Sprintf("%s/transfer/verify/%s", ts.paystackClient.baseURL, reference)
+
+	httpReq, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", ts.paystackClient.secretKey))
+
+	resp, err := ts.paystackClient.do(httpReq, "find_transfer")
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("paystack API error: status %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	var paystackResp PaystackVerifyTransferResponse
+	if err := json.Unmarshal(respBody, &paystackResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if !paystackResp.Status {
+		return nil, fmt.Errorf("failed to find transfer: %s", paystackResp.Message)
+	}
+
+	log.Printf("[INFO] Transfer found (reference: %s, transfer_code: %s, status: %s)",
+		reference, paystackResp.Data.TransferCode, paystackResp.Data.Status)
+
+	return &dto.TransferResponse{
+		TransferCode: paystackResp.Data.TransferCode,
+		Reference:    reference,
+		Status:       paystackResp.Data.Status,
+	}, nil
+}
+
+// VerifyTransfer returns the provider status of a transfer: "pending", "success",
+// "failed" or "reversed"
+func (ts *TransferService) VerifyTransfer(transferCode string) (string, error) {
+	url := fmt.Sprintf("%s/transfer/%s", ts.paystackClient.baseURL, transferCode)
+
+	httpReq, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", ts.paystackClient.secretKey))
+
+	resp, err := ts.paystackClient.do(httpReq, "verify_transfer")
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("paystack API error: status %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	var paystackResp PaystackVerifyTransferResponse
+	if err := json.Unmarshal(respBody, &paystackResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if !paystackResp.Status {
+		return "", fmt.Errorf("failed to verify transfer: %s", paystackResp.Message)
+	}
+
+	log.Printf("[INFO] Transfer verified (transfer_code: %s, status: %s)", transferCode, paystackResp.Data.Status)
+
+	return paystackResp.Data.Status, nil
+}
@@ -19,35 +19,51 @@ import (
 type WebhookService struct {
 	webhookRepo     *repository.WebhookRepository
 	paymentRepo     *repository.PaymentRepository
+	methodRepo      *repository.PaymentMethodRepository
 	eventPublisher  messaging.Publisher
+	payoutService   *PayoutService
+	replayWindow    time.Duration
 }
 
 // NewWebhookService creates a new webhook service
 func NewWebhookService(
 	webhookRepo *repository.WebhookRepository,
 	paymentRepo *repository.PaymentRepository,
+	methodRepo *repository.PaymentMethodRepository,
 	eventPublisher messaging.Publisher,
+	payoutService *PayoutService,
+	replayWindow time.Duration,
 ) *WebhookService {
 	return &WebhookService{
 		webhookRepo:    webhookRepo,
 		paymentRepo:    paymentRepo,
+		methodRepo:     methodRepo,
 		eventPublisher: eventPublisher,
+		payoutService:  payoutService,
+		replayWindow:   replayWindow,
 	}
 }
 
-// ProcessWebhook processes a Paystack webhook event
-func (ws *WebhookService) ProcessWebhook(ctx context.Context, payload *dto.WebhookPayload, signature string) error {
+// ProcessWebhook processes a Paystack webhook event. bodyHash is the SHA-256 of the raw,
+// signed request body.
+func (ws *WebhookService) ProcessWebhook(ctx context.Context, payload *dto.WebhookPayload, signature, bodyHash string) error {
+	// Reject payloads missing the fields their handler relies on
+	if err := validateWebhookPayload(payload); err != nil {
+		metrics.IncrementCounter("webhook.payload.invalid", "event:"+payload.Event)
+		return fmt.Errorf("invalid %s webhook: %w", payload.Event, err)
+	}
+
 	// Generate event ID from Paystack data
-	eventID := ws.generateEventID(payload)
+	eventID := ws.generateEventID(payload, bodyHash)
 
-	log.Printf("[INFO] Processing webhook event", map[string]interface{}{
+	log.Printf("[INFO] Processing webhook event: %v", map[string]interface{}{
 		"event_id":   eventID,
 		"event_type": payload.Event,
 	})
 
 	// Step 1: Check idempotency (has this event been processed?)
 	if ws.webhookRepo.IsEventProcessed(ctx, eventID) {
-		log.Printf("[INFO] Webhook event already processed, skipping", map[string]interface{}{
+		log.Printf("[INFO] Webhook event already processed, skipping: %v", map[string]interface{}{
 			"event_id":   eventID,
 			"event_type": payload.Event,
 		})
@@ -55,17 +71,24 @@ func (ws *WebhookService) ProcessWebhook(ctx context.Context, payload *dto.Webho
 		return nil // Not an error - just already processed
 	}
 
-	// Step 2: Save webhook event
+	// Step 2: Refuse a validly signed but old delivery unless it can still settle something
+	if err := ws.checkReplay(ctx, payload, time.Now()); err != nil {
+		metrics.IncrementCounter("webhook.replay.rejected", "event:"+payload.Event)
+		return err
+	}
+
+	// Step 3: Save webhook event
 	webhookEvent := &models.WebhookEvent{
 		EventID:   eventID,
 		Event:     payload.Event,
 		Data:      payload.Data,
 		Signature: signature,
+		BodyHash:  bodyHash,
 		Processed: false,
 	}
 
 	if err := ws.webhookRepo.SaveWebhookEvent(ctx, webhookEvent); err != nil {
-		log.Printf("[INFO] Failed to save webhook event", map[string]interface{}{
+		log.Printf("[INFO] Failed to save webhook event: %v", map[string]interface{}{
 			"error":      err.Error(),
 			"event_id":   eventID,
 			"event_type": payload.Event,
@@ -73,7 +96,7 @@ func (ws *WebhookService) ProcessWebhook(ctx context.Context, payload *dto.Webho
 		return fmt.Errorf("failed to save webhook: %w", err)
 	}
 
-	// Step 3: Process based on event type
+	// Step 4: Process based on event type
 	var processErr error
 	switch payload.Event {
 	case "charge.success":
@@ -82,10 +105,10 @@ func (ws *WebhookService) ProcessWebhook(ctx context.Context, payload *dto.Webho
 		processErr = ws.processChargeFailed(ctx, payload.Data)
 	case "transfer.success":
 		processErr = ws.processTransferSuccess(ctx, payload.Data)
-	case "transfer.failed":
+	case "transfer.failed", "transfer.reversed":
 		processErr = ws.processTransferFailed(ctx, payload.Data)
 	default:
-		log.Printf("[INFO] Unhandled webhook event type", map[string]interface{}{
+		log.Printf("[INFO] Unhandled webhook event type: %v", map[string]interface{}{
 			"event_type": payload.Event,
 		})
 		// Mark as processed even if we don't handle it
@@ -94,7 +117,7 @@ func (ws *WebhookService) ProcessWebhook(ctx context.Context, payload *dto.Webho
 	}
 
 	if processErr != nil {
-		log.Printf("[INFO] Failed to process webhook event", map[string]interface{}{
+		log.Printf("[INFO] Failed to process webhook event: %v", map[string]interface{}{
 			"error":      processErr.Error(),
 			"event_id":   eventID,
 			"event_type": payload.Event,
@@ -102,9 +125,9 @@ func (ws *WebhookService) ProcessWebhook(ctx context.Context, payload *dto.Webho
 		return processErr
 	}
 
-	// Step 4: Mark webhook as processed
+	// Step 5: Mark webhook as processed
 	if err := ws.webhookRepo.MarkWebhookProcessed(ctx, eventID); err != nil {
-		log.Printf("[INFO] Failed to mark webhook as processed", map[string]interface{}{
+		log.Printf("[INFO] Failed to mark webhook as processed: %v", map[string]interface{}{
 			"error":    err.Error(),
 			"event_id": eventID,
 		})
@@ -112,7 +135,7 @@ func (ws *WebhookService) ProcessWebhook(ctx context.Context, payload *dto.Webho
 	}
 
 	metrics.IncrementCounter("webhook.processed.count")
-	log.Printf("[INFO] Webhook event processed successfully", map[string]interface{}{
+	log.Printf("[INFO] Webhook event processed successfully: %v", map[string]interface{}{
 		"event_id":   eventID,
 		"event_type": payload.Event,
 	})
@@ -128,44 +151,63 @@ func (ws *WebhookService) processChargeSuccess(ctx context.Context, data map[str
 		return fmt.Errorf("missing or invalid reference in webhook data")
 	}
 
-	log.Printf("[INFO] Processing charge.success webhook", map[string]interface{}{
+	log.Printf("[INFO] Processing charge.success webhook: %v", map[string]interface{}{
 		"reference": reference,
 	})
 
 	// Get payment by reference
 	payment, err := ws.paymentRepo.GetPaymentByReference(ctx, reference)
 	if err != nil {
-		log.Printf("[INFO] Payment not found for webhook", map[string]interface{}{
+		log.Printf("[INFO] Payment not found for webhook: %v", map[string]interface{}{
 			"error":     err.Error(),
 			"reference": reference,
 		})
 		return fmt.Errorf("payment not found: %w", err)
 	}
 
+	// Keep every delivery, even when the API verify got there first
+	if err := ws.recordWebhookEvent(ctx, payment, "charge.success", data); err != nil {
+		return err
+	}
+
 	// Check if already verified (idempotency - might have been verified via API)
 	if payment.Status == models.PaymentStatusVerified {
-		log.Printf("[INFO] Payment already verified, webhook is backup confirmation", map[string]interface{}{
+		log.Printf("[INFO] Payment already verified, webhook is backup confirmation: %v", map[string]interface{}{
 			"payment_id": payment.PaymentID,
 			"reference":  reference,
 		})
 		return nil // Success - no action needed
 	}
 
+	// A charge for another amount or currency must not verify the payment. It stays
+	// unverified, and the delivery unprocessed, until someone reconciles it.
+	// validateWebhookPayload has checked both fields are present.
+	amount, _ := data["amount"].(float64)
+	currency, _ := data["currency"].(string)
+	if err := checkChargeMatchesPayment(payment, int64(amount), currency); err != nil {
+		metrics.IncrementCounter("webhook.payment.mismatch")
+		log.Printf("[ERROR] Charge does not match payment: %v (payment_id: %s, reference: %s)",
+			err, payment.PaymentID, reference)
+		return err
+	}
+
 	// Update payment status to VERIFIED
 	payment.Status = models.PaymentStatusVerified
-	payment.PaystackData = data
+	reconcileAppMetadata(payment, data["metadata"], "webhook")
 
 	if err := ws.paymentRepo.UpdatePayment(ctx, payment); err != nil {
-		log.Printf("[INFO] Failed to update payment status", map[string]interface{}{
+		log.Printf("[INFO] Failed to update payment status: %v", map[string]interface{}{
 			"error":      err.Error(),
 			"payment_id": payment.PaymentID,
 		})
 		return fmt.Errorf("failed to update payment: %w", err)
 	}
 
+	savePaymentMethod(ctx, ws.methodRepo, payment)
+
 	// Emit PaymentVerified event
-	if err := ws.emitPaymentVerifiedEvent(payment); err != nil {
-		log.Printf("[INFO] Failed to emit PaymentVerified event", map[string]interface{}{
+	if err := ws.emitPaymentVerifiedEvent(ctx, payment); err != nil {
+		log.Printf("[INFO] Failed to emit PaymentVerified event: %v", map[string]interface{}{
 			"error":      err.Error(),
 			"payment_id": payment.PaymentID,
 		})
@@ -173,7 +215,7 @@ func (ws *WebhookService) processChargeSuccess(ctx context.Context, data map[str
 	}
 
 	metrics.IncrementCounter("webhook.payment.verified.count")
-	log.Printf("[INFO] Payment verified via webhook", map[string]interface{}{
+	log.Printf("[INFO] Payment verified via webhook: %v", map[string]interface{}{
 		"payment_id": payment.PaymentID,
 		"reference":  reference,
 		"amount":     payment.Amount,
@@ -189,7 +231,7 @@ func (ws *WebhookService) processChargeFailed(ctx context.Context, data map[stri
 		return fmt.Errorf("missing or invalid reference in webhook data")
 	}
 
-	log.Printf("[INFO] Processing charge.failed webhook", map[string]interface{}{
+	log.Printf("[INFO] Processing charge.failed webhook: %v", map[string]interface{}{
 		"reference": reference,
 	})
 
@@ -199,16 +241,29 @@ func (ws *WebhookService) processChargeFailed(ctx context.Context, data map[stri
 		return fmt.Errorf("payment not found: %w", err)
 	}
 
+	if err := ws.recordWebhookEvent(ctx, payment, "charge.failed", data); err != nil {
+		return err
+	}
+
+	// A late failure notice must not undo a verified payment
+	if payment.Status == models.PaymentStatusVerified {
+		log.Printf("[INFO] Ignoring charge.failed for verified payment: %v", map[string]interface{}{
+			"payment_id": payment.PaymentID,
+			"reference":  reference,
+		})
+		return nil
+	}
+
 	// Update payment status to FAILED
 	payment.Status = models.PaymentStatusFailed
-	payment.PaystackData = data
+	reconcileAppMetadata(payment, data["metadata"], "webhook")
 
 	if err := ws.paymentRepo.UpdatePayment(ctx, payment); err != nil {
 		return fmt.Errorf("failed to update payment: %w", err)
 	}
 
 	metrics.IncrementCounter("webhook.payment.failed.count")
-	log.Printf("[INFO] Payment marked as failed via webhook", map[string]interface{}{
+	log.Printf("[INFO] Payment marked as failed via webhook: %v", map[string]interface{}{
 		"payment_id": payment.PaymentID,
 		"reference":  reference,
 	})
@@ -216,58 +271,96 @@ func (ws *WebhookService) processChargeFailed(ctx context.Context, data map[stri
 	return nil
 }
 
-// processTransferSuccess handles transfer.success webhook (for refunds)
+// processTransferSuccess handles transfer.success webhook (for withdrawals and refunds)
 func (ws *WebhookService) processTransferSuccess(ctx context.Context, data map[string]interface{}) error {
 	reference, ok := data["reference"].(string)
 	if !ok {
 		return fmt.Errorf("missing or invalid reference in webhook data")
 	}
 
-	log.Printf("[INFO] Processing transfer.success webhook", map[string]interface{}{
-		"reference": reference,
-	})
+	log.Printf("[INFO] Processing transfer.success webhook (reference: %s)", reference)
 
-	// TODO: Update refund disbursement status
-	// This will be handled by the refund disbursement service
+	handled, err := ws.payoutService.HandleTransferSuccess(ctx, reference)
+	if err != nil {
+		return err
+	}
+	if !handled {
+		log.Printf("[WARN] No payout found for transfer (reference: %s)", reference)
+	}
 
 	metrics.IncrementCounter("webhook.transfer.success.count")
 	return nil
 }
 
-// processTransferFailed handles transfer.failed webhook (for refunds)
+// processTransferFailed handles transfer.failed and transfer.reversed webhooks (for withdrawals and refunds)
 func (ws *WebhookService) processTransferFailed(ctx context.Context, data map[string]interface{}) error {
 	reference, ok := data["reference"].(string)
 	if !ok {
 		return fmt.Errorf("missing or invalid reference in webhook data")
 	}
 
-	log.Printf("[INFO] Processing transfer.failed webhook", map[string]interface{}{
-		"reference": reference,
-	})
+	log.Printf("[INFO] Processing transfer failure webhook (reference: %s)", reference)
 
-	// TODO: Update refund disbursement status
-	// This will be handled by the refund disbursement service
+	reason, _ := data["reason"].(string)
+	if reason == "" {
+		reason = "transfer failed"
+		if status, ok := data["status"].(string); ok && status != "" {
+			reason = fmt.Sprintf("transfer %s", status)
+		}
+	}
+
+	handled, err := ws.payoutService.HandleTransferFailed(ctx, reference, reason)
+	if err != nil {
+		return err
+	}
+	if !handled {
+		log.Printf("[WARN] No payout found for transfer (reference: %s)", reference)
+	}
 
 	metrics.IncrementCounter("webhook.transfer.failed.count")
 	return nil
 }
 
+// recordWebhookEvent appends a delivery to the payment's webhook history, skipping one
+// that was already recorded
+func (ws *WebhookService) recordWebhookEvent(ctx context.Context, payment *models.Payment, event string, data map[string]interface{}) error {
+	if hasWebhookEvent(payment, event, data) {
+		return nil
+	}
+
+	recorded := models.PaystackWebhookEvent{
+		Event:      event,
+		ReceivedAt: time.Now(),
+		Data:       data,
+	}
+	if err := ws.paymentRepo.AppendWebhookEvent(ctx, payment.PaymentID, recorded); err != nil {
+		return fmt.Errorf("failed to record webhook event: %w", err)
+	}
+
+	record := paystackRecord(payment)
+	record.WebhookEvents = append(record.WebhookEvents, recorded)
+	return nil
+}
+
 // emitPaymentVerifiedEvent emits a PaymentVerified event
-func (ws *WebhookService) emitPaymentVerifiedEvent(payment *models.Payment) error {
+func (ws *WebhookService) emitPaymentVerifiedEvent(ctx context.Context, payment *models.Payment) error {
 	event := events.PaymentVerified{
-		ID:        uuid.New().String(),
-		PaymentID: payment.PaymentID,
-		UserID:    payment.UserID,
-		GoalID:    payment.GoalID,
-		Amount:    payment.Amount,
-		CreatedAt: time.Now().Unix(),
+		ID:             uuid.New().String(),
+		PaymentID:      payment.PaymentID,
+		UserID:         payment.UserID,
+		GoalID:         payment.GoalID,
+		ContributionID: payment.ContributionID,
+		Amount:         payment.Amount,
+		Currency:       payment.Currency,
+		CreatedAt:      time.Now().Unix(),
 	}
+	event.AuthorizationCode, event.AuthorizationEmail = reusableAuthorization(payment)
 
-	if err := ws.eventPublisher.Publish("PaymentVerified", event); err != nil {
+	if err := ws.eventPublisher.PublishContext(ctx, "PaymentVerified", event); err != nil {
 		return fmt.Errorf("failed to publish event: %w", err)
 	}
 
-	log.Printf("[INFO] PaymentVerified event emitted from webhook", map[string]interface{}{
+	log.Printf("[INFO] PaymentVerified event emitted from webhook: %v", map[string]interface{}{
 		"event_id":   event.ID,
 		"payment_id": payment.PaymentID,
 		"user_id":    payment.UserID,
@@ -279,7 +372,7 @@ func (ws *WebhookService) emitPaymentVerifiedEvent(payment *models.Payment) erro
 }
 
 // generateEventID generates a unique event ID from webhook data
-func (ws *WebhookService) generateEventID(payload *dto.WebhookPayload) string {
+func (ws *WebhookService) generateEventID(payload *dto.WebhookPayload, bodyHash string) string {
 	// Try to extract Paystack's event ID if available
 	if id, ok := payload.Data["id"]; ok {
 		return fmt.Sprintf("%s-%v", payload.Event, id)
@@ -290,6 +383,6 @@ func (ws *WebhookService) generateEventID(payload *dto.WebhookPayload) string {
 		return fmt.Sprintf("%s-%s", payload.Event, reference)
 	}
 
-	// Last resort: generate UUID
-	return fmt.Sprintf("%s-%s", payload.Event, uuid.New().String())
+	// Last resort: the raw body, so a replayed delivery maps to the same event
+	return fmt.Sprintf("%s-%s", payload.Event, bodyHash)
 }
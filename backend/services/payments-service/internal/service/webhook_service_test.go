@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gofund/payments-service/internal/repository"
+	"github.com/gofund/payments-service/internal/testmongo"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+)
+
+// webhookFixture is a webhook service over a fresh test database, with the publisher it
+// emits events to
+type webhookFixture struct {
+	payments  *repository.PaymentRepository
+	transfers *repository.TransferRepository
+	publisher *recordingPublisher
+	webhooks  *WebhookService
+}
+
+func newWebhookFixture(t *testing.T) *webhookFixture {
+	t.Helper()
+	db := testmongo.Open(t)
+	ctx := context.Background()
+
+	webhookRepo := repository.NewWebhookRepository(db)
+	if err := webhookRepo.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("failed to create webhook indexes: %v", err)
+	}
+	transferRepo := repository.NewTransferRepository(db)
+	if err := transferRepo.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("failed to create transfer indexes: %v", err)
+	}
+
+	f := &webhookFixture{
+		payments:  repository.NewPaymentRepository(db),
+		transfers: transferRepo,
+		publisher: &recordingPublisher{},
+	}
+	payouts := NewPayoutService(transferRepo, nil, nil, f.publisher)
+	f.webhooks = NewWebhookService(webhookRepo, f.payments, repository.NewPaymentMethodRepository(db), f.publisher, payouts, 0)
+	return f
+}
+
+// createPayment records a pending payment for a recorded charge's reference
+func (f *webhookFixture) createPayment(t *testing.T, reference string, amount int64, currency string) *models.Payment {
+	t.Helper()
+	payment := &models.Payment{
+		PaymentID:         uuid.NewString(),
+		PaystackReference: reference,
+		UserID:            uuid.NewString(),
+		GoalID:            uuid.NewString(),
+		Amount:            amount,
+		Currency:          currency,
+		Status:            models.PaymentStatusPending,
+	}
+	if err := f.payments.CreatePayment(context.Background(), payment); err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+	return payment
+}
+
+// createTransfer records a pending payout for a recorded transfer's reference
+func (f *webhookFixture) createTransfer(t *testing.T, reference string, purpose models.TransferPurpose, amount int64) *models.Transfer {
+	t.Helper()
+	transfer := &models.Transfer{
+		Reference:    reference,
+		Purpose:      purpose,
+		SourceID:     uuid.NewString(),
+		GoalID:       uuid.NewString(),
+		Amount:       amount,
+		Currency:     "NGN",
+		TransferCode: "TRF_recorded",
+		Status:       models.TransferStatusPending,
+	}
+	if err := f.transfers.CreateTransfer(context.Background(), transfer); err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+	return transfer
+}
+
+func (f *webhookFixture) paymentStatus(t *testing.T, reference string) models.PaymentStatus {
+	t.Helper()
+	payment, err := f.payments.GetPaymentByReference(context.Background(), reference)
+	if err != nil {
+		t.Fatalf("GetPaymentByReference: %v", err)
+	}
+	return payment.Status
+}
+
+func TestRecordedWebhooksAreValid(t *testing.T) {
+	for _, name := range []string{"charge_success.json", "charge_failed.json", "transfer_success.json", "transfer_failed.json"} {
+		payload := loadWebhook(t, name)
+		if err := validateWebhookPayload(payload); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestCheckChargeMatchesPayment(t *testing.T) {
+	payment := &models.Payment{Amount: 507_500, Currency: "NGN"}
+	tests := []struct {
+		name     string
+		amount   int64
+		currency string
+		wantErr  bool
+	}{
+		{"exact", 507_500, "NGN", false},
+		{"currency case", 507_500, "ngn", false},
+		{"underpaid", 500_000, "NGN", true},
+		{"overpaid", 507_501, "NGN", true},
+		{"other currency", 507_500, "GHS", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkChargeMatchesPayment(payment, tt.amount, tt.currency)
+			if tt.wantErr != errors.Is(err, ErrChargeMismatch) {
+				t.Errorf("checkChargeMatchesPayment(%d %s) = %v, want mismatch %v", tt.amount, tt.currency, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChargeSuccessWebhook(t *testing.T) {
+	f := newWebhookFixture(t)
+	ctx := context.Background()
+	payload := loadWebhook(t, "charge_success.json")
+	payment := f.createPayment(t, "GOFUND-re4lyvq3s3", 507_500, "NGN")
+
+	if err := f.webhooks.ProcessWebhook(ctx, payload, "sig", "hash"); err != nil {
+		t.Fatalf("ProcessWebhook: %v", err)
+	}
+	// Paystack redelivers until it is acknowledged
+	if err := f.webhooks.ProcessWebhook(ctx, payload, "sig", "hash"); err != nil {
+		t.Fatalf("redelivered ProcessWebhook: %v", err)
+	}
+
+	if status := f.paymentStatus(t, payment.PaystackReference); status != models.PaymentStatusVerified {
+		t.Errorf("payment status = %s, want %s", status, models.PaymentStatusVerified)
+	}
+	verified := f.publisher.published("PaymentVerified")
+	if len(verified) != 1 {
+		t.Fatalf("PaymentVerified events = %d, want 1", len(verified))
+	}
+	if event := verified[0].(events.PaymentVerified); event.PaymentID != payment.PaymentID || event.Amount != 507_500 {
+		t.Errorf("PaymentVerified = %+v, want payment %s for 507500", event, payment.PaymentID)
+	}
+}
+
+func TestChargeSuccessWebhookMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		currency string
+	}{
+		{"amount", 1_000_000, "NGN"},
+		{"currency", 507_500, "GHS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newWebhookFixture(t)
+			payment := f.createPayment(t, "GOFUND-re4lyvq3s3", tt.amount, tt.currency)
+
+			err := f.webhooks.ProcessWebhook(context.Background(), loadWebhook(t, "charge_success.json"), "sig", "hash")
+			if !errors.Is(err, ErrChargeMismatch) {
+				t.Fatalf("ProcessWebhook: err = %v, want %v", err, ErrChargeMismatch)
+			}
+			if status := f.paymentStatus(t, payment.PaystackReference); status != models.PaymentStatusPending {
+				t.Errorf("payment status = %s, want %s", status, models.PaymentStatusPending)
+			}
+			if n := len(f.publisher.published("PaymentVerified")); n != 0 {
+				t.Errorf("PaymentVerified events = %d, want 0", n)
+			}
+		})
+	}
+}
+
+func TestChargeFailedWebhook(t *testing.T) {
+	f := newWebhookFixture(t)
+	ctx := context.Background()
+	payment := f.createPayment(t, "GOFUND-x7pk2mzq9a", 250_000, "NGN")
+
+	if err := f.webhooks.ProcessWebhook(ctx, loadWebhook(t, "charge_failed.json"), "sig", "hash"); err != nil {
+		t.Fatalf("ProcessWebhook: %v", err)
+	}
+	if status := f.paymentStatus(t, payment.PaystackReference); status != models.PaymentStatusFailed {
+		t.Errorf("payment status = %s, want %s", status, models.PaymentStatusFailed)
+	}
+}
+
+func TestChargeFailedWebhookAfterVerification(t *testing.T) {
+	f := newWebhookFixture(t)
+	ctx := context.Background()
+	payment := f.createPayment(t, "GOFUND-x7pk2mzq9a", 250_000, "NGN")
+	if err := f.payments.UpdatePaymentStatus(ctx, payment.PaymentID, models.PaymentStatusVerified); err != nil {
+		t.Fatalf("UpdatePaymentStatus: %v", err)
+	}
+
+	if err := f.webhooks.ProcessWebhook(ctx, loadWebhook(t, "charge_failed.json"), "sig", "hash"); err != nil {
+		t.Fatalf("ProcessWebhook: %v", err)
+	}
+	if status := f.paymentStatus(t, payment.PaystackReference); status != models.PaymentStatusVerified {
+		t.Errorf("payment status = %s, want %s", status, models.PaymentStatusVerified)
+	}
+}
+
+func TestTransferSuccessWebhook(t *testing.T) {
+	f := newWebhookFixture(t)
+	ctx := context.Background()
+	transfer := f.createTransfer(t, "WITHDRAWAL-0e7c3b9a-41d2-4f6e-8a5b-2c1d0e9f8a7b", models.TransferPurposeWithdrawal, 1_500_000)
+
+	if err := f.webhooks.ProcessWebhook(ctx, loadWebhook(t, "transfer_success.json"), "sig", "hash"); err != nil {
+		t.Fatalf("ProcessWebhook: %v", err)
+	}
+
+	settled, err := f.transfers.GetTransferByReference(ctx, transfer.Reference)
+	if err != nil {
+		t.Fatalf("GetTransferByReference: %v", err)
+	}
+	if settled.Status != models.TransferStatusSuccess {
+		t.Errorf("transfer status = %s, want %s", settled.Status, models.TransferStatusSuccess)
+	}
+	completed := f.publisher.published("WithdrawalCompleted")
+	if len(completed) != 1 {
+		t.Fatalf("WithdrawalCompleted events = %d, want 1", len(completed))
+	}
+	if event := completed[0].(events.WithdrawalCompleted); event.WithdrawalID != transfer.SourceID {
+		t.Errorf("WithdrawalCompleted for withdrawal %s, want %s", event.WithdrawalID, transfer.SourceID)
+	}
+}
+
+func TestTransferFailedWebhook(t *testing.T) {
+	f := newWebhookFixture(t)
+	ctx := context.Background()
+	transfer := f.createTransfer(t, "REFUND-6d1f2a3b-4c5d-4e6f-9a7b-8c9d0e1f2a3b", models.TransferPurposeRefund, 40_000)
+
+	if err := f.webhooks.ProcessWebhook(ctx, loadWebhook(t, "transfer_failed.json"), "sig", "hash"); err != nil {
+		t.Fatalf("ProcessWebhook: %v", err)
+	}
+
+	failed, err := f.transfers.GetTransferByReference(ctx, transfer.Reference)
+	if err != nil {
+		t.Fatalf("GetTransferByReference: %v", err)
+	}
+	if failed.Status != models.TransferStatusFailed {
+		t.Errorf("transfer status = %s, want %s", failed.Status, models.TransferStatusFailed)
+	}
+	if n := len(f.publisher.published("RefundDisbursementFailed")); n != 1 {
+		t.Errorf("RefundDisbursementFailed events = %d, want 1", n)
+	}
+	if n := len(f.publisher.published("RefundDisbursementCompleted")); n != 0 {
+		t.Errorf("RefundDisbursementCompleted events = %d, want 0", n)
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofund/payments-service/internal/dto"
+	"github.com/gofund/shared/models"
+	"github.com/gofund/shared/validator"
+)
+
+// ErrChargeMismatch is returned for a successful charge whose amount or currency is not the
+// payment's; such a charge leaves the payment unverified for someone to look at
+var ErrChargeMismatch = errors.New("charge_mismatch")
+
+// webhookFieldKind is the JSON type a webhook data field must have
+type webhookFieldKind int
+
+const (
+	webhookFieldString webhookFieldKind = iota
+	webhookFieldNumber
+)
+
+// webhookField is a data field a webhook handler relies on
+type webhookField struct {
+	name string
+	kind webhookFieldKind
+}
+
+// webhookRequiredFields lists, per handled event, the data fields that must be present
+// and non-empty. Events not listed are acknowledged without being handled.
+var webhookRequiredFields = map[string][]webhookField{
+	"charge.success": {
+		{"reference", webhookFieldString},
+		{"status", webhookFieldString},
+		{"amount", webhookFieldNumber},
+		{"currency", webhookFieldString},
+	},
+	"charge.failed": {
+		{"reference", webhookFieldString},
+	},
+	"transfer.success": {
+		{"reference", webhookFieldString},
+		{"transfer_code", webhookFieldString},
+		{"amount", webhookFieldNumber},
+	},
+	"transfer.failed": {
+		{"reference", webhookFieldString},
+		{"transfer_code", webhookFieldString},
+	},
+	"transfer.reversed": {
+		{"reference", webhookFieldString},
+		{"transfer_code", webhookFieldString},
+	},
+}
+
+// validateWebhookPayload checks that a handled event carries the fields its handler needs
+func validateWebhookPayload(payload *dto.WebhookPayload) error {
+	if payload.Event == "" {
+		return fmt.Errorf("missing event type")
+	}
+
+	fields, handled := webhookRequiredFields[payload.Event]
+	if !handled {
+		return nil
+	}
+	if payload.Data == nil {
+		return fmt.Errorf("missing data")
+	}
+
+	for _, field := range fields {
+		value, ok := payload.Data[field.name]
+		if !ok || value == nil {
+			return fmt.Errorf("missing %s", field.name)
+		}
+		switch field.kind {
+		case webhookFieldString:
+			if s, ok := value.(string); !ok || s == "" {
+				return fmt.Errorf("%s must be a non-empty string", field.name)
+			}
+		case webhookFieldNumber:
+			// encoding/json decodes every JSON number into a float64
+			if n, ok := value.(float64); !ok || n <= 0 {
+				return fmt.Errorf("%s must be a positive number", field.name)
+			}
+		}
+	}
+
+	// A charge.success that is not a successful charge must not verify a payment
+	if payload.Event == "charge.success" && payload.Data["status"] != "success" {
+		return fmt.Errorf("charge.success with status %v", payload.Data["status"])
+	}
+
+	return nil
+}
+
+// checkChargeMatchesPayment checks that Paystack charged exactly the payment's amount, in
+// the payment's currency
+func checkChargeMatchesPayment(payment *models.Payment, amount int64, currency string) error {
+	if amount != payment.Amount || !validator.SameCurrency(currency, payment.Currency) {
+		return fmt.Errorf("%w: charged %d %s, payment is %d %s",
+			ErrChargeMismatch, amount, currency, payment.Amount, payment.Currency)
+	}
+	return nil
+}
+
+// webhookTimestamp returns when Paystack says the event happened: data.paid_at, falling
+// back to data.created_at. ok is false when neither is a valid timestamp.
+func webhookTimestamp(data map[string]interface{}) (time.Time, bool) {
+	for _, key := range []string{"paid_at", "created_at"} {
+		value, _ := data[key].(string)
+		if value == "" {
+			continue
+		}
+		// RFC 3339 parsing also accepts Paystack's fractional seconds
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// checkReplay rejects a handled event whose Paystack timestamp is older than the replay
+// window, or missing, unless its reference is still unresolved locally and so can only be
+// settled by this delivery
+func (ws *WebhookService) checkReplay(ctx context.Context, payload *dto.WebhookPayload, now time.Time) error {
+	if ws.replayWindow <= 0 {
+		return nil
+	}
+	if _, handled := webhookRequiredFields[payload.Event]; !handled {
+		return nil
+	}
+
+	timestamp, ok := webhookTimestamp(payload.Data)
+	if ok && now.Sub(timestamp) <= ws.replayWindow {
+		return nil
+	}
+
+	reference, _ := payload.Data["reference"].(string)
+	unresolved, err := ws.isReferenceUnresolved(ctx, payload.Event, reference)
+	if err != nil {
+		return fmt.Errorf("failed to check reference %s: %w", reference, err)
+	}
+	if unresolved {
+		log.Printf("[WARN] Accepting stale %s webhook for unresolved reference %s", payload.Event, reference)
+		return nil
+	}
+
+	return fmt.Errorf("rejected %s webhook for reference %s: timestamp outside the %s replay window", payload.Event, reference, ws.replayWindow)
+}
+
+// isReferenceUnresolved reports whether the payment or payout named by a webhook is still
+// waiting for Paystack's outcome
+func (ws *WebhookService) isReferenceUnresolved(ctx context.Context, event, reference string) (bool, error) {
+	switch event {
+	case "charge.success", "charge.failed":
+		payment, err := ws.paymentRepo.GetPaymentByReference(ctx, reference)
+		if err != nil {
+			// An unknown reference has nothing left to settle
+			return false, nil
+		}
+		return payment.Status == models.PaymentStatusInitiated || payment.Status == models.PaymentStatusPending, nil
+	default:
+		return ws.payoutService.IsTransferPending(ctx, reference)
+	}
+}
@@ -0,0 +1,51 @@
+// Package testmongo connects payments-service tests to MongoDB. Tests that need a database
+// call Open, which skips them unless TEST_MONGODB_URI points at a server they may create
+// databases on, e.g.
+//
+//	TEST_MONGODB_URI=mongodb://localhost:27017 go test ./...
+package testmongo
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnvURI names the environment variable holding the test server's connection string
+const EnvURI = "TEST_MONGODB_URI"
+
+// Open returns a new database on the test server. Each test gets a database of its own,
+// so tests and packages running in parallel never see each other's documents; it is
+// dropped when the test ends.
+func Open(t testing.TB) *mongo.Database {
+	t.Helper()
+	uri := os.Getenv(EnvURI)
+	if uri == "" {
+		t.Skipf("%s not set; skipping database test", EnvURI)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("failed to connect to test server: %v", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		t.Fatalf("failed to reach test server: %v", err)
+	}
+
+	db := client.Database("test_" + strings.ReplaceAll(uuid.NewString(), "-", ""))
+	t.Cleanup(func() {
+		if err := db.Drop(context.Background()); err != nil {
+			t.Logf("failed to drop test database %s: %v", db.Name(), err)
+		}
+		client.Disconnect(context.Background())
+	})
+	return db
+}
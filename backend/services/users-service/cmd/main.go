@@ -7,10 +7,15 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/buildinfo"
 	"github.com/gofund/shared/database"
+	"github.com/gofund/shared/health"
 	"github.com/gofund/shared/jwt"
 	"github.com/gofund/shared/messaging"
 	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/ratelimit"
+	"github.com/gofund/shared/testhooks"
+	"github.com/gofund/users-service/internal/middleware"
 	"github.com/gofund/users-service/internal/repository"
 	"github.com/gofund/users-service/internal/router"
 	"github.com/gofund/users-service/internal/service"
@@ -29,7 +34,8 @@ func main() {
 	serviceName := getEnv("DD_SERVICE", "users-service")
 	env := getEnv("DD_ENV", "dev")
 	version := getEnv("DD_VERSION", "1.0.0")
-	
+	buildinfo.LogStartup(serviceName, version)
+
 	if err := metrics.InitDatadog(serviceName, env, version); err != nil {
 		log.Printf("Warning: Failed to initialize Datadog: %v", err)
 	} else {
@@ -56,6 +62,7 @@ func main() {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	sessionRepo := repository.NewSessionRepository(db)
+	kycRepo := repository.NewKYCRepository(db)
 
 	// Initialize JWT service
 	jwtSecret := getEnv("JWT_SECRET", "your-super-secret-jwt-key-change-in-production")
@@ -94,9 +101,9 @@ func main() {
 
 	// Initialize services
 	eventService := service.NewEventService(publisher)
-	userService := service.NewUserService(userRepo)
+	userService := service.NewUserService(userRepo, service.NewGoalsClient(getEnv("GOALS_SERVICE_URL", "")))
 	authService := service.NewAuthService(userRepo, sessionRepo, jwtService, eventService)
-	kycService := service.NewKYCService(userRepo, eventService)
+	kycService := service.NewKYCService(userRepo, kycRepo, eventService)
 
 
 	// Start notification consumers if available
@@ -112,6 +119,9 @@ func main() {
 	// Initialize Gin router
 	r := gin.Default()
 
+	// Tag every request with an ID that logs and published events carry
+	r.Use(middleware.RequestID())
+
 	// Add Datadog APM middleware (automatically tracks HTTP requests)
 	r.Use(gintrace.Middleware(serviceName))
 	
@@ -119,8 +129,29 @@ func main() {
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 
+	// Build info
+	r.GET("/version", gin.WrapF(buildinfo.Handler(serviceName, version)))
+
+	// Per-IP limits on the public auth routes, against credential stuffing and email flooding
+	limitStore := ratelimit.NewMemoryStore()
+	authLimits := router.AuthRateLimits{
+		Login:              ratelimit.New("auth_login", ratelimit.PerMinute(getEnvInt("RATE_LIMIT_LOGIN_PER_MINUTE", 10)), limitStore),
+		Register:           ratelimit.New("auth_register", ratelimit.PerHour(getEnvInt("RATE_LIMIT_REGISTER_PER_HOUR", 10)), limitStore),
+		ForgotPassword:     ratelimit.New("auth_forgot_password", ratelimit.PerHour(getEnvInt("RATE_LIMIT_FORGOT_PASSWORD_PER_HOUR", 5)), limitStore),
+		ResetPassword:      ratelimit.New("auth_reset_password", ratelimit.PerMinute(getEnvInt("RATE_LIMIT_RESET_PASSWORD_PER_MINUTE", 10)), limitStore),
+		ResendVerification: ratelimit.New("auth_resend_verification", ratelimit.PerHour(getEnvInt("RATE_LIMIT_RESEND_VERIFICATION_PER_HOUR", 5)), limitStore),
+	}
+
 	// Setup all routes
-	router.SetupRoutes(r, authService, userService, kycService)
+	router.SetupRoutes(r, authService, userService, kycService, getEnv("IDENTITY_HEADER_SECRET", ""), authLimits,
+		health.Gorm("postgres", db),
+		health.RabbitMQ("rabbitmq", rabbitConn),
+	)
+
+	// Test hooks for the e2e suite (refused in production)
+	if testhooks.Enabled(env, getEnv("ENV", "")) {
+		router.SetupTestRoutes(r, userService)
+	}
 
 
 	// Start server
@@ -2,24 +2,29 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofund/users-service/internal/dto"
+	"github.com/gofund/shared/identity"
 	"github.com/gofund/users-service/internal/service"
 )
 
 // AuthController handles authentication-related endpoints
 type AuthController struct {
-	authService *service.AuthService
-	userService *service.UserService
+	authService    *service.AuthService
+	userService    *service.UserService
+	identitySecret string
 }
 
-// NewAuthController creates a new auth controller instance
-func NewAuthController(authService *service.AuthService, userService *service.UserService) *AuthController {
+// NewAuthController creates a new auth controller instance.
+// identitySecret signs the identity headers returned to Nginx; it may be empty.
+func NewAuthController(authService *service.AuthService, userService *service.UserService, identitySecret string) *AuthController {
 	return &AuthController{
-		authService: authService,
-		userService: userService,
+		authService:    authService,
+		userService:    userService,
+		identitySecret: identitySecret,
 	}
 }
 
@@ -58,6 +63,15 @@ func (ac *AuthController) VerifyToken(c *gin.Context) {
 	c.Header("X-User-ID", claims.UserID)
 	c.Header("X-User-Email", claims.Email)
 	c.Header("X-User-Role", strings.Join(claims.Roles, ","))
+	emailVerified := ""
+	if verified, err := ac.authService.IsEmailVerified(claims.UserID); err == nil {
+		emailVerified = strconv.FormatBool(verified)
+		c.Header(identity.HeaderEmailVerified, emailVerified)
+	}
+	if ac.identitySecret != "" {
+		// Lets downstream services tell gateway-set headers from injected ones
+		c.Header(identity.HeaderSignature, identity.Sign(ac.identitySecret, claims.UserID, strings.Join(claims.Roles, ","), emailVerified))
+	}
 	
 	c.Status(http.StatusOK)
 }
@@ -66,19 +80,14 @@ func (ac *AuthController) VerifyToken(c *gin.Context) {
 func (ac *AuthController) Login(c *gin.Context) {
 	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request format",
-			"details": err.Error(),
-		})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Authenticate user
-	response, err := ac.authService.Login(&req)
+	response, err := ac.authService.Login(&req, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -89,19 +98,14 @@ func (ac *AuthController) Login(c *gin.Context) {
 func (ac *AuthController) Register(c *gin.Context) {
 	var req dto.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request format",
-			"details": err.Error(),
-		})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Register user
-	response, err := ac.authService.Register(&req)
+	response, err := ac.authService.Register(&req, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -112,19 +116,14 @@ func (ac *AuthController) Register(c *gin.Context) {
 func (ac *AuthController) RefreshToken(c *gin.Context) {
 	var req dto.RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request format",
-			"details": err.Error(),
-		})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Refresh token
 	tokenPair, err := ac.authService.RefreshToken(&req)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -135,18 +134,13 @@ func (ac *AuthController) RefreshToken(c *gin.Context) {
 func (ac *AuthController) Logout(c *gin.Context) {
 	var req dto.RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request format",
-			"details": err.Error(),
-		})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Logout (invalidate refresh token session)
 	if err := ac.authService.Logout(req.RefreshToken); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -159,18 +153,13 @@ func (ac *AuthController) Logout(c *gin.Context) {
 func (ac *AuthController) ForgotPassword(c *gin.Context) {
 	var req dto.ForgotPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request format",
-			"details": err.Error(),
-		})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Initiate password reset
 	if err := ac.authService.ForgotPassword(&req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to process password reset request",
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -183,18 +172,13 @@ func (ac *AuthController) ForgotPassword(c *gin.Context) {
 func (ac *AuthController) ResetPassword(c *gin.Context) {
 	var req dto.ResetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request format",
-			"details": err.Error(),
-		})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Reset password
 	if err := ac.authService.ResetPassword(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -203,23 +187,116 @@ func (ac *AuthController) ResetPassword(c *gin.Context) {
 	})
 }
 
+// ResendEmailVerification handles requests for a fresh email verification link
+func (ac *AuthController) ResendEmailVerification(c *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	if err := ac.authService.ResendEmailVerification(&req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "If the email exists and is unverified, a verification link has been sent",
+	})
+}
+
+// VerifyEmail handles email verification with token
+func (ac *AuthController) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	if err := ac.authService.VerifyEmail(&req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email verified successfully",
+	})
+}
+
+// ChangePassword handles password changes by a logged-in user
+func (ac *AuthController) ChangePassword(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	if err := ac.authService.ChangePassword(userID, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password changed successfully",
+	})
+}
+
+// ListSessions returns the current user's active sessions
+func (ac *AuthController) ListSessions(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	sessions, err := ac.authService.ListSessions(userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+	})
+}
+
+// RevokeSession signs the current user out of one of their sessions
+func (ac *AuthController) RevokeSession(c *gin.Context) {
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := ac.authService.RevokeSession(userID, c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Session revoked successfully",
+	})
+}
+
 // GetProfile returns the current user's profile
 func (ac *AuthController) GetProfile(c *gin.Context) {
 	// Extract user ID from header (set by Nginx after auth verification)
-	userID := c.GetHeader("X-User-ID")
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User not authenticated",
-		})
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
 	// Get user profile
 	profile, err := ac.userService.GetProfile(userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -232,29 +309,22 @@ func (ac *AuthController) GetProfile(c *gin.Context) {
 // UpdateProfile handles user profile updates
 func (ac *AuthController) UpdateProfile(c *gin.Context) {
 	// Extract user ID from header (set by Nginx after auth verification)
-	userID := c.GetHeader("X-User-ID")
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User not authenticated",
-		})
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
 	var req dto.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request format",
-			"details": err.Error(),
-		})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Update user profile
 	profile, err := ac.userService.UpdateProfile(userID, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -264,3 +334,11 @@ func (ac *AuthController) UpdateProfile(c *gin.Context) {
 		"message": "Profile updated successfully",
 	})
 }
+
+// clientInfo describes the client making the request, for session metadata
+func clientInfo(c *gin.Context) dto.ClientInfo {
+	return dto.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
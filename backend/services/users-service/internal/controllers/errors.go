@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/gofund/shared/errors"
+)
+
+var errUnauthenticated = apperrors.Unauthenticated("unauthenticated", "User not authenticated")
+
+// respondError writes err as a JSON error response. The status comes from the error's kind
+// (see shared/errors.HTTPStatus), so controllers never pick one themselves.
+func respondError(c *gin.Context, err error) {
+	body := gin.H{"error": apperrors.Message(err)}
+	if domainErr := apperrors.As(err); domainErr != nil {
+		body["code"] = domainErr.Code
+		// A request that failed to bind carries the binding error as its cause
+		if domainErr.Kind == apperrors.KindValidation && domainErr.Err != nil {
+			body["details"] = domainErr.Err.Error()
+		}
+	}
+
+	c.JSON(apperrors.HTTPStatus(err), body)
+}
+
+// invalidRequest classifies a request body that failed to bind
+func invalidRequest(err error) error {
+	return &apperrors.DomainError{
+		Kind:    apperrors.KindValidation,
+		Code:    "invalid_request",
+		Message: "Invalid request format",
+		Err:     err,
+	}
+}
+
+// requireUser returns the authenticated user's ID from the X-User-ID header set by Nginx
+func requireUser(c *gin.Context) (string, error) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		return "", errUnauthenticated
+	}
+	return userID, nil
+}
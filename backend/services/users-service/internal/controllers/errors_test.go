@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/users-service/internal/service"
+)
+
+// respond writes err through respondError and returns the status and decoded body
+func respond(t *testing.T, err error) (int, map[string]interface{}) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	respondError(c, err)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %s: %v", w.Body.String(), err)
+	}
+	return w.Code, body
+}
+
+func TestRespondErrorStatusClasses(t *testing.T) {
+	tests := []struct {
+		err      error
+		wantCode int
+	}{
+		{service.ErrInvalidUserID, http.StatusBadRequest},
+		{service.ErrInvalidSessionID, http.StatusBadRequest},
+		{service.ErrBioTooLong, http.StatusBadRequest},
+		{errUnauthenticated, http.StatusUnauthorized},
+		{service.ErrInvalidCredentials, http.StatusUnauthorized},
+		{service.ErrRefreshTokenReused, http.StatusUnauthorized},
+		{service.ErrSelfReview, http.StatusForbidden},
+		{service.ErrUserNotFound, http.StatusNotFound},
+		{service.ErrSessionNotFound, http.StatusNotFound},
+		{service.ErrKYCSubmissionNotFound, http.StatusNotFound},
+		{service.ErrEmailExists, http.StatusConflict},
+		{service.ErrKYCSubmissionPending, http.StatusConflict},
+		{errors.New("failed to create session"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			status, body := respond(t, tt.err)
+			if status != tt.wantCode {
+				t.Errorf("status = %d, want %d", status, tt.wantCode)
+			}
+			if want := apperrors.Message(tt.err); body["error"] != want {
+				t.Errorf("error = %v, want %q", body["error"], want)
+			}
+			if _, ok := body["code"]; ok == (tt.wantCode == http.StatusInternalServerError) {
+				t.Errorf("code = %v: only domain errors carry one", body["code"])
+			}
+		})
+	}
+}
+
+func TestInvalidRequestCarriesBindingDetails(t *testing.T) {
+	status, body := respond(t, invalidRequest(errors.New("Key: 'LoginRequest.Email' Error:Field validation for 'Email' failed on the 'required' tag")))
+
+	if status != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", status, http.StatusBadRequest)
+	}
+	if body["code"] != "invalid_request" || body["details"] == nil {
+		t.Errorf("body = %v, want the invalid_request code with the binding details", body)
+	}
+
+	// Other validation errors have no cause to leak
+	if _, body := respond(t, service.ErrInvalidUserID); body["details"] != nil {
+		t.Errorf("details = %v, want none", body["details"])
+	}
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gofund/users-service/internal/dto"
 	"github.com/gofund/users-service/internal/service"
@@ -20,9 +21,47 @@ func NewKYCController(kycService *service.KYCService) *KYCController {
 	}
 }
 
+// Submit handles identity document submission for KYC verification
+// @Summary Submit an identity document for KYC verification
+// @Description Submit an identity document for admin review. Document images are externally hosted https URLs, required for every document type but NIN.
+// @Tags KYC
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.SubmitKYCRequest true "KYC submission request"
+// @Success 201 {object} dto.KYCStatusResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/users/kyc [post]
+func (c *KYCController) Submit(ctx *gin.Context) {
+	// Extract user ID from header (set by Nginx after auth verification)
+	userID, err := requireUser(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	var req dto.SubmitKYCRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, invalidRequest(err))
+		return
+	}
+
+	response, err := c.kycService.Submit(userID, &req)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
 // SubmitNIN handles NIN submission for KYC verification
 // @Summary Submit NIN for KYC verification
-// @Description Submit National Identification Number for basic KYC verification (auto-approved in this dummy implementation)
+// @Description Submit National Identification Number for admin review. Equivalent to POST /api/v1/users/kyc with a nin document.
 // @Tags KYC
 // @Accept json
 // @Produce json
@@ -31,31 +70,27 @@ func NewKYCController(kycService *service.KYCService) *KYCController {
 // @Success 200 {object} dto.KYCStatusResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/v1/users/kyc/submit-nin [post]
 func (c *KYCController) SubmitNIN(ctx *gin.Context) {
-	// Get user ID from context (set by auth middleware)
-	userID, exists := ctx.Get("user_id")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Error: "unauthorized",
-		})
+	// Extract user ID from header (set by Nginx after auth verification)
+	userID, err := requireUser(ctx)
+	if err != nil {
+		respondError(ctx, err)
 		return
 	}
 
 	var req dto.SubmitNINRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error: err.Error(),
-		})
+		respondError(ctx, invalidRequest(err))
 		return
 	}
 
-	response, err := c.kycService.SubmitNIN(userID.(string), &req)
+	response, err := c.kycService.SubmitNIN(userID, &req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error: err.Error(),
-		})
+		respondError(ctx, err)
 		return
 	}
 
@@ -70,25 +105,88 @@ func (c *KYCController) SubmitNIN(ctx *gin.Context) {
 // @Security BearerAuth
 // @Success 200 {object} dto.KYCStatusResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/users/kyc [get]
 // @Router /api/v1/users/kyc/status [get]
 func (c *KYCController) GetKYCStatus(ctx *gin.Context) {
-	// Get user ID from context (set by auth middleware)
-	userID, exists := ctx.Get("user_id")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Error: "unauthorized",
-		})
+	// Extract user ID from header (set by Nginx after auth verification)
+	userID, err := requireUser(ctx)
+	if err != nil {
+		respondError(ctx, err)
 		return
 	}
 
-	response, err := c.kycService.GetKYCStatus(userID.(string))
+	response, err := c.kycService.GetKYCStatus(userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error: err.Error(),
-		})
+		respondError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, response)
 }
+
+// ListPendingSubmissions lists KYC submissions awaiting review
+// @Summary List pending KYC submissions (admin)
+// @Description List KYC submissions awaiting review, oldest first, with their documents unmasked
+// @Tags KYC
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {object} dto.KYCSubmissionListResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/kyc/pending [get]
+func (c *KYCController) ListPendingSubmissions(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "20"))
+
+	response, err := c.kycService.ListPendingSubmissions(page, pageSize)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ReviewSubmission approves or rejects a pending KYC submission
+// @Summary Review a KYC submission (admin)
+// @Description Approve a pending KYC submission, marking its user verified, or reject it with a reason shown to the user
+// @Tags KYC
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "KYC submission ID"
+// @Param request body dto.ReviewKYCRequest true "Review decision"
+// @Success 200 {object} models.KYCSubmission
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/admin/kyc/{id}/review [post]
+func (c *KYCController) ReviewSubmission(ctx *gin.Context) {
+	reviewerID, err := requireUser(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	var req dto.ReviewKYCRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, invalidRequest(err))
+		return
+	}
+
+	submission, err := c.kycService.ReviewSubmission(reviewerID, ctx.Param("id"), &req)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, submission)
+}
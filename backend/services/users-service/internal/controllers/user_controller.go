@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofund/users-service/internal/dto"
@@ -28,19 +29,14 @@ func NewUserController(authService *service.AuthService, userService *service.Us
 func (uc *UserController) CreateLightweightUser(c *gin.Context) {
 	var req dto.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"details": err.Error(),
-		})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Register user (lightweight - no password)
-	response, err := uc.authService.Register(&req)
+	response, err := uc.authService.Register(&req, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -56,19 +52,14 @@ func (uc *UserController) CreateLightweightUser(c *gin.Context) {
 func (uc *UserController) SetPassword(c *gin.Context) {
 	var req dto.SetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"details": err.Error(),
-		})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Set password and return auth tokens
-	response, err := uc.authService.SetPassword(&req)
+	response, err := uc.authService.SetPassword(&req, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -79,11 +70,9 @@ func (uc *UserController) SetPassword(c *gin.Context) {
 // Requires authentication
 func (uc *UserController) UpdateSettlementAccount(c *gin.Context) {
 	// Extract user ID from header (set by Nginx after auth verification)
-	userID := c.GetHeader("X-User-ID")
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User not authenticated",
-		})
+	userID, err := requireUser(c)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -94,18 +83,13 @@ func (uc *UserController) UpdateSettlementAccount(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"details": err.Error(),
-		})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Update settlement account
 	if err := uc.userService.UpdateSettlementAccount(userID, req.BankName, req.AccountNumber, req.AccountName); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -114,3 +98,82 @@ func (uc *UserController) UpdateSettlementAccount(c *gin.Context) {
 		"message": "Settlement account updated successfully",
 	})
 }
+
+// GetPublicProfile returns the public profile of the user with the given username
+func (uc *UserController) GetPublicProfile(c *gin.Context) {
+	profile, err := uc.userService.GetPublicProfile(c.Param("username"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": profile,
+	})
+}
+
+// GetDisplayNames returns display names for a comma-separated list of user IDs.
+// Internal endpoint used by other services to label user IDs.
+func (uc *UserController) GetDisplayNames(c *gin.Context) {
+	names, err := uc.userService.GetDisplayNames(splitUserIDs(c.Query("ids")))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": names,
+	})
+}
+
+// GetContacts returns real names and emails for a comma-separated list of user IDs.
+// Internal endpoint for services that must identify users, never exposed externally.
+func (uc *UserController) GetContacts(c *gin.Context) {
+	contacts, err := uc.userService.GetContacts(splitUserIDs(c.Query("ids")))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": contacts,
+	})
+}
+
+// GetSettlementAccounts returns settlement accounts for a comma-separated list of user IDs.
+// Internal endpoint for services that pay money out to users, never exposed externally.
+func (uc *UserController) GetSettlementAccounts(c *gin.Context) {
+	accounts, err := uc.userService.GetSettlementAccounts(splitUserIDs(c.Query("ids")))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": accounts,
+	})
+}
+
+// GetUser returns a single user's ID, email, names and KYC status.
+// Internal endpoint used by notifications-service to address emails and goals-service to
+// check KYC before large withdrawals.
+func (uc *UserController) GetUser(c *gin.Context) {
+	user, err := uc.userService.GetUserSummary(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// splitUserIDs splits a comma-separated ids query parameter, dropping empty entries
+func splitUserIDs(ids string) []string {
+	var userIDs []string
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			userIDs = append(userIDs, id)
+		}
+	}
+	return userIDs
+}
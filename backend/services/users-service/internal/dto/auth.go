@@ -31,6 +31,21 @@ type SetPasswordRequest struct {
 	Password string `json:"password" binding:"required,min=8"`
 }
 
+// ClientInfo describes the client a session is created for
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// SessionResponse represents an active session in response
+type SessionResponse struct {
+	ID        string    `json:"id"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 // AuthResponse represents authentication response
 type AuthResponse struct {
 	User         *UserResponse `json:"user"`
@@ -48,6 +63,8 @@ type UserResponse struct {
 	FirstName     string          `json:"first_name"`
 	LastName      string          `json:"last_name"`
 	Phone         string          `json:"phone"`
+	AvatarURL     string          `json:"avatar_url,omitempty"`
+	Bio           string          `json:"bio,omitempty"`
 	EmailVerified bool            `json:"email_verified"`
 	PhoneVerified bool            `json:"phone_verified"`
 	KYCVerified   bool            `json:"kyc_verified"`
@@ -61,6 +78,9 @@ type UpdateProfileRequest struct {
 	FirstName string `json:"first_name" binding:"omitempty,min=2,max=50"`
 	LastName  string `json:"last_name" binding:"omitempty,min=2,max=50"`
 	Phone     string `json:"phone" binding:"omitempty"`
+	// AvatarURL and Bio are left alone when omitted and cleared when empty
+	AvatarURL *string `json:"avatar_url"`
+	Bio       *string `json:"bio"`
 }
 
 // ForgotPasswordRequest represents a forgot password request
@@ -74,6 +94,24 @@ type ResetPasswordRequest struct {
 	NewPassword string `json:"new_password" binding:"required,min=8"`
 }
 
+// ResendVerificationRequest represents a request to resend the email verification link
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// VerifyEmailRequest represents an email verification request
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ChangePasswordRequest represents a logged-in password change request. RefreshToken
+// identifies the caller's session, which is kept; every other session is signed out.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+	RefreshToken    string `json:"refresh_token"`
+}
+
 // RefreshRequest represents a token refresh request
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
@@ -3,4 +3,5 @@ package dto
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
 }
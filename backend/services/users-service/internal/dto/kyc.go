@@ -1,15 +1,54 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/gofund/shared/models"
+)
 
 // SubmitNINRequest represents a NIN submission request
 type SubmitNINRequest struct {
 	NIN string `json:"nin" binding:"required,len=11"`
 }
 
+// SubmitKYCRequest is an identity document sent for admin review. Document URLs point at
+// externally hosted images of the document and are required for everything but a NIN.
+type SubmitKYCRequest struct {
+	DocumentType   models.KYCDocumentType `json:"document_type" binding:"required"`
+	DocumentNumber string                 `json:"document_number" binding:"required,max=50"`
+	DocumentURLs   []string               `json:"document_urls" binding:"omitempty,max=5"`
+}
+
+// ReviewKYCRequest is an admin's decision on a pending KYC submission. A reason is
+// required when rejecting and is shown to the user.
+type ReviewKYCRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=approve reject"`
+	Reason   string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// KYCSubmissionSummary is a user's view of their latest KYC submission
+type KYCSubmissionSummary struct {
+	ID              string                 `json:"id"`
+	DocumentType    models.KYCDocumentType `json:"document_type"`
+	DocumentNumber  string                 `json:"document_number"` // Masked for privacy
+	Status          models.KYCStatus       `json:"status"`
+	RejectionReason string                 `json:"rejection_reason,omitempty"`
+	SubmittedAt     time.Time              `json:"submitted_at"`
+	ReviewedAt      *time.Time             `json:"reviewed_at,omitempty"`
+}
+
 // KYCStatusResponse represents KYC status information
 type KYCStatusResponse struct {
-	KYCVerified   bool       `json:"kyc_verified"`
-	KYCVerifiedAt *time.Time `json:"kyc_verified_at,omitempty"`
-	NIN           string     `json:"nin,omitempty"` // Masked for privacy
+	KYCVerified   bool                  `json:"kyc_verified"`
+	KYCVerifiedAt *time.Time            `json:"kyc_verified_at,omitempty"`
+	NIN           string                `json:"nin,omitempty"` // Masked for privacy
+	Submission    *KYCSubmissionSummary `json:"submission,omitempty"`
+}
+
+// KYCSubmissionListResponse is a page of KYC submissions awaiting review, oldest first
+type KYCSubmissionListResponse struct {
+	Data  []models.KYCSubmission `json:"data"`
+	Total int64                  `json:"total"`
+	Page  int                    `json:"page"`
+	Size  int                    `json:"size"`
 }
@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+// UserDisplayName is the public-facing name of a user, served to other services
+type UserDisplayName struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+}
+
+// PublicProfile is what anyone may see about a user. It must never carry contact,
+// verification or settlement details.
+type PublicProfile struct {
+	Username        string    `json:"username"`
+	FirstName       string    `json:"first_name"`
+	AvatarURL       string    `json:"avatar_url,omitempty"`
+	Bio             string    `json:"bio,omitempty"`
+	JoinedAt        time.Time `json:"joined_at"`
+	PublicGoalCount int64     `json:"public_goal_count"`
+}
+
+// UserSummary identifies a single user to other services, e.g. notifications-service
+// resolving where to send an email
+type UserSummary struct {
+	ID          string `json:"id"`
+	Email       string `json:"email"`
+	Username    string `json:"username"`
+	FirstName   string `json:"first_name"`
+	KYCVerified bool   `json:"kyc_verified"`
+}
+
+// UserContact is a user's real name and email, served to other services that are
+// required to identify a user (e.g. large contributors to their goal owner)
+type UserContact struct {
+	ID        string `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+}
+
+// UserSettlementAccount is the bank account a user is paid out to, served to services that
+// disburse money to users (e.g. goals-service planning refunds)
+type UserSettlementAccount struct {
+	ID                      string `json:"id"`
+	SettlementBankName      string `json:"settlement_bank_name"`
+	SettlementAccountNumber string `json:"settlement_account_number"`
+	SettlementAccountName   string `json:"settlement_account_name"`
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/httperr"
+	"github.com/gofund/shared/identity"
+)
+
+// RequireRole ensures the X-User-Roles header (set by Nginx after auth verification)
+// includes at least one of roles
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, role := range strings.Split(c.GetHeader(identity.HeaderUserRoles), ",") {
+			for _, required := range roles {
+				if strings.TrimSpace(role) == required {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		httperr.Respond(c.Writer, c.Request, apperrors.Forbidden("role_required", "Forbidden: "+strings.Join(roles, " or ")+" role required"), nil)
+		c.Abort()
+	}
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofund/shared/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrPendingKYCSubmission is returned when a user already has a KYC submission
+// awaiting review
+var ErrPendingKYCSubmission = errors.New("pending KYC submission exists")
+
+// KYCRepository handles KYC submission database operations
+type KYCRepository struct {
+	db *gorm.DB
+}
+
+// NewKYCRepository creates a new KYC repository instance
+func NewKYCRepository(db *gorm.DB) *KYCRepository {
+	return &KYCRepository{db: db}
+}
+
+// CreateSubmission stores a new pending KYC submission. The one-pending-per-user index
+// turns a concurrent second submission into ErrPendingKYCSubmission.
+func (r *KYCRepository) CreateSubmission(submission *models.KYCSubmission) error {
+	if err := r.db.Create(submission).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return ErrPendingKYCSubmission
+		}
+		return err
+	}
+	return nil
+}
+
+// GetSubmissionByID retrieves a KYC submission by ID
+func (r *KYCRepository) GetSubmissionByID(id uuid.UUID) (*models.KYCSubmission, error) {
+	var submission models.KYCSubmission
+	if err := r.db.First(&submission, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// GetLatestSubmissionByUserID retrieves a user's most recent KYC submission
+func (r *KYCRepository) GetLatestSubmissionByUserID(userID uuid.UUID) (*models.KYCSubmission, error) {
+	var submission models.KYCSubmission
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").First(&submission).Error; err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// HasPendingSubmission reports whether a user has a KYC submission awaiting review
+func (r *KYCRepository) HasPendingSubmission(userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.KYCSubmission{}).
+		Where("user_id = ? AND status = ?", userID, models.KYCStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetPendingSubmissions returns a page of KYC submissions awaiting review, oldest first,
+// with the total count
+func (r *KYCRepository) GetPendingSubmissions(limit, offset int) ([]models.KYCSubmission, int64, error) {
+	var submissions []models.KYCSubmission
+	var total int64
+
+	query := r.db.Model(&models.KYCSubmission{}).Where("status = ?", models.KYCStatusPending)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Limit(limit).Offset(offset).
+		Order("created_at ASC").
+		Find(&submissions).Error
+
+	return submissions, total, err
+}
+
+// ReviewSubmission records an admin's decision on a pending submission and, when it is
+// approved, marks its user KYC verified in the same transaction. It returns false if the
+// submission was no longer pending.
+func (r *KYCRepository) ReviewSubmission(submission *models.KYCSubmission) (bool, error) {
+	reviewed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.KYCSubmission{}).
+			Where("id = ? AND status = ?", submission.ID, models.KYCStatusPending).
+			Updates(map[string]interface{}{
+				"status":           submission.Status,
+				"reviewer_id":      submission.ReviewerID,
+				"rejection_reason": submission.RejectionReason,
+				"reviewed_at":      submission.ReviewedAt,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		reviewed = true
+
+		if submission.Status != models.KYCStatusApproved {
+			return nil
+		}
+		updates := map[string]interface{}{
+			"kyc_verified":    true,
+			"kyc_verified_at": submission.ReviewedAt,
+		}
+		if submission.DocumentType == models.KYCDocumentNIN {
+			updates["nin"] = submission.DocumentNumber
+		}
+		return tx.Model(&models.User{}).Where("id = ?", submission.UserID).Updates(updates).Error
+	})
+	return reviewed, err
+}
@@ -9,6 +9,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrSessionNotFound is returned when no session matches a token hash
+var ErrSessionNotFound = errors.New("session not found")
+
 // SessionRepository handles session database operations
 type SessionRepository struct {
 	db *gorm.DB
@@ -29,17 +32,19 @@ func (r *SessionRepository) GetSessionByTokenHash(tokenHash string) (*models.Ses
 	var session models.Session
 	if err := r.db.Preload("User").First(&session, "token_hash = ?", tokenHash).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("session not found")
+			return nil, ErrSessionNotFound
 		}
 		return nil, err
 	}
 	return &session, nil
 }
 
-// GetUserSessions retrieves all sessions for a user
-func (r *SessionRepository) GetUserSessions(userID uuid.UUID) ([]models.Session, error) {
+// GetSessionsByUserID retrieves a user's active sessions: unexpired and not yet rotated
+func (r *SessionRepository) GetSessionsByUserID(userID uuid.UUID) ([]models.Session, error) {
 	var sessions []models.Session
-	if err := r.db.Where("user_id = ?", userID).Find(&sessions).Error; err != nil {
+	if err := r.db.Where("user_id = ? AND rotated_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&sessions).Error; err != nil {
 		return nil, err
 	}
 	return sessions, nil
@@ -52,7 +57,19 @@ func (r *SessionRepository) DeleteSession(tokenHash string) error {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("session not found")
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteSessionByID deletes one of a user's sessions by ID
+func (r *SessionRepository) DeleteSessionByID(userID, sessionID uuid.UUID) error {
+	result := r.db.Where("id = ? AND user_id = ?", sessionID, userID).Delete(&models.Session{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
 	}
 	return nil
 }
@@ -62,11 +79,33 @@ func (r *SessionRepository) DeleteUserSessions(userID uuid.UUID) error {
 	return r.db.Where("user_id = ?", userID).Delete(&models.Session{}).Error
 }
 
+// DeleteOtherUserSessions deletes all sessions for a user except the one with keepTokenHash
+func (r *SessionRepository) DeleteOtherUserSessions(userID uuid.UUID, keepTokenHash string) error {
+	return r.db.Where("user_id = ? AND token_hash <> ?", userID, keepTokenHash).Delete(&models.Session{}).Error
+}
+
 // DeleteExpiredSessions deletes all expired sessions
 func (r *SessionRepository) DeleteExpiredSessions() error {
 	return r.db.Where("expires_at < ?", time.Now()).Delete(&models.Session{}).Error
 }
 
+// RotateSession marks the session with oldTokenHash rotated and creates next in its
+// place. It returns false, creating nothing, if the old session was already rotated.
+func (r *SessionRepository) RotateSession(oldTokenHash string, next *models.Session) (bool, error) {
+	rotated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Session{}).
+			Where("token_hash = ? AND rotated_at IS NULL", oldTokenHash).
+			Update("rotated_at", time.Now())
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		rotated = true
+		return tx.Create(next).Error
+	})
+	return rotated, err
+}
+
 // UpdateSession updates an existing session
 func (r *SessionRepository) UpdateSession(session *models.Session) error {
 	return r.db.Save(session).Error
@@ -49,6 +49,16 @@ func (r *UserRepository) GetUserByID(id uuid.UUID) (*models.User, error) {
 	return &user, nil
 }
 
+// GetUsersByIDs retrieves the users with the given IDs; unknown IDs are skipped
+func (r *UserRepository) GetUsersByIDs(ids []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
 // GetUserByEmail retrieves a user by email
 func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
 	var user models.User
@@ -137,6 +147,59 @@ func (r *UserRepository) DeleteExpiredPasswordResetTokens() error {
 	return r.db.Where("expires_at < ?", time.Now()).Delete(&models.PasswordResetToken{}).Error
 }
 
+// ReplaceEmailVerificationToken stores a new email verification token, invalidating any
+// earlier unused tokens for the same user
+func (r *UserRepository) ReplaceEmailVerificationToken(token *models.EmailVerificationToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.EmailVerificationToken{}).
+			Where("user_id = ? AND used = false", token.UserID).
+			Update("used", true).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// GetEmailVerificationToken retrieves an unused email verification token by token hash
+func (r *UserRepository) GetEmailVerificationToken(tokenHash string) (*models.EmailVerificationToken, error) {
+	var token models.EmailVerificationToken
+	if err := r.db.First(&token, "token_hash = ? AND used = false", tokenHash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("token not found")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ConsumeEmailVerificationToken marks a token used and its user's email verified. It
+// returns false if the token had already been used.
+func (r *UserRepository) ConsumeEmailVerificationToken(token *models.EmailVerificationToken) (bool, error) {
+	consumed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.EmailVerificationToken{}).
+			Where("id = ? AND used = false", token.ID).
+			Update("used", true)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		consumed = true
+		return tx.Model(&models.User{}).
+			Where("id = ?", token.UserID).
+			Update("email_verified", true).Error
+	})
+	return consumed, err
+}
+
+// IsEmailVerified reports whether a user has verified their email
+func (r *UserRepository) IsEmailVerified(userID uuid.UUID) (bool, error) {
+	var user models.User
+	if err := r.db.Select("email_verified").First(&user, "id = ?", userID).Error; err != nil {
+		return false, err
+	}
+	return user.EmailVerified, nil
+}
+
 // GetUserByNIN retrieves a user by NIN (National Identification Number)
 func (r *UserRepository) GetUserByNIN(nin string) (*models.User, error) {
 	var user models.User
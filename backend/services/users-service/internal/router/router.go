@@ -2,39 +2,66 @@ package router
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/health"
+	"github.com/gofund/shared/models"
+	"github.com/gofund/shared/ratelimit"
 	"github.com/gofund/users-service/internal/controllers"
+	"github.com/gofund/users-service/internal/middleware"
 	"github.com/gofund/users-service/internal/service"
 )
 
+// AuthRateLimits are the limiters guarding the public auth routes. A nil limiter lets
+// every request through.
+type AuthRateLimits struct {
+	Login              *ratelimit.Limiter
+	Register           *ratelimit.Limiter
+	ForgotPassword     *ratelimit.Limiter
+	ResetPassword      *ratelimit.Limiter
+	ResendVerification *ratelimit.Limiter
+}
+
 // SetupRoutes configures all routes for the Users Service
-func SetupRoutes(r *gin.Engine, authService *service.AuthService, userService *service.UserService, kycService *service.KYCService) {
+func SetupRoutes(r *gin.Engine, authService *service.AuthService, userService *service.UserService, kycService *service.KYCService, identitySecret string, limits AuthRateLimits, readiness ...health.Checker) {
 	// Initialize controllers
-	authController := controllers.NewAuthController(authService, userService)
+	authController := controllers.NewAuthController(authService, userService, identitySecret)
 	userController := controllers.NewUserController(authService, userService)
 	kycController := controllers.NewKYCController(kycService)
 
 
-	// Health check endpoint
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "healthy", "service": "users-service"})
-	})
+	// Health checks: live only says the process is up, ready probes dependencies
+	r.GET("/health/live", gin.WrapF(health.LiveHandler("users-service")))
+	r.GET("/health/ready", gin.WrapF(health.ReadyHandler(health.DefaultTimeout, readiness...)))
 
 	// Internal routes (called by Nginx, not exposed externally)
 	internal := r.Group("/internal")
 	{
 		// Auth verification endpoint for Nginx auth_request
 		internal.GET("/verify", authController.VerifyToken)
+
+		// Display names for other services (e.g. goals-service contribution feeds)
+		internal.GET("/users/display-names", userController.GetDisplayNames)
+
+		// Real names and emails for services that must identify users (e.g. large contributors)
+		internal.GET("/users/contacts", userController.GetContacts)
+
+		// Settlement accounts for services that pay users out (e.g. goals-service refunds)
+		internal.GET("/users/settlement-accounts", userController.GetSettlementAccounts)
+
+		// A single user's email and names (e.g. notifications-service addressing emails)
+		internal.GET("/users/:id", userController.GetUser)
 	}
 
 	// Public authentication routes (no auth required)
 	auth := r.Group("/auth")
 	{
-		auth.POST("/login", authController.Login)
-		auth.POST("/register", authController.Register)
+		auth.POST("/login", ratelimit.Middleware(limits.Login, ratelimit.ByClientIP), authController.Login)
+		auth.POST("/register", ratelimit.Middleware(limits.Register, ratelimit.ByClientIP), authController.Register)
 		auth.POST("/refresh", authController.RefreshToken)
 		auth.POST("/logout", authController.Logout)
-		auth.POST("/forgot-password", authController.ForgotPassword)
-		auth.POST("/reset-password", authController.ResetPassword)
+		auth.POST("/forgot-password", ratelimit.Middleware(limits.ForgotPassword, ratelimit.ByClientIP), authController.ForgotPassword)
+		auth.POST("/reset-password", ratelimit.Middleware(limits.ResetPassword, ratelimit.ByClientIP), authController.ResetPassword)
+		auth.POST("/verify-email", authController.VerifyEmail)
+		auth.POST("/verify-email/resend", ratelimit.Middleware(limits.ResendVerification, ratelimit.ByClientIP), authController.ResendEmailVerification)
 	}
 
 	// Protected user routes (auth required - handled by Nginx)
@@ -43,8 +70,15 @@ func SetupRoutes(r *gin.Engine, authService *service.AuthService, userService *s
 		users.GET("/profile", authController.GetProfile)
 		users.PUT("/profile", authController.UpdateProfile)
 		users.PUT("/settlement-account", userController.UpdateSettlementAccount)
+		users.PUT("/password", authController.ChangePassword)
+		users.GET("/sessions", authController.ListSessions)
+		users.DELETE("/sessions/:id", authController.RevokeSession)
+		// Public profile (no auth - Nginx lets this path through)
+		users.GET("/:username/public", userController.GetPublicProfile)
 		
 		// KYC verification routes
+		users.POST("/kyc", kycController.Submit)
+		users.GET("/kyc", kycController.GetKYCStatus)
 		kyc := users.Group("/kyc")
 		{
 			kyc.POST("/submit-nin", kycController.SubmitNIN)
@@ -52,6 +86,14 @@ func SetupRoutes(r *gin.Engine, authService *service.AuthService, userService *s
 		}
 	}
 
+	// Admin KYC review routes (auth required - handled by Nginx, admin role enforced here)
+	adminKYC := r.Group("/admin/kyc")
+	adminKYC.Use(middleware.RequireRole(string(models.UserRoleAdmin)))
+	{
+		adminKYC.GET("/pending", kycController.ListPendingSubmissions)
+		adminKYC.POST("/:id/review", kycController.ReviewSubmission)
+	}
+
 	// Public user routes (for guest contributions/onboarding)
 	publicUsers := r.Group("/public/users")
 	{
@@ -69,4 +111,14 @@ func SetupRoutes(r *gin.Engine, authService *service.AuthService, userService *s
 	//     roles.PUT("/:id", roleController.UpdateRole)
 	//     roles.DELETE("/:id", roleController.DeleteRole)
 	// }
-}
\ No newline at end of file
+}
+
+// SetupTestRoutes registers the non-production hooks used by the e2e suite
+func SetupTestRoutes(r *gin.Engine, userService *service.UserService) {
+	testHookController := controllers.NewTestHookController(userService)
+
+	test := r.Group("/test")
+	{
+		test.POST("/users/:id/verify-email", testHookController.VerifyEmail)
+	}
+}
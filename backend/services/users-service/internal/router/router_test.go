@@ -0,0 +1,117 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/jwt"
+	"github.com/gofund/shared/models"
+	"github.com/gofund/shared/password"
+	"github.com/gofund/users-service/internal/repository"
+	"github.com/gofund/users-service/internal/service"
+	"github.com/gofund/users-service/internal/testdb"
+	"github.com/google/uuid"
+)
+
+// newTestRouter mounts every route over a fresh test schema and returns the user it seeded,
+// whose password is "correct horse"
+func newTestRouter(t *testing.T) (*gin.Engine, *models.User) {
+	t.Helper()
+	db := testdb.Open(t)
+	userRepo := repository.NewUserRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+
+	hash, err := password.HashPassword("correct horse", password.DefaultConfig())
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	user := &models.User{Email: "ada@example.com", Username: "ada", PasswordHash: hash, Role: models.UserRoleUser}
+	if err := userRepo.CreateUser(user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	authService := service.NewAuthService(userRepo, sessionRepo, jwt.NewJWTService("secret", time.Minute, time.Hour), nil)
+	userService := service.NewUserService(userRepo, nil)
+	kycService := service.NewKYCService(userRepo, repository.NewKYCRepository(db), nil)
+	SetupRoutes(r, authService, userService, kycService, "", AuthRateLimits{})
+	return r, user
+}
+
+func TestEndpointStatusCodes(t *testing.T) {
+	r, user := newTestRouter(t)
+
+	tests := []struct {
+		name         string
+		method, path string
+		userID       string
+		body         string
+		wantCode     int
+	}{
+		{name: "anonymous profile", method: http.MethodGet, path: "/users/profile", wantCode: http.StatusUnauthorized},
+		{name: "malformed user ID", method: http.MethodGet, path: "/users/profile", userID: "not-a-uuid", wantCode: http.StatusBadRequest},
+		{name: "missing user", method: http.MethodGet, path: "/users/profile", userID: uuid.NewString(), wantCode: http.StatusNotFound},
+		{name: "own profile", method: http.MethodGet, path: "/users/profile", userID: user.ID.String(), wantCode: http.StatusOK},
+		{name: "malformed session ID", method: http.MethodDelete, path: "/users/sessions/not-a-uuid", userID: user.ID.String(), wantCode: http.StatusBadRequest},
+		{name: "missing session", method: http.MethodDelete, path: "/users/sessions/" + uuid.NewString(), userID: user.ID.String(), wantCode: http.StatusNotFound},
+		{name: "malformed login", method: http.MethodPost, path: "/auth/login", body: `{"email":`, wantCode: http.StatusBadRequest},
+		{name: "wrong password", method: http.MethodPost, path: "/auth/login", body: `{"email":"ada@example.com","password":"wrong horse"}`, wantCode: http.StatusUnauthorized},
+		{name: "unknown email", method: http.MethodPost, path: "/auth/login", body: `{"email":"bob@example.com","password":"correct horse"}`, wantCode: http.StatusUnauthorized},
+		{name: "login", method: http.MethodPost, path: "/auth/login", body: `{"email":"ada@example.com","password":"correct horse"}`, wantCode: http.StatusOK},
+		{name: "registered email", method: http.MethodPost, path: "/auth/register", body: `{"email":"ada@example.com","password":"another horse"}`, wantCode: http.StatusConflict},
+		{name: "non-admin reviewing KYC", method: http.MethodGet, path: "/admin/kyc/pending", userID: user.ID.String(), wantCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.userID != "" {
+				req.Header.Set("X-User-ID", tt.userID)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("%s %s = %d, want %d: %s", tt.method, tt.path, w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestInternalUserLookup checks the user summary notifications-service addresses emails from
+func TestInternalUserLookup(t *testing.T) {
+	r, user := newTestRouter(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/internal/users/"+user.ID.String(), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var summary map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("decode %s: %v", w.Body.String(), err)
+	}
+	if summary["id"] != user.ID.String() || summary["email"] != user.Email || summary["username"] != user.Username {
+		t.Errorf("summary = %v, want user %s with their email and username", summary, user.ID)
+	}
+	if _, ok := summary["password_hash"]; ok {
+		t.Error("the summary includes the password hash")
+	}
+
+	for path, want := range map[string]int{
+		"/internal/users/" + uuid.NewString(): http.StatusNotFound,
+		"/internal/users/not-a-uuid":          http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, w.Code, want)
+		}
+	}
+}
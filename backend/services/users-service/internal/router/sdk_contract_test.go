@@ -0,0 +1,430 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/sdk"
+	"github.com/gofund/shared/events"
+	"github.com/gofund/shared/jwt"
+	"github.com/gofund/shared/models"
+	"github.com/gofund/shared/password"
+	"github.com/gofund/users-service/internal/repository"
+	"github.com/gofund/users-service/internal/service"
+	"github.com/gofund/users-service/internal/testdb"
+	"github.com/google/uuid"
+)
+
+const contractIdentitySecret = "contract-identity-secret"
+
+// usersFixture is the state the SDK contracts run against: ada, who signs in with
+// "correct horse" and has a second session, and an admin who reviews KYC. Contracts
+// record what later ones need, such as ada's latest refresh token.
+type usersFixture struct {
+	adaID, adminID, otherSessionID uuid.UUID
+	users                          *repository.UserRepository
+
+	refreshToken      string
+	resetToken        string
+	verificationToken string
+	submissionID      string
+}
+
+// Publish records the tokens users-service emails, so the contracts can follow the links
+func (f *usersFixture) Publish(eventType string, event interface{}) error {
+	switch e := event.(type) {
+	case events.PasswordResetRequested:
+		f.resetToken = e.Token
+	case events.EmailVerificationRequested:
+		f.verificationToken = e.Token
+	}
+	return nil
+}
+
+// PublishContext records like Publish
+func (f *usersFixture) PublishContext(_ context.Context, eventType string, event interface{}) error {
+	return f.Publish(eventType, event)
+}
+
+// sdkContract is one SDK method called against the users routes, as ada or, when admin
+// is set, as the admin. check, if any, inspects the result when the routes are backed by
+// a database.
+type sdkContract struct {
+	name  string
+	admin bool
+	call  func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error)
+	check func(t *testing.T, f *usersFixture, result interface{})
+}
+
+// sdkContracts covers every UsersClient method. They run in order, each seeing what the
+// earlier ones changed.
+func sdkContracts() []sdkContract {
+	return []sdkContract{
+		{
+			name: "Login",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				auth, err := c.Login(ctx, &sdk.LoginRequest{Email: "ada@example.com", Password: "correct horse"})
+				if err == nil && auth != nil {
+					f.refreshToken = auth.RefreshToken
+				}
+				return auth, err
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				auth := result.(*sdk.AuthResponse)
+				if auth.User == nil || auth.User.ID != f.adaID.String() || auth.AccessToken == "" || auth.RefreshToken == "" {
+					t.Errorf("auth = %+v, want tokens for ada", auth)
+				}
+			},
+		},
+		{
+			name: "RefreshToken",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				tokens, err := c.RefreshToken(ctx, f.refreshToken)
+				if err == nil && tokens != nil {
+					f.refreshToken = tokens.RefreshToken
+				}
+				return tokens, err
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				tokens := result.(*sdk.TokenPair)
+				if tokens.AccessToken == "" || tokens.RefreshToken == "" || tokens.ExpiresIn <= 0 {
+					t.Errorf("tokens = %+v, want a fresh pair", tokens)
+				}
+			},
+		},
+		{
+			name: "GetProfile",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return c.GetProfile(ctx)
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				user := result.(*sdk.User)
+				if user.ID != f.adaID.String() || user.Username != "ada" || user.Role != string(models.UserRoleUser) {
+					t.Errorf("profile = %+v, want ada's", user)
+				}
+			},
+		},
+		{
+			name: "UpdateProfile",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				bio := "Counts things"
+				return c.UpdateProfile(ctx, &sdk.UpdateProfileRequest{FirstName: "Augusta", Bio: &bio})
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				user := result.(*sdk.User)
+				if user.FirstName != "Augusta" || user.Bio != "Counts things" {
+					t.Errorf("profile = %+v, want the new name and bio", user)
+				}
+			},
+		},
+		{
+			name: "GetPublicProfile",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return c.GetPublicProfile(ctx, "ada")
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				profile := result.(*sdk.PublicProfile)
+				if profile.Username != "ada" || profile.FirstName != "Augusta" || profile.JoinedAt.IsZero() {
+					t.Errorf("public profile = %+v, want ada's", profile)
+				}
+			},
+		},
+		{
+			name: "UpdateSettlementAccount",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return nil, c.UpdateSettlementAccount(ctx, &sdk.SettlementAccountRequest{BankName: "Test Bank", AccountNumber: "0123456789", AccountName: "Ada Lovelace"})
+			},
+			check: func(t *testing.T, f *usersFixture, _ interface{}) {
+				user, err := f.users.GetUserByID(f.adaID)
+				if err != nil {
+					t.Fatalf("GetUserByID: %v", err)
+				}
+				if user.SettlementBankName != "Test Bank" || user.SettlementAccountNumber != "0123456789" || user.SettlementAccountName != "Ada Lovelace" {
+					t.Errorf("settlement account = %q %q %q, want the one sent", user.SettlementBankName, user.SettlementAccountNumber, user.SettlementAccountName)
+				}
+			},
+		},
+		{
+			name: "ListSessions",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return c.ListSessions(ctx)
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				sessions := result.([]sdk.Session)
+				if len(sessions) != 2 {
+					t.Fatalf("sessions = %+v, want the refreshed login and the other session", sessions)
+				}
+				if sessions[1].ID != f.otherSessionID.String() {
+					t.Errorf("oldest session = %s, want %s", sessions[1].ID, f.otherSessionID)
+				}
+			},
+		},
+		{
+			name: "RevokeSession",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return nil, c.RevokeSession(ctx, f.otherSessionID.String())
+			},
+		},
+		{
+			name: "ChangePassword",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return nil, c.ChangePassword(ctx, &sdk.ChangePasswordRequest{CurrentPassword: "correct horse", NewPassword: "battery staple", RefreshToken: f.refreshToken})
+			},
+		},
+		{
+			// Succeeds only if ChangePassword kept the session it was told to keep
+			name: "Logout",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return nil, c.Logout(ctx, f.refreshToken)
+			},
+		},
+		{
+			name: "ForgotPassword",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return nil, c.ForgotPassword(ctx, "ada@example.com")
+			},
+			check: func(t *testing.T, f *usersFixture, _ interface{}) {
+				if f.resetToken == "" {
+					t.Error("no password reset token was published")
+				}
+			},
+		},
+		{
+			name: "ResetPassword",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return nil, c.ResetPassword(ctx, &sdk.ResetPasswordRequest{Token: f.resetToken, NewPassword: "correct horse"})
+			},
+		},
+		{
+			name: "ResendEmailVerification",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return nil, c.ResendEmailVerification(ctx, "ada@example.com")
+			},
+			check: func(t *testing.T, f *usersFixture, _ interface{}) {
+				if f.verificationToken == "" {
+					t.Error("no email verification token was published")
+				}
+			},
+		},
+		{
+			name: "VerifyEmail",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return nil, c.VerifyEmail(ctx, f.verificationToken)
+			},
+			check: func(t *testing.T, f *usersFixture, _ interface{}) {
+				if verified, err := f.users.IsEmailVerified(f.adaID); err != nil || !verified {
+					t.Errorf("IsEmailVerified = %v, %v; want true", verified, err)
+				}
+			},
+		},
+		{
+			name: "Register",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return c.Register(ctx, &sdk.RegisterRequest{Email: "bob@example.com", Username: "bob", Password: "another horse", FirstName: "Bob"})
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				auth := result.(*sdk.AuthResponse)
+				if auth.User == nil || auth.User.Email != "bob@example.com" || auth.User.Username != "bob" || auth.RefreshToken == "" {
+					t.Errorf("auth = %+v, want bob signed in", auth)
+				}
+			},
+		},
+		{
+			name: "ContributionSignup",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return c.ContributionSignup(ctx, &sdk.RegisterRequest{Email: "carol.jones@example.com"})
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				user := result.(*sdk.User)
+				if user.Email != "carol.jones@example.com" || user.FirstName != "Carol" || user.LastName != "Jones" {
+					t.Errorf("user = %+v, want carol with names taken from her email", user)
+				}
+			},
+		},
+		{
+			name: "SetPassword",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return c.SetPassword(ctx, &sdk.SetPasswordRequest{Email: "carol.jones@example.com", Password: "carol's horse"})
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				auth := result.(*sdk.AuthResponse)
+				if auth.User == nil || auth.User.Email != "carol.jones@example.com" || auth.AccessToken == "" {
+					t.Errorf("auth = %+v, want carol signed in", auth)
+				}
+			},
+		},
+		{
+			name: "SubmitNIN",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return c.SubmitNIN(ctx, "12345678901")
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				status := result.(*sdk.KYCStatus)
+				if status.KYCVerified || status.Submission == nil || status.Submission.DocumentType != string(models.KYCDocumentNIN) || status.Submission.Status != string(models.KYCStatusPending) {
+					t.Errorf("status = %+v, want a pending NIN submission", status)
+				}
+			},
+		},
+		{
+			name:  "ListPendingKYCSubmissions",
+			admin: true,
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				page, err := c.ListPendingKYCSubmissions(ctx, 1, 10)
+				if err == nil && page != nil && len(page.Data) > 0 {
+					f.submissionID = page.Data[0].ID
+				}
+				return page, err
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				page := result.(*sdk.KYCSubmissionPage)
+				if page.Total != 1 || len(page.Data) != 1 || page.Data[0].UserID != f.adaID.String() {
+					t.Errorf("page = %+v, want ada's submission", page)
+				}
+			},
+		},
+		{
+			name:  "ReviewKYCSubmission",
+			admin: true,
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				submissionID := f.submissionID
+				if submissionID == "" {
+					submissionID = uuid.NewString()
+				}
+				return c.ReviewKYCSubmission(ctx, submissionID, &sdk.ReviewKYCRequest{Decision: "reject", Reason: "The NIN does not match the name"})
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				submission := result.(*sdk.KYCSubmission)
+				if submission.ID != f.submissionID || submission.Status != string(models.KYCStatusRejected) || submission.ReviewerID == nil || *submission.ReviewerID != f.adminID.String() {
+					t.Errorf("submission = %+v, want it rejected by the admin", submission)
+				}
+			},
+		},
+		{
+			name: "SubmitKYC",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return c.SubmitKYC(ctx, &sdk.SubmitKYCRequest{
+					DocumentType:   string(models.KYCDocumentPassport),
+					DocumentNumber: "A12345678",
+					DocumentURLs:   []string{"https://docs.example.com/passport.jpg"},
+				})
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				status := result.(*sdk.KYCStatus)
+				if status.Submission == nil || status.Submission.DocumentType != string(models.KYCDocumentPassport) || status.Submission.Status != string(models.KYCStatusPending) {
+					t.Errorf("status = %+v, want a pending passport submission", status)
+				}
+			},
+		},
+		{
+			name: "GetKYCStatus",
+			call: func(ctx context.Context, c *sdk.UsersClient, f *usersFixture) (interface{}, error) {
+				return c.GetKYCStatus(ctx)
+			},
+			check: func(t *testing.T, f *usersFixture, result interface{}) {
+				status := result.(*sdk.KYCStatus)
+				if status.KYCVerified || status.Submission == nil || status.Submission.DocumentType != string(models.KYCDocumentPassport) {
+					t.Errorf("status = %+v, want the passport submission as the latest", status)
+				}
+			},
+		},
+	}
+}
+
+// routeProbe serves every route r has with an empty answer, so an SDK request succeeds
+// exactly when its method and path match one of r's routes
+func routeProbe(r *gin.Engine) http.Handler {
+	probe := gin.New()
+	for _, route := range r.Routes() {
+		probe.Handle(route.Method, route.Path, func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/json", []byte("null"))
+		})
+	}
+	return probe
+}
+
+// TestSDKRoutes checks every SDK method requests a route the service has; unlike
+// TestSDKContract it needs no database
+func TestSDKRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	SetupRoutes(r, nil, nil, nil, "", AuthRateLimits{})
+	server := httptest.NewServer(routeProbe(r))
+	t.Cleanup(server.Close)
+
+	client := sdk.NewUsersClient(server.URL)
+	f := &usersFixture{adaID: uuid.New(), adminID: uuid.New(), otherSessionID: uuid.New()}
+	for _, tc := range sdkContracts() {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.call(context.Background(), client, f); err != nil {
+				t.Errorf("no route for the request: %v", err)
+			}
+		})
+	}
+}
+
+// TestSDKContract calls every SDK method against the real routes over a seeded database,
+// as ada or the admin signed in through the gateway
+func TestSDKContract(t *testing.T) {
+	db := testdb.Open(t)
+	f := seedUsers(t, repository.NewUserRepository(db), repository.NewSessionRepository(db))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	eventService := service.NewEventService(f)
+	authService := service.NewAuthService(f.users, repository.NewSessionRepository(db), jwt.NewJWTService("secret", time.Minute, time.Hour), eventService)
+	userService := service.NewUserService(f.users, nil)
+	kycService := service.NewKYCService(f.users, repository.NewKYCRepository(db), eventService)
+	SetupRoutes(r, authService, userService, kycService, contractIdentitySecret, AuthRateLimits{})
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	ada := sdk.NewUsersClient(server.URL, sdk.WithIdentity(contractIdentitySecret, f.adaID.String(), "user", true))
+	admin := sdk.NewUsersClient(server.URL, sdk.WithIdentity(contractIdentitySecret, f.adminID.String(), "user,admin", true))
+	for _, tc := range sdkContracts() {
+		t.Run(tc.name, func(t *testing.T) {
+			client := ada
+			if tc.admin {
+				client = admin
+			}
+			result, err := tc.call(context.Background(), client, f)
+			if err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			if tc.check != nil {
+				tc.check(t, f, result)
+			}
+		})
+	}
+}
+
+// seedUsers creates ada, with a session besides the ones the contracts sign in with,
+// and an admin
+func seedUsers(t *testing.T, users *repository.UserRepository, sessions *repository.SessionRepository) *usersFixture {
+	t.Helper()
+	f := &usersFixture{users: users}
+
+	hash, err := password.HashPassword("correct horse", password.DefaultConfig())
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	ada := &models.User{Email: "ada@example.com", Username: "ada", FirstName: "Ada", PasswordHash: hash, HasSetPassword: true, Role: models.UserRoleUser}
+	if err := users.CreateUser(ada); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	admin := &models.User{Email: "admin@example.com", Username: "admin", FirstName: "Grace", PasswordHash: hash, HasSetPassword: true, Role: models.UserRoleAdmin}
+	if err := users.CreateUser(admin); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	f.adaID, f.adminID = ada.ID, admin.ID
+
+	other := &models.Session{UserID: ada.ID, TokenHash: uuid.NewString(), ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now().Add(-time.Hour)}
+	if err := sessions.CreateSession(other); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	f.otherSessionID = other.ID
+	return f
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"log"
 	"strings"
 	"time"
 
@@ -16,6 +17,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// emailVerificationTTL is how long an email verification link stays valid
+const emailVerificationTTL = 24 * time.Hour
+
 // AuthService handles authentication business logic
 type AuthService struct {
 	userRepo     *repository.UserRepository
@@ -35,12 +39,12 @@ func NewAuthService(userRepo *repository.UserRepository, sessionRepo *repository
 }
 
 // Login authenticates a user and returns tokens
-func (s *AuthService) Login(req *dto.LoginRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) Login(req *dto.LoginRequest, client dto.ClientInfo) (*dto.AuthResponse, error) {
 	// Get user by email
 	user, err := s.userRepo.GetUserByEmail(req.Email)
 	if err != nil {
 		metrics.TrackLoginFailure("invalid_email")
-		return nil, errors.New("invalid credentials")
+		return nil, ErrInvalidCredentials
 	}
 
 	// Verify password
@@ -51,7 +55,7 @@ func (s *AuthService) Login(req *dto.LoginRequest) (*dto.AuthResponse, error) {
 	}
 	if !valid {
 		metrics.TrackLoginFailure("invalid_password")
-		return nil, errors.New("invalid credentials")
+		return nil, ErrInvalidCredentials
 	}
 
 	// Generate token pair with user role
@@ -70,8 +74,8 @@ func (s *AuthService) Login(req *dto.LoginRequest) (*dto.AuthResponse, error) {
 		ExpiresAt: time.Now().Add(30 * 24 * time.Hour), // 30 days
 		Metadata: map[string]interface{}{
 			"login_time": time.Now(),
-			"ip":         "", // Will be set by controller
-			"user_agent": "", // Will be set by controller
+			"ip":         client.IP,
+			"user_agent": client.UserAgent,
 		},
 	}
 
@@ -109,7 +113,7 @@ func (s *AuthService) Login(req *dto.LoginRequest) (*dto.AuthResponse, error) {
 }
 
 // Register creates a new user account (supports full registration and email-only)
-func (s *AuthService) Register(req *dto.RegisterRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) Register(req *dto.RegisterRequest, client dto.ClientInfo) (*dto.AuthResponse, error) {
 	// Check if email exists
 	user, err := s.userRepo.GetUserByEmail(req.Email)
 	if err == nil {
@@ -130,7 +134,7 @@ func (s *AuthService) Register(req *dto.RegisterRequest) (*dto.AuthResponse, err
 			}, nil
 		}
 		
-		return nil, errors.New("email already exists")
+		return nil, ErrEmailExists
 	}
 
 	// Prepare user fields
@@ -206,6 +210,11 @@ func (s *AuthService) Register(req *dto.RegisterRequest) (*dto.AuthResponse, err
 		}
 	}
 
+	// Send the verification link; the user can ask for another if this fails
+	if err := s.RequestEmailVerification(user); err != nil {
+		log.Printf("Failed to request email verification for user %s: %v", user.ID, err)
+	}
+
 	// If no password set, don't issue tokens (they need to set password later)
 	if !hasSetPassword {
 		return &dto.AuthResponse{
@@ -228,6 +237,8 @@ func (s *AuthService) Register(req *dto.RegisterRequest) (*dto.AuthResponse, err
 		ExpiresAt: time.Now().Add(30 * 24 * time.Hour),
 		Metadata: map[string]interface{}{
 			"registration_time": time.Now(),
+			"ip":                client.IP,
+			"user_agent":        client.UserAgent,
 		},
 	}
 
@@ -245,53 +256,132 @@ func (s *AuthService) Register(req *dto.RegisterRequest) (*dto.AuthResponse, err
 }
 
 
-// RefreshToken generates new access token using refresh token
+// RefreshToken exchanges a refresh token for a new token pair. Each refresh token can be
+// used once: its session is rotated into a new one, and presenting a rotated token again
+// is treated as theft, revoking every session of the user.
 func (s *AuthService) RefreshToken(req *dto.RefreshRequest) (*jwt.TokenPair, error) {
 	// Validate refresh token
 	claims, err := s.jwtService.ValidateRefreshToken(req.RefreshToken)
 	if err != nil {
-		return nil, errors.New("invalid refresh token")
+		return nil, ErrInvalidRefreshToken
 	}
 
 	// Check if session exists
 	refreshTokenHash := s.hashToken(req.RefreshToken)
 	session, err := s.sessionRepo.GetSessionByTokenHash(refreshTokenHash)
 	if err != nil {
-		return nil, errors.New("invalid session")
+		return nil, ErrInvalidSession
+	}
+
+	if session.IsRotated() {
+		return nil, s.revokeForReuse(session.UserID)
 	}
 
 	// Check if session is expired
 	if session.IsExpired() {
 		s.sessionRepo.DeleteSession(refreshTokenHash)
-		return nil, errors.New("session expired")
+		return nil, ErrSessionExpired
 	}
 
 	// Get user
 	userID, err := uuid.Parse(claims.UserID)
-	if err != nil {
-		return nil, errors.New("invalid user ID")
+	if err != nil || userID != session.UserID {
+		return nil, ErrInvalidRefreshToken
 	}
 
 	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, ErrInvalidRefreshToken
 	}
 
-	// Get user role
+	// Generate token pair with user role
 	roles := []string{string(user.Role)}
+	tokenPair, err := s.jwtService.GenerateTokenPair(user.ID.String(), user.Email, roles)
+	if err != nil {
+		return nil, errors.New("failed to generate tokens")
+	}
 
-	// Generate new access token (keep same refresh token)
-	accessToken, err := s.jwtService.GenerateAccessToken(user.ID.String(), user.Email, roles)
+	// The new session keeps the original expiry, so rotation cannot extend a login forever
+	next := &models.Session{
+		UserID:    user.ID,
+		TokenHash: s.hashToken(tokenPair.RefreshToken),
+		ExpiresAt: session.ExpiresAt,
+		Metadata:  session.Metadata,
+	}
+
+	rotated, err := s.sessionRepo.RotateSession(refreshTokenHash, next)
 	if err != nil {
-		return nil, errors.New("failed to generate access token")
+		return nil, errors.New("failed to rotate session")
+	}
+	if !rotated {
+		// A concurrent refresh rotated the session first: the token was used twice
+		return nil, s.revokeForReuse(user.ID)
 	}
 
-	return &jwt.TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: req.RefreshToken, // Keep the same refresh token
-		TokenType:    "Bearer",
-		ExpiresIn:    3600, // 1 hour
-	}, nil
+	metrics.TrackJWTIssued("access_token")
+	metrics.TrackJWTIssued("refresh_token")
+
+	return tokenPair, nil
+}
+
+// revokeForReuse deletes every session of a user whose rotated refresh token was
+// presented again and returns the error to report
+func (s *AuthService) revokeForReuse(userID uuid.UUID) error {
+	log.Printf("Refresh token reuse detected for user %s; revoking all sessions", userID)
+	metrics.IncrementCounter("auth.refresh_token.reuse")
+
+	if err := s.sessionRepo.DeleteUserSessions(userID); err != nil {
+		log.Printf("Failed to revoke sessions for user %s: %v", userID, err)
+	}
+	return ErrRefreshTokenReused
+}
+
+// ListSessions returns a user's active sessions, newest first
+func (s *AuthService) ListSessions(userID string) ([]dto.SessionResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	sessions, err := s.sessionRepo.GetSessionsByUserID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		ip, _ := session.Metadata["ip"].(string)
+		userAgent, _ := session.Metadata["user_agent"].(string)
+		responses = append(responses, dto.SessionResponse{
+			ID:        session.ID.String(),
+			IP:        ip,
+			UserAgent: userAgent,
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+		})
+	}
+	return responses, nil
+}
+
+// RevokeSession deletes one of a user's sessions, so its refresh token stops working at
+// once. Access tokens already issued for it remain valid until they expire.
+func (s *AuthService) RevokeSession(userID, sessionID string) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+	sid, err := uuid.Parse(sessionID)
+	if err != nil {
+		return ErrInvalidSessionID
+	}
+
+	if err := s.sessionRepo.DeleteSessionByID(uid, sid); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return nil
 }
 
 // ValidateAccessToken validates an access token and returns user info
@@ -311,7 +401,13 @@ func (s *AuthService) ValidateAccessToken(tokenString string) (*dto.Claims, erro
 // Logout invalidates a refresh token session
 func (s *AuthService) Logout(refreshToken string) error {
 	refreshTokenHash := s.hashToken(refreshToken)
-	return s.sessionRepo.DeleteSession(refreshTokenHash)
+	if err := s.sessionRepo.DeleteSession(refreshTokenHash); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return ErrInvalidSession
+		}
+		return err
+	}
+	return nil
 }
 
 // LogoutAllSessions invalidates all sessions for a user
@@ -361,18 +457,18 @@ func (s *AuthService) ResetPassword(req *dto.ResetPasswordRequest) error {
 	// Get password reset token
 	resetToken, err := s.userRepo.GetPasswordResetToken(tokenHash)
 	if err != nil {
-		return errors.New("invalid or expired token")
+		return ErrInvalidResetToken
 	}
 
 	// Check if token is expired
 	if resetToken.IsExpired() {
-		return errors.New("token has expired")
+		return ErrResetTokenExpired
 	}
 
 	// Get user
 	user, err := s.userRepo.GetUserByID(resetToken.UserID)
 	if err != nil {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 
 	// Hash new password
@@ -398,6 +494,130 @@ func (s *AuthService) ResetPassword(req *dto.ResetPasswordRequest) error {
 	return nil
 }
 
+// RequestEmailVerification issues a new email verification token for a user, replacing
+// any earlier one, and publishes it for notifications-service to email
+func (s *AuthService) RequestEmailVerification(user *models.User) error {
+	if user.EmailVerified {
+		return nil
+	}
+
+	verificationToken := uuid.New().String()
+	token := &models.EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: s.hashToken(verificationToken),
+		ExpiresAt: time.Now().Add(emailVerificationTTL),
+	}
+
+	if err := s.userRepo.ReplaceEmailVerificationToken(token); err != nil {
+		return errors.New("failed to create verification token")
+	}
+
+	if s.eventService != nil {
+		if err := s.eventService.PublishEmailVerificationRequested(user, verificationToken); err != nil {
+			return err
+		}
+	}
+
+	metrics.IncrementCounter("auth.email_verification.requested")
+	return nil
+}
+
+// ResendEmailVerification sends a fresh verification link to an unverified email
+func (s *AuthService) ResendEmailVerification(req *dto.ResendVerificationRequest) error {
+	user, err := s.userRepo.GetUserByEmail(req.Email)
+	if err != nil {
+		// Return success even if user doesn't exist for security
+		return nil
+	}
+
+	return s.RequestEmailVerification(user)
+}
+
+// VerifyEmail marks the email of the token's user verified and invalidates the token
+func (s *AuthService) VerifyEmail(req *dto.VerifyEmailRequest) error {
+	token, err := s.userRepo.GetEmailVerificationToken(s.hashToken(req.Token))
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	if token.IsExpired() {
+		return ErrVerificationTokenExpired
+	}
+
+	consumed, err := s.userRepo.ConsumeEmailVerificationToken(token)
+	if err != nil {
+		return errors.New("failed to verify email")
+	}
+	if !consumed {
+		// Used by a concurrent request between the lookup and now
+		return ErrInvalidVerificationToken
+	}
+
+	metrics.IncrementCounter("auth.email_verification.completed")
+	return nil
+}
+
+// IsEmailVerified reports whether a user has verified their email
+func (s *AuthService) IsEmailVerified(userID string) (bool, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return false, ErrInvalidUserID
+	}
+	return s.userRepo.IsEmailVerified(id)
+}
+
+// ChangePassword replaces a logged-in user's password after checking the current one.
+// Every session except the one holding refreshToken is signed out; with no refreshToken,
+// all of them are.
+func (s *AuthService) ChangePassword(userID string, req *dto.ChangePasswordRequest) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+
+	user, err := s.userRepo.GetUserByID(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	valid, err := password.VerifyPassword(req.CurrentPassword, user.PasswordHash)
+	if err != nil || !valid {
+		return ErrIncorrectPassword
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return ErrPasswordUnchanged
+	}
+
+	hashedPassword, err := password.HashPassword(req.NewPassword, nil)
+	if err != nil {
+		return errors.New("failed to process password")
+	}
+
+	user.PasswordHash = hashedPassword
+	if err := s.userRepo.UpdateUser(user); err != nil {
+		return errors.New("failed to update password")
+	}
+
+	// Keep the caller's session only if it is genuinely theirs
+	keepTokenHash := ""
+	if req.RefreshToken != "" {
+		if session, err := s.sessionRepo.GetSessionByTokenHash(s.hashToken(req.RefreshToken)); err == nil && session.UserID == user.ID {
+			keepTokenHash = session.TokenHash
+		}
+	}
+	if err := s.sessionRepo.DeleteOtherUserSessions(user.ID, keepTokenHash); err != nil {
+		log.Printf("Failed to sign out other sessions for user %s: %v", user.ID, err)
+	}
+
+	if s.eventService != nil {
+		if err := s.eventService.PublishPasswordChanged(user); err != nil {
+			log.Printf("Failed to publish PasswordChanged for user %s: %v", user.ID, err)
+		}
+	}
+
+	return nil
+}
+
 // hashToken creates a SHA256 hash of a token for storage
 func (s *AuthService) hashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
@@ -406,14 +626,14 @@ func (s *AuthService) hashToken(token string) string {
 
 
 // SetPassword handles first-time password setup
-func (s *AuthService) SetPassword(req *dto.SetPasswordRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) SetPassword(req *dto.SetPasswordRequest, client dto.ClientInfo) (*dto.AuthResponse, error) {
 	user, err := s.userRepo.GetUserByEmail(req.Email)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	if user.HasSetPassword {
-		return nil, errors.New("password already set")
+		return nil, ErrPasswordAlreadySet
 	}
 
 	hashedPassword, err := password.HashPassword(req.Password, nil)
@@ -443,6 +663,8 @@ func (s *AuthService) SetPassword(req *dto.SetPasswordRequest) (*dto.AuthRespons
 		ExpiresAt: time.Now().Add(30 * 24 * time.Hour),
 		Metadata: map[string]interface{}{
 			"set_password_time": time.Now(),
+			"ip":                client.IP,
+			"user_agent":        client.UserAgent,
 		},
 	}
 
@@ -463,7 +685,7 @@ func (s *AuthService) SetPassword(req *dto.SetPasswordRequest) (*dto.AuthRespons
 func (s *AuthService) UpdateProfile(userID uuid.UUID, req *dto.UpdateProfileRequest) (*dto.UserResponse, error) {
 	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	if req.FirstName != "" {
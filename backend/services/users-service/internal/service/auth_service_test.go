@@ -0,0 +1,149 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofund/shared/jwt"
+	"github.com/gofund/shared/models"
+	"github.com/gofund/shared/password"
+	"github.com/gofund/users-service/internal/dto"
+	"github.com/gofund/users-service/internal/repository"
+	"github.com/gofund/users-service/internal/testdb"
+)
+
+const testPassword = "correct horse"
+
+// newTestAuthService returns an auth service over a fresh test schema with one user
+func newTestAuthService(t *testing.T) (*AuthService, *models.User) {
+	t.Helper()
+	db := testdb.Open(t)
+	userRepo := repository.NewUserRepository(db)
+
+	hash, err := password.HashPassword(testPassword, password.DefaultConfig())
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	user := &models.User{Email: "ada@example.com", Username: "ada", PasswordHash: hash, Role: models.UserRoleUser}
+	if err := userRepo.CreateUser(user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	s := NewAuthService(userRepo, repository.NewSessionRepository(db), jwt.NewJWTService("secret", time.Minute, time.Hour), nil)
+	return s, user
+}
+
+// login signs the test user in and returns the refresh token
+func login(t *testing.T, s *AuthService, user *models.User) string {
+	t.Helper()
+	resp, err := s.Login(&dto.LoginRequest{Email: user.Email, Password: testPassword}, dto.ClientInfo{IP: "203.0.113.7"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return resp.RefreshToken
+}
+
+func refresh(s *AuthService, token string) (string, error) {
+	pair, err := s.RefreshToken(&dto.RefreshRequest{RefreshToken: token})
+	if err != nil {
+		return "", err
+	}
+	return pair.RefreshToken, nil
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	s, user := newTestAuthService(t)
+	first := login(t, s, user)
+
+	second, err := refresh(s, first)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if second == first {
+		t.Fatal("refresh returned the same refresh token")
+	}
+
+	old, err := s.sessionRepo.GetSessionByTokenHash(s.hashToken(first))
+	if err != nil {
+		t.Fatalf("the rotated session was deleted: %v", err)
+	}
+	if !old.IsRotated() {
+		t.Error("the old session was not marked rotated")
+	}
+	next, err := s.sessionRepo.GetSessionByTokenHash(s.hashToken(second))
+	if err != nil {
+		t.Fatalf("no session for the new refresh token: %v", err)
+	}
+	if !next.ExpiresAt.Equal(old.ExpiresAt) {
+		t.Errorf("new session expires %s, want the original %s", next.ExpiresAt, old.ExpiresAt)
+	}
+
+	if _, err := refresh(s, second); err != nil {
+		t.Errorf("refresh with the new token: %v", err)
+	}
+}
+
+// TestRefreshTokenReuseRevokesAllSessions presents a refresh token again after it was
+// rotated, as an attacker holding a stolen copy would
+func TestRefreshTokenReuseRevokesAllSessions(t *testing.T) {
+	s, user := newTestAuthService(t)
+	stolen := login(t, s, user)
+	otherDevice := login(t, s, user)
+
+	rotated, err := refresh(s, stolen)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	if _, err := refresh(s, stolen); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reused token: err = %v, want %v", err, ErrRefreshTokenReused)
+	}
+
+	sessions, err := s.sessionRepo.GetSessionsByUserID(user.ID)
+	if err != nil {
+		t.Fatalf("GetSessionsByUserID: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("%d sessions left after reuse, want every session revoked", len(sessions))
+	}
+	for name, token := range map[string]string{"rotated-in token": rotated, "other device": otherDevice, "reused token": stolen} {
+		if _, err := refresh(s, token); !errors.Is(err, ErrInvalidSession) {
+			t.Errorf("%s after reuse: err = %v, want %v", name, err, ErrInvalidSession)
+		}
+	}
+}
+
+// TestConcurrentRefreshIsReuse refreshes the same token twice at once: only one refresh
+// can rotate the session, and the other is treated as reuse
+func TestConcurrentRefreshIsReuse(t *testing.T) {
+	s, user := newTestAuthService(t)
+	token := login(t, s, user)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = refresh(s, token)
+		}(i)
+	}
+	wg.Wait()
+
+	var succeeded, reused int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, ErrRefreshTokenReused):
+			reused++
+		default:
+			t.Errorf("refresh: %v", err)
+		}
+	}
+	if succeeded != 1 || reused != 1 {
+		t.Errorf("%d refreshes succeeded and %d were reuse, want one of each", succeeded, reused)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Profile field limits, matching the users table columns
+const (
+	maxAvatarURLLength = 500
+	maxBioLength       = 280
+)
+
+// normalizeAvatarURL checks an avatar image URL. Only externally hosted images are
+// accepted for now; once uploads exist they will hand back a URL to our own storage that
+// passes the same check. An empty URL removes the avatar.
+func normalizeAvatarURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	if len(raw) > maxAvatarURLLength {
+		return "", ErrInvalidAvatarURL
+	}
+
+	parsed, ok := parseHTTPSURL(raw)
+	if !ok {
+		return "", ErrInvalidAvatarURL
+	}
+	return parsed.String(), nil
+}
+
+// parseHTTPSURL parses an absolute https URL with a host and no credentials
+func parseHTTPSURL(raw string) (*url.URL, bool) {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" || parsed.User != nil {
+		return nil, false
+	}
+	return parsed, true
+}
+
+// normalizeBio trims a bio and checks its length in characters
+func normalizeBio(raw string) (string, error) {
+	bio := strings.TrimSpace(raw)
+	if utf8.RuneCountInString(bio) > maxBioLength {
+		return "", ErrBioTooLong
+	}
+	return bio, nil
+}
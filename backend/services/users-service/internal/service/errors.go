@@ -0,0 +1,43 @@
+package service
+
+import (
+	"fmt"
+
+	apperrors "github.com/gofund/shared/errors"
+)
+
+var (
+	ErrInvalidUserID            = apperrors.Validation("invalid_user_id", "invalid user ID")
+	ErrUserNotFound             = apperrors.NotFound("user_not_found", "user not found")
+	ErrInvalidCredentials       = apperrors.Unauthenticated("invalid_credentials", "invalid credentials")
+	ErrInvalidRefreshToken      = apperrors.Unauthenticated("invalid_refresh_token", "invalid refresh token")
+	ErrInvalidSession           = apperrors.Unauthenticated("invalid_session", "invalid session")
+	ErrRefreshTokenReused       = apperrors.Unauthenticated("refresh_token_reused", "refresh token has already been used")
+	ErrInvalidSessionID         = apperrors.Validation("invalid_session_id", "invalid session ID")
+	ErrSessionNotFound          = apperrors.NotFound("session_not_found", "session not found")
+	ErrSessionExpired           = apperrors.Unauthenticated("session_expired", "session expired")
+	ErrEmailExists              = apperrors.Conflict("email_exists", "email already exists")
+	ErrIncorrectPassword        = apperrors.Validation("incorrect_password", "current password is incorrect")
+	ErrPasswordUnchanged        = apperrors.Validation("password_unchanged", "new password must differ from the current password")
+	ErrPasswordAlreadySet       = apperrors.Conflict("password_already_set", "password already set")
+	ErrInvalidResetToken        = apperrors.Validation("invalid_reset_token", "invalid or expired token")
+	ErrResetTokenExpired        = apperrors.Validation("reset_token_expired", "token has expired")
+	ErrInvalidVerificationToken = apperrors.Validation("invalid_verification_token", "invalid or expired verification token")
+	ErrVerificationTokenExpired = apperrors.Validation("verification_token_expired", "verification token has expired")
+	ErrAlreadyKYCVerified       = apperrors.Conflict("already_kyc_verified", "user is already KYC verified")
+	ErrInvalidNIN               = apperrors.Validation("invalid_nin", "invalid NIN format - must be 11 digits")
+	ErrNINRegistered            = apperrors.Conflict("nin_registered", "NIN already registered to another account")
+	ErrTooManyUserIDs           = apperrors.Validation("too_many_user_ids", fmt.Sprintf("at most %d user IDs can be requested at once", maxDisplayNameBatch))
+	ErrInvalidAvatarURL         = apperrors.Validation("invalid_avatar_url", fmt.Sprintf("avatar URL must be an absolute https URL of at most %d characters", maxAvatarURLLength))
+	ErrBioTooLong               = apperrors.Validation("bio_too_long", fmt.Sprintf("bio must be at most %d characters", maxBioLength))
+	ErrInvalidDocumentType      = apperrors.Validation("invalid_document_type", "document type must be one of nin, passport, drivers_license, voters_card")
+	ErrInvalidDocumentNumber    = apperrors.Validation("invalid_document_number", "document number must be 5 to 50 letters or digits")
+	ErrDocumentURLsRequired     = apperrors.Validation("document_urls_required", "at least one document image is required for this document type")
+	ErrInvalidDocumentURL       = apperrors.Validation("invalid_document_url", fmt.Sprintf("document URLs must be absolute https URLs of at most %d characters", maxDocumentURLLength))
+	ErrKYCSubmissionPending     = apperrors.Conflict("kyc_submission_pending", "a KYC submission is already awaiting review")
+	ErrInvalidSubmissionID      = apperrors.Validation("invalid_submission_id", "invalid KYC submission ID")
+	ErrKYCSubmissionNotFound    = apperrors.NotFound("kyc_submission_not_found", "KYC submission not found")
+	ErrKYCSubmissionNotPending  = apperrors.Conflict("kyc_submission_not_pending", "KYC submission has already been reviewed")
+	ErrRejectionReasonRequired  = apperrors.Validation("rejection_reason_required", "a reason is required when rejecting a KYC submission")
+	ErrSelfReview               = apperrors.Forbidden("self_review", "admins cannot review their own KYC submission")
+)
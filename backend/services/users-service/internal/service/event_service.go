@@ -47,6 +47,18 @@ func (s *EventService) PublishPasswordResetRequested(user *models.User, token st
 	return s.publisher.Publish("PasswordResetRequested", event)
 }
 
+// PublishPasswordChanged publishes a PasswordChanged event
+func (s *EventService) PublishPasswordChanged(user *models.User) error {
+	event := events.PasswordChanged{
+		ID:        uuid.New().String(),
+		UserID:    user.ID.String(),
+		Email:     user.Email,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	return s.publisher.Publish("PasswordChanged", event)
+}
+
 // PublishEmailVerificationRequested publishes an EmailVerificationRequested event
 func (s *EventService) PublishEmailVerificationRequested(user *models.User, token string) error {
 	event := events.EmailVerificationRequested{
@@ -72,3 +84,17 @@ func (s *EventService) PublishKYCVerified(user *models.User) error {
 
 	return s.publisher.Publish("KYCVerified", event)
 }
+
+// PublishKYCRejected publishes a KYCRejected event
+func (s *EventService) PublishKYCRejected(user *models.User, submission *models.KYCSubmission) error {
+	event := events.KYCRejected{
+		ID:           uuid.New().String(),
+		UserID:       user.ID.String(),
+		SubmissionID: submission.ID.String(),
+		Email:        user.Email,
+		Reason:       submission.RejectionReason,
+		CreatedAt:    time.Now().Unix(),
+	}
+
+	return s.publisher.Publish("KYCRejected", event)
+}
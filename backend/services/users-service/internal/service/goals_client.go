@@ -0,0 +1,59 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofund/shared/metrics"
+	"github.com/google/uuid"
+)
+
+// GoalsClient looks up a user's goals from goals-service
+type GoalsClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewGoalsClient creates a goals-service client. An empty baseURL disables lookups.
+func NewGoalsClient(baseURL string) *GoalsClient {
+	return &GoalsClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+// PublicGoalCount calls GET /internal/users/:id/goals on goals-service and returns how
+// many public goals the user owns
+func (gc *GoalsClient) PublicGoalCount(userID uuid.UUID) (int64, error) {
+	if gc.baseURL == "" {
+		return 0, fmt.Errorf("goals-service URL not configured")
+	}
+
+	endpoint := fmt.Sprintf("%s/internal/users/%s/goals", gc.baseURL, url.PathEscape(userID.String()))
+
+	start := time.Now()
+	resp, err := gc.client.Get(endpoint)
+	metrics.RecordDuration("users.goals_client.duration", start)
+	if err != nil {
+		metrics.IncrementCounter("users.goals_client.error")
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		metrics.IncrementCounter("users.goals_client.error")
+		return 0, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var summary struct {
+		PublicGoalCount int64 `json:"public_goal_count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return summary.PublicGoalCount, nil
+}
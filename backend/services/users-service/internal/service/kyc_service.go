@@ -2,106 +2,291 @@ package service
 
 import (
 	"errors"
+	"log"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/models"
 	"github.com/gofund/users-service/internal/dto"
 	"github.com/gofund/users-service/internal/repository"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// maxDocumentURLLength matches the limit on other externally hosted images
+const maxDocumentURLLength = maxAvatarURLLength
+
+const maxKYCPageSize = 100
+
+var documentNumberPattern = regexp.MustCompile(`^[A-Za-z0-9-]{5,50}$`)
+
 // KYCService handles KYC verification business logic
 type KYCService struct {
 	userRepo     *repository.UserRepository
+	kycRepo      *repository.KYCRepository
 	eventService *EventService
 }
 
 // NewKYCService creates a new KYC service instance
-func NewKYCService(userRepo *repository.UserRepository, eventService *EventService) *KYCService {
+func NewKYCService(userRepo *repository.UserRepository, kycRepo *repository.KYCRepository, eventService *EventService) *KYCService {
 	return &KYCService{
 		userRepo:     userRepo,
+		kycRepo:      kycRepo,
 		eventService: eventService,
 	}
 }
 
-// SubmitNIN submits a NIN for KYC verification
-// In this dummy implementation, we automatically verify the user
+// SubmitNIN submits a NIN for admin review. It is kept for clients of the original
+// NIN-only flow and behaves like Submit with a NIN document.
 func (s *KYCService) SubmitNIN(userID string, req *dto.SubmitNINRequest) (*dto.KYCStatusResponse, error) {
+	return s.Submit(userID, &dto.SubmitKYCRequest{
+		DocumentType:   models.KYCDocumentNIN,
+		DocumentNumber: req.NIN,
+	})
+}
+
+// Submit sends an identity document for admin review. A user may have only one
+// submission awaiting review and cannot submit once verified.
+func (s *KYCService) Submit(userID string, req *dto.SubmitKYCRequest) (*dto.KYCStatusResponse, error) {
 	// Parse user ID
 	id, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, errors.New("invalid user ID")
+		return nil, ErrInvalidUserID
 	}
 
 	// Get user
 	user, err := s.userRepo.GetUserByID(id)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	// Check if already verified
 	if user.KYCVerified {
-		return nil, errors.New("user is already KYC verified")
+		return nil, ErrAlreadyKYCVerified
 	}
 
-	// Validate NIN format (11 digits)
-	if !s.isValidNIN(req.NIN) {
-		return nil, errors.New("invalid NIN format - must be 11 digits")
+	documentNumber := strings.TrimSpace(req.DocumentNumber)
+	switch req.DocumentType {
+	case models.KYCDocumentNIN:
+		if !s.isValidNIN(documentNumber) {
+			return nil, ErrInvalidNIN
+		}
+		// Check if NIN is already used by another user
+		existingUser, err := s.userRepo.GetUserByNIN(documentNumber)
+		if err == nil && existingUser != nil && existingUser.ID != user.ID {
+			return nil, ErrNINRegistered
+		}
+	case models.KYCDocumentPassport, models.KYCDocumentDriversLicense, models.KYCDocumentVotersCard:
+		if !documentNumberPattern.MatchString(documentNumber) {
+			return nil, ErrInvalidDocumentNumber
+		}
+		if len(req.DocumentURLs) == 0 {
+			return nil, ErrDocumentURLsRequired
+		}
+	default:
+		return nil, ErrInvalidDocumentType
 	}
 
-	// Check if NIN is already used by another user
-	existingUser, err := s.userRepo.GetUserByNIN(req.NIN)
-	if err == nil && existingUser != nil && existingUser.ID != user.ID {
-		return nil, errors.New("NIN already registered to another account")
+	documentURLs, err := normalizeDocumentURLs(req.DocumentURLs)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update user with NIN and mark as verified (dummy verification)
-	now := time.Now()
-	user.NIN = req.NIN
-	user.KYCVerified = true
-	user.KYCVerifiedAt = &now
-
-	if err := s.userRepo.UpdateUser(user); err != nil {
-		return nil, errors.New("failed to update KYC status")
+	pending, err := s.kycRepo.HasPendingSubmission(user.ID)
+	if err != nil {
+		return nil, errors.New("failed to check KYC submissions")
+	}
+	if pending {
+		return nil, ErrKYCSubmissionPending
 	}
 
-	// Publish KYC verified event
-	if s.eventService != nil {
-		if err := s.eventService.PublishKYCVerified(user); err != nil {
-			// Log error but don't fail the verification
+	submission := &models.KYCSubmission{
+		UserID:         user.ID,
+		DocumentType:   req.DocumentType,
+		DocumentNumber: documentNumber,
+		DocumentURLs:   documentURLs,
+		Status:         models.KYCStatusPending,
+	}
+	if err := s.kycRepo.CreateSubmission(submission); err != nil {
+		if errors.Is(err, repository.ErrPendingKYCSubmission) {
+			return nil, ErrKYCSubmissionPending
 		}
+		return nil, errors.New("failed to submit KYC documents")
 	}
 
-	// Track KYC verification metric
-	metrics.TrackKYCVerification(user.ID.String())
+	metrics.IncrementCounter("users.kyc.submitted", "document_type:"+string(submission.DocumentType))
 
-	return &dto.KYCStatusResponse{
-		KYCVerified:   user.KYCVerified,
-		KYCVerifiedAt: user.KYCVerifiedAt,
-		NIN:           s.maskNIN(user.NIN),
-	}, nil
+	return s.statusResponse(user, submission), nil
 }
 
-// GetKYCStatus retrieves the KYC status for a user
+// GetKYCStatus retrieves the KYC status for a user, with their latest submission
 func (s *KYCService) GetKYCStatus(userID string) (*dto.KYCStatusResponse, error) {
 	// Parse user ID
 	id, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, errors.New("invalid user ID")
+		return nil, ErrInvalidUserID
 	}
 
 	// Get user
 	user, err := s.userRepo.GetUserByID(id)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
+	}
+
+	submission, err := s.kycRepo.GetLatestSubmissionByUserID(user.ID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("failed to load KYC submission")
+		}
+		submission = nil
+	}
+
+	return s.statusResponse(user, submission), nil
+}
+
+// ListPendingSubmissions returns a page of KYC submissions awaiting review, oldest first.
+// Admin only; the documents are returned unmasked.
+func (s *KYCService) ListPendingSubmissions(page, pageSize int) (*dto.KYCSubmissionListResponse, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > maxKYCPageSize {
+		pageSize = maxKYCPageSize
+	}
+
+	submissions, total, err := s.kycRepo.GetPendingSubmissions(pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, errors.New("failed to list KYC submissions")
+	}
+
+	return &dto.KYCSubmissionListResponse{
+		Data:  submissions,
+		Total: total,
+		Page:  page,
+		Size:  pageSize,
+	}, nil
+}
+
+// ReviewSubmission approves or rejects a pending KYC submission. Approval marks the user
+// KYC verified and publishes KYCVerified; rejection publishes KYCRejected so the user is
+// told the reason.
+func (s *KYCService) ReviewSubmission(reviewerID, submissionID string, req *dto.ReviewKYCRequest) (*models.KYCSubmission, error) {
+	reviewer, err := uuid.Parse(reviewerID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+	id, err := uuid.Parse(submissionID)
+	if err != nil {
+		return nil, ErrInvalidSubmissionID
+	}
+
+	submission, err := s.kycRepo.GetSubmissionByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrKYCSubmissionNotFound
+		}
+		return nil, errors.New("failed to load KYC submission")
+	}
+	if submission.UserID == reviewer {
+		return nil, ErrSelfReview
+	}
+	if submission.Status != models.KYCStatusPending {
+		return nil, ErrKYCSubmissionNotPending
+	}
+
+	user, err := s.userRepo.GetUserByID(submission.UserID)
+	if err != nil {
+		return nil, ErrUserNotFound
 	}
 
-	return &dto.KYCStatusResponse{
+	now := time.Now()
+	submission.ReviewerID = &reviewer
+	submission.ReviewedAt = &now
+	if req.Decision == "approve" {
+		// Another account may have been verified with the same NIN since this was submitted
+		if submission.DocumentType == models.KYCDocumentNIN {
+			existingUser, err := s.userRepo.GetUserByNIN(submission.DocumentNumber)
+			if err == nil && existingUser != nil && existingUser.ID != user.ID {
+				return nil, ErrNINRegistered
+			}
+		}
+		submission.Status = models.KYCStatusApproved
+	} else {
+		submission.RejectionReason = strings.TrimSpace(req.Reason)
+		if submission.RejectionReason == "" {
+			return nil, ErrRejectionReasonRequired
+		}
+		submission.Status = models.KYCStatusRejected
+	}
+
+	reviewed, err := s.kycRepo.ReviewSubmission(submission)
+	if err != nil {
+		return nil, errors.New("failed to review KYC submission")
+	}
+	if !reviewed {
+		return nil, ErrKYCSubmissionNotPending
+	}
+
+	metrics.IncrementCounter("users.kyc.reviewed", "status:"+string(submission.Status))
+
+	if submission.Status == models.KYCStatusApproved {
+		// Track KYC verification metric
+		metrics.TrackKYCVerification(user.ID.String())
+		if s.eventService != nil {
+			if err := s.eventService.PublishKYCVerified(user); err != nil {
+				log.Printf("Failed to publish KYCVerified event for user %s: %v", user.ID, err)
+			}
+		}
+	} else if s.eventService != nil {
+		if err := s.eventService.PublishKYCRejected(user, submission); err != nil {
+			log.Printf("Failed to publish KYCRejected event for user %s: %v", user.ID, err)
+		}
+	}
+
+	return submission, nil
+}
+
+// statusResponse builds a user's KYC status, with their latest submission if any
+func (s *KYCService) statusResponse(user *models.User, submission *models.KYCSubmission) *dto.KYCStatusResponse {
+	response := &dto.KYCStatusResponse{
 		KYCVerified:   user.KYCVerified,
 		KYCVerifiedAt: user.KYCVerifiedAt,
 		NIN:           s.maskNIN(user.NIN),
-	}, nil
+	}
+	if submission != nil {
+		response.Submission = &dto.KYCSubmissionSummary{
+			ID:              submission.ID.String(),
+			DocumentType:    submission.DocumentType,
+			DocumentNumber:  maskDocumentNumber(submission.DocumentNumber),
+			Status:          submission.Status,
+			RejectionReason: submission.RejectionReason,
+			SubmittedAt:     submission.CreatedAt,
+			ReviewedAt:      submission.ReviewedAt,
+		}
+	}
+	return response
+}
+
+// normalizeDocumentURLs trims and checks the image URLs of a KYC document
+func normalizeDocumentURLs(raw []string) ([]string, error) {
+	urls := make([]string, 0, len(raw))
+	for _, u := range raw {
+		u = strings.TrimSpace(u)
+		if len(u) > maxDocumentURLLength {
+			return nil, ErrInvalidDocumentURL
+		}
+		parsed, ok := parseHTTPSURL(u)
+		if !ok {
+			return nil, ErrInvalidDocumentURL
+		}
+		urls = append(urls, parsed.String())
+	}
+	return urls, nil
 }
 
 // isValidNIN validates NIN format (11 digits)
@@ -121,3 +306,12 @@ func (s *KYCService) maskNIN(nin string) string {
 	}
 	return "*******" + nin[7:]
 }
+
+// maskDocumentNumber masks a document number for privacy, showing only its last 4
+// characters
+func maskDocumentNumber(number string) string {
+	if len(number) <= 4 {
+		return "***"
+	}
+	return strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+}
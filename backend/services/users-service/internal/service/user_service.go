@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"log"
 
 	"github.com/gofund/shared/models"
 	"github.com/gofund/users-service/internal/dto"
@@ -11,13 +12,15 @@ import (
 
 // UserService handles user-related business logic
 type UserService struct {
-	userRepo *repository.UserRepository
+	userRepo    *repository.UserRepository
+	goalsClient *GoalsClient
 }
 
 // NewUserService creates a new user service instance
-func NewUserService(userRepo *repository.UserRepository) *UserService {
+func NewUserService(userRepo *repository.UserRepository, goalsClient *GoalsClient) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		goalsClient: goalsClient,
 	}
 }
 
@@ -25,27 +28,149 @@ func NewUserService(userRepo *repository.UserRepository) *UserService {
 func (s *UserService) GetProfile(userID string) (*dto.UserResponse, error) {
 	id, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, errors.New("invalid user ID")
+		return nil, ErrInvalidUserID
 	}
 
 	user, err := s.userRepo.GetUserByID(id)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	return mapUserToResponse(user), nil
 }
 
+// GetPublicProfile returns the public view of the user with the given username. The goal
+// count is best effort: when goals-service cannot be reached it is reported as zero.
+func (s *UserService) GetPublicProfile(username string) (*dto.PublicProfile, error) {
+	user, err := s.userRepo.GetUserByUsername(username)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	profile := &dto.PublicProfile{
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		AvatarURL: user.AvatarURL,
+		Bio:       user.Bio,
+		JoinedAt:  user.CreatedAt,
+	}
+	if s.goalsClient != nil {
+		if profile.PublicGoalCount, err = s.goalsClient.PublicGoalCount(user.ID); err != nil {
+			log.Printf("Failed to count public goals for user %s: %v", user.ID, err)
+		}
+	}
+	return profile, nil
+}
+
+// GetUserSummary returns the ID, email and names of a single user
+func (s *UserService) GetUserSummary(userID string) (*dto.UserSummary, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	user, err := s.userRepo.GetUserByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	return &dto.UserSummary{
+		ID:          user.ID.String(),
+		Email:       user.Email,
+		Username:    user.Username,
+		FirstName:   user.FirstName,
+		KYCVerified: user.KYCVerified,
+	}, nil
+}
+
+// maxDisplayNameBatch caps how many users can be looked up in one call
+const maxDisplayNameBatch = 100
+
+// GetDisplayNames returns the display names of the given users. Invalid and unknown
+// IDs are skipped.
+func (s *UserService) GetDisplayNames(userIDs []string) ([]dto.UserDisplayName, error) {
+	users, err := s.getUsersBatch(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]dto.UserDisplayName, 0, len(users))
+	for _, user := range users {
+		names = append(names, dto.UserDisplayName{
+			ID:        user.ID.String(),
+			Username:  user.Username,
+			FirstName: user.FirstName,
+		})
+	}
+	return names, nil
+}
+
+// GetContacts returns the real names and emails of the given users. Invalid and unknown
+// IDs are skipped.
+func (s *UserService) GetContacts(userIDs []string) ([]dto.UserContact, error) {
+	users, err := s.getUsersBatch(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := make([]dto.UserContact, 0, len(users))
+	for _, user := range users {
+		contacts = append(contacts, dto.UserContact{
+			ID:        user.ID.String(),
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Email:     user.Email,
+		})
+	}
+	return contacts, nil
+}
+
+// GetSettlementAccounts returns the settlement accounts of the given users. Invalid and
+// unknown IDs are skipped; users without an account are returned with empty fields.
+func (s *UserService) GetSettlementAccounts(userIDs []string) ([]dto.UserSettlementAccount, error) {
+	users, err := s.getUsersBatch(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]dto.UserSettlementAccount, 0, len(users))
+	for _, user := range users {
+		accounts = append(accounts, dto.UserSettlementAccount{
+			ID:                      user.ID.String(),
+			SettlementBankName:      user.SettlementBankName,
+			SettlementAccountNumber: user.SettlementAccountNumber,
+			SettlementAccountName:   user.SettlementAccountName,
+		})
+	}
+	return accounts, nil
+}
+
+// getUsersBatch loads a batch of at most maxDisplayNameBatch users by ID
+func (s *UserService) getUsersBatch(userIDs []string) ([]models.User, error) {
+	if len(userIDs) > maxDisplayNameBatch {
+		return nil, ErrTooManyUserIDs
+	}
+
+	ids := make([]uuid.UUID, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if id, err := uuid.Parse(userID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	return s.userRepo.GetUsersByIDs(ids)
+}
+
 // UpdateProfile updates user profile
 func (s *UserService) UpdateProfile(userID string, req *dto.UpdateProfileRequest) (*dto.UserResponse, error) {
 	id, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, errors.New("invalid user ID")
+		return nil, ErrInvalidUserID
 	}
 
 	user, err := s.userRepo.GetUserByID(id)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	// Update fields if provided
@@ -58,6 +183,16 @@ func (s *UserService) UpdateProfile(userID string, req *dto.UpdateProfileRequest
 	if req.Phone != "" {
 		user.Phone = req.Phone
 	}
+	if req.AvatarURL != nil {
+		if user.AvatarURL, err = normalizeAvatarURL(*req.AvatarURL); err != nil {
+			return nil, err
+		}
+	}
+	if req.Bio != nil {
+		if user.Bio, err = normalizeBio(*req.Bio); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.userRepo.UpdateUser(user); err != nil {
 		return nil, errors.New("failed to update profile")
@@ -70,12 +205,12 @@ func (s *UserService) UpdateProfile(userID string, req *dto.UpdateProfileRequest
 func (s *UserService) UpdateSettlementAccount(userID string, bankName, accountNumber, accountName string) error {
 	id, err := uuid.Parse(userID)
 	if err != nil {
-		return errors.New("invalid user ID")
+		return ErrInvalidUserID
 	}
 
 	user, err := s.userRepo.GetUserByID(id)
 	if err != nil {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 
 	user.SettlementBankName = bankName
@@ -97,6 +232,8 @@ func mapUserToResponse(user *models.User) *dto.UserResponse {
 		FirstName:     user.FirstName,
 		LastName:      user.LastName,
 		Phone:         user.Phone,
+		AvatarURL:     user.AvatarURL,
+		Bio:           user.Bio,
 		EmailVerified: user.EmailVerified,
 		PhoneVerified: user.PhoneVerified,
 		KYCVerified:   user.KYCVerified,
@@ -106,3 +243,19 @@ func mapUserToResponse(user *models.User) *dto.UserResponse {
 	}
 }
 
+// MarkEmailVerified marks a user's email as verified without a verification token.
+// It backs a test hook and must never be reachable in production.
+func (s *UserService) MarkEmailVerified(userID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+
+	user, err := s.userRepo.GetUserByID(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	user.EmailVerified = true
+	return s.userRepo.UpdateUser(user)
+}
@@ -0,0 +1,9 @@
+-- Migration: Add public profile fields
+-- Description: Adds an avatar image URL and a short bio shown on public profiles
+
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS avatar_url VARCHAR(500),
+ADD COLUMN IF NOT EXISTS bio VARCHAR(280);
+
+COMMENT ON COLUMN users.avatar_url IS 'Absolute https URL of the user''s avatar image';
+COMMENT ON COLUMN users.bio IS 'Short public bio, at most 280 characters';
@@ -0,0 +1,27 @@
+-- Migration: Create KYC submissions table
+-- Description: Identity documents users submit for admin review before being marked KYC verified
+
+CREATE TABLE IF NOT EXISTS kyc_submissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    document_type VARCHAR(30) NOT NULL,
+    document_number VARCHAR(50) NOT NULL,
+    document_urls JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewer_id UUID,
+    rejection_reason VARCHAR(500),
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Users look up their latest submission; admins page through the pending queue
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_user_id ON kyc_submissions(user_id);
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_status ON kyc_submissions(status);
+
+-- A user may only have one submission awaiting review at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kyc_submissions_one_pending
+    ON kyc_submissions(user_id) WHERE status = 'pending';
+
+COMMENT ON COLUMN kyc_submissions.document_urls IS 'Externally hosted images of the document';
+COMMENT ON COLUMN kyc_submissions.rejection_reason IS 'Shown to the user when an admin rejects the submission';
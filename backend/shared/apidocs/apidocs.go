@@ -0,0 +1,78 @@
+// Package apidocs serves a service's OpenAPI spec, generated at build time with swag,
+// together with a Swagger UI page for browsing it.
+package apidocs
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// EnvVar opts a service into serving its API docs
+const EnvVar = "ENABLE_API_DOCS"
+
+// DefaultSpecPath is where `swag init -o docs` writes the spec, relative to the
+// service's working directory
+const DefaultSpecPath = "docs/swagger.json"
+
+// Enabled reports whether the docs routes should be registered. They are opt-in through
+// ENABLE_API_DOCS and refused whenever any of the given environment names is production.
+func Enabled(envs ...string) bool {
+	if os.Getenv(EnvVar) != "true" {
+		return false
+	}
+
+	for _, env := range envs {
+		if strings.EqualFold(strings.TrimSpace(env), "production") {
+			log.Printf("Warning: %s is set but API docs are never served in production", EnvVar)
+			return false
+		}
+	}
+	return true
+}
+
+// Spec serves the generated spec file. The file is read on every request so a
+// regenerated spec shows up without a restart.
+func Spec(path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		spec, err := os.ReadFile(path)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("API spec not found at %s; generate it with `make docs`", path), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	}
+}
+
+// UI serves a Swagger UI page that loads the spec from specURL
+func UI(title, specURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := uiPage.Execute(w, struct{ Title, SpecURL string }{title, specURL}); err != nil {
+			log.Printf("[ERROR] Failed to render API docs page: %v", err)
+		}
+	}
+}
+
+var uiPage = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = function () {
+      SwaggerUIBundle({ url: "{{.SpecURL}}", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`))
@@ -0,0 +1,82 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"runtime"
+)
+
+// Build metadata stamped at build time, e.g.
+//
+//	go build -ldflags "-X github.com/gofund/shared/buildinfo.Version=v1.4.0 \
+//	  -X github.com/gofund/shared/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/gofund/shared/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Unstamped builds report "dev".
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+const unknown = "dev"
+
+// Info describes the running binary
+type Info struct {
+	Service   string `json:"service"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Stamped reports whether the version was set via -ldflags
+func Stamped() bool {
+	return Version != ""
+}
+
+// ResolveVersion returns the stamped version, falling back to the given value
+// (usually DD_VERSION) and then to "dev"
+func ResolveVersion(fallback string) string {
+	if Stamped() {
+		return Version
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return unknown
+}
+
+// Get returns the build info for a service, using fallbackVersion when the
+// binary was not stamped
+func Get(service, fallbackVersion string) Info {
+	return Info{
+		Service:   service,
+		Version:   ResolveVersion(fallbackVersion),
+		Commit:    orUnknown(Commit),
+		BuildTime: orUnknown(BuildTime),
+		GoVersion: runtime.Version(),
+	}
+}
+
+// Handler serves the build info as JSON; mount it at GET /version
+func Handler(service, fallbackVersion string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Get(service, fallbackVersion))
+	}
+}
+
+// LogStartup logs the build info once at startup
+func LogStartup(service, fallbackVersion string) {
+	info := Get(service, fallbackVersion)
+	log.Printf("Starting %s version=%s commit=%s built=%s go=%s", info.Service, info.Version, info.Commit, info.BuildTime, info.GoVersion)
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return unknown
+	}
+	return value
+}
@@ -0,0 +1,98 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// stamp sets the build variables as -ldflags -X would, restoring them when the test ends
+func stamp(t *testing.T, version, commit, buildTime string) {
+	t.Helper()
+	previous := [3]string{Version, Commit, BuildTime}
+	Version, Commit, BuildTime = version, commit, buildTime
+	t.Cleanup(func() { Version, Commit, BuildTime = previous[0], previous[1], previous[2] })
+}
+
+func serveVersion(t *testing.T, fallbackVersion string) Info {
+	t.Helper()
+	w := httptest.NewRecorder()
+	Handler("goals-service", fallbackVersion)(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var info Info
+	if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+		t.Fatalf("failed to decode %s: %v", w.Body.String(), err)
+	}
+	return info
+}
+
+func TestVersionEndpointReportsStampedValues(t *testing.T) {
+	stamp(t, "v1.4.0", "abc1234", "2026-10-16T12:00:00Z")
+
+	got := serveVersion(t, "1.0.0-dd")
+	want := Info{Service: "goals-service", Version: "v1.4.0", Commit: "abc1234", BuildTime: "2026-10-16T12:00:00Z", GoVersion: runtime.Version()}
+	if got != want {
+		t.Errorf("GET /version = %+v, want %+v", got, want)
+	}
+}
+
+func TestVersionEndpointDefaults(t *testing.T) {
+	stamp(t, "", "", "")
+
+	tests := []struct {
+		name            string
+		fallbackVersion string
+		wantVersion     string
+	}{
+		{"unstamped falls back to DD_VERSION", "1.0.0-dd", "1.0.0-dd"},
+		{"unstamped without DD_VERSION", "", "dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := serveVersion(t, tt.fallbackVersion)
+			if got.Version != tt.wantVersion || got.Commit != "dev" || got.BuildTime != "dev" {
+				t.Errorf("GET /version = %+v, want version %s with dev commit and build time", got, tt.wantVersion)
+			}
+			if Stamped() {
+				t.Error("Stamped = true for an unstamped build")
+			}
+		})
+	}
+}
+
+// TestLdflagsStamping builds a binary with the -ldflags documented on the variables, so
+// a renamed variable or moved package breaks here rather than in the release pipeline
+func TestLdflagsStamping(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a binary")
+	}
+	binary := filepath.Join(t.TempDir(), "version")
+	ldflags := "-X github.com/gofund/shared/buildinfo.Version=v9.9.9" +
+		" -X github.com/gofund/shared/buildinfo.Commit=deadbee" +
+		" -X github.com/gofund/shared/buildinfo.BuildTime=2026-10-16T00:00:00Z"
+	if out, err := exec.Command("go", "build", "-ldflags", ldflags, "-o", binary, "./testdata/version").CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+
+	out, err := exec.Command(binary).Output()
+	if err != nil {
+		t.Fatalf("running the stamped binary: %v", err)
+	}
+	var info Info
+	if err := json.Unmarshal(out, &info); err != nil {
+		t.Fatalf("failed to decode %s: %v", out, err)
+	}
+	if info.Version != "v9.9.9" || info.Commit != "deadbee" || info.BuildTime != "2026-10-16T00:00:00Z" {
+		t.Errorf("stamped binary reports %+v", info)
+	}
+}
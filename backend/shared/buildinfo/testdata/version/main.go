@@ -0,0 +1,13 @@
+// Command version prints the build info of its own binary, for checking -ldflags stamping
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/gofund/shared/buildinfo"
+)
+
+func main() {
+	json.NewEncoder(os.Stdout).Encode(buildinfo.Get("version", ""))
+}
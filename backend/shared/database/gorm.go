@@ -21,12 +21,23 @@ type Config struct {
 	DBName   string
 	SSLMode  string
 	LogLevel logger.LogLevel
+	// SlowQueryThreshold is how long a query may take before it is logged and reported to
+	// Datadog as slow. Zero turns slow query reporting off.
+	SlowQueryThreshold time.Duration
+	// ReplicaHosts are read replicas of the database that plain reads are sent to. With
+	// none, every query goes to Host.
+	ReplicaHosts []string
+}
+
+// postgresDSN returns the DSN of the database in cfg on the server at host and port
+func postgresDSN(cfg Config, host string, port int) string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		host, port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
 }
 
 // NewGormDB creates a new GORM database connection with Datadog tracing
 func NewGormDB(cfg Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
+	dsn := postgresDSN(cfg, cfg.Host, cfg.Port)
 
 	// Open database with Datadog tracing enabled
 	db, err := gormtrace.Open(postgres.Open(dsn), &gorm.Config{
@@ -39,6 +50,18 @@ func NewGormDB(cfg Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if cfg.SlowQueryThreshold > 0 {
+		if err := db.Use(&slowQueryPlugin{threshold: cfg.SlowQueryThreshold}); err != nil {
+			return nil, fmt.Errorf("failed to register slow query reporting: %w", err)
+		}
+	}
+
+	if len(cfg.ReplicaHosts) > 0 {
+		if err := registerReplicas(db, cfg); err != nil {
+			return nil, fmt.Errorf("failed to register read replicas: %w", err)
+		}
+	}
+
 	// Configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -102,6 +125,8 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.User{},
 		&models.Session{},
 		&models.PasswordResetToken{},
+		&models.EmailVerificationToken{},
+		&models.KYCSubmission{},
 	); err != nil {
 		return fmt.Errorf("failed to migrate user models: %w", err)
 	}
@@ -112,6 +137,19 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.Contribution{},
 		&models.Proof{},
 		&models.Vote{},
+		&models.GoalTrending{},
+		&models.Comment{},
+		&models.GoalUpdate{},
+		&models.GoalWatch{},
+		&models.RecurringContribution{},
+		&models.AuditLog{},
+		&models.GoalCollaborator{},
+		&models.WithdrawalApproval{},
+		&models.WithdrawalStatusEvent{},
+		&models.ProofMedia{},
+		&models.RefundRequest{},
+		&models.OutboxEvent{},
+		&models.GoalReport{},
 	); err != nil {
 		return fmt.Errorf("failed to migrate goal models: %w", err)
 	}
@@ -122,6 +160,7 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.Transaction{},
 		&models.LedgerEntry{},
 		&models.BalanceSnapshot{},
+		&models.ReconciliationReport{},
 	); err != nil {
 		return fmt.Errorf("failed to migrate ledger models: %w", err)
 	}
@@ -149,6 +188,11 @@ func SetupDatabase(cfg Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// Full-text search indexes are built on expressions over the migrated tables
+	if err := createSearchIndexes(db); err != nil {
+		return nil, err
+	}
+
 
 
 	return db, nil
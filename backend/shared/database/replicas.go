@@ -0,0 +1,49 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// registerReplicas sends the reads of db to the read replicas in cfg.ReplicaHosts, picked
+// at random per query. A replica is reached with the primary's port, credentials and
+// database unless its host is given as host:port.
+//
+// Writes, locking reads and every query in a transaction stay on the primary. A read that
+// must see a write just made, which a lagging replica may not have yet, is sent to the
+// primary with Clauses(dbresolver.Write).
+func registerReplicas(db *gorm.DB, cfg Config) error {
+	replicas := make([]gorm.Dialector, 0, len(cfg.ReplicaHosts))
+	for _, replica := range cfg.ReplicaHosts {
+		host, port := replica, cfg.Port
+		if h, p, err := net.SplitHostPort(replica); err == nil {
+			n, err := strconv.Atoi(p)
+			if err != nil {
+				return fmt.Errorf("invalid port in replica host %q", replica)
+			}
+			host, port = h, n
+		}
+		replicas = append(replicas, postgres.Open(postgresDSN(cfg, host, port)))
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}).
+		SetMaxIdleConns(10).
+		SetMaxOpenConns(100).
+		SetConnMaxLifetime(time.Hour)
+	if err := db.Use(resolver); err != nil {
+		return err
+	}
+
+	log.Printf("Routing reads of %s to %d read replicas", cfg.DBName, len(replicas))
+	return nil
+}
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/gofund/shared/models"
+	"gorm.io/gorm"
+)
+
+// createSearchIndexes creates the full-text search indexes, which GORM cannot express
+// as struct tags
+func createSearchIndexes(db *gorm.DB) error {
+	log.Println("Creating search indexes...")
+
+	query := fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_goals_search ON goals USING GIN ((%s))`, models.GoalSearchVector)
+	if err := db.Exec(query).Error; err != nil {
+		return fmt.Errorf("failed to create goal search index: %w", err)
+	}
+
+	log.Println("Search indexes created successfully")
+	return nil
+}
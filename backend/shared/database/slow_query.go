@@ -0,0 +1,109 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofund/shared/metrics"
+	"gorm.io/gorm"
+)
+
+const slowQueryStartKey = "slow_query:start"
+
+var (
+	// placeholderList matches a run of bind placeholders, e.g. an expanded IN list, so
+	// lists of any length share a digest
+	placeholderList = regexp.MustCompile(`\$\d+(\s*,\s*\$\d+)*`)
+	whitespace      = regexp.MustCompile(`\s+`)
+)
+
+// slowQueryPlugin logs every query that takes longer than threshold and reports it to
+// Datadog under its SQL digest. Bound values are never logged.
+type slowQueryPlugin struct {
+	threshold time.Duration
+}
+
+// Name implements gorm.Plugin
+func (p *slowQueryPlugin) Name() string {
+	return "gofund:slow_query"
+}
+
+// Initialize implements gorm.Plugin, timing every kind of statement
+func (p *slowQueryPlugin) Initialize(db *gorm.DB) error {
+	// registrar is a callback position, e.g. db.Callback().Query().Before("*")
+	type registrar interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}
+	register := func(operation string, before, after registrar) error {
+		if err := before.Register("slow_query:before_"+operation, p.start); err != nil {
+			return err
+		}
+		return after.Register("slow_query:after_"+operation, func(tx *gorm.DB) { p.finish(tx, operation) })
+	}
+
+	cb := db.Callback()
+	if err := register("create", cb.Create().Before("*"), cb.Create().After("*")); err != nil {
+		return err
+	}
+	if err := register("query", cb.Query().Before("*"), cb.Query().After("*")); err != nil {
+		return err
+	}
+	if err := register("update", cb.Update().Before("*"), cb.Update().After("*")); err != nil {
+		return err
+	}
+	if err := register("delete", cb.Delete().Before("*"), cb.Delete().After("*")); err != nil {
+		return err
+	}
+	if err := register("row", cb.Row().Before("*"), cb.Row().After("*")); err != nil {
+		return err
+	}
+	return register("raw", cb.Raw().Before("*"), cb.Raw().After("*"))
+}
+
+// start stamps the statement with when it began
+func (p *slowQueryPlugin) start(tx *gorm.DB) {
+	tx.InstanceSet(slowQueryStartKey, time.Now())
+}
+
+// finish reports the statement if it ran over the threshold
+func (p *slowQueryPlugin) finish(tx *gorm.DB, operation string) {
+	value, ok := tx.InstanceGet(slowQueryStartKey)
+	if !ok {
+		return
+	}
+	started, ok := value.(time.Time)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(started)
+	if elapsed < p.threshold {
+		return
+	}
+
+	sql := normalizeSQL(tx.Statement.SQL.String())
+	digest := sqlDigest(sql)
+	table := tx.Statement.Table
+	if table == "" {
+		table = "unknown"
+	}
+
+	metrics.TrackSlowQuery(digest, table, operation, elapsed)
+	log.Printf("Slow query (%s, digest %s, table %s): %s", elapsed.Round(time.Millisecond), digest, table, sql)
+}
+
+// normalizeSQL collapses whitespace and placeholder lists so queries of the same shape
+// read, and digest, the same
+func normalizeSQL(sql string) string {
+	sql = placeholderList.ReplaceAllString(sql, "?")
+	return strings.TrimSpace(whitespace.ReplaceAllString(sql, " "))
+}
+
+// sqlDigest identifies a normalized query by the first 16 hex digits of its SHA-256
+func sqlDigest(sql string) string {
+	sum := sha256.Sum256([]byte(sql))
+	return hex.EncodeToString(sum[:8])
+}
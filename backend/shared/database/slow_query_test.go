@@ -0,0 +1,116 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofund/shared/metrics"
+	"gorm.io/gorm"
+)
+
+func TestNormalizeSQL(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{
+			sql:  "SELECT count(*) FROM \"contributions\"\n\tWHERE goal_id = $1   AND status = $2",
+			want: `SELECT count(*) FROM "contributions" WHERE goal_id = ? AND status = ?`,
+		},
+		{
+			sql:  `SELECT * FROM "goals" WHERE id IN ($1,$2, $3)`,
+			want: `SELECT * FROM "goals" WHERE id IN (?)`,
+		},
+		{
+			sql:  `SELECT * FROM "goals" WHERE id IN ($1)`,
+			want: `SELECT * FROM "goals" WHERE id IN (?)`,
+		},
+	}
+	for _, tt := range tests {
+		if got := normalizeSQL(tt.sql); got != tt.want {
+			t.Errorf("normalizeSQL(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestSQLDigestIdentifiesTheQueryShape(t *testing.T) {
+	short := sqlDigest(normalizeSQL(`SELECT * FROM "goals" WHERE id IN ($1,$2)`))
+	long := sqlDigest(normalizeSQL("SELECT * FROM \"goals\"\nWHERE id IN ($1, $2, $3, $4)"))
+	other := sqlDigest(normalizeSQL(`SELECT * FROM "goals" WHERE owner_id IN ($1,$2)`))
+
+	if len(short) != 16 {
+		t.Errorf("digest %q is %d characters, want 16", short, len(short))
+	}
+	if short != long {
+		t.Errorf("queries differing only in list length digest to %s and %s", short, long)
+	}
+	if short == other {
+		t.Errorf("different queries share digest %s", short)
+	}
+}
+
+// countingClient counts the slow query metrics it receives
+type countingClient struct {
+	counts     map[string]int
+	histograms map[string]float64
+	tags       []string
+}
+
+func (c *countingClient) Incr(name string, tags []string, rate float64) error {
+	c.counts[name]++
+	c.tags = tags
+	return nil
+}
+
+func (c *countingClient) Histogram(name string, value float64, tags []string, rate float64) error {
+	c.histograms[name] = value
+	return nil
+}
+
+func (c *countingClient) Gauge(string, float64, []string, float64) error { return nil }
+func (c *countingClient) Close() error                                   { return nil }
+
+func TestSlowQueryPluginReportsQueriesOverTheThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		started   bool
+		wantCount int
+	}{
+		{name: "over the threshold", threshold: 0, started: true, wantCount: 1},
+		{name: "under the threshold", threshold: time.Hour, started: true},
+		{name: "not timed", threshold: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &countingClient{counts: map[string]int{}, histograms: map[string]float64{}}
+			previous := metrics.SetClient(client)
+			t.Cleanup(func() { metrics.SetClient(previous) })
+
+			plugin := &slowQueryPlugin{threshold: tt.threshold}
+			tx := &gorm.DB{Config: &gorm.Config{}, Statement: &gorm.Statement{Table: "contributions"}}
+			if tt.started {
+				plugin.start(tx)
+			}
+			tx.Statement.SQL.WriteString(`SELECT count(*) FROM "contributions" WHERE goal_id = $1 AND status = $2`)
+			plugin.finish(tx, "query")
+
+			if got := client.counts["db.slow_query.count"]; got != tt.wantCount {
+				t.Fatalf("slow queries reported = %d, want %d", got, tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+			if _, ok := client.histograms["db.slow_query.duration"]; !ok {
+				t.Error("no slow query duration was recorded")
+			}
+			digest := sqlDigest(`SELECT count(*) FROM "contributions" WHERE goal_id = ? AND status = ?`)
+			want := []string{"digest:" + digest, "table:contributions", "operation:query"}
+			if strings.Join(client.tags, ",") != strings.Join(want, ",") {
+				t.Errorf("tags = %v, want %v", client.tags, want)
+			}
+		})
+	}
+}
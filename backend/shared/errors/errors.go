@@ -1,26 +1,157 @@
 package errors
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Kind classifies a domain error by what went wrong, so transports can pick a status code
+// without knowing the individual error.
+//
+// State conflicts are a single class: a well-formed request that the current state of a
+// resource refuses (goal not open, already voted, refund in progress, insufficient
+// balance) is always KindConflict and maps to 409. 422 is not used.
+type Kind string
+
+const (
+	KindInternal        Kind = "internal"        // 500: anything not classified below
+	KindValidation      Kind = "validation"      // 400: the request itself is malformed or out of range
+	KindUnauthenticated Kind = "unauthenticated" // 401: credentials missing, invalid or expired
+	KindForbidden       Kind = "forbidden"       // 403: authenticated but not allowed to do this
+	KindNotFound        Kind = "not_found"       // 404: the resource does not exist
+	KindConflict        Kind = "conflict"        // 409: refused by the resource's current state
+	KindRateLimited     Kind = "rate_limited"    // 429: the caller sent too many requests
+)
 
 // DomainError represents a domain-specific error
 type DomainError struct {
+	Kind    Kind
 	Code    string
 	Message string
 	Err     error
 }
 
 func (e *DomainError) Error() string {
-	if e.Err != nil {
+	// A detailed copy (see WithDetail) already repeats its parent's message
+	if _, detailed := e.Err.(*DomainError); e.Err != nil && !detailed {
 		return fmt.Sprintf("[%s] %s: %v", e.Code, e.Message, e.Err)
 	}
 	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
 }
 
+// Unwrap returns the underlying error
+func (e *DomainError) Unwrap() error {
+	return e.Err
+}
+
+// WithDetail returns an error of the same kind and code whose message adds detail. It
+// still matches e with errors.Is.
+func (e *DomainError) WithDetail(detail string) *DomainError {
+	return &DomainError{
+		Kind:    e.Kind,
+		Code:    e.Code,
+		Message: e.Message + ": " + detail,
+		Err:     e,
+	}
+}
+
 // NewDomainError creates a new domain error
 func NewDomainError(code, message string, err error) *DomainError {
 	return &DomainError{
+		Kind:    KindInternal,
 		Code:    code,
 		Message: message,
 		Err:     err,
 	}
 }
+
+// New creates a domain error of the given kind
+func New(kind Kind, code, message string) *DomainError {
+	return &DomainError{Kind: kind, Code: code, Message: message}
+}
+
+// Validation creates a KindValidation error
+func Validation(code, message string) *DomainError {
+	return New(KindValidation, code, message)
+}
+
+// Unauthenticated creates a KindUnauthenticated error
+func Unauthenticated(code, message string) *DomainError {
+	return New(KindUnauthenticated, code, message)
+}
+
+// Forbidden creates a KindForbidden error
+func Forbidden(code, message string) *DomainError {
+	return New(KindForbidden, code, message)
+}
+
+// NotFound creates a KindNotFound error
+func NotFound(code, message string) *DomainError {
+	return New(KindNotFound, code, message)
+}
+
+// Conflict creates a KindConflict error
+func Conflict(code, message string) *DomainError {
+	return New(KindConflict, code, message)
+}
+
+// RateLimited creates a KindRateLimited error
+func RateLimited(code, message string) *DomainError {
+	return New(KindRateLimited, code, message)
+}
+
+// As returns the first DomainError in err's chain, or nil
+func As(err error) *DomainError {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return nil
+}
+
+// KindOf returns the kind of the first DomainError in err's chain. Errors that are not
+// domain errors are KindInternal.
+func KindOf(err error) Kind {
+	if domainErr := As(err); domainErr != nil && domainErr.Kind != "" {
+		return domainErr.Kind
+	}
+	return KindInternal
+}
+
+// HTTPStatus maps err to the status code for its kind
+func HTTPStatus(err error) int {
+	switch KindOf(err) {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthenticated:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Message returns the client-facing message for err: a domain error's own message, or
+// err.Error() for anything else, including errors that merely wrap a domain error
+func Message(err error) string {
+	if domainErr, ok := err.(*DomainError); ok {
+		return domainErr.Message
+	}
+	return err.Error()
+}
+
+// Code returns the code of the first DomainError in err's chain, or ""
+func Code(err error) string {
+	if domainErr := As(err); domainErr != nil {
+		return domainErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// TestHTTPStatus is the status contract every service's error responses follow
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"validation", Validation("invalid_amount", "amount must be greater than 0"), http.StatusBadRequest},
+		{"unauthenticated", Unauthenticated("unauthenticated", "not signed in"), http.StatusUnauthorized},
+		{"forbidden", Forbidden("forbidden", "not the owner"), http.StatusForbidden},
+		{"not found", NotFound("goal_not_found", "goal not found"), http.StatusNotFound},
+		{"conflict", Conflict("already_voted", "already voted"), http.StatusConflict},
+		{"rate limited", RateLimited("rate_limited", "slow down"), http.StatusTooManyRequests},
+		{"internal", New(KindInternal, "boom", "boom"), http.StatusInternalServerError},
+		{"wrapped domain error", fmt.Errorf("closing goal: %w", Conflict("invalid_goal_status", "goal is closed")), http.StatusConflict},
+		{"domain error without a kind", &DomainError{Code: "legacy", Message: "legacy"}, http.StatusInternalServerError},
+		{"plain error", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTTPStatus(tt.err); got != tt.want {
+				t.Errorf("HTTPStatus = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithDetailKeepsKindAndCode(t *testing.T) {
+	base := Validation("fixed_amount_mismatch", "amount does not match")
+	detailed := base.WithDetail("must be exactly 5000")
+
+	if !errors.Is(detailed, base) {
+		t.Error("a detailed error does not match its base with errors.Is")
+	}
+	if HTTPStatus(detailed) != http.StatusBadRequest || Code(detailed) != "fixed_amount_mismatch" {
+		t.Errorf("detailed error: status %d, code %q", HTTPStatus(detailed), Code(detailed))
+	}
+}
+
+func TestMessageAndCode(t *testing.T) {
+	domainErr := NotFound("goal_not_found", "goal not found")
+	wrapped := fmt.Errorf("loading goal: %w", domainErr)
+	plain := errors.New("connection refused")
+
+	if got := Message(domainErr); got != "goal not found" {
+		t.Errorf("Message(domain error) = %q", got)
+	}
+	if got := Message(wrapped); got != wrapped.Error() {
+		t.Errorf("Message(wrapped) = %q, want the wrapping message %q", got, wrapped.Error())
+	}
+	if Code(wrapped) != "goal_not_found" || Code(plain) != "" {
+		t.Errorf("Code(wrapped) = %q, Code(plain) = %q", Code(wrapped), Code(plain))
+	}
+	if KindOf(plain) != KindInternal {
+		t.Errorf("KindOf(plain) = %s, want %s", KindOf(plain), KindInternal)
+	}
+}
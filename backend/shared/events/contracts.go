@@ -9,12 +9,18 @@ type Event interface {
 
 // PaymentVerified event is emitted when a payment is verified
 type PaymentVerified struct {
-	ID        string
-	PaymentID string
-	UserID    string
-	GoalID    string
-	Amount    int64 // Amount in smallest currency unit (e.g., kobo for NGN)
-	CreatedAt int64
+	ID             string
+	PaymentID      string
+	UserID         string
+	GoalID         string
+	ContributionID string // contribution intent the payment was initialized for, if any
+	Amount         int64  // Amount in smallest currency unit (e.g., kobo for NGN)
+	Currency       string // ISO 4217 code the payment was made in; empty in events from before it was carried, which were all NGN
+	// AuthorizationCode and AuthorizationEmail identify the card paid with when Paystack
+	// allows charging it again (recurring contributions); empty otherwise
+	AuthorizationCode  string
+	AuthorizationEmail string
+	CreatedAt          int64
 }
 
 func (e PaymentVerified) EventType() string { return "PaymentVerified" }
@@ -23,12 +29,13 @@ func (e PaymentVerified) Timestamp() int64 { return e.CreatedAt }
 
 // LedgerEntryCreated event is emitted when a ledger entry is created
 type LedgerEntryCreated struct {
-	ID           string
+	ID            string
 	LedgerEntryID string
-	AccountID    string
-	Amount       int64
-	EntryType    string
-	CreatedAt    int64
+	TransactionID string
+	AccountID     string
+	Amount        int64
+	EntryType     string
+	CreatedAt     int64
 }
 
 func (e LedgerEntryCreated) EventType() string { return "LedgerEntryCreated" }
@@ -37,16 +44,222 @@ func (e LedgerEntryCreated) Timestamp() int64    { return e.CreatedAt }
 
 // GoalFunded event is emitted when a goal reaches its target
 type GoalFunded struct {
-	ID        string
-	GoalID    string
-	Amount    int64
-	CreatedAt int64
+	ID         string
+	GoalID     string
+	Title      string
+	Amount     int64
+	WatcherIDs []string
+	CreatedAt  int64
 }
 
 func (e GoalFunded) EventType() string { return "GoalFunded" }
 func (e GoalFunded) EventID() string   { return e.ID }
 func (e GoalFunded) Timestamp() int64  { return e.CreatedAt }
 
+// GoalClosed event is emitted when an owner closes a goal to new contributions
+type GoalClosed struct {
+	ID             string
+	GoalID         string
+	OwnerID        string
+	Title          string
+	ContributorIDs []string
+	CreatedAt      int64
+}
+
+func (e GoalClosed) EventType() string { return "GoalClosed" }
+func (e GoalClosed) EventID() string   { return e.ID }
+func (e GoalClosed) Timestamp() int64  { return e.CreatedAt }
+
+// GoalCancelled event is emitted when an owner cancels a goal
+type GoalCancelled struct {
+	ID             string
+	GoalID         string
+	OwnerID        string
+	Title          string
+	ContributorIDs []string
+	CreatedAt      int64
+}
+
+func (e GoalCancelled) EventType() string { return "GoalCancelled" }
+func (e GoalCancelled) EventID() string   { return e.ID }
+func (e GoalCancelled) Timestamp() int64  { return e.CreatedAt }
+
+// GoalArchived event is emitted when an owner deletes a goal that money has moved or may
+// still move through, which archives it instead
+type GoalArchived struct {
+	ID             string
+	GoalID         string
+	OwnerID        string
+	Title          string
+	ContributorIDs []string
+	CreatedAt      int64
+}
+
+func (e GoalArchived) EventType() string { return "GoalArchived" }
+func (e GoalArchived) EventID() string   { return e.ID }
+func (e GoalArchived) Timestamp() int64  { return e.CreatedAt }
+
+// GoalDeleted event is emitted when an owner deletes a goal that never received money
+type GoalDeleted struct {
+	ID        string
+	GoalID    string
+	OwnerID   string
+	Title     string
+	CreatedAt int64
+}
+
+func (e GoalDeleted) EventType() string { return "GoalDeleted" }
+func (e GoalDeleted) EventID() string   { return e.ID }
+func (e GoalDeleted) Timestamp() int64  { return e.CreatedAt }
+
+// GoalSuspended event is emitted when an admin suspends a goal
+type GoalSuspended struct {
+	ID        string
+	GoalID    string
+	OwnerID   string
+	Title     string
+	Reason    string
+	CreatedAt int64
+}
+
+func (e GoalSuspended) EventType() string { return "GoalSuspended" }
+func (e GoalSuspended) EventID() string   { return e.ID }
+func (e GoalSuspended) Timestamp() int64  { return e.CreatedAt }
+
+// GoalUnsuspended event is emitted when an admin lifts a goal's suspension. Status is the
+// status the goal returned to.
+type GoalUnsuspended struct {
+	ID        string
+	GoalID    string
+	OwnerID   string
+	Title     string
+	Status    string
+	CreatedAt int64
+}
+
+func (e GoalUnsuspended) EventType() string { return "GoalUnsuspended" }
+func (e GoalUnsuspended) EventID() string   { return e.ID }
+func (e GoalUnsuspended) Timestamp() int64  { return e.CreatedAt }
+
+// GoalReportsDismissed event is emitted when an admin dismisses the open reports on a goal
+type GoalReportsDismissed struct {
+	ID          string
+	GoalID      string
+	DismissedBy string
+	Dismissed   int64
+	CreatedAt   int64
+}
+
+func (e GoalReportsDismissed) EventType() string { return "GoalReportsDismissed" }
+func (e GoalReportsDismissed) EventID() string   { return e.ID }
+func (e GoalReportsDismissed) Timestamp() int64  { return e.CreatedAt }
+
+// GoalBankDetailsChanged event is emitted when the owner of a goal with confirmed
+// contributions changes its deposit account. ContributorIDs are the goal's confirmed
+// contributors at the time of the change.
+type GoalBankDetailsChanged struct {
+	ID                 string
+	GoalID             string
+	OwnerID            string
+	Title              string
+	BankName           string
+	AccountNumberLast4 string
+	ContributorIDs     []string
+	CreatedAt          int64
+}
+
+func (e GoalBankDetailsChanged) EventType() string { return "GoalBankDetailsChanged" }
+func (e GoalBankDetailsChanged) EventID() string   { return e.ID }
+func (e GoalBankDetailsChanged) Timestamp() int64  { return e.CreatedAt }
+
+// GoalCommented event is emitted when someone other than the owner comments on a goal or
+// replies to one of its comments
+type GoalCommented struct {
+	ID          string
+	GoalID      string
+	OwnerID     string
+	Title       string
+	CommentID   string
+	ParentID    string // empty for a top-level comment
+	CommenterID string
+	Excerpt     string
+	CreatedAt   int64
+}
+
+func (e GoalCommented) EventType() string { return "GoalCommented" }
+func (e GoalCommented) EventID() string   { return e.ID }
+func (e GoalCommented) Timestamp() int64  { return e.CreatedAt }
+
+// GoalUpdatePosted event is emitted when an owner posts an update to a goal. ContributorIDs
+// are the goal's confirmed contributors at the time of posting.
+type GoalUpdatePosted struct {
+	ID             string
+	GoalID         string
+	UpdateID       string
+	Title          string
+	UpdateTitle    string
+	ContributorIDs []string
+	WatcherIDs     []string
+	CreatedAt      int64
+}
+
+func (e GoalUpdatePosted) EventType() string { return "GoalUpdatePosted" }
+func (e GoalUpdatePosted) EventID() string   { return e.ID }
+func (e GoalUpdatePosted) Timestamp() int64  { return e.CreatedAt }
+
+// GoalClosedEarly event is emitted when a close-on-target goal reaches its target and is
+// closed automatically. Payments already in flight still land; no new ones are accepted.
+type GoalClosedEarly struct {
+	ID                     string
+	GoalID                 string
+	OwnerID                string
+	Title                  string
+	TargetAmount           int64
+	TotalAmount            int64
+	ContributorIDs         []string
+	PendingUserIDs         []string
+	PendingContributionIDs []string
+	CreatedAt              int64
+}
+
+func (e GoalClosedEarly) EventType() string { return "GoalClosedEarly" }
+func (e GoalClosedEarly) EventID() string   { return e.ID }
+func (e GoalClosedEarly) Timestamp() int64  { return e.CreatedAt }
+
+// GoalDeadlineReached event is emitted when an open goal passes its deadline and is closed
+// automatically. TargetMet tells whether the goal raised its full target in time.
+type GoalDeadlineReached struct {
+	ID             string
+	GoalID         string
+	OwnerID        string
+	Title          string
+	TargetAmount   int64
+	TotalAmount    int64
+	TargetMet      bool
+	ContributorIDs []string
+	Deadline       int64
+	CreatedAt      int64
+}
+
+func (e GoalDeadlineReached) EventType() string { return "GoalDeadlineReached" }
+func (e GoalDeadlineReached) EventID() string   { return e.ID }
+func (e GoalDeadlineReached) Timestamp() int64  { return e.CreatedAt }
+
+// GoalDeadlineApproaching event is emitted once per goal when an open goal comes within
+// the reminder window of its deadline, so its watchers can contribute before it closes
+type GoalDeadlineApproaching struct {
+	ID         string
+	GoalID     string
+	Title      string
+	Deadline   int64
+	WatcherIDs []string
+	CreatedAt  int64
+}
+
+func (e GoalDeadlineApproaching) EventType() string { return "GoalDeadlineApproaching" }
+func (e GoalDeadlineApproaching) EventID() string   { return e.ID }
+func (e GoalDeadlineApproaching) Timestamp() int64  { return e.CreatedAt }
+
 // ProofSubmitted event is emitted when proof is submitted
 type ProofSubmitted struct {
 	ID        string
@@ -71,6 +284,30 @@ func (e ProofVerified) EventType() string { return "ProofVerified" }
 func (e ProofVerified) EventID() string   { return e.ID }
 func (e ProofVerified) Timestamp() int64 { return e.CreatedAt }
 
+// ProofRejected event is emitted when contributors' votes reject a proof. Withdrawals
+// against its milestone (or the goal, for a goal-level proof) are blocked until another
+// proof is verified. Comments are those left with "not satisfied" votes, without voters;
+// RefundPath is the refund initiation endpoint notifications link to.
+type ProofRejected struct {
+	ID               string
+	GoalID           string
+	MilestoneID      string
+	ProofID          string
+	OwnerID          string
+	Title            string
+	ProofTitle       string
+	SatisfiedVotes   int64
+	UnsatisfiedVotes int64
+	Comments         []string
+	ContributorIDs   []string
+	RefundPath       string
+	CreatedAt        int64
+}
+
+func (e ProofRejected) EventType() string { return "ProofRejected" }
+func (e ProofRejected) EventID() string   { return e.ID }
+func (e ProofRejected) Timestamp() int64  { return e.CreatedAt }
+
 // UserSignedUp event is emitted when a user signs up
 type UserSignedUp struct {
 	ID        string
@@ -97,6 +334,18 @@ func (e PasswordResetRequested) EventType() string { return "PasswordResetReques
 func (e PasswordResetRequested) EventID() string   { return e.ID }
 func (e PasswordResetRequested) Timestamp() int64  { return e.CreatedAt }
 
+// PasswordChanged event is emitted when a logged-in user changes their password
+type PasswordChanged struct {
+	ID        string
+	UserID    string
+	Email     string
+	CreatedAt int64
+}
+
+func (e PasswordChanged) EventType() string { return "PasswordChanged" }
+func (e PasswordChanged) EventID() string   { return e.ID }
+func (e PasswordChanged) Timestamp() int64  { return e.CreatedAt }
+
 // EmailVerificationRequested event is emitted when email verification is requested
 type EmailVerificationRequested struct {
 	ID        string
@@ -123,21 +372,94 @@ func (e KYCVerified) EventType() string { return "KYCVerified" }
 func (e KYCVerified) EventID() string   { return e.ID }
 func (e KYCVerified) Timestamp() int64  { return e.CreatedAt }
 
-// RefundInitiated event is emitted when a refund is initiated
+// KYCRejected event is emitted when an admin rejects a user's KYC submission
+type KYCRejected struct {
+	ID           string
+	UserID       string
+	SubmissionID string
+	Email        string
+	Reason       string
+	CreatedAt    int64
+}
+
+func (e KYCRejected) EventType() string { return "KYCRejected" }
+func (e KYCRejected) EventID() string   { return e.ID }
+func (e KYCRejected) Timestamp() int64  { return e.CreatedAt }
+
+// RefundInitiated event is emitted when a refund is initiated. It carries the
+// disbursements so payments-service can pay them out without calling back.
+// RequestedBy is set when the refund settles a contributor's refund request.
 type RefundInitiated struct {
 	ID                string
 	RefundID          string
 	GoalID            string
 	InitiatedBy       string
+	RequestedBy       string
 	RefundPercentage  float64
 	TotalRefundAmount int64
+	Currency          string
+	Disbursements     []RefundDisbursementItem
 	CreatedAt         int64
 }
 
+// RefundDisbursementItem is one contributor's share of a refund, with the settlement
+// account snapshot it is paid to
+type RefundDisbursementItem struct {
+	DisbursementID string
+	ContributionID string
+	UserID         string
+	Amount         int64
+	Currency       string
+	BankName       string
+	AccountNumber  string
+	AccountName    string
+}
+
 func (e RefundInitiated) EventType() string { return "RefundInitiated" }
 func (e RefundInitiated) EventID() string   { return e.ID }
 func (e RefundInitiated) Timestamp() int64  { return e.CreatedAt }
 
+// RefundRequested event is emitted when a contributor asks for a contribution to be
+// refunded. AutoApproved requests were made within the grace window and are already
+// being refunded; the rest await the goal owner.
+type RefundRequested struct {
+	ID             string
+	RequestID      string
+	GoalID         string
+	GoalTitle      string
+	OwnerID        string
+	ContributionID string
+	UserID         string
+	Amount         int64
+	Currency       string
+	Reason         string
+	AutoApproved   bool
+	CreatedAt      int64
+}
+
+func (e RefundRequested) EventType() string { return "RefundRequested" }
+func (e RefundRequested) EventID() string   { return e.ID }
+func (e RefundRequested) Timestamp() int64  { return e.CreatedAt }
+
+// RefundRequestDenied event is emitted when a goal owner turns down a contributor's
+// refund request
+type RefundRequestDenied struct {
+	ID             string
+	RequestID      string
+	GoalID         string
+	GoalTitle      string
+	ContributionID string
+	UserID         string
+	Amount         int64
+	Currency       string
+	Note           string
+	CreatedAt      int64
+}
+
+func (e RefundRequestDenied) EventType() string { return "RefundRequestDenied" }
+func (e RefundRequestDenied) EventID() string   { return e.ID }
+func (e RefundRequestDenied) Timestamp() int64  { return e.CreatedAt }
+
 // RefundCompleted event is emitted when a refund is completed
 type RefundCompleted struct {
 	ID                string
@@ -151,6 +473,51 @@ func (e RefundCompleted) EventType() string { return "RefundCompleted" }
 func (e RefundCompleted) EventID() string   { return e.ID }
 func (e RefundCompleted) Timestamp() int64  { return e.CompletedAt }
 
+// ContributionConfirmed event is emitted when a contribution's payment is confirmed.
+// For anonymous contributions UserID is empty and ContributorName is "Anonymous".
+// Amount is the contribution itself; ChargeAmount is what the contributor paid, of which
+// FeeAmount went to the platform and NetAmount to the goal. Events from before platform
+// fees carry only Amount.
+type ContributionConfirmed struct {
+	ID              string
+	ContributionID  string
+	UserID          string
+	GoalID          string
+	GoalOwnerID     string
+	GoalTitle       string
+	Amount          int64
+	ChargeAmount    int64
+	FeeAmount       int64
+	NetAmount       int64
+	Currency        string
+	ContributorName string
+	IsAnonymous     bool
+	CreatedAt       int64
+}
+
+func (e ContributionConfirmed) EventType() string { return "ContributionConfirmed" }
+func (e ContributionConfirmed) EventID() string   { return e.ID }
+func (e ContributionConfirmed) Timestamp() int64  { return e.CreatedAt }
+
+// RecurringContributionFailed event is emitted when a recurring contribution stops
+// charging: PAUSED after its charges keep failing, or CANCELLED (PAUSED while suspended)
+// when its goal no longer accepts contributions. Reason says why.
+type RecurringContributionFailed struct {
+	ID                      string
+	RecurringContributionID string
+	UserID                  string
+	GoalID                  string
+	GoalTitle               string
+	Amount                  int64
+	Status                  string
+	Reason                  string
+	CreatedAt               int64
+}
+
+func (e RecurringContributionFailed) EventType() string { return "RecurringContributionFailed" }
+func (e RecurringContributionFailed) EventID() string   { return e.ID }
+func (e RecurringContributionFailed) Timestamp() int64  { return e.CreatedAt }
+
 // ContributionRefunded event is emitted when a contribution is refunded
 type ContributionRefunded struct {
 	ID             string
@@ -164,3 +531,120 @@ type ContributionRefunded struct {
 func (e ContributionRefunded) EventType() string { return "ContributionRefunded" }
 func (e ContributionRefunded) EventID() string   { return e.ID }
 func (e ContributionRefunded) Timestamp() int64  { return e.CreatedAt }
+
+// WithdrawalRequested event is emitted when a goal owner requests a withdrawal. It carries
+// a snapshot of the destination account so payments-service can pay out without a lookup.
+type WithdrawalRequested struct {
+	ID            string
+	WithdrawalID  string
+	GoalID        string
+	OwnerID       string
+	GoalTitle     string
+	Amount        int64
+	Currency      string
+	BankName      string
+	BankCode      string // empty when the owner gave only a bank name
+	AccountNumber string
+	AccountName   string
+	CreatedAt     int64
+}
+
+func (e WithdrawalRequested) EventType() string { return "WithdrawalRequested" }
+func (e WithdrawalRequested) EventID() string   { return e.ID }
+func (e WithdrawalRequested) Timestamp() int64  { return e.CreatedAt }
+
+// WithdrawalApprovalRequested event is emitted when a withdrawal on a co-owned goal needs
+// more approvals before it is paid out. ApproverIDs are the collaborators who have not
+// approved it yet.
+type WithdrawalApprovalRequested struct {
+	ID                string
+	WithdrawalID      string
+	GoalID            string
+	GoalTitle         string
+	RequestedBy       string
+	Amount            int64
+	Currency          string
+	ApprovalsReceived int
+	RequiredApprovals int
+	ApproverIDs       []string
+	CreatedAt         int64
+}
+
+func (e WithdrawalApprovalRequested) EventType() string { return "WithdrawalApprovalRequested" }
+func (e WithdrawalApprovalRequested) EventID() string   { return e.ID }
+func (e WithdrawalApprovalRequested) Timestamp() int64  { return e.CreatedAt }
+
+// WithdrawalCompleted event is emitted when the transfer for a withdrawal succeeds
+type WithdrawalCompleted struct {
+	ID                string
+	WithdrawalID      string
+	GoalID            string
+	OwnerID           string
+	GoalTitle         string
+	Amount            int64
+	Currency          string
+	TransferReference string
+	TransferCode      string
+	CompletedAt       int64
+}
+
+func (e WithdrawalCompleted) EventType() string { return "WithdrawalCompleted" }
+func (e WithdrawalCompleted) EventID() string   { return e.ID }
+func (e WithdrawalCompleted) Timestamp() int64  { return e.CompletedAt }
+
+// WithdrawalFailed event is emitted when the transfer for a withdrawal could not be
+// started or was rejected by the provider
+type WithdrawalFailed struct {
+	ID                string
+	WithdrawalID      string
+	GoalID            string
+	OwnerID           string
+	GoalTitle         string
+	Amount            int64
+	Currency          string
+	TransferReference string
+	Reason            string
+	CreatedAt         int64
+}
+
+func (e WithdrawalFailed) EventType() string { return "WithdrawalFailed" }
+func (e WithdrawalFailed) EventID() string   { return e.ID }
+func (e WithdrawalFailed) Timestamp() int64  { return e.CreatedAt }
+
+// RefundDisbursementCompleted event is emitted by payments-service when a refund
+// disbursement's transfer succeeds
+type RefundDisbursementCompleted struct {
+	ID                string
+	RefundID          string
+	DisbursementID    string
+	GoalID            string
+	UserID            string
+	Amount            int64
+	Currency          string
+	TransferReference string
+	TransferCode      string
+	CompletedAt       int64
+}
+
+func (e RefundDisbursementCompleted) EventType() string { return "RefundDisbursementCompleted" }
+func (e RefundDisbursementCompleted) EventID() string   { return e.ID }
+func (e RefundDisbursementCompleted) Timestamp() int64  { return e.CompletedAt }
+
+// RefundDisbursementFailed event is emitted by payments-service when a refund
+// disbursement's transfer could not be made or was reversed
+type RefundDisbursementFailed struct {
+	ID                string
+	RefundID          string
+	DisbursementID    string
+	GoalID            string
+	UserID            string
+	Amount            int64
+	Currency          string
+	TransferReference string
+	Reason            string
+	CreatedAt         int64
+}
+
+func (e RefundDisbursementFailed) EventType() string { return "RefundDisbursementFailed" }
+func (e RefundDisbursementFailed) EventID() string   { return e.ID }
+func (e RefundDisbursementFailed) Timestamp() int64  { return e.CreatedAt }
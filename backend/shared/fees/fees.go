@@ -0,0 +1,83 @@
+// Package fees computes the platform fee GoFund takes on contributions. goals-service
+// and payments-service read the same schedule, so the fee a contributor is charged at
+// checkout is the fee the goal is debited.
+package fees
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofund/shared/models"
+)
+
+// basisPointsPerWhole is 100%, in basis points
+const basisPointsPerWhole = 10000
+
+// Rate is the fee on one currency: a percentage of the amount, in basis points, plus a
+// fixed amount in the currency's smallest unit
+type Rate struct {
+	BasisPoints int64
+	Fixed       int64
+}
+
+// Schedule holds the fee rate per currency code. A currency without a rate, and a nil
+// schedule, carry no fee.
+type Schedule map[string]Rate
+
+// ParseSchedule parses a comma separated list of CURRENCY:BASIS_POINTS:FIXED entries,
+// e.g. "NGN:150:10000" for 1.5% plus ₦100. An empty value is an empty schedule.
+func ParseSchedule(value string) (Schedule, error) {
+	schedule := Schedule{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid fee entry %q: want CURRENCY:BASIS_POINTS:FIXED", entry)
+		}
+		currency := strings.ToUpper(strings.TrimSpace(parts[0]))
+		if len(currency) != 3 {
+			return nil, fmt.Errorf("invalid fee entry %q: currency must be a 3 letter code", entry)
+		}
+		if _, ok := schedule[currency]; ok {
+			return nil, fmt.Errorf("duplicate fee entry for %s", currency)
+		}
+		basisPoints, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil || basisPoints < 0 || basisPoints > basisPointsPerWhole {
+			return nil, fmt.Errorf("invalid fee entry %q: basis points must be between 0 and %d", entry, basisPointsPerWhole)
+		}
+		fixed, err := strconv.ParseInt(strings.TrimSpace(parts[2]), 10, 64)
+		if err != nil || fixed < 0 {
+			return nil, fmt.Errorf("invalid fee entry %q: fixed fee must be a non-negative integer", entry)
+		}
+
+		schedule[currency] = Rate{BasisPoints: basisPoints, Fixed: fixed}
+	}
+	return schedule, nil
+}
+
+// Fee returns the fee on amount in currency. The percentage is rounded to the nearest
+// smallest unit, halves rounding up, so 1.5% of 4,999 kobo is 75 kobo.
+func (s Schedule) Fee(currency string, amount int64) int64 {
+	rate, ok := s[strings.ToUpper(currency)]
+	if !ok || amount <= 0 {
+		return 0
+	}
+	return (amount*rate.BasisPoints+basisPointsPerWhole/2)/basisPointsPerWhole + rate.Fixed
+}
+
+// Split divides a contribution of amount under mode into the fee, what the contributor
+// is charged and what reaches the goal. The fee is always worked out on amount, so the
+// same contribution costs the same fee in either mode. Under FeeModeAbsorb net is zero or
+// less when the fee swallows the whole contribution.
+func (s Schedule) Split(mode models.FeeMode, currency string, amount int64) (fee, charge, net int64) {
+	fee = s.Fee(currency, amount)
+	if mode == models.FeeModeAddOn {
+		return fee, amount + fee, amount
+	}
+	return fee, amount, amount - fee
+}
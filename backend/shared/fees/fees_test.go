@@ -0,0 +1,105 @@
+package fees
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gofund/shared/models"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Schedule
+		wantErr bool
+	}{
+		{value: "", want: Schedule{}},
+		{value: "NGN:150:10000", want: Schedule{"NGN": {BasisPoints: 150, Fixed: 10000}}},
+		{value: " ngn:150:0 , GHS:200:50 ", want: Schedule{"NGN": {BasisPoints: 150}, "GHS": {BasisPoints: 200, Fixed: 50}}},
+		{value: "NGN:10000:0", want: Schedule{"NGN": {BasisPoints: 10000}}},
+		{value: "NGN:150", wantErr: true},
+		{value: "NAIRA:150:0", wantErr: true},
+		{value: "NGN:150:0,NGN:100:0", wantErr: true},
+		{value: "NGN:-1:0", wantErr: true},
+		{value: "NGN:10001:0", wantErr: true},
+		{value: "NGN:1.5:0", wantErr: true},
+		{value: "NGN:150:-100", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSchedule(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseSchedule(%q) = %v, want an error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSchedule(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestFeeRounding(t *testing.T) {
+	percent := Schedule{"NGN": {BasisPoints: 150}}
+	withFixed := Schedule{"NGN": {BasisPoints: 150, Fixed: 10000}}
+	tests := []struct {
+		name     string
+		schedule Schedule
+		currency string
+		amount   int64
+		want     int64
+	}{
+		{name: "rounds down below a half", schedule: percent, currency: "NGN", amount: 33, want: 0}, // 0.495
+		{name: "rounds up above a half", schedule: percent, currency: "NGN", amount: 34, want: 1},   // 0.51
+		{name: "half rounds up", schedule: percent, currency: "NGN", amount: 100, want: 2},          // 1.5
+		{name: "odd half rounds up", schedule: percent, currency: "NGN", amount: 300, want: 5},      // 4.5
+		{name: "odd kobo", schedule: percent, currency: "NGN", amount: 4_999, want: 75},             // 74.985
+		{name: "one kobo", schedule: percent, currency: "NGN", amount: 1, want: 0},                  // 0.015
+		{name: "exact", schedule: percent, currency: "NGN", amount: 1_000_000, want: 15_000},        // 15,000
+		{name: "fixed is added after rounding", schedule: withFixed, currency: "NGN", amount: 4_999, want: 10_075},
+		{name: "currency is case insensitive", schedule: percent, currency: "ngn", amount: 4_999, want: 75},
+		{name: "currency without a rate", schedule: withFixed, currency: "GHS", amount: 4_999, want: 0},
+		{name: "nil schedule", currency: "NGN", amount: 4_999, want: 0},
+		{name: "zero amount has no fixed fee", schedule: withFixed, currency: "NGN", amount: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Fee(tt.currency, tt.amount); got != tt.want {
+				t.Errorf("Fee(%s, %d) = %d, want %d", tt.currency, tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	schedule := Schedule{"NGN": {BasisPoints: 150, Fixed: 100}}
+	tests := []struct {
+		name             string
+		mode             models.FeeMode
+		amount           int64
+		fee, charge, net int64
+	}{
+		// 1.5% of 4,999 is 74.985 kobo, so 75 plus the fixed 100
+		{name: "add-on odd kobo", mode: models.FeeModeAddOn, amount: 4_999, fee: 175, charge: 5_174, net: 4_999},
+		{name: "absorb odd kobo", mode: models.FeeModeAbsorb, amount: 4_999, fee: 175, charge: 4_999, net: 4_824},
+		// 1.5% of 333 is 4.995 kobo
+		{name: "add-on just under a half", mode: models.FeeModeAddOn, amount: 333, fee: 105, charge: 438, net: 333},
+		{name: "absorb just under a half", mode: models.FeeModeAbsorb, amount: 333, fee: 105, charge: 333, net: 228},
+		// 1.5% of 100 is exactly 1.5 kobo
+		{name: "add-on half", mode: models.FeeModeAddOn, amount: 100, fee: 102, charge: 202, net: 100},
+		{name: "absorb half", mode: models.FeeModeAbsorb, amount: 100, fee: 102, charge: 100, net: -2},
+		{name: "absorb fee swallows the contribution", mode: models.FeeModeAbsorb, amount: 50, fee: 101, charge: 50, net: -51},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee, charge, net := schedule.Split(tt.mode, "NGN", tt.amount)
+			if fee != tt.fee || charge != tt.charge || net != tt.net {
+				t.Errorf("Split(%s, %d) = %d, %d, %d; want %d, %d, %d", tt.mode, tt.amount, fee, charge, net, tt.fee, tt.charge, tt.net)
+			}
+			// No kobo is lost or made up: the contributor pays what the goal and the platform receive
+			if charge != net+fee {
+				t.Errorf("charge %d != net %d + fee %d", charge, net, fee)
+			}
+		})
+	}
+}
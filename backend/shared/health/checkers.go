@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofund/shared/messaging"
+	"github.com/jmoiron/sqlx"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+)
+
+type checker struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) error
+}
+
+func (c checker) Name() string                    { return c.name }
+func (c checker) Critical() bool                  { return c.critical }
+func (c checker) Check(ctx context.Context) error { return c.check(ctx) }
+
+// Func adapts a plain function into a Checker
+func Func(name string, critical bool, check func(ctx context.Context) error) Checker {
+	return checker{name: name, critical: critical, check: check}
+}
+
+// Gorm checks a Postgres connection behind GORM with SELECT 1
+func Gorm(name string, db *gorm.DB) Checker {
+	return Func(name, true, func(ctx context.Context) error {
+		return db.WithContext(ctx).Exec("SELECT 1").Error
+	})
+}
+
+// SQLX checks a Postgres connection behind sqlx with SELECT 1
+func SQLX(name string, db *sqlx.DB) Checker {
+	return Func(name, true, func(ctx context.Context) error {
+		_, err := db.ExecContext(ctx, "SELECT 1")
+		return err
+	})
+}
+
+// Mongo pings the primary of a MongoDB deployment
+func Mongo(name string, db *mongo.Database) Checker {
+	return Func(name, true, func(ctx context.Context) error {
+		return db.Client().Ping(ctx, nil)
+	})
+}
+
+// RabbitMQ checks that the broker connection is open and can serve channels.
+// A nil connection (the service started without a broker) always fails.
+func RabbitMQ(name string, conn *messaging.RabbitMQConnection) Checker {
+	return Func(name, true, func(ctx context.Context) error {
+		if conn == nil {
+			return messaging.ErrNotConnected
+		}
+		return conn.Ping()
+	})
+}
+
+// QueueDepth is a non-critical check that fails once a queue backs up past
+// max ready messages
+func QueueDepth(conn *messaging.RabbitMQConnection, queue string, max int) Checker {
+	return Func("queue:"+queue, false, func(ctx context.Context) error {
+		if conn == nil {
+			return messaging.ErrNotConnected
+		}
+		depth, err := conn.QueueDepth(queue)
+		if err != nil {
+			return err
+		}
+		if depth > max {
+			return fmt.Errorf("%d messages ready, threshold %d", depth, max)
+		}
+		return nil
+	})
+}
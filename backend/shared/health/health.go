@@ -0,0 +1,123 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds each readiness probe so one hung dependency cannot
+// stall the whole report
+const DefaultTimeout = 2 * time.Second
+
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusFailed      = "failed"
+)
+
+// Checker probes a single dependency. A failing critical checker marks the
+// service not ready; a failing non-critical one only degrades it.
+type Checker interface {
+	Name() string
+	Critical() bool
+	Check(ctx context.Context) error
+}
+
+// CheckResult is the outcome of one probe
+type CheckResult struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the readiness body served at /health/ready
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Ready reports whether every critical check passed
+func (r Report) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+// Run executes all checkers concurrently, each bounded by timeout
+func Run(ctx context.Context, timeout time.Duration, checkers ...Checker) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checkers))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, checker := range checkers {
+		wg.Add(1)
+		go func(c Checker) {
+			defer wg.Done()
+			result := run(ctx, timeout, c)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[c.Name()] = result
+			if result.Status == StatusOK {
+				return
+			}
+			if c.Critical() {
+				report.Status = StatusUnavailable
+			} else if report.Status == StatusOK {
+				report.Status = StatusDegraded
+			}
+		}(checker)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func run(ctx context.Context, timeout time.Duration, c Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Check(ctx)
+	result := CheckResult{
+		Status:    StatusOK,
+		Critical:  c.Critical(),
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// LiveHandler reports that the process is up without touching dependencies;
+// mount it at GET /health/live
+func LiveHandler(service string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK, "service": service})
+	}
+}
+
+// ReadyHandler probes every dependency and answers 503 when a critical one
+// fails; mount it at GET /health/ready
+func ReadyHandler(timeout time.Duration, checkers ...Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := Run(r.Context(), timeout, checkers...)
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,63 @@
+package httperr
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/requestid"
+)
+
+// CodeInternal is the code of errors that are not domain errors
+const CodeInternal = "internal_error"
+
+// Response is the JSON body of an error response. Code is stable and meant for clients to
+// branch on; Message is for people. Error repeats Message for clients written against the
+// earlier {"error": "..."} responses.
+type Response struct {
+	Error     string                 `json:"error"`
+	Code      string                 `json:"code"`
+	Message   string                 `json:"message"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+}
+
+// Build returns the status code and body for err. The status comes from the error's kind
+// (see shared/errors.HTTPStatus); errors that are not domain errors are internal errors.
+func Build(err error, requestID string, details map[string]interface{}) (int, Response) {
+	code := apperrors.Code(err)
+	if code == "" {
+		if kind := apperrors.KindOf(err); kind != apperrors.KindInternal {
+			code = string(kind)
+		} else {
+			code = CodeInternal
+		}
+	}
+
+	message := apperrors.Message(err)
+	return apperrors.HTTPStatus(err), Response{
+		Error:     message,
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: requestID,
+	}
+}
+
+// Respond writes err as a JSON error response, tagged with the request ID carried by the
+// request's context. Internal errors are logged with the request ID so the response can
+// be matched to the log line.
+func Respond(w http.ResponseWriter, r *http.Request, err error, details map[string]interface{}) {
+	requestID := requestid.FromContext(r.Context())
+	status, body := Build(err, requestID, details)
+	if status >= http.StatusInternalServerError {
+		log.Printf("[ERROR] %s %s failed (request_id: %s): %v", r.Method, r.URL.Path, requestID, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("[ERROR] Failed to write error response (request_id: %s): %v", requestID, err)
+	}
+}
@@ -0,0 +1,110 @@
+package identity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gofund/shared/metrics"
+)
+
+// Headers carrying the caller's identity from the gateway to downstream services.
+// users-service signs the identity on /internal/verify and Nginx forwards the
+// signature alongside X-User-ID, X-User-Roles and X-User-Email-Verified.
+const (
+	HeaderUserID    = "X-User-ID"
+	HeaderUserRoles = "X-User-Roles"
+	HeaderSignature = "X-Internal-Identity-Signature"
+
+	// HeaderEmailVerified is "true" when the caller has verified their email. It is
+	// signed with the user ID and roles.
+	HeaderEmailVerified = "X-User-Email-Verified"
+
+	userHeaderPrefix     = "X-User-"
+	internalHeaderPrefix = "X-Internal-"
+)
+
+// Outcome describes how the identity headers on a request were classified
+type Outcome string
+
+const (
+	// OutcomeAnonymous means the request carried no identity headers
+	OutcomeAnonymous Outcome = "anonymous"
+	// OutcomeTrusted means the identity headers carried a valid signature
+	OutcomeTrusted Outcome = "trusted"
+	// OutcomeUnsigned means identity headers were present without a signature
+	OutcomeUnsigned Outcome = "unsigned"
+	// OutcomeForged means identity headers were present with a signature that does not match
+	OutcomeForged Outcome = "forged"
+)
+
+// Sign returns the hex HMAC-SHA256 signature of a user ID, comma-separated roles and
+// email verification flag ("true", "false" or "" when unknown)
+func Sign(secret, userID, roles, emailVerified string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(userID + "\n" + roles + "\n" + emailVerified))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature matches the user ID, roles and email verification flag
+func Verify(secret, userID, roles, emailVerified, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, userID, roles, emailVerified)), []byte(signature))
+}
+
+// Guard classifies and sanitises the identity headers of an inbound request.
+//
+// X-Internal-* headers are always removed once the signature has been read, so
+// handlers never see them. X-User-* headers are removed unless the signature
+// matches, so an identity only reaches handlers when the gateway vouched for it.
+// Every anomaly is logged and counted as auth.identity_header.anomaly.
+func Guard(header http.Header, secret string) Outcome {
+	userID := header.Get(HeaderUserID)
+	roles := header.Get(HeaderUserRoles)
+	emailVerified := header.Get(HeaderEmailVerified)
+	signature := header.Get(HeaderSignature)
+
+	stripPrefix(header, internalHeaderPrefix)
+
+	if !hasPrefix(header, userHeaderPrefix) {
+		return OutcomeAnonymous
+	}
+
+	outcome := OutcomeUnsigned
+	if signature != "" {
+		outcome = OutcomeForged
+		if Verify(secret, userID, roles, emailVerified, signature) {
+			return OutcomeTrusted
+		}
+	}
+
+	log.Printf("Untrusted identity headers (%s) for user %q", outcome, userID)
+	metrics.IncrementCounter("auth.identity_header.anomaly", "outcome:"+string(outcome))
+
+	stripPrefix(header, userHeaderPrefix)
+	return outcome
+}
+
+// stripPrefix removes every header whose canonical name starts with prefix
+func stripPrefix(header http.Header, prefix string) {
+	for name := range header {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), prefix) {
+			delete(header, name)
+		}
+	}
+}
+
+// hasPrefix reports whether any header's canonical name starts with prefix
+func hasPrefix(header http.Header, prefix string) bool {
+	for name := range header {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), prefix) {
+			return true
+		}
+	}
+	return false
+}
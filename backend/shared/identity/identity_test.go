@@ -0,0 +1,199 @@
+package identity
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofund/shared/metrics"
+)
+
+const testSecret = "test-identity-secret"
+
+// anomalyRecorder counts the auth.identity_header.anomaly metrics by tag
+type anomalyRecorder struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (r *anomalyRecorder) Incr(name string, tags []string, rate float64) error {
+	if name != "auth.identity_header.anomaly" {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts[strings.Join(tags, ",")]++
+	return nil
+}
+
+func (r *anomalyRecorder) Histogram(string, float64, []string, float64) error { return nil }
+func (r *anomalyRecorder) Gauge(string, float64, []string, float64) error     { return nil }
+func (r *anomalyRecorder) Close() error                                       { return nil }
+
+// headers builds a header from name, value pairs, canonicalising the names as a server would
+func headers(pairs ...string) http.Header {
+	header := http.Header{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		header.Set(pairs[i], pairs[i+1])
+	}
+	return header
+}
+
+func recordAnomalies(t *testing.T) *anomalyRecorder {
+	t.Helper()
+	r := &anomalyRecorder{counts: make(map[string]int)}
+	previous := metrics.SetClient(r)
+	t.Cleanup(func() { metrics.SetClient(previous) })
+	return r
+}
+
+func TestSignVerify(t *testing.T) {
+	signature := Sign(testSecret, "user-1", "user,admin", "true")
+
+	tests := []struct {
+		name          string
+		secret        string
+		userID        string
+		roles         string
+		emailVerified string
+		signature     string
+		want          bool
+	}{
+		{"matching", testSecret, "user-1", "user,admin", "true", signature, true},
+		{"other user", testSecret, "user-2", "user,admin", "true", signature, false},
+		{"escalated roles", testSecret, "user-1", "user,admin,superadmin", "true", signature, false},
+		{"email verification changed", testSecret, "user-1", "user,admin", "false", signature, false},
+		{"email verification dropped", testSecret, "user-1", "user,admin", "", signature, false},
+		{"other secret", "another-secret", "user-1", "user,admin", "true", signature, false},
+		{"no secret", "", "user-1", "user,admin", "true", Sign("", "user-1", "user,admin", "true"), false},
+		{"no signature", testSecret, "user-1", "user,admin", "true", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.secret, tt.userID, tt.roles, tt.emailVerified, tt.signature); got != tt.want {
+				t.Errorf("Verify = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuard(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      http.Header
+		want        Outcome
+		keepsUserID bool
+	}{
+		{
+			name:   "anonymous",
+			header: http.Header{},
+			want:   OutcomeAnonymous,
+		},
+		{
+			name:        "signed by the gateway",
+			header:      headers(HeaderUserID, "user-1", HeaderUserRoles, "user", HeaderEmailVerified, "true", HeaderSignature, Sign(testSecret, "user-1", "user", "true")),
+			want:        OutcomeTrusted,
+			keepsUserID: true,
+		},
+		{
+			name:   "forged signature",
+			header: headers(HeaderUserID, "user-1", HeaderUserRoles, "user", HeaderSignature, "deadbeef"),
+			want:   OutcomeForged,
+		},
+		{
+			name:   "roles added after signing",
+			header: headers(HeaderUserID, "user-1", HeaderUserRoles, "user,admin", HeaderSignature, Sign(testSecret, "user-1", "user", "")),
+			want:   OutcomeForged,
+		},
+		{
+			name:   "email verification added after signing",
+			header: headers(HeaderUserID, "user-1", HeaderUserRoles, "user", HeaderEmailVerified, "true", HeaderSignature, Sign(testSecret, "user-1", "user", "false")),
+			want:   OutcomeForged,
+		},
+		{
+			name:   "unsigned",
+			header: headers(HeaderUserID, "user-1", HeaderUserRoles, "admin", HeaderEmailVerified, "true"),
+			want:   OutcomeUnsigned,
+		},
+		{
+			name:   "only an internal header",
+			header: headers("X-Internal-Service-Token", "anything"),
+			want:   OutcomeAnonymous,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := tt.header.Clone()
+			header.Set("X-Internal-Service-Token", "injected")
+			header.Set("Accept", "application/json")
+
+			if got := Guard(header, testSecret); got != tt.want {
+				t.Errorf("Guard = %s, want %s", got, tt.want)
+			}
+			if (header.Get(HeaderUserID) != "") != tt.keepsUserID {
+				t.Errorf("X-User-ID after Guard = %q, want kept: %v", header.Get(HeaderUserID), tt.keepsUserID)
+			}
+			if !tt.keepsUserID && (header.Get(HeaderUserRoles) != "" || header.Get(HeaderEmailVerified) != "") {
+				t.Errorf("X-User-* headers left after dropping the identity: %v", header)
+			}
+			for name := range header {
+				if strings.HasPrefix(name, internalHeaderPrefix) {
+					t.Errorf("internal header %s survived Guard", name)
+				}
+			}
+			if header.Get("Accept") == "" {
+				t.Error("Guard removed an unrelated header")
+			}
+		})
+	}
+}
+
+func TestGuardStripsEveryUserHeaderOfAForgedIdentity(t *testing.T) {
+	header := http.Header{}
+	header.Set(HeaderUserID, "user-1")
+	header.Set(HeaderUserRoles, "admin")
+	header.Set(HeaderEmailVerified, "true")
+	header.Set("x-user-anything", "lowercase names are canonicalised too")
+	header.Set(HeaderSignature, "forged")
+
+	Guard(header, testSecret)
+
+	if len(header) != 0 {
+		t.Errorf("headers left after a forged identity: %v", header)
+	}
+}
+
+func TestGuardCountsAnomalies(t *testing.T) {
+	recorder := recordAnomalies(t)
+
+	Guard(headers(HeaderUserID, "u", HeaderSignature, Sign(testSecret, "u", "", "")), testSecret)
+	Guard(http.Header{}, testSecret)
+	Guard(headers(HeaderUserID, "u"), testSecret)
+	Guard(headers(HeaderUserRoles, "admin"), testSecret)
+	Guard(headers(HeaderUserID, "u", HeaderSignature, "forged"), testSecret)
+
+	want := map[string]int{"outcome:unsigned": 2, "outcome:forged": 1}
+	for tag, n := range want {
+		if recorder.counts[tag] != n {
+			t.Errorf("%s anomalies = %d, want %d", tag, recorder.counts[tag], n)
+		}
+	}
+	if len(recorder.counts) != len(want) {
+		t.Errorf("anomalies = %v, want %v", recorder.counts, want)
+	}
+}
+
+func TestGuardWithoutSecret(t *testing.T) {
+	// Nothing can be verified, so even a gateway signature is treated as forged
+	header := headers(HeaderUserID, "u", HeaderSignature, Sign("", "u", "", ""))
+	if got := Guard(header, ""); got != OutcomeForged || header.Get(HeaderUserID) != "" {
+		t.Errorf("Guard = %s, X-User-ID %q; want %s and the identity dropped", got, header.Get(HeaderUserID), OutcomeForged)
+	}
+
+	header = headers(HeaderUserID, "u")
+	if got := Guard(header, ""); got != OutcomeUnsigned || header.Get(HeaderUserID) != "" {
+		t.Errorf("Guard = %s, X-User-ID %q; want %s and the identity dropped", got, header.Get(HeaderUserID), OutcomeUnsigned)
+	}
+}
@@ -1,6 +1,11 @@
 package logger
 
-import "log"
+import (
+	"context"
+	"log"
+
+	"github.com/gofund/shared/requestid"
+)
 
 // Logger provides structured logging capabilities (Datadog-ready)
 type Logger interface {
@@ -28,3 +33,52 @@ func (l *DefaultLogger) Warn(msg string, fields map[string]interface{}) {
 func (l *DefaultLogger) Debug(msg string, fields map[string]interface{}) {
 	log.Printf("[DEBUG] %s %+v", msg, fields)
 }
+
+// contextLogger adds a request ID to the fields of every line
+type contextLogger struct {
+	DefaultLogger
+	requestID string
+}
+
+// WithContext returns a logger that adds the request ID carried by ctx (see
+// shared/requestid) to every line as the request_id field
+func WithContext(ctx context.Context) Logger {
+	return &contextLogger{requestID: requestid.FromContext(ctx)}
+}
+
+func (l *contextLogger) Info(msg string, fields map[string]interface{}) {
+	l.DefaultLogger.Info(msg, l.withRequestID(fields))
+}
+
+func (l *contextLogger) Error(msg string, err error, fields map[string]interface{}) {
+	l.DefaultLogger.Error(msg, err, l.withRequestID(fields))
+}
+
+func (l *contextLogger) Warn(msg string, fields map[string]interface{}) {
+	l.DefaultLogger.Warn(msg, l.withRequestID(fields))
+}
+
+func (l *contextLogger) Debug(msg string, fields map[string]interface{}) {
+	l.DefaultLogger.Debug(msg, l.withRequestID(fields))
+}
+
+func (l *contextLogger) withRequestID(fields map[string]interface{}) map[string]interface{} {
+	if l.requestID == "" {
+		return fields
+	}
+	withID := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		withID[k] = v
+	}
+	withID["request_id"] = l.requestID
+	return withID
+}
+
+// Printf logs like log.Printf, ending the line with the request ID carried by ctx
+func Printf(ctx context.Context, format string, args ...interface{}) {
+	if requestID := requestid.FromContext(ctx); requestID != "" {
+		format += " (request_id: %s)"
+		args = append(args, requestID)
+	}
+	log.Printf(format, args...)
+}
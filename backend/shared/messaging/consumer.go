@@ -1,6 +1,11 @@
 package messaging
 
+import "context"
+
 // Consumer handles consuming events from RabbitMQ
 type Consumer interface {
 	Consume(eventType string, handler func([]byte) error) error
+	// ConsumeContext consumes like Consume and passes handlers a context carrying the
+	// request ID of the publishing request, or a fresh one if the message had none
+	ConsumeContext(eventType string, handler func(ctx context.Context, data []byte) error) error
 }
@@ -1,6 +1,11 @@
 package messaging
 
+import "context"
+
 // Publisher handles publishing events to RabbitMQ
 type Publisher interface {
 	Publish(eventType string, event interface{}) error
+	// PublishContext publishes like Publish and attaches the request ID carried by ctx
+	// (see shared/requestid), so consumers can log against the originating request
+	PublishContext(ctx context.Context, eventType string, event interface{}) error
 }
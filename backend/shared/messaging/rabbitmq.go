@@ -1,61 +1,258 @@
 package messaging
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/gofund/shared/metrics"
+	"github.com/gofund/shared/requestid"
 	"github.com/streadway/amqp"
 )
 
-// RabbitMQConnection represents a RabbitMQ connection
+const (
+	initialReconnectDelay = 1 * time.Second
+	maxReconnectDelay     = 30 * time.Second
+)
+
+// ErrNotConnected is returned when the broker connection is down and a reconnect is in progress
+var ErrNotConnected = errors.New("rabbitmq: not connected")
+
+// topologyHook re-applies declarations and consumers on a freshly opened channel
+type topologyHook func(ch *amqp.Channel) error
+
+// RabbitMQConnection represents a RabbitMQ connection that reconnects automatically
 type RabbitMQConnection struct {
-	conn    *amqp.Connection
-	channel *amqp.Channel
+	url string
+
+	mu        sync.RWMutex
+	conn      *amqp.Connection
+	channel   *amqp.Channel
+	connected bool
+	closed    bool
+	hooks     []topologyHook
 }
 
 // NewRabbitMQConnection creates a new RabbitMQ connection
 func NewRabbitMQConnection(url string) (*RabbitMQConnection, error) {
-	conn, err := amqp.Dial(url)
+	r := &RabbitMQConnection{url: url}
+	if err := r.connect(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// connect dials the broker, opens a channel and starts watching for close notifications
+func (r *RabbitMQConnection) connect() error {
+	conn, err := amqp.Dial(r.url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
 	}
 
 	ch, err := conn.Channel()
 	if err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("failed to open channel: %w", err)
+		return fmt.Errorf("failed to open channel: %w", err)
 	}
 
-	return &RabbitMQConnection{
-		conn:    conn,
-		channel: ch,
-	}, nil
+	r.mu.Lock()
+	r.conn = conn
+	r.channel = ch
+	r.connected = true
+	r.mu.Unlock()
+
+	go r.watch(conn, ch)
+	return nil
 }
 
-// Close closes the RabbitMQ connection
-func (r *RabbitMQConnection) Close() error {
-	if r.channel != nil {
-		r.channel.Close()
+// watch blocks until the connection or channel closes and then triggers a reconnect
+func (r *RabbitMQConnection) watch(conn *amqp.Connection, ch *amqp.Channel) {
+	connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	chClosed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	var reason *amqp.Error
+	select {
+	case reason = <-connClosed:
+	case reason = <-chClosed:
+	}
+
+	r.mu.Lock()
+	r.connected = false
+	closed := r.closed
+	r.mu.Unlock()
+
+	if closed {
+		return
+	}
+
+	log.Printf("RabbitMQ connection lost: %v, reconnecting", reason)
+	metrics.IncrementCounter("rabbitmq.connection.lost")
+
+	// The channel may have died on its own; drop the connection so we start clean
+	conn.Close()
+	r.reconnect()
+}
+
+// reconnect re-dials with exponential backoff and re-applies the registered topology
+func (r *RabbitMQConnection) reconnect() {
+	delay := initialReconnectDelay
+
+	for attempt := 1; ; attempt++ {
+		time.Sleep(delay)
+
+		if r.isClosed() {
+			return
+		}
+
+		if err := r.connect(); err != nil {
+			log.Printf("RabbitMQ reconnect attempt %d failed: %v", attempt, err)
+			metrics.IncrementCounter("rabbitmq.reconnect.failed")
+			delay *= 2
+			if delay > maxReconnectDelay {
+				delay = maxReconnectDelay
+			}
+			continue
+		}
+
+		if err := r.applyHooks(); err != nil {
+			// Closing the connection hands control back to the new watcher, which retries
+			log.Printf("Failed to restore RabbitMQ topology after reconnect: %v", err)
+			r.mu.RLock()
+			conn := r.conn
+			r.mu.RUnlock()
+			conn.Close()
+			return
+		}
+
+		log.Printf("RabbitMQ reconnected after %d attempt(s)", attempt)
+		metrics.IncrementCounter("rabbitmq.reconnect.success")
+		return
+	}
+}
+
+// applyHooks runs every registered topology hook against the current channel
+func (r *RabbitMQConnection) applyHooks() error {
+	r.mu.RLock()
+	ch := r.channel
+	hooks := make([]topologyHook, len(r.hooks))
+	copy(hooks, r.hooks)
+	r.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(ch); err != nil {
+			return err
+		}
 	}
-	if r.conn != nil {
-		return r.conn.Close()
+	return nil
+}
+
+// register applies hook to the current channel and remembers it for future reconnects
+func (r *RabbitMQConnection) register(hook topologyHook) error {
+	ch, err := r.currentChannel()
+	if err != nil {
+		return err
 	}
+	if err := hook(ch); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.hooks = append(r.hooks, hook)
+	r.mu.Unlock()
 	return nil
 }
 
-// RabbitMQPublisher implements the Publisher interface
-type RabbitMQPublisher struct {
-	channel      *amqp.Channel
-	exchangeName string
+// currentChannel returns the live channel or ErrNotConnected while reconnecting
+func (r *RabbitMQConnection) currentChannel() (*amqp.Channel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.connected || r.channel == nil {
+		return nil, ErrNotConnected
+	}
+	return r.channel, nil
 }
 
-// NewRabbitMQPublisher creates a new RabbitMQ publisher
-func NewRabbitMQPublisher(conn *RabbitMQConnection, exchangeName string) (*RabbitMQPublisher, error) {
-	// Declare exchange
-	err := conn.channel.ExchangeDeclare(
+func (r *RabbitMQConnection) isClosed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.closed
+}
+
+// IsConnected reports whether the broker connection is currently up
+func (r *RabbitMQConnection) IsConnected() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.connected
+}
+
+// Ping verifies the broker connection is up and can still open channels. A
+// throwaway channel is used so a failed probe cannot close the shared one.
+func (r *RabbitMQConnection) Ping() error {
+	ch, err := r.probeChannel()
+	if err != nil {
+		return err
+	}
+	return ch.Close()
+}
+
+// QueueDepth returns the number of ready messages in a queue
+func (r *RabbitMQConnection) QueueDepth(queue string) (int, error) {
+	ch, err := r.probeChannel()
+	if err != nil {
+		return 0, err
+	}
+	defer ch.Close()
+
+	q, err := ch.QueueInspect(queue)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect queue %s: %w", queue, err)
+	}
+	return q.Messages, nil
+}
+
+// probeChannel opens a short-lived channel on the current connection
+func (r *RabbitMQConnection) probeChannel() (*amqp.Channel, error) {
+	r.mu.RLock()
+	conn := r.conn
+	connected := r.connected && r.channel != nil
+	r.mu.RUnlock()
+
+	if !connected || conn == nil {
+		return nil, ErrNotConnected
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	return ch, nil
+}
+
+// Close closes the RabbitMQ connection and stops reconnecting
+func (r *RabbitMQConnection) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	r.connected = false
+	conn := r.conn
+	ch := r.channel
+	r.mu.Unlock()
+
+	if ch != nil {
+		ch.Close()
+	}
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+// declareExchange declares the topic exchange used for all events
+func declareExchange(ch *amqp.Channel, exchangeName string) error {
+	err := ch.ExchangeDeclare(
 		exchangeName, // name
 		"topic",      // type
 		true,         // durable
@@ -65,100 +262,159 @@ func NewRabbitMQPublisher(conn *RabbitMQConnection, exchangeName string) (*Rabbi
 		nil,          // arguments
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to declare exchange: %w", err)
+		return fmt.Errorf("failed to declare exchange: %w", err)
+	}
+	return nil
+}
+
+// RabbitMQPublisher implements the Publisher interface
+type RabbitMQPublisher struct {
+	conn         *RabbitMQConnection
+	exchangeName string
+}
+
+// NewRabbitMQPublisher creates a new RabbitMQ publisher
+func NewRabbitMQPublisher(conn *RabbitMQConnection, exchangeName string) (*RabbitMQPublisher, error) {
+	// Declare exchange (and again after every reconnect)
+	err := conn.register(func(ch *amqp.Channel) error {
+		return declareExchange(ch, exchangeName)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &RabbitMQPublisher{
-		channel:      conn.channel,
+		conn:         conn,
 		exchangeName: exchangeName,
 	}, nil
 }
 
-// Publish publishes an event to RabbitMQ with Datadog metrics
+// Publish publishes an event to RabbitMQ with Datadog metrics.
+// While the connection is being re-established it returns an error wrapping ErrNotConnected.
 func (p *RabbitMQPublisher) Publish(eventType string, event interface{}) error {
+	return p.PublishContext(context.Background(), eventType, event)
+}
+
+// PublishContext publishes an event to RabbitMQ with the request ID carried by ctx in the
+// X-Request-ID message header
+func (p *RabbitMQPublisher) PublishContext(ctx context.Context, eventType string, event interface{}) error {
 	start := time.Now()
-	
+
 	body, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
+	ch, err := p.conn.currentChannel()
+	if err != nil {
+		metrics.TrackEventPublished(eventType, false, time.Since(start))
+		return fmt.Errorf("failed to publish event %s: %w", eventType, err)
+	}
+
 	routingKey := fmt.Sprintf("events.%s", eventType)
 
-	err = p.channel.Publish(
+	var headers amqp.Table
+	requestID := requestid.FromContext(ctx)
+	if requestID != "" {
+		headers = amqp.Table{requestid.Header: requestID}
+	}
+
+	err = ch.Publish(
 		p.exchangeName, // exchange
 		routingKey,     // routing key
 		false,          // mandatory
 		false,          // immediate
 		amqp.Publishing{
 			ContentType: "application/json",
+			Headers:     headers,
 			Body:        body,
 			Timestamp:   time.Now(),
 		},
 	)
-	
+
 	duration := time.Since(start)
-	
+
 	// Track event publishing metrics
 	if err != nil {
 		metrics.TrackEventPublished(eventType, false, duration)
+		if errors.Is(err, amqp.ErrClosed) {
+			return fmt.Errorf("failed to publish event %s: %w", eventType, ErrNotConnected)
+		}
 		return fmt.Errorf("failed to publish event: %w", err)
 	}
-	
+
 	metrics.TrackEventPublished(eventType, true, duration)
-	log.Printf("Published event: %s (duration: %v)", eventType, duration)
+	log.Printf("Published event: %s (duration: %v, request_id: %s)", eventType, duration, requestID)
 	return nil
 }
 
 // RabbitMQConsumer implements the Consumer interface
 type RabbitMQConsumer struct {
-	channel      *amqp.Channel
+	conn         *RabbitMQConnection
 	exchangeName string
 	queueName    string
 }
 
 // NewRabbitMQConsumer creates a new RabbitMQ consumer
 func NewRabbitMQConsumer(conn *RabbitMQConnection, exchangeName, queueName string) (*RabbitMQConsumer, error) {
-	// Declare exchange
-	err := conn.channel.ExchangeDeclare(
-		exchangeName, // name
-		"topic",      // type
-		true,         // durable
-		false,        // auto-deleted
-		false,        // internal
-		false,        // no-wait
-		nil,          // arguments
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to declare exchange: %w", err)
-	}
+	// Declare exchange and queue (and again after every reconnect)
+	err := conn.register(func(ch *amqp.Channel) error {
+		if err := declareExchange(ch, exchangeName); err != nil {
+			return err
+		}
 
-	// Declare queue
-	_, err = conn.channel.QueueDeclare(
-		queueName, // name
-		true,      // durable
-		false,     // delete when unused
-		false,     // exclusive
-		false,     // no-wait
-		nil,       // arguments
-	)
+		_, err := ch.QueueDeclare(
+			queueName, // name
+			true,      // durable
+			false,     // delete when unused
+			false,     // exclusive
+			false,     // no-wait
+			nil,       // arguments
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare queue: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to declare queue: %w", err)
+		return nil, err
 	}
 
 	return &RabbitMQConsumer{
-		channel:      conn.channel,
+		conn:         conn,
 		exchangeName: exchangeName,
 		queueName:    queueName,
 	}, nil
 }
 
-// Consume consumes events from RabbitMQ with Datadog metrics
+// Consume consumes events from RabbitMQ with Datadog metrics.
+// The binding and consumer are re-established automatically after a reconnect.
 func (c *RabbitMQConsumer) Consume(eventType string, handler func([]byte) error) error {
+	return c.ConsumeContext(eventType, func(_ context.Context, data []byte) error {
+		return handler(data)
+	})
+}
+
+// ConsumeContext consumes events like Consume, passing handlers a context that carries the
+// request ID from the message's X-Request-ID header
+func (c *RabbitMQConsumer) ConsumeContext(eventType string, handler func(ctx context.Context, data []byte) error) error {
+	err := c.conn.register(func(ch *amqp.Channel) error {
+		return c.startConsuming(ch, eventType, handler)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Started consuming events: %s", eventType)
+	return nil
+}
+
+// startConsuming binds the queue for eventType on ch and dispatches deliveries to handler
+func (c *RabbitMQConsumer) startConsuming(ch *amqp.Channel, eventType string, handler func(ctx context.Context, data []byte) error) error {
 	routingKey := fmt.Sprintf("events.%s", eventType)
 
 	// Bind queue to exchange
-	err := c.channel.QueueBind(
+	err := ch.QueueBind(
 		c.queueName,    // queue name
 		routingKey,     // routing key
 		c.exchangeName, // exchange
@@ -169,7 +425,7 @@ func (c *RabbitMQConsumer) Consume(eventType string, handler func([]byte) error)
 		return fmt.Errorf("failed to bind queue: %w", err)
 	}
 
-	msgs, err := c.channel.Consume(
+	msgs, err := ch.Consume(
 		c.queueName, // queue
 		"",          // consumer
 		true,        // auto-ack
@@ -182,23 +438,31 @@ func (c *RabbitMQConsumer) Consume(eventType string, handler func([]byte) error)
 		return fmt.Errorf("failed to register consumer: %w", err)
 	}
 
+	// The delivery channel is closed when the AMQP channel dies; the reconnect
+	// hook starts a fresh goroutine on the new channel.
 	go func() {
 		for msg := range msgs {
 			start := time.Now()
-			
+
 			// Calculate event age (time since published)
 			var eventAge time.Duration
 			if !msg.Timestamp.IsZero() {
 				eventAge = time.Since(msg.Timestamp)
 			}
-			
+
+			// Messages from publishers without a request ID still get one, so the logs of
+			// everything the handler triggers can be correlated
+			headerID, _ := msg.Headers[requestid.Header].(string)
+			requestID := requestid.FromHeader(headerID)
+			ctx := requestid.WithContext(context.Background(), requestID)
+
 			// Handle the message
-			err := handler(msg.Body)
+			err := handler(ctx, msg.Body)
 			processingDuration := time.Since(start)
-			
+
 			// Track event consumption metrics
 			if err != nil {
-				log.Printf("Error handling message: %v", err)
+				log.Printf("Error handling %s message (request_id: %s): %v", eventType, requestID, err)
 				metrics.TrackEventConsumed(eventType, false, processingDuration, eventAge)
 			} else {
 				metrics.TrackEventConsumed(eventType, true, processingDuration, eventAge)
@@ -206,6 +470,5 @@ func (c *RabbitMQConsumer) Consume(eventType string, handler func([]byte) error)
 		}
 	}()
 
-	log.Printf("Started consuming events: %s", eventType)
 	return nil
-}
\ No newline at end of file
+}
@@ -2,19 +2,79 @@ package metrics
 
 import (
 	"fmt"
+	"log"
+	"net"
+	"os"
 	"time"
 
 	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/gofund/shared/buildinfo"
 	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 )
 
-var (
-	// Client is the global DogStatsD client for custom metrics
-	Client *statsd.Client
+// Environment variables controlling where custom metrics are sent. The tracer reads
+// DD_AGENT_HOST on its own, so the same variable points both at the agent.
+const (
+	EnvAgentHost       = "DD_AGENT_HOST"
+	EnvDogStatsDPort   = "DD_DOGSTATSD_PORT"
+	EnvMetricsDisabled = "DD_METRICS_DISABLED"
+
+	defaultAgentHost     = "datadog-agent"
+	defaultDogStatsDPort = "8125"
 )
 
-// InitDatadog initializes Datadog tracing and metrics
+// Client is the subset of the DogStatsD client the helpers call through.
+// *statsd.Client satisfies it; tests can install a recording fake with SetClient.
+type Client interface {
+	Incr(name string, tags []string, rate float64) error
+	Histogram(name string, value float64, tags []string, rate float64) error
+	Gauge(name string, value float64, tags []string, rate float64) error
+	Close() error
+}
+
+// noopClient drops every metric; it is installed until InitDatadog succeeds and
+// whenever metrics are disabled
+type noopClient struct{}
+
+func (noopClient) Incr(string, []string, float64) error               { return nil }
+func (noopClient) Histogram(string, float64, []string, float64) error { return nil }
+func (noopClient) Gauge(string, float64, []string, float64) error     { return nil }
+func (noopClient) Close() error                                       { return nil }
+
+var client Client = noopClient{}
+
+// SetClient replaces the client the helpers send to and returns the previous one.
+// A nil client installs the no-op client.
+func SetClient(c Client) Client {
+	previous := client
+	if c == nil {
+		c = noopClient{}
+	}
+	client = c
+	return previous
+}
+
+// DisableMetrics installs the no-op client so every helper becomes a no-op
+func DisableMetrics() {
+	SetClient(nil).Close()
+}
+
+// MetricsDisabled reports whether DD_METRICS_DISABLED opts the service out of custom metrics
+func MetricsDisabled() bool {
+	return os.Getenv(EnvMetricsDisabled) == "true"
+}
+
+// StatsDAddress returns the DogStatsD address from DD_AGENT_HOST and DD_DOGSTATSD_PORT
+func StatsDAddress() string {
+	return net.JoinHostPort(getEnv(EnvAgentHost, defaultAgentHost), getEnv(EnvDogStatsDPort, defaultDogStatsDPort))
+}
+
+// InitDatadog initializes Datadog tracing and metrics. A version stamped into the
+// binary via buildinfo takes precedence over the one passed in. When metrics are
+// disabled, or the agent cannot be reached, the helpers fall back to a no-op client.
 func InitDatadog(serviceName, env, version string) error {
+	version = buildinfo.ResolveVersion(version)
+
 	// Start the tracer with configuration
 	tracer.Start(
 		tracer.WithServiceName(serviceName),
@@ -23,9 +83,15 @@ func InitDatadog(serviceName, env, version string) error {
 		tracer.WithRuntimeMetrics(),
 	)
 
+	if MetricsDisabled() {
+		DisableMetrics()
+		log.Printf("Custom metrics disabled via %s", EnvMetricsDisabled)
+		return nil
+	}
+
 	// Initialize DogStatsD client for custom metrics
-	var err error
-	Client, err = statsd.New("datadog-agent:8125",
+	address := StatsDAddress()
+	c, err := statsd.New(address,
 		statsd.WithNamespace("gofund."),
 		statsd.WithTags([]string{
 			fmt.Sprintf("service:%s", serviceName),
@@ -34,8 +100,10 @@ func InitDatadog(serviceName, env, version string) error {
 		}),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create statsd client: %w", err)
+		DisableMetrics()
+		return fmt.Errorf("failed to create statsd client for %s: %w", address, err)
 	}
+	SetClient(c).Close()
 
 	return nil
 }
@@ -43,32 +111,24 @@ func InitDatadog(serviceName, env, version string) error {
 // StopDatadog gracefully stops Datadog tracing and metrics
 func StopDatadog() {
 	tracer.Stop()
-	if Client != nil {
-		Client.Close()
-	}
+	SetClient(nil).Close()
 }
 
 // Business Metrics Helper Functions
 
 // IncrementCounter increments a counter metric with optional tags
 func IncrementCounter(metric string, tags ...string) {
-	if Client != nil {
-		Client.Incr(metric, tags, 1)
-	}
+	client.Incr(metric, tags, 1)
 }
 
 // RecordHistogram records a histogram value with optional tags
 func RecordHistogram(metric string, value float64, tags ...string) {
-	if Client != nil {
-		Client.Histogram(metric, value, tags, 1)
-	}
+	client.Histogram(metric, value, tags, 1)
 }
 
 // RecordGauge records a gauge value with optional tags
 func RecordGauge(metric string, value float64, tags ...string) {
-	if Client != nil {
-		Client.Gauge(metric, value, tags, 1)
-	}
+	client.Gauge(metric, value, tags, 1)
 }
 
 // RecordDuration records the duration of an operation
@@ -149,6 +209,17 @@ func TrackProofVerified(goalID string, verificationCount int) {
 	RecordGauge("goal.proof.verification_count", float64(verificationCount), fmt.Sprintf("goal_id:%s", goalID))
 }
 
+// TrackPlatformStats records the platform-wide totals: amount raised per currency in
+// major units, goals funded, open goals and distinct contributors
+func TrackPlatformStats(raised map[string]float64, goalsFunded, activeGoals, contributors int64) {
+	for currency, amount := range raised {
+		RecordGauge("platform.raised", amount, fmt.Sprintf("currency:%s", currency))
+	}
+	RecordGauge("platform.goals_funded", float64(goalsFunded))
+	RecordGauge("platform.goals_active", float64(activeGoals))
+	RecordGauge("platform.contributors", float64(contributors))
+}
+
 // Ledger Metrics
 
 // TrackLedgerEntryCreated tracks new ledger entries
@@ -231,6 +302,16 @@ func TrackEventConsumed(eventType string, success bool, processingDuration time.
 	RecordHistogram("event.processing.age", eventAge.Seconds(), fmt.Sprintf("event_type:%s", eventType))
 }
 
+// Database Metrics
+
+// TrackSlowQuery tracks a database query that ran over the slow query threshold. digest
+// identifies the query's shape, so every run of the same query shares it.
+func TrackSlowQuery(digest, table, operation string, duration time.Duration) {
+	tags := []string{fmt.Sprintf("digest:%s", digest), fmt.Sprintf("table:%s", table), fmt.Sprintf("operation:%s", operation)}
+	IncrementCounter("db.slow_query.count", tags...)
+	RecordHistogram("db.slow_query.duration", duration.Seconds(), tags...)
+}
+
 // Cache Metrics
 
 // TrackCacheHit tracks cache hits
@@ -274,3 +355,17 @@ func TrackNotificationRead(notificationType string) {
 func TrackNotificationDeleted(notificationType string) {
 	IncrementCounter("notification.deleted.count", fmt.Sprintf("type:%s", notificationType))
 }
+
+// Rate Limit Metrics
+
+// TrackRateLimited tracks a request refused by a rate limiter
+func TrackRateLimited(limiter string) {
+	IncrementCounter("http.rate_limited.count", fmt.Sprintf("limiter:%s", limiter))
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
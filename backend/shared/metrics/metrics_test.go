@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// metric is one call the recording client received
+type metric struct {
+	kind  string
+	name  string
+	value float64
+	tags  []string
+}
+
+type recordingClient struct {
+	metrics []metric
+	closed  bool
+}
+
+func (c *recordingClient) Incr(name string, tags []string, rate float64) error {
+	c.metrics = append(c.metrics, metric{kind: "count", name: name, value: 1, tags: tags})
+	return nil
+}
+
+func (c *recordingClient) Histogram(name string, value float64, tags []string, rate float64) error {
+	c.metrics = append(c.metrics, metric{kind: "histogram", name: name, value: value, tags: tags})
+	return nil
+}
+
+func (c *recordingClient) Gauge(name string, value float64, tags []string, rate float64) error {
+	c.metrics = append(c.metrics, metric{kind: "gauge", name: name, value: value, tags: tags})
+	return nil
+}
+
+func (c *recordingClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+// record installs a recording client for the rest of the test
+func record(t *testing.T) *recordingClient {
+	t.Helper()
+	c := &recordingClient{}
+	previous := SetClient(c)
+	t.Cleanup(func() { SetClient(previous) })
+	return c
+}
+
+func TestHelpersSendThroughTheClient(t *testing.T) {
+	tests := []struct {
+		name string
+		call func()
+		want []metric
+	}{
+		{
+			name: "counter",
+			call: func() { IncrementCounter("custom.count", "a:1", "b:2") },
+			want: []metric{{kind: "count", name: "custom.count", value: 1, tags: []string{"a:1", "b:2"}}},
+		},
+		{
+			name: "gauge without tags",
+			call: func() { RecordGauge("custom.gauge", 3) },
+			want: []metric{{kind: "gauge", name: "custom.gauge", value: 3}},
+		},
+		{
+			name: "payment success",
+			call: func() { TrackPaymentSuccess(5_000, "NGN", 2*time.Second) },
+			want: []metric{
+				{kind: "count", name: "payment.success.count", value: 1, tags: []string{"currency:NGN"}},
+				{kind: "histogram", name: "payment.amount", value: 5_000, tags: []string{"currency:NGN", "status:success"}},
+				{kind: "histogram", name: "payment.processing.duration", value: 2, tags: []string{"currency:NGN", "status:success"}},
+			},
+		},
+		{
+			name: "failed webhook",
+			call: func() { TrackWebhookProcessed("charge.success", false) },
+			want: []metric{{kind: "count", name: "payment.webhook.processed.count", value: 1, tags: []string{"event_type:charge.success", "status:failure"}}},
+		},
+		{
+			name: "slow query",
+			call: func() { TrackSlowQuery("abc123", "goals", "select", 1500*time.Millisecond) },
+			want: []metric{
+				{kind: "count", name: "db.slow_query.count", value: 1, tags: []string{"digest:abc123", "table:goals", "operation:select"}},
+				{kind: "histogram", name: "db.slow_query.duration", value: 1.5, tags: []string{"digest:abc123", "table:goals", "operation:select"}},
+			},
+		},
+		{
+			name: "platform stats",
+			call: func() { TrackPlatformStats(map[string]float64{"NGN": 1_250.5}, 4, 10, 37) },
+			want: []metric{
+				{kind: "gauge", name: "platform.raised", value: 1_250.5, tags: []string{"currency:NGN"}},
+				{kind: "gauge", name: "platform.goals_funded", value: 4},
+				{kind: "gauge", name: "platform.goals_active", value: 10},
+				{kind: "gauge", name: "platform.contributors", value: 37},
+			},
+		},
+		{
+			name: "rate limited",
+			call: func() { TrackRateLimited("login") },
+			want: []metric{{kind: "count", name: "http.rate_limited.count", value: 1, tags: []string{"limiter:login"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := record(t)
+			tt.call()
+			if !reflect.DeepEqual(c.metrics, tt.want) {
+				t.Errorf("sent %+v, want %+v", c.metrics, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordDurationMeasuresSinceStart(t *testing.T) {
+	c := record(t)
+	RecordDuration("op.duration", time.Now().Add(-2*time.Second), "op:test")
+
+	if len(c.metrics) != 1 {
+		t.Fatalf("sent %d metrics, want 1", len(c.metrics))
+	}
+	if got := c.metrics[0]; got.kind != "histogram" || got.value < 2 || got.value > 3 {
+		t.Errorf("sent %+v, want a histogram of about 2 seconds", got)
+	}
+}
+
+func TestDisableMetricsInstallsNoop(t *testing.T) {
+	c := record(t)
+	DisableMetrics()
+
+	if !c.closed {
+		t.Error("the replaced client was not closed")
+	}
+	// Every helper must be safe to call with metrics disabled
+	IncrementCounter("custom.count")
+	TrackEventConsumed("GoalCreated", true, time.Second, time.Minute)
+	if len(c.metrics) != 0 {
+		t.Errorf("the disabled client still received %+v", c.metrics)
+	}
+	if _, ok := SetClient(c).(noopClient); !ok {
+		t.Error("DisableMetrics did not install the no-op client")
+	}
+}
+
+func TestSetClientNilInstallsNoop(t *testing.T) {
+	c := record(t)
+	if previous := SetClient(nil); previous != c {
+		t.Errorf("SetClient returned %v, want the recording client", previous)
+	}
+	if _, ok := SetClient(c).(noopClient); !ok {
+		t.Error("SetClient(nil) did not install the no-op client")
+	}
+}
+
+func TestStatsDAddress(t *testing.T) {
+	tests := []struct {
+		host, port string
+		want       string
+	}{
+		{want: "datadog-agent:8125"},
+		{host: "localhost", want: "localhost:8125"},
+		{host: "10.0.0.5", port: "9125", want: "10.0.0.5:9125"},
+		{host: "::1", port: "8125", want: "[::1]:8125"},
+	}
+	for _, tt := range tests {
+		t.Setenv(EnvAgentHost, tt.host)
+		t.Setenv(EnvDogStatsDPort, tt.port)
+		if got := StatsDAddress(); got != tt.want {
+			t.Errorf("StatsDAddress() with host %q, port %q = %q, want %q", tt.host, tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	for value, want := range map[string]bool{"true": true, "": false, "false": false, "1": false} {
+		t.Setenv(EnvMetricsDisabled, value)
+		if got := MetricsDisabled(); got != want {
+			t.Errorf("MetricsDisabled() with %s=%q = %v, want %v", EnvMetricsDisabled, value, got, want)
+		}
+	}
+}
@@ -15,6 +15,7 @@ const (
 	EmailTypeProofVoted            EmailType = "proof_voted"
 	EmailTypeGoalFunded            EmailType = "goal_funded"
 	EmailTypeKYCVerified           EmailType = "kyc_verified"
+	EmailTypeDigest                EmailType = "digest"
 )
 
 // EmailPayload represents the data sent to the notification service
@@ -18,6 +18,34 @@ const (
 	GoalStatusVerified       GoalStatus = "VERIFIED"
 	GoalStatusClosed         GoalStatus = "CLOSED"
 	GoalStatusCancelled      GoalStatus = "CANCELLED"
+	GoalStatusArchived       GoalStatus = "ARCHIVED"
+	// GoalStatusSuspended is set by an admin; the goal takes no contributions or withdrawals
+	GoalStatusSuspended GoalStatus = "SUSPENDED"
+)
+
+// GoalVisibility controls who can find and open a goal
+type GoalVisibility string
+
+const (
+	// GoalVisibilityPublic goals are listed and open to everyone
+	GoalVisibilityPublic GoalVisibility = "PUBLIC"
+	// GoalVisibilityUnlisted goals are open to anyone with the link but never listed
+	GoalVisibilityUnlisted GoalVisibility = "UNLISTED"
+	// GoalVisibilityPrivate goals are open only to the owner, collaborators, contributors
+	// and holders of an invite link
+	GoalVisibilityPrivate GoalVisibility = "PRIVATE"
+)
+
+// FeeMode decides who pays the platform fee on a goal's contributions
+type FeeMode string
+
+const (
+	// FeeModeAbsorb takes the fee out of each contribution, so the goal receives less
+	// than was contributed
+	FeeModeAbsorb FeeMode = "absorb"
+	// FeeModeAddOn charges contributors the fee on top, so the goal receives exactly
+	// what was contributed
+	FeeModeAddOn FeeMode = "add_on"
 )
 
 // Goal represents a funding goal with milestone support
@@ -25,19 +53,51 @@ type Goal struct {
 	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	OwnerID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"owner_id"`
 	Title        string     `gorm:"not null;size:255" json:"title"`
+	Slug         *string    `gorm:"size:80;uniqueIndex" json:"slug,omitempty"` // Shareable name from the title; kept when it changes
 	Description  string     `gorm:"type:text" json:"description"`
 	TargetAmount int64      `gorm:"not null" json:"target_amount"` // Amount in smallest currency unit
 	Currency     string     `gorm:"not null;size:3;default:'NGN'" json:"currency"`
-	Deadline     *time.Time `json:"deadline,omitempty"`
+	Deadline     *time.Time `gorm:"index" json:"deadline,omitempty"`
 	Status       GoalStatus `gorm:"not null;default:'OPEN';size:20" json:"status"`
 	IsPublic     bool       `gorm:"not null;default:true" json:"is_public"`
+	// Visibility decides who may open the goal; IsPublic mirrors it as whether the goal is
+	// listed, and is true only for PUBLIC goals
+	Visibility GoalVisibility `gorm:"not null;default:'PUBLIC';size:20;index" json:"visibility"`
+	// CloseOnTarget closes the goal as soon as confirmed contributions reach the target
+	CloseOnTarget bool `gorm:"not null;default:false" json:"close_on_target"`
+	// FixedContributionAmount, when non-zero, is the only amount a contribution may be (dues-style goals)
+	FixedContributionAmount int64 `gorm:"not null;default:0" json:"fixed_contribution_amount"`
+	// RequireProofForWithdrawal holds withdrawals until contributors have verified a proof
+	// for the milestone (or, for goal-level withdrawals, a goal-level proof)
+	RequireProofForWithdrawal bool `gorm:"not null;default:false" json:"require_proof_for_withdrawal"`
+	// WeightedVoting verifies proofs by the share of contributed money behind satisfied votes
+	// rather than by a head count of satisfied contributors
+	WeightedVoting bool `gorm:"not null;default:false" json:"weighted_voting"`
+	// RequiredApprovals is how many of the owner and approver collaborators must approve a
+	// withdrawal before it is paid out; 1 means the requester's own approval is enough
+	RequiredApprovals int `gorm:"not null;default:1" json:"required_approvals"`
+	// FeeMode is who pays the platform fee on new contributions; contributions keep the
+	// mode they were made under
+	FeeMode FeeMode `gorm:"not null;default:'absorb';size:10" json:"fee_mode"`
+	// Moderation: the status to restore on unsuspension, and why the goal was suspended
+	SuspendedFromStatus GoalStatus `gorm:"size:20" json:"-"`
+	SuspendedAt         *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason    string     `gorm:"type:text" json:"suspension_reason,omitempty"`
+	// Funding totals, kept in step with confirmations and refunds so lists can sort by them:
+	// confirmed contributions net of completed refund disbursements, and the distinct
+	// contributors with money still in the goal
+	CurrentAmount    int64 `gorm:"not null;default:0;index" json:"current_amount"`
+	ContributorCount int64 `gorm:"not null;default:0;index" json:"contributor_count"`
+	// DeadlineReminderSentAt is when watchers were told the deadline is near, so the
+	// reminder goes out once per goal
+	DeadlineReminderSentAt *time.Time `json:"-"`
 
 	// Deposit account details (where goal owner receives withdrawals)
 	DepositBankName      string `gorm:"size:100" json:"deposit_bank_name,omitempty"`
 	DepositAccountNumber string `gorm:"size:20" json:"deposit_account_number,omitempty"`
 	DepositAccountName   string `gorm:"size:255" json:"deposit_account_name,omitempty"`
 
-	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	CreatedAt time.Time `gorm:"not null;index" json:"created_at"`
 	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
 
 	// Relationships
@@ -60,6 +120,19 @@ func (g *Goal) BeforeCreate(tx *gorm.DB) error {
 func (Goal) TableName() string {
 	return "goals"
 }
+// GoalTrending stores a goal's latest trending score, recomputed periodically by goals-service
+type GoalTrending struct {
+	GoalID     uuid.UUID `gorm:"type:uuid;primary_key" json:"goal_id"`
+	Score      float64   `gorm:"not null" json:"score"`
+	Rank       int       `gorm:"not null;index" json:"rank"`
+	ComputedAt time.Time `gorm:"not null" json:"computed_at"`
+}
+
+// TableName specifies the table name for GoalTrending
+func (GoalTrending) TableName() string {
+	return "goal_trending"
+}
+
 // RefundStatus represents the status of a refund
 type RefundStatus string
 
@@ -80,6 +153,7 @@ type Refund struct {
 	Currency          string       `gorm:"not null;size:3;default:'NGN'" json:"currency"`
 	Reason            string       `gorm:"type:text" json:"reason,omitempty"`
 	Status            RefundStatus `gorm:"not null;default:'PENDING';size:20" json:"status"`
+	Metadata          map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"metadata,omitempty"` // audit snapshot taken at initiation
 	CreatedAt         time.Time    `gorm:"not null" json:"created_at"`
 	CompletedAt       *time.Time   `json:"completed_at,omitempty"`
 
@@ -117,6 +191,12 @@ type RefundDisbursement struct {
 
 	Status                RefundStatus `gorm:"not null;default:'PENDING';size:20" json:"status"`
 	LedgerTransactionID   *uuid.UUID   `gorm:"type:uuid" json:"ledger_transaction_id,omitempty"`
+
+	// Payout transfer, filled in from payments-service events
+	TransferReference string `gorm:"size:100;index" json:"transfer_reference,omitempty"`
+	TransferCode      string `gorm:"size:100" json:"transfer_code,omitempty"`
+	FailureReason     string `gorm:"size:255" json:"failure_reason,omitempty"`
+
 	CreatedAt             time.Time    `gorm:"not null" json:"created_at"`
 	CompletedAt           *time.Time   `json:"completed_at,omitempty"`
 
@@ -137,6 +217,49 @@ func (rd *RefundDisbursement) BeforeCreate(tx *gorm.DB) error {
 func (RefundDisbursement) TableName() string {
 	return "refund_disbursements"
 }
+
+// RefundRequestStatus represents the status of a contributor's refund request
+type RefundRequestStatus string
+
+const (
+	RefundRequestStatusPending  RefundRequestStatus = "PENDING" // awaiting the goal owner
+	RefundRequestStatusApproved RefundRequestStatus = "APPROVED"
+	RefundRequestStatusDenied   RefundRequestStatus = "DENIED"
+)
+
+// RefundRequest is a contributor asking for one of their contributions to be refunded.
+// Requests on an open goal within the grace window after contributing are approved
+// automatically; the rest wait for the goal owner. RefundID is the refund an approval
+// started.
+type RefundRequest struct {
+	ID             uuid.UUID           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	GoalID         uuid.UUID           `gorm:"type:uuid;not null;index" json:"goal_id"`
+	ContributionID uuid.UUID           `gorm:"type:uuid;not null;index" json:"contribution_id"`
+	UserID         uuid.UUID           `gorm:"type:uuid;not null;index" json:"user_id"`
+	Amount         int64               `gorm:"not null" json:"amount"`
+	Currency       string              `gorm:"not null;size:3;default:'NGN'" json:"currency"`
+	Reason         string              `gorm:"type:text;not null" json:"reason"`
+	Status         RefundRequestStatus `gorm:"not null;default:'PENDING';size:20;index" json:"status"`
+	AutoApproved   bool                `gorm:"not null;default:false" json:"auto_approved"`
+	ReviewedBy     *uuid.UUID          `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewNote     string              `gorm:"type:text" json:"review_note,omitempty"`
+	RefundID       *uuid.UUID          `gorm:"type:uuid" json:"refund_id,omitempty"`
+	CreatedAt      time.Time           `gorm:"not null" json:"created_at"`
+	ReviewedAt     *time.Time          `json:"reviewed_at,omitempty"`
+}
+
+// BeforeCreate sets UUID before creating refund request
+func (r *RefundRequest) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for RefundRequest
+func (RefundRequest) TableName() string {
+	return "refund_requests"
+}
 // RecurrenceType represents the type of milestone recurrence
 type RecurrenceType string
 
@@ -170,6 +293,9 @@ type Milestone struct {
 	RecurrenceType     *RecurrenceType `gorm:"size:20" json:"recurrence_type,omitempty"`
 	RecurrenceInterval int             `json:"recurrence_interval,omitempty"`
 	NextDueDate        *time.Time      `json:"next_due_date,omitempty"`
+	// ParentMilestoneID is the recurring milestone this one was created from when it was
+	// completed; following it back gives the milestone's place in the recurrence chain
+	ParentMilestoneID *uuid.UUID `gorm:"type:uuid;index" json:"parent_milestone_id,omitempty"`
 
 	Status      MilestoneStatus `gorm:"not null;default:'PENDING';size:20" json:"status"`
 	CompletedAt *time.Time      `json:"completed_at,omitempty"`
@@ -205,21 +331,36 @@ const (
 	ContributionStatusConfirmed ContributionStatus = "CONFIRMED"
 	ContributionStatusFailed    ContributionStatus = "FAILED"
 	ContributionStatusRefunded  ContributionStatus = "REFUNDED"
+	ContributionStatusExpired   ContributionStatus = "EXPIRED"
 )
 
 // Contribution represents a user's contribution to a goal
 type Contribution struct {
 	ID          uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	GoalID      uuid.UUID          `gorm:"type:uuid;not null;index" json:"goal_id"`
-	MilestoneID *uuid.UUID         `gorm:"type:uuid;index" json:"milestone_id,omitempty"`
-	UserID      uuid.UUID          `gorm:"type:uuid;not null;index" json:"user_id"`
+	GoalID      uuid.UUID          `gorm:"type:uuid;not null;index;index:idx_contributions_goal_created;index:idx_contributions_goal_status" json:"goal_id"`
+	MilestoneID *uuid.UUID         `gorm:"type:uuid;index;index:idx_contributions_milestone_status" json:"milestone_id,omitempty"`
+	UserID      uuid.UUID          `gorm:"type:uuid;not null;index;index:idx_contributions_user_status" json:"user_id"`
 	PaymentID   *uuid.UUID         `gorm:"type:uuid;index" json:"payment_id,omitempty"` // Reference to payment service
 	Amount      int64              `gorm:"not null" json:"amount"`
 	Currency    string             `gorm:"not null;size:3;default:'NGN'" json:"currency"`
-	Status      ContributionStatus `gorm:"not null;default:'PENDING';size:20" json:"status"`
-	CreatedAt   time.Time          `gorm:"not null" json:"created_at"`
+	Status      ContributionStatus `gorm:"not null;default:'PENDING';size:20;index:idx_contributions_goal_status;index:idx_contributions_milestone_status;index:idx_contributions_user_status" json:"status"`
+	IsAnonymous bool               `gorm:"not null;default:false" json:"is_anonymous"` // hide the contributor from everyone but themselves
+	ExpiresAt   *time.Time         `gorm:"index" json:"expires_at,omitempty"` // pending intents expire if checkout is abandoned
+	CreatedAt   time.Time          `gorm:"not null;index:idx_contributions_goal_created" json:"created_at"` // goal stats read by goal and day
 	UpdatedAt   time.Time          `gorm:"not null" json:"updated_at"`
 
+	// Reusable card the contribution was paid with, which a recurring contribution can charge
+	AuthorizationCode       string     `gorm:"size:100" json:"-"`
+	AuthorizationEmail      string     `gorm:"size:255" json:"-"`
+	RecurringContributionID *uuid.UUID `gorm:"type:uuid;index" json:"recurring_contribution_id,omitempty"` // set when charged by a standing order
+
+	// Platform fee, fixed when the intent is created: FeeMode is the goal's mode at the
+	// time, FeeAmount the fee and NetAmount what reaches the goal. Contributions made
+	// before fees have no mode and no fee.
+	FeeMode   FeeMode `gorm:"size:10" json:"fee_mode,omitempty"`
+	FeeAmount int64   `gorm:"not null;default:0" json:"fee_amount"`
+	NetAmount int64   `gorm:"not null;default:0" json:"net_amount"`
+
 	// Relationships
 	Goal      Goal       `gorm:"constraint:OnDelete:CASCADE"`
 	Milestone *Milestone `gorm:"constraint:OnDelete:SET NULL"`
@@ -233,6 +374,85 @@ func (c *Contribution) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// IsExpired reports whether the contribution is an intent that expired before being confirmed
+func (c *Contribution) IsExpired(now time.Time) bool {
+	if c.Status == ContributionStatusExpired {
+		return true
+	}
+	return c.Status == ContributionStatusPending && c.ExpiresAt != nil && c.ExpiresAt.Before(now)
+}
+
+// ChargeAmount is what the contributor pays: the amount, plus the fee when it is added on
+func (c *Contribution) ChargeAmount() int64 {
+	if c.FeeMode == FeeModeAddOn {
+		return c.Amount + c.FeeAmount
+	}
+	return c.Amount
+}
+
+// GoalAmount is what reaches the goal: the amount, less the fee when the goal absorbs it.
+// Unlike NetAmount it is also right for contributions made before fees.
+func (c *Contribution) GoalAmount() int64 {
+	if c.FeeMode == FeeModeAbsorb {
+		return c.Amount - c.FeeAmount
+	}
+	return c.Amount
+}
+
+// RecurringContributionStatus represents the status of a recurring contribution
+type RecurringContributionStatus string
+
+const (
+	RecurringContributionActive    RecurringContributionStatus = "ACTIVE"
+	RecurringContributionPaused    RecurringContributionStatus = "PAUSED"
+	RecurringContributionCancelled RecurringContributionStatus = "CANCELLED"
+)
+
+// RecurringContribution is a standing order that charges a contributor's saved card
+// for a goal every interval
+type RecurringContribution struct {
+	ID                 uuid.UUID                   `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID             uuid.UUID                   `gorm:"type:uuid;not null;index" json:"user_id"`
+	GoalID             uuid.UUID                   `gorm:"type:uuid;not null;index" json:"goal_id"`
+	Amount             int64                       `gorm:"not null" json:"amount"`
+	Currency           string                      `gorm:"not null;size:3;default:'NGN'" json:"currency"`
+	Interval           RecurrenceType              `gorm:"not null;size:20" json:"interval"` // WEEKLY or MONTHLY
+	IsAnonymous        bool                        `gorm:"not null;default:false" json:"is_anonymous"`
+	Status             RecurringContributionStatus `gorm:"not null;default:'ACTIVE';size:20;index" json:"status"`
+	NextChargeDate     time.Time                   `gorm:"not null;index" json:"next_charge_date"`
+	FailedAttempts     int                         `gorm:"not null;default:0" json:"failed_attempts"` // failed charges in the current cycle
+	RetryAt            *time.Time                  `gorm:"index" json:"retry_at,omitempty"`           // next retry of a failed charge
+	LastFailureReason  string                      `gorm:"type:text" json:"last_failure_reason,omitempty"`
+	LastChargedAt      *time.Time                  `json:"last_charged_at,omitempty"`
+	AuthorizationCode  string                      `gorm:"not null;size:100" json:"-"`
+	AuthorizationEmail string                      `gorm:"not null;size:255" json:"-"`
+	CreatedAt          time.Time                   `gorm:"not null" json:"created_at"`
+	UpdatedAt          time.Time                   `gorm:"not null" json:"updated_at"`
+
+	// Relationships
+	Goal Goal `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate sets UUID before creating recurring contribution
+func (r *RecurringContribution) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for RecurringContribution
+func (RecurringContribution) TableName() string {
+	return "recurring_contributions"
+}
+
+// MaskContributor clears the contributor of an anonymous contribution unless viewerID made it
+func (c *Contribution) MaskContributor(viewerID uuid.UUID) {
+	if c.IsAnonymous && c.UserID != viewerID {
+		c.UserID = uuid.Nil
+	}
+}
+
 // TableName specifies the table name for Contribution
 func (Contribution) TableName() string {
 	return "contributions"
@@ -242,18 +462,22 @@ func (Contribution) TableName() string {
 type WithdrawalStatus string
 
 const (
-	WithdrawalStatusPending    WithdrawalStatus = "PENDING"
-	WithdrawalStatusProcessing WithdrawalStatus = "PROCESSING"
-	WithdrawalStatusCompleted  WithdrawalStatus = "COMPLETED"
-	WithdrawalStatusFailed     WithdrawalStatus = "FAILED"
+	// WithdrawalStatusAwaitingApproval holds a withdrawal on a co-owned goal until enough
+	// collaborators approve it; its amount is already reserved
+	WithdrawalStatusAwaitingApproval WithdrawalStatus = "AWAITING_APPROVAL"
+	WithdrawalStatusPending          WithdrawalStatus = "PENDING"
+	WithdrawalStatusProcessing       WithdrawalStatus = "PROCESSING"
+	WithdrawalStatusCompleted        WithdrawalStatus = "COMPLETED"
+	WithdrawalStatusFailed           WithdrawalStatus = "FAILED"
 )
 
 // Withdrawal represents a withdrawal request by goal owner
 type Withdrawal struct {
 	ID          uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	GoalID      uuid.UUID        `gorm:"type:uuid;not null;index" json:"goal_id"`
+	GoalID      uuid.UUID        `gorm:"type:uuid;not null;index;index:idx_withdrawals_goal_status" json:"goal_id"`
 	MilestoneID *uuid.UUID       `gorm:"type:uuid;index" json:"milestone_id,omitempty"`
 	OwnerID     uuid.UUID        `gorm:"type:uuid;not null;index" json:"owner_id"`
+	RequestedBy *uuid.UUID       `gorm:"type:uuid" json:"requested_by,omitempty"` // Collaborator who asked for it; empty means the owner
 	Amount      int64            `gorm:"not null" json:"amount"`
 	Currency    string           `gorm:"not null;size:3;default:'NGN'" json:"currency"`
 
@@ -261,16 +485,23 @@ type Withdrawal struct {
 	BankName      string `gorm:"not null;size:100" json:"bank_name"`
 	AccountNumber string `gorm:"not null;size:20" json:"account_number"`
 	AccountName   string `gorm:"not null;size:255" json:"account_name"`
+	BankCode      string `gorm:"size:10" json:"bank_code,omitempty"`
 
-	Status              WithdrawalStatus `gorm:"not null;default:'PENDING';size:20" json:"status"`
+	Status              WithdrawalStatus `gorm:"not null;default:'PENDING';size:20;index:idx_withdrawals_goal_status" json:"status"`
 	LedgerTransactionID *uuid.UUID       `gorm:"type:uuid" json:"ledger_transaction_id,omitempty"`
 
+	// Payout transfer, filled in from payments-service events
+	TransferReference string `gorm:"size:100;index" json:"transfer_reference,omitempty"`
+	TransferCode      string `gorm:"size:100" json:"transfer_code,omitempty"`
+	FailureReason     string `gorm:"size:255" json:"failure_reason,omitempty"`
+
 	RequestedAt time.Time  `gorm:"not null" json:"requested_at"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
 
 	// Relationships
-	Goal      Goal       `gorm:"constraint:OnDelete:CASCADE"`
-	Milestone *Milestone `gorm:"constraint:OnDelete:SET NULL"`
+	Goal      Goal                 `gorm:"constraint:OnDelete:CASCADE"`
+	Milestone *Milestone           `gorm:"constraint:OnDelete:SET NULL"`
+	Approvals []WithdrawalApproval `gorm:"foreignKey:WithdrawalID;constraint:OnDelete:CASCADE" json:"approvals,omitempty"`
 }
 
 // BeforeCreate sets UUID before creating withdrawal
@@ -289,6 +520,15 @@ func (Withdrawal) TableName() string {
 	return "withdrawals"
 }
 
+// ProofStatus represents whether contributors have verified or rejected a proof
+type ProofStatus string
+
+const (
+	ProofStatusPending  ProofStatus = "PENDING"
+	ProofStatusVerified ProofStatus = "VERIFIED"
+	ProofStatusRejected ProofStatus = "REJECTED"
+)
+
 // Proof represents proof of goal accomplishment
 type Proof struct {
 	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
@@ -300,6 +540,11 @@ type Proof struct {
 	MediaURLs   []string   `gorm:"type:jsonb;serializer:json" json:"media_urls,omitempty"`
 	SubmittedAt time.Time  `gorm:"not null" json:"submitted_at"`
 
+	// Set once, when enough contributors vote the proof satisfactory or not
+	Status     ProofStatus `gorm:"not null;default:'PENDING';size:20" json:"status"`
+	VerifiedAt *time.Time  `json:"verified_at,omitempty"`
+	RejectedAt *time.Time  `json:"rejected_at,omitempty"`
+
 	// Relationships
 	Goal      Goal       `gorm:"constraint:OnDelete:CASCADE"`
 	Milestone *Milestone `gorm:"constraint:OnDelete:SET NULL"`
@@ -314,6 +559,9 @@ func (p *Proof) BeforeCreate(tx *gorm.DB) error {
 	if p.SubmittedAt.IsZero() {
 		p.SubmittedAt = time.Now()
 	}
+	if p.Status == "" {
+		p.Status = ProofStatusPending
+	}
 	return nil
 }
 
@@ -325,16 +573,27 @@ func (Proof) TableName() string {
 // Vote represents a community vote on proof verification
 type Vote struct {
 	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	ProofID     uuid.UUID `gorm:"type:uuid;not null;index" json:"proof_id"`
+	ProofID     uuid.UUID `gorm:"type:uuid;not null;index;index:idx_votes_proof_satisfied" json:"proof_id"`
 	VoterID     uuid.UUID `gorm:"type:uuid;not null;index" json:"voter_id"`
-	IsSatisfied bool      `gorm:"not null" json:"is_satisfied"` // TRUE = satisfied, FALSE = not satisfied
+	IsSatisfied bool      `gorm:"not null;index:idx_votes_proof_satisfied" json:"is_satisfied"` // TRUE = satisfied, FALSE = not satisfied
 	Comment     string    `gorm:"type:text" json:"comment,omitempty"`
 	VotedAt     time.Time `gorm:"not null" json:"voted_at"`
+	// ShowIdentity lets the goal owner see who cast the vote; no one else ever does
+	ShowIdentity bool `gorm:"not null;default:false" json:"show_identity"`
 
 	// Relationships
 	Proof Proof `gorm:"constraint:OnDelete:CASCADE"`
 }
 
+// MaskVoter clears the voter of a vote unless viewerID cast it, or is the goal's owner
+// (ownerID) and the voter chose to show their identity
+func (v *Vote) MaskVoter(viewerID, ownerID uuid.UUID) {
+	if v.VoterID == viewerID || (v.ShowIdentity && viewerID == ownerID) {
+		return
+	}
+	v.VoterID = uuid.Nil
+}
+
 // BeforeCreate sets UUID before creating vote
 func (v *Vote) BeforeCreate(tx *gorm.DB) error {
 	if v.ID == uuid.Nil {
@@ -349,4 +608,314 @@ func (v *Vote) BeforeCreate(tx *gorm.DB) error {
 // TableName specifies the table name for Vote
 func (Vote) TableName() string {
 	return "votes"
-}
\ No newline at end of file
+}
+
+// CommentRemovedBody is shown in place of a deleted comment's body
+const CommentRemovedBody = "[removed]"
+
+// Comment is a public comment on a goal. Replies point at a top-level comment through
+// ParentID; there is one level of replies. Deleted comments keep their row, flagged, so
+// threads keep their shape.
+type Comment struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	GoalID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"goal_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	ParentID  *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	Body      string     `gorm:"type:text;not null" json:"body"`
+	IsDeleted bool       `gorm:"not null;default:false" json:"is_deleted"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt time.Time  `gorm:"not null;index" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"not null" json:"updated_at"`
+
+	// Relationships
+	Goal   Goal     `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Parent *Comment `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate sets UUID before creating comment
+func (c *Comment) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for Comment
+func (Comment) TableName() string {
+	return "comments"
+}
+
+// GoalUpdate is a progress announcement an owner posts to a goal's backers
+type GoalUpdate struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	GoalID    uuid.UUID `gorm:"type:uuid;not null;index" json:"goal_id"`
+	AuthorID  uuid.UUID `gorm:"type:uuid;not null" json:"author_id"`
+	Title     string    `gorm:"not null;size:255" json:"title"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	MediaURLs []string  `gorm:"type:jsonb;serializer:json" json:"media_urls,omitempty"`
+	CreatedAt time.Time `gorm:"not null;index" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+
+	// Relationships
+	Goal Goal `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate sets UUID before creating goal update
+func (u *GoalUpdate) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for GoalUpdate
+func (GoalUpdate) TableName() string {
+	return "goal_updates"
+}
+
+// GoalWatch is a user following a goal they have not necessarily contributed to
+type GoalWatch struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_goal_watches_user_goal" json:"user_id"`
+	GoalID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_goal_watches_user_goal;index" json:"goal_id"`
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+
+	// Relationships
+	Goal Goal `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate sets UUID before creating goal watch
+func (w *GoalWatch) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for GoalWatch
+func (GoalWatch) TableName() string {
+	return "goal_watches"
+}
+
+// AuditAction names a sensitive goal or financial action recorded in the audit log
+type AuditAction string
+
+const (
+	AuditActionBankDetailsChanged  AuditAction = "BANK_DETAILS_CHANGED"
+	AuditActionGoalClosed          AuditAction = "GOAL_CLOSED"
+	AuditActionGoalCancelled       AuditAction = "GOAL_CANCELLED"
+	AuditActionGoalArchived        AuditAction = "GOAL_ARCHIVED"
+	AuditActionGoalDeleted         AuditAction = "GOAL_DELETED"
+	AuditActionWithdrawalRequested AuditAction = "WITHDRAWAL_REQUESTED"
+	AuditActionWithdrawalApproved  AuditAction = "WITHDRAWAL_APPROVED"
+	AuditActionRefundInitiated     AuditAction = "REFUND_INITIATED"
+	AuditActionRefundRequestDenied AuditAction = "REFUND_REQUEST_DENIED"
+	AuditActionMilestoneCompleted  AuditAction = "MILESTONE_COMPLETED"
+	AuditActionMilestoneUpdated    AuditAction = "MILESTONE_UPDATED"
+	AuditActionMilestoneDeleted    AuditAction = "MILESTONE_DELETED"
+	AuditActionGoalSuspended       AuditAction = "GOAL_SUSPENDED"
+	AuditActionGoalUnsuspended     AuditAction = "GOAL_UNSUSPENDED"
+	AuditActionReportsDismissed    AuditAction = "REPORTS_DISMISSED"
+)
+
+// AuditLog records who performed a sensitive action on a goal, and what it changed, for
+// dispute resolution. Entries are never updated or deleted.
+type AuditLog struct {
+	ID         uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	GoalID     uuid.UUID              `gorm:"type:uuid;not null;index:idx_audit_logs_goal_created" json:"goal_id"`
+	ActorID    uuid.UUID              `gorm:"type:uuid;not null;index" json:"actor_id"`
+	Action     AuditAction            `gorm:"type:varchar(50);not null" json:"action"`
+	EntityType string                 `gorm:"type:varchar(50);not null" json:"entity_type"` // goal, withdrawal, refund, refund_request or milestone
+	EntityID   uuid.UUID              `gorm:"type:uuid;not null" json:"entity_id"`
+	Before     map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"before,omitempty"`
+	After      map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"after,omitempty"`
+	IP         string                 `gorm:"type:varchar(64)" json:"ip,omitempty"`
+	RequestID  string                 `gorm:"type:varchar(128)" json:"request_id,omitempty"`
+	CreatedAt  time.Time              `gorm:"not null;index:idx_audit_logs_goal_created" json:"created_at"`
+}
+
+// BeforeCreate sets UUID before creating audit log
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for AuditLog
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// CollaboratorRole is what a collaborator may do on a co-owned goal
+type CollaboratorRole string
+
+const (
+	// CollaboratorRoleOwner may request and approve withdrawals
+	CollaboratorRoleOwner CollaboratorRole = "owner"
+	// CollaboratorRoleApprover may only approve withdrawals
+	CollaboratorRoleApprover CollaboratorRole = "approver"
+)
+
+// GoalCollaborator is a user who helps organise a goal alongside its owner. The goal owner
+// is implicitly a collaborator and has no row here.
+type GoalCollaborator struct {
+	ID        uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	GoalID    uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_goal_collaborators_goal_user" json:"goal_id"`
+	UserID    uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_goal_collaborators_goal_user;index" json:"user_id"`
+	Role      CollaboratorRole `gorm:"not null;size:20" json:"role"`
+	InvitedBy uuid.UUID        `gorm:"type:uuid;not null" json:"invited_by"`
+	CreatedAt time.Time        `gorm:"not null" json:"created_at"`
+
+	// Relationships
+	Goal Goal `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate sets UUID before creating goal collaborator
+func (c *GoalCollaborator) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for GoalCollaborator
+func (GoalCollaborator) TableName() string {
+	return "goal_collaborators"
+}
+
+// WithdrawalApproval is one collaborator's sign-off on a withdrawal awaiting approval
+type WithdrawalApproval struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	WithdrawalID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_withdrawal_approvals_withdrawal_approver" json:"withdrawal_id"`
+	ApproverID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_withdrawal_approvals_withdrawal_approver" json:"approver_id"`
+	ApprovedAt   time.Time `gorm:"not null" json:"approved_at"`
+}
+
+// BeforeCreate sets UUID before creating withdrawal approval
+func (a *WithdrawalApproval) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for WithdrawalApproval
+func (WithdrawalApproval) TableName() string {
+	return "withdrawal_approvals"
+}
+
+// WithdrawalStatusEvent records a withdrawal entering a status, so its owner can see how
+// it progressed and why it failed. The first event of a withdrawal has no FromStatus.
+type WithdrawalStatusEvent struct {
+	ID           uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	WithdrawalID uuid.UUID        `gorm:"type:uuid;not null;index" json:"withdrawal_id"`
+	FromStatus   WithdrawalStatus `gorm:"size:20" json:"from_status,omitempty"`
+	Status       WithdrawalStatus `gorm:"not null;size:20" json:"status"`
+	// Reason is the failure reason of a withdrawal that entered FAILED
+	Reason    string    `gorm:"size:255" json:"reason,omitempty"`
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+}
+
+// BeforeCreate sets UUID before creating withdrawal status event
+func (e *WithdrawalStatusEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for WithdrawalStatusEvent
+func (WithdrawalStatusEvent) TableName() string {
+	return "withdrawal_status_events"
+}
+
+// ProofMedia is a file uploaded as evidence for a proof. It is stored under StorageKey
+// and served at URL; ProofID is set once a proof cites the URL.
+type ProofMedia struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UploadedBy  uuid.UUID  `gorm:"type:uuid;not null;index" json:"uploaded_by"`
+	ProofID     *uuid.UUID `gorm:"type:uuid;index" json:"proof_id,omitempty"`
+	StorageKey  string     `gorm:"not null;size:255;uniqueIndex" json:"-"`
+	URL         string     `gorm:"not null;size:512;uniqueIndex" json:"url"`
+	ContentType string     `gorm:"not null;size:50" json:"content_type"`
+	Size        int64      `gorm:"not null" json:"size"`
+	CreatedAt   time.Time  `gorm:"not null" json:"created_at"`
+}
+
+// BeforeCreate sets UUID before creating proof media
+func (m *ProofMedia) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for ProofMedia
+func (ProofMedia) TableName() string {
+	return "proof_media"
+}
+
+// GoalReportCategory is what a goal was reported or flagged for
+type GoalReportCategory string
+
+const (
+	GoalReportCategoryFraud         GoalReportCategory = "fraud"
+	GoalReportCategorySpam          GoalReportCategory = "spam"
+	GoalReportCategoryMisleading    GoalReportCategory = "misleading"
+	GoalReportCategoryInappropriate GoalReportCategory = "inappropriate"
+	GoalReportCategoryOther         GoalReportCategory = "other"
+	// GoalReportCategoryProofRejected flags a goal whose contributors voted a proof down
+	GoalReportCategoryProofRejected GoalReportCategory = "proof_rejected"
+)
+
+// GoalReportSource says who raised a report
+type GoalReportSource string
+
+const (
+	// GoalReportSourceUser is a report a user made
+	GoalReportSourceUser GoalReportSource = "USER"
+	// GoalReportSourceAuto is a flag goals-service raised itself
+	GoalReportSourceAuto GoalReportSource = "AUTO"
+)
+
+// GoalReportStatus represents the status of a goal report
+type GoalReportStatus string
+
+const (
+	GoalReportStatusOpen      GoalReportStatus = "OPEN"
+	GoalReportStatusDismissed GoalReportStatus = "DISMISSED"
+)
+
+// GoalReport is a user's report of a goal, or a flag raised automatically, awaiting an
+// admin in the moderation queue. Open reports count towards the goal's queue priority
+// until an admin dismisses them. A user has at most one open report per goal.
+type GoalReport struct {
+	ID          uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	GoalID      uuid.UUID          `gorm:"type:uuid;not null;index:idx_goal_reports_goal_status;uniqueIndex:idx_goal_reports_open_reporter" json:"goal_id"`
+	ReporterID  *uuid.UUID         `gorm:"type:uuid;index;uniqueIndex:idx_goal_reports_open_reporter,where:status = 'OPEN'" json:"reporter_id,omitempty"` // empty for automatic flags
+	Source      GoalReportSource   `gorm:"not null;default:'USER';size:10" json:"source"`
+	Category    GoalReportCategory `gorm:"not null;size:20" json:"category"`
+	Details     string             `gorm:"type:text" json:"details,omitempty"`
+	Status      GoalReportStatus   `gorm:"not null;default:'OPEN';size:20;index:idx_goal_reports_goal_status;index:idx_goal_reports_status_created" json:"status"`
+	DismissedBy *uuid.UUID         `gorm:"type:uuid" json:"dismissed_by,omitempty"`
+	DismissedAt *time.Time         `json:"dismissed_at,omitempty"`
+	CreatedAt   time.Time          `gorm:"not null;index:idx_goal_reports_status_created" json:"created_at"`
+	UpdatedAt   time.Time          `gorm:"not null" json:"updated_at"`
+
+	// Relationships
+	Goal Goal `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate sets UUID before creating goal report
+func (r *GoalReport) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for GoalReport
+func (GoalReport) TableName() string {
+	return "goal_reports"
+}
@@ -20,9 +20,9 @@ const (
 // Account represents an account in the double-entry ledger system
 type Account struct {
 	ID          uuid.UUID   `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	AccountType AccountType `gorm:"not null;size:20;index" json:"account_type"`
-	EntityID    uuid.UUID   `gorm:"type:uuid;not null;index" json:"entity_id"` // References user, goal, etc.
-	Currency    string      `gorm:"not null;size:3;default:'NGN'" json:"currency"`
+	AccountType AccountType `gorm:"not null;size:20;index;uniqueIndex:idx_accounts_entity" json:"account_type"`
+	EntityID    uuid.UUID   `gorm:"type:uuid;not null;index;uniqueIndex:idx_accounts_entity" json:"entity_id"` // References user, goal, etc.
+	Currency    string      `gorm:"not null;size:3;default:'NGN';uniqueIndex:idx_accounts_entity" json:"currency"`
 	CreatedAt   time.Time   `gorm:"not null" json:"created_at"`
 
 	// Relationships
@@ -54,7 +54,7 @@ type LedgerEntry struct {
 	Amount        int64                  `gorm:"not null" json:"amount"` // Always positive, type determines debit/credit
 	Currency      string                 `gorm:"not null;size:3;default:'NGN'" json:"currency"`
 	Description   string                 `gorm:"not null;size:500" json:"description"`
-	Metadata      map[string]interface{} `gorm:"type:jsonb" json:"metadata"`
+	Metadata      map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"metadata"`
 	CreatedAt     time.Time              `gorm:"not null;index" json:"created_at"`
 
 	// Relationships
@@ -89,15 +89,38 @@ func (bs *BalanceSnapshot) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// TransactionType represents what a ledger transaction records
+type TransactionType string
+
+const (
+	TransactionTypeContribution TransactionType = "CONTRIBUTION"
+	TransactionTypeWithdrawal   TransactionType = "WITHDRAWAL"
+	TransactionTypeRefund       TransactionType = "REFUND"
+	// TransactionTypeFee moves a contribution's platform fee from the goal to the
+	// platform's revenue account
+	TransactionTypeFee TransactionType = "FEE"
+)
+
+// TransactionStatus represents the state of a ledger transaction
+type TransactionStatus string
+
+const (
+	TransactionStatusPending   TransactionStatus = "PENDING"
+	TransactionStatusCompleted TransactionStatus = "COMPLETED"
+	TransactionStatusFailed    TransactionStatus = "FAILED"
+)
+
 // Transaction represents a financial transaction (for grouping ledger entries)
 type Transaction struct {
-	ID          uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Type        string                 `gorm:"not null;size:50;index" json:"type"` // CONTRIBUTION, WITHDRAWAL, etc.
-	Description string                 `gorm:"not null;size:500" json:"description"`
-	Amount      int64                  `gorm:"not null" json:"amount"`
-	Currency    string                 `gorm:"not null;size:3" json:"currency"`
-	Metadata    map[string]interface{} `gorm:"type:jsonb" json:"metadata"`
-	CreatedAt   time.Time              `gorm:"not null" json:"created_at"`
+	ID              uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Type            TransactionType        `gorm:"not null;size:50;index" json:"type"`
+	Description     string                 `gorm:"not null;size:500" json:"description"`
+	Amount          int64                  `gorm:"not null" json:"amount"`
+	Currency        string                 `gorm:"not null;size:3" json:"currency"`
+	Metadata        map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"metadata"`
+	Status          TransactionStatus      `gorm:"not null;size:20;default:'COMPLETED'" json:"status"`
+	TransactionDate time.Time              `gorm:"not null;index" json:"transaction_date"`
+	CreatedAt       time.Time              `gorm:"not null" json:"created_at"`
 
 	// Relationships
 	LedgerEntries []LedgerEntry `gorm:"foreignKey:TransactionID;constraint:OnDelete:RESTRICT" json:"ledger_entries,omitempty"`
@@ -109,4 +132,27 @@ func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
 		t.ID = uuid.New()
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// ReconciliationReport records an account whose balance snapshot disagreed with the sum
+// of its ledger entries during a reconciliation run
+type ReconciliationReport struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	RunID           uuid.UUID `gorm:"type:uuid;not null;index" json:"run_id"`
+	AccountID       uuid.UUID `gorm:"type:uuid;not null;index" json:"account_id"`
+	Currency        string    `gorm:"not null;size:3" json:"currency"`
+	ExpectedBalance int64     `gorm:"not null" json:"expected_balance"` // Credits minus debits
+	ActualBalance   int64     `gorm:"not null" json:"actual_balance"`   // Snapshot balance, 0 when missing
+	Difference      int64     `gorm:"not null" json:"difference"`       // Actual minus expected
+	SnapshotMissing bool      `gorm:"not null;default:false" json:"snapshot_missing"`
+	Corrected       bool      `gorm:"not null;default:false" json:"corrected"`
+	CreatedAt       time.Time `gorm:"not null;index" json:"created_at"`
+}
+
+// BeforeCreate sets UUID before creating reconciliation report
+func (rr *ReconciliationReport) BeforeCreate(tx *gorm.DB) error {
+	if rr.ID == uuid.Nil {
+		rr.ID = uuid.New()
+	}
+	return nil
+}
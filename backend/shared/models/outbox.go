@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboxEvent is an event recorded in the same transaction as the change it announces, so
+// the two commit or roll back together. The service's outbox dispatcher publishes it to
+// RabbitMQ afterwards, retrying with backoff until the broker takes it; a broker outage
+// delays events instead of losing them. An event is published at least once: one whose
+// dispatcher dies before recording it as sent goes out again.
+type OutboxEvent struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EventType string    `gorm:"type:varchar(100);not null" json:"event_type"`
+	Payload   string    `gorm:"type:jsonb;not null" json:"payload"`
+	RequestID string    `gorm:"type:varchar(128)" json:"request_id,omitempty"`
+	// NextAttemptAt is when the dispatcher next tries an unsent event: when it was
+	// recorded, then after each failure or while a dispatcher holds it
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_outbox_events_due,where:sent_at IS NULL" json:"next_attempt_at"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt        *time.Time `gorm:"index" json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"not null" json:"created_at"`
+}
+
+// BeforeCreate sets UUID before creating outbox event
+func (e *OutboxEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for OutboxEvent
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
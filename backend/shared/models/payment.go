@@ -23,14 +23,87 @@ type Payment struct {
 	PaystackReference  string                 `bson:"paystackReference,omitempty" json:"paystack_reference"`
 	UserID             string                 `bson:"userId" json:"user_id"`
 	GoalID             string                 `bson:"goalId,omitempty" json:"goal_id"`
+	ContributionID     string                 `bson:"contributionId,omitempty" json:"contribution_id,omitempty"` // goals-service contribution intent
 	Amount             int64                  `bson:"amount" json:"amount"`
+	FeeAmount          int64                  `bson:"feeAmount,omitempty" json:"fee_amount,omitempty"` // platform fee included in Amount
 	Currency           string                 `bson:"currency" json:"currency"`
 	Status             PaymentStatus          `bson:"status" json:"status"`
-	PaystackData       map[string]interface{} `bson:"paystackData,omitempty" json:"paystack_data"`
+	Paystack           *PaystackRecord        `bson:"paystack,omitempty" json:"paystack,omitempty"`
+	PaystackData       map[string]interface{} `bson:"paystackData,omitempty" json:"-"` // Legacy flat copy, see MigrateLegacyPaystackData
 	CreatedAt          time.Time              `bson:"createdAt" json:"created_at"`
 	UpdatedAt          time.Time              `bson:"updatedAt" json:"updated_at"`
 }
 
+// PaystackRecord is everything Paystack has told us about a payment, kept in separate
+// sections so a later response never erases an earlier one
+type PaystackRecord struct {
+	Initialization map[string]interface{} `bson:"initialization,omitempty" json:"initialization,omitempty"`
+	Verification   map[string]interface{} `bson:"verification,omitempty" json:"verification,omitempty"`
+	WebhookEvents  []PaystackWebhookEvent `bson:"webhookEvents,omitempty" json:"webhook_events,omitempty"`
+	AppMetadata    *PaymentAppMetadata    `bson:"appMetadata,omitempty" json:"app_metadata,omitempty"`
+}
+
+// PaystackWebhookEvent is one webhook delivery about a payment, appended in arrival order
+type PaystackWebhookEvent struct {
+	Event      string                 `bson:"event" json:"event"`
+	ReceivedAt time.Time              `bson:"receivedAt" json:"received_at"`
+	Data       map[string]interface{} `bson:"data" json:"data"`
+}
+
+// PaymentAppMetadata holds our own fields from the metadata sent to Paystack, validated
+// when it comes back so other services can rely on them
+type PaymentAppMetadata struct {
+	PaymentID      string                 `bson:"paymentId,omitempty" json:"payment_id,omitempty"`
+	UserID         string                 `bson:"userId,omitempty" json:"user_id,omitempty"`
+	GoalID         string                 `bson:"goalId,omitempty" json:"goal_id,omitempty"`
+	ContributionID string                 `bson:"contributionId,omitempty" json:"contribution_id,omitempty"`
+	MilestoneID    string                 `bson:"milestoneId,omitempty" json:"milestone_id,omitempty"`
+	Custom         map[string]interface{} `bson:"custom,omitempty" json:"custom,omitempty"` // Caller-supplied fields, e.g. custom_fields
+}
+
+// legacyInitializationKeys are the PaystackData keys written at initialization
+var legacyInitializationKeys = []string{"authorization_url", "access_code"}
+
+// MigrateLegacyPaystackData moves the flat PaystackData of documents written before
+// PaystackRecord existed into its sections. It runs on read; the next update persists the
+// result and drops the legacy field.
+func (p *Payment) MigrateLegacyPaystackData() {
+	if p.Paystack == nil {
+		p.Paystack = &PaystackRecord{}
+	}
+	if p.Paystack.AppMetadata == nil {
+		p.Paystack.AppMetadata = &PaymentAppMetadata{
+			PaymentID:      p.PaymentID,
+			UserID:         p.UserID,
+			GoalID:         p.GoalID,
+			ContributionID: p.ContributionID,
+		}
+	}
+	if len(p.PaystackData) == 0 {
+		p.PaystackData = nil
+		return
+	}
+
+	verification := make(map[string]interface{})
+	for k, v := range p.PaystackData {
+		verification[k] = v
+	}
+	for _, k := range legacyInitializationKeys {
+		if v, ok := verification[k]; ok {
+			if p.Paystack.Initialization == nil {
+				p.Paystack.Initialization = make(map[string]interface{})
+			}
+			p.Paystack.Initialization[k] = v
+			delete(verification, k)
+		}
+	}
+	if len(verification) > 0 && p.Paystack.Verification == nil {
+		p.Paystack.Verification = verification
+	}
+
+	p.PaystackData = nil
+}
+
 // WebhookEvent represents a webhook event from Paystack
 type WebhookEvent struct {
 	ID          primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
@@ -38,6 +111,7 @@ type WebhookEvent struct {
 	Event       string                 `bson:"event" json:"event"`      // Event type (charge.success, etc.)
 	Data        map[string]interface{} `bson:"data" json:"data"`        // Raw webhook payload
 	Signature   string                 `bson:"signature,omitempty" json:"signature"`
+	BodyHash    string                 `bson:"bodyHash,omitempty" json:"body_hash"` // SHA-256 of the raw request body
 	Processed   bool                   `bson:"processed" json:"processed"`
 	ReceivedAt  time.Time              `bson:"receivedAt" json:"received_at"`
 	ProcessedAt *time.Time             `bson:"processedAt,omitempty" json:"processed_at"`
@@ -73,6 +147,26 @@ const (
 	PaymentMethodQR           PaymentMethod = "QR"
 )
 
+// SavedPaymentMethod is a card a user has paid with, kept so they can pay with it again
+// without going through checkout. The authorization and its email never leave
+// payments-service.
+type SavedPaymentMethod struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID            string             `bson:"userId" json:"user_id"`
+	Fingerprint       string             `bson:"fingerprint" json:"-"` // Paystack's card signature, or the authorization code
+	AuthorizationCode string             `bson:"authorizationCode" json:"-"`
+	Email             string             `bson:"email" json:"-"` // customer email Paystack ties the authorization to
+	Channel           string             `bson:"channel,omitempty" json:"channel,omitempty"`
+	CardType          string             `bson:"cardType,omitempty" json:"card_type,omitempty"`
+	Last4             string             `bson:"last4,omitempty" json:"last4,omitempty"`
+	ExpMonth          string             `bson:"expMonth,omitempty" json:"exp_month,omitempty"`
+	ExpYear           string             `bson:"expYear,omitempty" json:"exp_year,omitempty"`
+	Bank              string             `bson:"bank,omitempty" json:"bank,omitempty"`
+	IsReusable        bool               `bson:"isReusable" json:"is_reusable"`
+	CreatedAt         time.Time          `bson:"createdAt" json:"created_at"`
+	LastUsedAt        time.Time          `bson:"lastUsedAt" json:"last_used_at"`
+}
+
 // PaymentRequest represents a payment initialization request
 type PaymentRequest struct {
 	ID            primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
@@ -87,4 +181,44 @@ type PaymentRequest struct {
 	Metadata      map[string]interface{} `bson:"metadata,omitempty" json:"metadata"`
 	CreatedAt     time.Time              `bson:"createdAt" json:"created_at"`
 	ExpiresAt     time.Time              `bson:"expiresAt" json:"expires_at"`
-}
\ No newline at end of file
+}
+// TransferStatus represents the status of an outgoing transfer
+type TransferStatus string
+
+const (
+	TransferStatusPending TransferStatus = "PENDING" // sent to the provider, awaiting its webhook
+	TransferStatusSuccess TransferStatus = "SUCCESS"
+	TransferStatusFailed  TransferStatus = "FAILED"
+)
+
+// TransferPurpose records what an outgoing transfer pays out
+type TransferPurpose string
+
+const (
+	TransferPurposeWithdrawal TransferPurpose = "WITHDRAWAL"
+	TransferPurposeRefund     TransferPurpose = "REFUND"
+)
+
+// Transfer represents a payout made through Paystack's Transfer API
+type Transfer struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Reference     string             `bson:"reference" json:"reference"` // Our transfer reference, unique per payout
+	Purpose       TransferPurpose    `bson:"purpose" json:"purpose"`
+	SourceID      string             `bson:"sourceId" json:"source_id"` // e.g. the goals-service withdrawal or refund disbursement ID
+	RefundID      string             `bson:"refundId,omitempty" json:"refund_id,omitempty"`
+	GoalID        string             `bson:"goalId,omitempty" json:"goal_id"`
+	OwnerID       string             `bson:"ownerId,omitempty" json:"owner_id"`
+	UserID        string             `bson:"userId,omitempty" json:"user_id,omitempty"` // Refunded contributor
+	GoalTitle     string             `bson:"goalTitle,omitempty" json:"goal_title"`
+	Amount        int64              `bson:"amount" json:"amount"`
+	Currency      string             `bson:"currency" json:"currency"`
+	BankCode      string             `bson:"bankCode,omitempty" json:"bank_code"`
+	AccountNumber string             `bson:"accountNumber" json:"account_number"`
+	AccountName   string             `bson:"accountName" json:"account_name"`
+	RecipientCode string             `bson:"recipientCode,omitempty" json:"recipient_code"`
+	TransferCode  string             `bson:"transferCode,omitempty" json:"transfer_code"`
+	Status        TransferStatus     `bson:"status" json:"status"`
+	FailureReason string             `bson:"failureReason,omitempty" json:"failure_reason,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"created_at"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updated_at"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// GoalSearchVector is the full-text search document of a goal: its title, weighted A,
+// and its description, weighted B, so title matches rank higher. The GIN index
+// idx_goals_search is built on this expression, and Postgres only uses it for queries
+// that repeat the expression exactly.
+const GoalSearchVector = `setweight(to_tsvector('english', coalesce(title, '')), 'A') || ` +
+	`setweight(to_tsvector('english', coalesce(description, '')), 'B')`
@@ -24,6 +24,8 @@ type User struct {
 	FirstName       string     `gorm:"size:100" json:"first_name"`
 	LastName        string     `gorm:"size:100" json:"last_name"`
 	Phone           string     `gorm:"size:20" json:"phone"`
+	AvatarURL       string     `gorm:"size:500" json:"avatar_url,omitempty"` // Externally hosted image for now
+	Bio             string     `gorm:"size:280" json:"bio,omitempty"`
 	EmailVerified   bool       `gorm:"default:false" json:"email_verified"`
 	PhoneVerified   bool       `gorm:"default:false" json:"phone_verified"`
 	
@@ -82,6 +84,32 @@ func (p *PasswordResetToken) IsExpired() bool {
 	return time.Now().After(p.ExpiresAt)
 }
 
+// EmailVerificationToken is a single-use token proving ownership of a user's email
+type EmailVerificationToken struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	TokenHash string    `gorm:"uniqueIndex;not null;size:255" json:"-"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	Used      bool      `gorm:"default:false" json:"used"`
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+
+	// Relationships
+	User User `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// BeforeCreate sets UUID before creating email verification token
+func (e *EmailVerificationToken) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsExpired checks if the token has expired
+func (e *EmailVerificationToken) IsExpired() bool {
+	return time.Now().After(e.ExpiresAt)
+}
+
 // Session represents a user session
 type Session struct {
 	ID        uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
@@ -90,6 +118,9 @@ type Session struct {
 	ExpiresAt time.Time              `gorm:"not null" json:"expires_at"`
 	Metadata  map[string]interface{} `gorm:"type:jsonb" json:"metadata"`
 	CreatedAt time.Time              `gorm:"not null" json:"created_at"`
+	// RotatedAt is set once the session's refresh token has been exchanged for a new one.
+	// Rotated sessions are kept until they expire so that reuse of their token is detected.
+	RotatedAt *time.Time `gorm:"index" json:"rotated_at,omitempty"`
 	
 	// Relationships
 	User User `gorm:"constraint:OnDelete:CASCADE"`
@@ -106,4 +137,51 @@ func (s *Session) BeforeCreate(tx *gorm.DB) error {
 // IsExpired checks if the session has expired
 func (s *Session) IsExpired() bool {
 	return time.Now().After(s.ExpiresAt)
-}
\ No newline at end of file
+}
+
+// IsRotated reports whether the session's refresh token has already been exchanged
+func (s *Session) IsRotated() bool {
+	return s.RotatedAt != nil
+}
+// KYCStatus is where a KYC submission is in admin review
+type KYCStatus string
+
+const (
+	KYCStatusPending  KYCStatus = "pending"
+	KYCStatusApproved KYCStatus = "approved"
+	KYCStatusRejected KYCStatus = "rejected"
+)
+
+// KYCDocumentType is the identity document a KYC submission is based on
+type KYCDocumentType string
+
+const (
+	KYCDocumentNIN            KYCDocumentType = "nin"
+	KYCDocumentPassport       KYCDocumentType = "passport"
+	KYCDocumentDriversLicense KYCDocumentType = "drivers_license"
+	KYCDocumentVotersCard     KYCDocumentType = "voters_card"
+)
+
+// KYCSubmission is an identity document a user has sent for admin review. Approving it
+// marks the user KYC verified.
+type KYCSubmission struct {
+	ID              uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID          uuid.UUID       `gorm:"type:uuid;not null;index" json:"user_id"`
+	DocumentType    KYCDocumentType `gorm:"size:30;not null" json:"document_type"`
+	DocumentNumber  string          `gorm:"size:50;not null" json:"document_number"`
+	DocumentURLs    []string        `gorm:"type:jsonb;serializer:json" json:"document_urls"`
+	Status          KYCStatus       `gorm:"size:20;not null;default:pending;index" json:"status"`
+	ReviewerID      *uuid.UUID      `gorm:"type:uuid" json:"reviewer_id,omitempty"`
+	RejectionReason string          `gorm:"size:500" json:"rejection_reason,omitempty"`
+	ReviewedAt      *time.Time      `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time       `gorm:"not null" json:"created_at"`
+	UpdatedAt       time.Time       `gorm:"not null" json:"updated_at"`
+}
+
+// BeforeCreate sets UUID before creating KYC submission
+func (k *KYCSubmission) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/identity"
+)
+
+// KeyFunc names the caller a request counts against
+type KeyFunc func(c *gin.Context) string
+
+// ByClientIP keys callers by client address, for routes reached before authentication
+func ByClientIP(c *gin.Context) string {
+	return Caller("", c.Request)
+}
+
+// ByUser keys callers by the user ID the gateway identified them by, falling back to
+// the client address. The middleware must run after the identity guard so the user ID
+// is trusted.
+func ByUser(c *gin.Context) string {
+	return Caller(c.GetHeader(identity.HeaderUserID), c.Request)
+}
+
+// Middleware throttles requests with limiter, answering 429 with Retry-After once the
+// bucket of the caller named by key is empty
+func Middleware(limiter *Limiter, key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, retryAfter := limiter.Allow(key(c))
+		if !allowed {
+			Reject(c.Writer, c.Request, retryAfter)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofund/shared/identity"
+)
+
+// headers builds a header from name, value pairs, canonicalising the names as a server would
+func headers(pairs ...string) http.Header {
+	header := http.Header{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		header.Set(pairs[i], pairs[i+1])
+	}
+	return header
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		key  KeyFunc
+		// second is sent after first; want is the status the second gets
+		first, second http.Header
+		want          int
+	}{
+		{
+			name:   "same address is throttled",
+			key:    ByClientIP,
+			first:  headers(HeaderRealIP, "10.0.0.1"),
+			second: headers(HeaderRealIP, "10.0.0.1"),
+			want:   http.StatusTooManyRequests,
+		},
+		{
+			name:   "other address is let through",
+			key:    ByClientIP,
+			first:  headers(HeaderRealIP, "10.0.0.1"),
+			second: headers(HeaderRealIP, "10.0.0.2"),
+			want:   http.StatusOK,
+		},
+		{
+			name:   "users behind one address are keyed apart",
+			key:    ByUser,
+			first:  headers(HeaderRealIP, "10.0.0.1", identity.HeaderUserID, "user-1"),
+			second: headers(HeaderRealIP, "10.0.0.1", identity.HeaderUserID, "user-2"),
+			want:   http.StatusOK,
+		},
+		{
+			name:   "user is throttled across addresses",
+			key:    ByUser,
+			first:  headers(HeaderRealIP, "10.0.0.1", identity.HeaderUserID, "user-1"),
+			second: headers(HeaderRealIP, "10.0.0.2", identity.HeaderUserID, "user-1"),
+			want:   http.StatusTooManyRequests,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := New("test", PerMinute(1), NewMemoryStore())
+			r := gin.New()
+			r.GET("/", Middleware(limiter, tt.key), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			serve := func(header http.Header) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header = header
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				return w
+			}
+			if w := serve(tt.first); w.Code != http.StatusOK {
+				t.Fatalf("first request: status = %d, want %d", w.Code, http.StatusOK)
+			}
+			w := serve(tt.second)
+			if w.Code != tt.want {
+				t.Fatalf("second request: status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.want == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+				t.Errorf("throttled response has no Retry-After header")
+			}
+		})
+	}
+}
+
+func TestMiddlewareWithoutLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", Middleware(nil, ByClientIP), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 5; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want %d", i, w.Code, http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops buckets that have refilled completely
+const sweepInterval = 10 * time.Minute
+
+// MemoryStore keeps buckets in process memory. Limits apply per instance, so a service
+// running N replicas lets through up to N times the limit.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // when the bucket will have refilled, after which it can be dropped
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), lastSweep: time.Now()}
+}
+
+// Take implements Store
+func (s *MemoryStore) Take(key string, limit Limit, now time.Time) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	capacity, rate := limit.capacity(), limit.rate()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	} else {
+		b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+		b.updated = now
+	}
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+		return false, wait
+	}
+
+	b.tokens--
+	b.full = now.Add(time.Duration((capacity - b.tokens) / rate * float64(time.Second)))
+	return true, 0
+}
+
+// sweep drops buckets that are full again; a new bucket starts full, so this changes
+// nothing but memory. Callers hold s.mu.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,138 @@
+// Package ratelimit throttles HTTP requests with token buckets. A Limiter holds one
+// route's limit; buckets live in a Store, keyed by limiter and caller, so several
+// limiters can share one store. Middleware applies a Limiter to gin routes.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/gofund/shared/errors"
+	"github.com/gofund/shared/httperr"
+	"github.com/gofund/shared/metrics"
+)
+
+// HeaderRealIP carries the client address set by the gateway, which overwrites any value
+// sent by the client
+const HeaderRealIP = "X-Real-IP"
+
+// ErrRateLimited is returned to throttled callers
+var ErrRateLimited = apperrors.RateLimited("rate_limited", "too many requests, please retry later")
+
+// Limit allows Requests per Window, refilled continuously. Burst is the bucket size, i.e.
+// how many requests may arrive at once; zero means Requests.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+	Burst    int
+}
+
+// PerMinute allows n requests a minute
+func PerMinute(n int) Limit {
+	return Limit{Requests: n, Window: time.Minute}
+}
+
+// PerHour allows n requests an hour
+func PerHour(n int) Limit {
+	return Limit{Requests: n, Window: time.Hour}
+}
+
+// capacity is the bucket size
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// rate is the refill rate in tokens per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Window.Seconds()
+}
+
+// disabled reports whether the limit lets everything through
+func (l Limit) disabled() bool {
+	return l.Requests <= 0 || l.Window <= 0
+}
+
+// Store holds token buckets. Take removes a token from key's bucket under limit and
+// reports whether there was one; when there was not, retryAfter is how long until there
+// will be. Implementations must be safe for concurrent use.
+type Store interface {
+	Take(key string, limit Limit, now time.Time) (allowed bool, retryAfter time.Duration)
+}
+
+// Limiter applies one limit to each caller of the routes it guards
+type Limiter struct {
+	name  string
+	limit Limit
+	store Store
+}
+
+// New creates a limiter. name labels its buckets and metrics, so limiters sharing a
+// store must have different names. A limit of zero requests disables the limiter.
+func New(name string, limit Limit, store Store) *Limiter {
+	return &Limiter{name: name, limit: limit, store: store}
+}
+
+// Allow takes a token for the caller and reports whether the request may proceed, and if
+// not, how long the caller should wait
+func (l *Limiter) Allow(caller string) (bool, time.Duration) {
+	if l == nil || l.limit.disabled() {
+		return true, 0
+	}
+
+	allowed, retryAfter := l.store.Take(l.name+":"+caller, l.limit, time.Now())
+	if !allowed {
+		metrics.TrackRateLimited(l.name)
+	}
+	return allowed, retryAfter
+}
+
+// Caller identifies who a request counts against: the authenticated user when there is
+// one, so users behind a shared address do not throttle each other, otherwise the client
+// address
+func Caller(userID string, r *http.Request) string {
+	if userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + ClientIP(r)
+}
+
+// ClientIP returns the client address set by the gateway, falling back to the address of
+// the connection for requests that did not come through it
+func ClientIP(r *http.Request) string {
+	if ip := r.Header.Get(HeaderRealIP); ip != "" {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Reject writes a 429 response telling the caller when to retry
+func Reject(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	httperr.Respond(w, r, ErrRateLimited, map[string]interface{}{
+		"retry_after_seconds": seconds,
+	})
+}
+
+// String describes the limit, e.g. for startup logs
+func (l Limit) String() string {
+	if l.disabled() {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d per %s (burst %d)", l.Requests, l.Window, int(l.capacity()))
+}
@@ -0,0 +1,43 @@
+package requestid
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+// Header carries the request ID between the client, the gateway and the services
+const Header = "X-Request-ID"
+
+// validID limits incoming IDs to something safe to echo back and write to logs
+var validID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type contextKey struct{}
+
+// New returns a fresh request ID
+func New() string {
+	return uuid.New().String()
+}
+
+// FromHeader returns the incoming X-Request-ID if it is usable, or a fresh ID otherwise
+func FromHeader(value string) string {
+	if validID.MatchString(value) {
+		return value
+	}
+	return New()
+}
+
+// WithContext returns a copy of ctx carrying the request ID
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, or ""
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,39 @@
+// Package testhooks gates endpoints that only exist so the e2e suite can drive flows
+// that normally need a human or a payment provider, such as verifying an email or
+// completing a payment.
+package testhooks
+
+import (
+	"log"
+	"os"
+	"strings"
+)
+
+// EnvVar opts a service into registering its test hooks
+const EnvVar = "ENABLE_TEST_HOOKS"
+
+// Enabled reports whether test hooks should be registered. They are opt-in through
+// ENABLE_TEST_HOOKS and refused whenever any of the given environment names is production.
+func Enabled(envs ...string) bool {
+	if os.Getenv(EnvVar) != "true" {
+		return false
+	}
+
+	for _, env := range envs {
+		if isProduction(env) {
+			log.Printf("Warning: %s is set but test hooks are never registered in production", EnvVar)
+			return false
+		}
+	}
+
+	log.Printf("Warning: test hooks are enabled; never expose this service publicly")
+	return true
+}
+
+func isProduction(env string) bool {
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case "production", "prod":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultCurrency is used when a goal is created without a currency
+const DefaultCurrency = "NGN"
+
+// ErrUnsupportedCurrency is returned for currencies GoFund does not accept payments in
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// supportedCurrencies are the ISO 4217 codes goals and payments may use. Paystack also
+// settles GHS and KES; add them here once payouts in those currencies are supported.
+var supportedCurrencies = map[string]bool{
+	"NGN": true,
+}
+
+// IsSupportedCurrency reports whether code is an accepted currency. Codes are matched
+// exactly, so normalize user input with NormalizeCurrency first.
+func IsSupportedCurrency(code string) bool {
+	return supportedCurrencies[code]
+}
+
+// SupportedCurrencies lists the accepted currency codes in alphabetical order
+func SupportedCurrencies() []string {
+	codes := make([]string, 0, len(supportedCurrencies))
+	for code := range supportedCurrencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// NormalizeCurrency upper-cases a currency code and checks that it is supported. An
+// empty code becomes DefaultCurrency.
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency, nil
+	}
+	if !IsSupportedCurrency(code) {
+		return "", fmt.Errorf("%w: %s (supported: %s)", ErrUnsupportedCurrency, code, strings.Join(SupportedCurrencies(), ", "))
+	}
+	return code, nil
+}
+
+// SameCurrency reports whether two currency codes name the same currency, treating an
+// empty code as DefaultCurrency
+func SameCurrency(a, b string) bool {
+	normalize := func(code string) string {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" {
+			return DefaultCurrency
+		}
+		return code
+	}
+	return normalize(a) == normalize(b)
+}
@@ -0,0 +1,66 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeCurrency(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{code: "NGN", want: "NGN"},
+		{code: " ngn ", want: "NGN"},
+		{code: "", want: DefaultCurrency},
+		{code: "USD", wantErr: true},
+		// Not supported until payouts in them are
+		{code: "GHS", wantErr: true},
+		{code: "KES", wantErr: true},
+		{code: "naira", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeCurrency(tt.code)
+		if tt.wantErr {
+			if !errors.Is(err, ErrUnsupportedCurrency) {
+				t.Errorf("NormalizeCurrency(%q) err = %v, want %v", tt.code, err, ErrUnsupportedCurrency)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeCurrency(%q) = %q, %v; want %q", tt.code, got, err, tt.want)
+		}
+	}
+}
+
+func TestSameCurrency(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "NGN", b: "NGN", want: true},
+		{a: "ngn", b: " NGN", want: true},
+		// Events published before currencies were carried are NGN
+		{a: "", b: "NGN", want: true},
+		{a: "NGN", b: "USD", want: false},
+		{a: "", b: "GHS", want: false},
+	}
+	for _, tt := range tests {
+		if got := SameCurrency(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameCurrency(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSupportedCurrencies(t *testing.T) {
+	if got := SupportedCurrencies(); !reflect.DeepEqual(got, []string{"NGN"}) {
+		t.Errorf("SupportedCurrencies() = %v, want [NGN]", got)
+	}
+	for _, code := range SupportedCurrencies() {
+		if !IsSupportedCurrency(code) {
+			t.Errorf("IsSupportedCurrency(%q) = false for a listed currency", code)
+		}
+	}
+}